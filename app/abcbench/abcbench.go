@@ -0,0 +1,102 @@
+// Package abcbench 在go test中运行abc-runner场景，并按SLA规则断言结果
+//
+// 场景与命令行用法一致：Command为命令名（redis、http、kafka等），Args为其后的参数。
+// 场景结束后不生成报告文件，最终指标快照直接返回给调用方，SLA违规时测试失败：
+//
+//	func TestCheckoutLatency(t *testing.T) {
+//		abcbench.Require(t, abcbench.Scenario{
+//			Command: "http",
+//			Args:    []string{"--url", "http://localhost:8080", "-n", "1000", "-c", "10"},
+//			SLA:     []string{"p99 < 50ms", "error_rate < 1%"},
+//		})
+//	}
+//
+// 在基准测试中使用时，平均延迟作为ns/op上报，并附带p99、吞吐量与错误率，
+// 每次迭代都会完整运行一次场景，建议配合 -benchtime=1x 使用。
+// 场景通过pkg/runner运行；需要结构化报告或在测试之外嵌入时直接使用runner包。
+package abcbench
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"abc-runner/app/core/metrics"
+	"abc-runner/pkg/runner"
+)
+
+// Scenario 一次测试场景
+type Scenario struct {
+	Command string        // 命令名称或别名，如redis、http、kafka
+	Args    []string      // 命令参数，与 abc-runner <command> 之后的参数相同
+	SLA     []string      // SLA规则，如 "p99 < 20ms"、"error_rate < 1%"、"rps > 5000"
+	Timeout time.Duration // 场景超时，0表示仅受调用方context约束
+}
+
+// Result 场景运行结果
+type Result struct {
+	Snapshot   *metrics.DefaultMetricsSnapshot
+	Assertions []metrics.SLAAssertion
+}
+
+// Passed 是否全部SLA规则通过
+func (r *Result) Passed() bool {
+	return metrics.SLAFailures(r.Assertions) == 0
+}
+
+// Failures 未通过的SLA断言
+func (r *Result) Failures() []metrics.SLAAssertion {
+	var failures []metrics.SLAAssertion
+	for _, assertion := range r.Assertions {
+		if !assertion.Passed {
+			failures = append(failures, assertion)
+		}
+	}
+	return failures
+}
+
+// Run 运行场景并判定SLA规则；运行失败或未产生指标快照时返回错误，SLA违规不视为错误
+func Run(ctx context.Context, scenario Scenario) (*Result, error) {
+	result, err := runner.Run(ctx, runner.Scenario{
+		Command: scenario.Command,
+		Args:    scenario.Args,
+		SLA:     scenario.SLA,
+		Timeout: scenario.Timeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Result{
+		Snapshot:   result.Snapshot,
+		Assertions: result.Report.SLA,
+	}, nil
+}
+
+// Require 在测试中运行场景：运行失败时终止测试，每条未通过的SLA规则记录一次测试失败
+// tb为*testing.B时同时上报延迟、吞吐量与错误率
+func Require(tb testing.TB, scenario Scenario) *Result {
+	tb.Helper()
+
+	result, err := Run(context.Background(), scenario)
+	if err != nil {
+		tb.Fatalf("abcbench: %v", err)
+	}
+	if b, ok := tb.(*testing.B); ok {
+		ReportMetrics(b, result.Snapshot)
+	}
+	for _, failure := range result.Failures() {
+		tb.Errorf("abcbench: SLA violated: %s (%s)", failure.Name, failure.Message)
+	}
+	return result
+}
+
+// ReportMetrics 将快照的核心指标上报为基准测试指标
+func ReportMetrics(b *testing.B, snapshot *metrics.DefaultMetricsSnapshot) {
+	core := snapshot.Core
+	b.ReportMetric(float64(core.Latency.Average.Nanoseconds()), "ns/op")
+	b.ReportMetric(float64(core.Latency.P99.Nanoseconds()), "p99-ns")
+	b.ReportMetric(core.Throughput.RPS, "ops/s")
+	if core.Operations.Total > 0 {
+		b.ReportMetric(float64(core.Operations.Failed)/float64(core.Operations.Total)*100, "err%")
+	}
+}
@@ -0,0 +1,40 @@
+package abcbench
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	scenario := Scenario{
+		Command: "http",
+		Args:    []string{"--url", server.URL, "-n", "50", "-c", "2"},
+		SLA:     []string{"error_rate < 1%", "p99 < 1ns"},
+	}
+	result, err := Run(context.Background(), scenario)
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if ops := result.Snapshot.Core.Operations; ops.Total == 0 || ops.Failed != 0 {
+		t.Errorf("expected successful operations, got %+v", ops)
+	}
+	if result.Passed() || len(result.Failures()) != 1 || result.Failures()[0].Metric != "p99" {
+		t.Errorf("expected only the p99 rule to fail, got %+v", result.Assertions)
+	}
+
+	if _, err := Run(context.Background(), Scenario{Command: "http", SLA: []string{"p99 fast"}}); err == nil {
+		t.Error("expected an error for an invalid SLA rule")
+	}
+	if _, err := Run(context.Background(), Scenario{Command: "http", Args: []string{"--help"}}); err == nil ||
+		!strings.Contains(err.Error(), "without producing a metrics snapshot") {
+		t.Errorf("expected a missing snapshot error, got %v", err)
+	}
+}
@@ -0,0 +1,203 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"abc-runner/app/adapters/clickhouse/config"
+	"abc-runner/app/adapters/clickhouse/connection"
+	"abc-runner/app/adapters/clickhouse/operations"
+	"abc-runner/app/core/interfaces"
+)
+
+// ClickHouseAdapter ClickHouse原生协议适配器 - 遵循统一架构模式
+// 职责：连接池管理、测试表准备、健康检查
+type ClickHouseAdapter struct {
+	config               *config.ClickHouseConfig
+	pool                 *connection.Pool
+	clickhouseOperations *operations.ClickHouseExecutor
+	metricsCollector     interfaces.DefaultMetricsCollector
+	mu                   sync.RWMutex
+	isConnected          bool
+	seededRows           int64
+
+	// 统计信息
+	totalOperations  int64
+	failedOperations int64
+}
+
+// NewClickHouseAdapter 创建ClickHouse适配器
+func NewClickHouseAdapter(metricsCollector interfaces.DefaultMetricsCollector) *ClickHouseAdapter {
+	return &ClickHouseAdapter{
+		metricsCollector: metricsCollector,
+	}
+}
+
+// Connect 建立连接池，配置了setup时创建测试表并按需写入初始数据
+func (c *ClickHouseAdapter) Connect(ctx context.Context, cfg interfaces.Config) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	chConfig, ok := cfg.(*config.ClickHouseConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type for ClickHouse adapter: expected *config.ClickHouseConfig, got %T", cfg)
+	}
+
+	if err := chConfig.Validate(); err != nil {
+		return fmt.Errorf("config validation failed: %w", err)
+	}
+	c.config = chConfig
+
+	pool, err := connection.NewPool(ctx, chConfig)
+	if err != nil {
+		return err
+	}
+
+	rows := operations.NewRowGenerator(chConfig.ClickHouseSpecific.Users, chConfig.ClickHouseSpecific.PayloadSize)
+	if chConfig.ClickHouseSpecific.Setup {
+		if err := c.setup(ctx, pool, rows); err != nil {
+			pool.Close()
+			return err
+		}
+	}
+
+	c.pool = pool
+	c.clickhouseOperations = operations.NewClickHouseExecutor(pool, chConfig, rows)
+	c.isConnected = true
+	return nil
+}
+
+// setup 使用池中的一个连接建表并写入初始数据
+func (c *ClickHouseAdapter) setup(ctx context.Context, pool *connection.Pool, rows *operations.RowGenerator) error {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer pool.Release(conn)
+
+	seeded, err := operations.Setup(ctx, conn, c.config, rows)
+	c.seededRows = seeded
+	return err
+}
+
+// Execute 执行操作 - 使用执行器处理
+func (c *ClickHouseAdapter) Execute(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	if !c.isConnected {
+		return nil, fmt.Errorf("adapter not connected")
+	}
+
+	atomic.AddInt64(&c.totalOperations, 1)
+	result, err := c.clickhouseOperations.ExecuteOperation(ctx, operation)
+	if err != nil {
+		atomic.AddInt64(&c.failedOperations, 1)
+	}
+	return result, err
+}
+
+// Close 关闭连接池
+func (c *ClickHouseAdapter) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.pool != nil {
+		c.pool.Close()
+		c.pool = nil
+	}
+	c.isConnected = false
+	return nil
+}
+
+// GetProtocolMetrics 获取协议特定指标
+func (c *ClickHouseAdapter) GetProtocolMetrics() map[string]interface{} {
+	metrics := map[string]interface{}{
+		"protocol":          "clickhouse",
+		"total_operations":  atomic.LoadInt64(&c.totalOperations),
+		"failed_operations": atomic.LoadInt64(&c.failedOperations),
+	}
+
+	if c.config != nil {
+		metrics["table"] = c.config.ClickHouseSpecific.Table
+		metrics["compression"] = c.config.Connection.Compression
+	}
+	if c.pool != nil {
+		metrics["server_version"] = c.pool.ServerVersion()
+		metrics["pool_stats"] = c.pool.Stats()
+	}
+	if stats := c.GetOperationStats(); stats != nil {
+		metrics["operation_stats"] = stats
+	}
+	if transfer := c.GetTransferStats(); transfer != nil {
+		metrics["transfer"] = *transfer
+	}
+
+	return metrics
+}
+
+// GetOperationStats 获取按操作类型的统计，未连接时返回nil
+func (c *ClickHouseAdapter) GetOperationStats() []operations.OperationStats {
+	if c.clickhouseOperations == nil {
+		return nil
+	}
+	return c.clickhouseOperations.OperationStats()
+}
+
+// GetTransferStats 获取插入与查询的数据量，未连接时返回nil
+func (c *ClickHouseAdapter) GetTransferStats() *operations.TransferStats {
+	if c.clickhouseOperations == nil {
+		return nil
+	}
+	stats := c.clickhouseOperations.TransferStats()
+	return &stats
+}
+
+// GetPoolStats 获取连接池统计，未连接时返回nil
+func (c *ClickHouseAdapter) GetPoolStats() *connection.PoolStats {
+	if c.pool == nil {
+		return nil
+	}
+	stats := c.pool.Stats()
+	return &stats
+}
+
+// ServerVersion 服务端名称与版本，未连接时为空
+func (c *ClickHouseAdapter) ServerVersion() string {
+	if c.pool == nil {
+		return ""
+	}
+	return c.pool.ServerVersion()
+}
+
+// SeededRows 本次运行由setup写入的初始行数
+func (c *ClickHouseAdapter) SeededRows() int64 {
+	return c.seededRows
+}
+
+// HealthCheck 健康检查：在池中的连接上发送Ping
+func (c *ClickHouseAdapter) HealthCheck(ctx context.Context) error {
+	if !c.isConnected {
+		return fmt.Errorf("adapter not connected")
+	}
+
+	conn, err := c.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.pool.Release(conn)
+
+	if err := conn.Ping(ctx); err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	return nil
+}
+
+// GetProtocolName 获取协议名称
+func (c *ClickHouseAdapter) GetProtocolName() string {
+	return "clickhouse"
+}
+
+// GetMetricsCollector 获取指标收集器
+func (c *ClickHouseAdapter) GetMetricsCollector() interfaces.DefaultMetricsCollector {
+	return c.metricsCollector
+}
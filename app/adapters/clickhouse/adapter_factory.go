@@ -0,0 +1,44 @@
+package clickhouse
+
+import (
+	"abc-runner/app/core/interfaces"
+)
+
+// AdapterFactory ClickHouse适配器工厂
+type AdapterFactory struct {
+	metricsCollector interfaces.DefaultMetricsCollector
+}
+
+// NewAdapterFactory 创建ClickHouse适配器工厂
+func NewAdapterFactory(metricsCollector interfaces.DefaultMetricsCollector) *AdapterFactory {
+	return &AdapterFactory{
+		metricsCollector: metricsCollector,
+	}
+}
+
+// CreateClickHouseAdapter 创建ClickHouse适配器 (实现ClickHouseAdapterFactory接口)
+func (f *AdapterFactory) CreateClickHouseAdapter() interfaces.ProtocolAdapter {
+	if f.metricsCollector == nil {
+		panic("metricsCollector cannot be nil - dependency injection required")
+	}
+
+	return NewClickHouseAdapter(f.metricsCollector)
+}
+
+// GetProtocolName 获取支持的协议名称
+func (f *AdapterFactory) GetProtocolName() string {
+	return "clickhouse"
+}
+
+// GetMetricsCollector 获取指标收集器
+func (f *AdapterFactory) GetMetricsCollector() interfaces.DefaultMetricsCollector {
+	return f.metricsCollector
+}
+
+// SetMetricsCollector 设置指标收集器
+func (f *AdapterFactory) SetMetricsCollector(collector interfaces.DefaultMetricsCollector) {
+	f.metricsCollector = collector
+}
+
+// 确保实现了interfaces.ClickHouseAdapterFactory接口
+var _ interfaces.ClickHouseAdapterFactory = (*AdapterFactory)(nil)
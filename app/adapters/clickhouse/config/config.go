@@ -0,0 +1,356 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/utils"
+)
+
+// 测试用例
+const (
+	TestCaseInsert = "insert" // 通过原生协议分批写入行
+	TestCaseQuery  = "query"  // 按权重执行查询模板
+	TestCaseMixed  = "mixed"  // 按read_percent混合查询与写入
+)
+
+// TestCases 支持的测试用例
+var TestCases = []string{TestCaseInsert, TestCaseQuery, TestCaseMixed}
+
+// 压缩方式
+const (
+	CompressionLZ4  = "lz4"
+	CompressionNone = "none"
+)
+
+// TablePlaceholder 查询模板中代表测试表的占位符
+const TablePlaceholder = "{{table}}"
+
+// DefaultTable 默认测试表
+const DefaultTable = "abc_runner_events"
+
+// DefaultQueries 默认查询模板，覆盖聚合、点查、排序与分位数
+var DefaultQueries = []QueryTemplate{
+	{Name: "count_by_event", SQL: "SELECT event, count() FROM {{table}} GROUP BY event", Weight: 1},
+	{Name: "user_summary", SQL: "SELECT count(), sum(value), max(ts) FROM {{table}} WHERE user_id = {{randInt 0 9999}}", Weight: 1},
+	{Name: "top_users", SQL: "SELECT user_id, sum(value) AS total FROM {{table}} GROUP BY user_id ORDER BY total DESC LIMIT 10", Weight: 1},
+	{Name: "value_quantiles", SQL: "SELECT event, quantiles(0.5, 0.9, 0.99)(value) FROM {{table}} GROUP BY event", Weight: 1},
+}
+
+// ClickHouseConfig ClickHouse协议配置
+type ClickHouseConfig struct {
+	Protocol           string                   `yaml:"protocol" json:"protocol"`
+	Connection         ConnectionConfig         `yaml:"connection" json:"connection"`
+	BenchMark          BenchmarkConfig          `yaml:"benchmark" json:"benchmark"`
+	ClickHouseSpecific ClickHouseSpecificConfig `yaml:"clickhouse_specific" json:"clickhouse_specific"`
+}
+
+// ConnectionConfig ClickHouse连接配置（原生TCP协议）
+type ConnectionConfig struct {
+	Address            string        `yaml:"address" json:"address"`
+	Port               int           `yaml:"port" json:"port"` // 原生协议端口，TLS通常为9440
+	User               string        `yaml:"user" json:"user"`
+	Password           string        `yaml:"password" json:"password"`
+	Database           string        `yaml:"database" json:"database"`
+	Compression        string        `yaml:"compression" json:"compression"` // lz4或none，数据块的传输压缩
+	PoolSize           int           `yaml:"pool_size" json:"pool_size"`     // 连接池大小，0表示与并发数相同
+	Timeout            time.Duration `yaml:"timeout" json:"timeout"`         // 建连与单次插入或查询的超时
+	Secure             bool          `yaml:"secure" json:"secure"`           // 使用TLS连接
+	InsecureSkipVerify bool          `yaml:"insecure_skip_verify" json:"insecure_skip_verify"`
+}
+
+// BenchmarkConfig ClickHouse基准测试配置
+type BenchmarkConfig struct {
+	Total       int           `yaml:"total" json:"total"`
+	Parallels   int           `yaml:"parallels" json:"parallels"`
+	TestCase    string        `yaml:"test_case" json:"test_case"`
+	Duration    time.Duration `yaml:"duration" json:"duration"`
+	Rate        int           `yaml:"rate" json:"rate"`                 // 每秒操作数上限，0表示不限速
+	ReadPercent int           `yaml:"read_percent" json:"read_percent"` // mixed中查询的比例(0-100)
+}
+
+// ClickHouseSpecificConfig ClickHouse特定配置
+type ClickHouseSpecificConfig struct {
+	Table       string          `yaml:"table" json:"table"`               // 测试表，需包含默认表的全部列
+	BatchSize   int             `yaml:"batch_size" json:"batch_size"`     // 每次插入的行数
+	PayloadSize int             `yaml:"payload_size" json:"payload_size"` // payload列的字节数
+	Users       int             `yaml:"users" json:"users"`               // user_id的取值范围[0, users)
+	Setup       bool            `yaml:"setup" json:"setup"`               // 运行前创建测试表，查询测试中表为空时写入seed_rows行
+	SeedRows    int             `yaml:"seed_rows" json:"seed_rows"`       // 查询测试前写入的行数
+	Queries     []QueryTemplate `yaml:"queries" json:"queries"`           // 查询模板，为空时使用默认查询
+}
+
+// QueryTemplate 查询模板，{{table}}替换为测试表，其余占位符与负载模板相同（如{{randInt 0 9999}}）
+type QueryTemplate struct {
+	Name   string `yaml:"name" json:"name"`
+	SQL    string `yaml:"sql" json:"sql"`
+	Weight int    `yaml:"weight" json:"weight"` // 被选中的相对权重
+}
+
+// NewDefaultClickHouseConfig 创建默认ClickHouse配置
+func NewDefaultClickHouseConfig() *ClickHouseConfig {
+	return &ClickHouseConfig{
+		Protocol: "clickhouse",
+		Connection: ConnectionConfig{
+			Address:     "localhost",
+			Port:        9000,
+			User:        "default",
+			Database:    "default",
+			Compression: CompressionLZ4,
+			Timeout:     30 * time.Second,
+		},
+		BenchMark: BenchmarkConfig{
+			Total:       1000,
+			Parallels:   4,
+			TestCase:    TestCaseInsert,
+			ReadPercent: 20,
+		},
+		ClickHouseSpecific: ClickHouseSpecificConfig{
+			Table:       DefaultTable,
+			BatchSize:   10000,
+			PayloadSize: 64,
+			Users:       10000,
+			Setup:       true,
+			SeedRows:    1000000,
+		},
+	}
+}
+
+// GetProtocol 实现Config接口
+func (c *ClickHouseConfig) GetProtocol() string {
+	return c.Protocol
+}
+
+// GetConnection 实现Config接口
+func (c *ClickHouseConfig) GetConnection() interfaces.ConnectionConfig {
+	return &c.Connection
+}
+
+// GetBenchmark 实现Config接口
+func (c *ClickHouseConfig) GetBenchmark() interfaces.BenchmarkConfig {
+	return &c.BenchMark
+}
+
+// Validate 实现Config接口
+func (c *ClickHouseConfig) Validate() error {
+	if c.Connection.Address == "" {
+		return fmt.Errorf("connection address cannot be empty")
+	}
+	if c.Connection.Port <= 0 || c.Connection.Port > 65535 {
+		return fmt.Errorf("invalid port number: %d", c.Connection.Port)
+	}
+	if c.Connection.User == "" {
+		return fmt.Errorf("user cannot be empty")
+	}
+	if c.Connection.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+	if c.Connection.PoolSize < 0 {
+		return fmt.Errorf("pool size cannot be negative")
+	}
+	switch c.Connection.Compression {
+	case CompressionLZ4, CompressionNone:
+	default:
+		return fmt.Errorf("invalid compression: %s, valid options: %s, %s",
+			c.Connection.Compression, CompressionLZ4, CompressionNone)
+	}
+
+	if c.BenchMark.Total <= 0 {
+		return fmt.Errorf("total operations must be greater than 0")
+	}
+	if c.BenchMark.Parallels <= 0 {
+		return fmt.Errorf("parallel connections must be greater than 0")
+	}
+	if !isTestCase(c.BenchMark.TestCase) {
+		return fmt.Errorf("invalid test case: %s, valid options: %s",
+			c.BenchMark.TestCase, strings.Join(TestCases, ", "))
+	}
+	if c.BenchMark.Rate < 0 {
+		return fmt.Errorf("rate cannot be negative")
+	}
+	if c.BenchMark.ReadPercent < 0 || c.BenchMark.ReadPercent > 100 {
+		return fmt.Errorf("read percent must be between 0 and 100")
+	}
+
+	specific := c.ClickHouseSpecific
+	if specific.Table == "" {
+		return fmt.Errorf("table cannot be empty")
+	}
+	if specific.BatchSize <= 0 {
+		return fmt.Errorf("batch size must be greater than 0")
+	}
+	if specific.PayloadSize < 0 {
+		return fmt.Errorf("payload size cannot be negative")
+	}
+	if specific.Users <= 0 {
+		return fmt.Errorf("users must be greater than 0")
+	}
+	if specific.SeedRows < 0 {
+		return fmt.Errorf("seed rows cannot be negative")
+	}
+
+	names := make(map[string]bool)
+	for i, query := range specific.GetQueries() {
+		if query.Name == "" {
+			return fmt.Errorf("query %d: name cannot be empty", i+1)
+		}
+		if query.Name == TestCaseInsert || names[query.Name] {
+			return fmt.Errorf("query %d: duplicate or reserved name %q", i+1, query.Name)
+		}
+		names[query.Name] = true
+		if strings.TrimSpace(query.SQL) == "" {
+			return fmt.Errorf("query %s: sql cannot be empty", query.Name)
+		}
+		if query.Weight <= 0 {
+			return fmt.Errorf("query %s: weight must be greater than 0", query.Name)
+		}
+		if _, err := utils.ParsePayloadTemplate(specific.Expand(query.SQL), nil); err != nil {
+			return fmt.Errorf("query %s: %w", query.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// isTestCase 是否为支持的测试用例
+func isTestCase(testCase string) bool {
+	for _, valid := range TestCases {
+		if testCase == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// RunsQueries 测试用例是否执行查询（需要表中已有数据）
+func (b *BenchmarkConfig) RunsQueries() bool {
+	return b.TestCase == TestCaseQuery || b.TestCase == TestCaseMixed
+}
+
+// GetQueries 获取查询模板，未配置时使用默认查询
+func (s *ClickHouseSpecificConfig) GetQueries() []QueryTemplate {
+	if len(s.Queries) > 0 {
+		return s.Queries
+	}
+	return DefaultQueries
+}
+
+// Expand 将查询中的表占位符替换为测试表
+func (s *ClickHouseSpecificConfig) Expand(sql string) string {
+	return strings.ReplaceAll(sql, TablePlaceholder, s.Table)
+}
+
+// GetPoolSize 获取连接池大小，未配置时与并发数相同
+func (c *ClickHouseConfig) GetPoolSize() int {
+	if c.Connection.PoolSize > 0 {
+		return c.Connection.PoolSize
+	}
+	return c.BenchMark.Parallels
+}
+
+// Clone 实现Config接口
+func (c *ClickHouseConfig) Clone() interfaces.Config {
+	clone := *c
+	clone.ClickHouseSpecific.Queries = append([]QueryTemplate(nil), c.ClickHouseSpecific.Queries...)
+	return &clone
+}
+
+// ConnectionConfig接口实现
+
+// GetAddresses 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetAddresses() []string {
+	return []string{net.JoinHostPort(c.Address, strconv.Itoa(c.Port))}
+}
+
+// GetCredentials 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetCredentials() map[string]string {
+	return map[string]string{
+		"user":     c.User,
+		"password": c.Password,
+		"database": c.Database,
+	}
+}
+
+// GetPoolConfig 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetPoolConfig() interfaces.PoolConfig {
+	return &PoolConfig{size: c.PoolSize, timeout: c.Timeout}
+}
+
+// GetTimeout 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetTimeout() time.Duration {
+	return c.Timeout
+}
+
+// PoolConfig 连接池配置（连接在启动时全部建立，不做空闲回收）
+type PoolConfig struct {
+	size    int
+	timeout time.Duration
+}
+
+func (p *PoolConfig) GetPoolSize() int                    { return p.size }
+func (p *PoolConfig) GetMinIdle() int                     { return p.size }
+func (p *PoolConfig) GetMaxIdle() int                     { return p.size }
+func (p *PoolConfig) GetIdleTimeout() time.Duration       { return 0 }
+func (p *PoolConfig) GetConnectionTimeout() time.Duration { return p.timeout }
+
+// BenchmarkConfig接口实现
+
+// GetTotal 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTotal() int {
+	return b.Total
+}
+
+// GetParallels 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetParallels() int {
+	return b.Parallels
+}
+
+// GetDataSize 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetDataSize() int {
+	return 0
+}
+
+// GetTTL 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTTL() time.Duration {
+	return 0
+}
+
+// GetReadPercent 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetReadPercent() int {
+	switch b.TestCase {
+	case TestCaseQuery:
+		return 100
+	case TestCaseMixed:
+		return b.ReadPercent
+	}
+	return 0
+}
+
+// GetRandomKeys 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetRandomKeys() int {
+	return 0
+}
+
+// GetTestCase 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTestCase() string {
+	return b.TestCase
+}
+
+// GetDuration 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetDuration() time.Duration {
+	return b.Duration
+}
+
+// GetTimeout 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTimeout() time.Duration {
+	return 0
+}
+
+// GetRampUp 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetRampUp() time.Duration {
+	return 0
+}
@@ -0,0 +1,72 @@
+package config
+
+import "testing"
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*ClickHouseConfig)
+		valid  bool
+	}{
+		{"default", func(c *ClickHouseConfig) {}, true},
+		{"query", func(c *ClickHouseConfig) { c.BenchMark.TestCase = TestCaseQuery }, true},
+		{"no compression", func(c *ClickHouseConfig) { c.Connection.Compression = CompressionNone }, true},
+		{"custom queries", func(c *ClickHouseConfig) {
+			c.ClickHouseSpecific.Queries = []QueryTemplate{
+				{Name: "recent", SQL: "SELECT count() FROM {{table}} WHERE ts > now() - {{randInt 60 3600}}", Weight: 3},
+				{Name: "all", SQL: "SELECT count() FROM {{table}}", Weight: 1},
+			}
+		}, true},
+		{"empty address", func(c *ClickHouseConfig) { c.Connection.Address = "" }, false},
+		{"invalid port", func(c *ClickHouseConfig) { c.Connection.Port = 70000 }, false},
+		{"unknown compression", func(c *ClickHouseConfig) { c.Connection.Compression = "zstd" }, false},
+		{"unknown test case", func(c *ClickHouseConfig) { c.BenchMark.TestCase = "select" }, false},
+		{"read percent", func(c *ClickHouseConfig) { c.BenchMark.ReadPercent = 101 }, false},
+		{"zero batch", func(c *ClickHouseConfig) { c.ClickHouseSpecific.BatchSize = 0 }, false},
+		{"zero users", func(c *ClickHouseConfig) { c.ClickHouseSpecific.Users = 0 }, false},
+		{"reserved query name", func(c *ClickHouseConfig) {
+			c.ClickHouseSpecific.Queries = []QueryTemplate{{Name: "insert", SQL: "SELECT 1", Weight: 1}}
+		}, false},
+		{"duplicate query name", func(c *ClickHouseConfig) {
+			c.ClickHouseSpecific.Queries = []QueryTemplate{
+				{Name: "q", SQL: "SELECT 1", Weight: 1},
+				{Name: "q", SQL: "SELECT 2", Weight: 1},
+			}
+		}, false},
+		{"zero weight", func(c *ClickHouseConfig) {
+			c.ClickHouseSpecific.Queries = []QueryTemplate{{Name: "q", SQL: "SELECT 1"}}
+		}, false},
+		{"invalid template", func(c *ClickHouseConfig) {
+			c.ClickHouseSpecific.Queries = []QueryTemplate{{Name: "q", SQL: "SELECT {{randInt", Weight: 1}}
+		}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := NewDefaultClickHouseConfig()
+			test.modify(cfg)
+			if err := cfg.Validate(); (err == nil) != test.valid {
+				t.Errorf("Validate() error = %v, want valid %v", err, test.valid)
+			}
+		})
+	}
+}
+
+func TestReadPercent(t *testing.T) {
+	cfg := NewDefaultClickHouseConfig()
+	for testCase, want := range map[string]int{TestCaseInsert: 0, TestCaseQuery: 100, TestCaseMixed: 20} {
+		cfg.BenchMark.TestCase = testCase
+		if got := cfg.BenchMark.GetReadPercent(); got != want {
+			t.Errorf("%s: GetReadPercent() = %d, want %d", testCase, got, want)
+		}
+		if queries := cfg.BenchMark.RunsQueries(); queries != (want > 0) {
+			t.Errorf("%s: RunsQueries() = %v", testCase, queries)
+		}
+	}
+}
+
+func TestExpand(t *testing.T) {
+	specific := ClickHouseSpecificConfig{Table: "db.events"}
+	if sql := specific.Expand("SELECT count() FROM {{table}} AS a JOIN {{table}} AS b USING id"); sql != "SELECT count() FROM db.events AS a JOIN db.events AS b USING id" {
+		t.Errorf("Expand() = %q", sql)
+	}
+}
@@ -0,0 +1,280 @@
+package connection
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/bits"
+
+	"github.com/pierrec/lz4/v4"
+)
+
+// 压缩帧的方法字节
+const (
+	methodNone = 0x02
+	methodLZ4  = 0x82
+)
+
+// 压缩帧：16字节CityHash128校验和 + 9字节头（方法、含头的压缩大小、原始大小）+ 压缩数据
+const (
+	checksumSize     = 16
+	frameHeaderSize  = 9
+	maxFrameDataSize = 1 << 30
+)
+
+// CompressFrame 将数据编码为一个压缩帧，LZ4无法缩小数据时使用不压缩的方法
+func CompressFrame(data []byte) []byte {
+	frame := make([]byte, checksumSize+frameHeaderSize+lz4.CompressBlockBound(len(data)))
+	payload := frame[checksumSize+frameHeaderSize:]
+	method := byte(methodLZ4)
+	n, err := lz4.CompressBlock(data, payload, nil)
+	if err != nil || n == 0 || n >= len(data) {
+		method = methodNone
+		n = copy(payload, data)
+	}
+	frame = frame[:checksumSize+frameHeaderSize+n]
+
+	header := frame[checksumSize:]
+	header[0] = method
+	binary.LittleEndian.PutUint32(header[1:], uint32(frameHeaderSize+n))
+	binary.LittleEndian.PutUint32(header[5:], uint32(len(data)))
+	low, high := cityHash128(header)
+	binary.LittleEndian.PutUint64(frame, low)
+	binary.LittleEndian.PutUint64(frame[8:], high)
+	return frame
+}
+
+// decompressor 从压缩帧流中读取解压后的数据
+// 一个数据块可能跨越多个帧，帧在读完当前帧后按需读取
+type decompressor struct {
+	src   io.Reader
+	frame []byte
+	data  []byte
+	pos   int
+}
+
+func newDecompressor(src io.Reader) *decompressor {
+	return &decompressor{src: src}
+}
+
+// next 读取并校验下一个帧
+func (d *decompressor) next() error {
+	var head [checksumSize + frameHeaderSize]byte
+	if _, err := io.ReadFull(d.src, head[:]); err != nil {
+		return err
+	}
+	header := head[checksumSize:]
+	size := int(binary.LittleEndian.Uint32(header[1:]))
+	rawSize := int(binary.LittleEndian.Uint32(header[5:]))
+	if size < frameHeaderSize || size > maxFrameDataSize || rawSize > maxFrameDataSize {
+		return fmt.Errorf("invalid compressed frame size %d (%d uncompressed)", size, rawSize)
+	}
+
+	d.frame = append(d.frame[:0], header...)
+	d.frame = append(d.frame, make([]byte, size-frameHeaderSize)...)
+	if _, err := io.ReadFull(d.src, d.frame[frameHeaderSize:]); err != nil {
+		return err
+	}
+	low, high := cityHash128(d.frame)
+	if low != binary.LittleEndian.Uint64(head[:]) || high != binary.LittleEndian.Uint64(head[8:]) {
+		return fmt.Errorf("compressed frame checksum mismatch")
+	}
+
+	payload := d.frame[frameHeaderSize:]
+	switch header[0] {
+	case methodNone:
+		if len(payload) != rawSize {
+			return fmt.Errorf("uncompressed frame size %d, expected %d", len(payload), rawSize)
+		}
+		d.data = append(d.data[:0], payload...)
+	case methodLZ4:
+		if cap(d.data) < rawSize {
+			d.data = make([]byte, rawSize)
+		}
+		d.data = d.data[:rawSize]
+		n, err := lz4.UncompressBlock(payload, d.data)
+		if err != nil {
+			return fmt.Errorf("failed to decompress frame: %w", err)
+		}
+		if n != rawSize {
+			return fmt.Errorf("decompressed %d bytes, expected %d", n, rawSize)
+		}
+	default:
+		return fmt.Errorf("unsupported compression method 0x%02x", header[0])
+	}
+	d.pos = 0
+	return nil
+}
+
+// Read 实现io.Reader
+func (d *decompressor) Read(p []byte) (int, error) {
+	for d.pos >= len(d.data) {
+		if err := d.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.data[d.pos:])
+	d.pos += n
+	return n, nil
+}
+
+// ReadByte 实现io.ByteReader
+func (d *decompressor) ReadByte() (byte, error) {
+	for d.pos >= len(d.data) {
+		if err := d.next(); err != nil {
+			return 0, err
+		}
+	}
+	b := d.data[d.pos]
+	d.pos++
+	return b, nil
+}
+
+// CityHash v1.0.2的128位哈希，ClickHouse以此校验压缩帧（与之后版本的CityHash结果不同）
+
+const (
+	cityK0 uint64 = 0xc3a5c85c97cb3127
+	cityK1 uint64 = 0xb492b66fbe98f273
+	cityK2 uint64 = 0x9ae16a3b2f90404f
+	cityK3 uint64 = 0xc949d7c7509e6557
+)
+
+func fetch64(s []byte) uint64 { return binary.LittleEndian.Uint64(s) }
+func fetch32(s []byte) uint64 { return uint64(binary.LittleEndian.Uint32(s)) }
+
+func rotate(v uint64, shift int) uint64 {
+	if shift == 0 {
+		return v
+	}
+	return bits.RotateLeft64(v, -shift)
+}
+
+func shiftMix(v uint64) uint64 { return v ^ (v >> 47) }
+
+func hashLen16(u, v uint64) uint64 {
+	const mul uint64 = 0x9ddfea08eb382d69
+	a := (u ^ v) * mul
+	a ^= a >> 47
+	b := (v ^ a) * mul
+	b ^= b >> 47
+	return b * mul
+}
+
+func hashLen0to16(s []byte) uint64 {
+	n := uint64(len(s))
+	switch {
+	case n > 8:
+		a := fetch64(s)
+		b := fetch64(s[n-8:])
+		return hashLen16(a, bits.RotateLeft64(b+n, -int(n))) ^ b
+	case n >= 4:
+		a := fetch32(s)
+		return hashLen16(n+(a<<3), fetch32(s[n-4:]))
+	case n > 0:
+		a, b, c := s[0], s[n>>1], s[n-1]
+		y := uint32(a) + uint32(b)<<8
+		z := uint32(n) + uint32(c)<<2
+		return shiftMix(uint64(y)*cityK2^uint64(z)*cityK3) * cityK2
+	}
+	return cityK2
+}
+
+func weakHashLen32WithSeeds(s []byte, a, b uint64) (uint64, uint64) {
+	w, x, y, z := fetch64(s), fetch64(s[8:]), fetch64(s[16:]), fetch64(s[24:])
+	a += w
+	b = rotate(b+a+z, 21)
+	c := a
+	a += x
+	a += y
+	b += rotate(a, 44)
+	return a + z, b + c
+}
+
+func cityMurmur(s []byte, seedLow, seedHigh uint64) (uint64, uint64) {
+	a, b := seedLow, seedHigh
+	var c, d uint64
+	n := len(s)
+	if n <= 16 {
+		a = shiftMix(a*cityK1) * cityK1
+		c = b*cityK1 + hashLen0to16(s)
+		if n >= 8 {
+			d = shiftMix(a + fetch64(s))
+		} else {
+			d = shiftMix(a + c)
+		}
+	} else {
+		c = hashLen16(fetch64(s[n-8:])+cityK1, a)
+		d = hashLen16(b+uint64(n), c+fetch64(s[n-16:]))
+		a += d
+		for l := n - 16; l > 0; l -= 16 {
+			a ^= shiftMix(fetch64(s)*cityK1) * cityK1
+			a *= cityK1
+			b ^= a
+			c ^= shiftMix(fetch64(s[8:])*cityK1) * cityK1
+			c *= cityK1
+			d ^= c
+			s = s[16:]
+		}
+	}
+	a = hashLen16(a, c)
+	b = hashLen16(d, b)
+	return a ^ b, hashLen16(b, a)
+}
+
+func cityHash128WithSeed(s []byte, seedLow, seedHigh uint64) (uint64, uint64) {
+	n := len(s)
+	if n < 128 {
+		return cityMurmur(s, seedLow, seedHigh)
+	}
+
+	x, y := seedLow, seedHigh
+	z := uint64(n) * cityK1
+	var v1, v2, w1, w2 uint64
+	v1 = rotate(y^cityK1, 49)*cityK1 + fetch64(s)
+	v2 = rotate(v1, 42)*cityK1 + fetch64(s[8:])
+	w1 = rotate(y+z, 35)*cityK1 + x
+	w2 = rotate(x+fetch64(s[88:]), 53) * cityK1
+
+	// pos为已处理的字节数；末尾的分组可能回读已处理过的数据，因此不截断s
+	pos := 0
+	for n >= 128 {
+		for i := 0; i < 2; i++ {
+			x = rotate(x+y+v1+fetch64(s[pos+16:]), 37) * cityK1
+			y = rotate(y+v2+fetch64(s[pos+48:]), 42) * cityK1
+			x ^= w2
+			y ^= v1
+			z = rotate(z^w1, 33)
+			v1, v2 = weakHashLen32WithSeeds(s[pos:], v2*cityK1, x+w1)
+			w1, w2 = weakHashLen32WithSeeds(s[pos+32:], z+w2, y)
+			z, x = x, z
+			pos += 64
+		}
+		n -= 128
+	}
+	y += rotate(w1, 37)*cityK0 + z
+	x += rotate(v1+z, 49) * cityK0
+	// 剩余不足128字节时，从末尾起每32字节一组处理
+	for done := 0; done < n; {
+		done += 32
+		y = rotate(y-x, 42)*cityK0 + v2
+		w1 += fetch64(s[pos+n-done+16:])
+		x = rotate(x, 49)*cityK0 + w1
+		w1 += v1
+		v1, v2 = weakHashLen32WithSeeds(s[pos+n-done:], v1, v2)
+	}
+	x = hashLen16(x, v1)
+	y = hashLen16(y, w1)
+	return hashLen16(x+v2, w2) + y, hashLen16(x+w2, y+v2)
+}
+
+// cityHash128 返回哈希的低64位与高64位
+func cityHash128(s []byte) (uint64, uint64) {
+	n := len(s)
+	switch {
+	case n >= 16:
+		return cityHash128WithSeed(s[16:], fetch64(s)^cityK3, fetch64(s[8:]))
+	case n >= 8:
+		return cityHash128WithSeed(nil, fetch64(s)^(uint64(n)*cityK0), fetch64(s[n-8:])^cityK1)
+	}
+	return cityHash128WithSeed(s, cityK0, cityK1)
+}
@@ -0,0 +1,431 @@
+package connection
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"abc-runner/app/adapters/clickhouse/config"
+)
+
+// ErrBadConn 连接在I/O中途失败，不能再复用
+var ErrBadConn = errors.New("clickhouse connection is broken")
+
+// clientName 握手与查询中上报的客户端名称
+const clientName = "abc-runner"
+
+// InsertResult 一次插入的结果
+type InsertResult struct {
+	Rows      int64 // 写入的行数
+	Bytes     int64 // 数据块的原始字节数
+	WireBytes int64 // 数据块在连接上发送的字节数（启用压缩时为压缩后大小）
+}
+
+// QueryResult 一次查询的结果
+type QueryResult struct {
+	Rows      int64 // 返回的行数
+	ReadRows  int64 // 服务端进度报告中扫描的行数
+	ReadBytes int64 // 服务端进度报告中扫描的（未压缩）字节数
+	WireBytes int64 // 从连接上收到的字节数（启用压缩时为压缩后大小）
+}
+
+// Conn 单个ClickHouse原生协议连接
+// 不是并发安全的，由连接池保证同一时刻只有一个使用者
+type Conn struct {
+	conn     net.Conn
+	reader   *bufio.Reader
+	counter  *countingReader
+	timeout  time.Duration
+	compress bool
+
+	revision      uint64 // 双方协议版本的较小者
+	serverName    string
+	serverVersion string
+
+	enc    Encoder
+	broken bool
+}
+
+// countingReader 统计从连接上读到的字节数
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// Dial 建立连接并完成握手与认证
+func Dial(ctx context.Context, cfg *config.ClickHouseConfig) (*Conn, error) {
+	address := cfg.Connection.GetAddresses()[0]
+	dialer := &net.Dialer{Timeout: cfg.Connection.Timeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	if tcp, ok := netConn.(*net.TCPConn); ok {
+		tcp.SetNoDelay(true)
+	}
+	netConn.SetDeadline(time.Now().Add(cfg.Connection.Timeout))
+
+	if cfg.Connection.Secure {
+		tlsConn := tls.Client(netConn, &tls.Config{
+			ServerName:         cfg.Connection.Address,
+			InsecureSkipVerify: cfg.Connection.InsecureSkipVerify,
+		})
+		if err := tlsConn.Handshake(); err != nil {
+			netConn.Close()
+			return nil, fmt.Errorf("TLS handshake failed: %w", err)
+		}
+		netConn = tlsConn
+	}
+
+	c := &Conn{
+		conn:     netConn,
+		timeout:  cfg.Connection.Timeout,
+		compress: cfg.Connection.Compression == config.CompressionLZ4,
+	}
+	c.counter = &countingReader{r: netConn}
+	c.reader = bufio.NewReaderSize(c.counter, 64*1024)
+
+	if err := c.handshake(cfg); err != nil {
+		c.conn.Close()
+		return nil, err
+	}
+	c.conn.SetDeadline(time.Time{})
+	return c, nil
+}
+
+// handshake 发送客户端Hello并读取服务端Hello，认证失败时服务端返回异常
+func (c *Conn) handshake(cfg *config.ClickHouseConfig) error {
+	c.enc.Reset()
+	c.enc.UVarint(ClientHello).Str(clientName).UVarint(1).UVarint(0).UVarint(ClientRevision).
+		Str(cfg.Connection.Database).Str(cfg.Connection.User).Str(cfg.Connection.Password)
+	if err := c.send(); err != nil {
+		return fmt.Errorf("failed to send hello: %w", err)
+	}
+
+	d := NewDecoder(c.reader)
+	switch packet := d.UVarint(); {
+	case d.Err() != nil:
+		return fmt.Errorf("failed to read hello: %w", d.Err())
+	case packet == ServerException:
+		exception := d.Exception()
+		if d.Err() != nil {
+			return d.Err()
+		}
+		return exception
+	case packet != ServerHello:
+		return fmt.Errorf("unexpected packet %d during handshake", packet)
+	}
+
+	name := d.Str()
+	major, minor := d.UVarint(), d.UVarint()
+	c.revision = min(d.UVarint(), ClientRevision)
+	var patch uint64
+	if c.revision >= revisionWithServerTimezone {
+		d.Str()
+	}
+	if c.revision >= revisionWithServerDisplayName {
+		d.Str()
+	}
+	if c.revision >= revisionWithVersionPatch {
+		patch = d.UVarint()
+	}
+	if err := d.Err(); err != nil {
+		return fmt.Errorf("failed to read hello: %w", err)
+	}
+	c.serverName = name
+	c.serverVersion = fmt.Sprintf("%d.%d", major, minor)
+	if patch > 0 {
+		c.serverVersion += "." + strconv.FormatUint(patch, 10)
+	}
+	return nil
+}
+
+// ServerVersion 服务端名称与版本
+func (c *Conn) ServerVersion() string {
+	return c.serverName + " " + c.serverVersion
+}
+
+// SetTimeout 设置单次往返的超时，如写入初始数据等长耗时操作需要放宽
+func (c *Conn) SetTimeout(timeout time.Duration) {
+	c.timeout = timeout
+}
+
+// Broken 连接是否已在I/O中途失败
+func (c *Conn) Broken() bool {
+	return c.broken
+}
+
+// Ping 发送Ping并等待Pong
+func (c *Conn) Ping(ctx context.Context) error {
+	stop, err := c.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	c.enc.Reset()
+	c.enc.UVarint(ClientPing)
+	if err := c.send(); err != nil {
+		return c.fail(ctx, err)
+	}
+	for {
+		d := NewDecoder(c.reader)
+		packet := d.UVarint()
+		switch {
+		case d.Err() != nil:
+			return c.fail(ctx, d.Err())
+		case packet == ServerPong:
+			return nil
+		case packet == ServerProgress:
+			c.readProgress(d)
+		default:
+			return c.fail(ctx, fmt.Errorf("unexpected packet %d in reply to ping", packet))
+		}
+	}
+}
+
+// Insert 以 INSERT INTO table (columns) VALUES 发送数据块
+// 服务端先返回表结构的表头块，列名与类型必须与数据块一致；不一致时发送空块结束插入并返回错误
+func (c *Conn) Insert(ctx context.Context, table string, block *Block) (InsertResult, error) {
+	stop, err := c.begin(ctx)
+	if err != nil {
+		return InsertResult{}, err
+	}
+	defer stop()
+
+	names := make([]string, len(block.Columns))
+	for i, column := range block.Columns {
+		names[i] = column.Name
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES", table, strings.Join(names, ", "))
+	if err := c.sendQuery(query); err != nil {
+		return InsertResult{}, c.fail(ctx, err)
+	}
+
+	header, err := c.receive(ctx, nil)
+	if err != nil {
+		return InsertResult{}, err
+	}
+	if header == nil {
+		return InsertResult{}, c.fail(ctx, fmt.Errorf("server ended the insert without a table header"))
+	}
+	mismatch := checkHeader(header, block)
+
+	result := InsertResult{}
+	c.enc.Reset()
+	if mismatch == nil {
+		result.Rows = int64(block.Rows)
+		result.Bytes, result.WireBytes = c.appendData(block)
+	}
+	c.appendData(&Block{})
+	if err := c.send(); err != nil {
+		return InsertResult{}, c.fail(ctx, err)
+	}
+	if _, err := c.receive(ctx, nil); err != nil {
+		return InsertResult{}, err
+	}
+	if mismatch != nil {
+		return InsertResult{}, mismatch
+	}
+	return result, nil
+}
+
+// checkHeader 检查数据块的列与表头一致
+func checkHeader(header, block *Block) error {
+	types := make(map[string]string, len(header.Columns))
+	for _, column := range header.Columns {
+		types[column.Name] = column.Type
+	}
+	for _, column := range block.Columns {
+		typ, ok := types[column.Name]
+		if !ok {
+			return fmt.Errorf("table has no column %s", column.Name)
+		}
+		if typ != column.Type {
+			return fmt.Errorf("column %s has type %s, expected %s", column.Name, typ, column.Type)
+		}
+	}
+	return nil
+}
+
+// Query 执行查询并读完全部结果，只统计行数不保留数据
+func (c *Conn) Query(ctx context.Context, query string) (QueryResult, error) {
+	stop, err := c.begin(ctx)
+	if err != nil {
+		return QueryResult{}, err
+	}
+	defer stop()
+
+	received := c.counter.n
+	if err := c.sendQuery(query); err != nil {
+		return QueryResult{}, c.fail(ctx, err)
+	}
+	var result QueryResult
+	_, err = c.receive(ctx, &result)
+	result.WireBytes = c.counter.n - received
+	return result, err
+}
+
+// Exec 执行不返回结果的语句（如DDL）
+func (c *Conn) Exec(ctx context.Context, query string) error {
+	_, err := c.Query(ctx, query)
+	return err
+}
+
+// sendQuery 发送查询数据包与结束外部表的空数据块
+func (c *Conn) sendQuery(query string) error {
+	c.enc.Reset()
+	c.enc.UVarint(ClientQuery).Str("")
+
+	// ClientInfo
+	hostname, _ := os.Hostname()
+	c.enc.UInt8(1).Str("").Str("").Str("[::ffff:127.0.0.1]:0") // 初始查询：user、query_id、address
+	c.enc.UInt8(1).Str(os.Getenv("USER")).Str(hostname).Str(clientName)
+	c.enc.UVarint(1).UVarint(0).UVarint(ClientRevision)
+	if c.revision >= revisionWithQuotaKey {
+		c.enc.Str("")
+	}
+
+	c.enc.Str("") // 设置列表结束
+	compression := uint64(0)
+	if c.compress {
+		compression = 1
+	}
+	c.enc.UVarint(StageComplete).UVarint(compression).Str(query)
+	c.appendData(&Block{})
+	return c.send()
+}
+
+// appendData 追加数据包，返回数据块的原始字节数与发送的字节数
+func (c *Conn) appendData(block *Block) (int64, int64) {
+	c.enc.UVarint(ClientData).Str("")
+	if !c.compress {
+		start := len(c.enc.buf)
+		block.Encode(&c.enc)
+		size := int64(len(c.enc.buf) - start)
+		return size, size
+	}
+	var raw Encoder
+	block.Encode(&raw)
+	frame := CompressFrame(raw.Bytes())
+	c.enc.Raw(frame)
+	return int64(len(raw.Bytes())), int64(len(frame))
+}
+
+// receive 读取数据包直到EndOfStream，或在插入中读到表头块时返回该块
+// result非nil时为查询，累计返回的行数与进度；服务端异常作为错误返回，连接仍可复用
+func (c *Conn) receive(ctx context.Context, result *QueryResult) (*Block, error) {
+	for {
+		d := NewDecoder(c.reader)
+		packet := d.UVarint()
+		if d.Err() != nil {
+			return nil, c.fail(ctx, d.Err())
+		}
+		switch packet {
+		case ServerData, ServerTotals, ServerExtremes:
+			block, err := c.readData(d)
+			if err != nil {
+				return nil, c.fail(ctx, err)
+			}
+			if result == nil {
+				return block, nil
+			}
+			if packet == ServerData {
+				result.Rows += int64(block.Rows)
+			}
+		case ServerProgress:
+			progress := c.readProgress(d)
+			if result != nil {
+				result.ReadRows += int64(progress.Rows)
+				result.ReadBytes += int64(progress.Bytes)
+			}
+		case ServerProfileInfo:
+			d.UVarint() // rows
+			d.UVarint() // blocks
+			d.UVarint() // bytes
+			d.UInt8()   // applied_limit
+			d.UVarint() // rows_before_limit
+			d.UInt8()   // calculated_rows_before_limit
+		case ServerException:
+			exception := d.Exception()
+			if d.Err() != nil {
+				return nil, c.fail(ctx, d.Err())
+			}
+			return nil, exception
+		case ServerEndOfStream:
+			return nil, nil
+		default:
+			return nil, c.fail(ctx, fmt.Errorf("unexpected packet %d", packet))
+		}
+		if d.Err() != nil {
+			return nil, c.fail(ctx, d.Err())
+		}
+	}
+}
+
+// readData 读取数据包的表名与数据块，启用压缩时数据块在压缩帧中
+func (c *Conn) readData(d *Decoder) (*Block, error) {
+	d.Str()
+	if d.Err() != nil {
+		return nil, d.Err()
+	}
+	if c.compress {
+		d = NewDecoder(newDecompressor(c.reader))
+	}
+	block := d.ReadBlock(false)
+	return block, d.Err()
+}
+
+// readProgress 读取进度数据包
+func (c *Conn) readProgress(d *Decoder) Progress {
+	return Progress{Rows: d.UVarint(), Bytes: d.UVarint(), TotalRows: d.UVarint()}
+}
+
+// begin 设置本次往返的截止时间，ctx取消时中断I/O；返回的stop需在往返结束后调用
+func (c *Conn) begin(ctx context.Context) (func() bool, error) {
+	if c.broken {
+		return nil, ErrBadConn
+	}
+	deadline := time.Now().Add(c.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	c.conn.SetDeadline(deadline)
+	return context.AfterFunc(ctx, func() {
+		c.conn.SetDeadline(time.Unix(1, 0))
+	}), nil
+}
+
+// fail 标记连接不可复用，ctx已结束时返回ctx的错误
+func (c *Conn) fail(ctx context.Context, err error) error {
+	c.broken = true
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return fmt.Errorf("%w: %v", ErrBadConn, err)
+}
+
+// send 发送编码器中的数据
+func (c *Conn) send() error {
+	_, err := c.conn.Write(c.enc.Bytes())
+	return err
+}
+
+// Close 关闭连接（原生协议没有结束消息）
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,476 @@
+package connection
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ClientRevision 客户端协议版本
+// 低于54405时服务端将LowCardinality列转换为普通列，低于54406时不发送服务端日志，低于54410时插入不发送列默认值信息
+const ClientRevision = 54213
+
+// 服务端在不同协议版本中增加的字段
+const (
+	revisionWithServerTimezone    = 54058
+	revisionWithQuotaKey          = 54060
+	revisionWithServerDisplayName = 54372
+	revisionWithVersionPatch      = 54401
+)
+
+// 客户端数据包类型
+const (
+	ClientHello  = 0
+	ClientQuery  = 1
+	ClientData   = 2
+	ClientCancel = 3
+	ClientPing   = 4
+)
+
+// 服务端数据包类型
+const (
+	ServerHello       = 0
+	ServerData        = 1
+	ServerException   = 2
+	ServerProgress    = 3
+	ServerPong        = 4
+	ServerEndOfStream = 5
+	ServerProfileInfo = 6
+	ServerTotals      = 7
+	ServerExtremes    = 8
+)
+
+// StageComplete 查询处理到最终结果
+const StageComplete = 2
+
+// maxStringSize 单个字符串的上限，超出视为协议错误
+const maxStringSize = 1 << 30
+
+// Exception 服务端返回的异常，嵌套异常记录在Nested中
+type Exception struct {
+	Code    int32
+	Name    string
+	Message string
+	Nested  *Exception
+}
+
+func (e *Exception) Error() string {
+	return fmt.Sprintf("code %d: %s", e.Code, e.Message)
+}
+
+// Progress 查询进度，服务端多次发送时累加
+type Progress struct {
+	Rows      uint64
+	Bytes     uint64
+	TotalRows uint64
+}
+
+// Encoder 原生协议编码器：VarUInt长度前缀的字符串、小端定长整数
+type Encoder struct {
+	buf []byte
+}
+
+// Bytes 已编码的数据
+func (e *Encoder) Bytes() []byte {
+	return e.buf
+}
+
+// Reset 清空已编码的数据
+func (e *Encoder) Reset() {
+	e.buf = e.buf[:0]
+}
+
+func (e *Encoder) UVarint(v uint64) *Encoder {
+	e.buf = binary.AppendUvarint(e.buf, v)
+	return e
+}
+
+func (e *Encoder) Str(s string) *Encoder {
+	e.buf = binary.AppendUvarint(e.buf, uint64(len(s)))
+	e.buf = append(e.buf, s...)
+	return e
+}
+
+func (e *Encoder) Raw(b []byte) *Encoder {
+	e.buf = append(e.buf, b...)
+	return e
+}
+
+func (e *Encoder) UInt8(v uint8) *Encoder {
+	e.buf = append(e.buf, v)
+	return e
+}
+
+func (e *Encoder) Int32(v int32) *Encoder {
+	e.buf = binary.LittleEndian.AppendUint32(e.buf, uint32(v))
+	return e
+}
+
+func (e *Encoder) UInt32(v uint32) *Encoder {
+	e.buf = binary.LittleEndian.AppendUint32(e.buf, v)
+	return e
+}
+
+func (e *Encoder) UInt64(v uint64) *Encoder {
+	e.buf = binary.LittleEndian.AppendUint64(e.buf, v)
+	return e
+}
+
+func (e *Encoder) Float64(v float64) *Encoder {
+	e.buf = binary.LittleEndian.AppendUint64(e.buf, math.Float64bits(v))
+	return e
+}
+
+// byteReader 解码器的数据来源：连接的缓冲读取器或压缩帧的解压器
+type byteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// Decoder 原生协议解码器，首个错误之后的读取均返回零值，由Err统一检查
+type Decoder struct {
+	r   byteReader
+	err error
+
+	// capture 非nil时记录读取的原始字节，用于保留列数据
+	capture *[]byte
+}
+
+// NewDecoder 创建解码器
+func NewDecoder(r byteReader) *Decoder {
+	return &Decoder{r: r}
+}
+
+// Err 解码过程中的首个错误
+func (d *Decoder) Err() error {
+	return d.err
+}
+
+func (d *Decoder) fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+func (d *Decoder) UVarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	var v uint64
+	for shift := 0; shift < 64; shift += 7 {
+		b, err := d.r.ReadByte()
+		if err != nil {
+			d.fail(err)
+			return 0
+		}
+		if d.capture != nil {
+			*d.capture = append(*d.capture, b)
+		}
+		v |= uint64(b&0x7f) << shift
+		if b < 0x80 {
+			return v
+		}
+	}
+	d.fail(fmt.Errorf("varint overflows 64 bits"))
+	return 0
+}
+
+// Raw 读取n字节
+func (d *Decoder) Raw(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		d.fail(err)
+		return nil
+	}
+	if d.capture != nil {
+		*d.capture = append(*d.capture, b...)
+	}
+	return b
+}
+
+// Skip 跳过n字节
+func (d *Decoder) Skip(n int) {
+	if d.err != nil {
+		return
+	}
+	if d.capture != nil {
+		d.Raw(n)
+		return
+	}
+	if _, err := io.CopyN(io.Discard, d.r, int64(n)); err != nil {
+		d.fail(err)
+	}
+}
+
+func (d *Decoder) Str() string {
+	n := d.UVarint()
+	if n > maxStringSize {
+		d.fail(fmt.Errorf("string length %d exceeds limit", n))
+		return ""
+	}
+	return string(d.Raw(int(n)))
+}
+
+func (d *Decoder) UInt8() uint8 {
+	if b := d.Raw(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (d *Decoder) Int32() int32 {
+	if b := d.Raw(4); b != nil {
+		return int32(binary.LittleEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (d *Decoder) UInt64() uint64 {
+	if b := d.Raw(8); b != nil {
+		return binary.LittleEndian.Uint64(b)
+	}
+	return 0
+}
+
+// Exception 读取异常数据包的内容（不含数据包类型）
+func (d *Decoder) Exception() *Exception {
+	e := &Exception{
+		Code:    d.Int32(),
+		Name:    d.Str(),
+		Message: d.Str(),
+	}
+	d.Str() // stack trace
+	if d.UInt8() != 0 {
+		e.Nested = d.Exception()
+	}
+	return e
+}
+
+// WriteException 编码异常数据包
+func (e *Encoder) WriteException(exception *Exception) *Encoder {
+	e.UVarint(ServerException).Int32(exception.Code).Str(exception.Name).Str(exception.Message).Str("")
+	return e.UInt8(0)
+}
+
+// Column 数据块中的一列，Data为原生格式的列数据（读取时仅在保留数据时填充）
+type Column struct {
+	Name string
+	Type string
+	Data []byte
+}
+
+// Block 数据块：表头的块没有行，空块表示数据结束
+type Block struct {
+	Rows    int
+	Columns []Column
+}
+
+// Encode 编码数据块：BlockInfo、列数、行数，随后每列的名称、类型与数据
+func (b *Block) Encode(e *Encoder) {
+	e.UVarint(1).UInt8(0)  // is_overflows
+	e.UVarint(2).Int32(-1) // bucket_num
+	e.UVarint(0)           // BlockInfo结束
+	e.UVarint(uint64(len(b.Columns))).UVarint(uint64(b.Rows))
+	for _, column := range b.Columns {
+		e.Str(column.Name).Str(column.Type).Raw(column.Data)
+	}
+}
+
+// ReadBlock 读取数据块，keep为false时跳过列数据
+func (d *Decoder) ReadBlock(keep bool) *Block {
+	for {
+		field := d.UVarint()
+		if d.err != nil || field == 0 {
+			break
+		}
+		switch field {
+		case 1:
+			d.UInt8()
+		case 2:
+			d.Int32()
+		default:
+			d.fail(fmt.Errorf("unknown block info field %d", field))
+		}
+	}
+	columns, rows := d.UVarint(), d.UVarint()
+	if d.err != nil {
+		return nil
+	}
+	if columns > 1<<16 || rows > 1<<31 {
+		d.fail(fmt.Errorf("invalid block size: %d columns, %d rows", columns, rows))
+		return nil
+	}
+
+	block := &Block{Rows: int(rows), Columns: make([]Column, columns)}
+	for i := range block.Columns {
+		column := &block.Columns[i]
+		column.Name = d.Str()
+		column.Type = d.Str()
+		if keep {
+			d.capture = &column.Data
+		}
+		d.skipColumn(column.Type, block.Rows)
+		d.capture = nil
+	}
+	if d.err != nil {
+		return nil
+	}
+	return block
+}
+
+// skipColumn 按类型读过rows行的列数据
+func (d *Decoder) skipColumn(typ string, rows int) {
+	if d.err != nil || rows == 0 {
+		return
+	}
+	if width := fixedWidth(typ); width > 0 {
+		d.Skip(width * rows)
+		return
+	}
+
+	name, args := splitType(typ)
+	switch name {
+	case "String":
+		for i := 0; i < rows && d.err == nil; i++ {
+			n := d.UVarint()
+			if n > maxStringSize {
+				d.fail(fmt.Errorf("string length %d exceeds limit", n))
+				return
+			}
+			d.Skip(int(n))
+		}
+	case "FixedString":
+		size, err := strconv.Atoi(firstArg(args))
+		if err != nil {
+			d.fail(fmt.Errorf("invalid column type %s", typ))
+			return
+		}
+		d.Skip(size * rows)
+	case "Nullable":
+		d.Skip(rows) // null map
+		d.skipColumn(firstArg(args), rows)
+	case "Array", "Map":
+		// 每行的结束偏移，最后一个偏移即元素总数
+		var total uint64
+		for i := 0; i < rows; i++ {
+			total = d.UInt64()
+		}
+		if total > 1<<31 {
+			d.fail(fmt.Errorf("invalid %s size %d", name, total))
+			return
+		}
+		for _, element := range args {
+			d.skipColumn(element, int(total))
+		}
+	case "Tuple":
+		for _, element := range args {
+			d.skipColumn(element, rows)
+		}
+	case "SimpleAggregateFunction":
+		if len(args) < 2 {
+			d.fail(fmt.Errorf("invalid column type %s", typ))
+			return
+		}
+		d.skipColumn(args[len(args)-1], rows)
+	case "Nothing":
+		d.Skip(rows)
+	default:
+		d.fail(fmt.Errorf("unsupported column type %s", typ))
+	}
+}
+
+// fixedWidth 定长类型每行的字节数，变长或未知类型返回0
+func fixedWidth(typ string) int {
+	name, args := splitType(typ)
+	switch name {
+	case "UInt8", "Int8", "Bool", "Enum8":
+		return 1
+	case "UInt16", "Int16", "Date", "Enum16":
+		return 2
+	case "UInt32", "Int32", "Float32", "DateTime", "Date32", "IPv4", "Decimal32":
+		return 4
+	case "UInt64", "Int64", "Float64", "DateTime64", "Decimal64", "Interval":
+		return 8
+	case "UInt128", "Int128", "UUID", "IPv6", "Decimal128":
+		return 16
+	case "UInt256", "Int256", "Decimal256":
+		return 32
+	case "Decimal":
+		precision, err := strconv.Atoi(firstArg(args))
+		switch {
+		case err != nil:
+			return 0
+		case precision <= 9:
+			return 4
+		case precision <= 18:
+			return 8
+		case precision <= 38:
+			return 16
+		}
+		return 32
+	}
+	if strings.HasPrefix(name, "Interval") {
+		return 8
+	}
+	return 0
+}
+
+// splitType 拆分类型名与顶层参数，如 Map(String, Array(UInt8)) → Map, [String, Array(UInt8)]
+// Tuple的命名元素（如 Tuple(a UInt8, b String)）只保留类型
+func splitType(typ string) (string, []string) {
+	typ = strings.TrimSpace(typ)
+	open := strings.IndexByte(typ, '(')
+	if open < 0 || !strings.HasSuffix(typ, ")") {
+		return typ, nil
+	}
+	name := typ[:open]
+	inner := typ[open+1 : len(typ)-1]
+
+	var args []string
+	depth, quoted, start := 0, false, 0
+	for i := 0; i < len(inner); i++ {
+		switch c := inner[i]; {
+		case c == '\'' && (i == 0 || inner[i-1] != '\\'):
+			quoted = !quoted
+		case quoted:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			args = append(args, strings.TrimSpace(inner[start:i]))
+			start = i + 1
+		}
+	}
+	args = append(args, strings.TrimSpace(inner[start:]))
+
+	if name == "Tuple" {
+		for i, arg := range args {
+			args[i] = tupleElementType(arg)
+		}
+	}
+	return name, args
+}
+
+// tupleElementType 去掉命名元组元素的名称
+func tupleElementType(arg string) string {
+	space := strings.IndexByte(arg, ' ')
+	paren := strings.IndexByte(arg, '(')
+	if space > 0 && (paren < 0 || space < paren) {
+		return strings.TrimSpace(arg[space+1:])
+	}
+	return arg
+}
+
+func firstArg(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return args[0]
+}
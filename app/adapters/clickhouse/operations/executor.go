@@ -0,0 +1,149 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"abc-runner/app/adapters/clickhouse/config"
+	"abc-runner/app/adapters/clickhouse/connection"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/core/utils"
+)
+
+// TransferStats 插入与查询的数据量，速率由调用方按实际测试时长计算
+type TransferStats struct {
+	InsertedRows    int64 `json:"inserted_rows"`
+	InsertedBytes   int64 `json:"inserted_bytes"`   // 数据块的原始字节数
+	CompressedBytes int64 `json:"compressed_bytes"` // 插入在连接上发送的字节数，未启用压缩时与原始字节数相同
+	ResultRows      int64 `json:"result_rows"`      // 查询返回的行数
+	ReadRows        int64 `json:"read_rows"`        // 查询在服务端扫描的行数
+	ReadBytes       int64 `json:"read_bytes"`       // 查询在服务端扫描的字节数
+	ReceivedBytes   int64 `json:"received_bytes"`   // 查询结果在连接上收到的字节数
+}
+
+// ClickHouseExecutor ClickHouse操作执行器
+type ClickHouseExecutor struct {
+	pool      *connection.Pool
+	rows      *RowGenerator
+	table     string
+	batchSize int
+	timeout   time.Duration
+	pacer     *utils.Pacer
+	tracker   *metrics.OperationTypeTracker
+
+	insertedRows    atomic.Int64
+	insertedBytes   atomic.Int64
+	compressedBytes atomic.Int64
+	resultRows      atomic.Int64
+	readRows        atomic.Int64
+	readBytes       atomic.Int64
+	receivedBytes   atomic.Int64
+}
+
+// NewClickHouseExecutor 创建ClickHouse操作执行器，rate大于0时限制每秒的操作数
+func NewClickHouseExecutor(pool *connection.Pool, cfg *config.ClickHouseConfig, rows *RowGenerator) *ClickHouseExecutor {
+	return &ClickHouseExecutor{
+		pool:      pool,
+		rows:      rows,
+		table:     cfg.ClickHouseSpecific.Table,
+		batchSize: cfg.ClickHouseSpecific.BatchSize,
+		timeout:   cfg.Connection.Timeout,
+		pacer:     utils.NewRatePacer(float64(cfg.BenchMark.Rate)),
+		tracker:   newOperationTracker(),
+	}
+}
+
+// ExecuteOperation 执行一次插入或查询
+// 数据块在计时前生成；延迟包含从连接池取用连接的等待时间
+func (e *ClickHouseExecutor) ExecuteOperation(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	if err := e.pacer.Wait(ctx); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	query, isRead := operation.Params["query"].(string)
+	var block *connection.Block
+	if !isRead {
+		block = e.rows.Block(e.batchSize)
+	}
+
+	startTime := time.Now()
+	var rows, written, read int64
+	conn, err := e.pool.Acquire(ctx)
+	if err == nil {
+		rows, written, read, err = e.run(ctx, conn, query, block)
+		e.pool.Release(conn)
+	}
+	duration := time.Since(startTime)
+
+	e.tracker.Record(operation.Type, rows, false, duration, err)
+	if err != nil {
+		err = fmt.Errorf("%s failed: %w", operation.Type, err)
+	}
+
+	result := &interfaces.OperationResult{
+		Success:      err == nil,
+		Duration:     duration,
+		IsRead:       isRead,
+		Error:        err,
+		Value:        rows,
+		BytesRead:    read,
+		BytesWritten: written,
+		Metadata: map[string]interface{}{
+			"protocol":       "clickhouse",
+			"operation_type": operation.Type,
+			"rows":           rows,
+		},
+	}
+	for k, v := range operation.Metadata {
+		result.Metadata[k] = v
+	}
+	return result, err
+}
+
+// run 在连接上执行插入（block非nil）或查询，返回写入或返回的行数，以及发送与接收的字节数：
+// 插入发送的是数据块在线路上的字节数（启用压缩时为压缩后），查询发送SQL文本并接收结果数据块
+func (e *ClickHouseExecutor) run(ctx context.Context, conn *connection.Conn, query string, block *connection.Block) (int64, int64, int64, error) {
+	if block != nil {
+		result, err := conn.Insert(ctx, e.table, block)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		e.insertedRows.Add(result.Rows)
+		e.insertedBytes.Add(result.Bytes)
+		e.compressedBytes.Add(result.WireBytes)
+		return result.Rows, result.WireBytes, 0, nil
+	}
+
+	result, err := conn.Query(ctx, query)
+	e.receivedBytes.Add(result.WireBytes)
+	if err != nil {
+		return 0, int64(len(query)), result.WireBytes, err
+	}
+	e.resultRows.Add(result.Rows)
+	e.readRows.Add(result.ReadRows)
+	e.readBytes.Add(result.ReadBytes)
+	return result.Rows, int64(len(query)), result.WireBytes, nil
+}
+
+// OperationStats 获取按操作类型的统计
+func (e *ClickHouseExecutor) OperationStats() []OperationStats {
+	return e.tracker.Stats()
+}
+
+// TransferStats 获取数据量统计
+func (e *ClickHouseExecutor) TransferStats() TransferStats {
+	return TransferStats{
+		InsertedRows:    e.insertedRows.Load(),
+		InsertedBytes:   e.insertedBytes.Load(),
+		CompressedBytes: e.compressedBytes.Load(),
+		ResultRows:      e.resultRows.Load(),
+		ReadRows:        e.readRows.Load(),
+		ReadBytes:       e.readBytes.Load(),
+		ReceivedBytes:   e.receivedBytes.Load(),
+	}
+}
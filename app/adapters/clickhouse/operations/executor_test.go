@@ -0,0 +1,419 @@
+package operations
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"abc-runner/app/adapters/clickhouse/config"
+	"abc-runner/app/adapters/clickhouse/connection"
+
+	"github.com/pierrec/lz4/v4"
+)
+
+// fakeServer 实现ClickHouse原生协议最小子集的测试服务端
+// 插入返回tableColumns的表头并统计收到的行；查询先发送进度再返回3行；SQL中含"missing"时返回UNKNOWN_TABLE异常
+type fakeServer struct {
+	listener net.Listener
+
+	// header 插入时返回的表头，nil时使用tableColumns
+	header []connection.Column
+
+	mutex    sync.Mutex
+	inserted int
+	queries  []string
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	server := &fakeServer{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (s *fakeServer) config() *config.ClickHouseConfig {
+	host, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	cfg := config.NewDefaultClickHouseConfig()
+	cfg.Connection.Address = host
+	cfg.Connection.Port, _ = strconv.Atoi(port)
+	cfg.Connection.Timeout = 2 * time.Second
+	cfg.BenchMark.Parallels = 1
+	cfg.ClickHouseSpecific.BatchSize = 50
+	return cfg
+}
+
+func (s *fakeServer) counts() (int, []string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.inserted, append([]string(nil), s.queries...)
+}
+
+func (s *fakeServer) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	d := connection.NewDecoder(reader)
+
+	// Hello：客户端名称、版本、协议版本、数据库、用户、密码
+	if d.UVarint() != connection.ClientHello {
+		return
+	}
+	d.Str()
+	d.UVarint()
+	d.UVarint()
+	d.UVarint()
+	d.Str()
+	d.Str()
+	d.Str()
+	if d.Err() != nil {
+		return
+	}
+	var e connection.Encoder
+	e.UVarint(connection.ServerHello).Str("ClickHouse").UVarint(24).UVarint(8).UVarint(connection.ClientRevision).Str("UTC")
+	conn.Write(e.Bytes())
+
+	for {
+		packet := d.UVarint()
+		if d.Err() != nil {
+			return
+		}
+		e.Reset()
+		switch packet {
+		case connection.ClientPing:
+			e.UVarint(connection.ServerPong)
+		case connection.ClientQuery:
+			query, compress := readQuery(d)
+			// 查询之后是结束外部表的空数据块
+			if d.UVarint() != connection.ClientData || readData(d, reader, compress) == nil {
+				return
+			}
+			s.mutex.Lock()
+			s.queries = append(s.queries, query)
+			s.mutex.Unlock()
+			if !s.handle(conn, &e, d, reader, query, compress) {
+				return
+			}
+		default:
+			return
+		}
+		conn.Write(e.Bytes())
+	}
+}
+
+// handle 处理一条查询，插入时先发送表头，再读取数据块直到空块
+func (s *fakeServer) handle(conn net.Conn, e *connection.Encoder, d *connection.Decoder, reader *bufio.Reader, query string, compress bool) bool {
+	switch {
+	case strings.Contains(query, "missing"):
+		e.WriteException(&connection.Exception{Code: 60, Name: "DB::Exception", Message: "Table default.missing does not exist"})
+		return true
+	case strings.HasPrefix(query, "INSERT INTO"):
+		s.mutex.Lock()
+		header := s.header
+		s.mutex.Unlock()
+		if header == nil {
+			for _, column := range tableColumns {
+				header = append(header, connection.Column{Name: column.name, Type: column.typ})
+			}
+		}
+		writeData(e, &connection.Block{Columns: header}, compress)
+		conn.Write(e.Bytes())
+		e.Reset()
+		for {
+			if d.UVarint() != connection.ClientData {
+				return false
+			}
+			block := readData(d, reader, compress)
+			if block == nil {
+				return false
+			}
+			if len(block.Columns) == 0 {
+				break
+			}
+			s.mutex.Lock()
+			s.inserted += block.Rows
+			s.mutex.Unlock()
+		}
+	default:
+		e.UVarint(connection.ServerProgress).UVarint(100).UVarint(800).UVarint(0)
+		writeData(e, &connection.Block{Rows: 3, Columns: []connection.Column{{Name: "c", Type: "UInt64", Data: make([]byte, 24)}}}, compress)
+	}
+	e.UVarint(connection.ServerEndOfStream)
+	return true
+}
+
+// readQuery 读取查询数据包，返回SQL与是否启用压缩
+func readQuery(d *connection.Decoder) (string, bool) {
+	d.Str() // query_id
+	// ClientInfo
+	d.UInt8()
+	d.Str()
+	d.Str()
+	d.Str()
+	d.UInt8()
+	d.Str()
+	d.Str()
+	d.Str()
+	d.UVarint()
+	d.UVarint()
+	d.UVarint()
+	d.Str() // quota_key
+	for d.Str() != "" && d.Err() == nil {
+	}
+	d.UVarint() // stage
+	compress := d.UVarint() == 1
+	return d.Str(), compress
+}
+
+// readData 读取数据包的表名与数据块，压缩的数据块在单个帧中
+func readData(d *connection.Decoder, reader *bufio.Reader, compress bool) *connection.Block {
+	d.Str()
+	if d.Err() != nil {
+		return nil
+	}
+	if !compress {
+		return d.ReadBlock(true)
+	}
+	data, err := readFrame(reader)
+	if err != nil {
+		return nil
+	}
+	return connection.NewDecoder(bytes.NewReader(data)).ReadBlock(true)
+}
+
+// readFrame 读取压缩帧并解压，不校验校验和
+func readFrame(reader io.Reader) ([]byte, error) {
+	var head [25]byte
+	if _, err := io.ReadFull(reader, head[:]); err != nil {
+		return nil, err
+	}
+	size := binary.LittleEndian.Uint32(head[17:])
+	rawSize := binary.LittleEndian.Uint32(head[21:])
+	payload := make([]byte, size-9)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return nil, err
+	}
+	switch head[16] {
+	case 0x02:
+		return payload, nil
+	case 0x82:
+		data := make([]byte, rawSize)
+		n, err := lz4.UncompressBlock(payload, data)
+		return data[:n], err
+	}
+	return nil, fmt.Errorf("unknown compression method 0x%02x", head[16])
+}
+
+// writeData 编码数据包，启用压缩时数据块放在压缩帧中
+func writeData(e *connection.Encoder, block *connection.Block, compress bool) {
+	e.UVarint(connection.ServerData).Str("")
+	if !compress {
+		block.Encode(e)
+		return
+	}
+	var raw connection.Encoder
+	block.Encode(&raw)
+	e.Raw(connection.CompressFrame(raw.Bytes()))
+}
+
+func newTestExecutor(t *testing.T, cfg *config.ClickHouseConfig) *ClickHouseExecutor {
+	t.Helper()
+	pool, err := connection.NewPool(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	t.Cleanup(func() { pool.Close() })
+	rows := NewRowGenerator(cfg.ClickHouseSpecific.Users, cfg.ClickHouseSpecific.PayloadSize)
+	return NewClickHouseExecutor(pool, cfg, rows)
+}
+
+func findStats(stats []OperationStats, operationType string) OperationStats {
+	for _, entry := range stats {
+		if entry.Type == operationType {
+			return entry
+		}
+	}
+	return OperationStats{}
+}
+
+func TestClickHouseExecutorInsert(t *testing.T) {
+	for _, compression := range []string{config.CompressionLZ4, config.CompressionNone} {
+		server := newFakeServer(t)
+		cfg := server.config()
+		cfg.Connection.Compression = compression
+		executor := newTestExecutor(t, cfg)
+		factory := NewOperationFactory(cfg)
+
+		var written int64
+		for i := 0; i < 3; i++ {
+			result, err := executor.ExecuteOperation(context.Background(), factory.CreateOperation(i, nil))
+			if err != nil {
+				t.Fatalf("%s: insert failed: %v", compression, err)
+			}
+			if result.IsRead || result.Value != int64(50) {
+				t.Fatalf("%s: unexpected result: %+v", compression, result)
+			}
+			if result.BytesWritten == 0 || result.BytesRead != 0 {
+				t.Fatalf("%s: expected written bytes only, got %d written, %d read", compression, result.BytesWritten, result.BytesRead)
+			}
+			written += result.BytesWritten
+		}
+
+		if inserted, _ := server.counts(); inserted != 150 {
+			t.Fatalf("%s: server received %d rows, want 150", compression, inserted)
+		}
+		transfer := executor.TransferStats()
+		if transfer.InsertedRows != 150 || transfer.InsertedBytes == 0 {
+			t.Fatalf("%s: unexpected transfer stats: %+v", compression, transfer)
+		}
+		// 随机payload仍可被LZ4压缩（字符集只有62个字符、数值列高位为0）
+		if compression == config.CompressionLZ4 && transfer.CompressedBytes >= transfer.InsertedBytes {
+			t.Fatalf("lz4: sent %d bytes for %d raw bytes", transfer.CompressedBytes, transfer.InsertedBytes)
+		}
+		if compression == config.CompressionNone && transfer.CompressedBytes != transfer.InsertedBytes {
+			t.Fatalf("none: sent %d bytes for %d raw bytes", transfer.CompressedBytes, transfer.InsertedBytes)
+		}
+		if written != transfer.CompressedBytes {
+			t.Fatalf("%s: results report %d written bytes, %d sent", compression, written, transfer.CompressedBytes)
+		}
+		if stats := findStats(executor.OperationStats(), OperationInsert); stats.Count != 3 || stats.Volume != 150 {
+			t.Fatalf("%s: unexpected insert stats: %+v", compression, stats)
+		}
+	}
+}
+
+func TestClickHouseExecutorQuery(t *testing.T) {
+	for _, compression := range []string{config.CompressionLZ4, config.CompressionNone} {
+		server := newFakeServer(t)
+		cfg := server.config()
+		cfg.Connection.Compression = compression
+		cfg.BenchMark.TestCase = config.TestCaseQuery
+		cfg.ClickHouseSpecific.Queries = []config.QueryTemplate{
+			{Name: "by_user", SQL: "SELECT count() FROM {{table}} WHERE user_id = {{randInt 5 5}}", Weight: 1},
+		}
+		executor := newTestExecutor(t, cfg)
+		factory := NewOperationFactory(cfg)
+
+		var read int64
+		for i := 0; i < 2; i++ {
+			result, err := executor.ExecuteOperation(context.Background(), factory.CreateOperation(i, nil))
+			if err != nil {
+				t.Fatalf("%s: query failed: %v", compression, err)
+			}
+			if !result.IsRead || result.Value != int64(3) {
+				t.Fatalf("%s: unexpected result: %+v", compression, result)
+			}
+			if result.BytesRead == 0 || result.BytesWritten != int64(len("SELECT count() FROM abc_runner_events WHERE user_id = 5")) {
+				t.Fatalf("%s: unexpected transferred bytes: %d read, %d written", compression, result.BytesRead, result.BytesWritten)
+			}
+			read += result.BytesRead
+		}
+
+		_, queries := server.counts()
+		if len(queries) != 2 || queries[0] != "SELECT count() FROM abc_runner_events WHERE user_id = 5" {
+			t.Fatalf("%s: unexpected queries: %q", compression, queries)
+		}
+		transfer := executor.TransferStats()
+		if transfer.ResultRows != 6 || transfer.ReadRows != 200 || transfer.ReadBytes != 1600 || transfer.ReceivedBytes == 0 {
+			t.Fatalf("%s: unexpected transfer stats: %+v", compression, transfer)
+		}
+		if read != transfer.ReceivedBytes {
+			t.Fatalf("%s: results report %d read bytes, %d received", compression, read, transfer.ReceivedBytes)
+		}
+		if stats := findStats(executor.OperationStats(), "by_user"); stats.Count != 2 || stats.Volume != 6 {
+			t.Fatalf("%s: unexpected query stats: %+v", compression, stats)
+		}
+	}
+}
+
+func TestClickHouseExecutorException(t *testing.T) {
+	server := newFakeServer(t)
+	cfg := server.config()
+	cfg.BenchMark.TestCase = config.TestCaseQuery
+	cfg.ClickHouseSpecific.Queries = []config.QueryTemplate{{Name: "missing", SQL: "SELECT * FROM missing", Weight: 1}}
+	executor := newTestExecutor(t, cfg)
+	factory := NewOperationFactory(cfg)
+
+	for i := 0; i < 2; i++ {
+		_, err := executor.ExecuteOperation(context.Background(), factory.CreateOperation(i, nil))
+		var exception *connection.Exception
+		if !errors.As(err, &exception) || exception.Code != 60 {
+			t.Fatalf("expected exception code 60, got %v", err)
+		}
+	}
+
+	// 服务端异常不会使连接失效
+	if stats := executor.pool.Stats(); stats.Reconnects != 0 {
+		t.Fatalf("unexpected reconnects after exceptions: %+v", stats)
+	}
+	stats := findStats(executor.OperationStats(), "missing")
+	if stats.Errors != 2 || stats.ErrorCodes["UNKNOWN_TABLE"] != 2 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestClickHouseInsertHeaderMismatch(t *testing.T) {
+	server := newFakeServer(t)
+	server.header = []connection.Column{{Name: "id", Type: "UInt64"}, {Name: "ts", Type: "DateTime64(3)"}}
+	cfg := server.config()
+	executor := newTestExecutor(t, cfg)
+
+	_, err := executor.ExecuteOperation(context.Background(), NewOperationFactory(cfg).CreateOperation(0, nil))
+	if err == nil || !strings.Contains(err.Error(), "column ts has type DateTime64(3)") {
+		t.Fatalf("expected a column type mismatch, got %v", err)
+	}
+	if inserted, _ := server.counts(); inserted != 0 {
+		t.Fatalf("server received %d rows after a mismatch", inserted)
+	}
+
+	// 插入以空块结束，连接仍可用
+	if stats := executor.pool.Stats(); stats.Reconnects != 0 {
+		t.Fatalf("unexpected reconnects: %+v", stats)
+	}
+	server.mutex.Lock()
+	server.header = nil
+	server.mutex.Unlock()
+	if _, err := executor.ExecuteOperation(context.Background(), NewOperationFactory(cfg).CreateOperation(1, nil)); err != nil {
+		t.Fatalf("insert after mismatch failed: %v", err)
+	}
+}
+
+func TestOperationFactoryMixed(t *testing.T) {
+	cfg := config.NewDefaultClickHouseConfig()
+	cfg.BenchMark.TestCase = config.TestCaseMixed
+	cfg.BenchMark.ReadPercent = 25
+	factory := NewOperationFactory(cfg)
+
+	seen := make(map[string]int)
+	for i := 0; i < 2000; i++ {
+		operation := factory.CreateOperation(i, nil)
+		seen[operation.Type]++
+		if query, ok := operation.Params["query"].(string); ok && strings.Contains(query, "{{") {
+			t.Fatalf("unrendered query: %s", query)
+		}
+	}
+	if seen[OperationInsert] < 1400 || seen[OperationInsert] > 1600 || len(seen) != 5 {
+		t.Fatalf("unexpected mixed distribution: %v", seen)
+	}
+}
@@ -0,0 +1,105 @@
+package operations
+
+import (
+	"math/rand/v2"
+
+	"abc-runner/app/adapters/clickhouse/config"
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/utils"
+)
+
+// OperationInsert 插入一批行的操作类型，查询操作的类型为模板名
+const OperationInsert = "insert"
+
+// queryTemplate 已解析的查询模板
+type queryTemplate struct {
+	name     string
+	weight   int
+	template *utils.PayloadTemplate
+}
+
+// OperationFactory ClickHouse操作工厂
+// 查询按模板权重随机选择，占位符在此按操作编号渲染
+type OperationFactory struct {
+	testCase    string
+	readPercent int
+	queries     []queryTemplate
+	totalWeight int
+}
+
+// NewOperationFactory 创建ClickHouse操作工厂，模板已在配置校验时检查过语法
+func NewOperationFactory(cfg *config.ClickHouseConfig) *OperationFactory {
+	factory := &OperationFactory{
+		testCase:    cfg.BenchMark.TestCase,
+		readPercent: cfg.BenchMark.ReadPercent,
+	}
+	specific := cfg.ClickHouseSpecific
+	for _, query := range specific.GetQueries() {
+		template, err := utils.ParsePayloadTemplate(specific.Expand(query.SQL), nil)
+		if err != nil {
+			continue
+		}
+		factory.queries = append(factory.queries, queryTemplate{name: query.Name, weight: query.Weight, template: template})
+		factory.totalWeight += query.Weight
+	}
+	return factory
+}
+
+// CreateOperation 创建操作
+func (f *OperationFactory) CreateOperation(jobID int, cfg execution.BenchmarkConfig) interfaces.Operation {
+	if !f.isQuery() {
+		return interfaces.Operation{
+			Type:     OperationInsert,
+			Params:   map[string]interface{}{"job_id": jobID},
+			Metadata: map[string]string{"operation_type": OperationInsert},
+		}
+	}
+
+	query := f.pickQuery()
+	return interfaces.Operation{
+		Type: query.name,
+		Params: map[string]interface{}{
+			"job_id": jobID,
+			"query":  query.template.Render(jobID),
+		},
+		Metadata: map[string]string{"operation_type": query.name},
+	}
+}
+
+// isQuery 本次操作是否为查询，mixed用例按read_percent随机
+func (f *OperationFactory) isQuery() bool {
+	switch f.testCase {
+	case config.TestCaseQuery:
+		return len(f.queries) > 0
+	case config.TestCaseMixed:
+		return len(f.queries) > 0 && rand.IntN(100) < f.readPercent
+	}
+	return false
+}
+
+// pickQuery 按权重随机选择查询模板
+func (f *OperationFactory) pickQuery() queryTemplate {
+	n := rand.IntN(f.totalWeight)
+	for _, query := range f.queries {
+		if n < query.weight {
+			return query
+		}
+		n -= query.weight
+	}
+	return f.queries[len(f.queries)-1]
+}
+
+// GetOperationType 获取操作类型
+func (f *OperationFactory) GetOperationType() string {
+	return f.testCase
+}
+
+// GetSupportedOperations 获取支持的操作类型
+func (f *OperationFactory) GetSupportedOperations() []string {
+	operations := []string{OperationInsert}
+	for _, query := range f.queries {
+		operations = append(operations, query.name)
+	}
+	return operations
+}
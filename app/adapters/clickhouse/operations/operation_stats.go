@@ -0,0 +1,49 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"abc-runner/app/adapters/clickhouse/connection"
+	"abc-runner/app/core/metrics"
+)
+
+// OperationStats 单个操作类型的统计，类型为insert或查询模板名，
+// Volume为写入或返回的行数（JSON字段"rows"）；
+// 错误码为ClickHouse的异常名（如"TOO_MANY_PARTS"）、未知异常的"code_<n>"、"timeout"或"connection"
+type OperationStats = metrics.OperationTypeStats
+
+// newOperationTracker 创建按操作类型的统计器
+func newOperationTracker() *metrics.OperationTypeTracker {
+	return metrics.NewOperationTypeTracker(errorCode, "rows", "")
+}
+
+// exceptionNames 压测中常见的异常码与名称（服务端只返回异常类名，不含错误名）
+var exceptionNames = map[int32]string{
+	47:  "UNKNOWN_IDENTIFIER",
+	60:  "UNKNOWN_TABLE",
+	62:  "SYNTAX_ERROR",
+	81:  "UNKNOWN_DATABASE",
+	159: "TIMEOUT_EXCEEDED",
+	202: "TOO_MANY_SIMULTANEOUS_QUERIES",
+	241: "MEMORY_LIMIT_EXCEEDED",
+	252: "TOO_MANY_PARTS",
+	394: "QUERY_WAS_CANCELLED",
+	516: "AUTHENTICATION_FAILED",
+}
+
+// errorCode 错误分类：服务端异常按异常码归类，其余按超时与连接错误归类
+func errorCode(err error) string {
+	var exception *connection.Exception
+	if errors.As(err, &exception) {
+		if name, ok := exceptionNames[exception.Code]; ok {
+			return name
+		}
+		return "code_" + strconv.Itoa(int(exception.Code))
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	return "connection"
+}
@@ -0,0 +1,78 @@
+package operations
+
+import (
+	"encoding/binary"
+	"math"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	"abc-runner/app/adapters/clickhouse/connection"
+)
+
+// 测试表的列，插入时按此顺序发送，服务端表头中的类型必须一致
+var tableColumns = []struct{ name, typ string }{
+	{"id", "UInt64"},
+	{"ts", "DateTime"},
+	{"user_id", "UInt32"},
+	{"event", "String"},
+	{"value", "Float64"},
+	{"payload", "String"},
+}
+
+// events event列的取值
+var events = []string{"view", "click", "add_to_cart", "purchase", "signup", "logout"}
+
+// payloadPoolSize payload取自的随机字符池大小，使压缩率接近真实数据而非重复字符
+const payloadPoolSize = 64 * 1024
+
+// RowGenerator 生成测试表的数据块
+// id全局递增；ts为最近一小时内的随机时间；user_id在[0, users)内均匀分布
+type RowGenerator struct {
+	users       int
+	payloadSize int
+	pool        []byte
+	nextID      atomic.Uint64
+}
+
+// NewRowGenerator 创建数据块生成器
+func NewRowGenerator(users, payloadSize int) *RowGenerator {
+	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	pool := make([]byte, payloadPoolSize+payloadSize)
+	for i := range pool {
+		pool[i] = charset[rand.IntN(len(charset))]
+	}
+	return &RowGenerator{users: users, payloadSize: payloadSize, pool: pool}
+}
+
+// Block 生成rows行的数据块
+func (g *RowGenerator) Block(rows int) *connection.Block {
+	ids := make([]byte, 0, 8*rows)
+	times := make([]byte, 0, 4*rows)
+	users := make([]byte, 0, 4*rows)
+	var names []byte
+	values := make([]byte, 0, 8*rows)
+	payloads := make([]byte, 0, rows*(g.payloadSize+2))
+
+	first := g.nextID.Add(uint64(rows)) - uint64(rows)
+	now := time.Now().Unix()
+	for i := 0; i < rows; i++ {
+		ids = binary.LittleEndian.AppendUint64(ids, first+uint64(i))
+		times = binary.LittleEndian.AppendUint32(times, uint32(now-rand.Int64N(3600)))
+		users = binary.LittleEndian.AppendUint32(users, uint32(rand.IntN(g.users)))
+		event := events[rand.IntN(len(events))]
+		names = binary.AppendUvarint(names, uint64(len(event)))
+		names = append(names, event...)
+		values = binary.LittleEndian.AppendUint64(values, math.Float64bits(rand.Float64()*1000))
+		offset := rand.IntN(payloadPoolSize)
+		payloads = binary.AppendUvarint(payloads, uint64(g.payloadSize))
+		payloads = append(payloads, g.pool[offset:offset+g.payloadSize]...)
+	}
+
+	data := [][]byte{ids, times, users, names, values, payloads}
+	block := &connection.Block{Rows: rows, Columns: make([]connection.Column, len(tableColumns))}
+	for i, column := range tableColumns {
+		block.Columns[i] = connection.Column{Name: column.name, Type: column.typ, Data: data[i]}
+	}
+	return block
+}
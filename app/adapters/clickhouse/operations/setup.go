@@ -0,0 +1,57 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"abc-runner/app/adapters/clickhouse/config"
+	"abc-runner/app/adapters/clickhouse/connection"
+)
+
+// setupTimeout 建表与写入初始数据的超时，写入大量行时远超单次操作的超时
+const setupTimeout = 10 * time.Minute
+
+// createTable 测试表的结构，按(user_id, ts)排序使按用户的查询可以利用主键
+const createTable = `CREATE TABLE IF NOT EXISTS %s (
+	id UInt64,
+	ts DateTime,
+	user_id UInt32,
+	event String,
+	value Float64,
+	payload String
+) ENGINE = MergeTree ORDER BY (user_id, ts)`
+
+// Setup 创建测试表（已存在时跳过）；执行查询的测试中表为空时写入seed_rows行
+// 返回写入的行数
+func Setup(ctx context.Context, conn *connection.Conn, cfg *config.ClickHouseConfig, rows *RowGenerator) (int64, error) {
+	conn.SetTimeout(setupTimeout)
+	defer conn.SetTimeout(cfg.Connection.Timeout)
+
+	specific := cfg.ClickHouseSpecific
+	if err := conn.Exec(ctx, fmt.Sprintf(createTable, specific.Table)); err != nil {
+		return 0, fmt.Errorf("failed to create table %s: %w", specific.Table, err)
+	}
+	if !cfg.BenchMark.RunsQueries() || specific.SeedRows == 0 {
+		return 0, nil
+	}
+
+	existing, err := conn.Query(ctx, fmt.Sprintf("SELECT 1 FROM %s LIMIT 1", specific.Table))
+	if err != nil {
+		return 0, fmt.Errorf("failed to check table %s: %w", specific.Table, err)
+	}
+	if existing.Rows > 0 {
+		return 0, nil
+	}
+
+	var seeded int64
+	for seeded < int64(specific.SeedRows) {
+		batch := min(specific.BatchSize, specific.SeedRows-int(seeded))
+		result, err := conn.Insert(ctx, specific.Table, rows.Block(batch))
+		if err != nil {
+			return seeded, fmt.Errorf("failed to seed table %s: %w", specific.Table, err)
+		}
+		seeded += result.Rows
+	}
+	return seeded, nil
+}
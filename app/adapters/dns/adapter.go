@@ -0,0 +1,161 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"abc-runner/app/adapters/dns/config"
+	"abc-runner/app/adapters/dns/connection"
+	"abc-runner/app/adapters/dns/operations"
+	"abc-runner/app/core/interfaces"
+)
+
+// DNSAdapter DNS协议适配器 - 遵循统一架构模式
+// 职责：查询客户端管理、状态维护、健康检查
+type DNSAdapter struct {
+	config           *config.DNSConfig
+	client           *connection.Client
+	dnsOperations    *operations.DNSExecutor
+	metricsCollector interfaces.DefaultMetricsCollector
+	mu               sync.RWMutex
+	isConnected      bool
+
+	// 统计信息
+	totalOperations  int64
+	failedOperations int64
+}
+
+// NewDNSAdapter 创建DNS适配器
+func NewDNSAdapter(metricsCollector interfaces.DefaultMetricsCollector) *DNSAdapter {
+	return &DNSAdapter{
+		metricsCollector: metricsCollector,
+	}
+}
+
+// Connect 合并查询名称并建立与DNS服务器的连接
+func (d *DNSAdapter) Connect(ctx context.Context, cfg interfaces.Config) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	dnsConfig, ok := cfg.(*config.DNSConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type for DNS adapter: expected *config.DNSConfig, got %T", cfg)
+	}
+
+	if err := dnsConfig.Validate(); err != nil {
+		return fmt.Errorf("config validation failed: %w", err)
+	}
+	if err := dnsConfig.ResolveNames(); err != nil {
+		return err
+	}
+	d.config = dnsConfig
+
+	client, err := connection.NewClient(ctx, dnsConfig)
+	if err != nil {
+		return err
+	}
+	d.client = client
+	d.dnsOperations = operations.NewDNSExecutor(client,
+		operations.NewQueryBuilder(dnsConfig.DNSSpecific), dnsConfig.BenchMark.Rate)
+
+	d.isConnected = true
+	return nil
+}
+
+// Execute 执行操作 - 使用执行器处理
+func (d *DNSAdapter) Execute(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	if !d.isConnected {
+		return nil, fmt.Errorf("adapter not connected")
+	}
+
+	atomic.AddInt64(&d.totalOperations, 1)
+	result, err := d.dnsOperations.ExecuteOperation(ctx, operation)
+	if err != nil {
+		atomic.AddInt64(&d.failedOperations, 1)
+	}
+	return result, err
+}
+
+// Close 关闭连接
+func (d *DNSAdapter) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.client != nil {
+		d.client.Close()
+		d.client = nil
+	}
+	d.isConnected = false
+	return nil
+}
+
+// GetProtocolMetrics 获取协议特定指标
+func (d *DNSAdapter) GetProtocolMetrics() map[string]interface{} {
+	metrics := map[string]interface{}{
+		"protocol":          "dns",
+		"total_operations":  atomic.LoadInt64(&d.totalOperations),
+		"failed_operations": atomic.LoadInt64(&d.failedOperations),
+	}
+
+	if d.config != nil {
+		metrics["target"] = d.config.Connection.GetTarget()
+		metrics["transport"] = d.config.Connection.Transport
+	}
+	if stats := d.GetOperationStats(); stats != nil {
+		metrics["operation_stats"] = stats
+	}
+	if codes := d.GetResponseCodeStats(); codes != nil {
+		metrics["response_codes"] = codes
+	}
+	if d.client != nil {
+		metrics["reconnects"] = d.client.Reconnects()
+	}
+
+	return metrics
+}
+
+// GetOperationStats 获取按记录类型的统计，未连接时返回nil
+func (d *DNSAdapter) GetOperationStats() []operations.OperationStats {
+	if d.dnsOperations == nil {
+		return nil
+	}
+	return d.dnsOperations.OperationStats()
+}
+
+// GetResponseCodeStats 获取按记录类型的响应码分布，未连接时返回nil
+func (d *DNSAdapter) GetResponseCodeStats() []operations.ResponseCodeStats {
+	if d.dnsOperations == nil {
+		return nil
+	}
+	return d.dnsOperations.ResponseCodeStats()
+}
+
+// HealthCheck 健康检查：以第一个名称与记录类型发送一次查询
+// 收到任何响应码的应答都说明服务器可达，SERVFAIL等失败响应在测试中按错误统计
+func (d *DNSAdapter) HealthCheck(ctx context.Context) error {
+	if !d.isConnected {
+		return fmt.Errorf("adapter not connected")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.config.Connection.Timeout)
+	defer cancel()
+	_, err := d.dnsOperations.Query(ctx, d.config.DNSSpecific.Names[0], d.config.DNSSpecific.Types[0])
+	var rcodeErr *operations.RCodeError
+	if err != nil && !errors.As(err, &rcodeErr) {
+		return fmt.Errorf("health check query to %s failed: %w", d.client.Target(), err)
+	}
+	return nil
+}
+
+// GetProtocolName 获取协议名称
+func (d *DNSAdapter) GetProtocolName() string {
+	return "dns"
+}
+
+// GetMetricsCollector 获取指标收集器
+func (d *DNSAdapter) GetMetricsCollector() interfaces.DefaultMetricsCollector {
+	return d.metricsCollector
+}
@@ -0,0 +1,340 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+)
+
+// 传输方式
+const (
+	TransportUDP = "udp"
+	TransportTCP = "tcp"
+	TransportDoT = "dot" // DNS over TLS（RFC 7858）
+	TransportDoH = "doh" // DNS over HTTPS（RFC 8484），POST application/dns-message
+)
+
+// TestCaseQuery 唯一的测试用例：按名称与记录类型轮转发送查询
+const TestCaseQuery = "query"
+
+// SupportedTypes 支持的记录类型
+var SupportedTypes = []string{"A", "AAAA", "SRV", "TXT", "CNAME", "MX", "NS", "PTR", "SOA"}
+
+// DNSConfig DNS协议配置
+type DNSConfig struct {
+	Protocol    string            `yaml:"protocol" json:"protocol"`
+	Connection  ConnectionConfig  `yaml:"connection" json:"connection"`
+	BenchMark   BenchmarkConfig   `yaml:"benchmark" json:"benchmark"`
+	DNSSpecific DNSSpecificConfig `yaml:"dns_specific" json:"dns_specific"`
+}
+
+// ConnectionConfig DNS连接配置
+type ConnectionConfig struct {
+	Address   string        `yaml:"address" json:"address"`     // 服务器地址，doh时不使用
+	Port      int           `yaml:"port" json:"port"`           // 为0时udp/tcp使用53，dot使用853
+	URL       string        `yaml:"url" json:"url"`             // doh的查询地址，如 https://dns.google/dns-query
+	Transport string        `yaml:"transport" json:"transport"` // "udp"、"tcp"、"dot" 或 "doh"
+	Timeout   time.Duration `yaml:"timeout" json:"timeout"`     // 单次查询超时
+	TLS       TLSConfig     `yaml:"tls" json:"tls"`             // dot与doh的TLS配置
+}
+
+// TLSConfig TLS配置
+type TLSConfig struct {
+	CAFile             string `yaml:"ca_file" json:"ca_file"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify" json:"insecure_skip_verify"`
+	ServerName         string `yaml:"server_name" json:"server_name"`
+}
+
+// BenchmarkConfig DNS基准测试配置
+type BenchmarkConfig struct {
+	Total     int           `yaml:"total" json:"total"`
+	Parallels int           `yaml:"parallels" json:"parallels"` // 并发查询者数，udp/tcp/dot每个查询者独占一个连接
+	TestCase  string        `yaml:"test_case" json:"test_case"` // "query"
+	Duration  time.Duration `yaml:"duration" json:"duration"`
+	Rate      int           `yaml:"rate" json:"rate"` // 每秒查询数上限，0表示不限速
+}
+
+// DNSSpecificConfig DNS特定配置
+type DNSSpecificConfig struct {
+	Names     []string `yaml:"names" json:"names"`           // 查询的名称
+	NamesFile string   `yaml:"names_file" json:"names_file"` // 每行一个名称的文件，与names合并；#开头的行为注释
+	Types     []string `yaml:"types" json:"types"`           // 记录类型，各名称按类型轮转查询
+
+	RandomSubdomain  bool `yaml:"random_subdomain" json:"random_subdomain"`   // 在名称前加随机标签，绕过解析器缓存
+	RecursionDesired bool `yaml:"recursion_desired" json:"recursion_desired"` // 设置RD标志
+	UDPSize          int  `yaml:"udp_size" json:"udp_size"`                   // EDNS0通告的UDP负载大小，0表示不携带OPT记录
+}
+
+// NewDefaultDNSConfig 创建默认DNS配置
+func NewDefaultDNSConfig() *DNSConfig {
+	return &DNSConfig{
+		Protocol: "dns",
+		Connection: ConnectionConfig{
+			Address:   "127.0.0.1",
+			Transport: TransportUDP,
+			Timeout:   2 * time.Second,
+		},
+		BenchMark: BenchmarkConfig{
+			Total:     10000,
+			Parallels: 10,
+			TestCase:  TestCaseQuery,
+		},
+		DNSSpecific: DNSSpecificConfig{
+			Names:            []string{"example.com"},
+			Types:            []string{"A"},
+			RecursionDesired: true,
+			UDPSize:          1232,
+		},
+	}
+}
+
+// GetProtocol 实现Config接口
+func (c *DNSConfig) GetProtocol() string {
+	return c.Protocol
+}
+
+// GetConnection 实现Config接口
+func (c *DNSConfig) GetConnection() interfaces.ConnectionConfig {
+	return &c.Connection
+}
+
+// GetBenchmark 实现Config接口
+func (c *DNSConfig) GetBenchmark() interfaces.BenchmarkConfig {
+	return &c.BenchMark
+}
+
+// Validate 实现Config接口
+func (c *DNSConfig) Validate() error {
+	switch c.Connection.Transport {
+	case TransportUDP, TransportTCP, TransportDoT:
+		if c.Connection.Address == "" {
+			return fmt.Errorf("server address is required")
+		}
+		if c.Connection.Port < 0 || c.Connection.Port > 65535 {
+			return fmt.Errorf("invalid port: %d", c.Connection.Port)
+		}
+	case TransportDoH:
+		parsed, err := url.Parse(c.Connection.URL)
+		if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+			return fmt.Errorf("invalid DoH url: %q (expected https://host/dns-query)", c.Connection.URL)
+		}
+	default:
+		return fmt.Errorf("invalid transport: %s, valid options: %s, %s, %s, %s",
+			c.Connection.Transport, TransportUDP, TransportTCP, TransportDoT, TransportDoH)
+	}
+	if c.Connection.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+
+	if c.BenchMark.Total <= 0 {
+		return fmt.Errorf("total operations must be greater than 0")
+	}
+	if c.BenchMark.Parallels <= 0 {
+		return fmt.Errorf("parallel connections must be greater than 0")
+	}
+	if c.BenchMark.TestCase != TestCaseQuery {
+		return fmt.Errorf("invalid test case: %s, valid options: %s", c.BenchMark.TestCase, TestCaseQuery)
+	}
+	if c.BenchMark.Rate < 0 {
+		return fmt.Errorf("rate cannot be negative")
+	}
+
+	specific := c.DNSSpecific
+	if len(specific.Names) == 0 && specific.NamesFile == "" {
+		return fmt.Errorf("at least one query name is required")
+	}
+	for _, name := range specific.Names {
+		if err := validateName(name); err != nil {
+			return err
+		}
+	}
+	if len(specific.Types) == 0 {
+		return fmt.Errorf("at least one record type is required")
+	}
+	for _, recordType := range specific.Types {
+		if !isSupportedType(recordType) {
+			return fmt.Errorf("unsupported record type: %s, valid options: %s", recordType, strings.Join(SupportedTypes, ", "))
+		}
+	}
+	if specific.UDPSize != 0 && (specific.UDPSize < 512 || specific.UDPSize > 65535) {
+		return fmt.Errorf("udp size must be 0 (no EDNS0) or between 512 and 65535")
+	}
+
+	return nil
+}
+
+// validateName 检查名称长度：整个名称不超过253个字符，每个标签1到63个字符
+func validateName(name string) error {
+	trimmed := strings.TrimSuffix(name, ".")
+	if trimmed == "" || len(trimmed) > 253 {
+		return fmt.Errorf("invalid query name: %q", name)
+	}
+	for _, label := range strings.Split(trimmed, ".") {
+		if label == "" || len(label) > 63 {
+			return fmt.Errorf("invalid query name: %q (labels must be 1-63 characters)", name)
+		}
+	}
+	return nil
+}
+
+// isSupportedType 记录类型是否受支持
+func isSupportedType(recordType string) bool {
+	for _, supported := range SupportedTypes {
+		if recordType == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveNames 将names_file中的名称合并到names并清空names_file，重复调用不会重复读取
+func (c *DNSConfig) ResolveNames() error {
+	if c.DNSSpecific.NamesFile == "" {
+		return nil
+	}
+
+	file, err := os.Open(c.DNSSpecific.NamesFile)
+	if err != nil {
+		return fmt.Errorf("failed to open names file: %w", err)
+	}
+	defer file.Close()
+	var names []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		name := strings.TrimSpace(scanner.Text())
+		if name == "" || strings.HasPrefix(name, "#") {
+			continue
+		}
+		if err := validateName(name); err != nil {
+			return fmt.Errorf("%s: %w", c.DNSSpecific.NamesFile, err)
+		}
+		names = append(names, name)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read names file: %w", err)
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("names file %s contains no names", c.DNSSpecific.NamesFile)
+	}
+	c.DNSSpecific.Names = append(c.DNSSpecific.Names, names...)
+	c.DNSSpecific.NamesFile = ""
+	return nil
+}
+
+// GetPort 获取端口，未设置时按传输方式取默认值
+func (c *ConnectionConfig) GetPort() int {
+	if c.Port > 0 {
+		return c.Port
+	}
+	if c.Transport == TransportDoT {
+		return 853
+	}
+	return 53
+}
+
+// GetTarget 获取目标描述，如 8.8.8.8:53/udp 或 DoH地址
+func (c *ConnectionConfig) GetTarget() string {
+	if c.Transport == TransportDoH {
+		return c.URL
+	}
+	return fmt.Sprintf("%s/%s", c.GetAddresses()[0], c.Transport)
+}
+
+// Clone 实现Config接口
+func (c *DNSConfig) Clone() interfaces.Config {
+	clone := *c
+	clone.DNSSpecific.Names = append([]string(nil), c.DNSSpecific.Names...)
+	clone.DNSSpecific.Types = append([]string(nil), c.DNSSpecific.Types...)
+	return &clone
+}
+
+// ConnectionConfig接口实现
+
+// GetAddresses 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetAddresses() []string {
+	if c.Transport == TransportDoH {
+		return []string{c.URL}
+	}
+	return []string{fmt.Sprintf("%s:%d", c.Address, c.GetPort())}
+}
+
+// GetCredentials 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetCredentials() map[string]string {
+	return map[string]string{}
+}
+
+// GetPoolConfig 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetPoolConfig() interfaces.PoolConfig {
+	return &EmptyPoolConfig{}
+}
+
+// GetTimeout 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetTimeout() time.Duration {
+	return c.Timeout
+}
+
+// EmptyPoolConfig 空的连接池配置（连接数由并发数决定）
+type EmptyPoolConfig struct{}
+
+func (p *EmptyPoolConfig) GetPoolSize() int                    { return 0 }
+func (p *EmptyPoolConfig) GetMinIdle() int                     { return 0 }
+func (p *EmptyPoolConfig) GetMaxIdle() int                     { return 0 }
+func (p *EmptyPoolConfig) GetIdleTimeout() time.Duration       { return 0 }
+func (p *EmptyPoolConfig) GetConnectionTimeout() time.Duration { return 0 }
+
+// BenchmarkConfig接口实现
+
+// GetTotal 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTotal() int {
+	return b.Total
+}
+
+// GetParallels 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetParallels() int {
+	return b.Parallels
+}
+
+// GetDataSize 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetDataSize() int {
+	return 0
+}
+
+// GetTTL 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTTL() time.Duration {
+	return 0
+}
+
+// GetReadPercent 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetReadPercent() int {
+	return 100
+}
+
+// GetRandomKeys 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetRandomKeys() int {
+	return 0
+}
+
+// GetTestCase 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTestCase() string {
+	return b.TestCase
+}
+
+// GetDuration 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetDuration() time.Duration {
+	return b.Duration
+}
+
+// GetTimeout 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTimeout() time.Duration {
+	return 0
+}
+
+// GetRampUp 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetRampUp() time.Duration {
+	return 0
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*DNSConfig)
+		valid  bool
+	}{
+		{"default", func(c *DNSConfig) {}, true},
+		{"dot", func(c *DNSConfig) { c.Connection.Transport = TransportDoT }, true},
+		{"doh", func(c *DNSConfig) {
+			c.Connection.Transport = TransportDoH
+			c.Connection.URL = "https://dns.example/dns-query"
+		}, true},
+		{"names file only", func(c *DNSConfig) {
+			c.DNSSpecific.Names = nil
+			c.DNSSpecific.NamesFile = "names.txt"
+		}, true},
+		{"no edns", func(c *DNSConfig) { c.DNSSpecific.UDPSize = 0 }, true},
+		{"invalid transport", func(c *DNSConfig) { c.Connection.Transport = "quic" }, false},
+		{"doh without https", func(c *DNSConfig) {
+			c.Connection.Transport = TransportDoH
+			c.Connection.URL = "http://dns.example/dns-query"
+		}, false},
+		{"no names", func(c *DNSConfig) { c.DNSSpecific.Names = nil }, false},
+		{"empty label", func(c *DNSConfig) { c.DNSSpecific.Names = []string{"a..example.com"} }, false},
+		{"long label", func(c *DNSConfig) {
+			c.DNSSpecific.Names = []string{strings.Repeat("a", 64) + ".example.com"}
+		}, false},
+		{"unsupported type", func(c *DNSConfig) { c.DNSSpecific.Types = []string{"A", "ANY"} }, false},
+		{"udp size too small", func(c *DNSConfig) { c.DNSSpecific.UDPSize = 100 }, false},
+		{"negative rate", func(c *DNSConfig) { c.BenchMark.Rate = -1 }, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := NewDefaultDNSConfig()
+			test.modify(cfg)
+			if err := cfg.Validate(); (err == nil) != test.valid {
+				t.Errorf("Validate() error = %v, want valid %v", err, test.valid)
+			}
+		})
+	}
+}
+
+func TestGetPort(t *testing.T) {
+	cfg := NewDefaultDNSConfig()
+	if port := cfg.Connection.GetPort(); port != 53 {
+		t.Errorf("udp port = %d, want 53", port)
+	}
+	cfg.Connection.Transport = TransportDoT
+	if port := cfg.Connection.GetPort(); port != 853 {
+		t.Errorf("dot port = %d, want 853", port)
+	}
+	cfg.Connection.Port = 5353
+	if target := cfg.Connection.GetTarget(); target != "127.0.0.1:5353/dot" {
+		t.Errorf("target = %q", target)
+	}
+}
+
+func TestResolveNames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "names.txt")
+	if err := os.WriteFile(path, []byte("# zone\nfoo.example.com\n\n  bar.example.com  \n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := NewDefaultDNSConfig()
+	cfg.DNSSpecific.NamesFile = path
+	if err := cfg.ResolveNames(); err != nil {
+		t.Fatalf("ResolveNames() error = %v", err)
+	}
+	// 重复调用不会再次读取文件
+	if err := cfg.ResolveNames(); err != nil {
+		t.Fatalf("second ResolveNames() error = %v", err)
+	}
+	want := []string{"example.com", "foo.example.com", "bar.example.com"}
+	if !reflect.DeepEqual(cfg.DNSSpecific.Names, want) {
+		t.Errorf("names = %v, want %v", cfg.DNSSpecific.Names, want)
+	}
+
+	os.WriteFile(path, []byte("# nothing\n"), 0o644)
+	cfg = NewDefaultDNSConfig()
+	cfg.DNSSpecific.NamesFile = path
+	if err := cfg.ResolveNames(); err == nil {
+		t.Error("expected error for a names file without names")
+	}
+}
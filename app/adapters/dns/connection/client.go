@@ -0,0 +1,332 @@
+package connection
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"abc-runner/app/adapters/dns/config"
+)
+
+// maxMessageSize DNS消息的最大长度（TCP长度前缀为16位）
+const maxMessageSize = 65535
+
+// dnsMessageType RFC 8484定义的媒体类型
+const dnsMessageType = "application/dns-message"
+
+// ErrIDMismatch TCP/DoT响应的ID与查询不符，连接上的请求与响应已错位
+var ErrIDMismatch = errors.New("response id does not match query")
+
+// HTTPStatusError DoH服务端返回的非200响应
+type HTTPStatusError struct {
+	StatusCode int
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("DoH server returned HTTP %d", e.StatusCode)
+}
+
+// Client DNS查询客户端
+// udp/tcp/dot维护与并发数相同的连接，每次查询独占一个连接；连接按需建立，失效后丢弃并在下次查询时重建。
+// doh使用共享的HTTP客户端，空闲连接数与并发数相同
+type Client struct {
+	address   string
+	url       string
+	transport string
+	timeout   time.Duration
+	tlsConfig *tls.Config
+	http      *http.Client
+
+	conns      chan net.Conn // nil表示需要重新建立连接
+	mutex      sync.Mutex
+	all        map[net.Conn]struct{}
+	reconnects atomic.Int64
+	closeOnce  sync.Once
+}
+
+// NewClient 创建查询客户端并建立全部连接
+func NewClient(ctx context.Context, cfg *config.DNSConfig) (*Client, error) {
+	client := &Client{
+		address:   cfg.Connection.GetAddresses()[0],
+		url:       cfg.Connection.URL,
+		transport: cfg.Connection.Transport,
+		timeout:   cfg.Connection.Timeout,
+		conns:     make(chan net.Conn, cfg.BenchMark.Parallels),
+		all:       make(map[net.Conn]struct{}),
+	}
+
+	if client.transport == config.TransportDoT || client.transport == config.TransportDoH {
+		tlsConfig, err := buildTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		client.tlsConfig = tlsConfig
+	}
+
+	if client.transport == config.TransportDoH {
+		client.http = &http.Client{
+			Timeout: cfg.Connection.Timeout,
+			Transport: &http.Transport{
+				Proxy:               http.ProxyFromEnvironment,
+				TLSClientConfig:     client.tlsConfig,
+				ForceAttemptHTTP2:   true,
+				MaxIdleConnsPerHost: cfg.BenchMark.Parallels,
+				IdleConnTimeout:     90 * time.Second,
+			},
+		}
+		return client, nil
+	}
+
+	for i := 0; i < cfg.BenchMark.Parallels; i++ {
+		conn, err := client.dial(ctx)
+		if err != nil {
+			client.Close()
+			return nil, err
+		}
+		client.conns <- conn
+	}
+	return client, nil
+}
+
+// buildTLSConfig 构建TLS客户端配置，dot未指定server_name时使用服务器地址
+func buildTLSConfig(cfg *config.DNSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         cfg.Connection.TLS.ServerName,
+		InsecureSkipVerify: cfg.Connection.TLS.InsecureSkipVerify,
+	}
+	if tlsConfig.ServerName == "" && cfg.Connection.Transport == config.TransportDoT {
+		tlsConfig.ServerName = cfg.Connection.Address
+	}
+	if cfg.Connection.TLS.CAFile != "" {
+		pem, err := os.ReadFile(cfg.Connection.TLS.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", cfg.Connection.TLS.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
+
+// dial 建立单个连接
+func (c *Client) dial(ctx context.Context) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: c.timeout}
+	var conn net.Conn
+	var err error
+	switch c.transport {
+	case config.TransportDoT:
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: c.tlsConfig}
+		conn, err = tlsDialer.DialContext(ctx, "tcp", c.address)
+	default:
+		conn, err = dialer.DialContext(ctx, c.transport, c.address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to DNS server %s over %s: %w", c.address, c.transport, err)
+	}
+
+	c.mutex.Lock()
+	c.all[conn] = struct{}{}
+	c.mutex.Unlock()
+	return conn, nil
+}
+
+// discard 关闭并移除失效连接
+func (c *Client) discard(conn net.Conn) {
+	c.mutex.Lock()
+	delete(c.all, conn)
+	c.mutex.Unlock()
+	conn.Close()
+}
+
+// Target 查询目标描述
+func (c *Client) Target() string {
+	if c.transport == config.TransportDoH {
+		return c.url
+	}
+	return c.address + "/" + c.transport
+}
+
+// Reconnects 连接失效后的重连次数
+func (c *Client) Reconnects() int64 {
+	return c.reconnects.Load()
+}
+
+// Exchange 发送一条查询并返回响应报文
+func (c *Client) Exchange(ctx context.Context, query []byte) ([]byte, error) {
+	if c.transport == config.TransportDoH {
+		return c.exchangeHTTPS(ctx, query)
+	}
+
+	var conn net.Conn
+	select {
+	case conn = <-c.conns:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	response, conn, err := c.exchangeConn(ctx, conn, query)
+	c.conns <- conn
+	return response, err
+}
+
+// exchangeConn 在独占的连接上完成一次查询，返回放回连接池的连接（失效时为nil）
+// tcp/dot连接被对端关闭（如服务端的空闲超时）时重连并重试一次；超时不重试，以免放大慢查询
+func (c *Client) exchangeConn(ctx context.Context, conn net.Conn, query []byte) ([]byte, net.Conn, error) {
+	reused := conn != nil
+	if conn == nil {
+		var err error
+		if conn, err = c.dial(ctx); err != nil {
+			return nil, nil, err
+		}
+		c.reconnects.Add(1)
+	}
+
+	response, err := c.roundTrip(ctx, conn, query)
+	if err == nil {
+		return response, conn, nil
+	}
+	if c.transport == config.TransportUDP {
+		// UDP套接字无状态，迟到的响应在下次读取时按ID跳过
+		return nil, conn, err
+	}
+
+	c.discard(conn)
+	if !reused || isTimeout(err) || errors.Is(err, ErrIDMismatch) || ctx.Err() != nil {
+		return nil, nil, err
+	}
+	conn, dialErr := c.dial(ctx)
+	if dialErr != nil {
+		return nil, nil, fmt.Errorf("%v; reconnect failed: %w", err, dialErr)
+	}
+	c.reconnects.Add(1)
+	if response, err = c.roundTrip(ctx, conn, query); err != nil {
+		c.discard(conn)
+		return nil, nil, err
+	}
+	return response, conn, nil
+}
+
+// roundTrip 带超时写入查询并读取响应
+func (c *Client) roundTrip(ctx context.Context, conn net.Conn, query []byte) ([]byte, error) {
+	deadline := time.Now().Add(c.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetDeadline(deadline)
+
+	if c.transport == config.TransportUDP {
+		return exchangeUDP(conn, query)
+	}
+	return exchangeStream(conn, query)
+}
+
+// exchangeUDP 发送一个报文并读取ID相同的响应，跳过此前超时查询的迟到响应
+func exchangeUDP(conn net.Conn, query []byte) ([]byte, error) {
+	if _, err := conn.Write(query); err != nil {
+		return nil, err
+	}
+	buffer := make([]byte, maxMessageSize)
+	for {
+		n, err := conn.Read(buffer)
+		if err != nil {
+			return nil, err
+		}
+		if n >= 2 && bytes.Equal(buffer[:2], query[:2]) {
+			return buffer[:n], nil
+		}
+	}
+}
+
+// exchangeStream 按RFC 1035 4.2.2以两字节长度前缀收发一条消息
+func exchangeStream(conn net.Conn, query []byte) ([]byte, error) {
+	frame := make([]byte, 2+len(query))
+	binary.BigEndian.PutUint16(frame, uint16(len(query)))
+	copy(frame[2:], query)
+	if _, err := conn.Write(frame); err != nil {
+		return nil, err
+	}
+
+	var length [2]byte
+	if _, err := io.ReadFull(conn, length[:]); err != nil {
+		return nil, err
+	}
+	response := make([]byte, binary.BigEndian.Uint16(length[:]))
+	if _, err := io.ReadFull(conn, response); err != nil {
+		return nil, err
+	}
+	if len(response) < 2 || !bytes.Equal(response[:2], query[:2]) {
+		return nil, ErrIDMismatch
+	}
+	return response, nil
+}
+
+// exchangeHTTPS 按RFC 8484以POST发送查询，查询ID置0以便HTTP缓存
+func (c *Client) exchangeHTTPS(ctx context.Context, query []byte) ([]byte, error) {
+	body := append([]byte(nil), query...)
+	body[0], body[1] = 0, 0
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", dnsMessageType)
+	request.Header.Set("Accept", dnsMessageType)
+
+	response, err := c.http.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, io.LimitReader(response.Body, maxMessageSize))
+		return nil, &HTTPStatusError{StatusCode: response.StatusCode}
+	}
+	message, err := io.ReadAll(io.LimitReader(response.Body, maxMessageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(message) > maxMessageSize {
+		return nil, fmt.Errorf("DoH response exceeds %d bytes", maxMessageSize)
+	}
+	if len(message) >= 2 {
+		// 还原查询ID，调用方按常规方式校验
+		message[0], message[1] = query[0], query[1]
+	}
+	return message, nil
+}
+
+// isTimeout 是否为超时错误
+func isTimeout(err error) bool {
+	var timeoutErr interface{ Timeout() bool }
+	return errors.As(err, &timeoutErr) && timeoutErr.Timeout()
+}
+
+// Close 关闭所有连接
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		if c.http != nil {
+			c.http.CloseIdleConnections()
+		}
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		for conn := range c.all {
+			conn.Close()
+		}
+		c.all = make(map[net.Conn]struct{})
+	})
+	return nil
+}
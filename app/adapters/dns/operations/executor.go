@@ -0,0 +1,146 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	"abc-runner/app/adapters/dns/connection"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/core/utils"
+)
+
+// ResponseCodeStats 单个记录类型的响应码分布，只统计收到并成功解析的响应
+type ResponseCodeStats struct {
+	Type      string           `json:"type"`
+	Responses int64            `json:"responses"`
+	Codes     map[string]int64 `json:"codes"`
+}
+
+// DNSExecutor DNS查询执行器
+type DNSExecutor struct {
+	client  *connection.Client
+	builder *QueryBuilder
+	pacer   *utils.Pacer
+
+	tracker *metrics.OperationTypeTracker
+	mutex   sync.Mutex
+	rcodes  map[string]map[string]int64 // 记录类型 -> 响应码 -> 次数
+}
+
+// NewDNSExecutor 创建DNS查询执行器，rate大于0时限制每秒发送的查询数
+func NewDNSExecutor(client *connection.Client, builder *QueryBuilder, rate int) *DNSExecutor {
+	return &DNSExecutor{
+		client:  client,
+		builder: builder,
+		pacer:   utils.NewRatePacer(float64(rate)),
+		tracker: newOperationTracker(),
+		rcodes:  make(map[string]map[string]int64),
+	}
+}
+
+// ExecuteOperation 执行一次查询，操作类型为记录类型，Key为查询名称
+func (e *DNSExecutor) ExecuteOperation(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	if err := e.pacer.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	startTime := time.Now()
+	response, err := e.Query(ctx, operation.Key, operation.Type)
+	duration := time.Since(startTime)
+
+	var answers int64
+	var truncated bool
+	if response != nil {
+		answers, truncated = int64(response.Answers), response.Truncated
+		e.recordRCode(operation.Type, response.RCode)
+	}
+	e.tracker.Record(operation.Type, answers, truncated, duration, err)
+	if err != nil {
+		err = fmt.Errorf("%s %s failed: %w", operation.Type, operation.Key, err)
+	}
+
+	result := &interfaces.OperationResult{
+		Success:  err == nil,
+		Duration: duration,
+		IsRead:   true,
+		Error:    err,
+		Value:    answers,
+		Metadata: map[string]interface{}{
+			"protocol":       "dns",
+			"operation_type": operation.Type,
+			"name":           operation.Key,
+			"answers":        answers,
+			"truncated":      truncated,
+		},
+	}
+	if response != nil {
+		result.BytesRead, result.BytesWritten = int64(response.Size), int64(response.QuerySize)
+		result.Metadata["rcode"] = response.RCode
+	}
+	for k, v := range operation.Metadata {
+		result.Metadata[k] = v
+	}
+	return result, err
+}
+
+// Query 发送一条查询并解析响应
+// 收到响应时即使响应码表示失败也返回解析结果，便于统计响应码分布
+func (e *DNSExecutor) Query(ctx context.Context, name, recordType string) (*Response, error) {
+	query, err := e.builder.Build(uint16(rand.Uint32()), name, recordType)
+	if err != nil {
+		return nil, err
+	}
+	message, err := e.client.Exchange(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	response, err := ParseResponse(message)
+	if err != nil {
+		return nil, err
+	}
+	response.QuerySize, response.Size = len(query), len(message)
+	if response.RCode != "NOERROR" && response.RCode != "NXDOMAIN" {
+		return response, &RCodeError{RCode: response.RCode}
+	}
+	return response, nil
+}
+
+// recordRCode 记录一个响应码
+func (e *DNSExecutor) recordRCode(recordType, rcode string) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	codes, ok := e.rcodes[recordType]
+	if !ok {
+		codes = make(map[string]int64)
+		e.rcodes[recordType] = codes
+	}
+	codes[rcode]++
+}
+
+// OperationStats 获取按记录类型的统计
+func (e *DNSExecutor) OperationStats() []OperationStats {
+	return e.tracker.Stats()
+}
+
+// ResponseCodeStats 获取按记录类型排序的响应码分布
+func (e *DNSExecutor) ResponseCodeStats() []ResponseCodeStats {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	stats := make([]ResponseCodeStats, 0, len(e.rcodes))
+	for recordType, codes := range e.rcodes {
+		entry := ResponseCodeStats{Type: recordType, Codes: make(map[string]int64, len(codes))}
+		for code, n := range codes {
+			entry.Codes[code] = n
+			entry.Responses += n
+		}
+		stats = append(stats, entry)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Type < stats[j].Type })
+	return stats
+}
@@ -0,0 +1,228 @@
+package operations
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/dns/dnsmessage"
+
+	"abc-runner/app/adapters/dns/config"
+	"abc-runner/app/adapters/dns/connection"
+	"abc-runner/app/core/interfaces"
+)
+
+// answer 模拟服务端：nx.开头的名称返回NXDOMAIN，fail.开头的返回SERVFAIL，big.开头的返回截断响应，
+// 其余A查询返回两条记录、其他类型返回空应答
+func answer(query []byte) []byte {
+	var parser dnsmessage.Parser
+	header, err := parser.Start(query)
+	if err != nil {
+		return nil
+	}
+	question, err := parser.Question()
+	if err != nil {
+		return nil
+	}
+
+	name := question.Name.String()
+	header.Response = true
+	switch {
+	case strings.HasPrefix(name, "nx."):
+		header.RCode = dnsmessage.RCodeNameError
+	case strings.HasPrefix(name, "fail."):
+		header.RCode = dnsmessage.RCodeServerFailure
+	case strings.HasPrefix(name, "big."):
+		header.Truncated = true
+	}
+	builder := dnsmessage.NewBuilder(nil, header)
+	builder.StartQuestions()
+	builder.Question(question)
+	builder.StartAnswers()
+	if header.RCode == dnsmessage.RCodeSuccess && question.Type == dnsmessage.TypeA {
+		for i := byte(1); i <= 2; i++ {
+			builder.AResource(dnsmessage.ResourceHeader{Name: question.Name, Class: dnsmessage.ClassINET, TTL: 60},
+				dnsmessage.AResource{A: [4]byte{192, 0, 2, i}})
+		}
+	}
+	response, _ := builder.Finish()
+	return response
+}
+
+func TestQueryBuilder(t *testing.T) {
+	specific := config.NewDefaultDNSConfig().DNSSpecific
+	specific.RandomSubdomain = true
+	query, err := NewQueryBuilder(specific).Build(42, "example.com", "SRV")
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	var parser dnsmessage.Parser
+	header, _ := parser.Start(query)
+	question, err := parser.Question()
+	if err != nil || header.ID != 42 || !header.RecursionDesired || question.Type != dnsmessage.TypeSRV {
+		t.Fatalf("unexpected query: %+v %+v (err %v)", header, question, err)
+	}
+	if name := question.Name.String(); !strings.HasSuffix(name, ".example.com.") || len(name) != len("r00000000.example.com.") {
+		t.Fatalf("unexpected random subdomain: %s", name)
+	}
+	parser.SkipAllQuestions()
+	parser.SkipAllAnswers()
+	parser.SkipAllAuthorities()
+	opt, err := parser.AdditionalHeader()
+	if err != nil || opt.Type != dnsmessage.TypeOPT || opt.Class != 1232 {
+		t.Fatalf("unexpected EDNS0 record: %+v (err %v)", opt, err)
+	}
+}
+
+func TestDNSExecutorUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer conn.Close()
+	go func() {
+		buffer := make([]byte, 65535)
+		for {
+			n, addr, err := conn.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			conn.WriteTo(answer(buffer[:n]), addr)
+		}
+	}()
+
+	cfg := config.NewDefaultDNSConfig()
+	cfg.DNSSpecific.Names = []string{"example.com", "nx.example.com", "fail.example.com", "big.example.com"}
+	cfg.DNSSpecific.Types = []string{"A", "TXT"}
+	executor := newTestExecutor(t, cfg, conn.LocalAddr())
+
+	factory := NewOperationFactory(cfg)
+	for i := 0; i < 8; i++ {
+		operation := factory.CreateOperation(i, nil)
+		_, err := executor.ExecuteOperation(context.Background(), operation)
+		if failed := strings.HasPrefix(operation.Key, "fail."); failed != (err != nil) {
+			t.Fatalf("query %s %s: unexpected error %v", operation.Type, operation.Key, err)
+		}
+	}
+
+	stats := executor.OperationStats()
+	if len(stats) != 2 || stats[0].Type != "A" || stats[1].Type != "TXT" {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	// A: example.com两条应答，big.截断响应同样两条应答
+	if a := stats[0]; a.Count != 4 || a.Errors != 1 || a.Volume != 4 || a.Flagged != 1 || a.ErrorCodes["SERVFAIL"] != 1 {
+		t.Fatalf("unexpected A stats: %+v", a)
+	}
+
+	codes := executor.ResponseCodeStats()
+	if len(codes) != 2 || codes[1].Type != "TXT" || codes[1].Responses != 4 ||
+		codes[1].Codes["NOERROR"] != 2 || codes[1].Codes["NXDOMAIN"] != 1 || codes[1].Codes["SERVFAIL"] != 1 {
+		t.Fatalf("unexpected response codes: %+v", codes)
+	}
+}
+
+func TestDNSExecutorTCPReconnect(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			// 每个连接只应答一条查询后关闭，模拟服务端的空闲超时
+			go func(conn net.Conn) {
+				defer conn.Close()
+				var length [2]byte
+				if _, err := io.ReadFull(conn, length[:]); err != nil {
+					return
+				}
+				query := make([]byte, binary.BigEndian.Uint16(length[:]))
+				if _, err := io.ReadFull(conn, query); err != nil {
+					return
+				}
+				response := answer(query)
+				frame := binary.BigEndian.AppendUint16(nil, uint16(len(response)))
+				conn.Write(append(frame, response...))
+			}(conn)
+		}
+	}()
+
+	cfg := config.NewDefaultDNSConfig()
+	cfg.Connection.Transport = config.TransportTCP
+	cfg.BenchMark.Parallels = 1
+	executor := newTestExecutor(t, cfg, listener.Addr())
+
+	for i := 0; i < 3; i++ {
+		result, err := executor.ExecuteOperation(context.Background(), interfaces.Operation{Type: "A", Key: "example.com"})
+		if err != nil || result.Value != int64(2) {
+			t.Fatalf("query %d failed: %v", i, err)
+		}
+	}
+	if reconnects := executor.client.Reconnects(); reconnects != 2 {
+		t.Fatalf("reconnects = %d, want 2", reconnects)
+	}
+}
+
+func TestDNSExecutorDoH(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/dns-message" ||
+			binary.BigEndian.Uint16(query) != 0 {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if strings.Contains(string(query), "busy") {
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/dns-message")
+		w.Write(answer(query))
+	}))
+	defer server.Close()
+
+	cfg := config.NewDefaultDNSConfig()
+	cfg.Connection.Transport = config.TransportDoH
+	cfg.Connection.URL = server.URL + "/dns-query"
+	cfg.Connection.TLS.InsecureSkipVerify = true
+	executor := newTestExecutor(t, cfg, nil)
+
+	if _, err := executor.ExecuteOperation(context.Background(), interfaces.Operation{Type: "A", Key: "example.com"}); err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	_, err := executor.ExecuteOperation(context.Background(), interfaces.Operation{Type: "A", Key: "busy.example.com"})
+	var statusErr *connection.HTTPStatusError
+	if !errors.As(err, &statusErr) || errorCode(err) != "http_503" {
+		t.Fatalf("expected HTTP 503, got %v", err)
+	}
+}
+
+func newTestExecutor(t *testing.T, cfg *config.DNSConfig, addr net.Addr) *DNSExecutor {
+	t.Helper()
+	if addr != nil {
+		host, port, _ := net.SplitHostPort(addr.String())
+		cfg.Connection.Address = host
+		cfg.Connection.Port, _ = strconv.Atoi(port)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("invalid config: %v", err)
+	}
+
+	client, err := connection.NewClient(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return NewDNSExecutor(client, NewQueryBuilder(cfg.DNSSpecific), 0)
+}
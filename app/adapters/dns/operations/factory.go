@@ -0,0 +1,45 @@
+package operations
+
+import (
+	"abc-runner/app/adapters/dns/config"
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+)
+
+// OperationFactory DNS操作工厂
+// 操作类型为记录类型：第jobID个查询使用第jobID%len(types)个类型，每轮类型查询完后切换到下一个名称
+type OperationFactory struct {
+	names []string
+	types []string
+}
+
+// NewOperationFactory 创建DNS操作工厂，names_file中的名称需已由ResolveNames合并到names
+func NewOperationFactory(cfg *config.DNSConfig) *OperationFactory {
+	return &OperationFactory{names: cfg.DNSSpecific.Names, types: cfg.DNSSpecific.Types}
+}
+
+// CreateOperation 创建查询操作
+func (f *OperationFactory) CreateOperation(jobID int, cfg execution.BenchmarkConfig) interfaces.Operation {
+	recordType := f.types[jobID%len(f.types)]
+	name := f.names[(jobID/len(f.types))%len(f.names)]
+	return interfaces.Operation{
+		Type: recordType,
+		Key:  name,
+		Params: map[string]interface{}{
+			"job_id": jobID,
+		},
+		Metadata: map[string]string{
+			"operation_type": recordType,
+		},
+	}
+}
+
+// GetOperationType 获取操作类型
+func (f *OperationFactory) GetOperationType() string {
+	return "query"
+}
+
+// GetSupportedOperations 获取支持的操作类型
+func (f *OperationFactory) GetSupportedOperations() []string {
+	return f.types
+}
@@ -0,0 +1,155 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+
+	"golang.org/x/net/dns/dnsmessage"
+
+	"abc-runner/app/adapters/dns/config"
+)
+
+// recordTypes 记录类型名称与类型值
+var recordTypes = map[string]dnsmessage.Type{
+	"A":     dnsmessage.TypeA,
+	"AAAA":  dnsmessage.TypeAAAA,
+	"SRV":   dnsmessage.TypeSRV,
+	"TXT":   dnsmessage.TypeTXT,
+	"CNAME": dnsmessage.TypeCNAME,
+	"MX":    dnsmessage.TypeMX,
+	"NS":    dnsmessage.TypeNS,
+	"PTR":   dnsmessage.TypePTR,
+	"SOA":   dnsmessage.TypeSOA,
+}
+
+// rcodeNames 响应码的常用写法，未列出的响应码输出为"RCODE<n>"
+var rcodeNames = map[dnsmessage.RCode]string{
+	dnsmessage.RCodeSuccess:        "NOERROR",
+	dnsmessage.RCodeFormatError:    "FORMERR",
+	dnsmessage.RCodeServerFailure:  "SERVFAIL",
+	dnsmessage.RCodeNameError:      "NXDOMAIN",
+	dnsmessage.RCodeNotImplemented: "NOTIMP",
+	dnsmessage.RCodeRefused:        "REFUSED",
+}
+
+// ErrMalformedResponse 响应无法解析或不是对查询的应答
+var ErrMalformedResponse = errors.New("malformed response")
+
+// RCodeError 服务端返回的失败响应码（SERVFAIL、REFUSED等）
+// NXDOMAIN是对查询的有效应答，不视为错误
+type RCodeError struct {
+	RCode string
+}
+
+func (e *RCodeError) Error() string {
+	return "server responded " + e.RCode
+}
+
+// Response 解析后的响应摘要
+type Response struct {
+	RCode     string
+	Truncated bool // 设置了TC标志，UDP响应超过了通告的负载大小
+	Answers   int
+	// QuerySize、Size 查询与响应报文的字节数，由Query填写
+	QuerySize, Size int
+}
+
+// QueryBuilder 查询报文构建器
+type QueryBuilder struct {
+	recursionDesired bool
+	udpSize          int
+	randomSubdomain  bool
+}
+
+// NewQueryBuilder 创建查询报文构建器
+func NewQueryBuilder(cfg config.DNSSpecificConfig) *QueryBuilder {
+	return &QueryBuilder{
+		recursionDesired: cfg.RecursionDesired,
+		udpSize:          cfg.UDPSize,
+		randomSubdomain:  cfg.RandomSubdomain,
+	}
+}
+
+// Build 构建一条查询报文，random_subdomain开启时在名称前加随机标签
+func (b *QueryBuilder) Build(id uint16, name, recordType string) ([]byte, error) {
+	qtype, ok := recordTypes[recordType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported record type: %s", recordType)
+	}
+	if b.randomSubdomain {
+		name = fmt.Sprintf("r%08x.%s", rand.Uint32(), name)
+	}
+	if !strings.HasSuffix(name, ".") {
+		name += "."
+	}
+	qname, err := dnsmessage.NewName(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query name %q: %w", name, err)
+	}
+
+	builder := dnsmessage.NewBuilder(make([]byte, 0, 64+len(name)), dnsmessage.Header{
+		ID:               id,
+		RecursionDesired: b.recursionDesired,
+	})
+	if err := builder.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := builder.Question(dnsmessage.Question{Name: qname, Type: qtype, Class: dnsmessage.ClassINET}); err != nil {
+		return nil, err
+	}
+	if b.udpSize > 0 {
+		if err := builder.StartAdditionals(); err != nil {
+			return nil, err
+		}
+		var opt dnsmessage.ResourceHeader
+		if err := opt.SetEDNS0(b.udpSize, dnsmessage.RCodeSuccess, false); err != nil {
+			return nil, err
+		}
+		if err := builder.OPTResource(opt, dnsmessage.OPTResource{}); err != nil {
+			return nil, err
+		}
+	}
+	return builder.Finish()
+}
+
+// ParseResponse 解析响应头与应答数
+func ParseResponse(message []byte) (*Response, error) {
+	var parser dnsmessage.Parser
+	header, err := parser.Start(message)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedResponse, err)
+	}
+	if !header.Response {
+		return nil, fmt.Errorf("%w: QR flag not set", ErrMalformedResponse)
+	}
+
+	response := &Response{RCode: RCodeName(header.RCode), Truncated: header.Truncated}
+	if err := parser.SkipAllQuestions(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedResponse, err)
+	}
+	for {
+		err := parser.SkipAnswer()
+		if err == dnsmessage.ErrSectionDone {
+			break
+		}
+		if err != nil {
+			// 截断的响应可能只包含部分应答
+			if header.Truncated {
+				break
+			}
+			return nil, fmt.Errorf("%w: %v", ErrMalformedResponse, err)
+		}
+		response.Answers++
+	}
+	return response, nil
+}
+
+// RCodeName 响应码名称
+func RCodeName(rcode dnsmessage.RCode) string {
+	if name, ok := rcodeNames[rcode]; ok {
+		return name
+	}
+	return fmt.Sprintf("RCODE%d", rcode)
+}
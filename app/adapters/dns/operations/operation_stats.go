@@ -0,0 +1,43 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"abc-runner/app/adapters/dns/connection"
+	"abc-runner/app/core/metrics"
+)
+
+// OperationStats 单个记录类型的统计，Volume为成功响应中的应答记录数（JSON字段"answers"），
+// Flagged为设置了TC标志的截断响应数（JSON字段"truncated"）；
+// 错误码为失败的响应码（如"SERVFAIL"、"REFUSED"），另有DoH的"http_<状态码>"、"timeout"、"malformed"与"network"
+type OperationStats = metrics.OperationTypeStats
+
+// newOperationTracker 创建按记录类型的统计器
+func newOperationTracker() *metrics.OperationTypeTracker {
+	return metrics.NewOperationTypeTracker(errorCode, "answers", "truncated")
+}
+
+// errorCode 错误分类：失败响应取响应码，DoH非200响应取HTTP状态码，其余按超时、响应错误与网络错误归类
+func errorCode(err error) string {
+	var rcodeErr *RCodeError
+	if errors.As(err, &rcodeErr) {
+		return rcodeErr.RCode
+	}
+	var statusErr *connection.HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return "http_" + strconv.Itoa(statusErr.StatusCode)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	var timeoutErr interface{ Timeout() bool }
+	if errors.As(err, &timeoutErr) && timeoutErr.Timeout() {
+		return "timeout"
+	}
+	if errors.Is(err, ErrMalformedResponse) || errors.Is(err, connection.ErrIDMismatch) {
+		return "malformed"
+	}
+	return "network"
+}
@@ -0,0 +1,192 @@
+package elasticsearch
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"abc-runner/app/adapters/elasticsearch/config"
+	"abc-runner/app/adapters/elasticsearch/connection"
+	"abc-runner/app/adapters/elasticsearch/operations"
+	"abc-runner/app/core/interfaces"
+)
+
+// ElasticsearchAdapter Elasticsearch/OpenSearch协议适配器 - 遵循统一架构模式
+// 职责：REST客户端管理、测试索引的创建与删除、健康检查
+type ElasticsearchAdapter struct {
+	config                  *config.ElasticsearchConfig
+	client                  *connection.Client
+	elasticsearchOperations *operations.ElasticsearchExecutor
+	metricsCollector        interfaces.DefaultMetricsCollector
+	clusterInfo             *connection.ClusterInfo
+	indexCreated            bool
+	mu                      sync.RWMutex
+	isConnected             bool
+
+	// 统计信息
+	totalOperations  int64
+	failedOperations int64
+}
+
+// NewElasticsearchAdapter 创建Elasticsearch适配器
+func NewElasticsearchAdapter(metricsCollector interfaces.DefaultMetricsCollector) *ElasticsearchAdapter {
+	return &ElasticsearchAdapter{
+		metricsCollector: metricsCollector,
+	}
+}
+
+// Connect 创建REST客户端，配置了setup时在索引不存在时创建测试索引
+func (e *ElasticsearchAdapter) Connect(ctx context.Context, cfg interfaces.Config) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	esConfig, ok := cfg.(*config.ElasticsearchConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type for Elasticsearch adapter: expected *config.ElasticsearchConfig, got %T", cfg)
+	}
+
+	if err := esConfig.Validate(); err != nil {
+		return fmt.Errorf("config validation failed: %w", err)
+	}
+	e.config = esConfig
+
+	client := connection.NewClient(esConfig)
+	executor, err := operations.NewElasticsearchExecutor(client, esConfig)
+	if err != nil {
+		return fmt.Errorf("config validation failed: %w", err)
+	}
+
+	if specific := esConfig.ElasticsearchSpecific; specific.Setup {
+		created, err := client.CreateIndex(ctx, specific.Index, specific.Shards, specific.Replicas)
+		if err != nil {
+			client.Close()
+			return err
+		}
+		e.indexCreated = created
+	}
+
+	e.client = client
+	e.elasticsearchOperations = executor
+	e.isConnected = true
+	return nil
+}
+
+// Execute 执行操作 - 使用执行器处理
+func (e *ElasticsearchAdapter) Execute(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	if !e.isConnected {
+		return nil, fmt.Errorf("adapter not connected")
+	}
+
+	atomic.AddInt64(&e.totalOperations, 1)
+	result, err := e.elasticsearchOperations.ExecuteOperation(ctx, operation)
+	if err != nil {
+		atomic.AddInt64(&e.failedOperations, 1)
+	}
+	return result, err
+}
+
+// Close 配置了teardown时删除测试索引，然后关闭空闲连接
+func (e *ElasticsearchAdapter) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var err error
+	if e.client != nil {
+		if e.config.ElasticsearchSpecific.Teardown {
+			ctx, cancel := context.WithTimeout(context.Background(), e.config.Connection.Timeout)
+			err = e.client.DeleteIndex(ctx, e.config.ElasticsearchSpecific.Index)
+			cancel()
+		}
+		e.client.Close()
+		e.client = nil
+	}
+	e.isConnected = false
+	return err
+}
+
+// GetProtocolMetrics 获取协议特定指标
+func (e *ElasticsearchAdapter) GetProtocolMetrics() map[string]interface{} {
+	metrics := map[string]interface{}{
+		"protocol":          "elasticsearch",
+		"total_operations":  atomic.LoadInt64(&e.totalOperations),
+		"failed_operations": atomic.LoadInt64(&e.failedOperations),
+	}
+
+	if e.config != nil {
+		metrics["index"] = e.config.ElasticsearchSpecific.Index
+		metrics["bulk_size"] = e.config.ElasticsearchSpecific.BulkSize
+	}
+	if info := e.GetClusterInfo(); info != nil {
+		metrics["cluster_name"] = info.ClusterName
+		metrics["product"] = info.Product()
+		metrics["version"] = info.Version.Number
+	}
+	if stats := e.GetOperationStats(); stats != nil {
+		metrics["operation_stats"] = stats
+	}
+	if stats := e.GetIndexingStats(); stats != nil {
+		metrics["indexing_stats"] = *stats
+	}
+
+	return metrics
+}
+
+// GetOperationStats 获取按操作类型的统计，未连接时返回nil
+func (e *ElasticsearchAdapter) GetOperationStats() []operations.OperationStats {
+	if e.elasticsearchOperations == nil {
+		return nil
+	}
+	return e.elasticsearchOperations.OperationStats()
+}
+
+// GetIndexingStats 获取写入吞吐量与背压统计，未连接时返回nil
+func (e *ElasticsearchAdapter) GetIndexingStats() *operations.IndexingStats {
+	if e.elasticsearchOperations == nil {
+		return nil
+	}
+	stats := e.elasticsearchOperations.IndexingStats()
+	return &stats
+}
+
+// GetClusterInfo 获取健康检查时读取的集群信息，未检查时返回nil
+func (e *ElasticsearchAdapter) GetClusterInfo() *connection.ClusterInfo {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.clusterInfo
+}
+
+// IndexCreated setup是否新建了测试索引
+func (e *ElasticsearchAdapter) IndexCreated() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.indexCreated
+}
+
+// HealthCheck 健康检查：读取集群信息，检查节点可达与认证是否有效
+func (e *ElasticsearchAdapter) HealthCheck(ctx context.Context) error {
+	if !e.isConnected {
+		return fmt.Errorf("adapter not connected")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.config.Connection.Timeout)
+	defer cancel()
+	info, err := e.client.Info(ctx)
+	if err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	e.mu.Lock()
+	e.clusterInfo = info
+	e.mu.Unlock()
+	return nil
+}
+
+// GetProtocolName 获取协议名称
+func (e *ElasticsearchAdapter) GetProtocolName() string {
+	return "elasticsearch"
+}
+
+// GetMetricsCollector 获取指标收集器
+func (e *ElasticsearchAdapter) GetMetricsCollector() interfaces.DefaultMetricsCollector {
+	return e.metricsCollector
+}
@@ -0,0 +1,44 @@
+package elasticsearch
+
+import (
+	"abc-runner/app/core/interfaces"
+)
+
+// AdapterFactory Elasticsearch适配器工厂
+type AdapterFactory struct {
+	metricsCollector interfaces.DefaultMetricsCollector
+}
+
+// NewAdapterFactory 创建Elasticsearch适配器工厂
+func NewAdapterFactory(metricsCollector interfaces.DefaultMetricsCollector) *AdapterFactory {
+	return &AdapterFactory{
+		metricsCollector: metricsCollector,
+	}
+}
+
+// CreateElasticsearchAdapter 创建Elasticsearch适配器 (实现ElasticsearchAdapterFactory接口)
+func (f *AdapterFactory) CreateElasticsearchAdapter() interfaces.ProtocolAdapter {
+	if f.metricsCollector == nil {
+		panic("metricsCollector cannot be nil - dependency injection required")
+	}
+
+	return NewElasticsearchAdapter(f.metricsCollector)
+}
+
+// GetProtocolName 获取支持的协议名称
+func (f *AdapterFactory) GetProtocolName() string {
+	return "elasticsearch"
+}
+
+// GetMetricsCollector 获取指标收集器
+func (f *AdapterFactory) GetMetricsCollector() interfaces.DefaultMetricsCollector {
+	return f.metricsCollector
+}
+
+// SetMetricsCollector 设置指标收集器
+func (f *AdapterFactory) SetMetricsCollector(collector interfaces.DefaultMetricsCollector) {
+	f.metricsCollector = collector
+}
+
+// 确保实现了interfaces.ElasticsearchAdapterFactory接口
+var _ interfaces.ElasticsearchAdapterFactory = (*AdapterFactory)(nil)
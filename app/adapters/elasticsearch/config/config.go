@@ -0,0 +1,296 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/utils"
+)
+
+// 操作类型，同时也是mixed以外的测试用例名
+const (
+	OperationIndex  = "index"  // 以一个bulk请求写入bulk_size个按模板生成的文档
+	OperationSearch = "search" // 执行一次按模板生成的查询
+)
+
+// TestCaseMixed 按read_percent混合search与index
+const TestCaseMixed = "mixed"
+
+// 默认值
+const (
+	DefaultIndex    = "abc_bench"
+	DefaultDocument = `{"@timestamp": {{timestamp}}, "user": "{{name}}", "email": "{{email}}", "level": "{{pick info warn error}}", "status": {{randInt 100 599}}, "duration_ms": {{randInt 1 5000}}, "message": "{{randString 200}}"}`
+	DefaultQuery    = `{"size": 10, "query": {"bool": {"filter": [{"term": {"level": "{{pick info warn error}}"}}, {"range": {"status": {"gte": {{randInt 100 599}}}}}]}}}`
+)
+
+// ElasticsearchConfig Elasticsearch/OpenSearch协议配置
+type ElasticsearchConfig struct {
+	Protocol              string                      `yaml:"protocol" json:"protocol"`
+	Connection            ConnectionConfig            `yaml:"connection" json:"connection"`
+	BenchMark             BenchmarkConfig             `yaml:"benchmark" json:"benchmark"`
+	ElasticsearchSpecific ElasticsearchSpecificConfig `yaml:"elasticsearch_specific" json:"elasticsearch_specific"`
+}
+
+// ConnectionConfig Elasticsearch连接配置
+type ConnectionConfig struct {
+	Addresses          []string          `yaml:"addresses" json:"addresses"` // 节点地址，如 http://localhost:9200，请求在各节点间轮转
+	Username           string            `yaml:"username" json:"username"`
+	Password           string            `yaml:"password" json:"password"`
+	APIKey             string            `yaml:"api_key" json:"api_key"` // Base64编码的API密钥，不能与Basic认证同时使用
+	Headers            map[string]string `yaml:"headers" json:"headers"`
+	Timeout            time.Duration     `yaml:"timeout" json:"timeout"`
+	InsecureSkipVerify bool              `yaml:"insecure_skip_verify" json:"insecure_skip_verify"`
+}
+
+// BenchmarkConfig Elasticsearch基准测试配置
+type BenchmarkConfig struct {
+	Total       int           `yaml:"total" json:"total"`
+	Parallels   int           `yaml:"parallels" json:"parallels"`
+	TestCase    string        `yaml:"test_case" json:"test_case"` // index、search或mixed
+	Duration    time.Duration `yaml:"duration" json:"duration"`
+	ReadPercent int           `yaml:"read_percent" json:"read_percent"` // mixed用例中search所占的百分比
+}
+
+// ElasticsearchSpecificConfig Elasticsearch特定配置
+type ElasticsearchSpecificConfig struct {
+	Index    string `yaml:"index" json:"index"`         // 写入与查询的索引
+	BulkSize int    `yaml:"bulk_size" json:"bulk_size"` // 每个bulk请求包含的文档数
+	Refresh  string `yaml:"refresh" json:"refresh"`     // bulk的refresh参数：false（默认）、true或wait_for
+	Setup    bool   `yaml:"setup" json:"setup"`         // 运行前在索引不存在时按shards与replicas创建索引
+	Shards   int    `yaml:"shards" json:"shards"`       // setup创建索引的主分片数，0表示使用集群默认值
+	Replicas int    `yaml:"replicas" json:"replicas"`   // setup创建索引的副本数，-1表示使用集群默认值
+	Teardown bool   `yaml:"teardown" json:"teardown"`   // 运行结束后删除索引
+
+	// Document 文档模板，支持负载模板占位符，渲染结果必须是JSON对象
+	Document utils.PayloadTemplateConfig `yaml:"document" json:"document"`
+	// Query 查询请求体模板（_search的JSON请求体），支持负载模板占位符
+	Query utils.PayloadTemplateConfig `yaml:"query" json:"query"`
+}
+
+// NewDefaultElasticsearchConfig 创建默认Elasticsearch配置
+func NewDefaultElasticsearchConfig() *ElasticsearchConfig {
+	return &ElasticsearchConfig{
+		Protocol: "elasticsearch",
+		Connection: ConnectionConfig{
+			Addresses: []string{"http://localhost:9200"},
+			Headers:   make(map[string]string),
+			Timeout:   30 * time.Second,
+		},
+		BenchMark: BenchmarkConfig{
+			Total:       1000,
+			Parallels:   10,
+			TestCase:    OperationIndex,
+			ReadPercent: 50,
+		},
+		ElasticsearchSpecific: ElasticsearchSpecificConfig{
+			Index:    DefaultIndex,
+			BulkSize: 500,
+			Shards:   1,
+			Replicas: -1,
+			Document: utils.PayloadTemplateConfig{Template: DefaultDocument},
+			Query:    utils.PayloadTemplateConfig{Template: DefaultQuery},
+		},
+	}
+}
+
+// GetProtocol 实现Config接口
+func (c *ElasticsearchConfig) GetProtocol() string {
+	return c.Protocol
+}
+
+// GetConnection 实现Config接口
+func (c *ElasticsearchConfig) GetConnection() interfaces.ConnectionConfig {
+	return &c.Connection
+}
+
+// GetBenchmark 实现Config接口
+func (c *ElasticsearchConfig) GetBenchmark() interfaces.BenchmarkConfig {
+	return &c.BenchMark
+}
+
+// Validate 实现Config接口
+func (c *ElasticsearchConfig) Validate() error {
+	if len(c.Connection.Addresses) == 0 {
+		return fmt.Errorf("at least one address is required")
+	}
+	for _, address := range c.Connection.Addresses {
+		parsed, err := url.Parse(address)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("invalid address: %q (expected http(s)://host:port)", address)
+		}
+	}
+	if c.Connection.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+	if c.Connection.APIKey != "" && c.Connection.Username != "" {
+		return fmt.Errorf("api key and basic auth cannot be used together")
+	}
+
+	if c.BenchMark.Total <= 0 {
+		return fmt.Errorf("total operations must be greater than 0")
+	}
+	if c.BenchMark.Parallels <= 0 {
+		return fmt.Errorf("parallel connections must be greater than 0")
+	}
+	switch c.BenchMark.TestCase {
+	case OperationIndex, OperationSearch:
+	case TestCaseMixed:
+		if c.BenchMark.ReadPercent < 0 || c.BenchMark.ReadPercent > 100 {
+			return fmt.Errorf("read percent must be between 0 and 100")
+		}
+	default:
+		return fmt.Errorf("invalid test case: %s, valid options: %s, %s, %s",
+			c.BenchMark.TestCase, OperationIndex, OperationSearch, TestCaseMixed)
+	}
+
+	specific := c.ElasticsearchSpecific
+	if specific.Index == "" || specific.Index != strings.ToLower(specific.Index) ||
+		strings.ContainsAny(specific.Index, ` ,*?"<>|\/#:`) || strings.HasPrefix(specific.Index, "_") {
+		return fmt.Errorf("invalid index name: %q (lowercase, no wildcards, commas or leading underscore)", specific.Index)
+	}
+	if specific.BulkSize <= 0 {
+		return fmt.Errorf("bulk size must be greater than 0")
+	}
+	switch specific.Refresh {
+	case "", "false", "true", "wait_for":
+	default:
+		return fmt.Errorf("invalid refresh: %s, valid options: false, true, wait_for", specific.Refresh)
+	}
+	if specific.Shards < 0 {
+		return fmt.Errorf("shards cannot be negative")
+	}
+	if specific.Replicas < -1 {
+		return fmt.Errorf("replicas must be -1 (cluster default) or greater")
+	}
+	if err := validateJSONTemplate("document", specific.Document); err != nil {
+		return err
+	}
+	if err := validateJSONTemplate("query", specific.Query); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateJSONTemplate 验证模板语法，并检查渲染结果是JSON对象
+func validateJSONTemplate(name string, template utils.PayloadTemplateConfig) error {
+	if !template.Enabled() {
+		return fmt.Errorf("%s template cannot be empty", name)
+	}
+	compiled, err := template.Compile()
+	if err != nil {
+		return fmt.Errorf("invalid %s template: %w", name, err)
+	}
+	var object map[string]interface{}
+	if err := json.Unmarshal([]byte(compiled.Render(0)), &object); err != nil {
+		return fmt.Errorf("%s template does not render a JSON object: %w", name, err)
+	}
+	return nil
+}
+
+// Clone 实现Config接口
+func (c *ElasticsearchConfig) Clone() interfaces.Config {
+	clone := *c
+	clone.Connection.Addresses = append([]string(nil), c.Connection.Addresses...)
+	clone.Connection.Headers = make(map[string]string, len(c.Connection.Headers))
+	for k, v := range c.Connection.Headers {
+		clone.Connection.Headers[k] = v
+	}
+	return &clone
+}
+
+// ConnectionConfig接口实现
+
+// GetAddresses 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetAddresses() []string {
+	return c.Addresses
+}
+
+// GetCredentials 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetCredentials() map[string]string {
+	return map[string]string{
+		"username": c.Username,
+		"password": c.Password,
+	}
+}
+
+// GetPoolConfig 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetPoolConfig() interfaces.PoolConfig {
+	return &EmptyPoolConfig{}
+}
+
+// GetTimeout 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetTimeout() time.Duration {
+	return c.Timeout
+}
+
+// EmptyPoolConfig 空的连接池配置（空闲连接数由并发数决定）
+type EmptyPoolConfig struct{}
+
+func (p *EmptyPoolConfig) GetPoolSize() int                    { return 0 }
+func (p *EmptyPoolConfig) GetMinIdle() int                     { return 0 }
+func (p *EmptyPoolConfig) GetMaxIdle() int                     { return 0 }
+func (p *EmptyPoolConfig) GetIdleTimeout() time.Duration       { return 0 }
+func (p *EmptyPoolConfig) GetConnectionTimeout() time.Duration { return 0 }
+
+// BenchmarkConfig接口实现
+
+// GetTotal 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTotal() int {
+	return b.Total
+}
+
+// GetParallels 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetParallels() int {
+	return b.Parallels
+}
+
+// GetDataSize 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetDataSize() int {
+	return 0
+}
+
+// GetTTL 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTTL() time.Duration {
+	return 0
+}
+
+// GetReadPercent 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetReadPercent() int {
+	switch b.TestCase {
+	case OperationSearch:
+		return 100
+	case TestCaseMixed:
+		return b.ReadPercent
+	}
+	return 0
+}
+
+// GetRandomKeys 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetRandomKeys() int {
+	return 0
+}
+
+// GetTestCase 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTestCase() string {
+	return b.TestCase
+}
+
+// GetDuration 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetDuration() time.Duration {
+	return b.Duration
+}
+
+// GetTimeout 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTimeout() time.Duration {
+	return 0
+}
+
+// GetRampUp 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetRampUp() time.Duration {
+	return 0
+}
@@ -0,0 +1,57 @@
+package config
+
+import "testing"
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*ElasticsearchConfig)
+		valid  bool
+	}{
+		{"default", func(c *ElasticsearchConfig) {}, true},
+		{"mixed", func(c *ElasticsearchConfig) {
+			c.BenchMark.TestCase = TestCaseMixed
+			c.BenchMark.ReadPercent = 20
+		}, true},
+		{"several addresses", func(c *ElasticsearchConfig) {
+			c.Connection.Addresses = []string{"https://es1:9200", "https://es2:9200"}
+		}, true},
+		{"invalid test case", func(c *ElasticsearchConfig) { c.BenchMark.TestCase = "update" }, false},
+		{"mixed read percent out of range", func(c *ElasticsearchConfig) {
+			c.BenchMark.TestCase = TestCaseMixed
+			c.BenchMark.ReadPercent = 120
+		}, false},
+		{"no addresses", func(c *ElasticsearchConfig) { c.Connection.Addresses = nil }, false},
+		{"address without scheme", func(c *ElasticsearchConfig) { c.Connection.Addresses = []string{"localhost:9200"} }, false},
+		{"api key with basic auth", func(c *ElasticsearchConfig) {
+			c.Connection.APIKey = "a2V5"
+			c.Connection.Username = "elastic"
+		}, false},
+		{"uppercase index", func(c *ElasticsearchConfig) { c.ElasticsearchSpecific.Index = "Logs" }, false},
+		{"wildcard index", func(c *ElasticsearchConfig) { c.ElasticsearchSpecific.Index = "logs-*" }, false},
+		{"zero bulk size", func(c *ElasticsearchConfig) { c.ElasticsearchSpecific.BulkSize = 0 }, false},
+		{"invalid refresh", func(c *ElasticsearchConfig) { c.ElasticsearchSpecific.Refresh = "sometimes" }, false},
+		{"invalid replicas", func(c *ElasticsearchConfig) { c.ElasticsearchSpecific.Replicas = -2 }, false},
+		{"document not an object", func(c *ElasticsearchConfig) { c.ElasticsearchSpecific.Document.Template = `[{{seq}}]` }, false},
+		{"empty query", func(c *ElasticsearchConfig) { c.ElasticsearchSpecific.Query.Template = "" }, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := NewDefaultElasticsearchConfig()
+			test.modify(cfg)
+			if err := cfg.Validate(); (err == nil) != test.valid {
+				t.Errorf("Validate() error = %v, want valid %v", err, test.valid)
+			}
+		})
+	}
+}
+
+func TestGetReadPercent(t *testing.T) {
+	cfg := NewDefaultElasticsearchConfig()
+	for testCase, want := range map[string]int{OperationIndex: 0, OperationSearch: 100, TestCaseMixed: 50} {
+		cfg.BenchMark.TestCase = testCase
+		if got := cfg.BenchMark.GetReadPercent(); got != want {
+			t.Errorf("GetReadPercent() for %s = %d, want %d", testCase, got, want)
+		}
+	}
+}
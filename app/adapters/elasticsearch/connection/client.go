@@ -0,0 +1,239 @@
+package connection
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+
+	"abc-runner/app/adapters/elasticsearch/config"
+)
+
+// 响应过滤，只取统计所需的字段以减少响应体解析开销
+const (
+	bulkFilterPath   = "took,errors,items.*.status,items.*.error.type,items.*.error.reason"
+	searchFilterPath = "took,timed_out,hits.total,_shards.failed"
+)
+
+// Response 一次请求的响应
+type Response struct {
+	StatusCode int
+	Body       []byte
+}
+
+// ClusterInfo 根路径返回的集群信息
+type ClusterInfo struct {
+	ClusterName string `json:"cluster_name"`
+	Version     struct {
+		Number       string `json:"number"`
+		Distribution string `json:"distribution"` // OpenSearch返回"opensearch"，Elasticsearch为空
+	} `json:"version"`
+}
+
+// Product 集群产品名称
+func (i *ClusterInfo) Product() string {
+	if i.Version.Distribution == "opensearch" {
+		return "OpenSearch"
+	}
+	return "Elasticsearch"
+}
+
+// Client Elasticsearch/OpenSearch REST客户端，请求按顺序在各节点间轮转
+type Client struct {
+	addresses  []string
+	next       atomic.Uint64
+	headers    http.Header
+	httpClient *http.Client
+}
+
+// NewClient 创建REST客户端，空闲连接数与并发数一致
+func NewClient(cfg *config.ElasticsearchConfig) *Client {
+	headers := make(http.Header)
+	headers.Set("User-Agent", "abc-runner")
+	switch {
+	case cfg.Connection.APIKey != "":
+		headers.Set("Authorization", "ApiKey "+cfg.Connection.APIKey)
+	case cfg.Connection.Username != "":
+		request := &http.Request{Header: make(http.Header)}
+		request.SetBasicAuth(cfg.Connection.Username, cfg.Connection.Password)
+		headers.Set("Authorization", request.Header.Get("Authorization"))
+	}
+	for name, value := range cfg.Connection.Headers {
+		headers.Set(name, value)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = cfg.BenchMark.Parallels
+	transport.MaxIdleConnsPerHost = cfg.BenchMark.Parallels
+	if cfg.Connection.InsecureSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	addresses := make([]string, len(cfg.Connection.Addresses))
+	for i, address := range cfg.Connection.Addresses {
+		addresses[i] = strings.TrimRight(address, "/")
+	}
+	return &Client{
+		addresses: addresses,
+		headers:   headers,
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   cfg.Connection.Timeout,
+		},
+	}
+}
+
+// Addresses 节点地址
+func (c *Client) Addresses() []string {
+	return c.addresses
+}
+
+// Bulk 发送NDJSON格式的bulk请求，refresh为空时不设置
+// 返回的错误只表示请求未得到HTTP响应，状态码由调用方判定
+func (c *Client) Bulk(ctx context.Context, index string, body []byte, refresh string) (*Response, error) {
+	query := url.Values{"filter_path": {bulkFilterPath}}
+	if refresh != "" {
+		query.Set("refresh", refresh)
+	}
+	return c.do(ctx, http.MethodPost, "/"+url.PathEscape(index)+"/_bulk?"+query.Encode(), "application/x-ndjson", body)
+}
+
+// Search 发送查询请求
+func (c *Client) Search(ctx context.Context, index string, body []byte) (*Response, error) {
+	query := url.Values{"filter_path": {searchFilterPath}}
+	return c.do(ctx, http.MethodPost, "/"+url.PathEscape(index)+"/_search?"+query.Encode(), "application/json", body)
+}
+
+// Info 获取集群信息，可用于检查节点可达与认证是否有效
+func (c *Client) Info(ctx context.Context) (*ClusterInfo, error) {
+	response, err := c.do(ctx, http.MethodGet, "/", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("HTTP %d: %s", response.StatusCode, ErrorReason(response.Body))
+	}
+	var info ClusterInfo
+	if err := json.Unmarshal(response.Body, &info); err != nil {
+		return nil, fmt.Errorf("unexpected cluster info response: %w", err)
+	}
+	return &info, nil
+}
+
+// CreateIndex 在索引不存在时创建索引，shards为0或replicas为-1时使用集群默认值；返回是否新建了索引
+func (c *Client) CreateIndex(ctx context.Context, index string, shards, replicas int) (bool, error) {
+	path := "/" + url.PathEscape(index)
+	response, err := c.do(ctx, http.MethodHead, path, "", nil)
+	if err != nil {
+		return false, err
+	}
+	if response.StatusCode == http.StatusOK {
+		return false, nil
+	}
+
+	settings := map[string]interface{}{}
+	if shards > 0 {
+		settings["number_of_shards"] = shards
+	}
+	if replicas >= 0 {
+		settings["number_of_replicas"] = replicas
+	}
+	body, _ := json.Marshal(map[string]interface{}{"settings": settings})
+	response, err = c.do(ctx, http.MethodPut, path, "application/json", body)
+	if err != nil {
+		return false, err
+	}
+	if response.StatusCode/100 != 2 {
+		// 并发创建时其他客户端可能已创建了索引
+		if ErrorType(response.Body) == "resource_already_exists_exception" {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to create index %s: HTTP %d: %s", index, response.StatusCode, ErrorReason(response.Body))
+	}
+	return true, nil
+}
+
+// DeleteIndex 删除索引，索引不存在时不视为错误
+func (c *Client) DeleteIndex(ctx context.Context, index string) error {
+	response, err := c.do(ctx, http.MethodDelete, "/"+url.PathEscape(index), "", nil)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode/100 != 2 && response.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("failed to delete index %s: HTTP %d: %s", index, response.StatusCode, ErrorReason(response.Body))
+	}
+	return nil
+}
+
+// Close 关闭空闲连接
+func (c *Client) Close() {
+	c.httpClient.CloseIdleConnections()
+}
+
+// do 向下一个节点发送请求并读取完整响应体
+func (c *Client) do(ctx context.Context, method, path, contentType string, body []byte) (*Response, error) {
+	address := c.addresses[(c.next.Add(1)-1)%uint64(len(c.addresses))]
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, address+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	request.Header = c.headers.Clone()
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return &Response{StatusCode: response.StatusCode, Body: data}, nil
+}
+
+// errorBody 错误响应体，如 {"error": {"type": "...", "reason": "..."}, "status": 429}
+type errorBody struct {
+	Error struct {
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
+	} `json:"error"`
+}
+
+// ErrorType 错误响应中的错误类型，无法解析时为空
+func ErrorType(body []byte) string {
+	var parsed errorBody
+	if json.Unmarshal(body, &parsed) != nil {
+		return ""
+	}
+	return parsed.Error.Type
+}
+
+// ErrorReason 错误响应的原因摘要，无法解析时返回截断的响应体
+func ErrorReason(body []byte) string {
+	const maxReasonSize = 512
+	var parsed errorBody
+	if json.Unmarshal(body, &parsed) == nil && parsed.Error.Type != "" {
+		if parsed.Error.Reason == "" {
+			return parsed.Error.Type
+		}
+		return parsed.Error.Type + ": " + parsed.Error.Reason
+	}
+	reason := strings.TrimSpace(string(body))
+	if len(reason) > maxReasonSize {
+		reason = reason[:maxReasonSize]
+	}
+	return reason
+}
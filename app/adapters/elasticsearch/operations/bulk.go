@@ -0,0 +1,138 @@
+package operations
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"abc-runner/app/core/utils"
+)
+
+// bulkAction bulk请求中每个文档前的动作行，索引由请求路径指定
+const bulkAction = "{\"index\":{}}\n"
+
+// BulkBuilder 按文档模板构建NDJSON格式的bulk请求体
+type BulkBuilder struct {
+	document *utils.PayloadTemplate
+	size     int
+}
+
+// NewBulkBuilder 创建bulk请求体构建器，size为每个请求的文档数
+// 模板中的换行在解析前替换为空格，多行书写的模板也能渲染为单行文档
+func NewBulkBuilder(document utils.PayloadTemplateConfig, size int) (*BulkBuilder, error) {
+	document.Template = strings.NewReplacer("\r\n", " ", "\n", " ").Replace(document.Template)
+	template, err := document.Compile()
+	if err != nil {
+		return nil, fmt.Errorf("invalid document template: %w", err)
+	}
+	return &BulkBuilder{document: template, size: size}, nil
+}
+
+// Size 每个请求的文档数
+func (b *BulkBuilder) Size() int {
+	return b.size
+}
+
+// Build 构建第batch个bulk请求体，文档按batch*size+i渲染，{{seq}}在各请求间不重复
+func (b *BulkBuilder) Build(batch int) []byte {
+	var body []byte
+	for i := 0; i < b.size; i++ {
+		body = append(body, bulkAction...)
+		body = append(body, b.document.Render(batch*b.size+i)...)
+		body = append(body, '\n')
+	}
+	return body
+}
+
+// BulkResult bulk响应中各条目的写入结果
+type BulkResult struct {
+	Indexed    int
+	Rejected   int            // 状态码429的条目
+	Failed     int            // 其他失败条目
+	ErrorTypes map[string]int // 按错误类型统计的失败条目
+	Reason     string         // 首个失败条目的错误类型与原因
+}
+
+// bulkResponse 经filter_path过滤的bulk响应
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+		Error  *struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// ParseBulkResponse 解析bulk响应，统计写入成功、被拒绝与失败的条目
+func ParseBulkResponse(body []byte) (*BulkResult, error) {
+	var response bulkResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("unexpected bulk response: %w", err)
+	}
+	result := &BulkResult{}
+	for _, item := range response.Items {
+		for _, action := range item {
+			switch {
+			case action.Status/100 == 2:
+				result.Indexed++
+				continue
+			case action.Status == 429:
+				result.Rejected++
+			default:
+				result.Failed++
+			}
+			errorType, reason := "unknown", ""
+			if action.Error != nil {
+				errorType, reason = action.Error.Type, action.Error.Reason
+			}
+			if result.ErrorTypes == nil {
+				result.ErrorTypes = make(map[string]int)
+			}
+			result.ErrorTypes[errorType]++
+			if result.Reason == "" {
+				result.Reason = errorType + ": " + reason
+			}
+		}
+	}
+	return result, nil
+}
+
+// searchResponse 经filter_path过滤的search响应
+type searchResponse struct {
+	TimedOut bool `json:"timed_out"`
+	Hits     struct {
+		// Total 7.x起为{"value": N, "relation": "eq"}，6.x为整数
+		Total json.RawMessage `json:"total"`
+	} `json:"hits"`
+	Shards struct {
+		Failed int `json:"failed"`
+	} `json:"_shards"`
+}
+
+// SearchResult search响应摘要
+type SearchResult struct {
+	Hits         int64
+	TimedOut     bool
+	ShardsFailed int
+}
+
+// ParseSearchResponse 解析search响应的命中总数、是否超时与失败分片数
+func ParseSearchResponse(body []byte) (*SearchResult, error) {
+	var response searchResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("unexpected search response: %w", err)
+	}
+	result := &SearchResult{TimedOut: response.TimedOut, ShardsFailed: response.Shards.Failed}
+	if len(response.Hits.Total) > 0 {
+		var total struct {
+			Value int64 `json:"value"`
+		}
+		if json.Unmarshal(response.Hits.Total, &total) != nil {
+			json.Unmarshal(response.Hits.Total, &total.Value)
+		}
+		result.Hits = total.Value
+	}
+	return result, nil
+}
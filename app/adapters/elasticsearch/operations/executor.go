@@ -0,0 +1,158 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"abc-runner/app/adapters/elasticsearch/config"
+	"abc-runner/app/adapters/elasticsearch/connection"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/core/utils"
+)
+
+// ElasticsearchExecutor Elasticsearch操作执行器
+// 请求体在执行器中渲染，使模板渲染分摊到各工作协程
+type ElasticsearchExecutor struct {
+	client   *connection.Client
+	index    string
+	refresh  string
+	bulk     *BulkBuilder
+	query    *utils.PayloadTemplate
+	tracker  *metrics.OperationTypeTracker
+	indexing *IndexingTracker
+}
+
+// NewElasticsearchExecutor 创建Elasticsearch操作执行器
+func NewElasticsearchExecutor(client *connection.Client, cfg *config.ElasticsearchConfig) (*ElasticsearchExecutor, error) {
+	specific := cfg.ElasticsearchSpecific
+	bulk, err := NewBulkBuilder(specific.Document, specific.BulkSize)
+	if err != nil {
+		return nil, err
+	}
+	query, err := specific.Query.Compile()
+	if err != nil {
+		return nil, fmt.Errorf("invalid query template: %w", err)
+	}
+	return &ElasticsearchExecutor{
+		client:   client,
+		index:    specific.Index,
+		refresh:  specific.Refresh,
+		bulk:     bulk,
+		query:    query,
+		tracker:  newOperationTracker(),
+		indexing: NewIndexingTracker(),
+	}, nil
+}
+
+// ExecuteOperation 执行Elasticsearch操作
+// 预填充的bulk请求不计入统计
+func (e *ElasticsearchExecutor) ExecuteOperation(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	jobID, _ := operation.Params["job_id"].(int)
+	prefill, _ := operation.Params["prefill"].(bool)
+
+	var volume int64
+	var timedOut bool
+	var bytes, responseBytes int
+	var err error
+	startTime := time.Now()
+	switch operation.Type {
+	case config.OperationIndex:
+		volume, bytes, err = e.bulkIndex(ctx, jobID, prefill)
+	case config.OperationSearch:
+		volume, timedOut, bytes, responseBytes, err = e.search(ctx, jobID)
+	default:
+		return nil, fmt.Errorf("unsupported operation type: %s", operation.Type)
+	}
+	duration := time.Since(startTime)
+
+	if !prefill {
+		e.tracker.Record(operation.Type, volume, timedOut, duration, err)
+	}
+	if err != nil {
+		err = fmt.Errorf("%s failed: %w", operation.Type, err)
+	}
+
+	result := &interfaces.OperationResult{
+		Success:  err == nil,
+		Duration: duration,
+		IsRead:   operation.Type == config.OperationSearch,
+		Error:    err,
+		Value:    volume,
+		Metadata: map[string]interface{}{
+			"protocol":       "elasticsearch",
+			"operation_type": operation.Type,
+			"docs":           volume,
+			"bytes":          bytes,
+		},
+	}
+	if !prefill {
+		result.BytesWritten, result.BytesRead = int64(bytes), int64(responseBytes)
+	}
+	for k, v := range operation.Metadata {
+		result.Metadata[k] = v
+	}
+	return result, err
+}
+
+// bulkIndex 以一个bulk请求写入bulk_size个文档，返回写入成功的文档数与请求体字节数
+// 部分文档失败时返回BulkItemError，被拒绝（429）的文档计入背压统计
+func (e *ElasticsearchExecutor) bulkIndex(ctx context.Context, jobID int, prefill bool) (int64, int, error) {
+	body := e.bulk.Build(jobID)
+	response, err := e.client.Bulk(ctx, e.index, body, e.refresh)
+	if err != nil {
+		if !prefill {
+			e.indexing.RecordBulk(0, e.bulk.Size(), len(body), nil)
+		}
+		return 0, len(body), err
+	}
+
+	var result *BulkResult
+	if response.StatusCode/100 != 2 {
+		err = &StatusError{StatusCode: response.StatusCode, Reason: connection.ErrorReason(response.Body)}
+	} else if result, err = ParseBulkResponse(response.Body); err == nil && result.Rejected+result.Failed > 0 {
+		err = &BulkItemError{Rejected: result.Rejected, Failed: result.Failed, Reason: result.Reason}
+	}
+	if !prefill {
+		e.indexing.RecordBulk(response.StatusCode, e.bulk.Size(), len(body), result)
+	}
+	if result == nil {
+		return 0, len(body), err
+	}
+	return int64(result.Indexed), len(body), err
+}
+
+// search 执行一次查询，返回命中总数、是否超时返回部分结果与请求体、响应体字节数
+// 存在失败分片时视为失败
+func (e *ElasticsearchExecutor) search(ctx context.Context, jobID int) (int64, bool, int, int, error) {
+	body := []byte(e.query.Render(jobID))
+	response, err := e.client.Search(ctx, e.index, body)
+	if err != nil {
+		e.indexing.RecordSearch(0)
+		return 0, false, len(body), 0, err
+	}
+	e.indexing.RecordSearch(response.StatusCode)
+	if response.StatusCode/100 != 2 {
+		return 0, false, len(body), len(response.Body), &StatusError{StatusCode: response.StatusCode, Reason: connection.ErrorReason(response.Body)}
+	}
+
+	result, err := ParseSearchResponse(response.Body)
+	if err != nil {
+		return 0, false, len(body), len(response.Body), err
+	}
+	if result.ShardsFailed > 0 {
+		return 0, false, len(body), len(response.Body), fmt.Errorf("%d shards failed", result.ShardsFailed)
+	}
+	return result.Hits, result.TimedOut, len(body), len(response.Body), nil
+}
+
+// OperationStats 获取按操作类型的统计
+func (e *ElasticsearchExecutor) OperationStats() []OperationStats {
+	return e.tracker.Stats()
+}
+
+// IndexingStats 获取写入吞吐量与背压统计
+func (e *ElasticsearchExecutor) IndexingStats() IndexingStats {
+	return e.indexing.Stats()
+}
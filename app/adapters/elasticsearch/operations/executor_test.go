@@ -0,0 +1,165 @@
+package operations
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"abc-runner/app/adapters/elasticsearch/config"
+	"abc-runner/app/adapters/elasticsearch/connection"
+)
+
+func TestBulkBuilder(t *testing.T) {
+	cfg := config.NewDefaultElasticsearchConfig()
+	cfg.ElasticsearchSpecific.Document.Template = "{\n  \"seq\": {{seq}}\n}"
+	builder, err := NewBulkBuilder(cfg.ElasticsearchSpecific.Document, 3)
+	if err != nil {
+		t.Fatalf("NewBulkBuilder failed: %v", err)
+	}
+
+	// 每个文档一行动作与一行文档，{{seq}}在各请求间连续
+	scanner := bufio.NewScanner(bytes.NewReader(builder.Build(2)))
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if len(lines) != 6 {
+		t.Fatalf("lines = %d, want 6: %q", len(lines), lines)
+	}
+	for i := 0; i < 3; i++ {
+		if lines[2*i] != `{"index":{}}` {
+			t.Fatalf("unexpected action line: %s", lines[2*i])
+		}
+		var doc struct{ Seq int }
+		if err := json.Unmarshal([]byte(lines[2*i+1]), &doc); err != nil || doc.Seq != 6+i {
+			t.Fatalf("unexpected document %q: %v", lines[2*i+1], err)
+		}
+	}
+}
+
+func TestParseSearchResponse(t *testing.T) {
+	for body, want := range map[string]SearchResult{
+		`{"timed_out":false,"hits":{"total":{"value":42,"relation":"eq"}},"_shards":{"failed":0}}`: {Hits: 42},
+		`{"timed_out":true,"hits":{"total":7},"_shards":{"failed":1}}`:                             {Hits: 7, TimedOut: true, ShardsFailed: 1},
+		`{"timed_out":false,"_shards":{"failed":0}}`:                                               {},
+	} {
+		result, err := ParseSearchResponse([]byte(body))
+		if err != nil || *result != want {
+			t.Errorf("ParseSearchResponse(%s) = %+v, %v, want %+v", body, result, err, want)
+		}
+	}
+}
+
+func TestExecuteOperation(t *testing.T) {
+	var bulkCalls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/logs/_bulk":
+			if r.Header.Get("Content-Type") != "application/x-ndjson" || r.URL.Query().Get("filter_path") == "" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			io.Copy(io.Discard, r.Body)
+			switch bulkCalls.Add(1) {
+			case 2:
+				// 部分条目被写线程池拒绝
+				io.WriteString(w, `{"errors":true,"items":[{"index":{"status":201}},`+
+					`{"index":{"status":429,"error":{"type":"es_rejected_execution_exception","reason":"queue full"}}}]}`)
+			case 3:
+				w.WriteHeader(http.StatusTooManyRequests)
+				io.WriteString(w, `{"error":{"type":"circuit_breaking_exception","reason":"data too large"},"status":429}`)
+			default:
+				io.WriteString(w, `{"errors":false,"items":[{"index":{"status":201}},{"index":{"status":201}}]}`)
+			}
+		case r.URL.Path == "/logs/_search":
+			body, _ := io.ReadAll(r.Body)
+			if strings.Contains(string(body), `"fail"`) {
+				io.WriteString(w, `{"timed_out":false,"hits":{"total":{"value":0}},"_shards":{"failed":2}}`)
+				return
+			}
+			io.WriteString(w, `{"timed_out":true,"hits":{"total":{"value":5,"relation":"eq"}},"_shards":{"failed":0}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cfg := config.NewDefaultElasticsearchConfig()
+	cfg.Connection.Addresses = []string{server.URL}
+	cfg.ElasticsearchSpecific.Index = "logs"
+	cfg.ElasticsearchSpecific.BulkSize = 2
+	cfg.ElasticsearchSpecific.Query.Template = `{"query":{"term":{"level":"{{pick ok fail}}"}}}`
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("invalid config: %v", err)
+	}
+	executor, err := NewElasticsearchExecutor(connection.NewClient(cfg), cfg)
+	if err != nil {
+		t.Fatalf("NewElasticsearchExecutor failed: %v", err)
+	}
+
+	// 预填充不计入统计
+	factory := NewOperationFactory(cfg)
+	if _, err := executor.ExecuteOperation(context.Background(), factory.CreatePrepareOperation(0)); err != nil {
+		t.Fatalf("prefill failed: %v", err)
+	}
+
+	result, err := executor.ExecuteOperation(context.Background(), newOperation(config.OperationIndex, 1, false))
+	var itemErr *BulkItemError
+	if !errors.As(err, &itemErr) || itemErr.Rejected != 1 || result.Value != int64(1) {
+		t.Fatalf("partial bulk: value=%v err=%v", result.Value, err)
+	}
+	_, err = executor.ExecuteOperation(context.Background(), newOperation(config.OperationIndex, 2, false))
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusTooManyRequests ||
+		!strings.Contains(statusErr.Reason, "data too large") {
+		t.Fatalf("throttled bulk: err=%v", err)
+	}
+	if _, err := executor.ExecuteOperation(context.Background(), newOperation(config.OperationIndex, 3, false)); err != nil {
+		t.Fatalf("bulk failed: %v", err)
+	}
+
+	var searched, failed int
+	for job := 0; searched == 0 || failed == 0; job++ {
+		if job > 100 {
+			t.Fatal("search template never picked both values")
+		}
+		result, err := executor.ExecuteOperation(context.Background(), newOperation(config.OperationSearch, job, false))
+		if err != nil {
+			failed++
+			continue
+		}
+		if !result.IsRead || result.Value != int64(5) {
+			t.Fatalf("unexpected search result: %+v", result)
+		}
+		searched++
+	}
+
+	stats := executor.IndexingStats()
+	if stats.BulkRequests != 3 || stats.DocsSent != 6 || stats.DocsIndexed != 3 || stats.DocsRejected != 1 {
+		t.Fatalf("unexpected indexing stats: %+v", stats)
+	}
+	if stats.Throttled != 1 || stats.StatusCodes[429] != 1 || stats.ItemErrors["es_rejected_execution_exception"] != 1 {
+		t.Fatalf("unexpected back-pressure stats: %+v", stats)
+	}
+
+	for _, s := range executor.OperationStats() {
+		switch s.Type {
+		case config.OperationIndex:
+			if s.Count != 3 || s.Errors != 2 || s.Volume != 2 || s.ErrorCodes["bulk_rejected"] != 1 || s.ErrorCodes["429"] != 1 {
+				t.Fatalf("unexpected index stats: %+v", s)
+			}
+		case config.OperationSearch:
+			if s.Errors != int64(failed) || s.Flagged != int64(searched) || s.Volume != int64(5*searched) {
+				t.Fatalf("unexpected search stats: %+v", s)
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package operations
+
+import (
+	"math/rand/v2"
+
+	"abc-runner/app/adapters/elasticsearch/config"
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+)
+
+// OperationFactory Elasticsearch操作工厂
+// 只选择操作类型，请求体在执行器中按操作编号渲染
+type OperationFactory struct {
+	testCase    string
+	readPercent int
+}
+
+// NewOperationFactory 创建Elasticsearch操作工厂
+func NewOperationFactory(cfg *config.ElasticsearchConfig) *OperationFactory {
+	return &OperationFactory{
+		testCase:    cfg.BenchMark.TestCase,
+		readPercent: cfg.BenchMark.ReadPercent,
+	}
+}
+
+// CreateOperation 创建操作，mixed用例按read_percent随机选择search或index
+func (f *OperationFactory) CreateOperation(jobID int, cfg execution.BenchmarkConfig) interfaces.Operation {
+	operationType := f.testCase
+	if operationType == config.TestCaseMixed {
+		operationType = config.OperationIndex
+		if rand.IntN(100) < f.readPercent {
+			operationType = config.OperationSearch
+		}
+	}
+	return newOperation(operationType, jobID, false)
+}
+
+// CreatePrepareOperation 创建预填充操作：写入一个bulk请求的文档，供search用例查询
+func (f *OperationFactory) CreatePrepareOperation(index int) interfaces.Operation {
+	return newOperation(config.OperationIndex, index, true)
+}
+
+// newOperation 创建指定类型的操作
+func newOperation(operationType string, jobID int, prefill bool) interfaces.Operation {
+	return interfaces.Operation{
+		Type: operationType,
+		Params: map[string]interface{}{
+			"job_id":  jobID,
+			"prefill": prefill,
+		},
+		Metadata: map[string]string{
+			"operation_type": operationType,
+		},
+	}
+}
+
+// GetOperationType 获取操作类型
+func (f *OperationFactory) GetOperationType() string {
+	return f.testCase
+}
+
+// GetSupportedOperations 获取支持的操作类型
+func (f *OperationFactory) GetSupportedOperations() []string {
+	return []string{config.OperationIndex, config.OperationSearch}
+}
+
+var _ execution.PrepareFactory = (*OperationFactory)(nil)
@@ -0,0 +1,64 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"abc-runner/app/core/metrics"
+)
+
+// StatusError 非2xx的HTTP响应
+type StatusError struct {
+	StatusCode int
+	Reason     string // 错误类型与原因，如 es_rejected_execution_exception: rejected execution of ...
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Reason)
+}
+
+// BulkItemError bulk请求成功返回但部分文档写入失败
+type BulkItemError struct {
+	Rejected int    // 条目级429，写线程池队列已满
+	Failed   int    // 其他条目级错误，如映射冲突
+	Reason   string // 首个失败条目的错误类型与原因
+}
+
+func (e *BulkItemError) Error() string {
+	return fmt.Sprintf("%d documents rejected, %d failed: %s", e.Rejected, e.Failed, e.Reason)
+}
+
+// OperationStats 单个操作类型的统计，Volume为index成功写入的文档数或search返回的命中总数（JSON字段"docs"），
+// Flagged为search中超时返回部分结果（timed_out）的次数（JSON字段"timed_out"）；
+// 错误码为HTTP状态码，另有"bulk_rejected"、"bulk_failed"、"timeout"与"client"
+type OperationStats = metrics.OperationTypeStats
+
+// newOperationTracker 创建按操作类型的统计器
+func newOperationTracker() *metrics.OperationTypeTracker {
+	return metrics.NewOperationTypeTracker(errorCode, "docs", "timed_out")
+}
+
+// errorCode 错误分类：非2xx响应取HTTP状态码，bulk条目错误按是否被拒绝（429）区分，其余按超时与客户端错误归类
+func errorCode(err error) string {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return strconv.Itoa(statusErr.StatusCode)
+	}
+	var itemErr *BulkItemError
+	if errors.As(err, &itemErr) {
+		if itemErr.Rejected > 0 {
+			return "bulk_rejected"
+		}
+		return "bulk_failed"
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	var timeoutErr interface{ Timeout() bool }
+	if errors.As(err, &timeoutErr) && timeoutErr.Timeout() {
+		return "timeout"
+	}
+	return "client"
+}
@@ -0,0 +1,98 @@
+package operations
+
+import (
+	"sync"
+)
+
+// IndexingStats 写入吞吐量与背压统计
+type IndexingStats struct {
+	BulkRequests    int64            `json:"bulk_requests"`
+	DocsSent        int64            `json:"docs_sent"`
+	DocsIndexed     int64            `json:"docs_indexed"`
+	DocsRejected    int64            `json:"docs_rejected"` // 条目级429，写线程池队列已满
+	DocsFailed      int64            `json:"docs_failed"`   // 其他条目级错误
+	BytesSent       int64            `json:"bytes_sent"`    // bulk请求体字节数
+	SearchRequests  int64            `json:"search_requests"`
+	Throttled       int64            `json:"throttled"` // HTTP 429响应，包括bulk与search
+	ThrottleRate    float64          `json:"throttle_rate"`
+	TransportErrors int64            `json:"transport_errors"` // 未收到HTTP响应
+	StatusCodes     map[int]int64    `json:"status_codes"`
+	ItemErrors      map[string]int64 `json:"item_errors,omitempty"` // 按错误类型统计的条目错误
+}
+
+// IndexingTracker 统计bulk与search请求的响应分布
+type IndexingTracker struct {
+	mutex sync.Mutex
+	stats IndexingStats
+}
+
+// NewIndexingTracker 创建写入统计器
+func NewIndexingTracker() *IndexingTracker {
+	return &IndexingTracker{
+		stats: IndexingStats{
+			StatusCodes: make(map[int]int64),
+			ItemErrors:  make(map[string]int64),
+		},
+	}
+}
+
+// RecordBulk 记录一次bulk请求，statusCode为0表示未收到响应；result仅在2xx响应时有效
+func (t *IndexingTracker) RecordBulk(statusCode, docs, bytes int, result *BulkResult) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.stats.BulkRequests++
+	t.stats.DocsSent += int64(docs)
+	t.stats.BytesSent += int64(bytes)
+	t.recordStatus(statusCode)
+	if result == nil {
+		return
+	}
+	t.stats.DocsIndexed += int64(result.Indexed)
+	t.stats.DocsRejected += int64(result.Rejected)
+	t.stats.DocsFailed += int64(result.Failed)
+	for errorType, n := range result.ErrorTypes {
+		t.stats.ItemErrors[errorType] += int64(n)
+	}
+}
+
+// RecordSearch 记录一次search请求，statusCode为0表示未收到响应
+func (t *IndexingTracker) RecordSearch(statusCode int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.stats.SearchRequests++
+	t.recordStatus(statusCode)
+}
+
+// recordStatus 记录响应状态码，调用方需持有锁
+func (t *IndexingTracker) recordStatus(statusCode int) {
+	if statusCode == 0 {
+		t.stats.TransportErrors++
+		return
+	}
+	t.stats.StatusCodes[statusCode]++
+	if statusCode == 429 {
+		t.stats.Throttled++
+	}
+}
+
+// Stats 获取统计快照
+func (t *IndexingTracker) Stats() IndexingStats {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	stats := t.stats
+	stats.StatusCodes = make(map[int]int64, len(t.stats.StatusCodes))
+	for code, count := range t.stats.StatusCodes {
+		stats.StatusCodes[code] = count
+	}
+	stats.ItemErrors = make(map[string]int64, len(t.stats.ItemErrors))
+	for errorType, count := range t.stats.ItemErrors {
+		stats.ItemErrors[errorType] = count
+	}
+	if requests := stats.BulkRequests + stats.SearchRequests; requests > 0 {
+		stats.ThrottleRate = float64(stats.Throttled) / float64(requests) * 100
+	}
+	return stats
+}
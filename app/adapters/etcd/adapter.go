@@ -0,0 +1,206 @@
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"abc-runner/app/adapters/etcd/config"
+	"abc-runner/app/adapters/etcd/connection"
+	"abc-runner/app/adapters/etcd/operations"
+	"abc-runner/app/core/interfaces"
+)
+
+// EtcdAdapter etcd v3协议适配器 - 遵循统一架构模式
+// 职责：客户端管理、测试数据准备、watch流管理、健康检查
+type EtcdAdapter struct {
+	config           *config.EtcdConfig
+	client           *connection.Client
+	etcdOperations   *operations.EtcdExecutor
+	watches          *operations.WatchTracker
+	metricsCollector interfaces.DefaultMetricsCollector
+	mu               sync.RWMutex
+	isConnected      bool
+	seeded           bool
+	members          []connection.MemberStatus
+
+	// 统计信息
+	totalOperations  int64
+	failedOperations int64
+}
+
+// NewEtcdAdapter 创建etcd适配器
+func NewEtcdAdapter(metricsCollector interfaces.DefaultMetricsCollector) *EtcdAdapter {
+	return &EtcdAdapter{
+		metricsCollector: metricsCollector,
+	}
+}
+
+// Connect 连接集群；读取测试按需填充键空间，watch测试打开watch流
+func (e *EtcdAdapter) Connect(ctx context.Context, cfg interfaces.Config) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	etcdConfig, ok := cfg.(*config.EtcdConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type for etcd adapter: expected *config.EtcdConfig, got %T", cfg)
+	}
+
+	if err := etcdConfig.Validate(); err != nil {
+		return fmt.Errorf("config validation failed: %w", err)
+	}
+	e.config = etcdConfig
+
+	client, err := connection.NewClient(ctx, etcdConfig)
+	if err != nil {
+		return err
+	}
+
+	if etcdConfig.EtcdSpecific.Setup && etcdConfig.BenchMark.ReadsKeys() {
+		seedCtx, cancel := context.WithTimeout(ctx, etcdConfig.Connection.Timeout*10)
+		seeded, err := operations.Seed(seedCtx, client, etcdConfig.EtcdSpecific,
+			etcdConfig.BenchMark.RandomKeys, etcdConfig.BenchMark.DataSize)
+		cancel()
+		e.seeded = seeded
+		if err != nil {
+			client.Close()
+			return err
+		}
+	}
+
+	var watches *operations.WatchTracker
+	if etcdConfig.BenchMark.TestCase == config.TestCaseWatch {
+		watchCtx, cancel := context.WithTimeout(ctx, etcdConfig.Connection.Timeout)
+		watches, err = operations.StartWatches(watchCtx, client, etcdConfig.EtcdSpecific, etcdConfig.Connection.Timeout)
+		cancel()
+		if err != nil {
+			client.Close()
+			return err
+		}
+	}
+
+	e.client = client
+	e.watches = watches
+	e.etcdOperations = operations.NewEtcdExecutor(client, etcdConfig, watches)
+	e.isConnected = true
+	return nil
+}
+
+// Execute 执行操作 - 使用执行器处理
+func (e *EtcdAdapter) Execute(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	if !e.isConnected {
+		return nil, fmt.Errorf("adapter not connected")
+	}
+
+	atomic.AddInt64(&e.totalOperations, 1)
+	result, err := e.etcdOperations.ExecuteOperation(ctx, operation)
+	if err != nil {
+		atomic.AddInt64(&e.failedOperations, 1)
+	}
+	return result, err
+}
+
+// Close 关闭watch流与连接
+func (e *EtcdAdapter) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.watches != nil {
+		e.watches.Close()
+		e.watches = nil
+	}
+	if e.client != nil {
+		e.client.Close()
+		e.client = nil
+	}
+	e.isConnected = false
+	return nil
+}
+
+// GetProtocolMetrics 获取协议特定指标
+func (e *EtcdAdapter) GetProtocolMetrics() map[string]interface{} {
+	metrics := map[string]interface{}{
+		"protocol":          "etcd",
+		"total_operations":  atomic.LoadInt64(&e.totalOperations),
+		"failed_operations": atomic.LoadInt64(&e.failedOperations),
+	}
+
+	if e.config != nil {
+		metrics["endpoints"] = e.config.Connection.Endpoints
+		metrics["key_prefix"] = e.config.EtcdSpecific.KeyPrefix
+	}
+	if stats := e.GetOperationStats(); stats != nil {
+		metrics["operation_stats"] = stats
+	}
+	if e.watches != nil {
+		metrics["watch"] = e.watches.Stats()
+	}
+	if e.client != nil {
+		metrics["reauthentications"] = e.client.Reauthentications()
+	}
+
+	return metrics
+}
+
+// GetOperationStats 获取按操作类型的统计，未连接时返回nil
+func (e *EtcdAdapter) GetOperationStats() []operations.OperationStats {
+	if e.etcdOperations == nil {
+		return nil
+	}
+	return e.etcdOperations.OperationStats()
+}
+
+// GetWatchStats 等待已写入的事件送达后获取watch统计，非watch测试返回nil
+func (e *EtcdAdapter) GetWatchStats() *operations.WatchStats {
+	if e.watches == nil {
+		return nil
+	}
+	e.watches.Wait()
+	stats := e.watches.Stats()
+	return &stats
+}
+
+// GetMemberStatus 获取健康检查时各成员的状态
+func (e *EtcdAdapter) GetMemberStatus() []connection.MemberStatus {
+	return e.members
+}
+
+// Seeded 本次运行是否由setup填充了键空间
+func (e *EtcdAdapter) Seeded() bool {
+	return e.seeded
+}
+
+// HealthCheck 健康检查：获取每个成员的状态，至少一个成员可达且集群有leader
+func (e *EtcdAdapter) HealthCheck(ctx context.Context) error {
+	if !e.isConnected {
+		return fmt.Errorf("adapter not connected")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.config.Connection.Timeout)
+	defer cancel()
+	e.members = e.client.Status(ctx)
+
+	var lastErr error
+	for _, member := range e.members {
+		switch {
+		case member.Err != nil:
+			lastErr = fmt.Errorf("status of %s failed: %w", member.Endpoint, member.Err)
+		case member.Status.Leader == 0:
+			lastErr = fmt.Errorf("member %s has no leader", member.Endpoint)
+		default:
+			return nil
+		}
+	}
+	return fmt.Errorf("no healthy etcd member: %w", lastErr)
+}
+
+// GetProtocolName 获取协议名称
+func (e *EtcdAdapter) GetProtocolName() string {
+	return "etcd"
+}
+
+// GetMetricsCollector 获取指标收集器
+func (e *EtcdAdapter) GetMetricsCollector() interfaces.DefaultMetricsCollector {
+	return e.metricsCollector
+}
@@ -0,0 +1,328 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+)
+
+// 测试用例
+const (
+	TestCasePut   = "put"   // 写入键空间中的键
+	TestCaseGet   = "get"   // 读取单个键
+	TestCaseRange = "range" // 从随机键开始按前缀范围扫描
+	TestCaseMixed = "mixed" // 按read_percent混合读取与写入
+	TestCaseWatch = "watch" // 写入被多个watcher监听的前缀，测量事件扇出延迟
+	TestCaseLease = "lease" // 租约创建、绑定键、续约与撤销的完整周期
+)
+
+// TestCases 支持的测试用例
+var TestCases = []string{TestCasePut, TestCaseGet, TestCaseRange, TestCaseMixed, TestCaseWatch, TestCaseLease}
+
+// EtcdConfig etcd协议配置
+type EtcdConfig struct {
+	Protocol     string             `yaml:"protocol" json:"protocol"`
+	Connection   ConnectionConfig   `yaml:"connection" json:"connection"`
+	BenchMark    BenchmarkConfig    `yaml:"benchmark" json:"benchmark"`
+	EtcdSpecific EtcdSpecificConfig `yaml:"etcd_specific" json:"etcd_specific"`
+}
+
+// ConnectionConfig etcd连接配置
+type ConnectionConfig struct {
+	Endpoints   []string      `yaml:"endpoints" json:"endpoints"`     // 成员的客户端地址（host:port）
+	Connections int           `yaml:"connections" json:"connections"` // 每个成员的gRPC连接数，请求在全部连接间轮转
+	Username    string        `yaml:"username" json:"username"`       // 启用认证时的用户名
+	Password    string        `yaml:"password" json:"password"`
+	Timeout     time.Duration `yaml:"timeout" json:"timeout"` // 建立连接与单次请求的超时
+	TLS         TLSConfig     `yaml:"tls" json:"tls"`
+}
+
+// TLSConfig TLS配置
+type TLSConfig struct {
+	Enabled            bool   `yaml:"enabled" json:"enabled"`
+	CAFile             string `yaml:"ca_file" json:"ca_file"`
+	CertFile           string `yaml:"cert_file" json:"cert_file"` // 客户端证书（client-cert-auth）
+	KeyFile            string `yaml:"key_file" json:"key_file"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify" json:"insecure_skip_verify"`
+	ServerName         string `yaml:"server_name" json:"server_name"`
+}
+
+// BenchmarkConfig etcd基准测试配置
+type BenchmarkConfig struct {
+	Total       int           `yaml:"total" json:"total"`
+	Parallels   int           `yaml:"parallels" json:"parallels"`
+	TestCase    string        `yaml:"test_case" json:"test_case"`
+	Duration    time.Duration `yaml:"duration" json:"duration"`
+	Rate        int           `yaml:"rate" json:"rate"`                 // 每秒操作数上限，0表示不限速
+	DataSize    int           `yaml:"data_size" json:"data_size"`       // 值的字节数
+	RandomKeys  int           `yaml:"random_keys" json:"random_keys"`   // 键空间大小
+	ReadPercent int           `yaml:"read_percent" json:"read_percent"` // mixed中读取的比例(0-100)
+}
+
+// EtcdSpecificConfig etcd特定配置
+type EtcdSpecificConfig struct {
+	KeyPrefix           string `yaml:"key_prefix" json:"key_prefix"`                     // 测试键的前缀，range与watch作用于该前缀
+	SerializablePercent int    `yaml:"serializable_percent" json:"serializable_percent"` // 读取中使用serializable读的比例(0-100)，其余为线性一致读
+	RangeLimit          int    `yaml:"range_limit" json:"range_limit"`                   // range每次最多返回的键数
+	Setup               bool   `yaml:"setup" json:"setup"`                               // 读取测试前前缀下没有键时写入整个键空间
+	Watchers            int    `yaml:"watchers" json:"watchers"`                         // watch测试中监听前缀的watcher数
+	LeaseTTL            int    `yaml:"lease_ttl" json:"lease_ttl"`                       // 租约的TTL（秒）
+	KeepAlives          int    `yaml:"keep_alives" json:"keep_alives"`                   // 每个租约撤销前的续约次数
+}
+
+// NewDefaultEtcdConfig 创建默认etcd配置
+func NewDefaultEtcdConfig() *EtcdConfig {
+	return &EtcdConfig{
+		Protocol: "etcd",
+		Connection: ConnectionConfig{
+			Endpoints:   []string{"127.0.0.1:2379"},
+			Connections: 1,
+			Timeout:     5 * time.Second,
+		},
+		BenchMark: BenchmarkConfig{
+			Total:       10000,
+			Parallels:   10,
+			TestCase:    TestCasePut,
+			DataSize:    256,
+			RandomKeys:  1000,
+			ReadPercent: 80,
+		},
+		EtcdSpecific: EtcdSpecificConfig{
+			KeyPrefix:  "/abc-runner/",
+			RangeLimit: 100,
+			Setup:      true,
+			Watchers:   10,
+			LeaseTTL:   10,
+			KeepAlives: 1,
+		},
+	}
+}
+
+// GetProtocol 实现Config接口
+func (c *EtcdConfig) GetProtocol() string {
+	return c.Protocol
+}
+
+// GetConnection 实现Config接口
+func (c *EtcdConfig) GetConnection() interfaces.ConnectionConfig {
+	return &c.Connection
+}
+
+// GetBenchmark 实现Config接口
+func (c *EtcdConfig) GetBenchmark() interfaces.BenchmarkConfig {
+	return &c.BenchMark
+}
+
+// Validate 实现Config接口
+func (c *EtcdConfig) Validate() error {
+	if len(c.Connection.Endpoints) == 0 {
+		return fmt.Errorf("at least one endpoint is required")
+	}
+	for _, endpoint := range c.Connection.Endpoints {
+		if _, _, err := net.SplitHostPort(endpoint); err != nil {
+			return fmt.Errorf("invalid endpoint %q (expected host:port): %w", endpoint, err)
+		}
+	}
+	if c.Connection.Connections <= 0 {
+		return fmt.Errorf("connections must be positive")
+	}
+	if c.Connection.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+	if c.Connection.Password != "" && c.Connection.Username == "" {
+		return fmt.Errorf("password requires a username")
+	}
+	if (c.Connection.TLS.CertFile == "") != (c.Connection.TLS.KeyFile == "") {
+		return fmt.Errorf("tls cert_file and key_file must be set together")
+	}
+
+	if c.BenchMark.Total <= 0 {
+		return fmt.Errorf("total operations must be greater than 0")
+	}
+	if c.BenchMark.Parallels <= 0 {
+		return fmt.Errorf("parallel connections must be greater than 0")
+	}
+	if !isTestCase(c.BenchMark.TestCase) {
+		return fmt.Errorf("invalid test case: %s, valid options: %s", c.BenchMark.TestCase, strings.Join(TestCases, ", "))
+	}
+	if c.BenchMark.Rate < 0 {
+		return fmt.Errorf("rate cannot be negative")
+	}
+	if c.BenchMark.DataSize < 8 {
+		return fmt.Errorf("data size must be at least 8 bytes (values carry a write timestamp)")
+	}
+	if c.BenchMark.RandomKeys <= 0 {
+		return fmt.Errorf("random keys must be positive")
+	}
+	if c.BenchMark.ReadPercent < 0 || c.BenchMark.ReadPercent > 100 {
+		return fmt.Errorf("read percent must be between 0 and 100")
+	}
+
+	specific := c.EtcdSpecific
+	if specific.KeyPrefix == "" {
+		return fmt.Errorf("key prefix cannot be empty")
+	}
+	if specific.SerializablePercent < 0 || specific.SerializablePercent > 100 {
+		return fmt.Errorf("serializable percent must be between 0 and 100")
+	}
+	if specific.RangeLimit <= 0 {
+		return fmt.Errorf("range limit must be positive")
+	}
+	if c.BenchMark.TestCase == TestCaseWatch && specific.Watchers <= 0 {
+		return fmt.Errorf("watchers must be positive for the watch test case")
+	}
+	if specific.LeaseTTL <= 0 {
+		return fmt.Errorf("lease ttl must be positive")
+	}
+	if specific.KeepAlives < 0 {
+		return fmt.Errorf("keep alives cannot be negative")
+	}
+
+	return nil
+}
+
+// isTestCase 是否为支持的测试用例
+func isTestCase(testCase string) bool {
+	for _, supported := range TestCases {
+		if testCase == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadsKeys 测试用例是否读取键空间，读取前需要写入数据
+func (b *BenchmarkConfig) ReadsKeys() bool {
+	switch b.TestCase {
+	case TestCaseGet, TestCaseRange:
+		return true
+	case TestCaseMixed:
+		return b.ReadPercent > 0
+	}
+	return false
+}
+
+// PrefixEnd 前缀范围的结束键：前缀最后一个不为0xff的字节加1
+func (s *EtcdSpecificConfig) PrefixEnd() string {
+	end := []byte(s.KeyPrefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return string(end[:i+1])
+		}
+	}
+	// 前缀全为0xff时范围一直到键空间末尾
+	return "\x00"
+}
+
+// Key 键空间中第index个键
+func (s *EtcdSpecificConfig) Key(index int) string {
+	return fmt.Sprintf("%skey-%08d", s.KeyPrefix, index)
+}
+
+// GetTarget 获取目标描述
+func (c *ConnectionConfig) GetTarget() string {
+	return strings.Join(c.Endpoints, ",")
+}
+
+// Clone 实现Config接口
+func (c *EtcdConfig) Clone() interfaces.Config {
+	clone := *c
+	clone.Connection.Endpoints = append([]string(nil), c.Connection.Endpoints...)
+	return &clone
+}
+
+// ConnectionConfig接口实现
+
+// GetAddresses 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetAddresses() []string {
+	return c.Endpoints
+}
+
+// GetCredentials 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetCredentials() map[string]string {
+	if c.Username == "" {
+		return map[string]string{}
+	}
+	return map[string]string{"username": c.Username, "password": c.Password}
+}
+
+// GetPoolConfig 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetPoolConfig() interfaces.PoolConfig {
+	return &EmptyPoolConfig{}
+}
+
+// GetTimeout 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetTimeout() time.Duration {
+	return c.Timeout
+}
+
+// EmptyPoolConfig 空的连接池配置（gRPC连接上的请求多路复用）
+type EmptyPoolConfig struct{}
+
+func (p *EmptyPoolConfig) GetPoolSize() int                    { return 0 }
+func (p *EmptyPoolConfig) GetMinIdle() int                     { return 0 }
+func (p *EmptyPoolConfig) GetMaxIdle() int                     { return 0 }
+func (p *EmptyPoolConfig) GetIdleTimeout() time.Duration       { return 0 }
+func (p *EmptyPoolConfig) GetConnectionTimeout() time.Duration { return 0 }
+
+// BenchmarkConfig接口实现
+
+// GetTotal 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTotal() int {
+	return b.Total
+}
+
+// GetParallels 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetParallels() int {
+	return b.Parallels
+}
+
+// GetDataSize 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetDataSize() int {
+	return b.DataSize
+}
+
+// GetTTL 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTTL() time.Duration {
+	return 0
+}
+
+// GetReadPercent 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetReadPercent() int {
+	switch b.TestCase {
+	case TestCaseGet, TestCaseRange:
+		return 100
+	case TestCaseMixed:
+		return b.ReadPercent
+	}
+	return 0
+}
+
+// GetRandomKeys 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetRandomKeys() int {
+	return b.RandomKeys
+}
+
+// GetTestCase 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTestCase() string {
+	return b.TestCase
+}
+
+// GetDuration 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetDuration() time.Duration {
+	return b.Duration
+}
+
+// GetTimeout 实现execution.BenchmarkConfig接口，请求超时由connection.timeout控制
+func (b *BenchmarkConfig) GetTimeout() time.Duration {
+	return 0
+}
+
+// GetRampUp 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetRampUp() time.Duration {
+	return 0
+}
@@ -0,0 +1,81 @@
+package config
+
+import "testing"
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*EtcdConfig)
+		valid  bool
+	}{
+		{"default", func(c *EtcdConfig) {}, true},
+		{"cluster", func(c *EtcdConfig) {
+			c.Connection.Endpoints = []string{"10.0.0.1:2379", "10.0.0.2:2379", "[::1]:2379"}
+		}, true},
+		{"watch", func(c *EtcdConfig) { c.BenchMark.TestCase = TestCaseWatch }, true},
+		{"lease without keepalives", func(c *EtcdConfig) {
+			c.BenchMark.TestCase = TestCaseLease
+			c.EtcdSpecific.KeepAlives = 0
+		}, true},
+		{"auth", func(c *EtcdConfig) {
+			c.Connection.Username = "root"
+			c.Connection.Password = "secret"
+		}, true},
+		{"no endpoints", func(c *EtcdConfig) { c.Connection.Endpoints = nil }, false},
+		{"endpoint without port", func(c *EtcdConfig) { c.Connection.Endpoints = []string{"127.0.0.1"} }, false},
+		{"password without user", func(c *EtcdConfig) { c.Connection.Password = "secret" }, false},
+		{"cert without key", func(c *EtcdConfig) { c.Connection.TLS.CertFile = "client.pem" }, false},
+		{"unknown test case", func(c *EtcdConfig) { c.BenchMark.TestCase = "txn" }, false},
+		{"value too small", func(c *EtcdConfig) { c.BenchMark.DataSize = 4 }, false},
+		{"serializable percent", func(c *EtcdConfig) { c.EtcdSpecific.SerializablePercent = 101 }, false},
+		{"empty prefix", func(c *EtcdConfig) { c.EtcdSpecific.KeyPrefix = "" }, false},
+		{"watch without watchers", func(c *EtcdConfig) {
+			c.BenchMark.TestCase = TestCaseWatch
+			c.EtcdSpecific.Watchers = 0
+		}, false},
+		{"zero lease ttl", func(c *EtcdConfig) { c.EtcdSpecific.LeaseTTL = 0 }, false},
+		{"negative rate", func(c *EtcdConfig) { c.BenchMark.Rate = -1 }, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := NewDefaultEtcdConfig()
+			test.modify(cfg)
+			if err := cfg.Validate(); (err == nil) != test.valid {
+				t.Errorf("Validate() error = %v, want valid %v", err, test.valid)
+			}
+		})
+	}
+}
+
+func TestPrefixEnd(t *testing.T) {
+	tests := map[string]string{
+		"/abc-runner/": "/abc-runner0",
+		"a\xff":        "b",
+		"\xff\xff":     "\x00",
+	}
+	for prefix, want := range tests {
+		specific := EtcdSpecificConfig{KeyPrefix: prefix}
+		if end := specific.PrefixEnd(); end != want {
+			t.Errorf("PrefixEnd(%q) = %q, want %q", prefix, end, want)
+		}
+	}
+	specific := EtcdSpecificConfig{KeyPrefix: "/p/"}
+	if key := specific.Key(42); key != "/p/key-00000042" {
+		t.Errorf("Key(42) = %q", key)
+	}
+}
+
+func TestReadPercent(t *testing.T) {
+	cfg := NewDefaultEtcdConfig()
+	for testCase, want := range map[string]int{
+		TestCasePut: 0, TestCaseGet: 100, TestCaseRange: 100, TestCaseMixed: 80, TestCaseWatch: 0, TestCaseLease: 0,
+	} {
+		cfg.BenchMark.TestCase = testCase
+		if got := cfg.BenchMark.GetReadPercent(); got != want {
+			t.Errorf("%s: GetReadPercent() = %d, want %d", testCase, got, want)
+		}
+		if reads := cfg.BenchMark.ReadsKeys(); reads != (want > 0) {
+			t.Errorf("%s: ReadsKeys() = %v", testCase, reads)
+		}
+	}
+}
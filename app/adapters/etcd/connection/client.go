@@ -0,0 +1,328 @@
+package connection
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"abc-runner/app/adapters/etcd/config"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// etcd v3 gRPC方法
+const (
+	MethodRange          = "/etcdserverpb.KV/Range"
+	MethodPut            = "/etcdserverpb.KV/Put"
+	MethodWatch          = "/etcdserverpb.Watch/Watch"
+	MethodLeaseGrant     = "/etcdserverpb.Lease/LeaseGrant"
+	MethodLeaseRevoke    = "/etcdserverpb.Lease/LeaseRevoke"
+	MethodLeaseKeepAlive = "/etcdserverpb.Lease/LeaseKeepAlive"
+	MethodAuthenticate   = "/etcdserverpb.Auth/Authenticate"
+	MethodStatus         = "/etcdserverpb.Maintenance/Status"
+)
+
+// bidiStream 双向流的描述
+var bidiStream = &grpc.StreamDesc{ServerStreams: true, ClientStreams: true}
+
+// Message 可编解码的etcd消息
+type Message interface {
+	Marshal() []byte
+	Unmarshal(data []byte) error
+}
+
+// memberConn 到一个成员的gRPC连接，启用认证时持有该连接上获取的令牌
+// simple令牌只在签发的成员上有效，因此每个连接各自认证
+type memberConn struct {
+	endpoint string
+	conn     *grpc.ClientConn
+	authMu   sync.Mutex
+	token    atomic.Value // string
+}
+
+// MemberStatus 一个成员的状态，Err非nil表示该成员不可达
+type MemberStatus struct {
+	Endpoint string
+	Status   *StatusResponse
+	Err      error
+}
+
+// Client etcd v3客户端
+// 每个成员建立connections个gRPC连接，一元请求在全部连接间轮转；消息按字段号直接编解码
+type Client struct {
+	conns    []*memberConn
+	next     atomic.Uint64
+	username string
+	password string
+	reauths  atomic.Int64
+}
+
+// NewClient 连接全部成员，启用认证时在每个连接上获取令牌
+func NewClient(ctx context.Context, cfg *config.EtcdConfig) (*Client, error) {
+	opts, err := dialOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &Client{
+		username: cfg.Connection.Username,
+		password: cfg.Connection.Password,
+	}
+	for _, endpoint := range cfg.Connection.Endpoints {
+		for i := 0; i < cfg.Connection.Connections; i++ {
+			conn, err := grpc.NewClient(endpoint, opts...)
+			if err != nil {
+				client.Close()
+				return nil, fmt.Errorf("failed to create connection to etcd endpoint %s: %w", endpoint, err)
+			}
+			conn.Connect()
+			client.conns = append(client.conns, &memberConn{endpoint: endpoint, conn: conn})
+		}
+	}
+
+	if client.username != "" {
+		authCtx, cancel := context.WithTimeout(ctx, cfg.Connection.Timeout)
+		defer cancel()
+		for _, member := range client.conns {
+			if err := client.authenticate(authCtx, member); err != nil {
+				client.Close()
+				return nil, err
+			}
+		}
+	}
+	return client, nil
+}
+
+// dialOptions 构建连接选项：TLS或明文，所有调用使用透传编解码器
+func dialOptions(cfg *config.EtcdConfig) ([]grpc.DialOption, error) {
+	creds := insecure.NewCredentials()
+	if cfg.Connection.TLS.Enabled {
+		tlsConfig, err := buildTLSConfig(cfg.Connection.TLS)
+		if err != nil {
+			return nil, err
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+	return []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(RawCodec{})),
+	}, nil
+}
+
+// buildTLSConfig 构建TLS配置
+func buildTLSConfig(cfg config.TLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         cfg.ServerName,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if cfg.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// authenticate 在连接上获取令牌
+func (c *Client) authenticate(ctx context.Context, member *memberConn) error {
+	member.authMu.Lock()
+	defer member.authMu.Unlock()
+
+	in := (&AuthenticateRequest{Name: c.username, Password: c.password}).Marshal()
+	var out []byte
+	if err := member.conn.Invoke(ctx, MethodAuthenticate, &in, &out); err != nil {
+		return fmt.Errorf("failed to authenticate as %s on %s: %w", c.username, member.endpoint, err)
+	}
+	var response AuthenticateResponse
+	if err := response.Unmarshal(out); err != nil {
+		return err
+	}
+	member.token.Store(response.Token)
+	return nil
+}
+
+// outgoing 为请求附加连接上的令牌
+func (m *memberConn) outgoing(ctx context.Context) context.Context {
+	if token, _ := m.token.Load().(string); token != "" {
+		return metadata.AppendToOutgoingContext(ctx, "token", token)
+	}
+	return ctx
+}
+
+// pick 轮转选择连接
+func (c *Client) pick() *memberConn {
+	return c.conns[(c.next.Add(1)-1)%uint64(len(c.conns))]
+}
+
+// invoke 发送一元请求；令牌失效（过期或成员重启）时重新认证并重试一次
+func (c *Client) invoke(ctx context.Context, member *memberConn, method string, request, response Message) error {
+	in := request.Marshal()
+	var out []byte
+	err := member.conn.Invoke(member.outgoing(ctx), method, &in, &out)
+	if err != nil && c.username != "" && status.Code(err) == codes.Unauthenticated {
+		c.reauths.Add(1)
+		if err = c.authenticate(ctx, member); err == nil {
+			err = member.conn.Invoke(member.outgoing(ctx), method, &in, &out)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return response.Unmarshal(out)
+}
+
+// Range 读取单个键或范围
+func (c *Client) Range(ctx context.Context, request *RangeRequest) (*RangeResponse, error) {
+	var response RangeResponse
+	if err := c.invoke(ctx, c.pick(), MethodRange, request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Put 写入键
+func (c *Client) Put(ctx context.Context, request *PutRequest) (*PutResponse, error) {
+	var response PutResponse
+	if err := c.invoke(ctx, c.pick(), MethodPut, request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// LeaseGrant 创建TTL秒的租约
+func (c *Client) LeaseGrant(ctx context.Context, ttl int64) (*LeaseGrantResponse, error) {
+	var response LeaseGrantResponse
+	if err := c.invoke(ctx, c.pick(), MethodLeaseGrant, &LeaseGrantRequest{TTL: ttl}, &response); err != nil {
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, fmt.Errorf("lease grant failed: %s", response.Error)
+	}
+	return &response, nil
+}
+
+// LeaseRevoke 撤销租约，绑定的键随之删除
+func (c *Client) LeaseRevoke(ctx context.Context, id int64) error {
+	var response LeaseRevokeResponse
+	return c.invoke(ctx, c.pick(), MethodLeaseRevoke, &LeaseIDRequest{ID: id}, &response)
+}
+
+// Status 依次获取每个成员的状态
+func (c *Client) Status(ctx context.Context) []MemberStatus {
+	var statuses []MemberStatus
+	seen := make(map[string]bool)
+	for _, member := range c.conns {
+		if seen[member.endpoint] {
+			continue
+		}
+		seen[member.endpoint] = true
+		var response StatusResponse
+		err := c.invoke(ctx, member, MethodStatus, &emptyMessage{}, &response)
+		if err != nil {
+			statuses = append(statuses, MemberStatus{Endpoint: member.endpoint, Err: err})
+			continue
+		}
+		statuses = append(statuses, MemberStatus{Endpoint: member.endpoint, Status: &response})
+	}
+	return statuses
+}
+
+// Stream etcd双向流（Watch与LeaseKeepAlive）
+type Stream struct {
+	stream grpc.ClientStream
+}
+
+// openStream 在轮转选出的连接上打开双向流，流的生命周期由ctx控制
+func (c *Client) openStream(ctx context.Context, method string) (*Stream, error) {
+	member := c.pick()
+	stream, err := member.conn.NewStream(member.outgoing(ctx), bidiStream, method)
+	if err != nil {
+		return nil, err
+	}
+	return &Stream{stream: stream}, nil
+}
+
+// Watch 打开watch流
+func (c *Client) Watch(ctx context.Context) (*Stream, error) {
+	return c.openStream(ctx, MethodWatch)
+}
+
+// LeaseKeepAlive 打开续约流
+func (c *Client) LeaseKeepAlive(ctx context.Context) (*Stream, error) {
+	return c.openStream(ctx, MethodLeaseKeepAlive)
+}
+
+// Send 发送一条消息
+func (s *Stream) Send(message Message) error {
+	in := message.Marshal()
+	return s.stream.SendMsg(&in)
+}
+
+// Recv 接收一条消息
+func (s *Stream) Recv(message Message) error {
+	var out []byte
+	if err := s.stream.RecvMsg(&out); err != nil {
+		return err
+	}
+	return message.Unmarshal(out)
+}
+
+// CloseSend 结束发送方向
+func (s *Stream) CloseSend() error {
+	return s.stream.CloseSend()
+}
+
+// Endpoints 连接的成员数
+func (c *Client) Endpoints() int {
+	seen := make(map[string]bool)
+	for _, member := range c.conns {
+		seen[member.endpoint] = true
+	}
+	return len(seen)
+}
+
+// Connections gRPC连接总数
+func (c *Client) Connections() int {
+	return len(c.conns)
+}
+
+// Reauthentications 令牌失效后重新认证的次数
+func (c *Client) Reauthentications() int64 {
+	return c.reauths.Load()
+}
+
+// Close 关闭全部连接
+func (c *Client) Close() {
+	for _, member := range c.conns {
+		member.conn.Close()
+	}
+	c.conns = nil
+}
+
+// emptyMessage 空请求（如StatusRequest）
+type emptyMessage struct{}
+
+func (emptyMessage) Marshal() []byte          { return nil }
+func (emptyMessage) Unmarshal(_ []byte) error { return nil }
@@ -0,0 +1,32 @@
+package connection
+
+import "fmt"
+
+// RawCodec 透传已编码消息的gRPC编解码器
+// etcd的protobuf消息由messages.go按字段号直接编解码，不依赖生成的代码；
+// 名称为"proto"，与服务端按content-type选择的编解码器一致
+type RawCodec struct{}
+
+// Marshal 返回已编码的消息
+func (RawCodec) Marshal(v interface{}) ([]byte, error) {
+	message, ok := v.(*[]byte)
+	if !ok {
+		return nil, fmt.Errorf("raw codec: unexpected message type %T", v)
+	}
+	return *message, nil
+}
+
+// Unmarshal 复制收到的消息，gRPC会复用data的缓冲区
+func (RawCodec) Unmarshal(data []byte, v interface{}) error {
+	message, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("raw codec: unexpected message type %T", v)
+	}
+	*message = append((*message)[:0], data...)
+	return nil
+}
+
+// Name 编解码器名称
+func (RawCodec) Name() string {
+	return "proto"
+}
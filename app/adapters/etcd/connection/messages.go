@@ -0,0 +1,594 @@
+package connection
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// etcd v3 API（etcdserverpb）中用到的消息，字段号与rpc.proto、kv.proto一致
+// 每个消息都可双向编解码，测试中的模拟服务端复用同一套编码
+
+// ResponseHeader 响应头
+type ResponseHeader struct {
+	ClusterID uint64
+	MemberID  uint64
+	Revision  int64
+	RaftTerm  uint64
+}
+
+func (h *ResponseHeader) Marshal() []byte {
+	var b []byte
+	b = appendVarint(b, 1, h.ClusterID)
+	b = appendVarint(b, 2, h.MemberID)
+	b = appendVarint(b, 3, uint64(h.Revision))
+	b = appendVarint(b, 4, h.RaftTerm)
+	return b
+}
+
+func (h *ResponseHeader) Unmarshal(data []byte) error {
+	return decodeMessage(data, func(num protowire.Number, raw []byte, value uint64) error {
+		switch num {
+		case 1:
+			h.ClusterID = value
+		case 2:
+			h.MemberID = value
+		case 3:
+			h.Revision = int64(value)
+		case 4:
+			h.RaftTerm = value
+		}
+		return nil
+	})
+}
+
+// KeyValue 键值对（mvccpb.KeyValue）
+type KeyValue struct {
+	Key            []byte
+	CreateRevision int64
+	ModRevision    int64
+	Version        int64
+	Value          []byte
+	Lease          int64
+}
+
+func (kv *KeyValue) Marshal() []byte {
+	var b []byte
+	b = appendBytes(b, 1, kv.Key)
+	b = appendVarint(b, 2, uint64(kv.CreateRevision))
+	b = appendVarint(b, 3, uint64(kv.ModRevision))
+	b = appendVarint(b, 4, uint64(kv.Version))
+	b = appendBytes(b, 5, kv.Value)
+	b = appendVarint(b, 6, uint64(kv.Lease))
+	return b
+}
+
+func (kv *KeyValue) Unmarshal(data []byte) error {
+	return decodeMessage(data, func(num protowire.Number, raw []byte, value uint64) error {
+		switch num {
+		case 1:
+			kv.Key = raw
+		case 2:
+			kv.CreateRevision = int64(value)
+		case 3:
+			kv.ModRevision = int64(value)
+		case 4:
+			kv.Version = int64(value)
+		case 5:
+			kv.Value = raw
+		case 6:
+			kv.Lease = int64(value)
+		}
+		return nil
+	})
+}
+
+// RangeRequest 读取单个键（RangeEnd为空）或[Key, RangeEnd)范围内的键
+type RangeRequest struct {
+	Key          []byte
+	RangeEnd     []byte
+	Limit        int64
+	Serializable bool // true时由收到请求的成员直接读取本地数据，否则为经过raft确认的线性一致读
+	KeysOnly     bool
+	CountOnly    bool
+}
+
+func (r *RangeRequest) Marshal() []byte {
+	var b []byte
+	b = appendBytes(b, 1, r.Key)
+	b = appendBytes(b, 2, r.RangeEnd)
+	b = appendVarint(b, 3, uint64(r.Limit))
+	b = appendBool(b, 7, r.Serializable)
+	b = appendBool(b, 8, r.KeysOnly)
+	b = appendBool(b, 9, r.CountOnly)
+	return b
+}
+
+func (r *RangeRequest) Unmarshal(data []byte) error {
+	return decodeMessage(data, func(num protowire.Number, raw []byte, value uint64) error {
+		switch num {
+		case 1:
+			r.Key = raw
+		case 2:
+			r.RangeEnd = raw
+		case 3:
+			r.Limit = int64(value)
+		case 7:
+			r.Serializable = value != 0
+		case 8:
+			r.KeysOnly = value != 0
+		case 9:
+			r.CountOnly = value != 0
+		}
+		return nil
+	})
+}
+
+// RangeResponse 范围读取的响应
+type RangeResponse struct {
+	Header ResponseHeader
+	Kvs    []KeyValue
+	More   bool  // 超过Limit时还有未返回的键
+	Count  int64 // 范围内的键总数
+}
+
+func (r *RangeResponse) Marshal() []byte {
+	b := appendMessage(nil, 1, r.Header.Marshal())
+	for i := range r.Kvs {
+		b = appendMessage(b, 2, r.Kvs[i].Marshal())
+	}
+	b = appendBool(b, 3, r.More)
+	b = appendVarint(b, 4, uint64(r.Count))
+	return b
+}
+
+func (r *RangeResponse) Unmarshal(data []byte) error {
+	return decodeMessage(data, func(num protowire.Number, raw []byte, value uint64) error {
+		switch num {
+		case 1:
+			return r.Header.Unmarshal(raw)
+		case 2:
+			var kv KeyValue
+			if err := kv.Unmarshal(raw); err != nil {
+				return err
+			}
+			r.Kvs = append(r.Kvs, kv)
+		case 3:
+			r.More = value != 0
+		case 4:
+			r.Count = int64(value)
+		}
+		return nil
+	})
+}
+
+// PutRequest 写入请求，Lease非0时键绑定到该租约
+type PutRequest struct {
+	Key   []byte
+	Value []byte
+	Lease int64
+}
+
+func (r *PutRequest) Marshal() []byte {
+	var b []byte
+	b = appendBytes(b, 1, r.Key)
+	b = appendBytes(b, 2, r.Value)
+	b = appendVarint(b, 3, uint64(r.Lease))
+	return b
+}
+
+func (r *PutRequest) Unmarshal(data []byte) error {
+	return decodeMessage(data, func(num protowire.Number, raw []byte, value uint64) error {
+		switch num {
+		case 1:
+			r.Key = raw
+		case 2:
+			r.Value = raw
+		case 3:
+			r.Lease = int64(value)
+		}
+		return nil
+	})
+}
+
+// PutResponse 写入的响应
+type PutResponse struct {
+	Header ResponseHeader
+}
+
+func (r *PutResponse) Marshal() []byte {
+	return appendMessage(nil, 1, r.Header.Marshal())
+}
+
+func (r *PutResponse) Unmarshal(data []byte) error {
+	return decodeHeaderOnly(data, &r.Header)
+}
+
+// WatchCreateRequest 创建watch，以WatchRequest.create_request发送
+type WatchCreateRequest struct {
+	Key           []byte
+	RangeEnd      []byte
+	StartRevision int64
+}
+
+// Marshal 编码为WatchRequest
+func (r *WatchCreateRequest) Marshal() []byte {
+	var create []byte
+	create = appendBytes(create, 1, r.Key)
+	create = appendBytes(create, 2, r.RangeEnd)
+	create = appendVarint(create, 3, uint64(r.StartRevision))
+	return appendMessage(nil, 1, create)
+}
+
+// Unmarshal 从WatchRequest解码，不是create_request时返回错误
+func (r *WatchCreateRequest) Unmarshal(data []byte) error {
+	found := false
+	err := decodeMessage(data, func(num protowire.Number, raw []byte, value uint64) error {
+		if num != 1 {
+			return nil
+		}
+		found = true
+		return decodeMessage(raw, func(num protowire.Number, raw []byte, value uint64) error {
+			switch num {
+			case 1:
+				r.Key = raw
+			case 2:
+				r.RangeEnd = raw
+			case 3:
+				r.StartRevision = int64(value)
+			}
+			return nil
+		})
+	})
+	if err == nil && !found {
+		err = fmt.Errorf("watch request is not a create request")
+	}
+	return err
+}
+
+// 事件类型
+const (
+	EventTypePut    = 0
+	EventTypeDelete = 1
+)
+
+// Event watch事件
+type Event struct {
+	Type int
+	Kv   KeyValue
+}
+
+func (e *Event) Marshal() []byte {
+	b := appendVarint(nil, 1, uint64(e.Type))
+	return appendMessage(b, 2, e.Kv.Marshal())
+}
+
+func (e *Event) Unmarshal(data []byte) error {
+	return decodeMessage(data, func(num protowire.Number, raw []byte, value uint64) error {
+		switch num {
+		case 1:
+			e.Type = int(value)
+		case 2:
+			return e.Kv.Unmarshal(raw)
+		}
+		return nil
+	})
+}
+
+// WatchResponse watch流上的响应：创建确认、取消通知或一批事件
+type WatchResponse struct {
+	Header          ResponseHeader
+	WatchID         int64
+	Created         bool
+	Canceled        bool
+	CompactRevision int64 // 起始版本已被压缩时服务端取消watch并给出压缩到的版本
+	CancelReason    string
+	Events          []Event
+}
+
+func (r *WatchResponse) Marshal() []byte {
+	b := appendMessage(nil, 1, r.Header.Marshal())
+	b = appendVarint(b, 2, uint64(r.WatchID))
+	b = appendBool(b, 3, r.Created)
+	b = appendBool(b, 4, r.Canceled)
+	b = appendVarint(b, 5, uint64(r.CompactRevision))
+	b = appendBytes(b, 6, []byte(r.CancelReason))
+	for i := range r.Events {
+		b = appendMessage(b, 11, r.Events[i].Marshal())
+	}
+	return b
+}
+
+func (r *WatchResponse) Unmarshal(data []byte) error {
+	return decodeMessage(data, func(num protowire.Number, raw []byte, value uint64) error {
+		switch num {
+		case 1:
+			return r.Header.Unmarshal(raw)
+		case 2:
+			r.WatchID = int64(value)
+		case 3:
+			r.Created = value != 0
+		case 4:
+			r.Canceled = value != 0
+		case 5:
+			r.CompactRevision = int64(value)
+		case 6:
+			r.CancelReason = string(raw)
+		case 11:
+			var event Event
+			if err := event.Unmarshal(raw); err != nil {
+				return err
+			}
+			r.Events = append(r.Events, event)
+		}
+		return nil
+	})
+}
+
+// LeaseGrantRequest 创建租约，ID为0时由服务端分配
+type LeaseGrantRequest struct {
+	TTL int64
+	ID  int64
+}
+
+func (r *LeaseGrantRequest) Marshal() []byte {
+	b := appendVarint(nil, 1, uint64(r.TTL))
+	return appendVarint(b, 2, uint64(r.ID))
+}
+
+func (r *LeaseGrantRequest) Unmarshal(data []byte) error {
+	return decodeMessage(data, func(num protowire.Number, raw []byte, value uint64) error {
+		switch num {
+		case 1:
+			r.TTL = int64(value)
+		case 2:
+			r.ID = int64(value)
+		}
+		return nil
+	})
+}
+
+// LeaseGrantResponse 创建租约的响应
+type LeaseGrantResponse struct {
+	Header ResponseHeader
+	ID     int64
+	TTL    int64
+	Error  string
+}
+
+func (r *LeaseGrantResponse) Marshal() []byte {
+	b := appendMessage(nil, 1, r.Header.Marshal())
+	b = appendVarint(b, 2, uint64(r.ID))
+	b = appendVarint(b, 3, uint64(r.TTL))
+	return appendBytes(b, 4, []byte(r.Error))
+}
+
+func (r *LeaseGrantResponse) Unmarshal(data []byte) error {
+	return decodeMessage(data, func(num protowire.Number, raw []byte, value uint64) error {
+		switch num {
+		case 1:
+			return r.Header.Unmarshal(raw)
+		case 2:
+			r.ID = int64(value)
+		case 3:
+			r.TTL = int64(value)
+		case 4:
+			r.Error = string(raw)
+		}
+		return nil
+	})
+}
+
+// LeaseIDRequest 只携带租约ID的请求（LeaseRevokeRequest与LeaseKeepAliveRequest）
+type LeaseIDRequest struct {
+	ID int64
+}
+
+func (r *LeaseIDRequest) Marshal() []byte {
+	return appendVarint(nil, 1, uint64(r.ID))
+}
+
+func (r *LeaseIDRequest) Unmarshal(data []byte) error {
+	return decodeMessage(data, func(num protowire.Number, raw []byte, value uint64) error {
+		if num == 1 {
+			r.ID = int64(value)
+		}
+		return nil
+	})
+}
+
+// LeaseRevokeResponse 撤销租约的响应
+type LeaseRevokeResponse struct {
+	Header ResponseHeader
+}
+
+func (r *LeaseRevokeResponse) Marshal() []byte {
+	return appendMessage(nil, 1, r.Header.Marshal())
+}
+
+func (r *LeaseRevokeResponse) Unmarshal(data []byte) error {
+	return decodeHeaderOnly(data, &r.Header)
+}
+
+// LeaseKeepAliveResponse 续约的响应，TTL不大于0表示租约已过期或被撤销
+type LeaseKeepAliveResponse struct {
+	Header ResponseHeader
+	ID     int64
+	TTL    int64
+}
+
+func (r *LeaseKeepAliveResponse) Marshal() []byte {
+	b := appendMessage(nil, 1, r.Header.Marshal())
+	b = appendVarint(b, 2, uint64(r.ID))
+	return appendVarint(b, 3, uint64(r.TTL))
+}
+
+func (r *LeaseKeepAliveResponse) Unmarshal(data []byte) error {
+	return decodeMessage(data, func(num protowire.Number, raw []byte, value uint64) error {
+		switch num {
+		case 1:
+			return r.Header.Unmarshal(raw)
+		case 2:
+			r.ID = int64(value)
+		case 3:
+			r.TTL = int64(value)
+		}
+		return nil
+	})
+}
+
+// AuthenticateRequest 用户名密码认证
+type AuthenticateRequest struct {
+	Name     string
+	Password string
+}
+
+func (r *AuthenticateRequest) Marshal() []byte {
+	b := appendBytes(nil, 1, []byte(r.Name))
+	return appendBytes(b, 2, []byte(r.Password))
+}
+
+func (r *AuthenticateRequest) Unmarshal(data []byte) error {
+	return decodeMessage(data, func(num protowire.Number, raw []byte, value uint64) error {
+		switch num {
+		case 1:
+			r.Name = string(raw)
+		case 2:
+			r.Password = string(raw)
+		}
+		return nil
+	})
+}
+
+// AuthenticateResponse 认证的响应，Token随后以"token"元数据携带
+type AuthenticateResponse struct {
+	Header ResponseHeader
+	Token  string
+}
+
+func (r *AuthenticateResponse) Marshal() []byte {
+	b := appendMessage(nil, 1, r.Header.Marshal())
+	return appendBytes(b, 2, []byte(r.Token))
+}
+
+func (r *AuthenticateResponse) Unmarshal(data []byte) error {
+	return decodeMessage(data, func(num protowire.Number, raw []byte, value uint64) error {
+		switch num {
+		case 1:
+			return r.Header.Unmarshal(raw)
+		case 2:
+			r.Token = string(raw)
+		}
+		return nil
+	})
+}
+
+// StatusResponse 成员状态（Maintenance/Status的请求为空消息）
+type StatusResponse struct {
+	Header  ResponseHeader
+	Version string
+	DbSize  int64
+	Leader  uint64
+}
+
+func (r *StatusResponse) Marshal() []byte {
+	b := appendMessage(nil, 1, r.Header.Marshal())
+	b = appendBytes(b, 2, []byte(r.Version))
+	b = appendVarint(b, 3, uint64(r.DbSize))
+	return appendVarint(b, 4, r.Leader)
+}
+
+func (r *StatusResponse) Unmarshal(data []byte) error {
+	return decodeMessage(data, func(num protowire.Number, raw []byte, value uint64) error {
+		switch num {
+		case 1:
+			return r.Header.Unmarshal(raw)
+		case 2:
+			r.Version = string(raw)
+		case 3:
+			r.DbSize = int64(value)
+		case 4:
+			r.Leader = value
+		}
+		return nil
+	})
+}
+
+// appendVarint 编码varint字段，零值按proto3省略
+func appendVarint(b []byte, num protowire.Number, value uint64) []byte {
+	if value == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, value)
+}
+
+// appendBool 编码bool字段，false省略
+func appendBool(b []byte, num protowire.Number, value bool) []byte {
+	if !value {
+		return b
+	}
+	return appendVarint(b, num, 1)
+}
+
+// appendBytes 编码bytes或string字段，空值省略
+func appendBytes(b []byte, num protowire.Number, value []byte) []byte {
+	if len(value) == 0 {
+		return b
+	}
+	return appendMessage(b, num, value)
+}
+
+// appendMessage 编码嵌套消息字段，空消息也要编码，如oneof中的create_request
+func appendMessage(b []byte, num protowire.Number, message []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, message)
+}
+
+// decodeMessage 依次解码消息中的字段：varint字段的值在value中，长度分隔字段的内容在raw中，其余类型跳过
+// raw引用data的内存，data由编解码器复制，可以直接保留
+func decodeMessage(data []byte, field func(num protowire.Number, raw []byte, value uint64) error) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return fmt.Errorf("malformed etcd message: %w", protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		var err error
+		switch typ {
+		case protowire.VarintType:
+			var value uint64
+			value, n = protowire.ConsumeVarint(data)
+			if n >= 0 {
+				err = field(num, nil, value)
+			}
+		case protowire.BytesType:
+			var raw []byte
+			raw, n = protowire.ConsumeBytes(data)
+			if n >= 0 {
+				err = field(num, raw, 0)
+			}
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, data)
+		}
+		if n < 0 {
+			return fmt.Errorf("malformed etcd message: %w", protowire.ParseError(n))
+		}
+		if err != nil {
+			return err
+		}
+		data = data[n:]
+	}
+	return nil
+}
+
+// decodeHeaderOnly 解码只关心响应头的消息
+func decodeHeaderOnly(data []byte, header *ResponseHeader) error {
+	return decodeMessage(data, func(num protowire.Number, raw []byte, value uint64) error {
+		if num == 1 {
+			return header.Unmarshal(raw)
+		}
+		return nil
+	})
+}
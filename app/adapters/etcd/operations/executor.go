@@ -0,0 +1,220 @@
+package operations
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"abc-runner/app/adapters/etcd/config"
+	"abc-runner/app/adapters/etcd/connection"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/core/utils"
+)
+
+// ErrLeaseExpired 续约响应的TTL不大于0，租约已过期或被撤销
+var ErrLeaseExpired = errors.New("lease expired or revoked")
+
+// EtcdExecutor etcd操作执行器
+type EtcdExecutor struct {
+	client    *connection.Client
+	specific  config.EtcdSpecificConfig
+	prefixEnd []byte
+	valueSize int
+	timeout   time.Duration
+	pacer     *utils.Pacer
+	tracker   *metrics.OperationTypeTracker
+	watches   *WatchTracker // watch测试中统计事件送达，其余测试为nil
+}
+
+// NewEtcdExecutor 创建etcd操作执行器，rate大于0时限制每秒的操作数
+func NewEtcdExecutor(client *connection.Client, cfg *config.EtcdConfig, watches *WatchTracker) *EtcdExecutor {
+	return &EtcdExecutor{
+		client:    client,
+		specific:  cfg.EtcdSpecific,
+		prefixEnd: []byte(cfg.EtcdSpecific.PrefixEnd()),
+		valueSize: cfg.BenchMark.DataSize,
+		timeout:   cfg.Connection.Timeout,
+		pacer:     utils.NewRatePacer(float64(cfg.BenchMark.Rate)),
+		tracker:   newOperationTracker(),
+		watches:   watches,
+	}
+}
+
+// ExecuteOperation 执行一次操作，租约周期的全部步骤共用一个超时
+func (e *EtcdExecutor) ExecuteOperation(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	if err := e.pacer.Wait(ctx); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	index, _ := operation.Params["key"].(int)
+	key := e.specific.Key(index)
+	isRead := false
+
+	// 读写字节数按请求与响应中的键值大小计算
+	startTime := time.Now()
+	var keys, read int64
+	written := int64(len(key))
+	var truncated bool
+	var err error
+	switch operation.Type {
+	case OperationPut:
+		written += int64(e.valueSize)
+		_, err = e.client.Put(ctx, &connection.PutRequest{Key: []byte(key), Value: NewValue(e.valueSize, time.Now())})
+		if err == nil {
+			keys = 1
+			if e.watches != nil {
+				e.watches.Expect()
+			}
+		}
+	case OperationGetLinearizable, OperationGetSerializable:
+		isRead = true
+		var response *connection.RangeResponse
+		response, err = e.client.Range(ctx, &connection.RangeRequest{
+			Key:          []byte(key),
+			Serializable: operation.Type == OperationGetSerializable,
+		})
+		if err == nil {
+			keys, read = int64(len(response.Kvs)), kvSize(response.Kvs)
+		}
+	case OperationRangeLinearizable, OperationRangeSerializable:
+		isRead = true
+		var response *connection.RangeResponse
+		response, err = e.client.Range(ctx, &connection.RangeRequest{
+			Key:          []byte(key),
+			RangeEnd:     e.prefixEnd,
+			Limit:        int64(e.specific.RangeLimit),
+			Serializable: operation.Type == OperationRangeSerializable,
+		})
+		written += int64(len(e.prefixEnd))
+		if err == nil {
+			keys, truncated, read = int64(len(response.Kvs)), response.More, kvSize(response.Kvs)
+		}
+	case OperationLease:
+		written += int64(e.valueSize)
+		err = e.leaseCycle(ctx, key)
+	default:
+		err = fmt.Errorf("unsupported operation type: %s", operation.Type)
+	}
+	duration := time.Since(startTime)
+
+	// 租约周期的各步骤已在leaseCycle中分别统计
+	if operation.Type != OperationLease {
+		e.tracker.Record(operation.Type, keys, truncated, duration, err)
+	}
+	if err != nil {
+		err = fmt.Errorf("%s %s failed: %w", operation.Type, key, err)
+	}
+
+	result := &interfaces.OperationResult{
+		Success:      err == nil,
+		Duration:     duration,
+		IsRead:       isRead,
+		Error:        err,
+		Value:        keys,
+		BytesRead:    read,
+		BytesWritten: written,
+		Metadata: map[string]interface{}{
+			"protocol":       "etcd",
+			"operation_type": operation.Type,
+			"key":            key,
+			"keys":           keys,
+		},
+	}
+	for k, v := range operation.Metadata {
+		result.Metadata[k] = v
+	}
+	return result, err
+}
+
+// leaseCycle 一次租约周期：创建租约、写入绑定该租约的键、在续约流上续约keep_alives次、撤销租约
+// 创建成功后无论后续步骤是否失败都会撤销，避免测试遗留的租约持有键直到过期
+func (e *EtcdExecutor) leaseCycle(ctx context.Context, key string) error {
+	start := time.Now()
+	grant, err := e.client.LeaseGrant(ctx, int64(e.specific.LeaseTTL))
+	e.tracker.Record(OperationLeaseGrant, 0, false, time.Since(start), err)
+	if err != nil {
+		return err
+	}
+
+	err = e.leaseUse(ctx, key, grant.ID)
+
+	start = time.Now()
+	revokeErr := e.client.LeaseRevoke(ctx, grant.ID)
+	e.tracker.Record(OperationLeaseRevoke, 0, false, time.Since(start), revokeErr)
+	if err == nil {
+		err = revokeErr
+	}
+	return err
+}
+
+// leaseUse 写入绑定租约的键并续约
+func (e *EtcdExecutor) leaseUse(ctx context.Context, key string, lease int64) error {
+	start := time.Now()
+	_, err := e.client.Put(ctx, &connection.PutRequest{Key: []byte(key), Value: NewValue(e.valueSize, start), Lease: lease})
+	e.tracker.Record(OperationLeasePut, 1, false, time.Since(start), err)
+	if err != nil || e.specific.KeepAlives == 0 {
+		return err
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := e.client.LeaseKeepAlive(streamCtx)
+	if err != nil {
+		e.tracker.Record(OperationLeaseKeepAlive, 0, false, 0, err)
+		return err
+	}
+	defer stream.CloseSend()
+	for i := 0; i < e.specific.KeepAlives; i++ {
+		start = time.Now()
+		err = stream.Send(&connection.LeaseIDRequest{ID: lease})
+		var response connection.LeaseKeepAliveResponse
+		if err == nil {
+			err = stream.Recv(&response)
+		}
+		if err == nil && response.TTL <= 0 {
+			err = ErrLeaseExpired
+		}
+		e.tracker.Record(OperationLeaseKeepAlive, 0, false, time.Since(start), err)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OperationStats 获取按操作类型的统计
+func (e *EtcdExecutor) OperationStats() []OperationStats {
+	return e.tracker.Stats()
+}
+
+// kvSize 返回键值对中键与值的总字节数
+func kvSize(kvs []connection.KeyValue) int64 {
+	var size int64
+	for _, kv := range kvs {
+		size += int64(len(kv.Key) + len(kv.Value))
+	}
+	return size
+}
+
+// NewValue 生成size字节的值，前8字节为写入时间（Unix纳秒，大端），watch据此计算事件送达延迟
+func NewValue(size int, at time.Time) []byte {
+	value := make([]byte, size)
+	binary.BigEndian.PutUint64(value, uint64(at.UnixNano()))
+	for i := 8; i < size; i++ {
+		value[i] = 'a' + byte(i%26)
+	}
+	return value
+}
+
+// writeTime 解析值中的写入时间
+func writeTime(value []byte) (time.Time, bool) {
+	if len(value) < 8 {
+		return time.Time{}, false
+	}
+	return time.Unix(0, int64(binary.BigEndian.Uint64(value))), true
+}
@@ -0,0 +1,383 @@
+package operations
+
+import (
+	"context"
+	"net"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"abc-runner/app/adapters/etcd/config"
+	"abc-runner/app/adapters/etcd/connection"
+	"abc-runner/app/core/interfaces"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeEtcd 内存中的etcd v3服务端，只实现测试用到的方法
+// noLeader为true时一元请求返回etcd的no leader错误；expireLeases为true时续约响应的TTL为0
+type fakeEtcd struct {
+	mutex        sync.Mutex
+	revision     int64
+	kv           map[string][]byte
+	leases       map[int64]bool
+	nextLease    int64
+	watchers     []chan connection.Event
+	serializable int
+	linearizable int
+	noLeader     bool
+	expireLeases bool
+}
+
+// startFakeEtcd 启动模拟服务端并返回连接到它的配置
+func startFakeEtcd(t *testing.T) (*fakeEtcd, *config.EtcdConfig) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	fake := &fakeEtcd{kv: make(map[string][]byte), leases: make(map[int64]bool)}
+	server := grpc.NewServer(grpc.ForceServerCodec(connection.RawCodec{}), grpc.UnknownServiceHandler(fake.handle))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	cfg := config.NewDefaultEtcdConfig()
+	cfg.Connection.Endpoints = []string{listener.Addr().String()}
+	cfg.Connection.Connections = 2
+	cfg.BenchMark.RandomKeys = 50
+	cfg.BenchMark.DataSize = 32
+	return fake, cfg
+}
+
+func (f *fakeEtcd) handle(srv interface{}, stream grpc.ServerStream) error {
+	method, _ := grpc.MethodFromServerStream(stream)
+	switch method {
+	case connection.MethodWatch:
+		return f.watch(stream)
+	case connection.MethodLeaseKeepAlive:
+		return f.keepAlive(stream)
+	}
+
+	var in []byte
+	if err := stream.RecvMsg(&in); err != nil {
+		return err
+	}
+	f.mutex.Lock()
+	if f.noLeader {
+		f.mutex.Unlock()
+		return status.Error(codes.Unavailable, "etcdserver: no leader")
+	}
+	response, err := f.unary(method, in)
+	f.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+	out := response.Marshal()
+	return stream.SendMsg(&out)
+}
+
+// unary 处理一元请求，调用方持有锁
+func (f *fakeEtcd) unary(method string, in []byte) (connection.Message, error) {
+	header := connection.ResponseHeader{MemberID: 1, Revision: f.revision}
+	switch method {
+	case connection.MethodRange:
+		var request connection.RangeRequest
+		if err := request.Unmarshal(in); err != nil {
+			return nil, err
+		}
+		if request.Serializable {
+			f.serializable++
+		} else {
+			f.linearizable++
+		}
+		return f.rangeKeys(&request, header), nil
+	case connection.MethodPut:
+		var request connection.PutRequest
+		if err := request.Unmarshal(in); err != nil {
+			return nil, err
+		}
+		if request.Lease != 0 && !f.leases[request.Lease] {
+			return nil, status.Error(codes.NotFound, "etcdserver: requested lease not found")
+		}
+		f.revision++
+		f.kv[string(request.Key)] = request.Value
+		event := connection.Event{Type: connection.EventTypePut, Kv: connection.KeyValue{Key: request.Key, Value: request.Value, ModRevision: f.revision}}
+		for _, watcher := range f.watchers {
+			watcher <- event
+		}
+		return &connection.PutResponse{Header: header}, nil
+	case connection.MethodLeaseGrant:
+		var request connection.LeaseGrantRequest
+		if err := request.Unmarshal(in); err != nil {
+			return nil, err
+		}
+		f.nextLease++
+		f.leases[f.nextLease] = true
+		return &connection.LeaseGrantResponse{Header: header, ID: f.nextLease, TTL: request.TTL}, nil
+	case connection.MethodLeaseRevoke:
+		var request connection.LeaseIDRequest
+		if err := request.Unmarshal(in); err != nil {
+			return nil, err
+		}
+		if !f.leases[request.ID] {
+			return nil, status.Error(codes.NotFound, "etcdserver: requested lease not found")
+		}
+		delete(f.leases, request.ID)
+		return &connection.LeaseRevokeResponse{Header: header}, nil
+	case connection.MethodStatus:
+		return &connection.StatusResponse{Header: header, Version: "3.5.0", Leader: 1}, nil
+	}
+	return nil, status.Errorf(codes.Unimplemented, "unknown method %s", method)
+}
+
+// rangeKeys 按键排序返回[key, range_end)中的键，range_end为空时只读取key
+func (f *fakeEtcd) rangeKeys(request *connection.RangeRequest, header connection.ResponseHeader) *connection.RangeResponse {
+	var keys []string
+	for key := range f.kv {
+		if key == string(request.Key) || len(request.RangeEnd) > 0 && key > string(request.Key) && key < string(request.RangeEnd) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	response := &connection.RangeResponse{Header: header, Count: int64(len(keys))}
+	if request.CountOnly {
+		return response
+	}
+	if request.Limit > 0 && int64(len(keys)) > request.Limit {
+		keys, response.More = keys[:request.Limit], true
+	}
+	for _, key := range keys {
+		response.Kvs = append(response.Kvs, connection.KeyValue{Key: []byte(key), Value: f.kv[key]})
+	}
+	return response
+}
+
+// watch 确认创建后把写入事件逐个推送给watcher
+func (f *fakeEtcd) watch(stream grpc.ServerStream) error {
+	var in []byte
+	if err := stream.RecvMsg(&in); err != nil {
+		return err
+	}
+	var request connection.WatchCreateRequest
+	if err := request.Unmarshal(in); err != nil {
+		return err
+	}
+	events := make(chan connection.Event, 1024)
+	f.mutex.Lock()
+	f.watchers = append(f.watchers, events)
+	f.mutex.Unlock()
+
+	out := (&connection.WatchResponse{WatchID: 1, Created: true}).Marshal()
+	if err := stream.SendMsg(&out); err != nil {
+		return err
+	}
+	for {
+		select {
+		case event := <-events:
+			out := (&connection.WatchResponse{WatchID: 1, Events: []connection.Event{event}}).Marshal()
+			if err := stream.SendMsg(&out); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// keepAlive 逐个回复续约请求
+func (f *fakeEtcd) keepAlive(stream grpc.ServerStream) error {
+	for {
+		var in []byte
+		if err := stream.RecvMsg(&in); err != nil {
+			return nil
+		}
+		var request connection.LeaseIDRequest
+		if err := request.Unmarshal(in); err != nil {
+			return err
+		}
+		response := connection.LeaseKeepAliveResponse{ID: request.ID, TTL: 10}
+		f.mutex.Lock()
+		if f.expireLeases || !f.leases[request.ID] {
+			response.TTL = 0
+		}
+		f.mutex.Unlock()
+		out := response.Marshal()
+		if err := stream.SendMsg(&out); err != nil {
+			return err
+		}
+	}
+}
+
+// newTestExecutor 连接模拟服务端并创建执行器
+func newTestExecutor(t *testing.T, cfg *config.EtcdConfig, watches bool) (*EtcdExecutor, *connection.Client, *WatchTracker) {
+	t.Helper()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("invalid config: %v", err)
+	}
+	client, err := connection.NewClient(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	var tracker *WatchTracker
+	if watches {
+		tracker, err = StartWatches(context.Background(), client, cfg.EtcdSpecific, time.Second)
+		if err != nil {
+			t.Fatalf("failed to start watches: %v", err)
+		}
+		t.Cleanup(tracker.Close)
+	}
+	return NewEtcdExecutor(client, cfg, tracker), client, tracker
+}
+
+// run 按工厂创建的操作执行count次
+func run(t *testing.T, executor *EtcdExecutor, cfg *config.EtcdConfig, count int) []*interfaces.OperationResult {
+	t.Helper()
+	factory := NewOperationFactory(cfg)
+	var results []*interfaces.OperationResult
+	for jobID := 0; jobID < count; jobID++ {
+		result, _ := executor.ExecuteOperation(context.Background(), factory.CreateOperation(jobID, nil))
+		results = append(results, result)
+	}
+	return results
+}
+
+// statsByType 按操作类型索引统计
+func statsByType(executor *EtcdExecutor) map[string]OperationStats {
+	stats := make(map[string]OperationStats)
+	for _, s := range executor.OperationStats() {
+		stats[s.Type] = s
+	}
+	return stats
+}
+
+func TestSeedAndReads(t *testing.T) {
+	fake, cfg := startFakeEtcd(t)
+	cfg.BenchMark.TestCase = config.TestCaseGet
+	cfg.EtcdSpecific.SerializablePercent = 50
+	executor, client, _ := newTestExecutor(t, cfg, false)
+
+	seeded, err := Seed(context.Background(), client, cfg.EtcdSpecific, cfg.BenchMark.RandomKeys, cfg.BenchMark.DataSize)
+	if err != nil || !seeded || len(fake.kv) != cfg.BenchMark.RandomKeys {
+		t.Fatalf("Seed() = %v, %v with %d keys", seeded, err, len(fake.kv))
+	}
+	if seeded, err := Seed(context.Background(), client, cfg.EtcdSpecific, cfg.BenchMark.RandomKeys, cfg.BenchMark.DataSize); err != nil || seeded {
+		t.Fatalf("second Seed() = %v, %v, want no seeding", seeded, err)
+	}
+
+	for _, result := range run(t, executor, cfg, 200) {
+		if !result.Success || !result.IsRead || result.Value != int64(1) {
+			t.Fatalf("unexpected get result: %+v", result)
+		}
+		if result.BytesWritten == 0 || result.BytesRead < int64(cfg.BenchMark.DataSize) {
+			t.Fatalf("expected the key and value to be counted, got written %d, read %d", result.BytesWritten, result.BytesRead)
+		}
+	}
+	stats := statsByType(executor)
+	linearizable, serializable := stats[OperationGetLinearizable], stats[OperationGetSerializable]
+	if linearizable.Count == 0 || serializable.Count == 0 || linearizable.Count+serializable.Count != 200 {
+		t.Fatalf("unexpected read split: %+v", stats)
+	}
+	if int64(fake.serializable) != serializable.Count || int64(fake.linearizable) != linearizable.Count+2 {
+		t.Errorf("server saw %d serializable / %d linearizable reads", fake.serializable, fake.linearizable)
+	}
+
+	cfg.BenchMark.TestCase = config.TestCaseRange
+	cfg.EtcdSpecific.SerializablePercent = 0
+	cfg.EtcdSpecific.RangeLimit = 10
+	executor = NewEtcdExecutor(client, cfg, nil)
+	for _, result := range run(t, executor, cfg, 50) {
+		if !result.Success || result.Value.(int64) > 10 {
+			t.Fatalf("unexpected range result: %+v", result)
+		}
+	}
+	ranges := statsByType(executor)[OperationRangeLinearizable]
+	if ranges.Count != 50 || ranges.Flagged == 0 || ranges.Flagged == ranges.Count {
+		t.Errorf("expected some ranges to be truncated by the limit: %+v", ranges)
+	}
+}
+
+func TestLeaseCycle(t *testing.T) {
+	fake, cfg := startFakeEtcd(t)
+	cfg.BenchMark.TestCase = config.TestCaseLease
+	cfg.EtcdSpecific.KeepAlives = 3
+	executor, _, _ := newTestExecutor(t, cfg, false)
+
+	for _, result := range run(t, executor, cfg, 10) {
+		if !result.Success {
+			t.Fatalf("lease cycle failed: %v", result.Error)
+		}
+	}
+	stats := statsByType(executor)
+	for operation, want := range map[string]int64{
+		OperationLeaseGrant: 10, OperationLeasePut: 10, OperationLeaseKeepAlive: 30, OperationLeaseRevoke: 10,
+	} {
+		if stats[operation].Count != want || stats[operation].Errors != 0 {
+			t.Errorf("%s: %+v, want %d", operation, stats[operation], want)
+		}
+	}
+	if _, ok := stats[OperationLease]; ok || len(fake.leases) != 0 {
+		t.Errorf("unexpected lease stats or leaked leases: %v, %v", stats, fake.leases)
+	}
+
+	// 续约失败时仍撤销租约
+	fake.expireLeases = true
+	executor = NewEtcdExecutor(executor.client, cfg, nil)
+	results := run(t, executor, cfg, 5)
+	stats = statsByType(executor)
+	if results[0].Success || stats[OperationLeaseKeepAlive].ErrorCodes["lease_not_found"] != 5 ||
+		stats[OperationLeaseRevoke].Count != 5 || len(fake.leases) != 0 {
+		t.Errorf("unexpected stats for expired leases: %+v", stats)
+	}
+}
+
+func TestWatchFanOut(t *testing.T) {
+	_, cfg := startFakeEtcd(t)
+	cfg.BenchMark.TestCase = config.TestCaseWatch
+	cfg.EtcdSpecific.Watchers = 3
+	executor, _, watches := newTestExecutor(t, cfg, true)
+
+	for _, result := range run(t, executor, cfg, 20) {
+		if !result.Success || result.IsRead {
+			t.Fatalf("unexpected put result: %+v", result)
+		}
+	}
+	watches.Wait()
+	stats := watches.Stats()
+	if stats.Watchers != 3 || stats.Expected != 60 || stats.Delivered != 60 || stats.Missed != 0 {
+		t.Fatalf("unexpected watch stats: %+v", stats)
+	}
+	if stats.Latency.Max <= 0 || stats.Latency.Max > time.Second {
+		t.Errorf("unexpected delivery latency: %+v", stats.Latency)
+	}
+}
+
+func TestErrorCodes(t *testing.T) {
+	fake, cfg := startFakeEtcd(t)
+	executor, _, _ := newTestExecutor(t, cfg, false)
+	fake.noLeader = true
+
+	results := run(t, executor, cfg, 3)
+	if results[0].Success {
+		t.Fatal("expected puts to fail without a leader")
+	}
+	if codes := statsByType(executor)[OperationPut].ErrorCodes; codes["no_leader"] != 3 {
+		t.Errorf("unexpected error codes: %v", codes)
+	}
+
+	for err, want := range map[error]string{
+		context.DeadlineExceeded: "timeout",
+		status.Error(codes.DeadlineExceeded, "context deadline exceeded"):                        "timeout",
+		status.Error(codes.OutOfRange, "etcdserver: mvcc: required revision has been compacted"): "compacted",
+		status.Error(codes.Unavailable, "connection refused"):                                    "unavailable",
+		ErrLeaseExpired: "lease_not_found",
+	} {
+		if code := errorCode(err); code != want {
+			t.Errorf("errorCode(%v) = %q, want %q", err, code, want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package operations
+
+import (
+	"math/rand/v2"
+
+	"abc-runner/app/adapters/etcd/config"
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+)
+
+// 操作类型：读取按一致性级别区分，便于分开统计线性一致读与serializable读的延迟
+const (
+	OperationPut               = "put"
+	OperationGetLinearizable   = "get_linearizable"
+	OperationGetSerializable   = "get_serializable"
+	OperationRangeLinearizable = "range_linearizable"
+	OperationRangeSerializable = "range_serializable"
+	OperationLease             = "lease" // 由lease_grant、lease_put、lease_keepalive、lease_revoke各步骤分别统计
+
+	OperationLeaseGrant     = "lease_grant"
+	OperationLeasePut       = "lease_put"
+	OperationLeaseKeepAlive = "lease_keepalive"
+	OperationLeaseRevoke    = "lease_revoke"
+)
+
+// OperationFactory etcd操作工厂
+// put依次覆盖键空间（第jobID个操作写第jobID%keys个键），读取随机选择键
+type OperationFactory struct {
+	testCase            string
+	keys                int
+	readPercent         int
+	serializablePercent int
+}
+
+// NewOperationFactory 创建etcd操作工厂
+func NewOperationFactory(cfg *config.EtcdConfig) *OperationFactory {
+	return &OperationFactory{
+		testCase:            cfg.BenchMark.TestCase,
+		keys:                cfg.BenchMark.RandomKeys,
+		readPercent:         cfg.BenchMark.ReadPercent,
+		serializablePercent: cfg.EtcdSpecific.SerializablePercent,
+	}
+}
+
+// CreateOperation 创建操作，Params中的key为键在键空间中的序号
+func (f *OperationFactory) CreateOperation(jobID int, cfg execution.BenchmarkConfig) interfaces.Operation {
+	operationType := f.pickOperation()
+	key := jobID % f.keys
+	if operationType != OperationPut && operationType != OperationLease {
+		key = rand.IntN(f.keys)
+	}
+	return interfaces.Operation{
+		Type: operationType,
+		Params: map[string]interface{}{
+			"job_id": jobID,
+			"key":    key,
+		},
+		Metadata: map[string]string{
+			"operation_type": operationType,
+		},
+	}
+}
+
+// pickOperation 按测试用例选择操作类型，读取按serializable_percent选择一致性级别
+func (f *OperationFactory) pickOperation() string {
+	switch f.testCase {
+	case config.TestCaseGet:
+		return f.read(OperationGetLinearizable, OperationGetSerializable)
+	case config.TestCaseRange:
+		return f.read(OperationRangeLinearizable, OperationRangeSerializable)
+	case config.TestCaseMixed:
+		if rand.IntN(100) < f.readPercent {
+			return f.read(OperationGetLinearizable, OperationGetSerializable)
+		}
+	case config.TestCaseLease:
+		return OperationLease
+	}
+	return OperationPut
+}
+
+// read 选择读取的一致性级别
+func (f *OperationFactory) read(linearizable, serializable string) string {
+	if f.serializablePercent > 0 && rand.IntN(100) < f.serializablePercent {
+		return serializable
+	}
+	return linearizable
+}
+
+// GetOperationType 获取操作类型
+func (f *OperationFactory) GetOperationType() string {
+	return f.testCase
+}
+
+// GetSupportedOperations 获取支持的操作类型
+func (f *OperationFactory) GetSupportedOperations() []string {
+	return []string{OperationPut, OperationGetLinearizable, OperationGetSerializable,
+		OperationRangeLinearizable, OperationRangeSerializable, OperationLease}
+}
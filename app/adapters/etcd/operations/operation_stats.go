@@ -0,0 +1,61 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"abc-runner/app/core/metrics"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// OperationStats 单个操作类型的统计，Volume为写入或读到的键数（JSON字段"keys"），
+// Flagged为因range_limit截断、范围内还有更多键的range次数（JSON字段"truncated"）；
+// 错误码为etcd的服务端错误（如"no_leader"、"too_many_requests"、"compacted"）、"timeout"或gRPC状态码
+type OperationStats = metrics.OperationTypeStats
+
+// newOperationTracker 创建按操作类型的统计器
+func newOperationTracker() *metrics.OperationTypeTracker {
+	return metrics.NewOperationTypeTracker(errorCode, "keys", "truncated")
+}
+
+// etcdErrors etcd服务端错误消息（rpctypes）与错误码
+var etcdErrors = []struct {
+	message string
+	code    string
+}{
+	{"etcdserver: no leader", "no_leader"},
+	{"etcdserver: leader changed", "leader_changed"},
+	{"etcdserver: too many requests", "too_many_requests"},
+	{"etcdserver: request timed out", "server_timeout"},
+	{"etcdserver: requested lease not found", "lease_not_found"},
+	{"etcdserver: mvcc: required revision has been compacted", "compacted"},
+	{"etcdserver: mvcc: database space exceeded", "no_space"},
+	{"etcdserver: invalid auth token", "invalid_token"},
+	{"etcdserver: permission denied", "permission_denied"},
+}
+
+// errorCode 错误分类：etcd服务端错误按消息归类，其余按超时与gRPC状态码归类
+func errorCode(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	if errors.Is(err, ErrLeaseExpired) {
+		return "lease_not_found"
+	}
+	s, ok := status.FromError(err)
+	if !ok {
+		return "error"
+	}
+	for _, known := range etcdErrors {
+		if strings.Contains(s.Message(), known.message) {
+			return known.code
+		}
+	}
+	if s.Code() == codes.DeadlineExceeded {
+		return "timeout"
+	}
+	return strings.ToLower(s.Code().String())
+}
@@ -0,0 +1,53 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"abc-runner/app/adapters/etcd/config"
+	"abc-runner/app/adapters/etcd/connection"
+)
+
+// seedConcurrency 填充数据时并发写入的请求数
+const seedConcurrency = 16
+
+// Seed 在前缀下没有键时写入整个键空间（keys个键，序号0..keys-1）；返回是否填充了数据
+func Seed(ctx context.Context, client *connection.Client, specific config.EtcdSpecificConfig, keys, valueSize int) (bool, error) {
+	response, err := client.Range(ctx, &connection.RangeRequest{
+		Key:       []byte(specific.KeyPrefix),
+		RangeEnd:  []byte(specific.PrefixEnd()),
+		CountOnly: true,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to count keys under %s: %w", specific.KeyPrefix, err)
+	}
+	if response.Count > 0 {
+		return false, nil
+	}
+
+	var wg sync.WaitGroup
+	var once sync.Once
+	var seedErr error
+	seedCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	slots := make(chan struct{}, seedConcurrency)
+	for i := 0; i < keys && seedCtx.Err() == nil; i++ {
+		key := specific.Key(i)
+		slots <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			if _, err := client.Put(seedCtx, &connection.PutRequest{Key: []byte(key), Value: NewValue(valueSize, time.Now())}); err != nil {
+				once.Do(func() {
+					seedErr = fmt.Errorf("failed to seed %s: %w", key, err)
+					cancel()
+				})
+			}
+		}()
+	}
+	wg.Wait()
+	return true, seedErr
+}
@@ -0,0 +1,151 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"abc-runner/app/adapters/etcd/config"
+	"abc-runner/app/adapters/etcd/connection"
+	"abc-runner/app/core/metrics"
+)
+
+// WatchStats watch扇出测试统计
+type WatchStats struct {
+	Watchers  int                    `json:"watchers"`  // 监听前缀的watcher数
+	Expected  int64                  `json:"expected"`  // 应送达的事件数：成功写入数×watcher数
+	Delivered int64                  `json:"delivered"` // 收到的写入事件数
+	Missed    int64                  `json:"missed"`    // 等待结束后仍未收到的事件数
+	Canceled  int64                  `json:"canceled"`  // 被服务端取消的watcher数（如起始版本已被压缩）
+	Latency   metrics.LatencyMetrics `json:"latency"`   // 发出写入到watcher收到事件的延迟
+}
+
+// WatchTracker watch扇出跟踪器
+// watchers个watch流（在全部连接间轮转）监听测试前缀，写入的值携带写入时间，收到事件时记录送达延迟；
+// 每次成功写入期待每个watcher各收到一个事件
+type WatchTracker struct {
+	watchers int
+	timeout  time.Duration
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+
+	expected  atomic.Int64
+	delivered atomic.Int64
+	canceled  atomic.Int64
+	active    atomic.Int64
+	latency   *metrics.LatencyTracker
+}
+
+// StartWatches 打开watchers个watch流，等待全部创建完成后开始接收事件；timeout为测试结束后等待事件送达的最长时间
+func StartWatches(ctx context.Context, client *connection.Client, specific config.EtcdSpecificConfig, timeout time.Duration) (*WatchTracker, error) {
+	watchCtx, cancel := context.WithCancel(context.Background())
+	t := &WatchTracker{
+		watchers: specific.Watchers,
+		timeout:  timeout,
+		cancel:   cancel,
+		latency: metrics.NewLatencyTracker(metrics.LatencyConfig{
+			SignificantDigits: metrics.DefaultHdrSignificantDigits,
+			SamplingRate:      1.0,
+		}),
+	}
+
+	request := &connection.WatchCreateRequest{Key: []byte(specific.KeyPrefix), RangeEnd: []byte(specific.PrefixEnd())}
+	for i := 0; i < specific.Watchers; i++ {
+		stream, err := client.Watch(watchCtx)
+		if err == nil {
+			err = createWatch(ctx, stream, request)
+		}
+		if err != nil {
+			t.Close()
+			return nil, fmt.Errorf("failed to create watcher %d on %s: %w", i+1, specific.KeyPrefix, err)
+		}
+		t.active.Add(1)
+		t.wg.Add(1)
+		go t.receive(stream)
+	}
+	return t, nil
+}
+
+// createWatch 发送创建请求并等待确认，ctx结束时放弃等待
+func createWatch(ctx context.Context, stream *connection.Stream, request *connection.WatchCreateRequest) error {
+	if err := stream.Send(request); err != nil {
+		return err
+	}
+	created := make(chan error, 1)
+	go func() {
+		var response connection.WatchResponse
+		err := stream.Recv(&response)
+		if err == nil && (!response.Created || response.Canceled) {
+			err = fmt.Errorf("watch was not created: %s", response.CancelReason)
+		}
+		created <- err
+	}()
+	select {
+	case err := <-created:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// receive 接收事件直到流结束或被服务端取消
+func (t *WatchTracker) receive(stream *connection.Stream) {
+	defer t.wg.Done()
+	defer t.active.Add(-1)
+	for {
+		var response connection.WatchResponse
+		if err := stream.Recv(&response); err != nil {
+			return
+		}
+		now := time.Now()
+		for _, event := range response.Events {
+			if event.Type != connection.EventTypePut {
+				continue
+			}
+			t.delivered.Add(1)
+			if at, ok := writeTime(event.Kv.Value); ok {
+				t.latency.Record(now.Sub(at))
+			}
+		}
+		if response.Canceled {
+			t.canceled.Add(1)
+			return
+		}
+	}
+}
+
+// Expect 登记一次成功写入
+func (t *WatchTracker) Expect() {
+	t.expected.Add(int64(t.watchers))
+}
+
+// Wait 等待已写入的事件送达全部watcher，最长等待timeout；所有watcher都已结束时不再等待
+func (t *WatchTracker) Wait() {
+	deadline := time.Now().Add(t.timeout)
+	for time.Now().Before(deadline) && t.active.Load() > 0 && t.delivered.Load() < t.expected.Load() {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// Stats 获取watch统计
+func (t *WatchTracker) Stats() WatchStats {
+	stats := WatchStats{
+		Watchers:  t.watchers,
+		Expected:  t.expected.Load(),
+		Delivered: t.delivered.Load(),
+		Canceled:  t.canceled.Load(),
+		Latency:   t.latency.GetMetrics(),
+	}
+	if stats.Delivered < stats.Expected {
+		stats.Missed = stats.Expected - stats.Delivered
+	}
+	return stats
+}
+
+// Close 关闭全部watch流
+func (t *WatchTracker) Close() {
+	t.cancel()
+	t.wg.Wait()
+}
@@ -150,9 +150,45 @@ func (adapter *GRPCAdapter) GetProtocolMetrics() map[string]interface{} {
 		metrics["connection_pool"] = poolStats
 	}
 
+	// 健康检查与反射探测统计
+	if adapter.grpcOperations != nil {
+		if stats := adapter.grpcOperations.ProbeStats(); stats != nil {
+			metrics["probe"] = stats
+		}
+	}
+
+	// 按gRPC状态码的调用统计
+	if adapter.grpcOperations != nil {
+		if stats := adapter.grpcOperations.StatusCodeStats(); stats != nil {
+			metrics["status_codes"] = stats
+		}
+	}
+
 	return metrics
 }
 
+// GetStatusCodeStats 获取按gRPC状态码的调用数与延迟，没有调用时返回nil
+func (adapter *GRPCAdapter) GetStatusCodeStats() []operations.StatusCodeStats {
+	adapter.mu.RLock()
+	defer adapter.mu.RUnlock()
+
+	if adapter.grpcOperations == nil {
+		return nil
+	}
+	return adapter.grpcOperations.StatusCodeStats()
+}
+
+// GetProbeStats 获取健康检查与反射探测统计，非探测用例时返回nil
+func (adapter *GRPCAdapter) GetProbeStats() *operations.ProbeStats {
+	adapter.mu.RLock()
+	defer adapter.mu.RUnlock()
+
+	if adapter.grpcOperations == nil {
+		return nil
+	}
+	return adapter.grpcOperations.ProbeStats()
+}
+
 // HealthCheck 健康检查
 func (adapter *GRPCAdapter) HealthCheck(ctx context.Context) error {
 	if !adapter.isConnected {
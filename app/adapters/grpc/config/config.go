@@ -60,6 +60,7 @@ type GRPCSpecificConfig struct {
 	Compression    string            `yaml:"compression" json:"compression"`           // 压缩算法
 	MaxMessageSize int               `yaml:"max_message_size" json:"max_message_size"` // 最大消息大小
 	Interceptors   InterceptorConfig `yaml:"interceptors" json:"interceptors"`         // 拦截器配置
+	HealthService  string            `yaml:"health_service" json:"health_service"`     // health_check用例检查的服务名，为空表示整个服务端
 }
 
 // TLSConfig TLS配置
@@ -182,7 +183,7 @@ func (c *GRPCConfig) Validate() error {
 	}
 
 	// 验证测试用例
-	validTestCases := []string{"unary_call", "server_stream", "client_stream", "bidirectional_stream"}
+	validTestCases := []string{"unary_call", "server_stream", "client_stream", "bidirectional_stream", "health_check", "reflection_list"}
 	valid := false
 	for _, testCase := range validTestCases {
 		if c.BenchMark.TestCase == testCase {
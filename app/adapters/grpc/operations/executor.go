@@ -4,13 +4,22 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sync/atomic"
 	"time"
 
 	"abc-runner/app/adapters/grpc/config"
 	"abc-runner/app/adapters/grpc/connection"
+	"abc-runner/app/core/execution"
 	"abc-runner/app/core/interfaces"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/metadata"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 // GRPCExecutor gRPC操作执行器 - 遵循统一架构模式
@@ -18,6 +27,9 @@ type GRPCExecutor struct {
 	connectionPool   *connection.ConnectionPool
 	config           *config.GRPCConfig
 	metricsCollector interfaces.DefaultMetricsCollector
+	probeTracker     *ProbeTracker
+	statusCodes      *StatusCodeTracker
+	reflectionAlpha  atomic.Bool // 服务端不支持v1反射时回退到v1alpha
 }
 
 // NewGRPCExecutor 创建gRPC操作执行器
@@ -26,11 +38,30 @@ func NewGRPCExecutor(
 	config *config.GRPCConfig,
 	metricsCollector interfaces.DefaultMetricsCollector,
 ) *GRPCExecutor {
-	return &GRPCExecutor{
+	executor := &GRPCExecutor{
 		connectionPool:   connectionPool,
 		config:           config,
 		metricsCollector: metricsCollector,
+		statusCodes:      NewStatusCodeTracker(),
 	}
+	if isProbeOperation(config.BenchMark.TestCase) {
+		executor.probeTracker = NewProbeTracker()
+	}
+	return executor
+}
+
+// ProbeStats 获取健康检查与反射探测统计，非探测用例时返回nil
+func (g *GRPCExecutor) ProbeStats() *ProbeStats {
+	if g.probeTracker == nil {
+		return nil
+	}
+	stats := g.probeTracker.Stats()
+	return &stats
+}
+
+// StatusCodeStats 获取按gRPC状态码的调用统计，没有调用时返回nil
+func (g *GRPCExecutor) StatusCodeStats() []StatusCodeStats {
+	return g.statusCodes.Stats()
 }
 
 // ExecuteOperation 执行gRPC操作 - 统一操作入口
@@ -47,6 +78,7 @@ func (g *GRPCExecutor) ExecuteOperation(ctx context.Context, operation interface
 		result.Success = false
 		result.Error = fmt.Errorf("failed to get connection: %w", err)
 		result.Duration = time.Since(startTime)
+		g.recordStatusCode(result, codes.Unavailable)
 		return result, result.Error
 	}
 
@@ -55,11 +87,21 @@ func (g *GRPCExecutor) ExecuteOperation(ctx context.Context, operation interface
 		result.Success = false
 		result.Error = fmt.Errorf("connection is nil")
 		result.Duration = time.Since(startTime)
+		g.recordStatusCode(result, codes.Unavailable)
 		return result, result.Error
 	}
 
 	// 添加认证metadata
 	ctx = g.addAuthMetadata(ctx)
+	if requestID, ok := execution.RequestIDFromContext(ctx); ok {
+		ctx = metadata.AppendToOutgoingContext(ctx, execution.RequestIDMetadata, requestID)
+	}
+	if trace, ok := execution.TraceContextFromContext(ctx); ok {
+		ctx = metadata.AppendToOutgoingContext(ctx, execution.TraceparentHeader, trace.Traceparent())
+	}
+	for _, header := range execution.HeadersFromContext(ctx) {
+		ctx = metadata.AppendToOutgoingContext(ctx, header[0], header[1])
+	}
 
 	var opErr error
 	switch operation.Type {
@@ -71,6 +113,10 @@ func (g *GRPCExecutor) ExecuteOperation(ctx context.Context, operation interface
 		opErr = g.executeClientStream(ctx, operation, result)
 	case "bidirectional_stream":
 		opErr = g.executeBidirectionalStream(ctx, operation, result)
+	case "health_check":
+		opErr = g.executeHealthCheck(ctx, conn, result)
+	case "reflection_list":
+		opErr = g.executeReflectionList(ctx, conn, result)
 	default:
 		opErr = fmt.Errorf("unsupported operation type: %s", operation.Type)
 	}
@@ -78,6 +124,7 @@ func (g *GRPCExecutor) ExecuteOperation(ctx context.Context, operation interface
 	result.Success = opErr == nil
 	result.Error = opErr
 	result.Duration = time.Since(startTime)
+	g.recordStatusCode(result, StatusCode(opErr))
 
 	// 添加操作特定元数据
 	for k, v := range operation.Metadata {
@@ -85,8 +132,10 @@ func (g *GRPCExecutor) ExecuteOperation(ctx context.Context, operation interface
 	}
 	result.Metadata["protocol"] = "grpc"
 	result.Metadata["operation_type"] = operation.Type
-	result.Metadata["service"] = g.config.GRPCSpecific.ServiceName
-	result.Metadata["method"] = g.config.GRPCSpecific.MethodName
+	if !isProbeOperation(operation.Type) {
+		result.Metadata["service"] = g.config.GRPCSpecific.ServiceName
+		result.Metadata["method"] = g.config.GRPCSpecific.MethodName
+	}
 	result.Metadata["execution_time_ms"] = float64(result.Duration.Nanoseconds()) / 1e6
 	result.Metadata["timestamp"] = time.Now()
 
@@ -104,6 +153,7 @@ func (g *GRPCExecutor) executeUnaryCall(ctx context.Context, operation interface
 	time.Sleep(10 * time.Millisecond) // 模拟调用延迟
 	operationDuration := time.Since(operationStartTime)
 
+	result.BytesWritten = requestSize(operation.Value)
 	result.Value = fmt.Sprintf("Unary call result for key: %s", operation.Key)
 	result.Metadata["operation_duration_ms"] = float64(operationDuration.Nanoseconds()) / 1e6
 	result.Metadata["call_type"] = "unary"
@@ -128,6 +178,7 @@ func (g *GRPCExecutor) executeServerStream(ctx context.Context, operation interf
 
 	operationDuration := time.Since(operationStartTime)
 
+	result.BytesWritten = requestSize(operation.Value)
 	result.Value = fmt.Sprintf("Server stream completed, received %d messages", messageCount)
 	result.Metadata["operation_duration_ms"] = float64(operationDuration.Nanoseconds()) / 1e6
 	result.Metadata["call_type"] = "server_stream"
@@ -153,6 +204,7 @@ func (g *GRPCExecutor) executeClientStream(ctx context.Context, operation interf
 
 	operationDuration := time.Since(operationStartTime)
 
+	result.BytesWritten = requestSize(operation.Value)
 	result.Value = fmt.Sprintf("Client stream completed, sent %d messages", messageCount)
 	result.Metadata["operation_duration_ms"] = float64(operationDuration.Nanoseconds()) / 1e6
 	result.Metadata["call_type"] = "client_stream"
@@ -178,6 +230,8 @@ func (g *GRPCExecutor) executeBidirectionalStream(ctx context.Context, operation
 
 	operationDuration := time.Since(operationStartTime)
 
+	result.BytesWritten = requestSize(operation.Value)
+	result.BytesRead = expectedResponseSize(operation.Value)
 	result.Value = fmt.Sprintf("Bidirectional stream completed, exchanged %d messages", messageCount)
 	result.Metadata["operation_duration_ms"] = float64(operationDuration.Nanoseconds()) / 1e6
 	result.Metadata["call_type"] = "bidirectional_stream"
@@ -186,6 +240,202 @@ func (g *GRPCExecutor) executeBidirectionalStream(ctx context.Context, operation
 	return nil
 }
 
+// executeHealthCheck 执行grpc.health.v1健康检查，仅SERVING视为成功
+func (g *GRPCExecutor) executeHealthCheck(ctx context.Context, conn *grpc.ClientConn, result *interfaces.OperationResult) error {
+	ctx, cancel := g.withTimeout(ctx)
+	defer cancel()
+
+	service := g.config.GRPCSpecific.HealthService
+	request := &grpc_health_v1.HealthCheckRequest{Service: service}
+	response, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, request)
+	result.BytesWritten = messageSize(request)
+	if err != nil {
+		g.recordProbeError(err)
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	result.BytesRead = messageSize(response)
+
+	servingStatus := response.GetStatus()
+	if g.probeTracker != nil {
+		g.probeTracker.RecordStatus(servingStatus.String())
+	}
+
+	result.Value = servingStatus.String()
+	result.Metadata["call_type"] = "health_check"
+	result.Metadata["health_service"] = service
+	result.Metadata["health_status"] = servingStatus.String()
+
+	if servingStatus != grpc_health_v1.HealthCheckResponse_SERVING {
+		return fmt.Errorf("health status %s", servingStatus)
+	}
+	return nil
+}
+
+// executeReflectionList 通过服务端反射列出服务，优先使用v1协议，不支持时回退到v1alpha
+func (g *GRPCExecutor) executeReflectionList(ctx context.Context, conn *grpc.ClientConn, result *interfaces.OperationResult) error {
+	ctx, cancel := g.withTimeout(ctx)
+	defer cancel()
+
+	version := "v1"
+	var services []string
+	var err error
+	if !g.reflectionAlpha.Load() {
+		services, err = listServicesV1(ctx, conn, result)
+		if status.Code(err) == codes.Unimplemented {
+			g.reflectionAlpha.Store(true)
+		}
+	}
+	if g.reflectionAlpha.Load() {
+		version = "v1alpha"
+		services, err = listServicesV1Alpha(ctx, conn, result)
+	}
+	if err != nil {
+		g.recordProbeError(err)
+		return fmt.Errorf("reflection list failed: %w", err)
+	}
+
+	if g.probeTracker != nil {
+		g.probeTracker.RecordServices(version, services)
+	}
+
+	result.Value = services
+	result.Metadata["call_type"] = "reflection_list"
+	result.Metadata["reflection_version"] = version
+	result.Metadata["service_count"] = len(services)
+	return nil
+}
+
+// listServicesV1 使用grpc.reflection.v1列出服务
+// 收发的反射消息大小累加到result
+func listServicesV1(ctx context.Context, conn *grpc.ClientConn, result *interfaces.OperationResult) ([]string, error) {
+	stream, err := reflectionv1.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	request := &reflectionv1.ServerReflectionRequest{
+		MessageRequest: &reflectionv1.ServerReflectionRequest_ListServices{ListServices: "*"},
+	}
+	if err := stream.Send(request); err != nil {
+		return nil, err
+	}
+	result.BytesWritten += messageSize(request)
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+	response, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	result.BytesRead += messageSize(response)
+	if errorResponse := response.GetErrorResponse(); errorResponse != nil {
+		return nil, status.Error(codes.Code(errorResponse.GetErrorCode()), errorResponse.GetErrorMessage())
+	}
+
+	var services []string
+	for _, service := range response.GetListServicesResponse().GetService() {
+		services = append(services, service.GetName())
+	}
+	return services, nil
+}
+
+// listServicesV1Alpha 使用grpc.reflection.v1alpha列出服务
+// 收发的反射消息大小累加到result
+func listServicesV1Alpha(ctx context.Context, conn *grpc.ClientConn, result *interfaces.OperationResult) ([]string, error) {
+	stream, err := reflectionv1alpha.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	request := &reflectionv1alpha.ServerReflectionRequest{
+		MessageRequest: &reflectionv1alpha.ServerReflectionRequest_ListServices{ListServices: "*"},
+	}
+	if err := stream.Send(request); err != nil {
+		return nil, err
+	}
+	result.BytesWritten += messageSize(request)
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+	response, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	result.BytesRead += messageSize(response)
+	if errorResponse := response.GetErrorResponse(); errorResponse != nil {
+		return nil, status.Error(codes.Code(errorResponse.GetErrorCode()), errorResponse.GetErrorMessage())
+	}
+
+	var services []string
+	for _, service := range response.GetListServicesResponse().GetService() {
+		services = append(services, service.GetName())
+	}
+	return services, nil
+}
+
+// messageSize 返回消息的protobuf编码大小
+func messageSize(message proto.Message) int64 {
+	return int64(proto.Size(message))
+}
+
+// requestSize 返回模拟调用发送的请求数据大小：载荷、请求内容与各条流消息
+func requestSize(value interface{}) int64 {
+	data, _ := value.(map[string]interface{})
+	var size int64
+	if payload, ok := data["payload"].([]byte); ok {
+		size += int64(len(payload))
+	}
+	if request, ok := data["request_data"].(string); ok {
+		size += int64(len(request))
+	}
+	if messages, ok := data["messages"].([]string); ok {
+		for _, message := range messages {
+			size += int64(len(message))
+		}
+	}
+	if pairs, ok := data["message_pairs"].([]map[string]string); ok {
+		for _, pair := range pairs {
+			size += int64(len(pair["send"]))
+		}
+	}
+	return size
+}
+
+// expectedResponseSize 返回双向流模拟调用期望收到的响应大小
+func expectedResponseSize(value interface{}) int64 {
+	data, _ := value.(map[string]interface{})
+	pairs, _ := data["message_pairs"].([]map[string]string)
+	var size int64
+	for _, pair := range pairs {
+		size += int64(len(pair["expect"]))
+	}
+	return size
+}
+
+// withTimeout 按单次操作超时限制探测调用
+func (g *GRPCExecutor) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if g.config.BenchMark.Timeout > 0 {
+		return context.WithTimeout(ctx, g.config.BenchMark.Timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// recordStatusCode 按状态码记录调用延迟，并写入结果元数据
+func (g *GRPCExecutor) recordStatusCode(result *interfaces.OperationResult, code codes.Code) {
+	g.statusCodes.Record(code, result.Duration)
+	result.Metadata["grpc_status"] = StatusCodeName(code)
+}
+
+// recordProbeError 记录失败的探测调用
+func (g *GRPCExecutor) recordProbeError(err error) {
+	if g.probeTracker != nil {
+		g.probeTracker.RecordError(err)
+	}
+}
+
+// isProbeOperation 判断是否为健康检查或反射探测用例
+func isProbeOperation(operationType string) bool {
+	return operationType == "health_check" || operationType == "reflection_list"
+}
+
 // addAuthMetadata 添加认证metadata
 func (g *GRPCExecutor) addAuthMetadata(ctx context.Context) context.Context {
 	if !g.config.GRPCSpecific.Auth.Enabled {
@@ -222,6 +472,8 @@ func (g *GRPCExecutor) isReadOperation(operationType string) bool {
 		"server_stream":        true,  // 服务器流是从服务器读取数据
 		"client_stream":        false, // 客户端流是向服务器发送数据
 		"bidirectional_stream": true,  // 双向流主要是交互，设为读操作
+		"health_check":         true,  // 健康检查只读取服务状态
+		"reflection_list":      true,  // 反射只读取服务列表
 	}
 	return readOperations[operationType]
 }
@@ -233,5 +485,7 @@ func (g *GRPCExecutor) GetSupportedOperations() []string {
 		"server_stream",
 		"client_stream",
 		"bidirectional_stream",
+		"health_check",
+		"reflection_list",
 	}
 }
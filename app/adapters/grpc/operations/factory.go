@@ -80,6 +80,8 @@ func (f *OperationFactory) CreateOperation(jobID int, config execution.Benchmark
 	case "bidirectional_stream":
 		metadata["stream_type"] = "bidirectional"
 		metadata["message_pairs"] = "4"
+	case "health_check":
+		metadata["health_service"] = f.config.GRPCSpecific.HealthService
 	}
 
 	return interfaces.Operation{
@@ -104,7 +106,7 @@ func (f *OperationFactory) GetConfig() *config.GRPCConfig {
 
 // GetSupportedOperations 获取支持的操作类型
 func (f *OperationFactory) GetSupportedOperations() []string {
-	return []string{"unary_call", "server_stream", "client_stream", "bidirectional_stream"}
+	return []string{"unary_call", "server_stream", "client_stream", "bidirectional_stream", "health_check", "reflection_list"}
 }
 
 // ValidateTestCase 验证测试用例是否支持
@@ -129,6 +131,10 @@ func (f *OperationFactory) generateKey(jobID int) string {
 		return fmt.Sprintf("grpc_client_stream_%s_%s_%d", f.serviceName, f.methodName, jobID)
 	case "bidirectional_stream":
 		return fmt.Sprintf("grpc_bidi_stream_%s_%s_%d", f.serviceName, f.methodName, jobID)
+	case "health_check":
+		return fmt.Sprintf("grpc_health_%s_%d", f.config.GRPCSpecific.HealthService, jobID)
+	case "reflection_list":
+		return fmt.Sprintf("grpc_reflection_%d", jobID)
 	default:
 		return fmt.Sprintf("grpc_op_%s_%s_%d", f.serviceName, f.methodName, jobID)
 	}
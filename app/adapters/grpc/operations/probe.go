@@ -0,0 +1,87 @@
+package operations
+
+import (
+	"sort"
+	"sync"
+
+	"google.golang.org/grpc/status"
+)
+
+// ProbeStats 健康检查与反射探测统计
+type ProbeStats struct {
+	Probes            int64            `json:"probes"`
+	Statuses          map[string]int64 `json:"statuses,omitempty"`           // 健康状态分布，如 SERVING、NOT_SERVING
+	Errors            map[string]int64 `json:"errors,omitempty"`             // 调用失败的gRPC状态码分布
+	ReflectionVersion string           `json:"reflection_version,omitempty"` // 服务端实际支持的反射协议：v1或v1alpha
+	Services          []string         `json:"services,omitempty"`           // 最近一次反射列出的服务
+}
+
+// ProbeTracker 健康检查与反射探测统计器
+type ProbeTracker struct {
+	mutex             sync.Mutex
+	probes            int64
+	statuses          map[string]int64
+	errors            map[string]int64
+	reflectionVersion string
+	services          []string
+}
+
+// NewProbeTracker 创建探测统计器
+func NewProbeTracker() *ProbeTracker {
+	return &ProbeTracker{
+		statuses: make(map[string]int64),
+		errors:   make(map[string]int64),
+	}
+}
+
+// RecordStatus 记录一次健康检查返回的状态
+func (t *ProbeTracker) RecordStatus(servingStatus string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.probes++
+	t.statuses[servingStatus]++
+}
+
+// RecordServices 记录一次反射列出的服务
+func (t *ProbeTracker) RecordServices(version string, services []string) {
+	sorted := append([]string(nil), services...)
+	sort.Strings(sorted)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.probes++
+	t.reflectionVersion = version
+	t.services = sorted
+}
+
+// RecordError 记录一次失败的探测，按gRPC状态码归类
+func (t *ProbeTracker) RecordError(err error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.probes++
+	t.errors[status.Code(err).String()]++
+}
+
+// Stats 获取统计结果
+func (t *ProbeTracker) Stats() ProbeStats {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	stats := ProbeStats{
+		Probes:            t.probes,
+		Statuses:          make(map[string]int64, len(t.statuses)),
+		Errors:            make(map[string]int64, len(t.errors)),
+		ReflectionVersion: t.reflectionVersion,
+		Services:          append([]string(nil), t.services...),
+	}
+	for key, count := range t.statuses {
+		stats.Statuses[key] = count
+	}
+	for key, count := range t.errors {
+		stats.Errors[key] = count
+	}
+	return stats
+}
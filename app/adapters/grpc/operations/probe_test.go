@@ -0,0 +1,162 @@
+package operations
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"abc-runner/app/adapters/grpc/config"
+	"abc-runner/app/adapters/grpc/connection"
+	"abc-runner/app/core/interfaces"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	reflectionv1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+)
+
+// startProbeServer 启动带健康检查服务的进程内gRPC服务端
+// alphaOnly为true时只注册v1alpha反射，用于验证协议回退
+func startProbeServer(t *testing.T, alphaOnly bool) (*health.Server, int) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	server := grpc.NewServer()
+	healthServer := health.NewServer()
+	grpc_health_v1.RegisterHealthServer(server, healthServer)
+	if alphaOnly {
+		reflectionv1alpha.RegisterServerReflectionServer(server, reflection.NewServer(reflection.ServerOptions{Services: server}))
+	} else {
+		reflection.Register(server)
+	}
+
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return healthServer, listener.Addr().(*net.TCPAddr).Port
+}
+
+// newProbeExecutor 创建连接到测试服务端的执行器
+func newProbeExecutor(t *testing.T, port int, testCase string) *GRPCExecutor {
+	cfg := config.NewDefaultGRPCConfig()
+	cfg.Connection.Address = "127.0.0.1"
+	cfg.Connection.Port = port
+	cfg.Connection.Pool.PoolSize = 1
+	cfg.BenchMark.TestCase = testCase
+	cfg.BenchMark.Timeout = 2 * time.Second
+
+	pool := connection.NewConnectionPool(cfg)
+	if err := pool.Initialize(context.Background()); err != nil {
+		t.Fatalf("failed to initialize pool: %v", err)
+	}
+	t.Cleanup(func() { pool.Close() })
+
+	// 拨号是非阻塞的，等待连接就绪
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		conn, err := pool.GetConnection()
+		if err == nil {
+			conn.GetConn().Connect()
+			if conn.GetConn().GetState() == connectivity.Ready {
+				break
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("connection not ready: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return NewGRPCExecutor(pool, cfg, nil)
+}
+
+func TestGRPCExecutor_HealthCheck(t *testing.T) {
+	healthServer, port := startProbeServer(t, false)
+	healthServer.SetServingStatus("demo.Service", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+
+	executor := newProbeExecutor(t, port, "health_check")
+	operation := interfaces.Operation{Type: "health_check"}
+
+	result, err := executor.ExecuteOperation(context.Background(), operation)
+	if err != nil || !result.Success || !result.IsRead || result.Value != "SERVING" {
+		t.Fatalf("expected overall server to be SERVING, got value=%v err=%v", result.Value, err)
+	}
+	if result.BytesRead == 0 {
+		t.Errorf("expected the health response size to be counted, got %d", result.BytesRead)
+	}
+
+	executor.config.GRPCSpecific.HealthService = "demo.Service"
+	if _, err := executor.ExecuteOperation(context.Background(), operation); err == nil || !strings.Contains(err.Error(), "NOT_SERVING") {
+		t.Errorf("expected NOT_SERVING failure, got %v", err)
+	}
+
+	executor.config.GRPCSpecific.HealthService = "missing.Service"
+	if _, err := executor.ExecuteOperation(context.Background(), operation); err == nil {
+		t.Error("expected unknown service to fail")
+	}
+
+	stats := executor.ProbeStats()
+	if stats.Probes != 3 || stats.Statuses["SERVING"] != 1 || stats.Statuses["NOT_SERVING"] != 1 || stats.Errors["NotFound"] != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestGRPCExecutor_ReflectionList(t *testing.T) {
+	for _, alphaOnly := range []bool{false, true} {
+		_, port := startProbeServer(t, alphaOnly)
+		executor := newProbeExecutor(t, port, "reflection_list")
+
+		for i := 0; i < 2; i++ {
+			result, err := executor.ExecuteOperation(context.Background(), interfaces.Operation{Type: "reflection_list"})
+			if err != nil || !result.Success {
+				t.Fatalf("alphaOnly=%v: reflection list failed: %v", alphaOnly, err)
+			}
+			if result.BytesWritten == 0 || result.BytesRead == 0 {
+				t.Fatalf("alphaOnly=%v: expected transferred bytes, got written %d, read %d", alphaOnly, result.BytesWritten, result.BytesRead)
+			}
+		}
+
+		want := "v1"
+		if alphaOnly {
+			want = "v1alpha"
+		}
+		stats := executor.ProbeStats()
+		if stats.Probes != 2 || stats.ReflectionVersion != want {
+			t.Errorf("alphaOnly=%v: unexpected stats: %+v", alphaOnly, stats)
+		}
+		if !strings.Contains(strings.Join(stats.Services, ","), "grpc.health.v1.Health") {
+			t.Errorf("alphaOnly=%v: services = %v", alphaOnly, stats.Services)
+		}
+	}
+}
+
+func TestGRPCExecutor_StreamBytes(t *testing.T) {
+	_, port := startProbeServer(t, false)
+	for _, testCase := range []string{"unary_call", "client_stream", "bidirectional_stream"} {
+		executor := newProbeExecutor(t, port, testCase)
+		operation := NewOperationFactory(executor.config).CreateOperation(1, nil)
+
+		result, err := executor.ExecuteOperation(context.Background(), operation)
+		if err != nil || !result.Success {
+			t.Fatalf("%s failed: %v", testCase, err)
+		}
+		// 模拟调用按请求数据计入写出字节数
+		if result.BytesWritten == 0 {
+			t.Errorf("%s: expected the request size to be counted", testCase)
+		}
+		if testCase == "bidirectional_stream" && result.BytesRead == 0 {
+			t.Errorf("%s: expected the response size to be counted", testCase)
+		}
+	}
+}
+
+func TestGRPCExecutor_ProbeStatsDisabled(t *testing.T) {
+	executor := NewGRPCExecutor(nil, config.NewDefaultGRPCConfig(), nil)
+	if executor.ProbeStats() != nil {
+		t.Error("expected no probe stats for unary_call")
+	}
+}
@@ -0,0 +1,134 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"abc-runner/app/core/metrics"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// StatusCodeStats 单个gRPC状态码的调用统计
+type StatusCodeStats struct {
+	Code    string                 `json:"code"` // 规范状态码名称，如 OK、UNAVAILABLE
+	Count   int64                  `json:"count"`
+	Percent float64                `json:"percent"` // 占全部调用的百分比
+	Latency metrics.LatencyMetrics `json:"latency"`
+}
+
+// statusCodeCounter 单个状态码的计数
+type statusCodeCounter struct {
+	count   int64
+	latency *metrics.LatencyTracker
+}
+
+// StatusCodeTracker 按gRPC状态码统计调用数与延迟
+type StatusCodeTracker struct {
+	mutex sync.Mutex
+	total int64
+	codes map[codes.Code]*statusCodeCounter
+}
+
+// NewStatusCodeTracker 创建状态码统计器
+func NewStatusCodeTracker() *StatusCodeTracker {
+	return &StatusCodeTracker{codes: make(map[codes.Code]*statusCodeCounter)}
+}
+
+// Record 记录一次调用的状态码与延迟
+func (t *StatusCodeTracker) Record(code codes.Code, latency time.Duration) {
+	t.mutex.Lock()
+	counter, ok := t.codes[code]
+	if !ok {
+		counter = &statusCodeCounter{
+			latency: metrics.NewLatencyTracker(metrics.LatencyConfig{
+				SignificantDigits: metrics.DefaultHdrSignificantDigits,
+				SamplingRate:      1.0,
+			}),
+		}
+		t.codes[code] = counter
+	}
+	counter.count++
+	t.total++
+	t.mutex.Unlock()
+
+	counter.latency.Record(latency)
+}
+
+// Stats 按状态码数值顺序（OK在前）获取统计，没有调用时返回nil
+func (t *StatusCodeTracker) Stats() []StatusCodeStats {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.total == 0 {
+		return nil
+	}
+	keys := make([]codes.Code, 0, len(t.codes))
+	for code := range t.codes {
+		keys = append(keys, code)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	stats := make([]StatusCodeStats, 0, len(keys))
+	for _, code := range keys {
+		counter := t.codes[code]
+		stats = append(stats, StatusCodeStats{
+			Code:    StatusCodeName(code),
+			Count:   counter.count,
+			Percent: float64(counter.count) / float64(t.total) * 100,
+			Latency: counter.latency.GetMetrics(),
+		})
+	}
+	return stats
+}
+
+// StatusCode 获取调用结果的gRPC状态码：成功为OK，本地超时与取消按对应状态码归类，
+// 不携带gRPC状态的其它错误为UNKNOWN
+func StatusCode(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+	if s, ok := status.FromError(err); ok {
+		return s.Code()
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return codes.DeadlineExceeded
+	}
+	if errors.Is(err, context.Canceled) {
+		return codes.Canceled
+	}
+	return codes.Unknown
+}
+
+// StatusCodeName 规范状态码名称，如 DEADLINE_EXCEEDED
+func StatusCodeName(code codes.Code) string {
+	if name, ok := statusCodeNames[code]; ok {
+		return name
+	}
+	return code.String()
+}
+
+// statusCodeNames gRPC规范中的状态码名称（codes.Code.String()为驼峰形式）
+var statusCodeNames = map[codes.Code]string{
+	codes.OK:                 "OK",
+	codes.Canceled:           "CANCELLED",
+	codes.Unknown:            "UNKNOWN",
+	codes.InvalidArgument:    "INVALID_ARGUMENT",
+	codes.DeadlineExceeded:   "DEADLINE_EXCEEDED",
+	codes.NotFound:           "NOT_FOUND",
+	codes.AlreadyExists:      "ALREADY_EXISTS",
+	codes.PermissionDenied:   "PERMISSION_DENIED",
+	codes.ResourceExhausted:  "RESOURCE_EXHAUSTED",
+	codes.FailedPrecondition: "FAILED_PRECONDITION",
+	codes.Aborted:            "ABORTED",
+	codes.OutOfRange:         "OUT_OF_RANGE",
+	codes.Unimplemented:      "UNIMPLEMENTED",
+	codes.Internal:           "INTERNAL",
+	codes.Unavailable:        "UNAVAILABLE",
+	codes.DataLoss:           "DATA_LOSS",
+	codes.Unauthenticated:    "UNAUTHENTICATED",
+}
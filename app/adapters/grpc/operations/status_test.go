@@ -0,0 +1,72 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatusCode(t *testing.T) {
+	cases := []struct {
+		err      error
+		expected codes.Code
+	}{
+		{nil, codes.OK},
+		{status.Error(codes.ResourceExhausted, "quota"), codes.ResourceExhausted},
+		{fmt.Errorf("health check failed: %w", status.Error(codes.Unavailable, "connection refused")), codes.Unavailable},
+		{fmt.Errorf("call failed: %w", context.DeadlineExceeded), codes.DeadlineExceeded},
+		{context.Canceled, codes.Canceled},
+		{errors.New("health status NOT_SERVING"), codes.Unknown},
+	}
+	for _, c := range cases {
+		if code := StatusCode(c.err); code != c.expected {
+			t.Errorf("StatusCode(%v) = %v, expected %v", c.err, code, c.expected)
+		}
+	}
+
+	if name := StatusCodeName(codes.DeadlineExceeded); name != "DEADLINE_EXCEEDED" {
+		t.Errorf("unexpected name %q", name)
+	}
+	if name := StatusCodeName(codes.Canceled); name != "CANCELLED" {
+		t.Errorf("unexpected name %q", name)
+	}
+}
+
+func TestStatusCodeTracker(t *testing.T) {
+	tracker := NewStatusCodeTracker()
+	if stats := tracker.Stats(); stats != nil {
+		t.Fatalf("expected no stats before the first call, got %+v", stats)
+	}
+
+	tracker.Record(codes.Unavailable, time.Millisecond)
+	for i := 0; i < 6; i++ {
+		tracker.Record(codes.OK, 10*time.Millisecond)
+	}
+	tracker.Record(codes.DeadlineExceeded, time.Second)
+	tracker.Record(codes.DeadlineExceeded, time.Second)
+	tracker.Record(codes.Unavailable, time.Millisecond)
+
+	stats := tracker.Stats()
+	expected := []struct {
+		code    string
+		count   int64
+		percent float64
+	}{{"OK", 6, 60}, {"DEADLINE_EXCEEDED", 2, 20}, {"UNAVAILABLE", 2, 20}}
+	if len(stats) != len(expected) {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	for i, e := range expected {
+		if stats[i].Code != e.code || stats[i].Count != e.count || stats[i].Percent != e.percent {
+			t.Errorf("stats[%d] = %s %d %.1f%%, expected %s %d %.1f%%", i,
+				stats[i].Code, stats[i].Count, stats[i].Percent, e.code, e.count, e.percent)
+		}
+	}
+	if stats[1].Latency.Max < 999*time.Millisecond || stats[2].Latency.Max > 2*time.Millisecond {
+		t.Errorf("expected latency per code, got %v and %v", stats[1].Latency.Max, stats[2].Latency.Max)
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"abc-runner/app/adapters/http/connection"
 	"abc-runner/app/adapters/http/operations"
 	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/core/transport"
 )
 
 // HttpAdapter HTTP协议适配器
@@ -18,6 +20,8 @@ type HttpAdapter struct {
 	connectionPool *connection.HTTPConnectionPool
 	httpOperations *operations.HttpExecutor
 	config         *httpConfig.HttpAdapterConfig
+	webhook        *operations.WebhookReceiver // 异步回调测试的回调接收端
+	tokens         *connection.TokenSource     // OAuth2认证的令牌来源
 
 	// 指标收集器
 	metricsCollector interfaces.DefaultMetricsCollector
@@ -87,6 +91,33 @@ func (h *HttpAdapter) Connect(ctx context.Context, config interfaces.Config) err
 	// 创建HTTP操作执行器
 	h.httpOperations = operations.NewHttpExecutor(pool, httpConfig, h.metricsCollector)
 
+	// OAuth2认证：运行开始前获取令牌
+	if httpConfig.Auth.Type == "oauth2" {
+		tokens := connection.NewTokenSource(httpConfig.Auth.OAuth2, pool.GetClient())
+		if err := tokens.Fetch(ctx); err != nil {
+			return err
+		}
+		h.tokens = tokens
+		h.httpOperations.SetTokenSource(tokens)
+	}
+
+	// API契约漂移检测：加载OpenAPI文档
+	if spec := httpConfig.Benchmark.Contract.Spec; spec != "" {
+		if err := h.httpOperations.LoadContract(spec); err != nil {
+			return err
+		}
+	}
+
+	// 异步回调测试：启动回调接收端
+	if httpConfig.Benchmark.TestCase == "webhook" {
+		receiver := operations.NewWebhookReceiver(httpConfig.Benchmark.Webhook)
+		if err := receiver.Start(); err != nil {
+			return err
+		}
+		h.webhook = receiver
+		h.httpOperations.SetWebhookReceiver(receiver)
+	}
+
 	// 执行健康检查
 	if err := h.HealthCheck(ctx); err != nil {
 		return fmt.Errorf("initial health check failed: %w", err)
@@ -133,6 +164,13 @@ func (h *HttpAdapter) Close() error {
 		h.connectionPool = nil
 	}
 
+	if h.webhook != nil {
+		if err := h.webhook.Close(); err != nil {
+			return fmt.Errorf("failed to stop webhook receiver: %w", err)
+		}
+		h.webhook = nil
+	}
+
 	h.httpOperations = nil
 	h.isConnected = false
 
@@ -179,6 +217,97 @@ func (h *HttpAdapter) GetProtocolMetrics() map[string]interface{} {
 		metrics["connection_pool"] = poolStats
 	}
 
+	// 添加缓存命中统计
+	if h.httpOperations != nil && h.config != nil && h.config.Benchmark.TestCase == "cache_mix" {
+		metrics["cache"] = h.httpOperations.CacheStats().ToMap()
+	}
+
+	// 添加页面加载统计
+	if h.httpOperations != nil && h.config != nil && h.config.Benchmark.TestCase == "page_load" {
+		metrics["page_load"] = h.httpOperations.PageLoadStats().ToMap()
+	}
+
+	// 添加流式响应统计
+	if h.httpOperations != nil && h.config != nil && h.config.Benchmark.TestCase == "stream" {
+		metrics["stream"] = h.httpOperations.StreamStats().ToMap()
+	}
+
+	// 添加多接口加权测试的按接口统计
+	if h.httpOperations != nil && h.config != nil && (h.config.Benchmark.TestCase == "weighted" || h.config.Benchmark.TestCase == "replay") {
+		endpoints := make([]map[string]interface{}, 0)
+		for _, endpoint := range h.httpOperations.EndpointStats() {
+			endpoints = append(endpoints, endpoint.ToMap())
+		}
+		metrics["endpoints"] = endpoints
+	}
+
+	// 添加请求链场景的按步骤统计
+	if h.httpOperations != nil && h.config != nil && h.config.Benchmark.TestCase == "scenario" {
+		steps := make([]map[string]interface{}, 0)
+		for _, step := range h.httpOperations.ScenarioStepStats() {
+			steps = append(steps, step.ToMap())
+		}
+		metrics["scenario_steps"] = steps
+	}
+
+	// 添加GraphQL测试的按操作统计
+	if h.httpOperations != nil && h.config != nil && h.config.Benchmark.TestCase == "graphql" {
+		operations := make([]map[string]interface{}, 0)
+		for _, operation := range h.httpOperations.GraphQLStats() {
+			operations = append(operations, operation.ToMap())
+		}
+		metrics["graphql_operations"] = operations
+	}
+
+	// 添加回放负载的特征
+	if h.httpOperations != nil {
+		if profile := h.httpOperations.WorkloadProfile(); profile != nil {
+			metrics["workload_profile"] = profile
+		}
+	}
+
+	// 添加响应断言统计
+	if h.httpOperations != nil {
+		if stats, ok := h.httpOperations.ValidationStats(); ok {
+			metrics["validation"] = stats.ToMap()
+		}
+	}
+
+	// 添加API契约校验统计
+	if h.httpOperations != nil {
+		if stats, ok := h.httpOperations.ContractStats(); ok {
+			metrics["contract"] = stats.ToMap()
+		}
+	}
+
+	// 添加cookie会话统计
+	if h.httpOperations != nil {
+		if stats, ok := h.httpOperations.CookieStats(); ok {
+			metrics["cookies"] = stats.ToMap()
+		}
+	}
+
+	// 添加OAuth2令牌统计
+	if h.tokens != nil {
+		metrics["oauth2"] = h.tokens.Stats().ToMap()
+	}
+
+	// 添加出站代理统计
+	if h.connectionPool != nil {
+		if stats, ok := h.connectionPool.ProxyStats(); ok {
+			proxies := make([]map[string]interface{}, 0, len(stats))
+			for _, proxy := range stats {
+				proxies = append(proxies, proxy.ToMap())
+			}
+			metrics["proxies"] = proxies
+		}
+	}
+
+	// 添加异步回调统计
+	if h.webhook != nil {
+		metrics["webhook"] = h.webhook.Stats().ToMap()
+	}
+
 	// 添加配置信息
 	if h.config != nil {
 		metrics["config"] = map[string]interface{}{
@@ -192,6 +321,173 @@ func (h *HttpAdapter) GetProtocolMetrics() map[string]interface{} {
 	return metrics
 }
 
+// CacheStats 获取缓存服务器测试的命中统计
+func (h *HttpAdapter) CacheStats() (operations.CacheStats, bool) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	if h.httpOperations == nil {
+		return operations.CacheStats{}, false
+	}
+	return h.httpOperations.CacheStats(), true
+}
+
+// PageLoadStats 获取页面加载测试的统计
+func (h *HttpAdapter) PageLoadStats() (operations.PageLoadStats, bool) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	if h.httpOperations == nil {
+		return operations.PageLoadStats{}, false
+	}
+	return h.httpOperations.PageLoadStats(), true
+}
+
+// StreamStats 获取流式响应测试的统计
+func (h *HttpAdapter) StreamStats() (operations.StreamStats, bool) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	if h.httpOperations == nil {
+		return operations.StreamStats{}, false
+	}
+	return h.httpOperations.StreamStats(), true
+}
+
+// LatencyPhases 获取按阶段的延迟统计，未连接或没有完整的请求时返回false
+func (h *HttpAdapter) LatencyPhases() ([]metrics.PhaseLatency, bool) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	if h.httpOperations == nil {
+		return nil, false
+	}
+	phases := h.httpOperations.LatencyPhases()
+	return phases, len(phases) > 0
+}
+
+// EndpointStats 获取多接口加权测试中各请求模板的统计
+func (h *HttpAdapter) EndpointStats() ([]operations.EndpointStats, bool) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	if h.httpOperations == nil {
+		return nil, false
+	}
+	return h.httpOperations.EndpointStats(), true
+}
+
+// ScenarioStepStats 获取请求链场景中各步骤的统计
+func (h *HttpAdapter) ScenarioStepStats() ([]operations.EndpointStats, bool) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	if h.httpOperations == nil {
+		return nil, false
+	}
+	return h.httpOperations.ScenarioStepStats(), true
+}
+
+// GraphQLStats 获取GraphQL测试中各操作的统计
+func (h *HttpAdapter) GraphQLStats() ([]operations.GraphQLOperationStats, bool) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	if h.httpOperations == nil {
+		return nil, false
+	}
+	return h.httpOperations.GraphQLStats(), true
+}
+
+// WorkloadProfile 获取回放测试中实际发出请求的负载特征
+func (h *HttpAdapter) WorkloadProfile() (*httpConfig.HttpWorkloadProfile, bool) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	if h.httpOperations == nil {
+		return nil, false
+	}
+	profile := h.httpOperations.WorkloadProfile()
+	return profile, profile != nil
+}
+
+// ValidationStats 获取响应断言的统计，未配置断言或尚无请求时ok为false
+func (h *HttpAdapter) ValidationStats() (operations.ValidationStats, bool) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	if h.httpOperations == nil {
+		return operations.ValidationStats{}, false
+	}
+	return h.httpOperations.ValidationStats()
+}
+
+// ContractStats 获取API契约校验统计，未指定契约文档时ok为false
+func (h *HttpAdapter) ContractStats() (operations.ContractStats, bool) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	if h.httpOperations == nil {
+		return operations.ContractStats{}, false
+	}
+	return h.httpOperations.ContractStats()
+}
+
+// CookieStats 获取cookie会话统计，未启用cookie时ok为false
+func (h *HttpAdapter) CookieStats() (operations.CookieStats, bool) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	if h.httpOperations == nil {
+		return operations.CookieStats{}, false
+	}
+	return h.httpOperations.CookieStats()
+}
+
+// OAuth2Stats 获取OAuth2令牌获取与刷新的统计
+func (h *HttpAdapter) OAuth2Stats() (connection.OAuth2Stats, bool) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	if h.tokens == nil {
+		return connection.OAuth2Stats{}, false
+	}
+	return h.tokens.Stats(), true
+}
+
+// WebhookStats 获取异步回调测试的统计
+func (h *HttpAdapter) WebhookStats() (operations.WebhookStats, bool) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	if h.webhook == nil {
+		return operations.WebhookStats{}, false
+	}
+	return h.webhook.Stats(), true
+}
+
+// TransportStats 获取io_uring传输统计，未启用时返回false
+func (h *HttpAdapter) TransportStats() (transport.Stats, bool) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	if h.connectionPool == nil {
+		return transport.Stats{}, false
+	}
+	return h.connectionPool.TransportStats()
+}
+
+// ProxyStats 获取各出站代理的统计，未配置代理时返回false
+func (h *HttpAdapter) ProxyStats() ([]connection.ProxyStats, bool) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	if h.connectionPool == nil {
+		return nil, false
+	}
+	return h.connectionPool.ProxyStats()
+}
+
 // IsConnected 检查连接状态
 func (h *HttpAdapter) IsConnected() bool {
 	h.mutex.RLock()
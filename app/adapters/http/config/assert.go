@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// HttpAssertion 响应断言，status、header、json与body_contains四选一
+// header与json可以再指定equals、contains或matches之一，均未指定时只要求存在
+type HttpAssertion struct {
+	Status       string `yaml:"status" json:"status"`               // 允许的状态码，如 "200"、"200,201"、"2xx"、"200-299"
+	Header       string `yaml:"header" json:"header"`               // 响应头名称
+	JSON         string `yaml:"json" json:"json"`                   // 响应JSON中的路径，如 $.data.id
+	BodyContains string `yaml:"body_contains" json:"body_contains"` // 响应体须包含的文本
+
+	Equals   string `yaml:"equals" json:"equals"`     // header/json的值须等于
+	Contains string `yaml:"contains" json:"contains"` // header/json的值须包含
+	Matches  string `yaml:"matches" json:"matches"`   // header/json的值须匹配的正则表达式
+}
+
+// Validate 验证断言
+func (a HttpAssertion) Validate() error {
+	kinds := 0
+	for _, set := range []bool{a.Status != "", a.Header != "", a.JSON != "", a.BodyContains != ""} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return fmt.Errorf("assertion needs exactly one of status, header, json or body_contains")
+	}
+
+	conditions := 0
+	for _, condition := range []string{a.Equals, a.Contains, a.Matches} {
+		if condition != "" {
+			conditions++
+		}
+	}
+	if conditions > 0 && a.Header == "" && a.JSON == "" {
+		return fmt.Errorf("equals, contains and matches apply only to header and json assertions")
+	}
+	if conditions > 1 {
+		return fmt.Errorf("assertion can use only one of equals, contains or matches")
+	}
+
+	if a.Status != "" {
+		if _, err := ParseStatusRanges(a.Status); err != nil {
+			return err
+		}
+	}
+	if a.Matches != "" {
+		if _, err := regexp.Compile(a.Matches); err != nil {
+			return fmt.Errorf("invalid matches regex: %w", err)
+		}
+	}
+	return nil
+}
+
+// String 断言的可读描述，用于统计与报告
+func (a HttpAssertion) String() string {
+	var subject string
+	switch {
+	case a.Status != "":
+		return "status " + a.Status
+	case a.BodyContains != "":
+		return fmt.Sprintf("body contains %q", a.BodyContains)
+	case a.Header != "":
+		subject = "header " + a.Header
+	default:
+		subject = "json " + a.JSON
+	}
+	switch {
+	case a.Equals != "":
+		return fmt.Sprintf("%s equals %q", subject, a.Equals)
+	case a.Contains != "":
+		return fmt.Sprintf("%s contains %q", subject, a.Contains)
+	case a.Matches != "":
+		return fmt.Sprintf("%s matches %q", subject, a.Matches)
+	default:
+		return subject + " exists"
+	}
+}
+
+// ParseStatusRanges 解析状态码列表，支持 200、2xx 与 200-299 形式，以逗号分隔
+func ParseStatusRanges(spec string) ([][2]int, error) {
+	var ranges [][2]int
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		switch {
+		case len(part) == 3 && strings.HasSuffix(part, "xx") && part[0] >= '1' && part[0] <= '5':
+			low := int(part[0]-'0') * 100
+			ranges = append(ranges, [2]int{low, low + 99})
+		case strings.Contains(part, "-"):
+			from, to, _ := strings.Cut(part, "-")
+			low, err1 := strconv.Atoi(from)
+			high, err2 := strconv.Atoi(to)
+			if err1 != nil || err2 != nil || low > high {
+				return nil, fmt.Errorf("invalid status range %q", part)
+			}
+			ranges = append(ranges, [2]int{low, high})
+		default:
+			code, err := strconv.Atoi(part)
+			if err != nil || code < 100 || code > 599 {
+				return nil, fmt.Errorf("invalid status code %q", part)
+			}
+			ranges = append(ranges, [2]int{code, code})
+		}
+	}
+	return ranges, nil
+}
+
+// validateAssertions 验证一组断言，where用于错误信息中的位置
+func validateAssertions(assertions []HttpAssertion, where string) error {
+	for i, assertion := range assertions {
+		if err := assertion.Validate(); err != nil {
+			return fmt.Errorf("invalid %s[%d]: %w", where, i, err)
+		}
+	}
+	return nil
+}
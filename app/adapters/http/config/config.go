@@ -1,10 +1,16 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
 	"time"
 
 	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/transport"
+	"abc-runner/app/core/utils"
 )
 
 // LoadDefaultHttpConfig 加载默认HTTP配置
@@ -34,6 +40,12 @@ func LoadDefaultHttpConfig() *HttpAdapterConfig {
 			Method:    "GET",
 			Path:      "/",
 			Headers:   make(map[string]string),
+			Cache:     DefaultHttpCacheConfig(),
+			Page:      DefaultHttpPageConfig(),
+			Webhook:   DefaultHttpWebhookConfig(),
+			Stream:    DefaultHttpStreamConfig(),
+			GraphQL:   DefaultHttpGraphQLConfig(),
+			Cookies:   HttpCookieConfig{Scope: CookieScopeWorker},
 		},
 		Auth: HttpAuthConfig{
 			Type: "none",
@@ -58,6 +70,23 @@ type HttpAdapterConfig struct {
 
 	// 基准测试配置
 	Benchmark HttpBenchmarkConfig `yaml:"benchmark" json:"benchmark"`
+
+	// 代理模式配置
+	Proxy HttpProxyConfig `yaml:"proxy" json:"proxy"`
+}
+
+// HttpProxyConfig 代理模式配置：abc-runner监听listen并转发真实应用的流量，
+// 记录每个接口的请求量、请求/响应大小与延迟，可导出为可回放的工作负载文件
+type HttpProxyConfig struct {
+	Listen         string        `yaml:"listen" json:"listen"`                   // 代理监听地址，为空时不启用代理模式
+	Forward        bool          `yaml:"forward" json:"forward"`                 // 正向代理（客户端将其设为HTTP代理），否则反向代理到base_url
+	Duration       time.Duration `yaml:"duration" json:"duration"`               // 运行时长，0表示直到中断
+	ExportWorkload string        `yaml:"export_workload" json:"export_workload"` // 导出工作负载文件路径，为空时不导出
+}
+
+// Enabled 是否启用代理模式
+func (p HttpProxyConfig) Enabled() bool {
+	return p.Listen != ""
 }
 
 // HttpConnectionConfig HTTP连接配置
@@ -70,6 +99,8 @@ type HttpConnectionConfig struct {
 	IdleConnTimeout    time.Duration `yaml:"idle_conn_timeout" json:"idle_conn_timeout"`     // 空闲连接超时
 	DisableCompression bool          `yaml:"disable_compression" json:"disable_compression"` // 禁用压缩
 	TLS                HttpTLSConfig `yaml:"tls" json:"tls"`                                 // TLS配置
+	Transport          string        `yaml:"transport" json:"transport"`                     // 网络传输: net, io_uring（实验性）
+	Proxies            []string      `yaml:"proxies" json:"proxies"`                         // 出站代理列表（http、https、socks5、socks5h），按连接轮换使用
 }
 
 // HttpTLSConfig TLS配置
@@ -90,6 +121,7 @@ type HttpTLSConfig struct {
 
 // HttpRequestConfig HTTP请求配置
 type HttpRequestConfig struct {
+	Name        string                `yaml:"name" json:"name"`                 // 名称，用于按接口统计，默认为方法与路径
 	Method      string                `yaml:"method" json:"method"`             // 请求方法
 	Path        string                `yaml:"path" json:"path"`                 // 请求路径
 	Headers     map[string]string     `yaml:"headers" json:"headers"`           // 请求头
@@ -97,6 +129,7 @@ type HttpRequestConfig struct {
 	ContentType string                `yaml:"content_type" json:"content_type"` // 内容类型
 	Weight      int                   `yaml:"weight" json:"weight"`             // 权重
 	Upload      *HttpFileUploadConfig `yaml:"upload" json:"upload"`             // 文件上传配置
+	Assert      []HttpAssertion       `yaml:"assert" json:"assert"`             // 响应断言，未通过时计为validation_failed
 }
 
 // HttpFileUploadConfig 文件上传配置
@@ -118,6 +151,58 @@ type HttpAuthConfig struct {
 	Username string `yaml:"username" json:"username"` // 用户名
 	Password string `yaml:"password" json:"password"` // 密码
 	Token    string `yaml:"token" json:"token"`       // Token
+
+	OAuth2 HttpOAuth2Config `yaml:"oauth2" json:"oauth2"` // OAuth2配置（type: oauth2）
+}
+
+// HttpOAuth2Config OAuth2令牌获取配置
+// 运行开始前获取访问令牌，令牌临近过期或请求返回401时刷新；令牌端点的请求不计入测试指标
+type HttpOAuth2Config struct {
+	GrantType     string        `yaml:"grant_type" json:"grant_type"`         // client_credentials（默认）或 refresh_token
+	TokenURL      string        `yaml:"token_url" json:"token_url"`           // 令牌端点
+	ClientID      string        `yaml:"client_id" json:"client_id"`           // 客户端ID
+	ClientSecret  string        `yaml:"client_secret" json:"client_secret"`   // 客户端密钥
+	Scopes        []string      `yaml:"scopes" json:"scopes"`                 // 申请的scope
+	RefreshToken  string        `yaml:"refresh_token" json:"refresh_token"`   // refresh_token模式的刷新令牌
+	AuthStyle     string        `yaml:"auth_style" json:"auth_style"`         // 客户端凭据的传递方式：header（Basic认证，默认）或 body
+	RefreshBefore time.Duration `yaml:"refresh_before" json:"refresh_before"` // 提前刷新的时间，默认30秒
+}
+
+// Grant 实际使用的授权类型
+func (c HttpOAuth2Config) Grant() string {
+	if c.GrantType == "" {
+		return "client_credentials"
+	}
+	return c.GrantType
+}
+
+// Validate 验证OAuth2配置
+func (c HttpOAuth2Config) Validate() error {
+	if c.TokenURL == "" {
+		return fmt.Errorf("token_url is required for oauth2 auth")
+	}
+	if parsed, err := url.Parse(c.TokenURL); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return fmt.Errorf("invalid oauth2 token_url: %s", c.TokenURL)
+	}
+	switch c.Grant() {
+	case "client_credentials":
+		if c.ClientID == "" {
+			return fmt.Errorf("client_id is required for the oauth2 client_credentials grant")
+		}
+	case "refresh_token":
+		if c.RefreshToken == "" {
+			return fmt.Errorf("refresh_token is required for the oauth2 refresh_token grant")
+		}
+	default:
+		return fmt.Errorf("unsupported oauth2 grant_type: %s (expected client_credentials or refresh_token)", c.GrantType)
+	}
+	if c.AuthStyle != "" && c.AuthStyle != "header" && c.AuthStyle != "body" {
+		return fmt.Errorf("invalid oauth2 auth_style: %s (expected header or body)", c.AuthStyle)
+	}
+	if c.RefreshBefore < 0 {
+		return fmt.Errorf("oauth2 refresh_before must not be negative")
+	}
+	return nil
 }
 
 // HttpUploadConfig HTTP上传配置
@@ -160,6 +245,233 @@ type HttpBenchmarkConfig struct {
 	Path        string            `yaml:"path" json:"path"`                 // 请求路径
 	Headers     map[string]string `yaml:"headers" json:"headers"`           // 请求头
 	QueryParams map[string]string `yaml:"query_params" json:"query_params"` // 查询参数
+
+	// 缓存服务器测试配置（test_case为cache_mix时生效）
+	Cache HttpCacheConfig `yaml:"cache" json:"cache"`
+
+	// 页面加载测试配置（test_case为page_load时生效）
+	Page HttpPageConfig `yaml:"page" json:"page"`
+
+	// 异步回调测试配置（test_case为webhook时生效）
+	Webhook HttpWebhookConfig `yaml:"webhook" json:"webhook"`
+
+	// 流式响应测试配置（test_case为stream时生效）
+	Stream HttpStreamConfig `yaml:"stream" json:"stream"`
+
+	// 请求链场景配置（test_case为scenario时生效）
+	Scenario HttpScenarioConfig `yaml:"scenario" json:"scenario"`
+
+	// GraphQL查询与变更配置（test_case为graphql时生效）
+	GraphQL HttpGraphQLConfig `yaml:"graphql" json:"graphql"`
+
+	// cookie会话配置，对所有测试用例生效
+	Cookies HttpCookieConfig `yaml:"cookies" json:"cookies"`
+
+	// API契约校验配置，按OpenAPI文档抽样校验响应，只报告违例不影响请求结果
+	Contract HttpContractConfig `yaml:"contract" json:"contract"`
+
+	// 请求体模板，替代按data_size生成的JSON请求体；其数据文件同时供weighted请求模板中的占位符使用
+	Payload utils.PayloadTemplateConfig `yaml:"payload" json:"payload"`
+
+	// 对所有响应执行的断言，与请求模板及场景步骤自身的断言叠加
+	Assert []HttpAssertion `yaml:"assert" json:"assert"`
+}
+
+// HttpCacheConfig 缓存服务器（CDN/反向代理）测试配置
+type HttpCacheConfig struct {
+	CacheablePercent int    `yaml:"cacheable_percent" json:"cacheable_percent"` // 可缓存请求占比(0-100)
+	CacheablePath    string `yaml:"cacheable_path" json:"cacheable_path"`       // 可缓存URL路径前缀，请求时追加对象编号
+	UncacheablePath  string `yaml:"uncacheable_path" json:"uncacheable_path"`   // 不可缓存URL路径前缀，请求时追加唯一查询参数
+	Objects          int    `yaml:"objects" json:"objects"`                     // 可缓存对象数量
+}
+
+// DefaultHttpCacheConfig 默认缓存服务器测试配置
+func DefaultHttpCacheConfig() HttpCacheConfig {
+	return HttpCacheConfig{
+		CacheablePercent: 80,
+		CacheablePath:    "/static/object",
+		UncacheablePath:  "/api/dynamic",
+		Objects:          100,
+	}
+}
+
+// HttpPageConfig 浏览器式页面加载测试配置
+type HttpPageConfig struct {
+	Path        string   `yaml:"path" json:"path"`               // 页面路径
+	Assets      []string `yaml:"assets" json:"assets"`           // 额外加载的子资源（相对页面地址解析）
+	Discover    bool     `yaml:"discover" json:"discover"`       // 从页面HTML中发现子资源
+	Parallelism int      `yaml:"parallelism" json:"parallelism"` // 每个页面并行加载子资源的请求数
+	MaxAssets   int      `yaml:"max_assets" json:"max_assets"`   // 每个页面最多加载的子资源数，0表示不限制
+}
+
+// DefaultHttpPageConfig 默认页面加载测试配置
+// 并行度取主流浏览器HTTP/1.1下每个主机的连接数上限
+func DefaultHttpPageConfig() HttpPageConfig {
+	return HttpPageConfig{
+		Path:        "/",
+		Discover:    true,
+		Parallelism: 6,
+		MaxAssets:   100,
+	}
+}
+
+// HttpWebhookConfig 异步API（提交任务后回调webhook）测试配置
+// 每个请求携带关联ID提交任务，由运行器内置的接收端按关联ID匹配回调并计算提交到回调的延迟
+type HttpWebhookConfig struct {
+	SubmitPath   string        `yaml:"submit_path" json:"submit_path"`     // 提交任务的路径（POST）
+	Listen       string        `yaml:"listen" json:"listen"`               // 回调接收端监听地址
+	CallbackPath string        `yaml:"callback_path" json:"callback_path"` // 回调接收路径
+	CallbackURL  string        `yaml:"callback_url" json:"callback_url"`   // 告知被测服务的回调地址，为空时由监听地址推导
+	IDHeader     string        `yaml:"id_header" json:"id_header"`         // 携带关联ID的请求头（提交与回调）
+	IDField      string        `yaml:"id_field" json:"id_field"`           // 回调JSON中关联ID的字段，支持a.b形式的嵌套路径
+	Timeout      time.Duration `yaml:"timeout" json:"timeout"`             // 等待回调的超时时间
+}
+
+// DefaultHttpWebhookConfig 默认异步回调测试配置
+func DefaultHttpWebhookConfig() HttpWebhookConfig {
+	return HttpWebhookConfig{
+		SubmitPath:   "/jobs",
+		Listen:       ":8099",
+		CallbackPath: "/callback",
+		IDHeader:     "X-Correlation-ID",
+		IDField:      "correlation_id",
+		Timeout:      30 * time.Second,
+	}
+}
+
+// HttpStreamConfig 流式响应（SSE/分块传输）测试配置
+// 每个操作打开一个流并持续读取，直到达到保持时间、事件数上限或服务端结束响应
+type HttpStreamConfig struct {
+	Path        string        `yaml:"path" json:"path"`                 // 流的路径（GET）
+	Mode        string        `yaml:"mode" json:"mode"`                 // sse按SSE事件（空行结束）计数，chunked按每次到达的数据计数
+	Duration    time.Duration `yaml:"duration" json:"duration"`         // 每个流保持打开的最长时间
+	MaxEvents   int           `yaml:"max_events" json:"max_events"`     // 收到该数量的事件后关闭流，0表示不限制
+	IdleTimeout time.Duration `yaml:"idle_timeout" json:"idle_timeout"` // 等待下一个事件的最长时间，超过视为流中断，0表示不限制
+}
+
+// DefaultHttpStreamConfig 默认流式响应测试配置
+func DefaultHttpStreamConfig() HttpStreamConfig {
+	return HttpStreamConfig{
+		Path:     "/events",
+		Mode:     "sse",
+		Duration: 10 * time.Second,
+	}
+}
+
+// HttpCookieConfig cookie会话配置
+// 启用后保存响应中的Set-Cookie并在后续请求中携带（包括重定向与请求链的各步骤），
+// 使登录会话、负载均衡的会话保持cookie在虚拟用户的整个测试过程中生效
+type HttpCookieConfig struct {
+	Enabled bool   `yaml:"enabled" json:"enabled"` // 启用cookie jar
+	Scope   string `yaml:"scope" json:"scope"`     // worker：每个虚拟用户（工作协程）独立的cookie jar；shared：所有虚拟用户共享一个cookie jar
+}
+
+// 可用的cookie jar范围
+const (
+	CookieScopeWorker = "worker"
+	CookieScopeShared = "shared"
+)
+
+// Validate 验证cookie会话配置
+func (c HttpCookieConfig) Validate() error {
+	switch c.Scope {
+	case "", CookieScopeWorker, CookieScopeShared:
+		return nil
+	}
+	return fmt.Errorf("cookies.scope must be %s or %s, got %q", CookieScopeWorker, CookieScopeShared, c.Scope)
+}
+
+// DefaultContractSampleRate 未指定抽样比例时校验的响应比例
+const DefaultContractSampleRate = 0.1
+
+// HttpContractConfig API契约漂移检测配置
+type HttpContractConfig struct {
+	Spec       string  `yaml:"spec" json:"spec"`               // OpenAPI 3.x或Swagger 2.0文档，为空时不校验
+	SampleRate float64 `yaml:"sample_rate" json:"sample_rate"` // 校验的响应比例(0-1]，为0时使用默认值
+}
+
+// GetSampleRate 获取校验的响应比例
+func (c HttpContractConfig) GetSampleRate() float64 {
+	if c.SampleRate == 0 {
+		return DefaultContractSampleRate
+	}
+	return c.SampleRate
+}
+
+// Validate 验证契约校验配置
+func (c HttpContractConfig) Validate() error {
+	if c.SampleRate < 0 || c.SampleRate > 1 {
+		return fmt.Errorf("contract.sample_rate must be between 0 and 1, got %v", c.SampleRate)
+	}
+	return nil
+}
+
+// HttpScenarioConfig 请求链场景（用户旅程）配置
+// 每个操作按顺序执行全部步骤，从响应中提取的变量以${name}注入后续步骤的路径、请求头与请求体
+type HttpScenarioConfig struct {
+	Variables map[string]string  `yaml:"variables" json:"variables"` // 初始变量，另有内置变量job_id与rand
+	Steps     []HttpScenarioStep `yaml:"steps" json:"steps"`
+}
+
+// HttpScenarioStep 场景中的一步请求，name用于按步骤统计
+type HttpScenarioStep struct {
+	HttpRequestConfig `yaml:",inline"`
+	Extract           []HttpExtractConfig `yaml:"extract" json:"extract"` // 从响应中提取的变量
+}
+
+// HttpExtractConfig 变量提取规则，json、header与regex三选一
+type HttpExtractConfig struct {
+	Var    string `yaml:"var" json:"var"`       // 变量名
+	JSON   string `yaml:"json" json:"json"`     // 响应JSON中的路径，如 $.data.token 或 items[0].id
+	Header string `yaml:"header" json:"header"` // 响应头名称
+	Regex  string `yaml:"regex" json:"regex"`   // 对响应体匹配的正则表达式，有捕获组时取第一个捕获组
+}
+
+// scenarioVariablePattern 合法的变量名
+var scenarioVariablePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Validate 验证场景配置
+func (s HttpScenarioConfig) Validate() error {
+	if len(s.Steps) == 0 {
+		return fmt.Errorf("scenario.steps cannot be empty")
+	}
+	for name := range s.Variables {
+		if !scenarioVariablePattern.MatchString(name) {
+			return fmt.Errorf("invalid scenario variable name %q", name)
+		}
+	}
+	validMethods := []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+	for i, step := range s.Steps {
+		if !contains(validMethods, step.Method) {
+			return fmt.Errorf("invalid method in scenario.steps[%d]: %q", i, step.Method)
+		}
+		if step.Path == "" {
+			return fmt.Errorf("path cannot be empty in scenario.steps[%d]", i)
+		}
+		if err := validateAssertions(step.Assert, fmt.Sprintf("scenario.steps[%d].assert", i)); err != nil {
+			return err
+		}
+		for j, extract := range step.Extract {
+			if !scenarioVariablePattern.MatchString(extract.Var) {
+				return fmt.Errorf("invalid variable name %q in scenario.steps[%d].extract[%d]", extract.Var, i, j)
+			}
+			sources := 0
+			for _, source := range []string{extract.JSON, extract.Header, extract.Regex} {
+				if source != "" {
+					sources++
+				}
+			}
+			if sources != 1 {
+				return fmt.Errorf("scenario.steps[%d].extract[%d] needs exactly one of json, header or regex", i, j)
+			}
+			if extract.Regex != "" {
+				if _, err := regexp.Compile(extract.Regex); err != nil {
+					return fmt.Errorf("invalid regex in scenario.steps[%d].extract[%d]: %w", i, j, err)
+				}
+			}
+		}
+	}
+	return nil
 }
 
 // 实现interfaces.Config接口
@@ -211,6 +523,10 @@ func (c *HttpAdapterConfig) Validate() error {
 		return fmt.Errorf("benchmark config validation failed: %w", err)
 	}
 
+	if c.Proxy.Duration < 0 {
+		return fmt.Errorf("proxy.duration must be non-negative")
+	}
+
 	return nil
 }
 
@@ -237,6 +553,18 @@ func (c *HttpAdapterConfig) Clone() interfaces.Config {
 	clone.Connection.TLS.CipherSuites = make([]string, len(c.Connection.TLS.CipherSuites))
 	copy(clone.Connection.TLS.CipherSuites, c.Connection.TLS.CipherSuites)
 
+	clone.Connection.Proxies = make([]string, len(c.Connection.Proxies))
+	copy(clone.Connection.Proxies, c.Connection.Proxies)
+
+	clone.Benchmark.Page.Assets = make([]string, len(c.Benchmark.Page.Assets))
+	copy(clone.Benchmark.Page.Assets, c.Benchmark.Page.Assets)
+
+	clone.Benchmark.Scenario.Steps = make([]HttpScenarioStep, len(c.Benchmark.Scenario.Steps))
+	copy(clone.Benchmark.Scenario.Steps, c.Benchmark.Scenario.Steps)
+
+	clone.Benchmark.GraphQL.Operations = make([]HttpGraphQLOperation, len(c.Benchmark.GraphQL.Operations))
+	copy(clone.Benchmark.GraphQL.Operations, c.Benchmark.GraphQL.Operations)
+
 	return &clone
 }
 
@@ -292,6 +620,16 @@ func (c *HttpAdapterConfig) validateConnectionConfig() error {
 		return fmt.Errorf("max_conns_per_host must be positive")
 	}
 
+	if err := transport.Validate(c.Connection.Transport); err != nil {
+		return err
+	}
+
+	for i, proxy := range c.Connection.Proxies {
+		if err := ValidateProxyURL(proxy); err != nil {
+			return fmt.Errorf("invalid connection.proxies[%d]: %w", i, err)
+		}
+	}
+
 	// 验证TLS配置
 	if c.Connection.TLS.ClientAuth {
 		if c.Connection.TLS.CertFile == "" {
@@ -322,6 +660,23 @@ func (c *HttpAdapterConfig) validateConnectionConfig() error {
 	return nil
 }
 
+// ValidateProxyURL 验证出站代理地址，支持http、https、socks5与socks5h（由代理解析域名），可带user:password@认证信息
+func ValidateProxyURL(proxy string) error {
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return err
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return fmt.Errorf("%q: unsupported proxy scheme %q (expected http, https, socks5 or socks5h)", u.Redacted(), u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("%q: missing proxy host", u.Redacted())
+	}
+	return nil
+}
+
 // validateRequestConfigs 验证请求配置
 func (c *HttpAdapterConfig) validateRequestConfigs() error {
 	if len(c.Requests) == 0 {
@@ -355,6 +710,10 @@ func (c *HttpAdapterConfig) validateSingleRequestConfig(req HttpRequestConfig, i
 		return fmt.Errorf("weight must be non-negative in request[%d]", index)
 	}
 
+	if err := validateAssertions(req.Assert, fmt.Sprintf("request[%d].assert", index)); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -375,6 +734,10 @@ func (c *HttpAdapterConfig) validateAuthConfig() error {
 		if c.Auth.Token == "" {
 			return fmt.Errorf("token is required for bearer auth")
 		}
+	case "oauth2":
+		if err := c.Auth.OAuth2.Validate(); err != nil {
+			return err
+		}
 	case "mutual_tls":
 		if c.Connection.TLS.CertFile == "" || c.Connection.TLS.KeyFile == "" {
 			return fmt.Errorf("cert_file and key_file are required for mutual TLS auth")
@@ -402,9 +765,146 @@ func (c *HttpAdapterConfig) validateBenchmarkConfig() error {
 		return fmt.Errorf("max_redirects must be non-negative")
 	}
 
+	if c.Benchmark.TestCase == "weighted" || c.Benchmark.TestCase == "replay" {
+		total := 0
+		for _, req := range c.Requests {
+			total += req.Weight
+		}
+		if total <= 0 {
+			return fmt.Errorf("the %s test case needs requests with a positive total weight", c.Benchmark.TestCase)
+		}
+	}
+
+	if c.Benchmark.TestCase == "scenario" {
+		if err := c.Benchmark.Scenario.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.Benchmark.TestCase == "graphql" {
+		if err := c.Benchmark.GraphQL.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := c.validatePayload(); err != nil {
+		return err
+	}
+
+	if err := validateAssertions(c.Benchmark.Assert, "benchmark.assert"); err != nil {
+		return err
+	}
+
+	if err := c.Benchmark.Cookies.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Benchmark.Contract.Validate(); err != nil {
+		return err
+	}
+
+	if c.Benchmark.TestCase == "cache_mix" {
+		if c.Benchmark.Cache.CacheablePercent < 0 || c.Benchmark.Cache.CacheablePercent > 100 {
+			return fmt.Errorf("cache.cacheable_percent must be between 0 and 100")
+		}
+		if c.Benchmark.Cache.Objects <= 0 {
+			return fmt.Errorf("cache.objects must be positive")
+		}
+	}
+
+	if c.Benchmark.TestCase == "page_load" {
+		if c.Benchmark.Page.Path == "" {
+			return fmt.Errorf("page.path cannot be empty")
+		}
+		if c.Benchmark.Page.Parallelism <= 0 {
+			return fmt.Errorf("page.parallelism must be positive")
+		}
+		if c.Benchmark.Page.MaxAssets < 0 {
+			return fmt.Errorf("page.max_assets must be non-negative")
+		}
+	}
+
+	if c.Benchmark.TestCase == "webhook" {
+		webhook := c.Benchmark.Webhook
+		if webhook.SubmitPath == "" || webhook.Listen == "" {
+			return fmt.Errorf("webhook.submit_path and webhook.listen cannot be empty")
+		}
+		if !strings.HasPrefix(webhook.CallbackPath, "/") {
+			return fmt.Errorf("webhook.callback_path must start with /")
+		}
+		if webhook.IDHeader == "" && webhook.IDField == "" {
+			return fmt.Errorf("webhook.id_header or webhook.id_field is required to correlate callbacks")
+		}
+		if webhook.Timeout <= 0 {
+			return fmt.Errorf("webhook.timeout must be positive")
+		}
+	}
+
+	if c.Benchmark.TestCase == "stream" {
+		stream := c.Benchmark.Stream
+		if stream.Path == "" {
+			return fmt.Errorf("stream.path cannot be empty")
+		}
+		if stream.Mode != "sse" && stream.Mode != "chunked" {
+			return fmt.Errorf("stream.mode must be sse or chunked, got %q", stream.Mode)
+		}
+		if stream.Duration <= 0 {
+			return fmt.Errorf("stream.duration must be positive")
+		}
+		if stream.MaxEvents < 0 || stream.IdleTimeout < 0 {
+			return fmt.Errorf("stream.max_events and stream.idle_timeout must be non-negative")
+		}
+	}
+
 	return nil
 }
 
+// validatePayload 验证请求体模板，weighted测试中还验证请求模板路径与请求体中的占位符
+func (c *HttpAdapterConfig) validatePayload() error {
+	data, err := c.Benchmark.Payload.LoadData()
+	if err != nil {
+		return err
+	}
+	if c.Benchmark.Payload.Enabled() {
+		if _, err := utils.ParsePayloadTemplate(c.Benchmark.Payload.Template, data); err != nil {
+			return fmt.Errorf("invalid payload template: %w", err)
+		}
+	}
+	if c.Benchmark.TestCase == "graphql" {
+		return c.Benchmark.GraphQL.validateVariables(data)
+	}
+	if c.Benchmark.TestCase != "weighted" {
+		return nil
+	}
+	for _, req := range c.Requests {
+		for _, text := range []string{req.Path, RequestBodyText(req.Body)} {
+			if !utils.IsPayloadTemplate(text) {
+				continue
+			}
+			if _, err := utils.ParsePayloadTemplate(text, data); err != nil {
+				return fmt.Errorf("invalid template in request %s %s: %w", req.Method, req.Path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// RequestBodyText 请求体的文本形式：字符串按原样，其余按JSON编码，用于识别和渲染其中的模板占位符
+func RequestBodyText(body interface{}) string {
+	switch v := body.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	}
+}
+
 // contains 检查字符串切片是否包含指定元素
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
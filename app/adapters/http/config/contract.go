@@ -0,0 +1,452 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContractUndocumented 请求路径或方法不在契约文档中的响应归入的操作名
+const ContractUndocumented = "(undocumented)"
+
+// maxContractViolations 单个响应最多报告的契约违例数
+const maxContractViolations = 10
+
+// contractMaxDepth 校验响应体时的最大嵌套深度，响应数据有限，仅防止schema间的循环组合无限展开
+const contractMaxDepth = 64
+
+// pathParameterPattern 匹配路径模板中的参数，如{petId}
+var pathParameterPattern = regexp.MustCompile(`\{[^}/]*\}`)
+
+// contractFormats 校验的字符串格式，其余格式不校验
+var contractFormats = map[string]func(string) bool{
+	"date-time": func(value string) bool { _, err := time.Parse(time.RFC3339, value); return err == nil },
+	"date":      func(value string) bool { _, err := time.Parse("2006-01-02", value); return err == nil },
+	"uuid":      regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`).MatchString,
+}
+
+// OpenAPIContract 作为响应契约的OpenAPI 3.x或Swagger 2.0文档
+type OpenAPIContract struct {
+	Source string // 文档路径与标题
+
+	doc    *openAPIDocument
+	prefix string // 服务器地址中的路径前缀
+	paths  []contractPath
+}
+
+// contractPath 文档中的一个路径模板
+type contractPath struct {
+	pattern  *regexp.Regexp
+	literals int // 模板中非参数部分的长度，多个模板匹配时取最具体的
+	item     map[string]interface{}
+	template string
+}
+
+// LoadOpenAPIContract 读取用于校验响应的OpenAPI文档
+func LoadOpenAPIContract(path string) (*OpenAPIContract, error) {
+	doc, err := loadOpenAPIDocument(path)
+	if err != nil {
+		return nil, err
+	}
+
+	contract := &OpenAPIContract{Source: doc.source(path), doc: doc}
+	_, contract.prefix = doc.server()
+	paths := mapField(doc.root, "paths")
+	for _, template := range sortedKeys(paths) {
+		var pattern strings.Builder
+		literals := 0
+		for _, part := range pathParameterPattern.Split(template, -1) {
+			if pattern.Len() > 0 {
+				pattern.WriteString(`[^/]+`)
+			}
+			pattern.WriteString(regexp.QuoteMeta(part))
+			literals += len(part)
+		}
+		contract.paths = append(contract.paths, contractPath{
+			pattern:  regexp.MustCompile("^" + pattern.String() + "$"),
+			literals: literals,
+			item:     doc.resolve(paths[template]),
+			template: template,
+		})
+	}
+	if len(contract.paths) == 0 {
+		return nil, fmt.Errorf("%s has no paths", path)
+	}
+	return contract, nil
+}
+
+// Check 按文档校验一个响应，返回匹配到的操作名（operationId，没有时为"方法 路径模板"）与违例
+// 请求路径可以带查询串或是完整URL；不在文档中的路径或方法归入ContractUndocumented；
+// 依次校验状态码是否在文档中声明、响应内容类型是否声明，以及JSON响应体是否符合schema
+func (c *OpenAPIContract) Check(method, requestPath string, statusCode int, header http.Header, body []byte) (string, []string) {
+	method = strings.ToLower(method)
+	item, template := c.match(requestPath)
+	if item == nil {
+		return ContractUndocumented, []string{"path is not documented"}
+	}
+	operation := mapField(item, method)
+	if operation == nil {
+		return ContractUndocumented, []string{fmt.Sprintf("%s %s is not documented", strings.ToUpper(method), template)}
+	}
+	name := stringField(operation, "operationId")
+	if name == "" {
+		name = strings.ToUpper(method) + " " + template
+	}
+
+	response := c.response(operation["responses"], statusCode)
+	if response == nil {
+		return name, []string{fmt.Sprintf("status %d is not documented", statusCode)}
+	}
+
+	schema, ok := c.responseSchema(response, header.Get("Content-Type"), len(body) > 0)
+	if !ok {
+		return name, []string{fmt.Sprintf("content type %q is not documented for status %d", header.Get("Content-Type"), statusCode)}
+	}
+	if schema == nil {
+		return name, nil
+	}
+
+	var document interface{}
+	if err := json.Unmarshal(body, &document); err != nil {
+		return name, []string{"body is not valid JSON"}
+	}
+	validator := &contractValidator{doc: c.doc, seen: make(map[string]bool)}
+	validator.validate(schema, document, "$", 0)
+	return name, validator.violations
+}
+
+// match 查找请求路径对应的路径项，多个模板匹配时取非参数部分最长的
+func (c *OpenAPIContract) match(requestPath string) (map[string]interface{}, string) {
+	if target, err := url.Parse(requestPath); err == nil {
+		requestPath = target.Path
+	}
+	if c.prefix != "" {
+		requestPath = strings.TrimPrefix(requestPath, c.prefix)
+	}
+	if requestPath == "" {
+		requestPath = "/"
+	}
+
+	var best *contractPath
+	for i := range c.paths {
+		path := &c.paths[i]
+		if !path.pattern.MatchString(requestPath) && !path.pattern.MatchString(strings.TrimSuffix(requestPath, "/")) {
+			continue
+		}
+		if best == nil || path.literals > best.literals {
+			best = path
+		}
+	}
+	if best == nil {
+		return nil, ""
+	}
+	return best.item, best.template
+}
+
+// response 按状态码查找响应定义：精确状态码、状态码范围（如2XX）、default
+// YAML中未加引号的状态码解析为整数键，这时responses不是以字符串为键的对象，按键的文本查找
+func (c *OpenAPIContract) response(responses interface{}, statusCode int) map[string]interface{} {
+	entries := asMap(responses)
+	if raw, ok := responses.(map[interface{}]interface{}); ok {
+		entries = make(map[string]interface{}, len(raw))
+		for key, value := range raw {
+			entries[fmt.Sprint(key)] = value
+		}
+	}
+
+	code := strconv.Itoa(statusCode)
+	for _, key := range []string{code, code[:1] + "XX", code[:1] + "xx", "default"} {
+		if response, ok := entries[key]; ok {
+			return c.doc.resolve(response)
+		}
+	}
+	return nil
+}
+
+// responseSchema 响应体需要符合的JSON schema；内容类型未在文档中声明时ok为false，无需校验时schema为nil
+func (c *OpenAPIContract) responseSchema(response map[string]interface{}, contentType string, hasBody bool) (map[string]interface{}, bool) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	isJSON := mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+
+	if c.doc.swagger {
+		if schema := c.doc.resolve(response["schema"]); schema != nil && hasBody && isJSON {
+			return schema, true
+		}
+		return nil, true
+	}
+
+	content := mapField(response, "content")
+	if len(content) == 0 || !hasBody {
+		return nil, true
+	}
+	for _, key := range matchingMediaTypes(content, mediaType) {
+		if !isJSON {
+			return nil, true
+		}
+		return c.doc.resolve(mapField(content, key)["schema"]), true
+	}
+	return nil, false
+}
+
+// matchingMediaTypes 文档中与响应内容类型匹配的媒体类型，精确匹配优先于type/*与*/*
+func matchingMediaTypes(content map[string]interface{}, mediaType string) []string {
+	major, _, _ := strings.Cut(mediaType, "/")
+	var exact, wildcard []string
+	for _, key := range sortedKeys(content) {
+		parsed, _, err := mime.ParseMediaType(key)
+		if err != nil {
+			parsed = key
+		}
+		switch {
+		case parsed == mediaType:
+			exact = append(exact, key)
+		case parsed == major+"/*", parsed == "*/*":
+			wildcard = append(wildcard, key)
+		}
+	}
+	return append(exact, wildcard...)
+}
+
+// contractValidator 按schema校验JSON值，同一违例在一个响应中只报告一次
+type contractValidator struct {
+	doc        *openAPIDocument
+	violations []string
+	seen       map[string]bool
+}
+
+// fail 记录违例，超过上限后忽略
+func (v *contractValidator) fail(path, format string, args ...interface{}) {
+	violation := path + ": " + fmt.Sprintf(format, args...)
+	if v.seen[violation] || len(v.violations) >= maxContractViolations {
+		return
+	}
+	v.seen[violation] = true
+	v.violations = append(v.violations, violation)
+}
+
+// matches 值是否符合schema，不记录违例（用于oneOf/anyOf）
+func (v *contractValidator) matches(schema map[string]interface{}, value interface{}, depth int) bool {
+	probe := &contractValidator{doc: v.doc, seen: make(map[string]bool)}
+	probe.validate(schema, value, "$", depth)
+	return len(probe.violations) == 0
+}
+
+// validate 校验值是否符合schema；数组元素的路径记为[*]，使不同响应中的同类违例可以合并统计
+func (v *contractValidator) validate(schema map[string]interface{}, value interface{}, path string, depth int) {
+	if schema == nil || depth > contractMaxDepth {
+		return
+	}
+
+	if value == nil {
+		if !nullable(schema) {
+			v.fail(path, "null is not allowed")
+		}
+		return
+	}
+
+	for _, part := range listField(schema, "allOf") {
+		v.validate(v.doc.resolve(part), value, path, depth+1)
+	}
+	// oneOf按anyOf处理：没有discriminator时多个分支同时匹配很常见，不视为契约漂移
+	for _, key := range []string{"oneOf", "anyOf"} {
+		choices := listField(schema, key)
+		if len(choices) == 0 {
+			continue
+		}
+		matched := false
+		for _, choice := range choices {
+			if v.matches(v.doc.resolve(choice), value, depth+1) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.fail(path, "does not match any %s schema", key)
+		}
+	}
+
+	if values := listField(schema, "enum"); len(values) > 0 {
+		found := false
+		for _, allowed := range values {
+			if sameJSONValue(allowed, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.fail(path, "%s is not one of the enum values", truncateValue(value))
+		}
+	}
+
+	types := schemaTypes(schema)
+	actual := jsonType(value)
+	if len(types) > 0 && !types[actual] && !(actual == "integer" && types["number"]) {
+		expected := make([]string, 0, len(types))
+		for t := range types {
+			expected = append(expected, t)
+		}
+		sort.Strings(expected)
+		v.fail(path, "expected %s, got %s", strings.Join(expected, " or "), actual)
+		return
+	}
+
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		v.validateObject(schema, typed, path, depth)
+	case []interface{}:
+		if n, ok := schema["minItems"]; ok && float64(len(typed)) < numberField(schema, "minItems", 0) {
+			v.fail(path, "has %d items, fewer than minItems %v", len(typed), n)
+		}
+		if n, ok := schema["maxItems"]; ok && float64(len(typed)) > numberField(schema, "maxItems", 0) {
+			v.fail(path, "has %d items, more than maxItems %v", len(typed), n)
+		}
+		items := v.doc.resolve(schema["items"])
+		for _, item := range typed {
+			v.validate(items, item, path+"[*]", depth+1)
+		}
+	case string:
+		length := float64(len([]rune(typed)))
+		if n, ok := schema["minLength"]; ok && length < numberField(schema, "minLength", 0) {
+			v.fail(path, "is shorter than minLength %v", n)
+		}
+		if n, ok := schema["maxLength"]; ok && length > numberField(schema, "maxLength", 0) {
+			v.fail(path, "is longer than maxLength %v", n)
+		}
+		if pattern := stringField(schema, "pattern"); pattern != "" {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(typed) {
+				v.fail(path, "does not match pattern %s", pattern)
+			}
+		}
+		if check, ok := contractFormats[stringField(schema, "format")]; ok && !check(typed) {
+			v.fail(path, "is not a valid %s", stringField(schema, "format"))
+		}
+	case float64:
+		if n, ok := schema["minimum"]; ok && (typed < numberField(schema, "minimum", 0) || typed == numberField(schema, "minimum", 0) && schema["exclusiveMinimum"] == true) {
+			v.fail(path, "is below minimum %v", n)
+		}
+		if n, ok := schema["maximum"]; ok && (typed > numberField(schema, "maximum", 0) || typed == numberField(schema, "maximum", 0) && schema["exclusiveMaximum"] == true) {
+			v.fail(path, "is above maximum %v", n)
+		}
+	}
+}
+
+// validateObject 校验对象的必填属性、各属性的值，以及additionalProperties
+func (v *contractValidator) validateObject(schema, object map[string]interface{}, path string, depth int) {
+	properties := mapField(schema, "properties")
+	for _, name := range listField(schema, "required") {
+		key := fmt.Sprint(name)
+		if _, ok := object[key]; ok {
+			continue
+		}
+		// 只写属性不会出现在响应中
+		if v.doc.resolve(properties[key])["writeOnly"] == true {
+			continue
+		}
+		v.fail(path, "missing required property %q", key)
+	}
+
+	additional := schema["additionalProperties"]
+	for _, key := range sortedKeys(object) {
+		if property, ok := properties[key]; ok {
+			v.validate(v.doc.resolve(property), object[key], path+"."+key, depth+1)
+			continue
+		}
+		switch extra := additional.(type) {
+		case bool:
+			if !extra {
+				v.fail(path, "undocumented property %q", key)
+			}
+		case map[string]interface{}:
+			v.validate(v.doc.resolve(extra), object[key], path+"."+key, depth+1)
+		}
+	}
+}
+
+// nullable schema是否允许null：OpenAPI 3.0的nullable、3.1类型数组中的null、Swagger的x-nullable，或未限定类型
+func nullable(schema map[string]interface{}) bool {
+	if schema["nullable"] == true || schema["x-nullable"] == true {
+		return true
+	}
+	if schemaTypes(schema)["null"] {
+		return true
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		for _, choice := range listField(schema, key) {
+			if asMap(choice)["type"] == "null" {
+				return true
+			}
+		}
+	}
+	return len(schemaTypes(schema)) == 0 && schema["enum"] == nil && schema["allOf"] == nil && schema["oneOf"] == nil && schema["anyOf"] == nil
+}
+
+// schemaTypes schema显式声明的类型，未声明时为空（不校验类型）
+func schemaTypes(schema map[string]interface{}) map[string]bool {
+	types := make(map[string]bool)
+	switch value := schema["type"].(type) {
+	case string:
+		types[value] = true
+	case []interface{}:
+		for _, t := range value {
+			types[fmt.Sprint(t)] = true
+		}
+	}
+	return types
+}
+
+// jsonType JSON值的类型，整数值为integer，非整数的数值为number
+func jsonType(value interface{}) string {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if typed == math.Trunc(typed) {
+			return "integer"
+		}
+		return "number"
+	}
+	return "null"
+}
+
+// sameJSONValue 比较文档中的枚举值与响应中的值，数值按浮点数比较
+func sameJSONValue(allowed, value interface{}) bool {
+	switch a := allowed.(type) {
+	case int:
+		b, ok := value.(float64)
+		return ok && float64(a) == b
+	case float64:
+		b, ok := value.(float64)
+		return ok && a == b
+	}
+	return fmt.Sprint(allowed) == fmt.Sprint(value)
+}
+
+// truncateValue 违例描述中展示的值
+func truncateValue(value interface{}) string {
+	data, _ := json.Marshal(value)
+	text := string(data)
+	if len(text) > 32 {
+		text = text[:32] + "…"
+	}
+	return text
+}
+
+// listField 对象中的数组字段
+func listField(object map[string]interface{}, key string) []interface{} {
+	list, _ := object[key].([]interface{})
+	return list
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+const contractOpenAPI = `
+openapi: 3.1.0
+info: {title: Orders}
+servers:
+  - url: https://api.example.com/v2
+paths:
+  /orders:
+    get:
+      operationId: listOrders
+      responses:
+        200:
+          content:
+            application/json:
+              schema:
+                type: array
+                items: {$ref: '#/components/schemas/Order'}
+  /orders/{id}:
+    get:
+      operationId: getOrder
+      responses:
+        '200':
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Order'}
+        4XX:
+          content:
+            application/problem+json:
+              schema:
+                type: object
+                required: [title]
+  /orders/summary:
+    get:
+      responses:
+        '204': {description: empty}
+components:
+  schemas:
+    Order:
+      type: object
+      additionalProperties: false
+      required: [id, state, total]
+      properties:
+        id: {type: string, format: uuid}
+        state: {type: string, enum: [open, paid]}
+        total: {type: number, minimum: 0}
+        items: {type: integer}
+        note: {type: [string, 'null']}
+        secret: {type: string, writeOnly: true}
+`
+
+func TestOpenAPIContract(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.yaml")
+	if err := os.WriteFile(path, []byte(contractOpenAPI), 0644); err != nil {
+		t.Fatalf("failed to write spec: %v", err)
+	}
+	contract, err := LoadOpenAPIContract(path)
+	if err != nil {
+		t.Fatalf("failed to load contract: %v", err)
+	}
+	if contract.Source != path+" (Orders)" {
+		t.Errorf("unexpected source %q", contract.Source)
+	}
+
+	json := http.Header{"Content-Type": []string{"application/json; charset=utf-8"}}
+	problem := http.Header{"Content-Type": []string{"application/problem+json"}}
+	const order = `{"id":"0b6e3a52-3d1c-4d6e-9a61-6c1c5e0c2f1a","state":"open","total":12.5,"note":null}`
+	cases := []struct {
+		method, path string
+		status       int
+		header       http.Header
+		body         string
+		operation    string
+		violations   []string
+	}{
+		{"GET", "/v2/orders/0b6e3a52?expand=items", 200, json, order, "getOrder", nil},
+		{"GET", "https://api.example.com/v2/orders", 200, json, "[" + order + "," + order + "]", "listOrders", nil},
+		// 具体的路径模板优先于参数模板，没有operationId时以方法与模板命名
+		{"GET", "/v2/orders/summary", 204, nil, "", "GET /orders/summary", nil},
+		{"GET", "/v2/orders/1", 200, json, `{"id":"1","state":"closed","total":-1,"items":1.5,"extra":true}`, "getOrder", []string{
+			`$.id: is not a valid uuid`,
+			`$: undocumented property "extra"`,
+			`$.items: expected integer, got number`,
+			`$.state: "closed" is not one of the enum values`,
+			`$.total: is below minimum 0`,
+		}},
+		{"GET", "/v2/orders", 200, json, `[{"id":7}]`, "listOrders", []string{
+			`$[*]: missing required property "state"`,
+			`$[*]: missing required property "total"`,
+			`$[*].id: expected string, got integer`,
+		}},
+		{"GET", "/v2/orders/1", 404, problem, `{"title":"not found"}`, "getOrder", nil},
+		{"GET", "/v2/orders/1", 500, json, `{}`, "getOrder", []string{"status 500 is not documented"}},
+		{"GET", "/v2/orders/1", 200, http.Header{"Content-Type": []string{"text/html"}}, "<html>", "getOrder", []string{
+			`content type "text/html" is not documented for status 200`,
+		}},
+		{"GET", "/v2/orders/1", 200, json, `{"id":`, "getOrder", []string{"body is not valid JSON"}},
+		{"DELETE", "/v2/orders/1", 204, nil, "", ContractUndocumented, []string{"DELETE /orders/{id} is not documented"}},
+		{"GET", "/v2/customers", 200, json, `{}`, ContractUndocumented, []string{"path is not documented"}},
+	}
+	for _, c := range cases {
+		operation, violations := contract.Check(c.method, c.path, c.status, c.header, []byte(c.body))
+		if operation != c.operation || !reflect.DeepEqual(sortedStrings(violations), sortedStrings(c.violations)) {
+			t.Errorf("%s %s %d: got %s %q, expected %s %q", c.method, c.path, c.status, operation, violations, c.operation, c.violations)
+		}
+	}
+
+	if err := (HttpContractConfig{SampleRate: 1.5}).Validate(); err == nil {
+		t.Errorf("expected a sample rate above 1 to be rejected")
+	}
+	if rate := (HttpContractConfig{}).GetSampleRate(); rate != DefaultContractSampleRate {
+		t.Errorf("expected the default sample rate, got %v", rate)
+	}
+}
+
+// sortedStrings 排序后的副本，nil与空切片等同
+func sortedStrings(values []string) []string {
+	sorted := append([]string{}, values...)
+	sort.Strings(sorted)
+	return sorted
+}
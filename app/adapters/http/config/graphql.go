@@ -0,0 +1,191 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"abc-runner/app/core/utils"
+)
+
+// HttpGraphQLConfig GraphQL查询与变更测试配置
+// 每个操作按权重选取一个GraphQL操作，以POST application/json发送到path；
+// 响应中非空的errors数组计为逻辑失败，统计按GraphQL操作名汇总而不是按URL
+type HttpGraphQLConfig struct {
+	Path       string                 `yaml:"path" json:"path"`             // GraphQL端点路径或完整URL
+	Operations []HttpGraphQLOperation `yaml:"operations" json:"operations"` // 按权重执行的GraphQL操作
+}
+
+// HttpGraphQLOperation 单个GraphQL操作
+type HttpGraphQLOperation struct {
+	Name      string      `yaml:"name" json:"name"`           // 报告中的名称；文档定义了多个操作时选取同名操作，未配置时取文档中的操作名
+	Query     string      `yaml:"query" json:"query"`         // GraphQL文档（query或mutation）
+	Variables interface{} `yaml:"variables" json:"variables"` // 变量，JSON对象或其文本，可使用请求体模板的占位符
+	Weight    int         `yaml:"weight" json:"weight"`       // 权重，全部为0时各操作均匀选取
+}
+
+// GraphQLOperationDefinition 文档中定义的一个操作
+type GraphQLOperationDefinition struct {
+	Type string // query、mutation或subscription
+	Name string // 操作名，匿名操作为空
+}
+
+// DefaultHttpGraphQLConfig 默认GraphQL测试配置
+func DefaultHttpGraphQLConfig() HttpGraphQLConfig {
+	return HttpGraphQLConfig{
+		Path: "/graphql",
+	}
+}
+
+// Validate 验证GraphQL测试配置，变量模板由validatePayload结合数据文件验证
+func (g HttpGraphQLConfig) Validate() error {
+	if g.Path == "" {
+		return fmt.Errorf("graphql.path cannot be empty")
+	}
+	if len(g.Operations) == 0 {
+		return fmt.Errorf("graphql.operations cannot be empty")
+	}
+	for i, operation := range g.Operations {
+		if strings.TrimSpace(operation.Query) == "" {
+			return fmt.Errorf("query cannot be empty in graphql.operations[%d]", i)
+		}
+		if operation.Weight < 0 {
+			return fmt.Errorf("weight must be non-negative in graphql.operations[%d]", i)
+		}
+		definition, err := operation.Resolve()
+		if err != nil {
+			return fmt.Errorf("invalid graphql.operations[%d]: %w", i, err)
+		}
+		if definition.Type == "subscription" {
+			return fmt.Errorf("invalid graphql.operations[%d]: subscriptions need a WebSocket or SSE transport and are not supported", i)
+		}
+	}
+	return nil
+}
+
+// validateVariables 验证各操作的变量：渲染占位符后必须是JSON对象
+func (g HttpGraphQLConfig) validateVariables(data *utils.TemplateData) error {
+	for i, operation := range g.Operations {
+		text := RequestBodyText(operation.Variables)
+		if text == "" {
+			continue
+		}
+		if utils.IsPayloadTemplate(text) {
+			template, err := utils.ParsePayloadTemplate(text, data)
+			if err != nil {
+				return fmt.Errorf("invalid template in graphql.operations[%d].variables: %w", i, err)
+			}
+			text = template.Render(0)
+		}
+		var variables map[string]interface{}
+		if err := json.Unmarshal([]byte(text), &variables); err != nil {
+			return fmt.Errorf("graphql.operations[%d].variables must be a JSON object: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Resolve 确定执行的操作：文档只有一个操作时取该操作，有多个时按name选取
+func (o HttpGraphQLOperation) Resolve() (GraphQLOperationDefinition, error) {
+	definitions := ParseGraphQLDocument(o.Query)
+	if len(definitions) == 0 {
+		return GraphQLOperationDefinition{}, fmt.Errorf("the document defines no query or mutation")
+	}
+	if o.Name != "" {
+		for _, definition := range definitions {
+			if definition.Name == o.Name {
+				return definition, nil
+			}
+		}
+	}
+	if len(definitions) > 1 {
+		if o.Name == "" {
+			return GraphQLOperationDefinition{}, fmt.Errorf("the document defines %d operations, set name to pick one", len(definitions))
+		}
+		return GraphQLOperationDefinition{}, fmt.Errorf("operation %q is not defined in the document", o.Name)
+	}
+	return definitions[0], nil
+}
+
+// ParseGraphQLDocument 按顺序列出文档中顶层的操作定义，片段定义不计入；
+// 只识别结构，不检查语法，语法错误由服务端在响应的errors中报告
+func ParseGraphQLDocument(document string) []GraphQLOperationDefinition {
+	var definitions []GraphQLOperationDefinition
+	depth := 0
+	header := ""          // 顶层当前定义的关键字：operation、fragment，或为空
+	nameExpected := false // 操作关键字之后、参数与选择集之前，下一个名称是操作名
+
+	for i := 0; i < len(document); i++ {
+		c := document[i]
+		switch {
+		case c == '#':
+			for i < len(document) && document[i] != '\n' {
+				i++
+			}
+		case c == '"':
+			i = skipGraphQLString(document, i)
+		case c == '{' || c == '(' || c == '[':
+			if depth == 0 && c == '{' && header == "" {
+				// 省略关键字的简写形式即匿名查询
+				definitions = append(definitions, GraphQLOperationDefinition{Type: "query"})
+				header = "operation"
+			}
+			nameExpected = false
+			depth++
+		case c == '}' || c == ')' || c == ']':
+			if depth > 0 {
+				depth--
+			}
+			if depth == 0 && c == '}' {
+				header = ""
+			}
+		case c == '_' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z':
+			start := i
+			for i+1 < len(document) && isGraphQLNameChar(document[i+1]) {
+				i++
+			}
+			if depth > 0 {
+				continue
+			}
+			word := document[start : i+1]
+			switch {
+			case header == "" && (word == "query" || word == "mutation" || word == "subscription"):
+				definitions = append(definitions, GraphQLOperationDefinition{Type: word})
+				header, nameExpected = "operation", true
+			case header == "" && word == "fragment":
+				header = "fragment"
+			case nameExpected:
+				definitions[len(definitions)-1].Name = word
+				nameExpected = false
+			}
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+		default:
+			nameExpected = false
+		}
+	}
+	return definitions
+}
+
+// skipGraphQLString 跳过从i开始的字符串或块字符串，返回其结束引号的位置
+func skipGraphQLString(document string, i int) int {
+	if strings.HasPrefix(document[i:], `"""`) {
+		if end := strings.Index(document[i+3:], `"""`); end >= 0 {
+			return i + 3 + end + 2
+		}
+		return len(document)
+	}
+	for i++; i < len(document); i++ {
+		switch document[i] {
+		case '\\':
+			i++
+		case '"', '\n':
+			return i
+		}
+	}
+	return len(document)
+}
+
+// isGraphQLNameChar 是否为GraphQL名称中的字符
+func isGraphQLNameChar(c byte) bool {
+	return c == '_' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9'
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseGraphQLDocument(t *testing.T) {
+	tests := map[string][]GraphQLOperationDefinition{
+		`{ viewer { id } }`: {{Type: "query"}},
+		`query GetUser($id: ID! = "query Fake") { user(id: $id) { ...fields } }
+		 fragment fields on User { id query }
+		 # mutation Commented { x }
+		 mutation UpdateUser @audit { update { id } }`: {{Type: "query", Name: "GetUser"}, {Type: "mutation", Name: "UpdateUser"}},
+		`query { search(filter: {query: "a"}) { query } }`: {{Type: "query"}},
+		`subscription OnEvent { event { id } }`:            {{Type: "subscription", Name: "OnEvent"}},
+		`fragment only on User { id }`:                     nil,
+	}
+	for document, want := range tests {
+		if got := ParseGraphQLDocument(document); !reflect.DeepEqual(got, want) {
+			t.Errorf("ParseGraphQLDocument(%q) = %+v, want %+v", document, got, want)
+		}
+	}
+}
+
+func TestGraphQLConfigValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation HttpGraphQLOperation
+		valid     bool
+	}{
+		{"anonymous", HttpGraphQLOperation{Query: `{ viewer { id } }`}, true},
+		{"pick by name", HttpGraphQLOperation{Name: "B", Query: `query A { a } query B { b }`}, true},
+		{"label for a single operation", HttpGraphQLOperation{Name: "viewer", Query: `query Me { me { id } }`}, true},
+		{"templated variables", HttpGraphQLOperation{Query: `query Q($id: ID) { q(id: $id) }`, Variables: `{"id": "{{uuid}}", "n": {{randInt 1 9}}}`}, true},
+		{"map variables", HttpGraphQLOperation{Query: `query Q($id: ID) { q(id: $id) }`, Variables: map[string]interface{}{"id": 1}}, true},
+		{"empty query", HttpGraphQLOperation{Query: "  "}, false},
+		{"ambiguous", HttpGraphQLOperation{Query: `query A { a } query B { b }`}, false},
+		{"unknown name", HttpGraphQLOperation{Name: "C", Query: `query A { a } query B { b }`}, false},
+		{"fragments only", HttpGraphQLOperation{Query: `fragment f on User { id }`}, false},
+		{"subscription", HttpGraphQLOperation{Query: `subscription S { s }`}, false},
+		{"negative weight", HttpGraphQLOperation{Query: `{ a }`, Weight: -1}, false},
+		{"variables not an object", HttpGraphQLOperation{Query: `{ a }`, Variables: `[1, 2]`}, false},
+		{"bad template", HttpGraphQLOperation{Query: `{ a }`, Variables: `{"id": "{{nope}}"}`}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := LoadDefaultHttpConfig()
+			config.Connection.BaseURL = "http://localhost:8080"
+			config.Benchmark.TestCase = "graphql"
+			config.Benchmark.GraphQL.Operations = []HttpGraphQLOperation{test.operation}
+			if err := config.Validate(); (err == nil) != test.valid {
+				t.Errorf("Validate() error = %v, want valid %v", err, test.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,509 @@
+package config
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/textproto"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// HttpImport 从浏览器HAR记录或curl命令转换得到的请求链场景，或从OpenAPI文档转换得到的加权请求模板
+// 与基础地址同源的请求写为路径，其余保留完整URL，因此--url可将记录的流量指向其他环境
+type HttpImport struct {
+	Source   string              // 来源描述，用于输出
+	BaseURL  string              // 首个请求的源（scheme://host），OpenAPI文档未指定主机时为空
+	Scenario HttpScenarioConfig  // 按记录顺序排列的步骤
+	Requests []HttpRequestConfig // OpenAPI导入的加权请求模板，每个操作一个
+	Skipped  int                 // 未导入的记录数（非HTTP(S)地址、不支持的方法、二进制请求体或未匹配过滤条件）
+}
+
+// Len 导入的请求数
+func (i *HttpImport) Len() int {
+	if len(i.Requests) > 0 {
+		return len(i.Requests)
+	}
+	return len(i.Scenario.Steps)
+}
+
+// Apply 以导入的场景或加权请求模板替换配置中的请求
+func (i *HttpImport) Apply(c *HttpAdapterConfig) {
+	if i.BaseURL != "" {
+		c.Connection.BaseURL = i.BaseURL
+	}
+	if len(i.Requests) > 0 {
+		c.Benchmark.TestCase = "weighted"
+		c.Requests = i.Requests
+		return
+	}
+	c.Benchmark.TestCase = "scenario"
+	c.Benchmark.Scenario = i.Scenario
+}
+
+// importSkippedHeaders 导入时不保留的请求头：由客户端自行生成的逐跳头与长度、压缩协商头
+var importSkippedHeaders = map[string]bool{
+	"Host": true, "Content-Length": true, "Accept-Encoding": true,
+	"Connection": true, "Keep-Alive": true, "Proxy-Connection": true,
+	"Te": true, "Trailer": true, "Transfer-Encoding": true, "Upgrade": true,
+}
+
+// importMethods 场景步骤支持的请求方法
+var importMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+
+// harFile HAR 1.2文件中导入用到的部分
+type harFile struct {
+	Log struct {
+		Entries []struct {
+			Request struct {
+				Method   string         `json:"method"`
+				URL      string         `json:"url"`
+				Headers  []harNameValue `json:"headers"`
+				PostData *struct {
+					MimeType string         `json:"mimeType"`
+					Text     string         `json:"text"`
+					Encoding string         `json:"encoding"`
+					Params   []harNameValue `json:"params"`
+				} `json:"postData"`
+			} `json:"request"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+// harNameValue HAR中的名称/值对
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ImportHAR 将HAR文件中的请求按记录顺序转换为场景步骤，include不为空时只导入URL匹配的请求
+func ImportHAR(path string, include *regexp.Regexp) (*HttpImport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HAR file: %w", err)
+	}
+	var har harFile
+	if err := json.Unmarshal(data, &har); err != nil {
+		return nil, fmt.Errorf("failed to parse HAR file %s: %w", path, err)
+	}
+
+	imported := &HttpImport{Source: path}
+	for _, entry := range har.Log.Entries {
+		request := entry.Request
+		if include != nil && !include.MatchString(request.URL) {
+			imported.Skipped++
+			continue
+		}
+		headers := make(map[string]string)
+		for _, header := range request.Headers {
+			// HTTP/2伪头部（:authority、:path等）由URL表达
+			if strings.HasPrefix(header.Name, ":") {
+				continue
+			}
+			name := canonicalHeader(header.Name)
+			if existing, ok := headers[name]; ok {
+				headers[name] = existing + ", " + header.Value
+			} else {
+				headers[name] = header.Value
+			}
+		}
+		var body string
+		if postData := request.PostData; postData != nil {
+			body = postData.Text
+			if postData.Encoding == "base64" {
+				decoded, err := base64.StdEncoding.DecodeString(body)
+				if err != nil {
+					return nil, fmt.Errorf("invalid base64 request body for %s: %w", request.URL, err)
+				}
+				body = string(decoded)
+			}
+			if body == "" && len(postData.Params) > 0 {
+				values := url.Values{}
+				for _, param := range postData.Params {
+					values.Add(param.Name, param.Value)
+				}
+				body = values.Encode()
+			}
+			if postData.MimeType != "" && headers["Content-Type"] == "" {
+				headers["Content-Type"] = postData.MimeType
+			}
+		}
+		if !imported.add(request.Method, request.URL, headers, body) {
+			imported.Skipped++
+		}
+	}
+	if len(imported.Scenario.Steps) == 0 {
+		return nil, fmt.Errorf("no HTTP(S) requests to import in %s (%d skipped)", path, imported.Skipped)
+	}
+	return imported, nil
+}
+
+// ImportCurl 将curl命令行（如浏览器"Copy as cURL"的结果）按顺序转换为场景步骤
+func ImportCurl(commands []string) (*HttpImport, error) {
+	imported := &HttpImport{Source: "curl"}
+	for _, command := range commands {
+		request, err := parseCurl(command)
+		if err != nil {
+			return nil, fmt.Errorf("invalid curl command: %w", err)
+		}
+		if !imported.add(request.method, request.url, request.headers, request.body) {
+			return nil, fmt.Errorf("invalid curl command: unsupported method %s, URL %q or binary body", request.method, request.url)
+		}
+	}
+	if len(imported.Scenario.Steps) == 0 {
+		return nil, fmt.Errorf("no curl commands to import")
+	}
+	return imported, nil
+}
+
+// add 添加一个步骤，非HTTP(S)地址、不支持的方法或二进制请求体（无法写入请求模板）时返回false
+func (i *HttpImport) add(method, rawURL string, headers map[string]string, body string) bool {
+	method = strings.ToUpper(method)
+	target, err := url.Parse(rawURL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" ||
+		!contains(importMethods, method) || !utf8.ValidString(body) {
+		return false
+	}
+	target.Fragment = ""
+
+	origin := target.Scheme + "://" + target.Host
+	if i.BaseURL == "" {
+		i.BaseURL = origin
+	}
+	path := target.String()
+	if origin == i.BaseURL {
+		path = target.RequestURI()
+	}
+
+	step := HttpScenarioStep{HttpRequestConfig: HttpRequestConfig{Method: method, Path: path}}
+	contentType := headers["Content-Type"]
+	for name, value := range headers {
+		if importSkippedHeaders[name] || name == "Content-Type" {
+			continue
+		}
+		if step.Headers == nil {
+			step.Headers = make(map[string]string)
+		}
+		step.Headers[name] = value
+	}
+	if body != "" {
+		step.Body, step.ContentType = importBody(contentType, body)
+		// 以文本发送的请求体保留原始类型
+		if step.ContentType == "text/plain" && contentType != "" {
+			if step.Headers == nil {
+				step.Headers = make(map[string]string)
+			}
+			step.Headers["Content-Type"] = contentType
+		}
+	}
+	i.Scenario.Steps = append(i.Scenario.Steps, step)
+	return true
+}
+
+// importBody 将请求体转换为请求模板的body：JSON解码为对象，表单解码为字段，其余按原文发送
+func importBody(contentType, body string) (interface{}, string) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		var value interface{}
+		if json.Unmarshal([]byte(body), &value) == nil {
+			return value, "application/json"
+		}
+	case mediaType == "application/x-www-form-urlencoded":
+		// 重复的字段无法用对象表达，按原文发送
+		if values, err := url.ParseQuery(body); err == nil {
+			form := make(map[string]interface{}, len(values))
+			for key, list := range values {
+				if len(list) > 1 {
+					return body, "text/plain"
+				}
+				form[key] = list[0]
+			}
+			return form, "application/x-www-form-urlencoded"
+		}
+	}
+	return body, "text/plain"
+}
+
+// canonicalHeader 请求头名称的规范形式（HTTP/2记录中为小写）
+func canonicalHeader(name string) string {
+	return textproto.CanonicalMIMEHeaderKey(strings.TrimSpace(name))
+}
+
+// curlRequest 从curl命令行解析出的请求
+type curlRequest struct {
+	method  string
+	url     string
+	headers map[string]string
+	body    string
+}
+
+// curlIgnoredFlags 不影响请求内容、导入时忽略的无参数选项
+var curlIgnoredFlags = map[string]bool{
+	"--compressed": true, "-k": true, "--insecure": true, "-L": true, "--location": true,
+	"-s": true, "--silent": true, "-S": true, "--show-error": true, "-v": true, "--verbose": true,
+	"-i": true, "--include": true, "-f": true, "--fail": true, "-N": true, "--no-buffer": true,
+	"--http1.1": true, "--http2": true, "--http2-prior-knowledge": true, "-#": true, "--progress-bar": true,
+}
+
+// curlIgnoredOptions 不影响请求内容、导入时忽略的带参数选项
+var curlIgnoredOptions = map[string]bool{
+	"-o": true, "--output": true, "-m": true, "--max-time": true, "--connect-timeout": true,
+	"-w": true, "--write-out": true, "--retry": true, "--cacert": true, "--cert": true, "--key": true,
+	"--resolve": true, "--proxy": true, "-x": true, "--limit-rate": true,
+}
+
+// parseCurl 解析一条curl命令行
+func parseCurl(command string) (*curlRequest, error) {
+	args, err := splitShellWords(command)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 || args[0] != "curl" {
+		return nil, fmt.Errorf("expected a command starting with curl")
+	}
+
+	request := &curlRequest{headers: make(map[string]string)}
+	var data []string
+	get := false
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		if curlIgnoredFlags[arg] {
+			continue
+		}
+		// 合并的短选项，如 -sSL
+		if len(arg) > 2 && arg[0] == '-' && arg[1] != '-' && allIgnoredShortFlags(arg[1:]) {
+			continue
+		}
+		if !strings.HasPrefix(arg, "-") {
+			if request.url != "" {
+				return nil, fmt.Errorf("more than one URL: %q and %q", request.url, arg)
+			}
+			request.url = arg
+			continue
+		}
+
+		switch arg {
+		case "-I", "--head":
+			request.method = "HEAD"
+			continue
+		case "-G", "--get":
+			get = true
+			continue
+		}
+
+		if i+1 >= len(args) {
+			return nil, fmt.Errorf("missing value for %s", arg)
+		}
+		value := args[i+1]
+		i++
+		switch {
+		case curlIgnoredOptions[arg]:
+		case arg == "-X" || arg == "--request":
+			request.method = strings.ToUpper(value)
+		case arg == "-H" || arg == "--header":
+			name, headerValue, ok := strings.Cut(value, ":")
+			if !ok {
+				return nil, fmt.Errorf("invalid header %q", value)
+			}
+			request.headers[canonicalHeader(name)] = strings.TrimSpace(headerValue)
+		case arg == "-d" || arg == "--data" || arg == "--data-ascii" || arg == "--data-binary":
+			if file, ok := strings.CutPrefix(value, "@"); ok {
+				content, err := os.ReadFile(file)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read %s data: %w", arg, err)
+				}
+				value = string(content)
+				if arg != "--data-binary" {
+					value = strings.NewReplacer("\r", "", "\n", "").Replace(value)
+				}
+			}
+			data = append(data, value)
+		case arg == "--data-raw":
+			data = append(data, value)
+		case arg == "--data-urlencode":
+			if name, content, ok := strings.Cut(value, "="); ok {
+				data = append(data, name+"="+url.QueryEscape(content))
+			} else {
+				data = append(data, url.QueryEscape(value))
+			}
+		case arg == "--json":
+			data = append(data, value)
+			setDefaultHeader(request.headers, "Content-Type", "application/json")
+			setDefaultHeader(request.headers, "Accept", "application/json")
+		case arg == "-u" || arg == "--user":
+			request.headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(value))
+		case arg == "-A" || arg == "--user-agent":
+			request.headers["User-Agent"] = value
+		case arg == "-e" || arg == "--referer":
+			request.headers["Referer"] = value
+		case arg == "-b" || arg == "--cookie":
+			if !strings.Contains(value, "=") {
+				return nil, fmt.Errorf("cookie files are not supported (%s %s)", arg, value)
+			}
+			request.headers["Cookie"] = value
+		case arg == "--url":
+			request.url = value
+		default:
+			return nil, fmt.Errorf("unsupported option %s", arg)
+		}
+	}
+
+	if request.url == "" {
+		return nil, fmt.Errorf("missing URL")
+	}
+	if !strings.Contains(request.url, "://") {
+		request.url = "http://" + request.url
+	}
+	body := strings.Join(data, "&")
+	switch {
+	case get && body != "":
+		separator := "?"
+		if strings.Contains(request.url, "?") {
+			separator = "&"
+		}
+		request.url += separator + body
+	case body != "":
+		request.body = body
+		setDefaultHeader(request.headers, "Content-Type", "application/x-www-form-urlencoded")
+		if request.method == "" {
+			request.method = "POST"
+		}
+	}
+	if request.method == "" {
+		request.method = "GET"
+	}
+	return request, nil
+}
+
+// allIgnoredShortFlags 合并短选项中的每个字母是否都是可忽略的无参数选项
+func allIgnoredShortFlags(flags string) bool {
+	for _, flag := range flags {
+		if !curlIgnoredFlags["-"+string(flag)] {
+			return false
+		}
+	}
+	return true
+}
+
+// setDefaultHeader 请求头未设置时设置默认值
+func setDefaultHeader(headers map[string]string, name, value string) {
+	if _, ok := headers[name]; !ok {
+		headers[name] = value
+	}
+}
+
+// splitShellWords 按POSIX shell规则拆分命令行，支持单引号、双引号、$'...'、反斜杠转义与续行
+func splitShellWords(command string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+		switch {
+		case c == '\\' && i+1 < len(command):
+			i++
+			if command[i] != '\n' {
+				word.WriteByte(command[i])
+				inWord = true
+			}
+		case c == '\'':
+			end := strings.IndexByte(command[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote")
+			}
+			word.WriteString(command[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+		case c == '$' && i+1 < len(command) && command[i+1] == '\'':
+			n, err := readANSIQuoted(command[i+2:], &word)
+			if err != nil {
+				return nil, err
+			}
+			i += n + 1
+			inWord = true
+		case c == '"':
+			i++
+			for ; i < len(command) && command[i] != '"'; i++ {
+				if command[i] == '\\' && i+1 < len(command) && strings.IndexByte("$`\"\\\n", command[i+1]) >= 0 {
+					i++
+					if command[i] == '\n' {
+						continue
+					}
+				}
+				word.WriteByte(command[i])
+			}
+			if i >= len(command) {
+				return nil, fmt.Errorf("unterminated double quote")
+			}
+			inWord = true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// readANSIQuoted 读取$'...'的内容（不含起始的$'），返回消耗的字节数（含结束引号）
+func readANSIQuoted(s string, word *strings.Builder) (int, error) {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\'':
+			return i + 1, nil
+		case '\\':
+			if i+1 >= len(s) {
+				return 0, fmt.Errorf("unterminated $' quote")
+			}
+			i++
+			switch e := s[i]; e {
+			case 'n':
+				word.WriteByte('\n')
+			case 't':
+				word.WriteByte('\t')
+			case 'r':
+				word.WriteByte('\r')
+			case 'x', 'u', 'U':
+				digits := map[byte]int{'x': 2, 'u': 4, 'U': 8}[e]
+				end := i + 1
+				for end < len(s) && end-i-1 < digits && isHexDigit(s[end]) {
+					end++
+				}
+				if end == i+1 {
+					return 0, fmt.Errorf("invalid \\%c escape in $' quote", e)
+				}
+				value, _ := strconv.ParseUint(s[i+1:end], 16, 32)
+				if e == 'x' {
+					word.WriteByte(byte(value))
+				} else {
+					word.WriteRune(rune(value))
+				}
+				i = end - 1
+			default:
+				word.WriteByte(e)
+			}
+		default:
+			word.WriteByte(c)
+		}
+	}
+	return 0, fmt.Errorf("unterminated $' quote")
+}
+
+// isHexDigit 是否为十六进制数字
+func isHexDigit(c byte) bool {
+	return ('0' <= c && c <= '9') || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+}
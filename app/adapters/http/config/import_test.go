@@ -0,0 +1,131 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestImportHAR(t *testing.T) {
+	har := `{"log": {"entries": [
+		{"request": {"method": "GET", "url": "https://shop.example.com/?ref=home#top",
+			"headers": [{"name": ":authority", "value": "shop.example.com"}, {"name": "accept", "value": "text/html"},
+				{"name": "cookie", "value": "sid=1"}, {"name": "accept-encoding", "value": "gzip"}]}},
+		{"request": {"method": "GET", "url": "https://cdn.example.com/app.js", "headers": []}},
+		{"request": {"method": "POST", "url": "https://shop.example.com/api/cart",
+			"headers": [{"name": "Content-Type", "value": "application/json; charset=utf-8"}, {"name": "Content-Length", "value": "21"}],
+			"postData": {"mimeType": "application/json; charset=utf-8", "text": "{\"sku\":\"A1\",\"qty\":2}"}}},
+		{"request": {"method": "POST", "url": "https://shop.example.com/api/login",
+			"postData": {"mimeType": "application/x-www-form-urlencoded", "params": [{"name": "user", "value": "bob"}]}}},
+		{"request": {"method": "PUT", "url": "https://shop.example.com/api/note",
+			"headers": [{"name": "content-type", "value": "application/xml"}],
+			"postData": {"mimeType": "application/xml", "text": "<note/>"}}},
+		{"request": {"method": "GET", "url": "wss://shop.example.com/live"}},
+		{"request": {"method": "CONNECT", "url": "https://shop.example.com:443"}}
+	]}}`
+	path := filepath.Join(t.TempDir(), "capture.har")
+	if err := os.WriteFile(path, []byte(har), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	imported, err := ImportHAR(path, nil)
+	if err != nil {
+		t.Fatalf("ImportHAR: %v", err)
+	}
+	if imported.BaseURL != "https://shop.example.com" || imported.Skipped != 2 || len(imported.Scenario.Steps) != 5 {
+		t.Fatalf("unexpected import: base=%s skipped=%d steps=%d", imported.BaseURL, imported.Skipped, len(imported.Scenario.Steps))
+	}
+	if err := imported.Scenario.Validate(); err != nil {
+		t.Fatalf("imported scenario is invalid: %v", err)
+	}
+
+	steps := imported.Scenario.Steps
+	if steps[0].Path != "/?ref=home" || !reflect.DeepEqual(steps[0].Headers, map[string]string{"Accept": "text/html", "Cookie": "sid=1"}) {
+		t.Errorf("unexpected first step: %+v", steps[0].HttpRequestConfig)
+	}
+	if steps[1].Path != "https://cdn.example.com/app.js" {
+		t.Errorf("expected other origins to keep the full URL, got %s", steps[1].Path)
+	}
+	if steps[2].ContentType != "application/json" || !reflect.DeepEqual(steps[2].Body, map[string]interface{}{"sku": "A1", "qty": float64(2)}) ||
+		steps[2].Headers != nil {
+		t.Errorf("unexpected JSON step: %+v", steps[2].HttpRequestConfig)
+	}
+	if steps[3].ContentType != "application/x-www-form-urlencoded" || !reflect.DeepEqual(steps[3].Body, map[string]interface{}{"user": "bob"}) {
+		t.Errorf("unexpected form step: %+v", steps[3].HttpRequestConfig)
+	}
+	if steps[4].ContentType != "text/plain" || steps[4].Body != "<note/>" || steps[4].Headers["Content-Type"] != "application/xml" {
+		t.Errorf("unexpected text step: %+v", steps[4].HttpRequestConfig)
+	}
+
+	filtered, err := ImportHAR(path, regexp.MustCompile(`/api/`))
+	if err != nil || len(filtered.Scenario.Steps) != 3 || filtered.Scenario.Steps[0].Path != "/api/cart" {
+		t.Fatalf("unexpected filtered import: %+v, %v", filtered, err)
+	}
+
+	config := LoadDefaultHttpConfig()
+	filtered.Apply(config)
+	if config.Connection.BaseURL != "https://shop.example.com" || config.Benchmark.TestCase != "scenario" || len(config.Benchmark.Scenario.Steps) != 3 {
+		t.Errorf("Apply did not switch the config to the imported scenario")
+	}
+
+	if _, err := ImportHAR(path, regexp.MustCompile(`nothing`)); err == nil {
+		t.Error("expected an error when no request is imported")
+	}
+}
+
+func TestImportCurl(t *testing.T) {
+	imported, err := ImportCurl([]string{
+		`curl 'https://api.example.com/login' \
+  -H 'accept: application/json' \
+  --data-raw $'{"user":"bob","note":"it\'s é"}' \
+  -H 'Content-Type: application/json' --compressed -sS`,
+		`curl -u bob:secret -G -d page=2 --data-urlencode 'q=a b' "https://api.example.com/orders?sort=desc"`,
+		`curl -X DELETE https://api.example.com/orders/7 -A bench`,
+		`curl api.example.com/health -I`,
+	})
+	if err != nil {
+		t.Fatalf("ImportCurl: %v", err)
+	}
+	steps := imported.Scenario.Steps
+	if imported.BaseURL != "https://api.example.com" || len(steps) != 4 {
+		t.Fatalf("unexpected import: base=%s steps=%d", imported.BaseURL, len(steps))
+	}
+
+	login := steps[0]
+	if login.Method != "POST" || login.Path != "/login" || login.ContentType != "application/json" ||
+		!reflect.DeepEqual(login.Body, map[string]interface{}{"user": "bob", "note": "it's é"}) ||
+		login.Headers["Accept"] != "application/json" {
+		t.Errorf("unexpected login step: %+v", login.HttpRequestConfig)
+	}
+	orders := steps[1]
+	if orders.Method != "GET" || orders.Path != "/orders?sort=desc&page=2&q=a+b" || orders.Body != nil ||
+		orders.Headers["Authorization"] != "Basic Ym9iOnNlY3JldA==" {
+		t.Errorf("unexpected orders step: %+v", orders.HttpRequestConfig)
+	}
+	if steps[2].Method != "DELETE" || steps[2].Path != "/orders/7" || steps[2].Headers["User-Agent"] != "bench" {
+		t.Errorf("unexpected delete step: %+v", steps[2].HttpRequestConfig)
+	}
+	if steps[3].Method != "HEAD" || steps[3].Path != "http://api.example.com/health" {
+		t.Errorf("unexpected head step: %+v", steps[3].HttpRequestConfig)
+	}
+
+	form, err := ImportCurl([]string{`curl https://api.example.com/form -d a=1 -d b=2`})
+	if err != nil || form.Scenario.Steps[0].Method != "POST" ||
+		!reflect.DeepEqual(form.Scenario.Steps[0].Body, map[string]interface{}{"a": "1", "b": "2"}) {
+		t.Errorf("unexpected form import: %+v, %v", form, err)
+	}
+
+	for _, command := range []string{
+		`wget https://example.com`,
+		`curl -H 'x: y'`,
+		`curl 'https://example.com`,
+		`curl --unknown-flag https://example.com`,
+		`curl -X TRACE https://example.com`,
+	} {
+		if _, err := ImportCurl([]string{command}); err == nil {
+			t.Errorf("expected an error for %q", command)
+		}
+	}
+}
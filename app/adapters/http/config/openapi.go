@@ -0,0 +1,526 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// openAPIWeightExtension 操作上指定默认权重的扩展字段
+const openAPIWeightExtension = "x-weight"
+
+// openAPIMaxDepth 由schema生成示例时的最大嵌套深度，防止递归引用无限展开
+const openAPIMaxDepth = 6
+
+// openAPIMethods OpenAPI路径项中的操作，按固定顺序导入
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+// templateLiteralPrefix 生成JSON请求体时标记不加引号的数值/布尔占位符
+const templateLiteralPrefix = "\x00abc-literal:"
+
+// templateLiteralPattern 匹配序列化后带标记的占位符字符串
+var templateLiteralPattern = regexp.MustCompile(`"\\u0000abc-literal:(\{\{[^"}]*\}\})"`)
+
+// openAPIDocument 解析为通用结构的OpenAPI 3.x或Swagger 2.0文档
+type openAPIDocument struct {
+	root    map[string]interface{}
+	swagger bool // Swagger 2.0
+}
+
+// ImportOpenAPI 将OpenAPI 3.x或Swagger 2.0文档（JSON或YAML）中的每个操作转换为加权请求模板
+// 路径参数、必填的查询参数与请求头取文档中的示例值，没有示例时按类型生成随机值的占位符；
+// JSON请求体取示例或由schema生成。权重默认为1，可由操作的x-weight扩展或weights（operationId到权重）指定，
+// 权重为0的操作不导入；include不为空时只导入operationId或"方法 路径"匹配的操作，废弃的操作不导入
+func ImportOpenAPI(path string, include *regexp.Regexp, weights map[string]int) (*HttpImport, error) {
+	doc, err := loadOpenAPIDocument(path)
+	if err != nil {
+		return nil, err
+	}
+
+	imported := &HttpImport{Source: doc.source(path)}
+	var prefix string
+	imported.BaseURL, prefix = doc.server()
+
+	known := make(map[string]bool)
+	paths := mapField(doc.root, "paths")
+	for _, pathName := range sortedKeys(paths) {
+		item := doc.resolve(paths[pathName])
+		for _, method := range openAPIMethods {
+			operation, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name := stringField(operation, "operationId")
+			if name == "" {
+				name = strings.ToUpper(method) + " " + pathName
+			}
+			known[name] = true
+			if include != nil && !include.MatchString(name) && !include.MatchString(strings.ToUpper(method)+" "+pathName) {
+				imported.Skipped++
+				continue
+			}
+			weight := 1
+			if value, ok := operation[openAPIWeightExtension].(int); ok {
+				weight = value
+			}
+			if value, ok := weights[name]; ok {
+				weight = value
+			}
+			if weight <= 0 || operation["deprecated"] == true {
+				imported.Skipped++
+				continue
+			}
+
+			request, ok := doc.request(method, prefix+pathName, item, operation)
+			if !ok {
+				imported.Skipped++
+				continue
+			}
+			request.Name = name
+			request.Weight = weight
+			imported.Requests = append(imported.Requests, request)
+		}
+	}
+	for name := range weights {
+		if !known[name] {
+			return nil, fmt.Errorf("weight given for unknown operation %q", name)
+		}
+	}
+	if len(imported.Requests) == 0 {
+		return nil, fmt.Errorf("no operations to import in %s (%d skipped)", path, imported.Skipped)
+	}
+	return imported, nil
+}
+
+// loadOpenAPIDocument 读取并解析OpenAPI 3.x或Swagger 2.0文档（JSON或YAML）
+func loadOpenAPIDocument(path string) (*openAPIDocument, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OpenAPI document: %w", err)
+	}
+	var root map[string]interface{}
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI document %s: %w", path, err)
+	}
+
+	doc := &openAPIDocument{root: root}
+	switch {
+	case strings.HasPrefix(stringField(root, "openapi"), "3."):
+	case stringField(root, "swagger") == "2.0":
+		doc.swagger = true
+	default:
+		return nil, fmt.Errorf("%s is not an OpenAPI 3.x or Swagger 2.0 document", path)
+	}
+	return doc, nil
+}
+
+// source 文档的来源描述：文件路径，文档有标题时附带标题
+func (d *openAPIDocument) source(path string) string {
+	if title := stringField(mapField(d.root, "info"), "title"); title != "" {
+		return fmt.Sprintf("%s (%s)", path, title)
+	}
+	return path
+}
+
+// server 文档中第一个服务器的源与路径前缀；相对地址或未指定主机时源为空，沿用配置中的地址
+func (d *openAPIDocument) server() (string, string) {
+	var raw string
+	if d.swagger {
+		host := stringField(d.root, "host")
+		if host != "" {
+			scheme := "https"
+			if schemes, ok := d.root["schemes"].([]interface{}); ok && len(schemes) > 0 {
+				scheme = fmt.Sprint(schemes[0])
+			}
+			raw = scheme + "://" + host
+		}
+		raw += stringField(d.root, "basePath")
+	} else if servers, ok := d.root["servers"].([]interface{}); ok && len(servers) > 0 {
+		server, _ := servers[0].(map[string]interface{})
+		raw = stringField(server, "url")
+		// 服务器变量取默认值
+		variables := mapField(server, "variables")
+		for name := range variables {
+			raw = strings.ReplaceAll(raw, "{"+name+"}", fmt.Sprint(mapField(variables, name)["default"]))
+		}
+	}
+
+	target, err := url.Parse(raw)
+	if err != nil {
+		return "", ""
+	}
+	prefix := strings.TrimSuffix(target.Path, "/")
+	if target.Scheme != "http" && target.Scheme != "https" || target.Host == "" {
+		return "", prefix
+	}
+	return target.Scheme + "://" + target.Host, prefix
+}
+
+// request 将一个操作转换为请求模板，请求体没有可用的JSON或表单类型时返回false
+func (d *openAPIDocument) request(method, path string, item, operation map[string]interface{}) (HttpRequestConfig, bool) {
+	request := HttpRequestConfig{Method: strings.ToUpper(method)}
+	var query []string
+	var form map[string]interface{}
+
+	for _, parameter := range d.parameters(item, operation) {
+		name := stringField(parameter, "name")
+		in := stringField(parameter, "in")
+		required := parameter["required"] == true
+		switch {
+		case in == "path":
+			path = strings.ReplaceAll(path, "{"+name+"}", d.parameterValue(parameter, url.PathEscape, true))
+		case in == "query" && required:
+			query = append(query, url.QueryEscape(name)+"="+d.parameterValue(parameter, url.QueryEscape, true))
+		case in == "header" && required:
+			// 请求头不经过模板渲染
+			if request.Headers == nil {
+				request.Headers = make(map[string]string)
+			}
+			request.Headers[canonicalHeader(name)] = d.parameterValue(parameter, nil, false)
+		case in == "body":
+			request.Body, request.ContentType = d.jsonBody(d.resolve(parameter["schema"]), nil)
+		case in == "formData" && required:
+			if form == nil {
+				form = make(map[string]interface{})
+			}
+			form[name] = d.sample(parameter, false, 0)
+		}
+	}
+	if form != nil {
+		request.Body, request.ContentType = form, "application/x-www-form-urlencoded"
+	}
+	if !d.swagger && operation["requestBody"] != nil {
+		requestBody := d.resolve(operation["requestBody"])
+		body, contentType, ok := d.requestBody(mapField(requestBody, "content"))
+		if !ok {
+			if requestBody["required"] == true {
+				return request, false
+			}
+		} else {
+			request.Body, request.ContentType = body, contentType
+		}
+	}
+
+	request.Path = path
+	if len(query) > 0 {
+		request.Path += "?" + strings.Join(query, "&")
+	}
+	return request, true
+}
+
+// parameters 合并路径项与操作的参数，操作中同名同位置的参数覆盖路径项中的
+func (d *openAPIDocument) parameters(item, operation map[string]interface{}) []map[string]interface{} {
+	var merged []map[string]interface{}
+	index := make(map[string]int)
+	for _, source := range []interface{}{item["parameters"], operation["parameters"]} {
+		list, _ := source.([]interface{})
+		for _, raw := range list {
+			parameter := d.resolve(raw)
+			key := stringField(parameter, "in") + ":" + stringField(parameter, "name")
+			if i, ok := index[key]; ok {
+				merged[i] = parameter
+				continue
+			}
+			index[key] = len(merged)
+			merged = append(merged, parameter)
+		}
+	}
+	return merged
+}
+
+// parameterValue 参数的取值：文档中的示例按escape转义，否则templated为true时为随机值占位符，为false时为固定值
+func (d *openAPIDocument) parameterValue(parameter map[string]interface{}, escape func(string) string, templated bool) string {
+	schema := parameter
+	if parameter["schema"] != nil {
+		schema = d.resolve(parameter["schema"])
+	}
+	value, ok := exampleOf(parameter)
+	if !ok {
+		value, ok = exampleOf(schema)
+	}
+	if !ok && templated {
+		return templateFor(schema, true)
+	}
+	if !ok {
+		value = fixedValue(schema)
+	}
+	text := fmt.Sprint(value)
+	if escape != nil {
+		text = escape(text)
+	}
+	return text
+}
+
+// requestBody 从OpenAPI 3请求体的content中选取JSON或表单类型，返回请求体与内容类型
+func (d *openAPIDocument) requestBody(content map[string]interface{}) (interface{}, string, bool) {
+	var form map[string]interface{}
+	for _, mediaType := range sortedKeys(content) {
+		parsed, _, _ := mime.ParseMediaType(mediaType)
+		media := mapField(content, mediaType)
+		switch {
+		case parsed == "application/json" || strings.HasSuffix(parsed, "+json"):
+			body, contentType := d.jsonBody(d.resolve(media["schema"]), media)
+			return body, contentType, true
+		case parsed == "application/x-www-form-urlencoded" && form == nil:
+			form = media
+		}
+	}
+	if form == nil {
+		return nil, "", false
+	}
+	value, ok := d.mediaExample(form)
+	if !ok {
+		value = d.sample(d.resolve(form["schema"]), false, 0)
+	}
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, "", false
+	}
+	return fields, "application/x-www-form-urlencoded", true
+}
+
+// jsonBody 生成JSON请求体：优先取媒体类型的示例，否则由schema生成
+// 含随机值占位符时返回JSON模板文本，由weighted测试按任务编号渲染，否则返回解码后的值
+func (d *openAPIDocument) jsonBody(schema, media map[string]interface{}) (interface{}, string) {
+	if value, ok := d.mediaExample(media); ok {
+		return value, "application/json"
+	}
+	value := d.sample(schema, true, 0)
+	data, err := json.Marshal(value)
+	if err != nil || !strings.Contains(string(data), "{{") {
+		return value, "application/json"
+	}
+	return templateLiteralPattern.ReplaceAllString(string(data), "$1"), "application/json"
+}
+
+// mediaExample 媒体类型对象中的example或第一个examples
+func (d *openAPIDocument) mediaExample(media map[string]interface{}) (interface{}, bool) {
+	if value, ok := media["example"]; ok {
+		return value, true
+	}
+	examples := mapField(media, "examples")
+	for _, name := range sortedKeys(examples) {
+		if value, ok := d.resolve(examples[name])["value"]; ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// sample 由schema生成示例值；templated为true时没有示例的字段生成随机值占位符，否则生成固定值
+func (d *openAPIDocument) sample(schema map[string]interface{}, templated bool, depth int) interface{} {
+	if schema == nil || depth > openAPIMaxDepth {
+		return nil
+	}
+	if value, ok := exampleOf(schema); ok {
+		return value
+	}
+	if all, ok := schema["allOf"].([]interface{}); ok {
+		merged := make(map[string]interface{})
+		for _, part := range all {
+			if object, ok := d.sample(d.resolve(part), templated, depth+1).(map[string]interface{}); ok {
+				for key, value := range object {
+					merged[key] = value
+				}
+			}
+		}
+		return merged
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if choices, ok := schema[key].([]interface{}); ok && len(choices) > 0 {
+			return d.sample(d.resolve(choices[0]), templated, depth+1)
+		}
+	}
+
+	switch schemaType(schema) {
+	case "object":
+		object := make(map[string]interface{})
+		properties := mapField(schema, "properties")
+		for _, name := range sortedKeys(properties) {
+			property := d.resolve(properties[name])
+			if property["readOnly"] == true {
+				continue
+			}
+			if value := d.sample(property, templated, depth+1); value != nil {
+				object[name] = value
+			}
+		}
+		return object
+	case "array":
+		item := d.sample(d.resolve(schema["items"]), templated, depth+1)
+		if item == nil {
+			return []interface{}{}
+		}
+		return []interface{}{item}
+	}
+	if !templated {
+		return fixedValue(schema)
+	}
+	placeholder := templateFor(schema, false)
+	if t := schemaType(schema); t == "integer" || t == "number" || t == "boolean" {
+		return templateLiteralPrefix + placeholder
+	}
+	return placeholder
+}
+
+// exampleOf schema或参数的example、default或第一个枚举值
+func exampleOf(schema map[string]interface{}) (interface{}, bool) {
+	for _, key := range []string{"example", "default", "x-example"} {
+		if value, ok := schema[key]; ok {
+			return value, true
+		}
+	}
+	if values, ok := schema["enum"].([]interface{}); ok && len(values) > 0 {
+		return values[0], true
+	}
+	return nil, false
+}
+
+// templateFor 按类型与格式生成随机值占位符；inURL为true时字符串不包含需要转义的字符
+func templateFor(schema map[string]interface{}, inURL bool) string {
+	switch schemaType(schema) {
+	case "integer":
+		low, high := numberField(schema, "minimum", 1), numberField(schema, "maximum", 1000)
+		return fmt.Sprintf("{{randInt %d %d}}", int64(low), int64(high))
+	case "number":
+		low, high := numberField(schema, "minimum", 0), numberField(schema, "maximum", 100)
+		return fmt.Sprintf("{{randFloat %s %s}}", strconv.FormatFloat(low, 'f', -1, 64), strconv.FormatFloat(high, 'f', -1, 64))
+	case "boolean":
+		return "{{pick true false}}"
+	}
+	switch stringField(schema, "format") {
+	case "uuid":
+		return "{{uuid}}"
+	case "email":
+		if !inURL {
+			return "{{email}}"
+		}
+	case "date-time":
+		if !inURL {
+			return "{{timestamp rfc3339}}"
+		}
+	}
+	length := 8
+	if n := numberField(schema, "minLength", 0); n > float64(length) {
+		length = int(n)
+	}
+	if n := numberField(schema, "maxLength", 0); n > 0 && n < float64(length) {
+		length = int(n)
+	}
+	return fmt.Sprintf("{{randString %d}}", length)
+}
+
+// fixedValue 按类型与格式生成固定示例值（表单请求体不经过模板渲染）
+func fixedValue(schema map[string]interface{}) interface{} {
+	switch schemaType(schema) {
+	case "integer":
+		return int64(numberField(schema, "minimum", 1))
+	case "number":
+		return numberField(schema, "minimum", 1)
+	case "boolean":
+		return true
+	}
+	switch stringField(schema, "format") {
+	case "uuid":
+		return "00000000-0000-4000-8000-000000000000"
+	case "email":
+		return "user@example.com"
+	case "date":
+		return "2024-01-01"
+	case "date-time":
+		return "2024-01-01T00:00:00Z"
+	}
+	return "string"
+}
+
+// schemaType schema的类型，未指定时按properties与items推断，默认为字符串
+func schemaType(schema map[string]interface{}) string {
+	switch value := schema["type"].(type) {
+	case string:
+		return value
+	case []interface{}:
+		// OpenAPI 3.1的类型数组，取第一个非null类型
+		for _, t := range value {
+			if t != "null" {
+				return fmt.Sprint(t)
+			}
+		}
+	}
+	switch {
+	case schema["properties"] != nil:
+		return "object"
+	case schema["items"] != nil:
+		return "array"
+	}
+	return "string"
+}
+
+// resolve 解析文档内的$ref引用（#/components/...、#/definitions/...），非对象时返回nil
+func (d *openAPIDocument) resolve(node interface{}) map[string]interface{} {
+	for depth := 0; depth <= openAPIMaxDepth; depth++ {
+		object, ok := node.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		ref, ok := object["$ref"].(string)
+		if !ok {
+			return object
+		}
+		pointer, ok := strings.CutPrefix(ref, "#/")
+		if !ok {
+			return nil
+		}
+		node = d.root
+		for _, token := range strings.Split(pointer, "/") {
+			token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+			node = mapField(asMap(node), token)
+		}
+	}
+	return nil
+}
+
+// asMap 将通用值视为对象，非对象时返回nil
+func asMap(node interface{}) map[string]interface{} {
+	object, _ := node.(map[string]interface{})
+	return object
+}
+
+// mapField 对象中的子对象，不存在或不是对象时返回nil
+func mapField(object map[string]interface{}, key string) map[string]interface{} {
+	return asMap(object[key])
+}
+
+// stringField 对象中的字符串字段
+func stringField(object map[string]interface{}, key string) string {
+	value, _ := object[key].(string)
+	return value
+}
+
+// numberField 对象中的数值字段，不存在时返回fallback
+func numberField(object map[string]interface{}, key string, fallback float64) float64 {
+	switch value := object[key].(type) {
+	case int:
+		return float64(value)
+	case float64:
+		return value
+	}
+	return fallback
+}
+
+// sortedKeys 对象的键按字典序排列，使导入结果稳定
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
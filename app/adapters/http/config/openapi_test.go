@@ -0,0 +1,195 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"abc-runner/app/core/utils"
+)
+
+const petstoreOpenAPI = `
+openapi: 3.0.3
+info: {title: Petstore}
+servers:
+  - url: https://{env}.example.com/v1/
+    variables:
+      env: {default: api}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      x-weight: 8
+      parameters:
+        - {name: limit, in: query, required: true, schema: {type: integer, maximum: 50}}
+        - {name: cursor, in: query, schema: {type: string}}
+    post:
+      operationId: createPet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/NewPet'}
+  /pets/{petId}:
+    parameters:
+      - {name: petId, in: path, required: true, schema: {type: string, format: uuid}}
+    get:
+      operationId: showPet
+      parameters:
+        - {name: X-Tenant, in: header, required: true, schema: {type: string, example: acme}}
+    put:
+      operationId: updatePet
+      requestBody:
+        content:
+          application/json:
+            examples:
+              rex: {value: {name: Rex, tag: dog}}
+    delete:
+      operationId: deletePet
+      deprecated: true
+  /pets/{petId}/photo:
+    post:
+      operationId: uploadPhoto
+      parameters:
+        - {name: petId, in: path, required: true, schema: {type: integer}, example: 7}
+      requestBody:
+        required: true
+        content:
+          multipart/form-data:
+            schema: {type: object}
+components:
+  schemas:
+    NewPet:
+      allOf:
+        - $ref: '#/components/schemas/Named'
+        - type: object
+          properties:
+            id: {type: integer, readOnly: true}
+            age: {type: integer, minimum: 1, maximum: 20}
+            vaccinated: {type: boolean}
+            tags: {type: array, items: {type: string, enum: [cute]}}
+    Named:
+      type: object
+      properties:
+        name: {type: string, maxLength: 6}
+`
+
+func TestImportOpenAPI(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "petstore.yaml")
+	if err := os.WriteFile(path, []byte(petstoreOpenAPI), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	imported, err := ImportOpenAPI(path, nil, map[string]int{"showPet": 3})
+	if err != nil {
+		t.Fatalf("ImportOpenAPI: %v", err)
+	}
+	if imported.BaseURL != "https://api.example.com" || imported.Skipped != 2 || imported.Len() != 4 {
+		t.Fatalf("unexpected import: base=%s skipped=%d requests=%+v", imported.BaseURL, imported.Skipped, imported.Requests)
+	}
+
+	requests := make(map[string]HttpRequestConfig)
+	for _, request := range imported.Requests {
+		requests[request.Name] = request
+	}
+	list := requests["listPets"]
+	if list.Method != "GET" || list.Path != "/v1/pets?limit={{randInt 1 50}}" || list.Weight != 8 {
+		t.Errorf("unexpected listPets: %+v", list)
+	}
+	show := requests["showPet"]
+	if show.Path != "/v1/pets/{{uuid}}" || show.Weight != 3 || show.Headers["X-Tenant"] != "acme" {
+		t.Errorf("unexpected showPet: %+v", show)
+	}
+	update := requests["updatePet"]
+	if update.Method != "PUT" || !reflect.DeepEqual(update.Body, map[string]interface{}{"name": "Rex", "tag": "dog"}) {
+		t.Errorf("unexpected updatePet: %+v", update)
+	}
+
+	// 由schema生成的请求体是JSON模板，渲染结果为合法JSON，只读字段不生成
+	create := requests["createPet"]
+	text, ok := create.Body.(string)
+	if create.ContentType != "application/json" || !ok {
+		t.Fatalf("expected a JSON template body for createPet, got %#v", create.Body)
+	}
+	template, err := utils.ParsePayloadTemplate(text, nil)
+	if err != nil {
+		t.Fatalf("invalid body template %s: %v", text, err)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(template.Render(1)), &body); err != nil {
+		t.Fatalf("rendered body is not JSON: %v", err)
+	}
+	if _, ok := body["id"]; ok || len(body["name"].(string)) != 6 || body["age"].(float64) < 1 ||
+		!reflect.DeepEqual(body["tags"], []interface{}{"cute"}) {
+		t.Errorf("unexpected rendered body: %v", body)
+	}
+	if _, ok := body["vaccinated"].(bool); !ok {
+		t.Errorf("expected a boolean vaccinated field, got %v", body["vaccinated"])
+	}
+
+	config := LoadDefaultHttpConfig()
+	imported.Apply(config)
+	if config.Benchmark.TestCase != "weighted" || len(config.Requests) != 4 || config.Connection.BaseURL != "https://api.example.com" {
+		t.Errorf("Apply did not switch the config to the imported requests")
+	}
+	if err := config.Validate(); err != nil {
+		t.Errorf("imported config is invalid: %v", err)
+	}
+
+	filtered, err := ImportOpenAPI(path, regexp.MustCompile(`^GET `), nil)
+	if err != nil || filtered.Len() != 2 {
+		t.Errorf("unexpected filtered import: %+v, %v", filtered, err)
+	}
+	if _, err := ImportOpenAPI(path, nil, map[string]int{"nothing": 1}); err == nil {
+		t.Error("expected an error for a weight on an unknown operation")
+	}
+}
+
+func TestImportOpenAPI_Swagger2(t *testing.T) {
+	spec := `{"swagger": "2.0", "basePath": "/api", "paths": {
+		"/login": {"post": {"operationId": "login", "parameters": [
+			{"name": "user", "in": "formData", "required": true, "type": "string"},
+			{"name": "remember", "in": "formData", "type": "boolean"}]}},
+		"/orders": {"post": {"parameters": [
+			{"name": "order", "in": "body", "schema": {"$ref": "#/definitions/Order"}}]}}},
+		"definitions": {"Order": {"type": "object", "properties": {
+			"sku": {"type": "string", "example": "A1"}, "qty": {"type": "integer", "default": 2}}}}}`
+	path := filepath.Join(t.TempDir(), "swagger.json")
+	if err := os.WriteFile(path, []byte(spec), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	imported, err := ImportOpenAPI(path, nil, nil)
+	if err != nil {
+		t.Fatalf("ImportOpenAPI: %v", err)
+	}
+	if imported.BaseURL != "" || imported.Len() != 2 {
+		t.Fatalf("unexpected import: %+v", imported)
+	}
+	login, order := imported.Requests[0], imported.Requests[1]
+	if login.Path != "/api/login" || login.ContentType != "application/x-www-form-urlencoded" ||
+		!reflect.DeepEqual(login.Body, map[string]interface{}{"user": "string"}) {
+		t.Errorf("unexpected login: %+v", login)
+	}
+	if order.Name != "POST /orders" || order.Path != "/api/orders" ||
+		!reflect.DeepEqual(order.Body, map[string]interface{}{"sku": "A1", "qty": 2}) {
+		t.Errorf("unexpected order: %+v", order)
+	}
+
+	config := LoadDefaultHttpConfig()
+	imported.Apply(config)
+	if config.Connection.BaseURL != "http://localhost:8080" {
+		t.Errorf("expected a document without host to keep the configured URL, got %s", config.Connection.BaseURL)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"info": {"title": "not a spec"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ImportOpenAPI(path, nil, nil); err == nil || !strings.Contains(err.Error(), "not an OpenAPI") {
+		t.Errorf("expected a document type error, got %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"time"
+
+	"abc-runner/app/core/config/yamlfile"
+
+	"gopkg.in/yaml.v3"
+)
+
+// HttpWorkload 可回放的工作负载文件，由代理模式根据观察到的真实流量导出
+// http部分与HTTP配置文件格式兼容，traffic部分记录观察到的流量特征供参考，回放时不使用
+type HttpWorkload struct {
+	HTTP    HttpWorkloadConfig `yaml:"http"`
+	Traffic HttpTrafficSummary `yaml:"traffic"`
+}
+
+// HttpWorkloadConfig 工作负载的回放配置
+type HttpWorkloadConfig struct {
+	Connection struct {
+		BaseURL string `yaml:"base_url"`
+	} `yaml:"connection"`
+	Requests  []HttpWorkloadRequest `yaml:"requests"`
+	Benchmark struct {
+		TestCase  string        `yaml:"test_case"`
+		Total     int           `yaml:"total"`     // 观察到的请求数
+		Parallels int           `yaml:"parallels"` // 观察到的最大并发请求数
+		RampUp    time.Duration `yaml:"ramp_up"`   // 观察时长，回放时请求均匀分布在该时间内，重现观察到的RPS
+	} `yaml:"benchmark"`
+}
+
+// HttpWorkloadRequest 工作负载中的一个请求，权重为观察到的次数
+type HttpWorkloadRequest struct {
+	Method      string            `yaml:"method"`
+	Path        string            `yaml:"path"` // 反向代理为路径及查询参数，正向代理为完整URL
+	Headers     map[string]string `yaml:"headers,omitempty"`
+	ContentType string            `yaml:"content_type,omitempty"`
+	Body        interface{}       `yaml:"body,omitempty"` // 首个请求体样本（JSON、表单或文本）
+	Weight      int               `yaml:"weight"`
+}
+
+// HttpTrafficSummary 观察到的流量特征
+type HttpTrafficSummary struct {
+	RecordedAt      time.Time             `yaml:"recorded_at"`
+	Mode            string                `yaml:"mode"` // reverse或forward
+	Duration        time.Duration         `yaml:"duration"`
+	Requests        int64                 `yaml:"requests"`
+	RPS             float64               `yaml:"rps"`
+	PeakConcurrency int64                 `yaml:"peak_concurrency"`
+	DroppedRequests int64                 `yaml:"dropped_requests,omitempty"` // 超出请求种类上限、未写入requests的请求数
+	Endpoints       []HttpTrafficEndpoint `yaml:"endpoints"`
+	Profile         *HttpWorkloadProfile  `yaml:"profile,omitempty"`
+}
+
+// HttpTrafficEndpoint 按接口（方法与归一化路径）汇总的流量特征
+type HttpTrafficEndpoint struct {
+	Operation        string        `yaml:"operation"`
+	Requests         int64         `yaml:"requests"`
+	Errors           int64         `yaml:"errors"`
+	AvgRequestBytes  int64         `yaml:"avg_request_bytes"`
+	AvgResponseBytes int64         `yaml:"avg_response_bytes"`
+	AvgLatency       time.Duration `yaml:"avg_latency"`
+	MaxLatency       time.Duration `yaml:"max_latency"`
+}
+
+// HttpWorkloadProfile 工作负载特征：操作构成、URL热度曲线、负载大小与到达间隔分布
+type HttpWorkloadProfile struct {
+	Requests     int64                   `yaml:"requests" json:"requests"`
+	Operations   []HttpRequestShare      `yaml:"operations" json:"operations"` // 按操作（方法与归一化路径）的请求构成
+	Popularity   HttpPopularityProfile   `yaml:"popularity" json:"popularity"`
+	RequestSize  HttpSizeDistribution    `yaml:"request_size" json:"request_size"`
+	ResponseSize HttpSizeDistribution    `yaml:"response_size" json:"response_size"`
+	InterArrival HttpInterArrivalProfile `yaml:"inter_arrival" json:"inter_arrival"`
+}
+
+// HttpRequestShare 一个操作或URL的请求数及占比
+type HttpRequestShare struct {
+	Name     string  `yaml:"name" json:"name"`
+	Requests int64   `yaml:"requests" json:"requests"`
+	Percent  float64 `yaml:"percent" json:"percent"`
+}
+
+// HttpPopularityProfile URL热度：最热门的URL及热度集中程度
+type HttpPopularityProfile struct {
+	DistinctURLs int                   `yaml:"distinct_urls" json:"distinct_urls"`
+	Top          []HttpRequestShare    `yaml:"top" json:"top"`
+	Curve        []HttpPopularityPoint `yaml:"curve" json:"curve"`
+	ZipfExponent float64               `yaml:"zipf_exponent" json:"zipf_exponent"` // 排名-请求数对数拟合的Zipf指数，越大越集中于少数URL
+}
+
+// HttpPopularityPoint 热度曲线上的一点：最热门的前URLPercent%的URL占全部请求的RequestPercent%
+type HttpPopularityPoint struct {
+	URLPercent     float64 `yaml:"url_percent" json:"url_percent"`
+	RequestPercent float64 `yaml:"request_percent" json:"request_percent"`
+}
+
+// HttpSizeDistribution 负载大小分布（字节）
+type HttpSizeDistribution struct {
+	Mean      int64            `yaml:"mean" json:"mean"`
+	P50       int64            `yaml:"p50" json:"p50"`
+	P90       int64            `yaml:"p90" json:"p90"`
+	P99       int64            `yaml:"p99" json:"p99"`
+	Max       int64            `yaml:"max" json:"max"`
+	Histogram []HttpSizeBucket `yaml:"histogram" json:"histogram"`
+}
+
+// HttpSizeBucket 大小直方图的一个区间
+type HttpSizeBucket struct {
+	Range    string  `yaml:"range" json:"range"`
+	Requests int64   `yaml:"requests" json:"requests"`
+	Percent  float64 `yaml:"percent" json:"percent"`
+}
+
+// HttpInterArrivalProfile 请求到达间隔分布
+type HttpInterArrivalProfile struct {
+	Mean time.Duration `yaml:"mean" json:"mean"`
+	P50  time.Duration `yaml:"p50" json:"p50"`
+	P90  time.Duration `yaml:"p90" json:"p90"`
+	P99  time.Duration `yaml:"p99" json:"p99"`
+	Max  time.Duration `yaml:"max" json:"max"`
+	CV   float64       `yaml:"cv" json:"cv"` // 变异系数：约为1时接近泊松到达，小于1更均匀，大于1更突发
+}
+
+// SaveHttpWorkload 将工作负载写入YAML文件
+func SaveHttpWorkload(path string, workload *HttpWorkload) error {
+	var buffer bytes.Buffer
+	buffer.WriteString("# abc-runner HTTP workload recorded in proxy mode, replay with:\n")
+	buffer.WriteString("#   abc-runner http --workload " + path + "\n")
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(workload); err != nil {
+		return fmt.Errorf("failed to encode workload: %w", err)
+	}
+	if err := os.WriteFile(path, buffer.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write workload %s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadHttpWorkload 读取工作负载文件
+func LoadHttpWorkload(path string) (*HttpWorkload, error) {
+	data, err := yamlfile.Read(path)
+	if err != nil {
+		return nil, err
+	}
+	workload := &HttpWorkload{}
+	if err := yaml.Unmarshal(data, workload); err != nil {
+		return nil, fmt.Errorf("failed to parse workload %s: %w", path, err)
+	}
+	if len(workload.HTTP.Requests) == 0 {
+		return nil, fmt.Errorf("workload %s contains no requests", path)
+	}
+	return workload, nil
+}
+
+// Apply 将工作负载应用到配置：请求列表按权重回放，总数、并发与时长取观察值
+func (w *HttpWorkload) Apply(c *HttpAdapterConfig) {
+	if w.HTTP.Connection.BaseURL != "" {
+		c.Connection.BaseURL = w.HTTP.Connection.BaseURL
+	}
+	c.Requests = make([]HttpRequestConfig, 0, len(w.HTTP.Requests))
+	for _, req := range w.HTTP.Requests {
+		c.Requests = append(c.Requests, HttpRequestConfig{
+			Method:      req.Method,
+			Path:        req.Path,
+			Headers:     req.Headers,
+			Body:        req.Body,
+			ContentType: req.ContentType,
+			Weight:      req.Weight,
+		})
+	}
+	c.Benchmark.TestCase = "replay"
+	if w.HTTP.Benchmark.Total > 0 {
+		c.Benchmark.Total = w.HTTP.Benchmark.Total
+	}
+	if w.HTTP.Benchmark.Parallels > 0 {
+		c.Benchmark.Parallels = w.HTTP.Benchmark.Parallels
+	}
+	c.Benchmark.RampUp = w.HTTP.Benchmark.RampUp
+}
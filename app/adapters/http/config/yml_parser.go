@@ -1,6 +1,9 @@
 package config
 
 import (
+	"fmt"
+
+	"abc-runner/app/core/config/yamlfile"
 	"abc-runner/app/core/interfaces"
 
 	"gopkg.in/yaml.v3"
@@ -37,3 +40,29 @@ func (h *HttpYAMLParser) Parse(data []byte) (interfaces.Config, error) {
 
 	return configWrapper.HTTP, nil
 }
+
+// LoadHttpConfigFile 读取HTTP配置文件（http:段或整个文件），文件中未设置的字段保留默认值
+func LoadHttpConfigFile(path string) (*HttpAdapterConfig, error) {
+	data, err := yamlfile.Read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var configWrapper struct {
+		HTTP yaml.Node `yaml:"http"`
+	}
+	if err := yaml.Unmarshal(data, &configWrapper); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	config := LoadDefaultHttpConfig()
+	if configWrapper.HTTP.Kind != 0 {
+		err = configWrapper.HTTP.Decode(config)
+	} else {
+		err = yaml.Unmarshal(data, config)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	return config, nil
+}
@@ -8,13 +8,17 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	httpConfig "abc-runner/app/adapters/http/config"
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/metrics"
 )
 
 // HttpClient HTTP客户端封装
@@ -22,6 +26,9 @@ type HttpClient struct {
 	client *http.Client
 	config *httpConfig.HttpAdapterConfig
 	pool   *HTTPConnectionPool
+	tokens *TokenSource // OAuth2令牌来源（auth.type: oauth2）
+
+	authWait atomic.Int64 // 累计等待OAuth2令牌端点的纳秒数
 }
 
 // NewHttpClient 创建HTTP客户端
@@ -33,32 +40,67 @@ func NewHttpClient(client *http.Client, config *httpConfig.HttpAdapterConfig, po
 	}
 }
 
+// SetTokenSource 设置OAuth2令牌来源
+func (c *HttpClient) SetTokenSource(tokens *TokenSource) {
+	c.tokens = tokens
+}
+
+// AuthWait 该客户端发出的请求累计等待OAuth2令牌端点的时间
+func (c *HttpClient) AuthWait() time.Duration {
+	return time.Duration(c.authWait.Load())
+}
+
 // ExecuteRequest 执行HTTP请求
+// OAuth2认证时请求返回401会刷新令牌并重试一次，等待令牌端点的时间记入响应的AuthDuration
 func (c *HttpClient) ExecuteRequest(ctx context.Context, reqConfig httpConfig.HttpRequestConfig) (*HttpResponse, error) {
+	response, token, err := c.executeOnce(ctx, reqConfig)
+	if err != nil || c.tokens == nil || response.StatusCode != http.StatusUnauthorized {
+		return response, err
+	}
+
+	c.tokens.Invalidate(token)
+	c.tokens.RecordRetry()
+	authDuration := response.AuthDuration
+	response, _, err = c.executeOnce(ctx, reqConfig)
+	if response != nil {
+		response.AuthDuration += authDuration
+	}
+	return response, err
+}
+
+// executeOnce 发送一次HTTP请求，返回响应与使用的OAuth2令牌
+func (c *HttpClient) executeOnce(ctx context.Context, reqConfig httpConfig.HttpRequestConfig) (*HttpResponse, string, error) {
 	// 构建完整URL
 	fullURL, err := c.buildURL(reqConfig.Path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build URL: %w", err)
+		return nil, "", fmt.Errorf("failed to build URL: %w", err)
 	}
 
 	// 准备请求体
 	body, contentType, err := c.prepareRequestBody(reqConfig)
 	if err != nil {
-		return nil, fmt.Errorf("failed to prepare request body: %w", err)
+		return nil, "", fmt.Errorf("failed to prepare request body: %w", err)
 	}
 
-	// 创建HTTP请求
-	req, err := http.NewRequestWithContext(ctx, reqConfig.Method, fullURL, body)
+	// 创建HTTP请求，按阶段记录耗时
+	trace := &phaseTrace{}
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace.clientTrace()), reqConfig.Method, fullURL, body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// 设置请求头
 	c.setRequestHeaders(req, reqConfig, contentType)
 
 	// 设置认证
-	if err := c.setAuthentication(req); err != nil {
-		return nil, fmt.Errorf("failed to set authentication: %w", err)
+	token, authDuration, err := c.setAuthentication(req)
+	c.authWait.Add(int64(authDuration))
+	if err != nil {
+		return &HttpResponse{
+			Duration:     authDuration,
+			AuthDuration: authDuration,
+			Error:        err,
+		}, token, fmt.Errorf("failed to set authentication: %w", err)
 	}
 
 	// 执行请求
@@ -68,10 +110,11 @@ func (c *HttpClient) ExecuteRequest(ctx context.Context, reqConfig httpConfig.Ht
 
 	if err != nil {
 		return &HttpResponse{
-			StatusCode: 0,
-			Duration:   duration,
-			Error:      err,
-		}, err
+			StatusCode:   0,
+			Duration:     duration,
+			AuthDuration: authDuration,
+			Error:        err,
+		}, token, err
 	}
 
 	// 读取响应体
@@ -79,10 +122,11 @@ func (c *HttpClient) ExecuteRequest(ctx context.Context, reqConfig httpConfig.Ht
 	if err != nil {
 		resp.Body.Close()
 		return &HttpResponse{
-			StatusCode: resp.StatusCode,
-			Duration:   duration,
-			Error:      err,
-		}, err
+			StatusCode:   resp.StatusCode,
+			Duration:     duration,
+			AuthDuration: authDuration,
+			Error:        err,
+		}, token, err
 	}
 
 	// 确保响应体被关闭
@@ -94,8 +138,54 @@ func (c *HttpClient) ExecuteRequest(ctx context.Context, reqConfig httpConfig.Ht
 		Body:          respBody,
 		ContentLength: resp.ContentLength,
 		Duration:      duration,
+		AuthDuration:  authDuration,
+		Phases:        trace.timings(time.Now()),
+		RequestSize:   max(req.ContentLength, 0),
 		Success:       c.isSuccessStatusCode(resp.StatusCode),
-	}, nil
+	}, token, nil
+}
+
+// OpenStream 发送流式请求，收到响应头后即返回，响应体由调用方持续读取并关闭
+// 流的生命周期由ctx控制，不受连接配置中请求超时的限制；firstByte为收到响应第一个字节的时间
+func (c *HttpClient) OpenStream(ctx context.Context, reqConfig httpConfig.HttpRequestConfig) (*http.Response, time.Time, error) {
+	fullURL, err := c.buildURL(reqConfig.Path)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to build URL: %w", err)
+	}
+
+	body, contentType, err := c.prepareRequestBody(reqConfig)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to prepare request body: %w", err)
+	}
+
+	var firstByte time.Time
+	trace := &httptrace.ClientTrace{
+		GotFirstResponseByte: func() { firstByte = time.Now() },
+	}
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace), reqConfig.Method, fullURL, body)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setRequestHeaders(req, reqConfig, contentType)
+
+	_, authDuration, err := c.setAuthentication(req)
+	c.authWait.Add(int64(authDuration))
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to set authentication: %w", err)
+	}
+
+	// 请求超时涵盖读取响应体的时间，流式请求使用不设超时的客户端副本（共享连接池）
+	client := *c.client
+	client.Timeout = 0
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if firstByte.IsZero() {
+		firstByte = time.Now()
+	}
+	return resp, firstByte, nil
 }
 
 // buildURL 构建完整URL
@@ -265,28 +355,47 @@ func (c *HttpClient) setRequestHeaders(req *http.Request, reqConfig httpConfig.H
 	for key, value := range reqConfig.Headers {
 		req.Header.Set(key, value)
 	}
+
+	// 设置请求ID，便于与服务端日志关联
+	if requestID, ok := execution.RequestIDFromContext(req.Context()); ok {
+		req.Header.Set(execution.RequestIDHeader, requestID)
+	}
+	if trace, ok := execution.TraceContextFromContext(req.Context()); ok {
+		req.Header.Set(execution.TraceparentHeader, trace.Traceparent())
+	}
+	// 拦截器附加的头部（如认证令牌）覆盖配置中的同名头部
+	for _, header := range execution.HeadersFromContext(req.Context()) {
+		req.Header.Set(header[0], header[1])
+	}
 }
 
-// setAuthentication 设置认证
-func (c *HttpClient) setAuthentication(req *http.Request) error {
+// setAuthentication 设置认证，OAuth2认证时返回使用的令牌与等待令牌端点的时间
+func (c *HttpClient) setAuthentication(req *http.Request) (string, time.Duration, error) {
 	switch c.config.Auth.Type {
 	case "none":
 		// 无需认证
-		return nil
+		return "", 0, nil
 	case "basic":
 		req.SetBasicAuth(c.config.Auth.Username, c.config.Auth.Password)
-		return nil
+		return "", 0, nil
 	case "bearer":
 		req.Header.Set("Authorization", "Bearer "+c.config.Auth.Token)
-		return nil
+		return "", 0, nil
 	case "oauth2":
-		// TODO: 实现OAuth2认证
-		return fmt.Errorf("OAuth2 authentication not implemented yet")
+		if c.tokens == nil {
+			return "", 0, fmt.Errorf("OAuth2 token source not initialized")
+		}
+		token, wait, err := c.tokens.Token(req.Context())
+		if err != nil {
+			return "", wait, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		return token, wait, nil
 	case "mutual_tls":
 		// TLS认证在传输层处理
-		return nil
+		return "", 0, nil
 	default:
-		return fmt.Errorf("unsupported authentication type: %s", c.config.Auth.Type)
+		return "", 0, fmt.Errorf("unsupported authentication type: %s", c.config.Auth.Type)
 	}
 }
 
@@ -316,6 +425,9 @@ type HttpResponse struct {
 	Body          []byte
 	ContentLength int64
 	Duration      time.Duration
+	AuthDuration  time.Duration        // 等待OAuth2令牌端点的时间，不属于被测请求的耗时
+	Phases        metrics.PhaseTimings // 各阶段耗时（DNS、建连、TLS、写请求、首字节、传输），仅完整读取响应时设置
+	RequestSize   int64                // 发送的请求体字节数
 	Success       bool
 	Error         error
 }
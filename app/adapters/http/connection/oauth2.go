@@ -0,0 +1,289 @@
+package connection
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	httpConfig "abc-runner/app/adapters/http/config"
+)
+
+// defaultRefreshBefore 默认在令牌过期前多久刷新
+const defaultRefreshBefore = 30 * time.Second
+
+// ErrTokenFetch 运行开始前未能获取OAuth2令牌
+var ErrTokenFetch = errors.New("failed to fetch OAuth2 token")
+
+// 令牌刷新原因
+const (
+	refreshReasonInitial      = "initial"
+	refreshReasonExpiry       = "expiry"
+	refreshReasonUnauthorized = "unauthorized"
+)
+
+// OAuth2Stats OAuth2令牌获取统计，令牌端点的请求不计入测试指标，仅在此统计
+type OAuth2Stats struct {
+	Grant                 string        `json:"grant"`
+	Fetches               int64         `json:"fetches"`                // 令牌端点请求次数（含失败）
+	Refreshes             int64         `json:"refreshes"`              // 运行中成功的刷新次数（不含首次获取）
+	ExpiryRefreshes       int64         `json:"expiry_refreshes"`       // 因临近过期的刷新
+	UnauthorizedRefreshes int64         `json:"unauthorized_refreshes"` // 因请求返回401的刷新
+	Failures              int64         `json:"failures"`               // 获取失败次数
+	RetriedRequests       int64         `json:"retried_requests"`       // 返回401后使用新令牌重试的请求数
+	AverageLatency        time.Duration `json:"avg_latency"`            // 令牌端点平均耗时
+	MaxLatency            time.Duration `json:"max_latency"`
+	LastError             string        `json:"last_error,omitempty"`
+}
+
+// ToMap 转换为报告使用的map
+func (s OAuth2Stats) ToMap() map[string]interface{} {
+	stats := map[string]interface{}{
+		"grant":                  s.Grant,
+		"fetches":                s.Fetches,
+		"refreshes":              s.Refreshes,
+		"expiry_refreshes":       s.ExpiryRefreshes,
+		"unauthorized_refreshes": s.UnauthorizedRefreshes,
+		"failures":               s.Failures,
+		"retried_requests":       s.RetriedRequests,
+		"avg_latency":            s.AverageLatency.String(),
+		"max_latency":            s.MaxLatency.String(),
+	}
+	if s.LastError != "" {
+		stats["last_error"] = s.LastError
+	}
+	return stats
+}
+
+// tokenResponse 令牌端点的响应
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	Error        string `json:"error"`
+	Description  string `json:"error_description"`
+}
+
+// TokenSource OAuth2访问令牌来源，所有并发请求共享同一令牌
+// 令牌临近过期或被服务端拒绝（401）时只由一个请求刷新，其余请求等待刷新结果
+type TokenSource struct {
+	config httpConfig.HttpOAuth2Config
+	client *http.Client
+
+	mutex        sync.RWMutex
+	token        string
+	expiry       time.Time // 零值表示令牌端点未给出有效期
+	refreshToken string
+	invalidated  bool // 令牌已被服务端拒绝
+
+	fetchMutex sync.Mutex // 串行化令牌端点请求
+
+	statsMutex   sync.Mutex
+	stats        OAuth2Stats
+	totalLatency time.Duration
+}
+
+// NewTokenSource 创建令牌来源，配置须已通过HttpOAuth2Config.Validate验证
+func NewTokenSource(config httpConfig.HttpOAuth2Config, client *http.Client) *TokenSource {
+	if config.RefreshBefore == 0 {
+		config.RefreshBefore = defaultRefreshBefore
+	}
+	return &TokenSource{
+		config:       config,
+		client:       client,
+		refreshToken: config.RefreshToken,
+		stats:        OAuth2Stats{Grant: config.Grant()},
+	}
+}
+
+// Fetch 在运行开始前获取首个令牌
+func (t *TokenSource) Fetch(ctx context.Context) error {
+	if _, err := t.refresh(ctx, "", refreshReasonInitial); err != nil {
+		return fmt.Errorf("%w: %w", ErrTokenFetch, err)
+	}
+	return nil
+}
+
+// Token 获取当前有效的令牌，必要时先刷新；返回值中的耗时为等待令牌端点的时间
+func (t *TokenSource) Token(ctx context.Context) (string, time.Duration, error) {
+	t.mutex.RLock()
+	token, reason := t.token, t.refreshReason()
+	t.mutex.RUnlock()
+	if reason == "" {
+		return token, 0, nil
+	}
+
+	start := time.Now()
+	refreshed, err := t.refresh(ctx, token, reason)
+	if err != nil && reason == refreshReasonExpiry && t.valid(token) {
+		// 提前刷新失败时继续使用尚未过期的旧令牌
+		return token, time.Since(start), nil
+	}
+	return refreshed, time.Since(start), err
+}
+
+// valid 令牌是否仍是当前令牌且尚未过期
+func (t *TokenSource) valid(token string) bool {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return token == t.token && !t.invalidated && (t.expiry.IsZero() || time.Now().Before(t.expiry))
+}
+
+// Invalidate 标记令牌已被服务端拒绝，下次获取时刷新；令牌已被其他请求刷新时忽略
+func (t *TokenSource) Invalidate(token string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.token == token {
+		t.invalidated = true
+	}
+}
+
+// RecordRetry 记录一次返回401后使用新令牌的重试
+func (t *TokenSource) RecordRetry() {
+	t.statsMutex.Lock()
+	defer t.statsMutex.Unlock()
+	t.stats.RetriedRequests++
+}
+
+// Stats 获取令牌获取统计
+func (t *TokenSource) Stats() OAuth2Stats {
+	t.statsMutex.Lock()
+	defer t.statsMutex.Unlock()
+
+	stats := t.stats
+	if stats.Fetches > 0 {
+		stats.AverageLatency = t.totalLatency / time.Duration(stats.Fetches)
+	}
+	return stats
+}
+
+// refreshReason 当前令牌需要刷新的原因，无需刷新时为空，调用方需持有锁
+func (t *TokenSource) refreshReason() string {
+	switch {
+	case t.token == "":
+		return refreshReasonInitial
+	case t.invalidated:
+		return refreshReasonUnauthorized
+	case !t.expiry.IsZero() && time.Until(t.expiry) < t.config.RefreshBefore:
+		return refreshReasonExpiry
+	default:
+		return ""
+	}
+}
+
+// refresh 从令牌端点获取新令牌；stale为调用方持有的旧令牌，等待期间已被其他请求刷新时直接返回新令牌
+func (t *TokenSource) refresh(ctx context.Context, stale, reason string) (string, error) {
+	t.fetchMutex.Lock()
+	defer t.fetchMutex.Unlock()
+
+	t.mutex.RLock()
+	current, pending := t.token, t.refreshReason()
+	refreshToken := t.refreshToken
+	t.mutex.RUnlock()
+	if current != stale && pending == "" {
+		return current, nil
+	}
+	if pending != "" {
+		reason = pending
+	}
+
+	start := time.Now()
+	response, err := t.request(ctx, refreshToken)
+	latency := time.Since(start)
+
+	t.statsMutex.Lock()
+	t.stats.Fetches++
+	t.totalLatency += latency
+	t.stats.MaxLatency = max(t.stats.MaxLatency, latency)
+	if err != nil {
+		t.stats.Failures++
+		t.stats.LastError = err.Error()
+	} else if reason != refreshReasonInitial || current != "" {
+		t.stats.Refreshes++
+		switch reason {
+		case refreshReasonExpiry:
+			t.stats.ExpiryRefreshes++
+		case refreshReasonUnauthorized:
+			t.stats.UnauthorizedRefreshes++
+		}
+	}
+	t.statsMutex.Unlock()
+	if err != nil {
+		return "", err
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.token = response.AccessToken
+	t.invalidated = false
+	t.expiry = time.Time{}
+	if response.ExpiresIn > 0 {
+		t.expiry = start.Add(time.Duration(response.ExpiresIn) * time.Second)
+	}
+	if response.RefreshToken != "" {
+		t.refreshToken = response.RefreshToken
+	}
+	return t.token, nil
+}
+
+// request 向令牌端点发送请求
+func (t *TokenSource) request(ctx context.Context, refreshToken string) (*tokenResponse, error) {
+	form := url.Values{"grant_type": {t.config.Grant()}}
+	if t.config.Grant() == "refresh_token" {
+		form.Set("refresh_token", refreshToken)
+	}
+	if len(t.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(t.config.Scopes, " "))
+	}
+	basic := t.config.AuthStyle != "body" && t.config.ClientID != ""
+	if !basic && t.config.ClientID != "" {
+		form.Set("client_id", t.config.ClientID)
+		if t.config.ClientSecret != "" {
+			form.Set("client_secret", t.config.ClientSecret)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create oauth2 token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if basic {
+		req.SetBasicAuth(url.QueryEscape(t.config.ClientID), url.QueryEscape(t.config.ClientSecret))
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("oauth2 token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read oauth2 token response: %w", err)
+	}
+	var token tokenResponse
+	decodeErr := json.Unmarshal(body, &token)
+	if resp.StatusCode != http.StatusOK {
+		if decodeErr == nil && token.Error != "" {
+			return nil, fmt.Errorf("oauth2 token endpoint returned HTTP %d: %s %s", resp.StatusCode, token.Error, token.Description)
+		}
+		return nil, fmt.Errorf("oauth2 token endpoint returned HTTP %d", resp.StatusCode)
+	}
+	if decodeErr != nil {
+		return nil, fmt.Errorf("invalid oauth2 token response: %w", decodeErr)
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("oauth2 token response has no access_token")
+	}
+	return &token, nil
+}
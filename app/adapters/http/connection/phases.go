@@ -0,0 +1,85 @@
+package connection
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"abc-runner/app/core/metrics"
+)
+
+// phaseTrace 通过httptrace记录一次请求各阶段的时间点
+// 建连回调在传输层的拨号协程中执行，请求取消后仍可能到达，因此加锁
+type phaseTrace struct {
+	mutex        sync.Mutex
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	gotConn      time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+}
+
+// set 在锁内记录时间点
+func (p *phaseTrace) set(at *time.Time) {
+	now := time.Now()
+	p.mutex.Lock()
+	*at = now
+	p.mutex.Unlock()
+}
+
+// clientTrace 返回记录时间点的httptrace回调
+func (p *phaseTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { p.set(&p.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { p.set(&p.dnsDone) },
+		ConnectStart: func(string, string) {
+			// 多地址并行拨号时取第一次开始
+			p.mutex.Lock()
+			if p.connectStart.IsZero() {
+				p.connectStart = time.Now()
+			}
+			p.mutex.Unlock()
+		},
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				p.set(&p.connectDone)
+			}
+		},
+		TLSHandshakeStart: func() { p.set(&p.tlsStart) },
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			if err == nil {
+				p.set(&p.tlsDone)
+			}
+		},
+		GotConn:              func(httptrace.GotConnInfo) { p.set(&p.gotConn) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { p.set(&p.wroteRequest) },
+		GotFirstResponseByte: func() { p.set(&p.firstByte) },
+	}
+}
+
+// timings 计算各阶段耗时，done为读完响应体的时间；缺少起止时间点的阶段为0（如复用连接时的建连）
+func (p *phaseTrace) timings(done time.Time) metrics.PhaseTimings {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return metrics.PhaseTimings{
+		DNS:       between(p.dnsStart, p.dnsDone),
+		Connect:   between(p.connectStart, p.connectDone),
+		TLS:       between(p.tlsStart, p.tlsDone),
+		Request:   between(p.gotConn, p.wroteRequest),
+		FirstByte: between(p.wroteRequest, p.firstByte),
+		Transfer:  between(p.firstByte, done),
+	}
+}
+
+// between 两个时间点之间的耗时，任一时间点缺失时为0
+func between(start, end time.Time) time.Duration {
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
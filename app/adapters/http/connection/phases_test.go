@@ -0,0 +1,49 @@
+package connection
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	httpConfig "abc-runner/app/adapters/http/config"
+)
+
+func TestExecuteRequestPhases(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(5 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		time.Sleep(5 * time.Millisecond)
+		io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	config := httpConfig.LoadDefaultHttpConfig()
+	config.Connection.BaseURL = server.URL
+	client := NewHttpClient(server.Client(), config, nil)
+	request := httpConfig.HttpRequestConfig{Method: http.MethodGet, Path: "/"}
+
+	first, err := client.ExecuteRequest(context.Background(), request)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	phases := first.Phases
+	if phases.Connect <= 0 || phases.TLS <= 0 || phases.Request <= 0 {
+		t.Errorf("new connection phases missing: %+v", phases)
+	}
+	if phases.FirstByte < 5*time.Millisecond || phases.Transfer < 5*time.Millisecond {
+		t.Errorf("server time and transfer not separated: %+v", phases)
+	}
+
+	// 复用连接时没有建连与握手
+	second, err := client.ExecuteRequest(context.Background(), request)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if second.Phases.Connect != 0 || second.Phases.TLS != 0 || second.Phases.FirstByte <= 0 {
+		t.Errorf("reused connection phases: %+v", second.Phases)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	httpConfig "abc-runner/app/adapters/http/config"
+	coreTransport "abc-runner/app/core/transport"
 )
 
 // HTTPConnectionPool HTTP连接池管理器
@@ -18,6 +19,8 @@ type HTTPConnectionPool struct {
 	// 配置和状态
 	config    *httpConfig.HttpAdapterConfig
 	isHealthy bool
+	ring      *coreTransport.Ring // 非nil时连接经io_uring收发
+	proxies   *ProxyRotator       // 非nil时请求经出站代理列表发送
 	
 	// 统计信息
 	activeConnections int64
@@ -44,12 +47,25 @@ type PoolConfig struct {
 
 // NewHTTPConnectionPool 创建HTTP连接池
 func NewHTTPConnectionPool(config *httpConfig.HttpAdapterConfig, poolConfig PoolConfig) (*HTTPConnectionPool, error) {
+	dialer := &net.Dialer{
+		Timeout:   poolConfig.ConnectionTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+	dialContext := dialer.DialContext
+
+	// 实验性的io_uring传输：连接仍由dialer建立，收发经io_uring批量提交
+	var ring *coreTransport.Ring
+	if config.Connection.Transport == coreTransport.IOURing {
+		var err error
+		if ring, err = coreTransport.NewRing(coreTransport.DefaultRingEntries); err != nil {
+			return nil, fmt.Errorf("failed to set up io_uring transport: %w", err)
+		}
+		dialContext = ring.Dialer(dialer)
+	}
+
 	// 创建自定义Transport
 	transport := &http.Transport{
-		DialContext: (&net.Dialer{
-			Timeout:   poolConfig.ConnectionTimeout,
-			KeepAlive: 30 * time.Second,
-		}).DialContext,
+		DialContext:           dialContext,
 		MaxIdleConns:          poolConfig.MaxIdleConns,
 		MaxIdleConnsPerHost:   poolConfig.MaxConnsPerHost,
 		MaxConnsPerHost:       poolConfig.MaxConnsPerHost,
@@ -64,9 +80,23 @@ func NewHTTPConnectionPool(config *httpConfig.HttpAdapterConfig, poolConfig Pool
 	// 由于原始配置结构中没有UseHTTPS字段，这里暂时跳过TLS配置
 	// 未来可以根据需要添加TLS配置
 	
+	// 出站代理：每个代理复制一份Transport，按连接轮换
+	var roundTripper http.RoundTripper = transport
+	var proxies *ProxyRotator
+	if len(config.Connection.Proxies) > 0 {
+		var err error
+		if proxies, err = NewProxyRotator(config.Connection.Proxies, transport); err != nil {
+			if ring != nil {
+				ring.Close()
+			}
+			return nil, err
+		}
+		roundTripper = proxies
+	}
+
 	// 创建HTTP客户端
 	client := &http.Client{
-		Transport: transport,
+		Transport: roundTripper,
 		Timeout:   poolConfig.RequestTimeout,
 	}
 	
@@ -82,6 +112,8 @@ func NewHTTPConnectionPool(config *httpConfig.HttpAdapterConfig, poolConfig Pool
 		client:    client,
 		config:    config,
 		isHealthy: true,
+		ring:      ring,
+		proxies:   proxies,
 	}
 	
 	return pool, nil
@@ -163,9 +195,43 @@ func (p *HTTPConnectionPool) GetStats() map[string]interface{} {
 		}
 	}
 	
+	if p.ring != nil {
+		stats["io_uring"] = p.ring.Stats().Map()
+	}
+
+	if p.proxies != nil {
+		proxies := make([]interface{}, 0)
+		for _, proxy := range p.proxies.Stats() {
+			proxies = append(proxies, proxy.ToMap())
+		}
+		stats["proxies"] = proxies
+	}
+	
 	return stats
 }
 
+// TransportStats 获取io_uring传输统计，未启用时返回false
+func (p *HTTPConnectionPool) TransportStats() (coreTransport.Stats, bool) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	if p.ring == nil {
+		return coreTransport.Stats{}, false
+	}
+	return p.ring.Stats(), true
+}
+
+// ProxyStats 获取各出站代理的统计，未配置代理时返回false
+func (p *HTTPConnectionPool) ProxyStats() ([]ProxyStats, bool) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	if p.proxies == nil {
+		return nil, false
+	}
+	return p.proxies.Stats(), true
+}
+
 // Close 关闭连接池
 func (p *HTTPConnectionPool) Close() error {
 	p.mutex.Lock()
@@ -177,6 +243,17 @@ func (p *HTTPConnectionPool) Close() error {
 		}
 		p.client = nil
 	}
+
+	if p.proxies != nil {
+		p.proxies.CloseIdleConnections()
+	}
+
+	if p.ring != nil {
+		if err := p.ring.Close(); err != nil {
+			return fmt.Errorf("failed to close io_uring transport: %w", err)
+		}
+		p.ring = nil
+	}
 	
 	p.isHealthy = false
 	return nil
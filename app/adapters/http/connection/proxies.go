@@ -0,0 +1,233 @@
+package connection
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"abc-runner/app/core/metrics"
+)
+
+// ProxyStats 单个出站代理的统计
+type ProxyStats struct {
+	Proxy       string                 `json:"proxy"`       // 代理地址（隐藏密码）
+	Requests    int64                  `json:"requests"`    // 经该代理发出的请求数
+	Errors      int64                  `json:"errors"`      // 未收到响应的请求数（代理连接失败、握手失败等）
+	Failed      int64                  `json:"failed"`      // 状态码>=400的响应数（含代理自身返回的407、502等）
+	Connections int64                  `json:"connections"` // 经该代理建立的连接数
+	StatusCodes map[int]int64          `json:"status_codes"`
+	Latency     metrics.LatencyMetrics `json:"latency"` // 请求到响应体读取完毕的时间
+	LastError   string                 `json:"last_error,omitempty"`
+}
+
+// ToMap 转换为报告使用的map
+func (s ProxyStats) ToMap() map[string]interface{} {
+	codes := make(map[string]interface{}, len(s.StatusCodes))
+	for code, count := range s.StatusCodes {
+		codes[fmt.Sprintf("%d", code)] = count
+	}
+	result := map[string]interface{}{
+		"proxy":        s.Proxy,
+		"requests":     s.Requests,
+		"errors":       s.Errors,
+		"failed":       s.Failed,
+		"connections":  s.Connections,
+		"status_codes": codes,
+		"latency": map[string]interface{}{
+			"avg":  s.Latency.Average.String(),
+			"min":  s.Latency.Min.String(),
+			"max":  s.Latency.Max.String(),
+			"p50":  s.Latency.P50.String(),
+			"p90":  s.Latency.P90.String(),
+			"p95":  s.Latency.P95.String(),
+			"p99":  s.Latency.P99.String(),
+			"p999": s.Latency.P999.String(),
+		},
+	}
+	if s.LastError != "" {
+		result["last_error"] = s.LastError
+	}
+	return result
+}
+
+// ProxyRotator 经出站代理列表发送请求的RoundTripper
+// 每个代理使用独立的Transport与连接池；请求优先复用已有空闲连接的代理，
+// 需要新连接时按轮询选择下一个代理，使连接均匀分布在各代理上
+type ProxyRotator struct {
+	routes []*proxyRoute
+	next   atomic.Uint64
+}
+
+// proxyRoute 一个出站代理及其统计
+type proxyRoute struct {
+	name      string
+	transport *http.Transport
+	open      atomic.Int64 // 当前打开的连接数
+	busy      atomic.Int64 // 正在进行的请求数
+
+	mutex       sync.Mutex
+	requests    int64
+	errors      int64
+	failed      int64
+	connections int64
+	statusCodes map[int]int64
+	lastError   string
+	latency     *metrics.LatencyTracker
+}
+
+// NewProxyRotator 创建出站代理轮换器，每个代理复制base的Transport配置
+func NewProxyRotator(proxies []string, base *http.Transport) (*ProxyRotator, error) {
+	if len(proxies) == 0 {
+		return nil, fmt.Errorf("no proxies configured")
+	}
+
+	rotator := &ProxyRotator{}
+	for _, proxy := range proxies {
+		proxyURL, err := url.Parse(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy %q: %w", proxy, err)
+		}
+
+		route := &proxyRoute{
+			name:        proxyURL.Redacted(),
+			statusCodes: make(map[int]int64),
+			latency: metrics.NewLatencyTracker(metrics.LatencyConfig{
+				SamplingRate: 1.0,
+			}),
+		}
+		transport := base.Clone()
+		transport.Proxy = http.ProxyURL(proxyURL)
+		dial := transport.DialContext
+		if dial == nil {
+			dial = (&net.Dialer{}).DialContext
+		}
+		transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := dial(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+			route.open.Add(1)
+			route.mutex.Lock()
+			route.connections++
+			route.mutex.Unlock()
+			return &proxyConn{Conn: conn, route: route}, nil
+		}
+		route.transport = transport
+		rotator.routes = append(rotator.routes, route)
+	}
+	return rotator, nil
+}
+
+// RoundTrip 经选中的代理发送请求
+func (r *ProxyRotator) RoundTrip(req *http.Request) (*http.Response, error) {
+	route := r.pick()
+	route.busy.Add(1)
+	start := time.Now()
+
+	resp, err := route.transport.RoundTrip(req)
+	if err != nil {
+		route.busy.Add(-1)
+		route.record(0, time.Since(start), err)
+		return nil, err
+	}
+	resp.Body = &proxyBody{ReadCloser: resp.Body, route: route, start: start, status: resp.StatusCode}
+	return resp, nil
+}
+
+// pick 选择代理：有空闲连接的代理优先，否则轮询下一个代理建立新连接
+func (r *ProxyRotator) pick() *proxyRoute {
+	for _, route := range r.routes {
+		if route.open.Load() > route.busy.Load() {
+			return route
+		}
+	}
+	return r.routes[(r.next.Add(1)-1)%uint64(len(r.routes))]
+}
+
+// Stats 获取各代理的统计，顺序与配置一致
+func (r *ProxyRotator) Stats() []ProxyStats {
+	stats := make([]ProxyStats, 0, len(r.routes))
+	for _, route := range r.routes {
+		stats = append(stats, route.stats())
+	}
+	return stats
+}
+
+// CloseIdleConnections 关闭所有代理的空闲连接
+func (r *ProxyRotator) CloseIdleConnections() {
+	for _, route := range r.routes {
+		route.transport.CloseIdleConnections()
+	}
+}
+
+// record 记录一次请求的结果，status为0表示未收到响应
+func (p *proxyRoute) record(status int, latency time.Duration, err error) {
+	p.latency.Record(latency)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.requests++
+	if err != nil {
+		p.errors++
+		p.lastError = err.Error()
+		return
+	}
+	p.statusCodes[status]++
+	if status >= 400 {
+		p.failed++
+	}
+}
+
+// stats 获取代理统计快照
+func (p *proxyRoute) stats() ProxyStats {
+	stats := ProxyStats{Proxy: p.name, Latency: p.latency.GetMetrics()}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	stats.Requests = p.requests
+	stats.Errors = p.errors
+	stats.Failed = p.failed
+	stats.Connections = p.connections
+	stats.LastError = p.lastError
+	stats.StatusCodes = make(map[int]int64, len(p.statusCodes))
+	for code, count := range p.statusCodes {
+		stats.StatusCodes[code] = count
+	}
+	return stats
+}
+
+// proxyConn 经代理建立的连接，关闭时更新打开的连接数
+type proxyConn struct {
+	net.Conn
+	route *proxyRoute
+	once  sync.Once
+}
+
+func (c *proxyConn) Close() error {
+	c.once.Do(func() { c.route.open.Add(-1) })
+	return c.Conn.Close()
+}
+
+// proxyBody 响应体，关闭时记录请求结果
+type proxyBody struct {
+	io.ReadCloser
+	route  *proxyRoute
+	start  time.Time
+	status int
+	once   sync.Once
+}
+
+func (b *proxyBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() {
+		b.route.busy.Add(-1)
+		b.route.record(b.status, time.Since(b.start), nil)
+	})
+	return err
+}
@@ -0,0 +1,158 @@
+package connection
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	httpConfig "abc-runner/app/adapters/http/config"
+)
+
+// newForwardProxy 启动一个转发绝对URI请求的HTTP代理，并统计经过的请求数
+func newForwardProxy(t *testing.T, requests *atomic.Int64) *httptest.Server {
+	transport := &http.Transport{}
+	t.Cleanup(transport.CloseIdleConnections)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !r.URL.IsAbs() {
+			http.Error(w, "not a proxy request", http.StatusBadRequest)
+			return
+		}
+		requests.Add(1)
+		out := r.Clone(r.Context())
+		out.RequestURI = ""
+		resp, err := transport.RoundTrip(out)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+		w.WriteHeader(resp.StatusCode)
+		io.Copy(w, resp.Body)
+	}))
+	t.Cleanup(proxy.Close)
+	return proxy
+}
+
+func TestProxyRotation(t *testing.T) {
+	// 前两个请求在服务端相互等待，迫使连接池同时打开两个连接
+	var arrived sync.WaitGroup
+	arrived.Add(2)
+	var held atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		if held.Add(1) <= 2 {
+			arrived.Done()
+			arrived.Wait()
+		}
+		io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	var first, second atomic.Int64
+	config := httpConfig.LoadDefaultHttpConfig()
+	config.Connection.BaseURL = server.URL
+	config.Connection.Proxies = []string{
+		"http://user:secret@" + newForwardProxy(t, &first).Listener.Addr().String(),
+		"http://" + newForwardProxy(t, &second).Listener.Addr().String(),
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("invalid config: %v", err)
+	}
+	pool, err := NewHttpConnectionPool(config)
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	defer pool.Close()
+
+	get := func(path string) int {
+		resp, err := pool.GetClient().Get(server.URL + path)
+		if err != nil {
+			t.Errorf("request failed: %v", err)
+			return 0
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			get("/")
+		}()
+	}
+	wg.Wait()
+	// 之后的请求复用已打开的连接，不再新建连接
+	for i := 0; i < 4; i++ {
+		get("/")
+	}
+	if status := get("/missing"); status != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", status)
+	}
+
+	if first.Load() == 0 || second.Load() == 0 || first.Load()+second.Load() != 7 {
+		t.Errorf("expected requests on both proxies, got %d and %d", first.Load(), second.Load())
+	}
+	stats, ok := pool.ProxyStats()
+	if !ok || len(stats) != 2 {
+		t.Fatalf("expected stats for 2 proxies, got %v", stats)
+	}
+	if stats[0].Proxy != "http://user:xxxxx@"+config.Connection.Proxies[0][len("http://user:secret@"):] {
+		t.Errorf("expected the password to be redacted, got %s", stats[0].Proxy)
+	}
+	var requests, failed int64
+	for i, proxy := range stats {
+		if proxy.Connections != 1 {
+			t.Errorf("proxy %d: expected one connection, got %d", i, proxy.Connections)
+		}
+		if proxy.Errors != 0 || proxy.Latency.P50 <= 0 {
+			t.Errorf("proxy %d: unexpected stats %+v", i, proxy)
+		}
+		requests += proxy.Requests
+		failed += proxy.Failed
+	}
+	if requests != 7 || failed != 1 || stats[0].Requests != first.Load() {
+		t.Errorf("unexpected per-proxy counts: %+v", stats)
+	}
+}
+
+func TestProxyErrors(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	dead := listener.Addr().String()
+	listener.Close()
+
+	config := httpConfig.LoadDefaultHttpConfig()
+	config.Connection.BaseURL = "http://example.invalid"
+	config.Connection.Proxies = []string{"socks5h://" + dead}
+	pool, err := NewHttpConnectionPool(config)
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	defer pool.Close()
+
+	if _, err := pool.GetClient().Get("http://example.invalid/"); err == nil {
+		t.Fatalf("expected the request through an unreachable proxy to fail")
+	}
+	stats, _ := pool.ProxyStats()
+	if len(stats) != 1 || stats[0].Requests != 1 || stats[0].Errors != 1 || stats[0].LastError == "" {
+		t.Errorf("expected one recorded error, got %+v", stats)
+	}
+
+	for _, proxy := range []string{"ftp://proxy:21", "http://", "localhost:3128"} {
+		if httpConfig.ValidateProxyURL(proxy) == nil {
+			t.Errorf("expected %q to be rejected", proxy)
+		}
+	}
+}
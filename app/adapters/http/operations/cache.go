@@ -0,0 +1,207 @@
+package operations
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheStatus 缓存命中状态
+type CacheStatus string
+
+const (
+	CacheStatusHit     CacheStatus = "hit"     // 由缓存直接响应
+	CacheStatusMiss    CacheStatus = "miss"    // 回源获取
+	CacheStatusUnknown CacheStatus = "unknown" // 响应中没有可识别的缓存头
+)
+
+// cacheStatusHeaders 按优先级检查的缓存状态头
+// X-Cache: Varnish/Squid/CloudFront/Fastly，X-Cache-Status: Nginx，CF-Cache-Status: Cloudflare
+var cacheStatusHeaders = []string{"Cache-Status", "X-Cache", "X-Cache-Status", "CF-Cache-Status"}
+
+// ParseCacheStatus 根据响应头判断缓存命中状态，返回状态和Age头的值
+// 支持RFC 9211 Cache-Status以及常见CDN/反向代理的私有头；没有状态头时以Age>0视为命中
+func ParseCacheStatus(headers http.Header) (CacheStatus, time.Duration) {
+	var age time.Duration
+	if value := headers.Get("Age"); value != "" {
+		if seconds, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && seconds >= 0 {
+			age = time.Duration(seconds) * time.Second
+		}
+	}
+
+	for _, name := range cacheStatusHeaders {
+		value := headers.Get(name)
+		if value == "" {
+			continue
+		}
+		var status CacheStatus
+		if name == "Cache-Status" {
+			status = parseStructuredCacheStatus(value)
+		} else {
+			status = parseCacheStatusToken(value)
+		}
+		if status != CacheStatusUnknown {
+			return status, age
+		}
+	}
+
+	if age > 0 {
+		return CacheStatusHit, age
+	}
+	return CacheStatusUnknown, age
+}
+
+// parseStructuredCacheStatus 解析RFC 9211 Cache-Status头
+// 多级缓存时以最靠近客户端（最后一个）的条目为准，例如 "Origin; fwd=miss, CDN; hit"
+func parseStructuredCacheStatus(value string) CacheStatus {
+	entries := strings.Split(value, ",")
+	entry := strings.ToLower(entries[len(entries)-1])
+	for _, param := range strings.Split(entry, ";")[1:] {
+		param = strings.TrimSpace(param)
+		if param == "hit" {
+			return CacheStatusHit
+		}
+		if strings.HasPrefix(param, "fwd=") {
+			return CacheStatusMiss
+		}
+	}
+	return CacheStatusUnknown
+}
+
+// parseCacheStatusToken 解析私有缓存头，例如 "HIT"、"TCP_MISS"、"HIT from proxy"、"Hit from cloudfront"
+// 多级缓存（如 "MISS, HIT"）时任一级命中即视为命中
+func parseCacheStatusToken(value string) CacheStatus {
+	status := CacheStatusUnknown
+	for _, part := range strings.Split(strings.ToUpper(value), ",") {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+		token := strings.TrimPrefix(fields[0], "TCP_")
+		switch {
+		case strings.HasPrefix(token, "HIT"), token == "STALE", token == "UPDATING",
+			token == "REVALIDATED", token == "REFRESH_HIT", token == "MEM_HIT", token == "IMS_HIT":
+			return CacheStatusHit
+		case strings.HasPrefix(token, "MISS"), token == "EXPIRED", token == "BYPASS",
+			token == "DYNAMIC", token == "PASS", token == "REFRESH_MISS", token == "REFRESH":
+			status = CacheStatusMiss
+		}
+	}
+	return status
+}
+
+// CacheStats 缓存服务器测试统计
+type CacheStats struct {
+	Requests          int64         `json:"requests"`
+	Cacheable         int64         `json:"cacheable"`           // 可缓存URL的请求数
+	Hits              int64         `json:"hits"`                // 缓存命中数
+	Misses            int64         `json:"misses"`              // 回源数
+	Unknown           int64         `json:"unknown"`             // 无法识别缓存状态的响应数
+	CacheableHits     int64         `json:"cacheable_hits"`      // 可缓存URL上的命中数
+	UncacheableHits   int64         `json:"uncacheable_hits"`    // 不可缓存URL被错误命中的次数
+	HitBytes          int64         `json:"hit_bytes"`           // 由缓存响应的字节数
+	TotalBytes        int64         `json:"total_bytes"`         // 响应总字节数
+	HitRatio          float64       `json:"hit_ratio"`           // 命中率(%)，基于可识别状态的响应
+	CacheableHitRatio float64       `json:"cacheable_hit_ratio"` // 可缓存URL的命中率(%)
+	RequestOffload    float64       `json:"request_offload"`     // 源站请求卸载率(%)
+	ByteOffload       float64       `json:"byte_offload"`        // 源站流量卸载率(%)
+	AverageAge        time.Duration `json:"average_age"`         // 命中响应的平均Age
+	HitLatency        time.Duration `json:"hit_latency"`         // 命中响应的平均延迟
+	MissLatency       time.Duration `json:"miss_latency"`        // 回源响应的平均延迟
+}
+
+// ToMap 转换为报告使用的map
+func (s CacheStats) ToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"requests":            s.Requests,
+		"cacheable":           s.Cacheable,
+		"hits":                s.Hits,
+		"misses":              s.Misses,
+		"unknown":             s.Unknown,
+		"cacheable_hits":      s.CacheableHits,
+		"uncacheable_hits":    s.UncacheableHits,
+		"hit_bytes":           s.HitBytes,
+		"total_bytes":         s.TotalBytes,
+		"hit_ratio":           s.HitRatio,
+		"cacheable_hit_ratio": s.CacheableHitRatio,
+		"request_offload":     s.RequestOffload,
+		"byte_offload":        s.ByteOffload,
+		"average_age":         s.AverageAge.String(),
+		"hit_latency":         s.HitLatency.String(),
+		"miss_latency":        s.MissLatency.String(),
+	}
+}
+
+// CacheTracker 缓存命中统计器
+type CacheTracker struct {
+	mutex       sync.Mutex
+	stats       CacheStats
+	totalAge    time.Duration
+	hitLatency  time.Duration
+	missLatency time.Duration
+}
+
+// NewCacheTracker 创建缓存命中统计器
+func NewCacheTracker() *CacheTracker {
+	return &CacheTracker{}
+}
+
+// Record 记录一次响应
+func (t *CacheTracker) Record(cacheable bool, status CacheStatus, age time.Duration, size int, latency time.Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.stats.Requests++
+	t.stats.TotalBytes += int64(size)
+	if cacheable {
+		t.stats.Cacheable++
+	}
+
+	switch status {
+	case CacheStatusHit:
+		t.stats.Hits++
+		t.stats.HitBytes += int64(size)
+		t.totalAge += age
+		t.hitLatency += latency
+		if cacheable {
+			t.stats.CacheableHits++
+		} else {
+			t.stats.UncacheableHits++
+		}
+	case CacheStatusMiss:
+		t.stats.Misses++
+		t.missLatency += latency
+	default:
+		t.stats.Unknown++
+	}
+}
+
+// Stats 获取统计结果
+func (t *CacheTracker) Stats() CacheStats {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	stats := t.stats
+	if known := stats.Hits + stats.Misses; known > 0 {
+		stats.HitRatio = float64(stats.Hits) / float64(known) * 100
+	}
+	if stats.Cacheable > 0 {
+		stats.CacheableHitRatio = float64(stats.CacheableHits) / float64(stats.Cacheable) * 100
+	}
+	if stats.Requests > 0 {
+		stats.RequestOffload = float64(stats.Hits) / float64(stats.Requests) * 100
+	}
+	if stats.TotalBytes > 0 {
+		stats.ByteOffload = float64(stats.HitBytes) / float64(stats.TotalBytes) * 100
+	}
+	if stats.Hits > 0 {
+		stats.AverageAge = t.totalAge / time.Duration(stats.Hits)
+		stats.HitLatency = t.hitLatency / time.Duration(stats.Hits)
+	}
+	if stats.Misses > 0 {
+		stats.MissLatency = t.missLatency / time.Duration(stats.Misses)
+	}
+	return stats
+}
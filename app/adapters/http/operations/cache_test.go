@@ -0,0 +1,89 @@
+package operations
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	httpConfig "abc-runner/app/adapters/http/config"
+)
+
+func TestParseCacheStatus(t *testing.T) {
+	cases := []struct {
+		headers map[string]string
+		status  CacheStatus
+		age     time.Duration
+	}{
+		{map[string]string{"X-Cache": "HIT"}, CacheStatusHit, 0},
+		{map[string]string{"X-Cache": "Miss from cloudfront"}, CacheStatusMiss, 0},
+		{map[string]string{"X-Cache": "MISS, HIT"}, CacheStatusHit, 0},
+		{map[string]string{"X-Cache": "TCP_MEM_HIT from proxy"}, CacheStatusHit, 0},
+		{map[string]string{"X-Cache-Status": "EXPIRED"}, CacheStatusMiss, 0},
+		{map[string]string{"CF-Cache-Status": "DYNAMIC"}, CacheStatusMiss, 0},
+		{map[string]string{"Cache-Status": "Origin; fwd=miss, CDN; hit; ttl=30"}, CacheStatusHit, 0},
+		{map[string]string{"Cache-Status": "CDN; fwd=uri-miss"}, CacheStatusMiss, 0},
+		{map[string]string{"Age": "42"}, CacheStatusHit, 42 * time.Second},
+		{map[string]string{"Age": "0"}, CacheStatusUnknown, 0},
+		{map[string]string{}, CacheStatusUnknown, 0},
+	}
+
+	for _, c := range cases {
+		headers := http.Header{}
+		for k, v := range c.headers {
+			headers.Set(k, v)
+		}
+		status, age := ParseCacheStatus(headers)
+		if status != c.status || age != c.age {
+			t.Errorf("ParseCacheStatus(%v) = %s, %v; expected %s, %v", c.headers, status, age, c.status, c.age)
+		}
+	}
+}
+
+func TestCacheTrackerStats(t *testing.T) {
+	tracker := NewCacheTracker()
+	tracker.Record(true, CacheStatusHit, 10*time.Second, 300, time.Millisecond)
+	tracker.Record(true, CacheStatusHit, 20*time.Second, 300, 3*time.Millisecond)
+	tracker.Record(true, CacheStatusMiss, 0, 300, 20*time.Millisecond)
+	tracker.Record(false, CacheStatusMiss, 0, 100, 40*time.Millisecond)
+
+	stats := tracker.Stats()
+	if stats.Requests != 4 || stats.Cacheable != 3 || stats.Hits != 2 || stats.Misses != 2 {
+		t.Fatalf("unexpected counters: %+v", stats)
+	}
+	if stats.HitRatio != 50 || stats.RequestOffload != 50 || stats.ByteOffload != 60 {
+		t.Errorf("unexpected ratios: hit=%.2f request=%.2f byte=%.2f", stats.HitRatio, stats.RequestOffload, stats.ByteOffload)
+	}
+	if stats.AverageAge != 15*time.Second || stats.HitLatency != 2*time.Millisecond || stats.MissLatency != 30*time.Millisecond {
+		t.Errorf("unexpected averages: %+v", stats)
+	}
+}
+
+func TestCacheMixOperations(t *testing.T) {
+	config := httpConfig.LoadDefaultHttpConfig()
+	config.Connection.BaseURL = "http://cdn.example.com"
+	config.Benchmark.TestCase = "cache_mix"
+	config.Benchmark.Cache.Objects = 10
+	factory := NewHttpOperationFactory(config)
+
+	cacheable := 0
+	for jobID := 0; jobID < 100; jobID++ {
+		op := factory.CreateOperation(jobID, nil)
+		isCacheable := op.Params["cacheable"].(bool)
+		headers := op.Params["headers"].(map[string]string)
+		if isCacheable {
+			cacheable++
+			if !strings.HasPrefix(op.Key, "/static/object/") || headers["Cache-Control"] != "" {
+				t.Errorf("unexpected cacheable request %d: %s %v", jobID, op.Key, headers)
+			}
+		} else if !strings.Contains(op.Key, "?nocache=") || headers["Cache-Control"] != "no-cache" {
+			t.Errorf("unexpected uncacheable request %d: %s %v", jobID, op.Key, headers)
+		}
+		if op.Value != nil {
+			t.Errorf("expected no request body for cache requests")
+		}
+	}
+	if cacheable != 80 {
+		t.Errorf("expected 80 cacheable requests, got %d", cacheable)
+	}
+}
@@ -0,0 +1,161 @@
+package operations
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	httpConfig "abc-runner/app/adapters/http/config"
+	"abc-runner/app/adapters/http/connection"
+)
+
+// ContractViolationStats 同一操作中一类契约违例的统计
+type ContractViolationStats struct {
+	Violation string `json:"violation"`
+	Count     int64  `json:"count"`
+	Sample    string `json:"sample"` // 首次出现该违例的请求
+}
+
+// ContractEndpointStats 单个文档操作的契约校验统计
+type ContractEndpointStats struct {
+	Operation  string                   `json:"operation"`
+	Sampled    int64                    `json:"sampled"`
+	Violating  int64                    `json:"violating"` // 存在违例的响应数
+	Violations []ContractViolationStats `json:"violations"`
+}
+
+// ContractStats API契约漂移检测统计
+type ContractStats struct {
+	Spec       string                  `json:"spec"`
+	SampleRate float64                 `json:"sample_rate"`
+	Sampled    int64                   `json:"sampled"`
+	Conforming int64                   `json:"conforming"`
+	Violating  int64                   `json:"violating"`
+	Endpoints  []ContractEndpointStats `json:"endpoints"`
+}
+
+// ToMap 转换为报告使用的map
+func (s ContractStats) ToMap() map[string]interface{} {
+	endpoints := make([]map[string]interface{}, 0, len(s.Endpoints))
+	for _, endpoint := range s.Endpoints {
+		violations := make([]map[string]interface{}, 0, len(endpoint.Violations))
+		for _, violation := range endpoint.Violations {
+			violations = append(violations, map[string]interface{}{
+				"violation": violation.Violation,
+				"count":     violation.Count,
+				"sample":    violation.Sample,
+			})
+		}
+		endpoints = append(endpoints, map[string]interface{}{
+			"operation":  endpoint.Operation,
+			"sampled":    endpoint.Sampled,
+			"violating":  endpoint.Violating,
+			"violations": violations,
+		})
+	}
+	return map[string]interface{}{
+		"spec":        s.Spec,
+		"sample_rate": s.SampleRate,
+		"sampled":     s.Sampled,
+		"conforming":  s.Conforming,
+		"violating":   s.Violating,
+		"endpoints":   endpoints,
+	}
+}
+
+// contractEndpoint 单个文档操作的累计数据
+type contractEndpoint struct {
+	stats      ContractEndpointStats
+	violations map[string]*ContractViolationStats
+}
+
+// ContractTracker 按OpenAPI文档抽样校验响应并按操作统计违例，nil时不做任何事
+type ContractTracker struct {
+	contract *httpConfig.OpenAPIContract
+	rate     float64
+	seen     atomic.Int64
+
+	mutex     sync.Mutex
+	stats     ContractStats
+	endpoints map[string]*contractEndpoint
+}
+
+// NewContractTracker 创建契约校验统计器，rate为校验的响应比例
+func NewContractTracker(contract *httpConfig.OpenAPIContract, rate float64) *ContractTracker {
+	return &ContractTracker{
+		contract:  contract,
+		rate:      rate,
+		stats:     ContractStats{Spec: contract.Source, SampleRate: rate},
+		endpoints: make(map[string]*contractEndpoint),
+	}
+}
+
+// sample 是否校验本次响应：按到达顺序均匀抽取rate比例的响应
+func (t *ContractTracker) sample() bool {
+	n := t.seen.Add(1)
+	return int64(float64(n)*t.rate) != int64(float64(n-1)*t.rate)
+}
+
+// Check 抽样校验一个响应，未得到响应的请求不参与校验
+func (t *ContractTracker) Check(method, path string, response *connection.HttpResponse) {
+	if t == nil || response == nil || response.Error != nil || !t.sample() {
+		return
+	}
+
+	operation, violations := t.contract.Check(method, path, response.StatusCode, response.Headers, response.Body)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	endpoint, ok := t.endpoints[operation]
+	if !ok {
+		endpoint = &contractEndpoint{
+			stats:      ContractEndpointStats{Operation: operation},
+			violations: make(map[string]*ContractViolationStats),
+		}
+		t.endpoints[operation] = endpoint
+	}
+	t.stats.Sampled++
+	endpoint.stats.Sampled++
+	if len(violations) == 0 {
+		t.stats.Conforming++
+		return
+	}
+	t.stats.Violating++
+	endpoint.stats.Violating++
+	for _, violation := range violations {
+		stats, ok := endpoint.violations[violation]
+		if !ok {
+			stats = &ContractViolationStats{Violation: violation, Sample: method + " " + path}
+			endpoint.violations[violation] = stats
+		}
+		stats.Count++
+	}
+}
+
+// Stats 获取统计结果，操作按名称排列，违例按出现次数从多到少排列
+func (t *ContractTracker) Stats() ContractStats {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	stats := t.stats
+	stats.Endpoints = make([]ContractEndpointStats, 0, len(t.endpoints))
+	for _, endpoint := range t.endpoints {
+		endpointStats := endpoint.stats
+		for _, violation := range endpoint.violations {
+			endpointStats.Violations = append(endpointStats.Violations, *violation)
+		}
+		sort.Slice(endpointStats.Violations, func(i, j int) bool {
+			a, b := endpointStats.Violations[i], endpointStats.Violations[j]
+			if a.Count != b.Count {
+				return a.Count > b.Count
+			}
+			return a.Violation < b.Violation
+		})
+		stats.Endpoints = append(stats.Endpoints, endpointStats)
+	}
+	sort.Slice(stats.Endpoints, func(i, j int) bool {
+		return stats.Endpoints[i].Operation < stats.Endpoints[j].Operation
+	})
+	return stats
+}
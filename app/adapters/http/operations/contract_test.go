@@ -0,0 +1,64 @@
+package operations
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	httpConfig "abc-runner/app/adapters/http/config"
+	"abc-runner/app/adapters/http/connection"
+)
+
+func TestContractTracker(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.json")
+	spec := `{"swagger": "2.0", "info": {"title": "Users"}, "basePath": "/api", "paths": {"/users/{id}": {"get": {
+		"operationId": "getUser",
+		"responses": {"200": {"schema": {"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}}}}}}}`
+	if err := os.WriteFile(path, []byte(spec), 0644); err != nil {
+		t.Fatalf("failed to write spec: %v", err)
+	}
+	contract, err := httpConfig.LoadOpenAPIContract(path)
+	if err != nil {
+		t.Fatalf("failed to load contract: %v", err)
+	}
+
+	header := http.Header{"Content-Type": []string{"application/json"}}
+	valid := &connection.HttpResponse{StatusCode: 200, Headers: header, Body: []byte(`{"name":"ann"}`)}
+	missing := &connection.HttpResponse{StatusCode: 200, Headers: header, Body: []byte(`{}`)}
+
+	// 抽样比例0.5时每两个响应校验一个
+	tracker := NewContractTracker(contract, 0.5)
+	for i := 0; i < 8; i++ {
+		response := valid
+		if i%4 == 1 {
+			response = missing
+		}
+		tracker.Check("GET", "/api/users/"+string(rune('0'+i)), response)
+	}
+	tracker.Check("GET", "/api/users/9", nil) // 未得到响应的请求不参与抽样
+	for i := 0; i < 2; i++ {
+		tracker.Check("POST", "/api/users/9", valid)
+	}
+	for i := 0; i < 2; i++ {
+		tracker.Check("GET", "/api/users/9", &connection.HttpResponse{StatusCode: 503, Headers: header})
+	}
+
+	stats := tracker.Stats()
+	if stats.Sampled != 6 || stats.Conforming != 2 || stats.Violating != 4 || stats.SampleRate != 0.5 {
+		t.Fatalf("unexpected totals: %+v", stats)
+	}
+	if len(stats.Endpoints) != 2 || stats.Endpoints[0].Operation != httpConfig.ContractUndocumented || stats.Endpoints[1].Operation != "getUser" {
+		t.Fatalf("unexpected endpoints: %+v", stats.Endpoints)
+	}
+	user := stats.Endpoints[1]
+	if user.Sampled != 5 || user.Violating != 3 || len(user.Violations) != 2 {
+		t.Fatalf("unexpected getUser stats: %+v", user)
+	}
+	if user.Violations[0].Violation != `$: missing required property "name"` || user.Violations[0].Count != 2 || user.Violations[0].Sample != "GET /api/users/1" {
+		t.Errorf("unexpected violation: %+v", user.Violations[0])
+	}
+
+	var disabled *ContractTracker
+	disabled.Check("GET", "/api/users/1", valid)
+}
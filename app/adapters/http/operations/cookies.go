@@ -0,0 +1,112 @@
+package operations
+
+import (
+	"context"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"sync"
+	"sync/atomic"
+
+	httpConfig "abc-runner/app/adapters/http/config"
+	"abc-runner/app/core/execution"
+)
+
+// sharedJarKey 共享范围及无法确定工作协程时使用的cookie jar
+const sharedJarKey = -1
+
+// CookieStats cookie会话统计
+type CookieStats struct {
+	Scope               string `json:"scope"`                 // cookie jar范围：worker或shared
+	Jars                int    `json:"jars"`                  // 创建的cookie jar数（即保持会话的虚拟用户数）
+	CookiesReceived     int64  `json:"cookies_received"`      // 响应中收到的Set-Cookie数
+	RequestsWithCookies int64  `json:"requests_with_cookies"` // 携带了cookie的请求数
+}
+
+// ToMap 转换为报告使用的map
+func (s CookieStats) ToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"scope":                 s.Scope,
+		"jars":                  s.Jars,
+		"cookies_received":      s.CookiesReceived,
+		"requests_with_cookies": s.RequestsWithCookies,
+	}
+}
+
+// CookieJars 按虚拟用户管理的cookie jar
+// worker范围下每个工作协程使用独立的cookie jar，其顺序执行的操作共享会话；shared范围下所有虚拟用户共享一个cookie jar
+type CookieJars struct {
+	scope string
+
+	mutex sync.Mutex
+	jars  map[int]*countingJar
+
+	received atomic.Int64
+	sent     atomic.Int64
+}
+
+// NewCookieJars 创建cookie jar管理器
+func NewCookieJars(config httpConfig.HttpCookieConfig) *CookieJars {
+	scope := config.Scope
+	if scope == "" {
+		scope = httpConfig.CookieScopeWorker
+	}
+	return &CookieJars{
+		scope: scope,
+		jars:  make(map[int]*countingJar),
+	}
+}
+
+// Jar 获取执行当前操作的虚拟用户的cookie jar
+func (c *CookieJars) Jar(ctx context.Context) http.CookieJar {
+	key := sharedJarKey
+	if c.scope == httpConfig.CookieScopeWorker {
+		if workerID, ok := execution.WorkerIDFromContext(ctx); ok {
+			key = workerID
+		}
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	jar, ok := c.jars[key]
+	if !ok {
+		// 未设置公共后缀列表时，cookie仅按请求的主机名匹配，测试环境的内部域名与IP地址均可使用
+		inner, _ := cookiejar.New(nil)
+		jar = &countingJar{Jar: inner, owner: c}
+		c.jars[key] = jar
+	}
+	return jar
+}
+
+// Stats 获取cookie会话统计
+func (c *CookieJars) Stats() CookieStats {
+	c.mutex.Lock()
+	jars := len(c.jars)
+	c.mutex.Unlock()
+
+	return CookieStats{
+		Scope:               c.scope,
+		Jars:                jars,
+		CookiesReceived:     c.received.Load(),
+		RequestsWithCookies: c.sent.Load(),
+	}
+}
+
+// countingJar 统计收发cookie的cookie jar
+type countingJar struct {
+	*cookiejar.Jar
+	owner *CookieJars
+}
+
+func (j *countingJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.owner.received.Add(int64(len(cookies)))
+	j.Jar.SetCookies(u, cookies)
+}
+
+func (j *countingJar) Cookies(u *url.URL) []*http.Cookie {
+	cookies := j.Jar.Cookies(u)
+	if len(cookies) > 0 {
+		j.owner.sent.Add(1)
+	}
+	return cookies
+}
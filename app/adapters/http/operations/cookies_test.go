@@ -0,0 +1,84 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	httpConfig "abc-runner/app/adapters/http/config"
+	"abc-runner/app/adapters/http/connection"
+	"abc-runner/app/core/execution"
+)
+
+func TestCookieJars(t *testing.T) {
+	var logins atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session, err := r.Cookie("session")
+		switch r.URL.Path {
+		case "/login":
+			// 已登录的会话直接进入首页，否则登录后重定向，cookie随重定向响应下发
+			if err != nil {
+				http.SetCookie(w, &http.Cookie{Name: "session", Value: fmt.Sprintf("s%d", logins.Add(1)), Path: "/"})
+			}
+			http.Redirect(w, r, "/home", http.StatusFound)
+		case "/home":
+			if err != nil {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, session.Value)
+		}
+	}))
+	defer server.Close()
+
+	run := func(cookies httpConfig.HttpCookieConfig) (*HttpExecutor, int) {
+		logins.Store(0)
+		config := httpConfig.LoadDefaultHttpConfig()
+		config.Connection.BaseURL = server.URL
+		config.Benchmark.TestCase = "weighted"
+		config.Benchmark.Cookies = cookies
+		config.Requests = []httpConfig.HttpRequestConfig{{Method: "GET", Path: "/login", Weight: 1}}
+		pool, err := connection.NewHttpConnectionPool(config)
+		if err != nil {
+			t.Fatalf("failed to create pool: %v", err)
+		}
+		defer pool.Close()
+
+		executor := NewHttpExecutor(pool, config, nil)
+		factory := NewHttpOperationFactory(config)
+		failed := 0
+		for jobID := 0; jobID < 6; jobID++ {
+			ctx := execution.WithWorkerID(context.Background(), jobID%2)
+			result, _ := executor.ExecuteOperation(ctx, factory.CreateOperation(jobID, nil))
+			if !result.Success {
+				failed++
+			}
+		}
+		return executor, failed
+	}
+
+	// 两个虚拟用户各登录一次，之后的请求沿用各自的会话
+	executor, failed := run(httpConfig.HttpCookieConfig{Enabled: true, Scope: httpConfig.CookieScopeWorker})
+	stats, ok := executor.CookieStats()
+	if failed != 0 || logins.Load() != 2 || !ok || stats.Jars != 2 || stats.CookiesReceived != 2 || stats.RequestsWithCookies != 10 {
+		t.Errorf("worker scope: failed %d, logins %d, stats %+v", failed, logins.Load(), stats)
+	}
+
+	executor, failed = run(httpConfig.HttpCookieConfig{Enabled: true, Scope: httpConfig.CookieScopeShared})
+	if stats, _ := executor.CookieStats(); failed != 0 || logins.Load() != 1 || stats.Jars != 1 {
+		t.Errorf("shared scope: failed %d, logins %d, stats %+v", failed, logins.Load(), stats)
+	}
+
+	// 未启用cookie时每次重定向后的首页请求都未携带会话
+	executor, failed = run(httpConfig.HttpCookieConfig{Scope: httpConfig.CookieScopeWorker})
+	if _, ok := executor.CookieStats(); failed != 6 || logins.Load() != 6 || ok {
+		t.Errorf("cookies disabled: failed %d, logins %d", failed, logins.Load())
+	}
+
+	if err := (httpConfig.HttpCookieConfig{Enabled: true, Scope: "user"}).Validate(); err == nil {
+		t.Errorf("expected an unknown scope to be rejected")
+	}
+}
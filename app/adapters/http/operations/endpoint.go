@@ -0,0 +1,125 @@
+package operations
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	httpConfig "abc-runner/app/adapters/http/config"
+	"abc-runner/app/core/metrics"
+)
+
+// EndpointStats 多接口加权测试中单个请求模板的统计
+type EndpointStats struct {
+	Name        string                 `json:"name"`
+	Method      string                 `json:"method"`
+	Path        string                 `json:"path"`
+	Weight      int                    `json:"weight"`
+	Requests    int64                  `json:"requests"`
+	Errors      int64                  `json:"errors"`       // 请求失败或状态码非2xx的请求数
+	StatusCodes map[int]int64          `json:"status_codes"` // 按状态码统计的响应数，0表示请求未得到响应
+	Bytes       int64                  `json:"bytes"`        // 响应体字节数
+	Latency     metrics.LatencyMetrics `json:"latency"`
+}
+
+// ToMap 转换为报告使用的map
+func (s EndpointStats) ToMap() map[string]interface{} {
+	codes := make(map[string]interface{}, len(s.StatusCodes))
+	for code, count := range s.StatusCodes {
+		codes[strconv.Itoa(code)] = count
+	}
+	return map[string]interface{}{
+		"name":         s.Name,
+		"method":       s.Method,
+		"path":         s.Path,
+		"weight":       s.Weight,
+		"requests":     s.Requests,
+		"errors":       s.Errors,
+		"status_codes": codes,
+		"bytes":        s.Bytes,
+		"latency":      latencyToMap(s.Latency),
+	}
+}
+
+// endpointCounter 单个请求模板的计数
+type endpointCounter struct {
+	stats   EndpointStats
+	latency *metrics.LatencyTracker
+}
+
+// EndpointTracker 按请求模板统计延迟与状态码
+type EndpointTracker struct {
+	mutex     sync.Mutex
+	order     []string
+	endpoints map[string]*endpointCounter
+}
+
+// NewEndpointTracker 创建接口统计器，按配置顺序登记请求模板
+func NewEndpointTracker(requests []httpConfig.HttpRequestConfig) *EndpointTracker {
+	t := &EndpointTracker{endpoints: make(map[string]*endpointCounter)}
+	for _, req := range requests {
+		name := EndpointName(req)
+		if counter, ok := t.endpoints[name]; ok {
+			counter.stats.Weight += req.Weight
+			continue
+		}
+		t.order = append(t.order, name)
+		t.endpoints[name] = &endpointCounter{
+			stats: EndpointStats{
+				Name:        name,
+				Method:      req.Method,
+				Path:        req.Path,
+				Weight:      req.Weight,
+				StatusCodes: make(map[int]int64),
+			},
+			latency: metrics.NewLatencyTracker(metrics.LatencyConfig{SamplingRate: 1.0}),
+		}
+	}
+	return t
+}
+
+// Record 记录一次请求结果，statusCode为0表示请求未得到响应
+func (t *EndpointTracker) Record(name string, statusCode int, bytes int, latency time.Duration, success bool) {
+	t.mutex.Lock()
+	counter, ok := t.endpoints[name]
+	if !ok {
+		t.mutex.Unlock()
+		return
+	}
+	counter.stats.Requests++
+	if !success {
+		counter.stats.Errors++
+	}
+	counter.stats.StatusCodes[statusCode]++
+	counter.stats.Bytes += int64(bytes)
+	t.mutex.Unlock()
+
+	counter.latency.Record(latency)
+}
+
+// Stats 按配置顺序获取各请求模板的统计
+func (t *EndpointTracker) Stats() []EndpointStats {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	stats := make([]EndpointStats, 0, len(t.order))
+	for _, name := range t.order {
+		counter := t.endpoints[name]
+		endpoint := counter.stats
+		endpoint.StatusCodes = make(map[int]int64, len(counter.stats.StatusCodes))
+		for code, count := range counter.stats.StatusCodes {
+			endpoint.StatusCodes[code] = count
+		}
+		endpoint.Latency = counter.latency.GetMetrics()
+		stats = append(stats, endpoint)
+	}
+	return stats
+}
+
+// EndpointName 请求模板的名称：配置的name，未配置时为方法加归一化路径
+func EndpointName(req httpConfig.HttpRequestConfig) string {
+	if req.Name != "" {
+		return req.Name
+	}
+	return OperationName(req.Method, req.Path)
+}
@@ -0,0 +1,102 @@
+package operations
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	httpConfig "abc-runner/app/adapters/http/config"
+	"abc-runner/app/adapters/http/connection"
+)
+
+func TestWeightedEndpoints(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.URL.Path == "/orders" && r.Method == http.MethodPost && string(body) == `{"item":"book"}`:
+			w.WriteHeader(http.StatusCreated)
+		case r.URL.Path == "/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		case r.Header.Get("Accept") != "application/json":
+			w.WriteHeader(http.StatusNotAcceptable)
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "http.yaml")
+	os.WriteFile(path, []byte(`http:
+  connection:
+    base_url: `+server.URL+`
+  requests:
+    - name: list users
+      method: GET
+      path: /users
+      headers:
+        Accept: application/json
+      weight: 6
+    - method: GET
+      path: /users/42
+      headers:
+        Accept: application/json
+      weight: 3
+    - method: POST
+      path: /orders
+      body:
+        item: book
+      weight: 1
+    - method: GET
+      path: /broken
+      weight: 0
+`), 0644)
+	config, err := httpConfig.LoadHttpConfigFile(path)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	config.Benchmark.TestCase = "weighted"
+	if err := config.Validate(); err != nil {
+		t.Fatalf("invalid config: %v", err)
+	}
+	if config.Connection.Timeout == 0 {
+		t.Errorf("expected defaults to be kept for fields missing from the file")
+	}
+
+	pool, err := connection.NewHttpConnectionPool(config)
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	defer pool.Close()
+	executor := NewHttpExecutor(pool, config, nil)
+	factory := NewHttpOperationFactory(config)
+
+	for jobID := 0; jobID < 500; jobID++ {
+		op := factory.CreateOperation(jobID, nil)
+		result, _ := executor.ExecuteOperation(context.Background(), op)
+		if !result.Success {
+			t.Fatalf("%s failed: %v", op.Metadata["operation_type"], result.Metadata["response_status"])
+		}
+	}
+
+	stats := executor.EndpointStats()
+	if len(stats) != 4 || stats[0].Name != "list users" || stats[1].Name != "GET /users/{id}" || stats[2].Name != "POST /orders" {
+		t.Fatalf("unexpected endpoints: %+v", stats)
+	}
+	if stats[3].Requests != 0 {
+		t.Errorf("zero-weight template was executed %d times", stats[3].Requests)
+	}
+	// 500个请求按6:3:1分配
+	for i, want := range []int64{300, 150, 50} {
+		if got := stats[i].Requests; got < want*8/10 || got > want*12/10 {
+			t.Errorf("%s: %d requests, want about %d", stats[i].Name, got, want)
+		}
+	}
+	if stats[2].StatusCodes[http.StatusCreated] != stats[2].Requests || stats[0].StatusCodes[http.StatusOK] != stats[0].Requests {
+		t.Errorf("unexpected status codes: %v %v", stats[0].StatusCodes, stats[2].StatusCodes)
+	}
+	if stats[0].Latency.P50 <= 0 {
+		t.Errorf("expected latency percentiles, got %+v", stats[0].Latency)
+	}
+}
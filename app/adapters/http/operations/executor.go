@@ -2,12 +2,20 @@ package operations
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	httpConfig "abc-runner/app/adapters/http/config"
 	"abc-runner/app/adapters/http/connection"
 	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
 )
 
 // HttpExecutor HTTP操作执行器
@@ -15,6 +23,20 @@ type HttpExecutor struct {
 	pool             *connection.HTTPConnectionPool
 	config           *httpConfig.HttpAdapterConfig
 	metricsCollector interfaces.DefaultMetricsCollector
+	cacheTracker     *CacheTracker
+	pageTracker      *PageLoadTracker
+	streamTracker    *StreamTracker
+	endpointTracker  *EndpointTracker
+	scenario         *ScenarioRunner
+	graphQLTracker   *GraphQLTracker
+	profiler         *WorkloadProfiler
+	webhook          *WebhookReceiver
+	validation       *ValidationTracker
+	assertions       map[string][]responseAssertion // 各请求模板自身的断言，按模板名称索引
+	tokens           *connection.TokenSource        // OAuth2令牌来源
+	cookies          *CookieJars                    // 虚拟用户的cookie jar（cookies.enabled）
+	contract         *ContractTracker               // API契约漂移检测（contract.spec）
+	phases           *metrics.PhaseTracker          // 单请求测试用例的按阶段延迟
 }
 
 // NewHttpExecutor 创建HTTP操作执行器
@@ -23,11 +45,129 @@ func NewHttpExecutor(
 	config *httpConfig.HttpAdapterConfig,
 	metricsCollector interfaces.DefaultMetricsCollector,
 ) *HttpExecutor {
-	return &HttpExecutor{
+	executor := &HttpExecutor{
 		pool:             pool,
 		config:           config,
 		metricsCollector: metricsCollector,
+		cacheTracker:     NewCacheTracker(),
+		pageTracker:      NewPageLoadTracker(),
+		streamTracker:    NewStreamTracker(),
+		phases:           metrics.NewPhaseTracker(),
+		endpointTracker:  NewEndpointTracker(config.Requests),
+		validation:       NewValidationTracker(config.Benchmark.Assert),
+		assertions:       make(map[string][]responseAssertion),
 	}
+	for _, req := range config.Requests {
+		if len(req.Assert) > 0 {
+			name := EndpointName(req)
+			executor.assertions[name] = append(executor.assertions[name], compileAssertions(req.Assert)...)
+		}
+	}
+	if config.Benchmark.TestCase == "scenario" {
+		executor.scenario = NewScenarioRunner(config.Benchmark.Scenario, executor.validation)
+	}
+	if config.Benchmark.TestCase == "graphql" {
+		executor.graphQLTracker = NewGraphQLTracker(config.Benchmark.GraphQL)
+	}
+	if config.Benchmark.TestCase == "replay" {
+		executor.profiler = NewWorkloadProfiler()
+	}
+	if config.Benchmark.Cookies.Enabled {
+		executor.cookies = NewCookieJars(config.Benchmark.Cookies)
+	}
+	return executor
+}
+
+// CacheStats 获取缓存命中统计（仅统计cache_mix测试用例的请求）
+func (h *HttpExecutor) CacheStats() CacheStats {
+	return h.cacheTracker.Stats()
+}
+
+// PageLoadStats 获取页面加载统计（仅统计page_load测试用例的页面）
+func (h *HttpExecutor) PageLoadStats() PageLoadStats {
+	return h.pageTracker.Stats()
+}
+
+// StreamStats 获取流式响应统计（仅统计stream测试用例的流）
+func (h *HttpExecutor) StreamStats() StreamStats {
+	return h.streamTracker.Stats()
+}
+
+// LatencyPhases 获取按阶段的延迟统计（DNS、建连、TLS、写请求、首字节、传输），
+// 页面加载、流式响应等组合操作不计入
+func (h *HttpExecutor) LatencyPhases() []metrics.PhaseLatency {
+	return h.phases.Breakdown()
+}
+
+// EndpointStats 获取各请求模板的统计（仅统计weighted与replay测试用例的请求）
+func (h *HttpExecutor) EndpointStats() []EndpointStats {
+	return h.endpointTracker.Stats()
+}
+
+// ScenarioStepStats 获取请求链场景各步骤的统计（仅scenario测试用例）
+func (h *HttpExecutor) ScenarioStepStats() []EndpointStats {
+	if h.scenario == nil {
+		return nil
+	}
+	return h.scenario.StepStats()
+}
+
+// GraphQLStats 获取各GraphQL操作的统计（仅graphql测试用例）
+func (h *HttpExecutor) GraphQLStats() []GraphQLOperationStats {
+	if h.graphQLTracker == nil {
+		return nil
+	}
+	return h.graphQLTracker.Stats()
+}
+
+// WorkloadProfile 获取实际回放的负载特征（仅replay测试用例），未执行请求时返回nil
+func (h *HttpExecutor) WorkloadProfile() *httpConfig.HttpWorkloadProfile {
+	if h.profiler == nil {
+		return nil
+	}
+	return h.profiler.Profile()
+}
+
+// ValidationStats 获取响应断言统计，未配置任何断言时ok为false
+func (h *HttpExecutor) ValidationStats() (ValidationStats, bool) {
+	stats := h.validation.Stats()
+	return stats, stats.Requests > 0
+}
+
+// CookieStats 获取cookie会话统计，未启用cookie时ok为false
+func (h *HttpExecutor) CookieStats() (CookieStats, bool) {
+	if h.cookies == nil {
+		return CookieStats{}, false
+	}
+	return h.cookies.Stats(), true
+}
+
+// ContractStats 获取API契约校验统计，未指定契约文档时ok为false
+func (h *HttpExecutor) ContractStats() (ContractStats, bool) {
+	if h.contract == nil {
+		return ContractStats{}, false
+	}
+	return h.contract.Stats(), true
+}
+
+// LoadContract 加载用于抽样校验响应的OpenAPI文档
+func (h *HttpExecutor) LoadContract(spec string) error {
+	contract, err := httpConfig.LoadOpenAPIContract(spec)
+	if err != nil {
+		return fmt.Errorf("failed to load API contract: %w", err)
+	}
+	h.contract = NewContractTracker(contract, h.config.Benchmark.Contract.GetSampleRate())
+	return nil
+}
+
+// SetTokenSource 设置OAuth2认证使用的令牌来源，令牌端点的耗时不计入操作耗时
+func (h *HttpExecutor) SetTokenSource(tokens *connection.TokenSource) {
+	h.tokens = tokens
+}
+
+// SetWebhookReceiver 设置异步回调测试使用的回调接收端
+func (h *HttpExecutor) SetWebhookReceiver(receiver *WebhookReceiver) {
+	h.webhook = receiver
 }
 
 // ExecuteOperation 执行HTTP操作
@@ -57,12 +197,51 @@ func (h *HttpExecutor) ExecuteOperation(ctx context.Context, operation interface
 	}
 	// HTTPConnectionPool不需要显式返回客户端
 
+	// 使用执行该操作的虚拟用户的cookie jar，客户端副本共享连接池
+	if h.cookies != nil {
+		session := *client
+		session.Jar = h.cookies.Jar(ctx)
+		client = &session
+	}
+
 	// 创建HTTP客户端封装
 	httpClient := connection.NewHttpClient(client, h.config, h.pool)
+	httpClient.SetTokenSource(h.tokens)
+
+	// 页面加载测试：文档及其子资源作为一个操作
+	if operation.Type == "http_page_load" {
+		return h.executePageLoad(ctx, operation, reqConfig, httpClient, startTime)
+	}
+
+	// 异步回调测试：提交任务并等待回调
+	if operation.Type == "http_webhook" {
+		return h.executeWebhook(ctx, operation, reqConfig, httpClient, startTime)
+	}
+
+	// 流式响应测试：一个流从打开到关闭作为一个操作
+	if operation.Type == "http_stream" {
+		return h.executeStream(ctx, operation, reqConfig, httpClient, startTime)
+	}
+
+	// 请求链场景：整个用户旅程作为一个操作
+	if operation.Type == "http_scenario" {
+		return h.executeScenario(ctx, operation, httpClient, startTime)
+	}
+
+	// GraphQL测试：errors数组非空的响应计为失败
+	if operation.Type == "http_graphql" {
+		return h.executeGraphQL(ctx, operation, reqConfig, httpClient, startTime)
+	}
 
 	// 执行HTTP请求
+	if h.profiler != nil {
+		h.profiler.Arrive()
+	}
 	response, err := httpClient.ExecuteRequest(ctx, reqConfig)
-	duration := time.Since(startTime)
+	duration := time.Since(startTime) - httpClient.AuthWait()
+	if err == nil && response != nil {
+		h.phases.Record(response.Phases)
+	}
 
 	// 构建操作结果
 	result := &interfaces.OperationResult{
@@ -72,17 +251,61 @@ func (h *HttpExecutor) ExecuteOperation(ctx context.Context, operation interface
 		Value:    h.createResultValue(response),
 		Metadata: h.createResultMetadata(operation, response),
 	}
+	if response != nil {
+		result.BytesRead, result.BytesWritten = int64(len(response.Body)), response.RequestSize
+	}
 
 	if err != nil {
 		result.Error = err
 		result.Success = false
 	}
 
+	// 响应断言：配置了断言时由断言判定成功与否，未通过的计为validation_failed，与传输错误、状态码错误区分
+	endpoint, weighted := operation.Params["endpoint"].(string)
+	validated := false
+	if own := h.assertions[endpoint]; h.validation.Applies(own) {
+		passed, validationErr := h.validation.Validate(reqConfig.Method+" "+reqConfig.Path, own, response, err)
+		validated, result.Success = true, passed
+		if validationErr != nil {
+			result.Error = validationErr
+			result.Metadata["error_category"] = ErrorCategoryValidation
+		}
+	}
+
+	// API契约漂移检测：抽样校验响应，不影响请求结果
+	h.contract.Check(reqConfig.Method, reqConfig.Path, response)
+
+	// 多接口加权测试：按请求模板统计
+	if weighted {
+		statusCode, size := 0, 0
+		if response != nil {
+			statusCode, size = response.StatusCode, len(response.Body)
+		}
+		h.endpointTracker.Record(endpoint, statusCode, size, duration, result.Success)
+		if h.profiler != nil {
+			h.profiler.Record(OperationName(reqConfig.Method, reqConfig.Path), reqConfig.Path, requestBodySize(reqConfig.Body), int64(size))
+		}
+	}
+
+	// 缓存服务器测试：解析缓存状态
+	cacheStatus := ""
+	if cacheable, ok := operation.Params["cacheable"].(bool); ok && response != nil && response.Error == nil {
+		status, age := ParseCacheStatus(response.Headers)
+		h.cacheTracker.Record(cacheable, status, age, len(response.Body), duration)
+		cacheStatus = string(status)
+		result.Metadata["cache_status"] = cacheStatus
+		result.Metadata["cacheable"] = cacheable
+	}
+
 	// 记录HTTP特定指标
 	if response != nil && h.metricsCollector != nil {
 		// 使用核心接口记录指标，通过metadata传递HTTP特定信息
+		success := response.StatusCode >= 200 && response.StatusCode < 300
+		if validated {
+			success = result.Success
+		}
 		operationResult := &interfaces.OperationResult{
-			Success:  response.StatusCode >= 200 && response.StatusCode < 300,
+			Success:  success,
 			IsRead:   h.isReadOperation(operation.Type),
 			Duration: duration,
 			Metadata: map[string]interface{}{
@@ -91,6 +314,12 @@ func (h *HttpExecutor) ExecuteOperation(ctx context.Context, operation interface
 				"url":         reqConfig.Path,
 			},
 		}
+		if cacheStatus != "" {
+			operationResult.Metadata["cache_status"] = cacheStatus
+		}
+		if category, ok := result.Metadata["error_category"]; ok {
+			operationResult.Metadata["error_category"] = category
+		}
 		h.metricsCollector.Record(operationResult)
 	}
 
@@ -159,7 +388,7 @@ func (h *HttpExecutor) extractRequestConfig(operation interfaces.Operation) (htt
 
 // isReadOperation 判断是否为读操作
 func (h *HttpExecutor) isReadOperation(operationType string) bool {
-	readMethods := []string{"http_get", "http_head", "http_options"}
+	readMethods := []string{"http_get", "http_head", "http_options", "http_page_load"}
 	for _, method := range readMethods {
 		if operationType == method {
 			return true
@@ -341,3 +570,486 @@ func (h *HttpExecutor) ExecuteUploadOperation(ctx context.Context, operation int
 
 	return h.ExecuteOperation(ctx, operation)
 }
+
+// executePageLoad 模拟浏览器加载页面：请求文档后按配置的并行度加载其引用的子资源
+// 操作耗时为完整的页面加载时间，文档或任一子资源失败时页面视为失败
+func (h *HttpExecutor) executePageLoad(ctx context.Context, operation interfaces.Operation, reqConfig httpConfig.HttpRequestConfig, httpClient *connection.HttpClient, startTime time.Time) (*interfaces.OperationResult, error) {
+	pageConfig := h.config.Benchmark.Page
+
+	response, err := httpClient.ExecuteRequest(ctx, reqConfig)
+	documentOK := err == nil && response != nil && response.IsSuccess()
+	if response != nil {
+		h.pageTracker.RecordDocument(response.Duration, len(response.Body))
+	}
+
+	var assets []string
+	if documentOK {
+		assets = h.pageAssets(reqConfig.Path, response)
+	}
+
+	var failedAssets, assetBytes int64
+	if len(assets) > 0 {
+		headers := map[string]string{"Accept": "*/*"}
+		if userAgent, ok := reqConfig.Headers["User-Agent"]; ok {
+			headers["User-Agent"] = userAgent
+		}
+
+		var wg sync.WaitGroup
+		var mutex sync.Mutex
+		slots := make(chan struct{}, pageConfig.Parallelism)
+		for _, asset := range assets {
+			wg.Add(1)
+			slots <- struct{}{}
+			go func(asset string) {
+				defer wg.Done()
+				defer func() { <-slots }()
+
+				assetStart := time.Now()
+				assetResponse, assetErr := httpClient.ExecuteRequest(ctx, httpConfig.HttpRequestConfig{
+					Method:  "GET",
+					Path:    asset,
+					Headers: headers,
+				})
+				success := assetErr == nil && assetResponse != nil && assetResponse.IsSuccess()
+				size := 0
+				if assetResponse != nil {
+					size = len(assetResponse.Body)
+				}
+				h.pageTracker.RecordAsset(success, time.Since(assetStart), size)
+
+				mutex.Lock()
+				if !success {
+					failedAssets++
+				}
+				assetBytes += int64(size)
+				mutex.Unlock()
+			}(asset)
+		}
+		wg.Wait()
+	}
+
+	loadTime := time.Since(startTime)
+	if response != nil {
+		loadTime -= response.AuthDuration
+	}
+	success := documentOK && failedAssets == 0
+	h.pageTracker.RecordPage(success, loadTime)
+
+	result := &interfaces.OperationResult{
+		Success:  success,
+		Duration: loadTime,
+		IsRead:   true,
+		Metadata: h.createResultMetadata(operation, response),
+	}
+	result.Metadata["assets"] = len(assets)
+	result.Metadata["failed_assets"] = failedAssets
+	if response != nil {
+		result.Value = map[string]interface{}{
+			"status_code":   response.StatusCode,
+			"assets":        len(assets),
+			"failed_assets": failedAssets,
+			"bytes":         int64(len(response.Body)) + assetBytes,
+			"load_time":     loadTime,
+		}
+	}
+	if err != nil {
+		result.Error = err
+	} else if failedAssets > 0 {
+		result.Error = fmt.Errorf("%d of %d assets failed to load", failedAssets, len(assets))
+	}
+
+	// 记录HTTP特定指标
+	if response != nil && h.metricsCollector != nil {
+		h.metricsCollector.Record(&interfaces.OperationResult{
+			Success:  success,
+			IsRead:   true,
+			Duration: loadTime,
+			Metadata: map[string]interface{}{
+				"status_code": response.StatusCode,
+				"method":      reqConfig.Method,
+				"url":         reqConfig.Path,
+				"assets":      len(assets),
+			},
+		})
+	}
+
+	return result, err
+}
+
+// executeWebhook 携带关联ID与回调地址提交任务，等待接收端收到对应回调
+// 操作耗时为开始提交到回调到达的时间，提交失败或超时未收到回调时操作失败
+func (h *HttpExecutor) executeWebhook(ctx context.Context, operation interfaces.Operation, reqConfig httpConfig.HttpRequestConfig, httpClient *connection.HttpClient, startTime time.Time) (*interfaces.OperationResult, error) {
+	result := &interfaces.OperationResult{
+		Metadata: h.createResultMetadata(operation, nil),
+	}
+	if h.webhook == nil {
+		result.Error = fmt.Errorf("webhook receiver is not running")
+		result.Duration = time.Since(startTime)
+		return result, result.Error
+	}
+
+	webhookConfig := h.config.Benchmark.Webhook
+	id, _ := operation.Params["correlation_id"].(string)
+	callbackURL := h.webhook.CallbackURL()
+
+	headers := make(map[string]string, len(reqConfig.Headers)+2)
+	for k, v := range reqConfig.Headers {
+		headers[k] = v
+	}
+	if webhookConfig.IDHeader != "" {
+		headers[webhookConfig.IDHeader] = id
+	}
+	headers["X-Callback-URL"] = callbackURL
+	reqConfig.Headers = headers
+
+	// 模板渲染的JSON对象请求体同样注入关联ID
+	if raw, ok := reqConfig.Body.(json.RawMessage); ok {
+		var object map[string]interface{}
+		if json.Unmarshal(raw, &object) == nil {
+			reqConfig.Body = object
+		}
+	}
+	if body, ok := reqConfig.Body.(map[string]interface{}); ok {
+		submitBody := make(map[string]interface{}, len(body)+2)
+		for k, v := range body {
+			submitBody[k] = v
+		}
+		submitBody["correlation_id"] = id
+		submitBody["callback_url"] = callbackURL
+		reqConfig.Body = submitBody
+	}
+
+	// 先登记再提交，回调可能先于提交响应到达
+	arrived := h.webhook.Expect(id)
+	response, err := httpClient.ExecuteRequest(ctx, reqConfig)
+	ackTime := time.Now()
+	startTime = startTime.Add(httpClient.AuthWait())
+	submitted := err == nil && response != nil && response.IsSuccess()
+	h.webhook.RecordSubmit(submitted, ackTime.Sub(startTime))
+	result.Metadata = h.createResultMetadata(operation, response)
+	result.Metadata["correlation_id"] = id
+
+	if !submitted {
+		h.webhook.Forget(id, false)
+		result.Duration = ackTime.Sub(startTime)
+		if err == nil {
+			err = fmt.Errorf("job submission failed with status %d", response.StatusCode)
+		}
+	} else {
+		timer := time.NewTimer(webhookConfig.Timeout)
+		defer timer.Stop()
+		var arrivedAt time.Time
+		select {
+		case arrivedAt = <-arrived:
+		case <-timer.C:
+		case <-ctx.Done():
+		}
+		// 超时与回调同时发生时以回调为准
+		if arrivedAt.IsZero() && !h.webhook.Forget(id, true) {
+			arrivedAt = <-arrived
+		}
+
+		switch {
+		case !arrivedAt.IsZero():
+			result.Success = true
+			result.Duration = arrivedAt.Sub(startTime)
+			h.webhook.RecordCallback(result.Duration, arrivedAt.Sub(ackTime))
+			result.Metadata["callback_delay"] = arrivedAt.Sub(ackTime)
+			result.Value = map[string]interface{}{
+				"status_code":    response.StatusCode,
+				"correlation_id": id,
+				"end_to_end":     result.Duration,
+			}
+		case ctx.Err() != nil:
+			result.Duration = time.Since(startTime)
+			err = ctx.Err()
+		default:
+			result.Duration = time.Since(startTime)
+			h.webhook.RecordTimeout()
+			err = fmt.Errorf("no callback for %s within %v", id, webhookConfig.Timeout)
+		}
+	}
+	result.Error = err
+
+	// 记录HTTP特定指标
+	if response != nil && h.metricsCollector != nil {
+		h.metricsCollector.Record(&interfaces.OperationResult{
+			Success:  result.Success,
+			Duration: result.Duration,
+			Metadata: map[string]interface{}{
+				"status_code": response.StatusCode,
+				"method":      reqConfig.Method,
+				"url":         reqConfig.Path,
+			},
+		})
+	}
+
+	return result, err
+}
+
+// executeScenario 按顺序执行场景的全部步骤，前序响应中提取的变量注入后续请求
+// 操作耗时为整个旅程的时间，任一步骤失败时操作失败，各步骤的延迟与状态码单独统计
+func (h *HttpExecutor) executeScenario(ctx context.Context, operation interfaces.Operation, httpClient *connection.HttpClient, startTime time.Time) (*interfaces.OperationResult, error) {
+	if h.scenario == nil {
+		err := fmt.Errorf("scenario is not configured")
+		return &interfaces.OperationResult{Duration: time.Since(startTime), Error: err}, err
+	}
+
+	jobID, _ := operation.Params["job_id"].(int)
+	completed, response, err := h.scenario.Run(ctx, httpClient, jobID)
+	result := &interfaces.OperationResult{
+		Success:  err == nil,
+		Duration: time.Since(startTime) - httpClient.AuthWait(),
+		Error:    err,
+		Metadata: h.createResultMetadata(operation, response),
+	}
+	result.Metadata["steps"] = len(h.scenario.steps)
+	result.Metadata["steps_completed"] = completed
+
+	// 记录HTTP特定指标
+	if response != nil && h.metricsCollector != nil {
+		h.metricsCollector.Record(&interfaces.OperationResult{
+			Success:  result.Success,
+			Duration: result.Duration,
+			Metadata: map[string]interface{}{
+				"status_code":     response.StatusCode,
+				"method":          "SCENARIO",
+				"steps_completed": completed,
+			},
+		})
+	}
+
+	return result, err
+}
+
+// executeGraphQL 执行一次GraphQL请求
+// 请求失败、状态码非2xx、响应不是GraphQL响应或errors数组非空均为失败，后者即使状态码为200也计为graphql_error
+func (h *HttpExecutor) executeGraphQL(ctx context.Context, operation interfaces.Operation, reqConfig httpConfig.HttpRequestConfig, httpClient *connection.HttpClient, startTime time.Time) (*interfaces.OperationResult, error) {
+	name, _ := operation.Params["graphql_operation"].(string)
+	isRead := operation.Params["graphql_type"] == "query"
+	response, err := httpClient.ExecuteRequest(ctx, reqConfig)
+	duration := time.Since(startTime) - httpClient.AuthWait()
+
+	result := &interfaces.OperationResult{
+		Success:  err == nil && response != nil && response.IsSuccess(),
+		Duration: duration,
+		IsRead:   isRead,
+		Value:    h.createResultValue(response),
+		Metadata: h.createResultMetadata(operation, response),
+	}
+	if err != nil {
+		result.Error = err
+	}
+
+	// 服务端可能以4xx/5xx返回GraphQL错误，只要响应体可解析就统计其中的错误
+	var graphQL *GraphQLResponse
+	if response != nil && err == nil {
+		parsed, parseErr := ParseGraphQLResponse(response.Body)
+		switch {
+		case parseErr == nil:
+			graphQL = parsed
+			if graphQLErr := parsed.Err(); graphQLErr != nil {
+				result.Success, result.Error = false, graphQLErr
+				result.Metadata["error_category"] = ErrorCategoryGraphQL
+				result.Metadata["graphql_errors"] = len(parsed.Errors)
+			}
+		case result.Success:
+			result.Success, result.Error = false, parseErr
+		}
+		if result.Error == nil && !result.Success {
+			result.Error = fmt.Errorf("HTTP %d", response.StatusCode)
+		}
+	}
+
+	// 响应断言在GraphQL判定之外叠加，GraphQL错误优先
+	if h.validation.Applies(nil) {
+		passed, validationErr := h.validation.Validate(name, nil, response, err)
+		if result.Success && !passed {
+			result.Success, result.Error = false, validationErr
+			result.Metadata["error_category"] = ErrorCategoryValidation
+		}
+	}
+
+	h.contract.Check(reqConfig.Method, reqConfig.Path, response)
+
+	statusCode, size := 0, 0
+	if response != nil {
+		statusCode, size = response.StatusCode, len(response.Body)
+	}
+	h.graphQLTracker.Record(name, statusCode, size, duration, result.Success, graphQL)
+
+	// 记录HTTP特定指标，操作类型为GraphQL操作名
+	if response != nil && h.metricsCollector != nil {
+		operationResult := &interfaces.OperationResult{
+			Success:  result.Success,
+			IsRead:   isRead,
+			Duration: duration,
+			Metadata: map[string]interface{}{
+				"status_code":    response.StatusCode,
+				"method":         reqConfig.Method,
+				"url":            reqConfig.Path,
+				"operation_type": name,
+			},
+		}
+		if category, ok := result.Metadata["error_category"]; ok {
+			operationResult.Metadata["error_category"] = category
+		}
+		h.metricsCollector.Record(operationResult)
+	}
+
+	if result.Error != nil {
+		return result, result.Error
+	}
+	return result, nil
+}
+
+// executeStream 打开流式响应并持续读取事件，直到达到保持时间、事件数上限、空闲超时或服务端结束响应
+// 操作耗时为流的存活时间；服务端正常结束、达到保持时间或事件数上限时操作成功
+func (h *HttpExecutor) executeStream(ctx context.Context, operation interfaces.Operation, reqConfig httpConfig.HttpRequestConfig, httpClient *connection.HttpClient, startTime time.Time) (*interfaces.OperationResult, error) {
+	streamConfig := h.config.Benchmark.Stream
+	streamCtx, cancel := context.WithTimeout(ctx, streamConfig.Duration)
+	defer cancel()
+
+	// 空闲超时通过取消流的上下文中断阻塞中的读取
+	var idle atomic.Bool
+	var idleTimer *time.Timer
+	if streamConfig.IdleTimeout > 0 {
+		idleTimer = time.AfterFunc(streamConfig.IdleTimeout, func() {
+			idle.Store(true)
+			cancel()
+		})
+		defer idleTimer.Stop()
+	}
+
+	response, firstByte, err := httpClient.OpenStream(streamCtx, reqConfig)
+	opened := startTime.Add(httpClient.AuthWait())
+	statusCode, ending := 0, ""
+	var events, size int64
+	if err == nil {
+		defer response.Body.Close()
+		statusCode = response.StatusCode
+		h.streamTracker.RecordTTFB(firstByte.Sub(opened))
+
+		if statusCode < 200 || statusCode >= 300 {
+			err = fmt.Errorf("stream request failed with status %d", statusCode)
+		} else {
+			reader := NewStreamReader(response.Body, streamConfig.Mode)
+			last := opened
+			for {
+				if _, err = reader.Next(); err != nil {
+					break
+				}
+				now := time.Now()
+				h.streamTracker.RecordEvent(events == 0, now.Sub(last))
+				last = now
+				events++
+				if idleTimer != nil {
+					idleTimer.Reset(streamConfig.IdleTimeout)
+				}
+				if streamConfig.MaxEvents > 0 && events >= int64(streamConfig.MaxEvents) {
+					ending, err = StreamEndMaxEvents, nil
+					break
+				}
+			}
+			size = reader.Bytes()
+		}
+	}
+	closed := time.Now()
+
+	switch {
+	case ending == StreamEndMaxEvents:
+	case idle.Load():
+		ending, err = StreamEndIdle, fmt.Errorf("no stream event within %v", streamConfig.IdleTimeout)
+	case ctx.Err() != nil:
+		ending, err = StreamEndCancelled, ctx.Err()
+	case statusCode >= 200 && statusCode < 300 && streamCtx.Err() != nil:
+		ending, err = StreamEndDuration, nil
+	case errors.Is(err, io.EOF):
+		ending, err = StreamEndCompleted, nil
+	default:
+		ending = StreamEndError
+	}
+	success := err == nil
+	h.streamTracker.RecordStream(success, ending, opened, closed, size)
+
+	result := &interfaces.OperationResult{
+		Success:  success,
+		Duration: closed.Sub(opened),
+		IsRead:   true,
+		Error:    err,
+		Value: map[string]interface{}{
+			"status_code": statusCode,
+			"events":      events,
+			"bytes":       size,
+			"ending":      ending,
+		},
+		Metadata: h.createResultMetadata(operation, nil),
+	}
+	result.Metadata["response_status"] = statusCode
+	result.Metadata["stream_events"] = events
+	result.Metadata["stream_ending"] = ending
+
+	// 记录HTTP特定指标
+	if statusCode != 0 && h.metricsCollector != nil {
+		h.metricsCollector.Record(&interfaces.OperationResult{
+			Success:  result.Success,
+			Duration: result.Duration,
+			Metadata: map[string]interface{}{
+				"status_code": statusCode,
+				"method":      reqConfig.Method,
+				"url":         reqConfig.Path,
+			},
+		})
+	}
+
+	return result, err
+}
+
+// pageAssets 确定页面需要加载的子资源：配置的资源加上从HTML中发现的资源，去重后按上限截断
+func (h *HttpExecutor) pageAssets(pagePath string, response *connection.HttpResponse) []string {
+	pageConfig := h.config.Benchmark.Page
+
+	refs := append([]string(nil), pageConfig.Assets...)
+	if pageConfig.Discover && strings.Contains(strings.ToLower(response.GetHeader("Content-Type")), "html") {
+		refs = append(refs, ExtractAssetURLs(response.Body)...)
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+
+	pageURL := pagePath
+	if base, err := url.Parse(h.config.Connection.BaseURL); err == nil {
+		if ref, err := url.Parse(pagePath); err == nil {
+			pageURL = base.ResolveReference(ref).String()
+		}
+	}
+
+	seen := make(map[string]bool)
+	var assets []string
+	for _, asset := range ResolveAssetURLs(pageURL, refs) {
+		if seen[asset] || asset == pageURL {
+			continue
+		}
+		seen[asset] = true
+		assets = append(assets, asset)
+		if pageConfig.MaxAssets > 0 && len(assets) >= pageConfig.MaxAssets {
+			break
+		}
+	}
+	return assets
+}
+
+// requestBodySize 请求体的大致字节数：字符串按原样，其余按JSON编码计算
+func requestBodySize(body interface{}) int64 {
+	switch v := body.(type) {
+	case nil:
+		return 0
+	case string:
+		return int64(len(v))
+	case []byte:
+		return int64(len(v))
+	default:
+		data, _ := json.Marshal(v)
+		return int64(len(data))
+	}
+}
@@ -1,7 +1,9 @@
 package operations
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -9,6 +11,7 @@ import (
 	httpConfig "abc-runner/app/adapters/http/config"
 	"abc-runner/app/core/execution"
 	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/utils"
 )
 
 // HttpOperationFactory HTTP操作工厂
@@ -16,19 +19,144 @@ type HttpOperationFactory struct {
 	config   *httpConfig.HttpAdapterConfig
 	testCase string
 	dataSize int
+	runID    string // 异步回调测试中关联ID的前缀，区分不同运行的回调
+
+	// 多接口加权与回放测试中各请求模板的累计权重
+	weights []int
+
+	// 请求体模板，未配置时按data_size生成JSON请求体
+	body *utils.PayloadTemplate
+	// weighted测试中与各请求模板对应的路径和请求体模板，不含占位符的为nil
+	requestTemplates []requestTemplate
+
+	// GraphQL测试中各操作的请求及其累计权重
+	graphQL        []graphQLRequest
+	graphQLWeights []int
+}
+
+// requestTemplate 请求模板中含占位符的路径与请求体
+type requestTemplate struct {
+	path *utils.PayloadTemplate
+	body *utils.PayloadTemplate
+}
+
+// graphQLRequest GraphQL测试中一个操作的请求内容
+type graphQLRequest struct {
+	name          string                 // 报告中的操作名
+	kind          string                 // query或mutation
+	query         string                 // GraphQL文档
+	operationName string                 // 随请求发送的operationName，匿名操作为空
+	variables     json.RawMessage        // 不含占位符的变量
+	template      *utils.PayloadTemplate // 含占位符的变量模板
 }
 
 // NewHttpOperationFactory 创建HTTP操作工厂
 func NewHttpOperationFactory(config *httpConfig.HttpAdapterConfig) *HttpOperationFactory {
-	return &HttpOperationFactory{
+	factory := &HttpOperationFactory{
 		config:   config,
 		testCase: config.Benchmark.TestCase,
 		dataSize: config.Benchmark.DataSize,
+		runID:    strconv.FormatInt(time.Now().UnixNano(), 36),
+	}
+	if factory.isWeighted() {
+		total := 0
+		for _, req := range config.Requests {
+			if req.Weight > 0 {
+				total += req.Weight
+			}
+			factory.weights = append(factory.weights, total)
+		}
+	}
+	factory.compileTemplates()
+	return factory
+}
+
+// compileTemplates 解析请求体模板与weighted请求模板中的占位符，模板在配置验证时已检查
+// 回放的请求体是记录下来的真实内容，不作为模板处理
+func (f *HttpOperationFactory) compileTemplates() {
+	data, err := f.config.Benchmark.Payload.LoadData()
+	if err != nil {
+		return
+	}
+	if f.config.Benchmark.Payload.Enabled() {
+		f.body, _ = utils.ParsePayloadTemplate(f.config.Benchmark.Payload.Template, data)
+	}
+	if f.testCase == "graphql" {
+		f.compileGraphQL(data)
+		return
+	}
+	if f.testCase != "weighted" {
+		return
+	}
+	templated := false
+	templates := make([]requestTemplate, len(f.config.Requests))
+	for i, req := range f.config.Requests {
+		if utils.IsPayloadTemplate(req.Path) {
+			templates[i].path, _ = utils.ParsePayloadTemplate(req.Path, data)
+		}
+		if text := httpConfig.RequestBodyText(req.Body); utils.IsPayloadTemplate(text) {
+			templates[i].body, _ = utils.ParsePayloadTemplate(text, data)
+		}
+		templated = templated || templates[i].path != nil || templates[i].body != nil
+	}
+	if templated {
+		f.requestTemplates = templates
 	}
 }
 
+// compileGraphQL 解析各GraphQL操作的变量模板并计算累计权重，权重全部为0时各操作均匀选取
+func (f *HttpOperationFactory) compileGraphQL(data *utils.TemplateData) {
+	operations := f.config.Benchmark.GraphQL.Operations
+	uniform := true
+	for _, operation := range operations {
+		uniform = uniform && operation.Weight == 0
+	}
+	total := 0
+	for i, operation := range operations {
+		definition, _ := operation.Resolve()
+		request := graphQLRequest{
+			name:          GraphQLOperationName(i, operation),
+			kind:          definition.Type,
+			query:         operation.Query,
+			operationName: definition.Name,
+		}
+		if text := httpConfig.RequestBodyText(operation.Variables); utils.IsPayloadTemplate(text) {
+			request.template, _ = utils.ParsePayloadTemplate(text, data)
+		} else if text != "" {
+			request.variables = json.RawMessage(text)
+		}
+		f.graphQL = append(f.graphQL, request)
+
+		if uniform {
+			total++
+		} else {
+			total += operation.Weight
+		}
+		f.graphQLWeights = append(f.graphQLWeights, total)
+	}
+}
+
+// renderBody 按任务编号渲染请求体模板；JSON请求中渲染结果为合法JSON时原样发送，否则作为字符串
+func renderBody(template *utils.PayloadTemplate, jobID int, contentType string) interface{} {
+	text := template.Render(jobID)
+	if (contentType == "" || contentType == "application/json") && json.Valid([]byte(text)) {
+		return json.RawMessage(text)
+	}
+	return text
+}
+
 // CreateOperation 创建HTTP操作
 func (f *HttpOperationFactory) CreateOperation(jobID int, config execution.BenchmarkConfig) interfaces.Operation {
+	// 多接口加权与回放测试按权重从请求模板中选取请求
+	if f.isWeighted() {
+		return f.createWeightedOperation(jobID)
+	}
+
+	// GraphQL测试按权重选取操作
+	if f.testCase == "graphql" && len(f.graphQL) > 0 {
+		return f.createGraphQLOperation(jobID)
+	}
+
 	// 生成操作键（URL路径）
 	path := f.generatePath(jobID)
 
@@ -57,6 +185,16 @@ func (f *HttpOperationFactory) CreateOperation(jobID int, config execution.Bench
 		"user_agent":     "abc-runner-http-client", // 默认值，因为配置中没有UserAgent字段
 	}
 
+	// 缓存服务器测试需要区分可缓存与不可缓存请求
+	if f.testCase == "cache_mix" {
+		params["cacheable"] = f.isCacheableJob(jobID)
+	}
+
+	// 异步回调测试为每个任务生成关联ID
+	if f.testCase == "webhook" {
+		params["correlation_id"] = fmt.Sprintf("abc-%s-%d", f.runID, jobID)
+	}
+
 	// 根据测试用例确定具体操作类型
 	operationType := f.determineOperationType(jobID)
 
@@ -107,6 +245,18 @@ func (f *HttpOperationFactory) determineOperationType(jobID int) string {
 	case "options_only":
 		return "http_options"
 
+	case "page_load":
+		return "http_page_load"
+
+	case "webhook":
+		return "http_webhook"
+
+	case "stream":
+		return "http_stream"
+
+	case "scenario":
+		return "http_scenario"
+
 	case "crud_operations":
 		// CRUD操作循环
 		switch jobID % 4 {
@@ -146,6 +296,31 @@ func (f *HttpOperationFactory) determineOperationType(jobID int) string {
 
 // generatePath 生成请求路径
 func (f *HttpOperationFactory) generatePath(jobID int) string {
+	// 缓存服务器测试的路径由配置决定，外部URL同样适用
+	if f.testCase == "cache_mix" {
+		return f.generateCachePath(jobID)
+	}
+
+	// 页面加载测试始终请求配置的页面
+	if f.testCase == "page_load" {
+		return f.config.Benchmark.Page.Path
+	}
+
+	// 异步回调测试始终向提交路径发送任务
+	if f.testCase == "webhook" {
+		return f.config.Benchmark.Webhook.SubmitPath
+	}
+
+	// 流式响应测试始终打开配置的流
+	if f.testCase == "stream" {
+		return f.config.Benchmark.Stream.Path
+	}
+
+	// 请求链场景的各步骤路径由执行器处理，这里以第一步为操作键
+	if f.testCase == "scenario" && len(f.config.Benchmark.Scenario.Steps) > 0 {
+		return f.config.Benchmark.Scenario.Steps[0].Path
+	}
+
 	// 如果是外部URL（非本地API），使用简单的根路径
 	if f.isExternalURL() {
 		return "/" // 对于外部网站，只访问根路径
@@ -193,7 +368,7 @@ func (f *HttpOperationFactory) generatePath(jobID int) string {
 // generateRequestBody 生成请求体
 func (f *HttpOperationFactory) generateRequestBody(jobID int) interface{} {
 	switch f.testCase {
-	case "get_only", "delete_only", "head_only", "options_only":
+	case "get_only", "delete_only", "head_only", "options_only", "cache_mix", "page_load", "stream", "scenario":
 		return nil // 这些方法通常不需要请求体
 
 	case "post_only", "put_only", "patch_only":
@@ -216,8 +391,12 @@ func (f *HttpOperationFactory) generateRequestBody(jobID int) interface{} {
 	}
 }
 
-// generateJSONBody 生成JSON请求体
-func (f *HttpOperationFactory) generateJSONBody(jobID int) map[string]interface{} {
+// generateJSONBody 生成JSON请求体，配置了请求体模板时按模板渲染
+func (f *HttpOperationFactory) generateJSONBody(jobID int) interface{} {
+	if f.body != nil {
+		return renderBody(f.body, jobID, "application/json")
+	}
+
 	body := map[string]interface{}{
 		"id":        jobID,
 		"name":      fmt.Sprintf("test_item_%d", jobID),
@@ -268,6 +447,25 @@ func (f *HttpOperationFactory) generateHeaders(jobID int) map[string]string {
 	case "rest_api_test":
 		headers["X-Test-Type"] = "rest-api"
 		headers["X-Job-ID"] = strconv.Itoa(jobID)
+
+	case "cache_mix":
+		delete(headers, "Content-Type")
+		headers["Accept"] = "*/*"
+		if !f.isCacheableJob(jobID) {
+			headers["Cache-Control"] = "no-cache"
+			headers["Pragma"] = "no-cache"
+		}
+
+	case "page_load":
+		delete(headers, "Content-Type")
+		headers["Accept"] = "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+
+	case "stream":
+		delete(headers, "Content-Type")
+		headers["Cache-Control"] = "no-cache"
+		if f.config.Benchmark.Stream.Mode == "sse" {
+			headers["Accept"] = "text/event-stream"
+		}
 	}
 
 	return headers
@@ -288,6 +486,7 @@ func (f *HttpOperationFactory) GetSupportedOperations() []string {
 	return []string{
 		"get_post_mixed", "get_only", "post_only", "put_only", "delete_only",
 		"patch_only", "head_only", "options_only", "crud_operations", "rest_api_test",
+		"cache_mix", "page_load", "webhook", "stream", "weighted", "replay", "scenario", "graphql",
 	}
 }
 
@@ -326,7 +525,7 @@ func (f *HttpOperationFactory) GetOperationMetadata(operationType string) map[st
 
 // isReadOperation 判断是否为读操作
 func (f *HttpOperationFactory) isReadOperation(operationType string) bool {
-	readOps := []string{"http_get", "http_head", "http_options"}
+	readOps := []string{"http_get", "http_head", "http_options", "http_page_load", "http_stream"}
 	for _, readOp := range readOps {
 		if readOp == operationType {
 			return true
@@ -335,6 +534,126 @@ func (f *HttpOperationFactory) isReadOperation(operationType string) bool {
 	return false
 }
 
+// isWeighted 是否按权重执行配置的请求模板
+func (f *HttpOperationFactory) isWeighted() bool {
+	return (f.testCase == "weighted" || f.testCase == "replay") && len(f.config.Requests) > 0
+}
+
+// createWeightedOperation 创建多接口加权测试的操作
+// 按jobID的散列在累计权重中选取请求模板，各模板的比例与权重一致且在时间上交错分布；
+// 操作类型取模板名称（默认为方法与归一化路径，与代理模式记录的操作类型一致，便于对比）
+func (f *HttpOperationFactory) createWeightedOperation(jobID int) interfaces.Operation {
+	index := weightedIndex(f.weights, jobID)
+	req := f.config.Requests[index]
+	name := EndpointName(req)
+
+	// 按任务编号渲染路径与请求体中的占位符，操作名称仍取模板本身以便按接口汇总
+	if f.requestTemplates != nil {
+		template := f.requestTemplates[index]
+		if template.path != nil {
+			req.Path = template.path.Render(jobID)
+		}
+		if template.body != nil {
+			req.Body = renderBody(template.body, jobID, req.ContentType)
+		}
+	}
+
+	return interfaces.Operation{
+		Type:  "http_" + strings.ToLower(req.Method),
+		Key:   req.Path,
+		Value: req.Body,
+		Params: map[string]interface{}{
+			"job_id":     jobID,
+			"test_case":  f.testCase,
+			"base_url":   f.config.Connection.BaseURL,
+			"timeout":    f.config.Connection.Timeout.Seconds(),
+			"raw_config": req,
+			"endpoint":   name,
+		},
+		TTL: f.config.Benchmark.TTL,
+		Metadata: map[string]string{
+			"operation_type": name,
+			"protocol":       "http",
+			"job_id":         strconv.Itoa(jobID),
+		},
+	}
+}
+
+// weightedIndex 按jobID的散列在累计权重中选取下标
+func weightedIndex(weights []int, jobID int) int {
+	total := weights[len(weights)-1]
+	if total <= 0 {
+		return 0
+	}
+	slot := int((uint64(jobID) * 0x9E3779B97F4A7C15 >> 16) % uint64(total))
+	return sort.SearchInts(weights, slot+1)
+}
+
+// createGraphQLOperation 创建GraphQL测试的操作：按权重选取GraphQL操作，渲染变量后以JSON请求体POST到GraphQL端点
+// 操作类型取GraphQL操作名，使同一URL上的不同查询与变更分别统计
+func (f *HttpOperationFactory) createGraphQLOperation(jobID int) interfaces.Operation {
+	request := f.graphQL[weightedIndex(f.graphQLWeights, jobID)]
+	variables := request.variables
+	if request.template != nil {
+		variables = json.RawMessage(request.template.Render(jobID))
+	}
+	body, _ := json.Marshal(struct {
+		Query         string          `json:"query"`
+		OperationName string          `json:"operationName,omitempty"`
+		Variables     json.RawMessage `json:"variables,omitempty"`
+	}{request.query, request.operationName, variables})
+
+	req := httpConfig.HttpRequestConfig{
+		Name:        request.name,
+		Method:      "POST",
+		Path:        f.config.Benchmark.GraphQL.Path,
+		Headers:     map[string]string{"Accept": "application/graphql-response+json, application/json"},
+		Body:        json.RawMessage(body),
+		ContentType: "application/json",
+	}
+	return interfaces.Operation{
+		Type:  "http_graphql",
+		Key:   req.Path,
+		Value: req.Body,
+		Params: map[string]interface{}{
+			"job_id":            jobID,
+			"test_case":         f.testCase,
+			"base_url":          f.config.Connection.BaseURL,
+			"timeout":           f.config.Connection.Timeout.Seconds(),
+			"raw_config":        req,
+			"graphql_operation": request.name,
+			"graphql_type":      request.kind,
+		},
+		TTL: f.config.Benchmark.TTL,
+		Metadata: map[string]string{
+			"operation_type": request.name,
+			"protocol":       "http",
+			"job_id":         strconv.Itoa(jobID),
+		},
+	}
+}
+
+// isCacheableJob 缓存服务器测试中该任务是否请求可缓存URL
+func (f *HttpOperationFactory) isCacheableJob(jobID int) bool {
+	return jobID%100 < f.config.Benchmark.Cache.CacheablePercent
+}
+
+// generateCachePath 生成缓存服务器测试的请求路径
+// 可缓存请求在固定数量的对象中分布，不可缓存请求追加唯一查询参数以绕过缓存
+func (f *HttpOperationFactory) generateCachePath(jobID int) string {
+	cache := f.config.Benchmark.Cache
+	if f.isCacheableJob(jobID) {
+		objects := cache.Objects
+		if objects <= 0 {
+			objects = 1
+		}
+		// 按可缓存请求的序号轮转对象，保证每个对象都会被访问到
+		index := (jobID/100)*cache.CacheablePercent + jobID%100
+		return fmt.Sprintf("%s/%d", cache.CacheablePath, index%objects)
+	}
+	return fmt.Sprintf("%s/%d?nocache=%d", cache.UncacheablePath, jobID, time.Now().UnixNano())
+}
+
 // isExternalURL 判断是否为外部URL
 func (f *HttpOperationFactory) isExternalURL() bool {
 	baseURL := f.config.Connection.BaseURL
@@ -345,9 +664,9 @@ func (f *HttpOperationFactory) isExternalURL() bool {
 // getHTTPMethodFromOperationType 根据操作类型获取HTTP方法
 func (f *HttpOperationFactory) getHTTPMethodFromOperationType(operationType string) string {
 	switch operationType {
-	case "http_get":
+	case "http_get", "http_page_load", "http_stream":
 		return "GET"
-	case "http_post":
+	case "http_post", "http_webhook":
 		return "POST"
 	case "http_put":
 		return "PUT"
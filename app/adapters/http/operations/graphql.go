@@ -0,0 +1,190 @@
+package operations
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	httpConfig "abc-runner/app/adapters/http/config"
+)
+
+// ErrorCategoryGraphQL 响应errors数组非空的错误类别，HTTP状态码可能仍为200
+const ErrorCategoryGraphQL = "graphql_error"
+
+// graphQLNoCode 错误没有extensions.code时统计使用的错误码
+const graphQLNoCode = "(no code)"
+
+// GraphQLOperationStats 单个GraphQL操作的统计
+type GraphQLOperationStats struct {
+	EndpointStats
+	Type          string           `json:"type"`           // query或mutation
+	GraphQLErrors int64            `json:"graphql_errors"` // errors数组非空的响应数
+	PartialData   int64            `json:"partial_data"`   // 同时带有data的错误响应数
+	ErrorCodes    map[string]int64 `json:"error_codes"`    // 按errors[].extensions.code统计的错误数
+}
+
+// ToMap 转换为报告使用的map
+func (s GraphQLOperationStats) ToMap() map[string]interface{} {
+	result := s.EndpointStats.ToMap()
+	delete(result, "method")
+	codes := make(map[string]interface{}, len(s.ErrorCodes))
+	for code, count := range s.ErrorCodes {
+		codes[code] = count
+	}
+	result["type"] = s.Type
+	result["graphql_errors"] = s.GraphQLErrors
+	result["partial_data"] = s.PartialData
+	result["error_codes"] = codes
+	return result
+}
+
+// graphQLCounter 单个GraphQL操作的错误计数
+type graphQLCounter struct {
+	errors      int64
+	partialData int64
+	codes       map[string]int64
+}
+
+// GraphQLTracker 按GraphQL操作名统计延迟、状态码与GraphQL错误
+type GraphQLTracker struct {
+	endpoints *EndpointTracker
+	mutex     sync.Mutex
+	types     map[string]string
+	counters  map[string]*graphQLCounter
+}
+
+// NewGraphQLTracker 创建GraphQL统计器，按配置顺序登记各操作
+func NewGraphQLTracker(config httpConfig.HttpGraphQLConfig) *GraphQLTracker {
+	t := &GraphQLTracker{
+		types:    make(map[string]string),
+		counters: make(map[string]*graphQLCounter),
+	}
+	requests := make([]httpConfig.HttpRequestConfig, 0, len(config.Operations))
+	for i, operation := range config.Operations {
+		definition, _ := operation.Resolve()
+		name := GraphQLOperationName(i, operation)
+		requests = append(requests, httpConfig.HttpRequestConfig{Name: name, Method: "POST", Path: config.Path, Weight: operation.Weight})
+		t.types[name] = definition.Type
+		t.counters[name] = &graphQLCounter{codes: make(map[string]int64)}
+	}
+	t.endpoints = NewEndpointTracker(requests)
+	return t
+}
+
+// Record 记录一次请求结果，response为nil表示未得到可解析的GraphQL响应
+func (t *GraphQLTracker) Record(name string, statusCode int, bytes int, latency time.Duration, success bool, response *GraphQLResponse) {
+	t.endpoints.Record(name, statusCode, bytes, latency, success)
+	if response == nil || len(response.Errors) == 0 {
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	counter, ok := t.counters[name]
+	if !ok {
+		return
+	}
+	counter.errors++
+	if response.HasData() {
+		counter.partialData++
+	}
+	for _, graphQLError := range response.Errors {
+		counter.codes[graphQLError.Code()]++
+	}
+}
+
+// Stats 按配置顺序获取各GraphQL操作的统计
+func (t *GraphQLTracker) Stats() []GraphQLOperationStats {
+	endpoints := t.endpoints.Stats()
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	stats := make([]GraphQLOperationStats, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		counter := t.counters[endpoint.Name]
+		operation := GraphQLOperationStats{
+			EndpointStats: endpoint,
+			Type:          t.types[endpoint.Name],
+			GraphQLErrors: counter.errors,
+			PartialData:   counter.partialData,
+			ErrorCodes:    make(map[string]int64, len(counter.codes)),
+		}
+		for code, count := range counter.codes {
+			operation.ErrorCodes[code] = count
+		}
+		stats = append(stats, operation)
+	}
+	return stats
+}
+
+// GraphQLOperationName 报告中GraphQL操作的名称：配置的name，其次为文档中的操作名，匿名操作按序号命名
+func GraphQLOperationName(index int, operation httpConfig.HttpGraphQLOperation) string {
+	if operation.Name != "" {
+		return operation.Name
+	}
+	definition, _ := operation.Resolve()
+	if definition.Name != "" {
+		return definition.Name
+	}
+	kind := definition.Type
+	if kind == "" {
+		kind = "query"
+	}
+	return fmt.Sprintf("anonymous %s #%d", kind, index+1)
+}
+
+// GraphQLResponse GraphQL响应中与结果判定相关的部分
+type GraphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []GraphQLError  `json:"errors"`
+}
+
+// GraphQLError GraphQL响应中的一个错误
+type GraphQLError struct {
+	Message    string                 `json:"message"`
+	Extensions map[string]interface{} `json:"extensions"`
+}
+
+// Code 错误的extensions.code，未提供时为graphQLNoCode
+func (e GraphQLError) Code() string {
+	if code, ok := e.Extensions["code"].(string); ok && code != "" {
+		return code
+	}
+	return graphQLNoCode
+}
+
+// HasData 响应是否带有非null的data
+func (r *GraphQLResponse) HasData() bool {
+	data := strings.TrimSpace(string(r.Data))
+	return data != "" && data != "null"
+}
+
+// Err 响应errors数组非空时返回描述第一个错误的错误
+func (r *GraphQLResponse) Err() error {
+	if len(r.Errors) == 0 {
+		return nil
+	}
+	first := r.Errors[0]
+	message := first.Message
+	if code := first.Code(); code != graphQLNoCode {
+		message = code + ": " + message
+	}
+	if len(r.Errors) > 1 {
+		message += fmt.Sprintf(" (and %d more)", len(r.Errors)-1)
+	}
+	return fmt.Errorf("%s: %s", ErrorCategoryGraphQL, message)
+}
+
+// ParseGraphQLResponse 解析GraphQL响应体，既没有data也没有errors的响应不是GraphQL响应
+func ParseGraphQLResponse(body []byte) (*GraphQLResponse, error) {
+	var response GraphQLResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("invalid GraphQL response: %w", err)
+	}
+	if response.Data == nil && response.Errors == nil {
+		return nil, fmt.Errorf("invalid GraphQL response: neither data nor errors is present")
+	}
+	return &response, nil
+}
@@ -0,0 +1,125 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	httpConfig "abc-runner/app/adapters/http/config"
+	"abc-runner/app/adapters/http/connection"
+)
+
+func TestExecuteGraphQL(t *testing.T) {
+	var mutations []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Query         string                 `json:"query"`
+			OperationName string                 `json:"operationName"`
+			Variables     map[string]interface{} `json:"variables"`
+		}
+		if r.URL.Path != "/graphql" || r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&request) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch request.OperationName {
+		case "GetUser":
+			// 偶数用户不存在：部分数据加一个带错误码的错误
+			if id := request.Variables["id"].(float64); int(id)%2 == 0 {
+				fmt.Fprintf(w, `{"data":{"user":null},"errors":[{"message":"user %v not found","extensions":{"code":"NOT_FOUND"}}]}`, id)
+				return
+			}
+			fmt.Fprint(w, `{"data":{"user":{"id":"1"}}}`)
+		case "CreateOrder":
+			mutations = append(mutations, request.Variables)
+			fmt.Fprint(w, `{"data":{"createOrder":{"id":"o1"}}}`)
+		case "":
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"errors":[{"message":"Cannot query field \"nope\""}]}`)
+		default:
+			fmt.Fprint(w, `not json`)
+		}
+	}))
+	defer server.Close()
+
+	config := httpConfig.LoadDefaultHttpConfig()
+	config.Connection.BaseURL = server.URL
+	config.Benchmark.TestCase = "graphql"
+	config.Benchmark.GraphQL.Operations = []httpConfig.HttpGraphQLOperation{
+		{Query: `query GetUser($id: Int!) { user(id: $id) { id } }`, Variables: `{"id": {{seq}}}`, Weight: 1},
+		{Name: "order", Query: `mutation CreateOrder($sku: String) { createOrder(sku: $sku) { id } }`, Variables: map[string]interface{}{"sku": "A1"}, Weight: 1},
+		{Query: `{ nope }`, Weight: 1},
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("invalid config: %v", err)
+	}
+
+	pool, err := connection.NewHttpConnectionPool(config)
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	defer pool.Close()
+	executor := NewHttpExecutor(pool, config, nil)
+	factory := NewHttpOperationFactory(config)
+
+	for jobID := 0; jobID < 60; jobID++ {
+		operation := factory.CreateOperation(jobID, nil)
+		result, err := executor.ExecuteOperation(context.Background(), operation)
+		switch name := operation.Params["graphql_operation"]; {
+		case name == "GetUser" && jobID%2 == 0:
+			if err == nil || result.Success || result.Metadata["error_category"] != ErrorCategoryGraphQL || !strings.Contains(err.Error(), "NOT_FOUND") {
+				t.Fatalf("job %d: expected a GraphQL error, got %v (%v)", jobID, err, result.Metadata["error_category"])
+			}
+		case name == "GetUser" || name == "order":
+			if err != nil || !result.Success || result.IsRead != (name == "GetUser") {
+				t.Fatalf("job %d (%s): unexpected result %v", jobID, name, err)
+			}
+		case name == "anonymous query #3":
+			if err == nil || result.Success {
+				t.Fatalf("job %d: expected the anonymous query to fail", jobID)
+			}
+		default:
+			t.Fatalf("job %d: unexpected operation %v", jobID, name)
+		}
+	}
+
+	stats := executor.GraphQLStats()
+	if len(stats) != 3 || stats[0].Name != "GetUser" || stats[1].Name != "order" || stats[1].Type != "mutation" || stats[2].Name != "anonymous query #3" {
+		t.Fatalf("unexpected operations: %+v", stats)
+	}
+	for _, operation := range stats {
+		if operation.Requests == 0 {
+			t.Fatalf("operation %s was never picked", operation.Name)
+		}
+	}
+	if user := stats[0]; user.GraphQLErrors == 0 || user.GraphQLErrors != user.Errors || user.PartialData != user.GraphQLErrors ||
+		user.ErrorCodes["NOT_FOUND"] != user.GraphQLErrors || user.StatusCodes[http.StatusOK] != user.Requests {
+		t.Errorf("unexpected GetUser stats: %+v", user)
+	}
+	if order := stats[1]; order.Errors != 0 || int64(len(mutations)) != order.Requests || mutations[0]["sku"] != "A1" {
+		t.Errorf("unexpected order stats: %+v (mutations %v)", order, mutations)
+	}
+	if anonymous := stats[2]; anonymous.Errors != anonymous.Requests || anonymous.PartialData != 0 ||
+		anonymous.ErrorCodes[graphQLNoCode] != anonymous.Requests || anonymous.StatusCodes[http.StatusBadRequest] != anonymous.Requests {
+		t.Errorf("unexpected anonymous query stats: %+v", anonymous)
+	}
+}
+
+func TestParseGraphQLResponse(t *testing.T) {
+	response, err := ParseGraphQLResponse([]byte(`{"data":null,"errors":[{"message":"boom"},{"message":"again","extensions":{"code":"INTERNAL"}}]}`))
+	if err != nil || response.HasData() {
+		t.Fatalf("unexpected response: %+v (%v)", response, err)
+	}
+	if err := response.Err(); err == nil || err.Error() != "graphql_error: boom (and 1 more)" {
+		t.Errorf("Err() = %v", err)
+	}
+	for _, body := range []string{`<html>`, `{"message":"not graphql"}`} {
+		if _, err := ParseGraphQLResponse([]byte(body)); err == nil {
+			t.Errorf("expected %s to be rejected", body)
+		}
+	}
+}
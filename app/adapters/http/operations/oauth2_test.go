@@ -0,0 +1,106 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	httpConfig "abc-runner/app/adapters/http/config"
+	"abc-runner/app/adapters/http/connection"
+)
+
+func TestOAuth2TokenRefresh(t *testing.T) {
+	var mutex sync.Mutex
+	issued, current := 0, ""
+	var apiRequests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		switch r.URL.Path {
+		case "/token":
+			id, secret, ok := r.BasicAuth()
+			r.ParseForm()
+			if !ok || id != "bench" || secret != "s3cret" || r.Form.Get("grant_type") != "client_credentials" || r.Form.Get("scope") != "orders.read" {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"error":"invalid_client"}`)
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+			issued++
+			current = fmt.Sprintf("token-%d", issued)
+			fmt.Fprintf(w, `{"access_token":%q,"token_type":"Bearer","expires_in":3600}`, current)
+		default:
+			apiRequests++
+			// 每10个请求吊销一次令牌，模拟服务端提前失效
+			if apiRequests%10 == 0 {
+				current = "revoked"
+			}
+			if r.Header.Get("Authorization") != "Bearer "+current {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `{"ok":true}`)
+		}
+	}))
+	defer server.Close()
+
+	config := httpConfig.LoadDefaultHttpConfig()
+	config.Connection.BaseURL = server.URL
+	config.Benchmark.TestCase = "weighted"
+	config.Requests = []httpConfig.HttpRequestConfig{{Method: "GET", Path: "/orders", Weight: 1}}
+	config.Auth = httpConfig.HttpAuthConfig{
+		Type: "oauth2",
+		OAuth2: httpConfig.HttpOAuth2Config{
+			TokenURL:     server.URL + "/token",
+			ClientID:     "bench",
+			ClientSecret: "s3cret",
+			Scopes:       []string{"orders.read"},
+		},
+	}
+	if err := config.Auth.OAuth2.Validate(); err != nil {
+		t.Fatalf("invalid oauth2 config: %v", err)
+	}
+
+	pool, err := connection.NewHttpConnectionPool(config)
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	defer pool.Close()
+	tokens := connection.NewTokenSource(config.Auth.OAuth2, pool.GetClient())
+	if err := tokens.Fetch(context.Background()); err != nil {
+		t.Fatalf("failed to fetch initial token: %v", err)
+	}
+	executor := NewHttpExecutor(pool, config, nil)
+	executor.SetTokenSource(tokens)
+	factory := NewHttpOperationFactory(config)
+
+	for jobID := 0; jobID < 30; jobID++ {
+		result, err := executor.ExecuteOperation(context.Background(), factory.CreateOperation(jobID, nil))
+		if err != nil || !result.Success {
+			t.Fatalf("request %d failed: %v", jobID, err)
+		}
+		// 令牌端点的耗时不计入请求耗时
+		if result.Duration >= 20*time.Millisecond {
+			t.Errorf("request %d includes token endpoint time: %v", jobID, result.Duration)
+		}
+	}
+
+	stats := tokens.Stats()
+	if stats.Fetches != 4 || stats.Refreshes != 3 || stats.UnauthorizedRefreshes != 3 || stats.RetriedRequests != 3 || stats.Failures != 0 {
+		t.Errorf("unexpected oauth2 stats: %+v", stats)
+	}
+	if stats.AverageLatency < 20*time.Millisecond {
+		t.Errorf("expected token endpoint latency to be tracked, got %v", stats.AverageLatency)
+	}
+
+	// 凭据错误时首次获取失败
+	config.Auth.OAuth2.ClientSecret = "wrong"
+	if err := connection.NewTokenSource(config.Auth.OAuth2, pool.GetClient()).Fetch(context.Background()); err == nil {
+		t.Errorf("expected token fetch with a wrong secret to fail")
+	}
+}
@@ -0,0 +1,198 @@
+package operations
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"abc-runner/app/core/metrics"
+)
+
+var (
+	// pageAssetTagPattern 匹配可能引用子资源的标签
+	pageAssetTagPattern = regexp.MustCompile(`(?is)<(script|img|link|source|iframe|video|audio|embed)\b[^>]*>`)
+	// pageAssetAttrPattern 匹配标签中的src/href属性
+	pageAssetAttrPattern = regexp.MustCompile(`(?is)\b(src|href|rel)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+)
+
+// pageLinkRels 会被浏览器加载的<link>类型
+var pageLinkRels = []string{"stylesheet", "icon", "preload", "modulepreload", "manifest"}
+
+// ExtractAssetURLs 从HTML中提取页面引用的子资源地址
+// 覆盖脚本、图片、样式表等浏览器在首屏会加载的资源；按出现顺序去重，忽略data:/javascript:与锚点
+func ExtractAssetURLs(body []byte) []string {
+	seen := make(map[string]bool)
+	var assets []string
+
+	for _, tag := range pageAssetTagPattern.FindAllSubmatch(body, -1) {
+		name := strings.ToLower(string(tag[1]))
+		attrs := make(map[string]string)
+		for _, attr := range pageAssetAttrPattern.FindAllSubmatch(tag[0], -1) {
+			value := string(attr[2]) + string(attr[3]) + string(attr[4])
+			attrs[strings.ToLower(string(attr[1]))] = strings.TrimSpace(value)
+		}
+
+		ref := attrs["src"]
+		if name == "link" {
+			if !isLoadedLinkRel(attrs["rel"]) {
+				continue
+			}
+			ref = attrs["href"]
+		}
+
+		lower := strings.ToLower(ref)
+		if ref == "" || strings.HasPrefix(ref, "#") || strings.HasPrefix(lower, "data:") ||
+			strings.HasPrefix(lower, "javascript:") || strings.HasPrefix(lower, "about:") {
+			continue
+		}
+		if !seen[ref] {
+			seen[ref] = true
+			assets = append(assets, ref)
+		}
+	}
+	return assets
+}
+
+// isLoadedLinkRel 判断<link rel>是否会触发资源加载
+func isLoadedLinkRel(rel string) bool {
+	for _, token := range strings.Fields(strings.ToLower(rel)) {
+		for _, loaded := range pageLinkRels {
+			if token == loaded {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ResolveAssetURLs 以页面地址为基准解析子资源地址，返回绝对URL
+func ResolveAssetURLs(pageURL string, refs []string) []string {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return refs
+	}
+
+	resolved := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		refURL, err := url.Parse(ref)
+		if err != nil {
+			continue
+		}
+		assetURL := base.ResolveReference(refURL)
+		assetURL.Fragment = ""
+		resolved = append(resolved, assetURL.String())
+	}
+	return resolved
+}
+
+// PageLoadStats 页面加载测试统计
+type PageLoadStats struct {
+	Pages         int64                  `json:"pages"`         // 加载的页面数
+	FailedPages   int64                  `json:"failed_pages"`  // 文档或任一子资源失败的页面数
+	Assets        int64                  `json:"assets"`        // 请求的子资源总数
+	FailedAssets  int64                  `json:"failed_assets"` // 失败的子资源数
+	Bytes         int64                  `json:"bytes"`         // 文档与子资源的响应字节数
+	AssetsPerPage float64                `json:"assets_per_page"`
+	PageLoad      metrics.LatencyMetrics `json:"page_load"` // 页面加载时间（文档+全部子资源）
+	Document      metrics.LatencyMetrics `json:"document"`  // 文档请求延迟
+	Asset         metrics.LatencyMetrics `json:"asset"`     // 单个子资源请求延迟
+}
+
+// ToMap 转换为报告使用的map
+func (s PageLoadStats) ToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"pages":           s.Pages,
+		"failed_pages":    s.FailedPages,
+		"assets":          s.Assets,
+		"failed_assets":   s.FailedAssets,
+		"bytes":           s.Bytes,
+		"assets_per_page": s.AssetsPerPage,
+		"page_load":       latencyToMap(s.PageLoad),
+		"document":        latencyToMap(s.Document),
+		"asset":           latencyToMap(s.Asset),
+	}
+}
+
+// latencyToMap 将延迟指标转换为报告使用的map
+func latencyToMap(m metrics.LatencyMetrics) map[string]interface{} {
+	return map[string]interface{}{
+		"avg":  m.Average.String(),
+		"min":  m.Min.String(),
+		"max":  m.Max.String(),
+		"p50":  m.P50.String(),
+		"p90":  m.P90.String(),
+		"p95":  m.P95.String(),
+		"p99":  m.P99.String(),
+		"p999": m.P999.String(),
+	}
+}
+
+// PageLoadTracker 页面加载统计器
+type PageLoadTracker struct {
+	pages        atomic.Int64
+	failedPages  atomic.Int64
+	assets       atomic.Int64
+	failedAssets atomic.Int64
+	bytes        atomic.Int64
+
+	pageLoad *metrics.LatencyTracker
+	document *metrics.LatencyTracker
+	asset    *metrics.LatencyTracker
+}
+
+// NewPageLoadTracker 创建页面加载统计器
+func NewPageLoadTracker() *PageLoadTracker {
+	config := metrics.LatencyConfig{
+		SamplingRate: 1.0,
+	}
+	return &PageLoadTracker{
+		pageLoad: metrics.NewLatencyTracker(config),
+		document: metrics.NewLatencyTracker(config),
+		asset:    metrics.NewLatencyTracker(config),
+	}
+}
+
+// RecordDocument 记录文档请求
+func (t *PageLoadTracker) RecordDocument(latency time.Duration, size int) {
+	t.document.Record(latency)
+	t.bytes.Add(int64(size))
+}
+
+// RecordAsset 记录子资源请求
+func (t *PageLoadTracker) RecordAsset(success bool, latency time.Duration, size int) {
+	t.assets.Add(1)
+	if !success {
+		t.failedAssets.Add(1)
+	}
+	t.asset.Record(latency)
+	t.bytes.Add(int64(size))
+}
+
+// RecordPage 记录一次完整的页面加载
+func (t *PageLoadTracker) RecordPage(success bool, loadTime time.Duration) {
+	t.pages.Add(1)
+	if !success {
+		t.failedPages.Add(1)
+	}
+	t.pageLoad.Record(loadTime)
+}
+
+// Stats 获取统计结果
+func (t *PageLoadTracker) Stats() PageLoadStats {
+	stats := PageLoadStats{
+		Pages:        t.pages.Load(),
+		FailedPages:  t.failedPages.Load(),
+		Assets:       t.assets.Load(),
+		FailedAssets: t.failedAssets.Load(),
+		Bytes:        t.bytes.Load(),
+		PageLoad:     t.pageLoad.GetMetrics(),
+		Document:     t.document.GetMetrics(),
+		Asset:        t.asset.GetMetrics(),
+	}
+	if stats.Pages > 0 {
+		stats.AssetsPerPage = float64(stats.Assets) / float64(stats.Pages)
+	}
+	return stats
+}
@@ -0,0 +1,112 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	httpConfig "abc-runner/app/adapters/http/config"
+	"abc-runner/app/adapters/http/connection"
+)
+
+func TestExtractAssetURLs(t *testing.T) {
+	html := []byte(`<html><head>
+		<link rel="stylesheet" href="/css/site.css">
+		<link rel="canonical" href="https://example.com/">
+		<LINK REL='preload icon' HREF='/img/logo.svg'>
+		<script src="app.js" defer></script>
+		<script>var inline = true;</script>
+	</head><body>
+		<img src=/img/hero.png alt="hero">
+		<img src="data:image/png;base64,AAAA">
+		<img src="/img/hero.png">
+		<a href="/next">next</a>
+	</body></html>`)
+
+	expected := []string{"/css/site.css", "/img/logo.svg", "app.js", "/img/hero.png"}
+	if assets := ExtractAssetURLs(html); !reflect.DeepEqual(assets, expected) {
+		t.Errorf("ExtractAssetURLs = %v, expected %v", assets, expected)
+	}
+
+	resolved := ResolveAssetURLs("http://example.com/shop/index.html", []string{"app.js", "/css/site.css#x", "https://cdn.example.com/lib.js"})
+	expected = []string{"http://example.com/shop/app.js", "http://example.com/css/site.css", "https://cdn.example.com/lib.js"}
+	if !reflect.DeepEqual(resolved, expected) {
+		t.Errorf("ResolveAssetURLs = %v, expected %v", resolved, expected)
+	}
+}
+
+func TestExecutePageLoad(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			fmt.Fprint(w, `<script src="/a.js"></script><link rel="stylesheet" href="/b.css">`)
+			for i := 0; i < 6; i++ {
+				fmt.Fprintf(w, `<img src="/img/%d.png">`, i)
+			}
+		case "/missing.png":
+			http.NotFound(w, r)
+		default:
+			current := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				seen := maxInFlight.Load()
+				if current <= seen || maxInFlight.CompareAndSwap(seen, current) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			fmt.Fprint(w, "asset")
+		}
+	}))
+	defer server.Close()
+
+	config := httpConfig.LoadDefaultHttpConfig()
+	config.Connection.BaseURL = server.URL
+	config.Benchmark.TestCase = "page_load"
+	config.Benchmark.Page.Parallelism = 2
+	pool, err := connection.NewHttpConnectionPool(config)
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	defer pool.Close()
+
+	executor := NewHttpExecutor(pool, config, nil)
+	factory := NewHttpOperationFactory(config)
+
+	result, err := executor.ExecuteOperation(context.Background(), factory.CreateOperation(0, nil))
+	if err != nil || !result.Success {
+		t.Fatalf("expected successful page load, got %v (%v)", result.Error, err)
+	}
+	if result.Metadata["assets"] != 8 {
+		t.Errorf("expected 8 assets, got %v", result.Metadata["assets"])
+	}
+	if maxInFlight.Load() != 2 {
+		t.Errorf("expected asset parallelism of 2, observed %d", maxInFlight.Load())
+	}
+	// 8个子资源、并行度2、每个20ms：页面加载时间至少80ms
+	if result.Duration < 80*time.Millisecond {
+		t.Errorf("expected page load time >= 80ms, got %v", result.Duration)
+	}
+
+	config.Benchmark.Page.Discover = false
+	config.Benchmark.Page.Assets = []string{"/a.js", "/missing.png"}
+	result, _ = executor.ExecuteOperation(context.Background(), factory.CreateOperation(1, nil))
+	if result.Success || result.Metadata["failed_assets"] != int64(1) {
+		t.Errorf("expected page with a failed asset to fail, got %+v", result.Metadata)
+	}
+
+	stats := executor.PageLoadStats()
+	if stats.Pages != 2 || stats.FailedPages != 1 || stats.Assets != 10 || stats.FailedAssets != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if stats.PageLoad.P50 < stats.Document.P50 {
+		t.Errorf("expected page load time to include assets: page %v, document %v", stats.PageLoad.P50, stats.Document.P50)
+	}
+}
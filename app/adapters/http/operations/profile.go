@@ -0,0 +1,265 @@
+package operations
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	httpConfig "abc-runner/app/adapters/http/config"
+)
+
+// maxProfileSamples 大小与到达间隔的采样上限，超出后按蓄水池抽样保留
+const maxProfileSamples = 100000
+
+// maxProfileURLs 参与热度统计的不同URL数量上限，超出的URL只计入总数
+const maxProfileURLs = 100000
+
+// profileTopURLs 特征报告中列出的热门URL数
+const profileTopURLs = 10
+
+// profileCurvePercents 热度曲线取样点：最热门的前x%的URL
+var profileCurvePercents = []float64{1, 5, 10, 20, 50}
+
+// profileSizeBuckets 大小直方图的区间上界（字节），最后一个区间无上界
+var profileSizeBuckets = []int64{0, 256, 1 << 10, 4 << 10, 16 << 10, 64 << 10, 256 << 10, 1 << 20}
+
+// WorkloadProfiler 工作负载特征统计
+// 代理模式下统计观察到的真实流量，回放时统计实际发出的请求，用于说明被回放的负载是什么样的
+type WorkloadProfiler struct {
+	mutex        sync.Mutex
+	requests     int64
+	operations   map[string]int64
+	urls         map[string]int64
+	requestSize  profileSamples
+	responseSize profileSamples
+	gaps         profileSamples
+	lastArrival  time.Time
+}
+
+// profileSamples 蓄水池抽样的样本
+type profileSamples struct {
+	seen    int64
+	sum     int64
+	max     int64
+	samples []int64
+}
+
+// add 记录一个值；总和与最大值按全部值计算，分位数按样本计算
+func (s *profileSamples) add(value int64) {
+	s.seen++
+	s.sum += value
+	s.max = max(s.max, value)
+	if len(s.samples) < maxProfileSamples {
+		s.samples = append(s.samples, value)
+	} else if i := rand.Int63n(s.seen); i < maxProfileSamples {
+		s.samples[i] = value
+	}
+}
+
+// sorted 样本的有序副本
+func (s *profileSamples) sorted() []int64 {
+	sorted := append([]int64(nil), s.samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
+
+// NewWorkloadProfiler 创建工作负载特征统计
+func NewWorkloadProfiler() *WorkloadProfiler {
+	return &WorkloadProfiler{
+		operations: make(map[string]int64),
+		urls:       make(map[string]int64),
+	}
+}
+
+// Arrive 记录一个请求的到达，须在请求开始时调用
+func (p *WorkloadProfiler) Arrive() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := time.Now()
+	if !p.lastArrival.IsZero() {
+		p.gaps.add(int64(now.Sub(p.lastArrival)))
+	}
+	p.lastArrival = now
+}
+
+// Record 记录一个请求的操作名、URL与请求/响应体大小
+func (p *WorkloadProfiler) Record(operation, url string, requestBytes, responseBytes int64) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.requests++
+	p.operations[operation]++
+	if _, ok := p.urls[url]; ok || len(p.urls) < maxProfileURLs {
+		p.urls[url]++
+	}
+	p.requestSize.add(requestBytes)
+	p.responseSize.add(responseBytes)
+}
+
+// Profile 生成特征报告，未记录任何请求时返回nil
+func (p *WorkloadProfiler) Profile() *httpConfig.HttpWorkloadProfile {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.requests == 0 {
+		return nil
+	}
+	profile := &httpConfig.HttpWorkloadProfile{
+		Requests:     p.requests,
+		Operations:   requestShares(p.operations, p.requests, 0),
+		RequestSize:  sizeDistribution(&p.requestSize),
+		ResponseSize: sizeDistribution(&p.responseSize),
+		InterArrival: interArrivalProfile(&p.gaps),
+	}
+
+	popularity := &profile.Popularity
+	popularity.DistinctURLs = len(p.urls)
+	popularity.Top = requestShares(p.urls, p.requests, profileTopURLs)
+	counts := make([]int64, 0, len(p.urls))
+	for _, count := range p.urls {
+		counts = append(counts, count)
+	}
+	sort.Slice(counts, func(i, j int) bool { return counts[i] > counts[j] })
+	for _, percent := range profileCurvePercents {
+		n := int(math.Ceil(float64(len(counts)) * percent / 100))
+		covered := int64(0)
+		for _, count := range counts[:n] {
+			covered += count
+		}
+		popularity.Curve = append(popularity.Curve, httpConfig.HttpPopularityPoint{
+			URLPercent:     percent,
+			RequestPercent: roundPercent(covered, p.requests),
+		})
+	}
+	popularity.ZipfExponent = zipfExponent(counts)
+	return profile
+}
+
+// requestShares 按请求数降序的占比列表，limit为0时不限数量
+func requestShares(counts map[string]int64, total int64, limit int) []httpConfig.HttpRequestShare {
+	shares := make([]httpConfig.HttpRequestShare, 0, len(counts))
+	for name, count := range counts {
+		shares = append(shares, httpConfig.HttpRequestShare{Name: name, Requests: count, Percent: roundPercent(count, total)})
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].Requests != shares[j].Requests {
+			return shares[i].Requests > shares[j].Requests
+		}
+		return shares[i].Name < shares[j].Name
+	})
+	if limit > 0 && len(shares) > limit {
+		shares = shares[:limit]
+	}
+	return shares
+}
+
+// sizeDistribution 大小分布与直方图，直方图按样本比例换算为请求数
+func sizeDistribution(s *profileSamples) httpConfig.HttpSizeDistribution {
+	sorted := s.sorted()
+	distribution := httpConfig.HttpSizeDistribution{
+		Mean: s.sum / s.seen,
+		P50:  sorted[percentileIndex(len(sorted), 0.50)],
+		P90:  sorted[percentileIndex(len(sorted), 0.90)],
+		P99:  sorted[percentileIndex(len(sorted), 0.99)],
+		Max:  s.max,
+	}
+
+	counts := make([]int64, len(profileSizeBuckets)+1)
+	for _, size := range sorted {
+		counts[sort.Search(len(profileSizeBuckets), func(i int) bool { return size <= profileSizeBuckets[i] })]++
+	}
+	for i, count := range counts {
+		if count == 0 {
+			continue
+		}
+		var label string
+		switch {
+		case i == 0:
+			label = "0"
+		case i == len(profileSizeBuckets):
+			label = "> " + formatBytes(profileSizeBuckets[i-1])
+		default:
+			label = "≤ " + formatBytes(profileSizeBuckets[i])
+		}
+		distribution.Histogram = append(distribution.Histogram, httpConfig.HttpSizeBucket{
+			Range:    label,
+			Requests: count * s.seen / int64(len(sorted)),
+			Percent:  roundPercent(count, int64(len(sorted))),
+		})
+	}
+	return distribution
+}
+
+// interArrivalProfile 到达间隔分布与变异系数
+func interArrivalProfile(s *profileSamples) httpConfig.HttpInterArrivalProfile {
+	if s.seen == 0 {
+		return httpConfig.HttpInterArrivalProfile{}
+	}
+	sorted := s.sorted()
+	mean := float64(s.sum) / float64(s.seen)
+	profile := httpConfig.HttpInterArrivalProfile{
+		Mean: time.Duration(mean),
+		P50:  time.Duration(sorted[percentileIndex(len(sorted), 0.50)]),
+		P90:  time.Duration(sorted[percentileIndex(len(sorted), 0.90)]),
+		P99:  time.Duration(sorted[percentileIndex(len(sorted), 0.99)]),
+		Max:  time.Duration(s.max),
+	}
+	if mean > 0 {
+		variance := 0.0
+		for _, gap := range sorted {
+			variance += (float64(gap) - mean) * (float64(gap) - mean)
+		}
+		profile.CV = math.Round(math.Sqrt(variance/float64(len(sorted)))/mean*100) / 100
+	}
+	return profile
+}
+
+// zipfExponent 对降序的请求数按 log(请求数) = c - s·log(排名) 做最小二乘拟合，返回s
+func zipfExponent(counts []int64) float64 {
+	if len(counts) < 2 {
+		return 0
+	}
+	var sumX, sumY, sumXY, sumXX float64
+	for rank, count := range counts {
+		x, y := math.Log(float64(rank+1)), math.Log(float64(count))
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	n := float64(len(counts))
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0
+	}
+	return math.Round(-(n*sumXY-sumX*sumY)/denominator*100) / 100
+}
+
+// percentileIndex 有序样本中分位数q对应的下标
+func percentileIndex(n int, q float64) int {
+	return int(math.Ceil(float64(n)*q)) - 1
+}
+
+// roundPercent 百分比，保留两位小数
+func roundPercent(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(total)*10000) / 100
+}
+
+// formatBytes 以B、KiB、MiB显示字节数
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%dMiB", n>>20)
+	case n >= 1<<10:
+		return fmt.Sprintf("%dKiB", n>>10)
+	default:
+		return fmt.Sprintf("%dB", n)
+	}
+}
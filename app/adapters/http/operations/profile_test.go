@@ -0,0 +1,57 @@
+package operations
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestWorkloadProfiler(t *testing.T) {
+	profiler := NewWorkloadProfiler()
+	if profiler.Profile() != nil {
+		t.Fatalf("expected no profile before any request")
+	}
+
+	// 20个URL按Zipf(s=1)分布：第k个URL被请求 1000/k 次
+	total := int64(0)
+	for rank := 1; rank <= 20; rank++ {
+		for i := 0; i < 1000/rank; i++ {
+			profiler.Arrive()
+			operation, body := "GET /items/{id}", int64(0)
+			if rank == 20 {
+				operation, body = "POST /items", 2048
+			}
+			profiler.Record(operation, fmt.Sprintf("/items/%d", rank), body, int64(100*rank))
+			total++
+		}
+	}
+
+	profile := profiler.Profile()
+	if profile.Requests != total || len(profile.Operations) != 2 || profile.Operations[0].Name != "GET /items/{id}" {
+		t.Fatalf("unexpected operation mix: %+v", profile.Operations)
+	}
+	popularity := profile.Popularity
+	if popularity.DistinctURLs != 20 || popularity.Top[0].Name != "/items/1" || len(popularity.Top) != profileTopURLs {
+		t.Errorf("unexpected popularity: %+v", popularity)
+	}
+	if math.Abs(popularity.ZipfExponent-1) > 0.05 {
+		t.Errorf("Zipf exponent = %v, want about 1", popularity.ZipfExponent)
+	}
+	// 前5%的URL即第1个URL，占1000/3590
+	if point := popularity.Curve[1]; point.URLPercent != 5 || math.Abs(point.RequestPercent-27.86) > 0.01 {
+		t.Errorf("unexpected popularity curve: %+v", popularity.Curve)
+	}
+	if last := popularity.Curve[len(popularity.Curve)-1]; last.RequestPercent < 80 {
+		t.Errorf("top half of the URLs should serve most requests: %+v", last)
+	}
+
+	if profile.RequestSize.Max != 2048 || profile.RequestSize.P50 != 0 || profile.RequestSize.Histogram[0].Range != "0" {
+		t.Errorf("unexpected request sizes: %+v", profile.RequestSize)
+	}
+	if profile.ResponseSize.Max != 2000 || profile.ResponseSize.P50 != 300 {
+		t.Errorf("unexpected response sizes: %+v", profile.ResponseSize)
+	}
+	if profile.InterArrival.Max <= 0 || profile.InterArrival.P50 > profile.InterArrival.P99 {
+		t.Errorf("unexpected inter-arrival times: %+v", profile.InterArrival)
+	}
+}
@@ -0,0 +1,638 @@
+package operations
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	httpConfig "abc-runner/app/adapters/http/config"
+	"abc-runner/app/core/interfaces"
+)
+
+// maxWorkloadRequests 工作负载中不同请求（方法与URL）数量上限，超出的请求只计入统计
+const maxWorkloadRequests = 1000
+
+// maxWorkloadBody 请求体样本上限，更大的请求体不写入工作负载
+const maxWorkloadBody = 64 << 10
+
+// workloadSkippedHeaders 不写入工作负载的请求头：逐跳头、由回放客户端生成的头以及凭据
+var workloadSkippedHeaders = map[string]bool{
+	"Host": true, "Content-Length": true, "Content-Type": true, "Accept-Encoding": true,
+	"Connection": true, "Keep-Alive": true, "Proxy-Connection": true, "Proxy-Authorization": true,
+	"Te": true, "Trailer": true, "Transfer-Encoding": true, "Upgrade": true,
+	"Authorization": true, "Cookie": true,
+	"X-Forwarded-For": true, "X-Forwarded-Host": true, "X-Forwarded-Proto": true, "Forwarded": true,
+}
+
+// ProxyStats 代理模式统计
+type ProxyStats struct {
+	Listen          string `json:"listen"`
+	Upstream        string `json:"upstream"` // 反向代理的目标地址，正向代理为空
+	Mode            string `json:"mode"`     // reverse或forward
+	Requests        int64  `json:"requests"`
+	Errors          int64  `json:"errors"`           // 转发失败或状态码>=400的请求数
+	Tunnels         int64  `json:"tunnels"`          // CONNECT隧道数，隧道内为加密流量，只转发不计时
+	BytesIn         int64  `json:"bytes_in"`         // 请求体字节数
+	BytesOut        int64  `json:"bytes_out"`        // 响应体字节数
+	PeakConcurrency int64  `json:"peak_concurrency"` // 同时处理中的最大请求数
+	Endpoints       int    `json:"endpoints"`        // 观察到的接口（方法与归一化路径）数
+	DroppedRequests int64  `json:"dropped_requests"` // 超出工作负载请求种类上限的请求数
+}
+
+// proxyEndpoint 按接口汇总的流量
+type proxyEndpoint struct {
+	requests      int64
+	errors        int64
+	requestBytes  int64
+	responseBytes int64
+	totalLatency  time.Duration
+	maxLatency    time.Duration
+}
+
+// workloadEntry 工作负载中的一个请求及其出现次数
+type workloadEntry struct {
+	request httpConfig.HttpWorkloadRequest
+	count   int64
+	origin  string
+}
+
+// proxyExchangeKey 请求context中保存本次交换的键
+type proxyExchangeKey struct{}
+
+// proxyExchange 一次请求/响应交换，记录转发到的URL
+type proxyExchange struct {
+	origin string // scheme://host
+	target string // 反向代理为路径及查询参数，正向代理为完整URL
+}
+
+// HttpProxy HTTP流量测量代理
+// 反向代理模式下将请求转发到base_url，正向代理模式下客户端将其设为HTTP代理；
+// 以方法与归一化路径作为operation_type记录每个请求的延迟与请求/响应大小，
+// 并按原始URL累计请求，可导出为按观察到的比例与速率回放的工作负载
+type HttpProxy struct {
+	config    *httpConfig.HttpAdapterConfig
+	target    *url.URL
+	collector interfaces.DefaultMetricsCollector
+	profiler  *WorkloadProfiler
+	proxy     *httputil.ReverseProxy
+	transport *http.Transport
+
+	listener net.Listener
+	server   *http.Server
+
+	requests atomic.Int64
+	errs     atomic.Int64
+	tunnels  atomic.Int64
+	bytesIn  atomic.Int64
+	bytesOut atomic.Int64
+	active   atomic.Int64
+	peak     atomic.Int64
+
+	mutex     sync.Mutex
+	endpoints map[string]*proxyEndpoint
+	workload  map[string]*workloadEntry
+	dropped   int64
+	tunnelsWG sync.WaitGroup
+	conns     map[net.Conn]struct{}
+}
+
+// NewHttpProxy 创建HTTP代理，调用Listen后开始监听
+func NewHttpProxy(config *httpConfig.HttpAdapterConfig, collector interfaces.DefaultMetricsCollector) (*HttpProxy, error) {
+	p := &HttpProxy{
+		config:    config,
+		collector: collector,
+		profiler:  NewWorkloadProfiler(),
+		endpoints: make(map[string]*proxyEndpoint),
+		workload:  make(map[string]*workloadEntry),
+		conns:     make(map[net.Conn]struct{}),
+	}
+	if !config.Proxy.Forward {
+		target, err := url.Parse(config.Connection.BaseURL)
+		if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+			return nil, fmt.Errorf("invalid proxy upstream %q (expected http://host[:port] or https://host[:port])", config.Connection.BaseURL)
+		}
+		p.target = target
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil // 避免经由环境变量中的代理再次转发
+	transport.MaxIdleConnsPerHost = 100
+	transport.ResponseHeaderTimeout = config.Connection.Timeout
+	if config.Connection.TLS.InsecureSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	p.transport = transport
+
+	p.proxy = &httputil.ReverseProxy{
+		Rewrite:      p.rewrite,
+		Transport:    transport,
+		ErrorHandler: p.handleError,
+	}
+	return p, nil
+}
+
+// Mode 代理模式：reverse或forward
+func (p *HttpProxy) Mode() string {
+	if p.config.Proxy.Forward {
+		return "forward"
+	}
+	return "reverse"
+}
+
+// CheckUpstream 反向代理模式下检查上游是否可连接，避免代理启动后所有请求都失败
+func (p *HttpProxy) CheckUpstream(ctx context.Context) error {
+	if p.target == nil {
+		return nil
+	}
+	host := p.target.Host
+	if p.target.Port() == "" {
+		port := "80"
+		if p.target.Scheme == "https" {
+			port = "443"
+		}
+		host = net.JoinHostPort(p.target.Hostname(), port)
+	}
+	dialer := net.Dialer{Timeout: 5 * time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return fmt.Errorf("upstream %s is not reachable: %w", p.target.Redacted(), err)
+	}
+	return conn.Close()
+}
+
+// Listen 开始监听代理地址
+func (p *HttpProxy) Listen() error {
+	listener, err := net.Listen("tcp", p.config.Proxy.Listen)
+	if err != nil {
+		return fmt.Errorf("failed to start HTTP proxy on %s: %w", p.config.Proxy.Listen, err)
+	}
+	p.listener = listener
+	return nil
+}
+
+// Addr 代理实际监听的地址
+func (p *HttpProxy) Addr() string {
+	if p.listener == nil {
+		return p.config.Proxy.Listen
+	}
+	return p.listener.Addr().String()
+}
+
+// Serve 处理请求直到ctx结束，随后等待处理中的请求完成并关闭CONNECT隧道
+func (p *HttpProxy) Serve(ctx context.Context) error {
+	if p.listener == nil {
+		if err := p.Listen(); err != nil {
+			return err
+		}
+	}
+	p.server = &http.Server{Handler: p, ReadHeaderTimeout: 10 * time.Second}
+
+	served := make(chan error, 1)
+	go func() { served <- p.server.Serve(p.listener) }()
+
+	select {
+	case err := <-served:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := p.server.Shutdown(shutdownCtx)
+
+	// 劫持的隧道连接不受Shutdown管理
+	p.mutex.Lock()
+	for conn := range p.conns {
+		conn.Close()
+	}
+	p.mutex.Unlock()
+	p.tunnelsWG.Wait()
+	p.transport.CloseIdleConnections()
+
+	if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	return nil
+}
+
+// ServeHTTP 转发一个请求并记录其延迟与大小
+func (p *HttpProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodConnect {
+		if !p.config.Proxy.Forward {
+			http.Error(w, "CONNECT is only supported in forward proxy mode", http.StatusMethodNotAllowed)
+			return
+		}
+		p.tunnel(w, r)
+		return
+	}
+	if p.config.Proxy.Forward && !r.URL.IsAbs() {
+		http.Error(w, "forward proxy expects absolute request URLs (configure abc-runner as the HTTP proxy)", http.StatusBadRequest)
+		return
+	}
+
+	active := p.active.Add(1)
+	defer p.active.Add(-1)
+	for peak := p.peak.Load(); active > peak && !p.peak.CompareAndSwap(peak, active); peak = p.peak.Load() {
+	}
+	p.profiler.Arrive()
+
+	start := time.Now()
+	body := &proxyRequestBody{ReadCloser: r.Body}
+	if r.Body != nil && r.Body != http.NoBody {
+		r.Body = body
+	}
+	exchange := &proxyExchange{}
+	recorder := &proxyResponseWriter{ResponseWriter: w, status: http.StatusOK}
+	p.proxy.ServeHTTP(recorder, r.WithContext(context.WithValue(r.Context(), proxyExchangeKey{}, exchange)))
+	latency := time.Since(start)
+
+	p.record(r, exchange, recorder, body, latency)
+}
+
+// rewrite 改写转发请求：反向代理指向base_url，正向代理保持客户端请求的URL
+func (p *HttpProxy) rewrite(pr *httputil.ProxyRequest) {
+	if p.target != nil {
+		pr.SetURL(p.target)
+		pr.SetXForwarded()
+	}
+	if exchange, ok := pr.In.Context().Value(proxyExchangeKey{}).(*proxyExchange); ok {
+		exchange.origin = pr.Out.URL.Scheme + "://" + pr.Out.URL.Host
+		if p.target != nil {
+			exchange.target = pr.Out.URL.RequestURI()
+		} else {
+			exchange.target = pr.Out.URL.String()
+		}
+	}
+}
+
+// handleError 上游不可达或响应失败时返回502并记录错误
+func (p *HttpProxy) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	if recorder, ok := w.(*proxyResponseWriter); ok {
+		recorder.err = err
+	}
+	w.WriteHeader(http.StatusBadGateway)
+}
+
+// record 记录一次交换的指标，并累计到接口统计与工作负载
+func (p *HttpProxy) record(r *http.Request, exchange *proxyExchange, recorder *proxyResponseWriter, body *proxyRequestBody, latency time.Duration) {
+	path := exchange.target
+	if path == "" {
+		path = r.URL.RequestURI()
+	}
+	operation := OperationName(r.Method, path)
+	success := recorder.err == nil && recorder.status < 400
+
+	p.requests.Add(1)
+	p.bytesIn.Add(body.size)
+	p.bytesOut.Add(recorder.size)
+	if !success {
+		p.errs.Add(1)
+	}
+	p.profiler.Record(operation, path, body.size, recorder.size)
+
+	if p.collector != nil {
+		result := &interfaces.OperationResult{
+			Success:  success,
+			Duration: latency,
+			IsRead:   r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions,
+			Error:    recorder.err,
+			Metadata: map[string]interface{}{
+				"operation_type": operation,
+				"status_code":    recorder.status,
+				"method":         r.Method,
+				"url":            path,
+				"request_size":   body.size,
+				"response_size":  recorder.size,
+			},
+		}
+		if result.Error == nil && !success {
+			result.Error = fmt.Errorf("HTTP %d", recorder.status)
+		}
+		p.collector.Record(result)
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	endpoint := p.endpoints[operation]
+	if endpoint == nil {
+		endpoint = &proxyEndpoint{}
+		p.endpoints[operation] = endpoint
+	}
+	endpoint.requests++
+	endpoint.requestBytes += body.size
+	endpoint.responseBytes += recorder.size
+	endpoint.totalLatency += latency
+	if latency > endpoint.maxLatency {
+		endpoint.maxLatency = latency
+	}
+	if !success {
+		endpoint.errors++
+	}
+
+	key := r.Method + " " + path
+	entry := p.workload[key]
+	if entry == nil {
+		if len(p.workload) >= maxWorkloadRequests {
+			p.dropped++
+			return
+		}
+		entry = &workloadEntry{
+			request: workloadRequest(r, path, body),
+			origin:  exchange.origin,
+		}
+		p.workload[key] = entry
+	}
+	entry.count++
+}
+
+// workloadRequest 根据首个观察到的请求生成工作负载请求
+// 凭据与逐跳头不写入；JSON、表单与文本请求体在不超过上限时作为样本写入
+func workloadRequest(r *http.Request, path string, body *proxyRequestBody) httpConfig.HttpWorkloadRequest {
+	req := httpConfig.HttpWorkloadRequest{Method: r.Method, Path: path}
+	for name, values := range r.Header {
+		if workloadSkippedHeaders[name] || strings.HasPrefix(name, "Proxy-") {
+			continue
+		}
+		if req.Headers == nil {
+			req.Headers = make(map[string]string)
+		}
+		req.Headers[name] = strings.Join(values, ", ")
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	req.ContentType = mediaType
+	if body.size == 0 || body.size > int64(body.sample.Len()) {
+		return req
+	}
+	sample := body.sample.Bytes()
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		var value interface{}
+		if json.Unmarshal(sample, &value) == nil {
+			req.ContentType = "application/json"
+			req.Body = value
+		}
+	case mediaType == "application/x-www-form-urlencoded":
+		if values, err := url.ParseQuery(string(sample)); err == nil {
+			form := make(map[string]interface{}, len(values))
+			for key := range values {
+				form[key] = values.Get(key)
+			}
+			req.Body = form
+		}
+	case strings.HasPrefix(mediaType, "text/"):
+		req.ContentType = "text/plain"
+		req.Body = string(sample)
+	}
+	return req
+}
+
+// tunnel 正向代理模式下处理CONNECT请求，双向转发加密流量
+func (p *HttpProxy) tunnel(w http.ResponseWriter, r *http.Request) {
+	upstream, err := net.DialTimeout("tcp", r.Host, 10*time.Second)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		upstream.Close()
+		http.Error(w, "connection hijacking not supported", http.StatusInternalServerError)
+		return
+	}
+	client, _, err := hijacker.Hijack()
+	if err != nil {
+		upstream.Close()
+		return
+	}
+	if _, err := client.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n")); err != nil {
+		client.Close()
+		upstream.Close()
+		return
+	}
+	p.tunnels.Add(1)
+
+	p.mutex.Lock()
+	p.conns[client] = struct{}{}
+	p.conns[upstream] = struct{}{}
+	p.mutex.Unlock()
+
+	p.tunnelsWG.Add(2)
+	relay := func(dst, src net.Conn) {
+		defer p.tunnelsWG.Done()
+		io.Copy(dst, src)
+		dst.Close()
+		src.Close()
+		p.mutex.Lock()
+		delete(p.conns, dst)
+		delete(p.conns, src)
+		p.mutex.Unlock()
+	}
+	go relay(upstream, client)
+	go relay(client, upstream)
+}
+
+// Stats 获取代理统计
+func (p *HttpProxy) Stats() ProxyStats {
+	stats := ProxyStats{
+		Listen:          p.Addr(),
+		Mode:            p.Mode(),
+		Requests:        p.requests.Load(),
+		Errors:          p.errs.Load(),
+		Tunnels:         p.tunnels.Load(),
+		BytesIn:         p.bytesIn.Load(),
+		BytesOut:        p.bytesOut.Load(),
+		PeakConcurrency: p.peak.Load(),
+	}
+	if p.target != nil {
+		stats.Upstream = p.target.Redacted()
+	}
+	p.mutex.Lock()
+	stats.Endpoints = len(p.endpoints)
+	stats.DroppedRequests = p.dropped
+	p.mutex.Unlock()
+	return stats
+}
+
+// Profile 观察到的流量特征，未观察到请求时返回nil
+func (p *HttpProxy) Profile() *httpConfig.HttpWorkloadProfile {
+	return p.profiler.Profile()
+}
+
+// Workload 根据观察到的流量生成工作负载
+// 每个请求的权重为其出现次数，总数、并发与时长取观察值，回放时重现请求比例与速率
+func (p *HttpProxy) Workload(elapsed time.Duration) *httpConfig.HttpWorkload {
+	stats := p.Stats()
+	profile := p.Profile()
+	elapsed = elapsed.Round(time.Millisecond)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	workload := &httpConfig.HttpWorkload{}
+	entries := make([]*workloadEntry, 0, len(p.workload))
+	for _, entry := range p.workload {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].count != entries[j].count {
+			return entries[i].count > entries[j].count
+		}
+		if entries[i].request.Path != entries[j].request.Path {
+			return entries[i].request.Path < entries[j].request.Path
+		}
+		return entries[i].request.Method < entries[j].request.Method
+	})
+
+	total := int64(0)
+	origins := make(map[string]int64)
+	for _, entry := range entries {
+		request := entry.request
+		request.Weight = int(entry.count)
+		workload.HTTP.Requests = append(workload.HTTP.Requests, request)
+		total += entry.count
+		origins[entry.origin] += entry.count
+	}
+
+	// 正向代理的请求为完整URL，base_url取请求最多的源站
+	if p.target != nil {
+		workload.HTTP.Connection.BaseURL = p.target.Scheme + "://" + p.target.Host
+	} else {
+		var best int64
+		for origin, count := range origins {
+			if count > best || (count == best && origin < workload.HTTP.Connection.BaseURL) {
+				workload.HTTP.Connection.BaseURL, best = origin, count
+			}
+		}
+	}
+	workload.HTTP.Benchmark.TestCase = "replay"
+	workload.HTTP.Benchmark.Total = int(total)
+	workload.HTTP.Benchmark.Parallels = int(max(stats.PeakConcurrency, 1))
+	workload.HTTP.Benchmark.RampUp = elapsed
+
+	traffic := &workload.Traffic
+	traffic.RecordedAt = time.Now().UTC().Truncate(time.Second)
+	traffic.Mode = stats.Mode
+	traffic.Duration = elapsed
+	traffic.Requests = stats.Requests
+	if elapsed > 0 {
+		traffic.RPS = float64(stats.Requests) / elapsed.Seconds()
+	}
+	traffic.PeakConcurrency = stats.PeakConcurrency
+	traffic.DroppedRequests = p.dropped
+	traffic.Profile = profile
+	for operation, endpoint := range p.endpoints {
+		traffic.Endpoints = append(traffic.Endpoints, httpConfig.HttpTrafficEndpoint{
+			Operation:        operation,
+			Requests:         endpoint.requests,
+			Errors:           endpoint.errors,
+			AvgRequestBytes:  endpoint.requestBytes / endpoint.requests,
+			AvgResponseBytes: endpoint.responseBytes / endpoint.requests,
+			AvgLatency:       endpoint.totalLatency / time.Duration(endpoint.requests),
+			MaxLatency:       endpoint.maxLatency,
+		})
+	}
+	sort.Slice(traffic.Endpoints, func(i, j int) bool {
+		if traffic.Endpoints[i].Requests != traffic.Endpoints[j].Requests {
+			return traffic.Endpoints[i].Requests > traffic.Endpoints[j].Requests
+		}
+		return traffic.Endpoints[i].Operation < traffic.Endpoints[j].Operation
+	})
+	return workload
+}
+
+// OperationName 请求的操作名：方法加归一化路径
+// 查询参数被去除，数字、UUID等标识符路径段替换为{id}，使同一接口的请求归为一类；
+// 完整URL保留主机名
+func OperationName(method, path string) string {
+	u, err := url.Parse(path)
+	if err != nil {
+		return method + " " + path
+	}
+	segments := strings.Split(u.Path, "/")
+	for i, segment := range segments {
+		if isIdentifierSegment(segment) {
+			segments[i] = "{id}"
+		}
+	}
+	normalized := strings.Join(segments, "/")
+	if normalized == "" {
+		normalized = "/"
+	}
+	return method + " " + u.Host + normalized
+}
+
+// isIdentifierSegment 路径段是否为标识符：纯数字、UUID或不短于16位的十六进制串
+func isIdentifierSegment(segment string) bool {
+	if segment == "" {
+		return false
+	}
+	digits, hex := true, true
+	for _, c := range segment {
+		isDigit := c >= '0' && c <= '9'
+		isHex := isDigit || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F') || c == '-'
+		digits = digits && isDigit
+		hex = hex && isHex
+	}
+	return digits || (hex && len(segment) >= 16)
+}
+
+// proxyRequestBody 统计请求体大小并保留不超过上限的样本
+type proxyRequestBody struct {
+	io.ReadCloser
+	size   int64
+	sample bytes.Buffer
+}
+
+// Read 读取请求体
+func (b *proxyRequestBody) Read(data []byte) (int, error) {
+	n, err := b.ReadCloser.Read(data)
+	b.size += int64(n)
+	if room := maxWorkloadBody - b.sample.Len(); room > 0 && n > 0 {
+		b.sample.Write(data[:min(n, room)])
+	}
+	return n, err
+}
+
+// proxyResponseWriter 记录响应状态码与响应体大小
+type proxyResponseWriter struct {
+	http.ResponseWriter
+	status int
+	size   int64
+	err    error
+}
+
+// WriteHeader 记录最终状态码（忽略1xx信息响应）
+func (w *proxyResponseWriter) WriteHeader(status int) {
+	if status >= 200 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write 记录响应体大小
+func (w *proxyResponseWriter) Write(data []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(data)
+	w.size += int64(n)
+	return n, err
+}
+
+// Unwrap 供http.ResponseController访问底层连接（流式响应的Flush）
+func (w *proxyResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,165 @@
+package operations
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	httpConfig "abc-runner/app/adapters/http/config"
+	"abc-runner/app/core/interfaces"
+)
+
+// recordingCollector 保存所有操作结果的指标收集器
+type recordingCollector struct {
+	mutex   sync.Mutex
+	results []*interfaces.OperationResult
+}
+
+func (c *recordingCollector) Record(result *interfaces.OperationResult) {
+	c.mutex.Lock()
+	c.results = append(c.results, result)
+	c.mutex.Unlock()
+}
+
+func (c *recordingCollector) Snapshot() *interfaces.DefaultMetricsSnapshot { return nil }
+func (c *recordingCollector) Reset()                                       {}
+func (c *recordingCollector) Stop()                                        {}
+
+func TestOperationName(t *testing.T) {
+	tests := map[string]string{
+		"/":                       "GET /",
+		"/users/42?expand=orders": "GET /users/{id}",
+		"/users/550e8400-e29b-41d4-a716-446655440000/avatar": "GET /users/{id}/avatar",
+		"/static/app.js":                    "GET /static/app.js",
+		"http://api.example.com/v2/items/7": "GET api.example.com/v2/items/{id}",
+	}
+	for path, want := range tests {
+		if got := OperationName(http.MethodGet, path); got != want {
+			t.Errorf("OperationName(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestHttpProxyWorkload(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/missing":
+			http.NotFound(w, r)
+		case r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"ok":true}`))
+		default:
+			w.Write([]byte(strings.Repeat("x", 100)))
+		}
+	}))
+	defer upstream.Close()
+
+	cfg := httpConfig.LoadDefaultHttpConfig()
+	cfg.Connection.BaseURL = upstream.URL
+	cfg.Proxy.Listen = "127.0.0.1:0"
+	collector := &recordingCollector{}
+	proxy, err := NewHttpProxy(cfg, collector)
+	if err != nil {
+		t.Fatalf("failed to create proxy: %v", err)
+	}
+	if err := proxy.CheckUpstream(context.Background()); err != nil {
+		t.Fatalf("upstream check failed: %v", err)
+	}
+	if err := proxy.Listen(); err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- proxy.Serve(ctx) }()
+
+	send := func(method, path, body string) {
+		request, _ := http.NewRequest(method, "http://"+proxy.Addr()+path, strings.NewReader(body))
+		request.Header.Set("Authorization", "Bearer secret")
+		request.Header.Set("X-Client", "test")
+		if body != "" {
+			request.Header.Set("Content-Type", "application/json; charset=utf-8")
+		}
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatalf("%s %s failed: %v", method, path, err)
+		}
+		response.Body.Close()
+	}
+	for i := 0; i < 3; i++ {
+		send(http.MethodGet, "/users/1", "")
+	}
+	send(http.MethodGet, "/users/2", "")
+	send(http.MethodPost, "/orders", `{"item":"book","qty":2}`)
+	send(http.MethodGet, "/missing", "")
+
+	cancel()
+	if err := <-served; err != nil {
+		t.Fatalf("serve failed: %v", err)
+	}
+
+	stats := proxy.Stats()
+	if stats.Requests != 6 || stats.Errors != 1 || stats.Endpoints != 3 {
+		t.Errorf("stats = %+v, want 6 requests, 1 error, 3 endpoints", stats)
+	}
+	if stats.BytesIn != int64(len(`{"item":"book","qty":2}`)) || stats.BytesOut != 4*100+11+int64(len("404 page not found\n")) {
+		t.Errorf("bytes in/out = %d/%d", stats.BytesIn, stats.BytesOut)
+	}
+	operations := make(map[string]int)
+	for _, result := range collector.results {
+		operations[result.Metadata["operation_type"].(string)]++
+	}
+	if operations["GET /users/{id}"] != 4 || operations["POST /orders"] != 1 || operations["GET /missing"] != 1 {
+		t.Errorf("recorded operations = %v", operations)
+	}
+
+	workload := proxy.Workload(time.Second)
+	if workload.HTTP.Connection.BaseURL != upstream.URL || workload.HTTP.Benchmark.Total != 6 || workload.HTTP.Benchmark.RampUp != time.Second {
+		t.Errorf("workload benchmark = %+v (base_url %s)", workload.HTTP.Benchmark, workload.HTTP.Connection.BaseURL)
+	}
+	first := workload.HTTP.Requests[0]
+	if first.Method != http.MethodGet || first.Path != "/users/1" || first.Weight != 3 {
+		t.Errorf("most frequent request = %+v", first)
+	}
+	var post httpConfig.HttpWorkloadRequest
+	for _, request := range workload.HTTP.Requests {
+		if request.Headers["Authorization"] != "" {
+			t.Errorf("credentials written to workload: %+v", request.Headers)
+		}
+		if request.Method == http.MethodPost {
+			post = request
+		}
+	}
+	body, _ := post.Body.(map[string]interface{})
+	if post.ContentType != "application/json" || body["item"] != "book" || post.Headers["X-Client"] != "test" {
+		t.Errorf("POST request = %+v", post)
+	}
+
+	// 导出后回放：按权重选取的请求比例与观察一致
+	path := filepath.Join(t.TempDir(), "workload.yaml")
+	if err := httpConfig.SaveHttpWorkload(path, workload); err != nil {
+		t.Fatalf("failed to save workload: %v", err)
+	}
+	loaded, err := httpConfig.LoadHttpWorkload(path)
+	if err != nil {
+		t.Fatalf("failed to load workload: %v", err)
+	}
+	replay := httpConfig.LoadDefaultHttpConfig()
+	loaded.Apply(replay)
+	if err := replay.Validate(); err != nil {
+		t.Fatalf("replay config invalid: %v", err)
+	}
+	factory := NewHttpOperationFactory(replay)
+	picked := make(map[string]int)
+	for job := 0; job < 6000; job++ {
+		operation := factory.CreateOperation(job, nil)
+		picked[operation.Metadata["operation_type"]]++
+	}
+	if share := float64(picked["GET /users/{id}"]) / 6000; share < 0.6 || share > 0.73 {
+		t.Errorf("replayed GET /users/{id} share = %.2f, want about 4/6 (%v)", share, picked)
+	}
+}
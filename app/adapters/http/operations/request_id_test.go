@@ -0,0 +1,57 @@
+package operations
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpConfig "abc-runner/app/adapters/http/config"
+	"abc-runner/app/adapters/http/connection"
+	"abc-runner/app/core/execution"
+)
+
+func TestRequestIDHeader(t *testing.T) {
+	received := make(chan http.Header, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header
+	}))
+	defer server.Close()
+
+	config := httpConfig.LoadDefaultHttpConfig()
+	config.Connection.BaseURL = server.URL
+	config.Requests = []httpConfig.HttpRequestConfig{{Method: "GET", Path: "/", Weight: 1}}
+	pool, err := connection.NewHttpConnectionPool(config)
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	defer pool.Close()
+	executor := NewHttpExecutor(pool, config, nil)
+	factory := NewHttpOperationFactory(config)
+
+	ctx := execution.WithRequestID(context.Background(), "3f9c2a1b-7")
+	ctx = execution.WithTraceContext(ctx, execution.TraceContext{
+		TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7", Sampled: true,
+	})
+	if result, err := executor.ExecuteOperation(ctx, factory.CreateOperation(0, nil)); err != nil || !result.Success {
+		t.Fatalf("request failed: %v", err)
+	}
+	headers := <-received
+	if header := headers.Get(execution.RequestIDHeader); header != "3f9c2a1b-7" {
+		t.Errorf("%s = %q, want 3f9c2a1b-7", execution.RequestIDHeader, header)
+	}
+	if header := headers.Get(execution.TraceparentHeader); header != "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01" {
+		t.Errorf("unexpected %s header %q", execution.TraceparentHeader, header)
+	}
+
+	// 未启用请求ID与trace-context时不发送对应请求头
+	if _, err := executor.ExecuteOperation(context.Background(), factory.CreateOperation(1, nil)); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	headers = <-received
+	for _, name := range []string{execution.RequestIDHeader, execution.TraceparentHeader} {
+		if header := headers.Get(name); header != "" {
+			t.Errorf("unexpected %s header %q", name, header)
+		}
+	}
+}
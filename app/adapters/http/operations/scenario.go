@@ -0,0 +1,250 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	httpConfig "abc-runner/app/adapters/http/config"
+	"abc-runner/app/adapters/http/connection"
+)
+
+// scenarioVariableRef 请求模板中的变量引用 ${name}
+var scenarioVariableRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// scenarioStep 预处理后的场景步骤
+type scenarioStep struct {
+	name       string
+	request    httpConfig.HttpRequestConfig
+	extract    []scenarioExtractor
+	assertions []responseAssertion
+}
+
+// scenarioExtractor 预编译的变量提取规则
+type scenarioExtractor struct {
+	variable string
+	json     string
+	header   string
+	regex    *regexp.Regexp
+}
+
+// ScenarioRunner 请求链场景执行器
+// 每次Run按顺序执行全部步骤，变量作用域为单次旅程；任一步骤失败或变量提取失败时中止旅程
+type ScenarioRunner struct {
+	variables  map[string]string
+	steps      []scenarioStep
+	tracker    *EndpointTracker
+	validation *ValidationTracker
+}
+
+// NewScenarioRunner 创建场景执行器，配置须已通过HttpScenarioConfig.Validate验证
+// 各步骤的响应执行validation中的全局断言与步骤自身的断言
+func NewScenarioRunner(config httpConfig.HttpScenarioConfig, validation *ValidationTracker) *ScenarioRunner {
+	runner := &ScenarioRunner{variables: config.Variables, validation: validation}
+	requests := make([]httpConfig.HttpRequestConfig, 0, len(config.Steps))
+	for i, step := range config.Steps {
+		request := step.HttpRequestConfig
+		if request.Name == "" {
+			request.Name = fmt.Sprintf("%d. %s", i+1, OperationName(request.Method, request.Path))
+		}
+		compiled := scenarioStep{name: request.Name, request: request, assertions: compileAssertions(step.Assert)}
+		for _, extract := range step.Extract {
+			extractor := scenarioExtractor{variable: extract.Var, json: extract.JSON, header: extract.Header}
+			if extract.Regex != "" {
+				extractor.regex = regexp.MustCompile(extract.Regex)
+			}
+			compiled.extract = append(compiled.extract, extractor)
+		}
+		runner.steps = append(runner.steps, compiled)
+		requests = append(requests, request)
+	}
+	runner.tracker = NewEndpointTracker(requests)
+	return runner
+}
+
+// StepStats 按步骤顺序获取各步骤的统计
+func (s *ScenarioRunner) StepStats() []EndpointStats {
+	return s.tracker.Stats()
+}
+
+// Run 执行一次用户旅程，返回完成的步骤数；最后一个响应随结果返回
+func (s *ScenarioRunner) Run(ctx context.Context, client *connection.HttpClient, jobID int) (int, *connection.HttpResponse, error) {
+	variables := make(map[string]string, len(s.variables)+2)
+	for name, value := range s.variables {
+		variables[name] = value
+	}
+	variables["job_id"] = strconv.Itoa(jobID)
+	variables["rand"] = strconv.Itoa(rand.Int())
+
+	var response *connection.HttpResponse
+	for i, step := range s.steps {
+		request, err := substituteRequest(step.request, variables)
+		if err != nil {
+			return i, response, fmt.Errorf("step %q: %w", step.name, err)
+		}
+
+		start := time.Now()
+		var stepErr error
+		response, stepErr = client.ExecuteRequest(ctx, request)
+		latency := time.Since(start)
+		statusCode, size := 0, 0
+		if response != nil {
+			statusCode, size = response.StatusCode, len(response.Body)
+			latency -= response.AuthDuration
+		}
+		success := stepErr == nil && response != nil && response.IsSuccess()
+		var validationErr error
+		if s.validation != nil && s.validation.Applies(step.assertions) {
+			success, validationErr = s.validation.Validate(step.name, step.assertions, response, stepErr)
+		}
+		s.tracker.Record(step.name, statusCode, size, latency, success)
+
+		if stepErr != nil {
+			return i, response, fmt.Errorf("step %q: %w", step.name, stepErr)
+		}
+		if validationErr != nil {
+			return i, response, fmt.Errorf("step %q: %w", step.name, validationErr)
+		}
+		if !success {
+			return i, response, fmt.Errorf("step %q: HTTP %d", step.name, statusCode)
+		}
+
+		for _, extractor := range step.extract {
+			value, ok := extractor.extract(response)
+			if !ok {
+				return i, response, fmt.Errorf("step %q: could not extract %s from the response", step.name, extractor.variable)
+			}
+			variables[extractor.variable] = value
+		}
+	}
+	return len(s.steps), response, nil
+}
+
+// extract 从响应中提取变量值
+func (e scenarioExtractor) extract(response *connection.HttpResponse) (string, bool) {
+	switch {
+	case e.header != "":
+		value := response.Headers.Get(e.header)
+		return value, value != ""
+	case e.regex != nil:
+		match := e.regex.FindSubmatch(response.Body)
+		if match == nil {
+			return "", false
+		}
+		if len(match) > 1 {
+			return string(match[1]), true
+		}
+		return string(match[0]), true
+	default:
+		var document interface{}
+		if err := json.Unmarshal(response.Body, &document); err != nil {
+			return "", false
+		}
+		value, ok := jsonPathValue(document, e.json)
+		if !ok {
+			return "", false
+		}
+		return scalarString(value), true
+	}
+}
+
+// jsonPathValue 按路径取JSON值，支持 $.a.b[0].c、a.b.0.c 等形式的字段与数组下标
+func jsonPathValue(document interface{}, path string) (interface{}, bool) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	path = strings.NewReplacer("[", ".", "]", "").Replace(path)
+	current := document
+	for _, part := range strings.Split(path, ".") {
+		if part == "" {
+			continue
+		}
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[part]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case []interface{}:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			current = node[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, current != nil
+}
+
+// scalarString 将提取到的JSON值转换为字符串，对象与数组保留JSON形式
+func scalarString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+// substituteRequest 将变量注入请求的路径、请求头与请求体中的字符串
+func substituteRequest(request httpConfig.HttpRequestConfig, variables map[string]string) (httpConfig.HttpRequestConfig, error) {
+	var missing string
+	replace := func(text string) string {
+		return scenarioVariableRef.ReplaceAllStringFunc(text, func(ref string) string {
+			name := ref[2 : len(ref)-1]
+			value, ok := variables[name]
+			if !ok && missing == "" {
+				missing = name
+			}
+			return value
+		})
+	}
+
+	request.Path = replace(request.Path)
+	if request.Headers != nil {
+		headers := make(map[string]string, len(request.Headers))
+		for name, value := range request.Headers {
+			headers[name] = replace(value)
+		}
+		request.Headers = headers
+	}
+	request.Body = substituteValue(request.Body, replace)
+
+	if missing != "" {
+		return request, fmt.Errorf("undefined variable ${%s}", missing)
+	}
+	return request, nil
+}
+
+// substituteValue 递归替换请求体中的字符串
+func substituteValue(value interface{}, replace func(string) string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return replace(v)
+	case map[string]interface{}:
+		substituted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			substituted[key] = substituteValue(item, replace)
+		}
+		return substituted
+	case []interface{}:
+		substituted := make([]interface{}, len(v))
+		for i, item := range v {
+			substituted[i] = substituteValue(item, replace)
+		}
+		return substituted
+	default:
+		return value
+	}
+}
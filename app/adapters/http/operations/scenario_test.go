@@ -0,0 +1,134 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	httpConfig "abc-runner/app/adapters/http/config"
+	"abc-runner/app/adapters/http/connection"
+)
+
+func TestJSONPathValue(t *testing.T) {
+	var document interface{}
+	json.Unmarshal([]byte(`{"data":{"token":"abc","items":[{"id":7},{"id":8.5}],"ok":true}}`), &document)
+	tests := map[string]string{
+		"$.data.token":      "abc",
+		"data.items[0].id":  "7",
+		"$.data.items.1.id": "8.5",
+		"$.data.ok":         "true",
+		"$.data.items[0]":   `{"id":7}`,
+	}
+	for path, want := range tests {
+		value, ok := jsonPathValue(document, path)
+		if !ok || scalarString(value) != want {
+			t.Errorf("jsonPathValue(%q) = %v (%v), want %s", path, value, ok, want)
+		}
+	}
+	for _, path := range []string{"$.data.missing", "$.data.items[5].id", "$.data.token.x"} {
+		if _, ok := jsonPathValue(document, path); ok {
+			t.Errorf("jsonPathValue(%q) should not match", path)
+		}
+	}
+}
+
+func TestExecuteScenario(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			var login map[string]string
+			json.NewDecoder(r.Body).Decode(&login)
+			if login["password"] != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("X-Session-ID", "s-"+login["user"])
+			fmt.Fprintf(w, `{"data":{"token":"tok-%s"}}`, login["user"])
+		case "/orders":
+			if r.Header.Get("Authorization") != "Bearer tok-u1" || r.Header.Get("X-Session") != "s-u1" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			fmt.Fprint(w, `<a href="/orders/order-42">latest</a>`)
+		case "/orders/42":
+			if r.Header.Get("Authorization") != "Bearer tok-u1" {
+				w.WriteHeader(http.StatusForbidden)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	config := httpConfig.LoadDefaultHttpConfig()
+	config.Connection.BaseURL = server.URL
+	config.Benchmark.TestCase = "scenario"
+	config.Benchmark.Scenario = httpConfig.HttpScenarioConfig{
+		Variables: map[string]string{"password": "secret"},
+		Steps: []httpConfig.HttpScenarioStep{
+			{
+				HttpRequestConfig: httpConfig.HttpRequestConfig{
+					Name: "login", Method: "POST", Path: "/login",
+					Body: map[string]interface{}{"user": "u${job_id}", "password": "${password}"},
+				},
+				Extract: []httpConfig.HttpExtractConfig{
+					{Var: "token", JSON: "$.data.token"},
+					{Var: "session", Header: "X-Session-ID"},
+				},
+			},
+			{
+				HttpRequestConfig: httpConfig.HttpRequestConfig{
+					Method: "GET", Path: "/orders",
+					Headers: map[string]string{"Authorization": "Bearer ${token}", "X-Session": "${session}"},
+				},
+				Extract: []httpConfig.HttpExtractConfig{{Var: "order", Regex: `order-(\d+)`}},
+			},
+			{
+				HttpRequestConfig: httpConfig.HttpRequestConfig{
+					Method: "GET", Path: "/orders/${order}",
+					Headers: map[string]string{"Authorization": "Bearer ${token}"},
+				},
+			},
+		},
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("invalid config: %v", err)
+	}
+
+	pool, err := connection.NewHttpConnectionPool(config)
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	defer pool.Close()
+	executor := NewHttpExecutor(pool, config, nil)
+	factory := NewHttpOperationFactory(config)
+
+	// job 1的令牌与会话能通过/orders的校验，job 2在第二步被拒绝
+	result, err := executor.ExecuteOperation(context.Background(), factory.CreateOperation(1, nil))
+	if err != nil || !result.Success || result.Metadata["steps_completed"] != 3 {
+		t.Fatalf("expected the journey to complete, got %v (%v)", err, result.Metadata["steps_completed"])
+	}
+	result, err = executor.ExecuteOperation(context.Background(), factory.CreateOperation(2, nil))
+	if err == nil || result.Success || result.Metadata["steps_completed"] != 1 || !strings.Contains(err.Error(), "HTTP 403") {
+		t.Fatalf("expected the journey to fail at step 2, got %v (%v)", err, result.Metadata["steps_completed"])
+	}
+
+	stats := executor.ScenarioStepStats()
+	if len(stats) != 3 || stats[0].Name != "login" || stats[1].Name != "2. GET /orders" || stats[2].Name != "3. GET /orders/${order}" {
+		t.Fatalf("unexpected steps: %+v", stats)
+	}
+	if stats[0].Requests != 2 || stats[1].Requests != 2 || stats[1].Errors != 1 || stats[1].StatusCodes[http.StatusForbidden] != 1 || stats[2].Requests != 1 {
+		t.Errorf("unexpected step stats: %+v", stats)
+	}
+
+	// 未定义的变量在发送请求前使旅程失败
+	config.Benchmark.Scenario.Steps[2].Path = "/orders/${missing}"
+	executor = NewHttpExecutor(pool, config, nil)
+	if _, err := executor.ExecuteOperation(context.Background(), factory.CreateOperation(1, nil)); err == nil || !strings.Contains(err.Error(), "${missing}") {
+		t.Errorf("expected undefined variable error, got %v", err)
+	}
+}
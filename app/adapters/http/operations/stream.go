@@ -0,0 +1,262 @@
+package operations
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"abc-runner/app/core/metrics"
+)
+
+// 流结束的原因
+const (
+	StreamEndCompleted = "completed"    // 服务端结束了响应
+	StreamEndDuration  = "duration"     // 达到配置的保持时间
+	StreamEndMaxEvents = "max_events"   // 达到配置的事件数上限
+	StreamEndIdle      = "idle_timeout" // 超过空闲超时未收到事件
+	StreamEndCancelled = "cancelled"    // 测试结束或被中断
+	StreamEndError     = "error"        // 连接失败、状态码错误或读取出错
+)
+
+// StreamReader 从流式响应体中切分事件
+// sse模式按SSE协议以空行结束一个事件，只有包含data字段的事件才计数（仅含注释的心跳不计）；
+// chunked模式以每次读到的数据为一个事件
+type StreamReader struct {
+	mode     string
+	counter  *countingReader
+	buffered *bufio.Reader
+	chunk    []byte
+	pending  error // chunked模式中随数据一起返回的错误，在下一次读取时返回
+}
+
+// countingReader 统计读取的字节数
+type countingReader struct {
+	reader io.Reader
+	bytes  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// NewStreamReader 创建流事件读取器，mode为sse或chunked
+func NewStreamReader(body io.Reader, mode string) *StreamReader {
+	counter := &countingReader{reader: body}
+	reader := &StreamReader{mode: mode, counter: counter}
+	if mode == "chunked" {
+		reader.chunk = make([]byte, 32*1024)
+	} else {
+		reader.buffered = bufio.NewReader(counter)
+	}
+	return reader
+}
+
+// Bytes 已从响应体读取的字节数
+func (r *StreamReader) Bytes() int64 {
+	return r.counter.bytes
+}
+
+// Next 阻塞直到下一个事件，返回事件的字节数；响应结束时返回io.EOF
+func (r *StreamReader) Next() (int, error) {
+	if r.mode == "chunked" {
+		return r.nextChunk()
+	}
+	return r.nextEvent()
+}
+
+// nextChunk 读取下一块到达的数据
+func (r *StreamReader) nextChunk() (int, error) {
+	if r.pending != nil {
+		return 0, r.pending
+	}
+	for {
+		n, err := r.counter.Read(r.chunk)
+		if n > 0 {
+			r.pending = err
+			return n, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+}
+
+// nextEvent 读取下一个SSE事件，响应在事件结束前中断时丢弃不完整的事件
+func (r *StreamReader) nextEvent() (int, error) {
+	size, hasData, continued := 0, false, false
+	for {
+		line, err := r.buffered.ReadSlice('\n')
+		size += len(line)
+		if err == bufio.ErrBufferFull {
+			// 超长行：字段名在第一段中，其余部分属于同一行
+			if !continued && isSSEDataField(line) {
+				hasData = true
+			}
+			continued = true
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		if continued {
+			continued = false
+			continue
+		}
+
+		line = bytes.TrimRight(line, "\r\n")
+		if len(line) == 0 {
+			if hasData {
+				return size, nil
+			}
+			// 心跳或没有data的事件不计数
+			size = 0
+			continue
+		}
+		if isSSEDataField(line) {
+			hasData = true
+		}
+	}
+}
+
+// isSSEDataField 判断SSE行是否为data字段
+func isSSEDataField(line []byte) bool {
+	return bytes.HasPrefix(line, []byte("data")) && (len(line) == 4 || line[4] == ':')
+}
+
+// StreamStats 流式响应测试统计
+type StreamStats struct {
+	Streams            int64                  `json:"streams"`               // 打开（含尝试打开）的流数
+	FailedStreams      int64                  `json:"failed_streams"`        // 失败的流数
+	Events             int64                  `json:"events"`                // 收到的事件总数
+	Bytes              int64                  `json:"bytes"`                 // 读取的响应体字节数
+	EventsPerSec       float64                `json:"events_per_sec"`        // 所有流合计的事件速率（首个流打开到最后一个流结束）
+	StreamEventsPerSec float64                `json:"stream_events_per_sec"` // 单个流的平均事件速率（事件数/流的总存活时间）
+	Endings            map[string]int64       `json:"endings"`               // 各结束原因的流数
+	TTFB               metrics.LatencyMetrics `json:"ttfb"`                  // 请求到响应第一个字节的时间
+	FirstEvent         metrics.LatencyMetrics `json:"first_event"`           // 请求到第一个事件的时间
+	InterEvent         metrics.LatencyMetrics `json:"inter_event"`           // 相邻事件的间隔
+	Lifetime           metrics.LatencyMetrics `json:"lifetime"`              // 流从请求到关闭的存活时间
+}
+
+// ToMap 转换为报告使用的map
+func (s StreamStats) ToMap() map[string]interface{} {
+	endings := make(map[string]interface{}, len(s.Endings))
+	for reason, count := range s.Endings {
+		endings[reason] = count
+	}
+	return map[string]interface{}{
+		"streams":               s.Streams,
+		"failed_streams":        s.FailedStreams,
+		"events":                s.Events,
+		"bytes":                 s.Bytes,
+		"events_per_sec":        s.EventsPerSec,
+		"stream_events_per_sec": s.StreamEventsPerSec,
+		"endings":               endings,
+		"ttfb":                  latencyToMap(s.TTFB),
+		"first_event":           latencyToMap(s.FirstEvent),
+		"inter_event":           latencyToMap(s.InterEvent),
+		"lifetime":              latencyToMap(s.Lifetime),
+	}
+}
+
+// StreamTracker 流式响应统计器
+type StreamTracker struct {
+	streams       atomic.Int64
+	failedStreams atomic.Int64
+	events        atomic.Int64
+	bytes         atomic.Int64
+
+	mutex     sync.Mutex
+	endings   map[string]int64
+	firstOpen time.Time
+	lastClose time.Time
+	alive     time.Duration // 所有流存活时间之和
+
+	ttfb       *metrics.LatencyTracker
+	firstEvent *metrics.LatencyTracker
+	interEvent *metrics.LatencyTracker
+	lifetime   *metrics.LatencyTracker
+}
+
+// NewStreamTracker 创建流式响应统计器
+func NewStreamTracker() *StreamTracker {
+	config := metrics.LatencyConfig{
+		SamplingRate: 1.0,
+	}
+	return &StreamTracker{
+		endings:    make(map[string]int64),
+		ttfb:       metrics.NewLatencyTracker(config),
+		firstEvent: metrics.NewLatencyTracker(config),
+		interEvent: metrics.NewLatencyTracker(config),
+		lifetime:   metrics.NewLatencyTracker(config),
+	}
+}
+
+// RecordTTFB 记录收到响应第一个字节的时间
+func (t *StreamTracker) RecordTTFB(ttfb time.Duration) {
+	t.ttfb.Record(ttfb)
+}
+
+// RecordEvent 记录一个事件，first表示流的第一个事件，此时latency为请求到该事件的时间，否则为与上一个事件的间隔
+func (t *StreamTracker) RecordEvent(first bool, latency time.Duration) {
+	t.events.Add(1)
+	if first {
+		t.firstEvent.Record(latency)
+	} else {
+		t.interEvent.Record(latency)
+	}
+}
+
+// RecordStream 记录一个结束的流
+func (t *StreamTracker) RecordStream(success bool, ending string, opened, closed time.Time, size int64) {
+	t.streams.Add(1)
+	if !success {
+		t.failedStreams.Add(1)
+	}
+	t.bytes.Add(size)
+	t.lifetime.Record(closed.Sub(opened))
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.endings[ending]++
+	t.alive += closed.Sub(opened)
+	if t.firstOpen.IsZero() || opened.Before(t.firstOpen) {
+		t.firstOpen = opened
+	}
+	if closed.After(t.lastClose) {
+		t.lastClose = closed
+	}
+}
+
+// Stats 获取统计结果
+func (t *StreamTracker) Stats() StreamStats {
+	stats := StreamStats{
+		Streams:       t.streams.Load(),
+		FailedStreams: t.failedStreams.Load(),
+		Events:        t.events.Load(),
+		Bytes:         t.bytes.Load(),
+		TTFB:          t.ttfb.GetMetrics(),
+		FirstEvent:    t.firstEvent.GetMetrics(),
+		InterEvent:    t.interEvent.GetMetrics(),
+		Lifetime:      t.lifetime.GetMetrics(),
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	stats.Endings = make(map[string]int64, len(t.endings))
+	for reason, count := range t.endings {
+		stats.Endings[reason] = count
+	}
+	if wall := t.lastClose.Sub(t.firstOpen); wall > 0 {
+		stats.EventsPerSec = float64(stats.Events) / wall.Seconds()
+	}
+	if t.alive > 0 {
+		stats.StreamEventsPerSec = float64(stats.Events) / t.alive.Seconds()
+	}
+	return stats
+}
@@ -0,0 +1,149 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	httpConfig "abc-runner/app/adapters/http/config"
+	"abc-runner/app/adapters/http/connection"
+)
+
+func TestStreamReaderSSE(t *testing.T) {
+	body := ": keep-alive\n\n" +
+		"event: tick\r\ndata: 1\r\n\r\n" +
+		"id: 2\nretry: 100\n\n" +
+		"data\n\n" +
+		"data: " + strings.Repeat("x", 8192) + "\ndata: more\n\n" +
+		"data: unterminated"
+
+	reader := NewStreamReader(strings.NewReader(body), "sse")
+	var sizes []int
+	for {
+		size, err := reader.Next()
+		if err != nil {
+			if err != io.EOF {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			break
+		}
+		sizes = append(sizes, size)
+	}
+
+	// 心跳与没有data的事件不计数，末尾不完整的事件被丢弃
+	expected := []int{len("event: tick\r\ndata: 1\r\n\r\n"), len("data\n\n"), 6 + 8192 + 1 + len("data: more\n\n")}
+	if fmt.Sprint(sizes) != fmt.Sprint(expected) {
+		t.Errorf("event sizes = %v, expected %v", sizes, expected)
+	}
+	if reader.Bytes() != int64(len(body)) {
+		t.Errorf("Bytes = %d, expected %d", reader.Bytes(), len(body))
+	}
+}
+
+func TestExecuteStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher := w.(http.Flusher)
+		switch r.URL.Path {
+		case "/events":
+			if r.Header.Get("Accept") != "text/event-stream" {
+				http.Error(w, "not an event stream request", http.StatusNotAcceptable)
+				return
+			}
+			w.Header().Set("Content-Type", "text/event-stream")
+			for i := 0; ; i++ {
+				if _, err := fmt.Fprintf(w, ": ping\n\ndata: %d\n\n", i); err != nil {
+					return
+				}
+				flusher.Flush()
+				select {
+				case <-r.Context().Done():
+					return
+				case <-time.After(10 * time.Millisecond):
+				}
+			}
+		case "/short":
+			for i := 0; i < 3; i++ {
+				fmt.Fprintf(w, "chunk %d", i)
+				flusher.Flush()
+				time.Sleep(5 * time.Millisecond)
+			}
+		case "/stall":
+			fmt.Fprint(w, "data: first\n\n")
+			flusher.Flush()
+			<-r.Context().Done()
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	config := httpConfig.LoadDefaultHttpConfig()
+	config.Connection.BaseURL = server.URL
+	config.Connection.Timeout = 50 * time.Millisecond
+	config.Benchmark.TestCase = "stream"
+	config.Benchmark.Stream.Duration = 200 * time.Millisecond
+	pool, err := connection.NewHttpConnectionPool(config)
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	defer pool.Close()
+
+	executor := NewHttpExecutor(pool, config, nil)
+	factory := NewHttpOperationFactory(config)
+	run := func(jobID int) map[string]interface{} {
+		result, _ := executor.ExecuteOperation(context.Background(), factory.CreateOperation(jobID, nil))
+		value, _ := result.Value.(map[string]interface{})
+		value["success"] = result.Success
+		value["duration"] = result.Duration
+		return value
+	}
+
+	// 流的保持时间超过请求超时，并在到达保持时间时正常结束
+	value := run(0)
+	if value["success"] != true || value["ending"] != StreamEndDuration || value["events"].(int64) < 5 {
+		t.Errorf("expected a stream kept open for its duration, got %v", value)
+	}
+	if value["duration"].(time.Duration) < 200*time.Millisecond {
+		t.Errorf("expected the stream to live at least 200ms, got %v", value["duration"])
+	}
+
+	config.Benchmark.Stream.MaxEvents = 3
+	if value := run(1); value["success"] != true || value["ending"] != StreamEndMaxEvents || value["events"] != int64(3) {
+		t.Errorf("expected the stream to close after 3 events, got %v", value)
+	}
+
+	config.Benchmark.Stream.MaxEvents = 0
+	config.Benchmark.Stream.Path = "/short"
+	config.Benchmark.Stream.Mode = "chunked"
+	factory = NewHttpOperationFactory(config)
+	if value := run(2); value["success"] != true || value["ending"] != StreamEndCompleted || value["events"].(int64) < 1 || value["bytes"] != int64(21) {
+		t.Errorf("expected a completed chunked stream of 21 bytes, got %v", value)
+	}
+
+	config.Benchmark.Stream.Path = "/stall"
+	config.Benchmark.Stream.Mode = "sse"
+	config.Benchmark.Stream.IdleTimeout = 30 * time.Millisecond
+	factory = NewHttpOperationFactory(config)
+	if value := run(3); value["success"] != false || value["ending"] != StreamEndIdle || value["events"] != int64(1) {
+		t.Errorf("expected the stalled stream to hit the idle timeout, got %v", value)
+	}
+
+	config.Benchmark.Stream.Path = "/missing"
+	factory = NewHttpOperationFactory(config)
+	if value := run(4); value["success"] != false || value["ending"] != StreamEndError || value["status_code"] != 404 {
+		t.Errorf("expected a failed stream for a 404 response, got %v", value)
+	}
+
+	stats := executor.StreamStats()
+	if stats.Streams != 5 || stats.FailedStreams != 2 || stats.Endings[StreamEndDuration] != 1 || stats.Endings[StreamEndIdle] != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if stats.EventsPerSec <= 0 || stats.InterEvent.P50 <= 0 || stats.TTFB.P50 <= 0 || stats.TTFB.P50 > stats.FirstEvent.P99 {
+		t.Errorf("unexpected stream latencies: %+v", stats)
+	}
+}
@@ -0,0 +1,299 @@
+package operations
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	httpConfig "abc-runner/app/adapters/http/config"
+	"abc-runner/app/adapters/http/connection"
+)
+
+// ErrorCategoryValidation 响应断言未通过的错误类别，与传输错误、状态码错误区分统计
+const ErrorCategoryValidation = "validation_failed"
+
+// maxValidationSamples 每条断言保留的失败响应样本数
+const maxValidationSamples = 3
+
+// maxSampleBodyBytes 失败样本中保留的响应体字节数
+const maxSampleBodyBytes = 512
+
+// responseAssertion 预编译的响应断言
+type responseAssertion struct {
+	name     string
+	status   [][2]int
+	header   string
+	json     string
+	body     string
+	equals   string
+	contains string
+	matches  *regexp.Regexp
+	hasValue bool // header/json断言是否指定了equals、contains或matches
+}
+
+// compileAssertions 预编译断言，断言须已通过HttpAssertion.Validate验证
+func compileAssertions(assertions []httpConfig.HttpAssertion) []responseAssertion {
+	compiled := make([]responseAssertion, 0, len(assertions))
+	for _, assertion := range assertions {
+		a := responseAssertion{
+			name:     assertion.String(),
+			header:   assertion.Header,
+			json:     assertion.JSON,
+			body:     assertion.BodyContains,
+			equals:   assertion.Equals,
+			contains: assertion.Contains,
+			hasValue: assertion.Equals != "" || assertion.Contains != "" || assertion.Matches != "",
+		}
+		if assertion.Status != "" {
+			a.status, _ = httpConfig.ParseStatusRanges(assertion.Status)
+		}
+		if assertion.Matches != "" {
+			a.matches = regexp.MustCompile(assertion.Matches)
+		}
+		compiled = append(compiled, a)
+	}
+	return compiled
+}
+
+// hasStatusAssertion 断言中是否包含状态码断言；包含时状态码由断言判定，非2xx也可以通过
+func hasStatusAssertion(assertions []responseAssertion) bool {
+	for _, assertion := range assertions {
+		if assertion.status != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// check 对响应执行断言，未通过时返回原因；document为惰性解析的响应JSON
+func (a *responseAssertion) check(response *connection.HttpResponse, document *responseDocument) (bool, string) {
+	switch {
+	case a.status != nil:
+		for _, r := range a.status {
+			if response.StatusCode >= r[0] && response.StatusCode <= r[1] {
+				return true, ""
+			}
+		}
+		return false, fmt.Sprintf("status %d", response.StatusCode)
+	case a.body != "":
+		if strings.Contains(string(response.Body), a.body) {
+			return true, ""
+		}
+		return false, "text not found in body"
+	case a.header != "":
+		values := response.Headers.Values(a.header)
+		if len(values) == 0 {
+			return false, "header missing"
+		}
+		return a.checkValue(strings.Join(values, ", "))
+	default:
+		value, ok := document.value(response, a.json)
+		if !ok {
+			if document.err != nil {
+				return false, "body is not JSON"
+			}
+			return false, "path not found"
+		}
+		return a.checkValue(scalarString(value))
+	}
+}
+
+// checkValue 按equals、contains或matches检查header/json的值
+func (a *responseAssertion) checkValue(value string) (bool, string) {
+	switch {
+	case !a.hasValue:
+		return true, ""
+	case a.equals != "" && value == a.equals,
+		a.contains != "" && strings.Contains(value, a.contains),
+		a.matches != nil && a.matches.MatchString(value):
+		return true, ""
+	default:
+		return false, fmt.Sprintf("got %q", truncateText(value, 64))
+	}
+}
+
+// responseDocument 惰性解析的响应JSON，同一响应的多条json断言只解析一次
+type responseDocument struct {
+	parsed   bool
+	document interface{}
+	err      error
+}
+
+// value 按路径取响应JSON中的值
+func (d *responseDocument) value(response *connection.HttpResponse, path string) (interface{}, bool) {
+	if !d.parsed {
+		d.parsed = true
+		d.err = json.Unmarshal(response.Body, &d.document)
+	}
+	if d.err != nil {
+		return nil, false
+	}
+	return jsonPathValue(d.document, path)
+}
+
+// AssertionStats 单条断言的统计
+type AssertionStats struct {
+	Assertion string                    `json:"assertion"`
+	Failures  int64                     `json:"failures"`
+	Samples   []ValidationFailureSample `json:"samples,omitempty"` // 最早的几个失败响应
+}
+
+// ValidationFailureSample 断言未通过的响应样本
+type ValidationFailureSample struct {
+	Request    string `json:"request"`
+	StatusCode int    `json:"status_code"`
+	Reason     string `json:"reason"`
+	Body       string `json:"body"` // 截断后的响应体
+}
+
+// ValidationStats 响应断言统计
+// 请求按结果分为：未得到响应的传输错误、状态码非2xx（未断言状态码时）、断言未通过与通过
+type ValidationStats struct {
+	Requests         int64            `json:"requests"`
+	Passed           int64            `json:"passed"`
+	ValidationFailed int64            `json:"validation_failed"`
+	StatusErrors     int64            `json:"status_errors"`
+	TransportErrors  int64            `json:"transport_errors"`
+	Assertions       []AssertionStats `json:"assertions"`
+}
+
+// ToMap 转换为报告使用的map
+func (s ValidationStats) ToMap() map[string]interface{} {
+	assertions := make([]map[string]interface{}, 0, len(s.Assertions))
+	for _, assertion := range s.Assertions {
+		samples := make([]map[string]interface{}, 0, len(assertion.Samples))
+		for _, sample := range assertion.Samples {
+			samples = append(samples, map[string]interface{}{
+				"request":     sample.Request,
+				"status_code": sample.StatusCode,
+				"reason":      sample.Reason,
+				"body":        sample.Body,
+			})
+		}
+		assertions = append(assertions, map[string]interface{}{
+			"assertion": assertion.Assertion,
+			"failures":  assertion.Failures,
+			"samples":   samples,
+		})
+	}
+	return map[string]interface{}{
+		"requests":          s.Requests,
+		"passed":            s.Passed,
+		"validation_failed": s.ValidationFailed,
+		"status_errors":     s.StatusErrors,
+		"transport_errors":  s.TransportErrors,
+		"assertions":        assertions,
+	}
+}
+
+// ValidationTracker 响应断言执行与统计
+type ValidationTracker struct {
+	global []responseAssertion
+
+	mutex      sync.Mutex
+	stats      ValidationStats
+	order      []string
+	assertions map[string]*AssertionStats
+}
+
+// NewValidationTracker 创建断言统计器，global为对所有响应执行的断言
+func NewValidationTracker(global []httpConfig.HttpAssertion) *ValidationTracker {
+	return &ValidationTracker{
+		global:     compileAssertions(global),
+		assertions: make(map[string]*AssertionStats),
+	}
+}
+
+// Validate 对一次请求的结果执行全局断言与请求自身的断言并记录统计
+// 返回响应是否通过全部断言（含状态码），断言未通过时返回validation_failed错误；
+// transportErr不为nil或没有响应时记为传输错误，未断言状态码时非2xx响应记为状态码错误，均不再执行断言
+func (t *ValidationTracker) Validate(request string, own []responseAssertion, response *connection.HttpResponse, transportErr error) (bool, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.stats.Requests++
+	if transportErr != nil || response == nil {
+		t.stats.TransportErrors++
+		return false, nil
+	}
+	if !hasStatusAssertion(t.global) && !hasStatusAssertion(own) && !response.IsSuccess() {
+		t.stats.StatusErrors++
+		return false, nil
+	}
+
+	var document responseDocument
+	var failure error
+	for _, assertions := range [][]responseAssertion{t.global, own} {
+		for i := range assertions {
+			assertion := &assertions[i]
+			passed, reason := assertion.check(response, &document)
+			if passed {
+				continue
+			}
+			t.recordFailure(assertion.name, ValidationFailureSample{
+				Request:    request,
+				StatusCode: response.StatusCode,
+				Reason:     reason,
+				Body:       truncateText(string(response.Body), maxSampleBodyBytes),
+			})
+			if failure == nil {
+				failure = fmt.Errorf("%s: assertion %s failed (%s)", ErrorCategoryValidation, assertion.name, reason)
+			}
+		}
+	}
+	if failure != nil {
+		t.stats.ValidationFailed++
+		return false, failure
+	}
+	t.stats.Passed++
+	return true, nil
+}
+
+// Applies 请求是否需要执行断言
+func (t *ValidationTracker) Applies(own []responseAssertion) bool {
+	return len(t.global) > 0 || len(own) > 0
+}
+
+// recordFailure 记录单条断言失败并保留样本，调用方需持有锁
+func (t *ValidationTracker) recordFailure(name string, sample ValidationFailureSample) {
+	stats, ok := t.assertions[name]
+	if !ok {
+		stats = &AssertionStats{Assertion: name}
+		t.assertions[name] = stats
+		t.order = append(t.order, name)
+	}
+	stats.Failures++
+	if len(stats.Samples) < maxValidationSamples {
+		stats.Samples = append(stats.Samples, sample)
+	}
+}
+
+// Stats 获取断言统计，各断言按首次失败的顺序排列
+func (t *ValidationTracker) Stats() ValidationStats {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	stats := t.stats
+	stats.Assertions = make([]AssertionStats, 0, len(t.order))
+	for _, name := range t.order {
+		assertion := *t.assertions[name]
+		assertion.Samples = append([]ValidationFailureSample(nil), assertion.Samples...)
+		stats.Assertions = append(stats.Assertions, assertion)
+	}
+	return stats
+}
+
+// truncateText 截断文本到最多limit字节，不截断多字节字符
+func truncateText(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut] + "…"
+}
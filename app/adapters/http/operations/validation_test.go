@@ -0,0 +1,127 @@
+package operations
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	httpConfig "abc-runner/app/adapters/http/config"
+	"abc-runner/app/adapters/http/connection"
+)
+
+func TestResponseValidation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users":
+			w.Write([]byte(`{"status":"ok","items":[{"id":1}]}`))
+		case "/orders":
+			w.Write([]byte(`{"status":"degraded","items":[]}`))
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"status":"ok"}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "http.yaml")
+	os.WriteFile(path, []byte(`http:
+  benchmark:
+    test_case: weighted
+    assert:
+      - status: "200,404"
+      - header: Content-Type
+        contains: json
+  connection:
+    base_url: `+server.URL+`
+  requests:
+    - name: users
+      method: GET
+      path: /users
+      weight: 1
+      assert:
+        - json: $.items[0].id
+    - name: orders
+      method: GET
+      path: /orders
+      weight: 1
+      assert:
+        - json: $.status
+          equals: ok
+    - name: missing
+      method: GET
+      path: /missing
+      weight: 1
+    - name: broken
+      method: GET
+      path: /broken
+      weight: 1
+`), 0644)
+	config, err := httpConfig.LoadHttpConfigFile(path)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	pool, err := connection.NewHttpConnectionPool(config)
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	defer pool.Close()
+	executor := NewHttpExecutor(pool, config, nil)
+	factory := NewHttpOperationFactory(config)
+
+	for jobID := 0; jobID < 100; jobID++ {
+		op := factory.CreateOperation(jobID, nil)
+		result, _ := executor.ExecuteOperation(context.Background(), op)
+		endpoint := op.Params["endpoint"].(string)
+		wantSuccess := endpoint == "users" || endpoint == "missing"
+		if result.Success != wantSuccess {
+			t.Fatalf("%s: expected success %v, got %v (%v)", endpoint, wantSuccess, result.Success, result.Error)
+		}
+		if !wantSuccess && result.Metadata["error_category"] != ErrorCategoryValidation {
+			t.Fatalf("%s: expected validation_failed category, got %v", endpoint, result.Metadata["error_category"])
+		}
+	}
+
+	stats, ok := executor.ValidationStats()
+	if !ok || stats.Requests != 100 || stats.TransportErrors != 0 || stats.StatusErrors != 0 {
+		t.Fatalf("unexpected validation stats: %+v", stats)
+	}
+	if stats.Passed+stats.ValidationFailed != 100 || stats.Passed == 0 || stats.ValidationFailed == 0 {
+		t.Errorf("unexpected pass/fail split: %+v", stats)
+	}
+
+	failures := make(map[string]AssertionStats)
+	for _, assertion := range stats.Assertions {
+		failures[assertion.Assertion] = assertion
+	}
+	status := failures[`status 200,404`]
+	equals := failures[`json $.status equals "ok"`]
+	if status.Failures == 0 || equals.Failures == 0 || status.Failures+equals.Failures != stats.ValidationFailed {
+		t.Errorf("unexpected assertion failures: %+v", stats.Assertions)
+	}
+	if len(equals.Samples) != maxValidationSamples || !strings.Contains(equals.Samples[0].Body, "degraded") || equals.Samples[0].Request != "GET /orders" {
+		t.Errorf("unexpected failure samples: %+v", equals.Samples)
+	}
+}
+
+func TestStatusAssertionRanges(t *testing.T) {
+	ranges, err := httpConfig.ParseStatusRanges("2xx, 404,500-503")
+	if err != nil || len(ranges) != 3 || ranges[0] != [2]int{200, 299} || ranges[2] != [2]int{500, 503} {
+		t.Fatalf("unexpected ranges %v, %v", ranges, err)
+	}
+	for _, invalid := range []string{"abc", "99", "503-500", "6xx"} {
+		if _, err := httpConfig.ParseStatusRanges(invalid); err == nil {
+			t.Errorf("expected %q to be rejected", invalid)
+		}
+	}
+	if err := (httpConfig.HttpAssertion{Status: "200", Equals: "x"}).Validate(); err == nil {
+		t.Errorf("expected equals on a status assertion to be rejected")
+	}
+}
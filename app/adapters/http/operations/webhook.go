@@ -0,0 +1,266 @@
+package operations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	httpConfig "abc-runner/app/adapters/http/config"
+	"abc-runner/app/core/metrics"
+)
+
+// maxCallbackBody 回调请求体读取上限
+const maxCallbackBody = 1 << 20
+
+// maxExpiredCallbacks 超时后仍保留以识别迟到回调的关联ID数上限
+const maxExpiredCallbacks = 100000
+
+// WebhookStats 异步回调测试统计
+type WebhookStats struct {
+	Submitted    int64                  `json:"submitted"`     // 提交成功的任务数
+	SubmitFailed int64                  `json:"submit_failed"` // 提交失败的任务数
+	Callbacks    int64                  `json:"callbacks"`     // 按时收到回调的任务数
+	TimedOut     int64                  `json:"timed_out"`     // 超时未收到回调的任务数
+	Late         int64                  `json:"late"`          // 超时后才到达的回调数
+	Unmatched    int64                  `json:"unmatched"`     // 关联ID未知或重复的回调数
+	Invalid      int64                  `json:"invalid"`       // 无法提取关联ID的回调数
+	CallbackRate float64                `json:"callback_rate"` // 按时回调占提交成功任务的百分比
+	Submit       metrics.LatencyMetrics `json:"submit"`        // 提交请求延迟
+	EndToEnd     metrics.LatencyMetrics `json:"end_to_end"`    // 开始提交到回调到达
+	AfterAck     metrics.LatencyMetrics `json:"after_ack"`     // 提交响应到回调到达
+}
+
+// ToMap 转换为报告使用的map
+func (s WebhookStats) ToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"submitted":     s.Submitted,
+		"submit_failed": s.SubmitFailed,
+		"callbacks":     s.Callbacks,
+		"timed_out":     s.TimedOut,
+		"late":          s.Late,
+		"unmatched":     s.Unmatched,
+		"invalid":       s.Invalid,
+		"callback_rate": s.CallbackRate,
+		"submit":        latencyToMap(s.Submit),
+		"end_to_end":    latencyToMap(s.EndToEnd),
+		"after_ack":     latencyToMap(s.AfterAck),
+	}
+}
+
+// WebhookReceiver 运行器内置的回调接收端，按关联ID将回调与等待中的任务匹配
+type WebhookReceiver struct {
+	config      httpConfig.HttpWebhookConfig
+	server      *http.Server
+	callbackURL string
+
+	mutex   sync.Mutex
+	pending map[string]chan time.Time
+	expired map[string]bool
+
+	submitted    atomic.Int64
+	submitFailed atomic.Int64
+	callbacks    atomic.Int64
+	timedOut     atomic.Int64
+	late         atomic.Int64
+	unmatched    atomic.Int64
+	invalid      atomic.Int64
+
+	submit   *metrics.LatencyTracker
+	endToEnd *metrics.LatencyTracker
+	afterAck *metrics.LatencyTracker
+}
+
+// NewWebhookReceiver 创建回调接收端，调用Start后开始监听
+func NewWebhookReceiver(config httpConfig.HttpWebhookConfig) *WebhookReceiver {
+	latencyConfig := metrics.LatencyConfig{
+		SamplingRate: 1.0,
+	}
+	return &WebhookReceiver{
+		config:   config,
+		pending:  make(map[string]chan time.Time),
+		expired:  make(map[string]bool),
+		submit:   metrics.NewLatencyTracker(latencyConfig),
+		endToEnd: metrics.NewLatencyTracker(latencyConfig),
+		afterAck: metrics.NewLatencyTracker(latencyConfig),
+	}
+}
+
+// Start 开始监听回调；未配置callback_url时以监听地址推导回调地址
+func (w *WebhookReceiver) Start() error {
+	listener, err := net.Listen("tcp", w.config.Listen)
+	if err != nil {
+		return fmt.Errorf("failed to start webhook receiver on %s: %w", w.config.Listen, err)
+	}
+
+	w.callbackURL = w.config.CallbackURL
+	if w.callbackURL == "" {
+		addr := listener.Addr().(*net.TCPAddr)
+		host := "localhost"
+		if !addr.IP.IsUnspecified() {
+			host = addr.IP.String()
+		}
+		w.callbackURL = "http://" + net.JoinHostPort(host, strconv.Itoa(addr.Port)) + w.config.CallbackPath
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(w.config.CallbackPath, w.handleCallback)
+	w.server = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go w.server.Serve(listener)
+	return nil
+}
+
+// CallbackURL 告知被测服务的回调地址
+func (w *WebhookReceiver) CallbackURL() string {
+	return w.callbackURL
+}
+
+// Expect 登记等待回调的关联ID，须在提交任务前调用，避免回调先于提交响应到达
+func (w *WebhookReceiver) Expect(id string) <-chan time.Time {
+	arrived := make(chan time.Time, 1)
+	w.mutex.Lock()
+	w.pending[id] = arrived
+	w.mutex.Unlock()
+	return arrived
+}
+
+// Forget 取消等待：提交失败时直接移除，超时时保留关联ID以识别迟到回调
+// 返回false表示回调已在取消前到达
+func (w *WebhookReceiver) Forget(id string, expire bool) bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if _, ok := w.pending[id]; !ok {
+		return false
+	}
+	delete(w.pending, id)
+	if expire && len(w.expired) < maxExpiredCallbacks {
+		w.expired[id] = true
+	}
+	return true
+}
+
+// RecordSubmit 记录任务提交结果
+func (w *WebhookReceiver) RecordSubmit(success bool, latency time.Duration) {
+	if success {
+		w.submitted.Add(1)
+	} else {
+		w.submitFailed.Add(1)
+	}
+	w.submit.Record(latency)
+}
+
+// RecordCallback 记录按时到达的回调
+func (w *WebhookReceiver) RecordCallback(endToEnd, afterAck time.Duration) {
+	w.callbacks.Add(1)
+	w.endToEnd.Record(endToEnd)
+	w.afterAck.Record(afterAck)
+}
+
+// RecordTimeout 记录超时未收到回调的任务
+func (w *WebhookReceiver) RecordTimeout() {
+	w.timedOut.Add(1)
+}
+
+// handleCallback 处理回调请求：按请求头或JSON字段提取关联ID并唤醒等待中的任务
+func (w *WebhookReceiver) handleCallback(rw http.ResponseWriter, r *http.Request) {
+	arrivedAt := time.Now()
+	body, _ := io.ReadAll(io.LimitReader(r.Body, maxCallbackBody))
+
+	id := ""
+	if w.config.IDHeader != "" {
+		id = r.Header.Get(w.config.IDHeader)
+	}
+	if id == "" && w.config.IDField != "" {
+		id = callbackField(body, w.config.IDField)
+	}
+	if id == "" {
+		w.invalid.Add(1)
+		http.Error(rw, "missing correlation id", http.StatusBadRequest)
+		return
+	}
+
+	w.mutex.Lock()
+	arrived, ok := w.pending[id]
+	if ok {
+		delete(w.pending, id)
+	}
+	late := !ok && w.expired[id]
+	if late {
+		delete(w.expired, id)
+	}
+	w.mutex.Unlock()
+
+	switch {
+	case ok:
+		arrived <- arrivedAt
+	case late:
+		w.late.Add(1)
+	default:
+		w.unmatched.Add(1)
+	}
+	// 未知或迟到的回调同样返回成功，避免被测服务重试
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// callbackField 按a.b形式的路径读取JSON回调中的字符串或数值字段
+func callbackField(body []byte, path string) string {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return ""
+	}
+	for _, name := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		value = object[name]
+	}
+	switch typed := value.(type) {
+	case string:
+		return typed
+	case json.Number:
+		return typed.String()
+	default:
+		return ""
+	}
+}
+
+// Stats 获取统计结果
+func (w *WebhookReceiver) Stats() WebhookStats {
+	stats := WebhookStats{
+		Submitted:    w.submitted.Load(),
+		SubmitFailed: w.submitFailed.Load(),
+		Callbacks:    w.callbacks.Load(),
+		TimedOut:     w.timedOut.Load(),
+		Late:         w.late.Load(),
+		Unmatched:    w.unmatched.Load(),
+		Invalid:      w.invalid.Load(),
+		Submit:       w.submit.GetMetrics(),
+		EndToEnd:     w.endToEnd.GetMetrics(),
+		AfterAck:     w.afterAck.GetMetrics(),
+	}
+	if stats.Submitted > 0 {
+		stats.CallbackRate = float64(stats.Callbacks) / float64(stats.Submitted) * 100
+	}
+	return stats
+}
+
+// Close 停止接收回调
+func (w *WebhookReceiver) Close() error {
+	if w.server == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return w.server.Shutdown(ctx)
+}
@@ -0,0 +1,120 @@
+package operations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	httpConfig "abc-runner/app/adapters/http/config"
+	"abc-runner/app/adapters/http/connection"
+)
+
+func TestExecuteWebhook(t *testing.T) {
+	// 模拟异步服务：接受任务后延迟回调，按job_id决定回调方式
+	postCallback := func(url string, header http.Header, body interface{}) {
+		data, _ := json.Marshal(body)
+		request, _ := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+		for k, v := range header {
+			request.Header[k] = v
+		}
+		if response, err := http.DefaultClient.Do(request); err == nil {
+			response.Body.Close()
+		}
+	}
+	late := make(chan func(), 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var job struct {
+			JobID         int    `json:"job_id"`
+			CorrelationID string `json:"correlation_id"`
+			CallbackURL   string `json:"callback_url"`
+		}
+		json.NewDecoder(r.Body).Decode(&job)
+		if r.URL.Path != "/v1/jobs" || job.CallbackURL != r.Header.Get("X-Callback-URL") ||
+			job.CorrelationID != r.Header.Get("X-Correlation-ID") {
+			http.Error(w, "bad job", http.StatusBadRequest)
+			return
+		}
+
+		switch job.JobID {
+		case 0:
+			go func() {
+				time.Sleep(30 * time.Millisecond)
+				postCallback(job.CallbackURL, http.Header{"X-Correlation-Id": {job.CorrelationID}}, nil)
+			}()
+		case 1:
+			go func() {
+				time.Sleep(30 * time.Millisecond)
+				postCallback(job.CallbackURL, nil, map[string]interface{}{"result": map[string]string{"ref": job.CorrelationID}})
+			}()
+		case 2:
+			late <- func() {
+				postCallback(job.CallbackURL, http.Header{"X-Correlation-Id": {job.CorrelationID}}, nil)
+			}
+		case 3:
+			http.Error(w, "queue full", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	config := httpConfig.LoadDefaultHttpConfig()
+	config.Connection.BaseURL = server.URL
+	config.Benchmark.TestCase = "webhook"
+	config.Benchmark.Webhook.SubmitPath = "/v1/jobs"
+	config.Benchmark.Webhook.Listen = "127.0.0.1:0"
+	config.Benchmark.Webhook.IDField = "result.ref"
+	config.Benchmark.Webhook.Timeout = 300 * time.Millisecond
+	if err := config.Validate(); err != nil {
+		t.Fatalf("invalid config: %v", err)
+	}
+	pool, err := connection.NewHttpConnectionPool(config)
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	defer pool.Close()
+
+	receiver := NewWebhookReceiver(config.Benchmark.Webhook)
+	if err := receiver.Start(); err != nil {
+		t.Fatalf("failed to start receiver: %v", err)
+	}
+	defer receiver.Close()
+	if !strings.HasPrefix(receiver.CallbackURL(), "http://127.0.0.1:") || !strings.HasSuffix(receiver.CallbackURL(), "/callback") {
+		t.Fatalf("unexpected callback URL %s", receiver.CallbackURL())
+	}
+
+	executor := NewHttpExecutor(pool, config, nil)
+	executor.SetWebhookReceiver(receiver)
+	factory := NewHttpOperationFactory(config)
+
+	for job := 0; job < 4; job++ {
+		result, err := executor.ExecuteOperation(context.Background(), factory.CreateOperation(job, nil))
+		if (err == nil) != (job < 2) || result.Success != (err == nil) {
+			t.Fatalf("job %d: success=%v err=%v", job, result.Success, err)
+		}
+		if job < 2 && result.Duration < 30*time.Millisecond {
+			t.Errorf("job %d: expected submit-to-callback latency >= 30ms, got %v", job, result.Duration)
+		}
+	}
+
+	// 超时后到达的回调、未知ID与缺少ID的回调
+	(<-late)()
+	postCallback(receiver.CallbackURL(), http.Header{"X-Correlation-Id": {"unknown"}}, nil)
+	postCallback(receiver.CallbackURL(), nil, map[string]string{"status": "done"})
+
+	stats := receiver.Stats()
+	if stats.Submitted != 3 || stats.SubmitFailed != 1 || stats.Callbacks != 2 || stats.TimedOut != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if stats.Late != 1 || stats.Unmatched != 1 || stats.Invalid != 1 {
+		t.Errorf("unexpected callback stats: late=%d unmatched=%d invalid=%d", stats.Late, stats.Unmatched, stats.Invalid)
+	}
+	if stats.EndToEnd.P50 < stats.AfterAck.P50 {
+		t.Errorf("end-to-end latency %v should include the delay after ack %v", stats.EndToEnd.P50, stats.AfterAck.P50)
+	}
+}
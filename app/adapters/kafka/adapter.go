@@ -2,7 +2,11 @@ package kafka
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -11,6 +15,7 @@ import (
 	"abc-runner/app/adapters/kafka/operations"
 
 	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
 
 	"github.com/segmentio/kafka-go"
 )
@@ -146,6 +151,16 @@ func (k *KafkaAdapter) GetProtocolMetrics() map[string]interface{} {
 		metrics["connection_pool"] = poolStats
 	}
 
+	// 添加批量读写统计
+	if k.kafkaOperations != nil {
+		if stats, ok := k.kafkaOperations.BatchStats(); ok {
+			metrics["batch"] = stats.ToMap()
+		}
+		if stats, ok := k.kafkaOperations.SerializationStats(); ok {
+			metrics["serialization"] = stats.ToMap()
+		}
+	}
+
 	// 添加配置信息
 	if k.config != nil {
 		metrics["config"] = map[string]interface{}{
@@ -290,6 +305,177 @@ func (k *KafkaAdapter) ProduceBatch(topic string, messages []*Message) (*BatchRe
 	return nil, fmt.Errorf("unexpected result type from batch produce operation")
 }
 
+// CreateTopic 创建主题，partitions与replicationFactor为0时使用benchmark.topic配置
+func (k *KafkaAdapter) CreateTopic(ctx context.Context, topic string, partitions, replicationFactor int) error {
+	params := map[string]interface{}{"topic": topic}
+	if partitions != 0 {
+		params["partitions"] = partitions
+	}
+	if replicationFactor != 0 {
+		params["replication_factor"] = replicationFactor
+	}
+	_, err := k.Execute(ctx, interfaces.Operation{Type: "create_topic", Key: topic, Params: params})
+	return err
+}
+
+// DeleteTopic 删除主题
+func (k *KafkaAdapter) DeleteTopic(ctx context.Context, topic string) error {
+	_, err := k.Execute(ctx, interfaces.Operation{Type: "delete_topic", Key: topic, Params: map[string]interface{}{"topic": topic}})
+	return err
+}
+
+// ListTopics 列出集群中的非内部主题
+func (k *KafkaAdapter) ListTopics(ctx context.Context) ([]string, error) {
+	result, err := k.Execute(ctx, interfaces.Operation{Type: "list_topics", Params: map[string]interface{}{}})
+	if err != nil {
+		return nil, err
+	}
+	topics, _ := result.Value.([]string)
+	return topics, nil
+}
+
+// SetupTopic 测试前创建测试主题并等待其出现在元数据中，返回主题是否由本次运行创建；主题已存在时沿用
+func (k *KafkaAdapter) SetupTopic(ctx context.Context) (bool, error) {
+	if k.config == nil {
+		return false, fmt.Errorf("kafka adapter not connected")
+	}
+	topic := k.config.Benchmark.DefaultTopic
+	if err := k.CreateTopic(ctx, topic, 0, 0); err != nil {
+		if errors.Is(err, kafka.TopicAlreadyExists) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	deadline := time.Now().Add(k.config.Benchmark.GetTimeout())
+	for {
+		topics, err := k.ListTopics(ctx)
+		if err == nil && slices.Contains(topics, topic) {
+			return true, nil
+		}
+		if time.Now().After(deadline) {
+			return true, fmt.Errorf("topic %s was created but did not appear in metadata within %v", topic, k.config.Benchmark.GetTimeout())
+		}
+		select {
+		case <-ctx.Done():
+			return true, ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// SetupSchema 准备Schema Registry序列化：配置了schema文件时向subject注册，否则获取subject的最新版本，
+// 并让生产者按该schema序列化消息
+func (k *KafkaAdapter) SetupSchema(ctx context.Context) (*connection.RegisteredSchema, error) {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+
+	if k.config == nil || k.kafkaOperations == nil {
+		return nil, fmt.Errorf("kafka adapter not connected")
+	}
+	registry := k.config.SchemaRegistry
+	subject := registry.GetSubject(k.config.Benchmark.DefaultTopic)
+	client := connection.NewSchemaRegistryClient(registry, k.config.Benchmark.GetTimeout())
+	defer client.Close()
+
+	var schema *connection.RegisteredSchema
+	if registry.SchemaFile != "" {
+		text, err := os.ReadFile(registry.SchemaFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read schema file: %w", err)
+		}
+		schemaType := strings.ToUpper(registry.GetFormat())
+		id, err := client.Register(ctx, subject, schemaType, string(text))
+		if err != nil {
+			return nil, fmt.Errorf("failed to register schema for subject %s: %w", subject, err)
+		}
+		schema = &connection.RegisteredSchema{ID: id, SchemaType: schemaType, Schema: string(text)}
+	} else {
+		latest, err := client.Latest(ctx, subject)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch schema for subject %s: %w", subject, err)
+		}
+		schema = latest
+	}
+
+	format := registry.GetFormat()
+	if format == "" {
+		format = strings.ToLower(schema.SchemaType)
+	}
+	serializer, err := operations.NewSerializer(format, subject, schema.ID, schema.Schema)
+	if err != nil {
+		return nil, err
+	}
+	k.kafkaOperations.SetSerializer(serializer)
+	return schema, nil
+}
+
+// SerializationStats 获取序列化统计，未配置Schema Registry时返回false
+func (k *KafkaAdapter) SerializationStats() (operations.SerializationStats, bool) {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+
+	if k.kafkaOperations == nil {
+		return operations.SerializationStats{}, false
+	}
+	return k.kafkaOperations.SerializationStats()
+}
+
+// LatencyPhases 获取新建broker连接的建连耗时，未连接或没有新建连接时返回false
+func (k *KafkaAdapter) LatencyPhases() ([]metrics.PhaseLatency, bool) {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+
+	if k.connPool == nil {
+		return nil, false
+	}
+	phases := k.connPool.LatencyPhases()
+	return phases, len(phases) > 0
+}
+
+// RunCommitBenchmark 执行偏移提交策略基准测试
+func (k *KafkaAdapter) RunCommitBenchmark(ctx context.Context) (*operations.CommitStats, error) {
+	if k.connPool == nil || k.config == nil {
+		return nil, fmt.Errorf("kafka adapter not connected")
+	}
+	return operations.NewCommitBenchmark(k.connPool, k.config, k.metricsCollector).Run(ctx)
+}
+
+// RunRelayBenchmark 执行跨集群复制延迟测试
+func (k *KafkaAdapter) RunRelayBenchmark(ctx context.Context) (*operations.RelayStats, error) {
+	if k.connPool == nil || k.config == nil {
+		return nil, fmt.Errorf("kafka adapter not connected")
+	}
+	return operations.NewRelayBenchmark(k.connPool, k.config, k.metricsCollector).Run(ctx)
+}
+
+// RunEndToEndBenchmark 执行端到端延迟测试
+func (k *KafkaAdapter) RunEndToEndBenchmark(ctx context.Context) (*operations.EndToEndStats, error) {
+	if k.connPool == nil || k.config == nil {
+		return nil, fmt.Errorf("kafka adapter not connected")
+	}
+	return operations.NewEndToEndBenchmark(k.connPool, k.config, k.metricsCollector).Run(ctx)
+}
+
+// RunColdReadBenchmark 执行冷数据读取（分层存储）测试
+func (k *KafkaAdapter) RunColdReadBenchmark(ctx context.Context) (*operations.ColdReadStats, error) {
+	if k.connPool == nil || k.config == nil {
+		return nil, fmt.Errorf("kafka adapter not connected")
+	}
+	return operations.NewColdReadBenchmark(k.connPool, k.config, k.metricsCollector).Run(ctx)
+}
+
+// BatchStats 获取批量生产/消费统计，没有执行过批量操作时返回false
+func (k *KafkaAdapter) BatchStats() (operations.BatchStats, bool) {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+
+	if k.kafkaOperations == nil {
+		return operations.BatchStats{}, false
+	}
+	return k.kafkaOperations.BatchStats()
+}
+
 // CreateOperation 创建操作（便捷方法）
 func (k *KafkaAdapter) CreateOperation(params map[string]interface{}) (interfaces.Operation, error) {
 	// 直接创建操作，不依赖外部工厂
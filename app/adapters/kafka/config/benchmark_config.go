@@ -16,8 +16,9 @@ func NewBenchmarkConfigAdapter(config *KafkaBenchmarkConfig) execution.Benchmark
 	return &BenchmarkConfigAdapter{config: config}
 }
 
+// GetTotal 获取任务数，批量读写时每个任务处理一批消息
 func (k *BenchmarkConfigAdapter) GetTotal() int {
-	return k.config.GetTotal()
+	return k.config.GetBatchCount()
 }
 
 func (k *BenchmarkConfigAdapter) GetParallels() int {
@@ -2,9 +2,12 @@ package config
 
 import (
 	"fmt"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/utils"
 	globalConfig "abc-runner/config"
 )
 
@@ -30,6 +33,9 @@ func LoadDefaultKafkaConfig() *KafkaAdapterConfig {
 			RequiredAcks: 1,
 			Compression:  "snappy",
 		},
+		Security: SecurityConfig{
+			TLS: TLSConfig{VerifySSL: true},
+		},
 		Consumer: ConsumerConfig{
 			GroupID:          "test-group",
 			AutoOffsetReset:  "earliest",
@@ -38,6 +44,8 @@ func LoadDefaultKafkaConfig() *KafkaAdapterConfig {
 			HeartbeatTimeout: time.Second * 3,
 			FetchMinBytes:    1,           // 设置默认值
 			FetchMaxBytes:    1024 * 1024, // 1MB
+			CommitSync:       true,
+			CommitBatch:      1,
 		},
 		Benchmark: KafkaBenchmarkConfig{
 			DefaultTopic: "test-topic", // 设置默认topic
@@ -52,6 +60,20 @@ func LoadDefaultKafkaConfig() *KafkaAdapterConfig {
 			ProducerPoolSize:   5,
 			ConsumerPoolSize:   5,
 		},
+		Relay: RelayConfig{
+			Rate:   100,
+			Window: time.Second,
+			Drain:  30 * time.Second,
+		},
+		ColdRead: ColdReadConfig{
+			Age:   24 * time.Hour,
+			Batch: 100,
+		},
+		EndToEnd: EndToEndConfig{
+			Rate:   100,
+			Window: time.Second,
+			Drain:  30 * time.Second,
+		},
 	}
 }
 
@@ -78,6 +100,18 @@ type KafkaAdapterConfig struct {
 
 	// 基准测试配置
 	Benchmark KafkaBenchmarkConfig `yaml:"benchmark" json:"benchmark"`
+
+	// 跨集群复制测试配置
+	Relay RelayConfig `yaml:"relay" json:"relay"`
+
+	// 冷数据读取测试配置
+	ColdRead ColdReadConfig `yaml:"cold_read" json:"cold_read"`
+
+	// 端到端延迟测试配置
+	EndToEnd EndToEndConfig `yaml:"end_to_end" json:"end_to_end"`
+
+	// Schema Registry配置，设置后生产的消息按schema序列化
+	SchemaRegistry SchemaRegistryConfig `yaml:"schema_registry" json:"schema_registry"`
 }
 
 // TopicConfig 主题配置
@@ -121,6 +155,8 @@ type ConsumerConfig struct {
 	ReadTimeout        time.Duration `yaml:"read_timeout" json:"read_timeout"`                 // 读取超时
 	WriteTimeout       time.Duration `yaml:"write_timeout" json:"write_timeout"`               // 写入超时
 	InitialOffset      string        `yaml:"initial_offset" json:"initial_offset"`             // 初始偏移: earliest, latest
+	CommitSync         bool          `yaml:"commit_sync" json:"commit_sync"`                   // 手动提交时是否同步提交
+	CommitBatch        int           `yaml:"commit_batch" json:"commit_batch"`                 // 手动提交时每N条消息提交一次
 }
 
 // SecurityConfig 安全配置
@@ -139,6 +175,28 @@ type TLSConfig struct {
 	ServerName string `yaml:"server_name" json:"server_name"` // 服务器名称
 }
 
+// Describe 描述启用的安全设置，如"SASL SCRAM-SHA-512 over TLS"，未启用时为"plaintext"
+func (s SecurityConfig) Describe() string {
+	description := "plaintext"
+	if s.SASL.Enabled {
+		description = "SASL " + s.SASL.Mechanism
+	}
+	if s.TLS.Enabled {
+		if s.SASL.Enabled {
+			description += " over TLS"
+		} else {
+			description = "TLS"
+		}
+		if s.TLS.CertFile != "" {
+			description += " (mutual)"
+		}
+		if !s.TLS.VerifySSL {
+			description += ", certificate verification disabled"
+		}
+	}
+	return description
+}
+
 // SASLConfig SASL配置
 type SASLConfig struct {
 	Enabled   bool   `yaml:"enabled" json:"enabled"`     // 是否启用SASL
@@ -160,6 +218,7 @@ type KafkaBenchmarkConfig struct {
 	DefaultTopic      string           `yaml:"default_topic" json:"default_topic"`           // 默认主题
 	MessageSizeRange  MessageSizeRange `yaml:"message_size_range" json:"message_size_range"` // 消息大小范围
 	BatchSizes        []int            `yaml:"batch_sizes" json:"batch_sizes"`               // 批处理大小列表
+	BatchSize         int              `yaml:"batch_size" json:"batch_size"`                 // 每次生产/消费操作的消息数，大于1时使用批量读写
 	PartitionStrategy string           `yaml:"partition_strategy" json:"partition_strategy"` // 分区策略
 	Total             int              `yaml:"total" json:"total"`                           // 总请求数
 	Parallels         int              `yaml:"parallels" json:"parallels"`                   // 并发数
@@ -171,6 +230,125 @@ type KafkaBenchmarkConfig struct {
 	TestType          string           `yaml:"test_type" json:"test_type"`                   // 测试类型
 	MessageSize       int              `yaml:"message_size" json:"message_size"`             // 消息大小
 	Timeout           time.Duration    `yaml:"timeout" json:"timeout"`                       // 超时时间
+	Restarts          int              `yaml:"restarts" json:"restarts"`                     // 提交策略测试中模拟的消费者重启次数
+	Topic             TopicSetupConfig `yaml:"topic" json:"topic"`                           // 测试主题的创建参数与自动创建/删除
+
+	Payload utils.PayloadTemplateConfig `yaml:"payload" json:"payload"` // 消息内容模板，未设置时按data_size生成
+}
+
+// TopicSetupConfig 测试主题配置
+// create_topic操作未指定分区数与副本数时使用这里的设置；Auto开启时测试前创建default_topic，测试后删除
+type TopicSetupConfig struct {
+	Auto              bool `yaml:"auto" json:"auto"`                             // 自动创建并在测试后删除测试主题，主题已存在时沿用且不删除
+	Partitions        int  `yaml:"partitions" json:"partitions"`                 // 分区数，0为1，-1使用broker默认值
+	ReplicationFactor int  `yaml:"replication_factor" json:"replication_factor"` // 副本数，0为1，-1使用broker默认值
+}
+
+// GetPartitions 获取创建主题的分区数
+func (t TopicSetupConfig) GetPartitions() int {
+	if t.Partitions == 0 {
+		return 1
+	}
+	return t.Partitions
+}
+
+// GetReplicationFactor 获取创建主题的副本数
+func (t TopicSetupConfig) GetReplicationFactor() int {
+	if t.ReplicationFactor == 0 {
+		return 1
+	}
+	return t.ReplicationFactor
+}
+
+// Validate 验证主题配置
+func (t TopicSetupConfig) Validate() error {
+	if t.Partitions < -1 {
+		return fmt.Errorf("partitions must be positive or -1 (broker default), got: %d", t.Partitions)
+	}
+	if t.ReplicationFactor < -1 {
+		return fmt.Errorf("replication_factor must be positive or -1 (broker default), got: %d", t.ReplicationFactor)
+	}
+	return nil
+}
+
+// RelayConfig 跨集群复制（MirrorMaker）延迟测试配置
+// 消息写入Brokers所在的源集群，从TargetBrokers所在的目标集群读取复制后的消息
+type RelayConfig struct {
+	TargetBrokers []string      `yaml:"target_brokers" json:"target_brokers"` // 目标集群Broker地址列表
+	TargetTopic   string        `yaml:"target_topic" json:"target_topic"`     // 目标集群中的复制主题，为空时与源主题同名
+	Rate          int           `yaml:"rate" json:"rate"`                     // 每秒发送消息数
+	Window        time.Duration `yaml:"window" json:"window"`                 // 延迟时间序列的统计窗口
+	Drain         time.Duration `yaml:"drain" json:"drain"`                   // 发送结束后等待复制完成的最长时间
+}
+
+// ColdReadConfig 冷数据读取（分层存储）测试配置
+// 交替读取早于Age的历史消息与分区末尾的最新消息，比较两者的拉取延迟
+type ColdReadConfig struct {
+	Age   time.Duration `yaml:"age" json:"age"`     // 冷读取的消息至少早于该时长
+	Batch int           `yaml:"batch" json:"batch"` // 每次拉取读取的消息数
+}
+
+// EndToEndConfig 端到端延迟测试配置
+// 以固定速率写入在消息头中携带发送时间与关联ID的消息，同时从同一主题读取，统计从发送到消费端收到的延迟
+type EndToEndConfig struct {
+	Rate   int           `yaml:"rate" json:"rate"`     // 每秒发送消息数
+	Window time.Duration `yaml:"window" json:"window"` // 延迟时间序列的统计窗口
+	Drain  time.Duration `yaml:"drain" json:"drain"`   // 发送结束后等待消费完成的最长时间
+}
+
+// SchemaRegistryConfig Schema Registry序列化配置
+// 设置SchemaFile时向Subject注册该schema（已存在时返回原ID），否则使用Subject的最新版本；
+// 消息按Confluent线格式编码：魔数0、4字节schema ID，Protobuf另加消息索引
+type SchemaRegistryConfig struct {
+	URL        string `yaml:"url" json:"url"`                 // Registry地址，为空时不序列化
+	Format     string `yaml:"format" json:"format"`           // avro、protobuf或json，为空时取自registry或schema文件扩展名
+	SchemaFile string `yaml:"schema_file" json:"schema_file"` // 注册的schema文件
+	Subject    string `yaml:"subject" json:"subject"`         // 为空时为"<topic>-value"
+	Username   string `yaml:"username" json:"username"`       // Basic认证用户名，如Confluent Cloud的API key
+	Password   string `yaml:"password" json:"password"`       // Basic认证密码
+}
+
+// Enabled 是否通过Schema Registry序列化消息
+func (s SchemaRegistryConfig) Enabled() bool {
+	return s.URL != ""
+}
+
+// GetSubject 获取subject，未配置时按TopicNameStrategy使用"<topic>-value"
+func (s SchemaRegistryConfig) GetSubject(topic string) string {
+	if s.Subject != "" {
+		return s.Subject
+	}
+	return topic + "-value"
+}
+
+// GetFormat 获取序列化格式：配置的格式，未配置时按schema文件扩展名推断，无法推断时为空
+func (s SchemaRegistryConfig) GetFormat() string {
+	if s.Format != "" {
+		return s.Format
+	}
+	switch strings.ToLower(filepath.Ext(s.SchemaFile)) {
+	case ".avsc", ".avro":
+		return "avro"
+	case ".proto":
+		return "protobuf"
+	case ".json":
+		return "json"
+	}
+	return ""
+}
+
+// Validate 验证Schema Registry配置
+func (s SchemaRegistryConfig) Validate() error {
+	if !s.Enabled() {
+		return nil
+	}
+	if format := s.GetFormat(); format != "" && !contains([]string{"avro", "protobuf", "json"}, format) {
+		return fmt.Errorf("invalid schema format: %s, must be one of avro, protobuf, json", format)
+	}
+	if s.SchemaFile != "" && s.GetFormat() == "" {
+		return fmt.Errorf("cannot infer the format of %s, set format to avro, protobuf or json", s.SchemaFile)
+	}
+	return nil
 }
 
 // MessageSizeRange 消息大小范围
@@ -232,11 +410,41 @@ func (c *KafkaAdapterConfig) Validate() error {
 		return fmt.Errorf("performance config validation failed: %w", err)
 	}
 
+	// 验证测试主题配置
+	if err := c.Benchmark.Topic.Validate(); err != nil {
+		return fmt.Errorf("topic config validation failed: %w", err)
+	}
 	// 验证基准测试配置
 	if err := c.validateBenchmarkConfig(); err != nil {
 		return fmt.Errorf("benchmark config validation failed: %w", err)
 	}
 
+	// 验证跨集群复制测试配置
+	if c.Benchmark.TestType == "relay" {
+		if err := c.validateRelayConfig(); err != nil {
+			return fmt.Errorf("relay config validation failed: %w", err)
+		}
+	}
+
+	// 验证Schema Registry配置
+	if err := c.SchemaRegistry.Validate(); err != nil {
+		return fmt.Errorf("schema registry config validation failed: %w", err)
+	}
+
+	// 验证端到端延迟测试配置
+	if c.Benchmark.TestType == "e2e" {
+		if err := c.validateEndToEndConfig(); err != nil {
+			return fmt.Errorf("end-to-end config validation failed: %w", err)
+		}
+	}
+
+	// 验证冷数据读取测试配置
+	if c.Benchmark.TestType == "coldread" {
+		if err := c.validateColdReadConfig(); err != nil {
+			return fmt.Errorf("cold read config validation failed: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -250,6 +458,9 @@ func (c *KafkaAdapterConfig) Clone() interfaces.Config {
 	clone.Benchmark.BatchSizes = make([]int, len(c.Benchmark.BatchSizes))
 	copy(clone.Benchmark.BatchSizes, c.Benchmark.BatchSizes)
 
+	clone.Relay.TargetBrokers = make([]string, len(c.Relay.TargetBrokers))
+	copy(clone.Relay.TargetBrokers, c.Relay.TargetBrokers)
+
 	return &clone
 }
 
@@ -333,30 +544,46 @@ func (c *KafkaAdapterConfig) validateConsumerConfig() error {
 		return fmt.Errorf("fetch_max_bytes must be greater than fetch_min_bytes")
 	}
 
+	// 验证偏移提交设置
+	if c.Consumer.AutoCommitInterval < 0 {
+		return fmt.Errorf("auto_commit_interval cannot be negative, got: %v", c.Consumer.AutoCommitInterval)
+	}
+
+	if c.Consumer.CommitBatch < 0 {
+		return fmt.Errorf("commit_batch cannot be negative, got: %d", c.Consumer.CommitBatch)
+	}
+
 	return nil
 }
 
 // validateSecurityConfig 验证安全配置
 func (c *KafkaAdapterConfig) validateSecurityConfig() error {
+	return c.Security.Validate()
+}
+
+// Validate 验证SASL与TLS设置，证书文件在创建连接池时加载
+func (s SecurityConfig) Validate() error {
 	// 验证SASL配置
-	if c.Security.SASL.Enabled {
+	if s.SASL.Enabled {
 		validMechanisms := []string{"PLAIN", "SCRAM-SHA-256", "SCRAM-SHA-512"}
-		if !contains(validMechanisms, c.Security.SASL.Mechanism) {
-			return fmt.Errorf("invalid SASL mechanism: %s, must be one of %v", c.Security.SASL.Mechanism, validMechanisms)
+		if !contains(validMechanisms, s.SASL.Mechanism) {
+			return fmt.Errorf("invalid SASL mechanism: %s, must be one of %v", s.SASL.Mechanism, validMechanisms)
 		}
 
-		if c.Security.SASL.Username == "" {
+		if s.SASL.Username == "" {
 			return fmt.Errorf("SASL username cannot be empty when SASL is enabled")
 		}
 
-		if c.Security.SASL.Password == "" {
+		if s.SASL.Password == "" {
 			return fmt.Errorf("SASL password cannot be empty when SASL is enabled")
 		}
 	}
 
 	// 验证TLS配置
-	if c.Security.TLS.Enabled {
-		// TLS文件路径验证可选择性进行，这里只检查基本设置
+	if s.TLS.Enabled {
+		if (s.TLS.CertFile == "") != (s.TLS.KeyFile == "") {
+			return fmt.Errorf("TLS cert_file and key_file must be set together")
+		}
 	}
 
 	return nil
@@ -397,6 +624,65 @@ func (c *KafkaAdapterConfig) validateBenchmarkConfig() error {
 		return fmt.Errorf("read_percent must be between 0 and 100, got: %d", c.Benchmark.ReadPercent)
 	}
 
+	if c.Benchmark.Restarts < 0 {
+		return fmt.Errorf("restarts cannot be negative, got: %d", c.Benchmark.Restarts)
+	}
+
+	if c.Benchmark.BatchSize < 0 {
+		return fmt.Errorf("batch_size cannot be negative, got: %d", c.Benchmark.BatchSize)
+	}
+
+	return nil
+}
+
+// validateRelayConfig 验证跨集群复制测试配置
+func (c *KafkaAdapterConfig) validateRelayConfig() error {
+	if len(c.Relay.TargetBrokers) == 0 {
+		return fmt.Errorf("target_brokers cannot be empty")
+	}
+
+	if c.Relay.Rate <= 0 {
+		return fmt.Errorf("rate must be positive, got: %d", c.Relay.Rate)
+	}
+
+	if c.Relay.Window <= 0 {
+		return fmt.Errorf("window must be positive, got: %v", c.Relay.Window)
+	}
+
+	if c.Relay.Drain < 0 {
+		return fmt.Errorf("drain cannot be negative, got: %v", c.Relay.Drain)
+	}
+
+	return nil
+}
+
+// validateEndToEndConfig 验证端到端延迟测试配置
+func (c *KafkaAdapterConfig) validateEndToEndConfig() error {
+	if c.EndToEnd.Rate <= 0 {
+		return fmt.Errorf("rate must be positive, got: %d", c.EndToEnd.Rate)
+	}
+
+	if c.EndToEnd.Window <= 0 {
+		return fmt.Errorf("window must be positive, got: %v", c.EndToEnd.Window)
+	}
+
+	if c.EndToEnd.Drain < 0 {
+		return fmt.Errorf("drain cannot be negative, got: %v", c.EndToEnd.Drain)
+	}
+
+	return nil
+}
+
+// validateColdReadConfig 验证冷数据读取测试配置
+func (c *KafkaAdapterConfig) validateColdReadConfig() error {
+	if c.ColdRead.Age <= 0 {
+		return fmt.Errorf("age must be positive, got: %v", c.ColdRead.Age)
+	}
+
+	if c.ColdRead.Batch <= 0 {
+		return fmt.Errorf("batch must be positive, got: %d", c.ColdRead.Batch)
+	}
+
 	return nil
 }
 
@@ -134,3 +134,86 @@ func TestKafkaConfigValidation(t *testing.T) {
 		t.Error("Zero total should fail validation")
 	}
 }
+
+func TestTopicSetupConfig(t *testing.T) {
+	config := LoadDefaultKafkaConfig()
+
+	// 未配置时按1个分区、1个副本创建
+	topic := config.Benchmark.Topic
+	if topic.Auto || topic.GetPartitions() != 1 || topic.GetReplicationFactor() != 1 {
+		t.Errorf("unexpected defaults: %+v", topic)
+	}
+
+	config.Benchmark.Topic = TopicSetupConfig{Auto: true, Partitions: 12, ReplicationFactor: -1}
+	if err := config.Validate(); err != nil {
+		t.Errorf("expected -1 (broker default) to be accepted: %v", err)
+	}
+	if config.Benchmark.Topic.GetPartitions() != 12 || config.Benchmark.Topic.GetReplicationFactor() != -1 {
+		t.Errorf("unexpected topic settings: %+v", config.Benchmark.Topic)
+	}
+
+	config.Benchmark.Topic.Partitions = -2
+	if err := config.Validate(); err == nil {
+		t.Error("negative partitions should fail validation")
+	}
+}
+
+func TestSecurityConfig(t *testing.T) {
+	security := LoadDefaultKafkaConfig().Security
+	if security.Describe() != "plaintext" || security.Validate() != nil {
+		t.Errorf("unexpected defaults: %+v", security)
+	}
+
+	security.SASL = SASLConfig{Enabled: true, Mechanism: "SCRAM-SHA-512", Username: "bench"}
+	if err := security.Validate(); err == nil {
+		t.Error("SASL without a password should fail validation")
+	}
+	security.SASL.Password = "secret"
+	security.TLS.Enabled = true
+	if err := security.Validate(); err != nil {
+		t.Errorf("unexpected validation error: %v", err)
+	}
+	if description := security.Describe(); description != "SASL SCRAM-SHA-512 over TLS" {
+		t.Errorf("unexpected description %q", description)
+	}
+
+	security.SASL.Mechanism = "OAUTHBEARER"
+	if err := security.Validate(); err == nil {
+		t.Error("unsupported SASL mechanism should fail validation")
+	}
+
+	security = SecurityConfig{TLS: TLSConfig{Enabled: true, CertFile: "client.crt"}}
+	if err := security.Validate(); err == nil {
+		t.Error("client certificate without a key should fail validation")
+	}
+	security.TLS.KeyFile = "client.key"
+	if description := security.Describe(); description != "TLS (mutual), certificate verification disabled" {
+		t.Errorf("unexpected description %q", description)
+	}
+}
+
+func TestSchemaRegistryConfig(t *testing.T) {
+	registry := SchemaRegistryConfig{}
+	if registry.Enabled() || registry.Validate() != nil {
+		t.Errorf("schema registry should be disabled by default")
+	}
+
+	registry = SchemaRegistryConfig{URL: "http://localhost:8081", SchemaFile: "schemas/order.avsc"}
+	if registry.GetFormat() != "avro" || registry.GetSubject("orders") != "orders-value" {
+		t.Errorf("unexpected format %q or subject %q", registry.GetFormat(), registry.GetSubject("orders"))
+	}
+	registry.SchemaFile = "order.proto"
+	registry.Subject = "orders-proto"
+	if registry.GetFormat() != "protobuf" || registry.GetSubject("orders") != "orders-proto" {
+		t.Errorf("unexpected format %q or subject %q", registry.GetFormat(), registry.GetSubject("orders"))
+	}
+
+	registry.SchemaFile = "order.txt"
+	if err := registry.Validate(); err == nil {
+		t.Error("a schema file of unknown format should fail validation")
+	}
+	registry.Format = "thrift"
+	if err := registry.Validate(); err == nil {
+		t.Error("an unsupported format should fail validation")
+	}
+}
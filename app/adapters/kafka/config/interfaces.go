@@ -76,6 +76,20 @@ func (b *KafkaBenchmarkConfig) GetTotal() int {
 	return b.Total
 }
 
+// GetBatchSize 获取每次操作的消息数，未开启批量读写时为1
+func (b *KafkaBenchmarkConfig) GetBatchSize() int {
+	if b.BatchSize <= 1 {
+		return 1
+	}
+	return b.BatchSize
+}
+
+// GetBatchCount 获取发送/读取全部消息所需的操作数
+func (b *KafkaBenchmarkConfig) GetBatchCount() int {
+	size := b.GetBatchSize()
+	return (b.Total + size - 1) / size
+}
+
 // GetParallels 获取并发数
 func (b *KafkaBenchmarkConfig) GetParallels() int {
 	return b.Parallels
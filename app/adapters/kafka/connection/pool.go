@@ -3,7 +3,10 @@ package connection
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"net"
+	"os"
 	"sync"
 	"time"
 
@@ -13,6 +16,7 @@ import (
 	"github.com/segmentio/kafka-go/sasl/scram"
 
 	"abc-runner/app/adapters/kafka/config"
+	"abc-runner/app/core/metrics"
 )
 
 // PoolConfig 连接池配置
@@ -37,7 +41,14 @@ type ConnectionPool struct {
 	consumers    []*kafka.Reader
 
 	// 管理客户端
-	adminConn *kafka.Conn
+	adminConn   *kafka.Conn
+	adminClient *kafka.Client // 主题管理请求，按请求类型路由到controller等broker
+
+	// 共享拨号器（包含TLS/SASL设置）
+	dialer *kafka.Dialer
+
+	// 新建broker连接的TCP建连耗时（TLS握手由kafka-go在建连后完成，不单独计时）
+	phases *metrics.PhaseTracker
 
 	// 同步控制
 	mutex  sync.RWMutex
@@ -53,6 +64,7 @@ func NewConnectionPool(kafkaConfig *config.KafkaAdapterConfig, poolConfig PoolCo
 		consumerPool: make(chan *kafka.Reader, poolConfig.ConsumerPoolSize),
 		producers:    make([]*kafka.Writer, 0, poolConfig.ProducerPoolSize),
 		consumers:    make([]*kafka.Reader, 0, poolConfig.ConsumerPoolSize),
+		phases:       metrics.NewPhaseTracker(),
 	}
 
 	// 初始化连接池
@@ -85,6 +97,8 @@ func (p *ConnectionPool) initialize() error {
 		}
 	}
 
+	p.dialer = p.createDialer(tlsConfig, saslMechanism)
+
 	// 初始化生产者池
 	if err := p.initializeProducers(tlsConfig, saslMechanism); err != nil {
 		return fmt.Errorf("failed to initialize producers: %w", err)
@@ -105,13 +119,15 @@ func (p *ConnectionPool) initialize() error {
 
 // initializeProducers 初始化生产者池
 func (p *ConnectionPool) initializeProducers(tlsConfig *tls.Config, saslMechanism sasl.Mechanism) error {
+	// 批量写入时一次写入的消息应能进入同一个请求批次，分区批次未满时最多等待linger
+	batchSize := max(p.config.Producer.BatchSize, p.config.Benchmark.BatchSize)
 	for i := 0; i < p.poolConfig.ProducerPoolSize; i++ {
 		writer := &kafka.Writer{
 			Addr:         kafka.TCP(p.config.Brokers...),
 			Topic:        "", // Topic will be set per message
 			Balancer:     p.createBalancer(),
 			MaxAttempts:  p.config.Producer.Retries + 1,
-			BatchSize:    p.config.Producer.BatchSize,
+			BatchSize:    batchSize,
 			BatchTimeout: p.config.Producer.LingerMs,
 			ReadTimeout:  p.config.Producer.ReadTimeout,
 			WriteTimeout: p.config.Producer.WriteTimeout,
@@ -173,6 +189,12 @@ func (p *ConnectionPool) initializeAdminConnection(tlsConfig *tls.Config, saslMe
 		return fmt.Errorf("failed to create admin connection: %w", err)
 	}
 
+	p.adminClient = &kafka.Client{
+		Addr:      kafka.TCP(p.config.Brokers...),
+		Timeout:   p.poolConfig.ConnectionTimeout,
+		Transport: p.createTransport(tlsConfig, saslMechanism),
+	}
+
 	return nil
 }
 
@@ -192,9 +214,17 @@ func (p *ConnectionPool) createTLSConfig() (*tls.Config, error) {
 		tlsConfig.Certificates = []tls.Certificate{cert}
 	}
 
-	// 加载CA证书
+	// 加载CA证书，未设置时使用系统根证书
 	if p.config.Security.TLS.CaFile != "" {
-		// TODO: 实现CA证书加载
+		pem, err := os.ReadFile(p.config.Security.TLS.CaFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", p.config.Security.TLS.CaFile)
+		}
+		tlsConfig.RootCAs = pool
 	}
 
 	return tlsConfig, nil
@@ -233,6 +263,8 @@ func (p *ConnectionPool) createDialer(tlsConfig *tls.Config, saslMechanism sasl.
 	dialer := &kafka.Dialer{
 		Timeout:   p.poolConfig.ConnectionTimeout,
 		DualStack: true,
+		// 超时由Dialer设置在ctx上
+		DialFunc: p.phases.WrapDial((&net.Dialer{}).DialContext),
 	}
 
 	if tlsConfig != nil {
@@ -247,12 +279,14 @@ func (p *ConnectionPool) createDialer(tlsConfig *tls.Config, saslMechanism sasl.
 }
 
 // createTransport 创建传输层
+// Transport自行完成TLS握手与SASL认证，不能借用Dialer的设置（Dialer.DialFunc只是可选的自定义拨号函数）
 func (p *ConnectionPool) createTransport(tlsConfig *tls.Config, saslMechanism sasl.Mechanism) *kafka.Transport {
-	transport := &kafka.Transport{
-		Dial: p.createDialer(tlsConfig, saslMechanism).DialFunc,
+	return &kafka.Transport{
+		Dial:        p.phases.WrapDial((&net.Dialer{Timeout: p.poolConfig.ConnectionTimeout}).DialContext),
+		DialTimeout: p.poolConfig.ConnectionTimeout,
+		TLS:         tlsConfig,
+		SASL:        saslMechanism,
 	}
-
-	return transport
 }
 
 // createBalancer 创建负载均衡器
@@ -388,6 +422,113 @@ func (p *ConnectionPool) ReturnConsumer(consumer *kafka.Reader) {
 	}
 }
 
+// NewGroupReader 创建独立于消费者池的消费者组读取器，由调用方负责关闭
+// commitInterval为0时每次ReadMessage同步提交，大于0时按间隔异步提交
+func (p *ConnectionPool) NewGroupReader(topic, groupID string, commitInterval time.Duration, startOffset int64) *kafka.Reader {
+	return kafka.NewReader(kafka.ReaderConfig{
+		Brokers:           p.config.Brokers,
+		Topic:             topic,
+		GroupID:           groupID,
+		MinBytes:          p.config.Consumer.FetchMinBytes,
+		MaxBytes:          p.config.Consumer.FetchMaxBytes,
+		MaxWait:           p.config.Consumer.FetchMaxWait,
+		ReadBatchTimeout:  p.config.Consumer.ReadTimeout,
+		StartOffset:       startOffset,
+		RebalanceTimeout:  p.config.Consumer.SessionTimeout,
+		HeartbeatInterval: p.config.Consumer.HeartbeatInterval,
+		CommitInterval:    commitInterval,
+		Dialer:            p.dialer,
+	})
+}
+
+// NewWriter 创建独立于生产者池的同步写入器，由调用方负责关闭
+// 延迟敏感的测试应使用较小的batchTimeout，避免消息在客户端等待凑批
+func (p *ConnectionPool) NewWriter(batchTimeout time.Duration) *kafka.Writer {
+	return &kafka.Writer{
+		Addr:         kafka.TCP(p.config.Brokers...),
+		Balancer:     p.createBalancer(),
+		MaxAttempts:  p.config.Producer.Retries + 1,
+		BatchTimeout: batchTimeout,
+		ReadTimeout:  p.config.Producer.ReadTimeout,
+		WriteTimeout: p.config.Producer.WriteTimeout,
+		RequiredAcks: p.parseAcks(p.config.Producer.Acks),
+		Compression:  p.parseCompression(p.config.Producer.Compression),
+		Transport:    p.createTransport(p.dialer.TLS, p.dialer.SASLMechanism),
+	}
+}
+
+// NewPartitionReaders 为指定集群上主题的每个分区创建读取器，由调用方负责关闭
+// 读取器从创建时刻各分区的末尾偏移开始，只读取之后写入的消息；
+// 与主集群共享TLS/SASL设置，用于读取复制到其它集群的主题
+func (p *ConnectionPool) NewPartitionReaders(ctx context.Context, brokers []string, topic string) ([]*kafka.Reader, error) {
+	if len(brokers) == 0 {
+		return nil, fmt.Errorf("no brokers specified")
+	}
+
+	partitions, err := p.dialer.LookupPartitions(ctx, "tcp", brokers[0], topic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lookup partitions of %s: %w", topic, err)
+	}
+	if len(partitions) == 0 {
+		return nil, fmt.Errorf("topic %s has no partitions", topic)
+	}
+
+	readers := make([]*kafka.Reader, 0, len(partitions))
+	closeAll := func() {
+		for _, reader := range readers {
+			reader.Close()
+		}
+	}
+
+	for _, partition := range partitions {
+		conn, err := p.dialer.DialLeader(ctx, "tcp", brokers[0], topic, partition.ID)
+		if err != nil {
+			closeAll()
+			return nil, fmt.Errorf("failed to dial leader of %s/%d: %w", topic, partition.ID, err)
+		}
+		offset, err := conn.ReadLastOffset()
+		conn.Close()
+		if err != nil {
+			closeAll()
+			return nil, fmt.Errorf("failed to read last offset of %s/%d: %w", topic, partition.ID, err)
+		}
+
+		reader := kafka.NewReader(kafka.ReaderConfig{
+			Brokers:   brokers,
+			Topic:     topic,
+			Partition: partition.ID,
+			MinBytes:  1,
+			MaxBytes:  p.config.Consumer.FetchMaxBytes,
+			MaxWait:   p.config.Consumer.FetchMaxWait,
+			Dialer:    p.dialer,
+		})
+		if err := reader.SetOffset(offset); err != nil {
+			reader.Close()
+			closeAll()
+			return nil, fmt.Errorf("failed to set offset of %s/%d: %w", topic, partition.ID, err)
+		}
+		readers = append(readers, reader)
+	}
+
+	return readers, nil
+}
+
+// LookupPartitions 查询主题的分区列表
+func (p *ConnectionPool) LookupPartitions(ctx context.Context, topic string) ([]kafka.Partition, error) {
+	if len(p.config.Brokers) == 0 {
+		return nil, fmt.Errorf("no brokers specified")
+	}
+	return p.dialer.LookupPartitions(ctx, "tcp", p.config.Brokers[0], topic)
+}
+
+// DialPartitionLeader 连接主题分区的leader，调用方负责关闭返回的连接
+func (p *ConnectionPool) DialPartitionLeader(ctx context.Context, topic string, partition int) (*kafka.Conn, error) {
+	if len(p.config.Brokers) == 0 {
+		return nil, fmt.Errorf("no brokers specified")
+	}
+	return p.dialer.DialLeader(ctx, "tcp", p.config.Brokers[0], topic, partition)
+}
+
 // GetAdminConnection 获取管理连接
 func (p *ConnectionPool) GetAdminConnection() *kafka.Conn {
 	p.mutex.RLock()
@@ -400,6 +541,18 @@ func (p *ConnectionPool) GetAdminConnection() *kafka.Conn {
 	return p.adminConn
 }
 
+// GetAdminClient 获取主题管理客户端
+func (p *ConnectionPool) GetAdminClient() *kafka.Client {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	if p.closed {
+		return nil
+	}
+
+	return p.adminClient
+}
+
 // Close 关闭连接池
 func (p *ConnectionPool) Close() error {
 	p.mutex.Lock()
@@ -433,10 +586,20 @@ func (p *ConnectionPool) Close() error {
 			// 记录错误
 		}
 	}
+	if p.adminClient != nil {
+		if transport, ok := p.adminClient.Transport.(*kafka.Transport); ok {
+			transport.CloseIdleConnections()
+		}
+	}
 
 	return nil
 }
 
+// LatencyPhases 新建broker连接的建连耗时统计
+func (p *ConnectionPool) LatencyPhases() []metrics.PhaseLatency {
+	return p.phases.Breakdown()
+}
+
 // Stats 获取连接池统计信息
 func (p *ConnectionPool) Stats() map[string]interface{} {
 	p.mutex.RLock()
@@ -0,0 +1,119 @@
+package connection
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"abc-runner/app/adapters/kafka/config"
+)
+
+// registryContentType Schema Registry REST API的内容类型
+const registryContentType = "application/vnd.schemaregistry.v1+json"
+
+// RegisteredSchema Schema Registry中的一个schema版本
+type RegisteredSchema struct {
+	ID         int    `json:"id"`
+	Version    int    `json:"version"`
+	SchemaType string `json:"schemaType"` // AVRO、PROTOBUF或JSON，Avro时registry可能省略
+	Schema     string `json:"schema"`
+}
+
+// SchemaRegistryClient Confluent兼容的Schema Registry客户端
+type SchemaRegistryClient struct {
+	baseURL  string
+	username string
+	password string
+	client   *http.Client
+}
+
+// NewSchemaRegistryClient 创建Schema Registry客户端
+func NewSchemaRegistryClient(cfg config.SchemaRegistryConfig, timeout time.Duration) *SchemaRegistryClient {
+	return &SchemaRegistryClient{
+		baseURL:  strings.TrimRight(cfg.URL, "/"),
+		username: cfg.Username,
+		password: cfg.Password,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+// Register 向subject注册schema并返回schema ID，相同schema已注册时registry返回原ID
+func (c *SchemaRegistryClient) Register(ctx context.Context, subject, schemaType, schema string) (int, error) {
+	request := map[string]string{"schema": schema}
+	// 省略schemaType即为Avro，兼容不支持该字段的旧版本registry
+	if schemaType != "AVRO" {
+		request["schemaType"] = schemaType
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return 0, err
+	}
+
+	var response struct {
+		ID int `json:"id"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/subjects/"+url.PathEscape(subject)+"/versions", body, &response); err != nil {
+		return 0, err
+	}
+	return response.ID, nil
+}
+
+// Latest 获取subject的最新schema版本
+func (c *SchemaRegistryClient) Latest(ctx context.Context, subject string) (*RegisteredSchema, error) {
+	var schema RegisteredSchema
+	if err := c.do(ctx, http.MethodGet, "/subjects/"+url.PathEscape(subject)+"/versions/latest", nil, &schema); err != nil {
+		return nil, err
+	}
+	if schema.SchemaType == "" {
+		schema.SchemaType = "AVRO"
+	}
+	return &schema, nil
+}
+
+// do 发送REST请求并解析JSON响应，错误时带上registry返回的error_code与message
+func (c *SchemaRegistryClient) do(ctx context.Context, method, path string, body []byte, out interface{}) error {
+	request, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create schema registry request: %w", err)
+	}
+	if c.username != "" || c.password != "" {
+		request.SetBasicAuth(c.username, c.password)
+	}
+	request.Header.Set("Accept", registryContentType)
+	if body != nil {
+		request.Header.Set("Content-Type", registryContentType)
+	}
+
+	response, err := c.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("schema registry request failed: %w", err)
+	}
+	defer response.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(response.Body, 4<<20))
+
+	if response.StatusCode >= 300 {
+		var registryError struct {
+			ErrorCode int    `json:"error_code"`
+			Message   string `json:"message"`
+		}
+		if json.Unmarshal(data, &registryError) == nil && registryError.Message != "" {
+			return fmt.Errorf("schema registry returned %d (error %d): %s", response.StatusCode, registryError.ErrorCode, registryError.Message)
+		}
+		return fmt.Errorf("schema registry returned %d: %s", response.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("invalid schema registry response: %w", err)
+	}
+	return nil
+}
+
+// Close 关闭空闲连接
+func (c *SchemaRegistryClient) Close() {
+	c.client.CloseIdleConnections()
+}
@@ -0,0 +1,448 @@
+package operations
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// avroMaxDepth 生成递归类型数据时的最大嵌套深度
+const avroMaxDepth = 8
+
+// avroSchema 解析后的Avro schema节点
+type avroSchema struct {
+	kind     string // null、boolean、int、long、float、double、bytes、string、record、enum、array、map、union、fixed
+	name     string // 命名类型的全名
+	fields   []avroField
+	symbols  []string
+	items    *avroSchema // array元素或map值
+	branches []*avroSchema
+	size     int
+}
+
+// avroField record中的字段
+type avroField struct {
+	name       string
+	schema     *avroSchema
+	def        interface{}
+	hasDefault bool
+}
+
+// avroCodec Avro二进制编码
+type avroCodec struct {
+	root *avroSchema
+}
+
+// newAvroCodec 解析Avro schema
+func newAvroCodec(text string) (*avroCodec, error) {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	var node interface{}
+	if err := decoder.Decode(&node); err != nil {
+		return nil, err
+	}
+
+	parser := &avroParser{names: make(map[string]*avroSchema)}
+	root, err := parser.parse(node, "")
+	if err != nil {
+		return nil, err
+	}
+	return &avroCodec{root: root}, nil
+}
+
+// avroParser 解析时记录已定义的命名类型
+type avroParser struct {
+	names map[string]*avroSchema
+}
+
+var avroPrimitives = map[string]bool{
+	"null": true, "boolean": true, "int": true, "long": true,
+	"float": true, "double": true, "bytes": true, "string": true,
+}
+
+// parse 解析schema节点，namespace为外层命名类型的命名空间
+func (p *avroParser) parse(node interface{}, namespace string) (*avroSchema, error) {
+	switch n := node.(type) {
+	case string:
+		if avroPrimitives[n] {
+			return &avroSchema{kind: n}, nil
+		}
+		if named, ok := p.names[p.fullName(n, namespace)]; ok {
+			return named, nil
+		}
+		if named, ok := p.names[n]; ok {
+			return named, nil
+		}
+		return nil, fmt.Errorf("unknown type %q", n)
+	case []interface{}:
+		union := &avroSchema{kind: "union"}
+		for _, branch := range n {
+			schema, err := p.parse(branch, namespace)
+			if err != nil {
+				return nil, err
+			}
+			union.branches = append(union.branches, schema)
+		}
+		if len(union.branches) == 0 {
+			return nil, fmt.Errorf("empty union")
+		}
+		return union, nil
+	case map[string]interface{}:
+		return p.parseComplex(n, namespace)
+	}
+	return nil, fmt.Errorf("invalid schema node %s", describeValue(node))
+}
+
+// parseComplex 解析对象形式的schema
+func (p *avroParser) parseComplex(node map[string]interface{}, namespace string) (*avroSchema, error) {
+	typeName, ok := node["type"].(string)
+	if !ok {
+		// {"type": {...}} 或 {"type": [...]}，逻辑类型等属性对编码没有影响
+		if inner, exists := node["type"]; exists {
+			return p.parse(inner, namespace)
+		}
+		return nil, fmt.Errorf("schema object without type")
+	}
+
+	switch typeName {
+	case "record", "error", "enum", "fixed":
+		name, _ := node["name"].(string)
+		if name == "" {
+			return nil, fmt.Errorf("%s without name", typeName)
+		}
+		if ns, ok := node["namespace"].(string); ok && !strings.Contains(name, ".") {
+			namespace = ns
+		}
+		schema := &avroSchema{kind: typeName, name: p.fullName(name, namespace)}
+		if typeName == "error" {
+			schema.kind = "record"
+		}
+		if i := strings.LastIndex(schema.name, "."); i >= 0 {
+			namespace = schema.name[:i]
+		}
+		// 先登记名称，以支持递归引用
+		p.names[schema.name] = schema
+		return schema, p.parseNamed(schema, node, namespace)
+	case "array", "map":
+		key := "items"
+		if typeName == "map" {
+			key = "values"
+		}
+		items, err := p.parse(node[key], namespace)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", typeName, key, err)
+		}
+		return &avroSchema{kind: typeName, items: items}, nil
+	}
+	return p.parse(typeName, namespace)
+}
+
+// parseNamed 解析record、enum与fixed的定义
+func (p *avroParser) parseNamed(schema *avroSchema, node map[string]interface{}, namespace string) error {
+	switch schema.kind {
+	case "record":
+		fields, _ := node["fields"].([]interface{})
+		for _, raw := range fields {
+			fieldNode, ok := raw.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("record %s: invalid field", schema.name)
+			}
+			name, _ := fieldNode["name"].(string)
+			fieldSchema, err := p.parse(fieldNode["type"], namespace)
+			if err != nil {
+				return fmt.Errorf("%s.%s: %w", schema.name, name, err)
+			}
+			def, hasDefault := fieldNode["default"]
+			schema.fields = append(schema.fields, avroField{name: name, schema: fieldSchema, def: def, hasDefault: hasDefault})
+		}
+	case "enum":
+		symbols, _ := node["symbols"].([]interface{})
+		for _, symbol := range symbols {
+			if s, ok := symbol.(string); ok {
+				schema.symbols = append(schema.symbols, s)
+			}
+		}
+		if len(schema.symbols) == 0 {
+			return fmt.Errorf("enum %s without symbols", schema.name)
+		}
+	case "fixed":
+		size, err := toInt64(node["size"])
+		if err != nil || size < 0 {
+			return fmt.Errorf("fixed %s: invalid size", schema.name)
+		}
+		schema.size = int(size)
+	}
+	return nil
+}
+
+// fullName 按命名空间补全类型名
+func (p *avroParser) fullName(name, namespace string) string {
+	if strings.Contains(name, ".") || namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+// encode 按Avro二进制格式编码
+func (c *avroCodec) encode(value interface{}) ([]byte, error) {
+	return appendAvro(nil, c.root, value, "$")
+}
+
+// appendAvro 编码一个值
+func appendAvro(buf []byte, schema *avroSchema, value interface{}, path string) ([]byte, error) {
+	switch schema.kind {
+	case "null":
+		if value != nil {
+			return nil, fmt.Errorf("%s: expected null, got %s", path, describeValue(value))
+		}
+		return buf, nil
+	case "boolean":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s: expected a boolean, got %s", path, describeValue(value))
+		}
+		if b {
+			return append(buf, 1), nil
+		}
+		return append(buf, 0), nil
+	case "int", "long":
+		n, err := toInt64(value)
+		if _, isString := value.(string); err != nil || isString {
+			return nil, fmt.Errorf("%s: expected an integer, got %s", path, describeValue(value))
+		}
+		if schema.kind == "int" && (n < math.MinInt32 || n > math.MaxInt32) {
+			return nil, fmt.Errorf("%s: %d overflows int", path, n)
+		}
+		return appendAvroLong(buf, n), nil
+	case "float", "double":
+		f, err := toFloat64(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if schema.kind == "float" {
+			return binary.LittleEndian.AppendUint32(buf, math.Float32bits(float32(f))), nil
+		}
+		return binary.LittleEndian.AppendUint64(buf, math.Float64bits(f)), nil
+	case "bytes", "string":
+		data, err := toBytes(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return append(appendAvroLong(buf, int64(len(data))), data...), nil
+	case "fixed":
+		data, err := toBytes(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if len(data) != schema.size {
+			return nil, fmt.Errorf("%s: expected %d bytes for %s, got %d", path, schema.size, schema.name, len(data))
+		}
+		return append(buf, data...), nil
+	case "enum":
+		symbol, _ := value.(string)
+		for i, s := range schema.symbols {
+			if s == symbol {
+				return appendAvroLong(buf, int64(i)), nil
+			}
+		}
+		return nil, fmt.Errorf("%s: %v is not a symbol of %s", path, value, schema.name)
+	case "record":
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: expected an object for %s, got %s", path, schema.name, describeValue(value))
+		}
+		var err error
+		for _, field := range schema.fields {
+			fieldValue, present := fields[field.name]
+			if !present {
+				if !field.hasDefault {
+					return nil, fmt.Errorf("%s: missing field", joinPath(path, field.name))
+				}
+				fieldValue = field.def
+			}
+			if buf, err = appendAvro(buf, field.schema, fieldValue, joinPath(path, field.name)); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: expected an array, got %s", path, describeValue(value))
+		}
+		var err error
+		if len(items) > 0 {
+			buf = appendAvroLong(buf, int64(len(items)))
+			for i, item := range items {
+				if buf, err = appendAvro(buf, schema.items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return appendAvroLong(buf, 0), nil
+	case "map":
+		entries, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: expected an object, got %s", path, describeValue(value))
+		}
+		keys := make([]string, 0, len(entries))
+		for key := range entries {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var err error
+		if len(keys) > 0 {
+			buf = appendAvroLong(buf, int64(len(keys)))
+			for _, key := range keys {
+				buf = append(appendAvroLong(buf, int64(len(key))), key...)
+				if buf, err = appendAvro(buf, schema.items, entries[key], joinPath(path, key)); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return appendAvroLong(buf, 0), nil
+	case "union":
+		index, branchValue, err := selectAvroBranch(schema, value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return appendAvro(appendAvroLong(buf, int64(index)), schema.branches[index], branchValue, path)
+	}
+	return nil, fmt.Errorf("%s: unsupported type %s", path, schema.kind)
+}
+
+// appendAvroLong 按zigzag变长编码写入整数
+func appendAvroLong(buf []byte, n int64) []byte {
+	return protowire.AppendVarint(buf, protowire.EncodeZigZag(n))
+}
+
+// selectAvroBranch 选择union分支：支持Avro JSON编码的 {"类型名": 值} 形式，否则取第一个匹配的分支
+func selectAvroBranch(schema *avroSchema, value interface{}) (int, interface{}, error) {
+	if wrapped, ok := value.(map[string]interface{}); ok && len(wrapped) == 1 {
+		for key, inner := range wrapped {
+			for i, branch := range schema.branches {
+				if avroTypeName(branch) == key {
+					return i, inner, nil
+				}
+			}
+		}
+	}
+	for i, branch := range schema.branches {
+		if avroMatches(branch, value) {
+			return i, value, nil
+		}
+	}
+	return 0, nil, fmt.Errorf("%s matches no branch of the union", describeValue(value))
+}
+
+// avroTypeName union分支在JSON编码中的类型名
+func avroTypeName(schema *avroSchema) string {
+	if schema.name != "" {
+		return schema.name
+	}
+	return schema.kind
+}
+
+// avroMatches 判断值是否可按该类型编码
+func avroMatches(schema *avroSchema, value interface{}) bool {
+	switch schema.kind {
+	case "null":
+		return value == nil
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "int", "long":
+		return isInteger(value)
+	case "float", "double":
+		_, err := toFloat64(value)
+		return err == nil
+	case "string", "bytes", "fixed":
+		data, err := toBytes(value)
+		return err == nil && (schema.kind != "fixed" || len(data) == schema.size)
+	case "enum":
+		symbol, _ := value.(string)
+		for _, s := range schema.symbols {
+			if s == symbol {
+				return true
+			}
+		}
+	case "record", "map":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	}
+	return false
+}
+
+// generate 按schema生成合成数据
+func (c *avroCodec) generate(rng *rand.Rand) interface{} {
+	return generateAvro(c.root, rng, 0)
+}
+
+// generateAvro 生成一个值，超过最大深度时union取null分支、集合为空
+func generateAvro(schema *avroSchema, rng *rand.Rand, depth int) interface{} {
+	switch schema.kind {
+	case "boolean":
+		return rng.IntN(2) == 1
+	case "int":
+		return int64(rng.Int32N(1000000))
+	case "long":
+		return rng.Int64N(1000000000000)
+	case "float", "double":
+		return math.Round(rng.Float64()*100000) / 100
+	case "bytes", "string":
+		return randomString(rng, 8, 16)
+	case "fixed":
+		return randomString(rng, schema.size, schema.size)
+	case "enum":
+		return schema.symbols[rng.IntN(len(schema.symbols))]
+	case "record":
+		fields := make(map[string]interface{}, len(schema.fields))
+		for _, field := range schema.fields {
+			fields[field.name] = generateAvro(field.schema, rng, depth+1)
+		}
+		return fields
+	case "array":
+		items := []interface{}{}
+		if depth < avroMaxDepth {
+			for i := 1 + rng.IntN(3); i > 0; i-- {
+				items = append(items, generateAvro(schema.items, rng, depth+1))
+			}
+		}
+		return items
+	case "map":
+		entries := map[string]interface{}{}
+		if depth < avroMaxDepth {
+			for i := 1 + rng.IntN(3); i > 0; i-- {
+				entries[fmt.Sprintf("key%d", i)] = generateAvro(schema.items, rng, depth+1)
+			}
+		}
+		return entries
+	case "union":
+		var candidates []*avroSchema
+		for _, branch := range schema.branches {
+			if (branch.kind == "null") == (depth >= avroMaxDepth) {
+				candidates = append(candidates, branch)
+			}
+		}
+		if len(candidates) == 0 {
+			candidates = schema.branches
+		}
+		branch := candidates[rng.IntN(len(candidates))]
+		value := generateAvro(branch, rng, depth+1)
+		// 命名类型与null之外的分支可能被前面的分支匹配（如int与long），按JSON编码形式包装以确定分支
+		if branch.kind != "null" {
+			return map[string]interface{}{avroTypeName(branch): value}
+		}
+		return value
+	}
+	return nil
+}
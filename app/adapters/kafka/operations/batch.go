@@ -0,0 +1,204 @@
+package operations
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"abc-runner/app/core/metrics"
+)
+
+// BatchSizeBucket 批次大小分布中的一个区间，包含Min到Max条消息的批次
+type BatchSizeBucket struct {
+	Min   int   `json:"min"`
+	Max   int   `json:"max"`
+	Count int64 `json:"count"`
+}
+
+// BatchDirectionStats 一个方向（生产或消费）的批量读写统计
+type BatchDirectionStats struct {
+	Batches     int64                  `json:"batches"`
+	Errors      int64                  `json:"errors"`
+	Messages    int64                  `json:"messages"`
+	Bytes       int64                  `json:"bytes"`
+	AvgSize     float64                `json:"avg_size"` // 成功批次的平均消息数
+	Sizes       []BatchSizeBucket      `json:"sizes"`
+	Latency     metrics.LatencyMetrics `json:"latency"`       // 单个批次的延迟
+	BytesPerSec float64                `json:"bytes_per_sec"` // 首个批次开始到最后一个批次结束期间的字节速率
+	MsgsPerSec  float64                `json:"msgs_per_sec"`
+}
+
+// ToMap 转换为报告使用的map
+func (s BatchDirectionStats) ToMap() map[string]interface{} {
+	sizes := make([]map[string]interface{}, 0, len(s.Sizes))
+	for _, bucket := range s.Sizes {
+		sizes = append(sizes, map[string]interface{}{
+			"min":   bucket.Min,
+			"max":   bucket.Max,
+			"count": bucket.Count,
+		})
+	}
+	return map[string]interface{}{
+		"batches":       s.Batches,
+		"errors":        s.Errors,
+		"messages":      s.Messages,
+		"bytes":         s.Bytes,
+		"avg_size":      s.AvgSize,
+		"sizes":         sizes,
+		"latency":       s.Latency,
+		"bytes_per_sec": s.BytesPerSec,
+		"msgs_per_sec":  s.MsgsPerSec,
+	}
+}
+
+// BatchStats 批量生产/消费统计
+type BatchStats struct {
+	BatchSize int                  `json:"batch_size"` // 配置的每批消息数
+	Produce   *BatchDirectionStats `json:"produce,omitempty"`
+	Consume   *BatchDirectionStats `json:"consume,omitempty"`
+}
+
+// ToMap 转换为报告使用的map
+func (s BatchStats) ToMap() map[string]interface{} {
+	result := map[string]interface{}{"batch_size": s.BatchSize}
+	if s.Produce != nil {
+		result["produce"] = s.Produce.ToMap()
+	}
+	if s.Consume != nil {
+		result["consume"] = s.Consume.ToMap()
+	}
+	return result
+}
+
+// batchDirectionTracker 一个方向的批次统计累计
+type batchDirectionTracker struct {
+	batches  int64
+	errors   int64
+	messages int64
+	bytes    int64
+	sizes    map[int]int64 // 按区间上界（2的幂）计数
+	first    time.Time
+	last     time.Time
+	latency  *metrics.LatencyTracker
+}
+
+func newBatchDirectionTracker() *batchDirectionTracker {
+	return &batchDirectionTracker{
+		sizes: make(map[int]int64),
+		latency: metrics.NewLatencyTracker(metrics.LatencyConfig{
+			SignificantDigits: metrics.DefaultHdrSignificantDigits,
+			SamplingRate:      1.0,
+		}),
+	}
+}
+
+// record 记录一个批次
+func (t *batchDirectionTracker) record(start time.Time, duration time.Duration, messages int, bytes int64, err error) {
+	if t.first.IsZero() || start.Before(t.first) {
+		t.first = start
+	}
+	if end := start.Add(duration); end.After(t.last) {
+		t.last = end
+	}
+
+	t.batches++
+	if err != nil {
+		t.errors++
+		return
+	}
+	t.messages += int64(messages)
+	t.bytes += bytes
+	t.sizes[batchSizeBucket(messages)]++
+	t.latency.Record(duration)
+}
+
+// stats 汇总统计
+func (t *batchDirectionTracker) stats() *BatchDirectionStats {
+	stats := &BatchDirectionStats{
+		Batches:  t.batches,
+		Errors:   t.errors,
+		Messages: t.messages,
+		Bytes:    t.bytes,
+		Latency:  t.latency.GetMetrics(),
+	}
+	if succeeded := t.batches - t.errors; succeeded > 0 {
+		stats.AvgSize = float64(t.messages) / float64(succeeded)
+	}
+	if elapsed := t.last.Sub(t.first); elapsed > 0 {
+		stats.BytesPerSec = float64(t.bytes) / elapsed.Seconds()
+		stats.MsgsPerSec = float64(t.messages) / elapsed.Seconds()
+	}
+
+	for max, count := range t.sizes {
+		stats.Sizes = append(stats.Sizes, BatchSizeBucket{Min: max/2 + 1, Max: max, Count: count})
+	}
+	sort.Slice(stats.Sizes, func(i, j int) bool { return stats.Sizes[i].Max < stats.Sizes[j].Max })
+	if len(stats.Sizes) > 0 && stats.Sizes[0].Max == 0 {
+		stats.Sizes[0].Min = 0
+	}
+	return stats
+}
+
+// batchSizeBucket 批次大小所在区间的上界：0、1、2、4、8…
+func batchSizeBucket(messages int) int {
+	if messages <= 0 {
+		return 0
+	}
+	bound := 1
+	for bound < messages {
+		bound *= 2
+	}
+	return bound
+}
+
+// BatchTracker 批量读写统计器，nil时不做任何事
+type BatchTracker struct {
+	batchSize int
+
+	mutex   sync.Mutex
+	produce *batchDirectionTracker
+	consume *batchDirectionTracker
+}
+
+// NewBatchTracker 创建批量读写统计器，batchSize为配置的每批消息数
+func NewBatchTracker(batchSize int) *BatchTracker {
+	return &BatchTracker{batchSize: batchSize}
+}
+
+// Record 记录一个批次，read表示消费批次
+func (t *BatchTracker) Record(read bool, start time.Time, duration time.Duration, messages int, bytes int64, err error) {
+	if t == nil {
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	direction := &t.produce
+	if read {
+		direction = &t.consume
+	}
+	if *direction == nil {
+		*direction = newBatchDirectionTracker()
+	}
+	(*direction).record(start, duration, messages, bytes, err)
+}
+
+// Stats 获取统计结果，没有记录过批次时返回false
+func (t *BatchTracker) Stats() (BatchStats, bool) {
+	if t == nil {
+		return BatchStats{}, false
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	stats := BatchStats{BatchSize: t.batchSize}
+	if t.produce != nil {
+		stats.Produce = t.produce.stats()
+	}
+	if t.consume != nil {
+		stats.Consume = t.consume.stats()
+	}
+	return stats, t.produce != nil || t.consume != nil
+}
@@ -0,0 +1,68 @@
+package operations
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBatchSizeBucket(t *testing.T) {
+	cases := map[int]int{0: 0, 1: 1, 2: 2, 3: 4, 4: 4, 5: 8, 100: 128, 128: 128, 129: 256}
+	for messages, expected := range cases {
+		if bucket := batchSizeBucket(messages); bucket != expected {
+			t.Errorf("batchSizeBucket(%d) = %d, expected %d", messages, bucket, expected)
+		}
+	}
+}
+
+func TestBatchTracker(t *testing.T) {
+	var nilTracker *BatchTracker
+	nilTracker.Record(false, time.Now(), time.Millisecond, 1, 1, nil)
+	if _, ok := nilTracker.Stats(); ok {
+		t.Errorf("expected no stats from a nil tracker")
+	}
+
+	tracker := NewBatchTracker(100)
+	if _, ok := tracker.Stats(); ok {
+		t.Errorf("expected no stats before the first batch")
+	}
+
+	start := time.Now()
+	tracker.Record(false, start, 500*time.Millisecond, 100, 512*1024, nil)
+	tracker.Record(false, start.Add(500*time.Millisecond), 500*time.Millisecond, 100, 512*1024, nil)
+	tracker.Record(false, start.Add(time.Second), 0, 40, 0, errors.New("leader not available"))
+	tracker.Record(false, start.Add(500*time.Millisecond), 100*time.Millisecond, 30, 0, nil)
+
+	stats, ok := tracker.Stats()
+	if !ok || stats.BatchSize != 100 || stats.Consume != nil || stats.Produce == nil {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	produce := stats.Produce
+	if produce.Batches != 4 || produce.Errors != 1 || produce.Messages != 230 {
+		t.Errorf("unexpected counts: %+v", produce)
+	}
+	if produce.AvgSize < 76.6 || produce.AvgSize > 76.7 {
+		t.Errorf("expected an average of 230/3 messages per batch, got %.2f", produce.AvgSize)
+	}
+	// 字节速率按首个批次开始到最后一个批次结束的1秒计算
+	if produce.BytesPerSec != 1024*1024 || produce.MsgsPerSec != 230 {
+		t.Errorf("expected 1 MB/s and 230 messages/s, got %.0f B/s and %.0f messages/s", produce.BytesPerSec, produce.MsgsPerSec)
+	}
+	expected := []BatchSizeBucket{{Min: 17, Max: 32, Count: 1}, {Min: 65, Max: 128, Count: 2}}
+	if len(produce.Sizes) != len(expected) {
+		t.Fatalf("unexpected size distribution %+v", produce.Sizes)
+	}
+	for i := range expected {
+		if produce.Sizes[i] != expected[i] {
+			t.Errorf("unexpected size distribution %+v, expected %+v", produce.Sizes, expected)
+		}
+	}
+	if produce.Latency.Max < 499*time.Millisecond {
+		t.Errorf("expected the batch latency to be tracked, got max %v", produce.Latency.Max)
+	}
+
+	tracker.Record(true, start, 10*time.Millisecond, 64, 64*1024, nil)
+	if stats, _ := tracker.Stats(); stats.Consume == nil || stats.Consume.Messages != 64 {
+		t.Errorf("expected consume batches to be tracked separately, got %+v", stats.Consume)
+	}
+}
@@ -0,0 +1,450 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	kafkaConfig "abc-runner/app/adapters/kafka/config"
+	"abc-runner/app/adapters/kafka/connection"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// ColdReadTier 冷读取或末尾读取的统计
+type ColdReadTier struct {
+	Reads      int64                  `json:"reads"`       // 拉取次数
+	Errors     int64                  `json:"errors"`      // 失败的拉取
+	Empty      int64                  `json:"empty"`       // 未返回消息的拉取
+	Messages   int64                  `json:"messages"`    // 读取的消息数
+	Bytes      int64                  `json:"bytes"`       // 读取的键与值字节数
+	Latency    metrics.LatencyMetrics `json:"latency"`     // 单次拉取延迟（发起拉取到读完本批消息）
+	MessageAge time.Duration          `json:"message_age"` // 读取到的消息的平均年龄
+	Throughput float64                `json:"throughput"`  // 拉取期间的读取速率（MB/s）
+}
+
+// ToMap 转换为报告使用的map
+func (t ColdReadTier) ToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"reads":       t.Reads,
+		"errors":      t.Errors,
+		"empty":       t.Empty,
+		"messages":    t.Messages,
+		"bytes":       t.Bytes,
+		"latency_avg": t.Latency.Average.String(),
+		"latency_p50": t.Latency.P50.String(),
+		"latency_p99": t.Latency.P99.String(),
+		"latency_max": t.Latency.Max.String(),
+		"message_age": t.MessageAge.String(),
+		"throughput":  t.Throughput,
+	}
+}
+
+// ColdReadStats 冷数据读取（分层存储）测试结果
+type ColdReadStats struct {
+	Topic          string        `json:"topic"`
+	Age            time.Duration `json:"age"`
+	Batch          int           `json:"batch"`
+	Partitions     int           `json:"partitions"`      // 参与测试的非空分区数
+	ColdPartitions int           `json:"cold_partitions"` // 存在早于Age消息的分区数
+	Cold           ColdReadTier  `json:"cold"`
+	Tail           ColdReadTier  `json:"tail"`
+	Wraps          int64         `json:"wraps"`       // 冷数据范围读完后从头重读的次数，重读可能命中broker缓存
+	PenaltyP50     float64       `json:"penalty_p50"` // 冷读取p50延迟相对末尾读取的倍数
+	PenaltyP99     float64       `json:"penalty_p99"` // 冷读取p99延迟相对末尾读取的倍数
+	Duration       time.Duration `json:"duration"`
+}
+
+// ToMap 转换为报告使用的map
+func (s *ColdReadStats) ToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"topic":           s.Topic,
+		"age":             s.Age.String(),
+		"batch":           s.Batch,
+		"partitions":      s.Partitions,
+		"cold_partitions": s.ColdPartitions,
+		"cold":            s.Cold.ToMap(),
+		"tail":            s.Tail.ToMap(),
+		"wraps":           s.Wraps,
+		"penalty_p50":     s.PenaltyP50,
+		"penalty_p99":     s.PenaltyP99,
+		"duration":        s.Duration.String(),
+	}
+}
+
+// ColdReadBenchmark 冷数据读取（分层存储）测试
+// 交替从早于Age的历史偏移和分区末尾拉取消息，分别统计拉取延迟，用于量化分层存储的远端读取开销；
+// 冷读取在各分区的历史范围内顺序前进，避免重复读取同一段数据命中broker缓存
+type ColdReadBenchmark struct {
+	pool      *connection.ConnectionPool
+	config    *kafkaConfig.KafkaAdapterConfig
+	collector interfaces.DefaultMetricsCollector
+}
+
+// NewColdReadBenchmark 创建冷数据读取测试
+func NewColdReadBenchmark(pool *connection.ConnectionPool, config *kafkaConfig.KafkaAdapterConfig, collector interfaces.DefaultMetricsCollector) *ColdReadBenchmark {
+	return &ColdReadBenchmark{
+		pool:      pool,
+		config:    config,
+		collector: collector,
+	}
+}
+
+// coldReadPartition 单个分区的读取范围
+type coldReadPartition struct {
+	id        int
+	coldStart int64 // 冷读取范围[coldStart, coldEnd)，其中的消息早于Age
+	coldEnd   int64
+	tailStart int64 // 末尾读取的起始偏移
+
+	mutex  sync.Mutex
+	cursor int64
+	wraps  int64
+}
+
+// planColdRange 根据分区的首尾偏移与Age对应的偏移计算读取范围
+// boundary为首条时间戳不早于(now-Age)的消息偏移，小于0表示全部消息都早于Age；
+// span为冷读取需要覆盖的消息数，取boundary之前最近的span条消息，且不与末尾读取的范围重叠
+func planColdRange(first, last, boundary, span int64, batch int) (coldStart, coldEnd, tailStart int64) {
+	tailStart = last - int64(batch)
+	if tailStart < first {
+		tailStart = first
+	}
+
+	coldEnd = boundary
+	if coldEnd < 0 || coldEnd > last {
+		coldEnd = last
+	}
+	if coldEnd > tailStart {
+		coldEnd = tailStart
+	}
+
+	coldStart = coldEnd - span
+	if coldStart < first {
+		coldStart = first
+	}
+	if coldEnd < coldStart {
+		coldEnd = coldStart
+	}
+	return coldStart, coldEnd, tailStart
+}
+
+// hasCold 分区是否存在可冷读取的消息
+func (p *coldReadPartition) hasCold() bool {
+	return p.coldEnd > p.coldStart
+}
+
+// nextCold 分配下一次冷读取的起始偏移，读完范围后回到起点重读
+func (p *coldReadPartition) nextCold(batch int) int64 {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.cursor >= p.coldEnd {
+		p.cursor = p.coldStart
+		p.wraps++
+	}
+	offset := p.cursor
+	p.cursor += int64(batch)
+	return offset
+}
+
+// Run 执行测试
+func (b *ColdReadBenchmark) Run(ctx context.Context) (*ColdReadStats, error) {
+	topic := b.config.Benchmark.DefaultTopic
+	coldRead := b.config.ColdRead
+
+	partitions, err := b.plan(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(partitions) == 0 {
+		return nil, fmt.Errorf("topic %s has no messages", topic)
+	}
+	var cold []*coldReadPartition
+	for _, partition := range partitions {
+		if partition.hasCold() {
+			cold = append(cold, partition)
+		}
+	}
+	if len(cold) == 0 {
+		return nil, fmt.Errorf("topic %s has no messages older than %v", topic, coldRead.Age)
+	}
+
+	stats := &ColdReadStats{
+		Topic:          topic,
+		Age:            coldRead.Age,
+		Batch:          coldRead.Batch,
+		Partitions:     len(partitions),
+		ColdPartitions: len(cold),
+	}
+
+	coldTier := newColdReadTierTracker()
+	tailTier := newColdReadTierTracker()
+
+	total := int64(b.config.Benchmark.Total)
+	parallels := b.config.Benchmark.Parallels
+	if parallels <= 0 {
+		parallels = 1
+	}
+
+	startTime := time.Now()
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for worker := 0; worker < parallels; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			conns := make(map[int]*kafka.Conn)
+			defer func() {
+				for _, conn := range conns {
+					conn.Close()
+				}
+			}()
+
+			for ctx.Err() == nil {
+				job := next.Add(1) - 1
+				if job >= total {
+					return
+				}
+				// 冷读取与末尾读取交替进行，两者经历相同的集群负载
+				isCold := job%2 == 0
+				round := int(job / 2)
+				var partition *coldReadPartition
+				var offset int64
+				if isCold {
+					partition = cold[round%len(cold)]
+					offset = partition.nextCold(coldRead.Batch)
+				} else {
+					partition = partitions[round%len(partitions)]
+					offset = partition.tailStart
+				}
+
+				result := b.fetch(ctx, conns, topic, partition.id, offset)
+				operationType := "tail_read"
+				if isCold {
+					operationType = "cold_read"
+					coldTier.record(result)
+				} else {
+					tailTier.record(result)
+				}
+				b.collector.Record(&interfaces.OperationResult{
+					Success:  result.err == nil,
+					IsRead:   true,
+					Duration: result.duration,
+					Error:    result.err,
+					Metadata: map[string]interface{}{
+						"operation_type": operationType,
+						"partition":      partition.id,
+						"offset":         offset,
+						"messages":       result.messages,
+					},
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats.Duration = time.Since(startTime)
+	stats.Cold = coldTier.stats()
+	stats.Tail = tailTier.stats()
+	for _, partition := range cold {
+		stats.Wraps += partition.wraps
+	}
+	stats.PenaltyP50 = latencyRatio(stats.Cold.Latency.P50, stats.Tail.Latency.P50)
+	stats.PenaltyP99 = latencyRatio(stats.Cold.Latency.P99, stats.Tail.Latency.P99)
+
+	if ctx.Err() != nil {
+		return stats, ctx.Err()
+	}
+	return stats, nil
+}
+
+// plan 查询各分区的首尾偏移与Age对应的偏移，计算读取范围
+func (b *ColdReadBenchmark) plan(ctx context.Context, topic string) ([]*coldReadPartition, error) {
+	lookup, err := b.pool.LookupPartitions(ctx, topic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lookup partitions of %s: %w", topic, err)
+	}
+	if len(lookup) == 0 {
+		return nil, fmt.Errorf("topic %s has no partitions", topic)
+	}
+
+	type offsets struct {
+		first, last, boundary int64
+	}
+	cutoff := time.Now().Add(-b.config.ColdRead.Age)
+	found := make([]offsets, len(lookup))
+	coldPartitions := 0
+	for i, partition := range lookup {
+		conn, err := b.pool.DialPartitionLeader(ctx, topic, partition.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial leader of %s/%d: %w", topic, partition.ID, err)
+		}
+		first, last, err := conn.ReadOffsets()
+		if err == nil {
+			found[i].boundary, err = conn.ReadOffset(cutoff)
+		}
+		conn.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read offsets of %s/%d: %w", topic, partition.ID, err)
+		}
+		found[i].first, found[i].last = first, last
+		if found[i].boundary < 0 || found[i].boundary > first {
+			coldPartitions++
+		}
+	}
+	if coldPartitions == 0 {
+		coldPartitions = 1
+	}
+
+	// 冷读取平均分配到存在历史消息的分区，每个分区覆盖足够的消息使整个测试不重读
+	batch := b.config.ColdRead.Batch
+	coldReads := int64(b.config.Benchmark.Total+1) / 2
+	span := (coldReads + int64(coldPartitions) - 1) / int64(coldPartitions) * int64(batch)
+
+	partitions := make([]*coldReadPartition, 0, len(lookup))
+	for i, partition := range lookup {
+		if found[i].last <= found[i].first {
+			continue // 空分区
+		}
+		coldStart, coldEnd, tailStart := planColdRange(found[i].first, found[i].last, found[i].boundary, span, batch)
+		partitions = append(partitions, &coldReadPartition{
+			id:        partition.ID,
+			coldStart: coldStart,
+			coldEnd:   coldEnd,
+			tailStart: tailStart,
+			cursor:    coldStart,
+		})
+	}
+	return partitions, nil
+}
+
+// coldReadResult 单次拉取的结果
+type coldReadResult struct {
+	messages int
+	bytes    int64
+	ageSum   time.Duration
+	duration time.Duration
+	err      error
+}
+
+// fetch 从指定偏移发起一次拉取并读取至多Batch条消息，连接出错后丢弃以便下次重连
+func (b *ColdReadBenchmark) fetch(ctx context.Context, conns map[int]*kafka.Conn, topic string, partition int, offset int64) coldReadResult {
+	conn, ok := conns[partition]
+	if !ok {
+		var err error
+		conn, err = b.pool.DialPartitionLeader(ctx, topic, partition)
+		if err != nil {
+			return coldReadResult{err: err}
+		}
+		conns[partition] = conn
+	}
+
+	conn.SetReadDeadline(time.Now().Add(b.config.Benchmark.GetTimeout()))
+	if _, err := conn.Seek(offset, kafka.SeekAbsolute|kafka.SeekDontCheck); err != nil {
+		conn.Close()
+		delete(conns, partition)
+		return coldReadResult{err: err}
+	}
+
+	var result coldReadResult
+	start := time.Now()
+	batch := conn.ReadBatchWith(kafka.ReadBatchConfig{
+		MinBytes: 1,
+		MaxBytes: b.config.Consumer.FetchMaxBytes,
+		MaxWait:  b.config.Consumer.FetchMaxWait,
+	})
+	for result.messages < b.config.ColdRead.Batch {
+		msg, err := batch.ReadMessage()
+		if err != nil {
+			break
+		}
+		result.messages++
+		result.bytes += int64(len(msg.Key) + len(msg.Value))
+		result.ageSum += start.Sub(msg.Time)
+	}
+	result.err = batch.Close()
+	result.duration = time.Since(start)
+	if result.err != nil {
+		conn.Close()
+		delete(conns, partition)
+	}
+	return result
+}
+
+// coldReadTierTracker 一类读取的统计累计
+type coldReadTierTracker struct {
+	mutex    sync.Mutex
+	reads    int64
+	errors   int64
+	empty    int64
+	messages int64
+	bytes    int64
+	ageSum   time.Duration
+	busy     time.Duration
+	latency  *metrics.LatencyTracker
+}
+
+func newColdReadTierTracker() *coldReadTierTracker {
+	return &coldReadTierTracker{
+		latency: metrics.NewLatencyTracker(metrics.LatencyConfig{
+			SignificantDigits: metrics.DefaultHdrSignificantDigits,
+			SamplingRate:      1.0,
+		}),
+	}
+}
+
+// record 记录一次拉取
+func (t *coldReadTierTracker) record(result coldReadResult) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.reads++
+	if result.err != nil {
+		t.errors++
+		return
+	}
+	if result.messages == 0 {
+		t.empty++
+	}
+	t.messages += int64(result.messages)
+	t.bytes += result.bytes
+	t.ageSum += result.ageSum
+	t.busy += result.duration
+	t.latency.Record(result.duration)
+}
+
+// stats 汇总统计
+func (t *coldReadTierTracker) stats() ColdReadTier {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	tier := ColdReadTier{
+		Reads:    t.reads,
+		Errors:   t.errors,
+		Empty:    t.empty,
+		Messages: t.messages,
+		Bytes:    t.bytes,
+		Latency:  t.latency.GetMetrics(),
+	}
+	if t.messages > 0 {
+		tier.MessageAge = t.ageSum / time.Duration(t.messages)
+	}
+	if t.busy > 0 {
+		tier.Throughput = float64(t.bytes) / 1024 / 1024 / t.busy.Seconds()
+	}
+	return tier
+}
+
+// latencyRatio 计算延迟倍数，基准为0时返回0
+func latencyRatio(value, base time.Duration) float64 {
+	if base <= 0 {
+		return 0
+	}
+	return float64(value) / float64(base)
+}
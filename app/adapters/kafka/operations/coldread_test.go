@@ -0,0 +1,71 @@
+package operations
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPlanColdRange(t *testing.T) {
+	cases := []struct {
+		name                          string
+		first, last, boundary, span   int64
+		coldStart, coldEnd, tailStart int64
+	}{
+		{"span before boundary", 0, 10000, 6000, 1000, 5000, 6000, 9900},
+		{"span clamped to first offset", 4000, 10000, 4500, 1000, 4000, 4500, 9900},
+		{"all messages older than age", 0, 10000, -1, 1000, 8900, 9900, 9900},
+		{"no messages older than age", 4000, 10000, 4000, 1000, 4000, 4000, 9900},
+		{"partition smaller than one batch", 0, 50, -1, 1000, 0, 0, 0},
+	}
+	for _, c := range cases {
+		coldStart, coldEnd, tailStart := planColdRange(c.first, c.last, c.boundary, c.span, 100)
+		if coldStart != c.coldStart || coldEnd != c.coldEnd || tailStart != c.tailStart {
+			t.Errorf("%s: got [%d, %d) tail %d, expected [%d, %d) tail %d", c.name,
+				coldStart, coldEnd, tailStart, c.coldStart, c.coldEnd, c.tailStart)
+		}
+	}
+}
+
+func TestColdReadCursorWraps(t *testing.T) {
+	partition := &coldReadPartition{coldStart: 100, coldEnd: 300, cursor: 100}
+	var offsets []int64
+	for i := 0; i < 5; i++ {
+		offsets = append(offsets, partition.nextCold(100))
+	}
+	expected := []int64{100, 200, 100, 200, 100}
+	for i := range expected {
+		if offsets[i] != expected[i] {
+			t.Fatalf("unexpected cold offsets %v, expected %v", offsets, expected)
+		}
+	}
+	if partition.wraps != 2 {
+		t.Errorf("expected 2 wraps, got %d", partition.wraps)
+	}
+}
+
+func TestColdReadTierTracker(t *testing.T) {
+	tracker := newColdReadTierTracker()
+	tracker.record(coldReadResult{messages: 100, bytes: 1024 * 1024, ageSum: 100 * time.Hour, duration: 500 * time.Millisecond})
+	tracker.record(coldReadResult{messages: 100, bytes: 1024 * 1024, ageSum: 300 * time.Hour, duration: 500 * time.Millisecond})
+	tracker.record(coldReadResult{duration: time.Millisecond})
+	tracker.record(coldReadResult{err: errors.New("timeout")})
+
+	stats := tracker.stats()
+	if stats.Reads != 4 || stats.Errors != 1 || stats.Empty != 1 || stats.Messages != 200 {
+		t.Errorf("unexpected counts: %+v", stats)
+	}
+	if stats.MessageAge != 2*time.Hour {
+		t.Errorf("expected average message age of 2h, got %v", stats.MessageAge)
+	}
+	if stats.Throughput < 1.9 || stats.Throughput > 2 {
+		t.Errorf("expected about 2 MB/s, got %.2f", stats.Throughput)
+	}
+
+	if ratio := latencyRatio(30*time.Millisecond, 10*time.Millisecond); ratio != 3 {
+		t.Errorf("expected a 3x penalty, got %.2f", ratio)
+	}
+	if ratio := latencyRatio(time.Millisecond, 0); ratio != 0 {
+		t.Errorf("expected no penalty without a baseline, got %.2f", ratio)
+	}
+}
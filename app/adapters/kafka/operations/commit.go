@@ -0,0 +1,365 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	kafkaConfig "abc-runner/app/adapters/kafka/config"
+	"abc-runner/app/adapters/kafka/connection"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// CommitStrategy 消费者偏移提交策略
+type CommitStrategy struct {
+	Auto     bool          // 自动提交（由客户端按间隔提交）
+	Interval time.Duration // 自动提交间隔，0表示每条消息同步提交
+	Sync     bool          // 手动提交时是否同步等待提交完成
+	Batch    int           // 手动提交时每N条消息提交一次
+}
+
+// NewCommitStrategy 从消费者配置创建提交策略
+func NewCommitStrategy(config *kafkaConfig.ConsumerConfig) CommitStrategy {
+	strategy := CommitStrategy{
+		Auto:     config.EnableAutoCommit,
+		Interval: config.AutoCommitInterval,
+		Sync:     config.CommitSync,
+		Batch:    config.CommitBatch,
+	}
+	if strategy.Batch <= 0 {
+		strategy.Batch = 1
+	}
+	return strategy
+}
+
+// String 策略描述
+func (s CommitStrategy) String() string {
+	if s.Auto {
+		if s.Interval <= 0 {
+			return "auto (per-message)"
+		}
+		return fmt.Sprintf("auto (interval=%v)", s.Interval)
+	}
+	mode := "async"
+	if s.Sync {
+		mode = "sync"
+	}
+	return fmt.Sprintf("manual %s (every %d messages)", mode, s.Batch)
+}
+
+// CommitStats 提交策略测试结果
+type CommitStats struct {
+	Strategy      string                 `json:"strategy"`
+	Restarts      int                    `json:"restarts"`
+	Produced      int64                  `json:"produced"`
+	Consumed      int64                  `json:"consumed"`
+	Unique        int64                  `json:"unique"`
+	Duplicates    int64                  `json:"duplicates"`
+	DuplicateRate float64                `json:"duplicate_rate"`
+	Commits       int64                  `json:"commits"`
+	CommitErrors  int64                  `json:"commit_errors"`
+	CommitLatency metrics.LatencyMetrics `json:"commit_latency"`
+	Duration      time.Duration          `json:"duration"`
+}
+
+// ToMap 转换为报告使用的map
+func (s *CommitStats) ToMap() map[string]interface{} {
+	result := map[string]interface{}{
+		"strategy":       s.Strategy,
+		"restarts":       s.Restarts,
+		"produced":       s.Produced,
+		"consumed":       s.Consumed,
+		"unique":         s.Unique,
+		"duplicates":     s.Duplicates,
+		"duplicate_rate": s.DuplicateRate,
+		"commits":        s.Commits,
+		"commit_errors":  s.CommitErrors,
+		"duration":       s.Duration.String(),
+	}
+	if s.Commits > 0 {
+		result["commit_latency_avg"] = s.CommitLatency.Average.String()
+		result["commit_latency_p50"] = s.CommitLatency.P50.String()
+		result["commit_latency_p99"] = s.CommitLatency.P99.String()
+		result["commit_latency_max"] = s.CommitLatency.Max.String()
+	}
+	return result
+}
+
+// groupReader 消费者组读取器，测试中可替换为模拟实现
+type groupReader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// CommitBenchmark 偏移提交策略基准测试
+// 先向主题写入一批带运行标识的消息，再用独立消费者组分阶段消费；
+// 每个阶段结束时直接关闭消费者（不提交未提交的偏移）以模拟重启，
+// 统计重新加入消费者组后重复消费的消息数量以及提交延迟
+type CommitBenchmark struct {
+	pool      *connection.ConnectionPool
+	config    *kafkaConfig.KafkaAdapterConfig
+	collector interfaces.DefaultMetricsCollector
+	strategy  CommitStrategy
+	newReader func(topic, groupID string) groupReader
+
+	commitLatency *metrics.LatencyTracker
+	commits       atomic.Int64
+	commitErrors  atomic.Int64
+}
+
+// NewCommitBenchmark 创建偏移提交策略基准测试
+func NewCommitBenchmark(pool *connection.ConnectionPool, config *kafkaConfig.KafkaAdapterConfig, collector interfaces.DefaultMetricsCollector) *CommitBenchmark {
+	return &CommitBenchmark{
+		pool:      pool,
+		config:    config,
+		collector: collector,
+		strategy:  NewCommitStrategy(&config.Consumer),
+		// 读取器不启用客户端自动提交：kafka-go在Close时会刷新待提交的偏移，无法模拟崩溃
+		newReader: func(topic, groupID string) groupReader {
+			return pool.NewGroupReader(topic, groupID, 0, kafka.FirstOffset)
+		},
+		commitLatency: metrics.NewLatencyTracker(metrics.LatencyConfig{
+			SamplingRate: 1.0,
+		}),
+	}
+}
+
+// Run 执行测试
+func (b *CommitBenchmark) Run(ctx context.Context) (*CommitStats, error) {
+	total := b.config.Benchmark.Total
+	restarts := b.config.Benchmark.Restarts
+	topic := b.config.Benchmark.DefaultTopic
+	runID := fmt.Sprintf("commit-%d", time.Now().UnixNano())
+	groupID := b.config.Consumer.GroupID + "-" + runID
+
+	startTime := time.Now()
+	stats := &CommitStats{Strategy: b.strategy.String(), Restarts: restarts}
+
+	produced, err := b.seed(ctx, topic, runID, total)
+	stats.Produced = int64(produced)
+	if err != nil {
+		return stats, fmt.Errorf("failed to seed topic %s: %w", topic, err)
+	}
+
+	tracker := newDuplicateTracker(runID + "-")
+	for phase, quota := range phaseQuotas(total, restarts) {
+		if err := b.consumePhase(ctx, topic, groupID, tracker, quota); err != nil {
+			return b.finish(stats, tracker, startTime), fmt.Errorf("consume phase %d failed: %w", phase+1, err)
+		}
+		if tracker.unique() >= int64(total) {
+			break
+		}
+	}
+
+	return b.finish(stats, tracker, startTime), nil
+}
+
+// finish 汇总统计
+func (b *CommitBenchmark) finish(stats *CommitStats, tracker *duplicateTracker, startTime time.Time) *CommitStats {
+	stats.Consumed = tracker.consumed
+	stats.Unique = tracker.unique()
+	stats.Duplicates = tracker.duplicates
+	if stats.Consumed > 0 {
+		stats.DuplicateRate = float64(stats.Duplicates) / float64(stats.Consumed) * 100
+	}
+	stats.Commits = b.commits.Load()
+	stats.CommitErrors = b.commitErrors.Load()
+	stats.CommitLatency = b.commitLatency.GetMetrics()
+	stats.Duration = time.Since(startTime)
+	return stats
+}
+
+// seed 写入带运行标识的测试消息
+func (b *CommitBenchmark) seed(ctx context.Context, topic, runID string, total int) (int, error) {
+	producer, err := b.pool.GetProducer()
+	if err != nil {
+		return 0, err
+	}
+	defer b.pool.ReturnProducer(producer)
+
+	value := []byte(strings.Repeat("x", b.config.Benchmark.MessageSize))
+	const batchSize = 500
+	produced := 0
+	for produced < total {
+		n := batchSize
+		if total-produced < n {
+			n = total - produced
+		}
+		messages := make([]kafka.Message, n)
+		for i := range messages {
+			messages[i] = kafka.Message{
+				Topic: topic,
+				Key:   []byte(fmt.Sprintf("%s-%d", runID, produced+i)),
+				Value: value,
+			}
+		}
+		if err := producer.WriteMessages(ctx, messages...); err != nil {
+			return produced, err
+		}
+		produced += n
+	}
+	return produced, nil
+}
+
+// consumePhase 消费指定数量的新消息后关闭消费者，模拟一次重启
+// 所有提交都由测试显式发出，自动提交模式按间隔模拟客户端的后台提交
+func (b *CommitBenchmark) consumePhase(ctx context.Context, topic, groupID string, tracker *duplicateTracker, quota int) error {
+	reader := b.newReader(topic, groupID)
+
+	var inflight sync.WaitGroup
+	defer func() {
+		// 等待进行中的提交完成后关闭，丢弃上次提交之后的偏移，等同于进程崩溃
+		inflight.Wait()
+		reader.Close()
+	}()
+
+	idle := b.config.Benchmark.Timeout
+	if idle <= 0 {
+		idle = 30 * time.Second
+	}
+
+	var pending []kafka.Message
+	lastCommit := time.Now()
+	phaseUnique := 0
+	for phaseUnique < quota {
+		fetchCtx, cancel := context.WithTimeout(ctx, idle)
+		fetchStart := time.Now()
+		msg, err := reader.FetchMessage(fetchCtx)
+		cancel()
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+				// 长时间没有新消息，视为主题已消费完毕
+				return nil
+			}
+			return err
+		}
+
+		isNew, counted := tracker.observe(string(msg.Key))
+		if counted {
+			b.collector.Record(&interfaces.OperationResult{
+				Success:  true,
+				IsRead:   true,
+				Duration: time.Since(fetchStart),
+				Metadata: map[string]interface{}{
+					"operation_type": "consume",
+					"partition":      msg.Partition,
+					"offset":         msg.Offset,
+					"duplicate":      !isNew,
+				},
+			})
+			if isNew {
+				phaseUnique++
+			}
+		}
+
+		pending = append(pending, msg)
+		if b.commitDue(len(pending), lastCommit) {
+			b.commit(ctx, reader, pending, &inflight)
+			pending = nil
+			lastCommit = time.Now()
+		}
+	}
+	return nil
+}
+
+// commitDue 按提交策略判断是否需要提交待提交的消息
+func (b *CommitBenchmark) commitDue(pending int, lastCommit time.Time) bool {
+	if b.strategy.Auto {
+		return b.strategy.Interval <= 0 || time.Since(lastCommit) >= b.strategy.Interval
+	}
+	return pending >= b.strategy.Batch
+}
+
+// commit 提交偏移并记录提交延迟
+// 按间隔自动提交与客户端后台提交一样异步执行，逐条自动提交同步执行
+func (b *CommitBenchmark) commit(ctx context.Context, reader groupReader, messages []kafka.Message, inflight *sync.WaitGroup) {
+	doCommit := func() {
+		start := time.Now()
+		err := reader.CommitMessages(ctx, messages...)
+		duration := time.Since(start)
+
+		if err != nil {
+			b.commitErrors.Add(1)
+		} else {
+			b.commits.Add(1)
+			b.commitLatency.Record(duration)
+		}
+		b.collector.Record(&interfaces.OperationResult{
+			Success:  err == nil,
+			IsRead:   false,
+			Duration: duration,
+			Error:    err,
+			Metadata: map[string]interface{}{
+				"operation_type": "commit",
+				"messages":       len(messages),
+			},
+		})
+	}
+
+	wait := b.strategy.Sync
+	if b.strategy.Auto {
+		wait = b.strategy.Interval <= 0
+	}
+	if wait {
+		doCommit()
+		return
+	}
+	inflight.Add(1)
+	go func() {
+		defer inflight.Done()
+		doCommit()
+	}()
+}
+
+// phaseQuotas 将总消息数按重启次数划分为各阶段需要消费的新消息数
+func phaseQuotas(total, restarts int) []int {
+	phases := restarts + 1
+	quotas := make([]int, phases)
+	for i := range quotas {
+		quotas[i] = total / phases
+		if i < total%phases {
+			quotas[i]++
+		}
+	}
+	return quotas
+}
+
+// duplicateTracker 按消息键统计重复消费
+type duplicateTracker struct {
+	prefix     string
+	seen       map[string]struct{}
+	consumed   int64
+	duplicates int64
+}
+
+// newDuplicateTracker 创建重复消费统计器，只统计键带有指定前缀的消息
+func newDuplicateTracker(prefix string) *duplicateTracker {
+	return &duplicateTracker{prefix: prefix, seen: make(map[string]struct{})}
+}
+
+// observe 记录一条消息，返回是否首次消费以及是否属于本次运行
+func (t *duplicateTracker) observe(key string) (isNew bool, counted bool) {
+	if !strings.HasPrefix(key, t.prefix) {
+		return false, false
+	}
+	t.consumed++
+	if _, ok := t.seen[key]; ok {
+		t.duplicates++
+		return false, true
+	}
+	t.seen[key] = struct{}{}
+	return true, true
+}
+
+// unique 已消费的不同消息数
+func (t *duplicateTracker) unique() int64 {
+	return int64(len(t.seen))
+}
@@ -0,0 +1,134 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	kafkaConfig "abc-runner/app/adapters/kafka/config"
+	"abc-runner/app/core/metrics"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func TestPhaseQuotas(t *testing.T) {
+	quotas := phaseQuotas(10, 2)
+	if len(quotas) != 3 || quotas[0] != 4 || quotas[1] != 3 || quotas[2] != 3 {
+		t.Fatalf("unexpected quotas: %v", quotas)
+	}
+	if quotas := phaseQuotas(5, 0); len(quotas) != 1 || quotas[0] != 5 {
+		t.Fatalf("unexpected quotas without restarts: %v", quotas)
+	}
+}
+
+func TestDuplicateTracker(t *testing.T) {
+	tracker := newDuplicateTracker("run-")
+
+	for _, key := range []string{"run-1", "run-2", "other-1", "run-1", "run-3", "run-2"} {
+		tracker.observe(key)
+	}
+
+	if tracker.consumed != 5 {
+		t.Errorf("expected 5 counted messages, got %d", tracker.consumed)
+	}
+	if tracker.unique() != 3 {
+		t.Errorf("expected 3 unique messages, got %d", tracker.unique())
+	}
+	if tracker.duplicates != 2 {
+		t.Errorf("expected 2 duplicates, got %d", tracker.duplicates)
+	}
+}
+
+func TestNewCommitStrategy(t *testing.T) {
+	consumer := kafkaConfig.ConsumerConfig{CommitSync: false, CommitBatch: 0}
+	strategy := NewCommitStrategy(&consumer)
+	if strategy.Auto || strategy.Sync || strategy.Batch != 1 {
+		t.Errorf("unexpected manual strategy: %+v", strategy)
+	}
+	if got := strategy.String(); got != "manual async (every 1 messages)" {
+		t.Errorf("unexpected description: %s", got)
+	}
+
+	consumer = kafkaConfig.ConsumerConfig{EnableAutoCommit: true}
+	if got := NewCommitStrategy(&consumer).String(); got != "auto (per-message)" {
+		t.Errorf("unexpected description: %s", got)
+	}
+}
+
+// fakeGroupReader 单分区的模拟消费者组读取器，从消费者组已提交的偏移开始读取
+type fakeGroupReader struct {
+	messages  []kafka.Message
+	committed *int64
+	next      int64
+}
+
+func (r *fakeGroupReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	if r.next >= int64(len(r.messages)) {
+		<-ctx.Done()
+		return kafka.Message{}, ctx.Err()
+	}
+	msg := r.messages[r.next]
+	r.next++
+	return msg, nil
+}
+
+func (r *fakeGroupReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	for _, msg := range msgs {
+		if offset := msg.Offset + 1; offset > atomic.LoadInt64(r.committed) {
+			atomic.StoreInt64(r.committed, offset)
+		}
+	}
+	return nil
+}
+
+func (r *fakeGroupReader) Close() error { return nil }
+
+// runCommitPhases 以指定提交策略分阶段消费20条消息，返回重复消费的消息数
+func runCommitPhases(t *testing.T, strategy CommitStrategy) int64 {
+	t.Helper()
+
+	var messages []kafka.Message
+	for i := 0; i < 20; i++ {
+		messages = append(messages, kafka.Message{Key: []byte(fmt.Sprintf("run-%d", i)), Offset: int64(i)})
+	}
+
+	collector := metrics.NewBaseCollector(nil, map[string]interface{}{})
+	defer collector.Stop()
+
+	var committed int64
+	benchmark := &CommitBenchmark{
+		config:        &kafkaConfig.KafkaAdapterConfig{Benchmark: kafkaConfig.KafkaBenchmarkConfig{Timeout: 20 * time.Millisecond}},
+		collector:     collector,
+		strategy:      strategy,
+		commitLatency: metrics.NewLatencyTracker(metrics.LatencyConfig{SamplingRate: 1.0}),
+		newReader: func(topic, groupID string) groupReader {
+			return &fakeGroupReader{messages: messages, committed: &committed, next: atomic.LoadInt64(&committed)}
+		},
+	}
+
+	tracker := newDuplicateTracker("run-")
+	for _, quota := range phaseQuotas(len(messages), 3) {
+		if err := benchmark.consumePhase(context.Background(), "topic", "group", tracker, quota); err != nil {
+			t.Fatalf("consume phase failed: %v", err)
+		}
+	}
+	if tracker.unique() != int64(len(messages)) {
+		t.Fatalf("expected all %d messages consumed, got %d", len(messages), tracker.unique())
+	}
+	return tracker.duplicates
+}
+
+func TestCommitBenchmark_AutoIntervalProducesDuplicates(t *testing.T) {
+	// 提交间隔远大于阶段时长，每次重启都丢失整个阶段的偏移
+	if duplicates := runCommitPhases(t, CommitStrategy{Auto: true, Interval: time.Hour}); duplicates == 0 {
+		t.Error("expected auto commit with an interval to redeliver messages after a restart")
+	}
+	if duplicates := runCommitPhases(t, CommitStrategy{Auto: true}); duplicates != 0 {
+		t.Errorf("expected no duplicates with per-message auto commit, got %d", duplicates)
+	}
+	if duplicates := runCommitPhases(t, CommitStrategy{Sync: true, Batch: 1}); duplicates != 0 {
+		t.Errorf("expected no duplicates with sync per-message commit, got %d", duplicates)
+	}
+}
@@ -129,11 +129,12 @@ func (c *ConsumerExecutor) ExecuteConsumeMessage(ctx context.Context, operation
 	}
 
 	return &interfaces.OperationResult{
-		Success:  true,
-		Duration: duration,
-		IsRead:   true,
-		Error:    nil,
-		Value:    message,
+		Success:   true,
+		Duration:  duration,
+		IsRead:    true,
+		Error:     nil,
+		Value:     message,
+		BytesRead: int64(messageSize),
 		Metadata: map[string]interface{}{
 			"topic":      msg.Topic,
 			"partition":  msg.Partition,
@@ -201,25 +202,24 @@ func (c *ConsumerExecutor) ExecuteConsumeBatch(ctx context.Context, operation in
 		defer cancel()
 	}
 
-	// 批量消费消息
+	// 批量拉取消息：读取器按fetch批次预取，这里取满maxMessages或超时为止，
+	// 之后对整批消息只提交一次偏移，而不是像ReadMessage那样逐条提交
+	fetched := make([]kafka.Message, 0, maxMessages)
 	messages := make([]*Message, 0, maxMessages)
 	totalSize := 0
 	successCount := 0
 	var lastErr error
 
 	for i := 0; i < maxMessages; i++ {
-		msg, err := consumer.ReadMessage(timeoutCtx)
+		msg, err := consumer.FetchMessage(timeoutCtx)
 		if err != nil {
+			// 超时说明已取完当前可读的消息，其他错误同样结束本批
 			lastErr = err
-			// 如果是超时错误，退出循环
-			if isTimeoutError(err) {
-				break
-			}
-			// 其他错误也退出循环
 			break
 		}
 
-		message := &Message{
+		fetched = append(fetched, msg)
+		messages = append(messages, &Message{
 			Key:       string(msg.Key),
 			Value:     string(msg.Value),
 			Headers:   convertHeaders(msg.Headers),
@@ -227,28 +227,16 @@ func (c *ConsumerExecutor) ExecuteConsumeBatch(ctx context.Context, operation in
 			Partition: int32(msg.Partition),
 			Offset:    msg.Offset,
 			Topic:     msg.Topic,
-		}
-
-		messages = append(messages, message)
+		})
 		totalSize += len(msg.Key) + len(msg.Value)
 		successCount++
+	}
 
-		// 使用核心接口记录每条消息的指标
-		msgResult := &interfaces.OperationResult{
-			Success:  true,
-			IsRead:   true,
-			Duration: 0, // 单条消息不单独计时
-			Error:    nil,
-			Metadata: map[string]interface{}{
-				"operation_type": "consume",
-				"topic":          topic,
-				"partition":      int32(msg.Partition),
-				"message_size":   int64(len(msg.Key) + len(msg.Value)),
-				"offset":         msg.Offset,
-				"client_id":      "consumer",
-			},
+	if len(fetched) > 0 && consumer.Config().GroupID != "" {
+		if err := consumer.CommitMessages(ctx, fetched...); err != nil {
+			lastErr = fmt.Errorf("failed to commit batch offsets: %w", err)
+			messages = messages[:0]
 		}
-		c.metricsCollector.Record(msgResult)
 	}
 
 	duration := time.Since(startTime)
@@ -287,12 +275,28 @@ func (c *ConsumerExecutor) ExecuteConsumeBatch(ctx context.Context, operation in
 		TotalSize:     totalSize,
 	}
 
-	return &interfaces.OperationResult{
+	// 使用核心接口记录整批消息的指标
+	c.metricsCollector.Record(&interfaces.OperationResult{
 		Success:  true,
-		Duration: duration,
 		IsRead:   true,
-		Error:    nil,
-		Value:    batchResult,
+		Duration: duration,
+		Metadata: map[string]interface{}{
+			"operation_type": "consume",
+			"topic":          topic,
+			"partition":      -1,
+			"message_size":   int64(totalSize),
+			"batch_size":     len(messages),
+			"client_id":      "consumer",
+		},
+	})
+
+	return &interfaces.OperationResult{
+		Success:   true,
+		Duration:  duration,
+		IsRead:    true,
+		Error:     nil,
+		Value:     batchResult,
+		BytesRead: int64(totalSize),
 		Metadata: map[string]interface{}{
 			"topic":           topic,
 			"requested_count": maxMessages,
@@ -0,0 +1,358 @@
+package operations
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	kafkaConfig "abc-runner/app/adapters/kafka/config"
+	"abc-runner/app/adapters/kafka/connection"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// 端到端延迟测试写入的消息头
+const (
+	EndToEndRunHeader    = "abc-e2e-run"     // 运行ID，用于过滤主题中的其它消息
+	EndToEndIDHeader     = "abc-e2e-id"      // 关联ID："<运行ID>-<序号>"
+	EndToEndSentAtHeader = "abc-e2e-sent-at" // 发送时间（Unix纳秒）
+)
+
+// EndToEndPartition 单个分区的端到端延迟
+type EndToEndPartition struct {
+	Partition int                    `json:"partition"`
+	Received  int64                  `json:"received"`
+	Latency   metrics.LatencyMetrics `json:"latency"`
+}
+
+// EndToEndStats 端到端延迟测试结果
+type EndToEndStats struct {
+	Topic      string                 `json:"topic"`
+	Sent       int64                  `json:"sent"`
+	SendErrors int64                  `json:"send_errors"`
+	Received   int64                  `json:"received"`
+	Duplicates int64                  `json:"duplicates"`
+	Lost       int64                  `json:"lost"`
+	LossRate   float64                `json:"loss_rate"`
+	Produce    metrics.LatencyMetrics `json:"produce"`    // 客户端写入到收到确认的延迟
+	EndToEnd   metrics.LatencyMetrics `json:"end_to_end"` // 发送到消费端收到的延迟
+	Partitions []EndToEndPartition    `json:"partitions"` // 按分区号排列
+	Windows    []RelayWindow          `json:"windows"`    // 按发送时间划分的延迟时间序列
+	Duration   time.Duration          `json:"duration"`
+}
+
+// ToMap 转换为报告使用的map
+func (s *EndToEndStats) ToMap() map[string]interface{} {
+	partitions := make([]map[string]interface{}, 0, len(s.Partitions))
+	for _, partition := range s.Partitions {
+		partitions = append(partitions, map[string]interface{}{
+			"partition": partition.Partition,
+			"received":  partition.Received,
+			"p50":       partition.Latency.P50.String(),
+			"p99":       partition.Latency.P99.String(),
+			"max":       partition.Latency.Max.String(),
+		})
+	}
+	windows := make([]map[string]interface{}, 0, len(s.Windows))
+	for _, window := range s.Windows {
+		windows = append(windows, window.ToMap())
+	}
+	return map[string]interface{}{
+		"topic":       s.Topic,
+		"sent":        s.Sent,
+		"send_errors": s.SendErrors,
+		"received":    s.Received,
+		"duplicates":  s.Duplicates,
+		"lost":        s.Lost,
+		"loss_rate":   s.LossRate,
+		"produce_p50": s.Produce.P50.String(),
+		"produce_p99": s.Produce.P99.String(),
+		"produce_max": s.Produce.Max.String(),
+		"e2e_avg":     s.EndToEnd.Average.String(),
+		"e2e_p50":     s.EndToEnd.P50.String(),
+		"e2e_p90":     s.EndToEnd.P90.String(),
+		"e2e_p99":     s.EndToEnd.P99.String(),
+		"e2e_p999":    s.EndToEnd.P999.String(),
+		"e2e_max":     s.EndToEnd.Max.String(),
+		"partitions":  partitions,
+		"windows":     windows,
+		"duration":    s.Duration.String(),
+	}
+}
+
+// EndToEndBenchmark 端到端延迟测试
+// 以固定速率写入消息，在消息头中携带发送时间与关联ID，同时从同一主题各分区读取，
+// 统计从发送到消费端收到的延迟；与客户端写入确认延迟一并报告，收发在同一主机计时，不受时钟偏差影响
+type EndToEndBenchmark struct {
+	pool      *connection.ConnectionPool
+	config    *kafkaConfig.KafkaAdapterConfig
+	collector interfaces.DefaultMetricsCollector
+	produce   *metrics.LatencyTracker
+	endToEnd  *metrics.LatencyTracker
+
+	partitionsMutex sync.Mutex
+	partitions      map[int]*endToEndPartition
+}
+
+// endToEndPartition 单个分区的累计数据
+type endToEndPartition struct {
+	received int64
+	latency  *metrics.LatencyTracker
+}
+
+// NewEndToEndBenchmark 创建端到端延迟测试
+func NewEndToEndBenchmark(pool *connection.ConnectionPool, config *kafkaConfig.KafkaAdapterConfig, collector interfaces.DefaultMetricsCollector) *EndToEndBenchmark {
+	return &EndToEndBenchmark{
+		pool:       pool,
+		config:     config,
+		collector:  collector,
+		produce:    newEndToEndTracker(),
+		endToEnd:   newEndToEndTracker(),
+		partitions: make(map[int]*endToEndPartition),
+	}
+}
+
+func newEndToEndTracker() *metrics.LatencyTracker {
+	return metrics.NewLatencyTracker(metrics.LatencyConfig{
+		SignificantDigits: metrics.DefaultHdrSignificantDigits,
+		SamplingRate:      1.0,
+	})
+}
+
+// Run 执行测试
+func (b *EndToEndBenchmark) Run(ctx context.Context) (*EndToEndStats, error) {
+	topic := b.config.Benchmark.DefaultTopic
+	runID := fmt.Sprintf("e2e-%d", time.Now().UnixNano())
+
+	// 先定位各分区的末尾偏移，只读取测试开始后写入的消息
+	readers, err := b.pool.NewPartitionReaders(ctx, b.config.Brokers, topic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach to topic %s: %w", topic, err)
+	}
+
+	startTime := time.Now()
+	tracker := newRelayTracker(runID, startTime, b.config.EndToEnd.Window, b.config.Benchmark.Total)
+
+	consumeCtx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	for _, reader := range readers {
+		wg.Add(1)
+		go func(reader *kafka.Reader) {
+			defer wg.Done()
+			b.consume(consumeCtx, reader, tracker)
+		}(reader)
+	}
+
+	sendErr := b.produceMessages(ctx, runID, tracker)
+	waitDelivered(ctx, tracker, b.config.EndToEnd.Drain)
+
+	cancel()
+	for _, reader := range readers {
+		reader.Close()
+	}
+	wg.Wait()
+
+	relayStats := tracker.stats()
+	stats := &EndToEndStats{
+		Topic:      topic,
+		Sent:       relayStats.Sent,
+		SendErrors: relayStats.SendErrors,
+		Received:   relayStats.Received,
+		Duplicates: relayStats.Duplicates,
+		Lost:       relayStats.Lost,
+		LossRate:   relayStats.LossRate,
+		Produce:    b.produce.GetMetrics(),
+		EndToEnd:   b.endToEnd.GetMetrics(),
+		Partitions: b.partitionStats(),
+		Windows:    relayStats.Windows,
+		Duration:   time.Since(startTime),
+	}
+	if sendErr != nil {
+		return stats, fmt.Errorf("failed to produce to %s: %w", topic, sendErr)
+	}
+	return stats, nil
+}
+
+// produceMessages 按配置速率写入消息，同一节拍内到期的消息合并为一次写入
+func (b *EndToEndBenchmark) produceMessages(ctx context.Context, runID string, tracker *relayTracker) error {
+	writer := b.pool.NewWriter(relayTick)
+	defer writer.Close()
+
+	total := b.config.Benchmark.Total
+	rate := float64(b.config.EndToEnd.Rate)
+	topic := b.config.Benchmark.DefaultTopic
+	value := bytes.Repeat([]byte("x"), b.config.Benchmark.MessageSize)
+
+	ticker := time.NewTicker(relayTick)
+	defer ticker.Stop()
+
+	start := time.Now()
+	seq := 0
+	for seq < total {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		due := min(int(time.Since(start).Seconds()*rate)+1, total)
+		if due <= seq {
+			continue
+		}
+
+		sentAt := time.Now()
+		messages := make([]kafka.Message, 0, due-seq)
+		for ; seq < due; seq++ {
+			messages = append(messages, encodeEndToEndMessage(topic, runID, seq, sentAt, value))
+		}
+
+		err := writer.WriteMessages(ctx, messages...)
+		duration := time.Since(sentAt)
+		b.collector.Record(&interfaces.OperationResult{
+			Success:  err == nil,
+			IsRead:   false,
+			Duration: duration,
+			Error:    err,
+			Metadata: map[string]interface{}{
+				"operation_type": "e2e_produce",
+				"messages":       len(messages),
+			},
+		})
+		if err != nil {
+			tracker.sendFailed(len(messages))
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			continue
+		}
+		b.produce.Record(duration)
+		tracker.sent(len(messages), sentAt)
+	}
+	return nil
+}
+
+// consume 读取本次运行写入的消息并记录端到端延迟
+func (b *EndToEndBenchmark) consume(ctx context.Context, reader *kafka.Reader, tracker *relayTracker) {
+	for {
+		msg, err := reader.ReadMessage(ctx)
+		if err != nil {
+			return
+		}
+		receivedAt := time.Now()
+
+		seq, sentAt, ok := decodeEndToEndHeaders(tracker.runID, msg.Headers)
+		if !ok {
+			continue
+		}
+		latency := receivedAt.Sub(sentAt)
+		isNew := tracker.received(seq, sentAt, latency)
+		if isNew {
+			b.endToEnd.Record(latency)
+			b.recordPartition(msg.Partition, latency)
+		}
+		b.collector.Record(&interfaces.OperationResult{
+			Success:  true,
+			IsRead:   true,
+			Duration: latency,
+			Metadata: map[string]interface{}{
+				"operation_type": "e2e",
+				"partition":      msg.Partition,
+				"offset":         msg.Offset,
+				"duplicate":      !isNew,
+			},
+		})
+	}
+}
+
+// recordPartition 记录分区的端到端延迟
+func (b *EndToEndBenchmark) recordPartition(partition int, latency time.Duration) {
+	b.partitionsMutex.Lock()
+	defer b.partitionsMutex.Unlock()
+	stats, ok := b.partitions[partition]
+	if !ok {
+		stats = &endToEndPartition{latency: newEndToEndTracker()}
+		b.partitions[partition] = stats
+	}
+	stats.received++
+	stats.latency.Record(latency)
+}
+
+// partitionStats 按分区号汇总延迟
+func (b *EndToEndBenchmark) partitionStats() []EndToEndPartition {
+	b.partitionsMutex.Lock()
+	defer b.partitionsMutex.Unlock()
+
+	partitions := make([]EndToEndPartition, 0, len(b.partitions))
+	for partition, stats := range b.partitions {
+		partitions = append(partitions, EndToEndPartition{
+			Partition: partition,
+			Received:  stats.received,
+			Latency:   stats.latency.GetMetrics(),
+		})
+	}
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i].Partition < partitions[j].Partition })
+	return partitions
+}
+
+// waitDelivered 等待已发送的消息全部被读取，或超过等待时间
+func waitDelivered(ctx context.Context, tracker *relayTracker, timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for !tracker.complete() && time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// encodeEndToEndMessage 构造携带运行ID、关联ID与发送时间消息头的消息，关联ID同时作为消息键
+func encodeEndToEndMessage(topic, runID string, seq int, sentAt time.Time, value []byte) kafka.Message {
+	id := []byte(runID + "-" + strconv.Itoa(seq))
+	return kafka.Message{
+		Topic: topic,
+		Key:   id,
+		Value: value,
+		Headers: []kafka.Header{
+			{Key: EndToEndRunHeader, Value: []byte(runID)},
+			{Key: EndToEndIDHeader, Value: id},
+			{Key: EndToEndSentAtHeader, Value: []byte(strconv.FormatInt(sentAt.UnixNano(), 10))},
+		},
+	}
+}
+
+// decodeEndToEndHeaders 解析消息头，返回序号与发送时间，非本次运行的消息返回false
+func decodeEndToEndHeaders(runID string, headers []kafka.Header) (int, time.Time, bool) {
+	var run, id, sentAt string
+	for _, header := range headers {
+		switch header.Key {
+		case EndToEndRunHeader:
+			run = string(header.Value)
+		case EndToEndIDHeader:
+			id = string(header.Value)
+		case EndToEndSentAtHeader:
+			sentAt = string(header.Value)
+		}
+	}
+	if run != runID || len(id) <= len(runID)+1 || id[:len(runID)+1] != runID+"-" {
+		return 0, time.Time{}, false
+	}
+	seq, err := strconv.Atoi(id[len(runID)+1:])
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	nanos, err := strconv.ParseInt(sentAt, 10, 64)
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	return seq, time.Unix(0, nanos), true
+}
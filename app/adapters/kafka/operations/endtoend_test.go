@@ -0,0 +1,27 @@
+package operations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func TestEndToEndHeadersRoundTrip(t *testing.T) {
+	sentAt := time.Unix(0, 1700000000123456789)
+	msg := encodeEndToEndMessage("bench", "e2e-1", 42, sentAt, []byte("payload"))
+	if string(msg.Key) != "e2e-1-42" || string(msg.Value) != "payload" {
+		t.Fatalf("unexpected key/value: %q %q", msg.Key, msg.Value)
+	}
+
+	seq, decoded, ok := decodeEndToEndHeaders("e2e-1", msg.Headers)
+	if !ok || seq != 42 || !decoded.Equal(sentAt) {
+		t.Fatalf("unexpected decode result: seq=%d sentAt=%v ok=%v", seq, decoded, ok)
+	}
+	if _, _, ok := decodeEndToEndHeaders("e2e-2", msg.Headers); ok {
+		t.Error("expected message from another run to be rejected")
+	}
+	if _, _, ok := decodeEndToEndHeaders("e2e-1", []kafka.Header{{Key: EndToEndRunHeader, Value: []byte("e2e-1")}}); ok {
+		t.Error("expected message without correlation ID to be rejected")
+	}
+}
@@ -3,11 +3,14 @@ package operations
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	kafkaConfig "abc-runner/app/adapters/kafka/config"
 	"abc-runner/app/adapters/kafka/connection"
 	"abc-runner/app/core/interfaces"
+
+	"github.com/segmentio/kafka-go"
 )
 
 // KafkaExecutor Kafka操作执行器 - 遵循统一架构模式
@@ -17,6 +20,7 @@ type KafkaExecutor struct {
 	metricsCollector interfaces.DefaultMetricsCollector
 	producer         *ProducerExecutor
 	consumer         *ConsumerExecutor
+	batches          *BatchTracker
 }
 
 // NewKafkaExecutor 创建Kafka操作执行器
@@ -25,13 +29,36 @@ func NewKafkaExecutor(
 	config *kafkaConfig.KafkaAdapterConfig,
 	metricsCollector interfaces.DefaultMetricsCollector,
 ) *KafkaExecutor {
+	batchSize := 1
+	if config != nil {
+		batchSize = config.Benchmark.GetBatchSize()
+	}
 	return &KafkaExecutor{
 		connPool:         connPool,
 		config:           config,
 		metricsCollector: metricsCollector,
 		producer:         NewProducerExecutor(connPool, metricsCollector),
 		consumer:         NewConsumerExecutor(connPool, metricsCollector),
+		batches:          NewBatchTracker(batchSize),
+	}
+}
+
+// SetSerializer 设置生产消息使用的Schema Registry序列化器
+func (k *KafkaExecutor) SetSerializer(serializer *Serializer) {
+	k.producer.SetSerializer(serializer)
+}
+
+// SerializationStats 获取序列化统计，未配置Schema Registry时返回false
+func (k *KafkaExecutor) SerializationStats() (SerializationStats, bool) {
+	if k.producer.serializer == nil {
+		return SerializationStats{}, false
 	}
+	return k.producer.serializer.Stats(), true
+}
+
+// BatchStats 获取批量生产/消费统计，没有执行过批量操作时返回false
+func (k *KafkaExecutor) BatchStats() (BatchStats, bool) {
+	return k.batches.Stats()
 }
 
 // ExecuteOperation 执行Kafka操作 - 统一操作入口
@@ -108,7 +135,14 @@ func (k *KafkaExecutor) executeProduceBatch(ctx context.Context, operation inter
 			Error:   fmt.Errorf("producer not initialized"),
 		}, fmt.Errorf("producer not initialized")
 	}
-	return k.producer.ExecuteProduceBatch(ctx, operation)
+	start := time.Now()
+	result, err := k.producer.ExecuteProduceBatch(ctx, operation)
+	messages, bytes := 0, int64(0)
+	if batch, ok := result.Value.(*BatchResult); ok {
+		messages, bytes = batch.SuccessCount, int64(batch.TotalSize)
+	}
+	k.batches.Record(false, start, result.Duration, messages, bytes, err)
+	return result, err
 }
 
 // executeConsumeMessage 执行单条消息消费
@@ -130,32 +164,110 @@ func (k *KafkaExecutor) executeConsumeBatch(ctx context.Context, operation inter
 			Error:   fmt.Errorf("consumer not initialized"),
 		}, fmt.Errorf("consumer not initialized")
 	}
-	return k.consumer.ExecuteConsumeBatch(ctx, operation)
+	start := time.Now()
+	result, err := k.consumer.ExecuteConsumeBatch(ctx, operation)
+	messages, bytes := 0, int64(0)
+	if batch, ok := result.Value.(*ConsumeBatchResult); ok {
+		messages, bytes = batch.SuccessCount, int64(batch.TotalSize)
+	}
+	k.batches.Record(true, start, result.Duration, messages, bytes, err)
+	return result, err
 }
 
 // executeCreateTopic 执行创建主题
+// 主题名取自topic参数，未设置时使用操作键；分区数与副本数取自partitions/replication_factor参数，未设置时使用benchmark.topic配置
 func (k *KafkaExecutor) executeCreateTopic(ctx context.Context, operation interfaces.Operation, result *interfaces.OperationResult) error {
-	// TODO: 实现主题创建逻辑
-	// 这需要使用Kafka Admin API
-	result.Value = fmt.Sprintf("Topic creation for operation: %s", operation.Key)
 	result.Metadata["admin_operation"] = "create_topic"
-	return fmt.Errorf("create topic operation not implemented yet")
+	client := k.connPool.GetAdminClient()
+	if client == nil {
+		return fmt.Errorf("admin client not initialized")
+	}
+
+	topic := adminTopic(operation)
+	partitions := k.config.Benchmark.Topic.GetPartitions()
+	if value, ok := operation.Params["partitions"].(int); ok {
+		partitions = value
+	}
+	replicationFactor := k.config.Benchmark.Topic.GetReplicationFactor()
+	if value, ok := operation.Params["replication_factor"].(int); ok {
+		replicationFactor = value
+	}
+	result.Value = topic
+	result.Metadata["topic"] = topic
+	result.Metadata["partitions"] = partitions
+	result.Metadata["replication_factor"] = replicationFactor
+
+	resp, err := client.CreateTopics(ctx, &kafka.CreateTopicsRequest{
+		Topics: []kafka.TopicConfig{{
+			Topic:             topic,
+			NumPartitions:     partitions,
+			ReplicationFactor: replicationFactor,
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("create topic %s: %w", topic, err)
+	}
+	if err := resp.Errors[topic]; err != nil {
+		return fmt.Errorf("create topic %s: %w", topic, err)
+	}
+	return nil
 }
 
-// executeDeleteTopic 执行删除主题
+// executeDeleteTopic 执行删除主题，主题名取自topic参数，未设置时使用操作键
 func (k *KafkaExecutor) executeDeleteTopic(ctx context.Context, operation interfaces.Operation, result *interfaces.OperationResult) error {
-	// TODO: 实现主题删除逻辑
-	result.Value = fmt.Sprintf("Topic deletion for operation: %s", operation.Key)
 	result.Metadata["admin_operation"] = "delete_topic"
-	return fmt.Errorf("delete topic operation not implemented yet")
+	client := k.connPool.GetAdminClient()
+	if client == nil {
+		return fmt.Errorf("admin client not initialized")
+	}
+
+	topic := adminTopic(operation)
+	result.Value = topic
+	result.Metadata["topic"] = topic
+
+	resp, err := client.DeleteTopics(ctx, &kafka.DeleteTopicsRequest{Topics: []string{topic}})
+	if err != nil {
+		return fmt.Errorf("delete topic %s: %w", topic, err)
+	}
+	if err := resp.Errors[topic]; err != nil {
+		return fmt.Errorf("delete topic %s: %w", topic, err)
+	}
+	return nil
 }
 
-// executeListTopics 执行列出主题
+// executeListTopics 执行列出主题，结果为按名称排序的主题名；include_internal参数为true时包含内部主题
 func (k *KafkaExecutor) executeListTopics(ctx context.Context, operation interfaces.Operation, result *interfaces.OperationResult) error {
-	// TODO: 实现主题列表查询逻辑
-	result.Value = []string{} // 空列表
 	result.Metadata["admin_operation"] = "list_topics"
-	return fmt.Errorf("list topics operation not implemented yet")
+	client := k.connPool.GetAdminClient()
+	if client == nil {
+		return fmt.Errorf("admin client not initialized")
+	}
+
+	resp, err := client.Metadata(ctx, &kafka.MetadataRequest{})
+	if err != nil {
+		return fmt.Errorf("list topics: %w", err)
+	}
+	includeInternal, _ := operation.Params["include_internal"].(bool)
+	topics := make([]string, 0, len(resp.Topics))
+	for _, topic := range resp.Topics {
+		if topic.Internal && !includeInternal {
+			continue
+		}
+		topics = append(topics, topic.Name)
+	}
+	sort.Strings(topics)
+
+	result.Value = topics
+	result.Metadata["topic_count"] = len(topics)
+	return nil
+}
+
+// adminTopic 获取主题管理操作的目标主题
+func adminTopic(operation interfaces.Operation) string {
+	if topic, ok := operation.Params["topic"].(string); ok && topic != "" {
+		return topic
+	}
+	return operation.Key
 }
 
 // executeDescribeConsumerGroups 执行描述消费者组
@@ -6,16 +6,25 @@ import (
 	"abc-runner/app/adapters/kafka/config"
 	"abc-runner/app/core/execution"
 	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/utils"
 )
 
 // OperationFactory Kafka操作工厂
 type OperationFactory struct {
-	config *config.KafkaAdapterConfig
+	config  *config.KafkaAdapterConfig
+	payload *utils.PayloadTemplate // 消息内容模板，未配置时按data_size生成
 }
 
 // NewOperationFactory 创建Kafka操作工厂
 func NewOperationFactory(config *config.KafkaAdapterConfig) execution.OperationFactory {
-	return &OperationFactory{config: config}
+	factory := &OperationFactory{config: config}
+	// 模板在解析参数时已验证
+	if config.Benchmark.Payload.Enabled() {
+		if payload, err := config.Benchmark.Payload.Compile(); err == nil {
+			factory.payload = payload
+		}
+	}
+	return factory
 }
 
 func (k *OperationFactory) CreateOperation(jobID int, benchmarkConfig execution.BenchmarkConfig) interfaces.Operation {
@@ -41,8 +50,10 @@ func (k *OperationFactory) CreateOperation(jobID int, benchmarkConfig execution.
 	key := fmt.Sprintf("key_%d", jobID)
 	value := fmt.Sprintf("message_%d", jobID)
 
-	// 如果有指定数据大小，生成相应大小的值
-	if benchmark.DataSize > 0 {
+	// 配置了模板时按任务编号渲染，否则如果有指定数据大小，生成相应大小的值
+	if k.payload != nil {
+		value = k.payload.Render(jobID)
+	} else if benchmark.DataSize > 0 {
 		value = generateRandomValue(benchmark.DataSize)
 	}
 
@@ -0,0 +1,187 @@
+package operations
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
+
+// jsonSchemaMaxDepth 生成嵌套对象与数组时的最大深度
+const jsonSchemaMaxDepth = 6
+
+// jsonSchemaCodec JSON Schema编码：消息内容即JSON文本，不做schema校验
+type jsonSchemaCodec struct {
+	root map[string]interface{}
+}
+
+// newJSONSchemaCodec 解析JSON Schema
+func newJSONSchemaCodec(text string) (*jsonSchemaCodec, error) {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	var root map[string]interface{}
+	if err := decoder.Decode(&root); err != nil {
+		return nil, err
+	}
+	return &jsonSchemaCodec{root: root}, nil
+}
+
+// encode 编码为JSON文本
+func (c *jsonSchemaCodec) encode(value interface{}) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+// generate 按schema生成合成数据
+func (c *jsonSchemaCodec) generate(rng *rand.Rand) interface{} {
+	return c.generateNode(c.root, rng, 0)
+}
+
+// resolve 解析本文档内的$ref（#/definitions/...、#/$defs/...）
+func (c *jsonSchemaCodec) resolve(node map[string]interface{}) map[string]interface{} {
+	for i := 0; i < jsonSchemaMaxDepth; i++ {
+		ref, ok := node["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#") {
+			return node
+		}
+		var target interface{} = c.root
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#"), "/") {
+			if part == "" {
+				continue
+			}
+			object, _ := target.(map[string]interface{})
+			target = object[strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")]
+		}
+		resolved, ok := target.(map[string]interface{})
+		if !ok {
+			return map[string]interface{}{}
+		}
+		node = resolved
+	}
+	return node
+}
+
+// generateNode 生成一个值：enum与const优先，组合关键字取第一个子schema（allOf合并各子schema的对象）
+func (c *jsonSchemaCodec) generateNode(node map[string]interface{}, rng *rand.Rand, depth int) interface{} {
+	node = c.resolve(node)
+	if value, ok := node["const"]; ok {
+		return value
+	}
+	if values, ok := node["enum"].([]interface{}); ok && len(values) > 0 {
+		return values[rng.IntN(len(values))]
+	}
+	for _, keyword := range []string{"oneOf", "anyOf"} {
+		if options, ok := node[keyword].([]interface{}); ok && len(options) > 0 {
+			option, _ := options[rng.IntN(len(options))].(map[string]interface{})
+			return c.generateNode(option, rng, depth)
+		}
+	}
+	if parts, ok := node["allOf"].([]interface{}); ok {
+		merged := map[string]interface{}{}
+		for _, part := range parts {
+			partNode, _ := part.(map[string]interface{})
+			if object, ok := c.generateNode(partNode, rng, depth).(map[string]interface{}); ok {
+				for key, value := range object {
+					merged[key] = value
+				}
+			}
+		}
+		return merged
+	}
+
+	switch jsonSchemaType(node) {
+	case "object":
+		object := map[string]interface{}{}
+		if depth >= jsonSchemaMaxDepth {
+			return object
+		}
+		properties, _ := node["properties"].(map[string]interface{})
+		for name, property := range properties {
+			propertyNode, _ := property.(map[string]interface{})
+			object[name] = c.generateNode(propertyNode, rng, depth+1)
+		}
+		return object
+	case "array":
+		items := []interface{}{}
+		itemNode, _ := node["items"].(map[string]interface{})
+		if depth < jsonSchemaMaxDepth {
+			for i := 1 + rng.IntN(3); i > 0; i-- {
+				items = append(items, c.generateNode(itemNode, rng, depth+1))
+			}
+		}
+		return items
+	case "integer":
+		low, high := jsonSchemaRange(node, 0, 1000000)
+		return int64(low) + rng.Int64N(int64(high-low)+1)
+	case "number":
+		low, high := jsonSchemaRange(node, 0, 1000)
+		return math.Round((low+rng.Float64()*(high-low))*100) / 100
+	case "boolean":
+		return rng.IntN(2) == 1
+	case "null":
+		return nil
+	}
+	return generateJSONString(node, rng)
+}
+
+// jsonSchemaType 节点的类型，类型列表取第一个非null类型，未声明时按关键字推断
+func jsonSchemaType(node map[string]interface{}) string {
+	switch t := node["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, candidate := range t {
+			if name, ok := candidate.(string); ok && name != "null" {
+				return name
+			}
+		}
+		return "null"
+	}
+	if _, ok := node["properties"]; ok {
+		return "object"
+	}
+	if _, ok := node["items"]; ok {
+		return "array"
+	}
+	return "string"
+}
+
+// jsonSchemaRange 数值的取值范围
+func jsonSchemaRange(node map[string]interface{}, low, high float64) (float64, float64) {
+	if minimum, err := toFloat64(node["minimum"]); err == nil {
+		low = minimum
+		if high < low {
+			high = low + 1000
+		}
+	}
+	if maximum, err := toFloat64(node["maximum"]); err == nil && maximum >= low {
+		high = maximum
+	}
+	return low, high
+}
+
+// generateJSONString 按format与长度限制生成字符串
+func generateJSONString(node map[string]interface{}, rng *rand.Rand) string {
+	switch node["format"] {
+	case "date-time":
+		return time.Unix(1600000000+rng.Int64N(200000000), 0).UTC().Format(time.RFC3339)
+	case "date":
+		return time.Unix(1600000000+rng.Int64N(200000000), 0).UTC().Format(time.DateOnly)
+	case "uuid":
+		return fmt.Sprintf("%08x-%04x-4%03x-%04x-%012x", rng.Uint32(), rng.IntN(1<<16), rng.IntN(1<<12),
+			0x8000|rng.IntN(1<<14), rng.Int64N(1<<48))
+	case "email":
+		return strings.ToLower(randomString(rng, 6, 10)) + "@example.com"
+	}
+
+	minLength, maxLength := 8, 16
+	if n, err := toInt64(node["minLength"]); err == nil && n >= 0 {
+		minLength = int(n)
+		maxLength = max(maxLength, minLength)
+	}
+	if n, err := toInt64(node["maxLength"]); err == nil && int(n) >= minLength {
+		maxLength = int(n)
+	}
+	return randomString(rng, minLength, maxLength)
+}
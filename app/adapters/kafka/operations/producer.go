@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"abc-runner/app/adapters/kafka/connection"
+	"abc-runner/app/core/execution"
 	"abc-runner/app/core/interfaces"
 
 	"github.com/segmentio/kafka-go"
@@ -15,6 +16,7 @@ import (
 type ProducerExecutor struct {
 	pool             *connection.ConnectionPool
 	metricsCollector interfaces.DefaultMetricsCollector
+	serializer       *Serializer
 }
 
 // NewProducerOperations 创建生产者操作实例
@@ -25,8 +27,31 @@ func NewProducerExecutor(pool *connection.ConnectionPool, metricsCollector inter
 	}
 }
 
+// SetSerializer 设置Schema Registry序列化器，nil表示按原样发送消息内容
+func (p *ProducerExecutor) SetSerializer(serializer *Serializer) {
+	p.serializer = serializer
+}
+
+// encodeValue 配置了序列化器时按schema序列化消息内容
+func (p *ProducerExecutor) encodeValue(value string) ([]byte, error) {
+	if p.serializer == nil {
+		return []byte(value), nil
+	}
+	return p.serializer.Serialize(value)
+}
+
 // ExecuteProduceMessage 执行单条消息生产
 func (p *ProducerExecutor) ExecuteProduceMessage(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	// 序列化在计时开始前完成，其耗时由序列化器单独统计
+	value, err := p.encodeValue(fmt.Sprintf("%v", operation.Value))
+	if err != nil {
+		return &interfaces.OperationResult{
+			Success: false,
+			IsRead:  false,
+			Error:   err,
+		}, err
+	}
+
 	startTime := time.Now()
 
 	// 解析参数
@@ -72,7 +97,7 @@ func (p *ProducerExecutor) ExecuteProduceMessage(ctx context.Context, operation
 	kafkaMessage := kafka.Message{
 		Topic: topic,
 		Key:   []byte(operation.Key),
-		Value: []byte(fmt.Sprintf("%v", operation.Value)),
+		Value: value,
 	}
 
 	// 添加Headers
@@ -86,6 +111,8 @@ func (p *ProducerExecutor) ExecuteProduceMessage(ctx context.Context, operation
 		}
 	}
 
+	appendRequestID(ctx, &kafkaMessage)
+
 	// 设置分区（如果指定）
 	if partition, ok := operation.Params["partition"].(int32); ok {
 		kafkaMessage.Partition = int(partition)
@@ -133,11 +160,12 @@ func (p *ProducerExecutor) ExecuteProduceMessage(ctx context.Context, operation
 	}
 
 	return &interfaces.OperationResult{
-		Success:  true,
-		Duration: duration,
-		IsRead:   false,
-		Error:    nil,
-		Value:    result,
+		Success:      true,
+		Duration:     duration,
+		IsRead:       false,
+		Error:        nil,
+		Value:        result,
+		BytesWritten: int64(messageSize),
 		Metadata: map[string]interface{}{
 			"topic":      topic,
 			"partition":  kafkaMessage.Partition,
@@ -149,6 +177,29 @@ func (p *ProducerExecutor) ExecuteProduceMessage(ctx context.Context, operation
 
 // ExecuteProduceBatch 执行批量消息生产
 func (p *ProducerExecutor) ExecuteProduceBatch(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	messages, ok := operation.Params["messages"].([]*Message)
+	if !ok || len(messages) == 0 {
+		return &interfaces.OperationResult{
+			Success: false,
+			IsRead:  false,
+			Error:   fmt.Errorf("messages parameter is required"),
+		}, fmt.Errorf("messages parameter is required")
+	}
+
+	// 序列化在计时开始前完成，其耗时由序列化器单独统计
+	values := make([][]byte, len(messages))
+	for i, msg := range messages {
+		value, err := p.encodeValue(msg.Value)
+		if err != nil {
+			return &interfaces.OperationResult{
+				Success: false,
+				IsRead:  false,
+				Error:   err,
+			}, err
+		}
+		values[i] = value
+	}
+
 	startTime := time.Now()
 
 	// 解析参数
@@ -162,16 +213,6 @@ func (p *ProducerExecutor) ExecuteProduceBatch(ctx context.Context, operation in
 		}, fmt.Errorf("topic parameter is required")
 	}
 
-	messages, ok := operation.Params["messages"].([]*Message)
-	if !ok || len(messages) == 0 {
-		return &interfaces.OperationResult{
-			Success:  false,
-			Duration: time.Since(startTime),
-			IsRead:   false,
-			Error:    fmt.Errorf("messages parameter is required"),
-		}, fmt.Errorf("messages parameter is required")
-	}
-
 	// 获取生产者
 	producer, err := p.pool.GetProducer()
 	if err != nil {
@@ -204,11 +245,11 @@ func (p *ProducerExecutor) ExecuteProduceBatch(ctx context.Context, operation in
 	kafkaMessages := make([]kafka.Message, 0, len(messages))
 	totalSize := 0
 
-	for _, msg := range messages {
+	for i, msg := range messages {
 		kafkaMessage := kafka.Message{
 			Topic:     topic,
 			Key:       []byte(msg.Key),
-			Value:     []byte(msg.Value),
+			Value:     values[i],
 			Partition: int(msg.Partition),
 		}
 
@@ -223,6 +264,8 @@ func (p *ProducerExecutor) ExecuteProduceBatch(ctx context.Context, operation in
 			}
 		}
 
+		appendRequestID(ctx, &kafkaMessage)
+
 		kafkaMessages = append(kafkaMessages, kafkaMessage)
 		totalSize += len(kafkaMessage.Key) + len(kafkaMessage.Value)
 	}
@@ -276,14 +319,16 @@ func (p *ProducerExecutor) ExecuteProduceBatch(ctx context.Context, operation in
 		SuccessCount:  len(results),
 		FailureCount:  0,
 		TotalDuration: duration,
+		TotalSize:     totalSize,
 	}
 
 	return &interfaces.OperationResult{
-		Success:  true,
-		Duration: duration,
-		IsRead:   false,
-		Error:    nil,
-		Value:    batchResult,
+		Success:      true,
+		Duration:     duration,
+		IsRead:       false,
+		Error:        nil,
+		Value:        batchResult,
+		BytesWritten: int64(totalSize),
 		Metadata: map[string]interface{}{
 			"topic":        topic,
 			"batch_size":   batchSize,
@@ -318,4 +363,27 @@ type BatchResult struct {
 	SuccessCount  int             `json:"success_count"`
 	FailureCount  int             `json:"failure_count"`
 	TotalDuration time.Duration   `json:"total_duration"`
+	TotalSize     int             `json:"total_size"`
+}
+
+// appendRequestID 将context中的请求ID、traceparent与拦截器附加的头部写入消息头，批量发送时同一批消息共用一个ID
+func appendRequestID(ctx context.Context, message *kafka.Message) {
+	if requestID, ok := execution.RequestIDFromContext(ctx); ok {
+		message.Headers = append(message.Headers, kafka.Header{
+			Key:   execution.RequestIDMetadata,
+			Value: []byte(requestID),
+		})
+	}
+	if trace, ok := execution.TraceContextFromContext(ctx); ok {
+		message.Headers = append(message.Headers, kafka.Header{
+			Key:   execution.TraceparentHeader,
+			Value: []byte(trace.Traceparent()),
+		})
+	}
+	for _, header := range execution.HeadersFromContext(ctx) {
+		message.Headers = append(message.Headers, kafka.Header{
+			Key:   header[0],
+			Value: []byte(header[1]),
+		})
+	}
 }
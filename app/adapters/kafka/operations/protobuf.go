@@ -0,0 +1,648 @@
+package operations
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// protoMaxDepth 生成嵌套消息数据时的最大深度
+const protoMaxDepth = 4
+
+// protoMessage .proto中的消息定义
+type protoMessage struct {
+	name   string // 含包名与外层消息的全名
+	fields []*protoField
+}
+
+// protoField 消息字段，map字段的key与value为map条目消息的1、2号字段
+type protoField struct {
+	name     string
+	number   protowire.Number
+	kind     string // 标量类型名，或message、enum
+	typeName string // 引用的消息或枚举名，解析后置为message/enum
+	repeated bool
+	message  *protoMessage
+	enum     *protoEnum
+	mapKey   *protoField
+	mapValue *protoField
+	scope    string // 字段所在消息的全名，用于解析相对类型名
+	oneof    string // 所属oneof名称
+}
+
+// protoEnum .proto中的枚举定义
+type protoEnum struct {
+	name   string
+	values []protoEnumValue
+}
+
+type protoEnumValue struct {
+	name   string
+	number int32
+}
+
+var protoScalars = map[string]bool{
+	"double": true, "float": true, "int32": true, "int64": true, "uint32": true, "uint64": true,
+	"sint32": true, "sint64": true, "fixed32": true, "fixed64": true, "sfixed32": true, "sfixed64": true,
+	"bool": true, "string": true, "bytes": true,
+}
+
+// protobufCodec Protobuf二进制编码，消息类型为文件中的第一个顶层消息
+type protobufCodec struct {
+	root *protoMessage
+}
+
+// newProtobufCodec 解析proto3文件（不解析import的文件，引用的外部类型会报错）
+func newProtobufCodec(text string) (*protobufCodec, error) {
+	parser := &protoParser{
+		tokens:   tokenizeProto(text),
+		messages: make(map[string]*protoMessage),
+		enums:    make(map[string]*protoEnum),
+	}
+	if err := parser.parseFile(); err != nil {
+		return nil, err
+	}
+	if parser.first == nil {
+		return nil, fmt.Errorf("no message definition found")
+	}
+	for _, field := range parser.fields {
+		if err := parser.resolve(field); err != nil {
+			return nil, err
+		}
+	}
+	return &protobufCodec{root: parser.first}, nil
+}
+
+// tokenizeProto 将.proto文本切分为标识符、字符串与符号，忽略注释
+func tokenizeProto(text string) []string {
+	var tokens []string
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(text[i:], "//"):
+			for i < len(text) && text[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(text[i:], "/*"):
+			end := strings.Index(text[i+2:], "*/")
+			if end < 0 {
+				return tokens
+			}
+			i += end + 4
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(text) && text[j] != c {
+				if text[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			tokens = append(tokens, text[i:min(j+1, len(text))])
+			i = j + 1
+		case c == '_' || c == '.' || c == '-' || c == '+' || (c >= '0' && c <= '9') || (c|0x20 >= 'a' && c|0x20 <= 'z'):
+			j := i + 1
+			for j < len(text) && (text[j] == '_' || text[j] == '.' || (text[j] >= '0' && text[j] <= '9') || (text[j]|0x20 >= 'a' && text[j]|0x20 <= 'z')) {
+				j++
+			}
+			tokens = append(tokens, text[i:j])
+			i = j
+		default:
+			tokens = append(tokens, string(c))
+			i++
+		}
+	}
+	return tokens
+}
+
+// protoParser .proto文件的递归下降解析
+type protoParser struct {
+	tokens   []string
+	pos      int
+	pkg      string
+	first    *protoMessage
+	messages map[string]*protoMessage
+	enums    map[string]*protoEnum
+	fields   []*protoField
+}
+
+func (p *protoParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *protoParser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+func (p *protoParser) expect(token string) error {
+	if got := p.next(); got != token {
+		return fmt.Errorf("expected %q, got %q", token, got)
+	}
+	return nil
+}
+
+// skipStatement 跳过到分号为止的语句
+func (p *protoParser) skipStatement() {
+	for p.pos < len(p.tokens) && p.next() != ";" {
+	}
+}
+
+// skipBlock 跳过花括号包围的块（如service、extend）
+func (p *protoParser) skipBlock() {
+	for p.pos < len(p.tokens) && p.peek() != "{" {
+		p.pos++
+	}
+	depth := 0
+	for p.pos < len(p.tokens) {
+		switch p.next() {
+		case "{":
+			depth++
+		case "}":
+			if depth--; depth == 0 {
+				return
+			}
+		}
+	}
+}
+
+// parseFile 解析文件的顶层定义
+func (p *protoParser) parseFile() error {
+	for p.pos < len(p.tokens) {
+		switch token := p.next(); token {
+		case "syntax", "edition":
+			p.skipStatement()
+		case "package":
+			p.pkg = p.next()
+			p.skipStatement()
+		case "import", "option":
+			p.skipStatement()
+		case "message":
+			message, err := p.parseMessage(p.pkg)
+			if err != nil {
+				return err
+			}
+			if p.first == nil {
+				p.first = message
+			}
+		case "enum":
+			if err := p.parseEnum(p.pkg); err != nil {
+				return err
+			}
+		case "service", "extend":
+			p.skipBlock()
+		case ";":
+		default:
+			return fmt.Errorf("unexpected %q at top level", token)
+		}
+	}
+	return nil
+}
+
+// qualify 拼接作用域与名称
+func qualify(scope, name string) string {
+	if scope == "" {
+		return name
+	}
+	return scope + "." + name
+}
+
+// parseMessage 解析message定义，scope为外层作用域
+func (p *protoParser) parseMessage(scope string) (*protoMessage, error) {
+	message := &protoMessage{name: qualify(scope, p.next())}
+	p.messages[message.name] = message
+	if err := p.expect("{"); err != nil {
+		return nil, fmt.Errorf("message %s: %w", message.name, err)
+	}
+	if err := p.parseMessageBody(message, ""); err != nil {
+		return nil, fmt.Errorf("message %s: %w", message.name, err)
+	}
+	return message, nil
+}
+
+// parseMessageBody 解析message或oneof的内容直到右花括号，oneof为所在oneof的名称
+func (p *protoParser) parseMessageBody(message *protoMessage, oneof string) error {
+	for {
+		switch token := p.peek(); token {
+		case "":
+			return fmt.Errorf("unexpected end of file")
+		case "}":
+			p.pos++
+			return nil
+		case ";":
+			p.pos++
+		case "option", "reserved", "extensions":
+			p.skipStatement()
+		case "extend":
+			p.skipBlock()
+		case "message":
+			p.pos++
+			if _, err := p.parseMessage(message.name); err != nil {
+				return err
+			}
+		case "enum":
+			p.pos++
+			if err := p.parseEnum(message.name); err != nil {
+				return err
+			}
+		case "oneof":
+			p.pos++
+			name := p.next()
+			if err := p.expect("{"); err != nil {
+				return err
+			}
+			if err := p.parseMessageBody(message, name); err != nil {
+				return err
+			}
+		default:
+			field, err := p.parseField(message.name, oneof)
+			if err != nil {
+				return err
+			}
+			message.fields = append(message.fields, field)
+		}
+	}
+}
+
+// parseField 解析字段定义：[repeated|optional|required] 类型 名称 = 编号 [选项];
+// 或 map<K, V> 名称 = 编号;
+func (p *protoParser) parseField(scope, oneof string) (*protoField, error) {
+	field := &protoField{scope: scope, oneof: oneof}
+	switch p.peek() {
+	case "repeated":
+		field.repeated = true
+		p.pos++
+	case "optional", "required":
+		p.pos++
+	}
+
+	typeName := p.next()
+	if typeName == "map" && p.peek() == "<" {
+		p.pos++
+		field.mapKey = &protoField{name: "key", number: 1, typeName: p.next(), scope: scope}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+		field.mapValue = &protoField{name: "value", number: 2, typeName: p.next(), scope: scope}
+		if err := p.expect(">"); err != nil {
+			return nil, err
+		}
+		field.kind = "map"
+		field.repeated = true
+		p.fields = append(p.fields, field.mapKey, field.mapValue)
+	} else {
+		field.typeName = typeName
+		if oneof != "" && field.repeated {
+			return nil, fmt.Errorf("repeated field in oneof")
+		}
+		p.fields = append(p.fields, field)
+	}
+
+	field.name = p.next()
+	if err := p.expect("="); err != nil {
+		return nil, fmt.Errorf("field %s: %w", field.name, err)
+	}
+	number, err := strconv.ParseInt(p.next(), 0, 32)
+	if err != nil || number <= 0 {
+		return nil, fmt.Errorf("field %s: invalid field number", field.name)
+	}
+	field.number = protowire.Number(number)
+	// 字段选项（如 [packed = false]）不影响编码
+	p.skipStatement()
+	return field, nil
+}
+
+// parseEnum 解析enum定义
+func (p *protoParser) parseEnum(scope string) error {
+	enum := &protoEnum{name: qualify(scope, p.next())}
+	p.enums[enum.name] = enum
+	if err := p.expect("{"); err != nil {
+		return fmt.Errorf("enum %s: %w", enum.name, err)
+	}
+	for {
+		switch token := p.next(); token {
+		case "":
+			return fmt.Errorf("enum %s: unexpected end of file", enum.name)
+		case "}":
+			if len(enum.values) == 0 {
+				return fmt.Errorf("enum %s without values", enum.name)
+			}
+			return nil
+		case ";":
+		case "option", "reserved":
+			p.skipStatement()
+		default:
+			if err := p.expect("="); err != nil {
+				return fmt.Errorf("enum %s: %w", enum.name, err)
+			}
+			number, err := strconv.ParseInt(p.next(), 0, 32)
+			if err != nil {
+				return fmt.Errorf("enum %s: invalid value for %s", enum.name, token)
+			}
+			enum.values = append(enum.values, protoEnumValue{name: token, number: int32(number)})
+			p.skipStatement()
+		}
+	}
+}
+
+// resolve 按protobuf作用域规则（由内向外）解析字段引用的类型
+func (p *protoParser) resolve(field *protoField) error {
+	if protoScalars[field.typeName] {
+		field.kind = field.typeName
+		return nil
+	}
+	if strings.HasPrefix(field.typeName, ".") {
+		return p.lookup(field, strings.TrimPrefix(field.typeName, "."))
+	}
+	for scope := field.scope; ; {
+		if p.lookup(field, qualify(scope, field.typeName)) == nil {
+			return nil
+		}
+		if scope == "" {
+			break
+		}
+		if i := strings.LastIndex(scope, "."); i >= 0 {
+			scope = scope[:i]
+		} else {
+			scope = ""
+		}
+	}
+	return fmt.Errorf("%s.%s: unknown type %s (imported types are not supported)", field.scope, field.name, field.typeName)
+}
+
+// lookup 按全名查找消息或枚举
+func (p *protoParser) lookup(field *protoField, name string) error {
+	if message, ok := p.messages[name]; ok {
+		field.kind, field.message = "message", message
+		return nil
+	}
+	if enum, ok := p.enums[name]; ok {
+		field.kind, field.enum = "enum", enum
+		return nil
+	}
+	return fmt.Errorf("unknown type %s", name)
+}
+
+// encode 按Protobuf二进制格式编码
+func (c *protobufCodec) encode(value interface{}) ([]byte, error) {
+	return appendProtoMessage(nil, c.root, value, "$")
+}
+
+// appendProtoMessage 按字段声明顺序编码消息，缺失或为null的字段不编码
+func appendProtoMessage(buf []byte, message *protoMessage, value interface{}, path string) ([]byte, error) {
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: expected an object for %s, got %s", path, message.name, describeValue(value))
+	}
+
+	var err error
+	for _, field := range message.fields {
+		fieldValue, present := fields[field.name]
+		if !present || fieldValue == nil {
+			continue
+		}
+		fieldPath := joinPath(path, field.name)
+		switch {
+		case field.kind == "map":
+			buf, err = appendProtoMap(buf, field, fieldValue, fieldPath)
+		case field.repeated:
+			buf, err = appendProtoRepeated(buf, field, fieldValue, fieldPath)
+		default:
+			buf, err = appendProtoField(buf, field, fieldValue, fieldPath)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
+
+// appendProtoRepeated 编码repeated字段，数值类型按proto3默认使用packed编码
+func appendProtoRepeated(buf []byte, field *protoField, value interface{}, path string) ([]byte, error) {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: expected an array, got %s", path, describeValue(value))
+	}
+	if len(items) == 0 {
+		return buf, nil
+	}
+
+	var err error
+	if field.kind != "string" && field.kind != "bytes" && field.kind != "message" {
+		var packed []byte
+		for i, item := range items {
+			if packed, err = appendProtoValue(packed, field, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return nil, err
+			}
+		}
+		buf = protowire.AppendTag(buf, field.number, protowire.BytesType)
+		return protowire.AppendBytes(buf, packed), nil
+	}
+	for i, item := range items {
+		if buf, err = appendProtoField(buf, field, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
+
+// appendProtoMap 编码map字段，每个条目为含key与value的嵌套消息
+func appendProtoMap(buf []byte, field *protoField, value interface{}, path string) ([]byte, error) {
+	entries, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: expected an object, got %s", path, describeValue(value))
+	}
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		entryPath := joinPath(path, key)
+		// JSON对象的键总是字符串，bool与整数类型的键按文本解析
+		var keyValue interface{} = key
+		if field.mapKey.kind == "bool" {
+			keyValue = key == "true"
+		}
+		entry, err := appendProtoField(nil, field.mapKey, keyValue, entryPath)
+		if err != nil {
+			return nil, err
+		}
+		if entry, err = appendProtoField(entry, field.mapValue, entries[key], entryPath); err != nil {
+			return nil, err
+		}
+		buf = protowire.AppendTag(buf, field.number, protowire.BytesType)
+		buf = protowire.AppendBytes(buf, entry)
+	}
+	return buf, nil
+}
+
+// appendProtoField 编码带tag的单个字段值
+func appendProtoField(buf []byte, field *protoField, value interface{}, path string) ([]byte, error) {
+	buf = protowire.AppendTag(buf, field.number, protoWireType(field.kind))
+	return appendProtoValue(buf, field, value, path)
+}
+
+// protoWireType 字段类型对应的线类型
+func protoWireType(kind string) protowire.Type {
+	switch kind {
+	case "double", "fixed64", "sfixed64":
+		return protowire.Fixed64Type
+	case "float", "fixed32", "sfixed32":
+		return protowire.Fixed32Type
+	case "string", "bytes", "message":
+		return protowire.BytesType
+	}
+	return protowire.VarintType
+}
+
+// appendProtoValue 编码不带tag的字段值
+func appendProtoValue(buf []byte, field *protoField, value interface{}, path string) ([]byte, error) {
+	switch field.kind {
+	case "double", "float":
+		f, err := toFloat64(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if field.kind == "float" {
+			return protowire.AppendFixed32(buf, math.Float32bits(float32(f))), nil
+		}
+		return protowire.AppendFixed64(buf, math.Float64bits(f)), nil
+	case "bool":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s: expected a boolean, got %s", path, describeValue(value))
+		}
+		return protowire.AppendVarint(buf, protowire.EncodeBool(b)), nil
+	case "string", "bytes":
+		data, err := toBytes(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return protowire.AppendBytes(buf, data), nil
+	case "message":
+		nested, err := appendProtoMessage(nil, field.message, value, path)
+		if err != nil {
+			return nil, err
+		}
+		return protowire.AppendBytes(buf, nested), nil
+	case "enum":
+		number, err := protoEnumNumber(field.enum, value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return protowire.AppendVarint(buf, uint64(int64(number))), nil
+	}
+
+	// 整数类型，64位JSON数值（如int64、uint64）按proto3 JSON映射也可写作字符串
+	n, err := toInt64(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	switch field.kind {
+	case "sint32", "sint64":
+		return protowire.AppendVarint(buf, protowire.EncodeZigZag(n)), nil
+	case "fixed32", "sfixed32":
+		return protowire.AppendFixed32(buf, uint32(n)), nil
+	case "fixed64", "sfixed64":
+		return protowire.AppendFixed64(buf, uint64(n)), nil
+	case "int32":
+		// 负数按64位符号扩展编码
+		return protowire.AppendVarint(buf, uint64(int64(int32(n)))), nil
+	case "uint32":
+		return protowire.AppendVarint(buf, uint64(uint32(n))), nil
+	}
+	return protowire.AppendVarint(buf, uint64(n)), nil
+}
+
+// protoEnumNumber 枚举值可写作名称或数值
+func protoEnumNumber(enum *protoEnum, value interface{}) (int32, error) {
+	if name, ok := value.(string); ok {
+		for _, v := range enum.values {
+			if v.name == name {
+				return v.number, nil
+			}
+		}
+		return 0, fmt.Errorf("%s is not a value of %s", name, enum.name)
+	}
+	n, err := toInt64(value)
+	return int32(n), err
+}
+
+// generate 按消息定义生成合成数据
+func (c *protobufCodec) generate(rng *rand.Rand) interface{} {
+	return generateProtoMessage(c.root, rng, 0)
+}
+
+// generateProtoMessage 生成消息数据，每个oneof只生成其中一个字段，超过最大深度时省略嵌套消息字段
+func generateProtoMessage(message *protoMessage, rng *rand.Rand, depth int) map[string]interface{} {
+	chosen := make(map[string]*protoField)
+	seen := make(map[string]int)
+	for _, field := range message.fields {
+		if field.oneof != "" {
+			// 蓄水池抽样，等概率选择oneof中的字段
+			if seen[field.oneof]++; rng.IntN(seen[field.oneof]) == 0 {
+				chosen[field.oneof] = field
+			}
+		}
+	}
+
+	fields := make(map[string]interface{}, len(message.fields))
+	for _, field := range message.fields {
+		if field.oneof != "" && chosen[field.oneof] != field {
+			continue
+		}
+		if depth >= protoMaxDepth && (field.kind == "message" || (field.kind == "map" && field.mapValue.kind == "message")) {
+			continue
+		}
+		switch {
+		case field.kind == "map":
+			entries := make(map[string]interface{})
+			for i := 1 + rng.IntN(3); i > 0; i-- {
+				key := fmt.Sprint(generateProtoValue(field.mapKey, rng, depth+1))
+				entries[key] = generateProtoValue(field.mapValue, rng, depth+1)
+			}
+			fields[field.name] = entries
+		case field.repeated:
+			items := make([]interface{}, 1+rng.IntN(3))
+			for i := range items {
+				items[i] = generateProtoValue(field, rng, depth+1)
+			}
+			fields[field.name] = items
+		default:
+			fields[field.name] = generateProtoValue(field, rng, depth+1)
+		}
+	}
+	return fields
+}
+
+// generateProtoValue 生成单个字段值
+func generateProtoValue(field *protoField, rng *rand.Rand, depth int) interface{} {
+	switch field.kind {
+	case "double", "float":
+		return math.Round(rng.Float64()*100000) / 100
+	case "bool":
+		return rng.IntN(2) == 1
+	case "string", "bytes":
+		return randomString(rng, 8, 16)
+	case "message":
+		return generateProtoMessage(field.message, rng, depth)
+	case "enum":
+		return field.enum.values[rng.IntN(len(field.enum.values))].name
+	case "int64", "uint64", "sint64", "fixed64", "sfixed64":
+		return json.Number(strconv.FormatInt(rng.Int64N(1000000000000), 10))
+	}
+	return json.Number(strconv.FormatInt(int64(rng.Int32N(1000000)), 10))
+}
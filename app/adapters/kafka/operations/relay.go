@@ -0,0 +1,420 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	kafkaConfig "abc-runner/app/adapters/kafka/config"
+	"abc-runner/app/adapters/kafka/connection"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// relayTick 发送节拍，同一节拍内到期的消息合并为一次写入
+const relayTick = 10 * time.Millisecond
+
+// relayWindowDigits 时间序列窗口直方图的有效数字位数（窗口较多时控制内存占用）
+const relayWindowDigits = 2
+
+// RelayWindow 复制延迟时间序列中的一个窗口，按消息发送时间划分
+type RelayWindow struct {
+	Start    time.Duration `json:"start"` // 窗口起点（相对测试开始）
+	Sent     int64         `json:"sent"`
+	Received int64         `json:"received"`
+	LagP50   time.Duration `json:"lag_p50"`
+	LagP99   time.Duration `json:"lag_p99"`
+	LagMax   time.Duration `json:"lag_max"`
+}
+
+// ToMap 转换为报告使用的map
+func (w RelayWindow) ToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"start":    w.Start.String(),
+		"sent":     w.Sent,
+		"received": w.Received,
+		"lag_p50":  w.LagP50.String(),
+		"lag_p99":  w.LagP99.String(),
+		"lag_max":  w.LagMax.String(),
+	}
+}
+
+// RelayStats 跨集群复制延迟测试结果
+type RelayStats struct {
+	SourceTopic string                 `json:"source_topic"`
+	TargetTopic string                 `json:"target_topic"`
+	Sent        int64                  `json:"sent"`
+	SendErrors  int64                  `json:"send_errors"`
+	Received    int64                  `json:"received"`
+	Duplicates  int64                  `json:"duplicates"`
+	Lost        int64                  `json:"lost"`
+	LossRate    float64                `json:"loss_rate"`
+	Lag         metrics.LatencyMetrics `json:"lag"`
+	Windows     []RelayWindow          `json:"windows"`
+	Duration    time.Duration          `json:"duration"`
+}
+
+// ToMap 转换为报告使用的map
+func (s *RelayStats) ToMap() map[string]interface{} {
+	windows := make([]map[string]interface{}, 0, len(s.Windows))
+	for _, window := range s.Windows {
+		windows = append(windows, window.ToMap())
+	}
+	return map[string]interface{}{
+		"source_topic": s.SourceTopic,
+		"target_topic": s.TargetTopic,
+		"sent":         s.Sent,
+		"send_errors":  s.SendErrors,
+		"received":     s.Received,
+		"duplicates":   s.Duplicates,
+		"lost":         s.Lost,
+		"loss_rate":    s.LossRate,
+		"lag_avg":      s.Lag.Average.String(),
+		"lag_p50":      s.Lag.P50.String(),
+		"lag_p99":      s.Lag.P99.String(),
+		"lag_p999":     s.Lag.P999.String(),
+		"lag_max":      s.Lag.Max.String(),
+		"windows":      windows,
+		"duration":     s.Duration.String(),
+	}
+}
+
+// RelayBenchmark 跨集群复制（MirrorMaker）延迟测试
+// 以固定速率向源集群写入带发送时间戳的消息，同时从目标集群的复制主题读取，
+// 按发送时间窗口统计复制延迟分布；发送与接收在同一主机上计时，不受集群间时钟偏差影响
+type RelayBenchmark struct {
+	pool      *connection.ConnectionPool
+	config    *kafkaConfig.KafkaAdapterConfig
+	collector interfaces.DefaultMetricsCollector
+	lag       *metrics.LatencyTracker
+}
+
+// NewRelayBenchmark 创建跨集群复制延迟测试
+func NewRelayBenchmark(pool *connection.ConnectionPool, config *kafkaConfig.KafkaAdapterConfig, collector interfaces.DefaultMetricsCollector) *RelayBenchmark {
+	return &RelayBenchmark{
+		pool:      pool,
+		config:    config,
+		collector: collector,
+		lag: metrics.NewLatencyTracker(metrics.LatencyConfig{
+			SignificantDigits: metrics.DefaultHdrSignificantDigits,
+			SamplingRate:      1.0,
+		}),
+	}
+}
+
+// Run 执行测试
+func (b *RelayBenchmark) Run(ctx context.Context) (*RelayStats, error) {
+	relay := b.config.Relay
+	sourceTopic := b.config.Benchmark.DefaultTopic
+	targetTopic := relay.TargetTopic
+	if targetTopic == "" {
+		targetTopic = sourceTopic
+	}
+	runID := fmt.Sprintf("relay-%d", time.Now().UnixNano())
+
+	// 先定位目标主题各分区的末尾偏移，保证不会漏读测试开始后复制过来的消息
+	readers, err := b.pool.NewPartitionReaders(ctx, relay.TargetBrokers, targetTopic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach to target topic %s: %w", targetTopic, err)
+	}
+
+	startTime := time.Now()
+	tracker := newRelayTracker(runID, startTime, relay.Window, b.config.Benchmark.Total)
+
+	consumeCtx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	for _, reader := range readers {
+		wg.Add(1)
+		go func(reader *kafka.Reader) {
+			defer wg.Done()
+			b.consume(consumeCtx, reader, tracker)
+		}(reader)
+	}
+
+	sendErr := b.produce(ctx, runID, tracker)
+	waitDelivered(ctx, tracker, relay.Drain)
+
+	cancel()
+	for _, reader := range readers {
+		reader.Close()
+	}
+	wg.Wait()
+
+	stats := tracker.stats()
+	stats.SourceTopic = sourceTopic
+	stats.TargetTopic = targetTopic
+	stats.Lag = b.lag.GetMetrics()
+	stats.Duration = time.Since(startTime)
+	if sendErr != nil {
+		return stats, fmt.Errorf("failed to produce to %s: %w", sourceTopic, sendErr)
+	}
+	return stats, nil
+}
+
+// produce 按配置速率向源集群写入消息
+func (b *RelayBenchmark) produce(ctx context.Context, runID string, tracker *relayTracker) error {
+	writer := b.pool.NewWriter(relayTick)
+	defer writer.Close()
+
+	total := b.config.Benchmark.Total
+	rate := float64(b.config.Relay.Rate)
+	topic := b.config.Benchmark.DefaultTopic
+	size := b.config.Benchmark.MessageSize
+
+	ticker := time.NewTicker(relayTick)
+	defer ticker.Stop()
+
+	start := time.Now()
+	seq := 0
+	for seq < total {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		due := int(time.Since(start).Seconds()*rate) + 1
+		if due > total {
+			due = total
+		}
+		if due <= seq {
+			continue
+		}
+
+		sentAt := time.Now()
+		messages := make([]kafka.Message, 0, due-seq)
+		for ; seq < due; seq++ {
+			messages = append(messages, kafka.Message{
+				Topic: topic,
+				Key:   []byte(runID + "-" + strconv.Itoa(seq)),
+				Value: encodeRelayPayload(runID, seq, sentAt, size),
+			})
+		}
+
+		err := writer.WriteMessages(ctx, messages...)
+		duration := time.Since(sentAt)
+		b.collector.Record(&interfaces.OperationResult{
+			Success:  err == nil,
+			IsRead:   false,
+			Duration: duration,
+			Error:    err,
+			Metadata: map[string]interface{}{
+				"operation_type": "relay_produce",
+				"messages":       len(messages),
+			},
+		})
+		if err != nil {
+			tracker.sendFailed(len(messages))
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			continue
+		}
+		tracker.sent(len(messages), sentAt)
+	}
+	return nil
+}
+
+// consume 从目标集群读取复制消息并记录延迟
+func (b *RelayBenchmark) consume(ctx context.Context, reader *kafka.Reader, tracker *relayTracker) {
+	for {
+		msg, err := reader.ReadMessage(ctx)
+		if err != nil {
+			return
+		}
+		receivedAt := time.Now()
+
+		seq, sentAt, ok := decodeRelayPayload(tracker.runID, msg.Value)
+		if !ok {
+			continue
+		}
+		lag := receivedAt.Sub(sentAt)
+		isNew := tracker.received(seq, sentAt, lag)
+		if isNew {
+			b.lag.Record(lag)
+		}
+		b.collector.Record(&interfaces.OperationResult{
+			Success:  true,
+			IsRead:   true,
+			Duration: lag,
+			Metadata: map[string]interface{}{
+				"operation_type": "relay",
+				"partition":      msg.Partition,
+				"offset":         msg.Offset,
+				"duplicate":      !isNew,
+			},
+		})
+	}
+}
+
+// encodeRelayPayload 编码消息内容："runID seq 发送时间(纳秒) "，不足size时以'x'填充
+func encodeRelayPayload(runID string, seq int, sentAt time.Time, size int) []byte {
+	header := runID + " " + strconv.Itoa(seq) + " " + strconv.FormatInt(sentAt.UnixNano(), 10) + " "
+	if len(header) >= size {
+		return []byte(header)
+	}
+	return []byte(header + strings.Repeat("x", size-len(header)))
+}
+
+// decodeRelayPayload 解析消息内容，非本次运行的消息返回false
+func decodeRelayPayload(runID string, payload []byte) (int, time.Time, bool) {
+	fields := strings.SplitN(string(payload), " ", 4)
+	if len(fields) < 3 || fields[0] != runID {
+		return 0, time.Time{}, false
+	}
+	seq, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	nanos, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	return seq, time.Unix(0, nanos), true
+}
+
+// relayWindowStats 单个时间窗口的累计数据
+type relayWindowStats struct {
+	sent      int64
+	received  int64
+	max       time.Duration
+	histogram *metrics.HdrHistogram
+}
+
+// relayTracker 跨集群复制消息追踪器，负责去重、丢失统计与时间窗口划分
+type relayTracker struct {
+	runID     string
+	startTime time.Time
+	window    time.Duration
+
+	mutex      sync.Mutex
+	seen       []bool
+	sentCount  int64
+	sendErrors int64
+	unique     int64
+	duplicates int64
+	windows    []*relayWindowStats
+}
+
+// newRelayTracker 创建追踪器
+func newRelayTracker(runID string, startTime time.Time, window time.Duration, total int) *relayTracker {
+	if window <= 0 {
+		window = time.Second
+	}
+	return &relayTracker{
+		runID:     runID,
+		startTime: startTime,
+		window:    window,
+		seen:      make([]bool, total),
+	}
+}
+
+// windowAt 获取发送时间所在的窗口（需持有锁）
+func (t *relayTracker) windowAt(sentAt time.Time) *relayWindowStats {
+	index := 0
+	if offset := sentAt.Sub(t.startTime); offset > 0 {
+		index = int(offset / t.window)
+	}
+	for len(t.windows) <= index {
+		t.windows = append(t.windows, &relayWindowStats{
+			histogram: metrics.NewHdrHistogram(metrics.DefaultHdrHighestValue, relayWindowDigits),
+		})
+	}
+	return t.windows[index]
+}
+
+// sent 记录成功写入源集群的消息
+func (t *relayTracker) sent(count int, sentAt time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.sentCount += int64(count)
+	t.windowAt(sentAt).sent += int64(count)
+}
+
+// sendFailed 记录写入失败的消息
+func (t *relayTracker) sendFailed(count int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.sendErrors += int64(count)
+}
+
+// received 记录从目标集群读取到的消息，返回是否首次读取
+func (t *relayTracker) received(seq int, sentAt time.Time, lag time.Duration) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if seq < 0 || seq >= len(t.seen) {
+		return false
+	}
+	if t.seen[seq] {
+		t.duplicates++
+		return false
+	}
+	t.seen[seq] = true
+	t.unique++
+
+	window := t.windowAt(sentAt)
+	window.received++
+	window.histogram.Record(lag.Nanoseconds())
+	if lag > window.max {
+		window.max = lag
+	}
+	return true
+}
+
+// complete 已发送的消息是否全部到达目标集群
+func (t *relayTracker) complete() bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.unique >= t.sentCount
+}
+
+// stats 汇总统计
+func (t *relayTracker) stats() *RelayStats {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	stats := &RelayStats{
+		Sent:       t.sentCount,
+		SendErrors: t.sendErrors,
+		Received:   t.unique,
+		Duplicates: t.duplicates,
+		Lost:       t.sentCount - t.unique,
+		Windows:    make([]RelayWindow, 0, len(t.windows)),
+	}
+	if stats.Lost < 0 {
+		stats.Lost = 0
+	}
+	if stats.Sent > 0 {
+		stats.LossRate = float64(stats.Lost) / float64(stats.Sent) * 100
+	}
+
+	for i, window := range t.windows {
+		result := RelayWindow{
+			Start:    time.Duration(i) * t.window,
+			Sent:     window.sent,
+			Received: window.received,
+			LagMax:   window.max,
+		}
+		if window.received > 0 {
+			values := window.histogram.ValuesAtQuantiles(50, 99)
+			result.LagP50 = minDuration(time.Duration(values[0]), window.max)
+			result.LagP99 = minDuration(time.Duration(values[1]), window.max)
+		}
+		stats.Windows = append(stats.Windows, result)
+	}
+	return stats
+}
+
+// minDuration 返回较小的时长
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,54 @@
+package operations
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRelayPayloadRoundTrip(t *testing.T) {
+	sentAt := time.Unix(0, 1700000000123456789)
+	payload := encodeRelayPayload("relay-1", 42, sentAt, 128)
+	if len(payload) != 128 {
+		t.Fatalf("expected padded payload of 128 bytes, got %d", len(payload))
+	}
+
+	seq, decoded, ok := decodeRelayPayload("relay-1", payload)
+	if !ok || seq != 42 || !decoded.Equal(sentAt) {
+		t.Fatalf("unexpected decode result: seq=%d sentAt=%v ok=%v", seq, decoded, ok)
+	}
+	if _, _, ok := decodeRelayPayload("relay-2", payload); ok {
+		t.Error("expected payload from another run to be rejected")
+	}
+}
+
+func TestRelayTrackerWindows(t *testing.T) {
+	start := time.Now()
+	tracker := newRelayTracker("relay-1", start, time.Second, 4)
+
+	tracker.sent(2, start)
+	tracker.sent(2, start.Add(1500*time.Millisecond))
+
+	tracker.received(0, start, 10*time.Millisecond)
+	tracker.received(1, start, 30*time.Millisecond)
+	tracker.received(1, start, 40*time.Millisecond)
+	tracker.received(2, start.Add(1500*time.Millisecond), 200*time.Millisecond)
+
+	if tracker.complete() {
+		t.Error("expected tracker to be incomplete with one message missing")
+	}
+
+	stats := tracker.stats()
+	if stats.Sent != 4 || stats.Received != 3 || stats.Duplicates != 1 || stats.Lost != 1 || stats.LossRate != 25 {
+		t.Errorf("unexpected totals: %+v", stats)
+	}
+	if len(stats.Windows) != 2 {
+		t.Fatalf("expected 2 windows, got %d", len(stats.Windows))
+	}
+	first, second := stats.Windows[0], stats.Windows[1]
+	if first.Sent != 2 || first.Received != 2 || first.LagMax != 30*time.Millisecond {
+		t.Errorf("unexpected first window: %+v", first)
+	}
+	if second.Start != time.Second || second.Sent != 2 || second.Received != 1 || second.LagP99 != 200*time.Millisecond {
+		t.Errorf("unexpected second window: %+v", second)
+	}
+}
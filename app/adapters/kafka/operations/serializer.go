@@ -0,0 +1,269 @@
+package operations
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"abc-runner/app/core/metrics"
+)
+
+// schemaCodec 按schema生成与编码消息内容
+type schemaCodec interface {
+	// generate 按schema生成一条合成数据
+	generate(rng *rand.Rand) interface{}
+	// encode 编码JSON解码得到的数据（数值为json.Number）
+	encode(value interface{}) ([]byte, error)
+}
+
+// SerializationStats Schema Registry序列化统计
+type SerializationStats struct {
+	Format      string                 `json:"format"`
+	Subject     string                 `json:"subject"`
+	SchemaID    int                    `json:"schema_id"`
+	Messages    int64                  `json:"messages"`
+	Errors      int64                  `json:"errors"`
+	Generated   int64                  `json:"generated"` // 按schema生成内容的消息数，其余来自消息模板渲染的JSON
+	Latency     metrics.LatencyMetrics `json:"latency"`   // 单条消息的序列化耗时，不含合成数据的生成
+	WireBytes   int64                  `json:"wire_bytes"`
+	AvgWireSize float64                `json:"avg_wire_size"` // 含线格式头的平均消息大小
+}
+
+// ToMap 转换为报告使用的map
+func (s SerializationStats) ToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"format":        s.Format,
+		"subject":       s.Subject,
+		"schema_id":     s.SchemaID,
+		"messages":      s.Messages,
+		"errors":        s.Errors,
+		"generated":     s.Generated,
+		"latency":       s.Latency,
+		"wire_bytes":    s.WireBytes,
+		"avg_wire_size": s.AvgWireSize,
+	}
+}
+
+// Serializer 按Schema Registry中的schema序列化生产的消息，使用Confluent线格式
+type Serializer struct {
+	format   string
+	subject  string
+	schemaID int
+	codec    schemaCodec
+	header   []byte // 魔数0、大端4字节schema ID，Protobuf另加消息索引
+	seq      atomic.Uint64
+
+	mutex     sync.Mutex
+	messages  int64
+	errors    int64
+	generated int64
+	wireBytes int64
+	latency   *metrics.LatencyTracker
+}
+
+// NewSerializer 解析schema并创建序列化器，format为avro、protobuf或json
+func NewSerializer(format, subject string, schemaID int, schema string) (*Serializer, error) {
+	header := []byte{0, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(header[1:], uint32(schemaID))
+
+	var codec schemaCodec
+	var err error
+	switch format {
+	case "avro":
+		codec, err = newAvroCodec(schema)
+	case "protobuf":
+		codec, err = newProtobufCodec(schema)
+		// 消息索引[0]（文件中的第一个消息）按约定简写为单个0字节
+		header = append(header, 0)
+	case "json":
+		codec, err = newJSONSchemaCodec(schema)
+	default:
+		return nil, fmt.Errorf("unsupported schema format: %s", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s schema: %w", format, err)
+	}
+
+	return &Serializer{
+		format:   format,
+		subject:  subject,
+		schemaID: schemaID,
+		codec:    codec,
+		header:   header,
+		latency: metrics.NewLatencyTracker(metrics.LatencyConfig{
+			SignificantDigits: metrics.DefaultHdrSignificantDigits,
+			SamplingRate:      1.0,
+		}),
+	}, nil
+}
+
+// Serialize 序列化一条消息：value为JSON对象时按schema编码该对象，否则按schema生成合成数据
+func (s *Serializer) Serialize(value string) ([]byte, error) {
+	var data interface{}
+	generated := !strings.HasPrefix(strings.TrimSpace(value), "{")
+	if generated {
+		seq := s.seq.Add(1)
+		data = s.codec.generate(rand.New(rand.NewPCG(seq, uint64(s.schemaID))))
+	} else {
+		decoder := json.NewDecoder(strings.NewReader(value))
+		decoder.UseNumber()
+		if err := decoder.Decode(&data); err != nil {
+			s.record(false, 0, 0, err)
+			return nil, fmt.Errorf("message value is not valid JSON: %w", err)
+		}
+	}
+
+	start := time.Now()
+	payload, err := s.codec.encode(data)
+	var message []byte
+	if err == nil {
+		message = make([]byte, 0, len(s.header)+len(payload))
+		message = append(append(message, s.header...), payload...)
+	}
+	s.record(generated, time.Since(start), len(message), err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize message: %w", err)
+	}
+	return message, nil
+}
+
+// record 记录一次序列化
+func (s *Serializer) record(generated bool, duration time.Duration, size int, err error) {
+	s.mutex.Lock()
+	s.messages++
+	if err != nil {
+		s.errors++
+		s.mutex.Unlock()
+		return
+	}
+	if generated {
+		s.generated++
+	}
+	s.wireBytes += int64(size)
+	s.mutex.Unlock()
+
+	s.latency.Record(duration)
+}
+
+// Stats 获取序列化统计
+func (s *Serializer) Stats() SerializationStats {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stats := SerializationStats{
+		Format:    s.format,
+		Subject:   s.subject,
+		SchemaID:  s.schemaID,
+		Messages:  s.messages,
+		Errors:    s.errors,
+		Generated: s.generated,
+		Latency:   s.latency.GetMetrics(),
+		WireBytes: s.wireBytes,
+	}
+	if succeeded := s.messages - s.errors; succeeded > 0 {
+		stats.AvgWireSize = float64(s.wireBytes) / float64(succeeded)
+	}
+	return stats
+}
+
+// randomString 生成长度在[min, max]之间的随机字母数字串
+func randomString(rng *rand.Rand, min, max int) string {
+	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	result := make([]byte, min+rng.IntN(max-min+1))
+	for i := range result {
+		result[i] = charset[rng.IntN(len(charset))]
+	}
+	return string(result)
+}
+
+// toInt64 将JSON数值或生成的整数转换为int64
+func toInt64(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n, nil
+		}
+		if f, err := v.Float64(); err == nil && f == float64(int64(f)) {
+			return int64(f), nil
+		}
+		return 0, fmt.Errorf("%s is not an integer", v)
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case float64:
+		if v == float64(int64(v)) {
+			return int64(v), nil
+		}
+		return 0, fmt.Errorf("%v is not an integer", v)
+	case string:
+		// 整数类型的map键在JSON中只能写作字符串
+		return strconv.ParseInt(v, 10, 64)
+	}
+	return 0, fmt.Errorf("expected an integer, got %s", describeValue(value))
+}
+
+// toFloat64 将JSON数值或生成的数值转换为float64
+func toFloat64(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case json.Number:
+		return v.Float64()
+	case float64:
+		return v, nil
+	case int64:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	}
+	return 0, fmt.Errorf("expected a number, got %s", describeValue(value))
+}
+
+// toBytes 将字符串或字节内容转换为字节
+func toBytes(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case string:
+		return []byte(v), nil
+	case []byte:
+		return v, nil
+	}
+	return nil, fmt.Errorf("expected a string, got %s", describeValue(value))
+}
+
+// isInteger 判断数值是否为整数
+func isInteger(value interface{}) bool {
+	_, err := toInt64(value)
+	_, isString := value.(string)
+	return err == nil && !isString
+}
+
+// describeValue 描述JSON值的类型，用于错误信息
+func describeValue(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number, int, int32, int64, float64:
+		return "number"
+	case string, []byte:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// joinPath 拼接错误信息中的字段路径
+func joinPath(path, field string) string {
+	return path + "." + field
+}
@@ -0,0 +1,166 @@
+package operations
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+const testAvroSchema = `{
+  "type": "record", "name": "Order", "namespace": "shop",
+  "fields": [
+    {"name": "id", "type": "long"},
+    {"name": "sku", "type": "string"},
+    {"name": "note", "type": ["null", "string"], "default": null},
+    {"name": "status", "type": {"type": "enum", "name": "Status", "symbols": ["NEW", "PAID"]}},
+    {"name": "lines", "type": {"type": "array", "items": "int"}},
+    {"name": "parent", "type": ["null", "shop.Order"], "default": null}
+  ]
+}`
+
+const testProtoSchema = `
+syntax = "proto3";
+package shop;
+
+// 订单
+message Order {
+  int64 id = 1;
+  string sku = 2;
+  repeated int32 qty = 3 [packed = true];
+  Status status = 4;
+  Item item = 5;
+  map<string, int32> tags = 6;
+  oneof payment {
+    string card = 7;
+    string wallet = 8;
+  }
+  reserved 9, 10;
+
+  enum Status {
+    UNKNOWN = 0;
+    PAID = 1;
+  }
+  message Item { double price = 1; }
+}
+`
+
+const testJSONSchema = `{
+  "type": "object",
+  "properties": {
+    "id": {"type": "integer", "minimum": 1, "maximum": 10},
+    "email": {"type": "string", "format": "email"},
+    "tags": {"type": "array", "items": {"$ref": "#/definitions/tag"}}
+  },
+  "definitions": {"tag": {"enum": ["a", "b"]}}
+}`
+
+func TestAvroSerializer(t *testing.T) {
+	serializer, err := NewSerializer("avro", "orders-value", 42, testAvroSchema)
+	if err != nil {
+		t.Fatalf("failed to create serializer: %v", err)
+	}
+
+	message, err := serializer.Serialize(`{"id": 1, "sku": "ab", "note": {"string": "x"}, "status": "PAID", "lines": [1, -1]}`)
+	if err != nil {
+		t.Fatalf("failed to serialize: %v", err)
+	}
+	expected := []byte{
+		0, 0, 0, 0, 42, // 魔数与schema ID
+		0x02,           // id = 1
+		0x04, 'a', 'b', // sku
+		0x02, 0x02, 'x', // note: union分支1
+		0x02,                   // status: PAID
+		0x04, 0x02, 0x01, 0x00, // lines: 2个元素的块与结束块
+		0x00, // parent: 默认值null
+	}
+	if !bytes.Equal(message, expected) {
+		t.Errorf("unexpected encoding\n got %x\nwant %x", message, expected)
+	}
+
+	if _, err := serializer.Serialize(`{"id": 1}`); err == nil {
+		t.Error("a missing field without default should fail")
+	}
+	stats := serializer.Stats()
+	if stats.Messages != 2 || stats.Errors != 1 || stats.WireBytes != int64(len(expected)) || stats.AvgWireSize != float64(len(expected)) {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestProtobufSerializer(t *testing.T) {
+	serializer, err := NewSerializer("protobuf", "orders-value", 7, testProtoSchema)
+	if err != nil {
+		t.Fatalf("failed to create serializer: %v", err)
+	}
+
+	message, err := serializer.Serialize(`{"id": 150, "sku": "a", "qty": [1, 2], "status": "PAID", "item": {"price": 1.5}, "tags": {"k": 1}}`)
+	if err != nil {
+		t.Fatalf("failed to serialize: %v", err)
+	}
+	expected := []byte{
+		0, 0, 0, 0, 7, 0, // 魔数、schema ID与消息索引
+		0x08, 0x96, 0x01, // id = 150
+		0x12, 0x01, 'a', // sku
+		0x1a, 0x02, 0x01, 0x02, // qty: packed
+		0x20, 0x01, // status: PAID
+		0x2a, 0x09, 0x09, 0, 0, 0, 0, 0, 0, 0xf8, 0x3f, // item.price = 1.5
+		0x32, 0x05, 0x0a, 0x01, 'k', 0x10, 0x01, // tags条目
+	}
+	if !bytes.Equal(message, expected) {
+		t.Errorf("unexpected encoding\n got %x\nwant %x", message, expected)
+	}
+
+	if _, err := NewSerializer("protobuf", "s", 1, `message A { google.protobuf.Timestamp at = 1; }`); err == nil {
+		t.Error("an unresolved imported type should fail")
+	}
+}
+
+func TestJSONSchemaSerializer(t *testing.T) {
+	serializer, err := NewSerializer("json", "users-value", 3, testJSONSchema)
+	if err != nil {
+		t.Fatalf("failed to create serializer: %v", err)
+	}
+
+	message, err := serializer.Serialize("kafka_test_message_1")
+	if err != nil {
+		t.Fatalf("failed to serialize: %v", err)
+	}
+	if !bytes.Equal(message[:5], []byte{0, 0, 0, 0, 3}) {
+		t.Fatalf("unexpected wire header %x", message[:5])
+	}
+	var value struct {
+		ID    int      `json:"id"`
+		Email string   `json:"email"`
+		Tags  []string `json:"tags"`
+	}
+	if err := json.Unmarshal(message[5:], &value); err != nil {
+		t.Fatalf("payload is not JSON: %v", err)
+	}
+	if value.ID < 1 || value.ID > 10 || len(value.Email) == 0 || len(value.Tags) == 0 || (value.Tags[0] != "a" && value.Tags[0] != "b") {
+		t.Errorf("generated value does not follow the schema: %s", message[5:])
+	}
+
+	if _, err := serializer.Serialize("{not json"); err == nil {
+		t.Error("an invalid JSON value should fail")
+	}
+	if stats := serializer.Stats(); stats.Generated != 1 || stats.Errors != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestGeneratedValuesEncode(t *testing.T) {
+	schemas := map[string]string{"avro": testAvroSchema, "protobuf": testProtoSchema, "json": testJSONSchema}
+	for format, schema := range schemas {
+		serializer, err := NewSerializer(format, "subject", 1, schema)
+		if err != nil {
+			t.Fatalf("%s: failed to create serializer: %v", format, err)
+		}
+		for i := 0; i < 200; i++ {
+			if _, err := serializer.Serialize("generated"); err != nil {
+				t.Fatalf("%s: generated value %d failed to encode: %v", format, i, err)
+			}
+		}
+		if stats := serializer.Stats(); stats.Generated != 200 || stats.WireBytes == 0 {
+			t.Errorf("%s: unexpected stats: %+v", format, stats)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"abc-runner/app/adapters/mongodb/config"
+	"abc-runner/app/adapters/mongodb/connection"
+	"abc-runner/app/adapters/mongodb/operations"
+	"abc-runner/app/core/interfaces"
+)
+
+// MongoAdapter MongoDB协议适配器 - 遵循统一架构模式
+// 职责：客户端管理、状态维护、健康检查
+type MongoAdapter struct {
+	config           *config.MongoConfig
+	client           *connection.Client
+	mongoOperations  *operations.MongoExecutor
+	metricsCollector interfaces.DefaultMetricsCollector
+	mu               sync.RWMutex
+	isConnected      bool
+	seeded           bool
+
+	// 统计信息
+	totalOperations  int64
+	failedOperations int64
+}
+
+// NewMongoAdapter 创建MongoDB适配器
+func NewMongoAdapter(metricsCollector interfaces.DefaultMetricsCollector) *MongoAdapter {
+	return &MongoAdapter{
+		metricsCollector: metricsCollector,
+	}
+}
+
+// Connect 建立客户端，配置了setup时填充测试集合并创建键字段索引
+func (m *MongoAdapter) Connect(ctx context.Context, cfg interfaces.Config) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mongoConfig, ok := cfg.(*config.MongoConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type for MongoDB adapter: expected *config.MongoConfig, got %T", cfg)
+	}
+
+	if err := mongoConfig.Validate(); err != nil {
+		return fmt.Errorf("config validation failed: %w", err)
+	}
+	builder, err := operations.NewDocumentBuilder(mongoConfig)
+	if err == nil {
+		err = builder.Check()
+	}
+	if err != nil {
+		return fmt.Errorf("config validation failed: %w", err)
+	}
+	m.config = mongoConfig
+
+	client, err := connection.NewClient(ctx, mongoConfig)
+	if err != nil {
+		return err
+	}
+
+	if specific := mongoConfig.MongoSpecific; specific.Setup {
+		seeded, err := operations.Seed(ctx, client.Collection(), builder, specific.Documents)
+		m.seeded = seeded
+		if err == nil && specific.Index {
+			err = operations.EnsureIndex(ctx, client.Collection(), specific.KeyField)
+		}
+		if err != nil {
+			client.Close()
+			return err
+		}
+	}
+
+	m.client = client
+	m.mongoOperations = operations.NewMongoExecutor(client.Collection(), builder)
+	m.isConnected = true
+	return nil
+}
+
+// Execute 执行操作 - 使用执行器处理
+func (m *MongoAdapter) Execute(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	if !m.isConnected {
+		return nil, fmt.Errorf("adapter not connected")
+	}
+
+	atomic.AddInt64(&m.totalOperations, 1)
+	result, err := m.mongoOperations.ExecuteOperation(ctx, operation)
+	if err != nil {
+		atomic.AddInt64(&m.failedOperations, 1)
+	}
+	return result, err
+}
+
+// Close 断开客户端
+func (m *MongoAdapter) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.client != nil {
+		m.client.Close()
+		m.client = nil
+	}
+	m.isConnected = false
+	return nil
+}
+
+// GetProtocolMetrics 获取协议特定指标
+func (m *MongoAdapter) GetProtocolMetrics() map[string]interface{} {
+	metrics := map[string]interface{}{
+		"protocol":          "mongodb",
+		"total_operations":  atomic.LoadInt64(&m.totalOperations),
+		"failed_operations": atomic.LoadInt64(&m.failedOperations),
+	}
+
+	if m.config != nil {
+		specific := m.config.MongoSpecific
+		metrics["collection"] = specific.Collection
+		metrics["key_field"] = specific.KeyField
+		metrics["key_distribution"] = specific.KeyDistribution.String()
+		metrics["read_preference"] = specific.ReadPreference
+		metrics["write_concern"] = specific.WriteConcern
+		if m.config.BenchMark.TestCase == config.TestCaseMixed {
+			metrics["mix"] = specific.Mix
+		}
+	}
+	if m.client != nil {
+		metrics["server_version"] = m.client.ServerVersion()
+		metrics["pool_stats"] = m.client.Stats()
+	}
+	if stats := m.GetOperationStats(); stats != nil {
+		metrics["operation_stats"] = stats
+	}
+
+	return metrics
+}
+
+// GetOperationStats 获取按操作类型的统计，未连接时返回nil
+func (m *MongoAdapter) GetOperationStats() []operations.OperationStats {
+	if m.mongoOperations == nil {
+		return nil
+	}
+	return m.mongoOperations.OperationStats()
+}
+
+// GetPoolStats 获取连接池统计，未连接时返回nil
+func (m *MongoAdapter) GetPoolStats() *connection.PoolStats {
+	if m.client == nil {
+		return nil
+	}
+	stats := m.client.Stats()
+	return &stats
+}
+
+// ServerVersion 服务端版本，未连接时为空
+func (m *MongoAdapter) ServerVersion() string {
+	if m.client == nil {
+		return ""
+	}
+	return m.client.ServerVersion()
+}
+
+// Seeded 本次运行是否由setup填充了测试集合
+func (m *MongoAdapter) Seeded() bool {
+	return m.seeded
+}
+
+// HealthCheck 健康检查：对主节点执行Ping
+func (m *MongoAdapter) HealthCheck(ctx context.Context) error {
+	if !m.isConnected {
+		return fmt.Errorf("adapter not connected")
+	}
+
+	if err := m.client.Ping(ctx); err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	return nil
+}
+
+// GetProtocolName 获取协议名称
+func (m *MongoAdapter) GetProtocolName() string {
+	return "mongodb"
+}
+
+// GetMetricsCollector 获取指标收集器
+func (m *MongoAdapter) GetMetricsCollector() interfaces.DefaultMetricsCollector {
+	return m.metricsCollector
+}
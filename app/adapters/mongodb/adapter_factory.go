@@ -0,0 +1,44 @@
+package mongodb
+
+import (
+	"abc-runner/app/core/interfaces"
+)
+
+// AdapterFactory MongoDB适配器工厂
+type AdapterFactory struct {
+	metricsCollector interfaces.DefaultMetricsCollector
+}
+
+// NewAdapterFactory 创建MongoDB适配器工厂
+func NewAdapterFactory(metricsCollector interfaces.DefaultMetricsCollector) *AdapterFactory {
+	return &AdapterFactory{
+		metricsCollector: metricsCollector,
+	}
+}
+
+// CreateMongoAdapter 创建MongoDB适配器 (实现MongoAdapterFactory接口)
+func (f *AdapterFactory) CreateMongoAdapter() interfaces.ProtocolAdapter {
+	if f.metricsCollector == nil {
+		panic("metricsCollector cannot be nil - dependency injection required")
+	}
+
+	return NewMongoAdapter(f.metricsCollector)
+}
+
+// GetProtocolName 获取支持的协议名称
+func (f *AdapterFactory) GetProtocolName() string {
+	return "mongodb"
+}
+
+// GetMetricsCollector 获取指标收集器
+func (f *AdapterFactory) GetMetricsCollector() interfaces.DefaultMetricsCollector {
+	return f.metricsCollector
+}
+
+// SetMetricsCollector 设置指标收集器
+func (f *AdapterFactory) SetMetricsCollector(collector interfaces.DefaultMetricsCollector) {
+	f.metricsCollector = collector
+}
+
+// 确保实现了interfaces.MongoAdapterFactory接口
+var _ interfaces.MongoAdapterFactory = (*AdapterFactory)(nil)
@@ -0,0 +1,375 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/utils"
+)
+
+// 操作类型，同时也是mixed以外的测试用例名
+const (
+	OperationFind      = "find"      // 按键查找单个文档
+	OperationInsert    = "insert"    // 插入按模板生成的文档
+	OperationUpdate    = "update"    // 按键更新单个文档
+	OperationAggregate = "aggregate" // 从随机键开始执行聚合管道
+)
+
+// TestCaseMixed 按mix权重混合各操作类型
+const TestCaseMixed = "mixed"
+
+// KeyPlaceholder 聚合管道中替换为本次操作键值的占位符
+const KeyPlaceholder = "{{key}}"
+
+// 默认值
+const (
+	DefaultCollection = "abc_bench"
+	DefaultKeyField   = "key"
+	DefaultDocument   = `{"name": "{{name}}", "email": "{{email}}", "score": {{randInt 0 1000}}, "tags": ["{{pick red green blue}}"], "payload": "{{randString 100}}"}`
+	DefaultUpdate     = `{"$inc": {"score": 1}, "$set": {"payload": "{{randString 100}}"}}`
+)
+
+// MongoConfig MongoDB协议配置
+type MongoConfig struct {
+	Protocol      string              `yaml:"protocol" json:"protocol"`
+	Connection    ConnectionConfig    `yaml:"connection" json:"connection"`
+	BenchMark     BenchmarkConfig     `yaml:"benchmark" json:"benchmark"`
+	MongoSpecific MongoSpecificConfig `yaml:"mongodb_specific" json:"mongodb_specific"`
+}
+
+// ConnectionConfig MongoDB连接配置
+type ConnectionConfig struct {
+	URI        string        `yaml:"uri" json:"uri"` // 连接串，设置时忽略address与port
+	Address    string        `yaml:"address" json:"address"`
+	Port       int           `yaml:"port" json:"port"`
+	Username   string        `yaml:"username" json:"username"`
+	Password   string        `yaml:"password" json:"password"`
+	AuthSource string        `yaml:"auth_source" json:"auth_source"` // 认证数据库，默认admin
+	Database   string        `yaml:"database" json:"database"`
+	ReplicaSet string        `yaml:"replica_set" json:"replica_set"`
+	TLS        bool          `yaml:"tls" json:"tls"`
+	PoolSize   int           `yaml:"pool_size" json:"pool_size"` // 最大连接数，0表示与并发数相同
+	Timeout    time.Duration `yaml:"timeout" json:"timeout"`     // 建连、服务器选择与单个操作的超时
+}
+
+// BenchmarkConfig MongoDB基准测试配置
+type BenchmarkConfig struct {
+	Total     int           `yaml:"total" json:"total"`
+	Parallels int           `yaml:"parallels" json:"parallels"`
+	TestCase  string        `yaml:"test_case" json:"test_case"` // find、insert、update、aggregate或mixed
+	Duration  time.Duration `yaml:"duration" json:"duration"`
+}
+
+// MongoSpecificConfig MongoDB特定配置
+type MongoSpecificConfig struct {
+	Collection string `yaml:"collection" json:"collection"`
+	Documents  int    `yaml:"documents" json:"documents"` // 键空间大小，find/update/aggregate访问的键取自[0, documents)
+	Setup      bool   `yaml:"setup" json:"setup"`         // 运行前在集合为空时填充documents个文档，并创建键字段索引
+
+	// KeyField 存放整数键的字段，insert生成的文档与setup填充的文档均带此字段
+	KeyField string `yaml:"key_field" json:"key_field"`
+	// Index setup时在键字段上创建升序索引；键字段为_id时始终有索引
+	Index bool `yaml:"index" json:"index"`
+	// KeyDistribution 键访问分布，默认uniform
+	KeyDistribution utils.KeyDistributionConfig `yaml:"key_distribution" json:"key_distribution"`
+
+	// Document 文档模板（Extended JSON），支持负载模板占位符，键字段自动追加
+	Document utils.PayloadTemplateConfig `yaml:"document" json:"document"`
+	// Update 更新文档模板（Extended JSON），支持负载模板占位符
+	Update string `yaml:"update" json:"update"`
+	// Pipeline 聚合管道（Extended JSON数组），{{key}}替换为本次操作的键；为空时从键开始取100个文档求score平均值
+	Pipeline string `yaml:"pipeline" json:"pipeline"`
+
+	// Mix mixed用例中各操作类型的权重
+	Mix MixConfig `yaml:"mix" json:"mix"`
+
+	WriteConcern   WriteConcernConfig `yaml:"write_concern" json:"write_concern"`
+	ReadPreference string             `yaml:"read_preference" json:"read_preference"` // primary、primaryPreferred、secondary、secondaryPreferred、nearest
+}
+
+// MixConfig mixed用例中各操作类型的权重
+type MixConfig struct {
+	Find      int `yaml:"find" json:"find"`
+	Insert    int `yaml:"insert" json:"insert"`
+	Update    int `yaml:"update" json:"update"`
+	Aggregate int `yaml:"aggregate" json:"aggregate"`
+}
+
+// WriteConcernConfig 写关注
+type WriteConcernConfig struct {
+	W       string `yaml:"w" json:"w"`             // majority或确认节点数，0表示不确认；为空时使用服务端默认值
+	Journal bool   `yaml:"journal" json:"journal"` // 要求写入日志后确认
+}
+
+// NewDefaultMongoConfig 创建默认MongoDB配置
+func NewDefaultMongoConfig() *MongoConfig {
+	return &MongoConfig{
+		Protocol: "mongodb",
+		Connection: ConnectionConfig{
+			Address:  "localhost",
+			Port:     27017,
+			Database: "test",
+			Timeout:  5 * time.Second,
+		},
+		BenchMark: BenchmarkConfig{
+			Total:     1000,
+			Parallels: 10,
+			TestCase:  OperationFind,
+		},
+		MongoSpecific: MongoSpecificConfig{
+			Collection:      DefaultCollection,
+			Documents:       10000,
+			KeyField:        DefaultKeyField,
+			Index:           true,
+			KeyDistribution: utils.KeyDistributionConfig{Type: utils.DistributionUniform},
+			Document:        utils.PayloadTemplateConfig{Template: DefaultDocument},
+			Update:          DefaultUpdate,
+			Mix: MixConfig{
+				Find:      70,
+				Insert:    10,
+				Update:    15,
+				Aggregate: 5,
+			},
+			ReadPreference: "primary",
+		},
+	}
+}
+
+// GetPoolSize 获取最大连接数，未配置时与并发数相同
+func (c *MongoConfig) GetPoolSize() int {
+	if c.Connection.PoolSize > 0 {
+		return c.Connection.PoolSize
+	}
+	return c.BenchMark.Parallels
+}
+
+// GetPipeline 获取聚合管道模板，未配置时返回默认管道
+func (c *MongoConfig) GetPipeline() string {
+	if c.MongoSpecific.Pipeline != "" {
+		return c.MongoSpecific.Pipeline
+	}
+	return fmt.Sprintf(`[{"$match": {%q: {"$gte": %s}}}, {"$sort": {%q: 1}}, {"$limit": 100}, {"$group": {"_id": null, "avg_score": {"$avg": "$score"}, "count": {"$sum": 1}}}]`,
+		c.MongoSpecific.KeyField, KeyPlaceholder, c.MongoSpecific.KeyField)
+}
+
+// GetProtocol 实现Config接口
+func (c *MongoConfig) GetProtocol() string {
+	return c.Protocol
+}
+
+// GetConnection 实现Config接口
+func (c *MongoConfig) GetConnection() interfaces.ConnectionConfig {
+	return &c.Connection
+}
+
+// GetBenchmark 实现Config接口
+func (c *MongoConfig) GetBenchmark() interfaces.BenchmarkConfig {
+	return &c.BenchMark
+}
+
+// Validate 实现Config接口
+func (c *MongoConfig) Validate() error {
+	if c.Connection.URI == "" {
+		if c.Connection.Address == "" {
+			return fmt.Errorf("connection address cannot be empty")
+		}
+		if c.Connection.Port <= 0 || c.Connection.Port > 65535 {
+			return fmt.Errorf("invalid port number: %d", c.Connection.Port)
+		}
+	} else if !strings.HasPrefix(c.Connection.URI, "mongodb://") && !strings.HasPrefix(c.Connection.URI, "mongodb+srv://") {
+		return fmt.Errorf("invalid uri: must start with mongodb:// or mongodb+srv://")
+	}
+	if c.Connection.Database == "" {
+		return fmt.Errorf("database cannot be empty")
+	}
+	if c.Connection.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+	if c.Connection.PoolSize < 0 {
+		return fmt.Errorf("pool size cannot be negative")
+	}
+
+	if c.BenchMark.Total <= 0 {
+		return fmt.Errorf("total operations must be greater than 0")
+	}
+	if c.BenchMark.Parallels <= 0 {
+		return fmt.Errorf("parallel connections must be greater than 0")
+	}
+
+	validTestCases := []string{OperationFind, OperationInsert, OperationUpdate, OperationAggregate, TestCaseMixed}
+	valid := false
+	for _, testCase := range validTestCases {
+		if c.BenchMark.TestCase == testCase {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Errorf("invalid test case: %s, valid options: %s",
+			c.BenchMark.TestCase, strings.Join(validTestCases, ", "))
+	}
+
+	specific := c.MongoSpecific
+	if specific.Collection == "" {
+		return fmt.Errorf("collection cannot be empty")
+	}
+	if specific.KeyField == "" {
+		return fmt.Errorf("key field cannot be empty")
+	}
+	if specific.Documents <= 0 {
+		return fmt.Errorf("documents must be greater than 0")
+	}
+	if err := specific.KeyDistribution.Validate(specific.Documents); err != nil {
+		return err
+	}
+	if !specific.Document.Enabled() {
+		return fmt.Errorf("document template cannot be empty")
+	}
+	if err := specific.Document.Validate(); err != nil {
+		return fmt.Errorf("invalid document template: %w", err)
+	}
+	if specific.Update == "" {
+		return fmt.Errorf("update template cannot be empty")
+	}
+	if _, err := utils.ParsePayloadTemplate(specific.Update, nil); err != nil {
+		return fmt.Errorf("invalid update template: %w", err)
+	}
+	if c.BenchMark.TestCase == TestCaseMixed {
+		mix := specific.Mix
+		if mix.Find < 0 || mix.Insert < 0 || mix.Update < 0 || mix.Aggregate < 0 {
+			return fmt.Errorf("mix weights cannot be negative")
+		}
+		if mix.Find+mix.Insert+mix.Update+mix.Aggregate == 0 {
+			return fmt.Errorf("mixed test case requires at least one positive mix weight")
+		}
+	}
+
+	switch specific.ReadPreference {
+	case "primary", "primaryPreferred", "secondary", "secondaryPreferred", "nearest":
+	default:
+		return fmt.Errorf("invalid read preference: %s, valid options: primary, primaryPreferred, secondary, secondaryPreferred, nearest",
+			specific.ReadPreference)
+	}
+	if w := specific.WriteConcern.W; w != "" && w != "majority" {
+		if n, err := strconv.Atoi(w); err != nil || n < 0 {
+			return fmt.Errorf("invalid write concern w: %s (expected majority or a non-negative number)", w)
+		}
+	}
+	if specific.WriteConcern.W == "0" && specific.WriteConcern.Journal {
+		return fmt.Errorf("journaled write concern cannot be unacknowledged (w=0)")
+	}
+
+	return nil
+}
+
+// Clone 实现Config接口
+func (c *MongoConfig) Clone() interfaces.Config {
+	clone := *c
+	return &clone
+}
+
+// ConnectionConfig接口实现
+
+// GetAddresses 实现ConnectionConfig接口，连接串中的密码被隐去
+func (c *ConnectionConfig) GetAddresses() []string {
+	if c.URI != "" {
+		if u, err := url.Parse(c.URI); err == nil && u.User != nil {
+			u.User = url.User(u.User.Username())
+			return []string{u.String()}
+		}
+		return []string{c.URI}
+	}
+	return []string{net.JoinHostPort(c.Address, strconv.Itoa(c.Port))}
+}
+
+// GetCredentials 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetCredentials() map[string]string {
+	return map[string]string{
+		"username":    c.Username,
+		"password":    c.Password,
+		"auth_source": c.AuthSource,
+	}
+}
+
+// GetPoolConfig 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetPoolConfig() interfaces.PoolConfig {
+	return &PoolConfig{size: c.PoolSize, timeout: c.Timeout}
+}
+
+// GetTimeout 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetTimeout() time.Duration {
+	return c.Timeout
+}
+
+// PoolConfig 连接池配置（映射到驱动的连接池设置）
+type PoolConfig struct {
+	size    int
+	timeout time.Duration
+}
+
+func (p *PoolConfig) GetPoolSize() int                    { return p.size }
+func (p *PoolConfig) GetMinIdle() int                     { return p.size }
+func (p *PoolConfig) GetMaxIdle() int                     { return p.size }
+func (p *PoolConfig) GetIdleTimeout() time.Duration       { return 0 }
+func (p *PoolConfig) GetConnectionTimeout() time.Duration { return p.timeout }
+
+// BenchmarkConfig接口实现
+
+// GetTotal 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTotal() int {
+	return b.Total
+}
+
+// GetParallels 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetParallels() int {
+	return b.Parallels
+}
+
+// GetDataSize 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetDataSize() int {
+	return 0
+}
+
+// GetTTL 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTTL() time.Duration {
+	return 0
+}
+
+// GetReadPercent 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetReadPercent() int {
+	switch b.TestCase {
+	case OperationFind, OperationAggregate:
+		return 100
+	}
+	return 0
+}
+
+// GetRandomKeys 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetRandomKeys() int {
+	return 0
+}
+
+// GetTestCase 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTestCase() string {
+	return b.TestCase
+}
+
+// GetDuration 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetDuration() time.Duration {
+	return b.Duration
+}
+
+// GetTimeout 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTimeout() time.Duration {
+	return 0
+}
+
+// GetRampUp 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetRampUp() time.Duration {
+	return 0
+}
@@ -0,0 +1,195 @@
+package connection
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"abc-runner/app/adapters/mongodb/config"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
+	"go.mongodb.org/mongo-driver/v2/mongo/writeconcern"
+)
+
+// Client MongoDB客户端，封装驱动的连接池并统计连接池事件
+type Client struct {
+	client        *mongo.Client
+	collection    *mongo.Collection
+	pool          *poolMonitor
+	serverVersion string
+}
+
+// PoolStats 连接池统计（来自驱动的连接池事件）
+type PoolStats struct {
+	MaxSize        int           `json:"max_size"`
+	Created        int64         `json:"created"`          // 新建的连接数
+	Closed         int64         `json:"closed"`           // 关闭的连接数
+	CheckedOut     int64         `json:"checked_out"`      // 取用连接的次数
+	CheckOutFailed int64         `json:"check_out_failed"` // 取用连接失败的次数（超时或连接池关闭）
+	AvgWait        time.Duration `json:"avg_wait"`         // 取用连接的平均等待时间
+	MaxWait        time.Duration `json:"max_wait"`         // 取用连接的最长等待时间
+	Cleared        int64         `json:"cleared"`          // 连接池因错误被清空的次数
+}
+
+// poolMonitor 汇总连接池事件
+type poolMonitor struct {
+	mutex     sync.Mutex
+	stats     PoolStats
+	totalWait time.Duration
+}
+
+func (m *poolMonitor) event(e *event.PoolEvent) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	switch e.Type {
+	case event.ConnectionCreated:
+		m.stats.Created++
+	case event.ConnectionClosed:
+		m.stats.Closed++
+	case event.ConnectionCheckedOut:
+		m.stats.CheckedOut++
+		m.totalWait += e.Duration
+		m.stats.MaxWait = max(m.stats.MaxWait, e.Duration)
+	case event.ConnectionCheckOutFailed:
+		m.stats.CheckOutFailed++
+	case event.ConnectionPoolCleared:
+		m.stats.Cleared++
+	}
+}
+
+func (m *poolMonitor) snapshot() PoolStats {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	stats := m.stats
+	if stats.CheckedOut > 0 {
+		stats.AvgWait = m.totalWait / time.Duration(stats.CheckedOut)
+	}
+	return stats
+}
+
+// NewClientOptions 按配置构造驱动选项
+func NewClientOptions(cfg *config.MongoConfig) (*options.ClientOptions, error) {
+	opts := options.Client().SetAppName("abc-runner")
+	conn := cfg.Connection
+	if conn.URI != "" {
+		opts.ApplyURI(conn.URI)
+	} else {
+		opts.SetHosts(cfg.Connection.GetAddresses())
+	}
+	if conn.Username != "" {
+		authSource := conn.AuthSource
+		if authSource == "" {
+			authSource = "admin"
+		}
+		opts.SetAuth(options.Credential{Username: conn.Username, Password: conn.Password, AuthSource: authSource})
+	}
+	if conn.ReplicaSet != "" {
+		opts.SetReplicaSet(conn.ReplicaSet)
+	}
+	if conn.TLS {
+		opts.SetTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12})
+	}
+
+	size := uint64(cfg.GetPoolSize())
+	opts.SetMaxPoolSize(size).SetMinPoolSize(size)
+	opts.SetConnectTimeout(conn.Timeout).SetServerSelectionTimeout(conn.Timeout).SetTimeout(conn.Timeout)
+
+	mode, err := readpref.ModeFromString(cfg.MongoSpecific.ReadPreference)
+	if err != nil {
+		return nil, err
+	}
+	readPreference, err := readpref.New(mode)
+	if err != nil {
+		return nil, err
+	}
+	opts.SetReadPreference(readPreference)
+
+	if wc := NewWriteConcern(cfg.MongoSpecific.WriteConcern); wc != nil {
+		opts.SetWriteConcern(wc)
+	}
+	return opts, opts.Validate()
+}
+
+// NewWriteConcern 按配置构造写关注，未配置时返回nil以使用服务端默认值
+func NewWriteConcern(cfg config.WriteConcernConfig) *writeconcern.WriteConcern {
+	if cfg.W == "" && !cfg.Journal {
+		return nil
+	}
+	wc := &writeconcern.WriteConcern{}
+	if cfg.W == "majority" {
+		wc.W = "majority"
+	} else if n, err := strconv.Atoi(cfg.W); err == nil {
+		wc.W = n
+	}
+	if cfg.Journal {
+		journal := true
+		wc.Journal = &journal
+	}
+	return wc
+}
+
+// NewClient 连接服务端并在返回前确认可达，查询服务端版本
+func NewClient(ctx context.Context, cfg *config.MongoConfig) (*Client, error) {
+	opts, err := NewClientOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	monitor := &poolMonitor{stats: PoolStats{MaxSize: cfg.GetPoolSize()}}
+	opts.SetPoolMonitor(&event.PoolMonitor{Event: monitor.event})
+
+	client, err := mongo.Connect(opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.Ping(ctx, readpref.Primary()); err != nil {
+		client.Disconnect(context.Background())
+		return nil, err
+	}
+
+	var buildInfo struct {
+		Version string `bson:"version"`
+	}
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "buildInfo", Value: 1}}).Decode(&buildInfo); err != nil {
+		client.Disconnect(context.Background())
+		return nil, fmt.Errorf("failed to query server version: %w", err)
+	}
+
+	return &Client{
+		client:        client,
+		collection:    client.Database(cfg.Connection.Database).Collection(cfg.MongoSpecific.Collection),
+		pool:          monitor,
+		serverVersion: buildInfo.Version,
+	}, nil
+}
+
+// Collection 测试集合
+func (c *Client) Collection() *mongo.Collection {
+	return c.collection
+}
+
+// Ping 检查与主节点的连通性
+func (c *Client) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx, readpref.Primary())
+}
+
+// ServerVersion 服务端版本
+func (c *Client) ServerVersion() string {
+	return c.serverVersion
+}
+
+// Stats 获取连接池统计
+func (c *Client) Stats() PoolStats {
+	return c.pool.snapshot()
+}
+
+// Close 断开连接
+func (c *Client) Close() error {
+	return c.client.Disconnect(context.Background())
+}
@@ -0,0 +1,94 @@
+package operations
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"abc-runner/app/adapters/mongodb/config"
+	"abc-runner/app/core/utils"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// DocumentBuilder 按模板生成文档、更新文档与聚合管道
+// 模板为Extended JSON（relaxed格式），渲染负载模板占位符后解析为BSON
+type DocumentBuilder struct {
+	keyField string
+	document *utils.PayloadTemplate
+	update   *utils.PayloadTemplate
+	pipeline string
+}
+
+// NewDocumentBuilder 解析配置中的模板
+func NewDocumentBuilder(cfg *config.MongoConfig) (*DocumentBuilder, error) {
+	specific := cfg.MongoSpecific
+	document, err := specific.Document.Compile()
+	if err != nil {
+		return nil, fmt.Errorf("invalid document template: %w", err)
+	}
+	update, err := utils.ParsePayloadTemplate(specific.Update, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid update template: %w", err)
+	}
+	return &DocumentBuilder{
+		keyField: specific.KeyField,
+		document: document,
+		update:   update,
+		pipeline: cfg.GetPipeline(),
+	}, nil
+}
+
+// Document 生成第seq个文档，键字段设为key（覆盖模板中的同名字段）
+func (b *DocumentBuilder) Document(seq, key int) (bson.D, error) {
+	var document bson.D
+	if err := bson.UnmarshalExtJSON([]byte(b.document.Render(seq)), false, &document); err != nil {
+		return nil, fmt.Errorf("document template is not valid Extended JSON: %w", err)
+	}
+	for i, element := range document {
+		if element.Key == b.keyField {
+			document = append(document[:i], document[i+1:]...)
+			break
+		}
+	}
+	return append(bson.D{{Key: b.keyField, Value: key}}, document...), nil
+}
+
+// Update 生成第seq个更新文档
+func (b *DocumentBuilder) Update(seq int) (bson.D, error) {
+	var update bson.D
+	if err := bson.UnmarshalExtJSON([]byte(b.update.Render(seq)), false, &update); err != nil {
+		return nil, fmt.Errorf("update template is not valid Extended JSON: %w", err)
+	}
+	return update, nil
+}
+
+// Pipeline 生成以key为起点的聚合管道
+func (b *DocumentBuilder) Pipeline(key int) (bson.A, error) {
+	// Extended JSON的顶层须为文档，将管道数组包在一个字段中解析
+	text := `{"pipeline": ` + strings.ReplaceAll(b.pipeline, config.KeyPlaceholder, strconv.Itoa(key)) + `}`
+	var wrapper struct {
+		Pipeline bson.A `bson:"pipeline"`
+	}
+	if err := bson.UnmarshalExtJSON([]byte(text), false, &wrapper); err != nil {
+		return nil, fmt.Errorf("pipeline is not a valid Extended JSON array: %w", err)
+	}
+	return wrapper.Pipeline, nil
+}
+
+// Filter 按键查找的过滤条件
+func (b *DocumentBuilder) Filter(key int) bson.D {
+	return bson.D{{Key: b.keyField, Value: key}}
+}
+
+// Check 各渲染一次文档、更新文档与聚合管道，确认模板能生成合法的Extended JSON
+func (b *DocumentBuilder) Check() error {
+	if _, err := b.Document(0, 0); err != nil {
+		return err
+	}
+	if _, err := b.Update(0); err != nil {
+		return err
+	}
+	_, err := b.Pipeline(0)
+	return err
+}
@@ -0,0 +1,177 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"abc-runner/app/adapters/mongodb/config"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Collection 执行器使用的集合操作，由*mongo.Collection实现
+type Collection interface {
+	FindOne(ctx context.Context, filter interface{}, opts ...options.Lister[options.FindOneOptions]) *mongo.SingleResult
+	InsertOne(ctx context.Context, document interface{}, opts ...options.Lister[options.InsertOneOptions]) (*mongo.InsertOneResult, error)
+	InsertMany(ctx context.Context, documents interface{}, opts ...options.Lister[options.InsertManyOptions]) (*mongo.InsertManyResult, error)
+	UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...options.Lister[options.UpdateOneOptions]) (*mongo.UpdateResult, error)
+	Aggregate(ctx context.Context, pipeline interface{}, opts ...options.Lister[options.AggregateOptions]) (*mongo.Cursor, error)
+	EstimatedDocumentCount(ctx context.Context, opts ...options.Lister[options.EstimatedDocumentCountOptions]) (int64, error)
+}
+
+var _ Collection = (*mongo.Collection)(nil)
+
+// MongoExecutor MongoDB操作执行器
+type MongoExecutor struct {
+	collection Collection
+	builder    *DocumentBuilder
+	tracker    *metrics.OperationTypeTracker
+}
+
+// NewMongoExecutor 创建MongoDB操作执行器
+func NewMongoExecutor(collection Collection, builder *DocumentBuilder) *MongoExecutor {
+	return &MongoExecutor{
+		collection: collection,
+		builder:    builder,
+		tracker:    newOperationTracker(),
+	}
+}
+
+// ExecuteOperation 执行MongoDB操作
+// 文档渲染不计入延迟；find未命中视为成功，返回0个文档
+func (e *MongoExecutor) ExecuteOperation(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	key, ok := operation.Params["key"].(int)
+	if !ok {
+		return nil, fmt.Errorf("missing key for %s operation", operation.Type)
+	}
+	jobID, _ := operation.Params["job_id"].(int)
+
+	// run 返回文档数与读取的BSON字节数，written为请求中的BSON字节数
+	var run func() (int64, int64, error)
+	var written int64
+	switch operation.Type {
+	case config.OperationFind:
+		filter := e.builder.Filter(key)
+		written = bsonSize(filter)
+		run = func() (int64, int64, error) { return e.find(ctx, filter) }
+	case config.OperationInsert:
+		document, err := e.builder.Document(jobID, key)
+		if err != nil {
+			return nil, err
+		}
+		written = bsonSize(document)
+		run = func() (int64, int64, error) { return e.insert(ctx, document) }
+	case config.OperationUpdate:
+		update, err := e.builder.Update(jobID)
+		if err != nil {
+			return nil, err
+		}
+		filter := e.builder.Filter(key)
+		written = bsonSize(filter, update)
+		run = func() (int64, int64, error) { return e.update(ctx, filter, update) }
+	case config.OperationAggregate:
+		pipeline, err := e.builder.Pipeline(key)
+		if err != nil {
+			return nil, err
+		}
+		written = bsonSize(pipeline...)
+		run = func() (int64, int64, error) { return e.aggregate(ctx, pipeline) }
+	default:
+		return nil, fmt.Errorf("unsupported operation type: %s", operation.Type)
+	}
+
+	startTime := time.Now()
+	documents, read, err := run()
+	duration := time.Since(startTime)
+	e.tracker.Record(operation.Type, documents, false, duration, err)
+	if err != nil {
+		err = fmt.Errorf("%s failed: %w", operation.Type, err)
+	}
+
+	result := &interfaces.OperationResult{
+		Success:      err == nil,
+		Duration:     duration,
+		IsRead:       operation.Type == config.OperationFind || operation.Type == config.OperationAggregate,
+		Error:        err,
+		Value:        documents,
+		BytesRead:    read,
+		BytesWritten: written,
+		Metadata: map[string]interface{}{
+			"protocol":       "mongodb",
+			"operation_type": operation.Type,
+			"key":            key,
+			"documents":      documents,
+		},
+	}
+	for k, v := range operation.Metadata {
+		result.Metadata[k] = v
+	}
+	return result, err
+}
+
+// find 按键查找单个文档
+func (e *MongoExecutor) find(ctx context.Context, filter interface{}) (int64, int64, error) {
+	raw, err := e.collection.FindOne(ctx, filter).Raw()
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	return 1, int64(len(raw)), nil
+}
+
+// insert 插入单个文档
+func (e *MongoExecutor) insert(ctx context.Context, document interface{}) (int64, int64, error) {
+	if _, err := e.collection.InsertOne(ctx, document); err != nil {
+		return 0, 0, err
+	}
+	return 1, 0, nil
+}
+
+// update 按键更新单个文档，返回匹配的文档数
+func (e *MongoExecutor) update(ctx context.Context, filter, update interface{}) (int64, int64, error) {
+	result, err := e.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return 0, 0, err
+	}
+	return result.MatchedCount, 0, nil
+}
+
+// aggregate 执行聚合管道并读完结果，返回结果文档数与字节数
+func (e *MongoExecutor) aggregate(ctx context.Context, pipeline interface{}) (int64, int64, error) {
+	cursor, err := e.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var count, size int64
+	for cursor.Next(ctx) {
+		count++
+		size += int64(len(cursor.Current))
+	}
+	return count, size, cursor.Err()
+}
+
+// bsonSize 计算文档编码为BSON后的总字节数，无法编码的值不计入
+func bsonSize(documents ...interface{}) int64 {
+	var size int64
+	for _, document := range documents {
+		if data, err := bson.Marshal(document); err == nil {
+			size += int64(len(data))
+		}
+	}
+	return size
+}
+
+// OperationStats 获取按操作类型的统计
+func (e *MongoExecutor) OperationStats() []OperationStats {
+	return e.tracker.Stats()
+}
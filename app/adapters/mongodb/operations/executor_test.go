@@ -0,0 +1,320 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"abc-runner/app/adapters/mongodb/config"
+	"abc-runner/app/adapters/mongodb/connection"
+	"abc-runner/app/core/utils"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// fakeCollection 记录收到的请求的测试集合
+// 过滤键大于等于documents的find未命中；err非nil时所有操作返回该错误
+type fakeCollection struct {
+	mutex     sync.Mutex
+	documents int
+	count     int64
+	inserted  []bson.D
+	batches   []int
+	filters   []interface{}
+	pipelines []interface{}
+	err       error
+}
+
+func (c *fakeCollection) FindOne(ctx context.Context, filter interface{}, opts ...options.Lister[options.FindOneOptions]) *mongo.SingleResult {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.filters = append(c.filters, filter)
+	if c.err != nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, c.err, nil)
+	}
+	if filter.(bson.D)[0].Value.(int) >= c.documents {
+		return mongo.NewSingleResultFromDocument(bson.D{}, mongo.ErrNoDocuments, nil)
+	}
+	return mongo.NewSingleResultFromDocument(filter, nil, nil)
+}
+
+func (c *fakeCollection) InsertOne(ctx context.Context, document interface{}, opts ...options.Lister[options.InsertOneOptions]) (*mongo.InsertOneResult, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.err != nil {
+		return nil, c.err
+	}
+	c.inserted = append(c.inserted, document.(bson.D))
+	return &mongo.InsertOneResult{}, nil
+}
+
+func (c *fakeCollection) InsertMany(ctx context.Context, documents interface{}, opts ...options.Lister[options.InsertManyOptions]) (*mongo.InsertManyResult, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	batch := documents.([]interface{})
+	c.batches = append(c.batches, len(batch))
+	for _, document := range batch {
+		c.inserted = append(c.inserted, document.(bson.D))
+	}
+	return &mongo.InsertManyResult{}, nil
+}
+
+func (c *fakeCollection) UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...options.Lister[options.UpdateOneOptions]) (*mongo.UpdateResult, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.err != nil {
+		return nil, c.err
+	}
+	c.filters = append(c.filters, filter)
+	return &mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1}, nil
+}
+
+func (c *fakeCollection) Aggregate(ctx context.Context, pipeline interface{}, opts ...options.Lister[options.AggregateOptions]) (*mongo.Cursor, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.err != nil {
+		return nil, c.err
+	}
+	c.pipelines = append(c.pipelines, pipeline)
+	return mongo.NewCursorFromDocuments([]interface{}{bson.D{{Key: "count", Value: 100}}}, nil, nil)
+}
+
+func (c *fakeCollection) EstimatedDocumentCount(ctx context.Context, opts ...options.Lister[options.EstimatedDocumentCountOptions]) (int64, error) {
+	return c.count, nil
+}
+
+func testConfig(testCase string) *config.MongoConfig {
+	cfg := config.NewDefaultMongoConfig()
+	cfg.BenchMark.TestCase = testCase
+	cfg.MongoSpecific.Documents = 100
+	return cfg
+}
+
+func newTestBuilder(t *testing.T, cfg *config.MongoConfig) *DocumentBuilder {
+	t.Helper()
+	builder, err := NewDocumentBuilder(cfg)
+	if err != nil {
+		t.Fatalf("failed to create document builder: %v", err)
+	}
+	if err := builder.Check(); err != nil {
+		t.Fatalf("default templates should render: %v", err)
+	}
+	return builder
+}
+
+func TestDocumentBuilder(t *testing.T) {
+	cfg := testConfig(config.OperationInsert)
+	cfg.MongoSpecific.Document.Template = `{"key": "ignored", "n": {{seq}}, "at": {"$date": "2024-01-01T00:00:00Z"}}`
+	builder := newTestBuilder(t, cfg)
+
+	document, err := builder.Document(7, 42)
+	if err != nil {
+		t.Fatalf("failed to build document: %v", err)
+	}
+	// 键字段放在首位并覆盖模板中的同名字段；Extended JSON类型按relaxed格式解析
+	if len(document) != 3 || document[0].Key != "key" || document[0].Value != 42 {
+		t.Fatalf("unexpected document: %v", document)
+	}
+	if document[1].Value != int32(7) {
+		t.Errorf("expected n to be rendered from seq, got %v (%T)", document[1].Value, document[1].Value)
+	}
+	if _, ok := document[2].Value.(bson.DateTime); !ok {
+		t.Errorf("expected $date to decode as a BSON date, got %T", document[2].Value)
+	}
+
+	pipeline, err := builder.Pipeline(5)
+	if err != nil {
+		t.Fatalf("failed to build pipeline: %v", err)
+	}
+	match := pipeline[0].(bson.D)[0].Value.(bson.D)[0]
+	if len(pipeline) != 4 || match.Key != "key" || match.Value.(bson.D)[0].Value != int32(5) {
+		t.Errorf("unexpected default pipeline: %v", pipeline)
+	}
+
+	cfg.MongoSpecific.KeyField = "_id"
+	cfg.MongoSpecific.Pipeline = `{"$match": {}}`
+	builder, err = NewDocumentBuilder(cfg)
+	if err != nil {
+		t.Fatalf("failed to create document builder: %v", err)
+	}
+	if document, _ := builder.Document(0, 3); document[0].Key != "_id" || document[0].Value != 3 {
+		t.Errorf("expected _id to carry the key, got %v", document)
+	}
+	if err := builder.Check(); err == nil || !strings.Contains(err.Error(), "pipeline") {
+		t.Errorf("expected a pipeline that is not an array to be rejected, got %v", err)
+	}
+
+	cfg.MongoSpecific.Pipeline = ""
+	cfg.MongoSpecific.Document.Template = `{"name": {{name}}}`
+	builder, _ = NewDocumentBuilder(cfg)
+	if err := builder.Check(); err == nil || !strings.Contains(err.Error(), "document template") {
+		t.Errorf("expected an unquoted string placeholder to be rejected, got %v", err)
+	}
+}
+
+func TestOperationFactoryKeys(t *testing.T) {
+	cfg := testConfig(config.TestCaseMixed)
+	cfg.MongoSpecific.KeyDistribution = utils.KeyDistributionConfig{Type: utils.DistributionZipfian}
+	factory := NewOperationFactory(cfg)
+
+	counts := make(map[string]int)
+	for i := 0; i < 10000; i++ {
+		operation := factory.CreateOperation(i, &cfg.BenchMark)
+		counts[operation.Type]++
+		key := operation.Params["key"].(int)
+		if operation.Type == config.OperationInsert {
+			if key != cfg.MongoSpecific.Documents+i {
+				t.Fatalf("expected insert %d to use a new key, got %d", i, key)
+			}
+		} else if key < 0 || key >= cfg.MongoSpecific.Documents {
+			t.Fatalf("key %d out of range for %s", key, operation.Type)
+		}
+	}
+	if counts[config.OperationFind] < 6500 || counts[config.OperationFind] > 7500 || len(counts) != 4 {
+		t.Errorf("unexpected mix: %v", counts)
+	}
+
+	cfg.MongoSpecific.Mix = config.MixConfig{Update: 1}
+	factory = NewOperationFactory(cfg)
+	for i := 0; i < 100; i++ {
+		if operation := factory.CreateOperation(i, &cfg.BenchMark); operation.Type != config.OperationUpdate {
+			t.Fatalf("expected only updates, got %s", operation.Type)
+		}
+	}
+}
+
+func TestMongoExecutorOperations(t *testing.T) {
+	cfg := testConfig(config.TestCaseMixed)
+	collection := &fakeCollection{documents: cfg.MongoSpecific.Documents}
+	executor := NewMongoExecutor(collection, newTestBuilder(t, cfg))
+	factory := NewOperationFactory(cfg)
+
+	for i := 0; i < 200; i++ {
+		operation := factory.CreateOperation(i, &cfg.BenchMark)
+		result, err := executor.ExecuteOperation(context.Background(), operation)
+		if err != nil || !result.Success || result.Value.(int64) != 1 {
+			t.Fatalf("operation %s failed: %v", operation.Type, err)
+		}
+		isRead := operation.Type == config.OperationFind || operation.Type == config.OperationAggregate
+		if result.IsRead != isRead {
+			t.Errorf("unexpected IsRead for %s", operation.Type)
+		}
+		// 请求文档按BSON大小计入写出，读操作计入返回文档的大小
+		if result.BytesWritten == 0 || isRead && result.BytesRead == 0 {
+			t.Errorf("expected transferred bytes for %s, got written %d, read %d", operation.Type, result.BytesWritten, result.BytesRead)
+		}
+	}
+
+	stats := executor.OperationStats()
+	if len(stats) != 4 {
+		t.Fatalf("expected stats for 4 operation types, got %+v", stats)
+	}
+	var total int64
+	for _, s := range stats {
+		total += s.Count
+		if s.Count != s.Volume || s.Errors != 0 {
+			t.Errorf("unexpected stats for %s: %+v", s.Type, s)
+		}
+	}
+	if total != 200 || int64(len(collection.inserted)) != stats[2].Count {
+		t.Errorf("expected 200 operations and one document per insert, got %d and %d", total, len(collection.inserted))
+	}
+
+	// 未命中的find视为成功，返回0个文档
+	miss := factory.CreateOperation(0, &cfg.BenchMark)
+	miss.Type = config.OperationFind
+	miss.Params["key"] = cfg.MongoSpecific.Documents
+	if result, err := executor.ExecuteOperation(context.Background(), miss); err != nil || result.Value.(int64) != 0 {
+		t.Errorf("expected a miss to succeed with 0 documents: %v", err)
+	}
+}
+
+func TestMongoExecutorErrorCodes(t *testing.T) {
+	cfg := testConfig(config.OperationUpdate)
+	collection := &fakeCollection{documents: cfg.MongoSpecific.Documents}
+	executor := NewMongoExecutor(collection, newTestBuilder(t, cfg))
+	operation := NewOperationFactory(cfg).CreateOperation(0, &cfg.BenchMark)
+
+	for _, err := range []error{
+		mongo.CommandError{Code: 13, Message: "not authorized"},
+		mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000, Message: "duplicate key"}}},
+		context.DeadlineExceeded,
+		errors.New("boom"),
+	} {
+		collection.err = err
+		if result, err := executor.ExecuteOperation(context.Background(), operation); err == nil || result.Success {
+			t.Fatal("expected the update to fail")
+		}
+	}
+
+	stats := executor.OperationStats()
+	want := map[string]int64{"13": 1, "11000": 1, "timeout": 1, "client": 1}
+	if len(stats) != 1 || stats[0].Errors != 4 || len(stats[0].ErrorCodes) != len(want) {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	for code, n := range want {
+		if stats[0].ErrorCodes[code] != n {
+			t.Errorf("expected %d error(s) with code %s, got %v", n, code, stats[0].ErrorCodes)
+		}
+	}
+}
+
+func TestSeed(t *testing.T) {
+	cfg := testConfig(config.OperationFind)
+	collection := &fakeCollection{}
+	builder := newTestBuilder(t, cfg)
+
+	seeded, err := Seed(context.Background(), collection, builder, 2500)
+	if err != nil || !seeded {
+		t.Fatalf("seed failed: seeded=%v err=%v", seeded, err)
+	}
+	if len(collection.batches) != 3 || collection.batches[2] != 500 {
+		t.Errorf("expected batches of 1000, 1000 and 500, got %v", collection.batches)
+	}
+	if last := collection.inserted[2499]; last[0].Value != 2499 {
+		t.Errorf("expected seeded keys 0..2499, got last key %v", last[0].Value)
+	}
+
+	collection.count = 1
+	if seeded, err := Seed(context.Background(), collection, builder, 2500); err != nil || seeded {
+		t.Errorf("expected seed to skip a non-empty collection: seeded=%v err=%v", seeded, err)
+	}
+}
+
+func TestNewClientOptions(t *testing.T) {
+	cfg := testConfig(config.OperationFind)
+	cfg.Connection.Username = "bench"
+	cfg.BenchMark.Parallels = 32
+	cfg.MongoSpecific.ReadPreference = "secondaryPreferred"
+	cfg.MongoSpecific.WriteConcern = config.WriteConcernConfig{W: "majority", Journal: true}
+
+	opts, err := connection.NewClientOptions(cfg)
+	if err != nil {
+		t.Fatalf("failed to build client options: %v", err)
+	}
+	if opts.Auth.AuthSource != "admin" || *opts.MaxPoolSize != 32 || opts.Hosts[0] != "localhost:27017" {
+		t.Errorf("unexpected client options: auth=%+v pool=%d hosts=%v", opts.Auth, *opts.MaxPoolSize, opts.Hosts)
+	}
+	if opts.ReadPreference.Mode().String() != "secondaryPreferred" {
+		t.Errorf("unexpected read preference: %v", opts.ReadPreference.Mode())
+	}
+	if opts.WriteConcern.W != "majority" || !*opts.WriteConcern.Journal {
+		t.Errorf("unexpected write concern: %+v", opts.WriteConcern)
+	}
+
+	if wc := connection.NewWriteConcern(config.WriteConcernConfig{W: "2"}); wc.W != 2 || wc.Journal != nil {
+		t.Errorf("unexpected numeric write concern: %+v", wc)
+	}
+	if connection.NewWriteConcern(config.WriteConcernConfig{}) != nil {
+		t.Error("expected an unset write concern to use the server default")
+	}
+
+	cfg.MongoSpecific.WriteConcern = config.WriteConcernConfig{W: "0", Journal: true}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected an unacknowledged journaled write concern to be rejected")
+	}
+}
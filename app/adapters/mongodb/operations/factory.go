@@ -0,0 +1,101 @@
+package operations
+
+import (
+	"math/rand/v2"
+
+	"abc-runner/app/adapters/mongodb/config"
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/utils"
+)
+
+// OperationFactory MongoDB操作工厂
+// 只选择操作类型与键，文档在执行器中渲染，使模板渲染分摊到各工作协程
+type OperationFactory struct {
+	testCase  string
+	documents int
+	keys      utils.KeyDistribution
+	mix       []weightedOperation
+	mixTotal  int
+}
+
+// weightedOperation mixed用例中的操作类型与权重
+type weightedOperation struct {
+	operationType string
+	weight        int
+}
+
+// NewOperationFactory 创建MongoDB操作工厂
+func NewOperationFactory(cfg *config.MongoConfig) *OperationFactory {
+	specific := cfg.MongoSpecific
+	factory := &OperationFactory{
+		testCase:  cfg.BenchMark.TestCase,
+		documents: specific.Documents,
+	}
+	factory.keys, _ = utils.NewKeyDistribution(specific.KeyDistribution, specific.Documents)
+	for _, entry := range []weightedOperation{
+		{config.OperationFind, specific.Mix.Find},
+		{config.OperationInsert, specific.Mix.Insert},
+		{config.OperationUpdate, specific.Mix.Update},
+		{config.OperationAggregate, specific.Mix.Aggregate},
+	} {
+		if entry.weight > 0 {
+			factory.mix = append(factory.mix, entry)
+			factory.mixTotal += entry.weight
+		}
+	}
+	return factory
+}
+
+// CreateOperation 创建操作：find/update/aggregate的键按键分布取自[0, documents)，
+// insert的键从documents开始按操作编号递增，不与已有文档冲突
+func (f *OperationFactory) CreateOperation(jobID int, cfg execution.BenchmarkConfig) interfaces.Operation {
+	operationType := f.pickOperation()
+	key := f.documents + jobID
+	if operationType != config.OperationInsert {
+		key = f.nextKey(jobID)
+	}
+	return interfaces.Operation{
+		Type: operationType,
+		Params: map[string]interface{}{
+			"job_id": jobID,
+			"key":    key,
+		},
+		Metadata: map[string]string{
+			"operation_type": operationType,
+		},
+	}
+}
+
+// nextKey 按键分布选择已有文档的键
+func (f *OperationFactory) nextKey(jobID int) int {
+	if f.keys == nil {
+		return rand.IntN(f.documents)
+	}
+	return f.keys.Next(jobID)
+}
+
+// pickOperation 选择操作类型，mixed用例按权重随机
+func (f *OperationFactory) pickOperation() string {
+	if f.testCase != config.TestCaseMixed {
+		return f.testCase
+	}
+	n := rand.IntN(f.mixTotal)
+	for _, entry := range f.mix {
+		if n < entry.weight {
+			return entry.operationType
+		}
+		n -= entry.weight
+	}
+	return f.mix[len(f.mix)-1].operationType
+}
+
+// GetOperationType 获取操作类型
+func (f *OperationFactory) GetOperationType() string {
+	return f.testCase
+}
+
+// GetSupportedOperations 获取支持的操作类型
+func (f *OperationFactory) GetSupportedOperations() []string {
+	return []string{config.OperationFind, config.OperationInsert, config.OperationUpdate, config.OperationAggregate}
+}
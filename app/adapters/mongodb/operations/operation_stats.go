@@ -0,0 +1,44 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"abc-runner/app/core/metrics"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// OperationStats 单个操作类型的统计，Volume为返回、写入或匹配的文档数（JSON字段"documents"），
+// 错误码为服务端错误码，超时记为"timeout"，网络错误记为"network"
+type OperationStats = metrics.OperationTypeStats
+
+// newOperationTracker 创建按操作类型的统计器
+func newOperationTracker() *metrics.OperationTypeTracker {
+	return metrics.NewOperationTypeTracker(errorCode, "documents", "")
+}
+
+// errorCode 错误分类：服务端错误取错误码，其余按超时、网络与客户端错误归类
+func errorCode(err error) string {
+	var commandErr mongo.CommandError
+	if errors.As(err, &commandErr) && commandErr.Code != 0 {
+		return strconv.Itoa(int(commandErr.Code))
+	}
+	var writeErr mongo.WriteException
+	if errors.As(err, &writeErr) {
+		if len(writeErr.WriteErrors) > 0 {
+			return strconv.Itoa(writeErr.WriteErrors[0].Code)
+		}
+		if writeErr.WriteConcernError != nil {
+			return strconv.Itoa(writeErr.WriteConcernError.Code)
+		}
+	}
+	switch {
+	case mongo.IsTimeout(err) || errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case mongo.IsNetworkError(err):
+		return "network"
+	}
+	return "client"
+}
@@ -0,0 +1,52 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// seedBatchDocuments 填充数据时每批插入的文档数
+const seedBatchDocuments = 1000
+
+// Seed 在集合为空时填充documents个文档，键依次为0..documents-1；返回是否填充了数据
+func Seed(ctx context.Context, collection Collection, builder *DocumentBuilder, documents int) (bool, error) {
+	count, err := collection.EstimatedDocumentCount(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to count documents: %w", err)
+	}
+	if count > 0 {
+		return false, nil
+	}
+
+	for seeded := 0; seeded < documents; seeded += seedBatchDocuments {
+		n := min(seedBatchDocuments, documents-seeded)
+		batch := make([]interface{}, n)
+		for i := range batch {
+			document, err := builder.Document(seeded+i, seeded+i)
+			if err != nil {
+				return true, err
+			}
+			batch[i] = document
+		}
+		if _, err := collection.InsertMany(ctx, batch, options.InsertMany().SetOrdered(false)); err != nil {
+			return true, fmt.Errorf("failed to seed documents: %w", err)
+		}
+	}
+	return true, nil
+}
+
+// EnsureIndex 在键字段上创建升序索引，索引已存在时为空操作；键字段为_id时跳过
+func EnsureIndex(ctx context.Context, collection *mongo.Collection, keyField string) error {
+	if keyField == "_id" {
+		return nil
+	}
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: keyField, Value: 1}}})
+	if err != nil {
+		return fmt.Errorf("failed to create index on %s: %w", keyField, err)
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"abc-runner/app/adapters/mysql/config"
+	"abc-runner/app/adapters/mysql/connection"
+	"abc-runner/app/adapters/mysql/operations"
+	"abc-runner/app/core/interfaces"
+)
+
+// MySQLAdapter MySQL协议适配器 - 遵循统一架构模式
+// 职责：连接池管理、状态维护、健康检查
+type MySQLAdapter struct {
+	config           *config.MySQLConfig
+	pool             *connection.Pool
+	mysqlOperations  *operations.MySQLExecutor
+	metricsCollector interfaces.DefaultMetricsCollector
+	mu               sync.RWMutex
+	isConnected      bool
+	tableCreated     bool
+
+	// 统计信息
+	totalOperations  int64
+	failedOperations int64
+}
+
+// NewMySQLAdapter 创建MySQL适配器
+func NewMySQLAdapter(metricsCollector interfaces.DefaultMetricsCollector) *MySQLAdapter {
+	return &MySQLAdapter{
+		metricsCollector: metricsCollector,
+	}
+}
+
+// Connect 建立连接池，配置了setup时创建并填充测试表
+func (m *MySQLAdapter) Connect(ctx context.Context, cfg interfaces.Config) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mysqlConfig, ok := cfg.(*config.MySQLConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type for MySQL adapter: expected *config.MySQLConfig, got %T", cfg)
+	}
+
+	if err := mysqlConfig.Validate(); err != nil {
+		return fmt.Errorf("config validation failed: %w", err)
+	}
+	m.config = mysqlConfig
+
+	pool, err := connection.NewPool(ctx, mysqlConfig)
+	if err != nil {
+		return err
+	}
+
+	if mysqlConfig.MySQLSpecific.Setup {
+		schema := operations.NewSchema(mysqlConfig.MySQLSpecific.Schema)
+		created, err := schema.Setup(ctx, pool.DB(), mysqlConfig.MySQLSpecific.Rows)
+		m.tableCreated = created
+		if err != nil {
+			pool.Close()
+			return err
+		}
+	}
+
+	executor, err := operations.NewMySQLExecutor(ctx, pool.DB(), mysqlConfig)
+	if err != nil {
+		pool.Close()
+		return err
+	}
+
+	m.pool = pool
+	m.mysqlOperations = executor
+	m.isConnected = true
+	return nil
+}
+
+// Execute 执行操作 - 使用执行器处理
+func (m *MySQLAdapter) Execute(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	if !m.isConnected {
+		return nil, fmt.Errorf("adapter not connected")
+	}
+
+	atomic.AddInt64(&m.totalOperations, 1)
+	result, err := m.mysqlOperations.ExecuteOperation(ctx, operation)
+	if err != nil {
+		atomic.AddInt64(&m.failedOperations, 1)
+	}
+	return result, err
+}
+
+// Close 关闭预编译语句与连接池
+func (m *MySQLAdapter) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.mysqlOperations != nil {
+		m.mysqlOperations.Close()
+	}
+	if m.pool != nil {
+		m.pool.Close()
+		m.pool = nil
+	}
+	m.isConnected = false
+	return nil
+}
+
+// GetProtocolMetrics 获取协议特定指标
+func (m *MySQLAdapter) GetProtocolMetrics() map[string]interface{} {
+	metrics := map[string]interface{}{
+		"protocol":          "mysql",
+		"total_operations":  atomic.LoadInt64(&m.totalOperations),
+		"failed_operations": atomic.LoadInt64(&m.failedOperations),
+	}
+
+	if m.config != nil {
+		metrics["prepared"] = m.config.MySQLSpecific.Prepared
+		metrics["read_percent"] = m.config.BenchMark.GetReadPercent()
+		metrics["schema"] = m.config.MySQLSpecific.Schema
+	}
+	if m.pool != nil {
+		metrics["server_version"] = m.pool.ServerVersion()
+		metrics["pool_stats"] = m.pool.Stats()
+	}
+	if stats := m.GetQueryStats(); stats != nil {
+		metrics["query_stats"] = stats
+	}
+
+	return metrics
+}
+
+// GetQueryStats 获取按操作类型的统计，未连接时返回nil
+func (m *MySQLAdapter) GetQueryStats() []operations.QueryStats {
+	if m.mysqlOperations == nil {
+		return nil
+	}
+	return m.mysqlOperations.QueryStats()
+}
+
+// GetPoolStats 获取连接池统计，未连接时返回nil
+func (m *MySQLAdapter) GetPoolStats() *connection.PoolStats {
+	if m.pool == nil {
+		return nil
+	}
+	stats := m.pool.Stats()
+	return &stats
+}
+
+// ServerVersion 服务端版本，未连接时为空
+func (m *MySQLAdapter) ServerVersion() string {
+	if m.pool == nil {
+		return ""
+	}
+	return m.pool.ServerVersion()
+}
+
+// TableCreated 本次运行是否由setup新建了测试表
+func (m *MySQLAdapter) TableCreated() bool {
+	return m.tableCreated
+}
+
+// HealthCheck 健康检查：对连接池执行Ping
+func (m *MySQLAdapter) HealthCheck(ctx context.Context) error {
+	if !m.isConnected {
+		return fmt.Errorf("adapter not connected")
+	}
+
+	if err := m.pool.DB().PingContext(ctx); err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	return nil
+}
+
+// GetProtocolName 获取协议名称
+func (m *MySQLAdapter) GetProtocolName() string {
+	return "mysql"
+}
+
+// GetMetricsCollector 获取指标收集器
+func (m *MySQLAdapter) GetMetricsCollector() interfaces.DefaultMetricsCollector {
+	return m.metricsCollector
+}
@@ -0,0 +1,328 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+)
+
+// 测试用例
+const (
+	TestCaseRead   = "read"   // 按主键点查
+	TestCaseWrite  = "write"  // 按主键更新整行payload
+	TestCaseInsert = "insert" // 插入新行
+	TestCaseMixed  = "mixed"  // 按read_percent混合点查与更新
+)
+
+// DefaultTable 默认测试表
+const DefaultTable = "abc_bench"
+
+// MySQLConfig MySQL协议配置
+type MySQLConfig struct {
+	Protocol      string              `yaml:"protocol" json:"protocol"`
+	Connection    ConnectionConfig    `yaml:"connection" json:"connection"`
+	BenchMark     BenchmarkConfig     `yaml:"benchmark" json:"benchmark"`
+	MySQLSpecific MySQLSpecificConfig `yaml:"mysql_specific" json:"mysql_specific"`
+}
+
+// ConnectionConfig MySQL连接配置
+type ConnectionConfig struct {
+	Address         string        `yaml:"address" json:"address"`
+	Port            int           `yaml:"port" json:"port"`
+	User            string        `yaml:"user" json:"user"`
+	Password        string        `yaml:"password" json:"password"`
+	Database        string        `yaml:"database" json:"database"`
+	TLS             string        `yaml:"tls" json:"tls"`                             // false、true、skip-verify或preferred
+	PoolSize        int           `yaml:"pool_size" json:"pool_size"`                 // 最大打开连接数，0表示与并发数相同
+	MaxIdle         int           `yaml:"max_idle" json:"max_idle"`                   // 最大空闲连接数，0表示与pool_size相同
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" json:"conn_max_lifetime"` // 连接最长存活时间，0表示不限
+	Timeout         time.Duration `yaml:"timeout" json:"timeout"`                     // 建连与读写超时
+}
+
+// BenchmarkConfig MySQL基准测试配置
+type BenchmarkConfig struct {
+	Total       int           `yaml:"total" json:"total"`
+	Parallels   int           `yaml:"parallels" json:"parallels"`
+	TestCase    string        `yaml:"test_case" json:"test_case"`       // read、write、insert或mixed
+	ReadPercent int           `yaml:"read_percent" json:"read_percent"` // mixed用例中点查的百分比
+	Duration    time.Duration `yaml:"duration" json:"duration"`
+}
+
+// MySQLSpecificConfig MySQL特定配置
+type MySQLSpecificConfig struct {
+	Rows     int  `yaml:"rows" json:"rows"`         // 点查与更新随机访问的id范围[1, rows]
+	Setup    bool `yaml:"setup" json:"setup"`       // 运行前创建表并填充rows行（表已存在时跳过）
+	Prepared bool `yaml:"prepared" json:"prepared"` // 使用服务端预编译语句；否则在客户端插值后以文本协议发送
+
+	// Schema 测试表结构
+	Schema SchemaConfig `yaml:"schema" json:"schema"`
+}
+
+// SchemaConfig 测试表结构：id主键、整数列k与若干定长payload列
+type SchemaConfig struct {
+	Table          string `yaml:"table" json:"table"`
+	Engine         string `yaml:"engine" json:"engine"`                   // 存储引擎
+	PayloadColumns int    `yaml:"payload_columns" json:"payload_columns"` // payload列数
+	RowSize        int    `yaml:"row_size" json:"row_size"`               // 每行payload的总字节数，均分到各payload列
+	IndexK         bool   `yaml:"index_k" json:"index_k"`                 // 在k列上建二级索引，写入时需维护索引
+}
+
+// NewDefaultMySQLConfig 创建默认MySQL配置
+func NewDefaultMySQLConfig() *MySQLConfig {
+	return &MySQLConfig{
+		Protocol: "mysql",
+		Connection: ConnectionConfig{
+			Address:  "localhost",
+			Port:     3306,
+			User:     "root",
+			Database: "test",
+			TLS:      "false",
+			Timeout:  5 * time.Second,
+		},
+		BenchMark: BenchmarkConfig{
+			Total:       1000,
+			Parallels:   10,
+			TestCase:    TestCaseRead,
+			ReadPercent: 80,
+		},
+		MySQLSpecific: MySQLSpecificConfig{
+			Rows: 10000,
+			Schema: SchemaConfig{
+				Table:          DefaultTable,
+				Engine:         "InnoDB",
+				PayloadColumns: 1,
+				RowSize:        100,
+			},
+		},
+	}
+}
+
+// GetPoolSize 获取最大打开连接数，未配置时与并发数相同
+func (c *MySQLConfig) GetPoolSize() int {
+	if c.Connection.PoolSize > 0 {
+		return c.Connection.PoolSize
+	}
+	return c.BenchMark.Parallels
+}
+
+// GetMaxIdle 获取最大空闲连接数，未配置时与连接池大小相同
+func (c *MySQLConfig) GetMaxIdle() int {
+	if c.Connection.MaxIdle > 0 {
+		return c.Connection.MaxIdle
+	}
+	return c.GetPoolSize()
+}
+
+// GetProtocol 实现Config接口
+func (c *MySQLConfig) GetProtocol() string {
+	return c.Protocol
+}
+
+// GetConnection 实现Config接口
+func (c *MySQLConfig) GetConnection() interfaces.ConnectionConfig {
+	return &c.Connection
+}
+
+// GetBenchmark 实现Config接口
+func (c *MySQLConfig) GetBenchmark() interfaces.BenchmarkConfig {
+	return &c.BenchMark
+}
+
+// Validate 实现Config接口
+func (c *MySQLConfig) Validate() error {
+	if c.Connection.Address == "" {
+		return fmt.Errorf("connection address cannot be empty")
+	}
+	if c.Connection.Port <= 0 || c.Connection.Port > 65535 {
+		return fmt.Errorf("invalid port number: %d", c.Connection.Port)
+	}
+	if c.Connection.User == "" {
+		return fmt.Errorf("user cannot be empty")
+	}
+	if c.Connection.Database == "" {
+		return fmt.Errorf("database cannot be empty")
+	}
+	if c.Connection.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+	if c.Connection.PoolSize < 0 || c.Connection.MaxIdle < 0 || c.Connection.ConnMaxLifetime < 0 {
+		return fmt.Errorf("pool settings cannot be negative")
+	}
+	switch c.Connection.TLS {
+	case "false", "true", "skip-verify", "preferred":
+	default:
+		return fmt.Errorf("invalid tls mode: %s, valid options: false, true, skip-verify, preferred", c.Connection.TLS)
+	}
+
+	if c.BenchMark.Total <= 0 {
+		return fmt.Errorf("total operations must be greater than 0")
+	}
+	if c.BenchMark.Parallels <= 0 {
+		return fmt.Errorf("parallel connections must be greater than 0")
+	}
+	if c.BenchMark.ReadPercent < 0 || c.BenchMark.ReadPercent > 100 {
+		return fmt.Errorf("read percent must be between 0 and 100")
+	}
+
+	validTestCases := []string{TestCaseRead, TestCaseWrite, TestCaseInsert, TestCaseMixed}
+	valid := false
+	for _, testCase := range validTestCases {
+		if c.BenchMark.TestCase == testCase {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Errorf("invalid test case: %s, valid options: %s",
+			c.BenchMark.TestCase, strings.Join(validTestCases, ", "))
+	}
+
+	specific := c.MySQLSpecific
+	if specific.Rows <= 0 {
+		return fmt.Errorf("rows must be greater than 0")
+	}
+	schema := specific.Schema
+	if schema.Table == "" {
+		return fmt.Errorf("table cannot be empty")
+	}
+	if schema.Engine == "" {
+		return fmt.Errorf("engine cannot be empty")
+	}
+	if schema.PayloadColumns <= 0 {
+		return fmt.Errorf("payload columns must be greater than 0")
+	}
+	if schema.RowSize < schema.PayloadColumns {
+		return fmt.Errorf("row size must be at least one byte per payload column")
+	}
+	if schema.ColumnSize() > maxColumnSize {
+		return fmt.Errorf("row size %d is too large: each payload column is limited to %d bytes", schema.RowSize, maxColumnSize)
+	}
+
+	return nil
+}
+
+// Clone 实现Config接口
+func (c *MySQLConfig) Clone() interfaces.Config {
+	clone := *c
+	return &clone
+}
+
+// maxColumnSize 单个payload列的最大字节数（VARCHAR上限受行大小65535字节限制，取保守值）
+const maxColumnSize = 16000
+
+// ColumnSize 每个payload列的字节数，余数并入第一列
+func (s SchemaConfig) ColumnSize() int {
+	return s.RowSize/s.PayloadColumns + s.RowSize%s.PayloadColumns
+}
+
+// ColumnSizes 各payload列的字节数
+func (s SchemaConfig) ColumnSizes() []int {
+	sizes := make([]int, s.PayloadColumns)
+	for i := range sizes {
+		sizes[i] = s.RowSize / s.PayloadColumns
+	}
+	sizes[0] += s.RowSize % s.PayloadColumns
+	return sizes
+}
+
+// ConnectionConfig接口实现
+
+// GetAddresses 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetAddresses() []string {
+	return []string{net.JoinHostPort(c.Address, strconv.Itoa(c.Port))}
+}
+
+// GetCredentials 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetCredentials() map[string]string {
+	return map[string]string{
+		"user":     c.User,
+		"password": c.Password,
+		"database": c.Database,
+	}
+}
+
+// GetPoolConfig 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetPoolConfig() interfaces.PoolConfig {
+	return &PoolConfig{size: c.PoolSize, maxIdle: c.MaxIdle, lifetime: c.ConnMaxLifetime, timeout: c.Timeout}
+}
+
+// GetTimeout 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetTimeout() time.Duration {
+	return c.Timeout
+}
+
+// PoolConfig 连接池配置（映射到database/sql的连接池设置）
+type PoolConfig struct {
+	size     int
+	maxIdle  int
+	lifetime time.Duration
+	timeout  time.Duration
+}
+
+func (p *PoolConfig) GetPoolSize() int                    { return p.size }
+func (p *PoolConfig) GetMinIdle() int                     { return 0 }
+func (p *PoolConfig) GetMaxIdle() int                     { return p.maxIdle }
+func (p *PoolConfig) GetIdleTimeout() time.Duration       { return p.lifetime }
+func (p *PoolConfig) GetConnectionTimeout() time.Duration { return p.timeout }
+
+// BenchmarkConfig接口实现
+
+// GetTotal 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTotal() int {
+	return b.Total
+}
+
+// GetParallels 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetParallels() int {
+	return b.Parallels
+}
+
+// GetDataSize 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetDataSize() int {
+	return 0
+}
+
+// GetTTL 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTTL() time.Duration {
+	return 0
+}
+
+// GetReadPercent 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetReadPercent() int {
+	switch b.TestCase {
+	case TestCaseRead:
+		return 100
+	case TestCaseMixed:
+		return b.ReadPercent
+	}
+	return 0
+}
+
+// GetRandomKeys 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetRandomKeys() int {
+	return 0
+}
+
+// GetTestCase 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTestCase() string {
+	return b.TestCase
+}
+
+// GetDuration 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetDuration() time.Duration {
+	return b.Duration
+}
+
+// GetTimeout 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTimeout() time.Duration {
+	return 0
+}
+
+// GetRampUp 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetRampUp() time.Duration {
+	return 0
+}
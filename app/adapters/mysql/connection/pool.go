@@ -0,0 +1,122 @@
+package connection
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"abc-runner/app/adapters/mysql/config"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Pool 基于database/sql的MySQL连接池
+type Pool struct {
+	db            *sql.DB
+	address       string
+	serverVersion string
+}
+
+// PoolStats 连接池统计（来自sql.DBStats）
+type PoolStats struct {
+	MaxOpen           int           `json:"max_open"`
+	Open              int           `json:"open"`
+	InUse             int           `json:"in_use"`
+	Idle              int           `json:"idle"`
+	WaitCount         int64         `json:"wait_count"`          // 等待空闲连接的次数
+	WaitDuration      time.Duration `json:"wait_duration"`       // 等待空闲连接的总时间
+	MaxIdleClosed     int64         `json:"max_idle_closed"`     // 因超过max_idle关闭的连接数
+	MaxLifetimeClosed int64         `json:"max_lifetime_closed"` // 因超过conn_max_lifetime关闭的连接数
+}
+
+// NewDSN 构造go-sql-driver的DSN
+// 非预编译模式开启客户端插值，参数化语句以文本协议一次往返发送
+func NewDSN(cfg *config.MySQLConfig) string {
+	driverConfig := mysql.NewConfig()
+	driverConfig.User = cfg.Connection.User
+	driverConfig.Passwd = cfg.Connection.Password
+	driverConfig.Net = "tcp"
+	driverConfig.Addr = cfg.Connection.GetAddresses()[0]
+	driverConfig.DBName = cfg.Connection.Database
+	driverConfig.Timeout = cfg.Connection.Timeout
+	driverConfig.ReadTimeout = cfg.Connection.Timeout
+	driverConfig.WriteTimeout = cfg.Connection.Timeout
+	driverConfig.TLSConfig = cfg.Connection.TLS
+	driverConfig.InterpolateParams = !cfg.MySQLSpecific.Prepared
+	driverConfig.ConnectionAttributes = "program_name:abc-runner"
+	return driverConfig.FormatDSN()
+}
+
+// NewPool 创建连接池并预先建立全部连接，使测量不含建连耗时
+func NewPool(ctx context.Context, cfg *config.MySQLConfig) (*Pool, error) {
+	db, err := sql.Open("mysql", NewDSN(cfg))
+	if err != nil {
+		return nil, err
+	}
+	size := cfg.GetPoolSize()
+	db.SetMaxOpenConns(size)
+	db.SetMaxIdleConns(cfg.GetMaxIdle())
+	db.SetConnMaxLifetime(cfg.Connection.ConnMaxLifetime)
+
+	pool := &Pool{db: db, address: cfg.Connection.GetAddresses()[0]}
+
+	// 同时持有size个连接，迫使连接池建满
+	conns := make([]*sql.Conn, 0, size)
+	defer func() {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}()
+	for i := 0; i < size; i++ {
+		conn, err := db.Conn(ctx)
+		if err == nil {
+			err = conn.PingContext(ctx)
+		}
+		if err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to open connection %d/%d: %w", i+1, size, err)
+		}
+		conns = append(conns, conn)
+	}
+	if err := conns[0].QueryRowContext(ctx, "SELECT VERSION()").Scan(&pool.serverVersion); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to query server version: %w", err)
+	}
+	return pool, nil
+}
+
+// DB 底层的sql.DB
+func (p *Pool) DB() *sql.DB {
+	return p.db
+}
+
+// ServerVersion 服务端版本
+func (p *Pool) ServerVersion() string {
+	return p.serverVersion
+}
+
+// Address 目标地址
+func (p *Pool) Address() string {
+	return p.address
+}
+
+// Stats 获取连接池统计
+func (p *Pool) Stats() PoolStats {
+	stats := p.db.Stats()
+	return PoolStats{
+		MaxOpen:           stats.MaxOpenConnections,
+		Open:              stats.OpenConnections,
+		InUse:             stats.InUse,
+		Idle:              stats.Idle,
+		WaitCount:         stats.WaitCount,
+		WaitDuration:      stats.WaitDuration,
+		MaxIdleClosed:     stats.MaxIdleClosed,
+		MaxLifetimeClosed: stats.MaxLifetimeClosed,
+	}
+}
+
+// Close 关闭连接池
+func (p *Pool) Close() error {
+	return p.db.Close()
+}
@@ -0,0 +1,172 @@
+package operations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"abc-runner/app/adapters/mysql/config"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+)
+
+// MySQLExecutor MySQL操作执行器
+type MySQLExecutor struct {
+	db         *sql.DB
+	queries    map[string]string
+	statements map[string]*sql.Stmt // 预编译模式下按操作类型的预编译语句
+	tracker    *metrics.OperationTypeTracker
+}
+
+// NewMySQLExecutor 创建MySQL操作执行器，预编译模式下在此预编译全部语句
+func NewMySQLExecutor(ctx context.Context, db *sql.DB, cfg *config.MySQLConfig) (*MySQLExecutor, error) {
+	schema := NewSchema(cfg.MySQLSpecific.Schema)
+	executor := &MySQLExecutor{
+		db: db,
+		queries: map[string]string{
+			config.TestCaseRead:   schema.SelectSQL(),
+			config.TestCaseWrite:  schema.UpdateSQL(),
+			config.TestCaseInsert: schema.InsertSQL(1),
+		},
+		tracker: newQueryTracker(),
+	}
+	if cfg.MySQLSpecific.Prepared {
+		executor.statements = make(map[string]*sql.Stmt, len(executor.queries))
+		for operationType, query := range executor.queries {
+			stmt, err := executor.db.PrepareContext(ctx, query)
+			if err != nil {
+				executor.Close()
+				return nil, fmt.Errorf("failed to prepare %s statement: %w", operationType, err)
+			}
+			executor.statements[operationType] = stmt
+		}
+	}
+	return executor, nil
+}
+
+// ExecuteOperation 执行MySQL操作
+// 操作的延迟包含从连接池取用连接的等待时间
+func (e *MySQLExecutor) ExecuteOperation(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	args, ok := operation.Params["args"].([]interface{})
+	if _, known := e.queries[operation.Type]; !ok || !known {
+		return nil, fmt.Errorf("unsupported operation type: %s", operation.Type)
+	}
+
+	startTime := time.Now()
+	var rows, read int64
+	var err error
+	if operation.Type == config.TestCaseRead {
+		rows, read, err = e.query(ctx, operation.Type, args)
+	} else {
+		rows, err = e.exec(ctx, operation.Type, args)
+	}
+	duration := time.Since(startTime)
+	e.tracker.Record(operation.Type, rows, false, duration, err)
+	if err != nil {
+		err = fmt.Errorf("%s failed: %w", operation.Type, err)
+	}
+
+	result := &interfaces.OperationResult{
+		Success:      err == nil,
+		Duration:     duration,
+		IsRead:       operation.Type == config.TestCaseRead,
+		Error:        err,
+		Value:        rows,
+		BytesRead:    read,
+		BytesWritten: e.requestSize(operation.Type, args),
+		Metadata: map[string]interface{}{
+			"protocol":       "mysql",
+			"operation_type": operation.Type,
+			"rows":           rows,
+		},
+	}
+	for k, v := range operation.Metadata {
+		result.Metadata[k] = v
+	}
+	return result, err
+}
+
+// query 执行点查并读完结果集，返回行数与结果集的列值字节数
+func (e *MySQLExecutor) query(ctx context.Context, operationType string, args []interface{}) (int64, int64, error) {
+	var rows *sql.Rows
+	var err error
+	if stmt, ok := e.statements[operationType]; ok {
+		rows, err = stmt.QueryContext(ctx, args...)
+	} else {
+		rows, err = e.db.QueryContext(ctx, e.queries[operationType], args...)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, 0, err
+	}
+	values := make([]sql.RawBytes, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	var count, size int64
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return count, size, err
+		}
+		for _, value := range values {
+			size += int64(len(value))
+		}
+		count++
+	}
+	return count, size, rows.Err()
+}
+
+// exec 执行写入，返回影响的行数
+func (e *MySQLExecutor) exec(ctx context.Context, operationType string, args []interface{}) (int64, error) {
+	var result sql.Result
+	var err error
+	if stmt, ok := e.statements[operationType]; ok {
+		result, err = stmt.ExecContext(ctx, args...)
+	} else {
+		result, err = e.db.ExecContext(ctx, e.queries[operationType], args...)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// requestSize 估算请求写出的字节数：SQL文本（预编译模式下只发送参数）与参数值
+func (e *MySQLExecutor) requestSize(operationType string, args []interface{}) int64 {
+	var size int64
+	if _, ok := e.statements[operationType]; !ok {
+		size = int64(len(e.queries[operationType]))
+	}
+	for _, arg := range args {
+		switch value := arg.(type) {
+		case string:
+			size += int64(len(value))
+		case []byte:
+			size += int64(len(value))
+		default:
+			size += 8
+		}
+	}
+	return size
+}
+
+// QueryStats 获取按操作类型的统计
+func (e *MySQLExecutor) QueryStats() []QueryStats {
+	return e.tracker.Stats()
+}
+
+// Close 关闭预编译语句
+func (e *MySQLExecutor) Close() error {
+	for _, stmt := range e.statements {
+		stmt.Close()
+	}
+	return nil
+}
@@ -0,0 +1,327 @@
+package operations
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"abc-runner/app/adapters/mysql/config"
+	"abc-runner/app/adapters/mysql/connection"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// fakeDriver 记录收到的SQL的database/sql测试驱动
+// SELECT返回一行；information_schema查询返回tableExists；SQL中含"missing"的语句返回1146错误
+type fakeDriver struct {
+	mutex       sync.Mutex
+	prepares    int
+	statements  []string // 直接执行（非预编译）的SQL
+	tableExists bool
+}
+
+var (
+	fakeDrivers     = make(map[string]*fakeDriver)
+	fakeDriverMutex sync.Mutex
+)
+
+func init() {
+	sql.Register("mysql-fake", fakeConnector{})
+}
+
+// openFakeDB 打开一个使用独立fakeDriver的sql.DB
+func openFakeDB(t *testing.T) (*sql.DB, *fakeDriver) {
+	t.Helper()
+	fake := &fakeDriver{}
+	fakeDriverMutex.Lock()
+	fakeDrivers[t.Name()] = fake
+	fakeDriverMutex.Unlock()
+	db, err := sql.Open("mysql-fake", t.Name())
+	if err != nil {
+		t.Fatalf("failed to open fake db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, fake
+}
+
+func (d *fakeDriver) record(query string, prepared bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if prepared {
+		d.prepares++
+	} else {
+		d.statements = append(d.statements, query)
+	}
+}
+
+func (d *fakeDriver) counts() (int, []string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.prepares, append([]string(nil), d.statements...)
+}
+
+type fakeConnector struct{}
+
+func (fakeConnector) Open(name string) (driver.Conn, error) {
+	fakeDriverMutex.Lock()
+	defer fakeDriverMutex.Unlock()
+	return &fakeConn{driver: fakeDrivers[name]}, nil
+}
+
+type fakeConn struct {
+	driver *fakeDriver
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	c.driver.record(query, true)
+	return &fakeStmt{conn: c, query: query}, nil
+}
+
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return nil, fmt.Errorf("transactions not supported") }
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.driver.record(query, false)
+	return c.exec(query, len(args))
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.driver.record(query, false)
+	return c.query(query)
+}
+
+func (c *fakeConn) exec(query string, args int) (driver.Result, error) {
+	if strings.Contains(query, "missing") {
+		return nil, &mysql.MySQLError{Number: 1146, Message: "table doesn't exist"}
+	}
+	if strings.HasPrefix(query, "INSERT") {
+		return driver.RowsAffected(strings.Count(query, "),") + 1), nil
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) query(query string) (driver.Rows, error) {
+	if strings.Contains(query, "missing") {
+		return nil, &mysql.MySQLError{Number: 1146, Message: "table doesn't exist"}
+	}
+	if strings.Contains(query, "information_schema") {
+		exists := int64(0)
+		if c.driver.tableExists {
+			exists = 1
+		}
+		return &fakeRows{columns: []string{"count"}, values: [][]driver.Value{{exists}}}, nil
+	}
+	return &fakeRows{columns: []string{"id", "k", "c0"}, values: [][]driver.Value{{int64(1), int64(0), "x"}}}, nil
+}
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.conn.exec(s.query, len(args))
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.conn.query(s.query)
+}
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func testConfig(testCase string) *config.MySQLConfig {
+	cfg := config.NewDefaultMySQLConfig()
+	cfg.BenchMark.TestCase = testCase
+	cfg.MySQLSpecific.Rows = 100
+	cfg.MySQLSpecific.Schema.PayloadColumns = 3
+	cfg.MySQLSpecific.Schema.RowSize = 100
+	return cfg
+}
+
+func TestSchemaSQL(t *testing.T) {
+	cfg := testConfig(config.TestCaseRead)
+	cfg.MySQLSpecific.Schema.IndexK = true
+	schema := NewSchema(cfg.MySQLSpecific.Schema)
+
+	if sizes := cfg.MySQLSpecific.Schema.ColumnSizes(); fmt.Sprint(sizes) != "[34 33 33]" {
+		t.Errorf("unexpected column sizes: %v", sizes)
+	}
+	create := schema.CreateTableSQL()
+	for _, want := range []string{"c0 VARCHAR(34)", "c2 VARCHAR(33)", "KEY k_idx (k)", "ENGINE=InnoDB"} {
+		if !strings.Contains(create, want) {
+			t.Errorf("expected %q in %s", want, create)
+		}
+	}
+	if got := schema.SelectSQL(); got != "SELECT id, k, c0, c1, c2 FROM abc_bench WHERE id = ?" {
+		t.Errorf("unexpected select: %s", got)
+	}
+	if got := schema.UpdateSQL(); got != "UPDATE abc_bench SET k = k + 1, c0 = ?, c1 = ?, c2 = ? WHERE id = ?" {
+		t.Errorf("unexpected update: %s", got)
+	}
+	if got := schema.InsertSQL(2); got != "INSERT INTO abc_bench (k, c0, c1, c2) VALUES (?, ?, ?, ?), (?, ?, ?, ?)" {
+		t.Errorf("unexpected insert: %s", got)
+	}
+}
+
+func TestSchemaSetup(t *testing.T) {
+	db, fake := openFakeDB(t)
+	cfg := testConfig(config.TestCaseRead)
+	cfg.MySQLSpecific.Rows = 2500
+	cfg.MySQLSpecific.Schema.PayloadColumns = 1
+	schema := NewSchema(cfg.MySQLSpecific.Schema)
+
+	created, err := schema.Setup(context.Background(), db, cfg.MySQLSpecific.Rows)
+	if err != nil || !created {
+		t.Fatalf("setup failed: created=%v err=%v", created, err)
+	}
+	_, statements := fake.counts()
+	// 存在性检查、建表与3批填充（1000+1000+500）
+	if len(statements) != 5 || !strings.HasPrefix(statements[1], "CREATE TABLE abc_bench") {
+		t.Fatalf("unexpected setup statements: %d", len(statements))
+	}
+	if rows := strings.Count(statements[4], "(?, ?)"); rows != 500 {
+		t.Errorf("expected the last batch to insert 500 rows, got %d", rows)
+	}
+
+	fake.tableExists = true
+	if created, err := schema.Setup(context.Background(), db, cfg.MySQLSpecific.Rows); err != nil || created {
+		t.Errorf("expected setup to skip an existing table: created=%v err=%v", created, err)
+	}
+}
+
+func TestMySQLExecutorModes(t *testing.T) {
+	for _, prepared := range []bool{false, true} {
+		t.Run(fmt.Sprintf("prepared=%v", prepared), func(t *testing.T) {
+			db, fake := openFakeDB(t)
+			db.SetMaxOpenConns(1)
+			cfg := testConfig(config.TestCaseMixed)
+			cfg.MySQLSpecific.Prepared = prepared
+			executor, err := NewMySQLExecutor(context.Background(), db, cfg)
+			if err != nil {
+				t.Fatalf("failed to create executor: %v", err)
+			}
+			defer executor.Close()
+
+			factory := NewOperationFactory(cfg)
+			for i := 0; i < 20; i++ {
+				operation := factory.CreateOperation(i, &cfg.BenchMark)
+				result, err := executor.ExecuteOperation(context.Background(), operation)
+				if err != nil || !result.Success || result.Value.(int64) != 1 {
+					t.Fatalf("operation %s failed: %v", operation.Type, err)
+				}
+				if result.IsRead != (operation.Type == config.TestCaseRead) {
+					t.Errorf("unexpected IsRead for %s", operation.Type)
+				}
+				// 点查读回一行，所有操作都写出参数
+				if result.BytesWritten == 0 || result.IsRead && result.BytesRead == 0 {
+					t.Errorf("expected transferred bytes for %s, got written %d, read %d", operation.Type, result.BytesWritten, result.BytesRead)
+				}
+			}
+
+			prepares, statements := fake.counts()
+			if prepared && (prepares != 3 || len(statements) != 0) {
+				t.Errorf("expected 3 prepares and no direct statements, got %d and %d", prepares, len(statements))
+			}
+			if !prepared && (prepares != 0 || len(statements) != 20) {
+				t.Errorf("expected 20 direct statements and no prepares, got %d and %d", len(statements), prepares)
+			}
+
+			var total int64
+			for _, stats := range executor.QueryStats() {
+				total += stats.Count
+			}
+			if total != 20 {
+				t.Errorf("expected 20 tracked operations, got %d", total)
+			}
+		})
+	}
+}
+
+func TestMySQLExecutorErrorCodes(t *testing.T) {
+	db, _ := openFakeDB(t)
+	cfg := testConfig(config.TestCaseRead)
+	cfg.MySQLSpecific.Schema.Table = "missing"
+	executor, err := NewMySQLExecutor(context.Background(), db, cfg)
+	if err != nil {
+		t.Fatalf("failed to create executor: %v", err)
+	}
+
+	operation := NewOperationFactory(cfg).CreateOperation(0, &cfg.BenchMark)
+	result, err := executor.ExecuteOperation(context.Background(), operation)
+	if err == nil || result.Success {
+		t.Fatal("expected the query against a missing table to fail")
+	}
+	stats := executor.QueryStats()
+	if len(stats) != 1 || stats[0].Errors != 1 || stats[0].ErrorCodes["1146"] != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestOperationFactoryReadPercent(t *testing.T) {
+	cfg := testConfig(config.TestCaseMixed)
+	cfg.BenchMark.ReadPercent = 70
+	factory := NewOperationFactory(cfg)
+
+	counts := make(map[string]int)
+	for i := 0; i < 10000; i++ {
+		operation := factory.CreateOperation(i, &cfg.BenchMark)
+		counts[operation.Type]++
+		args := operation.Params["args"].([]interface{})
+		switch operation.Type {
+		case config.TestCaseRead:
+			if id := args[0].(int); id < 1 || id > cfg.MySQLSpecific.Rows || len(args) != 1 {
+				t.Fatalf("unexpected read args: %v", args)
+			}
+		case config.TestCaseWrite:
+			if len(args) != 4 || len(args[0].(string)) != 34 || len(args[1].(string)) != 33 {
+				t.Fatalf("unexpected write args: %v", args)
+			}
+		}
+	}
+	if counts[config.TestCaseRead] < 6500 || counts[config.TestCaseRead] > 7500 || len(counts) != 2 {
+		t.Errorf("unexpected mix: %v", counts)
+	}
+}
+
+func TestNewDSN(t *testing.T) {
+	cfg := testConfig(config.TestCaseRead)
+	cfg.Connection.Password = "secret"
+	for _, prepared := range []bool{false, true} {
+		cfg.MySQLSpecific.Prepared = prepared
+		parsed, err := mysql.ParseDSN(connection.NewDSN(cfg))
+		if err != nil {
+			t.Fatalf("invalid DSN: %v", err)
+		}
+		if parsed.InterpolateParams == prepared || parsed.Addr != "localhost:3306" || parsed.Passwd != "secret" {
+			t.Errorf("unexpected DSN config for prepared=%v: %+v", prepared, parsed)
+		}
+	}
+
+	cfg.MySQLSpecific.Schema.RowSize = 2
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected a row size smaller than the payload column count to be rejected")
+	}
+}
@@ -0,0 +1,81 @@
+package operations
+
+import (
+	"math/rand/v2"
+
+	"abc-runner/app/adapters/mysql/config"
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+)
+
+// OperationFactory MySQL操作工厂
+// 操作参数在此生成：id在[1, rows]内均匀分布，payload从随机缓冲区截取
+type OperationFactory struct {
+	testCase    string
+	rows        int
+	readPercent int
+	schema      *Schema
+	payload     *payloadSource
+}
+
+// NewOperationFactory 创建MySQL操作工厂
+func NewOperationFactory(cfg *config.MySQLConfig) *OperationFactory {
+	return &OperationFactory{
+		testCase:    cfg.BenchMark.TestCase,
+		rows:        cfg.MySQLSpecific.Rows,
+		readPercent: cfg.BenchMark.ReadPercent,
+		schema:      NewSchema(cfg.MySQLSpecific.Schema),
+		payload:     newPayloadSource(),
+	}
+}
+
+// CreateOperation 创建操作
+func (f *OperationFactory) CreateOperation(jobID int, cfg execution.BenchmarkConfig) interfaces.Operation {
+	operationType := f.pickOperation()
+	return interfaces.Operation{
+		Type: operationType,
+		Params: map[string]interface{}{
+			"job_id": jobID,
+			"args":   f.newArgs(operationType),
+		},
+		Metadata: map[string]string{
+			"operation_type": operationType,
+		},
+	}
+}
+
+// pickOperation 选择操作类型，mixed用例按read_percent在点查与更新之间随机
+func (f *OperationFactory) pickOperation() string {
+	if f.testCase != config.TestCaseMixed {
+		return f.testCase
+	}
+	if rand.IntN(100) < f.readPercent {
+		return config.TestCaseRead
+	}
+	return config.TestCaseWrite
+}
+
+// newArgs 按操作类型生成参数：read(id)、write(payload..., id)、insert(k, payload...)
+func (f *OperationFactory) newArgs(operationType string) []interface{} {
+	id := 1 + rand.IntN(f.rows)
+	switch operationType {
+	case config.TestCaseWrite:
+		args := f.schema.appendPayload(make([]interface{}, 0, len(f.schema.sizes)+1), f.payload)
+		return append(args, id)
+	case config.TestCaseInsert:
+		args := make([]interface{}, 0, len(f.schema.sizes)+1)
+		return f.schema.appendPayload(append(args, rand.IntN(1000000)), f.payload)
+	default:
+		return []interface{}{id}
+	}
+}
+
+// GetOperationType 获取操作类型
+func (f *OperationFactory) GetOperationType() string {
+	return f.testCase
+}
+
+// GetSupportedOperations 获取支持的操作类型
+func (f *OperationFactory) GetSupportedOperations() []string {
+	return []string{config.TestCaseRead, config.TestCaseWrite, config.TestCaseInsert}
+}
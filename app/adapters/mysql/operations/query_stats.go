@@ -0,0 +1,28 @@
+package operations
+
+import (
+	"errors"
+	"strconv"
+
+	"abc-runner/app/core/metrics"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// QueryStats 单个查询类型的统计，Volume为返回或影响的行数（JSON字段"rows"），
+// 错误码为服务端错误号，连接错误记为"connection"
+type QueryStats = metrics.OperationTypeStats
+
+// newQueryTracker 创建按查询类型的统计器
+func newQueryTracker() *metrics.OperationTypeTracker {
+	return metrics.NewOperationTypeTracker(errorCode, "rows", "")
+}
+
+// errorCode 错误分类：服务端错误取错误号，其余记为"connection"
+func errorCode(err error) string {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return strconv.Itoa(int(mysqlErr.Number))
+	}
+	return "connection"
+}
@@ -0,0 +1,147 @@
+package operations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+
+	"abc-runner/app/adapters/mysql/config"
+)
+
+// fillBatchRows 填充数据时每条INSERT的行数上限
+const fillBatchRows = 1000
+
+// maxPlaceholders 单条语句的占位符上限（协议限制为65535）
+const maxPlaceholders = 60000
+
+// Schema 测试表结构与对应的SQL
+type Schema struct {
+	table   string
+	sizes   []int
+	columns []string // payload列名c0..cN-1
+	engine  string
+	indexK  bool
+}
+
+// NewSchema 根据配置创建表结构
+func NewSchema(cfg config.SchemaConfig) *Schema {
+	sizes := cfg.ColumnSizes()
+	columns := make([]string, len(sizes))
+	for i := range columns {
+		columns[i] = fmt.Sprintf("c%d", i)
+	}
+	return &Schema{
+		table:   cfg.Table,
+		sizes:   sizes,
+		columns: columns,
+		engine:  cfg.Engine,
+		indexK:  cfg.IndexK,
+	}
+}
+
+// CreateTableSQL 建表语句
+func (s *Schema) CreateTableSQL() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "CREATE TABLE %s (id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT, k INT NOT NULL DEFAULT 0", s.table)
+	for i, column := range s.columns {
+		fmt.Fprintf(&b, ", %s VARCHAR(%d) NOT NULL DEFAULT ''", column, s.sizes[i])
+	}
+	b.WriteString(", PRIMARY KEY (id)")
+	if s.indexK {
+		b.WriteString(", KEY k_idx (k)")
+	}
+	fmt.Fprintf(&b, ") ENGINE=%s", s.engine)
+	return b.String()
+}
+
+// SelectSQL 按主键点查，参数：id
+func (s *Schema) SelectSQL() string {
+	return fmt.Sprintf("SELECT id, k, %s FROM %s WHERE id = ?", strings.Join(s.columns, ", "), s.table)
+}
+
+// UpdateSQL 按主键更新整行，参数：payload列..., id
+func (s *Schema) UpdateSQL() string {
+	assignments := make([]string, len(s.columns))
+	for i, column := range s.columns {
+		assignments[i] = column + " = ?"
+	}
+	return fmt.Sprintf("UPDATE %s SET k = k + 1, %s WHERE id = ?", s.table, strings.Join(assignments, ", "))
+}
+
+// InsertSQL 插入rows行，参数：每行k与payload列...
+func (s *Schema) InsertSQL(rows int) string {
+	row := "(?" + strings.Repeat(", ?", len(s.columns)) + ")"
+	values := strings.TrimSuffix(strings.Repeat(row+", ", rows), ", ")
+	return fmt.Sprintf("INSERT INTO %s (k, %s) VALUES %s", s.table, strings.Join(s.columns, ", "), values)
+}
+
+// appendPayload 追加一行的payload参数
+func (s *Schema) appendPayload(args []interface{}, payload *payloadSource) []interface{} {
+	for _, size := range s.sizes {
+		args = append(args, payload.next(size))
+	}
+	return args
+}
+
+// Setup 创建测试表并填充rows行，表已存在时跳过；返回是否新建了表
+func (s *Schema) Setup(ctx context.Context, db *sql.DB, rows int) (bool, error) {
+	var exists int
+	err := db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?", s.table).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check table %s: %w", s.table, err)
+	}
+	if exists > 0 {
+		return false, nil
+	}
+
+	if _, err := db.ExecContext(ctx, s.CreateTableSQL()); err != nil {
+		return false, fmt.Errorf("failed to create table %s: %w", s.table, err)
+	}
+
+	batch := fillBatchRows
+	if perRow := len(s.columns) + 1; batch*perRow > maxPlaceholders {
+		batch = maxPlaceholders / perRow
+	}
+	payload := newPayloadSource()
+	for filled := 0; filled < rows; filled += batch {
+		n := min(batch, rows-filled)
+		args := make([]interface{}, 0, n*(len(s.columns)+1))
+		for i := 0; i < n; i++ {
+			args = append(args, rand.IntN(1000000))
+			args = s.appendPayload(args, payload)
+		}
+		if _, err := db.ExecContext(ctx, s.InsertSQL(n), args...); err != nil {
+			return true, fmt.Errorf("failed to fill table %s: %w", s.table, err)
+		}
+	}
+	return true, nil
+}
+
+// payloadSource 从预生成的随机字母数字缓冲区截取payload，避免每次操作生成随机串
+type payloadSource struct {
+	buffer string
+}
+
+// payloadBufferSize 随机缓冲区大小
+const payloadBufferSize = 64 << 10
+
+func newPayloadSource() *payloadSource {
+	const alphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	buffer := make([]byte, payloadBufferSize)
+	for i := range buffer {
+		buffer[i] = alphabet[rand.IntN(len(alphabet))]
+	}
+	return &payloadSource{buffer: string(buffer)}
+}
+
+// next 截取size字节的payload，超过缓冲区时重复拼接
+func (p *payloadSource) next(size int) string {
+	if size > len(p.buffer) {
+		return strings.Repeat(p.buffer, size/len(p.buffer)+1)[:size]
+	}
+	offset := rand.IntN(len(p.buffer) - size + 1)
+	return p.buffer[offset : offset+size]
+}
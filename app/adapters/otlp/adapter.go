@@ -0,0 +1,147 @@
+package otlp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"abc-runner/app/adapters/otlp/config"
+	"abc-runner/app/adapters/otlp/connection"
+	"abc-runner/app/adapters/otlp/operations"
+	"abc-runner/app/core/interfaces"
+)
+
+// OTLPAdapter OpenTelemetry OTLP导出适配器 - 遵循统一架构模式
+// 职责：导出客户端管理、请求生成、健康检查
+type OTLPAdapter struct {
+	config           *config.OTLPConfig
+	exporter         connection.Exporter
+	otlpOperations   *operations.OTLPExecutor
+	metricsCollector interfaces.DefaultMetricsCollector
+	mu               sync.RWMutex
+	isConnected      bool
+
+	// 统计信息
+	totalOperations  int64
+	failedOperations int64
+}
+
+// NewOTLPAdapter 创建OTLP适配器
+func NewOTLPAdapter(metricsCollector interfaces.DefaultMetricsCollector) *OTLPAdapter {
+	return &OTLPAdapter{
+		metricsCollector: metricsCollector,
+	}
+}
+
+// Connect 创建导出客户端并预编码资源属性
+func (o *OTLPAdapter) Connect(ctx context.Context, cfg interfaces.Config) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	otlpConfig, ok := cfg.(*config.OTLPConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type for OTLP adapter: expected *config.OTLPConfig, got %T", cfg)
+	}
+
+	if err := otlpConfig.Validate(); err != nil {
+		return fmt.Errorf("config validation failed: %w", err)
+	}
+	o.config = otlpConfig
+
+	exporter, err := connection.NewExporter(otlpConfig)
+	if err != nil {
+		return err
+	}
+	o.exporter = exporter
+	o.otlpOperations = operations.NewOTLPExecutor(exporter, operations.NewPayloadBuilder(otlpConfig),
+		otlpConfig.BenchMark.Rate, otlpConfig.OTLPSpecific.BatchSize)
+
+	o.isConnected = true
+	return nil
+}
+
+// Execute 执行操作 - 使用执行器处理
+func (o *OTLPAdapter) Execute(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	if !o.isConnected {
+		return nil, fmt.Errorf("adapter not connected")
+	}
+
+	atomic.AddInt64(&o.totalOperations, 1)
+	result, err := o.otlpOperations.ExecuteOperation(ctx, operation)
+	if err != nil {
+		atomic.AddInt64(&o.failedOperations, 1)
+	}
+	return result, err
+}
+
+// Close 关闭导出客户端
+func (o *OTLPAdapter) Close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var err error
+	if o.exporter != nil {
+		err = o.exporter.Close()
+		o.exporter = nil
+	}
+	o.isConnected = false
+	return err
+}
+
+// GetProtocolMetrics 获取协议特定指标
+func (o *OTLPAdapter) GetProtocolMetrics() map[string]interface{} {
+	metrics := map[string]interface{}{
+		"protocol":          "otlp",
+		"total_operations":  atomic.LoadInt64(&o.totalOperations),
+		"failed_operations": atomic.LoadInt64(&o.failedOperations),
+	}
+
+	if o.config != nil {
+		metrics["signal"] = o.config.BenchMark.TestCase
+		metrics["transport"] = o.config.Connection.Transport
+		metrics["batch_size"] = o.config.OTLPSpecific.BatchSize
+	}
+	if stats := o.GetExportStats(); stats != nil {
+		metrics["export_stats"] = *stats
+	}
+
+	return metrics
+}
+
+// GetExportStats 获取导出统计，未连接时返回nil
+func (o *OTLPAdapter) GetExportStats() *operations.ExportStats {
+	if o.otlpOperations == nil {
+		return nil
+	}
+	stats := o.otlpOperations.ExportStats()
+	return &stats
+}
+
+// HealthCheck 健康检查：发送一个空的导出请求，失败响应视为不健康
+func (o *OTLPAdapter) HealthCheck(ctx context.Context) error {
+	if !o.isConnected {
+		return fmt.Errorf("adapter not connected")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.config.Connection.Timeout)
+	defer cancel()
+	response, err := o.exporter.Export(ctx, []byte{})
+	if err != nil {
+		return fmt.Errorf("OTLP endpoint %s is unreachable: %w", o.exporter.Target(), err)
+	}
+	if !response.Success {
+		return fmt.Errorf("OTLP endpoint %s returned %s: %s", o.exporter.Target(), response.Status, response.Message)
+	}
+	return nil
+}
+
+// GetProtocolName 获取协议名称
+func (o *OTLPAdapter) GetProtocolName() string {
+	return "otlp"
+}
+
+// GetMetricsCollector 获取指标收集器
+func (o *OTLPAdapter) GetMetricsCollector() interfaces.DefaultMetricsCollector {
+	return o.metricsCollector
+}
@@ -0,0 +1,297 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+)
+
+// OTLP传输方式
+const (
+	TransportHTTP = "http" // OTLP/HTTP protobuf
+	TransportGRPC = "grpc" // OTLP/gRPC
+)
+
+// OTLPConfig OTLP导出协议配置
+type OTLPConfig struct {
+	Protocol     string             `yaml:"protocol" json:"protocol"`
+	Connection   ConnectionConfig   `yaml:"connection" json:"connection"`
+	BenchMark    BenchmarkConfig    `yaml:"benchmark" json:"benchmark"`
+	OTLPSpecific OTLPSpecificConfig `yaml:"otlp_specific" json:"otlp_specific"`
+}
+
+// ConnectionConfig OTLP连接配置
+type ConnectionConfig struct {
+	// Endpoint HTTP为基础URL（自动追加/v1/traces或/v1/metrics），gRPC为host:port
+	Endpoint           string            `yaml:"endpoint" json:"endpoint"`
+	Transport          string            `yaml:"transport" json:"transport"`     // http、grpc
+	Compression        string            `yaml:"compression" json:"compression"` // gzip、none
+	Timeout            time.Duration     `yaml:"timeout" json:"timeout"`
+	Headers            map[string]string `yaml:"headers" json:"headers"` // HTTP请求头或gRPC元数据
+	TLS                bool              `yaml:"tls" json:"tls"`         // gRPC使用TLS，HTTP由URL协议决定
+	InsecureSkipVerify bool              `yaml:"insecure_skip_verify" json:"insecure_skip_verify"`
+}
+
+// BenchmarkConfig OTLP基准测试配置
+type BenchmarkConfig struct {
+	Total     int           `yaml:"total" json:"total"`         // 总导出请求数
+	Parallels int           `yaml:"parallels" json:"parallels"` // 并发导出者数
+	TestCase  string        `yaml:"test_case" json:"test_case"` // "traces"、"metrics"
+	Duration  time.Duration `yaml:"duration" json:"duration"`
+	Rate      int           `yaml:"rate" json:"rate"` // 每秒导出的span（或数据点）数上限，0表示不限速
+}
+
+// OTLPSpecificConfig OTLP负载特定配置
+type OTLPSpecificConfig struct {
+	BatchSize      int    `yaml:"batch_size" json:"batch_size"`           // 每个请求的span数（traces）或数据点数（metrics）
+	ServiceName    string `yaml:"service_name" json:"service_name"`       // service.name资源属性前缀
+	Services       int    `yaml:"services" json:"services"`               // 不同service.name的数量，请求在各服务间轮转
+	SpansPerTrace  int    `yaml:"spans_per_trace" json:"spans_per_trace"` // 每个trace的span数（1个根span加子span）
+	Attributes     int    `yaml:"attributes" json:"attributes"`           // 每个span或数据点的额外属性数
+	AttributeSize  int    `yaml:"attribute_size" json:"attribute_size"`   // 额外属性值的字节数
+	ErrorPercent   int    `yaml:"error_percent" json:"error_percent"`     // 状态为ERROR的trace百分比
+	Series         int    `yaml:"series" json:"series"`                   // metrics的时间序列基数
+	MetricNames    int    `yaml:"metric_names" json:"metric_names"`       // metrics的指标名数量
+	MetricPrefix   string `yaml:"metric_prefix" json:"metric_prefix"`
+	ScopeName      string `yaml:"scope_name" json:"scope_name"` // InstrumentationScope名称
+	ResourceLabels int    `yaml:"resource_labels" json:"resource_labels"`
+}
+
+// NewDefaultOTLPConfig 创建默认OTLP配置
+func NewDefaultOTLPConfig() *OTLPConfig {
+	return &OTLPConfig{
+		Protocol: "otlp",
+		Connection: ConnectionConfig{
+			Endpoint:    "http://localhost:4318",
+			Transport:   TransportHTTP,
+			Compression: "gzip",
+			Timeout:     10 * time.Second,
+			Headers:     make(map[string]string),
+		},
+		BenchMark: BenchmarkConfig{
+			Total:     1000,
+			Parallels: 10,
+			TestCase:  "traces",
+		},
+		OTLPSpecific: OTLPSpecificConfig{
+			BatchSize:      512,
+			ServiceName:    "abc-runner",
+			Services:       1,
+			SpansPerTrace:  8,
+			Attributes:     5,
+			AttributeSize:  16,
+			Series:         10000,
+			MetricNames:    10,
+			MetricPrefix:   "abc_runner.metric",
+			ScopeName:      "abc-runner",
+			ResourceLabels: 3,
+		},
+	}
+}
+
+// DefaultGRPCEndpoint gRPC传输的默认地址
+const DefaultGRPCEndpoint = "localhost:4317"
+
+// ExportPath HTTP导出路径
+func (c *OTLPConfig) ExportPath() string {
+	if c.BenchMark.TestCase == "metrics" {
+		return "/v1/metrics"
+	}
+	return "/v1/traces"
+}
+
+// ExportURL HTTP导出地址，endpoint已包含路径时原样使用
+func (c *OTLPConfig) ExportURL() string {
+	endpoint := strings.TrimRight(c.Connection.Endpoint, "/")
+	if strings.HasSuffix(endpoint, "/v1/traces") || strings.HasSuffix(endpoint, "/v1/metrics") {
+		return endpoint
+	}
+	return endpoint + c.ExportPath()
+}
+
+// ItemName 每个请求中计数的条目名称
+func (c *OTLPConfig) ItemName() string {
+	if c.BenchMark.TestCase == "metrics" {
+		return "data points"
+	}
+	return "spans"
+}
+
+// GetProtocol 实现Config接口
+func (c *OTLPConfig) GetProtocol() string {
+	return c.Protocol
+}
+
+// GetConnection 实现Config接口
+func (c *OTLPConfig) GetConnection() interfaces.ConnectionConfig {
+	return &c.Connection
+}
+
+// GetBenchmark 实现Config接口
+func (c *OTLPConfig) GetBenchmark() interfaces.BenchmarkConfig {
+	return &c.BenchMark
+}
+
+// Validate 实现Config接口
+func (c *OTLPConfig) Validate() error {
+	connection := c.Connection
+	switch connection.Transport {
+	case TransportHTTP:
+		parsed, err := url.Parse(connection.Endpoint)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("invalid OTLP/HTTP endpoint: %q", connection.Endpoint)
+		}
+	case TransportGRPC:
+		if connection.Endpoint == "" || strings.Contains(connection.Endpoint, "://") {
+			return fmt.Errorf("invalid OTLP/gRPC endpoint: %q (expected host:port)", connection.Endpoint)
+		}
+	default:
+		return fmt.Errorf("invalid transport: %s, valid options: http, grpc", connection.Transport)
+	}
+	if connection.Compression != "gzip" && connection.Compression != "none" {
+		return fmt.Errorf("invalid compression: %s, valid options: gzip, none", connection.Compression)
+	}
+	if connection.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+
+	if c.BenchMark.Total <= 0 {
+		return fmt.Errorf("total operations must be greater than 0")
+	}
+	if c.BenchMark.Parallels <= 0 {
+		return fmt.Errorf("parallel connections must be greater than 0")
+	}
+	if c.BenchMark.TestCase != "traces" && c.BenchMark.TestCase != "metrics" {
+		return fmt.Errorf("invalid test case: %s, valid options: traces, metrics", c.BenchMark.TestCase)
+	}
+	if c.BenchMark.Rate < 0 {
+		return fmt.Errorf("rate cannot be negative")
+	}
+
+	specific := c.OTLPSpecific
+	if specific.BatchSize <= 0 {
+		return fmt.Errorf("batch size must be greater than 0")
+	}
+	if specific.ServiceName == "" {
+		return fmt.Errorf("service name cannot be empty")
+	}
+	if specific.Services <= 0 {
+		return fmt.Errorf("services must be greater than 0")
+	}
+	if specific.SpansPerTrace <= 0 {
+		return fmt.Errorf("spans per trace must be greater than 0")
+	}
+	if specific.Attributes < 0 || specific.AttributeSize < 0 || specific.ResourceLabels < 0 {
+		return fmt.Errorf("attributes, attribute size and resource labels cannot be negative")
+	}
+	if specific.ErrorPercent < 0 || specific.ErrorPercent > 100 {
+		return fmt.Errorf("error percent must be between 0 and 100")
+	}
+	if specific.Series <= 0 {
+		return fmt.Errorf("series must be greater than 0")
+	}
+	if specific.MetricNames <= 0 {
+		return fmt.Errorf("metric names must be greater than 0")
+	}
+	if specific.MetricPrefix == "" {
+		return fmt.Errorf("metric prefix cannot be empty")
+	}
+
+	return nil
+}
+
+// Clone 实现Config接口
+func (c *OTLPConfig) Clone() interfaces.Config {
+	clone := *c
+	clone.Connection.Headers = make(map[string]string, len(c.Connection.Headers))
+	for k, v := range c.Connection.Headers {
+		clone.Connection.Headers[k] = v
+	}
+	return &clone
+}
+
+// ConnectionConfig接口实现
+
+// GetAddresses 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetAddresses() []string {
+	return []string{c.Endpoint}
+}
+
+// GetCredentials 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetCredentials() map[string]string {
+	return map[string]string{}
+}
+
+// GetPoolConfig 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetPoolConfig() interfaces.PoolConfig {
+	return &EmptyPoolConfig{}
+}
+
+// GetTimeout 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetTimeout() time.Duration {
+	return c.Timeout
+}
+
+// EmptyPoolConfig 空的连接池配置（HTTP空闲连接数由并发数决定，gRPC复用单个连接）
+type EmptyPoolConfig struct{}
+
+func (p *EmptyPoolConfig) GetPoolSize() int                    { return 0 }
+func (p *EmptyPoolConfig) GetMinIdle() int                     { return 0 }
+func (p *EmptyPoolConfig) GetMaxIdle() int                     { return 0 }
+func (p *EmptyPoolConfig) GetIdleTimeout() time.Duration       { return 0 }
+func (p *EmptyPoolConfig) GetConnectionTimeout() time.Duration { return 0 }
+
+// BenchmarkConfig接口实现
+
+// GetTotal 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTotal() int {
+	return b.Total
+}
+
+// GetParallels 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetParallels() int {
+	return b.Parallels
+}
+
+// GetDataSize 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetDataSize() int {
+	return 0
+}
+
+// GetTTL 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTTL() time.Duration {
+	return 0
+}
+
+// GetReadPercent 实现BenchmarkConfig接口，OTLP导出均为写操作
+func (b *BenchmarkConfig) GetReadPercent() int {
+	return 0
+}
+
+// GetRandomKeys 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetRandomKeys() int {
+	return 0
+}
+
+// GetTestCase 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTestCase() string {
+	return b.TestCase
+}
+
+// GetDuration 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetDuration() time.Duration {
+	return b.Duration
+}
+
+// GetTimeout 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTimeout() time.Duration {
+	return 0
+}
+
+// GetRampUp 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetRampUp() time.Duration {
+	return 0
+}
@@ -0,0 +1,268 @@
+package connection
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"abc-runner/app/adapters/otlp/config"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	grpcgzip "google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// maxResponseSize 保留的最大响应字节数
+const maxResponseSize = 4096
+
+// gRPC导出方法
+const (
+	traceExportMethod   = "/opentelemetry.proto.collector.trace.v1.TraceService/Export"
+	metricsExportMethod = "/opentelemetry.proto.collector.metrics.v1.MetricsService/Export"
+)
+
+// ExportResponse 一次导出请求的结果
+type ExportResponse struct {
+	Status    string // 如"HTTP 200"、"OK"、"ResourceExhausted"
+	Success   bool
+	Retryable bool   // 按OTLP规范应重试的失败（限流或服务暂不可用）
+	Body      []byte // 成功响应的protobuf正文，用于解析partial_success
+	Message   string // 失败时的响应正文或gRPC状态信息
+	WireBytes int    // 实际发送的请求体字节数（压缩后），gRPC由传输层压缩时为0
+}
+
+// Exporter OTLP导出客户端
+type Exporter interface {
+	// Export 发送一个ExportServiceRequest，返回的错误只表示请求未得到响应
+	Export(ctx context.Context, payload []byte) (*ExportResponse, error)
+	Target() string
+	Close() error
+}
+
+// NewExporter 按传输方式创建导出客户端
+func NewExporter(cfg *config.OTLPConfig) (Exporter, error) {
+	if cfg.Connection.Transport == config.TransportGRPC {
+		return newGRPCExporter(cfg)
+	}
+	return newHTTPExporter(cfg), nil
+}
+
+// httpExporter OTLP/HTTP protobuf客户端
+type httpExporter struct {
+	url        string
+	headers    http.Header
+	gzip       bool
+	writers    sync.Pool
+	httpClient *http.Client
+}
+
+func newHTTPExporter(cfg *config.OTLPConfig) *httpExporter {
+	headers := make(http.Header)
+	headers.Set("Content-Type", "application/x-protobuf")
+	headers.Set("User-Agent", "abc-runner")
+	gzipEnabled := cfg.Connection.Compression == "gzip"
+	if gzipEnabled {
+		headers.Set("Content-Encoding", "gzip")
+	}
+	for name, value := range cfg.Connection.Headers {
+		headers.Set(name, value)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = cfg.BenchMark.Parallels
+	transport.MaxIdleConnsPerHost = cfg.BenchMark.Parallels
+	transport.DisableCompression = true
+	if cfg.Connection.InsecureSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	return &httpExporter{
+		url:     cfg.ExportURL(),
+		headers: headers,
+		gzip:    gzipEnabled,
+		writers: sync.Pool{New: func() interface{} { return gzip.NewWriter(nil) }},
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   cfg.Connection.Timeout,
+		},
+	}
+}
+
+// compress 按配置压缩请求体
+func (e *httpExporter) compress(payload []byte) ([]byte, error) {
+	if !e.gzip {
+		return payload, nil
+	}
+	var buffer bytes.Buffer
+	writer := e.writers.Get().(*gzip.Writer)
+	defer e.writers.Put(writer)
+	writer.Reset(&buffer)
+	if _, err := writer.Write(payload); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func (e *httpExporter) Export(ctx context.Context, payload []byte) (*ExportResponse, error) {
+	body, err := e.compress(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compress request: %w", err)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	request.Header = e.headers.Clone()
+
+	response, err := e.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	data, _ := io.ReadAll(io.LimitReader(response.Body, maxResponseSize))
+	// 读完响应体以复用连接
+	io.Copy(io.Discard, response.Body)
+
+	result := &ExportResponse{
+		Status:    fmt.Sprintf("HTTP %d", response.StatusCode),
+		Success:   response.StatusCode/100 == 2,
+		WireBytes: len(body),
+	}
+	if result.Success {
+		result.Body = data
+	} else {
+		// OTLP/HTTP规范：429、502、503、504可重试
+		switch response.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			result.Retryable = true
+		}
+		result.Message = strings.TrimSpace(string(data))
+	}
+	return result, nil
+}
+
+func (e *httpExporter) Target() string {
+	return e.url
+}
+
+func (e *httpExporter) Close() error {
+	e.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// grpcExporter OTLP/gRPC客户端，所有并发请求复用一个HTTP/2连接
+type grpcExporter struct {
+	endpoint string
+	method   string
+	metadata metadata.MD
+	timeout  time.Duration
+	conn     *grpc.ClientConn
+}
+
+func newGRPCExporter(cfg *config.OTLPConfig) (*grpcExporter, error) {
+	transportCredentials := insecure.NewCredentials()
+	if cfg.Connection.TLS {
+		transportCredentials = credentials.NewTLS(&tls.Config{InsecureSkipVerify: cfg.Connection.InsecureSkipVerify})
+	}
+	callOptions := []grpc.CallOption{grpc.ForceCodec(rawCodec{})}
+	if cfg.Connection.Compression == "gzip" {
+		callOptions = append(callOptions, grpc.UseCompressor(grpcgzip.Name))
+	}
+
+	conn, err := grpc.NewClient(cfg.Connection.Endpoint,
+		grpc.WithTransportCredentials(transportCredentials),
+		grpc.WithUserAgent("abc-runner"),
+		grpc.WithDefaultCallOptions(callOptions...),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gRPC client for %s: %w", cfg.Connection.Endpoint, err)
+	}
+
+	method := traceExportMethod
+	if cfg.BenchMark.TestCase == "metrics" {
+		method = metricsExportMethod
+	}
+	return &grpcExporter{
+		endpoint: cfg.Connection.Endpoint,
+		method:   method,
+		metadata: metadata.New(cfg.Connection.Headers),
+		timeout:  cfg.Connection.Timeout,
+		conn:     conn,
+	}, nil
+}
+
+func (e *grpcExporter) Export(ctx context.Context, payload []byte) (*ExportResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+	if len(e.metadata) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, e.metadata)
+	}
+
+	var reply []byte
+	err := e.conn.Invoke(ctx, e.method, payload, &reply)
+	code := status.Code(err)
+	result := &ExportResponse{
+		Status:  code.String(),
+		Success: err == nil,
+	}
+	if err == nil {
+		result.Body = reply
+		return result, nil
+	}
+
+	// OTLP/gRPC规范中可重试的状态码
+	switch code {
+	case codes.Canceled, codes.DeadlineExceeded, codes.Aborted, codes.OutOfRange,
+		codes.Unavailable, codes.DataLoss, codes.ResourceExhausted:
+		result.Retryable = true
+	}
+	result.Message = status.Convert(err).Message()
+	return result, nil
+}
+
+func (e *grpcExporter) Target() string {
+	return e.endpoint + e.method
+}
+
+func (e *grpcExporter) Close() error {
+	return e.conn.Close()
+}
+
+// rawCodec 直接收发已编码的protobuf字节，避免依赖生成的OTLP消息类型
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	data, ok := v.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("raw codec: unexpected message type %T", v)
+	}
+	return data, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	target, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("raw codec: unexpected message type %T", v)
+	}
+	*target = append((*target)[:0], data...)
+	return nil
+}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
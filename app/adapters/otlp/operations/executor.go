@@ -0,0 +1,86 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"abc-runner/app/adapters/otlp/connection"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/utils"
+)
+
+// OTLPExecutor OTLP导出操作执行器
+type OTLPExecutor struct {
+	exporter connection.Exporter
+	builder  *PayloadBuilder
+	pacer    *utils.Pacer
+	tracker  *ExportTracker
+}
+
+// NewOTLPExecutor 创建OTLP导出执行器
+// rate大于0时按每个请求的条目数换算为请求速率进行限速
+func NewOTLPExecutor(exporter connection.Exporter, builder *PayloadBuilder, rate, itemsPerRequest int) *OTLPExecutor {
+	return &OTLPExecutor{
+		exporter: exporter,
+		builder:  builder,
+		pacer:    utils.NewRatePacer(float64(rate) / float64(itemsPerRequest)),
+		tracker:  NewExportTracker(),
+	}
+}
+
+// ExportStats 获取导出统计
+func (e *OTLPExecutor) ExportStats() ExportStats {
+	return e.tracker.Stats()
+}
+
+// ExecuteOperation 执行一次导出，仅成功响应视为成功（部分成功单独统计被拒绝的条目）
+func (e *OTLPExecutor) ExecuteOperation(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	if operation.Type != "traces" && operation.Type != "metrics" {
+		return nil, fmt.Errorf("unsupported operation type: %s", operation.Type)
+	}
+	index, ok := operation.Params["job_id"].(int)
+	if !ok {
+		return nil, fmt.Errorf("invalid job id: %v", operation.Params["job_id"])
+	}
+	if err := e.pacer.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	payload, items := e.builder.Build(index, time.Now())
+
+	startTime := time.Now()
+	response, err := e.exporter.Export(ctx, payload)
+	duration := time.Since(startTime)
+
+	status := "transport error"
+	if response != nil {
+		status = response.Status
+		if !response.Success {
+			err = fmt.Errorf("OTLP export rejected with %s: %s", response.Status, response.Message)
+		}
+	}
+	e.tracker.Record(response, items, len(payload))
+
+	result := &interfaces.OperationResult{
+		Success:  err == nil,
+		Duration: duration,
+		IsRead:   false,
+		Error:    err,
+		Value:    items,
+		Metadata: map[string]interface{}{
+			"protocol":       "otlp",
+			"operation_type": operation.Type,
+			"items":          items,
+			"bytes":          len(payload),
+			"status":         status,
+		},
+	}
+	if response != nil {
+		result.BytesWritten = int64(len(payload))
+	}
+	for k, v := range operation.Metadata {
+		result.Metadata[k] = v
+	}
+	return result, err
+}
@@ -0,0 +1,221 @@
+package operations
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"abc-runner/app/adapters/otlp/config"
+	"abc-runner/app/adapters/otlp/connection"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// scopeItems 遍历导出请求中的span（或metric）消息
+func scopeItems(payload []byte, fn func(item []byte)) {
+	messages(payload, fieldResourceData, func(resource []byte) {
+		messages(resource, fieldScopeData, func(scope []byte) {
+			messages(scope, fieldScopeItem, fn)
+		})
+	})
+}
+
+// messages 遍历指定字段号的嵌套消息
+func messages(data []byte, field protowire.Number, fn func([]byte)) {
+	forEachField(data, func(num protowire.Number, typ protowire.Type, value []byte, varint uint64) {
+		if num == field && typ == protowire.BytesType {
+			fn(value)
+		}
+	})
+}
+
+// countSpans 统计请求中的span数与不同trace数
+func countSpans(payload []byte) (int, int) {
+	spans := 0
+	traces := make(map[string]bool)
+	scopeItems(payload, func(span []byte) {
+		spans++
+		messages(span, fieldSpanTraceID, func(traceID []byte) { traces[string(traceID)] = true })
+	})
+	return spans, len(traces)
+}
+
+// countDataPoints 统计请求中的数据点数与指标数
+func countDataPoints(payload []byte) (int, int) {
+	points, metrics := 0, 0
+	scopeItems(payload, func(metric []byte) {
+		metrics++
+		messages(metric, fieldMetricGauge, func(gauge []byte) {
+			messages(gauge, fieldGaugeDataPoints, func([]byte) { points++ })
+		})
+	})
+	return points, metrics
+}
+
+// partialSuccess 编码带partial_success的Export响应
+func partialSuccess(rejected int64, message string) []byte {
+	var partial []byte
+	partial = protowire.AppendTag(partial, fieldRejectedItems, protowire.VarintType)
+	partial = protowire.AppendVarint(partial, uint64(rejected))
+	partial = protowire.AppendTag(partial, fieldPartialErrorMsg, protowire.BytesType)
+	partial = protowire.AppendString(partial, message)
+	return appendMessage(nil, fieldPartialSuccess, partial)
+}
+
+func TestOTLPHTTPTraces(t *testing.T) {
+	var mutex sync.Mutex
+	var payloads [][]byte
+	var calls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" || r.Header.Get("Content-Encoding") != "gzip" || r.Header.Get("X-Tenant") != "bench" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		reader, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, _ := io.ReadAll(reader)
+		mutex.Lock()
+		payloads = append(payloads, body)
+		mutex.Unlock()
+
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		switch calls.Add(1) {
+		case 2:
+			w.Write(partialSuccess(4, "spans too old"))
+		case 3:
+			http.Error(w, "rate limited", http.StatusTooManyRequests)
+		case 4:
+			http.Error(w, "invalid payload", http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	cfg := config.NewDefaultOTLPConfig()
+	cfg.Connection.Endpoint = server.URL
+	cfg.Connection.Headers["X-Tenant"] = "bench"
+	cfg.OTLPSpecific.BatchSize = 20
+	cfg.OTLPSpecific.SpansPerTrace = 5
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("invalid config: %v", err)
+	}
+	exporter, err := connection.NewExporter(cfg)
+	if err != nil {
+		t.Fatalf("failed to create exporter: %v", err)
+	}
+	defer exporter.Close()
+
+	executor := NewOTLPExecutor(exporter, NewPayloadBuilder(cfg), 0, cfg.OTLPSpecific.BatchSize)
+	factory := NewOperationFactory("traces")
+	for job := 0; job < 5; job++ {
+		result, err := executor.ExecuteOperation(context.Background(), factory.CreateOperation(job, nil))
+		if (err == nil) != (job != 2 && job != 3) || result.Success != (err == nil) {
+			t.Fatalf("job %d: success=%v err=%v", job, result.Success, err)
+		}
+	}
+
+	if spans, traces := countSpans(payloads[0]); spans != 20 || traces != 4 {
+		t.Fatalf("request carried %d spans in %d traces, want 20 in 4", spans, traces)
+	}
+
+	stats := executor.ExportStats()
+	if stats.Requests != 5 || stats.Accepted != 3 || stats.Retryable != 1 || stats.Permanent != 1 || stats.PartialSuccess != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	if stats.ItemsSent != 100 || stats.ItemsAccepted != 56 || stats.ItemsRejected != 4 {
+		t.Fatalf("unexpected item stats: %+v", stats)
+	}
+	if stats.ErrorSamples["HTTP 429"] != "rate limited" || stats.ErrorSamples["partial_success"] != "spans too old" {
+		t.Fatalf("unexpected error samples: %+v", stats.ErrorSamples)
+	}
+	if stats.BytesSent == 0 || stats.BytesSent >= stats.UncompressedBytes {
+		t.Fatalf("expected compressed bytes below raw bytes: %+v", stats)
+	}
+}
+
+// testCodec 测试服务端使用的原始字节编解码器
+type testCodec struct{}
+
+func (testCodec) Marshal(v interface{}) ([]byte, error) { return v.([]byte), nil }
+func (testCodec) Unmarshal(data []byte, v interface{}) error {
+	*(v.(*[]byte)) = append([]byte(nil), data...)
+	return nil
+}
+func (testCodec) Name() string { return "proto" }
+
+func TestOTLPGRPCMetrics(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	var mutex sync.Mutex
+	var payloads [][]byte
+	var methods []string
+	server := grpc.NewServer(grpc.ForceServerCodec(testCodec{}), grpc.UnknownServiceHandler(
+		func(srv interface{}, stream grpc.ServerStream) error {
+			var request []byte
+			if err := stream.RecvMsg(&request); err != nil {
+				return err
+			}
+			method, _ := grpc.MethodFromServerStream(stream)
+			mutex.Lock()
+			payloads = append(payloads, request)
+			methods = append(methods, method)
+			count := len(payloads)
+			mutex.Unlock()
+			if count == 2 {
+				return status.Error(codes.ResourceExhausted, "memory limiter refused data")
+			}
+			return stream.SendMsg([]byte{})
+		}))
+	go server.Serve(listener)
+	defer server.Stop()
+
+	cfg := config.NewDefaultOTLPConfig()
+	cfg.Connection.Transport = config.TransportGRPC
+	cfg.Connection.Endpoint = listener.Addr().String()
+	cfg.BenchMark.TestCase = "metrics"
+	cfg.OTLPSpecific.BatchSize = 30
+	cfg.OTLPSpecific.Series = 45
+	cfg.OTLPSpecific.MetricNames = 4
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("invalid config: %v", err)
+	}
+	exporter, err := connection.NewExporter(cfg)
+	if err != nil {
+		t.Fatalf("failed to create exporter: %v", err)
+	}
+	defer exporter.Close()
+
+	executor := NewOTLPExecutor(exporter, NewPayloadBuilder(cfg), 0, cfg.OTLPSpecific.BatchSize)
+	factory := NewOperationFactory("metrics")
+	for job := 0; job < 3; job++ {
+		result, err := executor.ExecuteOperation(context.Background(), factory.CreateOperation(job, nil))
+		if (err == nil) != (job != 1) || result.Success != (err == nil) {
+			t.Fatalf("job %d: success=%v err=%v", job, result.Success, err)
+		}
+	}
+
+	if methods[0] != "/opentelemetry.proto.collector.metrics.v1.MetricsService/Export" {
+		t.Fatalf("unexpected method: %s", methods[0])
+	}
+	if points, metrics := countDataPoints(payloads[0]); points != 30 || metrics != 4 {
+		t.Fatalf("request carried %d data points in %d metrics, want 30 in 4", points, metrics)
+	}
+
+	stats := executor.ExportStats()
+	if stats.Accepted != 2 || stats.Retryable != 1 || stats.ItemsAccepted != 60 || stats.Statuses["ResourceExhausted"] != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
@@ -0,0 +1,39 @@
+package operations
+
+import (
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+)
+
+// OperationFactory OTLP操作工厂，每个任务为一次导出请求
+type OperationFactory struct {
+	testCase string
+}
+
+// NewOperationFactory 创建OTLP操作工厂
+func NewOperationFactory(testCase string) *OperationFactory {
+	return &OperationFactory{testCase: testCase}
+}
+
+// CreateOperation 创建操作
+func (f *OperationFactory) CreateOperation(jobID int, config execution.BenchmarkConfig) interfaces.Operation {
+	return interfaces.Operation{
+		Type: f.testCase,
+		Params: map[string]interface{}{
+			"job_id": jobID,
+		},
+		Metadata: map[string]string{
+			"operation_type": f.testCase,
+		},
+	}
+}
+
+// GetOperationType 获取操作类型
+func (f *OperationFactory) GetOperationType() string {
+	return f.testCase
+}
+
+// GetSupportedOperations 获取支持的操作类型
+func (f *OperationFactory) GetSupportedOperations() []string {
+	return []string{"traces", "metrics"}
+}
@@ -0,0 +1,351 @@
+package operations
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"abc-runner/app/adapters/otlp/config"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// OTLP protobuf字段号（opentelemetry-proto v1）
+const (
+	// ExportTraceServiceRequest / ExportMetricsServiceRequest
+	fieldResourceData = 1 // resource_spans / resource_metrics
+	// ExportTraceServiceResponse / ExportMetricsServiceResponse
+	fieldPartialSuccess  = 1
+	fieldRejectedItems   = 1 // rejected_spans / rejected_data_points
+	fieldPartialErrorMsg = 2
+
+	// ResourceSpans / ResourceMetrics
+	fieldResource  = 1
+	fieldScopeData = 2 // scope_spans / scope_metrics
+	// Resource
+	fieldResourceAttributes = 1
+	// ScopeSpans / ScopeMetrics
+	fieldScope     = 1
+	fieldScopeItem = 2 // spans / metrics
+	// InstrumentationScope
+	fieldScopeName    = 1
+	fieldScopeVersion = 2
+
+	// Span
+	fieldSpanTraceID    = 1
+	fieldSpanID         = 2
+	fieldSpanParentID   = 4
+	fieldSpanName       = 5
+	fieldSpanKind       = 6
+	fieldSpanStartTime  = 7
+	fieldSpanEndTime    = 8
+	fieldSpanAttributes = 9
+	fieldSpanStatus     = 15
+	// Status
+	fieldStatusMessage = 2
+	fieldStatusCode    = 3
+
+	// Metric
+	fieldMetricName  = 1
+	fieldMetricUnit  = 3
+	fieldMetricGauge = 5
+	// Gauge
+	fieldGaugeDataPoints = 1
+	// NumberDataPoint
+	fieldPointTime       = 3
+	fieldPointAsDouble   = 4
+	fieldPointAttributes = 7
+
+	// KeyValue
+	fieldKey   = 1
+	fieldValue = 2
+	// AnyValue
+	fieldStringValue = 1
+	fieldIntValue    = 3
+)
+
+// Span枚举值
+const (
+	spanKindInternal = 1
+	spanKindServer   = 2
+	spanKindClient   = 3
+	statusCodeError  = 2
+)
+
+// PayloadBuilder 生成OTLP导出请求
+// 资源与InstrumentationScope在创建时编码，每个请求只编码span或数据点
+type PayloadBuilder struct {
+	traces        bool
+	batchSize     int
+	spansPerTrace int
+	errorPercent  int
+	attributes    []string // 额外属性键
+	valueFiller   string   // 额外属性值的填充部分
+	series        int
+	metricNames   []string
+	resources     [][]byte // 每个服务的Resource编码
+	scope         []byte   // InstrumentationScope编码
+}
+
+// NewPayloadBuilder 按配置创建请求生成器
+func NewPayloadBuilder(cfg *config.OTLPConfig) *PayloadBuilder {
+	specific := cfg.OTLPSpecific
+	builder := &PayloadBuilder{
+		traces:        cfg.BenchMark.TestCase != "metrics",
+		batchSize:     specific.BatchSize,
+		spansPerTrace: specific.SpansPerTrace,
+		errorPercent:  specific.ErrorPercent,
+		valueFiller:   strings.Repeat("x", specific.AttributeSize),
+		series:        specific.Series,
+	}
+	for i := 0; i < specific.Attributes; i++ {
+		builder.attributes = append(builder.attributes, fmt.Sprintf("abc.attr.%d", i))
+	}
+	for i := 0; i < specific.MetricNames; i++ {
+		builder.metricNames = append(builder.metricNames, fmt.Sprintf("%s.%d", specific.MetricPrefix, i))
+	}
+
+	for service := 0; service < specific.Services; service++ {
+		name := specific.ServiceName
+		if specific.Services > 1 {
+			name = fmt.Sprintf("%s-%d", specific.ServiceName, service)
+		}
+		var resource []byte
+		resource = appendKeyValue(resource, fieldResourceAttributes, "service.name", stringValue(name))
+		resource = appendKeyValue(resource, fieldResourceAttributes, "telemetry.sdk.name", stringValue("abc-runner"))
+		for i := 0; i < specific.ResourceLabels; i++ {
+			resource = appendKeyValue(resource, fieldResourceAttributes,
+				fmt.Sprintf("abc.resource.%d", i), stringValue(fmt.Sprintf("value-%d", i)))
+		}
+		builder.resources = append(builder.resources, resource)
+	}
+
+	builder.scope = protowire.AppendTag(nil, fieldScopeName, protowire.BytesType)
+	builder.scope = protowire.AppendString(builder.scope, specific.ScopeName)
+	builder.scope = protowire.AppendTag(builder.scope, fieldScopeVersion, protowire.BytesType)
+	builder.scope = protowire.AppendString(builder.scope, "1.0.0")
+	return builder
+}
+
+// Build 生成第index个请求，返回请求体与其中的span（或数据点）数
+func (b *PayloadBuilder) Build(index int, now time.Time) ([]byte, int) {
+	var items []byte
+	if b.traces {
+		items = b.buildSpans(index, now)
+	} else {
+		items = b.buildMetrics(index, now)
+	}
+
+	scopeData := appendMessage(nil, fieldScope, b.scope)
+	scopeData = append(scopeData, items...)
+
+	resourceData := appendMessage(nil, fieldResource, b.resources[index%len(b.resources)])
+	resourceData = appendMessage(resourceData, fieldScopeData, scopeData)
+
+	return appendMessage(nil, fieldResourceData, resourceData), b.batchSize
+}
+
+// buildSpans 生成一批span，每个trace由一个SERVER根span和若干子span组成
+func (b *PayloadBuilder) buildSpans(index int, now time.Time) []byte {
+	var spans []byte
+	var traceID [16]byte
+	var rootID [8]byte
+	var rootStart, rootEnd int64
+	traceError := false
+
+	for i := 0; i < b.batchSize; i++ {
+		position := i % b.spansPerTrace
+		var span []byte
+		var spanID [8]byte
+		binary.BigEndian.PutUint64(spanID[:], rand.Uint64())
+
+		if position == 0 {
+			binary.BigEndian.PutUint64(traceID[:8], rand.Uint64())
+			binary.BigEndian.PutUint64(traceID[8:], rand.Uint64())
+			rootID = spanID
+			duration := int64(5*time.Millisecond) + rand.Int63n(int64(95*time.Millisecond))
+			rootEnd = now.UnixNano()
+			rootStart = rootEnd - duration
+			traceError = b.errorPercent > 0 && rand.Intn(100) < b.errorPercent
+		}
+
+		span = appendBytesField(span, fieldSpanTraceID, traceID[:])
+		span = appendBytesField(span, fieldSpanID, spanID[:])
+		start, end := rootStart, rootEnd
+		kind, name := spanKindServer, "GET /api/resource"
+		if position > 0 {
+			span = appendBytesField(span, fieldSpanParentID, rootID[:])
+			// 子span落在根span的时间范围内
+			window := rootEnd - rootStart
+			start = rootStart + rand.Int63n(window/2+1)
+			end = start + rand.Int63n(rootEnd-start+1)
+			kind, name = spanKindInternal, "operation-"+strconv.Itoa(position)
+			if position%2 == 1 {
+				kind, name = spanKindClient, "SELECT resource"
+			}
+		}
+		span = protowire.AppendTag(span, fieldSpanName, protowire.BytesType)
+		span = protowire.AppendString(span, name)
+		span = protowire.AppendTag(span, fieldSpanKind, protowire.VarintType)
+		span = protowire.AppendVarint(span, uint64(kind))
+		span = appendFixed64(span, fieldSpanStartTime, uint64(start))
+		span = appendFixed64(span, fieldSpanEndTime, uint64(end))
+
+		sequence := index*b.batchSize + i
+		span = appendKeyValue(span, fieldSpanAttributes, "abc.sequence", intValue(int64(sequence)))
+		span = b.appendAttributes(span, fieldSpanAttributes, sequence)
+
+		if traceError && position == 0 {
+			var status []byte
+			status = protowire.AppendTag(status, fieldStatusMessage, protowire.BytesType)
+			status = protowire.AppendString(status, "simulated error")
+			status = protowire.AppendTag(status, fieldStatusCode, protowire.VarintType)
+			status = protowire.AppendVarint(status, statusCodeError)
+			span = appendMessage(span, fieldSpanStatus, status)
+		}
+
+		spans = appendMessage(spans, fieldScopeItem, span)
+	}
+	return spans
+}
+
+// buildMetrics 生成一批gauge数据点，数据点按序列编号轮转覆盖全部序列并按指标名分组
+func (b *PayloadBuilder) buildMetrics(index int, now time.Time) []byte {
+	points := make([][]byte, len(b.metricNames))
+	timestamp := uint64(now.UnixNano())
+	for i := 0; i < b.batchSize; i++ {
+		series := (index*b.batchSize + i) % b.series
+		metric := series % len(b.metricNames)
+
+		var point []byte
+		point = appendFixed64(point, fieldPointTime, timestamp)
+		point = appendFixed64(point, fieldPointAsDouble, math.Float64bits(rand.Float64()*1000))
+		point = appendKeyValue(point, fieldPointAttributes, "abc.series", intValue(int64(series)))
+		point = b.appendAttributes(point, fieldPointAttributes, series)
+
+		points[metric] = appendMessage(points[metric], fieldGaugeDataPoints, point)
+	}
+
+	var metrics []byte
+	for i, gauge := range points {
+		if gauge == nil {
+			continue
+		}
+		var metric []byte
+		metric = protowire.AppendTag(metric, fieldMetricName, protowire.BytesType)
+		metric = protowire.AppendString(metric, b.metricNames[i])
+		metric = protowire.AppendTag(metric, fieldMetricUnit, protowire.BytesType)
+		metric = protowire.AppendString(metric, "1")
+		metric = appendMessage(metric, fieldMetricGauge, gauge)
+		metrics = appendMessage(metrics, fieldScopeItem, metric)
+	}
+	return metrics
+}
+
+// appendAttributes 追加额外属性，值以id结尾，metrics中id为序列编号以保持基数稳定
+func (b *PayloadBuilder) appendAttributes(data []byte, num protowire.Number, id int) []byte {
+	if len(b.attributes) == 0 {
+		return data
+	}
+	suffix := strconv.Itoa(id)
+	filler := b.valueFiller
+	if len(filler) > len(suffix) {
+		filler = filler[:len(filler)-len(suffix)]
+	} else {
+		filler = ""
+	}
+	for _, key := range b.attributes {
+		data = appendKeyValue(data, num, key, stringValue(filler+suffix))
+	}
+	return data
+}
+
+// ParsePartialSuccess 解析Export响应中的partial_success，返回被拒绝的条目数与错误信息
+func ParsePartialSuccess(body []byte) (int64, string) {
+	var rejected int64
+	var message string
+	forEachField(body, func(num protowire.Number, typ protowire.Type, value []byte, varint uint64) {
+		if num != fieldPartialSuccess || typ != protowire.BytesType {
+			return
+		}
+		forEachField(value, func(num protowire.Number, typ protowire.Type, value []byte, varint uint64) {
+			switch {
+			case num == fieldRejectedItems && typ == protowire.VarintType:
+				rejected = int64(varint)
+			case num == fieldPartialErrorMsg && typ == protowire.BytesType:
+				message = string(value)
+			}
+		})
+	})
+	return rejected, message
+}
+
+// forEachField 遍历消息字段，遇到无法解析的数据时停止
+func forEachField(data []byte, fn func(num protowire.Number, typ protowire.Type, value []byte, varint uint64)) {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return
+		}
+		data = data[n:]
+
+		var value []byte
+		var varint uint64
+		switch typ {
+		case protowire.BytesType:
+			value, n = protowire.ConsumeBytes(data)
+		case protowire.VarintType:
+			varint, n = protowire.ConsumeVarint(data)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, data)
+		}
+		if n < 0 {
+			return
+		}
+		data = data[n:]
+		fn(num, typ, value, varint)
+	}
+}
+
+// appendMessage 追加嵌套消息字段
+func appendMessage(data []byte, num protowire.Number, message []byte) []byte {
+	data = protowire.AppendTag(data, num, protowire.BytesType)
+	return protowire.AppendBytes(data, message)
+}
+
+// appendBytesField 追加bytes字段
+func appendBytesField(data []byte, num protowire.Number, value []byte) []byte {
+	data = protowire.AppendTag(data, num, protowire.BytesType)
+	return protowire.AppendBytes(data, value)
+}
+
+// appendFixed64 追加fixed64字段
+func appendFixed64(data []byte, num protowire.Number, value uint64) []byte {
+	data = protowire.AppendTag(data, num, protowire.Fixed64Type)
+	return protowire.AppendFixed64(data, value)
+}
+
+// appendKeyValue 追加KeyValue字段，value为已编码的AnyValue
+func appendKeyValue(data []byte, num protowire.Number, key string, value []byte) []byte {
+	var keyValue []byte
+	keyValue = protowire.AppendTag(keyValue, fieldKey, protowire.BytesType)
+	keyValue = protowire.AppendString(keyValue, key)
+	keyValue = appendMessage(keyValue, fieldValue, value)
+	return appendMessage(data, num, keyValue)
+}
+
+// stringValue 编码字符串AnyValue
+func stringValue(value string) []byte {
+	data := protowire.AppendTag(nil, fieldStringValue, protowire.BytesType)
+	return protowire.AppendString(data, value)
+}
+
+// intValue 编码整数AnyValue
+func intValue(value int64) []byte {
+	data := protowire.AppendTag(nil, fieldIntValue, protowire.VarintType)
+	return protowire.AppendVarint(data, uint64(value))
+}
@@ -0,0 +1,105 @@
+package operations
+
+import (
+	"sync"
+
+	"abc-runner/app/adapters/otlp/connection"
+)
+
+// ExportStats OTLP导出统计，条目为span（traces）或数据点（metrics）
+type ExportStats struct {
+	Requests          int64             `json:"requests"`
+	Accepted          int64             `json:"accepted"`         // 成功响应（含部分成功）
+	PartialSuccess    int64             `json:"partial_success"`  // 成功响应中带有rejected条目的请求
+	Retryable         int64             `json:"retryable"`        // 限流或服务暂不可用，按OTLP规范应重试
+	Permanent         int64             `json:"permanent"`        // 不可重试的失败
+	TransportErrors   int64             `json:"transport_errors"` // 未收到响应
+	RetryableRate     float64           `json:"retryable_rate"`
+	ItemsSent         int64             `json:"items_sent"`
+	ItemsAccepted     int64             `json:"items_accepted"`
+	ItemsRejected     int64             `json:"items_rejected"` // partial_success中报告的拒绝条目
+	BytesSent         int64             `json:"bytes_sent"`     // 压缩后的请求体字节数（仅HTTP）
+	UncompressedBytes int64             `json:"uncompressed_bytes"`
+	Statuses          map[string]int64  `json:"statuses"`
+	ErrorSamples      map[string]string `json:"error_samples,omitempty"` // 每个失败状态首次出现时的信息
+}
+
+// ExportTracker 统计导出请求的响应分布
+type ExportTracker struct {
+	mutex sync.Mutex
+	stats ExportStats
+}
+
+// NewExportTracker 创建导出统计器
+func NewExportTracker() *ExportTracker {
+	return &ExportTracker{
+		stats: ExportStats{
+			Statuses:     make(map[string]int64),
+			ErrorSamples: make(map[string]string),
+		},
+	}
+}
+
+// Record 记录一次导出，response为nil表示未收到响应
+func (t *ExportTracker) Record(response *connection.ExportResponse, items, uncompressed int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.stats.Requests++
+	t.stats.ItemsSent += int64(items)
+	t.stats.UncompressedBytes += int64(uncompressed)
+	if response == nil {
+		t.stats.TransportErrors++
+		return
+	}
+	t.stats.BytesSent += int64(response.WireBytes)
+	t.stats.Statuses[response.Status]++
+
+	switch {
+	case response.Success:
+		t.stats.Accepted++
+		rejected, message := ParsePartialSuccess(response.Body)
+		if rejected > int64(items) {
+			rejected = int64(items)
+		}
+		if rejected > 0 || message != "" {
+			t.stats.PartialSuccess++
+			t.stats.ItemsRejected += rejected
+			t.sample("partial_success", message)
+		}
+		t.stats.ItemsAccepted += int64(items) - rejected
+	case response.Retryable:
+		t.stats.Retryable++
+		t.sample(response.Status, response.Message)
+	default:
+		t.stats.Permanent++
+		t.sample(response.Status, response.Message)
+	}
+}
+
+// sample 保留每个状态首次出现时的信息
+func (t *ExportTracker) sample(status, message string) {
+	if _, ok := t.stats.ErrorSamples[status]; !ok {
+		t.stats.ErrorSamples[status] = message
+	}
+}
+
+// Stats 获取统计快照
+func (t *ExportTracker) Stats() ExportStats {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	stats := t.stats
+	stats.Statuses = make(map[string]int64, len(t.stats.Statuses))
+	for status, count := range t.stats.Statuses {
+		stats.Statuses[status] = count
+	}
+	stats.ErrorSamples = make(map[string]string, len(t.stats.ErrorSamples))
+	for status, message := range t.stats.ErrorSamples {
+		stats.ErrorSamples[status] = message
+	}
+	if stats.Requests > 0 {
+		stats.RetryableRate = float64(stats.Retryable) / float64(stats.Requests) * 100
+	}
+	return stats
+}
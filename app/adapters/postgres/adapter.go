@@ -0,0 +1,190 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"abc-runner/app/adapters/postgres/config"
+	"abc-runner/app/adapters/postgres/connection"
+	"abc-runner/app/adapters/postgres/operations"
+	"abc-runner/app/core/interfaces"
+)
+
+// PostgresAdapter PostgreSQL协议适配器 - 遵循统一架构模式
+// 职责：连接池管理、状态维护、健康检查
+type PostgresAdapter struct {
+	config             *config.PostgresConfig
+	pool               *connection.Pool
+	postgresOperations *operations.PostgresExecutor
+	metricsCollector   interfaces.DefaultMetricsCollector
+	mu                 sync.RWMutex
+	isConnected        bool
+	tableCreated       bool
+
+	// 统计信息
+	totalOperations  int64
+	failedOperations int64
+}
+
+// NewPostgresAdapter 创建PostgreSQL适配器
+func NewPostgresAdapter(metricsCollector interfaces.DefaultMetricsCollector) *PostgresAdapter {
+	return &PostgresAdapter{
+		metricsCollector: metricsCollector,
+	}
+}
+
+// Connect 建立连接池，配置了setup时创建并填充测试表
+func (p *PostgresAdapter) Connect(ctx context.Context, cfg interfaces.Config) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pgConfig, ok := cfg.(*config.PostgresConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type for PostgreSQL adapter: expected *config.PostgresConfig, got %T", cfg)
+	}
+
+	if err := pgConfig.Validate(); err != nil {
+		return fmt.Errorf("config validation failed: %w", err)
+	}
+	p.config = pgConfig
+
+	pool, err := connection.NewPool(ctx, pgConfig)
+	if err != nil {
+		return err
+	}
+
+	if pgConfig.PostgresSpecific.Setup {
+		if err := p.setup(ctx, pool); err != nil {
+			pool.Close()
+			return err
+		}
+	}
+
+	p.pool = pool
+	p.postgresOperations = operations.NewPostgresExecutor(pool, pgConfig)
+	p.isConnected = true
+	return nil
+}
+
+// setup 使用池中的一个连接建表并填充数据
+func (p *PostgresAdapter) setup(ctx context.Context, pool *connection.Pool) error {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer pool.Release(conn)
+
+	created, err := operations.Setup(ctx, conn, p.config)
+	p.tableCreated = created
+	return err
+}
+
+// Execute 执行操作 - 使用执行器处理
+func (p *PostgresAdapter) Execute(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	if !p.isConnected {
+		return nil, fmt.Errorf("adapter not connected")
+	}
+
+	atomic.AddInt64(&p.totalOperations, 1)
+	result, err := p.postgresOperations.ExecuteOperation(ctx, operation)
+	if err != nil {
+		atomic.AddInt64(&p.failedOperations, 1)
+	}
+	return result, err
+}
+
+// Close 关闭连接池
+func (p *PostgresAdapter) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.pool != nil {
+		p.pool.Close()
+		p.pool = nil
+	}
+	p.isConnected = false
+	return nil
+}
+
+// GetProtocolMetrics 获取协议特定指标
+func (p *PostgresAdapter) GetProtocolMetrics() map[string]interface{} {
+	metrics := map[string]interface{}{
+		"protocol":          "postgres",
+		"total_operations":  atomic.LoadInt64(&p.totalOperations),
+		"failed_operations": atomic.LoadInt64(&p.failedOperations),
+	}
+
+	if p.config != nil {
+		metrics["prepared"] = p.config.PostgresSpecific.Prepared
+		metrics["transaction"] = p.config.PostgresSpecific.Transaction
+	}
+	if p.pool != nil {
+		metrics["server_version"] = p.pool.ServerVersion()
+		metrics["pool_stats"] = p.pool.Stats()
+	}
+	if stats := p.GetQueryStats(); stats != nil {
+		metrics["query_stats"] = stats
+	}
+
+	return metrics
+}
+
+// GetQueryStats 获取按查询类型的统计，未连接时返回nil
+func (p *PostgresAdapter) GetQueryStats() []operations.QueryStats {
+	if p.postgresOperations == nil {
+		return nil
+	}
+	return p.postgresOperations.QueryStats()
+}
+
+// GetPoolStats 获取连接池统计，未连接时返回nil
+func (p *PostgresAdapter) GetPoolStats() *connection.PoolStats {
+	if p.pool == nil {
+		return nil
+	}
+	stats := p.pool.Stats()
+	return &stats
+}
+
+// ServerVersion 服务端版本，未连接时为空
+func (p *PostgresAdapter) ServerVersion() string {
+	if p.pool == nil {
+		return ""
+	}
+	return p.pool.ServerVersion()
+}
+
+// TableCreated 本次运行是否由setup新建了测试表
+func (p *PostgresAdapter) TableCreated() bool {
+	return p.tableCreated
+}
+
+// HealthCheck 健康检查：在池中的连接上执行SELECT 1
+func (p *PostgresAdapter) HealthCheck(ctx context.Context) error {
+	if !p.isConnected {
+		return fmt.Errorf("adapter not connected")
+	}
+
+	conn, err := p.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer p.pool.Release(conn)
+
+	if _, err := conn.SimpleQuery(ctx, "SELECT 1"); err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	return nil
+}
+
+// GetProtocolName 获取协议名称
+func (p *PostgresAdapter) GetProtocolName() string {
+	return "postgres"
+}
+
+// GetMetricsCollector 获取指标收集器
+func (p *PostgresAdapter) GetMetricsCollector() interfaces.DefaultMetricsCollector {
+	return p.metricsCollector
+}
@@ -0,0 +1,328 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+)
+
+// 查询类型
+const (
+	QuerySelect = "select"
+	QueryInsert = "insert"
+	QueryUpdate = "update"
+)
+
+// 默认查询，参数形态固定：select($1=id)、insert($1=k, $2=payload)、update($1=delta, $2=id)
+// 自定义查询需保持相同的参数个数与含义
+const (
+	DefaultTable       = "abc_bench"
+	defaultSelectQuery = "SELECT id, k, payload FROM %s WHERE id = $1"
+	defaultInsertQuery = "INSERT INTO %s (k, payload) VALUES ($1, $2)"
+	defaultUpdateQuery = "UPDATE %s SET k = k + $1 WHERE id = $2"
+)
+
+// PostgresConfig PostgreSQL协议配置
+type PostgresConfig struct {
+	Protocol         string                 `yaml:"protocol" json:"protocol"`
+	Connection       ConnectionConfig       `yaml:"connection" json:"connection"`
+	BenchMark        BenchmarkConfig        `yaml:"benchmark" json:"benchmark"`
+	PostgresSpecific PostgresSpecificConfig `yaml:"postgres_specific" json:"postgres_specific"`
+}
+
+// ConnectionConfig PostgreSQL连接配置
+type ConnectionConfig struct {
+	Address  string        `yaml:"address" json:"address"`
+	Port     int           `yaml:"port" json:"port"`
+	User     string        `yaml:"user" json:"user"`
+	Password string        `yaml:"password" json:"password"`
+	Database string        `yaml:"database" json:"database"`
+	SSLMode  string        `yaml:"sslmode" json:"sslmode"`     // disable、prefer或require（require不校验证书）
+	PoolSize int           `yaml:"pool_size" json:"pool_size"` // 连接池大小，0表示与并发数相同
+	Timeout  time.Duration `yaml:"timeout" json:"timeout"`     // 建连与单条语句的超时
+}
+
+// BenchmarkConfig PostgreSQL基准测试配置
+type BenchmarkConfig struct {
+	Total     int           `yaml:"total" json:"total"`
+	Parallels int           `yaml:"parallels" json:"parallels"`
+	TestCase  string        `yaml:"test_case" json:"test_case"` // select、insert、update或mixed
+	Duration  time.Duration `yaml:"duration" json:"duration"`
+}
+
+// PostgresSpecificConfig PostgreSQL特定配置
+type PostgresSpecificConfig struct {
+	Table        string `yaml:"table" json:"table"`                 // 默认查询使用的表
+	Rows         int    `yaml:"rows" json:"rows"`                   // select/update随机访问的id范围[1, rows]
+	Setup        bool   `yaml:"setup" json:"setup"`                 // 运行前创建表并填充rows行（表已存在时跳过）
+	PayloadSize  int    `yaml:"payload_size" json:"payload_size"`   // insert与填充时payload列的字节数
+	Prepared     bool   `yaml:"prepared" json:"prepared"`           // 每个连接预编译一次语句后复用；否则每次使用未命名语句
+	Transaction  bool   `yaml:"transaction" json:"transaction"`     // 每个操作包裹在BEGIN/COMMIT中
+	TxStatements int    `yaml:"tx_statements" json:"tx_statements"` // 事务模式下每个事务执行的语句数
+
+	// Mix mixed用例中各查询类型的权重
+	Mix MixConfig `yaml:"mix" json:"mix"`
+
+	// Queries 自定义查询，为空时使用针对table的默认查询
+	Queries QueriesConfig `yaml:"queries" json:"queries"`
+}
+
+// MixConfig mixed用例的查询权重
+type MixConfig struct {
+	Select int `yaml:"select" json:"select"`
+	Insert int `yaml:"insert" json:"insert"`
+	Update int `yaml:"update" json:"update"`
+}
+
+// QueriesConfig 自定义查询
+type QueriesConfig struct {
+	Select string `yaml:"select" json:"select"`
+	Insert string `yaml:"insert" json:"insert"`
+	Update string `yaml:"update" json:"update"`
+}
+
+// NewDefaultPostgresConfig 创建默认PostgreSQL配置
+func NewDefaultPostgresConfig() *PostgresConfig {
+	return &PostgresConfig{
+		Protocol: "postgres",
+		Connection: ConnectionConfig{
+			Address:  "localhost",
+			Port:     5432,
+			User:     "postgres",
+			Database: "postgres",
+			SSLMode:  "disable",
+			Timeout:  5 * time.Second,
+		},
+		BenchMark: BenchmarkConfig{
+			Total:     1000,
+			Parallels: 10,
+			TestCase:  QuerySelect,
+		},
+		PostgresSpecific: PostgresSpecificConfig{
+			Table:        DefaultTable,
+			Rows:         10000,
+			PayloadSize:  100,
+			TxStatements: 1,
+			Mix:          MixConfig{Select: 80, Insert: 10, Update: 10},
+		},
+	}
+}
+
+// GetQuery 获取查询类型对应的SQL，未自定义时使用默认查询
+func (c *PostgresConfig) GetQuery(queryType string) string {
+	table := c.PostgresSpecific.Table
+	queries := c.PostgresSpecific.Queries
+	switch queryType {
+	case QuerySelect:
+		if queries.Select != "" {
+			return queries.Select
+		}
+		return fmt.Sprintf(defaultSelectQuery, table)
+	case QueryInsert:
+		if queries.Insert != "" {
+			return queries.Insert
+		}
+		return fmt.Sprintf(defaultInsertQuery, table)
+	case QueryUpdate:
+		if queries.Update != "" {
+			return queries.Update
+		}
+		return fmt.Sprintf(defaultUpdateQuery, table)
+	}
+	return ""
+}
+
+// GetPoolSize 获取连接池大小，未配置时与并发数相同
+func (c *PostgresConfig) GetPoolSize() int {
+	if c.Connection.PoolSize > 0 {
+		return c.Connection.PoolSize
+	}
+	return c.BenchMark.Parallels
+}
+
+// GetProtocol 实现Config接口
+func (c *PostgresConfig) GetProtocol() string {
+	return c.Protocol
+}
+
+// GetConnection 实现Config接口
+func (c *PostgresConfig) GetConnection() interfaces.ConnectionConfig {
+	return &c.Connection
+}
+
+// GetBenchmark 实现Config接口
+func (c *PostgresConfig) GetBenchmark() interfaces.BenchmarkConfig {
+	return &c.BenchMark
+}
+
+// Validate 实现Config接口
+func (c *PostgresConfig) Validate() error {
+	if c.Connection.Address == "" {
+		return fmt.Errorf("connection address cannot be empty")
+	}
+	if c.Connection.Port <= 0 || c.Connection.Port > 65535 {
+		return fmt.Errorf("invalid port number: %d", c.Connection.Port)
+	}
+	if c.Connection.User == "" {
+		return fmt.Errorf("user cannot be empty")
+	}
+	if c.Connection.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+	if c.Connection.PoolSize < 0 {
+		return fmt.Errorf("pool size cannot be negative")
+	}
+	switch c.Connection.SSLMode {
+	case "disable", "prefer", "require":
+	default:
+		return fmt.Errorf("invalid sslmode: %s, valid options: disable, prefer, require", c.Connection.SSLMode)
+	}
+
+	if c.BenchMark.Total <= 0 {
+		return fmt.Errorf("total operations must be greater than 0")
+	}
+	if c.BenchMark.Parallels <= 0 {
+		return fmt.Errorf("parallel connections must be greater than 0")
+	}
+
+	validTestCases := []string{QuerySelect, QueryInsert, QueryUpdate, "mixed"}
+	valid := false
+	for _, testCase := range validTestCases {
+		if c.BenchMark.TestCase == testCase {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Errorf("invalid test case: %s, valid options: %s",
+			c.BenchMark.TestCase, strings.Join(validTestCases, ", "))
+	}
+
+	specific := c.PostgresSpecific
+	if specific.Table == "" {
+		return fmt.Errorf("table cannot be empty")
+	}
+	if specific.Rows <= 0 {
+		return fmt.Errorf("rows must be greater than 0")
+	}
+	if specific.PayloadSize < 0 {
+		return fmt.Errorf("payload size cannot be negative")
+	}
+	if specific.Transaction && specific.TxStatements <= 0 {
+		return fmt.Errorf("tx statements must be greater than 0")
+	}
+	if c.BenchMark.TestCase == "mixed" {
+		mix := specific.Mix
+		if mix.Select < 0 || mix.Insert < 0 || mix.Update < 0 {
+			return fmt.Errorf("mix weights cannot be negative")
+		}
+		if mix.Select+mix.Insert+mix.Update == 0 {
+			return fmt.Errorf("mix weights cannot all be zero")
+		}
+	}
+
+	return nil
+}
+
+// Clone 实现Config接口
+func (c *PostgresConfig) Clone() interfaces.Config {
+	clone := *c
+	return &clone
+}
+
+// ConnectionConfig接口实现
+
+// GetAddresses 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetAddresses() []string {
+	return []string{net.JoinHostPort(c.Address, strconv.Itoa(c.Port))}
+}
+
+// GetCredentials 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetCredentials() map[string]string {
+	return map[string]string{
+		"user":     c.User,
+		"password": c.Password,
+		"database": c.Database,
+	}
+}
+
+// GetPoolConfig 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetPoolConfig() interfaces.PoolConfig {
+	return &PoolConfig{size: c.PoolSize, timeout: c.Timeout}
+}
+
+// GetTimeout 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetTimeout() time.Duration {
+	return c.Timeout
+}
+
+// PoolConfig 连接池配置（连接在启动时全部建立，不做空闲回收）
+type PoolConfig struct {
+	size    int
+	timeout time.Duration
+}
+
+func (p *PoolConfig) GetPoolSize() int                    { return p.size }
+func (p *PoolConfig) GetMinIdle() int                     { return p.size }
+func (p *PoolConfig) GetMaxIdle() int                     { return p.size }
+func (p *PoolConfig) GetIdleTimeout() time.Duration       { return 0 }
+func (p *PoolConfig) GetConnectionTimeout() time.Duration { return p.timeout }
+
+// BenchmarkConfig接口实现
+
+// GetTotal 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTotal() int {
+	return b.Total
+}
+
+// GetParallels 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetParallels() int {
+	return b.Parallels
+}
+
+// GetDataSize 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetDataSize() int {
+	return 0
+}
+
+// GetTTL 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTTL() time.Duration {
+	return 0
+}
+
+// GetReadPercent 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetReadPercent() int {
+	if b.TestCase == QuerySelect {
+		return 100
+	}
+	return 0
+}
+
+// GetRandomKeys 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetRandomKeys() int {
+	return 0
+}
+
+// GetTestCase 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTestCase() string {
+	return b.TestCase
+}
+
+// GetDuration 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetDuration() time.Duration {
+	return b.Duration
+}
+
+// GetTimeout 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTimeout() time.Duration {
+	return 0
+}
+
+// GetRampUp 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetRampUp() time.Duration {
+	return 0
+}
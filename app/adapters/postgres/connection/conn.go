@@ -0,0 +1,347 @@
+package connection
+
+import (
+	"bufio"
+	"context"
+	"crypto/md5"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"abc-runner/app/adapters/postgres/config"
+
+	"github.com/xdg-go/scram"
+)
+
+// ErrBadConn 连接在I/O中途失败，不能再复用
+var ErrBadConn = errors.New("postgres connection is broken")
+
+// Conn 单个PostgreSQL连接，使用v3协议的简单查询与扩展查询
+// 不是并发安全的，由连接池保证同一时刻只有一个使用者
+type Conn struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	timeout time.Duration
+
+	// statements 已在本连接上预编译的语句：SQL → 语句名
+	statements    map[string]string
+	nextStatement int
+
+	serverParams map[string]string
+	txStatus     byte
+	broken       bool
+}
+
+// Dial 建立连接并完成SSL协商、启动与认证
+func Dial(ctx context.Context, cfg *config.PostgresConfig) (*Conn, error) {
+	address := net.JoinHostPort(cfg.Connection.Address, strconv.Itoa(cfg.Connection.Port))
+	dialer := &net.Dialer{Timeout: cfg.Connection.Timeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	if tcp, ok := netConn.(*net.TCPConn); ok {
+		tcp.SetNoDelay(true)
+	}
+
+	c := &Conn{
+		conn:         netConn,
+		timeout:      cfg.Connection.Timeout,
+		statements:   make(map[string]string),
+		serverParams: make(map[string]string),
+	}
+	c.conn.SetDeadline(time.Now().Add(cfg.Connection.Timeout))
+
+	if cfg.Connection.SSLMode != "disable" {
+		if err := c.negotiateSSL(cfg); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+	}
+	c.reader = bufio.NewReaderSize(c.conn, 32*1024)
+
+	if err := c.startup(cfg); err != nil {
+		c.conn.Close()
+		return nil, err
+	}
+	c.conn.SetDeadline(time.Time{})
+	return c, nil
+}
+
+// negotiateSSL 发送SSLRequest，服务端接受时升级为TLS；prefer模式下服务端拒绝则继续明文
+func (c *Conn) negotiateSSL(cfg *config.PostgresConfig) error {
+	if _, err := c.conn.Write(newMessage(0).int32(sslRequestCode).finish()); err != nil {
+		return fmt.Errorf("failed to send SSL request: %w", err)
+	}
+	var answer [1]byte
+	if _, err := c.conn.Read(answer[:]); err != nil {
+		return fmt.Errorf("failed to read SSL response: %w", err)
+	}
+	switch answer[0] {
+	case 'S':
+		tlsConn := tls.Client(c.conn, &tls.Config{
+			ServerName:         cfg.Connection.Address,
+			InsecureSkipVerify: true, // 与libpq的sslmode=require一致：加密但不校验证书
+		})
+		if err := tlsConn.Handshake(); err != nil {
+			return fmt.Errorf("TLS handshake failed: %w", err)
+		}
+		c.conn = tlsConn
+		return nil
+	case 'N':
+		if cfg.Connection.SSLMode == "require" {
+			return fmt.Errorf("server does not support SSL, but sslmode is require")
+		}
+		return nil
+	}
+	return fmt.Errorf("unexpected SSL response %q", answer[0])
+}
+
+// startup 发送StartupMessage并完成认证，直到ReadyForQuery
+func (c *Conn) startup(cfg *config.PostgresConfig) error {
+	startup := newMessage(0).int32(protocolVersion).
+		cstring("user").cstring(cfg.Connection.User).
+		cstring("application_name").cstring("abc-runner")
+	if cfg.Connection.Database != "" {
+		startup.cstring("database").cstring(cfg.Connection.Database)
+	}
+	if err := c.send(startup.cstring("").finish()); err != nil {
+		return fmt.Errorf("failed to send startup message: %w", err)
+	}
+
+	var scramConversation *scram.ClientConversation
+	for {
+		kind, body, err := readMessage(c.reader)
+		if err != nil {
+			return fmt.Errorf("failed to read startup response: %w", err)
+		}
+		switch kind {
+		case msgAuthentication:
+			if len(body) < 4 {
+				return fmt.Errorf("malformed authentication request")
+			}
+			switch code := binary.BigEndian.Uint32(body); code {
+			case authOK:
+			case authCleartext:
+				err = c.send(newMessage(msgPassword).cstring(cfg.Connection.Password).finish())
+			case authMD5:
+				if len(body) < 8 {
+					return fmt.Errorf("malformed MD5 authentication request")
+				}
+				err = c.send(newMessage(msgPassword).cstring(md5Password(cfg.Connection.User, cfg.Connection.Password, body[4:8])).finish())
+			case authSASL:
+				scramConversation, err = c.startSCRAM(cfg.Connection.Password, body[4:])
+			case authSASLContinue, authSASLFinal:
+				if scramConversation == nil {
+					return fmt.Errorf("unexpected SASL message before SASL start")
+				}
+				var response string
+				response, err = scramConversation.Step(string(body[4:]))
+				if err == nil && code == authSASLContinue {
+					err = c.send(newMessage(msgPassword).bytes([]byte(response)).finish())
+				}
+			default:
+				return fmt.Errorf("unsupported authentication method %d", code)
+			}
+			if err != nil {
+				return fmt.Errorf("authentication failed: %w", err)
+			}
+		case msgParameterStatus:
+			if name := indexZero(body); name >= 0 {
+				value := body[name+1:]
+				if end := indexZero(value); end >= 0 {
+					c.serverParams[string(body[:name])] = string(value[:end])
+				}
+			}
+		case msgBackendKeyData, msgNoticeResponse:
+		case msgErrorResponse:
+			return parseError(body)
+		case msgReadyForQuery:
+			if len(body) > 0 {
+				c.txStatus = body[0]
+			}
+			return nil
+		default:
+			return fmt.Errorf("unexpected message %q during startup", kind)
+		}
+	}
+}
+
+// startSCRAM 选择SCRAM-SHA-256并发送SASLInitialResponse
+// PostgreSQL忽略SCRAM消息中的用户名，以启动消息中的用户为准
+func (c *Conn) startSCRAM(password string, mechanisms []byte) (*scram.ClientConversation, error) {
+	supported := false
+	for len(mechanisms) > 0 && mechanisms[0] != 0 {
+		end := indexZero(mechanisms)
+		if end < 0 {
+			break
+		}
+		supported = supported || string(mechanisms[:end]) == "SCRAM-SHA-256"
+		mechanisms = mechanisms[end+1:]
+	}
+	if !supported {
+		return nil, fmt.Errorf("server requires a SASL mechanism other than SCRAM-SHA-256")
+	}
+
+	client, err := scram.SHA256.NewClient("", password, "")
+	if err != nil {
+		return nil, err
+	}
+	conversation := client.NewConversation()
+	first, err := conversation.Step("")
+	if err != nil {
+		return nil, err
+	}
+	initial := newMessage(msgPassword).cstring("SCRAM-SHA-256").int32(len(first)).bytes([]byte(first))
+	return conversation, c.send(initial.finish())
+}
+
+// md5Password 计算MD5认证的口令："md5" + md5(md5(password + user) + salt)
+func md5Password(user, password string, salt []byte) string {
+	inner := md5.Sum([]byte(password + user))
+	outer := md5.Sum(append([]byte(hex.EncodeToString(inner[:])), salt...))
+	return "md5" + hex.EncodeToString(outer[:])
+}
+
+// ServerVersion 服务端版本（server_version参数）
+func (c *Conn) ServerVersion() string {
+	return c.serverParams["server_version"]
+}
+
+// SetTimeout 设置单次往返的超时，如填充数据等长耗时语句需要放宽
+func (c *Conn) SetTimeout(timeout time.Duration) {
+	c.timeout = timeout
+}
+
+// Broken 连接是否已在I/O中途失败
+func (c *Conn) Broken() bool {
+	return c.broken
+}
+
+// InTransaction 最近一次ReadyForQuery报告的事务状态是否为事务中（含失败的事务）
+func (c *Conn) InTransaction() bool {
+	return c.txStatus == 'T' || c.txStatus == 'E'
+}
+
+// SimpleQuery 使用简单查询协议执行SQL（可含多条语句），返回最后一条语句的结果
+func (c *Conn) SimpleQuery(ctx context.Context, sql string) (Result, error) {
+	return c.roundTrip(ctx, newMessage(msgQuery).cstring(sql).finish())
+}
+
+// Exec 使用扩展查询协议执行参数化语句，参数以文本格式发送
+// prepare为true时语句在本连接上只Parse一次并按名称复用，否则每次使用未命名语句
+func (c *Conn) Exec(ctx context.Context, sql string, args []string, prepare bool) (Result, error) {
+	var batch []byte
+	statement, prepared := "", false
+	if prepare {
+		statement, prepared = c.statements[sql]
+		if !prepared {
+			c.nextStatement++
+			statement = "abc_" + strconv.Itoa(c.nextStatement)
+			batch = append(batch, parseMessage(statement, sql)...)
+		}
+	} else {
+		batch = append(batch, parseMessage("", sql)...)
+	}
+
+	bind := newMessage(msgBind).cstring("").cstring(statement).int16(0).int16(len(args))
+	for _, arg := range args {
+		bind.int32(len(arg)).bytes([]byte(arg))
+	}
+	batch = append(batch, bind.int16(0).finish()...)
+	batch = append(batch, newMessage(msgExecute).cstring("").int32(0).finish()...)
+	batch = append(batch, newMessage(msgSync).finish()...)
+
+	result, err := c.roundTrip(ctx, batch)
+	// 即使执行失败，只要收到ParseComplete，命名语句就已存在于服务端
+	if prepare && !prepared && result.parsed {
+		c.statements[sql] = statement
+	}
+	return result, err
+}
+
+func parseMessage(name, sql string) []byte {
+	return newMessage(msgParse).cstring(name).cstring(sql).int16(0).finish()
+}
+
+// roundTrip 发送消息并读取到ReadyForQuery，返回最后一条CommandComplete的结果与首个错误
+// ctx取消或超时会中断I/O，此时连接标记为不可复用
+func (c *Conn) roundTrip(ctx context.Context, batch []byte) (Result, error) {
+	if c.broken {
+		return Result{}, ErrBadConn
+	}
+	deadline := time.Now().Add(c.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	c.conn.SetDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() {
+		c.conn.SetDeadline(time.Unix(1, 0))
+	})
+	defer stop()
+
+	if err := c.send(batch); err != nil {
+		return Result{}, c.fail(ctx, err)
+	}
+
+	result := Result{BytesSent: int64(len(batch))}
+	var firstErr error
+	for {
+		kind, body, err := readMessage(c.reader)
+		if err != nil {
+			return result, c.fail(ctx, err)
+		}
+		result.BytesReceived += int64(5 + len(body))
+		switch kind {
+		case msgDataRow:
+			result.Rows++
+		case msgCommandComplete:
+			if end := indexZero(body); end >= 0 {
+				result.Tag = string(body[:end])
+			}
+		case msgErrorResponse:
+			if firstErr == nil {
+				firstErr = parseError(body)
+			}
+		case msgReadyForQuery:
+			if len(body) > 0 {
+				c.txStatus = body[0]
+			}
+			return result, firstErr
+		case msgParseComplete:
+			result.parsed = true
+		case msgRowDescription, msgBindComplete, msgNoData, msgParameterDescription,
+			msgEmptyQueryResponse, msgNoticeResponse, msgParameterStatus, msgNotification:
+		default:
+			return result, c.fail(ctx, fmt.Errorf("unexpected message %q", kind))
+		}
+	}
+}
+
+// fail 标记连接不可复用，ctx已结束时返回ctx的错误
+func (c *Conn) fail(ctx context.Context, err error) error {
+	c.broken = true
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return fmt.Errorf("%w: %v", ErrBadConn, err)
+}
+
+func (c *Conn) send(data []byte) error {
+	_, err := c.conn.Write(data)
+	return err
+}
+
+// Close 发送Terminate并关闭连接
+func (c *Conn) Close() error {
+	if !c.broken {
+		c.conn.SetWriteDeadline(time.Now().Add(time.Second))
+		c.send(newMessage(msgTerminate).finish())
+	}
+	return c.conn.Close()
+}
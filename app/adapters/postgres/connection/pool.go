@@ -0,0 +1,139 @@
+package connection
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"abc-runner/app/adapters/postgres/config"
+)
+
+// Pool 固定大小的连接池
+// 启动时建立全部连接，使测量不含建连耗时；失效的连接在下次取用时重建
+type Pool struct {
+	cfg   *config.PostgresConfig
+	slots chan *Conn // nil表示需要重建的空位
+
+	mutex sync.Mutex
+	all   map[*Conn]struct{}
+
+	serverVersion string
+	reconnects    atomic.Int64
+	waitNanos     atomic.Int64
+	acquires      atomic.Int64
+	closeOnce     sync.Once
+}
+
+// PoolStats 连接池统计
+type PoolStats struct {
+	Size       int           `json:"size"`
+	Reconnects int64         `json:"reconnects"`
+	Acquires   int64         `json:"acquires"`
+	AvgWait    time.Duration `json:"avg_wait"` // 取用连接的平均等待时间，持续偏高说明连接池小于并发数
+}
+
+// NewPool 创建连接池并建立全部连接
+func NewPool(ctx context.Context, cfg *config.PostgresConfig) (*Pool, error) {
+	size := cfg.GetPoolSize()
+	pool := &Pool{
+		cfg:   cfg,
+		slots: make(chan *Conn, size),
+		all:   make(map[*Conn]struct{}),
+	}
+	for i := 0; i < size; i++ {
+		conn, err := pool.dial(ctx)
+		if err != nil {
+			pool.Close()
+			return nil, err
+		}
+		if i == 0 {
+			pool.serverVersion = conn.ServerVersion()
+		}
+		pool.slots <- conn
+	}
+	return pool, nil
+}
+
+func (p *Pool) dial(ctx context.Context) (*Conn, error) {
+	conn, err := Dial(ctx, p.cfg)
+	if err != nil {
+		return nil, err
+	}
+	p.mutex.Lock()
+	p.all[conn] = struct{}{}
+	p.mutex.Unlock()
+	return conn, nil
+}
+
+// Acquire 取用一个连接，空位上的失效连接在此重建；使用完毕后必须调用Release
+func (p *Pool) Acquire(ctx context.Context) (*Conn, error) {
+	start := time.Now()
+	var conn *Conn
+	select {
+	case conn = <-p.slots:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	p.waitNanos.Add(int64(time.Since(start)))
+	p.acquires.Add(1)
+
+	if conn != nil {
+		return conn, nil
+	}
+	conn, err := p.dial(ctx)
+	if err != nil {
+		p.slots <- nil
+		return nil, err
+	}
+	p.reconnects.Add(1)
+	return conn, nil
+}
+
+// Release 归还连接，失效的连接被关闭并留下空位
+func (p *Pool) Release(conn *Conn) {
+	if conn.Broken() {
+		p.mutex.Lock()
+		delete(p.all, conn)
+		p.mutex.Unlock()
+		conn.Close()
+		conn = nil
+	}
+	p.slots <- conn
+}
+
+// ServerVersion 服务端版本
+func (p *Pool) ServerVersion() string {
+	return p.serverVersion
+}
+
+// Stats 获取连接池统计
+func (p *Pool) Stats() PoolStats {
+	stats := PoolStats{
+		Size:       cap(p.slots),
+		Reconnects: p.reconnects.Load(),
+		Acquires:   p.acquires.Load(),
+	}
+	if stats.Acquires > 0 {
+		stats.AvgWait = time.Duration(p.waitNanos.Load() / stats.Acquires)
+	}
+	return stats
+}
+
+// Address 目标地址
+func (p *Pool) Address() string {
+	return p.cfg.Connection.GetAddresses()[0]
+}
+
+// Close 关闭所有连接
+func (p *Pool) Close() error {
+	p.closeOnce.Do(func() {
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+		for conn := range p.all {
+			conn.Close()
+		}
+		p.all = make(map[*Conn]struct{})
+	})
+	return nil
+}
@@ -0,0 +1,190 @@
+package connection
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// 协议版本3.0与SSL协商请求码
+const (
+	protocolVersion = 196608
+	sslRequestCode  = 80877103
+)
+
+// maxMessageSize 单条后端消息的上限，超出视为协议错误
+const maxMessageSize = 1 << 30
+
+// 前端消息类型
+const (
+	msgPassword  = 'p'
+	msgQuery     = 'Q'
+	msgParse     = 'P'
+	msgBind      = 'B'
+	msgExecute   = 'E'
+	msgSync      = 'S'
+	msgTerminate = 'X'
+)
+
+// 后端消息类型
+const (
+	msgAuthentication       = 'R'
+	msgParameterStatus      = 'S'
+	msgBackendKeyData       = 'K'
+	msgReadyForQuery        = 'Z'
+	msgErrorResponse        = 'E'
+	msgNoticeResponse       = 'N'
+	msgCommandComplete      = 'C'
+	msgDataRow              = 'D'
+	msgRowDescription       = 'T'
+	msgEmptyQueryResponse   = 'I'
+	msgParseComplete        = '1'
+	msgBindComplete         = '2'
+	msgNoData               = 'n'
+	msgParameterDescription = 't'
+	msgNotification         = 'A'
+)
+
+// 认证请求类型
+const (
+	authOK           = 0
+	authCleartext    = 3
+	authMD5          = 5
+	authSASL         = 10
+	authSASLContinue = 11
+	authSASLFinal    = 12
+)
+
+// PgError 服务端返回的ErrorResponse
+type PgError struct {
+	Severity string
+	Code     string // SQLSTATE
+	Message  string
+	Detail   string
+}
+
+func (e *PgError) Error() string {
+	if e.Detail != "" {
+		return fmt.Sprintf("%s: %s (SQLSTATE %s): %s", e.Severity, e.Message, e.Code, e.Detail)
+	}
+	return fmt.Sprintf("%s: %s (SQLSTATE %s)", e.Severity, e.Message, e.Code)
+}
+
+// parseError 解析ErrorResponse的字段列表
+func parseError(body []byte) *PgError {
+	pgErr := &PgError{}
+	for len(body) > 0 && body[0] != 0 {
+		field := body[0]
+		end := indexZero(body[1:])
+		if end < 0 {
+			break
+		}
+		value := string(body[1 : 1+end])
+		body = body[2+end:]
+		switch field {
+		case 'S':
+			pgErr.Severity = value
+		case 'C':
+			pgErr.Code = value
+		case 'M':
+			pgErr.Message = value
+		case 'D':
+			pgErr.Detail = value
+		}
+	}
+	return pgErr
+}
+
+// Result 一条语句的执行结果
+type Result struct {
+	Rows int64  // 返回的行数（DataRow）
+	Tag  string // CommandComplete标签，如"INSERT 0 1"、"UPDATE 3"
+
+	BytesSent     int64 // 发送的前端消息字节数
+	BytesReceived int64 // 读取的后端消息字节数（含消息头）
+
+	parsed bool // 收到了ParseComplete
+}
+
+// RowsAffected 从命令标签解析影响的行数，SELECT为返回的行数
+func (r Result) RowsAffected() int64 {
+	if index := strings.LastIndexByte(r.Tag, ' '); index >= 0 {
+		if n, err := strconv.ParseInt(r.Tag[index+1:], 10, 64); err == nil {
+			return n
+		}
+	}
+	return r.Rows
+}
+
+// message 前端消息编码器，长度字段在finish时回填
+type message struct {
+	buf []byte
+	off int // 长度字段的位置
+}
+
+// newMessage 创建消息，kind为0表示无类型字节的启动类消息
+func newMessage(kind byte) *message {
+	m := &message{}
+	if kind != 0 {
+		m.buf = append(m.buf, kind)
+		m.off = 1
+	}
+	m.buf = append(m.buf, 0, 0, 0, 0)
+	return m
+}
+
+func (m *message) int16(v int) *message {
+	m.buf = binary.BigEndian.AppendUint16(m.buf, uint16(v))
+	return m
+}
+
+func (m *message) int32(v int) *message {
+	m.buf = binary.BigEndian.AppendUint32(m.buf, uint32(v))
+	return m
+}
+
+func (m *message) cstring(s string) *message {
+	m.buf = append(m.buf, s...)
+	m.buf = append(m.buf, 0)
+	return m
+}
+
+func (m *message) bytes(b []byte) *message {
+	m.buf = append(m.buf, b...)
+	return m
+}
+
+// finish 回填长度（含长度字段本身，不含类型字节）
+func (m *message) finish() []byte {
+	binary.BigEndian.PutUint32(m.buf[m.off:], uint32(len(m.buf)-m.off))
+	return m.buf
+}
+
+// readMessage 读取一条后端消息
+func readMessage(reader *bufio.Reader) (byte, []byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(reader, header[:]); err != nil {
+		return 0, nil, err
+	}
+	length := int(binary.BigEndian.Uint32(header[1:]))
+	if length < 4 || length > maxMessageSize {
+		return 0, nil, fmt.Errorf("invalid message length %d for message type %q", length, header[0])
+	}
+	body := make([]byte, length-4)
+	if _, err := io.ReadFull(reader, body); err != nil {
+		return 0, nil, err
+	}
+	return header[0], body, nil
+}
+
+func indexZero(b []byte) int {
+	for i, c := range b {
+		if c == 0 {
+			return i
+		}
+	}
+	return -1
+}
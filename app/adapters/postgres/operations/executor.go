@@ -0,0 +1,128 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"abc-runner/app/adapters/postgres/config"
+	"abc-runner/app/adapters/postgres/connection"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+)
+
+// PostgresExecutor PostgreSQL操作执行器
+type PostgresExecutor struct {
+	pool        *connection.Pool
+	queries     map[string]string
+	prepared    bool
+	transaction bool
+	tracker     *metrics.OperationTypeTracker
+}
+
+// NewPostgresExecutor 创建PostgreSQL操作执行器
+func NewPostgresExecutor(pool *connection.Pool, cfg *config.PostgresConfig) *PostgresExecutor {
+	queries := make(map[string]string)
+	for _, queryType := range []string{config.QuerySelect, config.QueryInsert, config.QueryUpdate} {
+		queries[queryType] = cfg.GetQuery(queryType)
+	}
+	return &PostgresExecutor{
+		pool:        pool,
+		queries:     queries,
+		prepared:    cfg.PostgresSpecific.Prepared,
+		transaction: cfg.PostgresSpecific.Transaction,
+		tracker:     newQueryTracker(),
+	}
+}
+
+// ExecuteOperation 执行PostgreSQL操作
+// 操作的延迟包含从连接池取用连接的等待时间，与pgbench的口径一致
+func (e *PostgresExecutor) ExecuteOperation(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	statements, ok := operation.Params["statements"].([]Statement)
+	if !ok || len(statements) == 0 {
+		return nil, fmt.Errorf("unsupported operation type: %s", operation.Type)
+	}
+
+	startTime := time.Now()
+	var rows, written, read int64
+	conn, err := e.pool.Acquire(ctx)
+	if err == nil {
+		rows, written, read, err = e.run(ctx, conn, statements)
+		e.pool.Release(conn)
+	}
+	duration := time.Since(startTime)
+
+	if e.transaction {
+		e.tracker.Record(OperationTransaction, rows, false, duration, err)
+	}
+
+	isRead := true
+	for _, statement := range statements {
+		isRead = isRead && statement.Type == config.QuerySelect
+	}
+
+	result := &interfaces.OperationResult{
+		Success:      err == nil,
+		Duration:     duration,
+		IsRead:       isRead,
+		Error:        err,
+		Value:        rows,
+		BytesRead:    read,
+		BytesWritten: written,
+		Metadata: map[string]interface{}{
+			"protocol":       "postgres",
+			"operation_type": operation.Type,
+			"statements":     len(statements),
+			"rows":           rows,
+		},
+	}
+	for k, v := range operation.Metadata {
+		result.Metadata[k] = v
+	}
+	return result, err
+}
+
+// run 在连接上执行语句，事务模式下包裹在BEGIN/COMMIT中，出错时回滚
+// 返回影响的行数与收发的协议字节数
+func (e *PostgresExecutor) run(ctx context.Context, conn *connection.Conn, statements []Statement) (rows, written, read int64, err error) {
+	simpleQuery := func(sql string) error {
+		result, err := conn.SimpleQuery(ctx, sql)
+		written += result.BytesSent
+		read += result.BytesReceived
+		return err
+	}
+
+	if e.transaction {
+		if err := simpleQuery("BEGIN"); err != nil {
+			return 0, written, read, fmt.Errorf("begin failed: %w", err)
+		}
+	}
+
+	for _, statement := range statements {
+		start := time.Now()
+		result, err := conn.Exec(ctx, e.queries[statement.Type], statement.Args, e.prepared)
+		written += result.BytesSent
+		read += result.BytesReceived
+		affected := result.RowsAffected()
+		e.tracker.Record(statement.Type, affected, false, time.Since(start), err)
+		if err != nil {
+			if e.transaction && !conn.Broken() && conn.InTransaction() {
+				simpleQuery("ROLLBACK")
+			}
+			return rows, written, read, fmt.Errorf("%s failed: %w", statement.Type, err)
+		}
+		rows += affected
+	}
+
+	if e.transaction {
+		if err := simpleQuery("COMMIT"); err != nil {
+			return rows, written, read, fmt.Errorf("commit failed: %w", err)
+		}
+	}
+	return rows, written, read, nil
+}
+
+// QueryStats 获取按查询类型的统计
+func (e *PostgresExecutor) QueryStats() []QueryStats {
+	return e.tracker.Stats()
+}
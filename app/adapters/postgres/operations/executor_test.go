@@ -0,0 +1,381 @@
+package operations
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"abc-runner/app/adapters/postgres/config"
+	"abc-runner/app/adapters/postgres/connection"
+
+	"github.com/xdg-go/scram"
+)
+
+// fakeServer 实现PostgreSQL v3协议最小子集的测试服务端
+// SQL中含"missing"的语句返回42P01错误；password非空时要求SCRAM-SHA-256认证
+type fakeServer struct {
+	listener net.Listener
+	password string
+
+	mutex   sync.Mutex
+	parses  int
+	queries []string // 简单查询协议收到的SQL
+}
+
+func newFakeServer(t *testing.T, password string) *fakeServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	server := &fakeServer{listener: listener, password: password}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (s *fakeServer) config() *config.PostgresConfig {
+	host, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	cfg := config.NewDefaultPostgresConfig()
+	cfg.Connection.Address = host
+	cfg.Connection.Port, _ = strconv.Atoi(port)
+	cfg.Connection.Password = s.password
+	cfg.Connection.Timeout = 2 * time.Second
+	cfg.BenchMark.Parallels = 1
+	return cfg
+}
+
+func (s *fakeServer) counts() (int, []string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.parses, append([]string(nil), s.queries...)
+}
+
+func (s *fakeServer) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	if _, err := readStartup(reader); err != nil {
+		return
+	}
+	if s.password != "" {
+		if err := s.authenticate(conn, reader); err != nil {
+			return
+		}
+	}
+	write(conn, 'R', binary.BigEndian.AppendUint32(nil, 0))
+	write(conn, 'S', []byte("server_version\x0016.4\x00"))
+	txStatus := byte('I')
+	write(conn, 'Z', []byte{txStatus})
+
+	statements := make(map[string]string)
+	var portal string
+	failed := false
+	for {
+		kind, body, err := readFrontend(reader)
+		if err != nil || kind == 'X' {
+			return
+		}
+		if failed && kind != 'S' {
+			continue // 出错后忽略消息直到Sync
+		}
+		switch kind {
+		case 'Q':
+			sql := cstrings(body)[0]
+			s.mutex.Lock()
+			s.queries = append(s.queries, sql)
+			s.mutex.Unlock()
+			switch sql {
+			case "BEGIN":
+				txStatus = 'T'
+			case "COMMIT", "ROLLBACK":
+				txStatus = 'I'
+			default:
+				write(conn, 'D', []byte{0, 1, 0, 0, 0, 1, '1'})
+			}
+			write(conn, 'C', []byte(strings.Fields(sql)[0]+"\x00"))
+			write(conn, 'Z', []byte{txStatus})
+		case 'P':
+			fields := cstrings(body)
+			statements[fields[0]] = fields[1]
+			s.mutex.Lock()
+			s.parses++
+			s.mutex.Unlock()
+			write(conn, '1', nil)
+		case 'B':
+			fields := cstrings(body)
+			portal = statements[fields[1]]
+			write(conn, '2', nil)
+		case 'E':
+			switch {
+			case strings.Contains(portal, "missing"):
+				write(conn, 'E', []byte("SERROR\x00C42P01\x00Mrelation \"missing\" does not exist\x00\x00"))
+				if txStatus == 'T' {
+					txStatus = 'E'
+				}
+				failed = true
+			case strings.HasPrefix(portal, "SELECT"):
+				write(conn, 'D', []byte{0, 1, 0, 0, 0, 1, '7'})
+				write(conn, 'C', []byte("SELECT 1\x00"))
+			case strings.HasPrefix(portal, "INSERT"):
+				write(conn, 'C', []byte("INSERT 0 1\x00"))
+			default:
+				write(conn, 'C', []byte("UPDATE 1\x00"))
+			}
+		case 'S':
+			failed = false
+			write(conn, 'Z', []byte{txStatus})
+		}
+	}
+}
+
+// authenticate 完成SCRAM-SHA-256认证，成功后由调用方发送AuthenticationOk
+func (s *fakeServer) authenticate(conn net.Conn, reader *bufio.Reader) error {
+	kf := scram.KeyFactors{Salt: "0123456789abcdef", Iters: 4096}
+	client, _ := scram.SHA256.NewClient("", s.password, "")
+	stored := client.GetStoredCredentials(kf)
+	server, err := scram.SHA256.NewServer(func(string) (scram.StoredCredentials, error) {
+		return stored, nil
+	})
+	if err != nil {
+		return err
+	}
+	conversation := server.NewConversation()
+
+	write(conn, 'R', append(binary.BigEndian.AppendUint32(nil, 10), "SCRAM-SHA-256\x00\x00"...))
+	_, body, err := readFrontend(reader)
+	if err != nil {
+		return err
+	}
+	mechanism := indexByte(body, 0)
+	first := body[mechanism+5:] // 跳过机制名结尾的0与4字节长度
+	response, err := conversation.Step(string(first))
+	if err != nil {
+		return err
+	}
+	write(conn, 'R', append(binary.BigEndian.AppendUint32(nil, 11), response...))
+
+	_, body, err = readFrontend(reader)
+	if err != nil {
+		return err
+	}
+	response, err = conversation.Step(string(body))
+	if err != nil {
+		write(conn, 'E', []byte("SFATAL\x00C28P01\x00Mpassword authentication failed\x00\x00"))
+		return err
+	}
+	write(conn, 'R', append(binary.BigEndian.AppendUint32(nil, 12), response...))
+	return nil
+}
+
+func readStartup(reader *bufio.Reader) ([]byte, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(reader, header[:]); err != nil {
+		return nil, err
+	}
+	body := make([]byte, binary.BigEndian.Uint32(header[:])-4)
+	_, err := io.ReadFull(reader, body)
+	return body, err
+}
+
+func readFrontend(reader *bufio.Reader) (byte, []byte, error) {
+	kind, err := reader.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	body, err := readStartup(reader)
+	return kind, body, err
+}
+
+func write(conn net.Conn, kind byte, body []byte) {
+	message := append([]byte{kind}, binary.BigEndian.AppendUint32(nil, uint32(len(body)+4))...)
+	conn.Write(append(message, body...))
+}
+
+func cstrings(body []byte) []string {
+	return strings.Split(string(body), "\x00")
+}
+
+func indexByte(body []byte, b byte) int {
+	for i, c := range body {
+		if c == b {
+			return i
+		}
+	}
+	return -1
+}
+
+func newTestExecutor(t *testing.T, cfg *config.PostgresConfig) *PostgresExecutor {
+	t.Helper()
+	pool, err := connection.NewPool(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	t.Cleanup(func() { pool.Close() })
+	return NewPostgresExecutor(pool, cfg)
+}
+
+func findStats(stats []QueryStats, queryType string) QueryStats {
+	for _, entry := range stats {
+		if entry.Type == queryType {
+			return entry
+		}
+	}
+	return QueryStats{}
+}
+
+func TestPostgresExecutorPrepared(t *testing.T) {
+	for _, prepared := range []bool{false, true} {
+		server := newFakeServer(t, "")
+		cfg := server.config()
+		cfg.PostgresSpecific.Prepared = prepared
+		executor := newTestExecutor(t, cfg)
+		factory := NewOperationFactory(cfg)
+
+		for i := 0; i < 3; i++ {
+			result, err := executor.ExecuteOperation(context.Background(), factory.CreateOperation(i, nil))
+			if err != nil {
+				t.Fatalf("prepared=%v: select failed: %v", prepared, err)
+			}
+			if !result.IsRead || result.Value != int64(1) {
+				t.Fatalf("prepared=%v: unexpected result: %+v", prepared, result)
+			}
+			// 读写字节数为实际收发的协议消息大小
+			if result.BytesWritten == 0 || result.BytesRead == 0 {
+				t.Fatalf("prepared=%v: expected transferred bytes, got written %d, read %d", prepared, result.BytesWritten, result.BytesRead)
+			}
+		}
+
+		// 预编译时每个连接只Parse一次
+		wantParses := 3
+		if prepared {
+			wantParses = 1
+		}
+		if parses, _ := server.counts(); parses != wantParses {
+			t.Fatalf("prepared=%v: parses = %d, want %d", prepared, parses, wantParses)
+		}
+		stats := findStats(executor.QueryStats(), config.QuerySelect)
+		if stats.Count != 3 || stats.Volume != 3 || stats.Errors != 0 {
+			t.Fatalf("prepared=%v: unexpected stats: %+v", prepared, stats)
+		}
+	}
+}
+
+func TestPostgresExecutorTransaction(t *testing.T) {
+	server := newFakeServer(t, "")
+	cfg := server.config()
+	cfg.BenchMark.TestCase = "mixed"
+	cfg.PostgresSpecific.Mix = config.MixConfig{Insert: 1, Update: 1}
+	cfg.PostgresSpecific.Transaction = true
+	cfg.PostgresSpecific.TxStatements = 4
+	executor := newTestExecutor(t, cfg)
+	factory := NewOperationFactory(cfg)
+
+	operation := factory.CreateOperation(0, nil)
+	if operation.Type != OperationTransaction {
+		t.Fatalf("operation type = %s, want %s", operation.Type, OperationTransaction)
+	}
+	result, err := executor.ExecuteOperation(context.Background(), operation)
+	if err != nil {
+		t.Fatalf("transaction failed: %v", err)
+	}
+	if result.IsRead || result.Value != int64(4) {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if result.BytesWritten == 0 || result.BytesRead == 0 {
+		t.Fatalf("expected transferred bytes, got written %d, read %d", result.BytesWritten, result.BytesRead)
+	}
+
+	if _, queries := server.counts(); strings.Join(queries, ",") != "BEGIN,COMMIT" {
+		t.Fatalf("simple queries = %v, want BEGIN,COMMIT", queries)
+	}
+	stats := executor.QueryStats()
+	tx := findStats(stats, OperationTransaction)
+	insert, update := findStats(stats, config.QueryInsert), findStats(stats, config.QueryUpdate)
+	if tx.Count != 1 || tx.Volume != 4 || insert.Count+update.Count != 4 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestPostgresExecutorErrorRollback(t *testing.T) {
+	server := newFakeServer(t, "")
+	cfg := server.config()
+	cfg.BenchMark.TestCase = config.QueryUpdate
+	cfg.PostgresSpecific.Transaction = true
+	cfg.PostgresSpecific.Prepared = true
+	cfg.PostgresSpecific.Queries.Update = "UPDATE missing SET k = k + $1 WHERE id = $2"
+	executor := newTestExecutor(t, cfg)
+	factory := NewOperationFactory(cfg)
+
+	for i := 0; i < 2; i++ {
+		_, err := executor.ExecuteOperation(context.Background(), factory.CreateOperation(i, nil))
+		var pgErr *connection.PgError
+		if !errors.As(err, &pgErr) || pgErr.Code != "42P01" {
+			t.Fatalf("expected SQLSTATE 42P01, got %v", err)
+		}
+	}
+
+	// 服务端错误不会使连接失效：回滚后同一连接继续使用
+	if _, queries := server.counts(); strings.Join(queries, ",") != "BEGIN,ROLLBACK,BEGIN,ROLLBACK" {
+		t.Fatalf("simple queries = %v", queries)
+	}
+	update := findStats(executor.QueryStats(), config.QueryUpdate)
+	if update.Errors != 2 || update.ErrorCodes["42P01"] != 2 || update.ErrorRate != 100 {
+		t.Fatalf("unexpected update stats: %+v", update)
+	}
+	if tx := findStats(executor.QueryStats(), OperationTransaction); tx.Errors != 2 {
+		t.Fatalf("unexpected transaction stats: %+v", tx)
+	}
+}
+
+func TestPostgresSCRAMAuthentication(t *testing.T) {
+	server := newFakeServer(t, "s3cret")
+	cfg := server.config()
+	executor := newTestExecutor(t, cfg)
+	if _, err := executor.ExecuteOperation(context.Background(), NewOperationFactory(cfg).CreateOperation(0, nil)); err != nil {
+		t.Fatalf("select after SCRAM authentication failed: %v", err)
+	}
+
+	cfg.Connection.Password = "wrong"
+	if _, err := connection.NewPool(context.Background(), cfg); err == nil {
+		t.Fatalf("expected authentication failure with a wrong password")
+	}
+}
+
+func TestOperationFactoryMix(t *testing.T) {
+	cfg := config.NewDefaultPostgresConfig()
+	cfg.BenchMark.TestCase = "mixed"
+	cfg.PostgresSpecific.Rows = 5
+	cfg.PostgresSpecific.Mix = config.MixConfig{Select: 3, Update: 1}
+	factory := NewOperationFactory(cfg)
+
+	seen := make(map[string]int)
+	for i := 0; i < 2000; i++ {
+		operation := factory.CreateOperation(i, nil)
+		statement := operation.Params["statements"].([]Statement)[0]
+		seen[statement.Type]++
+
+		id, _ := strconv.Atoi(statement.Args[len(statement.Args)-1])
+		if id < 1 || id > 5 {
+			t.Fatalf("%s id %d outside [1, 5]", statement.Type, id)
+		}
+	}
+	if seen[config.QueryInsert] != 0 || seen[config.QuerySelect] < 1300 || seen[config.QueryUpdate] < 350 {
+		t.Fatalf("unexpected mix distribution: %v", seen)
+	}
+}
@@ -0,0 +1,129 @@
+package operations
+
+import (
+	"math/rand/v2"
+	"strconv"
+	"strings"
+
+	"abc-runner/app/adapters/postgres/config"
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+)
+
+// OperationTransaction 事务模式下的操作类型
+const OperationTransaction = "transaction"
+
+// Statement 一条待执行的语句：查询类型与文本格式的参数
+type Statement struct {
+	Type string
+	Args []string
+}
+
+// OperationFactory PostgreSQL操作工厂
+// 非事务模式下每个操作一条语句；事务模式下每个操作为一个包含tx_statements条语句的事务
+type OperationFactory struct {
+	testCase     string
+	rows         int
+	payload      string
+	transaction  bool
+	txStatements int
+	mix          []weightedQuery
+	mixTotal     int
+}
+
+// weightedQuery mixed用例中的查询类型与权重
+type weightedQuery struct {
+	queryType string
+	weight    int
+}
+
+// NewOperationFactory 创建PostgreSQL操作工厂
+func NewOperationFactory(cfg *config.PostgresConfig) *OperationFactory {
+	specific := cfg.PostgresSpecific
+	factory := &OperationFactory{
+		testCase:     cfg.BenchMark.TestCase,
+		rows:         specific.Rows,
+		payload:      strings.Repeat("x", specific.PayloadSize),
+		transaction:  specific.Transaction,
+		txStatements: specific.TxStatements,
+	}
+	for _, entry := range []weightedQuery{
+		{config.QuerySelect, specific.Mix.Select},
+		{config.QueryInsert, specific.Mix.Insert},
+		{config.QueryUpdate, specific.Mix.Update},
+	} {
+		if entry.weight > 0 {
+			factory.mix = append(factory.mix, entry)
+			factory.mixTotal += entry.weight
+		}
+	}
+	return factory
+}
+
+// CreateOperation 创建操作，语句参数在此生成：id在[1, rows]内均匀分布
+func (f *OperationFactory) CreateOperation(jobID int, cfg execution.BenchmarkConfig) interfaces.Operation {
+	count := 1
+	if f.transaction {
+		count = f.txStatements
+	}
+	statements := make([]Statement, count)
+	for i := range statements {
+		statements[i] = f.newStatement(f.pickQuery())
+	}
+
+	operationType := statements[0].Type
+	if f.transaction {
+		operationType = OperationTransaction
+	}
+	return interfaces.Operation{
+		Type: operationType,
+		Params: map[string]interface{}{
+			"job_id":     jobID,
+			"statements": statements,
+		},
+		Metadata: map[string]string{
+			"operation_type": operationType,
+		},
+	}
+}
+
+// pickQuery 选择查询类型，mixed用例按权重随机
+func (f *OperationFactory) pickQuery() string {
+	if f.testCase != "mixed" {
+		return f.testCase
+	}
+	n := rand.IntN(f.mixTotal)
+	for _, entry := range f.mix {
+		if n < entry.weight {
+			return entry.queryType
+		}
+		n -= entry.weight
+	}
+	return f.mix[len(f.mix)-1].queryType
+}
+
+// newStatement 按查询类型生成参数：select(id)、insert(k, payload)、update(delta, id)
+func (f *OperationFactory) newStatement(queryType string) Statement {
+	id := strconv.Itoa(1 + rand.IntN(f.rows))
+	switch queryType {
+	case config.QueryInsert:
+		return Statement{Type: queryType, Args: []string{strconv.Itoa(rand.IntN(1000000)), f.payload}}
+	case config.QueryUpdate:
+		return Statement{Type: queryType, Args: []string{strconv.Itoa(rand.IntN(2001) - 1000), id}}
+	default:
+		return Statement{Type: queryType, Args: []string{id}}
+	}
+}
+
+// GetOperationType 获取操作类型
+func (f *OperationFactory) GetOperationType() string {
+	if f.transaction {
+		return OperationTransaction
+	}
+	return f.testCase
+}
+
+// GetSupportedOperations 获取支持的操作类型
+func (f *OperationFactory) GetSupportedOperations() []string {
+	return []string{config.QuerySelect, config.QueryInsert, config.QueryUpdate, OperationTransaction}
+}
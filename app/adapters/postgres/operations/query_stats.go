@@ -0,0 +1,27 @@
+package operations
+
+import (
+	"errors"
+
+	"abc-runner/app/adapters/postgres/connection"
+	"abc-runner/app/core/metrics"
+)
+
+// QueryStats 单个查询类型的统计，Volume为返回或影响的行数（JSON字段"rows"），
+// 错误码为SQLSTATE，连接错误记为"connection"；
+// 事务模式下另有transaction条目，延迟为BEGIN到COMMIT的整个事务
+type QueryStats = metrics.OperationTypeStats
+
+// newQueryTracker 创建按查询类型的统计器
+func newQueryTracker() *metrics.OperationTypeTracker {
+	return metrics.NewOperationTypeTracker(errorCode, "rows", "")
+}
+
+// errorCode 错误分类：服务端错误取SQLSTATE，其余记为"connection"
+func errorCode(err error) string {
+	var pgErr *connection.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+	return "connection"
+}
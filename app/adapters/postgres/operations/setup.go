@@ -0,0 +1,45 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"abc-runner/app/adapters/postgres/config"
+	"abc-runner/app/adapters/postgres/connection"
+)
+
+// setupTimeout 建表与填充数据的超时，填充大量行时远超单条语句的超时
+const setupTimeout = 10 * time.Minute
+
+// Setup 创建默认查询使用的表并填充rows行，表已存在时跳过
+// 返回是否新建了表
+func Setup(ctx context.Context, conn *connection.Conn, cfg *config.PostgresConfig) (bool, error) {
+	table := cfg.PostgresSpecific.Table
+	exists, err := conn.Exec(ctx, "SELECT 1 WHERE to_regclass($1) IS NOT NULL", []string{table}, false)
+	if err != nil {
+		return false, fmt.Errorf("failed to check table %s: %w", table, err)
+	}
+	if exists.Rows > 0 {
+		return false, nil
+	}
+
+	conn.SetTimeout(setupTimeout)
+	defer conn.SetTimeout(cfg.Connection.Timeout)
+
+	create := fmt.Sprintf("CREATE TABLE %s (id BIGSERIAL PRIMARY KEY, k INTEGER NOT NULL DEFAULT 0, payload TEXT)", table)
+	if _, err := conn.SimpleQuery(ctx, create); err != nil {
+		return false, fmt.Errorf("failed to create table %s: %w", table, err)
+	}
+
+	fill := fmt.Sprintf("INSERT INTO %s (k, payload) SELECT (random() * 1000000)::int, repeat('x', $1::int) FROM generate_series(1, $2::int)", table)
+	args := []string{strconv.Itoa(cfg.PostgresSpecific.PayloadSize), strconv.Itoa(cfg.PostgresSpecific.Rows)}
+	if _, err := conn.Exec(ctx, fill, args, false); err != nil {
+		return true, fmt.Errorf("failed to fill table %s: %w", table, err)
+	}
+	if _, err := conn.SimpleQuery(ctx, "ANALYZE "+table); err != nil {
+		return true, fmt.Errorf("failed to analyze table %s: %w", table, err)
+	}
+	return true, nil
+}
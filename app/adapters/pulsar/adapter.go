@@ -0,0 +1,239 @@
+package pulsar
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"abc-runner/app/adapters/pulsar/config"
+	"abc-runner/app/adapters/pulsar/connection"
+	"abc-runner/app/adapters/pulsar/operations"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+)
+
+// PulsarAdapter Apache Pulsar协议适配器 - 遵循统一架构模式
+// 职责：客户端、生产者与订阅管理、订阅积压采样、健康检查
+type PulsarAdapter struct {
+	config           *config.PulsarConfig
+	client           *connection.Client
+	pulsarOperations *operations.PulsarExecutor
+	backlogSampler   *operations.BacklogSampler
+	metricsCollector interfaces.DefaultMetricsCollector
+	mu               sync.RWMutex
+	isConnected      bool
+
+	// 统计信息
+	totalOperations  int64
+	failedOperations int64
+}
+
+// NewPulsarAdapter 创建Pulsar适配器
+func NewPulsarAdapter(metricsCollector interfaces.DefaultMetricsCollector) *PulsarAdapter {
+	return &PulsarAdapter{
+		metricsCollector: metricsCollector,
+	}
+}
+
+// Connect 建立连接并创建生产者，consume用例同时订阅测试主题
+func (p *PulsarAdapter) Connect(ctx context.Context, cfg interfaces.Config) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pulsarConfig, ok := cfg.(*config.PulsarConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type for Pulsar adapter: expected *config.PulsarConfig, got %T", cfg)
+	}
+
+	if err := pulsarConfig.Validate(); err != nil {
+		return fmt.Errorf("config validation failed: %w", err)
+	}
+	p.config = pulsarConfig
+
+	client, err := connection.NewClient(pulsarConfig)
+	if err != nil {
+		return err
+	}
+
+	producers := make([]operations.Producer, 0, len(client.Producers()))
+	for _, producer := range client.Producers() {
+		producers = append(producers, producer)
+	}
+	p.client = client
+	p.pulsarOperations = operations.NewPulsarExecutor(producers, client.Messages(), pulsarConfig)
+	p.isConnected = true
+	return nil
+}
+
+// StartBacklogSampling 按backlog_sample_interval开始通过管理API采样订阅积压，未配置间隔时不采样
+func (p *PulsarAdapter) StartBacklogSampling(ctx context.Context) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.client == nil || p.backlogSampler != nil {
+		return
+	}
+	specific := p.config.PulsarSpecific
+	if specific.BacklogSampleInterval <= 0 {
+		return
+	}
+	conn := p.config.Connection
+	admin := connection.NewAdminClient(conn.GetAdminURL(), conn.Token, conn.Timeout)
+	p.backlogSampler = operations.NewBacklogSampler(admin, p.config.GetTopic(), specific.Consumer.Subscription, specific.BacklogSampleInterval)
+	p.backlogSampler.Start(ctx)
+}
+
+// StopBacklogSampling 停止积压采样并返回统计；未采样时返回nil
+func (p *PulsarAdapter) StopBacklogSampling() *operations.BacklogStats {
+	p.mu.RLock()
+	sampler := p.backlogSampler
+	p.mu.RUnlock()
+
+	if sampler == nil {
+		return nil
+	}
+	stats := sampler.Stop()
+	return &stats
+}
+
+// FlushProducers 发出缓冲的批次并等待async模式下的全部确认
+func (p *PulsarAdapter) FlushProducers() error {
+	if p.pulsarOperations == nil {
+		return nil
+	}
+	return p.pulsarOperations.Flush()
+}
+
+// Execute 执行操作 - 使用执行器处理
+func (p *PulsarAdapter) Execute(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	if !p.isConnected {
+		return nil, fmt.Errorf("adapter not connected")
+	}
+
+	atomic.AddInt64(&p.totalOperations, 1)
+	result, err := p.pulsarOperations.ExecuteOperation(ctx, operation)
+	if err != nil {
+		atomic.AddInt64(&p.failedOperations, 1)
+	}
+	return result, err
+}
+
+// Close 等待未确认的发送后关闭生产者、消费者与客户端
+func (p *PulsarAdapter) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.backlogSampler != nil {
+		p.backlogSampler.Stop()
+	}
+	if p.pulsarOperations != nil {
+		p.pulsarOperations.Flush()
+	}
+	if p.client != nil {
+		p.client.Close()
+		p.client = nil
+	}
+	p.isConnected = false
+	return nil
+}
+
+// GetProtocolMetrics 获取协议特定指标
+func (p *PulsarAdapter) GetProtocolMetrics() map[string]interface{} {
+	metrics := map[string]interface{}{
+		"protocol":          "pulsar",
+		"total_operations":  atomic.LoadInt64(&p.totalOperations),
+		"failed_operations": atomic.LoadInt64(&p.failedOperations),
+	}
+
+	if p.config != nil {
+		specific := p.config.PulsarSpecific
+		metrics["topic"] = p.config.GetTopic()
+		if p.config.BenchMark.TestCase == config.OperationConsume {
+			metrics["subscription"] = specific.Consumer.Subscription
+			metrics["subscription_type"] = specific.Consumer.SubscriptionType
+			metrics["consumers"] = specific.Consumer.Consumers
+		} else {
+			metrics["producers"] = specific.Producer.Producers
+			metrics["send_mode"] = specific.Producer.SendMode
+			metrics["batching"] = specific.Producer.Batching
+			metrics["compression"] = specific.Producer.Compression
+		}
+	}
+	if stats := p.GetOperationStats(); stats != nil {
+		metrics["operation_stats"] = stats
+	}
+	if stats := p.GetConsumerStats(); len(stats) > 0 {
+		metrics["consumer_stats"] = stats
+	}
+	if latency := p.GetEndToEndLatency(); latency != nil {
+		metrics["end_to_end_latency"] = latency
+	}
+	if stats := p.GetBacklogStats(); stats != nil {
+		metrics["backlog"] = stats
+	}
+
+	return metrics
+}
+
+// GetOperationStats 获取按操作类型的统计，未连接时返回nil
+func (p *PulsarAdapter) GetOperationStats() []operations.OperationStats {
+	if p.pulsarOperations == nil {
+		return nil
+	}
+	return p.pulsarOperations.OperationStats()
+}
+
+// GetConsumerStats 获取各消费者的接收统计，未连接时返回nil
+func (p *PulsarAdapter) GetConsumerStats() []operations.ConsumerStats {
+	if p.pulsarOperations == nil {
+		return nil
+	}
+	return p.pulsarOperations.ConsumerStats()
+}
+
+// GetEndToEndLatency 获取从发送到接收的延迟，未连接或没有接收到消息时返回nil
+func (p *PulsarAdapter) GetEndToEndLatency() *metrics.LatencyMetrics {
+	if p.pulsarOperations == nil {
+		return nil
+	}
+	latency := p.pulsarOperations.EndToEndLatency()
+	if latency.Max == 0 {
+		return nil
+	}
+	return &latency
+}
+
+// GetBacklogStats 获取订阅积压采样统计，未采样时返回nil
+func (p *PulsarAdapter) GetBacklogStats() *operations.BacklogStats {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.backlogSampler == nil {
+		return nil
+	}
+	stats := p.backlogSampler.Stats()
+	return &stats
+}
+
+// HealthCheck 健康检查：查询测试主题的分区
+func (p *PulsarAdapter) HealthCheck(ctx context.Context) error {
+	if !p.isConnected {
+		return fmt.Errorf("adapter not connected")
+	}
+
+	if err := p.client.Ping(); err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	return nil
+}
+
+// GetProtocolName 获取协议名称
+func (p *PulsarAdapter) GetProtocolName() string {
+	return "pulsar"
+}
+
+// GetMetricsCollector 获取指标收集器
+func (p *PulsarAdapter) GetMetricsCollector() interfaces.DefaultMetricsCollector {
+	return p.metricsCollector
+}
@@ -0,0 +1,404 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/utils"
+)
+
+// 操作类型，同时也是测试用例名
+const (
+	OperationProduce = "produce" // 向主题发送消息
+	OperationConsume = "consume" // 从订阅接收一条消息并确认
+)
+
+// 发送模式
+const (
+	SendModeSync  = "sync"  // 每次发送等待服务端确认，延迟为确认延迟
+	SendModeAsync = "async" // 消息进入发送队列即完成，确认延迟由回调单独统计
+)
+
+// 订阅类型
+const (
+	SubscriptionExclusive = "exclusive"  // 只允许一个消费者
+	SubscriptionShared    = "shared"     // 消息在各消费者之间轮流分发
+	SubscriptionFailover  = "failover"   // 每个分区只有一个活跃消费者，其余为备用
+	SubscriptionKeyShared = "key_shared" // 相同键的消息分发给同一消费者
+)
+
+// 默认值
+const (
+	DefaultTopic        = "persistent://public/default/abc_bench"
+	DefaultSubscription = "abc-bench"
+)
+
+// PulsarConfig Apache Pulsar协议配置
+type PulsarConfig struct {
+	Protocol       string               `yaml:"protocol" json:"protocol"`
+	Connection     ConnectionConfig     `yaml:"connection" json:"connection"`
+	BenchMark      BenchmarkConfig      `yaml:"benchmark" json:"benchmark"`
+	PulsarSpecific PulsarSpecificConfig `yaml:"pulsar_specific" json:"pulsar_specific"`
+}
+
+// ConnectionConfig Pulsar连接配置
+type ConnectionConfig struct {
+	ServiceURL string        `yaml:"service_url" json:"service_url"` // pulsar://或pulsar+ssl://服务地址，设置时忽略address与port
+	Address    string        `yaml:"address" json:"address"`
+	Port       int           `yaml:"port" json:"port"`
+	TLS        bool          `yaml:"tls" json:"tls"`
+	Token      string        `yaml:"token" json:"token"`         // JWT认证令牌
+	AdminURL   string        `yaml:"admin_url" json:"admin_url"` // 管理REST API地址，采样积压时使用，为空时为http://<address>:8080
+	Timeout    time.Duration `yaml:"timeout" json:"timeout"`     // 建连与操作（创建生产者、订阅、同步发送）的超时
+}
+
+// BenchmarkConfig Pulsar基准测试配置
+type BenchmarkConfig struct {
+	Total     int           `yaml:"total" json:"total"`
+	Parallels int           `yaml:"parallels" json:"parallels"`
+	TestCase  string        `yaml:"test_case" json:"test_case"` // produce或consume
+	Duration  time.Duration `yaml:"duration" json:"duration"`
+}
+
+// PulsarSpecificConfig Pulsar特定配置
+type PulsarSpecificConfig struct {
+	Topic    string         `yaml:"topic" json:"topic"` // 完整主题名，短名称按persistent://public/default/补全
+	Producer ProducerConfig `yaml:"producer" json:"producer"`
+	Consumer ConsumerConfig `yaml:"consumer" json:"consumer"`
+
+	// BacklogSampleInterval 运行期间通过管理API采样订阅积压的间隔，0表示不采样
+	BacklogSampleInterval time.Duration `yaml:"backlog_sample_interval" json:"backlog_sample_interval"`
+}
+
+// ProducerConfig 生产者配置
+type ProducerConfig struct {
+	Producers int    `yaml:"producers" json:"producers"` // 生产者数，各工作协程按操作编号轮流使用
+	SendMode  string `yaml:"send_mode" json:"send_mode"` // sync或async
+	// MaxPending async模式下每个生产者等待确认的消息上限，队列满时发送阻塞
+	MaxPending int  `yaml:"max_pending" json:"max_pending"`
+	Batching   bool `yaml:"batching" json:"batching"`
+	// BatchingMaxMessages 单个批次的最大消息数
+	BatchingMaxMessages int `yaml:"batching_max_messages" json:"batching_max_messages"`
+	// BatchingMaxDelay 批次未满时的最长等待时间
+	BatchingMaxDelay time.Duration `yaml:"batching_max_delay" json:"batching_max_delay"`
+	Compression      string        `yaml:"compression" json:"compression"` // none、lz4、zlib或zstd
+	// Keys 消息键数量，key_shared订阅按键分发；0表示不设置键
+	Keys int `yaml:"keys" json:"keys"`
+	// MessageSize 未配置负载模板时随机消息体的字节数
+	MessageSize int                         `yaml:"message_size" json:"message_size"`
+	Payload     utils.PayloadTemplateConfig `yaml:"payload" json:"payload"` // 消息体模板，设置时忽略message_size
+}
+
+// ConsumerConfig 消费者配置
+type ConsumerConfig struct {
+	Subscription     string `yaml:"subscription" json:"subscription"`
+	SubscriptionType string `yaml:"subscription_type" json:"subscription_type"` // exclusive、shared、failover或key_shared
+	Consumers        int    `yaml:"consumers" json:"consumers"`                 // 同一订阅下的消费者数，exclusive时只能为1
+	// ReceiverQueueSize 每个消费者预取的消息数
+	ReceiverQueueSize int `yaml:"receiver_queue_size" json:"receiver_queue_size"`
+	// InitialPosition 订阅首次创建时的起始位置：earliest或latest
+	InitialPosition string `yaml:"initial_position" json:"initial_position"`
+	// ReceiveTimeout 单次接收等待消息的最长时间，超时计为失败
+	ReceiveTimeout time.Duration `yaml:"receive_timeout" json:"receive_timeout"`
+}
+
+// NewDefaultPulsarConfig 创建默认Pulsar配置
+func NewDefaultPulsarConfig() *PulsarConfig {
+	return &PulsarConfig{
+		Protocol: "pulsar",
+		Connection: ConnectionConfig{
+			Address: "localhost",
+			Port:    6650,
+			Timeout: 10 * time.Second,
+		},
+		BenchMark: BenchmarkConfig{
+			Total:     1000,
+			Parallels: 10,
+			TestCase:  OperationProduce,
+		},
+		PulsarSpecific: PulsarSpecificConfig{
+			Topic: DefaultTopic,
+			Producer: ProducerConfig{
+				Producers:           1,
+				SendMode:            SendModeSync,
+				MaxPending:          1000,
+				Batching:            true,
+				BatchingMaxMessages: 1000,
+				BatchingMaxDelay:    10 * time.Millisecond,
+				Compression:         "none",
+				MessageSize:         1024,
+			},
+			Consumer: ConsumerConfig{
+				Subscription:      DefaultSubscription,
+				SubscriptionType:  SubscriptionShared,
+				Consumers:         1,
+				ReceiverQueueSize: 1000,
+				InitialPosition:   "earliest",
+				ReceiveTimeout:    5 * time.Second,
+			},
+		},
+	}
+}
+
+// GetServiceURL 获取服务地址，未配置service_url时由地址与端口拼接
+func (c *ConnectionConfig) GetServiceURL() string {
+	if c.ServiceURL != "" {
+		return c.ServiceURL
+	}
+	scheme := "pulsar"
+	if c.TLS {
+		scheme = "pulsar+ssl"
+	}
+	return scheme + "://" + net.JoinHostPort(c.Address, strconv.Itoa(c.Port))
+}
+
+// GetAdminURL 获取管理REST API地址，未配置时使用服务地址的主机与8080端口
+func (c *ConnectionConfig) GetAdminURL() string {
+	if c.AdminURL != "" {
+		return strings.TrimRight(c.AdminURL, "/")
+	}
+	host := c.Address
+	if u, err := url.Parse(c.ServiceURL); c.ServiceURL != "" && err == nil {
+		host = u.Hostname()
+	}
+	scheme := "http"
+	if c.TLS || strings.HasPrefix(c.ServiceURL, "pulsar+ssl://") {
+		scheme = "https"
+	}
+	return scheme + "://" + net.JoinHostPort(host, "8080")
+}
+
+// GetTopic 获取完整主题名，短名称按persistent://public/default/补全
+func (c *PulsarConfig) GetTopic() string {
+	topic := c.PulsarSpecific.Topic
+	if strings.Contains(topic, "://") {
+		return topic
+	}
+	if strings.Count(topic, "/") == 2 {
+		return "persistent://" + topic
+	}
+	return "persistent://public/default/" + topic
+}
+
+// GetProtocol 实现Config接口
+func (c *PulsarConfig) GetProtocol() string {
+	return c.Protocol
+}
+
+// GetConnection 实现Config接口
+func (c *PulsarConfig) GetConnection() interfaces.ConnectionConfig {
+	return &c.Connection
+}
+
+// GetBenchmark 实现Config接口
+func (c *PulsarConfig) GetBenchmark() interfaces.BenchmarkConfig {
+	return &c.BenchMark
+}
+
+// Validate 实现Config接口
+func (c *PulsarConfig) Validate() error {
+	if c.Connection.ServiceURL == "" {
+		if c.Connection.Address == "" {
+			return fmt.Errorf("connection address cannot be empty")
+		}
+		if c.Connection.Port <= 0 || c.Connection.Port > 65535 {
+			return fmt.Errorf("invalid port number: %d", c.Connection.Port)
+		}
+	} else if !strings.HasPrefix(c.Connection.ServiceURL, "pulsar://") && !strings.HasPrefix(c.Connection.ServiceURL, "pulsar+ssl://") {
+		return fmt.Errorf("invalid service url: must start with pulsar:// or pulsar+ssl://")
+	}
+	if c.Connection.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+
+	if c.BenchMark.Total <= 0 {
+		return fmt.Errorf("total operations must be greater than 0")
+	}
+	if c.BenchMark.Parallels <= 0 {
+		return fmt.Errorf("parallel connections must be greater than 0")
+	}
+	switch c.BenchMark.TestCase {
+	case OperationProduce, OperationConsume:
+	default:
+		return fmt.Errorf("invalid test case: %s, valid options: %s, %s",
+			c.BenchMark.TestCase, OperationProduce, OperationConsume)
+	}
+
+	specific := c.PulsarSpecific
+	if specific.Topic == "" {
+		return fmt.Errorf("topic cannot be empty")
+	}
+	if topic := c.GetTopic(); !strings.HasPrefix(topic, "persistent://") && !strings.HasPrefix(topic, "non-persistent://") {
+		return fmt.Errorf("invalid topic: %s (expected persistent:// or non-persistent://)", specific.Topic)
+	}
+
+	producer := specific.Producer
+	if producer.Producers <= 0 {
+		return fmt.Errorf("producers must be greater than 0")
+	}
+	switch producer.SendMode {
+	case SendModeSync, SendModeAsync:
+	default:
+		return fmt.Errorf("invalid send mode: %s, valid options: %s, %s", producer.SendMode, SendModeSync, SendModeAsync)
+	}
+	if producer.MaxPending <= 0 {
+		return fmt.Errorf("max pending messages must be greater than 0")
+	}
+	if producer.Batching {
+		if producer.BatchingMaxMessages <= 0 {
+			return fmt.Errorf("batching max messages must be greater than 0")
+		}
+		if producer.BatchingMaxDelay <= 0 {
+			return fmt.Errorf("batching max delay must be positive")
+		}
+	}
+	switch producer.Compression {
+	case "none", "lz4", "zlib", "zstd":
+	default:
+		return fmt.Errorf("invalid compression: %s, valid options: none, lz4, zlib, zstd", producer.Compression)
+	}
+	if producer.Keys < 0 {
+		return fmt.Errorf("keys cannot be negative")
+	}
+	if producer.Payload.Enabled() {
+		if err := producer.Payload.Validate(); err != nil {
+			return fmt.Errorf("invalid payload template: %w", err)
+		}
+	} else if producer.MessageSize < 0 {
+		return fmt.Errorf("message size cannot be negative")
+	}
+
+	consumer := specific.Consumer
+	if consumer.Subscription == "" {
+		return fmt.Errorf("subscription cannot be empty")
+	}
+	switch consumer.SubscriptionType {
+	case SubscriptionExclusive, SubscriptionShared, SubscriptionFailover, SubscriptionKeyShared:
+	default:
+		return fmt.Errorf("invalid subscription type: %s, valid options: %s, %s, %s, %s", consumer.SubscriptionType,
+			SubscriptionExclusive, SubscriptionShared, SubscriptionFailover, SubscriptionKeyShared)
+	}
+	if consumer.Consumers <= 0 {
+		return fmt.Errorf("consumers must be greater than 0")
+	}
+	if consumer.SubscriptionType == SubscriptionExclusive && consumer.Consumers > 1 {
+		return fmt.Errorf("an exclusive subscription allows a single consumer, got %d", consumer.Consumers)
+	}
+	if consumer.ReceiverQueueSize <= 0 {
+		return fmt.Errorf("receiver queue size must be greater than 0")
+	}
+	switch consumer.InitialPosition {
+	case "earliest", "latest":
+	default:
+		return fmt.Errorf("invalid initial position: %s, valid options: earliest, latest", consumer.InitialPosition)
+	}
+	if consumer.ReceiveTimeout <= 0 {
+		return fmt.Errorf("receive timeout must be positive")
+	}
+	if specific.BacklogSampleInterval < 0 {
+		return fmt.Errorf("backlog sample interval cannot be negative")
+	}
+
+	return nil
+}
+
+// Clone 实现Config接口
+func (c *PulsarConfig) Clone() interfaces.Config {
+	clone := *c
+	return &clone
+}
+
+// ConnectionConfig接口实现
+
+// GetAddresses 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetAddresses() []string {
+	return []string{c.GetServiceURL()}
+}
+
+// GetCredentials 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetCredentials() map[string]string {
+	return map[string]string{
+		"token": c.Token,
+	}
+}
+
+// GetPoolConfig 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetPoolConfig() interfaces.PoolConfig {
+	return &PoolConfig{timeout: c.Timeout}
+}
+
+// GetTimeout 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetTimeout() time.Duration {
+	return c.Timeout
+}
+
+// PoolConfig 连接池配置（客户端按broker自动管理连接）
+type PoolConfig struct {
+	timeout time.Duration
+}
+
+func (p *PoolConfig) GetPoolSize() int                    { return 1 }
+func (p *PoolConfig) GetMinIdle() int                     { return 1 }
+func (p *PoolConfig) GetMaxIdle() int                     { return 1 }
+func (p *PoolConfig) GetIdleTimeout() time.Duration       { return 0 }
+func (p *PoolConfig) GetConnectionTimeout() time.Duration { return p.timeout }
+
+// BenchmarkConfig接口实现
+
+// GetTotal 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTotal() int {
+	return b.Total
+}
+
+// GetParallels 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetParallels() int {
+	return b.Parallels
+}
+
+// GetDataSize 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetDataSize() int {
+	return 0
+}
+
+// GetTTL 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTTL() time.Duration {
+	return 0
+}
+
+// GetReadPercent 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetReadPercent() int {
+	if b.TestCase == OperationConsume {
+		return 100
+	}
+	return 0
+}
+
+// GetRandomKeys 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetRandomKeys() int {
+	return 0
+}
+
+// GetTestCase 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTestCase() string {
+	return b.TestCase
+}
+
+// GetDuration 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetDuration() time.Duration {
+	return b.Duration
+}
+
+// GetTimeout 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTimeout() time.Duration {
+	return 0
+}
+
+// GetRampUp 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetRampUp() time.Duration {
+	return 0
+}
@@ -0,0 +1,62 @@
+package config
+
+import "testing"
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*PulsarConfig)
+		valid  bool
+	}{
+		{"default", func(c *PulsarConfig) {}, true},
+		{"consume key_shared", func(c *PulsarConfig) {
+			c.BenchMark.TestCase = OperationConsume
+			c.PulsarSpecific.Consumer.SubscriptionType = SubscriptionKeyShared
+			c.PulsarSpecific.Consumer.Consumers = 3
+		}, true},
+		{"invalid test case", func(c *PulsarConfig) { c.BenchMark.TestCase = "mixed" }, false},
+		{"invalid send mode", func(c *PulsarConfig) { c.PulsarSpecific.Producer.SendMode = "fire" }, false},
+		{"invalid compression", func(c *PulsarConfig) { c.PulsarSpecific.Producer.Compression = "gzip" }, false},
+		{"exclusive with two consumers", func(c *PulsarConfig) {
+			c.PulsarSpecific.Consumer.SubscriptionType = SubscriptionExclusive
+			c.PulsarSpecific.Consumer.Consumers = 2
+		}, false},
+		{"invalid subscription type", func(c *PulsarConfig) { c.PulsarSpecific.Consumer.SubscriptionType = "broadcast" }, false},
+		{"invalid service url", func(c *PulsarConfig) { c.Connection.ServiceURL = "http://broker:8080" }, false},
+		{"invalid topic", func(c *PulsarConfig) { c.PulsarSpecific.Topic = "kafka://a/b/c" }, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := NewDefaultPulsarConfig()
+			test.modify(cfg)
+			if err := cfg.Validate(); (err == nil) != test.valid {
+				t.Errorf("Validate() error = %v, want valid %v", err, test.valid)
+			}
+		})
+	}
+}
+
+func TestConfigURLs(t *testing.T) {
+	cfg := NewDefaultPulsarConfig()
+	if url := cfg.Connection.GetServiceURL(); url != "pulsar://localhost:6650" {
+		t.Errorf("service url = %s", url)
+	}
+	if url := cfg.Connection.GetAdminURL(); url != "http://localhost:8080" {
+		t.Errorf("admin url = %s", url)
+	}
+	cfg.Connection.ServiceURL = "pulsar+ssl://broker:6651"
+	if url := cfg.Connection.GetAdminURL(); url != "https://broker:8080" {
+		t.Errorf("admin url for tls service url = %s", url)
+	}
+
+	for topic, want := range map[string]string{
+		"orders":                            "persistent://public/default/orders",
+		"tenant/ns/orders":                  "persistent://tenant/ns/orders",
+		"non-persistent://tenant/ns/orders": "non-persistent://tenant/ns/orders",
+	} {
+		cfg.PulsarSpecific.Topic = topic
+		if got := cfg.GetTopic(); got != want {
+			t.Errorf("GetTopic(%s) = %s, want %s", topic, got, want)
+		}
+	}
+}
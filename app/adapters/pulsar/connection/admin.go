@@ -0,0 +1,99 @@
+package connection
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// AdminClient 通过管理REST API查询主题统计
+type AdminClient struct {
+	client  *http.Client
+	baseURL string
+	token   string
+}
+
+// SubscriptionBacklog 订阅的积压与消费者数
+type SubscriptionBacklog struct {
+	Backlog   int64
+	Consumers int
+}
+
+// topicStats 主题统计中使用的字段
+type topicStats struct {
+	Subscriptions map[string]struct {
+		MsgBacklog int64             `json:"msgBacklog"`
+		Consumers  []json.RawMessage `json:"consumers"`
+	} `json:"subscriptions"`
+}
+
+// NewAdminClient 创建管理API客户端
+func NewAdminClient(baseURL, token string, timeout time.Duration) *AdminClient {
+	return &AdminClient{
+		client:  &http.Client{Timeout: timeout},
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+	}
+}
+
+// Backlog 查询订阅的积压消息数；分区主题使用汇总各分区的partitioned-stats
+func (a *AdminClient) Backlog(ctx context.Context, topic, subscription string) (SubscriptionBacklog, error) {
+	path, err := topicPath(topic)
+	if err != nil {
+		return SubscriptionBacklog{}, err
+	}
+
+	stats, status, err := a.stats(ctx, path+"/stats")
+	if status == http.StatusNotFound {
+		// 分区主题本身没有stats，改为查询各分区的汇总
+		stats, _, err = a.stats(ctx, path+"/partitioned-stats")
+	}
+	if err != nil {
+		return SubscriptionBacklog{}, err
+	}
+
+	sub, ok := stats.Subscriptions[subscription]
+	if !ok {
+		return SubscriptionBacklog{}, fmt.Errorf("subscription %s not found on %s", subscription, topic)
+	}
+	return SubscriptionBacklog{Backlog: sub.MsgBacklog, Consumers: len(sub.Consumers)}, nil
+}
+
+// stats 请求统计接口，返回HTTP状态码以便区分主题不存在
+func (a *AdminClient) stats(ctx context.Context, path string) (*topicStats, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.baseURL+path, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if a.token != "" {
+		req.Header.Set("Authorization", "Bearer "+a.token)
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, resp.StatusCode, fmt.Errorf("admin api %s: %s %s", path, resp.Status, strings.TrimSpace(string(body)))
+	}
+	var stats topicStats
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("failed to decode topic stats: %w", err)
+	}
+	return &stats, resp.StatusCode, nil
+}
+
+// topicPath 将persistent://tenant/namespace/topic转换为管理API路径
+func topicPath(topic string) (string, error) {
+	domain, name, ok := strings.Cut(topic, "://")
+	if !ok || strings.Count(name, "/") != 2 {
+		return "", fmt.Errorf("invalid topic %s (expected persistent://tenant/namespace/topic)", topic)
+	}
+	return "/admin/v2/" + domain + "/" + name, nil
+}
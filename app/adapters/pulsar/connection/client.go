@@ -0,0 +1,158 @@
+package connection
+
+import (
+	"fmt"
+	"strconv"
+
+	"abc-runner/app/adapters/pulsar/config"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/apache/pulsar-client-go/pulsar/log"
+)
+
+// Client Pulsar客户端，持有测试主题的生产者与同一订阅下的消费者
+type Client struct {
+	topic     string
+	client    pulsar.Client
+	producers []pulsar.Producer
+	consumers []pulsar.Consumer
+	messages  chan pulsar.ConsumerMessage
+}
+
+// compressionTypes 压缩算法名称到客户端常量的映射
+var compressionTypes = map[string]pulsar.CompressionType{
+	"none": pulsar.NoCompression,
+	"lz4":  pulsar.LZ4,
+	"zlib": pulsar.ZLib,
+	"zstd": pulsar.ZSTD,
+}
+
+// subscriptionTypes 订阅类型名称到客户端常量的映射
+var subscriptionTypes = map[string]pulsar.SubscriptionType{
+	config.SubscriptionExclusive: pulsar.Exclusive,
+	config.SubscriptionShared:    pulsar.Shared,
+	config.SubscriptionFailover:  pulsar.Failover,
+	config.SubscriptionKeyShared: pulsar.KeyShared,
+}
+
+// NewClientOptions 按配置构造客户端选项，客户端日志被关闭以免干扰输出
+func NewClientOptions(cfg *config.ConnectionConfig) pulsar.ClientOptions {
+	options := pulsar.ClientOptions{
+		URL:               cfg.GetServiceURL(),
+		ConnectionTimeout: cfg.Timeout,
+		OperationTimeout:  cfg.Timeout,
+		Logger:            log.DefaultNopLogger(),
+	}
+	if cfg.Token != "" {
+		options.Authentication = pulsar.NewAuthenticationToken(cfg.Token)
+	}
+	return options
+}
+
+// NewProducerOptions 按配置构造生产者选项，生产者名称由服务端分配以保证在主题内唯一
+func NewProducerOptions(cfg *config.PulsarConfig) pulsar.ProducerOptions {
+	producer := cfg.PulsarSpecific.Producer
+	options := pulsar.ProducerOptions{
+		Topic:              cfg.GetTopic(),
+		SendTimeout:        cfg.Connection.Timeout,
+		MaxPendingMessages: producer.MaxPending,
+		DisableBatching:    !producer.Batching,
+		CompressionType:    compressionTypes[producer.Compression],
+	}
+	if producer.Batching {
+		options.BatchingMaxMessages = uint(producer.BatchingMaxMessages)
+		options.BatchingMaxPublishDelay = producer.BatchingMaxDelay
+	}
+	return options
+}
+
+// NewConsumerOptions 按配置构造第index个消费者的选项，各消费者共用messages通道
+func NewConsumerOptions(cfg *config.PulsarConfig, index int, messages chan pulsar.ConsumerMessage) pulsar.ConsumerOptions {
+	consumer := cfg.PulsarSpecific.Consumer
+	options := pulsar.ConsumerOptions{
+		Topic:                       cfg.GetTopic(),
+		SubscriptionName:            consumer.Subscription,
+		Type:                        subscriptionTypes[consumer.SubscriptionType],
+		Name:                        "abc-runner-" + strconv.Itoa(index),
+		ReceiverQueueSize:           consumer.ReceiverQueueSize,
+		SubscriptionInitialPosition: pulsar.SubscriptionPositionLatest,
+		MessageChannel:              messages,
+	}
+	if consumer.InitialPosition == "earliest" {
+		options.SubscriptionInitialPosition = pulsar.SubscriptionPositionEarliest
+	}
+	return options
+}
+
+// NewClient 连接服务端并创建生产者；consume用例同时创建订阅的消费者，使预填充的消息进入订阅
+func NewClient(cfg *config.PulsarConfig) (*Client, error) {
+	client, err := pulsar.NewClient(NewClientOptions(&cfg.Connection))
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{topic: cfg.GetTopic(), client: client}
+
+	specific := cfg.PulsarSpecific
+	for i := 0; i < specific.Producer.Producers; i++ {
+		producer, err := client.CreateProducer(NewProducerOptions(cfg))
+		if err != nil {
+			c.Close()
+			return nil, fmt.Errorf("failed to create producer on %s: %w", cfg.GetTopic(), err)
+		}
+		c.producers = append(c.producers, producer)
+	}
+
+	if cfg.BenchMark.TestCase == config.OperationConsume {
+		c.messages = make(chan pulsar.ConsumerMessage)
+		for i := 0; i < specific.Consumer.Consumers; i++ {
+			consumer, err := client.Subscribe(NewConsumerOptions(cfg, i, c.messages))
+			if err != nil {
+				c.Close()
+				return nil, fmt.Errorf("failed to subscribe %s to %s: %w", specific.Consumer.Subscription, cfg.GetTopic(), err)
+			}
+			c.consumers = append(c.consumers, consumer)
+		}
+	}
+	return c, nil
+}
+
+// Producers 测试主题的生产者
+func (c *Client) Producers() []pulsar.Producer {
+	return c.producers
+}
+
+// Messages 各消费者收到的消息，未订阅时为nil
+func (c *Client) Messages() <-chan pulsar.ConsumerMessage {
+	return c.messages
+}
+
+// Consumers 消费者数
+func (c *Client) Consumers() int {
+	return len(c.consumers)
+}
+
+// Ping 查询测试主题的分区，检查与服务端的往返
+func (c *Client) Ping() error {
+	if c.client == nil {
+		return fmt.Errorf("client is closed")
+	}
+	_, err := c.client.TopicPartitions(c.topic)
+	return err
+}
+
+// Close 关闭消费者、生产者与客户端
+func (c *Client) Close() error {
+	for _, consumer := range c.consumers {
+		consumer.Close()
+	}
+	c.consumers = nil
+	for _, producer := range c.producers {
+		producer.Close()
+	}
+	c.producers = nil
+	if c.client != nil {
+		c.client.Close()
+		c.client = nil
+	}
+	return nil
+}
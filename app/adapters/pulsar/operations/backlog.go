@@ -0,0 +1,128 @@
+package operations
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"abc-runner/app/adapters/pulsar/connection"
+)
+
+// BacklogSource 查询订阅积压，由*connection.AdminClient实现
+type BacklogSource interface {
+	Backlog(ctx context.Context, topic, subscription string) (connection.SubscriptionBacklog, error)
+}
+
+// BacklogStats 订阅在运行期间的积压采样统计
+type BacklogStats struct {
+	Topic        string  `json:"topic"`
+	Subscription string  `json:"subscription"`
+	Samples      int     `json:"samples"`
+	Errors       int     `json:"errors,omitempty"` // 采样失败次数
+	First        int64   `json:"first"`            // 首次采样的积压消息数
+	Last         int64   `json:"last"`             // 最后一次采样的积压消息数
+	Min          int64   `json:"min"`
+	Max          int64   `json:"max"`
+	Average      float64 `json:"average"`
+	Growth       float64 `json:"growth_per_sec"` // 首末两次采样间的平均增长速度，持续为正说明消费跟不上生产
+	Consumers    int     `json:"consumers"`      // 最后一次采样时订阅的消费者数
+	LastError    string  `json:"last_error,omitempty"`
+}
+
+// BacklogSampler 运行期间按固定间隔采样订阅积压
+type BacklogSampler struct {
+	source   BacklogSource
+	interval time.Duration
+	mutex    sync.Mutex
+	stats    BacklogStats
+	sum      int64
+	firstAt  time.Time
+	lastAt   time.Time
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// NewBacklogSampler 创建订阅积压采样器
+func NewBacklogSampler(source BacklogSource, topic, subscription string, interval time.Duration) *BacklogSampler {
+	return &BacklogSampler{
+		source:   source,
+		interval: interval,
+		stats:    BacklogStats{Topic: topic, Subscription: subscription},
+	}
+}
+
+// Start 立即采样一次，之后每个间隔采样一次，直到Stop或ctx结束
+func (s *BacklogSampler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.done = make(chan struct{})
+	s.sample(ctx)
+
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.sample(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop 停止采样并做最后一次采样，返回统计
+func (s *BacklogSampler) Stop() BacklogStats {
+	if s.cancel != nil {
+		s.cancel()
+		<-s.done
+		s.cancel = nil
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		s.sample(ctx)
+		cancel()
+	}
+	return s.Stats()
+}
+
+// sample 采样一次
+func (s *BacklogSampler) sample(ctx context.Context) {
+	backlog, err := s.source.Backlog(ctx, s.stats.Topic, s.stats.Subscription)
+	now := time.Now()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	stats := &s.stats
+	if err != nil {
+		stats.Errors++
+		stats.LastError = err.Error()
+		return
+	}
+	messages := backlog.Backlog
+	if stats.Samples == 0 {
+		stats.First, stats.Min, stats.Max = messages, messages, messages
+		s.firstAt = now
+	}
+	stats.Samples++
+	stats.Last = messages
+	stats.Consumers = backlog.Consumers
+	stats.Min = min(stats.Min, messages)
+	stats.Max = max(stats.Max, messages)
+	s.sum += messages
+	s.lastAt = now
+}
+
+// Stats 获取采样统计
+func (s *BacklogSampler) Stats() BacklogStats {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stats := s.stats
+	if stats.Samples > 0 {
+		stats.Average = float64(s.sum) / float64(stats.Samples)
+	}
+	if elapsed := s.lastAt.Sub(s.firstAt).Seconds(); stats.Samples > 1 && elapsed > 0 {
+		stats.Growth = float64(stats.Last-stats.First) / elapsed
+	}
+	return stats
+}
@@ -0,0 +1,246 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"abc-runner/app/adapters/pulsar/config"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+)
+
+// SentAtProperty 消息属性：发送时刻的Unix纳秒时间戳，用于计算端到端延迟
+const SentAtProperty = "abc-sent-at"
+
+// Producer 测试主题的生产者，由pulsar.Producer实现
+type Producer interface {
+	Send(ctx context.Context, msg *pulsar.ProducerMessage) (pulsar.MessageID, error)
+	SendAsync(ctx context.Context, msg *pulsar.ProducerMessage, callback func(pulsar.MessageID, *pulsar.ProducerMessage, error))
+	Flush() error
+}
+
+// ConsumerStats 单个消费者收到的消息数，反映订阅类型下消息在各消费者之间的分布
+type ConsumerStats struct {
+	Name     string  `json:"name"`
+	Received int64   `json:"received"`
+	Share    float64 `json:"share"` // 占全部接收消息的百分比
+}
+
+// PulsarExecutor Pulsar操作执行器
+// produce按操作编号轮流使用各生产者；consume从订阅下全部消费者共用的消息通道接收，由订阅类型决定消息分发
+type PulsarExecutor struct {
+	producers []Producer
+	messages  <-chan pulsar.ConsumerMessage
+	sendMode  string
+	timeout   time.Duration
+	tracker   *metrics.OperationTypeTracker
+
+	// pending async模式下等待服务端确认的消息
+	pending sync.WaitGroup
+
+	endToEnd *metrics.LatencyTracker // 发送到接收的延迟
+
+	consumerMutex sync.Mutex
+	consumers     map[string]int64
+}
+
+// NewPulsarExecutor 创建Pulsar操作执行器，messages为nil时不支持consume
+func NewPulsarExecutor(producers []Producer, messages <-chan pulsar.ConsumerMessage, cfg *config.PulsarConfig) *PulsarExecutor {
+	specific := cfg.PulsarSpecific
+	return &PulsarExecutor{
+		producers: producers,
+		messages:  messages,
+		sendMode:  specific.Producer.SendMode,
+		timeout:   specific.Consumer.ReceiveTimeout,
+		tracker:   newOperationTracker(),
+		endToEnd: metrics.NewLatencyTracker(metrics.LatencyConfig{
+			SignificantDigits: metrics.DefaultHdrSignificantDigits,
+			SamplingRate:      1.0,
+		}),
+		consumers: make(map[string]int64),
+	}
+}
+
+// ExecuteOperation 执行Pulsar操作
+// 预填充的发送始终同步执行且不计入按操作类型的统计
+func (e *PulsarExecutor) ExecuteOperation(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	prefill, _ := operation.Params["prefill"].(bool)
+
+	var bytes int64
+	var redelivered bool
+	var err error
+	startTime := time.Now()
+	switch operation.Type {
+	case config.OperationProduce:
+		bytes, err = e.produce(ctx, operation, prefill)
+	case config.OperationConsume:
+		bytes, redelivered, err = e.consume(ctx)
+	default:
+		return nil, fmt.Errorf("unsupported operation type: %s", operation.Type)
+	}
+	duration := time.Since(startTime)
+
+	if !prefill {
+		e.tracker.Record(operation.Type, bytes, redelivered, duration, err)
+	}
+	if err != nil {
+		err = fmt.Errorf("%s failed: %w", operation.Type, err)
+	}
+
+	result := &interfaces.OperationResult{
+		Success:  err == nil,
+		Duration: duration,
+		IsRead:   operation.Type == config.OperationConsume,
+		Error:    err,
+		Value:    bytes,
+		Metadata: map[string]interface{}{
+			"protocol":       "pulsar",
+			"operation_type": operation.Type,
+			"bytes":          bytes,
+		},
+	}
+	if operation.Type == config.OperationProduce {
+		result.BytesWritten = bytes
+	} else {
+		result.BytesRead = bytes
+	}
+	if operation.Key != "" {
+		result.Metadata["key"] = operation.Key
+	}
+	for k, v := range operation.Metadata {
+		result.Metadata[k] = v
+	}
+	return result, err
+}
+
+// produce 发送一条消息：sync模式等待服务端确认；async模式在消息进入发送队列后返回，
+// 确认结果与延迟由回调记为produce_ack
+func (e *PulsarExecutor) produce(ctx context.Context, operation interfaces.Operation, prefill bool) (int64, error) {
+	if len(e.producers) == 0 {
+		return 0, fmt.Errorf("no producer available")
+	}
+	body, ok := operation.Value.([]byte)
+	if !ok {
+		return 0, fmt.Errorf("missing message body")
+	}
+	jobID, _ := operation.Params["job_id"].(int)
+	producer := e.producers[jobID%len(e.producers)]
+
+	sentAt := time.Now()
+	msg := &pulsar.ProducerMessage{
+		Payload:    body,
+		Key:        operation.Key,
+		Properties: map[string]string{SentAtProperty: strconv.FormatInt(sentAt.UnixNano(), 10)},
+	}
+	if prefill || e.sendMode != config.SendModeAsync {
+		if _, err := producer.Send(ctx, msg); err != nil {
+			return 0, err
+		}
+		return int64(len(body)), nil
+	}
+
+	e.pending.Add(1)
+	// 回调在发送超时后也会被调用，不受操作上下文取消的影响
+	producer.SendAsync(context.WithoutCancel(ctx), msg, func(_ pulsar.MessageID, _ *pulsar.ProducerMessage, err error) {
+		e.tracker.Record(config.OperationProduce+"_ack", int64(len(body)), false, time.Since(sentAt), err)
+		e.pending.Done()
+	})
+	return int64(len(body)), nil
+}
+
+// consume 接收一条消息并确认，等待超过receive_timeout时失败
+func (e *PulsarExecutor) consume(ctx context.Context) (int64, bool, error) {
+	if e.messages == nil {
+		return 0, false, fmt.Errorf("no consumer subscribed")
+	}
+	timer := time.NewTimer(e.timeout)
+	defer timer.Stop()
+
+	var received pulsar.ConsumerMessage
+	select {
+	case msg, ok := <-e.messages:
+		if !ok {
+			return 0, false, fmt.Errorf("consumers are closed")
+		}
+		received = msg
+	case <-timer.C:
+		return 0, false, ErrReceiveTimeout
+	case <-ctx.Done():
+		return 0, false, ctx.Err()
+	}
+
+	receivedAt := time.Now()
+	if err := received.Consumer.Ack(received.Message); err != nil {
+		return 0, false, err
+	}
+	e.recordDelivery(received.Consumer.Name(), received.Message, receivedAt)
+	return int64(len(received.Payload())), received.RedeliveryCount() > 0, nil
+}
+
+// recordDelivery 记录消息的端到端延迟与接收的消费者；没有发送时刻属性的消息按发布时间计算
+func (e *PulsarExecutor) recordDelivery(consumer string, msg pulsar.Message, receivedAt time.Time) {
+	sentAt := msg.PublishTime()
+	if value, ok := msg.Properties()[SentAtProperty]; ok {
+		if nanos, err := strconv.ParseInt(value, 10, 64); err == nil {
+			sentAt = time.Unix(0, nanos)
+		}
+	}
+	if !sentAt.IsZero() && receivedAt.After(sentAt) {
+		e.endToEnd.Record(receivedAt.Sub(sentAt))
+	}
+
+	e.consumerMutex.Lock()
+	e.consumers[consumer]++
+	e.consumerMutex.Unlock()
+}
+
+// Flush 发出各生产者缓冲的批次并等待async模式下全部消息的确认，应在计算测试时长之前调用
+func (e *PulsarExecutor) Flush() error {
+	var firstErr error
+	for _, producer := range e.producers {
+		if err := producer.Flush(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	e.pending.Wait()
+	return firstErr
+}
+
+// OperationStats 获取按操作类型的统计
+func (e *PulsarExecutor) OperationStats() []OperationStats {
+	return e.tracker.Stats()
+}
+
+// EndToEndLatency 获取从发送到接收的延迟
+func (e *PulsarExecutor) EndToEndLatency() metrics.LatencyMetrics {
+	return e.endToEnd.GetMetrics()
+}
+
+// ConsumerStats 获取按名称排序的各消费者接收统计
+func (e *PulsarExecutor) ConsumerStats() []ConsumerStats {
+	e.consumerMutex.Lock()
+	defer e.consumerMutex.Unlock()
+
+	var total int64
+	for _, n := range e.consumers {
+		total += n
+	}
+	stats := make([]ConsumerStats, 0, len(e.consumers))
+	for name, n := range e.consumers {
+		entry := ConsumerStats{Name: name, Received: n}
+		if total > 0 {
+			entry.Share = float64(n) / float64(total) * 100
+		}
+		stats = append(stats, entry)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Name < stats[j].Name
+	})
+	return stats
+}
@@ -0,0 +1,304 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"abc-runner/app/adapters/pulsar/config"
+	"abc-runner/app/adapters/pulsar/connection"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+)
+
+// fakeProducer 记录发送的消息，async发送的回调在独立协程中延迟调用
+type fakeProducer struct {
+	mutex   sync.Mutex
+	sent    []*pulsar.ProducerMessage
+	err     error
+	delay   time.Duration
+	flushes int
+}
+
+func (p *fakeProducer) Send(ctx context.Context, msg *pulsar.ProducerMessage) (pulsar.MessageID, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.err != nil {
+		return nil, p.err
+	}
+	p.sent = append(p.sent, msg)
+	return nil, nil
+}
+
+func (p *fakeProducer) SendAsync(ctx context.Context, msg *pulsar.ProducerMessage, callback func(pulsar.MessageID, *pulsar.ProducerMessage, error)) {
+	go func() {
+		time.Sleep(p.delay)
+		_, err := p.Send(ctx, msg)
+		callback(nil, msg, err)
+	}()
+}
+
+func (p *fakeProducer) Flush() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.flushes++
+	return nil
+}
+
+// fakeConsumer 记录确认次数的消费者，未实现的方法不会被调用
+type fakeConsumer struct {
+	pulsar.Consumer
+	name  string
+	mutex sync.Mutex
+	acks  int
+}
+
+func (c *fakeConsumer) Name() string { return c.name }
+
+func (c *fakeConsumer) Ack(msg pulsar.Message) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.acks++
+	return nil
+}
+
+// fakeMessage 测试消息，未实现的方法不会被调用
+type fakeMessage struct {
+	pulsar.Message
+	payload     []byte
+	properties  map[string]string
+	publishTime time.Time
+	redelivered uint32
+}
+
+func (m *fakeMessage) Payload() []byte               { return m.payload }
+func (m *fakeMessage) Properties() map[string]string { return m.properties }
+func (m *fakeMessage) PublishTime() time.Time        { return m.publishTime }
+func (m *fakeMessage) RedeliveryCount() uint32       { return m.redelivered }
+
+func TestOperationFactory(t *testing.T) {
+	cfg := config.NewDefaultPulsarConfig()
+	cfg.PulsarSpecific.Producer.MessageSize = 64
+	cfg.PulsarSpecific.Producer.Keys = 2
+	factory := NewOperationFactory(cfg)
+
+	for jobID, want := range []string{"key-0", "key-1", "key-0"} {
+		operation := factory.CreateOperation(jobID, &cfg.BenchMark)
+		if operation.Type != config.OperationProduce || operation.Key != want {
+			t.Errorf("job %d: %s with key %s, want produce with %s", jobID, operation.Type, operation.Key, want)
+		}
+		if body := operation.Value.([]byte); len(body) != 64 {
+			t.Errorf("job %d: body size %d, want 64", jobID, len(body))
+		}
+	}
+
+	cfg.PulsarSpecific.Producer.Keys = 0
+	cfg.PulsarSpecific.Producer.Payload.Template = `{"seq":{{seq}}}`
+	operation := NewOperationFactory(cfg).CreatePrepareOperation(7)
+	if operation.Key != "" || string(operation.Value.([]byte)) != `{"seq":7}` {
+		t.Errorf("prepare operation = %q %s", operation.Key, operation.Value)
+	}
+	if prefill, _ := operation.Params["prefill"].(bool); !prefill {
+		t.Error("prepare operation should be marked as prefill")
+	}
+
+	cfg.BenchMark.TestCase = config.OperationConsume
+	if operation := NewOperationFactory(cfg).CreateOperation(0, &cfg.BenchMark); operation.Type != config.OperationConsume {
+		t.Errorf("consume test created %s", operation.Type)
+	}
+}
+
+func TestExecutorProduceSync(t *testing.T) {
+	cfg := config.NewDefaultPulsarConfig()
+	producers := []*fakeProducer{{}, {}}
+	executor := NewPulsarExecutor([]Producer{producers[0], producers[1]}, nil, cfg)
+	factory := NewOperationFactory(cfg)
+	ctx := context.Background()
+
+	for jobID := 0; jobID < 4; jobID++ {
+		if _, err := executor.ExecuteOperation(ctx, factory.CreateOperation(jobID, &cfg.BenchMark)); err != nil {
+			t.Fatalf("produce: %v", err)
+		}
+	}
+	// 各生产者按操作编号轮流使用，消息携带发送时刻
+	if len(producers[0].sent) != 2 || len(producers[1].sent) != 2 {
+		t.Fatalf("sent %d and %d messages", len(producers[0].sent), len(producers[1].sent))
+	}
+	if _, err := strconv.ParseInt(producers[0].sent[0].Properties[SentAtProperty], 10, 64); err != nil {
+		t.Errorf("sent-at property: %v", err)
+	}
+
+	// 预填充不计入统计
+	if _, err := executor.ExecuteOperation(ctx, factory.CreatePrepareOperation(0)); err != nil {
+		t.Fatalf("prefill: %v", err)
+	}
+	producers[1].err = pulsar.ErrSendQueueIsFull
+	result, err := executor.ExecuteOperation(ctx, factory.CreateOperation(5, &cfg.BenchMark))
+	if err == nil || result.Success {
+		t.Fatal("send to a full queue should fail")
+	}
+
+	stats := executor.OperationStats()
+	if len(stats) != 1 || stats[0].Count != 5 || stats[0].Errors != 1 || stats[0].Volume != 4*1024 {
+		t.Fatalf("stats = %+v", stats)
+	}
+	if stats[0].ErrorCodes["queue_full"] != 1 {
+		t.Errorf("error codes = %v", stats[0].ErrorCodes)
+	}
+}
+
+func TestExecutorProduceAsync(t *testing.T) {
+	cfg := config.NewDefaultPulsarConfig()
+	cfg.PulsarSpecific.Producer.SendMode = config.SendModeAsync
+	producer := &fakeProducer{delay: 20 * time.Millisecond}
+	executor := NewPulsarExecutor([]Producer{producer}, nil, cfg)
+	factory := NewOperationFactory(cfg)
+
+	for jobID := 0; jobID < 3; jobID++ {
+		if _, err := executor.ExecuteOperation(context.Background(), factory.CreateOperation(jobID, &cfg.BenchMark)); err != nil {
+			t.Fatalf("produce: %v", err)
+		}
+	}
+	// Flush等待全部确认回调
+	if err := executor.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if producer.flushes != 1 {
+		t.Errorf("flushes = %d", producer.flushes)
+	}
+
+	stats := executor.OperationStats()
+	if len(stats) != 2 || stats[0].Type != "produce" || stats[1].Type != "produce_ack" {
+		t.Fatalf("stats = %+v", stats)
+	}
+	if stats[0].Count != 3 || stats[1].Count != 3 || stats[1].Errors != 0 {
+		t.Errorf("stats = %+v", stats)
+	}
+	if stats[1].Latency.P50 < 20*time.Millisecond {
+		t.Errorf("ack latency p50 = %v, want at least the send delay", stats[1].Latency.P50)
+	}
+}
+
+func TestExecutorConsume(t *testing.T) {
+	cfg := config.NewDefaultPulsarConfig()
+	cfg.BenchMark.TestCase = config.OperationConsume
+	consumers := []*fakeConsumer{{name: "c0"}, {name: "c1"}}
+
+	messages := make(chan pulsar.ConsumerMessage, 4)
+	sentAt := time.Now().Add(-50 * time.Millisecond)
+	for i := 0; i < 4; i++ {
+		messages <- pulsar.ConsumerMessage{
+			Consumer: consumers[min(i, 1)],
+			Message: &fakeMessage{
+				payload:     []byte("abc"),
+				properties:  map[string]string{SentAtProperty: strconv.FormatInt(sentAt.UnixNano(), 10)},
+				redelivered: uint32(i / 3),
+			},
+		}
+	}
+	executor := NewPulsarExecutor(nil, messages, cfg)
+	operation := NewOperationFactory(cfg).CreateOperation(0, &cfg.BenchMark)
+	for i := 0; i < 4; i++ {
+		if _, err := executor.ExecuteOperation(context.Background(), operation); err != nil {
+			t.Fatalf("consume %d: %v", i, err)
+		}
+	}
+
+	if consumers[0].acks != 1 || consumers[1].acks != 3 {
+		t.Errorf("acks = %d, %d", consumers[0].acks, consumers[1].acks)
+	}
+	stats := executor.OperationStats()
+	if stats[0].Count != 4 || stats[0].Volume != 12 || stats[0].Flagged != 1 {
+		t.Errorf("stats = %+v", stats[0])
+	}
+	if latency := executor.EndToEndLatency(); latency.P50 < 50*time.Millisecond {
+		t.Errorf("end-to-end p50 = %v, want at least 50ms", latency.P50)
+	}
+	if got := fmt.Sprint(executor.ConsumerStats()); got != "[{c0 1 25} {c1 3 75}]" {
+		t.Errorf("consumer stats = %s", got)
+	}
+}
+
+func TestExecutorConsumeTimeout(t *testing.T) {
+	cfg := config.NewDefaultPulsarConfig()
+	cfg.BenchMark.TestCase = config.OperationConsume
+	cfg.PulsarSpecific.Consumer.ReceiveTimeout = 10 * time.Millisecond
+	executor := NewPulsarExecutor(nil, make(chan pulsar.ConsumerMessage), cfg)
+
+	result, err := executor.ExecuteOperation(context.Background(), NewOperationFactory(cfg).CreateOperation(0, &cfg.BenchMark))
+	if !errors.Is(err, ErrReceiveTimeout) || result.Success {
+		t.Fatalf("consume from an empty subscription: success %v, err %v", result.Success, err)
+	}
+	if codes := executor.OperationStats()[0].ErrorCodes; codes["timeout"] != 1 {
+		t.Errorf("error codes = %v", codes)
+	}
+}
+
+func TestErrorCodes(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{pulsar.ErrSendTimeout, "timeout"},
+		{fmt.Errorf("produce failed: %w", pulsar.ErrProducerClosed), "closed"},
+		{pulsar.ErrMessageTooLarge, "message_too_big"},
+		{pulsar.ErrProducerFenced, "result_" + strconv.Itoa(int(pulsar.ProducerFenced))},
+		{ErrReceiveTimeout, "timeout"},
+		{context.DeadlineExceeded, "timeout"},
+		{errors.New("boom"), "client"},
+	}
+	for _, test := range tests {
+		if got := errorCode(test.err); got != test.want {
+			t.Errorf("errorCode(%v) = %s, want %s", test.err, got, test.want)
+		}
+	}
+}
+
+func TestBacklogSampler(t *testing.T) {
+	var mutex sync.Mutex
+	backlogs := []int{100, 400, 250}
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		// 分区主题只有partitioned-stats
+		if r.URL.Path != "/admin/v2/persistent/public/default/orders/partitioned-stats" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		mutex.Lock()
+		backlog := backlogs[min(calls, len(backlogs)-1)]
+		calls++
+		mutex.Unlock()
+		fmt.Fprintf(w, `{"subscriptions":{"abc-bench":{"msgBacklog":%d,"consumers":[{},{}]}}}`, backlog)
+	}))
+	defer server.Close()
+
+	admin := connection.NewAdminClient(server.URL, "secret", time.Second)
+	sampler := NewBacklogSampler(admin, "persistent://public/default/orders", "abc-bench", time.Hour)
+	sampler.Start(context.Background())
+	sampler.sample(context.Background())
+	time.Sleep(10 * time.Millisecond)
+	s := sampler.Stop()
+
+	if s.Samples != 3 || s.First != 100 || s.Last != 250 || s.Min != 100 || s.Max != 400 || s.Average != 250 || s.Consumers != 2 {
+		t.Errorf("stats = %+v", s)
+	}
+	if s.Growth <= 0 {
+		t.Errorf("growth = %f, want positive", s.Growth)
+	}
+
+	missing := NewBacklogSampler(admin, "persistent://public/default/orders", "other", time.Hour)
+	missing.Start(context.Background())
+	if s := missing.Stop(); s.Samples != 0 || s.Errors != 2 || s.LastError == "" {
+		t.Errorf("missing subscription stats = %+v", s)
+	}
+}
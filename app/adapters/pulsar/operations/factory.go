@@ -0,0 +1,101 @@
+package operations
+
+import (
+	"math/rand/v2"
+	"strconv"
+
+	"abc-runner/app/adapters/pulsar/config"
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/utils"
+)
+
+// OperationFactory Pulsar操作工厂
+type OperationFactory struct {
+	config  *config.PulsarConfig
+	payload *utils.PayloadTemplate // 消息体模板，未配置时使用message_size字节的随机消息体
+	body    []byte
+}
+
+// NewOperationFactory 创建Pulsar操作工厂
+func NewOperationFactory(cfg *config.PulsarConfig) *OperationFactory {
+	factory := &OperationFactory{config: cfg}
+	producer := cfg.PulsarSpecific.Producer
+	// 模板在解析参数时已验证
+	if producer.Payload.Enabled() {
+		if payload, err := producer.Payload.Compile(); err == nil {
+			factory.payload = payload
+		}
+	}
+	if factory.payload == nil {
+		factory.body = randomBody(producer.MessageSize)
+	}
+	return factory
+}
+
+// CreateOperation 创建操作：produce按操作编号轮流使用各生产者，consume从订阅的消费者接收
+func (f *OperationFactory) CreateOperation(jobID int, cfg execution.BenchmarkConfig) interfaces.Operation {
+	if f.config.BenchMark.TestCase == config.OperationConsume {
+		return interfaces.Operation{
+			Type: config.OperationConsume,
+			Params: map[string]interface{}{
+				"job_id": jobID,
+			},
+			Metadata: map[string]string{
+				"operation_type": config.OperationConsume,
+			},
+		}
+	}
+	return f.createProduce(jobID, false)
+}
+
+// CreatePrepareOperation 创建预填充操作：向测试主题同步发送一条消息，供consume用例接收
+func (f *OperationFactory) CreatePrepareOperation(index int) interfaces.Operation {
+	return f.createProduce(index, true)
+}
+
+// createProduce 创建发送操作，消息体按编号渲染，配置了keys时按编号轮流使用各消息键
+func (f *OperationFactory) createProduce(jobID int, prefill bool) interfaces.Operation {
+	body := f.body
+	if f.payload != nil {
+		body = []byte(f.payload.Render(jobID))
+	}
+	key := ""
+	if keys := f.config.PulsarSpecific.Producer.Keys; keys > 0 {
+		key = "key-" + strconv.Itoa(jobID%keys)
+	}
+	return interfaces.Operation{
+		Type:  config.OperationProduce,
+		Key:   key,
+		Value: body,
+		Params: map[string]interface{}{
+			"job_id":  jobID,
+			"prefill": prefill,
+		},
+		Metadata: map[string]string{
+			"operation_type": config.OperationProduce,
+		},
+	}
+}
+
+// GetOperationType 获取操作类型
+func (f *OperationFactory) GetOperationType() string {
+	return f.config.BenchMark.TestCase
+}
+
+// GetSupportedOperations 获取支持的操作类型
+func (f *OperationFactory) GetSupportedOperations() []string {
+	return []string{config.OperationProduce, config.OperationConsume}
+}
+
+// randomBody 生成size字节的随机可打印消息体，各次发送共用
+func randomBody(size int) []byte {
+	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	body := make([]byte, size)
+	for i := range body {
+		body[i] = letters[rand.IntN(len(letters))]
+	}
+	return body
+}
+
+var _ execution.PrepareFactory = (*OperationFactory)(nil)
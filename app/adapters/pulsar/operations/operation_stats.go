@@ -0,0 +1,59 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"abc-runner/app/core/metrics"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+)
+
+// ErrReceiveTimeout 在receive_timeout内没有收到消息
+var ErrReceiveTimeout = errors.New("no message received before timeout")
+
+// OperationStats 单个操作类型的统计，Volume为成功发送或接收的消息体字节数（JSON字段"bytes"），
+// Flagged为接收到的重新投递消息数（JSON字段"redelivered"）；
+// 错误码为客户端结果码名称，另有"timeout"与"client"
+type OperationStats = metrics.OperationTypeStats
+
+// newOperationTracker 创建按操作类型的统计器
+func newOperationTracker() *metrics.OperationTypeTracker {
+	return metrics.NewOperationTypeTracker(errorCode, "bytes", "redelivered")
+}
+
+// resultCodes 常见客户端结果码的名称
+var resultCodes = map[pulsar.Result]string{
+	pulsar.TimeoutError:                          "timeout",
+	pulsar.ConnectError:                          "connect",
+	pulsar.NotConnectedError:                     "not_connected",
+	pulsar.AlreadyClosedError:                    "closed",
+	pulsar.ProducerClosed:                        "closed",
+	pulsar.ConsumerClosed:                        "closed",
+	pulsar.ProducerQueueIsFull:                   "queue_full",
+	pulsar.ClientMemoryBufferIsFull:              "memory_full",
+	pulsar.ProducerBlockedQuotaExceededError:     "quota_exceeded",
+	pulsar.ProducerBlockedQuotaExceededException: "quota_exceeded",
+	pulsar.MessageTooBig:                         "message_too_big",
+	pulsar.TopicNotFound:                         "topic_not_found",
+	pulsar.TopicTerminated:                       "topic_terminated",
+	pulsar.AuthenticationError:                   "authentication",
+	pulsar.AuthorizationError:                    "authorization",
+	pulsar.BrokerPersistenceError:                "persistence",
+}
+
+// errorCode 错误分类：客户端错误取结果码名称（未列出的为"result_N"），接收超时与上下文超时为"timeout"，其余为"client"
+func errorCode(err error) string {
+	var pulsarErr *pulsar.Error
+	if errors.As(err, &pulsarErr) {
+		if code, ok := resultCodes[pulsarErr.Result()]; ok {
+			return code
+		}
+		return "result_" + strconv.Itoa(int(pulsarErr.Result()))
+	}
+	if errors.Is(err, ErrReceiveTimeout) || errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	return "client"
+}
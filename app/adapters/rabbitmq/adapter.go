@@ -0,0 +1,241 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"abc-runner/app/adapters/rabbitmq/config"
+	"abc-runner/app/adapters/rabbitmq/connection"
+	"abc-runner/app/adapters/rabbitmq/operations"
+	"abc-runner/app/core/interfaces"
+)
+
+// RabbitMQAdapter RabbitMQ(AMQP 0.9.1)协议适配器 - 遵循统一架构模式
+// 职责：连接与通道管理、拓扑声明、队列深度采样、健康检查
+type RabbitMQAdapter struct {
+	config             *config.RabbitMQConfig
+	client             *connection.Client
+	sessions           []*connection.Session
+	rabbitMQOperations *operations.RabbitExecutor
+	depthSampler       *operations.DepthSampler
+	metricsCollector   interfaces.DefaultMetricsCollector
+	mu                 sync.RWMutex
+	isConnected        bool
+
+	// 统计信息
+	totalOperations  int64
+	failedOperations int64
+}
+
+// NewRabbitMQAdapter 创建RabbitMQ适配器
+func NewRabbitMQAdapter(metricsCollector interfaces.DefaultMetricsCollector) *RabbitMQAdapter {
+	return &RabbitMQAdapter{
+		metricsCollector: metricsCollector,
+	}
+}
+
+// Connect 建立连接并声明拓扑，为每个并发工作协程打开一个通道
+func (r *RabbitMQAdapter) Connect(ctx context.Context, cfg interfaces.Config) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rabbitConfig, ok := cfg.(*config.RabbitMQConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type for RabbitMQ adapter: expected *config.RabbitMQConfig, got %T", cfg)
+	}
+
+	if err := rabbitConfig.Validate(); err != nil {
+		return fmt.Errorf("config validation failed: %w", err)
+	}
+	r.config = rabbitConfig
+
+	client, err := connection.NewClient(ctx, rabbitConfig)
+	if err != nil {
+		return err
+	}
+	if err := client.Declare(); err != nil {
+		client.Close()
+		return err
+	}
+
+	sessions := make([]operations.Session, rabbitConfig.BenchMark.Parallels)
+	r.sessions = make([]*connection.Session, 0, len(sessions))
+	for i := range sessions {
+		session, err := client.NewSession(i)
+		if err != nil {
+			client.Close()
+			r.sessions = nil
+			return fmt.Errorf("failed to open channel %d: %w", i, err)
+		}
+		r.sessions = append(r.sessions, session)
+		sessions[i] = session
+	}
+
+	r.client = client
+	r.rabbitMQOperations = operations.NewRabbitExecutor(sessions, rabbitConfig)
+	r.isConnected = true
+	return nil
+}
+
+// StartDepthSampling 按depth_sample_interval开始采样各测试队列的深度，未配置间隔时不采样
+func (r *RabbitMQAdapter) StartDepthSampling(ctx context.Context) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.client == nil || r.depthSampler != nil {
+		return
+	}
+	specific := r.config.RabbitMQSpecific
+	if specific.DepthSampleInterval <= 0 {
+		return
+	}
+	r.depthSampler = operations.NewDepthSampler(r.client, specific.Queue.Names, specific.DepthSampleInterval)
+	r.depthSampler.Start(ctx)
+}
+
+// StopDepthSampling 停止队列深度采样，返回各队列的统计；未采样时返回nil
+func (r *RabbitMQAdapter) StopDepthSampling() []operations.QueueDepthStats {
+	r.mu.RLock()
+	sampler := r.depthSampler
+	r.mu.RUnlock()
+
+	if sampler == nil {
+		return nil
+	}
+	return sampler.Stop()
+}
+
+// Execute 执行操作 - 使用执行器处理
+func (r *RabbitMQAdapter) Execute(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	if !r.isConnected {
+		return nil, fmt.Errorf("adapter not connected")
+	}
+
+	atomic.AddInt64(&r.totalOperations, 1)
+	result, err := r.rabbitMQOperations.ExecuteOperation(ctx, operation)
+	if err != nil {
+		atomic.AddInt64(&r.failedOperations, 1)
+	}
+	return result, err
+}
+
+// Close 确认尚未确认的消息后关闭通道与连接
+func (r *RabbitMQAdapter) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.depthSampler != nil {
+		r.depthSampler.Stop()
+	}
+	if r.rabbitMQOperations != nil {
+		r.rabbitMQOperations.Flush()
+	}
+	for _, session := range r.sessions {
+		session.Close()
+	}
+	r.sessions = nil
+	if r.client != nil {
+		r.client.Close()
+		r.client = nil
+	}
+	r.isConnected = false
+	return nil
+}
+
+// GetProtocolMetrics 获取协议特定指标
+func (r *RabbitMQAdapter) GetProtocolMetrics() map[string]interface{} {
+	metrics := map[string]interface{}{
+		"protocol":          "rabbitmq",
+		"total_operations":  atomic.LoadInt64(&r.totalOperations),
+		"failed_operations": atomic.LoadInt64(&r.failedOperations),
+	}
+
+	if r.config != nil {
+		specific := r.config.RabbitMQSpecific
+		metrics["exchange"] = specific.Exchange.Name
+		metrics["exchange_type"] = specific.Exchange.Type
+		metrics["queues"] = specific.Queue.Names
+		metrics["publisher_confirms"] = specific.Publisher.Confirms
+		metrics["persistent"] = specific.Publisher.Persistent
+		if r.config.BenchMark.TestCase == config.OperationConsume {
+			metrics["ack_mode"] = specific.Consumer.AckMode
+			metrics["prefetch"] = specific.Consumer.Prefetch
+		}
+	}
+	if r.client != nil {
+		metrics["server_version"] = r.client.ServerVersion()
+		metrics["client_stats"] = r.client.Stats(len(r.sessions))
+	}
+	if stats := r.GetOperationStats(); stats != nil {
+		metrics["operation_stats"] = stats
+	}
+	if stats := r.GetQueueDepthStats(); stats != nil {
+		metrics["queue_depth"] = stats
+	}
+
+	return metrics
+}
+
+// GetOperationStats 获取按操作类型的统计，未连接时返回nil
+func (r *RabbitMQAdapter) GetOperationStats() []operations.OperationStats {
+	if r.rabbitMQOperations == nil {
+		return nil
+	}
+	return r.rabbitMQOperations.OperationStats()
+}
+
+// GetQueueDepthStats 获取队列深度采样统计，未采样时返回nil
+func (r *RabbitMQAdapter) GetQueueDepthStats() []operations.QueueDepthStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.depthSampler == nil {
+		return nil
+	}
+	return r.depthSampler.Stats()
+}
+
+// GetClientStats 获取连接、通道、退回与阻塞统计，未连接时返回nil
+func (r *RabbitMQAdapter) GetClientStats() *connection.ClientStats {
+	if r.client == nil {
+		return nil
+	}
+	stats := r.client.Stats(len(r.sessions))
+	return &stats
+}
+
+// ServerVersion 服务端版本，未连接时为空
+func (r *RabbitMQAdapter) ServerVersion() string {
+	if r.client == nil {
+		return ""
+	}
+	return r.client.ServerVersion()
+}
+
+// HealthCheck 健康检查：检查各连接是否仍然打开
+func (r *RabbitMQAdapter) HealthCheck(ctx context.Context) error {
+	if !r.isConnected {
+		return fmt.Errorf("adapter not connected")
+	}
+
+	if err := r.client.Ping(); err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	return nil
+}
+
+// GetProtocolName 获取协议名称
+func (r *RabbitMQAdapter) GetProtocolName() string {
+	return "rabbitmq"
+}
+
+// GetMetricsCollector 获取指标收集器
+func (r *RabbitMQAdapter) GetMetricsCollector() interfaces.DefaultMetricsCollector {
+	return r.metricsCollector
+}
+
+// 确保连接层实现了执行器使用的接口
+var _ operations.Session = (*connection.Session)(nil)
+var _ operations.QueueInspector = (*connection.Client)(nil)
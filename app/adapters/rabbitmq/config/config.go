@@ -0,0 +1,416 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/utils"
+)
+
+// 操作类型，同时也是测试用例名
+const (
+	OperationPublish = "publish" // 向交换机发布消息
+	OperationConsume = "consume" // 从队列接收一条消息并按确认模式确认
+)
+
+// 消费者确认模式
+const (
+	AckModeAuto   = "auto"   // 服务端投递即视为确认（no-ack）
+	AckModeManual = "manual" // 每条消息单独确认
+	AckModeBatch  = "batch"  // 每ack_batch条消息以multiple方式确认一次
+)
+
+// 默认值
+const (
+	DefaultExchange = "abc_bench"
+	DefaultQueue    = "abc_bench"
+)
+
+// RabbitMQConfig RabbitMQ(AMQP 0.9.1)协议配置
+type RabbitMQConfig struct {
+	Protocol         string                 `yaml:"protocol" json:"protocol"`
+	Connection       ConnectionConfig       `yaml:"connection" json:"connection"`
+	BenchMark        BenchmarkConfig        `yaml:"benchmark" json:"benchmark"`
+	RabbitMQSpecific RabbitMQSpecificConfig `yaml:"rabbitmq_specific" json:"rabbitmq_specific"`
+}
+
+// ConnectionConfig RabbitMQ连接配置
+type ConnectionConfig struct {
+	URI         string        `yaml:"uri" json:"uri"` // amqp://或amqps://连接串，设置时忽略address、port、用户名与vhost
+	Address     string        `yaml:"address" json:"address"`
+	Port        int           `yaml:"port" json:"port"`
+	Username    string        `yaml:"username" json:"username"`
+	Password    string        `yaml:"password" json:"password"`
+	VHost       string        `yaml:"vhost" json:"vhost"`
+	TLS         bool          `yaml:"tls" json:"tls"`
+	Connections int           `yaml:"connections" json:"connections"` // TCP连接数，各工作协程的通道均匀分布在这些连接上
+	Heartbeat   time.Duration `yaml:"heartbeat" json:"heartbeat"`
+	Timeout     time.Duration `yaml:"timeout" json:"timeout"` // 建连与声明的超时
+}
+
+// BenchmarkConfig RabbitMQ基准测试配置
+type BenchmarkConfig struct {
+	Total     int           `yaml:"total" json:"total"`
+	Parallels int           `yaml:"parallels" json:"parallels"`
+	TestCase  string        `yaml:"test_case" json:"test_case"` // publish或consume
+	Duration  time.Duration `yaml:"duration" json:"duration"`
+}
+
+// RabbitMQSpecificConfig RabbitMQ特定配置
+type RabbitMQSpecificConfig struct {
+	Exchange ExchangeConfig `yaml:"exchange" json:"exchange"`
+	Queue    QueueConfig    `yaml:"queue" json:"queue"`
+	// RoutingKey 发布与绑定使用的路由键，为空时每个队列以队列名绑定，发布按操作编号轮询各队列
+	RoutingKey string `yaml:"routing_key" json:"routing_key"`
+
+	Publisher PublisherConfig `yaml:"publisher" json:"publisher"`
+	Consumer  ConsumerConfig  `yaml:"consumer" json:"consumer"`
+
+	// DepthSampleInterval 运行期间采样各队列深度的间隔，0表示不采样
+	DepthSampleInterval time.Duration `yaml:"depth_sample_interval" json:"depth_sample_interval"`
+}
+
+// ExchangeConfig 交换机配置，名称为空时使用默认交换机（按队列名路由）
+type ExchangeConfig struct {
+	Name       string `yaml:"name" json:"name"`
+	Type       string `yaml:"type" json:"type"` // direct、fanout、topic或headers
+	Durable    bool   `yaml:"durable" json:"durable"`
+	AutoDelete bool   `yaml:"auto_delete" json:"auto_delete"`
+	Declare    bool   `yaml:"declare" json:"declare"` // 运行前声明交换机，否则要求已存在
+}
+
+// QueueConfig 队列配置
+type QueueConfig struct {
+	Names      []string `yaml:"names" json:"names"` // 测试队列，consume的工作协程按编号轮流从各队列接收
+	Type       string   `yaml:"type" json:"type"`   // classic或quorum，对应x-queue-type参数；为空时使用服务端默认值
+	Durable    bool     `yaml:"durable" json:"durable"`
+	AutoDelete bool     `yaml:"auto_delete" json:"auto_delete"`
+	MaxLength  int      `yaml:"max_length" json:"max_length"` // x-max-length参数，0表示不限制
+	Declare    bool     `yaml:"declare" json:"declare"`       // 运行前声明队列并绑定到交换机，否则要求已存在
+	Purge      bool     `yaml:"purge" json:"purge"`           // 运行前清空队列（在预填充之前）
+}
+
+// PublisherConfig 发布者配置
+type PublisherConfig struct {
+	// Confirms 开启发布确认，每次发布等待服务端确认，延迟包含确认时间；被拒绝（nack）的发布计为失败
+	Confirms bool `yaml:"confirms" json:"confirms"`
+	// Mandatory 要求消息至少路由到一个队列，无法路由的消息由服务端退回并计数
+	Mandatory  bool `yaml:"mandatory" json:"mandatory"`
+	Persistent bool `yaml:"persistent" json:"persistent"` // 以持久化投递模式发布
+	// MessageSize 未配置负载模板时随机消息体的字节数
+	MessageSize int                         `yaml:"message_size" json:"message_size"`
+	Payload     utils.PayloadTemplateConfig `yaml:"payload" json:"payload"` // 消息体模板，设置时忽略message_size
+}
+
+// ConsumerConfig 消费者配置
+type ConsumerConfig struct {
+	AckMode  string `yaml:"ack_mode" json:"ack_mode"`   // auto、manual或batch
+	AckBatch int    `yaml:"ack_batch" json:"ack_batch"` // batch模式下每次确认的消息数
+	Prefetch int    `yaml:"prefetch" json:"prefetch"`   // 每个通道未确认消息的上限（basic.qos），0表示不限制
+	// ReceiveTimeout 单次接收等待消息的最长时间，超时计为失败
+	ReceiveTimeout time.Duration `yaml:"receive_timeout" json:"receive_timeout"`
+}
+
+// NewDefaultRabbitMQConfig 创建默认RabbitMQ配置
+func NewDefaultRabbitMQConfig() *RabbitMQConfig {
+	return &RabbitMQConfig{
+		Protocol: "rabbitmq",
+		Connection: ConnectionConfig{
+			Address:     "localhost",
+			Port:        5672,
+			Username:    "guest",
+			Password:    "guest",
+			VHost:       "/",
+			Connections: 1,
+			Heartbeat:   10 * time.Second,
+			Timeout:     5 * time.Second,
+		},
+		BenchMark: BenchmarkConfig{
+			Total:     1000,
+			Parallels: 10,
+			TestCase:  OperationPublish,
+		},
+		RabbitMQSpecific: RabbitMQSpecificConfig{
+			Exchange: ExchangeConfig{
+				Name:    DefaultExchange,
+				Type:    "direct",
+				Declare: true,
+			},
+			Queue: QueueConfig{
+				Names:   []string{DefaultQueue},
+				Declare: true,
+			},
+			Publisher: PublisherConfig{
+				Confirms:    true,
+				MessageSize: 1024,
+			},
+			Consumer: ConsumerConfig{
+				AckMode:        AckModeManual,
+				AckBatch:       10,
+				Prefetch:       100,
+				ReceiveTimeout: 5 * time.Second,
+			},
+		},
+	}
+}
+
+// GetRoutingKey 获取发布到第index个队列使用的路由键
+func (c *RabbitMQConfig) GetRoutingKey(index int) string {
+	specific := c.RabbitMQSpecific
+	if specific.RoutingKey != "" {
+		return specific.RoutingKey
+	}
+	queues := specific.Queue.Names
+	return queues[index%len(queues)]
+}
+
+// GetQueueArgs 获取声明队列的参数
+func (c *RabbitMQConfig) GetQueueArgs() map[string]interface{} {
+	queue := c.RabbitMQSpecific.Queue
+	args := make(map[string]interface{})
+	if queue.Type != "" {
+		args["x-queue-type"] = queue.Type
+	}
+	if queue.MaxLength > 0 {
+		args["x-max-length"] = int64(queue.MaxLength)
+	}
+	return args
+}
+
+// GetProtocol 实现Config接口
+func (c *RabbitMQConfig) GetProtocol() string {
+	return c.Protocol
+}
+
+// GetConnection 实现Config接口
+func (c *RabbitMQConfig) GetConnection() interfaces.ConnectionConfig {
+	return &c.Connection
+}
+
+// GetBenchmark 实现Config接口
+func (c *RabbitMQConfig) GetBenchmark() interfaces.BenchmarkConfig {
+	return &c.BenchMark
+}
+
+// Validate 实现Config接口
+func (c *RabbitMQConfig) Validate() error {
+	if c.Connection.URI == "" {
+		if c.Connection.Address == "" {
+			return fmt.Errorf("connection address cannot be empty")
+		}
+		if c.Connection.Port <= 0 || c.Connection.Port > 65535 {
+			return fmt.Errorf("invalid port number: %d", c.Connection.Port)
+		}
+	} else if !strings.HasPrefix(c.Connection.URI, "amqp://") && !strings.HasPrefix(c.Connection.URI, "amqps://") {
+		return fmt.Errorf("invalid uri: must start with amqp:// or amqps://")
+	}
+	if c.Connection.Connections <= 0 {
+		return fmt.Errorf("connections must be greater than 0")
+	}
+	if c.Connection.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+	if c.Connection.Heartbeat < 0 {
+		return fmt.Errorf("heartbeat cannot be negative")
+	}
+
+	if c.BenchMark.Total <= 0 {
+		return fmt.Errorf("total operations must be greater than 0")
+	}
+	if c.BenchMark.Parallels <= 0 {
+		return fmt.Errorf("parallel connections must be greater than 0")
+	}
+	switch c.BenchMark.TestCase {
+	case OperationPublish, OperationConsume:
+	default:
+		return fmt.Errorf("invalid test case: %s, valid options: %s, %s",
+			c.BenchMark.TestCase, OperationPublish, OperationConsume)
+	}
+
+	specific := c.RabbitMQSpecific
+	switch specific.Exchange.Type {
+	case "direct", "fanout", "topic", "headers":
+	default:
+		return fmt.Errorf("invalid exchange type: %s, valid options: direct, fanout, topic, headers", specific.Exchange.Type)
+	}
+	if specific.Exchange.Name == "" && specific.RoutingKey != "" {
+		return fmt.Errorf("routing key requires a named exchange (the default exchange routes by queue name)")
+	}
+	if specific.Exchange.Name == "" && specific.Exchange.Declare {
+		return fmt.Errorf("the default exchange cannot be declared, set exchange.declare to false")
+	}
+
+	queue := specific.Queue
+	if len(queue.Names) == 0 {
+		return fmt.Errorf("at least one queue is required")
+	}
+	seen := make(map[string]bool, len(queue.Names))
+	for _, name := range queue.Names {
+		if name == "" {
+			return fmt.Errorf("queue name cannot be empty")
+		}
+		if seen[name] {
+			return fmt.Errorf("duplicate queue: %s", name)
+		}
+		seen[name] = true
+	}
+	switch queue.Type {
+	case "", "classic", "quorum":
+	default:
+		return fmt.Errorf("invalid queue type: %s, valid options: classic, quorum", queue.Type)
+	}
+	if queue.Type == "quorum" && !queue.Durable {
+		return fmt.Errorf("quorum queues must be durable")
+	}
+	if queue.MaxLength < 0 {
+		return fmt.Errorf("queue max length cannot be negative")
+	}
+
+	publisher := specific.Publisher
+	if publisher.Payload.Enabled() {
+		if err := publisher.Payload.Validate(); err != nil {
+			return fmt.Errorf("invalid payload template: %w", err)
+		}
+	} else if publisher.MessageSize < 0 {
+		return fmt.Errorf("message size cannot be negative")
+	}
+
+	consumer := specific.Consumer
+	switch consumer.AckMode {
+	case AckModeAuto, AckModeManual:
+	case AckModeBatch:
+		if consumer.AckBatch <= 0 {
+			return fmt.Errorf("ack batch must be greater than 0 in batch ack mode")
+		}
+		if consumer.Prefetch > 0 && consumer.AckBatch > consumer.Prefetch {
+			return fmt.Errorf("ack batch %d exceeds prefetch %d, consumers would stall waiting for unacknowledged messages",
+				consumer.AckBatch, consumer.Prefetch)
+		}
+	default:
+		return fmt.Errorf("invalid ack mode: %s, valid options: %s, %s, %s",
+			consumer.AckMode, AckModeAuto, AckModeManual, AckModeBatch)
+	}
+	if consumer.Prefetch < 0 || consumer.Prefetch > 65535 {
+		return fmt.Errorf("prefetch must be between 0 and 65535")
+	}
+	if consumer.ReceiveTimeout <= 0 {
+		return fmt.Errorf("receive timeout must be positive")
+	}
+	if specific.DepthSampleInterval < 0 {
+		return fmt.Errorf("depth sample interval cannot be negative")
+	}
+
+	return nil
+}
+
+// Clone 实现Config接口
+func (c *RabbitMQConfig) Clone() interfaces.Config {
+	clone := *c
+	clone.RabbitMQSpecific.Queue.Names = append([]string(nil), c.RabbitMQSpecific.Queue.Names...)
+	return &clone
+}
+
+// ConnectionConfig接口实现
+
+// GetAddresses 实现ConnectionConfig接口，连接串中的密码被隐去
+func (c *ConnectionConfig) GetAddresses() []string {
+	if c.URI != "" {
+		if u, err := url.Parse(c.URI); err == nil && u.User != nil {
+			u.User = url.User(u.User.Username())
+			return []string{u.String()}
+		}
+		return []string{c.URI}
+	}
+	return []string{net.JoinHostPort(c.Address, strconv.Itoa(c.Port))}
+}
+
+// GetCredentials 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetCredentials() map[string]string {
+	return map[string]string{
+		"username": c.Username,
+		"password": c.Password,
+		"vhost":    c.VHost,
+	}
+}
+
+// GetPoolConfig 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetPoolConfig() interfaces.PoolConfig {
+	return &PoolConfig{size: c.Connections, timeout: c.Timeout}
+}
+
+// GetTimeout 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetTimeout() time.Duration {
+	return c.Timeout
+}
+
+// PoolConfig 连接池配置（TCP连接数）
+type PoolConfig struct {
+	size    int
+	timeout time.Duration
+}
+
+func (p *PoolConfig) GetPoolSize() int                    { return p.size }
+func (p *PoolConfig) GetMinIdle() int                     { return p.size }
+func (p *PoolConfig) GetMaxIdle() int                     { return p.size }
+func (p *PoolConfig) GetIdleTimeout() time.Duration       { return 0 }
+func (p *PoolConfig) GetConnectionTimeout() time.Duration { return p.timeout }
+
+// BenchmarkConfig接口实现
+
+// GetTotal 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTotal() int {
+	return b.Total
+}
+
+// GetParallels 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetParallels() int {
+	return b.Parallels
+}
+
+// GetDataSize 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetDataSize() int {
+	return 0
+}
+
+// GetTTL 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTTL() time.Duration {
+	return 0
+}
+
+// GetReadPercent 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetReadPercent() int {
+	if b.TestCase == OperationConsume {
+		return 100
+	}
+	return 0
+}
+
+// GetRandomKeys 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetRandomKeys() int {
+	return 0
+}
+
+// GetTestCase 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTestCase() string {
+	return b.TestCase
+}
+
+// GetDuration 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetDuration() time.Duration {
+	return b.Duration
+}
+
+// GetTimeout 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTimeout() time.Duration {
+	return 0
+}
+
+// GetRampUp 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetRampUp() time.Duration {
+	return 0
+}
@@ -0,0 +1,40 @@
+package config
+
+import "testing"
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*RabbitMQConfig)
+		valid  bool
+	}{
+		{"default", func(c *RabbitMQConfig) {}, true},
+		{"consume batch", func(c *RabbitMQConfig) {
+			c.BenchMark.TestCase = OperationConsume
+			c.RabbitMQSpecific.Consumer.AckMode = AckModeBatch
+		}, true},
+		{"invalid test case", func(c *RabbitMQConfig) { c.BenchMark.TestCase = "mixed" }, false},
+		{"invalid ack mode", func(c *RabbitMQConfig) { c.RabbitMQSpecific.Consumer.AckMode = "never" }, false},
+		{"ack batch over prefetch", func(c *RabbitMQConfig) {
+			c.RabbitMQSpecific.Consumer.AckMode = AckModeBatch
+			c.RabbitMQSpecific.Consumer.AckBatch = 500
+		}, false},
+		{"non-durable quorum", func(c *RabbitMQConfig) { c.RabbitMQSpecific.Queue.Type = "quorum" }, false},
+		{"duplicate queue", func(c *RabbitMQConfig) { c.RabbitMQSpecific.Queue.Names = []string{"a", "a"} }, false},
+		{"routing key on default exchange", func(c *RabbitMQConfig) {
+			c.RabbitMQSpecific.Exchange.Name = ""
+			c.RabbitMQSpecific.Exchange.Declare = false
+			c.RabbitMQSpecific.RoutingKey = "k"
+		}, false},
+		{"invalid uri", func(c *RabbitMQConfig) { c.Connection.URI = "http://mq" }, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := NewDefaultRabbitMQConfig()
+			test.modify(cfg)
+			if err := cfg.Validate(); (err == nil) != test.valid {
+				t.Errorf("Validate() error = %v, want valid %v", err, test.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package connection
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"abc-runner/app/adapters/rabbitmq/config"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Client RabbitMQ客户端，持有若干TCP连接并在其上为各工作协程分配通道
+type Client struct {
+	config      *config.RabbitMQConfig
+	connections []*amqp.Connection
+
+	// inspector 查询队列深度的专用通道，被动声明不存在的队列会关闭通道，因此按需重新打开
+	inspectorMutex sync.Mutex
+	inspector      *amqp.Channel
+
+	returned atomic.Int64 // 服务端退回的无法路由的消息数（mandatory）
+	blocked  atomic.Int64 // 服务端因资源告警阻塞发布的次数（connection.blocked）
+}
+
+// ClientStats 客户端统计
+type ClientStats struct {
+	Connections int   `json:"connections"`
+	Channels    int   `json:"channels"`
+	Returned    int64 `json:"returned"` // 无法路由而被退回的消息数
+	Blocked     int64 `json:"blocked"`  // 服务端阻塞发布的次数（内存或磁盘告警）
+}
+
+// NewURI 按配置构造连接串，未配置uri时由地址、端口、用户名与vhost拼接
+func NewURI(cfg *config.ConnectionConfig) string {
+	if cfg.URI != "" {
+		return cfg.URI
+	}
+	uri := amqp.URI{
+		Scheme:   "amqp",
+		Host:     cfg.Address,
+		Port:     cfg.Port,
+		Username: cfg.Username,
+		Password: cfg.Password,
+		Vhost:    cfg.VHost,
+	}
+	if cfg.TLS {
+		uri.Scheme = "amqps"
+	}
+	if uri.Vhost == "" {
+		uri.Vhost = "/"
+	}
+	return uri.String()
+}
+
+// NewClient 建立配置数量的TCP连接，建连与握手受timeout限制
+func NewClient(ctx context.Context, cfg *config.RabbitMQConfig) (*Client, error) {
+	client := &Client{config: cfg}
+	uri := NewURI(&cfg.Connection)
+
+	for i := 0; i < cfg.Connection.Connections; i++ {
+		if err := ctx.Err(); err != nil {
+			client.Close()
+			return nil, err
+		}
+		amqpConfig := amqp.Config{
+			Heartbeat:  cfg.Connection.Heartbeat,
+			Properties: amqp.Table{"connection_name": "abc-runner-" + strconv.Itoa(i)},
+			Dial:       amqp.DefaultDial(cfg.Connection.Timeout),
+		}
+		if cfg.Connection.TLS && cfg.Connection.URI == "" {
+			amqpConfig.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+
+		conn, err := amqp.DialConfig(uri, amqpConfig)
+		if err != nil {
+			client.Close()
+			return nil, err
+		}
+		blocked := conn.NotifyBlocked(make(chan amqp.Blocking, 1))
+		go func() {
+			for b := range blocked {
+				if b.Active {
+					client.blocked.Add(1)
+				}
+			}
+		}()
+		client.connections = append(client.connections, conn)
+	}
+	return client, nil
+}
+
+// Declare 按配置声明交换机与队列并绑定，配置了purge时清空队列
+func (c *Client) Declare() error {
+	ch, err := c.connections[0].Channel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	specific := c.config.RabbitMQSpecific
+	exchange := specific.Exchange
+	if exchange.Declare {
+		if err := ch.ExchangeDeclare(exchange.Name, exchange.Type, exchange.Durable, exchange.AutoDelete, false, false, nil); err != nil {
+			return fmt.Errorf("failed to declare exchange %s: %w", exchange.Name, err)
+		}
+	}
+
+	queue := specific.Queue
+	for i, name := range queue.Names {
+		if queue.Declare {
+			if _, err := ch.QueueDeclare(name, queue.Durable, queue.AutoDelete, false, false, c.config.GetQueueArgs()); err != nil {
+				return fmt.Errorf("failed to declare queue %s: %w", name, err)
+			}
+			if exchange.Name != "" {
+				if err := ch.QueueBind(name, c.config.GetRoutingKey(i), exchange.Name, false, nil); err != nil {
+					return fmt.Errorf("failed to bind queue %s to exchange %s: %w", name, exchange.Name, err)
+				}
+			}
+		}
+		if queue.Purge {
+			if _, err := ch.QueuePurge(name, false); err != nil {
+				return fmt.Errorf("failed to purge queue %s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// NewSession 为第index个工作协程打开通道，通道轮流分布在各连接上
+// 开启发布确认时将通道置为确认模式；consume用例设置预取并从第index个队列（取模）开始消费
+func (c *Client) NewSession(index int) (*Session, error) {
+	ch, err := c.connections[index%len(c.connections)].Channel()
+	if err != nil {
+		return nil, err
+	}
+
+	specific := c.config.RabbitMQSpecific
+	session := &Session{channel: ch, confirms: specific.Publisher.Confirms}
+	if session.confirms {
+		if err := ch.Confirm(false); err != nil {
+			ch.Close()
+			return nil, fmt.Errorf("failed to enable publisher confirms: %w", err)
+		}
+	}
+	if specific.Publisher.Mandatory {
+		returns := ch.NotifyReturn(make(chan amqp.Return, 16))
+		go func() {
+			for range returns {
+				c.returned.Add(1)
+			}
+		}()
+	}
+
+	if c.config.BenchMark.TestCase == config.OperationConsume {
+		consumer := specific.Consumer
+		if err := ch.Qos(consumer.Prefetch, 0, false); err != nil {
+			ch.Close()
+			return nil, fmt.Errorf("failed to set prefetch: %w", err)
+		}
+		queues := specific.Queue.Names
+		queue := queues[index%len(queues)]
+		deliveries, err := ch.Consume(queue, "abc-runner-"+strconv.Itoa(index), consumer.AckMode == config.AckModeAuto, false, false, false, nil)
+		if err != nil {
+			ch.Close()
+			return nil, fmt.Errorf("failed to consume from queue %s: %w", queue, err)
+		}
+		session.deliveries = deliveries
+	}
+	return session, nil
+}
+
+// InspectQueue 被动声明队列，获取就绪消息数与消费者数
+func (c *Client) InspectQueue(name string) (int, int, error) {
+	c.inspectorMutex.Lock()
+	defer c.inspectorMutex.Unlock()
+
+	if c.inspector == nil || c.inspector.IsClosed() {
+		ch, err := c.connections[0].Channel()
+		if err != nil {
+			return 0, 0, err
+		}
+		c.inspector = ch
+	}
+	queue, err := c.inspector.QueueDeclarePassive(name, false, false, false, false, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	return queue.Messages, queue.Consumers, nil
+}
+
+// ServerVersion 服务端版本
+func (c *Client) ServerVersion() string {
+	if len(c.connections) == 0 {
+		return ""
+	}
+	version, _ := c.connections[0].Properties["version"].(string)
+	return version
+}
+
+// Ping 检查各连接是否仍然打开
+func (c *Client) Ping() error {
+	for i, conn := range c.connections {
+		if conn.IsClosed() {
+			return fmt.Errorf("connection %d is closed", i)
+		}
+	}
+	return nil
+}
+
+// Stats 获取客户端统计，channels为各连接上打开的通道总数
+func (c *Client) Stats(channels int) ClientStats {
+	return ClientStats{
+		Connections: len(c.connections),
+		Channels:    channels,
+		Returned:    c.returned.Load(),
+		Blocked:     c.blocked.Load(),
+	}
+}
+
+// Close 关闭全部连接，连接上的通道随之关闭
+func (c *Client) Close() error {
+	var firstErr error
+	for _, conn := range c.connections {
+		if err := conn.Close(); err != nil && firstErr == nil && err != amqp.ErrClosed {
+			firstErr = err
+		}
+	}
+	c.connections = nil
+	return firstErr
+}
+
+// Session 单个工作协程使用的通道
+type Session struct {
+	channel    *amqp.Channel
+	confirms   bool
+	deliveries <-chan amqp.Delivery
+}
+
+// Publish 发布消息；开启发布确认时等待服务端确认，返回消息是否被确认（未开启时始终为true）
+func (s *Session) Publish(ctx context.Context, exchange, key string, mandatory bool, msg amqp.Publishing) (bool, error) {
+	if !s.confirms {
+		return true, s.channel.PublishWithContext(ctx, exchange, key, mandatory, false, msg)
+	}
+	confirmation, err := s.channel.PublishWithDeferredConfirmWithContext(ctx, exchange, key, mandatory, false, msg)
+	if err != nil {
+		return false, err
+	}
+	return confirmation.WaitContext(ctx)
+}
+
+// Receive 等待下一条投递的消息，通道关闭时返回错误
+func (s *Session) Receive(ctx context.Context) (amqp.Delivery, error) {
+	if s.deliveries == nil {
+		return amqp.Delivery{}, fmt.Errorf("session is not consuming")
+	}
+	select {
+	case delivery, ok := <-s.deliveries:
+		if !ok {
+			return amqp.Delivery{}, amqp.ErrClosed
+		}
+		return delivery, nil
+	case <-ctx.Done():
+		return amqp.Delivery{}, ctx.Err()
+	}
+}
+
+// Close 关闭通道，未确认的消息由服务端重新入队
+func (s *Session) Close() error {
+	return s.channel.Close()
+}
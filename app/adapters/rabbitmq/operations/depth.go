@@ -0,0 +1,134 @@
+package operations
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// QueueInspector 查询队列就绪消息数与消费者数，由*connection.Client实现
+type QueueInspector interface {
+	InspectQueue(name string) (int, int, error)
+}
+
+// QueueDepthStats 单个队列在运行期间的深度采样统计
+type QueueDepthStats struct {
+	Queue     string  `json:"queue"`
+	Samples   int     `json:"samples"`
+	Errors    int     `json:"errors,omitempty"` // 采样失败次数
+	First     int     `json:"first"`            // 首次采样的就绪消息数
+	Last      int     `json:"last"`             // 最后一次采样的就绪消息数
+	Min       int     `json:"min"`
+	Max       int     `json:"max"`
+	Average   float64 `json:"average"`
+	Growth    float64 `json:"growth_per_sec"` // 首末两次采样间的平均增长速度，持续为正说明消费跟不上发布
+	Consumers int     `json:"consumers"`      // 最后一次采样的消费者数
+	LastError string  `json:"last_error,omitempty"`
+}
+
+// queueDepth 单个队列的采样累计
+type queueDepth struct {
+	stats   QueueDepthStats
+	sum     int64
+	firstAt time.Time
+	lastAt  time.Time
+}
+
+// DepthSampler 运行期间按固定间隔采样各队列深度
+type DepthSampler struct {
+	inspector QueueInspector
+	interval  time.Duration
+	mutex     sync.Mutex
+	queues    []*queueDepth
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+// NewDepthSampler 创建队列深度采样器
+func NewDepthSampler(inspector QueueInspector, queues []string, interval time.Duration) *DepthSampler {
+	sampler := &DepthSampler{inspector: inspector, interval: interval}
+	for _, name := range queues {
+		sampler.queues = append(sampler.queues, &queueDepth{stats: QueueDepthStats{Queue: name}})
+	}
+	return sampler
+}
+
+// Start 立即采样一次，之后每个间隔采样一次，直到Stop或ctx结束
+func (s *DepthSampler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.done = make(chan struct{})
+	s.sample()
+
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.sample()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop 停止采样并做最后一次采样，返回各队列的统计
+func (s *DepthSampler) Stop() []QueueDepthStats {
+	if s.cancel != nil {
+		s.cancel()
+		<-s.done
+		s.cancel = nil
+		s.sample()
+	}
+	return s.Stats()
+}
+
+// sample 采样各队列一次
+func (s *DepthSampler) sample() {
+	for _, queue := range s.queues {
+		messages, consumers, err := s.inspector.InspectQueue(queue.stats.Queue)
+		now := time.Now()
+
+		s.mutex.Lock()
+		stats := &queue.stats
+		if err != nil {
+			stats.Errors++
+			stats.LastError = err.Error()
+			s.mutex.Unlock()
+			continue
+		}
+		if stats.Samples == 0 {
+			stats.First, stats.Min, stats.Max = messages, messages, messages
+			queue.firstAt = now
+		}
+		stats.Samples++
+		stats.Last = messages
+		stats.Consumers = consumers
+		stats.Min = min(stats.Min, messages)
+		stats.Max = max(stats.Max, messages)
+		queue.sum += int64(messages)
+		queue.lastAt = now
+		s.mutex.Unlock()
+	}
+}
+
+// Stats 获取各队列的采样统计，顺序与配置的队列一致
+func (s *DepthSampler) Stats() []QueueDepthStats {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stats := make([]QueueDepthStats, 0, len(s.queues))
+	for _, queue := range s.queues {
+		entry := queue.stats
+		if entry.Samples > 0 {
+			entry.Average = float64(queue.sum) / float64(entry.Samples)
+		}
+		if elapsed := queue.lastAt.Sub(queue.firstAt).Seconds(); entry.Samples > 1 && elapsed > 0 {
+			entry.Growth = float64(entry.Last-entry.First) / elapsed
+		}
+		stats = append(stats, entry)
+	}
+	return stats
+}
@@ -0,0 +1,198 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"abc-runner/app/adapters/rabbitmq/config"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Session 单个工作协程使用的通道，由*connection.Session实现
+type Session interface {
+	// Publish 发布消息，返回消息是否被服务端确认（未开启发布确认时始终为true）
+	Publish(ctx context.Context, exchange, key string, mandatory bool, msg amqp.Publishing) (bool, error)
+	// Receive 等待下一条投递的消息
+	Receive(ctx context.Context) (amqp.Delivery, error)
+}
+
+// worker 通道及其尚未确认的消息（batch确认模式）
+type worker struct {
+	session Session
+	unacked int
+	last    amqp.Delivery // 最近一条未确认的消息，以multiple方式确认时覆盖此前全部消息
+}
+
+// RabbitExecutor RabbitMQ操作执行器
+// 每个操作独占一个通道，通道数与并发数相同，使确认模式与预取按通道生效
+type RabbitExecutor struct {
+	workers      chan *worker
+	exchange     string
+	mandatory    bool
+	deliveryMode uint8
+	ackMode      string
+	ackBatch     int
+	timeout      time.Duration
+	tracker      *metrics.OperationTypeTracker
+}
+
+// NewRabbitExecutor 创建RabbitMQ操作执行器
+func NewRabbitExecutor(sessions []Session, cfg *config.RabbitMQConfig) *RabbitExecutor {
+	specific := cfg.RabbitMQSpecific
+	executor := &RabbitExecutor{
+		workers:      make(chan *worker, len(sessions)),
+		exchange:     specific.Exchange.Name,
+		mandatory:    specific.Publisher.Mandatory,
+		deliveryMode: amqp.Transient,
+		ackMode:      specific.Consumer.AckMode,
+		ackBatch:     specific.Consumer.AckBatch,
+		timeout:      specific.Consumer.ReceiveTimeout,
+		tracker:      newOperationTracker(),
+	}
+	if specific.Publisher.Persistent {
+		executor.deliveryMode = amqp.Persistent
+	}
+	for _, session := range sessions {
+		executor.workers <- &worker{session: session}
+	}
+	return executor
+}
+
+// ExecuteOperation 执行RabbitMQ操作
+// 预填充的发布不计入按操作类型的统计
+func (e *RabbitExecutor) ExecuteOperation(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	var w *worker
+	select {
+	case w = <-e.workers:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { e.workers <- w }()
+
+	var bytes int64
+	var redelivered bool
+	var err error
+	startTime := time.Now()
+	switch operation.Type {
+	case config.OperationPublish:
+		bytes, err = e.publish(ctx, w, operation)
+	case config.OperationConsume:
+		bytes, redelivered, err = e.consume(ctx, w)
+	default:
+		return nil, fmt.Errorf("unsupported operation type: %s", operation.Type)
+	}
+	duration := time.Since(startTime)
+
+	if prefill, _ := operation.Params["prefill"].(bool); !prefill {
+		e.tracker.Record(operation.Type, bytes, redelivered, duration, err)
+	}
+	if err != nil {
+		err = fmt.Errorf("%s failed: %w", operation.Type, err)
+	}
+
+	result := &interfaces.OperationResult{
+		Success:  err == nil,
+		Duration: duration,
+		IsRead:   operation.Type == config.OperationConsume,
+		Error:    err,
+		Value:    bytes,
+		Metadata: map[string]interface{}{
+			"protocol":       "rabbitmq",
+			"operation_type": operation.Type,
+			"bytes":          bytes,
+		},
+	}
+	if operation.Type == config.OperationPublish {
+		result.BytesWritten = bytes
+		result.Metadata["routing_key"] = operation.Key
+	} else {
+		result.BytesRead = bytes
+	}
+	for k, v := range operation.Metadata {
+		result.Metadata[k] = v
+	}
+	return result, err
+}
+
+// publish 发布一条消息，开启发布确认时包含等待确认的时间
+func (e *RabbitExecutor) publish(ctx context.Context, w *worker, operation interfaces.Operation) (int64, error) {
+	body, ok := operation.Value.([]byte)
+	if !ok {
+		return 0, fmt.Errorf("missing message body")
+	}
+	msg := amqp.Publishing{
+		ContentType:  "application/octet-stream",
+		DeliveryMode: e.deliveryMode,
+		Timestamp:    time.Now(),
+		Body:         body,
+	}
+	acked, err := w.session.Publish(ctx, e.exchange, operation.Key, e.mandatory, msg)
+	if err != nil {
+		return 0, err
+	}
+	if !acked {
+		return 0, ErrNacked
+	}
+	return int64(len(body)), nil
+}
+
+// consume 接收一条消息并按确认模式确认，等待超过receive_timeout时失败
+func (e *RabbitExecutor) consume(ctx context.Context, w *worker) (int64, bool, error) {
+	receiveCtx, cancel := context.WithTimeout(ctx, e.timeout)
+	delivery, err := w.session.Receive(receiveCtx)
+	cancel()
+	if err != nil {
+		return 0, false, err
+	}
+
+	switch e.ackMode {
+	case config.AckModeManual:
+		err = delivery.Ack(false)
+	case config.AckModeBatch:
+		w.unacked++
+		w.last = delivery
+		if w.unacked >= e.ackBatch {
+			err = e.flush(w)
+		}
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return int64(len(delivery.Body)), delivery.Redelivered, nil
+}
+
+// flush 以multiple方式确认通道上尚未确认的消息
+func (e *RabbitExecutor) flush(w *worker) error {
+	if w.unacked == 0 {
+		return nil
+	}
+	w.unacked = 0
+	return w.last.Ack(true)
+}
+
+// Flush 确认batch模式下各通道尚未确认的消息，应在关闭通道前调用，否则这些消息会重新入队
+func (e *RabbitExecutor) Flush() error {
+	// 取出全部通道，等待进行中的操作完成
+	workers := make([]*worker, 0, cap(e.workers))
+	var firstErr error
+	for len(workers) < cap(e.workers) {
+		w := <-e.workers
+		workers = append(workers, w)
+		if err := e.flush(w); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	for _, w := range workers {
+		e.workers <- w
+	}
+	return firstErr
+}
+
+// OperationStats 获取按操作类型的统计
+func (e *RabbitExecutor) OperationStats() []OperationStats {
+	return e.tracker.Stats()
+}
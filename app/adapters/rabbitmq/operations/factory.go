@@ -0,0 +1,96 @@
+package operations
+
+import (
+	"math/rand/v2"
+
+	"abc-runner/app/adapters/rabbitmq/config"
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/utils"
+)
+
+// OperationFactory RabbitMQ操作工厂
+type OperationFactory struct {
+	config  *config.RabbitMQConfig
+	payload *utils.PayloadTemplate // 消息体模板，未配置时使用message_size字节的随机消息体
+	body    []byte
+}
+
+// NewOperationFactory 创建RabbitMQ操作工厂
+func NewOperationFactory(cfg *config.RabbitMQConfig) *OperationFactory {
+	factory := &OperationFactory{config: cfg}
+	publisher := cfg.RabbitMQSpecific.Publisher
+	// 模板在解析参数时已验证
+	if publisher.Payload.Enabled() {
+		if payload, err := publisher.Payload.Compile(); err == nil {
+			factory.payload = payload
+		}
+	}
+	if factory.payload == nil {
+		factory.body = randomBody(publisher.MessageSize)
+	}
+	return factory
+}
+
+// CreateOperation 创建操作：publish按操作编号轮询各队列的路由键，consume由工作协程的通道接收
+func (f *OperationFactory) CreateOperation(jobID int, cfg execution.BenchmarkConfig) interfaces.Operation {
+	if f.config.BenchMark.TestCase == config.OperationConsume {
+		return interfaces.Operation{
+			Type: config.OperationConsume,
+			Params: map[string]interface{}{
+				"job_id": jobID,
+			},
+			Metadata: map[string]string{
+				"operation_type": config.OperationConsume,
+			},
+		}
+	}
+	return f.createPublish(jobID, false)
+}
+
+// CreatePrepareOperation 创建预填充操作：向测试队列发布一条消息，供consume用例接收
+func (f *OperationFactory) CreatePrepareOperation(index int) interfaces.Operation {
+	return f.createPublish(index, true)
+}
+
+// createPublish 创建发布操作，消息体按编号渲染
+func (f *OperationFactory) createPublish(jobID int, prefill bool) interfaces.Operation {
+	body := f.body
+	if f.payload != nil {
+		body = []byte(f.payload.Render(jobID))
+	}
+	return interfaces.Operation{
+		Type:  config.OperationPublish,
+		Key:   f.config.GetRoutingKey(jobID),
+		Value: body,
+		Params: map[string]interface{}{
+			"job_id":  jobID,
+			"prefill": prefill,
+		},
+		Metadata: map[string]string{
+			"operation_type": config.OperationPublish,
+		},
+	}
+}
+
+// GetOperationType 获取操作类型
+func (f *OperationFactory) GetOperationType() string {
+	return f.config.BenchMark.TestCase
+}
+
+// GetSupportedOperations 获取支持的操作类型
+func (f *OperationFactory) GetSupportedOperations() []string {
+	return []string{config.OperationPublish, config.OperationConsume}
+}
+
+// randomBody 生成size字节的随机可打印消息体，各次发布共用
+func randomBody(size int) []byte {
+	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	body := make([]byte, size)
+	for i := range body {
+		body[i] = letters[rand.IntN(len(letters))]
+	}
+	return body
+}
+
+var _ execution.PrepareFactory = (*OperationFactory)(nil)
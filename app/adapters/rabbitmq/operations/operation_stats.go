@@ -0,0 +1,41 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"abc-runner/app/core/metrics"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// ErrNacked 开启发布确认时服务端拒绝（basic.nack）了消息
+var ErrNacked = errors.New("message was nacked by the broker")
+
+// OperationStats 单个操作类型的统计，Volume为成功发布或接收的消息体字节数（JSON字段"bytes"），
+// Flagged为接收到的重新投递消息数（JSON字段"redelivered"）；
+// 错误码为AMQP回复码，另有"nack"、"timeout"、"closed"与"client"
+type OperationStats = metrics.OperationTypeStats
+
+// newOperationTracker 创建按操作类型的统计器
+func newOperationTracker() *metrics.OperationTypeTracker {
+	return metrics.NewOperationTypeTracker(errorCode, "bytes", "redelivered")
+}
+
+// errorCode 错误分类：服务端关闭通道或连接时取AMQP回复码，其余按拒绝、超时、关闭与客户端错误归类
+func errorCode(err error) string {
+	var amqpErr *amqp.Error
+	if errors.As(err, &amqpErr) && amqpErr.Code != 0 && amqpErr != amqp.ErrClosed {
+		return strconv.Itoa(amqpErr.Code)
+	}
+	switch {
+	case errors.Is(err, ErrNacked):
+		return "nack"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, amqp.ErrClosed):
+		return "closed"
+	}
+	return "client"
+}
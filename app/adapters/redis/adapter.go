@@ -10,6 +10,7 @@ import (
 	"abc-runner/app/adapters/redis/connection"
 	operation "abc-runner/app/adapters/redis/operations"
 	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
 
 	"github.com/go-redis/redis/v8"
 )
@@ -21,6 +22,16 @@ type RedisAdapter struct {
 	redisOperations *operation.RedisExecutor
 	client          redis.Cmdable
 	config          *redisConfig.RedisConfig
+	script          *operation.LuaScript          // 启用Lua脚本基准测试时已注册的脚本
+	cluster         *operation.ClusterTracker     // 集群模式下按节点与按槽的统计
+	verify          *operation.VerifyTracker      // 启用数据校验时的校验统计
+	failover        *operation.FailoverTracker    // 哨兵模式下启用故障转移跟踪时的统计
+	keyspace        *operation.KeyspaceTracker    // 启用键空间通知测试时的送达统计
+	leaderboard     *operation.LeaderboardTracker // 排行榜负载的分数更新与排名查询统计
+
+	// RESP3模式组件（可选client tracking客户端缓存）
+	resp3Pool     *connection.RESP3Pool
+	resp3Executor *operation.RESP3Executor
 
 	// 指标收集器
 	metricsCollector interfaces.DefaultMetricsCollector
@@ -65,9 +76,40 @@ func (r *RedisAdapter) Connect(ctx context.Context, config interfaces.Config) er
 	}
 
 	r.config = redisConfig
+	if redisConfig.BenchMark.Verify {
+		r.verify = operation.NewVerifyTracker()
+	}
+	if redisConfig.BenchMark.IsLeaderboard() {
+		r.leaderboard = operation.NewLeaderboardTracker(redisConfig.BenchMark.Leaderboard)
+	}
 
-	// 创建连接池
-	pool, err := connection.NewRedisConnectionPool(redisConfig)
+	// RESP3模式使用独立的连接池与执行器
+	if redisConfig.UseRESP3() {
+		return r.connectRESP3(ctx, redisConfig)
+	}
+
+	// 创建连接池，集群模式下为每个节点客户端挂载统计hook
+	var nodeHook func(addr string) redis.Hook
+	if redisConfig.GetMode() == "cluster" {
+		r.cluster = operation.NewClusterTracker()
+		nodeHook = r.cluster.NodeHook
+	}
+	var pool *connection.RedisConnectionPool
+	var err error
+	if redisConfig.BenchMark.Failover.Enabled() {
+		// 哨兵模式故障转移跟踪：订阅主节点切换，统计新建连接与命令结果
+		tlsConfig, err := connection.NewTLSConfig(redisConfig.TLS)
+		if err != nil {
+			return err
+		}
+		r.failover = operation.NewFailoverTracker(redisConfig.Sentinel, tlsConfig)
+		if err := r.failover.Connect(ctx, redisConfig.Pool.ConnectionTimeout); err != nil {
+			return err
+		}
+		pool, err = connection.NewRedisConnectionPoolWithConnectHook(redisConfig, r.failover.OnConnect)
+	} else {
+		pool, err = connection.NewRedisConnectionPoolWithNodeHook(redisConfig, nodeHook)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create Redis connection pool: %w", err)
 	}
@@ -76,6 +118,8 @@ func (r *RedisAdapter) Connect(ctx context.Context, config interfaces.Config) er
 
 	// 创建Redis操作执行器
 	r.redisOperations = operation.NewRedisExecutor(pool, redisConfig, r.metricsCollector)
+	r.redisOperations.SetVerifier(r.verify)
+	r.redisOperations.SetLeaderboard(r.leaderboard)
 
 	// 获取客户端
 	client := pool.GetClient()
@@ -84,12 +128,116 @@ func (r *RedisAdapter) Connect(ctx context.Context, config interfaces.Config) er
 	}
 
 	r.client = client
+	if r.failover != nil {
+		client.AddHook(r.failover)
+	}
+
+	// 键空间通知测试：订阅写入键的通知，写入命令在发出前登记等待
+	if redisConfig.BenchMark.Notifications.Enabled {
+		db := redisConfig.Standalone.Db
+		if redisConfig.GetMode() == "sentinel" {
+			db = redisConfig.Sentinel.Db
+		}
+		r.keyspace = operation.NewKeyspaceTracker(redisConfig.BenchMark.Notifications, db)
+		if err := r.keyspace.Connect(ctx, client); err != nil {
+			return err
+		}
+		client.AddHook(r.keyspace)
+	}
 
 	// 执行健康检查
 	if err := r.HealthCheck(ctx); err != nil {
 		return fmt.Errorf("initial health check failed: %w", err)
 	}
 
+	// 加载并注册Lua脚本
+	if redisConfig.Script.Enabled() {
+		script, err := operation.LoadLuaScript(redisConfig.Script.File, redisConfig.Script.UseEval)
+		if err != nil {
+			return err
+		}
+		if err := script.Register(ctx, client); err != nil {
+			return err
+		}
+		r.redisOperations.SetScript(script)
+		r.script = script
+	}
+
+	r.isConnected = true
+	return nil
+}
+
+// ForkConnection 为按核执行的引擎建立一个使用独立连接池的适配器，共享配置、脚本、校验与排行榜统计及指标收集器
+// RESP3、集群、故障转移与pipeline模式的统计挂在原适配器上，这些模式下不拆分连接
+func (r *RedisAdapter) ForkConnection(ctx context.Context) (interfaces.ProtocolAdapter, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if !r.isConnected || r.config == nil {
+		return nil, fmt.Errorf("redis adapter is not connected")
+	}
+	if r.resp3Executor != nil || r.cluster != nil || r.failover != nil || r.keyspace != nil || r.config.BenchMark.GetPipeline() > 1 {
+		return nil, fmt.Errorf("per-core connections are not supported in this Redis mode")
+	}
+
+	pool, err := connection.NewRedisConnectionPoolWithNodeHook(r.config, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Redis connection pool: %w", err)
+	}
+	client := pool.GetClient()
+	if client == nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to get Redis client from pool")
+	}
+
+	executor := operation.NewRedisExecutor(pool, r.config, r.metricsCollector)
+	executor.SetVerifier(r.verify)
+	executor.SetLeaderboard(r.leaderboard)
+	if r.script != nil {
+		executor.SetScript(r.script)
+	}
+
+	forked := &RedisAdapter{
+		connectionPool:   pool,
+		redisOperations:  executor,
+		client:           client,
+		config:           r.config,
+		script:           r.script,
+		verify:           r.verify,
+		leaderboard:      r.leaderboard,
+		metricsCollector: r.metricsCollector,
+		isConnected:      true,
+		startTime:        time.Now(),
+	}
+	if err := forked.HealthCheck(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("health check failed: %w", err)
+	}
+	return forked, nil
+}
+
+// connectRESP3 建立RESP3连接池，开启client tracking时创建本地缓存并订阅失效通知
+func (r *RedisAdapter) connectRESP3(ctx context.Context, cfg *redisConfig.RedisConfig) error {
+	var cache *operation.ClientCache
+	var onPush func(*connection.RESP3Push)
+	if cfg.Client.Tracking.Enabled {
+		cache = operation.NewClientCache(0, cfg.Client.Tracking.BCast)
+		onPush = cache.HandlePush
+	}
+
+	pool, err := connection.NewRESP3Pool(ctx, cfg, onPush)
+	if err != nil {
+		return fmt.Errorf("failed to create RESP3 connection pool: %w", err)
+	}
+
+	r.resp3Pool = pool
+	r.resp3Executor = operation.NewRESP3Executor(pool, cfg, cache)
+	r.resp3Executor.SetVerifier(r.verify)
+
+	if err := r.HealthCheck(ctx); err != nil {
+		return fmt.Errorf("initial health check failed: %w", err)
+	}
+
 	r.isConnected = true
 	return nil
 }
@@ -104,7 +252,13 @@ func (r *RedisAdapter) Execute(ctx context.Context, operation interfaces.Operati
 	r.incrementTotalOperations()
 
 	// 执行操作
-	result, err := r.redisOperations.ExecuteOperation(ctx, operation)
+	var result *interfaces.OperationResult
+	var err error
+	if r.resp3Executor != nil {
+		result, err = r.resp3Executor.ExecuteOperation(ctx, operation)
+	} else {
+		result, err = r.redisOperations.ExecuteOperation(ctx, operation)
+	}
 
 	// 更新统计信息
 	if err != nil || (result != nil && !result.Success) {
@@ -124,6 +278,11 @@ func (r *RedisAdapter) Close() error {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
+	// 键空间通知跟踪器需在连接池关闭前恢复服务端配置，保留跟踪器以便关闭后读取统计
+	if r.keyspace != nil {
+		r.keyspace.Close()
+	}
+
 	if r.connectionPool != nil {
 		if err := r.connectionPool.Close(); err != nil {
 			return fmt.Errorf("failed to close Redis connection pool: %w", err)
@@ -131,6 +290,17 @@ func (r *RedisAdapter) Close() error {
 		r.connectionPool = nil
 	}
 
+	if r.resp3Pool != nil {
+		r.resp3Pool.Close()
+		r.resp3Pool = nil
+		r.resp3Executor = nil
+	}
+
+	// 保留跟踪器以便关闭后读取统计
+	if r.failover != nil {
+		r.failover.Close()
+	}
+
 	r.client = nil
 	r.isConnected = false
 
@@ -139,6 +309,10 @@ func (r *RedisAdapter) Close() error {
 
 // HealthCheck 健康检查
 func (r *RedisAdapter) HealthCheck(ctx context.Context) error {
+	if r.resp3Pool != nil {
+		return r.resp3Pool.Ping(ctx)
+	}
+
 	// 在连接过程中允许健康检查，只检查客户端是否初始化
 	if r.client == nil {
 		return fmt.Errorf("redis client not initialized")
@@ -179,6 +353,46 @@ func (r *RedisAdapter) GetProtocolMetrics() map[string]interface{} {
 		metrics["connection_pool"] = poolStats
 	}
 
+	// 添加客户端缓存统计信息
+	if stats := r.GetClientCacheStats(); stats != nil {
+		metrics["client_cache"] = stats
+	}
+
+	// 添加pipeline统计信息
+	if stats := r.GetPipelineStats(); stats != nil {
+		metrics["pipeline"] = stats
+	}
+
+	// 添加集群统计信息
+	if stats := r.GetClusterStats(); stats != nil {
+		metrics["cluster"] = stats
+	}
+
+	// 添加数据校验统计信息
+	if stats := r.GetVerifyStats(); stats != nil {
+		metrics["verify"] = stats
+	}
+
+	// 添加故障转移统计信息
+	if stats := r.GetFailoverStats(); stats != nil {
+		metrics["failover"] = stats
+	}
+
+	// 添加排行榜负载统计信息
+	if stats := r.GetLeaderboardStats(); stats != nil {
+		metrics["leaderboard"] = stats
+	}
+
+	// 添加键空间通知统计信息
+	if stats := r.GetKeyspaceStats(); stats != nil {
+		metrics["keyspace_notifications"] = stats
+	}
+
+	// 添加Lua脚本信息
+	if info := r.GetScriptInfo(); info != nil {
+		metrics["script"] = info
+	}
+
 	// 添加配置信息
 	if r.config != nil {
 		connectionConfig := r.config.GetConnection()
@@ -191,6 +405,96 @@ func (r *RedisAdapter) GetProtocolMetrics() map[string]interface{} {
 	return metrics
 }
 
+// GetLatencyPhases 获取新建连接的建连与TLS握手耗时，未连接或没有新建连接时返回nil
+func (r *RedisAdapter) GetLatencyPhases() []metrics.PhaseLatency {
+	if r.connectionPool == nil {
+		return nil
+	}
+	return r.connectionPool.LatencyPhases()
+}
+
+// GetClientCacheStats 获取客户端缓存（client tracking）统计，未启用时返回nil
+func (r *RedisAdapter) GetClientCacheStats() *operation.ClientCacheStats {
+	if r.resp3Executor == nil {
+		return nil
+	}
+	return r.resp3Executor.CacheStats()
+}
+
+// GetPipelineStats 获取pipeline模式的批次与单命令统计，未启用pipeline时返回nil
+func (r *RedisAdapter) GetPipelineStats() *operation.PipelineStats {
+	if r.redisOperations == nil || r.config == nil || r.config.BenchMark.GetPipeline() <= 1 {
+		return nil
+	}
+	stats := r.redisOperations.PipelineStats()
+	return &stats
+}
+
+// GetClusterStats 获取集群模式下按节点、重定向与热点槽的统计，非集群模式返回nil
+func (r *RedisAdapter) GetClusterStats() *operation.ClusterStats {
+	if r.cluster == nil {
+		return nil
+	}
+	stats := r.cluster.Stats()
+	return &stats
+}
+
+// GetVerifyStats 获取数据校验统计，未启用verify时返回nil
+func (r *RedisAdapter) GetVerifyStats() *operation.VerifyStats {
+	if r.verify == nil {
+		return nil
+	}
+	stats := r.verify.Stats()
+	return &stats
+}
+
+// GetLeaderboardStats 获取排行榜负载统计，case不是leaderboard时返回nil
+func (r *RedisAdapter) GetLeaderboardStats() *operation.LeaderboardStats {
+	if r.leaderboard == nil {
+		return nil
+	}
+	stats := r.leaderboard.Stats()
+	return &stats
+}
+
+// StartFailover 开始故障转移跟踪，配置了failover.after时按时强制故障转移；未启用时不做任何事
+func (r *RedisAdapter) StartFailover() {
+	if r.failover != nil {
+		r.failover.Arm(r.config.BenchMark.Failover.After)
+	}
+}
+
+// GetFailoverStats 获取故障转移统计，未启用故障转移跟踪时返回nil
+func (r *RedisAdapter) GetFailoverStats() *operation.FailoverStats {
+	if r.failover == nil {
+		return nil
+	}
+	stats := r.failover.Stats()
+	return &stats
+}
+
+// GetKeyspaceStats 获取键空间通知统计，等待在途通知送达或超时后统计；未启用时返回nil
+func (r *RedisAdapter) GetKeyspaceStats() *operation.KeyspaceStats {
+	if r.keyspace == nil {
+		return nil
+	}
+	r.keyspace.Wait()
+	stats := r.keyspace.Stats()
+	return &stats
+}
+
+// GetScriptInfo 获取已注册的Lua脚本信息，未启用时返回nil
+func (r *RedisAdapter) GetScriptInfo() map[string]interface{} {
+	if r.script == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"file": r.script.File(),
+		"sha":  r.script.SHA(),
+		"mode": r.script.Mode(),
+	}
+}
+
 // IsConnected 检查连接状态
 func (r *RedisAdapter) IsConnected() bool {
 	r.mutex.RLock()
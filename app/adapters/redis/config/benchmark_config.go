@@ -17,8 +17,14 @@ func NewBenchmarkConfigAdapter(config interfaces.BenchmarkConfig) execution.Benc
 	return &BenchmarkConfigAdapter{config: config}
 }
 
+// GetTotal 获取执行引擎的任务数，启用pipeline时每个任务为一批命令
 func (r *BenchmarkConfigAdapter) GetTotal() int {
-	return r.config.GetTotal()
+	total := r.config.GetTotal()
+	if pipelined, ok := r.config.(interface{ GetPipeline() int }); ok && pipelined.GetPipeline() > 1 {
+		size := pipelined.GetPipeline()
+		return (total + size - 1) / size
+	}
+	return total
 }
 
 func (r *BenchmarkConfigAdapter) GetParallels() int {
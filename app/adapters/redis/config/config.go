@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/utils"
 )
 
 // RedisConfig Redis配置实现
@@ -16,6 +17,62 @@ type RedisConfig struct {
 	Standalone StandAloneInfo      `yaml:"standalone"`
 	Sentinel   SentinelInfo        `yaml:"sentinel"`
 	Cluster    ClusterInfo         `yaml:"cluster"`
+	Client     ClientConfig        `yaml:"client"`
+	Script     ScriptConfig        `yaml:"script"`
+	TLS        TLSConfig           `yaml:"tls"`
+	Proxy      ProxyConfig         `yaml:"proxy"`
+}
+
+// ProxyConfig 代理模式配置：abc-runner监听listen并将流量转发到单机地址，记录真实客户端的每条命令延迟
+type ProxyConfig struct {
+	Listen        string        `yaml:"listen"`         // 代理监听地址，为空表示不使用代理模式
+	Duration      time.Duration `yaml:"duration"`       // 运行时长，0表示直到中断
+	InjectLatency time.Duration `yaml:"inject_latency"` // 每条回复转发前注入的延迟
+}
+
+// Enabled 是否启用代理模式
+func (p ProxyConfig) Enabled() bool {
+	return p.Listen != ""
+}
+
+// TLSConfig TLS与双向TLS配置，用于连接托管云Redis等TLS终结的端点，对所有模式（含哨兵）生效
+type TLSConfig struct {
+	Enabled            bool   `yaml:"enabled"`
+	CAFile             string `yaml:"ca_file"`              // 校验服务端证书的CA，为空时使用系统根证书
+	CertFile           string `yaml:"cert_file"`            // 客户端证书，与key_file同时设置时启用双向TLS
+	KeyFile            string `yaml:"key_file"`             // 客户端私钥
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"` // 跳过服务端证书校验
+	ServerName         string `yaml:"server_name"`          // SNI及证书校验使用的主机名，为空时取连接地址的主机部分
+}
+
+// ScriptConfig Lua脚本（EVAL/EVALSHA）基准测试配置
+// KEYS与ARGV模板支持占位符：{id} 命令序号，{key} 按random_keys生成的键，
+// {value} 按data_size生成的值，{rand} 随机整数
+type ScriptConfig struct {
+	File     string   `yaml:"file"`      // Lua脚本文件，设置后所有命令均为脚本调用
+	Keys     []string `yaml:"keys"`      // KEYS模板
+	Args     []string `yaml:"args"`      // ARGV模板
+	UseEval  bool     `yaml:"use_eval"`  // 每次发送完整脚本（EVAL），默认通过SCRIPT LOAD注册后使用EVALSHA
+	ReadOnly bool     `yaml:"read_only"` // 脚本不写数据时计为读操作
+}
+
+// Enabled 是否启用Lua脚本基准测试
+func (s *ScriptConfig) Enabled() bool {
+	return s.File != ""
+}
+
+// ClientConfig 客户端协议配置
+type ClientConfig struct {
+	Protocol int            `yaml:"protocol"` // RESP协议版本：2（默认）或3
+	Tracking TrackingConfig `yaml:"tracking"`
+}
+
+// TrackingConfig 客户端缓存（CLIENT TRACKING）配置，需要RESP3
+type TrackingConfig struct {
+	Enabled  bool     `yaml:"enabled"`
+	BCast    bool     `yaml:"bcast"`    // 广播模式，按前缀接收失效通知
+	Prefixes []string `yaml:"prefixes"` // 广播模式下关注的键前缀
+	NoLoop   bool     `yaml:"noloop"`   // 不接收本连接写入导致的失效通知
 }
 
 // StandAloneInfo 单机配置
@@ -27,10 +84,11 @@ type StandAloneInfo struct {
 
 // SentinelInfo 哨兵配置
 type SentinelInfo struct {
-	MasterName string   `yaml:"master_name"`
-	Addrs      []string `yaml:"addrs"`
-	Password   string   `yaml:"password"`
-	Db         int      `yaml:"db"`
+	MasterName       string   `yaml:"master_name"`
+	Addrs            []string `yaml:"addrs"`
+	Password         string   `yaml:"password"`          // 主从数据节点密码
+	SentinelPassword string   `yaml:"sentinel_password"` // 哨兵自身的密码（requirepass），为空表示无需认证
+	Db               int      `yaml:"db"`
 }
 
 // ClusterInfo 集群配置
@@ -57,6 +115,103 @@ type BenchmarkConfigImpl struct {
 	ReadPercent int    `yaml:"read_percent"`
 	RandomKeys  int    `yaml:"random_keys"`
 	Case        string `yaml:"case"`
+	Pipeline    int    `yaml:"pipeline"` // 每次往返批量发送的命令数，0或1表示不使用pipeline
+	Verify      bool   `yaml:"verify"`   // SET/HSET写入带校验和的值，GET/HGET校验读取结果
+
+	// KeyDistribution random_keys键空间内的访问分布，默认顺序循环
+	KeyDistribution utils.KeyDistributionConfig `yaml:"key_distribution"`
+
+	// Failover 哨兵模式下的故障转移跟踪与强制故障转移
+	Failover FailoverConfig `yaml:"failover"`
+
+	// Notifications 订阅写入键的键空间通知，测量通知送达延迟与丢失
+	Notifications NotificationsConfig `yaml:"notifications"`
+
+	// Payload SET写入值的模板，未设置时按data_size生成
+	Payload utils.PayloadTemplateConfig `yaml:"payload"`
+
+	// Leaderboard 排行榜负载（case为leaderboard时生效）
+	Leaderboard LeaderboardConfig `yaml:"leaderboard"`
+}
+
+// CaseLeaderboard 排行榜负载：ZADD/ZINCRBY更新分数，ZREVRANGE WITHSCORES查询前N名，read_percent为排名查询占比
+const CaseLeaderboard = "leaderboard"
+
+// LeaderboardConfig 排行榜（有序集合）负载配置
+// 成员按zipfian分布被更新，少数活跃成员承担大部分分数更新
+type LeaderboardConfig struct {
+	Boards     int     `yaml:"boards"`      // 排行榜（有序集合键）数量，默认1
+	Members    int     `yaml:"members"`     // 每个排行榜的成员数，默认10000
+	TopN       int     `yaml:"top_n"`       // 排名查询返回的名次数，默认10
+	AddPercent int     `yaml:"add_percent"` // 分数更新中以ZADD直接设置分数的占比，其余为ZINCRBY
+	Skew       float64 `yaml:"skew"`        // 成员访问的zipfian偏斜系数，默认0.99
+}
+
+// GetBoards 获取排行榜数量
+func (l LeaderboardConfig) GetBoards() int {
+	if l.Boards <= 0 {
+		return 1
+	}
+	return l.Boards
+}
+
+// GetMembers 获取每个排行榜的成员数
+func (l LeaderboardConfig) GetMembers() int {
+	if l.Members <= 0 {
+		return 10000
+	}
+	return l.Members
+}
+
+// GetTopN 获取排名查询返回的名次数
+func (l LeaderboardConfig) GetTopN() int {
+	if l.TopN <= 0 {
+		return 10
+	}
+	return l.TopN
+}
+
+// MemberDistribution 成员访问分布
+func (l LeaderboardConfig) MemberDistribution() utils.KeyDistributionConfig {
+	return utils.KeyDistributionConfig{Type: utils.DistributionZipfian, Skew: l.Skew}
+}
+
+// Validate 验证排行榜配置
+func (l LeaderboardConfig) Validate() error {
+	if l.Boards < 0 || l.Members < 0 || l.TopN < 0 {
+		return fmt.Errorf("leaderboard boards, members and top_n cannot be negative")
+	}
+	if l.AddPercent < 0 || l.AddPercent > 100 {
+		return fmt.Errorf("leaderboard add_percent must be between 0 and 100, got: %d", l.AddPercent)
+	}
+	return l.MemberDistribution().Validate(l.GetMembers())
+}
+
+// FailoverConfig 故障转移测试配置，仅支持哨兵模式
+type FailoverConfig struct {
+	Track bool          `yaml:"track"` // 跟踪外部触发的故障转移（如人为停止主节点）
+	After time.Duration `yaml:"after"` // 测量开始后经过该时长发送SENTINEL FAILOVER，0表示不主动触发
+}
+
+// Enabled 是否启用故障转移跟踪
+func (f FailoverConfig) Enabled() bool {
+	return f.Track || f.After > 0
+}
+
+// NotificationsConfig 键空间通知（keyspace notifications）测试配置
+// 写入负载运行期间订阅__keyspace@<db>__:*，将每次写入与其通知配对，统计从发出写入到收到通知的延迟
+type NotificationsConfig struct {
+	Enabled   bool          `yaml:"enabled"`   // 启用键空间通知测试
+	Configure bool          `yaml:"configure"` // 测试期间以CONFIG SET开启notify-keyspace-events，结束后恢复原值
+	Timeout   time.Duration `yaml:"timeout"`   // 写入后等待通知的最长时间，超过仍未收到视为丢失，0表示默认1s
+}
+
+// GetTimeout 获取等待通知的超时时间
+func (n NotificationsConfig) GetTimeout() time.Duration {
+	if n.Timeout <= 0 {
+		return time.Second
+	}
+	return n.Timeout
 }
 
 // ConnectionConfigImpl 连接配置实现
@@ -98,6 +253,11 @@ func (c *RedisConfig) GetSentinelConfig() SentinelInfo {
 	return c.Sentinel
 }
 
+// UseRESP3 是否使用RESP3协议（开启client tracking时隐含RESP3）
+func (c *RedisConfig) UseRESP3() bool {
+	return c.Client.Protocol == 3 || c.Client.Tracking.Enabled
+}
+
 // GetClusterConfig 获取集群配置
 func (c *RedisConfig) GetClusterConfig() ClusterInfo {
 	return c.Cluster
@@ -176,6 +336,56 @@ func (c *RedisConfig) Validate() error {
 		}
 	}
 
+	switch c.Client.Protocol {
+	case 0, 2, 3:
+	default:
+		return fmt.Errorf("unsupported RESP protocol version: %d", c.Client.Protocol)
+	}
+	if c.UseRESP3() && c.GetMode() != "standalone" {
+		return fmt.Errorf("RESP3/client tracking mode currently supports standalone mode only")
+	}
+	if c.Client.Tracking.Enabled && c.Client.Protocol == 2 {
+		return fmt.Errorf("client tracking requires RESP3 protocol")
+	}
+	if c.UseRESP3() && c.BenchMark.GetPipeline() > 1 {
+		return fmt.Errorf("pipeline mode is not supported with RESP3/client tracking")
+	}
+	if c.UseRESP3() && c.Script.Enabled() {
+		return fmt.Errorf("script mode is not supported with RESP3/client tracking")
+	}
+	if c.BenchMark.Failover.Enabled() && c.GetMode() != "sentinel" {
+		return fmt.Errorf("failover tracking requires sentinel mode")
+	}
+	if c.BenchMark.Failover.After < 0 {
+		return fmt.Errorf("failover delay cannot be negative")
+	}
+	if c.BenchMark.Notifications.Enabled && (c.GetMode() == "cluster" || c.UseRESP3()) {
+		return fmt.Errorf("keyspace notification testing supports standalone and sentinel modes over RESP2 only")
+	}
+	if c.BenchMark.Notifications.Timeout < 0 {
+		return fmt.Errorf("notification timeout cannot be negative")
+	}
+	if c.BenchMark.IsLeaderboard() {
+		if c.UseRESP3() || c.BenchMark.GetPipeline() > 1 || c.Script.Enabled() {
+			return fmt.Errorf("the leaderboard case is not supported with RESP3/client tracking, pipeline or script mode")
+		}
+		if err := c.BenchMark.Leaderboard.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.Proxy.Enabled() && c.GetMode() != "standalone" {
+		return fmt.Errorf("proxy mode supports standalone mode only")
+	}
+	if c.Proxy.Duration < 0 || c.Proxy.InjectLatency < 0 {
+		return fmt.Errorf("proxy duration and injected latency cannot be negative")
+	}
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		return fmt.Errorf("tls cert_file and key_file must be set together")
+	}
+	if !c.TLS.Enabled && (c.TLS.CAFile != "" || c.TLS.CertFile != "" || c.TLS.ServerName != "" || c.TLS.InsecureSkipVerify) {
+		return fmt.Errorf("tls options are set but tls is not enabled")
+	}
+
 	return c.BenchMark.Validate()
 }
 
@@ -194,6 +404,14 @@ func (c *RedisConfig) Clone() interfaces.Config {
 		copy(cloned.Cluster.Addrs, c.Cluster.Addrs)
 	}
 
+	if len(c.Client.Tracking.Prefixes) > 0 {
+		cloned.Client.Tracking.Prefixes = make([]string, len(c.Client.Tracking.Prefixes))
+		copy(cloned.Client.Tracking.Prefixes, c.Client.Tracking.Prefixes)
+	}
+
+	cloned.Script.Keys = append([]string(nil), c.Script.Keys...)
+	cloned.Script.Args = append([]string(nil), c.Script.Args...)
+
 	return &cloned
 }
 
@@ -315,6 +533,19 @@ func (b *BenchmarkConfigImpl) GetRandomKeys() int {
 	return b.RandomKeys
 }
 
+// GetPipeline 获取每批pipeline命令数，未启用时为1
+func (b *BenchmarkConfigImpl) GetPipeline() int {
+	if b.Pipeline <= 1 {
+		return 1
+	}
+	return b.Pipeline
+}
+
+// IsLeaderboard 是否为排行榜负载
+func (b *BenchmarkConfigImpl) IsLeaderboard() bool {
+	return b.Case == CaseLeaderboard
+}
+
 // GetTestCase 获取测试用例
 func (b *BenchmarkConfigImpl) GetTestCase() string {
 	if b.Case == "" {
@@ -341,6 +572,14 @@ func (b *BenchmarkConfigImpl) Validate() error {
 		return fmt.Errorf("read_percent must be between 0 and 100")
 	}
 
+	if b.Pipeline < 0 {
+		return fmt.Errorf("pipeline cannot be negative")
+	}
+
+	if err := b.KeyDistribution.Validate(b.RandomKeys); err != nil {
+		return fmt.Errorf("key_distribution: %w", err)
+	}
+
 	return nil
 }
 
@@ -357,6 +596,7 @@ func NewDefaultRedisConfig() *RedisConfig {
 			ReadPercent: 50,
 			RandomKeys:  0,
 			Case:        "get",
+			Leaderboard: LeaderboardConfig{AddPercent: 10},
 		},
 		Pool: PoolConfigImpl{
 			PoolSize:          10,
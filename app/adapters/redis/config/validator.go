@@ -138,8 +138,16 @@ func validateBenchmark(config *RedisConfig) error {
 		return fmt.Errorf("benchmark ttl cannot be negative, got: %d", benchmark.TTL)
 	}
 
+	if benchmark.Pipeline < 0 {
+		return fmt.Errorf("benchmark pipeline cannot be negative, got: %d", benchmark.Pipeline)
+	}
+
+	if err := benchmark.KeyDistribution.Validate(benchmark.RandomKeys); err != nil {
+		return fmt.Errorf("benchmark key_distribution: %w", err)
+	}
+
 	// 验证测试用例
-	validCases := []string{"get", "set", "set_get", "set_get_random", "pub", "sub"}
+	validCases := []string{"get", "set", "set_get", "set_get_random", "pub", "sub", CaseLeaderboard}
 	if benchmark.Case != "" {
 		found := false
 		for _, validCase := range validCases {
@@ -290,7 +290,7 @@ func TestValidateBenchmark(t *testing.T) {
 	})
 
 	t.Run("Valid Test Cases", func(t *testing.T) {
-		validCases := []string{"get", "set", "set_get", "set_get_random", "pub", "sub"}
+		validCases := []string{"get", "set", "set_get", "set_get_random", "pub", "sub", "leaderboard"}
 
 		for _, testCase := range validCases {
 			config := NewDefaultRedisConfig()
@@ -3,29 +3,60 @@ package connection
 import (
 	"context"
 	"fmt"
+	"net"
 	"sync"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 
 	"abc-runner/app/adapters/redis/config"
+	"abc-runner/app/core/metrics"
 )
 
 // RedisConnectionPool Redis连接池
 type RedisConnectionPool struct {
-	client redis.UniversalClient
-	config *config.RedisConfig
-	mutex  sync.RWMutex
+	client    redis.UniversalClient
+	config    *config.RedisConfig
+	nodeHook  func(addr string) redis.Hook                    // 集群模式下为每个节点客户端添加的hook
+	onConnect func(ctx context.Context, cn *redis.Conn) error // 新建连接回调
+	phases    *metrics.PhaseTracker                           // 新建连接的建连与TLS握手耗时，重连后继续累计
+	mutex     sync.RWMutex
 }
 
 // NewRedisConnectionPool 创建连接池
 func NewRedisConnectionPool(cfg *config.RedisConfig) (*RedisConnectionPool, error) {
+	return NewRedisConnectionPoolWithNodeHook(cfg, nil)
+}
+
+// NewRedisConnectionPoolWithNodeHook 创建连接池，集群模式下为每个节点客户端添加nodeHook创建的hook
+func NewRedisConnectionPoolWithNodeHook(cfg *config.RedisConfig, nodeHook func(addr string) redis.Hook) (*RedisConnectionPool, error) {
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
 
 	pool := &RedisConnectionPool{
-		config: cfg,
+		config:   cfg,
+		nodeHook: nodeHook,
+	}
+
+	client, err := pool.createClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Redis client: %w", err)
+	}
+
+	pool.client = client
+	return pool, nil
+}
+
+// NewRedisConnectionPoolWithConnectHook 创建连接池，每次新建连接时调用onConnect
+func NewRedisConnectionPoolWithConnectHook(cfg *config.RedisConfig, onConnect func(ctx context.Context, cn *redis.Conn) error) (*RedisConnectionPool, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	pool := &RedisConnectionPool{
+		config:    cfg,
+		onConnect: onConnect,
 	}
 
 	client, err := pool.createClient()
@@ -39,7 +70,27 @@ func NewRedisConnectionPool(cfg *config.RedisConfig) (*RedisConnectionPool, erro
 
 // createClient 创建Redis客户端
 func (p *RedisConnectionPool) createClient() (redis.UniversalClient, error) {
+	tlsConfig, err := NewTLSConfig(p.config.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.phases == nil {
+		p.phases = metrics.NewPhaseTracker()
+	}
+	// 自行拨号以分别记录建连与TLS握手耗时，拨号参数与go-redis的默认拨号一致
+	dialer := &net.Dialer{
+		Timeout:   p.config.Pool.ConnectionTimeout,
+		KeepAlive: 5 * time.Minute,
+	}
+	if dialer.Timeout <= 0 {
+		dialer.Timeout = 5 * time.Second
+	}
+
 	options := &redis.UniversalOptions{
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return p.phases.DialTLS(ctx, dialer, network, addr, tlsConfig)
+		},
 		PoolSize:     p.config.Pool.PoolSize,
 		MinIdleConns: p.config.Pool.MinIdle,
 		IdleTimeout:  p.config.Pool.IdleTimeout,
@@ -48,6 +99,8 @@ func (p *RedisConnectionPool) createClient() (redis.UniversalClient, error) {
 		WriteTimeout: 30 * time.Second, // 默认值
 		PoolTimeout:  p.config.Pool.ConnectionTimeout,
 		MaxRetries:   3, // 默认值
+		OnConnect:    p.onConnect,
+		TLSConfig:    tlsConfig,
 	}
 
 	// 根据模式设置连接参数
@@ -65,6 +118,7 @@ func (p *RedisConnectionPool) createClient() (redis.UniversalClient, error) {
 		if sentinel.Password != "" {
 			options.Password = sentinel.Password
 		}
+		options.SentinelPassword = sentinel.SentinelPassword
 		options.DB = sentinel.Db
 	default: // standalone
 		standalone := p.config.GetStandaloneConfig()
@@ -75,6 +129,16 @@ func (p *RedisConnectionPool) createClient() (redis.UniversalClient, error) {
 		options.DB = standalone.Db
 	}
 
+	if p.config.GetMode() == "cluster" && p.nodeHook != nil {
+		clusterOptions := options.Cluster()
+		clusterOptions.NewClient = func(opt *redis.Options) *redis.Client {
+			node := redis.NewClient(opt)
+			node.AddHook(p.nodeHook(opt.Addr))
+			return node
+		}
+		return redis.NewClusterClient(clusterOptions), nil
+	}
+
 	client := redis.NewUniversalClient(options)
 	return client, nil
 }
@@ -101,6 +165,16 @@ func (p *RedisConnectionPool) Ping(ctx context.Context) error {
 	return nil
 }
 
+// LatencyPhases 新建连接的建连与TLS握手耗时统计
+func (p *RedisConnectionPool) LatencyPhases() []metrics.PhaseLatency {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	if p.phases == nil {
+		return nil
+	}
+	return p.phases.Breakdown()
+}
+
 // GetStats 获取连接池统计信息
 func (p *RedisConnectionPool) GetStats() map[string]interface{} {
 	client := p.GetClient()
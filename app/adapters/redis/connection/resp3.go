@@ -0,0 +1,332 @@
+package connection
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RESP3Error 服务端返回的错误回复
+type RESP3Error struct {
+	Message string
+}
+
+func (e *RESP3Error) Error() string {
+	return e.Message
+}
+
+// RESP3Push 服务端推送消息（如client tracking的invalidate消息）
+type RESP3Push struct {
+	Kind string
+	Data []interface{}
+}
+
+// ErrRESP3ConnClosed 连接已关闭
+var ErrRESP3ConnClosed = errors.New("resp3 connection closed")
+
+// ReadRESP3 从reader中读取一个RESP3值
+// 返回值类型：string、int64、float64、bool、nil、[]interface{}、map[string]interface{}、*RESP3Error、*RESP3Push
+func ReadRESP3(reader *bufio.Reader) (interface{}, error) {
+	line, err := readRESP3Line(reader)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, fmt.Errorf("empty RESP3 line")
+	}
+
+	prefix, payload := line[0], line[1:]
+	switch prefix {
+	case '+': // simple string
+		return payload, nil
+	case '-': // simple error
+		return &RESP3Error{Message: payload}, nil
+	case ':': // integer
+		return strconv.ParseInt(payload, 10, 64)
+	case '(': // big number
+		return payload, nil
+	case ',': // double
+		switch payload {
+		case "inf":
+			return math.Inf(1), nil
+		case "-inf":
+			return math.Inf(-1), nil
+		}
+		return strconv.ParseFloat(payload, 64)
+	case '#': // boolean
+		return payload == "t", nil
+	case '_': // null
+		return nil, nil
+	case '$', '=', '!': // bulk string / verbatim string / bulk error
+		size, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bulk length %q: %w", payload, err)
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, buf); err != nil {
+			return nil, err
+		}
+		value := string(buf[:size])
+		switch prefix {
+		case '=':
+			// 去掉 "txt:" 等格式前缀
+			if len(value) >= 4 && value[3] == ':' {
+				value = value[4:]
+			}
+		case '!':
+			return &RESP3Error{Message: value}, nil
+		}
+		return value, nil
+	case '*', '~', '>': // array / set / push
+		count, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, fmt.Errorf("invalid aggregate length %q: %w", payload, err)
+		}
+		if count < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, count)
+		for i := 0; i < count; i++ {
+			if items[i], err = ReadRESP3(reader); err != nil {
+				return nil, err
+			}
+		}
+		if prefix == '>' {
+			push := &RESP3Push{Data: items}
+			if count > 0 {
+				push.Kind, _ = items[0].(string)
+				push.Data = items[1:]
+			}
+			return push, nil
+		}
+		return items, nil
+	case '%': // map
+		count, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, fmt.Errorf("invalid map length %q: %w", payload, err)
+		}
+		result := make(map[string]interface{}, count)
+		for i := 0; i < count; i++ {
+			key, err := ReadRESP3(reader)
+			if err != nil {
+				return nil, err
+			}
+			value, err := ReadRESP3(reader)
+			if err != nil {
+				return nil, err
+			}
+			result[fmt.Sprint(key)] = value
+		}
+		return result, nil
+	case '|': // attribute，读取后丢弃，返回其后的实际值
+		count, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, fmt.Errorf("invalid attribute length %q: %w", payload, err)
+		}
+		for i := 0; i < count*2; i++ {
+			if _, err := ReadRESP3(reader); err != nil {
+				return nil, err
+			}
+		}
+		return ReadRESP3(reader)
+	default:
+		return nil, fmt.Errorf("unknown RESP3 type prefix %q", prefix)
+	}
+}
+
+// WriteRESP3Command 以RESP数组格式写入命令
+func WriteRESP3Command(writer *bufio.Writer, args ...string) error {
+	writer.WriteString("*")
+	writer.WriteString(strconv.Itoa(len(args)))
+	writer.WriteString("\r\n")
+	for _, arg := range args {
+		writer.WriteString("$")
+		writer.WriteString(strconv.Itoa(len(arg)))
+		writer.WriteString("\r\n")
+		writer.WriteString(arg)
+		writer.WriteString("\r\n")
+	}
+	return writer.Flush()
+}
+
+// readRESP3Line 读取一行并去掉结尾的CRLF
+func readRESP3Line(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// resp3Reply 等待中的命令回复
+type resp3Reply struct {
+	value interface{}
+	err   error
+}
+
+// RESP3Conn 支持RESP3推送消息的Redis连接
+// 命令按FIFO顺序匹配回复，推送消息由后台读协程分发给推送处理函数
+type RESP3Conn struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	writer  *bufio.Writer
+	onPush  func(*RESP3Push)
+	timeout time.Duration
+
+	writeMutex sync.Mutex
+	pending    chan chan resp3Reply
+
+	closeOnce sync.Once
+	closed    chan struct{}
+	err       error
+}
+
+// DialRESP3 建立RESP3连接并完成HELLO握手，tlsConfig非nil时使用TLS
+func DialRESP3(ctx context.Context, addr, password string, db int, timeout time.Duration, tlsConfig *tls.Config, onPush func(*RESP3Push)) (*RESP3Conn, error) {
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+	var conn net.Conn
+	var err error
+	if tlsConfig != nil {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %w", addr, err)
+	}
+
+	c := &RESP3Conn{
+		conn:    conn,
+		reader:  bufio.NewReader(conn),
+		writer:  bufio.NewWriter(conn),
+		onPush:  onPush,
+		timeout: timeout,
+		pending: make(chan chan resp3Reply, 1024),
+		closed:  make(chan struct{}),
+	}
+	go c.readLoop()
+
+	hello := []string{"HELLO", "3"}
+	if password != "" {
+		hello = append(hello, "AUTH", "default", password)
+	}
+	if _, err := c.Do(ctx, hello...); err != nil {
+		c.Close()
+		return nil, fmt.Errorf("RESP3 handshake failed (requires Redis 6.0+): %w", err)
+	}
+
+	if db != 0 {
+		if _, err := c.Do(ctx, "SELECT", strconv.Itoa(db)); err != nil {
+			c.Close()
+			return nil, fmt.Errorf("failed to select db %d: %w", db, err)
+		}
+	}
+
+	return c, nil
+}
+
+// Do 发送命令并等待回复
+func (c *RESP3Conn) Do(ctx context.Context, args ...string) (interface{}, error) {
+	replyChan := make(chan resp3Reply, 1)
+
+	c.writeMutex.Lock()
+	select {
+	case <-c.closed:
+		c.writeMutex.Unlock()
+		return nil, c.closeErr()
+	default:
+	}
+	// 先登记等待者再写入，保证回复到达时一定能匹配到
+	c.pending <- replyChan
+	c.conn.SetWriteDeadline(time.Now().Add(c.timeout))
+	if err := WriteRESP3Command(c.writer, args...); err != nil {
+		c.writeMutex.Unlock()
+		c.fail(err)
+		return nil, fmt.Errorf("failed to write command: %w", err)
+	}
+	c.writeMutex.Unlock()
+
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+
+	select {
+	case reply := <-replyChan:
+		if reply.err != nil {
+			return nil, reply.err
+		}
+		if respErr, ok := reply.value.(*RESP3Error); ok {
+			return nil, respErr
+		}
+		return reply.value, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timer.C:
+		return nil, fmt.Errorf("command %s timed out after %v", args[0], c.timeout)
+	case <-c.closed:
+		return nil, c.closeErr()
+	}
+}
+
+// Close 关闭连接
+func (c *RESP3Conn) Close() error {
+	c.fail(ErrRESP3ConnClosed)
+	return nil
+}
+
+// readLoop 后台读取回复与推送消息
+func (c *RESP3Conn) readLoop() {
+	for {
+		value, err := ReadRESP3(c.reader)
+		if err != nil {
+			c.fail(err)
+			return
+		}
+
+		if push, ok := value.(*RESP3Push); ok {
+			if c.onPush != nil {
+				c.onPush(push)
+			}
+			continue
+		}
+
+		select {
+		case replyChan := <-c.pending:
+			replyChan <- resp3Reply{value: value}
+		default:
+			// 没有等待中的命令，丢弃未预期的回复
+		}
+	}
+}
+
+// fail 关闭连接并唤醒所有等待者
+func (c *RESP3Conn) fail(err error) {
+	c.closeOnce.Do(func() {
+		c.err = err
+		close(c.closed)
+		c.conn.Close()
+	})
+}
+
+// closeErr 获取关闭原因
+func (c *RESP3Conn) closeErr() error {
+	if c.err == nil || errors.Is(c.err, ErrRESP3ConnClosed) {
+		return ErrRESP3ConnClosed
+	}
+	return fmt.Errorf("%w: %v", ErrRESP3ConnClosed, c.err)
+}
@@ -0,0 +1,114 @@
+package connection
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"abc-runner/app/adapters/redis/config"
+)
+
+// RESP3Pool RESP3连接池，可选为每个连接开启client tracking（客户端缓存失效通知）
+type RESP3Pool struct {
+	conns  []*RESP3Conn
+	config *config.RedisConfig
+	next   uint64
+	mutex  sync.RWMutex
+}
+
+// NewRESP3Pool 创建RESP3连接池，onPush接收所有连接上的推送消息
+func NewRESP3Pool(ctx context.Context, cfg *config.RedisConfig, onPush func(*RESP3Push)) (*RESP3Pool, error) {
+	if cfg.GetMode() != "standalone" {
+		return nil, fmt.Errorf("RESP3 client mode currently supports standalone Redis only, got %s", cfg.GetMode())
+	}
+
+	size := cfg.Pool.PoolSize
+	if size <= 0 {
+		size = 10
+	}
+
+	tlsConfig, err := NewTLSConfig(cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	standalone := cfg.GetStandaloneConfig()
+	tracking := cfg.Client.Tracking
+	pool := &RESP3Pool{
+		conns:  make([]*RESP3Conn, 0, size),
+		config: cfg,
+	}
+
+	for i := 0; i < size; i++ {
+		conn, err := DialRESP3(ctx, standalone.Addr, standalone.Password, standalone.Db, cfg.Pool.ConnectionTimeout, tlsConfig, onPush)
+		if err != nil {
+			pool.Close()
+			return nil, err
+		}
+		pool.conns = append(pool.conns, conn)
+
+		if tracking.Enabled {
+			if _, err := conn.Do(ctx, trackingCommand(tracking)...); err != nil {
+				pool.Close()
+				return nil, fmt.Errorf("failed to enable client tracking: %w", err)
+			}
+		}
+	}
+
+	return pool, nil
+}
+
+// trackingCommand 构造 CLIENT TRACKING ON 命令
+func trackingCommand(tracking config.TrackingConfig) []string {
+	args := []string{"CLIENT", "TRACKING", "ON"}
+	if tracking.BCast {
+		args = append(args, "BCAST")
+		for _, prefix := range tracking.Prefixes {
+			args = append(args, "PREFIX", prefix)
+		}
+	}
+	if tracking.NoLoop {
+		args = append(args, "NOLOOP")
+	}
+	return args
+}
+
+// Get 轮询获取一个连接
+func (p *RESP3Pool) Get() *RESP3Conn {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	if len(p.conns) == 0 {
+		return nil
+	}
+	index := atomic.AddUint64(&p.next, 1)
+	return p.conns[index%uint64(len(p.conns))]
+}
+
+// Ping 健康检查
+func (p *RESP3Pool) Ping(ctx context.Context) error {
+	conn := p.Get()
+	if conn == nil {
+		return fmt.Errorf("resp3 pool is closed")
+	}
+	_, err := conn.Do(ctx, "PING")
+	return err
+}
+
+// Size 连接数量
+func (p *RESP3Pool) Size() int {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return len(p.conns)
+}
+
+// Close 关闭所有连接
+func (p *RESP3Pool) Close() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for _, conn := range p.conns {
+		conn.Close()
+	}
+	p.conns = nil
+	return nil
+}
@@ -0,0 +1,113 @@
+package connection
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"abc-runner/app/adapters/redis/config"
+)
+
+// fakeTLSRedis 只应答PING的TLS Redis，requireClientCert为true时要求双向TLS
+// 返回监听地址与存放证书的目录：ca.pem（自签名证书），cert.pem与key.pem（同一证书作为客户端证书）
+func fakeTLSRedis(t *testing.T, requireClientCert bool) (addr, dir string) {
+	server := httptest.NewUnstartedServer(nil)
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	dir = t.TempDir()
+	key, err := x509.MarshalPKCS8PrivateKey(server.TLS.Certificates[0].PrivateKey)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	for name, data := range map[string][]byte{
+		"ca.pem":   cert,
+		"cert.pem": cert,
+		"key.pem":  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key}),
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	tlsConfig := server.TLS.Clone()
+	tlsConfig.NextProtos = nil
+	if requireClientCert {
+		tlsConfig.ClientAuth = tls.RequireAnyClientCert
+	}
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", tlsConfig)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					if _, err := ReadRESP3(reader); err != nil {
+						return
+					}
+					conn.Write([]byte("+PONG\r\n"))
+				}
+			}(conn)
+		}
+	}()
+	return listener.Addr().String(), dir
+}
+
+func TestTLSConnection(t *testing.T) {
+	addr, dir := fakeTLSRedis(t, true)
+	caFile := filepath.Join(dir, "ca.pem")
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	newConfig := func(tlsConfig config.TLSConfig) *config.RedisConfig {
+		cfg := config.NewDefaultRedisConfig()
+		cfg.Standalone.Addr = addr
+		cfg.Standalone.Password = ""
+		cfg.Pool.ConnectionTimeout = time.Second
+		cfg.TLS = tlsConfig
+		return cfg
+	}
+	ping := func(cfg *config.RedisConfig) error {
+		pool, err := NewRedisConnectionPool(cfg)
+		if err != nil {
+			return err
+		}
+		defer pool.Close()
+		return pool.Ping(context.Background())
+	}
+
+	mutual := config.TLSConfig{Enabled: true, CAFile: caFile, CertFile: certFile, KeyFile: keyFile, ServerName: "example.com"}
+	if err := ping(newConfig(mutual)); err != nil {
+		t.Fatalf("mutual TLS ping failed: %v", err)
+	}
+	if err := ping(newConfig(config.TLSConfig{Enabled: true, CAFile: caFile})); err == nil {
+		t.Error("expected a handshake failure without a client certificate")
+	}
+	if err := ping(newConfig(config.TLSConfig{Enabled: true, CertFile: certFile, KeyFile: keyFile})); err == nil {
+		t.Error("expected a verification failure without the CA")
+	}
+	if err := ping(newConfig(config.TLSConfig{Enabled: true, CertFile: certFile, KeyFile: keyFile, InsecureSkipVerify: true})); err != nil {
+		t.Errorf("insecure ping failed: %v", err)
+	}
+	if err := newConfig(config.TLSConfig{Enabled: true, CertFile: certFile}).Validate(); err == nil {
+		t.Error("expected an error for a certificate without a key")
+	}
+}
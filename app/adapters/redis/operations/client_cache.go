@@ -0,0 +1,273 @@
+package operation
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"abc-runner/app/adapters/redis/connection"
+	"abc-runner/app/core/metrics"
+)
+
+// defaultClientCacheEntries 客户端缓存默认最大条目数
+const defaultClientCacheEntries = 100000
+
+// ClientCacheStats 客户端缓存（client tracking）统计
+type ClientCacheStats struct {
+	Hits               int64         `json:"hits"`
+	Misses             int64         `json:"misses"`
+	HitRatio           float64       `json:"hit_ratio"`
+	CachedKeys         int           `json:"cached_keys"`
+	Invalidations      int64         `json:"invalidations"`       // 收到的invalidate消息数
+	InvalidatedKeys    int64         `json:"invalidated_keys"`    // 被失效的键数量
+	FlushInvalidations int64         `json:"flush_invalidations"` // 整体失效（FLUSHALL等）次数
+	InvalidationRate   float64       `json:"invalidation_rate"`   // 每秒invalidate消息数
+	StaleReads         int64         `json:"stale_reads"`         // 写入确认后、失效通知到达前的缓存命中
+	StaleWindowAvg     time.Duration `json:"stale_window_avg"`    // 写入确认到失效通知的平均窗口
+	StaleWindowP99     time.Duration `json:"stale_window_p99"`
+	StaleWindowMax     time.Duration `json:"stale_window_max"`
+	StaleWindowSamples int           `json:"stale_window_samples"`
+}
+
+// cacheEntry 本地缓存条目
+type cacheEntry struct {
+	value   interface{}
+	loading bool // 正在从服务端加载，加载期间收到失效通知则丢弃加载结果
+}
+
+// pendingWrite 本客户端发起、尚未收到失效通知的写入
+type pendingWrite struct {
+	ackedAt     time.Time // 写入确认时间，零值表示写入尚未确认
+	invalidated bool      // 写入确认前已收到失效通知
+}
+
+// ClientCache 基于RESP3 client tracking失效通知的本地缓存
+type ClientCache struct {
+	entries    map[string]*cacheEntry
+	writes     map[string]*pendingWrite
+	maxEntries int
+	bcast      bool
+	mutex      sync.Mutex
+
+	hits               int64
+	misses             int64
+	invalidations      int64
+	invalidatedKeys    int64
+	flushInvalidations int64
+	staleReads         int64
+	staleWindows       *metrics.LatencyTracker
+	staleWindowSamples int64
+	startTime          time.Time
+}
+
+// NewClientCache 创建客户端缓存，maxEntries<=0时使用默认容量
+// bcast表示服务端以广播模式发送失效通知（无论键是否被本地缓存）
+func NewClientCache(maxEntries int, bcast bool) *ClientCache {
+	if maxEntries <= 0 {
+		maxEntries = defaultClientCacheEntries
+	}
+	return &ClientCache{
+		entries:    make(map[string]*cacheEntry),
+		writes:     make(map[string]*pendingWrite),
+		maxEntries: maxEntries,
+		bcast:      bcast,
+		startTime:  time.Now(),
+		staleWindows: metrics.NewLatencyTracker(metrics.LatencyConfig{
+			SignificantDigits: metrics.DefaultHdrSignificantDigits,
+			SamplingRate:      1.0,
+		}),
+	}
+}
+
+// Get 查询本地缓存，命中时返回缓存值
+func (c *ClientCache) Get(key string) (interface{}, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || entry.loading {
+		atomic.AddInt64(&c.misses, 1)
+		return nil, false
+	}
+
+	atomic.AddInt64(&c.hits, 1)
+	if write, pending := c.writes[key]; pending && !write.ackedAt.IsZero() {
+		atomic.AddInt64(&c.staleReads, 1)
+	}
+	return entry.value, true
+}
+
+// beginLoad 标记键开始从服务端加载
+func (c *ClientCache) beginLoad(key string) *cacheEntry {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if len(c.entries) >= c.maxEntries {
+		// 容量已满，随机淘汰一个条目
+		for evict := range c.entries {
+			delete(c.entries, evict)
+			break
+		}
+	}
+
+	entry := &cacheEntry{loading: true}
+	c.entries[key] = entry
+	return entry
+}
+
+// completeLoad 完成加载，加载期间键已失效时丢弃结果
+func (c *ClientCache) completeLoad(key string, entry *cacheEntry, value interface{}, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.entries[key] != entry {
+		return
+	}
+	if err != nil {
+		delete(c.entries, key)
+		return
+	}
+	entry.value = value
+	entry.loading = false
+}
+
+// beginWrite 记录写入开始
+func (c *ClientCache) beginWrite(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.writes[key] = &pendingWrite{}
+}
+
+// completeWrite 记录写入确认，写入失败或确认前已失效时不再追踪
+func (c *ClientCache) completeWrite(key string, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	write, ok := c.writes[key]
+	if !ok {
+		return
+	}
+	if err != nil || write.invalidated {
+		if write.invalidated {
+			c.recordStaleWindow(0)
+		}
+		delete(c.writes, key)
+		return
+	}
+	// 键不在本地缓存中且非广播模式时不会收到失效通知
+	if _, cached := c.entries[key]; !cached && !c.bcast {
+		delete(c.writes, key)
+		return
+	}
+	write.ackedAt = time.Now()
+}
+
+// Invalidate 处理失效通知，keys为nil表示整体失效
+func (c *ClientCache) Invalidate(keys []string) {
+	now := time.Now()
+	atomic.AddInt64(&c.invalidations, 1)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if keys == nil {
+		atomic.AddInt64(&c.flushInvalidations, 1)
+		atomic.AddInt64(&c.invalidatedKeys, int64(len(c.entries)))
+		c.entries = make(map[string]*cacheEntry)
+		for key, write := range c.writes {
+			c.resolveWrite(key, write, now)
+		}
+		return
+	}
+
+	for _, key := range keys {
+		if _, ok := c.entries[key]; ok {
+			delete(c.entries, key)
+			atomic.AddInt64(&c.invalidatedKeys, 1)
+		}
+		if write, ok := c.writes[key]; ok {
+			c.resolveWrite(key, write, now)
+		}
+	}
+}
+
+// HandlePush 处理服务端推送消息，用作RESP3连接池的推送回调
+func (cache *ClientCache) HandlePush(push *connection.RESP3Push) {
+	if push.Kind != "invalidate" || len(push.Data) == 0 {
+		return
+	}
+
+	// 失效键列表为null时表示整体失效（FLUSHALL/FLUSHDB）
+	rawKeys, ok := push.Data[0].([]interface{})
+	if !ok {
+		cache.Invalidate(nil)
+		return
+	}
+
+	keys := make([]string, 0, len(rawKeys))
+	for _, raw := range rawKeys {
+		if key, ok := raw.(string); ok {
+			keys = append(keys, key)
+		}
+	}
+	cache.Invalidate(keys)
+}
+
+// resolveWrite 失效通知到达时结束对写入的追踪并记录窗口（需持有锁）
+func (c *ClientCache) resolveWrite(key string, write *pendingWrite, now time.Time) {
+	if write.ackedAt.IsZero() {
+		// 失效通知先于写入确认到达
+		write.invalidated = true
+		return
+	}
+	c.recordStaleWindow(now.Sub(write.ackedAt))
+	delete(c.writes, key)
+}
+
+// recordStaleWindow 记录失效窗口样本
+func (c *ClientCache) recordStaleWindow(window time.Duration) {
+	c.staleWindows.Record(window)
+	atomic.AddInt64(&c.staleWindowSamples, 1)
+}
+
+// evict 本地移除键（NOLOOP模式下本客户端的写入不会收到失效通知）
+func (c *ClientCache) evict(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.entries, key)
+	delete(c.writes, key)
+}
+
+// Stats 获取缓存统计
+func (c *ClientCache) Stats() ClientCacheStats {
+	c.mutex.Lock()
+	cachedKeys := len(c.entries)
+	c.mutex.Unlock()
+
+	stats := ClientCacheStats{
+		Hits:               atomic.LoadInt64(&c.hits),
+		Misses:             atomic.LoadInt64(&c.misses),
+		CachedKeys:         cachedKeys,
+		Invalidations:      atomic.LoadInt64(&c.invalidations),
+		InvalidatedKeys:    atomic.LoadInt64(&c.invalidatedKeys),
+		FlushInvalidations: atomic.LoadInt64(&c.flushInvalidations),
+		StaleReads:         atomic.LoadInt64(&c.staleReads),
+		StaleWindowSamples: int(atomic.LoadInt64(&c.staleWindowSamples)),
+	}
+
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		stats.HitRatio = float64(stats.Hits) / float64(lookups) * 100
+	}
+	if elapsed := time.Since(c.startTime).Seconds(); elapsed > 0 {
+		stats.InvalidationRate = float64(stats.Invalidations) / elapsed
+	}
+
+	if stats.StaleWindowSamples > 0 {
+		windows := c.staleWindows.GetMetrics()
+		stats.StaleWindowAvg = windows.Average
+		stats.StaleWindowP99 = windows.P99
+		stats.StaleWindowMax = windows.Max
+	}
+
+	return stats
+}
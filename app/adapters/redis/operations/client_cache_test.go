@@ -0,0 +1,189 @@
+package operation
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	redisConfig "abc-runner/app/adapters/redis/config"
+	"abc-runner/app/adapters/redis/connection"
+	"abc-runner/app/core/interfaces"
+)
+
+// fakeTrackingServer 最小化的RESP3服务端，支持HELLO/CLIENT TRACKING/GET/SET并在写入时推送失效通知
+func fakeTrackingServer(t *testing.T) (string, func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	var (
+		mutex   sync.Mutex
+		data    = map[string]string{}
+		clients []*bufio.Writer
+		locks   []*sync.Mutex
+	)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				writer := bufio.NewWriter(conn)
+				writeMutex := &sync.Mutex{}
+				reply := func(s string) {
+					writeMutex.Lock()
+					writer.WriteString(s)
+					writer.Flush()
+					writeMutex.Unlock()
+				}
+
+				for {
+					value, err := connection.ReadRESP3(reader)
+					if err != nil {
+						return
+					}
+					args, _ := value.([]interface{})
+					if len(args) == 0 {
+						continue
+					}
+					command := strings.ToUpper(args[0].(string))
+					switch command {
+					case "HELLO":
+						reply("%1\r\n+proto\r\n:3\r\n")
+					case "CLIENT":
+						mutex.Lock()
+						clients = append(clients, writer)
+						locks = append(locks, writeMutex)
+						mutex.Unlock()
+						reply("+OK\r\n")
+					case "PING":
+						reply("+PONG\r\n")
+					case "GET":
+						mutex.Lock()
+						v, ok := data[args[1].(string)]
+						mutex.Unlock()
+						if !ok {
+							reply("_\r\n")
+						} else {
+							reply("$" + strconv.Itoa(len(v)) + "\r\n" + v + "\r\n")
+						}
+					case "SET":
+						key := args[1].(string)
+						mutex.Lock()
+						data[key] = args[2].(string)
+						targets := append([]*bufio.Writer(nil), clients...)
+						targetLocks := append([]*sync.Mutex(nil), locks...)
+						mutex.Unlock()
+						reply("+OK\r\n")
+						// 异步推送失效通知，模拟网络延迟
+						time.Sleep(time.Millisecond)
+						for i, w := range targets {
+							targetLocks[i].Lock()
+							w.WriteString(">2\r\n$10\r\ninvalidate\r\n*1\r\n$" + strconv.Itoa(len(key)) + "\r\n" + key + "\r\n")
+							w.Flush()
+							targetLocks[i].Unlock()
+						}
+					default:
+						reply("-ERR unknown command\r\n")
+					}
+				}
+			}(conn)
+		}
+	}()
+
+	return listener.Addr().String(), func() { listener.Close() }
+}
+
+func TestRESP3Executor_ClientTracking(t *testing.T) {
+	addr, stop := fakeTrackingServer(t)
+	defer stop()
+
+	cfg := redisConfig.NewDefaultRedisConfig()
+	cfg.Standalone.Addr = addr
+	cfg.Pool.PoolSize = 2
+	cfg.Pool.ConnectionTimeout = 2 * time.Second
+	cfg.Client.Tracking.Enabled = true
+
+	cache := NewClientCache(0, false)
+	pool, err := connection.NewRESP3Pool(context.Background(), cfg, cache.HandlePush)
+	if err != nil {
+		t.Fatalf("failed to create RESP3 pool: %v", err)
+	}
+	defer pool.Close()
+
+	executor := NewRESP3Executor(pool, cfg, cache)
+	ctx := context.Background()
+
+	if _, err := executor.ExecuteOperation(ctx, interfaces.Operation{Type: "set", Key: "k", Value: "v1"}); err != nil {
+		t.Fatalf("set failed: %v", err)
+	}
+
+	// 第一次读取未命中，第二次命中本地缓存
+	result, err := executor.ExecuteOperation(ctx, interfaces.Operation{Type: "get", Key: "k"})
+	if err != nil || result.Value != "v1" || result.Metadata["cache_hit"] != false {
+		t.Fatalf("expected cache miss returning v1, got %v (err=%v, meta=%v)", result.Value, err, result.Metadata)
+	}
+	result, _ = executor.ExecuteOperation(ctx, interfaces.Operation{Type: "get", Key: "k"})
+	if result.Value != "v1" || result.Metadata["cache_hit"] != true {
+		t.Fatalf("expected cache hit returning v1, got %v (meta=%v)", result.Value, result.Metadata)
+	}
+
+	// 写入后等待失效通知，再次读取应返回新值
+	if _, err := executor.ExecuteOperation(ctx, interfaces.Operation{Type: "set", Key: "k", Value: "v2"}); err != nil {
+		t.Fatalf("set failed: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for cache.Stats().Invalidations == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	result, _ = executor.ExecuteOperation(ctx, interfaces.Operation{Type: "get", Key: "k"})
+	if result.Value != "v2" {
+		t.Fatalf("expected fresh value v2 after invalidation, got %v", result.Value)
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 1 || stats.Misses != 2 {
+		t.Errorf("unexpected hit/miss counts: hits=%d misses=%d", stats.Hits, stats.Misses)
+	}
+	if stats.InvalidatedKeys == 0 {
+		t.Error("expected invalidated keys to be counted")
+	}
+	if stats.StaleWindowSamples == 0 {
+		t.Error("expected stale window samples")
+	}
+}
+
+func TestClientCache_FlushInvalidation(t *testing.T) {
+	cache := NewClientCache(0, false)
+	entry := cache.beginLoad("a")
+	cache.completeLoad("a", entry, "1", nil)
+
+	// null键列表表示整体失效
+	cache.HandlePush(&connection.RESP3Push{Kind: "invalidate", Data: []interface{}{nil}})
+
+	if _, hit := cache.Get("a"); hit {
+		t.Error("expected cache to be flushed")
+	}
+	if stats := cache.Stats(); stats.FlushInvalidations != 1 {
+		t.Errorf("expected 1 flush invalidation, got %d", stats.FlushInvalidations)
+	}
+
+	// 加载期间失效时丢弃加载结果
+	entry = cache.beginLoad("b")
+	cache.Invalidate([]string{"b"})
+	cache.completeLoad("b", entry, "stale", nil)
+	if _, hit := cache.Get("b"); hit {
+		t.Error("expected value loaded during invalidation to be discarded")
+	}
+}
@@ -0,0 +1,332 @@
+package operation
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// clusterSlots Redis Cluster哈希槽数量
+const clusterSlots = 16384
+
+// 热点统计的规模限制
+const (
+	maxTrackedKeys = 100000 // 记录访问次数的不同键上限，超出后新键只计入槽统计
+	topSlotsLimit  = 10
+	topKeysLimit   = 10
+)
+
+// keylessCommands 不携带键的命令，不计入槽与热点键统计
+var keylessCommands = map[string]bool{
+	"ping": true, "echo": true, "info": true, "script": true, "hello": true, "auth": true,
+	"select": true, "client": true, "config": true, "command": true, "dbsize": true,
+	"flushdb": true, "flushall": true, "time": true,
+}
+
+// NodeStats 单个集群节点的统计
+type NodeStats struct {
+	Addr     string  `json:"addr"`
+	Commands int64   `json:"commands"` // 由该节点处理的命令数（不含被重定向的请求）
+	Errors   int64   `json:"errors"`   // 不含MOVED/ASK的错误回复
+	Moved    int64   `json:"moved"`    // 该节点返回的MOVED重定向
+	Ask      int64   `json:"ask"`      // 该节点返回的ASK重定向
+	Share    float64 `json:"share"`    // 占全部命令的百分比
+}
+
+// SlotStats 单个哈希槽的访问统计
+type SlotStats struct {
+	Slot        int     `json:"slot"`
+	Node        string  `json:"node"` // 最近处理该槽命令的节点
+	Commands    int64   `json:"commands"`
+	Share       float64 `json:"share"`
+	HotKey      string  `json:"hot_key"` // 该槽内访问最多的键
+	HotKeyCount int64   `json:"hot_key_count"`
+}
+
+// KeyStats 单个键的访问统计
+type KeyStats struct {
+	Key      string `json:"key"`
+	Slot     int    `json:"slot"`
+	Commands int64  `json:"commands"`
+}
+
+// ClusterStats Redis Cluster按节点与按槽的统计
+type ClusterStats struct {
+	Nodes         []NodeStats `json:"nodes"`
+	Commands      int64       `json:"commands"`
+	Moved         int64       `json:"moved"`
+	Ask           int64       `json:"ask"`
+	RedirectRate  float64     `json:"redirect_rate"` // 重定向占全部请求（含被重定向的请求）的百分比
+	ActiveSlots   int         `json:"active_slots"`
+	TopSlots      []SlotStats `json:"top_slots"`
+	HotKeys       []KeyStats  `json:"hot_keys"`
+	KeysTruncated bool        `json:"keys_truncated"` // 不同键数量超过上限，热点键统计不完整
+}
+
+// nodeCounter 单个节点的计数器
+type nodeCounter struct {
+	commands atomic.Int64
+	errors   atomic.Int64
+	moved    atomic.Int64
+	ask      atomic.Int64
+}
+
+// ClusterTracker 通过节点客户端的hook统计集群命令的路由情况
+type ClusterTracker struct {
+	nodes sync.Map // addr -> *nodeCounter
+	slots [clusterSlots]atomic.Int64
+
+	mutex         sync.Mutex
+	slotNodes     map[int]string
+	keys          map[string]int64
+	keysTruncated bool
+}
+
+// NewClusterTracker 创建集群统计器
+func NewClusterTracker() *ClusterTracker {
+	return &ClusterTracker{
+		slotNodes: make(map[int]string),
+		keys:      make(map[string]int64),
+	}
+}
+
+// NodeHook 为指定地址的节点客户端创建hook，用于redis.ClusterOptions.NewClient
+func (t *ClusterTracker) NodeHook(addr string) redis.Hook {
+	return &clusterNodeHook{addr: addr, tracker: t}
+}
+
+// node 获取节点计数器
+func (t *ClusterTracker) node(addr string) *nodeCounter {
+	if counter, ok := t.nodes.Load(addr); ok {
+		return counter.(*nodeCounter)
+	}
+	counter, _ := t.nodes.LoadOrStore(addr, &nodeCounter{})
+	return counter.(*nodeCounter)
+}
+
+// record 记录节点对一条命令的处理结果
+func (t *ClusterTracker) record(addr string, cmd redis.Cmder) {
+	name := cmd.Name()
+	// 集群客户端内部的拓扑发现与ASKING命令
+	if name == "cluster" || name == "readonly" || name == "asking" {
+		return
+	}
+
+	counter := t.node(addr)
+	err := cmd.Err()
+	if err != nil && err != redis.Nil {
+		message := err.Error()
+		switch {
+		case strings.HasPrefix(message, "MOVED "):
+			counter.moved.Add(1)
+			return
+		case strings.HasPrefix(message, "ASK "):
+			counter.ask.Add(1)
+			return
+		}
+		counter.errors.Add(1)
+	}
+	counter.commands.Add(1)
+
+	key, ok := commandKey(cmd.Args())
+	if !ok {
+		return
+	}
+	slot := Slot(key)
+	t.slots[slot].Add(1)
+
+	t.mutex.Lock()
+	if t.slotNodes[slot] != addr {
+		t.slotNodes[slot] = addr
+	}
+	if _, exists := t.keys[key]; exists || len(t.keys) < maxTrackedKeys {
+		t.keys[key]++
+	} else {
+		t.keysTruncated = true
+	}
+	t.mutex.Unlock()
+}
+
+// commandKey 提取命令的第一个键，EVAL/EVALSHA取KEYS中的第一个
+func commandKey(args []interface{}) (string, bool) {
+	if len(args) < 2 {
+		return "", false
+	}
+	name := strings.ToLower(toString(args[0]))
+	if keylessCommands[name] {
+		return "", false
+	}
+	if name == "eval" || name == "evalsha" || name == "eval_ro" || name == "evalsha_ro" {
+		if len(args) < 4 {
+			return "", false
+		}
+		if numKeys, err := strconv.Atoi(toString(args[2])); err != nil || numKeys < 1 {
+			return "", false
+		}
+		return toString(args[3]), true
+	}
+	return toString(args[1]), true
+}
+
+// toString 将命令参数转换为字符串
+func toString(arg interface{}) string {
+	switch value := arg.(type) {
+	case string:
+		return value
+	case []byte:
+		return string(value)
+	case int:
+		return strconv.Itoa(value)
+	case int64:
+		return strconv.FormatInt(value, 10)
+	default:
+		return ""
+	}
+}
+
+// Stats 获取集群统计快照
+func (t *ClusterTracker) Stats() ClusterStats {
+	var stats ClusterStats
+	var requests int64
+	t.nodes.Range(func(addr, value interface{}) bool {
+		counter := value.(*nodeCounter)
+		node := NodeStats{
+			Addr:     addr.(string),
+			Commands: counter.commands.Load(),
+			Errors:   counter.errors.Load(),
+			Moved:    counter.moved.Load(),
+			Ask:      counter.ask.Load(),
+		}
+		stats.Nodes = append(stats.Nodes, node)
+		stats.Commands += node.Commands
+		stats.Moved += node.Moved
+		stats.Ask += node.Ask
+		return true
+	})
+	sort.Slice(stats.Nodes, func(i, j int) bool { return stats.Nodes[i].Addr < stats.Nodes[j].Addr })
+	for i := range stats.Nodes {
+		stats.Nodes[i].Share = percent(stats.Nodes[i].Commands, stats.Commands)
+	}
+	requests = stats.Commands + stats.Moved + stats.Ask
+	stats.RedirectRate = percent(stats.Moved+stats.Ask, requests)
+
+	// 按槽汇总
+	var slotTotal int64
+	for slot := range t.slots {
+		count := t.slots[slot].Load()
+		if count == 0 {
+			continue
+		}
+		stats.ActiveSlots++
+		slotTotal += count
+		stats.TopSlots = append(stats.TopSlots, SlotStats{Slot: slot, Commands: count})
+	}
+	sort.Slice(stats.TopSlots, func(i, j int) bool {
+		if stats.TopSlots[i].Commands != stats.TopSlots[j].Commands {
+			return stats.TopSlots[i].Commands > stats.TopSlots[j].Commands
+		}
+		return stats.TopSlots[i].Slot < stats.TopSlots[j].Slot
+	})
+	if len(stats.TopSlots) > topSlotsLimit {
+		stats.TopSlots = stats.TopSlots[:topSlotsLimit]
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	stats.KeysTruncated = t.keysTruncated
+
+	hotKeys := make(map[int]KeyStats, len(stats.TopSlots))
+	for key, count := range t.keys {
+		entry := KeyStats{Key: key, Slot: Slot(key), Commands: count}
+		stats.HotKeys = append(stats.HotKeys, entry)
+		if current, ok := hotKeys[entry.Slot]; !ok || count > current.Commands ||
+			(count == current.Commands && key < current.Key) {
+			hotKeys[entry.Slot] = entry
+		}
+	}
+	for i := range stats.TopSlots {
+		slot := &stats.TopSlots[i]
+		slot.Node = t.slotNodes[slot.Slot]
+		slot.Share = percent(slot.Commands, slotTotal)
+		if hot, ok := hotKeys[slot.Slot]; ok {
+			slot.HotKey = hot.Key
+			slot.HotKeyCount = hot.Commands
+		}
+	}
+
+	sort.Slice(stats.HotKeys, func(i, j int) bool {
+		if stats.HotKeys[i].Commands != stats.HotKeys[j].Commands {
+			return stats.HotKeys[i].Commands > stats.HotKeys[j].Commands
+		}
+		return stats.HotKeys[i].Key < stats.HotKeys[j].Key
+	})
+	if len(stats.HotKeys) > topKeysLimit {
+		stats.HotKeys = stats.HotKeys[:topKeysLimit]
+	}
+	return stats
+}
+
+// percent 计算百分比，分母为0时返回0
+func percent(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
+
+// clusterNodeHook 挂在单个节点客户端上的hook
+type clusterNodeHook struct {
+	addr    string
+	tracker *ClusterTracker
+}
+
+func (h *clusterNodeHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	return ctx, nil
+}
+
+func (h *clusterNodeHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	h.tracker.record(h.addr, cmd)
+	return nil
+}
+
+func (h *clusterNodeHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	return ctx, nil
+}
+
+func (h *clusterNodeHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	for _, cmd := range cmds {
+		h.tracker.record(h.addr, cmd)
+	}
+	return nil
+}
+
+// Slot 计算键所属的哈希槽（CRC16-XMODEM，支持{hashtag}）
+func Slot(key string) int {
+	if start := strings.IndexByte(key, '{'); start >= 0 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+	return int(crc16(key) % clusterSlots)
+}
+
+// crc16 CRC16-XMODEM（多项式0x1021）
+func crc16(data string) uint16 {
+	var crc uint16
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
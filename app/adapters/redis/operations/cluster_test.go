@@ -0,0 +1,76 @@
+package operation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-redis/redis/v8"
+)
+
+func TestSlot(t *testing.T) {
+	if slot := Slot("123456789"); slot != 12739 {
+		t.Fatalf("Slot(123456789) = %d, want 12739", slot)
+	}
+	if slot := Slot("foo"); slot != 12182 {
+		t.Fatalf("Slot(foo) = %d, want 12182", slot)
+	}
+	if Slot("{user:1}:name") != Slot("{user:1}:email") || Slot("{user:1}:name") != Slot("user:1") {
+		t.Fatalf("hashtag keys should map to the same slot")
+	}
+	// 空hashtag使用整个键
+	if Slot("{}foo") == Slot("") {
+		t.Fatalf("empty hashtag should hash the whole key")
+	}
+}
+
+func TestClusterTracker(t *testing.T) {
+	ctx := context.Background()
+	tracker := NewClusterTracker()
+	nodeA := tracker.NodeHook("127.0.0.1:7000")
+	nodeB := tracker.NodeHook("127.0.0.1:7001")
+
+	command := func(err error, args ...interface{}) redis.Cmder {
+		cmd := redis.NewStringCmd(ctx, args...)
+		if err != nil {
+			cmd.SetErr(err)
+		}
+		return cmd
+	}
+
+	// 节点A：3次hot、1次miss（redis.Nil不算错误）、1次MOVED、1次错误
+	for i := 0; i < 3; i++ {
+		nodeA.AfterProcess(ctx, command(nil, "get", "hot"))
+	}
+	nodeA.AfterProcess(ctx, command(redis.Nil, "get", "cold"))
+	nodeA.AfterProcess(ctx, command(errors.New("MOVED 3999 127.0.0.1:7001"), "get", "moved"))
+	nodeA.AfterProcess(ctx, command(errors.New("WRONGTYPE Operation against a key"), "get", "hot"))
+	// 节点B：pipeline中一次ASK，内部命令与无键命令不计入槽统计
+	nodeB.AfterProcessPipeline(ctx, []redis.Cmder{
+		command(nil, "set", "moved", "v"),
+		command(errors.New("ASK 3999 127.0.0.1:7000"), "get", "migrating"),
+		command(nil, "cluster", "slots"),
+		command(nil, "ping"),
+		command(nil, "evalsha", "abc", 1, "{hot}:x"),
+	})
+
+	stats := tracker.Stats()
+	if len(stats.Nodes) != 2 || stats.Commands != 8 || stats.Moved != 1 || stats.Ask != 1 {
+		t.Fatalf("unexpected totals: %+v", stats)
+	}
+	a, b := stats.Nodes[0], stats.Nodes[1]
+	if a.Commands != 5 || a.Errors != 1 || a.Moved != 1 || b.Commands != 3 || b.Ask != 1 {
+		t.Fatalf("unexpected node stats: %+v, %+v", a, b)
+	}
+	if stats.RedirectRate != 20 {
+		t.Fatalf("redirect rate = %.2f, want 20", stats.RedirectRate)
+	}
+
+	top := stats.TopSlots[0]
+	if top.Slot != Slot("hot") || top.Commands != 5 || top.HotKey != "hot" || top.HotKeyCount != 4 || top.Node != "127.0.0.1:7001" {
+		t.Fatalf("unexpected top slot: %+v", top)
+	}
+	if stats.ActiveSlots != 3 || stats.HotKeys[0].Key != "hot" || stats.HotKeys[0].Commands != 4 {
+		t.Fatalf("unexpected slot/key stats: %+v", stats)
+	}
+}
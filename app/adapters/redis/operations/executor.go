@@ -17,6 +17,10 @@ type RedisExecutor struct {
 	connectionPool   *connection.RedisConnectionPool
 	config           *redisConfig.RedisConfig
 	metricsCollector interfaces.DefaultMetricsCollector
+	pipelineTracker  *PipelineTracker
+	script           *LuaScript
+	verifier         *VerifyTracker
+	leaderboard      *LeaderboardTracker
 }
 
 // NewRedisExecutor 创建Redis操作执行器
@@ -29,9 +33,30 @@ func NewRedisExecutor(
 		connectionPool:   connectionPool,
 		config:           config,
 		metricsCollector: metricsCollector,
+		pipelineTracker:  NewPipelineTracker(),
 	}
 }
 
+// PipelineStats 获取pipeline模式的批次与命令统计
+func (r *RedisExecutor) PipelineStats() PipelineStats {
+	return r.pipelineTracker.Stats()
+}
+
+// SetScript 设置eval操作执行的Lua脚本
+func (r *RedisExecutor) SetScript(script *LuaScript) {
+	r.script = script
+}
+
+// SetVerifier 设置数据校验器，SET/HSET写入带校验值，GET/HGET校验读取结果
+func (r *RedisExecutor) SetVerifier(verifier *VerifyTracker) {
+	r.verifier = verifier
+}
+
+// SetLeaderboard 设置排行榜负载统计器，分数更新与排名查询的延迟分别记录
+func (r *RedisExecutor) SetLeaderboard(leaderboard *LeaderboardTracker) {
+	r.leaderboard = leaderboard
+}
+
 // ExecuteOperation 执行Redis操作 - 统一操作入口
 func (r *RedisExecutor) ExecuteOperation(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
 	startTime := time.Now()
@@ -48,68 +73,161 @@ func (r *RedisExecutor) ExecuteOperation(ctx context.Context, operation interfac
 		return result, result.Error
 	}
 
+	// pipeline模式：一批命令作为一个操作
+	if operation.Type == "pipeline" {
+		return r.executePipeline(ctx, client, operation, startTime)
+	}
+
 	var opErr error
+	result.Value, opErr = r.executeCommand(ctx, client, operation)
+	if opErr == nil {
+		r.verifier.CheckRead(operation, result.Value)
+	}
+
+	result.Success = opErr == nil
+	result.Error = opErr
+	result.Duration = time.Since(startTime)
+	r.leaderboard.Record(operation, result.Value, result.Duration, opErr)
+	if opErr == nil {
+		result.BytesWritten = writtenPayload(operation)
+		if result.IsRead {
+			result.BytesRead = readPayload(result.Value)
+		}
+	}
+
+	// 添加操作特定元数据
+	for k, v := range operation.Metadata {
+		result.Metadata[k] = v
+	}
+	result.Metadata["operation_type"] = operation.Type
+	result.Metadata["key"] = operation.Key
+
+	return result, opErr
+}
+
+// executeCommand 按操作类型执行单条命令，client为Pipeliner时命令仅进入队列
+func (r *RedisExecutor) executeCommand(ctx context.Context, client redis.Cmdable, operation interfaces.Operation) (interface{}, error) {
 	switch operation.Type {
 	case "get":
-		result.Value, opErr = r.executeGet(ctx, client, operation)
+		return r.executeGet(ctx, client, operation)
 	case "set":
-		opErr = r.executeSet(ctx, client, operation)
+		return nil, r.executeSet(ctx, client, operation)
 	case "del":
-		result.Value, opErr = r.executeDelete(ctx, client, operation)
+		return r.executeDelete(ctx, client, operation)
 	case "incr":
-		result.Value, opErr = r.executeIncr(ctx, client, operation)
+		return r.executeIncr(ctx, client, operation)
 	case "decr":
-		result.Value, opErr = r.executeDecr(ctx, client, operation)
+		return r.executeDecr(ctx, client, operation)
 	case "hget":
-		result.Value, opErr = r.executeHGet(ctx, client, operation)
+		return r.executeHGet(ctx, client, operation)
 	case "hset":
-		opErr = r.executeHSet(ctx, client, operation)
+		return nil, r.executeHSet(ctx, client, operation)
 	case "hgetall":
-		result.Value, opErr = r.executeHGetAll(ctx, client, operation)
+		return r.executeHGetAll(ctx, client, operation)
 	case "lpush":
-		result.Value, opErr = r.executeLPush(ctx, client, operation)
+		return r.executeLPush(ctx, client, operation)
 	case "rpush":
-		result.Value, opErr = r.executeRPush(ctx, client, operation)
+		return r.executeRPush(ctx, client, operation)
 	case "lpop":
-		result.Value, opErr = r.executeLPop(ctx, client, operation)
+		return r.executeLPop(ctx, client, operation)
 	case "rpop":
-		result.Value, opErr = r.executeRPop(ctx, client, operation)
+		return r.executeRPop(ctx, client, operation)
 	case "sadd":
-		result.Value, opErr = r.executeSAdd(ctx, client, operation)
+		return r.executeSAdd(ctx, client, operation)
 	case "smembers":
-		result.Value, opErr = r.executeSMembers(ctx, client, operation)
+		return r.executeSMembers(ctx, client, operation)
 	case "srem":
-		result.Value, opErr = r.executeSRem(ctx, client, operation)
+		return r.executeSRem(ctx, client, operation)
 	case "sismember":
-		result.Value, opErr = r.executeSIsMember(ctx, client, operation)
+		return r.executeSIsMember(ctx, client, operation)
 	case "zadd":
-		result.Value, opErr = r.executeZAdd(ctx, client, operation)
+		return r.executeZAdd(ctx, client, operation)
 	case "zrange":
-		result.Value, opErr = r.executeZRange(ctx, client, operation)
+		return r.executeZRange(ctx, client, operation)
 	case "zrem":
-		result.Value, opErr = r.executeZRem(ctx, client, operation)
+		return r.executeZRem(ctx, client, operation)
 	case "zrank":
-		result.Value, opErr = r.executeZRank(ctx, client, operation)
+		return r.executeZRank(ctx, client, operation)
+	case "zincrby":
+		return r.executeZIncrBy(ctx, client, operation)
+	case "zrevrange":
+		return r.executeZRevRange(ctx, client, operation)
 	case "publish":
-		result.Value, opErr = r.executePublish(ctx, client, operation)
+		return r.executePublish(ctx, client, operation)
 	case "subscribe":
-		result.Value, opErr = r.executeSubscribe(ctx, client, operation)
+		return r.executeSubscribe(ctx, client, operation)
+	case "eval":
+		return r.executeEval(ctx, client, operation)
 	default:
-		opErr = fmt.Errorf("unsupported operation type: %s", operation.Type)
+		return nil, fmt.Errorf("unsupported operation type: %s", operation.Type)
 	}
+}
 
-	result.Success = opErr == nil
-	result.Error = opErr
-	result.Duration = time.Since(startTime)
-
-	// 添加操作特定元数据
+// executePipeline 通过go-redis Pipeliner在一次往返中执行一批命令
+// 操作耗时为整批往返时间；键不存在（redis.Nil）不计为失败，任一命令失败时整批视为失败
+func (r *RedisExecutor) executePipeline(ctx context.Context, client redis.UniversalClient, operation interfaces.Operation, startTime time.Time) (*interfaces.OperationResult, error) {
+	commands, _ := operation.Params["commands"].([]interfaces.Operation)
+	result := &interfaces.OperationResult{
+		Metadata: make(map[string]interface{}),
+	}
 	for k, v := range operation.Metadata {
 		result.Metadata[k] = v
 	}
 	result.Metadata["operation_type"] = operation.Type
 	result.Metadata["key"] = operation.Key
+	result.Metadata["commands"] = len(commands)
 
-	return result, opErr
+	if len(commands) == 0 {
+		result.Error = fmt.Errorf("pipeline operation has no commands")
+		result.Duration = time.Since(startTime)
+		return result, result.Error
+	}
+
+	pipe := client.Pipeline()
+	var failed, reads int
+	var firstErr error
+	for _, command := range commands {
+		if r.isReadOperation(command.Type) {
+			reads++
+		}
+		result.BytesWritten += writtenPayload(command)
+		// 参数无效的命令不会进入队列，直接计为失败
+		if _, err := r.executeCommand(ctx, pipe, command); err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	// Exec返回首个失败命令的错误（可能只是redis.Nil），因此逐条检查结果
+	cmds, _ := pipe.Exec(ctx)
+	result.Duration = time.Since(startTime)
+	for _, cmd := range cmds {
+		r.verifier.CheckCmd(cmd)
+		result.BytesRead += cmdPayload(cmd)
+		if err := cmd.Err(); err != nil && err != redis.Nil {
+			failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	r.pipelineTracker.RecordBatch(len(commands), failed, result.Duration)
+
+	// 整批均为读命令时计为读操作
+	result.IsRead = reads == len(commands)
+	result.Success = failed == 0
+	result.Metadata["failed_commands"] = failed
+	result.Value = map[string]interface{}{
+		"commands":        len(commands),
+		"failed_commands": failed,
+	}
+	if failed > 0 {
+		result.Error = fmt.Errorf("%d of %d pipelined commands failed: %w", failed, len(commands), firstErr)
+	}
+
+	return result, result.Error
 }
 
 // 具体操作实现方法
@@ -130,6 +248,9 @@ func (r *RedisExecutor) executeSet(ctx context.Context, client redis.Cmdable, op
 	if !ok {
 		return fmt.Errorf("invalid value type for SET operation: expected string")
 	}
+	if r.verifier != nil {
+		valueStr = r.verifier.Seal(operation.Key, len(valueStr))
+	}
 
 	cmd := client.Set(ctx, operation.Key, valueStr, operation.TTL)
 	return cmd.Err()
@@ -182,6 +303,9 @@ func (r *RedisExecutor) executeHSet(ctx context.Context, client redis.Cmdable, o
 	if !ok {
 		return fmt.Errorf("invalid value type for HSET operation: expected string")
 	}
+	if r.verifier != nil {
+		valueStr = r.verifier.Seal(operation.Key+"/"+field, len(valueStr))
+	}
 
 	cmd := client.HSet(ctx, operation.Key, field, valueStr)
 	return cmd.Err()
@@ -342,6 +466,45 @@ func (r *RedisExecutor) executeZRank(ctx context.Context, client redis.Cmdable,
 	return rank, err
 }
 
+// executeZIncrBy 执行ZINCRBY操作
+func (r *RedisExecutor) executeZIncrBy(ctx context.Context, client redis.Cmdable, operation interfaces.Operation) (interface{}, error) {
+	increment, ok := operation.Params["increment"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("increment parameter is required for ZINCRBY operation")
+	}
+
+	valueStr, ok := operation.Value.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid value type for ZINCRBY operation: expected string")
+	}
+
+	cmd := client.ZIncrBy(ctx, operation.Key, increment, valueStr)
+	score, err := cmd.Result()
+	return score, err
+}
+
+// executeZRevRange 执行ZREVRANGE操作（按分数从高到低），withscores参数为true时返回[]redis.Z
+func (r *RedisExecutor) executeZRevRange(ctx context.Context, client redis.Cmdable, operation interfaces.Operation) (interface{}, error) {
+	start, ok := operation.Params["start"].(int64)
+	if !ok {
+		start = 0
+	}
+
+	stop, ok := operation.Params["stop"].(int64)
+	if !ok {
+		stop = -1
+	}
+
+	if withScores, _ := operation.Params["withscores"].(bool); withScores {
+		cmd := client.ZRevRangeWithScores(ctx, operation.Key, start, stop)
+		entries, err := cmd.Result()
+		return entries, err
+	}
+	cmd := client.ZRevRange(ctx, operation.Key, start, stop)
+	members, err := cmd.Result()
+	return members, err
+}
+
 // executePublish 执行PUBLISH操作
 func (r *RedisExecutor) executePublish(ctx context.Context, client redis.Cmdable, operation interfaces.Operation) (interface{}, error) {
 	channel := operation.Key
@@ -362,8 +525,24 @@ func (r *RedisExecutor) executeSubscribe(ctx context.Context, client redis.Cmdab
 	return fmt.Sprintf("subscribed to channel: %s", operation.Key), nil
 }
 
+// executeEval 执行Lua脚本（EVALSHA或EVAL）
+func (r *RedisExecutor) executeEval(ctx context.Context, client redis.Cmdable, operation interfaces.Operation) (interface{}, error) {
+	if r.script == nil {
+		return nil, fmt.Errorf("no Lua script loaded for EVAL operation")
+	}
+
+	keys, _ := operation.Params["keys"].([]string)
+	args, _ := operation.Params["args"].([]interface{})
+	return r.script.Run(ctx, client, keys, args...)
+}
+
 // isReadOperation 判断是否为读操作
 func (r *RedisExecutor) isReadOperation(operationType string) bool {
+	// 脚本是否只读由配置决定
+	if operationType == "eval" {
+		return r.config.Script.ReadOnly
+	}
+
 	readOperations := map[string]bool{
 		"get":       true,
 		"hget":      true,
@@ -374,6 +553,7 @@ func (r *RedisExecutor) isReadOperation(operationType string) bool {
 		"sismember": true,
 		"zrange":    true,
 		"zrank":     true,
+		"zrevrange": true,
 		"subscribe": true,
 		// 写操作
 		"set":     false,
@@ -386,6 +566,7 @@ func (r *RedisExecutor) isReadOperation(operationType string) bool {
 		"sadd":    false,
 		"srem":    false,
 		"zadd":    false,
+		"zincrby": false,
 		"zrem":    false,
 		"publish": false,
 	}
@@ -402,5 +583,6 @@ func (r *RedisExecutor) GetSupportedOperations() []string {
 		"sadd", "srem", "smembers", "sismember",
 		"zadd", "zrem", "zrange", "zrank",
 		"publish", "subscribe",
+		"eval",
 	}
 }
@@ -3,46 +3,126 @@ package operation
 import (
 	"fmt"
 
+	redisConfig "abc-runner/app/adapters/redis/config"
 	"abc-runner/app/core/execution"
 	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/utils"
 )
 
 // OperationFactory Redis操作工厂
 type OperationFactory struct {
 	config interfaces.Config
+	script *redisConfig.ScriptConfig // 启用Lua脚本时所有命令均为eval
+	keys   utils.KeyDistribution     // random_keys键空间内的访问分布
+	value  *utils.PayloadTemplate    // 写入值模板，未配置时按data_size生成
+
+	leaderboard *leaderboardWorkload // case为leaderboard时生成排行榜命令
 }
 
 // NewOperationFactory 创建Redis操作工厂
 func NewOperationFactory(config interfaces.Config) execution.OperationFactory {
-	return &OperationFactory{config: config}
+	factory := &OperationFactory{config: config}
+	if cfg, ok := config.(*redisConfig.RedisConfig); ok {
+		if cfg.Script.Enabled() {
+			factory.script = &cfg.Script
+		}
+		// 分布配置无效时退回按random_keys顺序循环
+		if keys, err := utils.NewKeyDistribution(cfg.BenchMark.KeyDistribution, cfg.BenchMark.RandomKeys); err == nil {
+			factory.keys = keys
+		}
+		// 排行榜配置在连接前已验证
+		if cfg.BenchMark.IsLeaderboard() {
+			if leaderboard, err := newLeaderboardWorkload(cfg.BenchMark.Leaderboard); err == nil {
+				factory.leaderboard = leaderboard
+			}
+		}
+		// 模板在解析参数时已验证
+		if cfg.BenchMark.Payload.Enabled() {
+			if value, err := cfg.BenchMark.Payload.Compile(); err == nil {
+				factory.value = value
+			}
+		}
+	}
+	return factory
 }
 
 func (r *OperationFactory) CreateOperation(jobID int, benchmarkConfig execution.BenchmarkConfig) interfaces.Operation {
 	benchmark := r.config.GetBenchmark()
 
-	// 根据读写比例决定操作类型
-	isRead := (jobID % 100) < benchmark.GetReadPercent()
+	// 启用pipeline时每个任务为一批命令，命令编号在所有批次间连续
+	if pipelined, ok := benchmark.(interface{ GetPipeline() int }); ok && pipelined.GetPipeline() > 1 {
+		return r.createPipelineOperation(jobID, pipelined.GetPipeline(), benchmark)
+	}
 
-	var opType string
-	var key, value string
+	return r.createCommand(jobID, benchmark)
+}
 
-	// 生成键
-	if benchmark.GetRandomKeys() > 0 {
-		key = fmt.Sprintf("key_%d", jobID%benchmark.GetRandomKeys())
-	} else {
-		key = fmt.Sprintf("key_%d", jobID)
+// CreatePrepareOperation 创建预填充操作：以data_size大小的值写入key_<index>
+// 与random_keys键空间的键名一致，预填充random_keys个键即可覆盖全部读取
+// 排行榜负载时为各排行榜写入成员的初始分数
+func (r *OperationFactory) CreatePrepareOperation(index int) interfaces.Operation {
+	if r.leaderboard != nil {
+		return r.leaderboard.createPrepare(index)
+	}
+	benchmark := r.config.GetBenchmark()
+	return interfaces.Operation{
+		Type:  "set",
+		Key:   fmt.Sprintf("key_%d", index),
+		Value: r.generateValue(index, benchmark),
+		TTL:   benchmark.GetTTL(),
+		Params: map[string]interface{}{
+			"operation_type": "set",
+			"job_id":         index,
+			"is_read":        false,
+			"prefill":        true,
+		},
+	}
+}
+
+// createPipelineOperation 创建一批pipeline命令，最后一批按剩余命令数截断
+func (r *OperationFactory) createPipelineOperation(jobID, size int, benchmark interfaces.BenchmarkConfig) interfaces.Operation {
+	first := jobID * size
+	if remaining := benchmark.GetTotal() - first; remaining > 0 && remaining < size {
+		size = remaining
+	}
+
+	commands := make([]interfaces.Operation, size)
+	for i := range commands {
+		commands[i] = r.createCommand(first+i, benchmark)
 	}
 
+	return interfaces.Operation{
+		Type: "pipeline",
+		Key:  commands[0].Key,
+		Params: map[string]interface{}{
+			"operation_type": "pipeline",
+			"job_id":         jobID,
+			"commands":       commands,
+		},
+	}
+}
+
+// createCommand 按读写比例创建单条命令
+func (r *OperationFactory) createCommand(commandID int, benchmark interfaces.BenchmarkConfig) interfaces.Operation {
+	if r.script != nil {
+		return r.createScriptCommand(commandID, benchmark)
+	}
+	if r.leaderboard != nil {
+		return r.leaderboard.create(commandID, benchmark.GetReadPercent())
+	}
+
+	// 根据读写比例决定操作类型
+	isRead := (commandID % 100) < benchmark.GetReadPercent()
+
+	var opType string
+	var value string
+	key := r.generateKey(commandID, benchmark)
+
 	if isRead {
 		opType = "get"
 	} else {
 		opType = "set"
-		// 生成指定大小的值
-		dataSize := benchmark.GetDataSize()
-		if dataSize <= 0 {
-			dataSize = 64
-		}
-		value = generateRandomValue(dataSize)
+		value = r.generateValue(commandID, benchmark)
 	}
 
 	operation := interfaces.Operation{
@@ -52,7 +132,7 @@ func (r *OperationFactory) CreateOperation(jobID int, benchmarkConfig execution.
 		TTL:   benchmark.GetTTL(),
 		Params: map[string]interface{}{
 			"operation_type": opType,
-			"job_id":         jobID,
+			"job_id":         commandID,
 			"is_read":        isRead,
 		},
 	}
@@ -60,6 +140,64 @@ func (r *OperationFactory) CreateOperation(jobID int, benchmarkConfig execution.
 	return operation
 }
 
+// createScriptCommand 按KEYS/ARGV模板创建一次Lua脚本调用
+func (r *OperationFactory) createScriptCommand(commandID int, benchmark interfaces.BenchmarkConfig) interfaces.Operation {
+	key := r.generateKey(commandID, benchmark)
+	value := func() string { return r.generateValue(commandID, benchmark) }
+
+	keys := renderScriptTemplates(r.script.Keys, commandID, key, value)
+	rendered := renderScriptTemplates(r.script.Args, commandID, key, value)
+	args := make([]interface{}, len(rendered))
+	for i, arg := range rendered {
+		args[i] = arg
+	}
+
+	// 集群模式按首个KEY路由
+	if len(keys) > 0 {
+		key = keys[0]
+	}
+
+	return interfaces.Operation{
+		Type: "eval",
+		Key:  key,
+		Params: map[string]interface{}{
+			"operation_type": "eval",
+			"job_id":         commandID,
+			"is_read":        r.script.ReadOnly,
+			"keys":           keys,
+			"args":           args,
+		},
+	}
+}
+
+// generateKey 生成键，random_keys大于0时按配置的访问分布在键空间内取键
+func (r *OperationFactory) generateKey(commandID int, benchmark interfaces.BenchmarkConfig) string {
+	if r.keys != nil {
+		return fmt.Sprintf("key_%d", r.keys.Next(commandID))
+	}
+	if benchmark.GetRandomKeys() > 0 {
+		return fmt.Sprintf("key_%d", commandID%benchmark.GetRandomKeys())
+	}
+	return fmt.Sprintf("key_%d", commandID)
+}
+
+// generateValue 生成写入值：配置了模板时按命令编号渲染，否则按data_size生成
+func (r *OperationFactory) generateValue(commandID int, benchmark interfaces.BenchmarkConfig) string {
+	if r.value != nil {
+		return r.value.Render(commandID)
+	}
+	return generateDataValue(benchmark)
+}
+
+// generateDataValue 按data_size生成写入值
+func generateDataValue(benchmark interfaces.BenchmarkConfig) string {
+	dataSize := benchmark.GetDataSize()
+	if dataSize <= 0 {
+		dataSize = 64
+	}
+	return generateRandomValue(dataSize)
+}
+
 // generateRandomValue 生成指定大小的随机值
 func generateRandomValue(size int) string {
 	if size <= 0 {
@@ -76,3 +214,6 @@ func generateRandomValue(size int) string {
 
 	return string(result)
 }
+
+// 确保支持预填充阶段
+var _ execution.PrepareFactory = (*OperationFactory)(nil)
@@ -0,0 +1,304 @@
+package operation
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	redisConfig "abc-runner/app/adapters/redis/config"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// MasterSwitch 哨兵发布的一次主节点切换（+switch-master）
+type MasterSwitch struct {
+	At   time.Duration `json:"at"` // 距跟踪开始的时间
+	From string        `json:"from"`
+	To   string        `json:"to"`
+}
+
+// FailoverStats 故障转移期间的客户端表现
+type FailoverStats struct {
+	MasterName    string         `json:"master_name"`
+	InitialMaster string         `json:"initial_master"`
+	FinalMaster   string         `json:"final_master"`
+	Triggered     bool           `json:"triggered"`              // 是否由本次运行发起SENTINEL FAILOVER
+	TriggeredAt   time.Duration  `json:"triggered_at,omitempty"` // 发起时间，距跟踪开始
+	TriggerError  string         `json:"trigger_error,omitempty"`
+	Switches      []MasterSwitch `json:"switches"`
+	Connections   int64          `json:"connections"` // 跟踪期间新建的连接数
+	Reconnects    int64          `json:"reconnects"`  // 故障转移开始（发起、主节点切换或首个错误）之后新建的连接数
+	Commands      int64          `json:"commands"`
+	Errors        int64          `json:"errors"`          // 故障窗口内（首个错误起）失败的命令数
+	ErrorSpike    time.Duration  `json:"error_spike"`     // 首个错误到最后一个错误的时长
+	Recovered     bool           `json:"recovered"`       // 最后一个错误之后是否有命令成功，无错误时为true
+	TimeToRecover time.Duration  `json:"time_to_recover"` // 发起故障转移（或首个错误）到恢复成功
+}
+
+// FailoverTracker 跟踪哨兵模式下的故障转移：记录主节点切换、重连次数、错误窗口与恢复时间
+// 通过go-redis hook观察每条命令的结果，通过OnConnect统计新建连接
+type FailoverTracker struct {
+	config     redisConfig.SentinelInfo
+	tlsConfig  *tls.Config
+	sentinel   *redis.SentinelClient
+	pubsub     *redis.PubSub
+	armed      atomic.Bool
+	disrupted  atomic.Bool // 故障转移已开始
+	hasErrored atomic.Bool
+	commands   atomic.Int64
+	connects   atomic.Int64
+	reconnects atomic.Int64
+	errorCount atomic.Int64
+
+	mutex         sync.Mutex
+	start         time.Time
+	timer         *time.Timer
+	initialMaster string
+	currentMaster string
+	triggeredAt   time.Time
+	triggerErr    error
+	switches      []MasterSwitch
+	firstError    time.Time
+	lastError     time.Time
+	recoveredAt   time.Time
+}
+
+// NewFailoverTracker 创建故障转移跟踪器，tlsConfig非nil时以TLS连接哨兵
+func NewFailoverTracker(config redisConfig.SentinelInfo, tlsConfig *tls.Config) *FailoverTracker {
+	return &FailoverTracker{config: config, tlsConfig: tlsConfig}
+}
+
+// Connect 连接首个可用的哨兵，读取当前主节点并订阅+switch-master事件
+func (f *FailoverTracker) Connect(ctx context.Context, dialTimeout time.Duration) error {
+	var lastErr error
+	for _, addr := range f.config.Addrs {
+		sentinel := redis.NewSentinelClient(&redis.Options{
+			Addr:        addr,
+			Password:    f.config.SentinelPassword,
+			DialTimeout: dialTimeout,
+			TLSConfig:   f.tlsConfig,
+		})
+		master, err := sentinel.GetMasterAddrByName(ctx, f.config.MasterName).Result()
+		if err != nil {
+			sentinel.Close()
+			lastErr = fmt.Errorf("sentinel %s: %w", addr, err)
+			continue
+		}
+
+		pubsub := sentinel.Subscribe(ctx, "+switch-master")
+		if _, err := pubsub.Receive(ctx); err != nil {
+			pubsub.Close()
+			sentinel.Close()
+			lastErr = fmt.Errorf("sentinel %s: failed to subscribe to +switch-master: %w", addr, err)
+			continue
+		}
+
+		f.sentinel = sentinel
+		f.pubsub = pubsub
+		f.initialMaster = net.JoinHostPort(master[0], master[1])
+		f.currentMaster = f.initialMaster
+		go f.watch(pubsub.Channel())
+		return nil
+	}
+	return fmt.Errorf("failed to reach any sentinel for master %s: %w", f.config.MasterName, lastErr)
+}
+
+// watch 处理哨兵的主节点切换事件："<master-name> <old-ip> <old-port> <new-ip> <new-port>"
+func (f *FailoverTracker) watch(messages <-chan *redis.Message) {
+	for msg := range messages {
+		fields := strings.Fields(msg.Payload)
+		if len(fields) != 5 || fields[0] != f.config.MasterName {
+			continue
+		}
+		now := time.Now()
+		f.mutex.Lock()
+		f.switches = append(f.switches, MasterSwitch{
+			At:   f.since(now),
+			From: net.JoinHostPort(fields[1], fields[2]),
+			To:   net.JoinHostPort(fields[3], fields[4]),
+		})
+		f.currentMaster = net.JoinHostPort(fields[3], fields[4])
+		f.mutex.Unlock()
+		f.disrupted.Store(true)
+	}
+}
+
+// Arm 开始跟踪（通常在测量开始时调用），after>0时在after之后通过哨兵强制故障转移
+func (f *FailoverTracker) Arm(after time.Duration) {
+	f.mutex.Lock()
+	f.start = time.Now()
+	if after > 0 {
+		f.timer = time.AfterFunc(after, f.trigger)
+	}
+	f.mutex.Unlock()
+	f.armed.Store(true)
+}
+
+// trigger 发送SENTINEL FAILOVER，强制将一个副本提升为主节点
+func (f *FailoverTracker) trigger() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	now := time.Now()
+	f.disrupted.Store(true)
+	err := f.sentinel.Failover(ctx, f.config.MasterName).Err()
+
+	f.mutex.Lock()
+	f.triggeredAt = now
+	f.triggerErr = err
+	f.mutex.Unlock()
+}
+
+// OnConnect go-redis新建连接回调
+func (f *FailoverTracker) OnConnect(ctx context.Context, cn *redis.Conn) error {
+	if !f.armed.Load() {
+		return nil
+	}
+	f.connects.Add(1)
+	if f.disrupted.Load() {
+		f.reconnects.Add(1)
+	}
+	return nil
+}
+
+// observe 记录一条命令的结果；redis.Nil等业务回复不视为错误
+func (f *FailoverTracker) observe(err error) {
+	if !f.armed.Load() {
+		return
+	}
+	f.commands.Add(1)
+	now := time.Now()
+
+	if err != nil && err != redis.Nil && !errors.Is(err, context.Canceled) {
+		if _, isReply := err.(redis.Error); isReply && !isFailoverReply(err) {
+			return
+		}
+		f.mutex.Lock()
+		// 已发起故障转移时，只统计之后的错误
+		if f.triggeredAt.IsZero() && f.timer != nil {
+			f.mutex.Unlock()
+			return
+		}
+		if f.firstError.IsZero() {
+			f.firstError = now
+		}
+		f.lastError = now
+		f.recoveredAt = time.Time{}
+		f.mutex.Unlock()
+		f.errorCount.Add(1)
+		f.hasErrored.Store(true)
+		f.disrupted.Store(true)
+		return
+	}
+
+	if f.hasErrored.Load() {
+		f.mutex.Lock()
+		if f.recoveredAt.IsZero() {
+			f.recoveredAt = now
+		}
+		f.mutex.Unlock()
+	}
+}
+
+// isFailoverReply 故障转移过程中服务端返回的错误回复（只读副本、主节点不可用等）
+func isFailoverReply(err error) bool {
+	message := err.Error()
+	for _, prefix := range []string{"READONLY", "LOADING", "MASTERDOWN", "TRYAGAIN", "CLUSTERDOWN"} {
+		if strings.HasPrefix(message, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// BeforeProcess 实现redis.Hook
+func (f *FailoverTracker) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	return ctx, nil
+}
+
+// AfterProcess 实现redis.Hook
+func (f *FailoverTracker) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	f.observe(cmd.Err())
+	return nil
+}
+
+// BeforeProcessPipeline 实现redis.Hook
+func (f *FailoverTracker) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	return ctx, nil
+}
+
+// AfterProcessPipeline 实现redis.Hook
+func (f *FailoverTracker) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	for _, cmd := range cmds {
+		f.observe(cmd.Err())
+	}
+	return nil
+}
+
+// since 距跟踪开始的时间
+func (f *FailoverTracker) since(t time.Time) time.Duration {
+	if f.start.IsZero() || t.Before(f.start) {
+		return 0
+	}
+	return t.Sub(f.start)
+}
+
+// Stats 获取故障转移统计
+func (f *FailoverTracker) Stats() FailoverStats {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	stats := FailoverStats{
+		MasterName:    f.config.MasterName,
+		InitialMaster: f.initialMaster,
+		FinalMaster:   f.currentMaster,
+		Triggered:     !f.triggeredAt.IsZero(),
+		Switches:      append([]MasterSwitch{}, f.switches...),
+		Connections:   f.connects.Load(),
+		Reconnects:    f.reconnects.Load(),
+		Commands:      f.commands.Load(),
+		Errors:        f.errorCount.Load(),
+	}
+	if stats.Triggered {
+		stats.TriggeredAt = f.since(f.triggeredAt)
+	}
+	if f.triggerErr != nil {
+		stats.TriggerError = f.triggerErr.Error()
+	}
+
+	stats.Recovered = f.firstError.IsZero()
+	if !f.firstError.IsZero() {
+		stats.ErrorSpike = f.lastError.Sub(f.firstError)
+		stats.Recovered = !f.recoveredAt.IsZero()
+		if stats.Recovered {
+			from := f.firstError
+			if stats.Triggered {
+				from = f.triggeredAt
+			}
+			stats.TimeToRecover = f.recoveredAt.Sub(from)
+		}
+	}
+	return stats
+}
+
+// Close 停止跟踪并关闭哨兵连接
+func (f *FailoverTracker) Close() error {
+	f.mutex.Lock()
+	if f.timer != nil {
+		f.timer.Stop()
+	}
+	f.mutex.Unlock()
+
+	if f.pubsub != nil {
+		f.pubsub.Close()
+	}
+	if f.sentinel != nil {
+		return f.sentinel.Close()
+	}
+	return nil
+}
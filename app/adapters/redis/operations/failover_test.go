@@ -0,0 +1,141 @@
+package operation
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	redisConfig "abc-runner/app/adapters/redis/config"
+	"abc-runner/app/adapters/redis/connection"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// fakeSentinel 最小化的哨兵：应答get-master-addr-by-name，收到SENTINEL FAILOVER后
+// 切换主节点并向+switch-master订阅者推送事件
+func fakeSentinel(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	var mutex sync.Mutex
+	master := "6379"
+	var subscribers []net.Conn
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					value, err := connection.ReadRESP3(reader)
+					if err != nil {
+						return
+					}
+					args, _ := value.([]interface{})
+					if len(args) == 0 {
+						continue
+					}
+					command := strings.ToUpper(args[0].(string))
+					if command == "SENTINEL" {
+						command += " " + strings.ToUpper(args[1].(string))
+					}
+
+					mutex.Lock()
+					switch command {
+					case "SENTINEL GET-MASTER-ADDR-BY-NAME":
+						conn.Write([]byte("*2\r\n$9\r\n127.0.0.1\r\n$4\r\n" + master + "\r\n"))
+					case "SUBSCRIBE":
+						subscribers = append(subscribers, conn)
+						conn.Write([]byte("*3\r\n$9\r\nsubscribe\r\n$14\r\n+switch-master\r\n:1\r\n"))
+					case "SENTINEL FAILOVER":
+						event := "mymaster 127.0.0.1 " + master + " 127.0.0.1 6380"
+						master = "6380"
+						conn.Write([]byte("+OK\r\n"))
+						for _, sub := range subscribers {
+							sub.Write([]byte("*3\r\n$7\r\nmessage\r\n$14\r\n+switch-master\r\n$" +
+								strconv.Itoa(len(event)) + "\r\n" + event + "\r\n"))
+						}
+					default:
+						conn.Write([]byte("-ERR unknown command\r\n"))
+					}
+					mutex.Unlock()
+				}
+			}(conn)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+// replyError 服务端错误回复
+type replyError string
+
+func (e replyError) Error() string { return string(e) }
+
+func (replyError) RedisError() {}
+
+func TestFailoverTracker(t *testing.T) {
+	tracker := NewFailoverTracker(redisConfig.SentinelInfo{
+		MasterName: "mymaster",
+		Addrs:      []string{"127.0.0.1:1", fakeSentinel(t)},
+	}, nil)
+	ctx := context.Background()
+	if err := tracker.Connect(ctx, time.Second); err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer tracker.Close()
+
+	command := func(err error) {
+		cmd := redis.NewStatusCmd(ctx, "set", "k", "v")
+		cmd.SetErr(err)
+		tracker.AfterProcess(ctx, cmd)
+	}
+	dropped := errors.New("connection reset by peer")
+
+	// 跟踪开始前与发起故障转移前的错误均不计入
+	command(dropped)
+	tracker.Arm(50 * time.Millisecond)
+	tracker.OnConnect(ctx, nil)
+	command(dropped)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(tracker.Stats().Switches) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("no +switch-master event received")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	command(dropped)
+	time.Sleep(20 * time.Millisecond)
+	command(replyError("READONLY You can't write against a read only replica."))
+	command(replyError("WRONGTYPE Operation against a key holding the wrong kind of value"))
+	tracker.OnConnect(ctx, nil)
+	command(redis.Nil)
+	command(nil)
+
+	stats := tracker.Stats()
+	if !stats.Triggered || stats.TriggerError != "" || stats.InitialMaster != "127.0.0.1:6379" || stats.FinalMaster != "127.0.0.1:6380" {
+		t.Fatalf("unexpected failover stats: %+v", stats)
+	}
+	if stats.Errors != 2 || stats.Connections != 2 || stats.Reconnects != 1 || stats.Commands != 6 {
+		t.Errorf("unexpected counters: errors=%d connections=%d reconnects=%d commands=%d",
+			stats.Errors, stats.Connections, stats.Reconnects, stats.Commands)
+	}
+	if !stats.Recovered || stats.ErrorSpike < 20*time.Millisecond || stats.TimeToRecover < stats.ErrorSpike {
+		t.Errorf("unexpected recovery: recovered=%v spike=%v recover=%v", stats.Recovered, stats.ErrorSpike, stats.TimeToRecover)
+	}
+}
@@ -0,0 +1,369 @@
+package operation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	redisConfig "abc-runner/app/adapters/redis/config"
+	"abc-runner/app/core/metrics"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// notifyEvents 跟踪的写命令及其必然产生的键空间事件
+// DEL、LPOP、SADD等命令只在键或元素实际变化时才产生事件，无法判断是否应收到通知，不参与配对
+var notifyEvents = map[string]string{
+	"set":    "set",
+	"setex":  "set",
+	"incr":   "incrby",
+	"incrby": "incrby",
+	"decr":   "decrby",
+	"decrby": "decrby",
+	"hset":   "hset",
+	"hmset":  "hset",
+	"lpush":  "lpush",
+	"rpush":  "rpush",
+}
+
+// requiredNotifyClasses 跟踪的事件所需的notify-keyspace-events类别：K键空间、$字符串、h哈希、l列表
+const requiredNotifyClasses = "K$hl"
+
+// KeyspaceStats 键空间通知测试统计
+type KeyspaceStats struct {
+	Channel     string                 `json:"channel"`     // 订阅的模式
+	Configured  bool                   `json:"configured"`  // 是否由本次运行开启了notify-keyspace-events
+	Flags       string                 `json:"flags"`       // 测试期间生效的notify-keyspace-events
+	Writes      int64                  `json:"writes"`      // 成功且应产生通知的写入数
+	Delivered   int64                  `json:"delivered"`   // 超时前收到通知的写入数
+	Late        int64                  `json:"late"`        // 超过超时后才收到通知的写入数
+	Lost        int64                  `json:"lost"`        // 超时后仍未收到通知的写入数
+	Pending     int64                  `json:"pending"`     // 统计时仍在等待通知（未超时）的写入数
+	LossRate    float64                `json:"loss_rate"`   // 丢失占写入的百分比
+	Unmatched   int64                  `json:"unmatched"`   // 无法与本次写入配对的通知（其他客户端写入、过期、淘汰等）
+	Events      map[string]int64       `json:"events"`      // 收到的各类事件数
+	Disconnects int64                  `json:"disconnects"` // 订阅连接中断次数，期间的通知会丢失
+	Latency     metrics.LatencyMetrics `json:"latency"`     // 发出写入到收到通知的延迟
+}
+
+// pendingNotify 等待通知的一次写入
+type pendingNotify struct {
+	event  string
+	sentAt time.Time
+}
+
+// KeyspaceTracker 键空间通知跟踪器
+// 通过go-redis hook在写命令发出时登记期待的事件，订阅连接收到同一键的同名事件时配对并记录送达延迟；
+// 登记发生在命令发出前，通知先于写入回复到达时同样能够配对
+type KeyspaceTracker struct {
+	config  redisConfig.NotificationsConfig
+	timeout time.Duration
+	channel string
+	prefix  string
+
+	client     redis.UniversalClient
+	pubsub     *redis.PubSub
+	closed     atomic.Bool
+	done       chan struct{}
+	configured bool
+	restored   bool
+	original   string // 开启前的notify-keyspace-events，结束时恢复
+	flags      string
+
+	mutex     sync.Mutex
+	pending   map[string][]*pendingNotify
+	events    map[string]int64
+	writes    int64
+	delivered int64
+	late      int64
+	unmatched int64
+
+	disconnects atomic.Int64
+	latency     *metrics.LatencyTracker
+}
+
+// NewKeyspaceTracker 创建键空间通知跟踪器，db为写入负载使用的数据库
+func NewKeyspaceTracker(config redisConfig.NotificationsConfig, db int) *KeyspaceTracker {
+	prefix := fmt.Sprintf("__keyspace@%d__:", db)
+	return &KeyspaceTracker{
+		config:  config,
+		timeout: config.GetTimeout(),
+		channel: prefix + "*",
+		prefix:  prefix,
+		done:    make(chan struct{}),
+		pending: make(map[string][]*pendingNotify),
+		events:  make(map[string]int64),
+		latency: metrics.NewLatencyTracker(metrics.LatencyConfig{
+			SignificantDigits: metrics.DefaultHdrSignificantDigits,
+			SamplingRate:      1.0,
+		}),
+	}
+}
+
+// Connect 检查（或开启）notify-keyspace-events并订阅键空间通知
+func (k *KeyspaceTracker) Connect(ctx context.Context, client redis.UniversalClient) error {
+	k.client = client
+
+	flags, err := getNotifyFlags(ctx, client)
+	switch {
+	case err != nil && k.config.Configure:
+		return fmt.Errorf("failed to read notify-keyspace-events: %w", err)
+	case err != nil:
+		// 托管服务可能禁用CONFIG命令，此时假定已在服务端开启
+		flags = "unknown"
+	case k.config.Configure:
+		if missing := missingNotifyClasses(flags); missing != "" {
+			enabled := flags + missing
+			if err := client.ConfigSet(ctx, "notify-keyspace-events", enabled).Err(); err != nil {
+				return fmt.Errorf("failed to enable keyspace notifications: %w", err)
+			}
+			k.configured, k.original = true, flags
+			if flags, err = getNotifyFlags(ctx, client); err != nil {
+				flags = enabled
+			}
+		}
+	default:
+		if missing := missingNotifyClasses(flags); missing != "" {
+			return fmt.Errorf("notify-keyspace-events is %q, missing %q: enable keyspace notifications on the server or set notifications.configure (--configure-notify)", flags, missing)
+		}
+	}
+	k.flags = flags
+
+	pubsub := client.PSubscribe(ctx, k.channel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		k.restore()
+		return fmt.Errorf("failed to subscribe to %s: %w", k.channel, err)
+	}
+	k.pubsub = pubsub
+	go k.receive()
+	return nil
+}
+
+// getNotifyFlags 读取服务端当前的notify-keyspace-events
+func getNotifyFlags(ctx context.Context, client redis.UniversalClient) (string, error) {
+	values, err := client.ConfigGet(ctx, "notify-keyspace-events").Result()
+	if err != nil {
+		return "", err
+	}
+	if len(values) < 2 {
+		return "", fmt.Errorf("unexpected CONFIG GET reply %v", values)
+	}
+	flags, _ := values[1].(string)
+	return flags, nil
+}
+
+// missingNotifyClasses 返回flags中缺少的跟踪事件所需类别，A包含除键空间/键事件外的全部类别
+func missingNotifyClasses(flags string) string {
+	var missing strings.Builder
+	for _, class := range requiredNotifyClasses {
+		if strings.ContainsRune(flags, class) || (class != 'K' && strings.ContainsRune(flags, 'A')) {
+			continue
+		}
+		missing.WriteRune(class)
+	}
+	return missing.String()
+}
+
+// receive 接收键空间通知直到跟踪器关闭，连接中断时由go-redis重新订阅
+func (k *KeyspaceTracker) receive() {
+	defer close(k.done)
+	for {
+		msg, err := k.pubsub.ReceiveMessage(context.Background())
+		if err != nil {
+			if k.closed.Load() {
+				return
+			}
+			k.disconnects.Add(1)
+			time.Sleep(10 * time.Millisecond)
+			continue
+		}
+		k.deliver(strings.TrimPrefix(msg.Channel, k.prefix), msg.Payload, time.Now())
+	}
+}
+
+// deliver 将通知与同一键最早登记的同名事件配对
+func (k *KeyspaceTracker) deliver(key, event string, at time.Time) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	k.events[event]++
+	queue := k.pending[key]
+	for i, write := range queue {
+		if write.event != event {
+			continue
+		}
+		k.pending[key] = append(queue[:i:i], queue[i+1:]...)
+		if len(k.pending[key]) == 0 {
+			delete(k.pending, key)
+		}
+		latency := at.Sub(write.sentAt)
+		k.latency.Record(latency)
+		if latency > k.timeout {
+			k.late++
+		} else {
+			k.delivered++
+		}
+		return
+	}
+	k.unmatched++
+}
+
+// expect 写命令发出前登记期待的事件，返回nil表示该命令不参与配对
+func (k *KeyspaceTracker) expect(cmd redis.Cmder) *pendingNotify {
+	event, ok := notifyEvents[strings.ToLower(cmd.Name())]
+	args := cmd.Args()
+	if !ok || len(args) < 2 {
+		return nil
+	}
+	key, ok := args[1].(string)
+	if !ok {
+		return nil
+	}
+
+	write := &pendingNotify{event: event, sentAt: time.Now()}
+	k.mutex.Lock()
+	k.pending[key] = append(k.pending[key], write)
+	k.mutex.Unlock()
+	return write
+}
+
+// settle 写命令完成后确认登记：失败的写入不会产生通知，从等待中移除
+func (k *KeyspaceTracker) settle(cmd redis.Cmder, write *pendingNotify) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	if cmd.Err() == nil {
+		k.writes++
+		return
+	}
+	key := cmd.Args()[1].(string)
+	queue := k.pending[key]
+	for i, pending := range queue {
+		if pending == write {
+			k.pending[key] = append(queue[:i:i], queue[i+1:]...)
+			break
+		}
+	}
+	if len(k.pending[key]) == 0 {
+		delete(k.pending, key)
+	}
+}
+
+// keyspaceWritesKey context键，保存命令发出前的登记
+type keyspaceWritesKey struct{}
+
+// BeforeProcess 实现redis.Hook
+func (k *KeyspaceTracker) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	if write := k.expect(cmd); write != nil {
+		return context.WithValue(ctx, keyspaceWritesKey{}, []*pendingNotify{write}), nil
+	}
+	return ctx, nil
+}
+
+// AfterProcess 实现redis.Hook
+func (k *KeyspaceTracker) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	if writes, ok := ctx.Value(keyspaceWritesKey{}).([]*pendingNotify); ok {
+		k.settle(cmd, writes[0])
+	}
+	return nil
+}
+
+// BeforeProcessPipeline 实现redis.Hook
+func (k *KeyspaceTracker) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	writes := make([]*pendingNotify, len(cmds))
+	for i, cmd := range cmds {
+		writes[i] = k.expect(cmd)
+	}
+	return context.WithValue(ctx, keyspaceWritesKey{}, writes), nil
+}
+
+// AfterProcessPipeline 实现redis.Hook
+func (k *KeyspaceTracker) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	writes, _ := ctx.Value(keyspaceWritesKey{}).([]*pendingNotify)
+	for i, cmd := range cmds {
+		if i < len(writes) && writes[i] != nil {
+			k.settle(cmd, writes[i])
+		}
+	}
+	return nil
+}
+
+// Wait 等待已发出写入的通知到达，最长等待一个超时时间
+func (k *KeyspaceTracker) Wait() {
+	deadline := time.Now().Add(k.timeout)
+	for time.Now().Before(deadline) {
+		k.mutex.Lock()
+		waiting := len(k.pending)
+		k.mutex.Unlock()
+		if waiting == 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// Stats 获取键空间通知统计，等待超过超时时间的写入计为丢失
+func (k *KeyspaceTracker) Stats() KeyspaceStats {
+	now := time.Now()
+
+	k.mutex.Lock()
+	stats := KeyspaceStats{
+		Channel:     k.channel,
+		Configured:  k.configured,
+		Flags:       k.flags,
+		Writes:      k.writes,
+		Delivered:   k.delivered,
+		Late:        k.late,
+		Unmatched:   k.unmatched,
+		Events:      make(map[string]int64, len(k.events)),
+		Disconnects: k.disconnects.Load(),
+	}
+	for event, count := range k.events {
+		stats.Events[event] = count
+	}
+	for _, queue := range k.pending {
+		for _, write := range queue {
+			if now.Sub(write.sentAt) > k.timeout {
+				stats.Lost++
+			} else {
+				stats.Pending++
+			}
+		}
+	}
+	k.mutex.Unlock()
+
+	if stats.Writes > 0 {
+		stats.LossRate = float64(stats.Lost) / float64(stats.Writes) * 100
+	}
+	if stats.Delivered+stats.Late > 0 {
+		stats.Latency = k.latency.GetMetrics()
+	}
+	return stats
+}
+
+// restore 恢复测试前的notify-keyspace-events
+func (k *KeyspaceTracker) restore() {
+	if !k.configured || k.restored || k.client == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	k.restored = k.client.ConfigSet(ctx, "notify-keyspace-events", k.original).Err() == nil
+}
+
+// Close 取消订阅并恢复notify-keyspace-events，需在关闭写入客户端之前调用
+func (k *KeyspaceTracker) Close() error {
+	if !k.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+	if k.pubsub != nil {
+		k.pubsub.Close()
+		<-k.done
+	}
+	k.restore()
+	return nil
+}
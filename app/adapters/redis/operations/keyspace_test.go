@@ -0,0 +1,212 @@
+package operation
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	redisConfig "abc-runner/app/adapters/redis/config"
+	"abc-runner/app/adapters/redis/connection"
+	"abc-runner/app/core/interfaces"
+)
+
+// fakeNotifyServer 最小化的RESP2服务端，支持PING/CONFIG/PSUBSCRIBE/SET/INCR/DEL并向订阅者发布键空间通知
+// 键前缀drop:的写入不发布通知，键前缀late:的通知延迟200ms发布
+func fakeNotifyServer(t *testing.T, flags string) (string, func() string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	var mutex sync.Mutex
+	data := map[string]bool{}
+	type subscriber struct {
+		pattern string
+		writer  *bufio.Writer
+		lock    *sync.Mutex
+	}
+	var subscribers []subscriber
+
+	publish := func(key, event string) {
+		mutex.Lock()
+		targets := append([]subscriber(nil), subscribers...)
+		mutex.Unlock()
+		channel := "__keyspace@0__:" + key
+		for _, sub := range targets {
+			sub.lock.Lock()
+			fmt.Fprintf(sub.writer, "*4\r\n$8\r\npmessage\r\n$%d\r\n%s\r\n$%d\r\n%s\r\n$%d\r\n%s\r\n",
+				len(sub.pattern), sub.pattern, len(channel), channel, len(event), event)
+			sub.writer.Flush()
+			sub.lock.Unlock()
+		}
+	}
+	notify := func(key, event string) {
+		switch {
+		case strings.HasPrefix(key, "drop:"):
+		case strings.HasPrefix(key, "late:"):
+			time.AfterFunc(200*time.Millisecond, func() { publish(key, event) })
+		default:
+			publish(key, event)
+		}
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				writer := bufio.NewWriter(conn)
+				lock := &sync.Mutex{}
+
+				for {
+					value, err := connection.ReadRESP3(reader)
+					if err != nil {
+						return
+					}
+					args, _ := value.([]interface{})
+					if len(args) == 0 {
+						continue
+					}
+					lock.Lock()
+					switch strings.ToUpper(args[0].(string)) {
+					case "PING":
+						writer.WriteString("+PONG\r\n")
+					case "CONFIG":
+						if strings.ToUpper(args[1].(string)) == "GET" {
+							mutex.Lock()
+							fmt.Fprintf(writer, "*2\r\n$22\r\nnotify-keyspace-events\r\n$%d\r\n%s\r\n", len(flags), flags)
+							mutex.Unlock()
+						} else {
+							mutex.Lock()
+							flags = args[3].(string)
+							mutex.Unlock()
+							writer.WriteString("+OK\r\n")
+						}
+					case "PSUBSCRIBE":
+						pattern := args[1].(string)
+						mutex.Lock()
+						subscribers = append(subscribers, subscriber{pattern: pattern, writer: writer, lock: lock})
+						mutex.Unlock()
+						fmt.Fprintf(writer, "*3\r\n$10\r\npsubscribe\r\n$%d\r\n%s\r\n:1\r\n", len(pattern), pattern)
+					case "SET":
+						key := args[1].(string)
+						mutex.Lock()
+						data[key] = true
+						mutex.Unlock()
+						notify(key, "set")
+						writer.WriteString("+OK\r\n")
+					case "INCR":
+						notify(args[1].(string), "incrby")
+						writer.WriteString(":1\r\n")
+					case "DEL":
+						key := args[1].(string)
+						mutex.Lock()
+						existed := data[key]
+						delete(data, key)
+						mutex.Unlock()
+						if existed {
+							notify(key, "del")
+							writer.WriteString(":1\r\n")
+						} else {
+							writer.WriteString(":0\r\n")
+						}
+					default:
+						writer.WriteString("-ERR unknown command\r\n")
+					}
+					writer.Flush()
+					lock.Unlock()
+				}
+			}(conn)
+		}
+	}()
+
+	current := func() string {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return flags
+	}
+	return listener.Addr().String(), current
+}
+
+func TestKeyspaceTracker(t *testing.T) {
+	addr, flags := fakeNotifyServer(t, "")
+	cfg := redisConfig.NewDefaultRedisConfig()
+	cfg.Standalone.Addr = addr
+	cfg.Pool.ConnectionTimeout = 2 * time.Second
+	cfg.BenchMark.TTL = 0
+
+	pool, err := connection.NewRedisConnectionPool(cfg)
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	defer pool.Close()
+	client := pool.GetClient()
+
+	// 服务端未开启键空间通知时拒绝测试，configure时开启并在结束后恢复
+	if err := NewKeyspaceTracker(cfg.BenchMark.Notifications, 0).Connect(context.Background(), client); err == nil {
+		t.Fatalf("expected an error while notify-keyspace-events is empty")
+	}
+	tracker := NewKeyspaceTracker(redisConfig.NotificationsConfig{Enabled: true, Configure: true, Timeout: 50 * time.Millisecond}, 0)
+	if err := tracker.Connect(context.Background(), client); err != nil {
+		t.Fatalf("failed to connect tracker: %v", err)
+	}
+	if flags() != "K$hl" {
+		t.Fatalf("expected keyspace notifications to be enabled, got %q", flags())
+	}
+	client.AddHook(tracker)
+
+	executor := NewRedisExecutor(pool, cfg, nil)
+	operations := []interfaces.Operation{
+		{Type: "set", Key: "a", Value: "1"},
+		{Type: "set", Key: "a", Value: "2"},
+		{Type: "incr", Key: "counter"},
+		{Type: "del", Key: "a"},       // 条件写入，通知不参与配对
+		{Type: "del", Key: "missing"}, // 键不存在，不产生通知
+		{Type: "set", Key: "drop:b", Value: "1"},
+		{Type: "set", Key: "late:c", Value: "1"},
+	}
+	for _, operation := range operations {
+		if _, err := executor.ExecuteOperation(context.Background(), operation); err != nil {
+			t.Fatalf("%s %s failed: %v", operation.Type, operation.Key, err)
+		}
+	}
+	time.Sleep(300 * time.Millisecond)
+	tracker.Wait()
+
+	stats := tracker.Stats()
+	if stats.Writes != 5 || stats.Delivered != 3 || stats.Late != 1 || stats.Lost != 1 || stats.Pending != 0 {
+		t.Errorf("unexpected pairing: %+v", stats)
+	}
+	if stats.Unmatched != 1 || stats.Events["set"] != 3 || stats.Events["del"] != 1 || stats.LossRate != 20 {
+		t.Errorf("unexpected events: %+v", stats)
+	}
+	if !stats.Configured || stats.Latency.Max < 200*time.Millisecond || stats.Latency.Min <= 0 {
+		t.Errorf("unexpected latency or configuration: %+v", stats)
+	}
+
+	tracker.Close()
+	if flags() != "" {
+		t.Errorf("expected notify-keyspace-events to be restored, got %q", flags())
+	}
+	if stats := tracker.Stats(); stats.Disconnects != 0 {
+		t.Errorf("expected no disconnects when closing, got %d", stats.Disconnects)
+	}
+}
+
+func TestMissingNotifyClasses(t *testing.T) {
+	for flags, expected := range map[string]string{"": "K$hl", "KA": "", "AK": "", "Kx$": "hl", "EA": "K", "K$hlg": ""} {
+		if missing := missingNotifyClasses(flags); missing != expected {
+			t.Errorf("missingNotifyClasses(%q) = %q, expected %q", flags, missing, expected)
+		}
+	}
+}
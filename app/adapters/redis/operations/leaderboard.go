@@ -0,0 +1,197 @@
+package operation
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	redisConfig "abc-runner/app/adapters/redis/config"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/core/utils"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// LeaderboardStats 排行榜负载统计，分数更新与排名查询的延迟分别统计
+type LeaderboardStats struct {
+	Boards        int                    `json:"boards"`
+	Members       int                    `json:"members"`
+	TopN          int                    `json:"top_n"`
+	Updates       int64                  `json:"updates"`        // 分数更新（ZADD与ZINCRBY）
+	Adds          int64                  `json:"adds"`           // 其中ZADD的次数
+	Increments    int64                  `json:"increments"`     // 其中ZINCRBY的次数
+	UpdateErrors  int64                  `json:"update_errors"`  // 失败的分数更新
+	Queries       int64                  `json:"queries"`        // 排名查询（ZREVRANGE WITHSCORES）
+	QueryErrors   int64                  `json:"query_errors"`   // 失败的排名查询
+	EntriesRead   int64                  `json:"entries_read"`   // 排名查询返回的名次总数
+	UpdateLatency metrics.LatencyMetrics `json:"update_latency"` // 分数更新延迟
+	QueryLatency  metrics.LatencyMetrics `json:"query_latency"`  // 排名查询延迟
+}
+
+// LeaderboardTracker 排行榜负载统计器，nil时不做任何事
+type LeaderboardTracker struct {
+	config redisConfig.LeaderboardConfig
+
+	adds         atomic.Int64
+	increments   atomic.Int64
+	updateErrors atomic.Int64
+	queries      atomic.Int64
+	queryErrors  atomic.Int64
+	entriesRead  atomic.Int64
+
+	updateLatency *metrics.LatencyTracker
+	queryLatency  *metrics.LatencyTracker
+}
+
+// NewLeaderboardTracker 创建排行榜负载统计器
+func NewLeaderboardTracker(config redisConfig.LeaderboardConfig) *LeaderboardTracker {
+	latencyConfig := metrics.LatencyConfig{
+		SignificantDigits: metrics.DefaultHdrSignificantDigits,
+		SamplingRate:      1.0,
+	}
+	return &LeaderboardTracker{
+		config:        config,
+		updateLatency: metrics.NewLatencyTracker(latencyConfig),
+		queryLatency:  metrics.NewLatencyTracker(latencyConfig),
+	}
+}
+
+// Record 记录一次排行榜命令的结果，非排行榜命令忽略
+func (t *LeaderboardTracker) Record(operation interfaces.Operation, value interface{}, latency time.Duration, err error) {
+	if t == nil {
+		return
+	}
+	if prefill, _ := operation.Params["prefill"].(bool); prefill {
+		return
+	}
+
+	switch operation.Type {
+	case "zadd", "zincrby":
+		if err != nil {
+			t.updateErrors.Add(1)
+			return
+		}
+		if operation.Type == "zadd" {
+			t.adds.Add(1)
+		} else {
+			t.increments.Add(1)
+		}
+		t.updateLatency.Record(latency)
+	case "zrevrange":
+		t.queries.Add(1)
+		if err != nil {
+			t.queryErrors.Add(1)
+			return
+		}
+		if entries, ok := value.([]redis.Z); ok {
+			t.entriesRead.Add(int64(len(entries)))
+		}
+		t.queryLatency.Record(latency)
+	}
+}
+
+// Stats 获取统计结果
+func (t *LeaderboardTracker) Stats() LeaderboardStats {
+	stats := LeaderboardStats{
+		Boards:       t.config.GetBoards(),
+		Members:      t.config.GetMembers(),
+		TopN:         t.config.GetTopN(),
+		Adds:         t.adds.Load(),
+		Increments:   t.increments.Load(),
+		UpdateErrors: t.updateErrors.Load(),
+		Queries:      t.queries.Load(),
+		QueryErrors:  t.queryErrors.Load(),
+		EntriesRead:  t.entriesRead.Load(),
+	}
+	stats.Updates = stats.Adds + stats.Increments + stats.UpdateErrors
+	stats.UpdateLatency = t.updateLatency.GetMetrics()
+	stats.QueryLatency = t.queryLatency.GetMetrics()
+	return stats
+}
+
+// leaderboardWorkload 生成排行榜负载的命令：按read_percent决定排名查询，其余为分数更新
+type leaderboardWorkload struct {
+	config  redisConfig.LeaderboardConfig
+	members utils.KeyDistribution
+}
+
+func newLeaderboardWorkload(config redisConfig.LeaderboardConfig) (*leaderboardWorkload, error) {
+	members, err := utils.NewKeyDistribution(config.MemberDistribution(), config.GetMembers())
+	if err != nil {
+		return nil, err
+	}
+	return &leaderboardWorkload{config: config, members: members}, nil
+}
+
+// leaderboardKey 排行榜的有序集合键
+func leaderboardKey(board int) string {
+	return fmt.Sprintf("leaderboard:%d", board)
+}
+
+// leaderboardMember 排行榜成员名，编号越小越活跃
+func leaderboardMember(member int) string {
+	return fmt.Sprintf("player:%d", member)
+}
+
+// leaderboardScore 按命令编号生成的初始分数，分散在0到999999之间
+func leaderboardScore(commandID int) float64 {
+	return float64((commandID * 7919) % 1000000)
+}
+
+// create 创建一条排行榜命令
+func (w *leaderboardWorkload) create(commandID, readPercent int) interfaces.Operation {
+	key := leaderboardKey(commandID % w.config.GetBoards())
+	isRead := (commandID % 100) < readPercent
+
+	var operation interfaces.Operation
+	switch {
+	case isRead:
+		operation = interfaces.Operation{
+			Type: "zrevrange",
+			Key:  key,
+			Params: map[string]interface{}{
+				"start":      int64(0),
+				"stop":       int64(w.config.GetTopN() - 1),
+				"withscores": true,
+			},
+		}
+	case (commandID/100)%100 < w.config.AddPercent:
+		// 与读写比例使用不同的编号位，避免ZADD总是落在同一类命令编号上
+		operation = interfaces.Operation{
+			Type:   "zadd",
+			Key:    key,
+			Value:  leaderboardMember(w.members.Next(commandID)),
+			Params: map[string]interface{}{"score": leaderboardScore(commandID)},
+		}
+	default:
+		operation = interfaces.Operation{
+			Type:   "zincrby",
+			Key:    key,
+			Value:  leaderboardMember(w.members.Next(commandID)),
+			Params: map[string]interface{}{"increment": float64(1 + commandID%10)},
+		}
+	}
+
+	operation.Params["operation_type"] = operation.Type
+	operation.Params["job_id"] = commandID
+	operation.Params["is_read"] = isRead
+	return operation
+}
+
+// createPrepare 创建预填充命令：依次为每个排行榜写入成员的初始分数
+func (w *leaderboardWorkload) createPrepare(index int) interfaces.Operation {
+	boards := w.config.GetBoards()
+	return interfaces.Operation{
+		Type:  "zadd",
+		Key:   leaderboardKey(index % boards),
+		Value: leaderboardMember((index / boards) % w.config.GetMembers()),
+		Params: map[string]interface{}{
+			"operation_type": "zadd",
+			"job_id":         index,
+			"is_read":        false,
+			"prefill":        true,
+			"score":          leaderboardScore(index),
+		},
+	}
+}
@@ -0,0 +1,92 @@
+package operation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	redisConfig "abc-runner/app/adapters/redis/config"
+
+	"github.com/go-redis/redis/v8"
+)
+
+func TestLeaderboardWorkload(t *testing.T) {
+	cfg := redisConfig.NewDefaultRedisConfig()
+	cfg.BenchMark.Case = redisConfig.CaseLeaderboard
+	cfg.BenchMark.ReadPercent = 30
+	cfg.BenchMark.Leaderboard.Boards = 4
+	cfg.BenchMark.Leaderboard.Members = 1000
+	factory := NewOperationFactory(cfg)
+
+	counts := map[string]int{}
+	members := map[string]int{}
+	boards := map[string]bool{}
+	for commandID := 0; commandID < 10000; commandID++ {
+		operation := factory.CreateOperation(commandID, nil)
+		counts[operation.Type]++
+		boards[operation.Key] = true
+		switch operation.Type {
+		case "zrevrange":
+			if operation.Params["stop"] != int64(9) || operation.Params["withscores"] != true || operation.Params["is_read"] != true {
+				t.Fatalf("unexpected rank query: %+v", operation)
+			}
+		case "zadd", "zincrby":
+			members[operation.Value.(string)]++
+		default:
+			t.Fatalf("unexpected operation type %s", operation.Type)
+		}
+	}
+
+	// 30%排名查询，其余更新中10%为ZADD
+	if counts["zrevrange"] != 3000 || counts["zadd"] != 700 || counts["zincrby"] != 6300 {
+		t.Errorf("unexpected command mix: %v", counts)
+	}
+	if len(boards) != 4 {
+		t.Errorf("expected 4 boards, got %v", boards)
+	}
+	// zipfian分布下编号最小的成员最活跃
+	if members["player:0"] < members["player:500"]*10 {
+		t.Errorf("expected player:0 to be much hotter than player:500, got %d and %d", members["player:0"], members["player:500"])
+	}
+
+	// 预填充依次覆盖每个排行榜的全部成员
+	prepared := map[string]bool{}
+	for index := 0; index < 4000; index++ {
+		operation := factory.(*OperationFactory).CreatePrepareOperation(index)
+		prepared[operation.Key+"/"+operation.Value.(string)] = true
+	}
+	if len(prepared) != 4000 {
+		t.Errorf("expected 4000 distinct board members, got %d", len(prepared))
+	}
+}
+
+func TestLeaderboardTracker(t *testing.T) {
+	tracker := NewLeaderboardTracker(redisConfig.LeaderboardConfig{Members: 100})
+	workload, err := newLeaderboardWorkload(redisConfig.LeaderboardConfig{Members: 100})
+	if err != nil {
+		t.Fatalf("failed to create workload: %v", err)
+	}
+
+	query := workload.create(0, 100)
+	update := workload.create(0, 0)
+	tracker.Record(query, []redis.Z{{Score: 3, Member: "player:1"}, {Score: 2, Member: "player:0"}}, 3*time.Millisecond, nil)
+	tracker.Record(query, nil, time.Millisecond, errors.New("timeout"))
+	tracker.Record(update, float64(1), time.Millisecond, nil)
+	tracker.Record(update, nil, time.Millisecond, errors.New("timeout"))
+	tracker.Record(workload.createPrepare(0), int64(1), time.Millisecond, nil) // 预填充不计入
+	tracker.Record(query, nil, time.Millisecond, nil)
+
+	stats := tracker.Stats()
+	if stats.Queries != 3 || stats.QueryErrors != 1 || stats.EntriesRead != 2 || stats.QueryLatency.Max < 3*time.Millisecond {
+		t.Errorf("unexpected rank query stats: %+v", stats)
+	}
+	if stats.Updates != 2 || stats.Adds+stats.Increments != 1 || stats.UpdateErrors != 1 || stats.UpdateLatency.Max >= 2*time.Millisecond {
+		t.Errorf("unexpected update stats: %+v", stats)
+	}
+	if stats.Boards != 1 || stats.TopN != 10 || stats.Members != 100 {
+		t.Errorf("unexpected defaults: %+v", stats)
+	}
+
+	var disabled *LeaderboardTracker
+	disabled.Record(query, nil, time.Millisecond, nil)
+}
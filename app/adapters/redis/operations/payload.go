@@ -0,0 +1,59 @@
+package operation
+
+import (
+	"github.com/go-redis/redis/v8"
+
+	"abc-runner/app/core/interfaces"
+)
+
+// writtenPayload 写入命令携带的值的字节数，不含键名；读命令与不带值的命令为0
+func writtenPayload(operation interfaces.Operation) int64 {
+	switch value := operation.Value.(type) {
+	case string:
+		return int64(len(value))
+	case []byte:
+		return int64(len(value))
+	}
+	return 0
+}
+
+// readPayload 读命令返回的值的字节数，计数器、排名等数值结果为0
+func readPayload(value interface{}) int64 {
+	var size int64
+	switch v := value.(type) {
+	case string:
+		size = int64(len(v))
+	case []byte:
+		size = int64(len(v))
+	case []string:
+		for _, item := range v {
+			size += int64(len(item))
+		}
+	case map[string]string:
+		for field, item := range v {
+			size += int64(len(field) + len(item))
+		}
+	case []redis.Z:
+		for _, entry := range v {
+			if member, ok := entry.Member.(string); ok {
+				size += int64(len(member))
+			}
+		}
+	}
+	return size
+}
+
+// cmdPayload pipeline中单条命令返回的值的字节数
+func cmdPayload(cmd redis.Cmder) int64 {
+	switch c := cmd.(type) {
+	case *redis.StringCmd:
+		return readPayload(c.Val())
+	case *redis.StringSliceCmd:
+		return readPayload(c.Val())
+	case *redis.StringStringMapCmd:
+		return readPayload(c.Val())
+	case *redis.ZSliceCmd:
+		return readPayload(c.Val())
+	}
+	return 0
+}
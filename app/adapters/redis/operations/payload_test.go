@@ -0,0 +1,51 @@
+package operation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	redisConfig "abc-runner/app/adapters/redis/config"
+	"abc-runner/app/adapters/redis/connection"
+	"abc-runner/app/core/interfaces"
+)
+
+func TestRedisExecutor_PayloadBytes(t *testing.T) {
+	cfg := redisConfig.NewDefaultRedisConfig()
+	cfg.Standalone.Addr = fakeRESP2Server(t)
+	cfg.Pool.PoolSize = 1
+	cfg.Pool.ConnectionTimeout = 2 * time.Second
+
+	pool, err := connection.NewRedisConnectionPool(cfg)
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	defer pool.Close()
+	executor := NewRedisExecutor(pool, cfg, nil)
+	ctx := context.Background()
+
+	result, err := executor.ExecuteOperation(ctx, interfaces.Operation{Type: "set", Key: "key", Value: "hello"})
+	if err != nil || result.BytesWritten != 5 || result.BytesRead != 0 {
+		t.Fatalf("set: written=%d read=%d err=%v", result.BytesWritten, result.BytesRead, err)
+	}
+	result, err = executor.ExecuteOperation(ctx, interfaces.Operation{Type: "get", Key: "key"})
+	if err != nil || result.BytesRead != 5 || result.BytesWritten != 0 {
+		t.Fatalf("get: written=%d read=%d err=%v", result.BytesWritten, result.BytesRead, err)
+	}
+
+	// pipeline累计整批命令的值，键不存在时不计读取
+	result, err = executor.ExecuteOperation(ctx, interfaces.Operation{
+		Type: "pipeline",
+		Params: map[string]interface{}{
+			"commands": []interfaces.Operation{
+				{Type: "set", Key: "other", Value: "abc"},
+				{Type: "get", Key: "key"},
+				{Type: "get", Key: "other"},
+				{Type: "get", Key: "missing"},
+			},
+		},
+	})
+	if err != nil || result.BytesWritten != 3 || result.BytesRead != 8 {
+		t.Errorf("pipeline: written=%d read=%d err=%v", result.BytesWritten, result.BytesRead, err)
+	}
+}
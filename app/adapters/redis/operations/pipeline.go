@@ -0,0 +1,99 @@
+package operation
+
+import (
+	"sync"
+	"time"
+
+	"abc-runner/app/core/metrics"
+)
+
+// PipelineStats pipeline模式统计
+// 批次延迟为一次往返（发送整批命令到收到全部回复）的耗时，
+// 单命令延迟为批次延迟按批内命令数均摊后的值
+type PipelineStats struct {
+	Batches        int64                  `json:"batches"`
+	FailedBatches  int64                  `json:"failed_batches"` // 含失败命令或整批执行失败的批次
+	Commands       int64                  `json:"commands"`
+	FailedCommands int64                  `json:"failed_commands"`
+	BatchSize      float64                `json:"batch_size"`   // 平均每批命令数
+	CommandRate    float64                `json:"command_rate"` // 每秒完成的命令数
+	BatchLatency   metrics.LatencyMetrics `json:"batch_latency"`
+	CommandLatency metrics.LatencyMetrics `json:"command_latency"`
+}
+
+// PipelineTracker pipeline批次与命令统计器
+type PipelineTracker struct {
+	mutex          sync.Mutex
+	batches        int64
+	failedBatches  int64
+	commands       int64
+	failedCommands int64
+	firstBatch     time.Time
+	lastBatch      time.Time
+
+	batchLatency   *metrics.LatencyTracker
+	commandLatency *metrics.LatencyTracker
+}
+
+// NewPipelineTracker 创建pipeline统计器
+func NewPipelineTracker() *PipelineTracker {
+	config := metrics.LatencyConfig{
+		SignificantDigits: metrics.DefaultHdrSignificantDigits,
+		SamplingRate:      1.0,
+	}
+	return &PipelineTracker{
+		batchLatency:   metrics.NewLatencyTracker(config),
+		commandLatency: metrics.NewLatencyTracker(config),
+	}
+}
+
+// RecordBatch 记录一批命令的执行结果
+func (t *PipelineTracker) RecordBatch(commands, failed int, latency time.Duration) {
+	if commands <= 0 {
+		return
+	}
+
+	t.batchLatency.Record(latency)
+	perCommand := latency / time.Duration(commands)
+	for i := 0; i < commands; i++ {
+		t.commandLatency.Record(perCommand)
+	}
+
+	now := time.Now()
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.batches == 0 {
+		t.firstBatch = now.Add(-latency)
+	}
+	t.lastBatch = now
+	t.batches++
+	t.commands += int64(commands)
+	if failed > 0 {
+		t.failedBatches++
+		t.failedCommands += int64(failed)
+	}
+}
+
+// Stats 获取统计结果
+func (t *PipelineTracker) Stats() PipelineStats {
+	t.mutex.Lock()
+	stats := PipelineStats{
+		Batches:        t.batches,
+		FailedBatches:  t.failedBatches,
+		Commands:       t.commands,
+		FailedCommands: t.failedCommands,
+	}
+	elapsed := t.lastBatch.Sub(t.firstBatch)
+	t.mutex.Unlock()
+
+	if stats.Batches > 0 {
+		stats.BatchSize = float64(stats.Commands) / float64(stats.Batches)
+	}
+	if elapsed > 0 {
+		stats.CommandRate = float64(stats.Commands) / elapsed.Seconds()
+	}
+	stats.BatchLatency = t.batchLatency.GetMetrics()
+	stats.CommandLatency = t.commandLatency.GetMetrics()
+	return stats
+}
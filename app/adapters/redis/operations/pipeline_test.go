@@ -0,0 +1,182 @@
+package operation
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	redisConfig "abc-runner/app/adapters/redis/config"
+	"abc-runner/app/adapters/redis/connection"
+	"abc-runner/app/core/interfaces"
+)
+
+// fakeRESP2Server 最小化的RESP2服务端，支持PING/GET/SET与SCRIPT LOAD/EVALSHA/EVAL，其余命令返回错误
+// 脚本不会真正执行，调用成功时返回KEYS数量
+func fakeRESP2Server(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	var mutex sync.Mutex
+	data := map[string]string{}
+	scripts := map[string]bool{}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				writer := bufio.NewWriter(conn)
+
+				for {
+					value, err := connection.ReadRESP3(reader)
+					if err != nil {
+						return
+					}
+					args, _ := value.([]interface{})
+					if len(args) == 0 {
+						continue
+					}
+					switch strings.ToUpper(args[0].(string)) {
+					case "PING":
+						writer.WriteString("+PONG\r\n")
+					case "GET":
+						mutex.Lock()
+						v, ok := data[args[1].(string)]
+						mutex.Unlock()
+						if !ok {
+							writer.WriteString("$-1\r\n")
+						} else {
+							writer.WriteString("$" + strconv.Itoa(len(v)) + "\r\n" + v + "\r\n")
+						}
+					case "SET":
+						mutex.Lock()
+						data[args[1].(string)] = args[2].(string)
+						mutex.Unlock()
+						writer.WriteString("+OK\r\n")
+					case "SCRIPT":
+						sum := sha1.Sum([]byte(args[2].(string)))
+						sha := hex.EncodeToString(sum[:])
+						mutex.Lock()
+						scripts[sha] = true
+						mutex.Unlock()
+						writer.WriteString("$40\r\n" + sha + "\r\n")
+					case "EVALSHA":
+						mutex.Lock()
+						loaded := scripts[args[1].(string)]
+						mutex.Unlock()
+						if !loaded {
+							writer.WriteString("-NOSCRIPT No matching script. Please use EVAL.\r\n")
+						} else {
+							writer.WriteString(":" + args[2].(string) + "\r\n")
+						}
+					case "EVAL":
+						sum := sha1.Sum([]byte(args[1].(string)))
+						mutex.Lock()
+						scripts[hex.EncodeToString(sum[:])] = true
+						mutex.Unlock()
+						writer.WriteString(":" + args[2].(string) + "\r\n")
+					default:
+						writer.WriteString("-ERR unknown command\r\n")
+					}
+					// 请求缓冲区读空后再刷新，使一批pipeline命令的回复一次写出
+					if reader.Buffered() == 0 {
+						writer.Flush()
+					}
+				}
+			}(conn)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestRedisExecutor_Pipeline(t *testing.T) {
+	cfg := redisConfig.NewDefaultRedisConfig()
+	cfg.Standalone.Addr = fakeRESP2Server(t)
+	cfg.Pool.PoolSize = 2
+	cfg.Pool.ConnectionTimeout = 2 * time.Second
+	cfg.BenchMark.Pipeline = 4
+
+	pool, err := connection.NewRedisConnectionPool(cfg)
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	defer pool.Close()
+
+	executor := NewRedisExecutor(pool, cfg, nil)
+	operation := interfaces.Operation{
+		Type: "pipeline",
+		Params: map[string]interface{}{
+			"commands": []interfaces.Operation{
+				{Type: "set", Key: "a", Value: "1"},
+				{Type: "get", Key: "a"},
+				{Type: "get", Key: "missing"},
+				{Type: "incr", Key: "a"},
+				{Type: "set", Key: "b", Value: 2}, // 参数无效，不进入队列
+			},
+		},
+	}
+
+	result, err := executor.ExecuteOperation(context.Background(), operation)
+	if err == nil || result.Success {
+		t.Fatalf("expected batch with failed commands to fail, got success=%v err=%v", result.Success, err)
+	}
+	if failed := result.Metadata["failed_commands"]; failed != 2 {
+		t.Errorf("failed commands = %v, want 2", failed)
+	}
+
+	operation.Params["commands"] = []interfaces.Operation{{Type: "get", Key: "a"}, {Type: "get", Key: "missing"}}
+	result, err = executor.ExecuteOperation(context.Background(), operation)
+	if err != nil || !result.Success || !result.IsRead {
+		t.Fatalf("expected read-only batch to succeed, got success=%v read=%v err=%v", result.Success, result.IsRead, err)
+	}
+
+	stats := executor.PipelineStats()
+	if stats.Batches != 2 || stats.FailedBatches != 1 || stats.Commands != 7 || stats.FailedCommands != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if stats.BatchSize != 3.5 || stats.CommandLatency.Max >= stats.BatchLatency.Max {
+		t.Errorf("batch size = %v, command max = %v, batch max = %v",
+			stats.BatchSize, stats.CommandLatency.Max, stats.BatchLatency.Max)
+	}
+}
+
+func TestOperationFactory_Pipeline(t *testing.T) {
+	cfg := redisConfig.NewDefaultRedisConfig()
+	cfg.BenchMark.Total = 10
+	cfg.BenchMark.Pipeline = 4
+
+	benchmark := redisConfig.NewBenchmarkConfigAdapter(cfg.GetBenchmark())
+	if jobs := benchmark.GetTotal(); jobs != 3 {
+		t.Fatalf("jobs = %d, want 3", jobs)
+	}
+
+	factory := NewOperationFactory(cfg)
+	var keys []string
+	for jobID := 0; jobID < benchmark.GetTotal(); jobID++ {
+		operation := factory.CreateOperation(jobID, benchmark)
+		if operation.Type != "pipeline" {
+			t.Fatalf("operation type = %s, want pipeline", operation.Type)
+		}
+		for _, command := range operation.Params["commands"].([]interfaces.Operation) {
+			keys = append(keys, command.Key)
+		}
+	}
+	if len(keys) != 10 || keys[0] != "key_0" || keys[9] != "key_9" {
+		t.Errorf("commands across batches = %v", keys)
+	}
+}
@@ -0,0 +1,450 @@
+package operation
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	redisConfig "abc-runner/app/adapters/redis/config"
+	"abc-runner/app/adapters/redis/connection"
+	"abc-runner/app/core/interfaces"
+)
+
+// ProxyStats 代理模式统计
+type ProxyStats struct {
+	Listen          string        `json:"listen"`
+	Upstream        string        `json:"upstream"`
+	Connections     int64         `json:"connections"`        // 接受的客户端连接数
+	Active          int64         `json:"active_connections"` // 当前活跃的客户端连接数
+	DialErrors      int64         `json:"dial_errors"`        // 连接上游Redis失败次数
+	Commands        int64         `json:"commands"`           // 已匹配回复并记录延迟的命令数
+	ErrorReplies    int64         `json:"error_replies"`
+	Untracked       int64         `json:"untracked_replies"` // 不对应单条命令的回复（pub/sub消息、MONITOR输出、RESP3推送）
+	BytesIn         int64         `json:"bytes_in"`          // 客户端发往Redis的字节数
+	BytesOut        int64         `json:"bytes_out"`         // Redis发往客户端的字节数
+	InjectedLatency time.Duration `json:"injected_latency"`  // 每条回复注入的延迟
+}
+
+// proxyReadCommands 代理记录为读操作的只读命令，其余命令计为写操作
+var proxyReadCommands = map[string]bool{
+	"get": true, "mget": true, "getrange": true, "strlen": true, "exists": true, "ttl": true, "pttl": true, "type": true,
+	"hget": true, "hmget": true, "hgetall": true, "hkeys": true, "hvals": true, "hlen": true, "hexists": true,
+	"lrange": true, "lindex": true, "llen": true,
+	"smembers": true, "sismember": true, "smismember": true, "scard": true, "srandmember": true,
+	"zrange": true, "zrangebyscore": true, "zrevrange": true, "zrank": true, "zrevrank": true, "zscore": true, "zcard": true, "zcount": true,
+	"scan": true, "hscan": true, "sscan": true, "zscan": true, "keys": true, "dbsize": true,
+	"ping": true, "echo": true, "info": true, "evalsha_ro": true, "eval_ro": true,
+}
+
+// proxyPassthroughCommands 使连接进入推送模式的命令，之后的回复不再与命令一一对应，只转发不计时
+var proxyPassthroughCommands = map[string]bool{
+	"subscribe": true, "psubscribe": true, "ssubscribe": true, "monitor": true,
+}
+
+// pendingCommand 已转发、等待回复的命令
+type pendingCommand struct {
+	name  string
+	start time.Time
+}
+
+// RedisProxy Redis命令延迟代理
+// 位于真实客户端与Redis之间，透明转发RESP流量，按FIFO顺序将回复与命令匹配，
+// 以命令名作为operation_type记录客户端观察到的每条命令延迟，可选为每条回复注入固定延迟
+type RedisProxy struct {
+	listen      string
+	upstream    string
+	tlsConfig   *tls.Config
+	dialTimeout time.Duration
+	inject      time.Duration
+	collector   interfaces.DefaultMetricsCollector
+
+	listener net.Listener
+	wg       sync.WaitGroup
+	mutex    sync.Mutex
+	conns    map[net.Conn]struct{}
+
+	connections  atomic.Int64
+	active       atomic.Int64
+	dialErrors   atomic.Int64
+	commands     atomic.Int64
+	errorReplies atomic.Int64
+	untracked    atomic.Int64
+	bytesIn      atomic.Int64
+	bytesOut     atomic.Int64
+}
+
+// NewRedisProxy 根据配置创建Redis命令延迟代理：上游为单机地址，配置了TLS时以TLS连接上游
+func NewRedisProxy(config *redisConfig.RedisConfig, collector interfaces.DefaultMetricsCollector) (*RedisProxy, error) {
+	tlsConfig, err := connection.NewTLSConfig(config.TLS)
+	if err != nil {
+		return nil, err
+	}
+	return &RedisProxy{
+		listen:      config.Proxy.Listen,
+		upstream:    config.Standalone.Addr,
+		tlsConfig:   tlsConfig,
+		dialTimeout: config.Pool.ConnectionTimeout,
+		inject:      config.Proxy.InjectLatency,
+		collector:   collector,
+		conns:       make(map[net.Conn]struct{}),
+	}, nil
+}
+
+// CheckUpstream 确认上游Redis可连接
+func (p *RedisProxy) CheckUpstream(ctx context.Context) error {
+	conn, err := p.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("upstream %s unreachable: %w", p.upstream, err)
+	}
+	return conn.Close()
+}
+
+// Listen 开始监听客户端连接
+func (p *RedisProxy) Listen() error {
+	listener, err := net.Listen("tcp", p.listen)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", p.listen, err)
+	}
+	p.listener = listener
+	return nil
+}
+
+// Addr 实际监听地址
+func (p *RedisProxy) Addr() string {
+	if p.listener == nil {
+		return p.listen
+	}
+	return p.listener.Addr().String()
+}
+
+// Serve 接受并代理客户端连接，直到ctx结束；返回前关闭所有连接
+func (p *RedisProxy) Serve(ctx context.Context) error {
+	if p.listener == nil {
+		if err := p.Listen(); err != nil {
+			return err
+		}
+	}
+
+	go func() {
+		<-ctx.Done()
+		p.listener.Close()
+		p.mutex.Lock()
+		for conn := range p.conns {
+			conn.Close()
+		}
+		p.mutex.Unlock()
+	}()
+
+	for {
+		client, err := p.listener.Accept()
+		if err != nil {
+			p.wg.Wait()
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("accept failed: %w", err)
+		}
+		p.connections.Add(1)
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.handle(ctx, client)
+		}()
+	}
+}
+
+// track 登记或注销连接，代理停止时统一关闭；已停止时返回false
+func (p *RedisProxy) track(ctx context.Context, conn net.Conn, add bool) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if !add {
+		delete(p.conns, conn)
+		return true
+	}
+	if ctx.Err() != nil {
+		return false
+	}
+	p.conns[conn] = struct{}{}
+	return true
+}
+
+// dial 连接上游Redis
+func (p *RedisProxy) dial(ctx context.Context) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: p.dialTimeout}
+	if p.tlsConfig != nil {
+		return (&tls.Dialer{NetDialer: dialer, Config: p.tlsConfig}).DialContext(ctx, "tcp", p.upstream)
+	}
+	return dialer.DialContext(ctx, "tcp", p.upstream)
+}
+
+// handle 代理单个客户端连接：每个客户端连接对应一条上游连接
+func (p *RedisProxy) handle(ctx context.Context, client net.Conn) {
+	defer client.Close()
+	upstream, err := p.dial(ctx)
+	if err != nil {
+		p.dialErrors.Add(1)
+		client.Write([]byte("-ERR abc-runner proxy: upstream unavailable\r\n"))
+		return
+	}
+	defer upstream.Close()
+
+	if !p.track(ctx, client, true) || !p.track(ctx, upstream, true) {
+		return
+	}
+	defer p.track(ctx, client, false)
+	defer p.track(ctx, upstream, false)
+
+	p.active.Add(1)
+	defer p.active.Add(-1)
+
+	session := &proxySession{proxy: p}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		session.forwardReplies(upstream, client)
+		client.Close()
+	}()
+	session.forwardCommands(client, upstream)
+	upstream.Close()
+	<-done
+}
+
+// proxySession 单个客户端连接的命令与回复匹配状态
+type proxySession struct {
+	proxy       *RedisProxy
+	mutex       sync.Mutex
+	pending     []pendingCommand
+	passthrough bool // 已进入pub/sub或MONITOR模式
+}
+
+// forwardCommands 读取客户端命令，登记后原样转发给上游
+func (s *proxySession) forwardCommands(client, upstream net.Conn) {
+	reader := bufio.NewReader(client)
+	var raw []byte
+	for {
+		var err error
+		raw, err = readRESPRequest(reader, raw[:0])
+		if err != nil {
+			return
+		}
+		name := requestCommandName(raw)
+
+		s.mutex.Lock()
+		if proxyPassthroughCommands[name] {
+			s.passthrough = true
+		}
+		if !s.passthrough && name != "" {
+			s.pending = append(s.pending, pendingCommand{name: name, start: time.Now()})
+		}
+		s.mutex.Unlock()
+
+		if _, err := upstream.Write(raw); err != nil {
+			return
+		}
+		s.proxy.bytesIn.Add(int64(len(raw)))
+	}
+}
+
+// proxyReply 从上游读取、等待转发给客户端的回复
+type proxyReply struct {
+	raw     []byte
+	arrived time.Time
+	command pendingCommand
+	matched bool
+}
+
+// forwardReplies 读取上游回复并与最早的待回复命令匹配，交由writeReplies转发
+// 读取与转发分离，使注入延迟以回复的实际到达时间为起点
+func (s *proxySession) forwardReplies(upstream, client net.Conn) {
+	replies := make(chan proxyReply, 1024)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.writeReplies(client, replies)
+	}()
+	defer func() {
+		close(replies)
+		<-done
+	}()
+
+	reader := bufio.NewReader(upstream)
+	for {
+		raw, err := readRESPValue(reader, nil)
+		if err != nil {
+			return
+		}
+		reply := proxyReply{raw: raw, arrived: time.Now()}
+		if raw[0] != '>' {
+			s.mutex.Lock()
+			if len(s.pending) > 0 {
+				reply.command = s.pending[0]
+				reply.matched = true
+				s.pending = s.pending[1:]
+			}
+			s.mutex.Unlock()
+		}
+		replies <- reply
+	}
+}
+
+// writeReplies 按到达顺序转发回复并记录命令延迟，pipeline中同时到达的回复不会累加注入延迟
+func (s *proxySession) writeReplies(client net.Conn, replies <-chan proxyReply) {
+	failed := false
+	for reply := range replies {
+		if failed {
+			continue
+		}
+		if s.proxy.inject > 0 {
+			time.Sleep(time.Until(reply.arrived.Add(s.proxy.inject)))
+		}
+		if _, err := client.Write(reply.raw); err != nil {
+			failed = true
+			continue
+		}
+		s.proxy.bytesOut.Add(int64(len(reply.raw)))
+
+		if !reply.matched {
+			s.proxy.untracked.Add(1)
+			continue
+		}
+		s.proxy.record(reply.command, reply.raw, time.Since(reply.command.start))
+	}
+}
+
+// record 记录一条命令的延迟，错误回复计为失败
+func (p *RedisProxy) record(command pendingCommand, reply []byte, duration time.Duration) {
+	p.commands.Add(1)
+	var replyErr error
+	if reply[0] == '-' || reply[0] == '!' {
+		p.errorReplies.Add(1)
+		replyErr = errors.New(replyErrorMessage(reply))
+	}
+	if p.collector == nil {
+		return
+	}
+	p.collector.Record(&interfaces.OperationResult{
+		Success:  replyErr == nil,
+		Duration: duration,
+		IsRead:   proxyReadCommands[command.name],
+		Error:    replyErr,
+		Metadata: map[string]interface{}{"operation_type": command.name},
+	})
+}
+
+// Stats 获取代理统计
+func (p *RedisProxy) Stats() ProxyStats {
+	return ProxyStats{
+		Listen:          p.Addr(),
+		Upstream:        p.upstream,
+		Connections:     p.connections.Load(),
+		Active:          p.active.Load(),
+		DialErrors:      p.dialErrors.Load(),
+		Commands:        p.commands.Load(),
+		ErrorReplies:    p.errorReplies.Load(),
+		Untracked:       p.untracked.Load(),
+		BytesIn:         p.bytesIn.Load(),
+		BytesOut:        p.bytesOut.Load(),
+		InjectedLatency: p.inject,
+	}
+}
+
+// readRESPRequest 读取一条客户端请求：RESP数组或内联命令（如telnet发送的 "PING\r\n"）
+func readRESPRequest(reader *bufio.Reader, buf []byte) ([]byte, error) {
+	first, err := reader.Peek(1)
+	if err != nil {
+		return buf, err
+	}
+	if first[0] == '*' {
+		return readRESPValue(reader, buf)
+	}
+	line, err := reader.ReadBytes('\n')
+	return append(buf, line...), err
+}
+
+// readRESPValue 读取一个完整的RESP2/RESP3值并原样追加到buf
+func readRESPValue(reader *bufio.Reader, buf []byte) ([]byte, error) {
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		return buf, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return buf, fmt.Errorf("malformed RESP line %q", line)
+	}
+	buf = append(buf, line...)
+
+	switch line[0] {
+	case '+', '-', ':', '_', '#', ',', '(':
+		return buf, nil
+	case '$', '!', '=':
+		n, err := strconv.Atoi(string(line[1 : len(line)-2]))
+		if err != nil {
+			return buf, fmt.Errorf("malformed RESP length %q", line)
+		}
+		if n < 0 {
+			return buf, nil
+		}
+		start := len(buf)
+		buf = append(buf, make([]byte, n+2)...)
+		_, err = io.ReadFull(reader, buf[start:])
+		return buf, err
+	case '*', '~', '>', '%', '|':
+		n, err := strconv.Atoi(string(line[1 : len(line)-2]))
+		if err != nil {
+			return buf, fmt.Errorf("malformed RESP length %q", line)
+		}
+		if line[0] == '%' || line[0] == '|' {
+			n *= 2
+		}
+		for i := 0; i < n; i++ {
+			if buf, err = readRESPValue(reader, buf); err != nil {
+				return buf, err
+			}
+		}
+		// 属性（|）附加在随后的实际回复之前
+		if line[0] == '|' {
+			return readRESPValue(reader, buf)
+		}
+		return buf, nil
+	default:
+		return buf, fmt.Errorf("unsupported RESP type %q", line[0])
+	}
+}
+
+// requestCommandName 提取请求的命令名（小写）
+func requestCommandName(raw []byte) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	if raw[0] != '*' {
+		fields := strings.Fields(string(raw))
+		if len(fields) == 0 {
+			return ""
+		}
+		return strings.ToLower(fields[0])
+	}
+	// *<n>\r\n$<len>\r\n<name>\r\n
+	parts := bytes.SplitN(raw, []byte("\r\n"), 4)
+	if len(parts) < 3 {
+		return ""
+	}
+	return strings.ToLower(string(parts[2]))
+}
+
+// replyErrorMessage 错误回复的文本（简单错误或RESP3块错误）
+func replyErrorMessage(reply []byte) string {
+	lines := bytes.SplitN(reply, []byte("\r\n"), 3)
+	if reply[0] == '!' && len(lines) > 1 {
+		return string(lines[1])
+	}
+	return string(lines[0][1:])
+}
@@ -0,0 +1,100 @@
+package operation
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	redisConfig "abc-runner/app/adapters/redis/config"
+	"abc-runner/app/core/interfaces"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// recordingCollector 保存所有操作结果的指标收集器
+type recordingCollector struct {
+	mutex   sync.Mutex
+	results []*interfaces.OperationResult
+}
+
+func (c *recordingCollector) Record(result *interfaces.OperationResult) {
+	c.mutex.Lock()
+	c.results = append(c.results, result)
+	c.mutex.Unlock()
+}
+
+func (c *recordingCollector) Snapshot() *interfaces.DefaultMetricsSnapshot { return nil }
+func (c *recordingCollector) Reset()                                       {}
+func (c *recordingCollector) Stop()                                        {}
+
+func TestRedisProxy(t *testing.T) {
+	cfg := redisConfig.NewDefaultRedisConfig()
+	cfg.Standalone.Addr = fakeRESP2Server(t)
+	cfg.Pool.ConnectionTimeout = time.Second
+	cfg.Proxy.Listen = "127.0.0.1:0"
+	cfg.Proxy.InjectLatency = 20 * time.Millisecond
+
+	collector := &recordingCollector{}
+	proxy, err := NewRedisProxy(cfg, collector)
+	if err != nil {
+		t.Fatalf("failed to create proxy: %v", err)
+	}
+	if err := proxy.CheckUpstream(context.Background()); err != nil {
+		t.Fatalf("upstream check failed: %v", err)
+	}
+	if err := proxy.Listen(); err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- proxy.Serve(ctx) }()
+
+	client := redis.NewClient(&redis.Options{Addr: proxy.Addr(), PoolSize: 1, MaxRetries: -1})
+	if err := client.Set(ctx, "k", "v", 0).Err(); err != nil {
+		t.Fatalf("SET through proxy failed: %v", err)
+	}
+	if v, err := client.Get(ctx, "k").Result(); err != nil || v != "v" {
+		t.Fatalf("GET through proxy = %q, %v", v, err)
+	}
+	if err := client.Incr(ctx, "k").Err(); err == nil {
+		t.Fatal("expected the unknown command error to pass through")
+	}
+
+	// pipeline中同时到达的回复只注入一次延迟
+	start := time.Now()
+	pipe := client.Pipeline()
+	for i := 0; i < 5; i++ {
+		pipe.Get(ctx, "missing")
+	}
+	pipe.Exec(ctx)
+	if elapsed := time.Since(start); elapsed > 5*cfg.Proxy.InjectLatency {
+		t.Errorf("pipelined replies were delayed one after another: %v", elapsed)
+	}
+	client.Close()
+
+	cancel()
+	if err := <-served; err != nil {
+		t.Fatalf("serve failed: %v", err)
+	}
+
+	stats := proxy.Stats()
+	if stats.Connections != 1 || stats.Commands != 8 || stats.ErrorReplies != 1 || stats.Active != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if len(collector.results) != 8 {
+		t.Fatalf("recorded %d results, want 8", len(collector.results))
+	}
+	for i, want := range []string{"set", "get", "incr", "get"} {
+		result := collector.results[i]
+		if result.Metadata["operation_type"] != want {
+			t.Errorf("result %d operation = %v, want %s", i, result.Metadata["operation_type"], want)
+		}
+		if result.Duration < cfg.Proxy.InjectLatency {
+			t.Errorf("result %d duration %v does not include the injected latency", i, result.Duration)
+		}
+	}
+	if !collector.results[1].IsRead || collector.results[0].IsRead || collector.results[2].Success {
+		t.Errorf("unexpected classification: %+v %+v %+v", collector.results[0], collector.results[1], collector.results[2])
+	}
+}
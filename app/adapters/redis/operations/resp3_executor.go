@@ -0,0 +1,142 @@
+package operation
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	redisConfig "abc-runner/app/adapters/redis/config"
+	"abc-runner/app/adapters/redis/connection"
+	"abc-runner/app/core/interfaces"
+)
+
+// RESP3Executor 基于RESP3连接的操作执行器，可选启用客户端缓存
+type RESP3Executor struct {
+	pool     *connection.RESP3Pool
+	config   *redisConfig.RedisConfig
+	cache    *ClientCache
+	verifier *VerifyTracker
+}
+
+// NewRESP3Executor 创建RESP3操作执行器，cache为nil时不使用客户端缓存
+func NewRESP3Executor(pool *connection.RESP3Pool, config *redisConfig.RedisConfig, cache *ClientCache) *RESP3Executor {
+	return &RESP3Executor{
+		pool:   pool,
+		config: config,
+		cache:  cache,
+	}
+}
+
+// SetVerifier 设置数据校验器，SET写入带校验值，GET校验读取结果（含客户端缓存命中）
+func (r *RESP3Executor) SetVerifier(verifier *VerifyTracker) {
+	r.verifier = verifier
+}
+
+// ExecuteOperation 执行Redis操作
+func (r *RESP3Executor) ExecuteOperation(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	startTime := time.Now()
+	result := &interfaces.OperationResult{
+		IsRead:   operation.Type == "get",
+		Metadata: make(map[string]interface{}),
+	}
+
+	conn := r.pool.Get()
+	if conn == nil {
+		result.Error = fmt.Errorf("failed to get RESP3 connection from pool")
+		result.Duration = time.Since(startTime)
+		return result, result.Error
+	}
+
+	var opErr error
+	switch operation.Type {
+	case "get":
+		result.Value, opErr = r.executeGet(ctx, conn, operation, result)
+		if opErr == nil {
+			r.verifier.CheckRead(operation, result.Value)
+		}
+	case "set":
+		opErr = r.executeWrite(ctx, conn, operation.Key, r.setArgs(operation)...)
+	case "del":
+		opErr = r.executeWrite(ctx, conn, operation.Key, "DEL", operation.Key)
+	case "incr":
+		opErr = r.executeWrite(ctx, conn, operation.Key, "INCR", operation.Key)
+	case "decr":
+		opErr = r.executeWrite(ctx, conn, operation.Key, "DECR", operation.Key)
+	default:
+		opErr = fmt.Errorf("unsupported operation type in RESP3 mode: %s", operation.Type)
+	}
+
+	result.Success = opErr == nil
+	result.Error = opErr
+	result.Duration = time.Since(startTime)
+
+	for k, v := range operation.Metadata {
+		result.Metadata[k] = v
+	}
+	result.Metadata["operation_type"] = operation.Type
+	result.Metadata["key"] = operation.Key
+	result.Metadata["protocol"] = "resp3"
+
+	return result, opErr
+}
+
+// executeGet 执行GET，启用客户端缓存时优先读取本地缓存
+func (r *RESP3Executor) executeGet(ctx context.Context, conn *connection.RESP3Conn, operation interfaces.Operation, result *interfaces.OperationResult) (interface{}, error) {
+	if r.cache == nil {
+		return conn.Do(ctx, "GET", operation.Key)
+	}
+
+	if value, hit := r.cache.Get(operation.Key); hit {
+		result.Metadata["cache_hit"] = true
+		return value, nil
+	}
+
+	result.Metadata["cache_hit"] = false
+	entry := r.cache.beginLoad(operation.Key)
+	value, err := conn.Do(ctx, "GET", operation.Key)
+	r.cache.completeLoad(operation.Key, entry, value, err)
+	return value, err
+}
+
+// executeWrite 执行写命令并追踪失效窗口
+func (r *RESP3Executor) executeWrite(ctx context.Context, conn *connection.RESP3Conn, key string, args ...string) error {
+	if r.cache == nil {
+		_, err := conn.Do(ctx, args...)
+		return err
+	}
+
+	if r.config.Client.Tracking.NoLoop {
+		// NOLOOP模式下本客户端的写入不会触发失效通知，需主动移除
+		_, err := conn.Do(ctx, args...)
+		r.cache.evict(key)
+		return err
+	}
+
+	r.cache.beginWrite(key)
+	_, err := conn.Do(ctx, args...)
+	r.cache.completeWrite(key, err)
+	return err
+}
+
+// setArgs 构造SET命令参数
+func (r *RESP3Executor) setArgs(operation interfaces.Operation) []string {
+	value := fmt.Sprint(operation.Value)
+	if r.verifier != nil {
+		value = r.verifier.Seal(operation.Key, len(value))
+	}
+	args := []string{"SET", operation.Key, value}
+	if operation.TTL > 0 {
+		args = append(args, "PX", strconv.FormatInt(operation.TTL.Milliseconds(), 10))
+	}
+	return args
+}
+
+// CacheStats 获取客户端缓存统计，未启用时返回nil
+func (r *RESP3Executor) CacheStats() *ClientCacheStats {
+	if r.cache == nil {
+		return nil
+	}
+	stats := r.cache.Stats()
+	return &stats
+}
@@ -0,0 +1,114 @@
+package operation
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// LuaScript 基准测试使用的Lua脚本
+type LuaScript struct {
+	file    string
+	source  string
+	sha     string
+	useEval bool
+}
+
+// LoadLuaScript 读取Lua脚本文件并计算SHA1
+func LoadLuaScript(file string, useEval bool) (*LuaScript, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Lua script: %w", err)
+	}
+	source := string(data)
+	if strings.TrimSpace(source) == "" {
+		return nil, fmt.Errorf("Lua script %s is empty", file)
+	}
+
+	sum := sha1.Sum(data)
+	return &LuaScript{
+		file:    file,
+		source:  source,
+		sha:     hex.EncodeToString(sum[:]),
+		useEval: useEval,
+	}, nil
+}
+
+// Register 通过SCRIPT LOAD将脚本注册到服务端（集群模式下注册到所有主节点）
+func (s *LuaScript) Register(ctx context.Context, client redis.Cmdable) error {
+	sha, err := client.ScriptLoad(ctx, s.source).Result()
+	if err != nil {
+		return fmt.Errorf("SCRIPT LOAD failed: %w", err)
+	}
+	if sha != s.sha {
+		return fmt.Errorf("SCRIPT LOAD returned sha %s, expected %s", sha, s.sha)
+	}
+	return nil
+}
+
+// Run 执行脚本：默认EVALSHA，服务端脚本缓存被清空（NOSCRIPT）时回退为EVAL
+// client为Pipeliner时命令仅进入队列，NOSCRIPT在Exec时才会返回，不做回退
+func (s *LuaScript) Run(ctx context.Context, client redis.Cmdable, keys []string, args ...interface{}) (interface{}, error) {
+	if s.useEval {
+		return scriptResult(client.Eval(ctx, s.source, keys, args...).Result())
+	}
+
+	value, err := client.EvalSha(ctx, s.sha, keys, args...).Result()
+	if err != nil && strings.HasPrefix(err.Error(), "NOSCRIPT") {
+		return scriptResult(client.Eval(ctx, s.source, keys, args...).Result())
+	}
+	return scriptResult(value, err)
+}
+
+// scriptResult 脚本返回nil（redis.Nil）不视为错误
+func scriptResult(value interface{}, err error) (interface{}, error) {
+	if err == redis.Nil {
+		return nil, nil
+	}
+	return value, err
+}
+
+// File 脚本文件路径
+func (s *LuaScript) File() string {
+	return s.file
+}
+
+// SHA 脚本SHA1
+func (s *LuaScript) SHA() string {
+	return s.sha
+}
+
+// Mode 脚本调用方式：EVAL或EVALSHA
+func (s *LuaScript) Mode() string {
+	if s.useEval {
+		return "EVAL"
+	}
+	return "EVALSHA"
+}
+
+// renderScriptTemplates 替换KEYS/ARGV模板中的占位符
+func renderScriptTemplates(templates []string, commandID int, key string, value func() string) []string {
+	rendered := make([]string, len(templates))
+	for i, template := range templates {
+		if !strings.Contains(template, "{") {
+			rendered[i] = template
+			continue
+		}
+		replacements := []string{"{id}", strconv.Itoa(commandID), "{key}", key}
+		if strings.Contains(template, "{value}") {
+			replacements = append(replacements, "{value}", value())
+		}
+		if strings.Contains(template, "{rand}") {
+			replacements = append(replacements, "{rand}", strconv.Itoa(rand.Int()))
+		}
+		rendered[i] = strings.NewReplacer(replacements...).Replace(template)
+	}
+	return rendered
+}
@@ -0,0 +1,97 @@
+package operation
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	redisConfig "abc-runner/app/adapters/redis/config"
+	"abc-runner/app/adapters/redis/connection"
+	"abc-runner/app/core/interfaces"
+)
+
+// writeScript 写入测试用Lua脚本文件
+func writeScript(t *testing.T, source string) string {
+	file := filepath.Join(t.TempDir(), "script.lua")
+	if err := os.WriteFile(file, []byte(source), 0o644); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+	return file
+}
+
+func TestOperationFactory_Script(t *testing.T) {
+	cfg := redisConfig.NewDefaultRedisConfig()
+	cfg.BenchMark.RandomKeys = 10
+	cfg.BenchMark.DataSize = 4
+	cfg.Script = redisConfig.ScriptConfig{
+		File:     "script.lua",
+		Keys:     []string{"rl:{key}", "seq:{id}"},
+		Args:     []string{"100", "{value}", "{id}-{key}"},
+		ReadOnly: true,
+	}
+
+	benchmark := redisConfig.NewBenchmarkConfigAdapter(cfg.GetBenchmark())
+	operation := NewOperationFactory(cfg).CreateOperation(13, benchmark)
+
+	if operation.Type != "eval" || operation.Key != "rl:key_3" {
+		t.Fatalf("operation = %s %s, want eval rl:key_3", operation.Type, operation.Key)
+	}
+	keys := operation.Params["keys"].([]string)
+	if len(keys) != 2 || keys[1] != "seq:13" {
+		t.Errorf("keys = %v", keys)
+	}
+	args := operation.Params["args"].([]interface{})
+	if len(args) != 3 || args[0] != "100" || args[1] != "abcd" || args[2] != "13-key_3" {
+		t.Errorf("args = %v", args)
+	}
+}
+
+func TestRedisExecutor_Eval(t *testing.T) {
+	cfg := redisConfig.NewDefaultRedisConfig()
+	cfg.Standalone.Addr = fakeRESP2Server(t)
+	cfg.Pool.ConnectionTimeout = 2 * time.Second
+	cfg.Script.File = writeScript(t, "return #KEYS")
+
+	pool, err := connection.NewRedisConnectionPool(cfg)
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	defer pool.Close()
+
+	executor := NewRedisExecutor(pool, cfg, nil)
+	operation := interfaces.Operation{
+		Type:   "eval",
+		Params: map[string]interface{}{"keys": []string{"a", "b"}, "args": []interface{}{"1"}},
+	}
+	if _, err := executor.ExecuteOperation(context.Background(), operation); err == nil {
+		t.Fatal("expected eval without a loaded script to fail")
+	}
+
+	// 未注册时EVALSHA返回NOSCRIPT，回退为EVAL
+	script, err := LoadLuaScript(cfg.Script.File, false)
+	if err != nil {
+		t.Fatalf("failed to load script: %v", err)
+	}
+	executor.SetScript(script)
+	result, err := executor.ExecuteOperation(context.Background(), operation)
+	if err != nil || result.Value != int64(2) || result.IsRead {
+		t.Fatalf("eval result = %v, read = %v, err = %v", result.Value, result.IsRead, err)
+	}
+
+	if err := script.Register(context.Background(), pool.GetClient()); err != nil {
+		t.Fatalf("failed to register script: %v", err)
+	}
+	cfg.Script.ReadOnly = true
+	result, err = executor.ExecuteOperation(context.Background(), operation)
+	if err != nil || result.Value != int64(2) || !result.IsRead {
+		t.Errorf("evalsha result = %v, read = %v, err = %v", result.Value, result.IsRead, err)
+	}
+}
+
+func TestLoadLuaScript_Empty(t *testing.T) {
+	if _, err := LoadLuaScript(writeScript(t, "  \n"), false); err == nil {
+		t.Error("expected empty script to be rejected")
+	}
+}
@@ -0,0 +1,202 @@
+package operation
+
+import (
+	"fmt"
+	"hash/crc32"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"abc-runner/app/core/interfaces"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// verifiedValuePrefix 带校验的值的格式：abcv1:<crc32c>:<key>:<seq>:<filler>
+// 校验和覆盖第二个冒号之后的全部内容，键名写入值中用于发现串键
+const verifiedValuePrefix = "abcv1:"
+
+// maxVerifySamples 保留的异常样本数
+const maxVerifySamples = 10
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// VerifyStats 数据校验统计
+type VerifyStats struct {
+	Writes     int64    `json:"writes"`     // 写入的带校验值
+	Reads      int64    `json:"reads"`      // 校验的读取结果（含键不存在）
+	Valid      int64    `json:"valid"`      // 校验通过
+	Missing    int64    `json:"missing"`    // 键不存在
+	Corrupted  int64    `json:"corrupted"`  // 校验和不匹配或格式损坏
+	Mismatched int64    `json:"mismatched"` // 校验和正确但属于其他键
+	Unverified int64    `json:"unverified"` // 不含校验头（如非verify模式写入的值）
+	ErrorRate  float64  `json:"error_rate"` // (corrupted + mismatched) / reads
+	Samples    []string `json:"samples,omitempty"`
+}
+
+// VerifyTracker 为写入值附加校验和并校验读取结果，校验失败不计为操作错误
+type VerifyTracker struct {
+	sequence   atomic.Int64
+	writes     atomic.Int64
+	reads      atomic.Int64
+	valid      atomic.Int64
+	missing    atomic.Int64
+	corrupted  atomic.Int64
+	mismatched atomic.Int64
+	unverified atomic.Int64
+
+	mutex   sync.Mutex
+	samples []string
+}
+
+// NewVerifyTracker 创建数据校验统计器
+func NewVerifyTracker() *VerifyTracker {
+	return &VerifyTracker{}
+}
+
+// Seal 生成写入identity的带校验值，size为期望的值长度（不足以容纳校验头时自动加长）
+func (v *VerifyTracker) Seal(identity string, size int) string {
+	if v == nil {
+		return ""
+	}
+	v.writes.Add(1)
+
+	body := identity + ":" + strconv.FormatInt(v.sequence.Add(1), 10) + ":"
+	if filler := size - len(verifiedValuePrefix) - 9 - len(body); filler > 0 {
+		body += generateRandomValue(filler)
+	}
+	return fmt.Sprintf("%s%08x:%s", verifiedValuePrefix, crc32.Checksum([]byte(body), castagnoli), body)
+}
+
+// Check 校验identity读取到的值，value为nil表示键不存在
+func (v *VerifyTracker) Check(identity string, value interface{}) {
+	if v == nil {
+		return
+	}
+	v.reads.Add(1)
+
+	var data string
+	switch typed := value.(type) {
+	case nil:
+		v.missing.Add(1)
+		return
+	case string:
+		data = typed
+	case []byte:
+		data = string(typed)
+	default:
+		v.unverified.Add(1)
+		return
+	}
+
+	if !strings.HasPrefix(data, verifiedValuePrefix) {
+		v.unverified.Add(1)
+		return
+	}
+	rest := data[len(verifiedValuePrefix):]
+	if len(rest) < 9 || rest[8] != ':' {
+		v.corrupted.Add(1)
+		v.sample("corrupted", identity, data)
+		return
+	}
+	checksum, err := strconv.ParseUint(rest[:8], 16, 32)
+	body := rest[9:]
+	switch {
+	case err != nil || uint32(checksum) != crc32.Checksum([]byte(body), castagnoli):
+		v.corrupted.Add(1)
+		v.sample("corrupted", identity, data)
+	case !strings.HasPrefix(body, identity+":"):
+		v.mismatched.Add(1)
+		v.sample("mismatched", identity, data)
+	default:
+		v.valid.Add(1)
+	}
+}
+
+// CheckRead 校验单条GET/HGET命令的结果
+func (v *VerifyTracker) CheckRead(operation interfaces.Operation, value interface{}) {
+	if identity, ok := verifyIdentity(operation.Type, operation.Key, operation.Params["field"]); ok {
+		v.Check(identity, value)
+	}
+}
+
+// CheckCmd 校验pipeline中GET/HGET命令的结果，其他命令与执行失败的命令忽略
+func (v *VerifyTracker) CheckCmd(cmd redis.Cmder) {
+	if v == nil {
+		return
+	}
+	stringCmd, ok := cmd.(*redis.StringCmd)
+	if !ok {
+		return
+	}
+	args := cmd.Args()
+	if len(args) < 2 {
+		return
+	}
+	var field interface{}
+	if len(args) > 2 {
+		field = toString(args[2])
+	}
+	identity, ok := verifyIdentity(cmd.Name(), toString(args[1]), field)
+	if !ok {
+		return
+	}
+
+	value, err := stringCmd.Result()
+	switch {
+	case err == redis.Nil:
+		v.Check(identity, nil)
+	case err == nil:
+		v.Check(identity, value)
+	}
+}
+
+// verifyIdentity 读命令所校验值的标识：GET为键名，HGET为键名/字段名，与写入时Seal的标识一致
+func verifyIdentity(operationType, key string, field interface{}) (string, bool) {
+	switch operationType {
+	case "get":
+		return key, true
+	case "hget":
+		name, ok := field.(string)
+		if !ok {
+			return "", false
+		}
+		return key + "/" + name, true
+	default:
+		return "", false
+	}
+}
+
+// sample 保留前若干个异常样本
+func (v *VerifyTracker) sample(kind, identity, value string) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	if len(v.samples) >= maxVerifySamples {
+		return
+	}
+	if len(value) > 64 {
+		value = value[:64] + "..."
+	}
+	v.samples = append(v.samples, fmt.Sprintf("%s %s: %q", kind, identity, value))
+}
+
+// Stats 获取校验统计
+func (v *VerifyTracker) Stats() VerifyStats {
+	stats := VerifyStats{
+		Writes:     v.writes.Load(),
+		Reads:      v.reads.Load(),
+		Valid:      v.valid.Load(),
+		Missing:    v.missing.Load(),
+		Corrupted:  v.corrupted.Load(),
+		Mismatched: v.mismatched.Load(),
+		Unverified: v.unverified.Load(),
+	}
+	if stats.Reads > 0 {
+		stats.ErrorRate = float64(stats.Corrupted+stats.Mismatched) / float64(stats.Reads) * 100
+	}
+	v.mutex.Lock()
+	stats.Samples = append([]string(nil), v.samples...)
+	v.mutex.Unlock()
+	return stats
+}
@@ -0,0 +1,83 @@
+package operation
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	redisConfig "abc-runner/app/adapters/redis/config"
+	"abc-runner/app/adapters/redis/connection"
+	"abc-runner/app/core/interfaces"
+)
+
+func TestRedisExecutor_Verify(t *testing.T) {
+	cfg := redisConfig.NewDefaultRedisConfig()
+	cfg.Standalone.Addr = fakeRESP2Server(t)
+	cfg.Pool.PoolSize = 2
+	cfg.Pool.ConnectionTimeout = 2 * time.Second
+
+	pool, err := connection.NewRedisConnectionPool(cfg)
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	defer pool.Close()
+
+	verifier := NewVerifyTracker()
+	executor := NewRedisExecutor(pool, cfg, nil)
+	executor.SetVerifier(verifier)
+	// 未启用校验的执行器用于模拟其他客户端的写入
+	plain := NewRedisExecutor(pool, cfg, nil)
+
+	run := func(executor *RedisExecutor, operation interfaces.Operation) interface{} {
+		result, err := executor.ExecuteOperation(context.Background(), operation)
+		if err != nil || !result.Success {
+			t.Fatalf("%s %s failed: %v", operation.Type, operation.Key, err)
+		}
+		return result.Value
+	}
+
+	run(executor, interfaces.Operation{Type: "set", Key: "a", Value: strings.Repeat("x", 48)})
+	sealed, _ := run(plain, interfaces.Operation{Type: "get", Key: "a"}).(string)
+	if len(sealed) != 48 || !strings.HasPrefix(sealed, verifiedValuePrefix) {
+		t.Fatalf("unexpected sealed value %q", sealed)
+	}
+
+	run(plain, interfaces.Operation{Type: "set", Key: "b", Value: "plain"})
+	run(plain, interfaces.Operation{Type: "set", Key: "c", Value: sealed})
+	run(plain, interfaces.Operation{Type: "set", Key: "d", Value: sealed[:len(sealed)-1] + "!"})
+
+	for _, key := range []string{"a", "b", "c", "d", "missing"} {
+		run(executor, interfaces.Operation{Type: "get", Key: key})
+	}
+	run(executor, interfaces.Operation{
+		Type: "pipeline",
+		Params: map[string]interface{}{
+			"commands": []interfaces.Operation{
+				{Type: "set", Key: "e", Value: "v"},
+				{Type: "get", Key: "e"},
+			},
+		},
+	})
+
+	stats := verifier.Stats()
+	if stats.Writes != 2 || stats.Reads != 6 || stats.Valid != 2 || stats.Missing != 1 || stats.Unverified != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if stats.Corrupted != 1 || stats.Mismatched != 1 || len(stats.Samples) != 2 {
+		t.Errorf("expected one corrupted and one wrong-key read, got %+v", stats)
+	}
+}
+
+func TestVerifyTracker_HashFields(t *testing.T) {
+	verifier := NewVerifyTracker()
+	value := verifier.Seal("user:1/name", 0)
+
+	verifier.CheckRead(interfaces.Operation{Type: "hget", Key: "user:1", Params: map[string]interface{}{"field": "name"}}, value)
+	verifier.CheckRead(interfaces.Operation{Type: "hget", Key: "user:1", Params: map[string]interface{}{"field": "email"}}, value)
+	verifier.CheckRead(interfaces.Operation{Type: "incr", Key: "user:1"}, value)
+
+	if stats := verifier.Stats(); stats.Reads != 2 || stats.Valid != 1 || stats.Mismatched != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
@@ -0,0 +1,139 @@
+package remotewrite
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"abc-runner/app/adapters/remotewrite/config"
+	"abc-runner/app/adapters/remotewrite/connection"
+	"abc-runner/app/adapters/remotewrite/operations"
+	"abc-runner/app/core/interfaces"
+)
+
+// RemoteWriteAdapter Prometheus remote-write协议适配器 - 遵循统一架构模式
+// 职责：HTTP客户端管理、序列生成、健康检查
+type RemoteWriteAdapter struct {
+	config                *config.RemoteWriteConfig
+	client                *connection.Client
+	remoteWriteOperations *operations.RemoteWriteExecutor
+	metricsCollector      interfaces.DefaultMetricsCollector
+	mu                    sync.RWMutex
+	isConnected           bool
+
+	// 统计信息
+	totalOperations  int64
+	failedOperations int64
+}
+
+// NewRemoteWriteAdapter 创建remote-write适配器
+func NewRemoteWriteAdapter(metricsCollector interfaces.DefaultMetricsCollector) *RemoteWriteAdapter {
+	return &RemoteWriteAdapter{
+		metricsCollector: metricsCollector,
+	}
+}
+
+// Connect 创建HTTP客户端并预生成序列标签
+func (r *RemoteWriteAdapter) Connect(ctx context.Context, cfg interfaces.Config) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rwConfig, ok := cfg.(*config.RemoteWriteConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type for remote-write adapter: expected *config.RemoteWriteConfig, got %T", cfg)
+	}
+
+	if err := rwConfig.Validate(); err != nil {
+		return fmt.Errorf("config validation failed: %w", err)
+	}
+	r.config = rwConfig
+
+	r.client = connection.NewClient(rwConfig)
+	r.remoteWriteOperations = operations.NewRemoteWriteExecutor(r.client, operations.NewSeriesSet(rwConfig),
+		rwConfig.BenchMark.SampleRate, rwConfig.SamplesPerRequest())
+
+	r.isConnected = true
+	return nil
+}
+
+// Execute 执行操作 - 使用执行器处理
+func (r *RemoteWriteAdapter) Execute(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	if !r.isConnected {
+		return nil, fmt.Errorf("adapter not connected")
+	}
+
+	atomic.AddInt64(&r.totalOperations, 1)
+	result, err := r.remoteWriteOperations.ExecuteOperation(ctx, operation)
+	if err != nil {
+		atomic.AddInt64(&r.failedOperations, 1)
+	}
+	return result, err
+}
+
+// Close 关闭空闲连接
+func (r *RemoteWriteAdapter) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.client != nil {
+		r.client.Close()
+		r.client = nil
+	}
+	r.isConnected = false
+	return nil
+}
+
+// GetProtocolMetrics 获取协议特定指标
+func (r *RemoteWriteAdapter) GetProtocolMetrics() map[string]interface{} {
+	metrics := map[string]interface{}{
+		"protocol":          "remotewrite",
+		"total_operations":  atomic.LoadInt64(&r.totalOperations),
+		"failed_operations": atomic.LoadInt64(&r.failedOperations),
+	}
+
+	if r.config != nil {
+		metrics["series"] = r.config.RemoteWriteSpecific.Series
+		metrics["samples_per_request"] = r.config.SamplesPerRequest()
+	}
+	if stats := r.GetIngestStats(); stats != nil {
+		metrics["ingest_stats"] = *stats
+	}
+
+	return metrics
+}
+
+// GetIngestStats 获取写入统计，未连接时返回nil
+func (r *RemoteWriteAdapter) GetIngestStats() *operations.IngestStats {
+	if r.remoteWriteOperations == nil {
+		return nil
+	}
+	stats := r.remoteWriteOperations.IngestStats()
+	return &stats
+}
+
+// HealthCheck 健康检查：发送一个空的WriteRequest，2xx以外的响应视为失败
+func (r *RemoteWriteAdapter) HealthCheck(ctx context.Context) error {
+	if !r.isConnected {
+		return fmt.Errorf("adapter not connected")
+	}
+
+	response, err := r.client.Probe(ctx, r.config.Connection.Timeout)
+	if err != nil {
+		return fmt.Errorf("remote-write endpoint %s is unreachable: %w", r.client.URL(), err)
+	}
+	if response.StatusCode/100 != 2 {
+		return fmt.Errorf("remote-write endpoint %s returned HTTP %d: %s", r.client.URL(), response.StatusCode, response.Message)
+	}
+	return nil
+}
+
+// GetProtocolName 获取协议名称
+func (r *RemoteWriteAdapter) GetProtocolName() string {
+	return "remotewrite"
+}
+
+// GetMetricsCollector 获取指标收集器
+func (r *RemoteWriteAdapter) GetMetricsCollector() interfaces.DefaultMetricsCollector {
+	return r.metricsCollector
+}
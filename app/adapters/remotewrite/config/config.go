@@ -0,0 +1,254 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+)
+
+// RemoteWriteConfig Prometheus remote-write协议配置
+type RemoteWriteConfig struct {
+	Protocol            string                    `yaml:"protocol" json:"protocol"`
+	Connection          ConnectionConfig          `yaml:"connection" json:"connection"`
+	BenchMark           BenchmarkConfig           `yaml:"benchmark" json:"benchmark"`
+	RemoteWriteSpecific RemoteWriteSpecificConfig `yaml:"remote_write_specific" json:"remote_write_specific"`
+}
+
+// ConnectionConfig remote-write连接配置
+type ConnectionConfig struct {
+	URL                string            `yaml:"url" json:"url"` // 如 http://mimir:8080/api/v1/push
+	Timeout            time.Duration     `yaml:"timeout" json:"timeout"`
+	Headers            map[string]string `yaml:"headers" json:"headers"`
+	Tenant             string            `yaml:"tenant" json:"tenant"` // 写入X-Scope-OrgID（Mimir/Cortex/Loki多租户）
+	Username           string            `yaml:"username" json:"username"`
+	Password           string            `yaml:"password" json:"password"`
+	BearerToken        string            `yaml:"bearer_token" json:"bearer_token"`
+	InsecureSkipVerify bool              `yaml:"insecure_skip_verify" json:"insecure_skip_verify"`
+}
+
+// BenchmarkConfig remote-write基准测试配置
+type BenchmarkConfig struct {
+	Total      int           `yaml:"total" json:"total"`         // 总请求数
+	Parallels  int           `yaml:"parallels" json:"parallels"` // 并发写入者数
+	TestCase   string        `yaml:"test_case" json:"test_case"` // "write"
+	Duration   time.Duration `yaml:"duration" json:"duration"`
+	SampleRate int           `yaml:"sample_rate" json:"sample_rate"` // 每秒写入的样本数上限，0表示不限速
+}
+
+// RemoteWriteSpecificConfig remote-write特定配置
+type RemoteWriteSpecificConfig struct {
+	Series           int    `yaml:"series" json:"series"`                         // 序列基数（活跃序列总数）
+	BatchSize        int    `yaml:"batch_size" json:"batch_size"`                 // 每个请求包含的序列数
+	SamplesPerSeries int    `yaml:"samples_per_series" json:"samples_per_series"` // 每个序列在一个请求中的样本数
+	MetricNames      int    `yaml:"metric_names" json:"metric_names"`             // 指标名数量，序列均匀分布在各指标名下
+	ExtraLabels      int    `yaml:"extra_labels" json:"extra_labels"`             // 每个序列额外携带的标签数
+	MetricPrefix     string `yaml:"metric_prefix" json:"metric_prefix"`           // 指标名前缀
+	Job              string `yaml:"job" json:"job"`                               // job标签值，区分多次运行写入的数据
+}
+
+// NewDefaultRemoteWriteConfig 创建默认remote-write配置
+func NewDefaultRemoteWriteConfig() *RemoteWriteConfig {
+	return &RemoteWriteConfig{
+		Protocol: "remotewrite",
+		Connection: ConnectionConfig{
+			URL:     "http://localhost:9090/api/v1/write",
+			Timeout: 30 * time.Second,
+			Headers: make(map[string]string),
+		},
+		BenchMark: BenchmarkConfig{
+			Total:     1000,
+			Parallels: 10,
+			TestCase:  "write",
+		},
+		RemoteWriteSpecific: RemoteWriteSpecificConfig{
+			Series:           10000,
+			BatchSize:        500,
+			SamplesPerSeries: 1,
+			MetricNames:      10,
+			ExtraLabels:      3,
+			MetricPrefix:     "abc_runner_metric",
+			Job:              "abc-runner",
+		},
+	}
+}
+
+// SamplesPerRequest 每个请求包含的样本数
+func (c *RemoteWriteConfig) SamplesPerRequest() int {
+	return c.batchSize() * c.RemoteWriteSpecific.SamplesPerSeries
+}
+
+// Batches 序列被划分成的批次数，请求按批次轮转以覆盖全部序列
+func (c *RemoteWriteConfig) Batches() int {
+	batchSize := c.batchSize()
+	return (c.RemoteWriteSpecific.Series + batchSize - 1) / batchSize
+}
+
+// batchSize 实际批大小，不超过序列基数
+func (c *RemoteWriteConfig) batchSize() int {
+	if c.RemoteWriteSpecific.BatchSize > c.RemoteWriteSpecific.Series {
+		return c.RemoteWriteSpecific.Series
+	}
+	return c.RemoteWriteSpecific.BatchSize
+}
+
+// GetProtocol 实现Config接口
+func (c *RemoteWriteConfig) GetProtocol() string {
+	return c.Protocol
+}
+
+// GetConnection 实现Config接口
+func (c *RemoteWriteConfig) GetConnection() interfaces.ConnectionConfig {
+	return &c.Connection
+}
+
+// GetBenchmark 实现Config接口
+func (c *RemoteWriteConfig) GetBenchmark() interfaces.BenchmarkConfig {
+	return &c.BenchMark
+}
+
+// Validate 实现Config接口
+func (c *RemoteWriteConfig) Validate() error {
+	parsed, err := url.Parse(c.Connection.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("invalid remote-write URL: %q", c.Connection.URL)
+	}
+	if c.Connection.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+	if c.Connection.BearerToken != "" && c.Connection.Username != "" {
+		return fmt.Errorf("bearer token and basic auth cannot be used together")
+	}
+
+	if c.BenchMark.Total <= 0 {
+		return fmt.Errorf("total operations must be greater than 0")
+	}
+	if c.BenchMark.Parallels <= 0 {
+		return fmt.Errorf("parallel connections must be greater than 0")
+	}
+	if c.BenchMark.TestCase != "write" {
+		return fmt.Errorf("invalid test case: %s, valid options: write", c.BenchMark.TestCase)
+	}
+	if c.BenchMark.SampleRate < 0 {
+		return fmt.Errorf("sample rate cannot be negative")
+	}
+
+	specific := c.RemoteWriteSpecific
+	if specific.Series <= 0 {
+		return fmt.Errorf("series must be greater than 0")
+	}
+	if specific.BatchSize <= 0 {
+		return fmt.Errorf("batch size must be greater than 0")
+	}
+	if specific.SamplesPerSeries <= 0 {
+		return fmt.Errorf("samples per series must be greater than 0")
+	}
+	if specific.MetricNames <= 0 {
+		return fmt.Errorf("metric names must be greater than 0")
+	}
+	if specific.ExtraLabels < 0 {
+		return fmt.Errorf("extra labels cannot be negative")
+	}
+	if specific.MetricPrefix == "" {
+		return fmt.Errorf("metric prefix cannot be empty")
+	}
+
+	return nil
+}
+
+// Clone 实现Config接口
+func (c *RemoteWriteConfig) Clone() interfaces.Config {
+	clone := *c
+	clone.Connection.Headers = make(map[string]string, len(c.Connection.Headers))
+	for k, v := range c.Connection.Headers {
+		clone.Connection.Headers[k] = v
+	}
+	return &clone
+}
+
+// ConnectionConfig接口实现
+
+// GetAddresses 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetAddresses() []string {
+	return []string{c.URL}
+}
+
+// GetCredentials 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetCredentials() map[string]string {
+	return map[string]string{
+		"username": c.Username,
+		"password": c.Password,
+	}
+}
+
+// GetPoolConfig 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetPoolConfig() interfaces.PoolConfig {
+	return &EmptyPoolConfig{}
+}
+
+// GetTimeout 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetTimeout() time.Duration {
+	return c.Timeout
+}
+
+// EmptyPoolConfig 空的连接池配置（空闲连接数由并发数决定）
+type EmptyPoolConfig struct{}
+
+func (p *EmptyPoolConfig) GetPoolSize() int                    { return 0 }
+func (p *EmptyPoolConfig) GetMinIdle() int                     { return 0 }
+func (p *EmptyPoolConfig) GetMaxIdle() int                     { return 0 }
+func (p *EmptyPoolConfig) GetIdleTimeout() time.Duration       { return 0 }
+func (p *EmptyPoolConfig) GetConnectionTimeout() time.Duration { return 0 }
+
+// BenchmarkConfig接口实现
+
+// GetTotal 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTotal() int {
+	return b.Total
+}
+
+// GetParallels 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetParallels() int {
+	return b.Parallels
+}
+
+// GetDataSize 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetDataSize() int {
+	return 0
+}
+
+// GetTTL 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTTL() time.Duration {
+	return 0
+}
+
+// GetReadPercent 实现BenchmarkConfig接口，remote-write均为写操作
+func (b *BenchmarkConfig) GetReadPercent() int {
+	return 0
+}
+
+// GetRandomKeys 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetRandomKeys() int {
+	return 0
+}
+
+// GetTestCase 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTestCase() string {
+	return b.TestCase
+}
+
+// GetDuration 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetDuration() time.Duration {
+	return b.Duration
+}
+
+// GetTimeout 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTimeout() time.Duration {
+	return 0
+}
+
+// GetRampUp 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetRampUp() time.Duration {
+	return 0
+}
@@ -0,0 +1,122 @@
+package connection
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"abc-runner/app/adapters/remotewrite/config"
+
+	"github.com/klauspost/compress/s2"
+)
+
+// maxErrorBodySize 错误响应保留的最大字节数
+const maxErrorBodySize = 512
+
+// PushResponse 一次写入请求的响应
+type PushResponse struct {
+	StatusCode int
+	Message    string // 非2xx响应的正文摘要
+}
+
+// Client remote-write HTTP客户端
+type Client struct {
+	url        string
+	headers    http.Header
+	httpClient *http.Client
+}
+
+// NewClient 创建remote-write客户端，空闲连接数与并发数一致
+func NewClient(cfg *config.RemoteWriteConfig) *Client {
+	headers := make(http.Header)
+	headers.Set("Content-Type", "application/x-protobuf")
+	headers.Set("Content-Encoding", "snappy")
+	headers.Set("User-Agent", "abc-runner")
+	headers.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	if cfg.Connection.Tenant != "" {
+		headers.Set("X-Scope-OrgID", cfg.Connection.Tenant)
+	}
+	if cfg.Connection.BearerToken != "" {
+		headers.Set("Authorization", "Bearer "+cfg.Connection.BearerToken)
+	}
+	for name, value := range cfg.Connection.Headers {
+		headers.Set(name, value)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = cfg.BenchMark.Parallels
+	transport.MaxIdleConnsPerHost = cfg.BenchMark.Parallels
+	// 请求体已按snappy压缩，禁止透明压缩响应以免干扰延迟测量
+	transport.DisableCompression = true
+	if cfg.Connection.InsecureSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	client := &Client{
+		url:     cfg.Connection.URL,
+		headers: headers,
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   cfg.Connection.Timeout,
+		},
+	}
+	if cfg.Connection.Username != "" {
+		client.headers.Set("Authorization", basicAuth(cfg.Connection.Username, cfg.Connection.Password))
+	}
+	return client
+}
+
+// basicAuth 构建Basic认证头
+func basicAuth(username, password string) string {
+	request := &http.Request{Header: make(http.Header)}
+	request.SetBasicAuth(username, password)
+	return request.Header.Get("Authorization")
+}
+
+// URL 写入地址
+func (c *Client) URL() string {
+	return c.url
+}
+
+// Push 发送一个已压缩的WriteRequest
+// 返回的错误只表示请求未得到HTTP响应（连接失败、超时等），状态码由调用方判定
+func (c *Client) Push(ctx context.Context, body []byte) (*PushResponse, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	request.Header = c.headers.Clone()
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	result := &PushResponse{StatusCode: response.StatusCode}
+	if response.StatusCode/100 != 2 {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, maxErrorBodySize))
+		result.Message = strings.TrimSpace(string(message))
+	}
+	// 读完响应体以复用连接
+	io.Copy(io.Discard, response.Body)
+	return result, nil
+}
+
+// Close 关闭空闲连接
+func (c *Client) Close() {
+	c.httpClient.CloseIdleConnections()
+}
+
+// Probe 发送一个空的WriteRequest检查端点是否可达
+// 空请求对兼容的接收端是合法的写入，返回的状态码可用于提前发现认证或路径错误
+func (c *Client) Probe(ctx context.Context, timeout time.Duration) (*PushResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return c.Push(ctx, s2.EncodeSnappy(nil, nil))
+}
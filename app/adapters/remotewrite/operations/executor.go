@@ -0,0 +1,88 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"abc-runner/app/adapters/remotewrite/connection"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/utils"
+)
+
+// RemoteWriteExecutor remote-write操作执行器
+type RemoteWriteExecutor struct {
+	client  *connection.Client
+	series  *SeriesSet
+	pacer   *utils.Pacer
+	tracker *IngestTracker
+}
+
+// NewRemoteWriteExecutor 创建remote-write操作执行器
+// sampleRate大于0时按每个请求的样本数换算为请求速率进行限速
+func NewRemoteWriteExecutor(client *connection.Client, series *SeriesSet, sampleRate, samplesPerRequest int) *RemoteWriteExecutor {
+	return &RemoteWriteExecutor{
+		client:  client,
+		series:  series,
+		pacer:   utils.NewRatePacer(float64(sampleRate) / float64(samplesPerRequest)),
+		tracker: NewIngestTracker(),
+	}
+}
+
+// IngestStats 获取写入统计
+func (e *RemoteWriteExecutor) IngestStats() IngestStats {
+	return e.tracker.Stats()
+}
+
+// ExecuteOperation 执行remote-write操作，仅2xx响应视为成功
+func (e *RemoteWriteExecutor) ExecuteOperation(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	if operation.Type != "write" {
+		return nil, fmt.Errorf("unsupported operation type: %s", operation.Type)
+	}
+	batch, ok := operation.Params["batch"].(int)
+	if !ok || batch < 0 || batch >= e.series.Batches() {
+		return nil, fmt.Errorf("invalid batch index: %v", operation.Params["batch"])
+	}
+	if err := e.pacer.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	request, samples := e.series.Build(batch, time.Now())
+	body := Compress(request)
+
+	startTime := time.Now()
+	response, err := e.client.Push(ctx, body)
+	duration := time.Since(startTime)
+
+	statusCode, message := 0, ""
+	if response != nil {
+		statusCode, message = response.StatusCode, response.Message
+		if statusCode/100 != 2 {
+			err = fmt.Errorf("remote write rejected with HTTP %d: %s", statusCode, message)
+		}
+	}
+	e.tracker.Record(statusCode, message, samples, len(body), len(request))
+
+	result := &interfaces.OperationResult{
+		Success:  err == nil,
+		Duration: duration,
+		IsRead:   false,
+		Error:    err,
+		Value:    samples,
+		Metadata: map[string]interface{}{
+			"protocol":       "remotewrite",
+			"operation_type": operation.Type,
+			"batch":          batch,
+			"samples":        samples,
+			"bytes":          len(body),
+			"status_code":    statusCode,
+		},
+	}
+	if response != nil {
+		result.BytesWritten = int64(len(body))
+	}
+	for k, v := range operation.Metadata {
+		result.Metadata[k] = v
+	}
+	return result, err
+}
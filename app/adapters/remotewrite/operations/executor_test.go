@@ -0,0 +1,182 @@
+package operations
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"abc-runner/app/adapters/remotewrite/config"
+	"abc-runner/app/adapters/remotewrite/connection"
+
+	"github.com/klauspost/compress/s2"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// decodedSeries 解码后的序列
+type decodedSeries struct {
+	labels     [][2]string
+	timestamps []int64
+}
+
+// decodeWriteRequest 解码snappy压缩的WriteRequest
+func decodeWriteRequest(t *testing.T, body []byte) []decodedSeries {
+	t.Helper()
+	raw, err := s2.Decode(nil, body)
+	if err != nil {
+		t.Fatalf("snappy decode failed: %v", err)
+	}
+
+	var series []decodedSeries
+	forEachField(t, raw, func(num protowire.Number, value []byte) {
+		var decoded decodedSeries
+		forEachField(t, value, func(num protowire.Number, value []byte) {
+			switch num {
+			case fieldTimeSeriesLabels:
+				var label [2]string
+				forEachField(t, value, func(num protowire.Number, value []byte) {
+					label[num-1] = string(value)
+				})
+				decoded.labels = append(decoded.labels, label)
+			case fieldTimeSeriesSamples:
+				forEachField(t, value, func(num protowire.Number, value []byte) {
+					if num == fieldSampleTimestamp {
+						timestamp, _ := protowire.ConsumeVarint(value)
+						decoded.timestamps = append(decoded.timestamps, int64(timestamp))
+					}
+				})
+			}
+		})
+		series = append(series, decoded)
+	})
+	return series
+}
+
+// forEachField 遍历消息字段，定长与变长字段以原始字节回调，varint字段回调其编码
+func forEachField(t *testing.T, data []byte, fn func(protowire.Number, []byte)) {
+	t.Helper()
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			t.Fatalf("invalid tag")
+		}
+		data = data[n:]
+		var value []byte
+		switch typ {
+		case protowire.BytesType:
+			value, n = protowire.ConsumeBytes(data)
+		case protowire.VarintType:
+			_, n = protowire.ConsumeVarint(data)
+			value = data[:n]
+		case protowire.Fixed64Type:
+			_, n = protowire.ConsumeFixed64(data)
+			value = data[:n]
+		default:
+			t.Fatalf("unexpected wire type %v", typ)
+		}
+		if n < 0 {
+			t.Fatalf("invalid field %d", num)
+		}
+		data = data[n:]
+		fn(num, value)
+	}
+}
+
+func TestRemoteWriteExecutor(t *testing.T) {
+	var mutex sync.Mutex
+	var requests [][]decodedSeries
+	var calls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != "snappy" || r.Header.Get("X-Scope-OrgID") != "bench" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		series := decodeWriteRequest(t, body)
+		mutex.Lock()
+		requests = append(requests, series)
+		mutex.Unlock()
+
+		switch calls.Add(1) {
+		case 3:
+			http.Error(w, "ingestion rate limit exceeded", http.StatusTooManyRequests)
+		case 4:
+			http.Error(w, "ingester unavailable", http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	cfg := config.NewDefaultRemoteWriteConfig()
+	cfg.Connection.URL = server.URL
+	cfg.Connection.Tenant = "bench"
+	cfg.RemoteWriteSpecific.Series = 25
+	cfg.RemoteWriteSpecific.BatchSize = 10
+	cfg.RemoteWriteSpecific.SamplesPerSeries = 2
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("invalid config: %v", err)
+	}
+
+	series := NewSeriesSet(cfg)
+	if series.Batches() != 3 {
+		t.Fatalf("batches = %d, want 3", series.Batches())
+	}
+	executor := NewRemoteWriteExecutor(connection.NewClient(cfg), series, 0, cfg.SamplesPerRequest())
+	factory := NewOperationFactory("write", series.Batches())
+
+	for job := 0; job < 6; job++ {
+		result, err := executor.ExecuteOperation(context.Background(), factory.CreateOperation(job, nil))
+		if (err == nil) != (job != 2 && job != 3) || result.Success != (err == nil) {
+			t.Fatalf("job %d: success=%v err=%v", job, result.Success, err)
+		}
+	}
+
+	// 最后一个批次只有5个序列；标签有序；同一批次的时间戳跨请求严格递增
+	if len(requests[0]) != 10 || len(requests[2]) != 5 {
+		t.Fatalf("unexpected batch sizes: %d, %d", len(requests[0]), len(requests[2]))
+	}
+	for _, s := range requests[0] {
+		if len(s.labels) != 6 || s.labels[0][0] != "__name__" || len(s.timestamps) != 2 {
+			t.Fatalf("unexpected series: %+v", s)
+		}
+		for i := 1; i < len(s.labels); i++ {
+			if s.labels[i-1][0] >= s.labels[i][0] {
+				t.Fatalf("labels not sorted: %+v", s.labels)
+			}
+		}
+	}
+	if requests[3][0].timestamps[0] <= requests[0][0].timestamps[1] {
+		t.Fatalf("timestamps did not increase across requests: %v then %v",
+			requests[0][0].timestamps, requests[3][0].timestamps)
+	}
+
+	stats := executor.IngestStats()
+	if stats.Requests != 6 || stats.Accepted != 4 || stats.Throttled != 1 || stats.ServerErrors != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	if stats.SamplesSent != 100 || stats.SamplesAccepted != 70 || stats.ErrorSamples[429] != "ingestion rate limit exceeded" {
+		t.Fatalf("unexpected sample stats: %+v", stats)
+	}
+}
+
+func TestSeriesSetTimestampsUnique(t *testing.T) {
+	cfg := config.NewDefaultRemoteWriteConfig()
+	cfg.RemoteWriteSpecific.Series = 1
+	cfg.RemoteWriteSpecific.SamplesPerSeries = 3
+	series := NewSeriesSet(cfg)
+
+	// 同一毫秒内的两次写入不能产生重复时间戳
+	now := time.Now()
+	first, _ := series.Build(0, now)
+	second, _ := series.Build(0, now)
+	a := decodeWriteRequest(t, Compress(first))[0].timestamps
+	b := decodeWriteRequest(t, Compress(second))[0].timestamps
+	if a[2] != now.UnixMilli() || b[0] != a[2]+1 {
+		t.Fatalf("unexpected timestamps: %v then %v", a, b)
+	}
+}
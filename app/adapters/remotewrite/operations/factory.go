@@ -0,0 +1,44 @@
+package operations
+
+import (
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+)
+
+// OperationFactory remote-write操作工厂，按任务序号在批次间轮转以覆盖全部序列
+type OperationFactory struct {
+	testCase string
+	batches  int
+}
+
+// NewOperationFactory 创建remote-write操作工厂
+func NewOperationFactory(testCase string, batches int) *OperationFactory {
+	return &OperationFactory{
+		testCase: testCase,
+		batches:  batches,
+	}
+}
+
+// CreateOperation 创建操作
+func (f *OperationFactory) CreateOperation(jobID int, config execution.BenchmarkConfig) interfaces.Operation {
+	return interfaces.Operation{
+		Type: f.testCase,
+		Params: map[string]interface{}{
+			"job_id": jobID,
+			"batch":  jobID % f.batches,
+		},
+		Metadata: map[string]string{
+			"operation_type": f.testCase,
+		},
+	}
+}
+
+// GetOperationType 获取操作类型
+func (f *OperationFactory) GetOperationType() string {
+	return f.testCase
+}
+
+// GetSupportedOperations 获取支持的操作类型
+func (f *OperationFactory) GetSupportedOperations() []string {
+	return []string{"write"}
+}
@@ -0,0 +1,141 @@
+package operations
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"abc-runner/app/adapters/remotewrite/config"
+
+	"github.com/klauspost/compress/s2"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// prompb.WriteRequest/TimeSeries/Label/Sample的字段号
+const (
+	fieldWriteRequestTimeSeries protowire.Number = 1
+	fieldTimeSeriesLabels       protowire.Number = 1
+	fieldTimeSeriesSamples      protowire.Number = 2
+	fieldLabelName              protowire.Number = 1
+	fieldLabelValue             protowire.Number = 2
+	fieldSampleValue            protowire.Number = 1
+	fieldSampleTimestamp        protowire.Number = 2
+)
+
+// batchState 单个批次的写入进度
+type batchState struct {
+	mutex         sync.Mutex
+	lastTimestamp int64 // 已写入的最大时间戳（毫秒）
+	value         float64
+}
+
+// SeriesSet 生成remote-write请求的序列集合
+// 序列按批次划分，每个请求写入一个批次；同一批次的时间戳严格递增，避免接收端判定为乱序或重复样本
+type SeriesSet struct {
+	labels           [][]byte // 每个序列预编码的标签字段
+	batchSize        int
+	samplesPerSeries int
+	batches          []batchState
+}
+
+// NewSeriesSet 根据配置生成序列集合并预编码标签
+func NewSeriesSet(cfg *config.RemoteWriteConfig) *SeriesSet {
+	specific := cfg.RemoteWriteSpecific
+	set := &SeriesSet{
+		labels:           make([][]byte, specific.Series),
+		batchSize:        specific.BatchSize,
+		samplesPerSeries: specific.SamplesPerSeries,
+		batches:          make([]batchState, cfg.Batches()),
+	}
+	for i := range set.labels {
+		set.labels[i] = encodeLabels(seriesLabels(specific, i))
+	}
+	return set
+}
+
+// seriesLabels 第i个序列的标签
+// series_id保证序列唯一，额外标签取值基数较低，模拟method、status等常见维度
+func seriesLabels(specific config.RemoteWriteSpecificConfig, i int) [][2]string {
+	labels := [][2]string{
+		{"__name__", specific.MetricPrefix + "_" + strconv.Itoa(i%specific.MetricNames)},
+		{"job", specific.Job},
+		{"series_id", strconv.Itoa(i)},
+	}
+	for k := 0; k < specific.ExtraLabels; k++ {
+		value := (i / specific.MetricNames) % ((k + 1) * 10)
+		labels = append(labels, [2]string{"label_" + strconv.Itoa(k), "value_" + strconv.Itoa(value)})
+	}
+	// remote-write要求标签按名称排序
+	sort.Slice(labels, func(a, b int) bool { return labels[a][0] < labels[b][0] })
+	return labels
+}
+
+// encodeLabels 编码TimeSeries的labels字段
+func encodeLabels(labels [][2]string) []byte {
+	var encoded []byte
+	for _, label := range labels {
+		var body []byte
+		body = protowire.AppendTag(body, fieldLabelName, protowire.BytesType)
+		body = protowire.AppendString(body, label[0])
+		body = protowire.AppendTag(body, fieldLabelValue, protowire.BytesType)
+		body = protowire.AppendString(body, label[1])
+
+		encoded = protowire.AppendTag(encoded, fieldTimeSeriesLabels, protowire.BytesType)
+		encoded = protowire.AppendBytes(encoded, body)
+	}
+	return encoded
+}
+
+// Batches 批次数
+func (s *SeriesSet) Batches() int {
+	return len(s.batches)
+}
+
+// Build 为指定批次构建WriteRequest，返回未压缩的protobuf与样本数
+func (s *SeriesSet) Build(batch int, now time.Time) ([]byte, int) {
+	first := batch * s.batchSize
+	last := first + s.batchSize
+	if last > len(s.labels) {
+		last = len(s.labels)
+	}
+
+	// 为本批次分配连续的毫秒时间戳，最后一个样本不早于当前时间
+	state := &s.batches[batch]
+	state.mutex.Lock()
+	start := now.UnixMilli() - int64(s.samplesPerSeries-1)
+	if start <= state.lastTimestamp {
+		start = state.lastTimestamp + 1
+	}
+	state.lastTimestamp = start + int64(s.samplesPerSeries-1)
+	baseValue := state.value
+	state.value += float64(s.samplesPerSeries)
+	state.mutex.Unlock()
+
+	var samples []byte
+	for j := 0; j < s.samplesPerSeries; j++ {
+		var sample []byte
+		sample = protowire.AppendTag(sample, fieldSampleValue, protowire.Fixed64Type)
+		sample = protowire.AppendFixed64(sample, math.Float64bits(baseValue+float64(j+1)))
+		sample = protowire.AppendTag(sample, fieldSampleTimestamp, protowire.VarintType)
+		sample = protowire.AppendVarint(sample, uint64(start+int64(j)))
+
+		samples = protowire.AppendTag(samples, fieldTimeSeriesSamples, protowire.BytesType)
+		samples = protowire.AppendBytes(samples, sample)
+	}
+
+	var request []byte
+	for i := first; i < last; i++ {
+		request = protowire.AppendTag(request, fieldWriteRequestTimeSeries, protowire.BytesType)
+		request = protowire.AppendVarint(request, uint64(len(s.labels[i])+len(samples)))
+		request = append(request, s.labels[i]...)
+		request = append(request, samples...)
+	}
+	return request, (last - first) * s.samplesPerSeries
+}
+
+// Compress 使用snappy块格式压缩请求体（remote-write 1.0规定的编码）
+func Compress(request []byte) []byte {
+	return s2.EncodeSnappy(nil, request)
+}
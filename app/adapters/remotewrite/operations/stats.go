@@ -0,0 +1,92 @@
+package operations
+
+import (
+	"sync"
+)
+
+// IngestStats remote-write写入统计
+type IngestStats struct {
+	Requests          int64          `json:"requests"`
+	Accepted          int64          `json:"accepted"`         // 2xx
+	Throttled         int64          `json:"throttled"`        // 429
+	ServerErrors      int64          `json:"server_errors"`    // 5xx
+	ClientErrors      int64          `json:"client_errors"`    // 429以外的4xx，如乱序、超出限制
+	TransportErrors   int64          `json:"transport_errors"` // 未收到HTTP响应
+	ThrottleRate      float64        `json:"throttle_rate"`
+	ServerErrorRate   float64        `json:"server_error_rate"`
+	SamplesSent       int64          `json:"samples_sent"`
+	SamplesAccepted   int64          `json:"samples_accepted"`
+	BytesSent         int64          `json:"bytes_sent"` // snappy压缩后的请求体字节数
+	UncompressedBytes int64          `json:"uncompressed_bytes"`
+	StatusCodes       map[int]int64  `json:"status_codes"`
+	ErrorSamples      map[int]string `json:"error_samples,omitempty"` // 每个非2xx状态码首次出现时的响应正文
+}
+
+// IngestTracker 统计写入请求的响应分布
+type IngestTracker struct {
+	mutex sync.Mutex
+	stats IngestStats
+}
+
+// NewIngestTracker 创建写入统计器
+func NewIngestTracker() *IngestTracker {
+	return &IngestTracker{
+		stats: IngestStats{
+			StatusCodes:  make(map[int]int64),
+			ErrorSamples: make(map[int]string),
+		},
+	}
+}
+
+// Record 记录一次请求，statusCode为0表示未收到响应
+func (t *IngestTracker) Record(statusCode int, message string, samples, compressed, uncompressed int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.stats.Requests++
+	t.stats.SamplesSent += int64(samples)
+	t.stats.BytesSent += int64(compressed)
+	t.stats.UncompressedBytes += int64(uncompressed)
+
+	switch {
+	case statusCode == 0:
+		t.stats.TransportErrors++
+		return
+	case statusCode/100 == 2:
+		t.stats.Accepted++
+		t.stats.SamplesAccepted += int64(samples)
+	case statusCode == 429:
+		t.stats.Throttled++
+	case statusCode/100 == 5:
+		t.stats.ServerErrors++
+	default:
+		t.stats.ClientErrors++
+	}
+	t.stats.StatusCodes[statusCode]++
+	if statusCode/100 != 2 {
+		if _, ok := t.stats.ErrorSamples[statusCode]; !ok {
+			t.stats.ErrorSamples[statusCode] = message
+		}
+	}
+}
+
+// Stats 获取统计快照
+func (t *IngestTracker) Stats() IngestStats {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	stats := t.stats
+	stats.StatusCodes = make(map[int]int64, len(t.stats.StatusCodes))
+	for code, count := range t.stats.StatusCodes {
+		stats.StatusCodes[code] = count
+	}
+	stats.ErrorSamples = make(map[int]string, len(t.stats.ErrorSamples))
+	for code, message := range t.stats.ErrorSamples {
+		stats.ErrorSamples[code] = message
+	}
+	if stats.Requests > 0 {
+		stats.ThrottleRate = float64(stats.Throttled) / float64(stats.Requests) * 100
+		stats.ServerErrorRate = float64(stats.ServerErrors) / float64(stats.Requests) * 100
+	}
+	return stats
+}
@@ -0,0 +1,210 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"abc-runner/app/adapters/s3/config"
+	"abc-runner/app/adapters/s3/connection"
+	"abc-runner/app/adapters/s3/operations"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+)
+
+// S3Adapter S3兼容对象存储协议适配器 - 遵循统一架构模式
+// 职责：REST客户端管理、存储桶创建与测试对象清理、健康检查
+type S3Adapter struct {
+	config           *config.S3Config
+	client           *connection.Client
+	s3Operations     *operations.S3Executor
+	metricsCollector interfaces.DefaultMetricsCollector
+	bucketCreated    bool
+	mu               sync.RWMutex
+	isConnected      bool
+
+	// 统计信息
+	totalOperations  int64
+	failedOperations int64
+}
+
+// NewS3Adapter 创建S3适配器
+func NewS3Adapter(metricsCollector interfaces.DefaultMetricsCollector) *S3Adapter {
+	return &S3Adapter{
+		metricsCollector: metricsCollector,
+	}
+}
+
+// Connect 创建REST客户端，配置了create_bucket时在存储桶不存在时创建
+func (s *S3Adapter) Connect(ctx context.Context, cfg interfaces.Config) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s3Config, ok := cfg.(*config.S3Config)
+	if !ok {
+		return fmt.Errorf("invalid config type for S3 adapter: expected *config.S3Config, got %T", cfg)
+	}
+
+	if err := s3Config.Validate(); err != nil {
+		return fmt.Errorf("config validation failed: %w", err)
+	}
+	s.config = s3Config
+
+	client, err := connection.NewClient(s3Config)
+	if err != nil {
+		return err
+	}
+	if s3Config.S3Specific.CreateBucket {
+		ctx, cancel := context.WithTimeout(ctx, s3Config.Connection.Timeout)
+		created, err := client.CreateBucket(ctx, s3Config.Connection.Region)
+		cancel()
+		if err != nil {
+			client.Close()
+			return fmt.Errorf("failed to create bucket %s: %w", s3Config.S3Specific.Bucket, err)
+		}
+		s.bucketCreated = created
+	}
+
+	s.client = client
+	s.s3Operations = operations.NewS3Executor(client, s3Config)
+	s.isConnected = true
+	return nil
+}
+
+// Execute 执行操作 - 使用执行器处理
+func (s *S3Adapter) Execute(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	if !s.isConnected {
+		return nil, fmt.Errorf("adapter not connected")
+	}
+
+	atomic.AddInt64(&s.totalOperations, 1)
+	result, err := s.s3Operations.ExecuteOperation(ctx, operation)
+	if err != nil {
+		atomic.AddInt64(&s.failedOperations, 1)
+	}
+	return result, err
+}
+
+// Close 配置了cleanup时删除前缀下的全部对象，然后关闭空闲连接
+func (s *S3Adapter) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var err error
+	if s.client != nil {
+		if s.config.S3Specific.Cleanup {
+			_, err = s.deletePrefix(context.Background())
+		}
+		s.client.Close()
+		s.client = nil
+	}
+	s.isConnected = false
+	return err
+}
+
+// deletePrefix 逐页列出前缀下的对象并批量删除，返回删除的对象数
+func (s *S3Adapter) deletePrefix(ctx context.Context) (int, error) {
+	deleted := 0
+	for {
+		pageCtx, cancel := context.WithTimeout(ctx, s.config.Connection.Timeout)
+		page, err := s.client.ListObjects(pageCtx, s.config.S3Specific.Prefix, 1000, "")
+		if err == nil && len(page.Keys) > 0 {
+			err = s.client.DeleteObjects(pageCtx, page.Keys)
+		}
+		cancel()
+		if err != nil {
+			return deleted, fmt.Errorf("failed to clean up %s: %w", s.config.S3Specific.Prefix, err)
+		}
+		// 删除后重新从第一页列出，直到前缀下没有对象
+		deleted += len(page.Keys)
+		if !page.IsTruncated || len(page.Keys) == 0 {
+			return deleted, nil
+		}
+	}
+}
+
+// GetProtocolMetrics 获取协议特定指标
+func (s *S3Adapter) GetProtocolMetrics() map[string]interface{} {
+	metrics := map[string]interface{}{
+		"protocol":          "s3",
+		"total_operations":  atomic.LoadInt64(&s.totalOperations),
+		"failed_operations": atomic.LoadInt64(&s.failedOperations),
+	}
+
+	if s.config != nil {
+		metrics["endpoint"] = s.config.Connection.Endpoint
+		metrics["bucket"] = s.config.S3Specific.Bucket
+		metrics["prefix"] = s.config.S3Specific.Prefix
+	}
+	if stats := s.GetOperationStats(); stats != nil {
+		metrics["operation_stats"] = stats
+	}
+	if latency := s.GetFirstByteLatency(); latency != nil {
+		metrics["first_byte_latency"] = latency
+	}
+
+	return metrics
+}
+
+// GetOperationStats 获取按操作类型的统计，未连接时返回nil
+func (s *S3Adapter) GetOperationStats() []operations.OperationStats {
+	if s.s3Operations == nil {
+		return nil
+	}
+	return s.s3Operations.OperationStats()
+}
+
+// GetFirstByteLatency 获取get的首字节时间统计，未连接或没有成功的get时返回nil
+func (s *S3Adapter) GetFirstByteLatency() *metrics.LatencyMetrics {
+	if s.s3Operations == nil {
+		return nil
+	}
+	latency := s.s3Operations.FirstByteLatency()
+	if latency.Max == 0 {
+		return nil
+	}
+	return &latency
+}
+
+// GetUploadedParts 获取分片上传中上传成功的分片数
+func (s *S3Adapter) GetUploadedParts() int64 {
+	if s.s3Operations == nil {
+		return 0
+	}
+	return s.s3Operations.UploadedParts()
+}
+
+// BucketCreated create_bucket是否新建了存储桶
+func (s *S3Adapter) BucketCreated() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.bucketCreated
+}
+
+// HealthCheck 健康检查：检查存储桶是否存在且凭证有权访问
+func (s *S3Adapter) HealthCheck(ctx context.Context) error {
+	if !s.isConnected {
+		return fmt.Errorf("adapter not connected")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.config.Connection.Timeout)
+	defer cancel()
+	if err := s.client.HeadBucket(ctx); err != nil {
+		if responseErr, ok := err.(*connection.ResponseError); ok && responseErr.StatusCode == 404 {
+			return fmt.Errorf("bucket %s does not exist (use --create-bucket to create it)", s.config.S3Specific.Bucket)
+		}
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	return nil
+}
+
+// GetProtocolName 获取协议名称
+func (s *S3Adapter) GetProtocolName() string {
+	return "s3"
+}
+
+// GetMetricsCollector 获取指标收集器
+func (s *S3Adapter) GetMetricsCollector() interfaces.DefaultMetricsCollector {
+	return s.metricsCollector
+}
@@ -0,0 +1,390 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+)
+
+// 操作类型，同时也是mixed以外的测试用例名
+const (
+	OperationPut    = "put"    // 上传一个对象，大小不小于multipart_threshold时分片上传
+	OperationGet    = "get"    // 下载一个对象并读取完整内容
+	OperationDelete = "delete" // 删除一个对象
+	OperationList   = "list"   // 按前缀列出一页对象（ListObjectsV2）
+)
+
+// TestCaseMixed 按read_percent混合get与put
+const TestCaseMixed = "mixed"
+
+// S3协议限制
+const (
+	MinPartSize = 5 << 20 // 除最后一个分片外，分片不能小于5MiB
+	MaxParts    = 10000   // 一次分片上传最多10000个分片
+)
+
+// bucketNamePattern 存储桶命名规则（小写字母、数字、点与连字符，3到63个字符）
+var bucketNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+
+// S3Config S3兼容对象存储协议配置
+type S3Config struct {
+	Protocol   string           `yaml:"protocol" json:"protocol"`
+	Connection ConnectionConfig `yaml:"connection" json:"connection"`
+	BenchMark  BenchmarkConfig  `yaml:"benchmark" json:"benchmark"`
+	S3Specific S3SpecificConfig `yaml:"s3_specific" json:"s3_specific"`
+}
+
+// ConnectionConfig 连接配置
+type ConnectionConfig struct {
+	Endpoint           string        `yaml:"endpoint" json:"endpoint"` // 服务地址，如 http://localhost:9000 或 https://s3.us-east-1.amazonaws.com
+	Region             string        `yaml:"region" json:"region"`     // 签名使用的区域，MinIO默认为us-east-1
+	AccessKey          string        `yaml:"access_key" json:"access_key"`
+	SecretKey          string        `yaml:"secret_key" json:"secret_key"`
+	SessionToken       string        `yaml:"session_token" json:"session_token"` // 临时凭证的会话令牌
+	PathStyle          bool          `yaml:"path_style" json:"path_style"`       // 路径风格寻址（endpoint/bucket/key），为false时使用虚拟主机风格（bucket.endpoint/key）
+	Timeout            time.Duration `yaml:"timeout" json:"timeout"`
+	InsecureSkipVerify bool          `yaml:"insecure_skip_verify" json:"insecure_skip_verify"`
+}
+
+// BenchmarkConfig 基准测试配置
+type BenchmarkConfig struct {
+	Total       int           `yaml:"total" json:"total"`
+	Parallels   int           `yaml:"parallels" json:"parallels"`
+	TestCase    string        `yaml:"test_case" json:"test_case"` // put、get、delete、list或mixed
+	Duration    time.Duration `yaml:"duration" json:"duration"`
+	ReadPercent int           `yaml:"read_percent" json:"read_percent"` // mixed用例中get所占的百分比
+}
+
+// S3SpecificConfig S3特定配置
+type S3SpecificConfig struct {
+	Bucket  string `yaml:"bucket" json:"bucket"`
+	Prefix  string `yaml:"prefix" json:"prefix"`   // 对象键前缀，list按此前缀列出
+	Objects int    `yaml:"objects" json:"objects"` // 对象键空间，第N个操作访问第N%objects个对象
+
+	// ObjectSizes 对象大小分布，按权重随机选择一项，再在[min, max]内均匀取值
+	ObjectSizes []ObjectSize `yaml:"object_sizes" json:"object_sizes"`
+
+	MultipartThreshold int64 `yaml:"multipart_threshold" json:"multipart_threshold"` // 不小于此大小的对象分片上传
+	PartSize           int64 `yaml:"part_size" json:"part_size"`                     // 分片大小
+	PartConcurrency    int   `yaml:"part_concurrency" json:"part_concurrency"`       // 一次分片上传中并发上传的分片数
+
+	ListMaxKeys  int  `yaml:"list_max_keys" json:"list_max_keys"` // list每页返回的最大对象数
+	CreateBucket bool `yaml:"create_bucket" json:"create_bucket"` // 运行前在存储桶不存在时创建
+	Cleanup      bool `yaml:"cleanup" json:"cleanup"`             // 运行结束后删除前缀下的全部对象
+}
+
+// ObjectSize 对象大小分布中的一项，max为0时大小固定为min
+type ObjectSize struct {
+	Min    int64 `yaml:"min" json:"min"`
+	Max    int64 `yaml:"max" json:"max"`
+	Weight int   `yaml:"weight" json:"weight"`
+}
+
+// String 以 64KiB 或 1MiB-4MiB 的形式描述大小
+func (s ObjectSize) String() string {
+	if s.Max <= s.Min {
+		return FormatSize(s.Min)
+	}
+	return FormatSize(s.Min) + "-" + FormatSize(s.Max)
+}
+
+// NewDefaultS3Config 创建默认S3配置
+func NewDefaultS3Config() *S3Config {
+	return &S3Config{
+		Protocol: "s3",
+		Connection: ConnectionConfig{
+			Endpoint:  "http://localhost:9000",
+			Region:    "us-east-1",
+			PathStyle: true,
+			Timeout:   60 * time.Second,
+		},
+		BenchMark: BenchmarkConfig{
+			Total:       1000,
+			Parallels:   10,
+			TestCase:    OperationPut,
+			ReadPercent: 50,
+		},
+		S3Specific: S3SpecificConfig{
+			Bucket:             "abc-bench",
+			Prefix:             "abc-bench/",
+			Objects:            1000,
+			ObjectSizes:        []ObjectSize{{Min: 64 << 10, Weight: 1}},
+			MultipartThreshold: 64 << 20,
+			PartSize:           16 << 20,
+			PartConcurrency:    4,
+			ListMaxKeys:        1000,
+		},
+	}
+}
+
+// GetProtocol 实现Config接口
+func (c *S3Config) GetProtocol() string {
+	return c.Protocol
+}
+
+// GetConnection 实现Config接口
+func (c *S3Config) GetConnection() interfaces.ConnectionConfig {
+	return &c.Connection
+}
+
+// GetBenchmark 实现Config接口
+func (c *S3Config) GetBenchmark() interfaces.BenchmarkConfig {
+	return &c.BenchMark
+}
+
+// Validate 实现Config接口
+func (c *S3Config) Validate() error {
+	endpoint, err := url.Parse(c.Connection.Endpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return fmt.Errorf("invalid endpoint: %q (expected http(s)://host[:port])", c.Connection.Endpoint)
+	}
+	if c.Connection.Region == "" {
+		return fmt.Errorf("region is required")
+	}
+	if (c.Connection.AccessKey == "") != (c.Connection.SecretKey == "") {
+		return fmt.Errorf("access key and secret key must be set together")
+	}
+	if c.Connection.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+
+	if c.BenchMark.Total <= 0 {
+		return fmt.Errorf("total operations must be greater than 0")
+	}
+	if c.BenchMark.Parallels <= 0 {
+		return fmt.Errorf("parallel connections must be greater than 0")
+	}
+	switch c.BenchMark.TestCase {
+	case OperationPut, OperationGet, OperationDelete, OperationList:
+	case TestCaseMixed:
+		if c.BenchMark.ReadPercent < 0 || c.BenchMark.ReadPercent > 100 {
+			return fmt.Errorf("read percent must be between 0 and 100")
+		}
+	default:
+		return fmt.Errorf("invalid test case: %s, valid options: %s, %s, %s, %s, %s", c.BenchMark.TestCase,
+			OperationPut, OperationGet, OperationDelete, OperationList, TestCaseMixed)
+	}
+
+	specific := c.S3Specific
+	if !bucketNamePattern.MatchString(specific.Bucket) {
+		return fmt.Errorf("invalid bucket name: %q (3-63 lowercase letters, digits, dots and hyphens)", specific.Bucket)
+	}
+	if specific.Objects <= 0 {
+		return fmt.Errorf("objects must be greater than 0")
+	}
+	if len(specific.ObjectSizes) == 0 {
+		return fmt.Errorf("at least one object size is required")
+	}
+	for _, size := range specific.ObjectSizes {
+		if size.Min < 0 || (size.Max != 0 && size.Max < size.Min) {
+			return fmt.Errorf("invalid object size range: %d-%d", size.Min, size.Max)
+		}
+		if size.Weight <= 0 {
+			return fmt.Errorf("object size %s: weight must be greater than 0", size)
+		}
+	}
+	if specific.PartSize < MinPartSize {
+		return fmt.Errorf("part size must be at least %s", FormatSize(MinPartSize))
+	}
+	if specific.MultipartThreshold <= 0 {
+		return fmt.Errorf("multipart threshold must be greater than 0")
+	}
+	if parts := (c.MaxObjectSize() + specific.PartSize - 1) / specific.PartSize; parts > MaxParts {
+		return fmt.Errorf("object size %s needs %d parts of %s, more than the limit of %d",
+			FormatSize(c.MaxObjectSize()), parts, FormatSize(specific.PartSize), MaxParts)
+	}
+	if specific.PartConcurrency <= 0 {
+		return fmt.Errorf("part concurrency must be greater than 0")
+	}
+	if specific.ListMaxKeys <= 0 || specific.ListMaxKeys > 1000 {
+		return fmt.Errorf("list max keys must be between 1 and 1000")
+	}
+
+	return nil
+}
+
+// MaxObjectSize 对象大小分布中的最大值
+func (c *S3Config) MaxObjectSize() int64 {
+	var largest int64
+	for _, size := range c.S3Specific.ObjectSizes {
+		largest = max(largest, size.Min, size.Max)
+	}
+	return largest
+}
+
+// Clone 实现Config接口
+func (c *S3Config) Clone() interfaces.Config {
+	clone := *c
+	clone.S3Specific.ObjectSizes = append([]ObjectSize(nil), c.S3Specific.ObjectSizes...)
+	return &clone
+}
+
+// ParseObjectSizes 解析对象大小分布，如 "64KiB" 或 "4KiB:60,1MiB:30,16MiB-64MiB:10"
+// 每项为 SIZE 或 MIN-MAX，可带 :WEIGHT 权重（默认1）
+func ParseObjectSizes(spec string) ([]ObjectSize, error) {
+	var sizes []ObjectSize
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		size := ObjectSize{Weight: 1}
+		if sizeSpec, weight, found := strings.Cut(entry, ":"); found {
+			var err error
+			if size.Weight, err = strconv.Atoi(weight); err != nil {
+				return nil, fmt.Errorf("invalid weight in %q", entry)
+			}
+			entry = sizeSpec
+		}
+		minSpec, maxSpec, isRange := strings.Cut(entry, "-")
+		var err error
+		if size.Min, err = ParseSize(minSpec); err != nil {
+			return nil, err
+		}
+		if isRange {
+			if size.Max, err = ParseSize(maxSpec); err != nil {
+				return nil, err
+			}
+		}
+		sizes = append(sizes, size)
+	}
+	if len(sizes) == 0 {
+		return nil, fmt.Errorf("empty object size list")
+	}
+	return sizes, nil
+}
+
+// sizeUnits 大小单位，均按1024进制
+var sizeUnits = []struct {
+	suffix string
+	bytes  int64
+}{
+	{"GiB", 1 << 30}, {"MiB", 1 << 20}, {"KiB", 1 << 10},
+	{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10},
+	{"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}, {"B", 1},
+}
+
+// ParseSize 解析带单位的大小，如 512、4KiB、16MB、1G（单位均按1024进制）
+func ParseSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	multiplier := int64(1)
+	for _, unit := range sizeUnits {
+		if strings.HasSuffix(strings.ToUpper(s), strings.ToUpper(unit.suffix)) {
+			s = strings.TrimSpace(s[:len(s)-len(unit.suffix)])
+			multiplier = unit.bytes
+			break
+		}
+	}
+	value, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid size: %q", s)
+	}
+	return value * multiplier, nil
+}
+
+// FormatSize 以最大的整除单位输出大小，如 65536 输出为 64KiB
+func FormatSize(bytes int64) string {
+	for _, unit := range sizeUnits[:3] {
+		if bytes >= unit.bytes && bytes%unit.bytes == 0 {
+			return strconv.FormatInt(bytes/unit.bytes, 10) + unit.suffix
+		}
+	}
+	return strconv.FormatInt(bytes, 10) + "B"
+}
+
+// ConnectionConfig接口实现
+
+// GetAddresses 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetAddresses() []string {
+	return []string{c.Endpoint}
+}
+
+// GetCredentials 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetCredentials() map[string]string {
+	return map[string]string{
+		"access_key": c.AccessKey,
+		"secret_key": c.SecretKey,
+	}
+}
+
+// GetPoolConfig 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetPoolConfig() interfaces.PoolConfig {
+	return &EmptyPoolConfig{}
+}
+
+// GetTimeout 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetTimeout() time.Duration {
+	return c.Timeout
+}
+
+// EmptyPoolConfig 空的连接池配置（空闲连接数由并发数决定）
+type EmptyPoolConfig struct{}
+
+func (p *EmptyPoolConfig) GetPoolSize() int                    { return 0 }
+func (p *EmptyPoolConfig) GetMinIdle() int                     { return 0 }
+func (p *EmptyPoolConfig) GetMaxIdle() int                     { return 0 }
+func (p *EmptyPoolConfig) GetIdleTimeout() time.Duration       { return 0 }
+func (p *EmptyPoolConfig) GetConnectionTimeout() time.Duration { return 0 }
+
+// BenchmarkConfig接口实现
+
+// GetTotal 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTotal() int {
+	return b.Total
+}
+
+// GetParallels 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetParallels() int {
+	return b.Parallels
+}
+
+// GetDataSize 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetDataSize() int {
+	return 0
+}
+
+// GetTTL 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTTL() time.Duration {
+	return 0
+}
+
+// GetReadPercent 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetReadPercent() int {
+	switch b.TestCase {
+	case OperationGet, OperationList:
+		return 100
+	case TestCaseMixed:
+		return b.ReadPercent
+	}
+	return 0
+}
+
+// GetRandomKeys 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetRandomKeys() int {
+	return 0
+}
+
+// GetTestCase 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTestCase() string {
+	return b.TestCase
+}
+
+// GetDuration 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetDuration() time.Duration {
+	return b.Duration
+}
+
+// GetTimeout 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTimeout() time.Duration {
+	return 0
+}
+
+// GetRampUp 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetRampUp() time.Duration {
+	return 0
+}
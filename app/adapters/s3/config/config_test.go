@@ -0,0 +1,72 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*S3Config)
+		valid  bool
+	}{
+		{"default", func(c *S3Config) {}, true},
+		{"mixed", func(c *S3Config) {
+			c.BenchMark.TestCase = TestCaseMixed
+			c.BenchMark.ReadPercent = 80
+		}, true},
+		{"credentials", func(c *S3Config) {
+			c.Connection.AccessKey = "minioadmin"
+			c.Connection.SecretKey = "minioadmin"
+		}, true},
+		{"invalid test case", func(c *S3Config) { c.BenchMark.TestCase = "copy" }, false},
+		{"invalid endpoint", func(c *S3Config) { c.Connection.Endpoint = "localhost:9000" }, false},
+		{"access key without secret", func(c *S3Config) { c.Connection.AccessKey = "minioadmin" }, false},
+		{"uppercase bucket", func(c *S3Config) { c.S3Specific.Bucket = "Bench" }, false},
+		{"no object sizes", func(c *S3Config) { c.S3Specific.ObjectSizes = nil }, false},
+		{"inverted size range", func(c *S3Config) {
+			c.S3Specific.ObjectSizes = []ObjectSize{{Min: 2048, Max: 1024, Weight: 1}}
+		}, false},
+		{"zero weight", func(c *S3Config) { c.S3Specific.ObjectSizes[0].Weight = 0 }, false},
+		{"part size below minimum", func(c *S3Config) { c.S3Specific.PartSize = 1 << 20 }, false},
+		{"too many parts", func(c *S3Config) {
+			c.S3Specific.ObjectSizes = []ObjectSize{{Min: 100 << 30, Weight: 1}}
+			c.S3Specific.PartSize = MinPartSize
+		}, false},
+		{"list max keys above limit", func(c *S3Config) { c.S3Specific.ListMaxKeys = 5000 }, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := NewDefaultS3Config()
+			test.modify(cfg)
+			if err := cfg.Validate(); (err == nil) != test.valid {
+				t.Errorf("Validate() error = %v, want valid %v", err, test.valid)
+			}
+		})
+	}
+}
+
+func TestParseObjectSizes(t *testing.T) {
+	sizes, err := ParseObjectSizes("4KiB:60, 1MB:30,16MiB-64MiB:10")
+	if err != nil {
+		t.Fatalf("ParseObjectSizes failed: %v", err)
+	}
+	want := []ObjectSize{
+		{Min: 4 << 10, Weight: 60},
+		{Min: 1 << 20, Weight: 30},
+		{Min: 16 << 20, Max: 64 << 20, Weight: 10},
+	}
+	if !reflect.DeepEqual(sizes, want) {
+		t.Fatalf("ParseObjectSizes = %+v, want %+v", sizes, want)
+	}
+	if sizes[2].String() != "16MiB-64MiB" || FormatSize(1500) != "1500B" {
+		t.Errorf("unexpected formatting: %s, %s", sizes[2], FormatSize(1500))
+	}
+
+	for _, spec := range []string{"", "4XB", "4KiB:heavy", "-1"} {
+		if _, err := ParseObjectSizes(spec); err == nil {
+			t.Errorf("ParseObjectSizes(%q) succeeded", spec)
+		}
+	}
+}
@@ -0,0 +1,381 @@
+package connection
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"abc-runner/app/adapters/s3/config"
+)
+
+// ResponseError 非2xx响应，Code为S3错误码（如 SlowDown、NoSuchKey、AccessDenied）
+type ResponseError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *ResponseError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Code)
+	}
+	return fmt.Sprintf("HTTP %d: %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// errorResponse S3错误响应体
+type errorResponse struct {
+	XMLName xml.Name `xml:"Error"`
+	Code    string   `xml:"Code"`
+	Message string   `xml:"Message"`
+}
+
+// ObjectRead 一次下载的结果
+type ObjectRead struct {
+	Bytes     int64         // 读取的对象字节数
+	FirstByte time.Duration // 从发出请求到收到首个响应字节的时间
+}
+
+// ListResult 一页ListObjectsV2结果
+type ListResult struct {
+	Keys                  []string
+	IsTruncated           bool
+	NextContinuationToken string
+}
+
+// listBucketResult ListObjectsV2响应体
+type listBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// CompletedPart 已上传的分片
+type CompletedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+// Client S3 REST客户端，使用Signature V4签名，未配置凭证时发送匿名请求
+type Client struct {
+	endpoint   *url.URL
+	bucket     string
+	pathStyle  bool
+	signer     *Signer
+	httpClient *http.Client
+}
+
+// NewClient 创建S3客户端，空闲连接数按并发数与分片并发数计算
+func NewClient(cfg *config.S3Config) (*Client, error) {
+	endpoint, err := url.Parse(strings.TrimRight(cfg.Connection.Endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint: %w", err)
+	}
+
+	idle := cfg.BenchMark.Parallels * cfg.S3Specific.PartConcurrency
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = idle
+	transport.MaxIdleConnsPerHost = idle
+	// 不请求gzip，读取的字节数即对象大小
+	transport.DisableCompression = true
+	if cfg.Connection.InsecureSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	client := &Client{
+		endpoint:  endpoint,
+		bucket:    cfg.S3Specific.Bucket,
+		pathStyle: cfg.Connection.PathStyle,
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   cfg.Connection.Timeout,
+		},
+	}
+	if cfg.Connection.AccessKey != "" {
+		client.signer = NewSigner(cfg.Connection.AccessKey, cfg.Connection.SecretKey,
+			cfg.Connection.SessionToken, cfg.Connection.Region)
+	}
+	return client, nil
+}
+
+// PutObject 上传对象
+func (c *Client) PutObject(ctx context.Context, key string, body []byte) error {
+	response, err := c.send(ctx, http.MethodPut, key, nil, body, UnsignedPayload, nil)
+	if err != nil {
+		return err
+	}
+	return drain(response)
+}
+
+// GetObject 下载对象并读取完整内容
+func (c *Client) GetObject(ctx context.Context, key string) (*ObjectRead, error) {
+	startTime := time.Now()
+	var firstByte time.Duration
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotFirstResponseByte: func() { firstByte = time.Since(startTime) },
+	})
+
+	response, err := c.send(ctx, http.MethodGet, key, nil, nil, emptyPayloadHash, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	n, err := io.Copy(io.Discard, response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object: %w", err)
+	}
+	return &ObjectRead{Bytes: n, FirstByte: firstByte}, nil
+}
+
+// DeleteObject 删除对象，对象不存在时S3同样返回成功
+func (c *Client) DeleteObject(ctx context.Context, key string) error {
+	response, err := c.send(ctx, http.MethodDelete, key, nil, nil, emptyPayloadHash, nil)
+	if err != nil {
+		return err
+	}
+	return drain(response)
+}
+
+// ListObjects 按前缀列出一页对象，token为上一页返回的NextContinuationToken
+func (c *Client) ListObjects(ctx context.Context, prefix string, maxKeys int, token string) (*ListResult, error) {
+	query := url.Values{
+		"list-type": {"2"},
+		"prefix":    {prefix},
+		"max-keys":  {strconv.Itoa(maxKeys)},
+	}
+	if token != "" {
+		query.Set("continuation-token", token)
+	}
+	var parsed listBucketResult
+	if err := c.sendXML(ctx, http.MethodGet, "", query, nil, &parsed); err != nil {
+		return nil, err
+	}
+	result := &ListResult{
+		Keys:                  make([]string, len(parsed.Contents)),
+		IsTruncated:           parsed.IsTruncated,
+		NextContinuationToken: parsed.NextContinuationToken,
+	}
+	for i, content := range parsed.Contents {
+		result.Keys[i] = content.Key
+	}
+	return result, nil
+}
+
+// CreateMultipartUpload 开始分片上传，返回UploadId
+func (c *Client) CreateMultipartUpload(ctx context.Context, key string) (string, error) {
+	var parsed struct {
+		UploadID string `xml:"UploadId"`
+	}
+	if err := c.sendXML(ctx, http.MethodPost, key, url.Values{"uploads": {""}}, nil, &parsed); err != nil {
+		return "", err
+	}
+	if parsed.UploadID == "" {
+		return "", fmt.Errorf("create multipart upload returned no upload id")
+	}
+	return parsed.UploadID, nil
+}
+
+// UploadPart 上传一个分片，返回分片的ETag
+func (c *Client) UploadPart(ctx context.Context, key, uploadID string, partNumber int, body []byte) (string, error) {
+	query := url.Values{"partNumber": {strconv.Itoa(partNumber)}, "uploadId": {uploadID}}
+	response, err := c.send(ctx, http.MethodPut, key, query, body, UnsignedPayload, nil)
+	if err != nil {
+		return "", err
+	}
+	etag := response.Header.Get("ETag")
+	return etag, drain(response)
+}
+
+// CompleteMultipartUpload 按分片编号顺序合并分片
+func (c *Client) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []CompletedPart) error {
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+		Parts   []CompletedPart `xml:"Part"`
+	}{Parts: parts})
+	if err != nil {
+		return err
+	}
+	// 合并耗时较长时服务端先返回200，再在响应体中报告错误，由sendXML按响应体根元素判定
+	var parsed struct {
+		XMLName xml.Name
+	}
+	return c.sendXML(ctx, http.MethodPost, key, url.Values{"uploadId": {uploadID}}, body, &parsed)
+}
+
+// AbortMultipartUpload 放弃分片上传，释放已上传的分片
+func (c *Client) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	response, err := c.send(ctx, http.MethodDelete, key, url.Values{"uploadId": {uploadID}}, nil, emptyPayloadHash, nil)
+	if err != nil {
+		return err
+	}
+	return drain(response)
+}
+
+// HeadBucket 检查存储桶是否存在且可访问，可用于检查服务可达与凭证是否有效
+func (c *Client) HeadBucket(ctx context.Context) error {
+	response, err := c.send(ctx, http.MethodHead, "", nil, nil, emptyPayloadHash, nil)
+	if err != nil {
+		return err
+	}
+	return drain(response)
+}
+
+// CreateBucket 创建存储桶，存储桶已属于当前用户时不视为错误；返回是否新建了存储桶
+func (c *Client) CreateBucket(ctx context.Context, region string) (bool, error) {
+	var body []byte
+	// us-east-1为默认区域，不能指定LocationConstraint
+	if region != "us-east-1" {
+		body, _ = xml.Marshal(struct {
+			XMLName            xml.Name `xml:"CreateBucketConfiguration"`
+			LocationConstraint string   `xml:"LocationConstraint"`
+		}{LocationConstraint: region})
+	}
+	response, err := c.send(ctx, http.MethodPut, "", nil, body, PayloadHash(body), nil)
+	if err != nil {
+		if responseErr, ok := err.(*ResponseError); ok && responseErr.Code == "BucketAlreadyOwnedByYou" {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, drain(response)
+}
+
+// DeleteObjects 批量删除对象（每次最多1000个）
+func (c *Client) DeleteObjects(ctx context.Context, keys []string) error {
+	type object struct {
+		Key string `xml:"Key"`
+	}
+	request := struct {
+		XMLName xml.Name `xml:"Delete"`
+		Quiet   bool     `xml:"Quiet"`
+		Objects []object `xml:"Object"`
+	}{Quiet: true}
+	for _, key := range keys {
+		request.Objects = append(request.Objects, object{Key: key})
+	}
+	body, err := xml.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	// 批量删除要求Content-MD5
+	sum := md5.Sum(body)
+	header := http.Header{"Content-Md5": {base64.StdEncoding.EncodeToString(sum[:])}}
+	response, err := c.send(ctx, http.MethodPost, "", url.Values{"delete": {""}}, body, PayloadHash(body), header)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	var parsed struct {
+		Errors []struct {
+			Key     string `xml:"Key"`
+			Code    string `xml:"Code"`
+			Message string `xml:"Message"`
+		} `xml:"Error"`
+	}
+	if err := xml.NewDecoder(response.Body).Decode(&parsed); err != nil && err != io.EOF {
+		return fmt.Errorf("unexpected delete response: %w", err)
+	}
+	if len(parsed.Errors) > 0 {
+		first := parsed.Errors[0]
+		return fmt.Errorf("failed to delete %d objects, first %s: %s: %s", len(parsed.Errors), first.Key, first.Code, first.Message)
+	}
+	return nil
+}
+
+// Close 关闭空闲连接
+func (c *Client) Close() {
+	c.httpClient.CloseIdleConnections()
+}
+
+// sendXML 发送请求并将XML响应体解析到result；响应体根元素为Error时返回ResponseError
+func (c *Client) sendXML(ctx context.Context, method, key string, query url.Values, body []byte, result interface{}) error {
+	response, err := c.send(ctx, method, key, query, body, PayloadHash(body), nil)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	var failure errorResponse
+	if xml.Unmarshal(data, &failure) == nil && failure.Code != "" {
+		return &ResponseError{StatusCode: response.StatusCode, Code: failure.Code, Message: failure.Message}
+	}
+	if err := xml.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("unexpected response: %w", err)
+	}
+	return nil
+}
+
+// send 签名并发送请求，key为空时请求存储桶本身
+// 非2xx响应读取并解析错误响应体后返回ResponseError，否则由调用方读取并关闭响应体
+func (c *Client) send(ctx context.Context, method, key string, query url.Values, body []byte, payloadHash string, header http.Header) (*http.Response, error) {
+	target := *c.endpoint
+	path := "/" + key
+	if c.pathStyle {
+		path = "/" + c.bucket
+		if key != "" {
+			path += "/" + key
+		}
+	} else {
+		target.Host = c.bucket + "." + target.Host
+	}
+	target.Path = path
+	target.RawPath = EncodePath(path)
+	target.RawQuery = query.Encode()
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, target.String(), reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for name, values := range header {
+		request.Header[name] = values
+	}
+	request.Header.Set("User-Agent", "abc-runner")
+	if c.signer != nil {
+		c.signer.Sign(request, payloadHash)
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode/100 != 2 {
+		defer response.Body.Close()
+		data, _ := io.ReadAll(io.LimitReader(response.Body, 64<<10))
+		responseErr := &ResponseError{StatusCode: response.StatusCode, Code: http.StatusText(response.StatusCode)}
+		var parsed errorResponse
+		if xml.Unmarshal(data, &parsed) == nil && parsed.Code != "" {
+			responseErr.Code, responseErr.Message = parsed.Code, parsed.Message
+		}
+		return nil, responseErr
+	}
+	return response, nil
+}
+
+// drain 读完并关闭响应体，使连接可以复用
+func drain(response *http.Response) error {
+	defer response.Body.Close()
+	if _, err := io.Copy(io.Discard, response.Body); err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	return nil
+}
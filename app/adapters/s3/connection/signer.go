@@ -0,0 +1,164 @@
+package connection
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 签名相关常量
+const (
+	signingAlgorithm = "AWS4-HMAC-SHA256"
+	signingService   = "s3"
+	amzDateFormat    = "20060102T150405Z"
+
+	// UnsignedPayload 不对请求体计算摘要，上传对象时避免对大对象做一次额外的SHA-256
+	UnsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+// emptyPayloadHash 空请求体的SHA-256
+var emptyPayloadHash = PayloadHash(nil)
+
+// Signer AWS Signature Version 4 请求签名
+type Signer struct {
+	accessKey    string
+	secretKey    string
+	sessionToken string
+	region       string
+	now          func() time.Time
+}
+
+// NewSigner 创建请求签名器
+func NewSigner(accessKey, secretKey, sessionToken, region string) *Signer {
+	return &Signer{
+		accessKey:    accessKey,
+		secretKey:    secretKey,
+		sessionToken: sessionToken,
+		region:       region,
+		now:          time.Now,
+	}
+}
+
+// PayloadHash 请求体的SHA-256十六进制摘要
+func PayloadHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// Sign 为请求添加x-amz-date、x-amz-content-sha256与Authorization请求头
+// 签名覆盖host、content-md5、content-type、range与全部x-amz-*请求头
+func (s *Signer) Sign(request *http.Request, payloadHash string) {
+	now := s.now().UTC()
+	amzDate := now.Format(amzDateFormat)
+	request.Header.Set("X-Amz-Date", amzDate)
+	request.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.sessionToken != "" {
+		request.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+
+	canonicalHeaders, signedHeaders := canonicalizeHeaders(request)
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		canonicalURI(request.URL),
+		canonicalQuery(request.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	date := amzDate[:8]
+	scope := date + "/" + s.region + "/" + signingService + "/aws4_request"
+	stringToSign := signingAlgorithm + "\n" + amzDate + "\n" + scope + "\n" + PayloadHash([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, signingService)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	request.Header.Set("Authorization", signingAlgorithm+" Credential="+s.accessKey+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+// canonicalizeHeaders 按名称排序的规范请求头与签名请求头列表
+func canonicalizeHeaders(request *http.Request) (string, string) {
+	host := request.Host
+	if host == "" {
+		host = request.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range request.Header {
+		name = strings.ToLower(name)
+		switch {
+		case name == "content-md5", name == "content-type", name == "range", strings.HasPrefix(name, "x-amz-"):
+			headers[name] = strings.Join(values, ",")
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonical strings.Builder
+	for _, name := range names {
+		canonical.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	return canonical.String(), strings.Join(names, ";")
+}
+
+// canonicalURI 规范路径：各段按RFC 3986编码，S3不对路径做二次编码
+func canonicalURI(u *url.URL) string {
+	if u.Path == "" {
+		return "/"
+	}
+	return EncodePath(u.Path)
+}
+
+// canonicalQuery 规范查询字符串：按参数名与值排序，无值参数输出为 name=
+func canonicalQuery(query url.Values) string {
+	pairs := make([]string, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(name, true)+"="+uriEncode(value, true))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// EncodePath 按RFC 3986编码路径，保留分隔符"/"
+func EncodePath(path string) string {
+	return uriEncode(path, false)
+}
+
+// uriEncode 除非保留字符（A-Z a-z 0-9 - _ . ~）外全部百分号编码，encodeSlash为false时保留"/"
+func uriEncode(s string, encodeSlash bool) string {
+	const hexDigits = "0123456789ABCDEF"
+	var encoded strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/' && !encodeSlash:
+			encoded.WriteByte(c)
+		default:
+			encoded.WriteByte('%')
+			encoded.WriteByte(hexDigits[c>>4])
+			encoded.WriteByte(hexDigits[c&15])
+		}
+	}
+	return encoded.String()
+}
+
+// hmacSHA256 计算HMAC-SHA256
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
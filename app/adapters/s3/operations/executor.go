@@ -0,0 +1,223 @@
+package operations
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"abc-runner/app/adapters/s3/config"
+	"abc-runner/app/adapters/s3/connection"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+)
+
+// S3Executor S3操作执行器
+// 对象内容取自一块预先生成的随机数据，上传时不为每个对象分配内存
+type S3Executor struct {
+	client          *connection.Client
+	prefix          string
+	objects         int
+	sizes           *SizeSampler
+	threshold       int64
+	partSize        int64
+	partConcurrency int
+	listMaxKeys     int
+	abortTimeout    time.Duration
+	payload         []byte
+
+	tracker   *metrics.OperationTypeTracker
+	firstByte *metrics.LatencyTracker // get的首字节时间
+	parts     atomic.Int64            // 分片上传中上传成功的分片数
+}
+
+// NewS3Executor 创建S3操作执行器
+func NewS3Executor(client *connection.Client, cfg *config.S3Config) *S3Executor {
+	specific := cfg.S3Specific
+
+	// 小于阈值的对象整体上传，分片不超过part_size，随机数据按两者中较大的一个生成
+	payload := make([]byte, min(cfg.MaxObjectSize(), max(specific.MultipartThreshold, specific.PartSize)))
+	rand.Read(payload)
+
+	return &S3Executor{
+		client:          client,
+		prefix:          specific.Prefix,
+		objects:         specific.Objects,
+		sizes:           NewSizeSampler(specific.ObjectSizes),
+		threshold:       specific.MultipartThreshold,
+		partSize:        specific.PartSize,
+		partConcurrency: specific.PartConcurrency,
+		listMaxKeys:     specific.ListMaxKeys,
+		abortTimeout:    cfg.Connection.Timeout,
+		payload:         payload,
+		tracker:         newOperationTracker(),
+		firstByte: metrics.NewLatencyTracker(metrics.LatencyConfig{
+			SignificantDigits: metrics.DefaultHdrSignificantDigits,
+			SamplingRate:      1.0,
+		}),
+	}
+}
+
+// Key 第jobID个操作访问的对象键
+func (e *S3Executor) Key(jobID int) string {
+	return fmt.Sprintf("%sobj-%08d", e.prefix, jobID%e.objects)
+}
+
+// ExecuteOperation 执行S3操作
+// 预填充的上传不计入统计
+func (e *S3Executor) ExecuteOperation(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	jobID, _ := operation.Params["job_id"].(int)
+	prefill, _ := operation.Params["prefill"].(bool)
+	key := e.Key(jobID)
+
+	var volume int64
+	var multipart bool
+	var firstByte time.Duration
+	var err error
+	startTime := time.Now()
+	switch operation.Type {
+	case config.OperationPut:
+		volume, multipart, err = e.put(ctx, key)
+	case config.OperationGet:
+		var read *connection.ObjectRead
+		if read, err = e.client.GetObject(ctx, key); err == nil {
+			volume, firstByte = read.Bytes, read.FirstByte
+		}
+	case config.OperationDelete:
+		err = e.client.DeleteObject(ctx, key)
+	case config.OperationList:
+		var page *connection.ListResult
+		if page, err = e.client.ListObjects(ctx, e.prefix, e.listMaxKeys, ""); err == nil {
+			volume = int64(len(page.Keys))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported operation type: %s", operation.Type)
+	}
+	duration := time.Since(startTime)
+
+	if !prefill {
+		e.tracker.Record(operation.Type, volume, multipart, duration, err)
+		if operation.Type == config.OperationGet && err == nil {
+			e.firstByte.Record(firstByte)
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("%s %s failed: %w", operation.Type, key, err)
+	}
+
+	result := &interfaces.OperationResult{
+		Success:  err == nil,
+		Duration: duration,
+		IsRead:   operation.Type == config.OperationGet || operation.Type == config.OperationList,
+		Error:    err,
+		Value:    volume,
+		Metadata: map[string]interface{}{
+			"protocol":       "s3",
+			"operation_type": operation.Type,
+			"key":            key,
+			"volume":         volume,
+		},
+	}
+	if err == nil {
+		switch operation.Type {
+		case config.OperationPut:
+			result.BytesWritten = volume
+		case config.OperationGet:
+			result.BytesRead = volume
+		}
+	}
+	if firstByte > 0 {
+		result.Metadata["first_byte"] = firstByte
+	}
+	for k, v := range operation.Metadata {
+		result.Metadata[k] = v
+	}
+	return result, err
+}
+
+// put 按大小分布上传一个对象，返回对象大小与是否分片上传
+func (e *S3Executor) put(ctx context.Context, key string) (int64, bool, error) {
+	size := e.sizes.Next()
+	if size < e.threshold {
+		return size, false, e.client.PutObject(ctx, key, e.payload[:size])
+	}
+	return size, true, e.multipartPut(ctx, key, size)
+}
+
+// multipartPut 分片上传，最多part_concurrency个分片并发上传；任一分片失败时放弃整个上传
+func (e *S3Executor) multipartPut(ctx context.Context, key string, size int64) error {
+	uploadID, err := e.client.CreateMultipartUpload(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	count := int((size + e.partSize - 1) / e.partSize)
+	parts := make([]connection.CompletedPart, count)
+	partCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var partErr error
+	numbers := make(chan int)
+	for i := 0; i < min(e.partConcurrency, count); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range numbers {
+				offset := int64(n) * e.partSize
+				etag, err := e.client.UploadPart(partCtx, key, uploadID, n+1, e.payload[:min(e.partSize, size-offset)])
+				if err != nil {
+					errOnce.Do(func() {
+						partErr = fmt.Errorf("part %d: %w", n+1, err)
+						cancel()
+					})
+					continue
+				}
+				parts[n] = connection.CompletedPart{PartNumber: n + 1, ETag: etag}
+				e.parts.Add(1)
+			}
+		}()
+	}
+dispatch:
+	for n := 0; n < count; n++ {
+		select {
+		case numbers <- n:
+		case <-partCtx.Done():
+			break dispatch
+		}
+	}
+	close(numbers)
+	wg.Wait()
+
+	if partErr == nil {
+		partErr = ctx.Err()
+	}
+	if partErr == nil {
+		partErr = e.client.CompleteMultipartUpload(ctx, key, uploadID, parts)
+	}
+	if partErr != nil {
+		// 原上下文可能已取消，放弃上传使用独立的超时
+		abortCtx, abortCancel := context.WithTimeout(context.Background(), e.abortTimeout)
+		defer abortCancel()
+		e.client.AbortMultipartUpload(abortCtx, key, uploadID)
+	}
+	return partErr
+}
+
+// OperationStats 获取按操作类型的统计
+func (e *S3Executor) OperationStats() []OperationStats {
+	return e.tracker.Stats()
+}
+
+// FirstByteLatency 获取get的首字节时间统计
+func (e *S3Executor) FirstByteLatency() metrics.LatencyMetrics {
+	return e.firstByte.GetMetrics()
+}
+
+// UploadedParts 获取分片上传中上传成功的分片数
+func (e *S3Executor) UploadedParts() int64 {
+	return e.parts.Load()
+}
@@ -0,0 +1,254 @@
+package operations
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"abc-runner/app/adapters/s3/config"
+	"abc-runner/app/adapters/s3/connection"
+)
+
+// fakeS3 路径风格寻址的内存对象存储，只实现测试用到的接口
+type fakeS3 struct {
+	mutex    sync.Mutex
+	objects  map[string]int
+	uploads  map[string]map[int]int // uploadId -> 分片编号 -> 大小
+	aborted  int
+	slowDown string // 上传此键时返回503 SlowDown
+	failPart int    // 上传此编号的分片时返回500
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{objects: make(map[string]int), uploads: make(map[string]map[int]int)}
+}
+
+func (s *fakeS3) writeError(w http.ResponseWriter, status int, code string) {
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s from fake</Message></Error>", code, code)
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=ak/") {
+		s.writeError(w, http.StatusForbidden, "AccessDenied")
+		return
+	}
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != "bench" {
+		s.writeError(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+	query := r.URL.Query()
+	body, _ := io.ReadAll(r.Body)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	switch {
+	case r.Method == http.MethodGet && key == "":
+		var keys []string
+		for k := range s.objects {
+			if strings.HasPrefix(k, query.Get("prefix")) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		fmt.Fprint(w, "<ListBucketResult>")
+		for _, k := range keys {
+			fmt.Fprintf(w, "<Contents><Key>%s</Key></Contents>", k)
+		}
+		fmt.Fprint(w, "<IsTruncated>false</IsTruncated></ListBucketResult>")
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		uploadID := fmt.Sprintf("upload-%d", len(s.uploads))
+		s.uploads[uploadID] = make(map[int]int)
+		fmt.Fprintf(w, "<InitiateMultipartUploadResult><UploadId>%s</UploadId></InitiateMultipartUploadResult>", uploadID)
+	case r.Method == http.MethodPut && query.Has("partNumber"):
+		var number int
+		fmt.Sscan(query.Get("partNumber"), &number)
+		if number == s.failPart {
+			s.writeError(w, http.StatusInternalServerError, "InternalError")
+			return
+		}
+		s.uploads[query.Get("uploadId")][number] = len(body)
+		w.Header().Set("ETag", fmt.Sprintf(`"etag-%d"`, number))
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		var request struct {
+			Parts []connection.CompletedPart `xml:"Part"`
+		}
+		xml.Unmarshal(body, &request)
+		parts := s.uploads[query.Get("uploadId")]
+		size := 0
+		for i, part := range request.Parts {
+			if part.PartNumber != i+1 || part.ETag != fmt.Sprintf(`"etag-%d"`, i+1) {
+				s.writeError(w, http.StatusBadRequest, "InvalidPartOrder")
+				return
+			}
+			size += parts[part.PartNumber]
+		}
+		s.objects[key] = size
+		fmt.Fprint(w, "<CompleteMultipartUploadResult></CompleteMultipartUploadResult>")
+	case r.Method == http.MethodDelete && query.Has("uploadId"):
+		s.aborted++
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPut:
+		if r.Header.Get("X-Amz-Content-Sha256") != connection.UnsignedPayload {
+			s.writeError(w, http.StatusBadRequest, "InvalidRequest")
+			return
+		}
+		if key == s.slowDown {
+			s.writeError(w, http.StatusServiceUnavailable, "SlowDown")
+			return
+		}
+		s.objects[key] = len(body)
+	case r.Method == http.MethodGet:
+		size, ok := s.objects[key]
+		if !ok {
+			s.writeError(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Write(make([]byte, size))
+	case r.Method == http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		s.writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+// newTestExecutor 创建连接到fakeS3的执行器
+func newTestExecutor(t *testing.T, server *httptest.Server, modify func(*config.S3Config)) *S3Executor {
+	t.Helper()
+	cfg := config.NewDefaultS3Config()
+	cfg.Connection.Endpoint = server.URL
+	cfg.Connection.AccessKey = "ak"
+	cfg.Connection.SecretKey = "sk"
+	cfg.S3Specific.Bucket = "bench"
+	cfg.S3Specific.Prefix = "run/"
+	cfg.S3Specific.Objects = 4
+	modify(cfg)
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("invalid config: %v", err)
+	}
+	client, err := connection.NewClient(cfg)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	return NewS3Executor(client, cfg)
+}
+
+func TestExecuteOperation(t *testing.T) {
+	fake := newFakeS3()
+	fake.slowDown = "run/obj-00000003"
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	executor := newTestExecutor(t, server, func(cfg *config.S3Config) {
+		cfg.S3Specific.ObjectSizes = []config.ObjectSize{{Min: 1000, Max: 2000, Weight: 1}}
+	})
+	ctx := context.Background()
+
+	// 预填充不计入统计
+	factory := NewOperationFactory(&config.S3Config{BenchMark: config.BenchmarkConfig{TestCase: config.OperationGet}})
+	if _, err := executor.ExecuteOperation(ctx, factory.CreatePrepareOperation(0)); err != nil {
+		t.Fatalf("prefill failed: %v", err)
+	}
+
+	var uploaded int64
+	for job := 1; job < 4; job++ {
+		result, err := executor.ExecuteOperation(ctx, newOperation(config.OperationPut, job, false))
+		if job == 3 {
+			var responseErr *connection.ResponseError
+			if !errors.As(err, &responseErr) || responseErr.Code != "SlowDown" {
+				t.Fatalf("expected SlowDown, got %v", err)
+			}
+			continue
+		}
+		if err != nil || result.Value.(int64) < 1000 || result.Value.(int64) > 2000 {
+			t.Fatalf("put %d: value=%v err=%v", job, result.Value, err)
+		}
+		uploaded += result.Value.(int64)
+	}
+
+	var downloaded int64
+	for job := 4; job < 8; job++ {
+		result, err := executor.ExecuteOperation(ctx, newOperation(config.OperationGet, job, false))
+		if job == 7 {
+			if err == nil || !strings.Contains(err.Error(), "NoSuchKey") {
+				t.Fatalf("expected NoSuchKey, got %v", err)
+			}
+			continue
+		}
+		if err != nil || !result.IsRead || result.Metadata["first_byte"] == nil {
+			t.Fatalf("get %d: %+v, %v", job, result, err)
+		}
+		downloaded += result.Value.(int64)
+	}
+
+	result, err := executor.ExecuteOperation(ctx, newOperation(config.OperationList, 0, false))
+	if err != nil || result.Value != int64(3) {
+		t.Fatalf("list: value=%v err=%v", result.Value, err)
+	}
+	if _, err := executor.ExecuteOperation(ctx, newOperation(config.OperationDelete, 1, false)); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if _, ok := fake.objects["run/obj-00000001"]; ok {
+		t.Fatal("object was not deleted")
+	}
+
+	stats := make(map[string]OperationStats)
+	for _, s := range executor.OperationStats() {
+		stats[s.Type] = s
+	}
+	if s := stats[config.OperationPut]; s.Count != 3 || s.Errors != 1 || s.ErrorCodes["SlowDown"] != 1 || s.Volume != uploaded {
+		t.Fatalf("unexpected put stats: %+v (uploaded %d)", s, uploaded)
+	}
+	// 第0个对象由预填充上传，下载的字节数包括它
+	if s := stats[config.OperationGet]; s.Count != 4 || s.ErrorCodes["NoSuchKey"] != 1 || s.Volume != downloaded {
+		t.Fatalf("unexpected get stats: %+v (downloaded %d)", s, downloaded)
+	}
+	if latency := executor.FirstByteLatency(); latency.Max == 0 || latency.Max > stats[config.OperationGet].Latency.Max {
+		t.Fatalf("unexpected first byte latency: %+v", latency)
+	}
+}
+
+func TestMultipartPut(t *testing.T) {
+	fake := newFakeS3()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	size := int64(2*config.MinPartSize + 1024)
+	executor := newTestExecutor(t, server, func(cfg *config.S3Config) {
+		cfg.S3Specific.ObjectSizes = []config.ObjectSize{{Min: size, Weight: 1}}
+		cfg.S3Specific.MultipartThreshold = config.MinPartSize
+		cfg.S3Specific.PartSize = config.MinPartSize
+		cfg.S3Specific.PartConcurrency = 2
+	})
+
+	result, err := executor.ExecuteOperation(context.Background(), newOperation(config.OperationPut, 0, false))
+	if err != nil || result.Value != size {
+		t.Fatalf("multipart put: value=%v err=%v", result.Value, err)
+	}
+	if fake.objects["run/obj-00000000"] != int(size) || executor.UploadedParts() != 3 {
+		t.Fatalf("object size %d, parts %d", fake.objects["run/obj-00000000"], executor.UploadedParts())
+	}
+
+	// 分片失败时放弃上传
+	fake.failPart = 2
+	if _, err := executor.ExecuteOperation(context.Background(), newOperation(config.OperationPut, 1, false)); err == nil ||
+		!strings.Contains(err.Error(), "part 2") {
+		t.Fatalf("expected part 2 failure, got %v", err)
+	}
+	if fake.aborted != 1 {
+		t.Fatalf("aborted = %d, want 1", fake.aborted)
+	}
+
+	s := executor.OperationStats()[0]
+	if s.Count != 2 || s.Flagged != 1 || s.ErrorCodes["InternalError"] != 1 {
+		t.Fatalf("unexpected put stats: %+v", s)
+	}
+}
@@ -0,0 +1,67 @@
+package operations
+
+import (
+	"math/rand/v2"
+
+	"abc-runner/app/adapters/s3/config"
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+)
+
+// OperationFactory S3操作工厂
+// 只选择操作类型，对象键在执行器中按操作编号确定
+type OperationFactory struct {
+	testCase    string
+	readPercent int
+}
+
+// NewOperationFactory 创建S3操作工厂
+func NewOperationFactory(cfg *config.S3Config) *OperationFactory {
+	return &OperationFactory{
+		testCase:    cfg.BenchMark.TestCase,
+		readPercent: cfg.BenchMark.ReadPercent,
+	}
+}
+
+// CreateOperation 创建操作，mixed用例按read_percent随机选择get或put
+func (f *OperationFactory) CreateOperation(jobID int, cfg execution.BenchmarkConfig) interfaces.Operation {
+	operationType := f.testCase
+	if operationType == config.TestCaseMixed {
+		operationType = config.OperationPut
+		if rand.IntN(100) < f.readPercent {
+			operationType = config.OperationGet
+		}
+	}
+	return newOperation(operationType, jobID, false)
+}
+
+// CreatePrepareOperation 创建预填充操作：上传第index个对象，供get用例下载
+func (f *OperationFactory) CreatePrepareOperation(index int) interfaces.Operation {
+	return newOperation(config.OperationPut, index, true)
+}
+
+// newOperation 创建指定类型的操作
+func newOperation(operationType string, jobID int, prefill bool) interfaces.Operation {
+	return interfaces.Operation{
+		Type: operationType,
+		Params: map[string]interface{}{
+			"job_id":  jobID,
+			"prefill": prefill,
+		},
+		Metadata: map[string]string{
+			"operation_type": operationType,
+		},
+	}
+}
+
+// GetOperationType 获取操作类型
+func (f *OperationFactory) GetOperationType() string {
+	return f.testCase
+}
+
+// GetSupportedOperations 获取支持的操作类型
+func (f *OperationFactory) GetSupportedOperations() []string {
+	return []string{config.OperationPut, config.OperationGet, config.OperationDelete, config.OperationList}
+}
+
+var _ execution.PrepareFactory = (*OperationFactory)(nil)
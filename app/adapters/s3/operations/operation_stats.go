@@ -0,0 +1,35 @@
+package operations
+
+import (
+	"context"
+	"errors"
+
+	"abc-runner/app/adapters/s3/connection"
+	"abc-runner/app/core/metrics"
+)
+
+// OperationStats 单个操作类型的统计，Volume为put上传与get下载的字节数或list返回的对象数（JSON字段"volume"），
+// Flagged为put中分片上传的次数（JSON字段"multipart"）；
+// 错误码为S3错误码（如"SlowDown"、"NoSuchKey"），另有"timeout"与"client"
+type OperationStats = metrics.OperationTypeStats
+
+// newOperationTracker 创建按操作类型的统计器
+func newOperationTracker() *metrics.OperationTypeTracker {
+	return metrics.NewOperationTypeTracker(errorCode, "volume", "multipart")
+}
+
+// errorCode 错误分类：服务端错误取S3错误码，其余按超时与客户端错误归类
+func errorCode(err error) string {
+	var responseErr *connection.ResponseError
+	if errors.As(err, &responseErr) {
+		return responseErr.Code
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	var timeoutErr interface{ Timeout() bool }
+	if errors.As(err, &timeoutErr) && timeoutErr.Timeout() {
+		return "timeout"
+	}
+	return "client"
+}
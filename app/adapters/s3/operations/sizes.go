@@ -0,0 +1,37 @@
+package operations
+
+import (
+	"math/rand/v2"
+
+	"abc-runner/app/adapters/s3/config"
+)
+
+// SizeSampler 按对象大小分布随机生成对象大小
+type SizeSampler struct {
+	sizes       []config.ObjectSize
+	totalWeight int
+}
+
+// NewSizeSampler 创建对象大小采样器
+func NewSizeSampler(sizes []config.ObjectSize) *SizeSampler {
+	sampler := &SizeSampler{sizes: sizes}
+	for _, size := range sizes {
+		sampler.totalWeight += size.Weight
+	}
+	return sampler
+}
+
+// Next 按权重选择一项，再在[min, max]内均匀取值
+func (s *SizeSampler) Next() int64 {
+	pick := rand.IntN(s.totalWeight)
+	for _, size := range s.sizes {
+		if pick < size.Weight {
+			if size.Max <= size.Min {
+				return size.Min
+			}
+			return size.Min + rand.Int64N(size.Max-size.Min+1)
+		}
+		pick -= size.Weight
+	}
+	return s.sizes[len(s.sizes)-1].Min
+}
@@ -0,0 +1,182 @@
+package smtp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"abc-runner/app/adapters/smtp/config"
+	"abc-runner/app/adapters/smtp/connection"
+	"abc-runner/app/adapters/smtp/operations"
+	"abc-runner/app/core/interfaces"
+)
+
+// SMTPAdapter SMTP协议适配器 - 遵循统一架构模式
+// 职责：会话池管理、状态维护、健康检查
+type SMTPAdapter struct {
+	config           *config.SMTPConfig
+	pool             *connection.Pool
+	smtpOperations   *operations.SMTPExecutor
+	metricsCollector interfaces.DefaultMetricsCollector
+	mu               sync.RWMutex
+	isConnected      bool
+
+	// 统计信息
+	totalOperations  int64
+	failedOperations int64
+}
+
+// NewSMTPAdapter 创建SMTP适配器
+func NewSMTPAdapter(metricsCollector interfaces.DefaultMetricsCollector) *SMTPAdapter {
+	return &SMTPAdapter{
+		metricsCollector: metricsCollector,
+	}
+}
+
+// Connect 为每个并发发送者建立SMTP会话
+func (s *SMTPAdapter) Connect(ctx context.Context, cfg interfaces.Config) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	smtpConfig, ok := cfg.(*config.SMTPConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type for SMTP adapter: expected *config.SMTPConfig, got %T", cfg)
+	}
+
+	if err := smtpConfig.Validate(); err != nil {
+		return fmt.Errorf("config validation failed: %w", err)
+	}
+	s.config = smtpConfig
+
+	pool, err := connection.NewPool(ctx, smtpConfig)
+	if err != nil {
+		return err
+	}
+	s.pool = pool
+	s.smtpOperations = operations.NewSMTPExecutor(pool, smtpConfig)
+
+	s.isConnected = true
+	return nil
+}
+
+// Execute 执行操作 - 使用执行器处理
+func (s *SMTPAdapter) Execute(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	if !s.isConnected {
+		return nil, fmt.Errorf("adapter not connected")
+	}
+
+	atomic.AddInt64(&s.totalOperations, 1)
+	result, err := s.smtpOperations.ExecuteOperation(ctx, operation)
+	if err != nil {
+		atomic.AddInt64(&s.failedOperations, 1)
+	}
+	return result, err
+}
+
+// Close 关闭全部会话
+func (s *SMTPAdapter) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.pool != nil {
+		s.pool.Close()
+		s.pool = nil
+	}
+	s.isConnected = false
+	return nil
+}
+
+// GetProtocolMetrics 获取协议特定指标
+func (s *SMTPAdapter) GetProtocolMetrics() map[string]interface{} {
+	metrics := map[string]interface{}{
+		"protocol":          "smtp",
+		"total_operations":  atomic.LoadInt64(&s.totalOperations),
+		"failed_operations": atomic.LoadInt64(&s.failedOperations),
+	}
+
+	if s.config != nil {
+		metrics["security"] = s.config.Connection.Security
+	}
+	if stats := s.GetDeliveryStats(); stats != nil {
+		metrics["delivery"] = *stats
+	}
+	if stats := s.GetOperationStats(); stats != nil {
+		metrics["operation_stats"] = stats
+	}
+	if stats := s.GetPoolStats(); stats != nil {
+		metrics["pool_stats"] = *stats
+	}
+
+	return metrics
+}
+
+// GetOperationStats 获取发送的统计，未连接时返回nil
+func (s *SMTPAdapter) GetOperationStats() []operations.OperationStats {
+	if s.smtpOperations == nil {
+		return nil
+	}
+	return s.smtpOperations.OperationStats()
+}
+
+// GetDeliveryStats 获取按结果分类的统计，未连接时返回nil
+func (s *SMTPAdapter) GetDeliveryStats() *operations.DeliveryStats {
+	if s.smtpOperations == nil {
+		return nil
+	}
+	stats := s.smtpOperations.DeliveryStats()
+	return &stats
+}
+
+// GetPoolStats 获取会话池统计，未连接时返回nil
+func (s *SMTPAdapter) GetPoolStats() *connection.PoolStats {
+	if s.pool == nil {
+		return nil
+	}
+	stats := s.pool.Stats()
+	return &stats
+}
+
+// Greeting 服务端的220问候语，未连接时为空
+func (s *SMTPAdapter) Greeting() string {
+	if s.pool == nil {
+		return ""
+	}
+	return s.pool.Greeting()
+}
+
+// Extensions 服务端通告的扩展，未连接时为nil
+func (s *SMTPAdapter) Extensions() []string {
+	if s.pool == nil {
+		return nil
+	}
+	return s.pool.Extensions()
+}
+
+// HealthCheck 健康检查：在池中的会话上发送NOOP
+func (s *SMTPAdapter) HealthCheck(ctx context.Context) error {
+	if !s.isConnected {
+		return fmt.Errorf("adapter not connected")
+	}
+
+	client, err := s.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.pool.Release(client)
+
+	if err := client.Noop(ctx); err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	return nil
+}
+
+// GetProtocolName 获取协议名称
+func (s *SMTPAdapter) GetProtocolName() string {
+	return "smtp"
+}
+
+// GetMetricsCollector 获取指标收集器
+func (s *SMTPAdapter) GetMetricsCollector() interfaces.DefaultMetricsCollector {
+	return s.metricsCollector
+}
@@ -0,0 +1,285 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/utils"
+)
+
+// TestCaseSend 发送消息的测试用例
+const TestCaseSend = "send"
+
+// 连接安全模式
+const (
+	SecurityNone     = "none"     // 明文
+	SecurityStartTLS = "starttls" // 明文连接后通过STARTTLS升级
+	SecurityTLS      = "tls"      // 连接即TLS（SMTPS，通常为465端口）
+)
+
+// 认证机制，auto按服务端通告的机制依次选择CRAM-MD5、PLAIN、LOGIN
+const (
+	AuthAuto    = "auto"
+	AuthPlain   = "plain"
+	AuthLogin   = "login"
+	AuthCRAMMD5 = "cram-md5"
+)
+
+// SMTPConfig SMTP协议配置
+type SMTPConfig struct {
+	Protocol     string             `yaml:"protocol" json:"protocol"`
+	Connection   ConnectionConfig   `yaml:"connection" json:"connection"`
+	BenchMark    BenchmarkConfig    `yaml:"benchmark" json:"benchmark"`
+	SMTPSpecific SMTPSpecificConfig `yaml:"smtp_specific" json:"smtp_specific"`
+}
+
+// ConnectionConfig SMTP连接配置
+type ConnectionConfig struct {
+	Address            string        `yaml:"address" json:"address"`
+	Port               int           `yaml:"port" json:"port"`
+	Security           string        `yaml:"security" json:"security"` // none、starttls或tls
+	InsecureSkipVerify bool          `yaml:"insecure_skip_verify" json:"insecure_skip_verify"`
+	Username           string        `yaml:"username" json:"username"` // 为空时不认证
+	Password           string        `yaml:"password" json:"password"`
+	AuthMechanism      string        `yaml:"auth_mechanism" json:"auth_mechanism"` // auto、plain、login或cram-md5
+	HeloName           string        `yaml:"helo_name" json:"helo_name"`           // EHLO中的主机名，为空时使用本机主机名
+	Timeout            time.Duration `yaml:"timeout" json:"timeout"`               // 建连与单条命令的超时
+}
+
+// BenchmarkConfig SMTP基准测试配置
+type BenchmarkConfig struct {
+	Total     int           `yaml:"total" json:"total"`
+	Parallels int           `yaml:"parallels" json:"parallels"` // 并发发送者数，每个发送者独占一个连接
+	TestCase  string        `yaml:"test_case" json:"test_case"` // "send"
+	Duration  time.Duration `yaml:"duration" json:"duration"`
+	Rate      int           `yaml:"rate" json:"rate"` // 每秒发送的消息数上限，0表示不限速
+}
+
+// SMTPSpecificConfig SMTP特定配置
+type SMTPSpecificConfig struct {
+	From                  string   `yaml:"from" json:"from"`                                       // 信封发件人
+	To                    []string `yaml:"to" json:"to"`                                           // 信封收件人，支持负载模板占位符
+	Subject               string   `yaml:"subject" json:"subject"`                                 // 主题，消息序号追加在末尾
+	MessageSize           int      `yaml:"message_size" json:"message_size"`                       // 消息长度（字节，含头部）
+	MaxMessageSize        int      `yaml:"max_message_size" json:"max_message_size"`               // 大于message_size时在两者间随机取长度
+	MessagesPerConnection int      `yaml:"messages_per_connection" json:"messages_per_connection"` // 每个连接发送的消息数，达到后QUIT并重连，0表示不限
+}
+
+// NewDefaultSMTPConfig 创建默认SMTP配置
+func NewDefaultSMTPConfig() *SMTPConfig {
+	return &SMTPConfig{
+		Protocol: "smtp",
+		Connection: ConnectionConfig{
+			Address:       "localhost",
+			Port:          25,
+			Security:      SecurityNone,
+			AuthMechanism: AuthAuto,
+			Timeout:       30 * time.Second,
+		},
+		BenchMark: BenchmarkConfig{
+			Total:     1000,
+			Parallels: 10,
+			TestCase:  TestCaseSend,
+		},
+		SMTPSpecific: SMTPSpecificConfig{
+			From:        "abc-runner@example.com",
+			To:          []string{"bench@example.com"},
+			Subject:     "abc-runner test message",
+			MessageSize: 4096,
+		},
+	}
+}
+
+// GetProtocol 实现Config接口
+func (c *SMTPConfig) GetProtocol() string {
+	return c.Protocol
+}
+
+// GetConnection 实现Config接口
+func (c *SMTPConfig) GetConnection() interfaces.ConnectionConfig {
+	return &c.Connection
+}
+
+// GetBenchmark 实现Config接口
+func (c *SMTPConfig) GetBenchmark() interfaces.BenchmarkConfig {
+	return &c.BenchMark
+}
+
+// Validate 实现Config接口
+func (c *SMTPConfig) Validate() error {
+	if c.Connection.Address == "" {
+		return fmt.Errorf("connection address cannot be empty")
+	}
+	if c.Connection.Port <= 0 || c.Connection.Port > 65535 {
+		return fmt.Errorf("invalid port number: %d", c.Connection.Port)
+	}
+	switch c.Connection.Security {
+	case SecurityNone, SecurityStartTLS, SecurityTLS:
+	default:
+		return fmt.Errorf("invalid security mode: %s, valid options: none, starttls, tls", c.Connection.Security)
+	}
+	switch c.Connection.AuthMechanism {
+	case AuthAuto, AuthPlain, AuthLogin, AuthCRAMMD5:
+	default:
+		return fmt.Errorf("invalid auth mechanism: %s, valid options: auto, plain, login, cram-md5", c.Connection.AuthMechanism)
+	}
+	if c.Connection.Password != "" && c.Connection.Username == "" {
+		return fmt.Errorf("password requires a username")
+	}
+	if c.Connection.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+
+	if c.BenchMark.Total <= 0 {
+		return fmt.Errorf("total operations must be greater than 0")
+	}
+	if c.BenchMark.Parallels <= 0 {
+		return fmt.Errorf("parallel connections must be greater than 0")
+	}
+	if c.BenchMark.TestCase != TestCaseSend {
+		return fmt.Errorf("invalid test case: %s, valid options: send", c.BenchMark.TestCase)
+	}
+	if c.BenchMark.Rate < 0 {
+		return fmt.Errorf("rate cannot be negative")
+	}
+
+	specific := c.SMTPSpecific
+	if err := validateAddress(specific.From); err != nil {
+		return fmt.Errorf("invalid sender: %w", err)
+	}
+	if len(specific.To) == 0 {
+		return fmt.Errorf("at least one recipient is required")
+	}
+	for _, to := range specific.To {
+		template, err := utils.ParsePayloadTemplate(to, nil)
+		if err != nil {
+			return fmt.Errorf("invalid recipient %q: %w", to, err)
+		}
+		if to == "" {
+			return fmt.Errorf("recipient cannot be empty")
+		}
+		if err := validateAddress(template.Render(0)); err != nil {
+			return fmt.Errorf("invalid recipient: %w", err)
+		}
+	}
+	if specific.MessageSize <= 0 {
+		return fmt.Errorf("message size must be greater than 0")
+	}
+	if specific.MaxMessageSize != 0 && specific.MaxMessageSize < specific.MessageSize {
+		return fmt.Errorf("max message size %d is smaller than message size %d", specific.MaxMessageSize, specific.MessageSize)
+	}
+	if specific.MessagesPerConnection < 0 {
+		return fmt.Errorf("messages per connection cannot be negative")
+	}
+
+	return nil
+}
+
+// validateAddress 检查信封地址：可为空（空发件人<>），否则需包含@且不含尖括号、空白与换行
+func validateAddress(address string) error {
+	if address == "" {
+		return nil
+	}
+	if strings.ContainsAny(address, "<> \t\r\n") {
+		return fmt.Errorf("%q must be a bare address without brackets or whitespace", address)
+	}
+	if !strings.Contains(address, "@") {
+		return fmt.Errorf("%q is missing @", address)
+	}
+	return nil
+}
+
+// Clone 实现Config接口
+func (c *SMTPConfig) Clone() interfaces.Config {
+	clone := *c
+	clone.SMTPSpecific.To = append([]string(nil), c.SMTPSpecific.To...)
+	return &clone
+}
+
+// ConnectionConfig接口实现
+
+// GetAddresses 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetAddresses() []string {
+	return []string{fmt.Sprintf("%s:%d", c.Address, c.Port)}
+}
+
+// GetCredentials 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetCredentials() map[string]string {
+	return map[string]string{
+		"username": c.Username,
+		"password": c.Password,
+	}
+}
+
+// GetPoolConfig 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetPoolConfig() interfaces.PoolConfig {
+	return &EmptyPoolConfig{}
+}
+
+// GetTimeout 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetTimeout() time.Duration {
+	return c.Timeout
+}
+
+// EmptyPoolConfig 空的连接池配置（连接数量由并发数决定）
+type EmptyPoolConfig struct{}
+
+func (p *EmptyPoolConfig) GetPoolSize() int                    { return 0 }
+func (p *EmptyPoolConfig) GetMinIdle() int                     { return 0 }
+func (p *EmptyPoolConfig) GetMaxIdle() int                     { return 0 }
+func (p *EmptyPoolConfig) GetIdleTimeout() time.Duration       { return 0 }
+func (p *EmptyPoolConfig) GetConnectionTimeout() time.Duration { return 0 }
+
+// BenchmarkConfig接口实现
+
+// GetTotal 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTotal() int {
+	return b.Total
+}
+
+// GetParallels 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetParallels() int {
+	return b.Parallels
+}
+
+// GetDataSize 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetDataSize() int {
+	return 0
+}
+
+// GetTTL 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTTL() time.Duration {
+	return 0
+}
+
+// GetReadPercent 实现BenchmarkConfig接口，发送均为写操作
+func (b *BenchmarkConfig) GetReadPercent() int {
+	return 0
+}
+
+// GetRandomKeys 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetRandomKeys() int {
+	return 0
+}
+
+// GetTestCase 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTestCase() string {
+	return b.TestCase
+}
+
+// GetDuration 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetDuration() time.Duration {
+	return b.Duration
+}
+
+// GetTimeout 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTimeout() time.Duration {
+	return 0
+}
+
+// GetRampUp 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetRampUp() time.Duration {
+	return 0
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*SMTPConfig)
+		valid  bool
+	}{
+		{"default", func(c *SMTPConfig) {}, true},
+		{"starttls with auth", func(c *SMTPConfig) {
+			c.Connection.Security = SecurityStartTLS
+			c.Connection.Username = "bench"
+			c.Connection.Password = "secret"
+			c.Connection.AuthMechanism = AuthLogin
+		}, true},
+		{"null sender", func(c *SMTPConfig) { c.SMTPSpecific.From = "" }, true},
+		{"templated recipient", func(c *SMTPConfig) {
+			c.SMTPSpecific.To = []string{"user{{randInt 1 1000}}@example.com"}
+		}, true},
+		{"size range", func(c *SMTPConfig) { c.SMTPSpecific.MaxMessageSize = 65536 }, true},
+		{"invalid security", func(c *SMTPConfig) { c.Connection.Security = "ssl" }, false},
+		{"invalid mechanism", func(c *SMTPConfig) { c.Connection.AuthMechanism = "xoauth2" }, false},
+		{"password without username", func(c *SMTPConfig) { c.Connection.Password = "secret" }, false},
+		{"no recipients", func(c *SMTPConfig) { c.SMTPSpecific.To = nil }, false},
+		{"bracketed recipient", func(c *SMTPConfig) { c.SMTPSpecific.To = []string{"<bench@example.com>"} }, false},
+		{"recipient without domain", func(c *SMTPConfig) { c.SMTPSpecific.To = []string{"bench"} }, false},
+		{"bad template", func(c *SMTPConfig) { c.SMTPSpecific.To = []string{"{{nope}}@example.com"} }, false},
+		{"header injection", func(c *SMTPConfig) { c.SMTPSpecific.From = "a@example.com\r\nRCPT TO:<b@example.com>" }, false},
+		{"max below size", func(c *SMTPConfig) { c.SMTPSpecific.MaxMessageSize = 100 }, false},
+		{"negative per connection", func(c *SMTPConfig) { c.SMTPSpecific.MessagesPerConnection = -1 }, false},
+		{"negative rate", func(c *SMTPConfig) { c.BenchMark.Rate = -1 }, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := NewDefaultSMTPConfig()
+			test.modify(cfg)
+			if err := cfg.Validate(); (err == nil) != test.valid {
+				t.Errorf("Validate() error = %v, want valid %v", err, test.valid)
+			}
+		})
+	}
+}
+
+func TestClone(t *testing.T) {
+	cfg := NewDefaultSMTPConfig()
+	clone := cfg.Clone().(*SMTPConfig)
+	clone.SMTPSpecific.To[0] = "other@example.com"
+	if cfg.SMTPSpecific.To[0] != "bench@example.com" {
+		t.Errorf("clone shares recipients with the original: %v", cfg.SMTPSpecific.To)
+	}
+}
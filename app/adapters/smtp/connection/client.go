@@ -0,0 +1,403 @@
+package connection
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/textproto"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"abc-runner/app/adapters/smtp/config"
+)
+
+// ErrBadConn 连接在I/O中途失败或已被服务端关闭，不能再复用
+var ErrBadConn = errors.New("smtp connection is broken")
+
+// ReplyError 服务端对某条命令的非预期应答
+type ReplyError struct {
+	Command string // 出错的命令，如"RCPT TO"
+	Code    int
+	Message string
+}
+
+func (e *ReplyError) Error() string {
+	return fmt.Sprintf("%s: %d %s", e.Command, e.Code, e.Message)
+}
+
+// Temporary 是否为4xx临时失败（消息被延迟，可稍后重试）
+func (e *ReplyError) Temporary() bool {
+	return e.Code >= 400 && e.Code < 500
+}
+
+// SendResult 一次发送的结果
+type SendResult struct {
+	Accepted int // 接受的收件人数
+	Rejected int // 被拒绝的收件人数，至少一个收件人被接受时消息仍会发送
+	Bytes    int // 消息的字节数（不含点号转义与结束行）
+}
+
+// Client 单个SMTP会话
+// 不是并发安全的，由连接池保证同一时刻只有一个使用者
+type Client struct {
+	conn    net.Conn
+	text    *textproto.Conn
+	timeout time.Duration
+
+	greeting   string            // 220问候语
+	extensions map[string]string // EHLO通告的扩展，键为大写的扩展名
+	sent       int               // 本连接已发送的消息数
+	broken     bool
+}
+
+// Dial 建立连接并完成问候、EHLO、可选的STARTTLS与认证
+func Dial(ctx context.Context, cfg *config.SMTPConfig, tlsConfig *tls.Config) (*Client, error) {
+	address := cfg.Connection.GetAddresses()[0]
+	dialer := &net.Dialer{Timeout: cfg.Connection.Timeout}
+	var netConn net.Conn
+	var err error
+	if cfg.Connection.Security == config.SecurityTLS {
+		netConn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", address)
+	} else {
+		netConn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{conn: netConn, text: textproto.NewConn(netConn), timeout: cfg.Connection.Timeout}
+	stop, _ := c.begin(ctx)
+	defer stop()
+	if err := c.handshake(cfg, tlsConfig); err != nil {
+		c.Close()
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// handshake 读取问候语并完成会话建立
+func (c *Client) handshake(cfg *config.SMTPConfig, tlsConfig *tls.Config) error {
+	_, greeting, err := c.text.ReadResponse(220)
+	if err != nil {
+		return c.replyError("greeting", err)
+	}
+	c.greeting = greeting
+
+	heloName := cfg.Connection.HeloName
+	if heloName == "" {
+		heloName, _ = os.Hostname()
+		if heloName == "" {
+			heloName = "localhost"
+		}
+	}
+	if err := c.hello(heloName); err != nil {
+		return err
+	}
+
+	if cfg.Connection.Security == config.SecurityStartTLS {
+		if _, ok := c.extensions["STARTTLS"]; !ok {
+			return fmt.Errorf("server does not advertise STARTTLS")
+		}
+		if _, err := c.cmd(220, "STARTTLS", "STARTTLS"); err != nil {
+			return err
+		}
+		tlsConn := tls.Client(c.conn, tlsConfig)
+		if err := tlsConn.Handshake(); err != nil {
+			return fmt.Errorf("TLS handshake failed: %w", err)
+		}
+		c.conn = tlsConn
+		c.text = textproto.NewConn(tlsConn)
+		// STARTTLS之后服务端丢弃之前的会话状态，需重新EHLO
+		if err := c.hello(heloName); err != nil {
+			return err
+		}
+	}
+
+	if cfg.Connection.Username != "" {
+		return c.auth(cfg.Connection)
+	}
+	return nil
+}
+
+// hello 发送EHLO并记录扩展，服务端不支持EHLO时退回HELO
+func (c *Client) hello(name string) error {
+	message, err := c.cmd(250, "EHLO", "EHLO %s", name)
+	var reply *ReplyError
+	if errors.As(err, &reply) && reply.Code >= 500 {
+		c.extensions = map[string]string{}
+		_, err = c.cmd(250, "HELO", "HELO %s", name)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	c.extensions = map[string]string{}
+	lines := strings.Split(message, "\n")
+	for _, line := range lines[1:] { // 首行为服务端域名
+		name, args, _ := strings.Cut(line, " ")
+		c.extensions[strings.ToUpper(name)] = args
+	}
+	return nil
+}
+
+// auth 按配置的机制认证，auto时按服务端通告依次选择CRAM-MD5、PLAIN、LOGIN
+func (c *Client) auth(cfg config.ConnectionConfig) error {
+	advertised, ok := c.extensions["AUTH"]
+	if !ok {
+		return fmt.Errorf("server does not advertise AUTH")
+	}
+	mechanism := cfg.AuthMechanism
+	if mechanism == config.AuthAuto {
+		mechanisms := strings.Fields(strings.ToLower(advertised))
+		mechanism = ""
+		for _, candidate := range []string{config.AuthCRAMMD5, config.AuthPlain, config.AuthLogin} {
+			if contains(mechanisms, candidate) {
+				mechanism = candidate
+				break
+			}
+		}
+		if mechanism == "" {
+			return fmt.Errorf("no supported AUTH mechanism in %q", advertised)
+		}
+	}
+
+	encode := base64.StdEncoding.EncodeToString
+	switch mechanism {
+	case config.AuthPlain:
+		_, err := c.cmd(235, "AUTH", "AUTH PLAIN %s", encode([]byte("\x00"+cfg.Username+"\x00"+cfg.Password)))
+		return err
+	case config.AuthLogin:
+		if _, err := c.cmd(334, "AUTH", "AUTH LOGIN"); err != nil {
+			return err
+		}
+		if _, err := c.cmd(334, "AUTH", "%s", encode([]byte(cfg.Username))); err != nil {
+			return err
+		}
+		_, err := c.cmd(235, "AUTH", "%s", encode([]byte(cfg.Password)))
+		return err
+	default:
+		challenge, err := c.cmd(334, "AUTH", "AUTH CRAM-MD5")
+		if err != nil {
+			return err
+		}
+		decoded, err := base64.StdEncoding.DecodeString(challenge)
+		if err != nil {
+			return fmt.Errorf("invalid CRAM-MD5 challenge: %w", err)
+		}
+		mac := hmac.New(md5.New, []byte(cfg.Password))
+		mac.Write(decoded)
+		_, err = c.cmd(235, "AUTH", "%s", encode([]byte(cfg.Username+" "+hex.EncodeToString(mac.Sum(nil)))))
+		return err
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Greeting 服务端的220问候语
+func (c *Client) Greeting() string {
+	return c.greeting
+}
+
+// Extensions 服务端通告的扩展名（大写）
+func (c *Client) Extensions() []string {
+	names := make([]string, 0, len(c.extensions))
+	for name := range c.extensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Sent 本连接已成功发送的消息数
+func (c *Client) Sent() int {
+	return c.sent
+}
+
+// Broken 连接是否已失效
+func (c *Client) Broken() bool {
+	return c.broken
+}
+
+// Send 发送一封消息：MAIL FROM、每个收件人的RCPT TO、DATA
+// 全部收件人被拒绝时返回第一个收件人的应答错误；MAIL或RCPT失败后发送RSET恢复会话
+func (c *Client) Send(ctx context.Context, from string, to []string, message []byte) (SendResult, error) {
+	stop, err := c.begin(ctx)
+	if err != nil {
+		return SendResult{}, err
+	}
+	defer stop()
+
+	var result SendResult
+	mail := "MAIL FROM:<%s>"
+	args := []interface{}{from}
+	if _, ok := c.extensions["SIZE"]; ok {
+		mail += " SIZE=%d"
+		args = append(args, len(message))
+	}
+	if _, err := c.cmd(250, "MAIL FROM", mail, args...); err != nil {
+		return result, c.abort(ctx, err)
+	}
+
+	var firstErr error
+	for _, recipient := range to {
+		// 250为接受，251为转发给其他地址
+		if _, err := c.cmd(25, "RCPT TO", "RCPT TO:<%s>", recipient); err != nil {
+			var reply *ReplyError
+			if !errors.As(err, &reply) {
+				return result, c.abort(ctx, err)
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+			result.Rejected++
+			continue
+		}
+		result.Accepted++
+	}
+	if result.Accepted == 0 {
+		return result, c.abort(ctx, firstErr)
+	}
+
+	if _, err := c.cmd(354, "DATA", "DATA"); err != nil {
+		return result, c.abort(ctx, err)
+	}
+	writer := c.text.DotWriter()
+	_, err = writer.Write(message)
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		return result, c.fail(ctx, err)
+	}
+	result.Bytes = len(message)
+
+	// 最终应答结束本次事务，失败时无需RSET
+	_, _, err = c.text.ReadResponse(250)
+	if err != nil {
+		return result, c.check(ctx, c.replyError("DATA", err))
+	}
+	c.sent++
+	return result, nil
+}
+
+// abort 在MAIL或RCPT失败后发送RSET，使连接可继续使用
+func (c *Client) abort(ctx context.Context, err error) error {
+	err = c.check(ctx, err)
+	if c.broken {
+		return err
+	}
+	if _, resetErr := c.cmd(250, "RSET", "RSET"); resetErr != nil {
+		c.check(ctx, resetErr)
+		c.broken = true
+	}
+	return err
+}
+
+// check 服务端应答错误保持原样（421表示服务端即将关闭连接），其余错误标记连接失效
+func (c *Client) check(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	var reply *ReplyError
+	if errors.As(err, &reply) {
+		if reply.Code == 421 {
+			c.broken = true
+		}
+		return err
+	}
+	return c.fail(ctx, err)
+}
+
+// Noop 发送NOOP，用于健康检查
+func (c *Client) Noop(ctx context.Context) error {
+	stop, err := c.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer stop()
+	_, err = c.cmd(250, "NOOP", "NOOP")
+	return c.check(ctx, err)
+}
+
+// Quit 发送QUIT并关闭连接，忽略服务端的应答错误
+func (c *Client) Quit() error {
+	if !c.broken {
+		c.conn.SetDeadline(time.Now().Add(c.timeout))
+		c.cmd(221, "QUIT", "QUIT")
+	}
+	return c.Close()
+}
+
+// Close 关闭连接
+func (c *Client) Close() error {
+	c.broken = true
+	return c.text.Close()
+}
+
+// cmd 发送一条命令并读取应答，应答码不符时返回ReplyError
+// expect为一位或两位数时按前缀匹配（如2匹配全部2xx）
+func (c *Client) cmd(expect int, command, format string, args ...interface{}) (string, error) {
+	id, err := c.text.Cmd(format, args...)
+	if err != nil {
+		return "", err
+	}
+	c.text.StartResponse(id)
+	defer c.text.EndResponse(id)
+	_, message, err := c.text.ReadResponse(expect)
+	if err != nil {
+		return message, c.replyError(command, err)
+	}
+	return message, nil
+}
+
+// replyError 将textproto的应答错误转换为ReplyError，其余错误原样返回
+func (c *Client) replyError(command string, err error) error {
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) {
+		return &ReplyError{Command: command, Code: protoErr.Code, Message: protoErr.Msg}
+	}
+	return err
+}
+
+// begin 设置本次往返的截止时间，ctx取消时中断I/O；返回的stop需在往返结束后调用
+func (c *Client) begin(ctx context.Context) (func() bool, error) {
+	if c.broken {
+		return nil, ErrBadConn
+	}
+	deadline := time.Now().Add(c.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	c.conn.SetDeadline(deadline)
+	return context.AfterFunc(ctx, func() {
+		c.conn.SetDeadline(time.Unix(1, 0))
+	}), nil
+}
+
+// fail 标记连接不可复用，ctx已结束时返回ctx的错误
+func (c *Client) fail(ctx context.Context, err error) error {
+	c.broken = true
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return fmt.Errorf("%w: %v", ErrBadConn, err)
+}
@@ -0,0 +1,180 @@
+package connection
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"abc-runner/app/adapters/smtp/config"
+)
+
+// Pool 与并发数相同的SMTP会话
+// 启动时建立全部会话；失效的会话以及达到messages_per_connection的会话在下次取用时重建，
+// 重建耗时计入该次发送的延迟
+type Pool struct {
+	cfg       *config.SMTPConfig
+	tlsConfig *tls.Config
+	perConn   int
+	slots     chan *Client // nil表示需要重建的空位
+
+	mutex sync.Mutex
+	all   map[*Client]struct{}
+
+	greeting    string
+	extensions  []string
+	connections atomic.Int64 // 建立的会话总数
+	reconnects  atomic.Int64 // 会话失效后的重建次数
+	waitNanos   atomic.Int64
+	acquires    atomic.Int64
+	closeOnce   sync.Once
+}
+
+// PoolStats 连接池统计
+type PoolStats struct {
+	Size        int           `json:"size"`
+	Connections int64         `json:"connections"` // 建立的会话总数，含按messages_per_connection轮换的会话
+	Reconnects  int64         `json:"reconnects"`  // 会话失效（网络错误或421）后的重建次数
+	AvgWait     time.Duration `json:"avg_wait"`
+}
+
+// NewPool 创建连接池并建立全部会话
+func NewPool(ctx context.Context, cfg *config.SMTPConfig) (*Pool, error) {
+	pool := &Pool{
+		cfg:       cfg,
+		tlsConfig: buildTLSConfig(cfg),
+		perConn:   cfg.SMTPSpecific.MessagesPerConnection,
+		slots:     make(chan *Client, cfg.BenchMark.Parallels),
+		all:       make(map[*Client]struct{}),
+	}
+	for i := 0; i < cfg.BenchMark.Parallels; i++ {
+		client, err := pool.dial(ctx)
+		if err != nil {
+			pool.Close()
+			return nil, err
+		}
+		if i == 0 {
+			pool.greeting = client.Greeting()
+			pool.extensions = client.Extensions()
+		}
+		pool.slots <- client
+	}
+	return pool, nil
+}
+
+// buildTLSConfig 构建TLS客户端配置，不使用TLS时返回nil
+func buildTLSConfig(cfg *config.SMTPConfig) *tls.Config {
+	if cfg.Connection.Security == config.SecurityNone {
+		return nil
+	}
+	return &tls.Config{
+		ServerName:         cfg.Connection.Address,
+		InsecureSkipVerify: cfg.Connection.InsecureSkipVerify,
+	}
+}
+
+func (p *Pool) dial(ctx context.Context) (*Client, error) {
+	client, err := Dial(ctx, p.cfg, p.tlsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SMTP session with %s: %w", p.Address(), err)
+	}
+	p.connections.Add(1)
+	p.mutex.Lock()
+	p.all[client] = struct{}{}
+	p.mutex.Unlock()
+	return client, nil
+}
+
+// Acquire 取用一个会话，空位在此重建；使用完毕后必须调用Release
+func (p *Pool) Acquire(ctx context.Context) (*Client, error) {
+	start := time.Now()
+	var client *Client
+	select {
+	case client = <-p.slots:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	p.waitNanos.Add(int64(time.Since(start)))
+	p.acquires.Add(1)
+
+	if client != nil {
+		return client, nil
+	}
+	client, err := p.dial(ctx)
+	if err != nil {
+		p.slots <- nil
+		return nil, err
+	}
+	return client, nil
+}
+
+// Release 归还会话：失效的会话被关闭，达到轮换条件的会话发送QUIT后关闭，均留下空位
+func (p *Pool) Release(client *Client) {
+	switch {
+	case client.Broken():
+		p.reconnects.Add(1)
+		p.remove(client)
+		client.Close()
+		client = nil
+	case p.perConn > 0 && client.Sent() >= p.perConn:
+		p.remove(client)
+		client.Quit()
+		client = nil
+	}
+	p.slots <- client
+}
+
+func (p *Pool) remove(client *Client) {
+	p.mutex.Lock()
+	delete(p.all, client)
+	p.mutex.Unlock()
+}
+
+// Greeting 首个会话的220问候语
+func (p *Pool) Greeting() string {
+	return p.greeting
+}
+
+// Extensions 首个会话中服务端通告的扩展
+func (p *Pool) Extensions() []string {
+	return p.extensions
+}
+
+// Stats 获取连接池统计
+func (p *Pool) Stats() PoolStats {
+	stats := PoolStats{
+		Size:        cap(p.slots),
+		Connections: p.connections.Load(),
+		Reconnects:  p.reconnects.Load(),
+	}
+	if acquires := p.acquires.Load(); acquires > 0 {
+		stats.AvgWait = time.Duration(p.waitNanos.Load() / acquires)
+	}
+	return stats
+}
+
+// Address 目标地址
+func (p *Pool) Address() string {
+	return p.cfg.Connection.GetAddresses()[0]
+}
+
+// Close 向所有会话并行发送QUIT并关闭
+func (p *Pool) Close() error {
+	p.closeOnce.Do(func() {
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+		var wg sync.WaitGroup
+		for client := range p.all {
+			wg.Add(1)
+			go func(client *Client) {
+				defer wg.Done()
+				client.Quit()
+			}(client)
+		}
+		wg.Wait()
+		p.all = make(map[*Client]struct{})
+	})
+	return nil
+}
@@ -0,0 +1,148 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"abc-runner/app/adapters/smtp/config"
+	"abc-runner/app/adapters/smtp/connection"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/core/utils"
+)
+
+// DeliveryStats 按结果分类的消息数，速率由调用方按实际测试时长计算
+type DeliveryStats struct {
+	Accepted           int64 `json:"accepted"`
+	Deferred           int64 `json:"deferred"`            // 4xx临时失败
+	Rejected           int64 `json:"rejected"`            // 5xx永久失败
+	Failed             int64 `json:"failed"`              // 网络错误与超时
+	RejectedRecipients int64 `json:"rejected_recipients"` // 含被接受消息中被拒绝的收件人
+	Bytes              int64 `json:"bytes"`               // 被接受消息的字节数
+}
+
+// SMTPExecutor SMTP操作执行器
+type SMTPExecutor struct {
+	pool    *connection.Pool
+	builder *MessageBuilder
+	from    string
+	pacer   *utils.Pacer
+	tracker *metrics.OperationTypeTracker
+
+	sequence           atomic.Int64
+	accepted           atomic.Int64
+	deferred           atomic.Int64
+	rejected           atomic.Int64
+	failed             atomic.Int64
+	rejectedRecipients atomic.Int64
+	bytes              atomic.Int64
+
+	mutex  sync.Mutex
+	random *rand.Rand
+}
+
+// NewSMTPExecutor 创建SMTP操作执行器，rate大于0时限制每秒发送的消息数
+func NewSMTPExecutor(pool *connection.Pool, cfg *config.SMTPConfig) *SMTPExecutor {
+	return &SMTPExecutor{
+		pool:    pool,
+		builder: NewMessageBuilder(cfg.SMTPSpecific),
+		from:    cfg.SMTPSpecific.From,
+		pacer:   utils.NewRatePacer(float64(cfg.BenchMark.Rate)),
+		tracker: newOperationTracker(),
+		random:  rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// ExecuteOperation 发送一封消息
+// 消息在计时前生成；延迟包含从连接池取用会话（及按需重建会话）的时间，直到DATA的最终应答
+func (e *SMTPExecutor) ExecuteOperation(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	if operation.Type != config.TestCaseSend {
+		return nil, fmt.Errorf("unsupported operation type: %s", operation.Type)
+	}
+	to, _ := operation.Params["to"].([]string)
+	if len(to) == 0 {
+		return nil, fmt.Errorf("send operation has no recipients")
+	}
+	if err := e.pacer.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	e.mutex.Lock()
+	size := e.builder.Size(e.random)
+	e.mutex.Unlock()
+	seq := e.sequence.Add(1)
+	message := e.builder.Build(seq, size, to[0], time.Now())
+
+	startTime := time.Now()
+	var result connection.SendResult
+	client, err := e.pool.Acquire(ctx)
+	if err == nil {
+		result, err = client.Send(ctx, e.from, to, message)
+		e.pool.Release(client)
+	}
+	duration := time.Since(startTime)
+
+	e.record(result, err)
+	var accepted int64
+	if err == nil {
+		accepted = int64(result.Bytes)
+	}
+	e.tracker.Record(operation.Type, accepted, result.Rejected > 0, duration, err)
+
+	opResult := &interfaces.OperationResult{
+		Success:      err == nil,
+		Duration:     duration,
+		IsRead:       false,
+		Error:        err,
+		Value:        result.Bytes,
+		BytesWritten: accepted,
+		Metadata: map[string]interface{}{
+			"protocol":       "smtp",
+			"operation_type": operation.Type,
+			"sequence":       seq,
+			"outcome":        outcome(err),
+			"recipients":     result.Accepted,
+		},
+	}
+	for k, v := range operation.Metadata {
+		opResult.Metadata[k] = v
+	}
+	return opResult, err
+}
+
+// record 按结果分类计数
+func (e *SMTPExecutor) record(result connection.SendResult, err error) {
+	e.rejectedRecipients.Add(int64(result.Rejected))
+	switch outcome(err) {
+	case "accepted":
+		e.accepted.Add(1)
+		e.bytes.Add(int64(result.Bytes))
+	case "deferred":
+		e.deferred.Add(1)
+	case "rejected":
+		e.rejected.Add(1)
+	default:
+		e.failed.Add(1)
+	}
+}
+
+// OperationStats 获取发送的统计
+func (e *SMTPExecutor) OperationStats() []OperationStats {
+	return e.tracker.Stats()
+}
+
+// DeliveryStats 获取按结果分类的统计
+func (e *SMTPExecutor) DeliveryStats() DeliveryStats {
+	return DeliveryStats{
+		Accepted:           e.accepted.Load(),
+		Deferred:           e.deferred.Load(),
+		Rejected:           e.rejected.Load(),
+		Failed:             e.failed.Load(),
+		RejectedRecipients: e.rejectedRecipients.Load(),
+		Bytes:              e.bytes.Load(),
+	}
+}
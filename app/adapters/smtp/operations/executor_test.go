@@ -0,0 +1,343 @@
+package operations
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"abc-runner/app/adapters/smtp/config"
+	"abc-runner/app/adapters/smtp/connection"
+	"abc-runner/app/core/interfaces"
+)
+
+// fakeMTA 最小的SMTP服务端：reject@前缀的收件人返回550，defer@前缀返回451
+type fakeMTA struct {
+	listener net.Listener
+
+	mutex    sync.Mutex
+	sessions int
+	auth     []string // 认证成功的机制
+	mailArgs []string
+	messages []string
+}
+
+func newFakeMTA(t *testing.T) *fakeMTA {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	server := &fakeMTA{listener: listener}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { listener.Close() })
+	return server
+}
+
+func (s *fakeMTA) serve(conn net.Conn) {
+	defer conn.Close()
+	s.mutex.Lock()
+	s.sessions++
+	s.mutex.Unlock()
+
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 fake.test ESMTP ready")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			text.PrintfLine("250-fake.test\r\n250-SIZE 10240000\r\n250-AUTH PLAIN LOGIN CRAM-MD5\r\n250 8BITMIME")
+		case "AUTH":
+			s.authenticate(text, arg)
+		case "MAIL":
+			s.mutex.Lock()
+			s.mailArgs = append(s.mailArgs, arg)
+			s.mutex.Unlock()
+			text.PrintfLine("250 OK")
+		case "RCPT":
+			switch {
+			case strings.Contains(arg, "<reject@"):
+				text.PrintfLine("550 5.1.1 no such user")
+			case strings.Contains(arg, "<defer@"):
+				text.PrintfLine("451 4.3.0 try again later")
+			default:
+				text.PrintfLine("250 OK")
+			}
+		case "DATA":
+			text.PrintfLine("354 go ahead")
+			data, err := io.ReadAll(text.DotReader())
+			if err != nil {
+				return
+			}
+			s.mutex.Lock()
+			s.messages = append(s.messages, string(data))
+			s.mutex.Unlock()
+			text.PrintfLine("250 queued")
+		case "RSET", "NOOP":
+			text.PrintfLine("250 OK")
+		case "QUIT":
+			text.PrintfLine("221 bye")
+			return
+		default:
+			text.PrintfLine("502 command not implemented")
+		}
+	}
+}
+
+// authenticate 校验用户bench、密码secret
+func (s *fakeMTA) authenticate(text *textproto.Conn, arg string) {
+	decode := func(value string) string {
+		decoded, _ := base64.StdEncoding.DecodeString(value)
+		return string(decoded)
+	}
+	mechanism, initial, _ := strings.Cut(arg, " ")
+	ok := false
+	switch mechanism {
+	case "PLAIN":
+		ok = decode(initial) == "\x00bench\x00secret"
+	case "LOGIN":
+		text.PrintfLine("334 VXNlcm5hbWU6")
+		user, _ := text.ReadLine()
+		text.PrintfLine("334 UGFzc3dvcmQ6")
+		password, _ := text.ReadLine()
+		ok = decode(user) == "bench" && decode(password) == "secret"
+	case "CRAM-MD5":
+		challenge := "<1234.5678@fake.test>"
+		text.PrintfLine("334 %s", base64.StdEncoding.EncodeToString([]byte(challenge)))
+		response, _ := text.ReadLine()
+		mac := hmac.New(md5.New, []byte("secret"))
+		mac.Write([]byte(challenge))
+		ok = decode(response) == "bench "+hex.EncodeToString(mac.Sum(nil))
+	}
+	if !ok {
+		text.PrintfLine("535 5.7.8 authentication failed")
+		return
+	}
+	s.mutex.Lock()
+	s.auth = append(s.auth, mechanism)
+	s.mutex.Unlock()
+	text.PrintfLine("235 2.7.0 authenticated")
+}
+
+func (s *fakeMTA) snapshot() (sessions int, auth, mailArgs, messages []string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.sessions, append([]string(nil), s.auth...), append([]string(nil), s.mailArgs...), append([]string(nil), s.messages...)
+}
+
+func newTestExecutor(t *testing.T, cfg *config.SMTPConfig, server *fakeMTA) (*SMTPExecutor, *connection.Pool) {
+	t.Helper()
+	host, port, _ := net.SplitHostPort(server.listener.Addr().String())
+	cfg.Connection.Address = host
+	cfg.Connection.Port, _ = strconv.Atoi(port)
+	cfg.Connection.Timeout = 5 * time.Second
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("invalid config: %v", err)
+	}
+
+	pool, err := connection.NewPool(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	t.Cleanup(func() { pool.Close() })
+	return NewSMTPExecutor(pool, cfg), pool
+}
+
+func sendOperation(to ...string) interfaces.Operation {
+	return interfaces.Operation{
+		Type:   config.TestCaseSend,
+		Params: map[string]interface{}{"to": to},
+	}
+}
+
+func TestMessageBuilderSize(t *testing.T) {
+	cfg := config.NewDefaultSMTPConfig().SMTPSpecific
+	builder := NewMessageBuilder(cfg)
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	message := string(builder.Build(42, 2000, "bench@example.com", now))
+	if len(message) != 2000 {
+		t.Fatalf("message length = %d, want 2000", len(message))
+	}
+	for _, want := range []string{
+		"From: <abc-runner@example.com>\r\n",
+		"To: <bench@example.com>\r\n",
+		"Subject: abc-runner test message #42\r\n",
+		"Date: Fri, 02 Jan 2026 03:04:05 +0000\r\n",
+		"@example.com>\r\n",
+		"X-Abc-Runner-Seq: 42\r\n\r\nseq=42\r\n",
+	} {
+		if !strings.Contains(message, want) {
+			t.Fatalf("message missing %q:\n%s", want, message)
+		}
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(message, "\r\n"), "\r\n") {
+		if len(line) > 78 || strings.HasPrefix(line, ".") {
+			t.Fatalf("unexpected line %q", line)
+		}
+	}
+
+	// 头部已超过目标长度时正文只有序号
+	if short := string(builder.Build(7, 10, "bench@example.com", now)); !strings.HasSuffix(short, "\r\n\r\nseq=7\r\n") {
+		t.Fatalf("unexpected short message: %q", short)
+	}
+}
+
+func TestSMTPExecutorAuth(t *testing.T) {
+	for _, mechanism := range []string{config.AuthPlain, config.AuthLogin, config.AuthCRAMMD5, config.AuthAuto} {
+		server := newFakeMTA(t)
+		cfg := config.NewDefaultSMTPConfig()
+		cfg.BenchMark.Parallels = 1
+		cfg.Connection.Username = "bench"
+		cfg.Connection.Password = "secret"
+		cfg.Connection.AuthMechanism = mechanism
+		cfg.SMTPSpecific.MessageSize = 1000
+		executor, _ := newTestExecutor(t, cfg, server)
+
+		result, err := executor.ExecuteOperation(context.Background(), sendOperation("a@example.com", "b@example.com"))
+		if err != nil {
+			t.Fatalf("%s: send failed: %v", mechanism, err)
+		}
+		if result.Metadata["outcome"] != "accepted" || result.Metadata["recipients"] != 2 {
+			t.Fatalf("%s: unexpected metadata: %v", mechanism, result.Metadata)
+		}
+
+		_, auth, mailArgs, messages := server.snapshot()
+		want := strings.ToUpper(mechanism)
+		if mechanism == config.AuthAuto {
+			want = "CRAM-MD5"
+		}
+		if len(auth) != 1 || auth[0] != want {
+			t.Fatalf("%s: server saw auth %v", mechanism, auth)
+		}
+		if len(mailArgs) != 1 || mailArgs[0] != "FROM:<abc-runner@example.com> SIZE=1000" {
+			t.Fatalf("%s: unexpected MAIL arguments %v", mechanism, mailArgs)
+		}
+		// DotReader把CRLF转换为LF
+		if len(messages) != 1 || !strings.Contains(messages[0], "X-Abc-Runner-Seq: 1\n") {
+			t.Fatalf("%s: unexpected messages %q", mechanism, messages)
+		}
+	}
+
+	server := newFakeMTA(t)
+	cfg := config.NewDefaultSMTPConfig()
+	cfg.BenchMark.Parallels = 1
+	cfg.Connection.Username = "bench"
+	cfg.Connection.Password = "wrong"
+	host, port, _ := net.SplitHostPort(server.listener.Addr().String())
+	cfg.Connection.Address = host
+	cfg.Connection.Port, _ = strconv.Atoi(port)
+	if _, err := connection.NewPool(context.Background(), cfg); err == nil || !strings.Contains(err.Error(), "AUTH: 535") {
+		t.Fatalf("expected AUTH failure, got %v", err)
+	}
+}
+
+func TestSMTPExecutorOutcomes(t *testing.T) {
+	server := newFakeMTA(t)
+	cfg := config.NewDefaultSMTPConfig()
+	cfg.BenchMark.Parallels = 1
+	cfg.SMTPSpecific.MessageSize = 500
+	executor, pool := newTestExecutor(t, cfg, server)
+
+	for _, tc := range []struct {
+		to      []string
+		outcome string
+	}{
+		{[]string{"ok@example.com"}, "accepted"},
+		{[]string{"ok@example.com", "reject@example.com"}, "accepted"},
+		{[]string{"reject@example.com"}, "rejected"},
+		{[]string{"defer@example.com", "reject@example.com"}, "deferred"},
+		{[]string{"ok@example.com"}, "accepted"},
+	} {
+		result, _ := executor.ExecuteOperation(context.Background(), sendOperation(tc.to...))
+		if result.Metadata["outcome"] != tc.outcome {
+			t.Fatalf("%v: outcome = %v, want %s (err %v)", tc.to, result.Metadata["outcome"], tc.outcome, result.Error)
+		}
+	}
+
+	delivery := executor.DeliveryStats()
+	if delivery.Accepted != 3 || delivery.Rejected != 1 || delivery.Deferred != 1 || delivery.Failed != 0 ||
+		delivery.RejectedRecipients != 4 || delivery.Bytes != 1500 {
+		t.Fatalf("unexpected delivery stats: %+v", delivery)
+	}
+
+	stats := executor.OperationStats()
+	if len(stats) != 1 {
+		t.Fatalf("unexpected operation stats: %+v", stats)
+	}
+	s := stats[0]
+	if s.Count != 5 || s.Errors != 2 || s.Flagged != 1 || s.Volume != 1500 ||
+		s.ErrorCodes["rcpt_to_550"] != 1 || s.ErrorCodes["rcpt_to_451"] != 1 {
+		t.Fatalf("unexpected operation stats: %+v", s)
+	}
+
+	// 被拒绝后以RSET恢复，会话保持可用
+	client, err := pool.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire failed: %v", err)
+	}
+	if err := client.Noop(context.Background()); err != nil || client.Broken() {
+		t.Fatalf("NOOP failed: %v", err)
+	}
+	pool.Release(client)
+	if sessions, _, _, messages := server.snapshot(); sessions != 1 || len(messages) != 3 {
+		t.Fatalf("sessions = %d, messages = %d", sessions, len(messages))
+	}
+	if pool.Stats().Reconnects != 0 {
+		t.Fatalf("unexpected pool stats: %+v", pool.Stats())
+	}
+}
+
+func TestSMTPPoolMessagesPerConnection(t *testing.T) {
+	server := newFakeMTA(t)
+	cfg := config.NewDefaultSMTPConfig()
+	cfg.BenchMark.Parallels = 1
+	cfg.SMTPSpecific.MessagesPerConnection = 2
+	executor, pool := newTestExecutor(t, cfg, server)
+
+	for i := 0; i < 5; i++ {
+		if _, err := executor.ExecuteOperation(context.Background(), sendOperation("ok@example.com")); err != nil {
+			t.Fatalf("send %d failed: %v", i, err)
+		}
+	}
+	if stats := pool.Stats(); stats.Connections != 3 || stats.Reconnects != 0 {
+		t.Fatalf("unexpected pool stats: %+v", stats)
+	}
+	if sessions, _, _, _ := server.snapshot(); sessions != 3 {
+		t.Fatalf("server saw %d sessions, want 3", sessions)
+	}
+}
+
+func TestOperationFactoryRecipients(t *testing.T) {
+	cfg := config.NewDefaultSMTPConfig()
+	cfg.SMTPSpecific.To = []string{"user{{seq}}@example.com", "fixed@example.com"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("invalid config: %v", err)
+	}
+	operation := NewOperationFactory(cfg).CreateOperation(7, &cfg.BenchMark)
+	to, _ := operation.Params["to"].([]string)
+	if len(to) != 2 || to[0] != "user7@example.com" || to[1] != "fixed@example.com" {
+		t.Fatalf("unexpected recipients: %v", to)
+	}
+}
@@ -0,0 +1,54 @@
+package operations
+
+import (
+	"abc-runner/app/adapters/smtp/config"
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/utils"
+)
+
+// OperationFactory SMTP操作工厂，收件人模板在此按操作编号渲染
+type OperationFactory struct {
+	recipients []*utils.PayloadTemplate
+}
+
+// NewOperationFactory 创建SMTP操作工厂，收件人模板已在配置校验时检查过语法
+func NewOperationFactory(cfg *config.SMTPConfig) *OperationFactory {
+	factory := &OperationFactory{}
+	for _, to := range cfg.SMTPSpecific.To {
+		template, err := utils.ParsePayloadTemplate(to, nil)
+		if err != nil {
+			continue
+		}
+		factory.recipients = append(factory.recipients, template)
+	}
+	return factory
+}
+
+// CreateOperation 创建发送操作，消息内容由执行器在发送时生成
+func (f *OperationFactory) CreateOperation(jobID int, cfg execution.BenchmarkConfig) interfaces.Operation {
+	to := make([]string, len(f.recipients))
+	for i, recipient := range f.recipients {
+		to[i] = recipient.Render(jobID)
+	}
+	return interfaces.Operation{
+		Type: config.TestCaseSend,
+		Params: map[string]interface{}{
+			"job_id": jobID,
+			"to":     to,
+		},
+		Metadata: map[string]string{
+			"operation_type": config.TestCaseSend,
+		},
+	}
+}
+
+// GetOperationType 获取操作类型
+func (f *OperationFactory) GetOperationType() string {
+	return config.TestCaseSend
+}
+
+// GetSupportedOperations 获取支持的操作类型
+func (f *OperationFactory) GetSupportedOperations() []string {
+	return []string{config.TestCaseSend}
+}
@@ -0,0 +1,90 @@
+package operations
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"abc-runner/app/adapters/smtp/config"
+)
+
+// filler 消息正文填充字符
+const filler = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// lineLength 正文每行的字符数（不含CRLF），低于RFC5322建议的78
+const lineLength = 76
+
+// MessageBuilder RFC5322纯文本消息构建器
+// 头部包含序号（主题末尾与X-Abc-Runner-Seq），正文按行填充到目标长度；头部已超过目标长度时正文只有序号
+type MessageBuilder struct {
+	from    string
+	subject string
+	domain  string
+	minSize int
+	maxSize int
+}
+
+// NewMessageBuilder 创建消息构建器
+func NewMessageBuilder(cfg config.SMTPSpecificConfig) *MessageBuilder {
+	from := cfg.From
+	if from == "" {
+		from = "abc-runner@localhost"
+	}
+	_, domain, _ := strings.Cut(from, "@")
+	maxSize := cfg.MaxMessageSize
+	if maxSize < cfg.MessageSize {
+		maxSize = cfg.MessageSize
+	}
+	return &MessageBuilder{
+		from:    from,
+		subject: cfg.Subject,
+		domain:  domain,
+		minSize: cfg.MessageSize,
+		maxSize: maxSize,
+	}
+}
+
+// Size 在长度范围内随机选取消息长度，固定长度时直接返回
+func (b *MessageBuilder) Size(random *rand.Rand) int {
+	if b.maxSize == b.minSize {
+		return b.minSize
+	}
+	return b.minSize + random.Intn(b.maxSize-b.minSize+1)
+}
+
+// Build 构建序号为seq、长度为size的消息，行以CRLF结尾
+func (b *MessageBuilder) Build(seq int64, size int, to string, now time.Time) []byte {
+	message := make([]byte, 0, size+lineLength)
+	message = append(message, "From: <"...)
+	message = append(message, b.from...)
+	message = append(message, ">\r\nTo: <"...)
+	message = append(message, to...)
+	message = append(message, ">\r\nSubject: "...)
+	message = append(message, b.subject...)
+	message = append(message, " #"...)
+	message = strconv.AppendInt(message, seq, 10)
+	message = append(message, "\r\nDate: "...)
+	message = now.AppendFormat(message, time.RFC1123Z)
+	message = append(message, "\r\nMessage-ID: <"...)
+	message = strconv.AppendInt(message, seq, 10)
+	message = append(message, '.')
+	message = strconv.AppendInt(message, now.UnixNano(), 10)
+	message = append(message, "@"...)
+	message = append(message, b.domain...)
+	message = append(message, ">\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=us-ascii\r\nX-Abc-Runner-Seq: "...)
+	message = strconv.AppendInt(message, seq, 10)
+	message = append(message, "\r\n\r\nseq="...)
+	message = strconv.AppendInt(message, seq, 10)
+	message = append(message, "\r\n"...)
+
+	for i := 0; len(message) < size; i++ {
+		// 每行至少一个字符，最后一行截短使总长度恰为size（剩余不足3字节时略超）
+		n := min(lineLength, max(size-len(message)-2, 1))
+		for j := 0; j < n; j++ {
+			message = append(message, filler[(i+j)%len(filler)])
+		}
+		message = append(message, "\r\n"...)
+	}
+	return message
+}
@@ -0,0 +1,52 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"abc-runner/app/adapters/smtp/connection"
+	"abc-runner/app/core/metrics"
+)
+
+// OperationStats 发送的统计，Volume为被接受消息的字节数（JSON字段"bytes"），
+// Flagged为部分收件人被拒绝但消息仍被接受的次数（JSON字段"partial"）；
+// 错误码为出错的命令与应答码（如"rcpt_to_550"、"data_451"），另有"timeout"与"connection"
+type OperationStats = metrics.OperationTypeStats
+
+// newOperationTracker 创建发送统计器
+func newOperationTracker() *metrics.OperationTypeTracker {
+	return metrics.NewOperationTypeTracker(errorCode, "bytes", "partial")
+}
+
+// errorCode 错误分类：服务端应答按命令与应答码归类，其余按超时与连接错误归类
+func errorCode(err error) string {
+	var reply *connection.ReplyError
+	if errors.As(err, &reply) {
+		return strings.ToLower(strings.ReplaceAll(reply.Command, " ", "_")) + "_" + strconv.Itoa(reply.Code)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	var timeoutErr interface{ Timeout() bool }
+	if errors.As(err, &timeoutErr) && timeoutErr.Timeout() {
+		return "timeout"
+	}
+	return "connection"
+}
+
+// outcome 发送结果分类：accepted、deferred（4xx）、rejected（5xx）或failed（网络错误与超时）
+func outcome(err error) string {
+	if err == nil {
+		return "accepted"
+	}
+	var reply *connection.ReplyError
+	if !errors.As(err, &reply) {
+		return "failed"
+	}
+	if reply.Temporary() {
+		return "deferred"
+	}
+	return "rejected"
+}
@@ -0,0 +1,154 @@
+package snmp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"abc-runner/app/adapters/snmp/config"
+	"abc-runner/app/adapters/snmp/connection"
+	"abc-runner/app/adapters/snmp/operations"
+	"abc-runner/app/core/interfaces"
+)
+
+// SNMPAdapter SNMP协议适配器 - 遵循统一架构模式
+// 职责：套接字管理、状态维护、健康检查
+type SNMPAdapter struct {
+	config           *config.SNMPConfig
+	client           *connection.Client
+	snmpOperations   *operations.SNMPExecutor
+	metricsCollector interfaces.DefaultMetricsCollector
+	mu               sync.RWMutex
+	isConnected      bool
+
+	// 统计信息
+	totalOperations  int64
+	failedOperations int64
+}
+
+// NewSNMPAdapter 创建SNMP适配器
+func NewSNMPAdapter(metricsCollector interfaces.DefaultMetricsCollector) *SNMPAdapter {
+	return &SNMPAdapter{
+		metricsCollector: metricsCollector,
+	}
+}
+
+// Connect 初始化UDP套接字并规范化OID
+func (s *SNMPAdapter) Connect(ctx context.Context, cfg interfaces.Config) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snmpConfig, ok := cfg.(*config.SNMPConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type for SNMP adapter: expected *config.SNMPConfig, got %T", cfg)
+	}
+
+	if err := snmpConfig.Validate(); err != nil {
+		return fmt.Errorf("config validation failed: %w", err)
+	}
+	for i, oid := range snmpConfig.SNMPSpecific.OIDs {
+		normalized, err := connection.NormalizeOID(oid)
+		if err != nil {
+			return fmt.Errorf("config validation failed: %w", err)
+		}
+		snmpConfig.SNMPSpecific.OIDs[i] = normalized
+	}
+	s.config = snmpConfig
+
+	client, err := connection.NewClient(snmpConfig)
+	if err != nil {
+		return err
+	}
+	s.client = client
+	s.snmpOperations = operations.NewSNMPExecutor(client, snmpConfig.SNMPSpecific.MaxRepetitions)
+
+	s.isConnected = true
+	return nil
+}
+
+// Execute 执行操作 - 使用执行器处理
+func (s *SNMPAdapter) Execute(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	if !s.isConnected {
+		return nil, fmt.Errorf("adapter not connected")
+	}
+
+	atomic.AddInt64(&s.totalOperations, 1)
+	result, err := s.snmpOperations.ExecuteOperation(ctx, operation)
+	if err != nil {
+		atomic.AddInt64(&s.failedOperations, 1)
+	}
+	return result, err
+}
+
+// Close 关闭套接字
+func (s *SNMPAdapter) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.client != nil {
+		s.client.Close()
+		s.client = nil
+	}
+	s.isConnected = false
+	return nil
+}
+
+// GetProtocolMetrics 获取协议特定指标
+func (s *SNMPAdapter) GetProtocolMetrics() map[string]interface{} {
+	metrics := map[string]interface{}{
+		"protocol":          "snmp",
+		"total_operations":  atomic.LoadInt64(&s.totalOperations),
+		"failed_operations": atomic.LoadInt64(&s.failedOperations),
+	}
+
+	if s.config != nil {
+		metrics["version"] = s.config.SNMPSpecific.Version
+		metrics["test_case"] = s.config.BenchMark.TestCase
+		metrics["oids"] = s.config.GetOIDs()
+	}
+	if stats := s.GetOIDStats(); stats != nil {
+		metrics["oid_stats"] = stats
+	}
+
+	return metrics
+}
+
+// GetOIDStats 获取按OID的延迟与超时统计，未连接时返回nil
+func (s *SNMPAdapter) GetOIDStats() []operations.OIDStats {
+	if s.snmpOperations == nil {
+		return nil
+	}
+	return s.snmpOperations.OIDStats()
+}
+
+// HealthCheck 健康检查：对第一个OID发送一次GET（walk用例为GETNEXT），代理有响应即视为可用
+func (s *SNMPAdapter) HealthCheck(ctx context.Context) error {
+	if !s.isConnected {
+		return fmt.Errorf("adapter not connected")
+	}
+
+	oid := s.config.GetOIDs()[0]
+	var response *connection.PDU
+	var err error
+	if s.config.BenchMark.TestCase == "walk" {
+		response, err = s.client.GetNext(ctx, oid)
+	} else {
+		response, err = s.client.Get(ctx, oid)
+	}
+	// 代理返回error-status也说明其可达
+	if err != nil && response == nil {
+		return fmt.Errorf("SNMP agent %s did not respond: %w", s.client.Address(), err)
+	}
+	return nil
+}
+
+// GetProtocolName 获取协议名称
+func (s *SNMPAdapter) GetProtocolName() string {
+	return "snmp"
+}
+
+// GetMetricsCollector 获取指标收集器
+func (s *SNMPAdapter) GetMetricsCollector() interfaces.DefaultMetricsCollector {
+	return s.metricsCollector
+}
@@ -0,0 +1,231 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+)
+
+// 默认轮询的OID
+const (
+	DefaultGetOID  = "1.3.6.1.2.1.1.3.0" // SNMPv2-MIB::sysUpTime.0
+	DefaultWalkOID = "1.3.6.1.2.1.1"     // SNMPv2-MIB::system
+)
+
+// SNMPConfig SNMP协议配置
+type SNMPConfig struct {
+	Protocol     string             `yaml:"protocol" json:"protocol"`
+	Connection   ConnectionConfig   `yaml:"connection" json:"connection"`
+	BenchMark    BenchmarkConfig    `yaml:"benchmark" json:"benchmark"`
+	SNMPSpecific SNMPSpecificConfig `yaml:"snmp_specific" json:"snmp_specific"`
+}
+
+// ConnectionConfig SNMP连接配置
+type ConnectionConfig struct {
+	Address string        `yaml:"address" json:"address"`
+	Port    int           `yaml:"port" json:"port"`
+	Timeout time.Duration `yaml:"timeout" json:"timeout"` // 单次请求等待响应的超时
+	Retries int           `yaml:"retries" json:"retries"` // 超时后的重传次数
+}
+
+// BenchmarkConfig SNMP基准测试配置
+type BenchmarkConfig struct {
+	Total     int           `yaml:"total" json:"total"`
+	Parallels int           `yaml:"parallels" json:"parallels"`
+	TestCase  string        `yaml:"test_case" json:"test_case"` // "get" 或 "walk"
+	Duration  time.Duration `yaml:"duration" json:"duration"`
+}
+
+// SNMPSpecificConfig SNMP特定配置
+type SNMPSpecificConfig struct {
+	Version        string   `yaml:"version" json:"version"`                 // "1" 或 "2c"
+	Community      string   `yaml:"community" json:"community"`             // 团体名
+	OIDs           []string `yaml:"oids" json:"oids"`                       // 轮询的OID，按任务轮转
+	MaxRepetitions int      `yaml:"max_repetitions" json:"max_repetitions"` // v2c walk每次GETBULK返回的最大行数
+}
+
+// NewDefaultSNMPConfig 创建默认SNMP配置
+func NewDefaultSNMPConfig() *SNMPConfig {
+	return &SNMPConfig{
+		Protocol: "snmp",
+		Connection: ConnectionConfig{
+			Address: "localhost",
+			Port:    161,
+			Timeout: 2 * time.Second,
+			Retries: 0,
+		},
+		BenchMark: BenchmarkConfig{
+			Total:     1000,
+			Parallels: 10,
+			TestCase:  "get",
+		},
+		SNMPSpecific: SNMPSpecificConfig{
+			Version:        "2c",
+			Community:      "public",
+			MaxRepetitions: 10,
+		},
+	}
+}
+
+// GetOIDs 获取轮询的OID，未配置时按测试用例使用默认OID
+func (c *SNMPConfig) GetOIDs() []string {
+	if len(c.SNMPSpecific.OIDs) > 0 {
+		return c.SNMPSpecific.OIDs
+	}
+	if c.BenchMark.TestCase == "walk" {
+		return []string{DefaultWalkOID}
+	}
+	return []string{DefaultGetOID}
+}
+
+// GetProtocol 实现Config接口
+func (c *SNMPConfig) GetProtocol() string {
+	return c.Protocol
+}
+
+// GetConnection 实现Config接口
+func (c *SNMPConfig) GetConnection() interfaces.ConnectionConfig {
+	return &c.Connection
+}
+
+// GetBenchmark 实现Config接口
+func (c *SNMPConfig) GetBenchmark() interfaces.BenchmarkConfig {
+	return &c.BenchMark
+}
+
+// Validate 实现Config接口
+func (c *SNMPConfig) Validate() error {
+	if c.Connection.Address == "" {
+		return fmt.Errorf("connection address cannot be empty")
+	}
+	if c.Connection.Port <= 0 || c.Connection.Port > 65535 {
+		return fmt.Errorf("invalid port number: %d", c.Connection.Port)
+	}
+	if c.Connection.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+	if c.Connection.Retries < 0 {
+		return fmt.Errorf("retries cannot be negative")
+	}
+
+	if c.BenchMark.Total <= 0 {
+		return fmt.Errorf("total operations must be greater than 0")
+	}
+	if c.BenchMark.Parallels <= 0 {
+		return fmt.Errorf("parallel connections must be greater than 0")
+	}
+
+	validTestCases := []string{"get", "walk"}
+	valid := false
+	for _, testCase := range validTestCases {
+		if c.BenchMark.TestCase == testCase {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Errorf("invalid test case: %s, valid options: %s",
+			c.BenchMark.TestCase, strings.Join(validTestCases, ", "))
+	}
+
+	if c.SNMPSpecific.Version != "1" && c.SNMPSpecific.Version != "2c" {
+		return fmt.Errorf("invalid SNMP version: %s, valid options: 1, 2c", c.SNMPSpecific.Version)
+	}
+	if c.SNMPSpecific.MaxRepetitions <= 0 {
+		return fmt.Errorf("max repetitions must be greater than 0")
+	}
+
+	return nil
+}
+
+// Clone 实现Config接口
+func (c *SNMPConfig) Clone() interfaces.Config {
+	clone := *c
+	clone.SNMPSpecific.OIDs = append([]string(nil), c.SNMPSpecific.OIDs...)
+	return &clone
+}
+
+// ConnectionConfig接口实现
+
+// GetAddresses 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetAddresses() []string {
+	return []string{fmt.Sprintf("%s:%d", c.Address, c.Port)}
+}
+
+// GetCredentials 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetCredentials() map[string]string {
+	return map[string]string{} // 团体名属于协议配置，不作为凭据暴露
+}
+
+// GetPoolConfig 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetPoolConfig() interfaces.PoolConfig {
+	return &EmptyPoolConfig{}
+}
+
+// GetTimeout 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetTimeout() time.Duration {
+	return c.Timeout
+}
+
+// EmptyPoolConfig 空的连接池配置（套接字数量由并发数决定）
+type EmptyPoolConfig struct{}
+
+func (p *EmptyPoolConfig) GetPoolSize() int                    { return 0 }
+func (p *EmptyPoolConfig) GetMinIdle() int                     { return 0 }
+func (p *EmptyPoolConfig) GetMaxIdle() int                     { return 0 }
+func (p *EmptyPoolConfig) GetIdleTimeout() time.Duration       { return 0 }
+func (p *EmptyPoolConfig) GetConnectionTimeout() time.Duration { return 0 }
+
+// BenchmarkConfig接口实现
+
+// GetTotal 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTotal() int {
+	return b.Total
+}
+
+// GetParallels 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetParallels() int {
+	return b.Parallels
+}
+
+// GetDataSize 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetDataSize() int {
+	return 0
+}
+
+// GetTTL 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTTL() time.Duration {
+	return 0
+}
+
+// GetReadPercent 实现BenchmarkConfig接口，SNMP轮询均为读操作
+func (b *BenchmarkConfig) GetReadPercent() int {
+	return 100
+}
+
+// GetRandomKeys 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetRandomKeys() int {
+	return 0
+}
+
+// GetTestCase 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTestCase() string {
+	return b.TestCase
+}
+
+// GetDuration 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetDuration() time.Duration {
+	return b.Duration
+}
+
+// GetTimeout 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTimeout() time.Duration {
+	return 0
+}
+
+// GetRampUp 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetRampUp() time.Duration {
+	return 0
+}
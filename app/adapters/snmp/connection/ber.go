@@ -0,0 +1,437 @@
+package connection
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// BER标签
+const (
+	tagInteger     byte = 0x02
+	tagOctetString byte = 0x04
+	tagNull        byte = 0x05
+	tagOID         byte = 0x06
+	tagSequence    byte = 0x30
+)
+
+// SNMP应用类型与异常值标签
+const (
+	TypeIPAddress      byte = 0x40
+	TypeCounter32      byte = 0x41
+	TypeGauge32        byte = 0x42
+	TypeTimeTicks      byte = 0x43
+	TypeOpaque         byte = 0x44
+	TypeCounter64      byte = 0x46
+	TypeNoSuchObject   byte = 0x80
+	TypeNoSuchInstance byte = 0x81
+	TypeEndOfMibView   byte = 0x82
+)
+
+// PDU类型
+const (
+	PDUGetRequest     byte = 0xA0
+	PDUGetNextRequest byte = 0xA1
+	PDUGetResponse    byte = 0xA2
+	PDUGetBulkRequest byte = 0xA5
+)
+
+// SNMP协议版本在报文中的取值
+const (
+	Version1  = 0
+	Version2c = 1
+)
+
+// errorStatusNames SNMP error-status名称
+var errorStatusNames = []string{
+	"noError", "tooBig", "noSuchName", "badValue", "readOnly", "genErr",
+	"noAccess", "wrongType", "wrongLength", "wrongEncoding", "wrongValue",
+	"noCreation", "inconsistentValue", "resourceUnavailable", "commitFailed",
+	"undoFailed", "authorizationError", "notWritable", "inconsistentName",
+}
+
+// ErrorStatusName 获取error-status名称
+func ErrorStatusName(status int) string {
+	if status >= 0 && status < len(errorStatusNames) {
+		return errorStatusNames[status]
+	}
+	return "error(" + strconv.Itoa(status) + ")"
+}
+
+// VarBind 变量绑定
+type VarBind struct {
+	OID   string
+	Type  byte
+	Value interface{} // int64、uint64、string（OCTET STRING/IpAddress/OID）、[]byte（Opaque）或nil
+}
+
+// IsException 是否为noSuchObject、noSuchInstance或endOfMibView
+func (v VarBind) IsException() bool {
+	return v.Type == TypeNoSuchObject || v.Type == TypeNoSuchInstance || v.Type == TypeEndOfMibView
+}
+
+// ExceptionName 异常值名称
+func (v VarBind) ExceptionName() string {
+	switch v.Type {
+	case TypeNoSuchObject:
+		return "noSuchObject"
+	case TypeNoSuchInstance:
+		return "noSuchInstance"
+	case TypeEndOfMibView:
+		return "endOfMibView"
+	}
+	return ""
+}
+
+// PDU SNMP协议数据单元
+// GetBulkRequest中ErrorStatus与ErrorIndex分别为non-repeaters与max-repetitions
+type PDU struct {
+	Type        byte
+	RequestID   int32
+	ErrorStatus int
+	ErrorIndex  int
+	VarBinds    []VarBind
+
+	// 客户端收到的响应上记录本次请求收发的UDP报文字节数（含重传），不参与编解码
+	BytesSent     int
+	BytesReceived int
+}
+
+// Message SNMPv1/v2c报文
+type Message struct {
+	Version   int
+	Community string
+	PDU       PDU
+}
+
+// Marshal 编码报文，请求中的变量绑定值均编码为NULL
+func (m *Message) Marshal() ([]byte, error) {
+	var varBinds []byte
+	for _, varBind := range m.PDU.VarBinds {
+		oid, err := encodeOID(varBind.OID)
+		if err != nil {
+			return nil, err
+		}
+		varBinds = append(varBinds, encodeTLV(tagSequence, append(oid, tagNull, 0x00))...)
+	}
+
+	var pdu []byte
+	pdu = append(pdu, encodeInteger(int64(m.PDU.RequestID))...)
+	pdu = append(pdu, encodeInteger(int64(m.PDU.ErrorStatus))...)
+	pdu = append(pdu, encodeInteger(int64(m.PDU.ErrorIndex))...)
+	pdu = append(pdu, encodeTLV(tagSequence, varBinds)...)
+
+	var message []byte
+	message = append(message, encodeInteger(int64(m.Version))...)
+	message = append(message, encodeTLV(tagOctetString, []byte(m.Community))...)
+	message = append(message, encodeTLV(m.PDU.Type, pdu)...)
+	return encodeTLV(tagSequence, message), nil
+}
+
+// UnmarshalMessage 解码报文
+func UnmarshalMessage(data []byte) (*Message, error) {
+	tag, body, _, err := readTLV(data)
+	if err != nil {
+		return nil, err
+	}
+	if tag != tagSequence {
+		return nil, fmt.Errorf("snmp message: expected SEQUENCE, got tag 0x%02x", tag)
+	}
+
+	message := &Message{}
+	version, body, err := readInteger(body)
+	if err != nil {
+		return nil, fmt.Errorf("snmp message version: %w", err)
+	}
+	message.Version = int(version)
+
+	tag, community, body, err := readTLV(body)
+	if err != nil || tag != tagOctetString {
+		return nil, fmt.Errorf("snmp message community: invalid encoding")
+	}
+	message.Community = string(community)
+
+	pduType, pdu, _, err := readTLV(body)
+	if err != nil {
+		return nil, fmt.Errorf("snmp pdu: %w", err)
+	}
+	message.PDU.Type = pduType
+
+	requestID, pdu, err := readInteger(pdu)
+	if err != nil {
+		return nil, fmt.Errorf("snmp request-id: %w", err)
+	}
+	errorStatus, pdu, err := readInteger(pdu)
+	if err != nil {
+		return nil, fmt.Errorf("snmp error-status: %w", err)
+	}
+	errorIndex, pdu, err := readInteger(pdu)
+	if err != nil {
+		return nil, fmt.Errorf("snmp error-index: %w", err)
+	}
+	message.PDU.RequestID = int32(requestID)
+	message.PDU.ErrorStatus = int(errorStatus)
+	message.PDU.ErrorIndex = int(errorIndex)
+
+	tag, varBinds, _, err := readTLV(pdu)
+	if err != nil || tag != tagSequence {
+		return nil, fmt.Errorf("snmp varbind list: invalid encoding")
+	}
+	for len(varBinds) > 0 {
+		var entry []byte
+		tag, entry, varBinds, err = readTLV(varBinds)
+		if err != nil || tag != tagSequence {
+			return nil, fmt.Errorf("snmp varbind: invalid encoding")
+		}
+		varBind, err := decodeVarBind(entry)
+		if err != nil {
+			return nil, err
+		}
+		message.PDU.VarBinds = append(message.PDU.VarBinds, varBind)
+	}
+
+	return message, nil
+}
+
+// decodeVarBind 解码单个变量绑定
+func decodeVarBind(data []byte) (VarBind, error) {
+	tag, oid, rest, err := readTLV(data)
+	if err != nil || tag != tagOID {
+		return VarBind{}, fmt.Errorf("snmp varbind name: invalid encoding")
+	}
+	name, err := decodeOID(oid)
+	if err != nil {
+		return VarBind{}, err
+	}
+
+	tag, value, _, err := readTLV(rest)
+	if err != nil {
+		return VarBind{}, fmt.Errorf("snmp varbind %s value: %w", name, err)
+	}
+
+	varBind := VarBind{OID: name, Type: tag}
+	switch tag {
+	case tagInteger:
+		varBind.Value = decodeInteger(value)
+	case tagOctetString:
+		varBind.Value = string(value)
+	case tagOID:
+		varBind.Value, err = decodeOID(value)
+	case TypeIPAddress:
+		if len(value) == 4 {
+			varBind.Value = net.IP(value).String()
+		}
+	case TypeCounter32, TypeGauge32, TypeTimeTicks, TypeCounter64:
+		varBind.Value = decodeUnsigned(value)
+	case TypeOpaque:
+		varBind.Value = append([]byte(nil), value...)
+	}
+	return varBind, err
+}
+
+// encodeTLV 编码标签-长度-值
+func encodeTLV(tag byte, value []byte) []byte {
+	encoded := append([]byte{tag}, encodeLength(len(value))...)
+	return append(encoded, value...)
+}
+
+// encodeLength 编码BER长度
+func encodeLength(length int) []byte {
+	if length < 0x80 {
+		return []byte{byte(length)}
+	}
+	var bytes []byte
+	for length > 0 {
+		bytes = append([]byte{byte(length)}, bytes...)
+		length >>= 8
+	}
+	return append([]byte{0x80 | byte(len(bytes))}, bytes...)
+}
+
+// encodeInteger 编码INTEGER（最短补码）
+func encodeInteger(value int64) []byte {
+	var bytes []byte
+	for {
+		bytes = append([]byte{byte(value)}, bytes...)
+		value >>= 8
+		if (value == 0 && bytes[0]&0x80 == 0) || (value == -1 && bytes[0]&0x80 != 0) {
+			break
+		}
+	}
+	return encodeTLV(tagInteger, bytes)
+}
+
+// encodeOID 编码点分形式的OID
+func encodeOID(oid string) ([]byte, error) {
+	arcs, err := ParseOID(oid)
+	if err != nil {
+		return nil, err
+	}
+	encoded := encodeBase128(arcs[0]*40 + arcs[1])
+	for _, arc := range arcs[2:] {
+		encoded = append(encoded, encodeBase128(arc)...)
+	}
+	return encodeTLV(tagOID, encoded), nil
+}
+
+// encodeBase128 编码OID子标识
+func encodeBase128(value uint64) []byte {
+	bytes := []byte{byte(value & 0x7f)}
+	for value >>= 7; value > 0; value >>= 7 {
+		bytes = append([]byte{byte(value&0x7f) | 0x80}, bytes...)
+	}
+	return bytes
+}
+
+// readTLV 读取一个标签-长度-值，返回剩余数据
+func readTLV(data []byte) (byte, []byte, []byte, error) {
+	if len(data) < 2 {
+		return 0, nil, nil, fmt.Errorf("truncated BER data")
+	}
+	tag := data[0]
+	length := int(data[1])
+	offset := 2
+	if length&0x80 != 0 {
+		count := length & 0x7f
+		if count == 0 || count > 4 || len(data) < 2+count {
+			return 0, nil, nil, fmt.Errorf("invalid BER length")
+		}
+		length = 0
+		for _, b := range data[2 : 2+count] {
+			length = length<<8 | int(b)
+		}
+		offset += count
+	}
+	if length < 0 || len(data) < offset+length {
+		return 0, nil, nil, fmt.Errorf("truncated BER data")
+	}
+	return tag, data[offset : offset+length], data[offset+length:], nil
+}
+
+// readInteger 读取INTEGER
+func readInteger(data []byte) (int64, []byte, error) {
+	tag, value, rest, err := readTLV(data)
+	if err != nil {
+		return 0, nil, err
+	}
+	if tag != tagInteger {
+		return 0, nil, fmt.Errorf("expected INTEGER, got tag 0x%02x", tag)
+	}
+	return decodeInteger(value), rest, nil
+}
+
+// decodeInteger 解码补码整数
+func decodeInteger(value []byte) int64 {
+	var result int64
+	for i, b := range value {
+		if i == 0 && b&0x80 != 0 {
+			result = -1
+		}
+		result = result<<8 | int64(b)
+	}
+	return result
+}
+
+// decodeUnsigned 解码无符号整数（Counter32、Gauge32、TimeTicks、Counter64）
+func decodeUnsigned(value []byte) uint64 {
+	var result uint64
+	for _, b := range value {
+		result = result<<8 | uint64(b)
+	}
+	return result
+}
+
+// decodeOID 解码OID为点分形式
+func decodeOID(value []byte) (string, error) {
+	if len(value) == 0 {
+		return "", fmt.Errorf("empty OID")
+	}
+
+	var arcs []uint64
+	var arc uint64
+	for i, b := range value {
+		arc = arc<<7 | uint64(b&0x7f)
+		if b&0x80 != 0 {
+			if i == len(value)-1 {
+				return "", fmt.Errorf("truncated OID")
+			}
+			continue
+		}
+		if len(arcs) == 0 {
+			first := arc / 40
+			if first > 2 {
+				first = 2
+			}
+			arcs = append(arcs, first, arc-first*40)
+		} else {
+			arcs = append(arcs, arc)
+		}
+		arc = 0
+	}
+	return FormatOID(arcs), nil
+}
+
+// ParseOID 解析点分形式的OID，允许前导点
+func ParseOID(oid string) ([]uint64, error) {
+	parts := strings.Split(strings.TrimPrefix(strings.TrimSpace(oid), "."), ".")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("invalid OID %q: needs at least two arcs", oid)
+	}
+	arcs := make([]uint64, len(parts))
+	for i, part := range parts {
+		arc, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid OID %q: %w", oid, err)
+		}
+		arcs[i] = arc
+	}
+	if arcs[0] > 2 || (arcs[0] < 2 && arcs[1] >= 40) {
+		return nil, fmt.Errorf("invalid OID %q: bad leading arcs", oid)
+	}
+	return arcs, nil
+}
+
+// FormatOID 将子标识格式化为点分形式
+func FormatOID(arcs []uint64) string {
+	parts := make([]string, len(arcs))
+	for i, arc := range arcs {
+		parts[i] = strconv.FormatUint(arc, 10)
+	}
+	return strings.Join(parts, ".")
+}
+
+// NormalizeOID 规范化OID（去除前导点并校验）
+func NormalizeOID(oid string) (string, error) {
+	arcs, err := ParseOID(oid)
+	if err != nil {
+		return "", err
+	}
+	return FormatOID(arcs), nil
+}
+
+// CompareOID 按子标识逐个比较两个OID，返回-1、0或1
+func CompareOID(a, b string) int {
+	arcsA, _ := ParseOID(a)
+	arcsB, _ := ParseOID(b)
+	for i := 0; i < len(arcsA) && i < len(arcsB); i++ {
+		if arcsA[i] != arcsB[i] {
+			if arcsA[i] < arcsB[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(arcsA) < len(arcsB):
+		return -1
+	case len(arcsA) > len(arcsB):
+		return 1
+	}
+	return 0
+}
+
+// InSubtree 判断oid是否位于root子树内（不含root本身）
+func InSubtree(root, oid string) bool {
+	return strings.HasPrefix(oid, root+".")
+}
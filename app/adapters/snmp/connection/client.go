@@ -0,0 +1,185 @@
+package connection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"abc-runner/app/adapters/snmp/config"
+)
+
+// ErrTimeout 请求（含重传）在超时内未收到匹配的响应
+var ErrTimeout = errors.New("snmp request timed out")
+
+// maxPacketSize 接收缓冲区大小
+const maxPacketSize = 65535
+
+// Client SNMPv1/v2c客户端
+// 维护一组已连接的UDP套接字，每个请求独占一个套接字，按request-id匹配响应，
+// 丢弃超时请求迟到的响应
+type Client struct {
+	address   string
+	version   int
+	community string
+	timeout   time.Duration
+	retries   int
+
+	sockets   chan *net.UDPConn
+	all       []*net.UDPConn
+	requestID atomic.Int32
+	closeOnce sync.Once
+}
+
+// NewClient 创建SNMP客户端，套接字数量与并发数一致
+func NewClient(cfg *config.SNMPConfig) (*Client, error) {
+	address := net.JoinHostPort(cfg.Connection.Address, fmt.Sprint(cfg.Connection.Port))
+	version := Version2c
+	if cfg.SNMPSpecific.Version == "1" {
+		version = Version1
+	}
+
+	client := &Client{
+		address:   address,
+		version:   version,
+		community: cfg.SNMPSpecific.Community,
+		timeout:   cfg.Connection.Timeout,
+		retries:   cfg.Connection.Retries,
+		sockets:   make(chan *net.UDPConn, cfg.BenchMark.Parallels),
+	}
+	client.requestID.Store(int32(time.Now().UnixNano() & 0x3fffffff))
+
+	for i := 0; i < cfg.BenchMark.Parallels; i++ {
+		conn, err := net.Dial("udp", address)
+		if err != nil {
+			client.Close()
+			return nil, fmt.Errorf("failed to dial SNMP agent %s: %w", address, err)
+		}
+		udpConn := conn.(*net.UDPConn)
+		client.all = append(client.all, udpConn)
+		client.sockets <- udpConn
+	}
+	return client, nil
+}
+
+// Address 目标地址
+func (c *Client) Address() string {
+	return c.address
+}
+
+// Get 发送GetRequest
+func (c *Client) Get(ctx context.Context, oids ...string) (*PDU, error) {
+	return c.request(ctx, PDUGetRequest, 0, 0, oids)
+}
+
+// GetNext 发送GetNextRequest
+func (c *Client) GetNext(ctx context.Context, oids ...string) (*PDU, error) {
+	return c.request(ctx, PDUGetNextRequest, 0, 0, oids)
+}
+
+// GetBulk 发送GetBulkRequest（仅v2c）
+func (c *Client) GetBulk(ctx context.Context, nonRepeaters, maxRepetitions int, oids ...string) (*PDU, error) {
+	if c.version == Version1 {
+		return nil, fmt.Errorf("GetBulkRequest requires SNMP v2c")
+	}
+	return c.request(ctx, PDUGetBulkRequest, nonRepeaters, maxRepetitions, oids)
+}
+
+// IsV1 是否为SNMPv1
+func (c *Client) IsV1() bool {
+	return c.version == Version1
+}
+
+// request 发送请求并等待匹配的响应，超时后按配置重传
+// 响应中error-status非零时返回错误
+func (c *Client) request(ctx context.Context, pduType byte, errorStatus, errorIndex int, oids []string) (*PDU, error) {
+	var conn *net.UDPConn
+	select {
+	case conn = <-c.sockets:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { c.sockets <- conn }()
+
+	requestID := c.requestID.Add(1) & 0x7fffffff
+	message := Message{
+		Version:   c.version,
+		Community: c.community,
+		PDU: PDU{
+			Type:        pduType,
+			RequestID:   requestID,
+			ErrorStatus: errorStatus,
+			ErrorIndex:  errorIndex,
+		},
+	}
+	for _, oid := range oids {
+		message.PDU.VarBinds = append(message.PDU.VarBinds, VarBind{OID: oid})
+	}
+	packet, err := message.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := make([]byte, maxPacketSize)
+	sent := 0
+	for attempt := 0; attempt <= c.retries; attempt++ {
+		if _, err := conn.Write(packet); err != nil {
+			return nil, fmt.Errorf("failed to send SNMP request: %w", err)
+		}
+		sent += len(packet)
+
+		deadline := time.Now().Add(c.timeout)
+		if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+			deadline = ctxDeadline
+		}
+		conn.SetReadDeadline(deadline)
+
+		response, err := c.readResponse(conn, buffer, requestID)
+		if err == nil {
+			response.BytesSent = sent
+			if response.ErrorStatus != 0 {
+				return response, fmt.Errorf("snmp error-status %s (index %d)",
+					ErrorStatusName(response.ErrorStatus), response.ErrorIndex)
+			}
+			return response, nil
+		}
+
+		var netErr net.Error
+		if !errors.As(err, &netErr) || !netErr.Timeout() {
+			return nil, err
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+	return nil, ErrTimeout
+}
+
+// readResponse 读取与requestID匹配的GetResponse，忽略无法解析或不匹配的报文
+func (c *Client) readResponse(conn *net.UDPConn, buffer []byte, requestID int32) (*PDU, error) {
+	for {
+		n, err := conn.Read(buffer)
+		if err != nil {
+			return nil, err
+		}
+		message, err := UnmarshalMessage(buffer[:n])
+		if err != nil || message.PDU.Type != PDUGetResponse || message.PDU.RequestID != requestID {
+			continue
+		}
+		message.PDU.BytesReceived = n
+		return &message.PDU, nil
+	}
+}
+
+// Close 关闭所有套接字
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		for _, conn := range c.all {
+			conn.Close()
+		}
+	})
+	return nil
+}
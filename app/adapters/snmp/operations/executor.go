@@ -0,0 +1,140 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"abc-runner/app/adapters/snmp/connection"
+	"abc-runner/app/core/interfaces"
+)
+
+// maxWalkRequests 单次walk的最大请求数，防止代理返回异常时无限循环
+const maxWalkRequests = 10000
+
+// SNMPExecutor SNMP操作执行器
+type SNMPExecutor struct {
+	client         *connection.Client
+	maxRepetitions int
+	oidTracker     *OIDTracker
+}
+
+// NewSNMPExecutor 创建SNMP操作执行器
+func NewSNMPExecutor(client *connection.Client, maxRepetitions int) *SNMPExecutor {
+	return &SNMPExecutor{
+		client:         client,
+		maxRepetitions: maxRepetitions,
+		oidTracker:     NewOIDTracker(),
+	}
+}
+
+// OIDStats 获取按OID的延迟与超时统计
+func (e *SNMPExecutor) OIDStats() []OIDStats {
+	return e.oidTracker.Stats()
+}
+
+// ExecuteOperation 执行SNMP操作
+func (e *SNMPExecutor) ExecuteOperation(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	startTime := time.Now()
+	result := &interfaces.OperationResult{
+		IsRead:   true,
+		Metadata: make(map[string]interface{}),
+	}
+
+	var varBinds []connection.VarBind
+	var requests int
+	var err error
+	switch operation.Type {
+	case "get":
+		varBinds, err = e.executeGet(ctx, operation.Key, result)
+		requests = 1
+	case "walk":
+		varBinds, requests, err = e.executeWalk(ctx, operation.Key, result)
+	default:
+		err = fmt.Errorf("unsupported operation type: %s", operation.Type)
+	}
+
+	result.Duration = time.Since(startTime)
+	result.Success = err == nil
+	result.Error = err
+	e.oidTracker.Record(operation.Key, len(varBinds), result.Duration, err)
+
+	for k, v := range operation.Metadata {
+		result.Metadata[k] = v
+	}
+	result.Metadata["protocol"] = "snmp"
+	result.Metadata["operation_type"] = operation.Type
+	result.Metadata["oid"] = operation.Key
+	result.Metadata["requests"] = requests
+	result.Metadata["varbinds"] = len(varBinds)
+	if err == nil && operation.Type == "get" {
+		result.Value = varBinds[0].Value
+	} else {
+		result.Value = len(varBinds)
+	}
+
+	return result, err
+}
+
+// executeGet 获取单个OID，异常值（noSuchObject等）视为失败
+func (e *SNMPExecutor) executeGet(ctx context.Context, oid string, result *interfaces.OperationResult) ([]connection.VarBind, error) {
+	response, err := e.client.Get(ctx, oid)
+	addTransfer(result, response)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.VarBinds) != 1 {
+		return nil, fmt.Errorf("expected 1 varbind, got %d", len(response.VarBinds))
+	}
+	if varBind := response.VarBinds[0]; varBind.IsException() {
+		return nil, fmt.Errorf("%s: %s", oid, varBind.ExceptionName())
+	}
+	return response.VarBinds, nil
+}
+
+// executeWalk 遍历子树：v2c使用GETBULK，v1使用GETNEXT
+// 返回的OID离开子树、到达endOfMibView或v1返回noSuchName时结束
+func (e *SNMPExecutor) executeWalk(ctx context.Context, root string, result *interfaces.OperationResult) ([]connection.VarBind, int, error) {
+	var collected []connection.VarBind
+	current := root
+	for requests := 1; requests <= maxWalkRequests; requests++ {
+		var response *connection.PDU
+		var err error
+		if e.client.IsV1() {
+			response, err = e.client.GetNext(ctx, current)
+			// v1代理在MIB末尾返回noSuchName
+			if err != nil && response != nil && connection.ErrorStatusName(response.ErrorStatus) == "noSuchName" {
+				return collected, requests, nil
+			}
+		} else {
+			response, err = e.client.GetBulk(ctx, 0, e.maxRepetitions, current)
+		}
+		addTransfer(result, response)
+		if err != nil {
+			return collected, requests, err
+		}
+		if len(response.VarBinds) == 0 {
+			return collected, requests, nil
+		}
+
+		for _, varBind := range response.VarBinds {
+			if varBind.Type == connection.TypeEndOfMibView || !connection.InSubtree(root, varBind.OID) {
+				return collected, requests, nil
+			}
+			if connection.CompareOID(varBind.OID, current) <= 0 {
+				return collected, requests, fmt.Errorf("walk %s: agent returned non-increasing OID %s after %s", root, varBind.OID, current)
+			}
+			collected = append(collected, varBind)
+			current = varBind.OID
+		}
+	}
+	return collected, maxWalkRequests, fmt.Errorf("walk %s: exceeded %d requests", root, maxWalkRequests)
+}
+
+// addTransfer 将响应上记录的收发报文字节数累加到结果，超时等未收到响应时不计入
+func addTransfer(result *interfaces.OperationResult, response *connection.PDU) {
+	if response != nil {
+		result.BytesWritten += int64(response.BytesSent)
+		result.BytesRead += int64(response.BytesReceived)
+	}
+}
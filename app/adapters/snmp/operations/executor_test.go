@@ -0,0 +1,181 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"abc-runner/app/adapters/snmp/config"
+	"abc-runner/app/adapters/snmp/connection"
+	"abc-runner/app/core/interfaces"
+)
+
+// testMIB 模拟代理的MIB，按OID升序排列
+var testMIB = []string{
+	"1.3.6.1.2.1.1.1.0",
+	"1.3.6.1.2.1.1.3.0",
+	"1.3.6.1.2.1.1.4.0",
+	"1.3.6.1.2.1.1.5.0",
+	"1.3.6.1.2.1.1.6.0",
+	"1.3.6.1.2.1.1.7.0",
+	"1.3.6.1.2.1.2.1.0",
+}
+
+// startTestAgent 启动一个应答Get/GetNext/GetBulk的模拟SNMP代理，drop为true时不应答
+func startTestAgent(t *testing.T, drop bool) int {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buffer := make([]byte, 65535)
+		for {
+			n, addr, err := conn.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			if drop {
+				continue
+			}
+			request, err := connection.UnmarshalMessage(buffer[:n])
+			if err != nil {
+				continue
+			}
+			response := *request
+			response.PDU = respond(request.PDU)
+			packet, err := response.Marshal()
+			if err != nil {
+				continue
+			}
+			conn.WriteTo(packet, addr)
+		}
+	}()
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+// respond 按testMIB生成响应PDU，超出MIB时返回noSuchName
+func respond(request connection.PDU) connection.PDU {
+	response := connection.PDU{Type: connection.PDUGetResponse, RequestID: request.RequestID}
+	next := func(oid string) (string, bool) {
+		for _, candidate := range testMIB {
+			if connection.CompareOID(candidate, oid) > 0 {
+				return candidate, true
+			}
+		}
+		return "", false
+	}
+
+	switch request.Type {
+	case connection.PDUGetRequest:
+		for i, varBind := range request.VarBinds {
+			found := false
+			for _, candidate := range testMIB {
+				found = found || candidate == varBind.OID
+			}
+			if !found {
+				return connection.PDU{Type: connection.PDUGetResponse, RequestID: request.RequestID, ErrorStatus: 2, ErrorIndex: i + 1, VarBinds: request.VarBinds}
+			}
+			response.VarBinds = append(response.VarBinds, connection.VarBind{OID: varBind.OID})
+		}
+	case connection.PDUGetNextRequest:
+		for i, varBind := range request.VarBinds {
+			oid, ok := next(varBind.OID)
+			if !ok {
+				return connection.PDU{Type: connection.PDUGetResponse, RequestID: request.RequestID, ErrorStatus: 2, ErrorIndex: i + 1, VarBinds: request.VarBinds}
+			}
+			response.VarBinds = append(response.VarBinds, connection.VarBind{OID: oid})
+		}
+	case connection.PDUGetBulkRequest:
+		current := request.VarBinds[0].OID
+		for i := 0; i < request.ErrorIndex; i++ {
+			oid, ok := next(current)
+			if !ok {
+				break
+			}
+			response.VarBinds = append(response.VarBinds, connection.VarBind{OID: oid})
+			current = oid
+		}
+	}
+	return response
+}
+
+func newTestExecutor(t *testing.T, port int, version string, timeout time.Duration) *SNMPExecutor {
+	t.Helper()
+	cfg := config.NewDefaultSNMPConfig()
+	cfg.Connection.Address = "127.0.0.1"
+	cfg.Connection.Port = port
+	cfg.Connection.Timeout = timeout
+	cfg.BenchMark.Parallels = 2
+	cfg.SNMPSpecific.Version = version
+
+	client, err := connection.NewClient(cfg)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return NewSNMPExecutor(client, 2)
+}
+
+func TestSNMPExecutorGet(t *testing.T) {
+	executor := newTestExecutor(t, startTestAgent(t, false), "2c", time.Second)
+	ctx := context.Background()
+
+	result, err := executor.ExecuteOperation(ctx, interfaces.Operation{Type: "get", Key: "1.3.6.1.2.1.1.3.0"})
+	if err != nil || !result.Success {
+		t.Fatalf("get failed: %v", err)
+	}
+	if result.BytesWritten == 0 || result.BytesRead == 0 {
+		t.Fatalf("expected packet sizes to be counted, got written %d, read %d", result.BytesWritten, result.BytesRead)
+	}
+	if _, err := executor.ExecuteOperation(ctx, interfaces.Operation{Type: "get", Key: "1.3.6.1.2.1.1.2.0"}); err == nil {
+		t.Fatal("expected noSuchName error for missing OID")
+	}
+
+	stats := executor.OIDStats()
+	if len(stats) != 2 || stats[0].OID != "1.3.6.1.2.1.1.2.0" || stats[0].Errors != 1 || stats[1].VarBinds != 1 {
+		t.Fatalf("unexpected OID stats: %+v", stats)
+	}
+}
+
+func TestSNMPExecutorWalk(t *testing.T) {
+	port := startTestAgent(t, false)
+	for _, version := range []string{"2c", "1"} {
+		executor := newTestExecutor(t, port, version, time.Second)
+
+		// system子树共6个OID，之后的OID离开子树
+		result, err := executor.ExecuteOperation(context.Background(), interfaces.Operation{Type: "walk", Key: "1.3.6.1.2.1.1"})
+		if err != nil {
+			t.Fatalf("v%s walk failed: %v", version, err)
+		}
+		if result.Value != 6 {
+			t.Fatalf("v%s walk returned %v varbinds, want 6", version, result.Value)
+		}
+		if result.BytesWritten == 0 || result.BytesRead <= result.BytesWritten {
+			t.Fatalf("v%s walk: unexpected packet sizes: written %d, read %d", version, result.BytesWritten, result.BytesRead)
+		}
+
+		// 遍历到MIB末尾：v2c返回空响应，v1返回noSuchName
+		result, err = executor.ExecuteOperation(context.Background(), interfaces.Operation{Type: "walk", Key: "1.3.6.1.2.1.2"})
+		if err != nil || result.Value != 1 {
+			t.Fatalf("v%s walk to end of MIB: value=%v err=%v", version, result.Value, err)
+		}
+	}
+}
+
+func TestSNMPExecutorTimeout(t *testing.T) {
+	executor := newTestExecutor(t, startTestAgent(t, true), "2c", 50*time.Millisecond)
+
+	_, err := executor.ExecuteOperation(context.Background(), interfaces.Operation{Type: "get", Key: "1.3.6.1.2.1.1.3.0"})
+	if !errors.Is(err, connection.ErrTimeout) {
+		t.Fatalf("expected timeout, got %v", err)
+	}
+	stats := executor.OIDStats()
+	if len(stats) != 1 || stats[0].Timeouts != 1 || stats[0].TimeoutRate != 100 {
+		t.Fatalf("unexpected OID stats: %+v", stats)
+	}
+}
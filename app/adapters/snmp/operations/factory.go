@@ -0,0 +1,46 @@
+package operations
+
+import (
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+)
+
+// OperationFactory SNMP操作工厂，按任务序号在OID间轮转
+type OperationFactory struct {
+	testCase string
+	oids     []string
+}
+
+// NewOperationFactory 创建SNMP操作工厂
+func NewOperationFactory(testCase string, oids []string) *OperationFactory {
+	return &OperationFactory{
+		testCase: testCase,
+		oids:     oids,
+	}
+}
+
+// CreateOperation 创建操作，Key为GET的OID或WALK的根OID
+func (f *OperationFactory) CreateOperation(jobID int, config execution.BenchmarkConfig) interfaces.Operation {
+	oid := f.oids[jobID%len(f.oids)]
+	return interfaces.Operation{
+		Type: f.testCase,
+		Key:  oid,
+		Params: map[string]interface{}{
+			"job_id": jobID,
+		},
+		Metadata: map[string]string{
+			"operation_type": f.testCase,
+			"oid":            oid,
+		},
+	}
+}
+
+// GetOperationType 获取操作类型
+func (f *OperationFactory) GetOperationType() string {
+	return f.testCase
+}
+
+// GetSupportedOperations 获取支持的操作类型
+func (f *OperationFactory) GetSupportedOperations() []string {
+	return []string{"get", "walk"}
+}
@@ -0,0 +1,102 @@
+package operations
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"abc-runner/app/adapters/snmp/connection"
+	"abc-runner/app/core/metrics"
+)
+
+// OIDStats 单个OID的轮询统计
+// walk用例按根OID统计，延迟为整棵子树遍历的耗时
+type OIDStats struct {
+	OID         string                 `json:"oid"`
+	Requests    int64                  `json:"requests"`
+	Timeouts    int64                  `json:"timeouts"`
+	Errors      int64                  `json:"errors"` // 超时以外的失败，如noSuchObject、error-status
+	TimeoutRate float64                `json:"timeout_rate"`
+	ErrorRate   float64                `json:"error_rate"`
+	VarBinds    int64                  `json:"varbinds"` // 成功返回的变量绑定数
+	Latency     metrics.LatencyMetrics `json:"latency"`  // 成功请求的延迟
+}
+
+// oidCounter 单个OID的计数器
+type oidCounter struct {
+	requests int64
+	timeouts int64
+	errors   int64
+	varBinds int64
+	latency  *metrics.LatencyTracker
+}
+
+// OIDTracker 按OID统计延迟与超时率
+type OIDTracker struct {
+	mutex    sync.Mutex
+	counters map[string]*oidCounter
+	config   metrics.LatencyConfig
+}
+
+// NewOIDTracker 创建OID统计器
+func NewOIDTracker() *OIDTracker {
+	return &OIDTracker{
+		counters: make(map[string]*oidCounter),
+		config: metrics.LatencyConfig{
+			SignificantDigits: metrics.DefaultHdrSignificantDigits,
+			SamplingRate:      1.0,
+		},
+	}
+}
+
+// Record 记录一次请求结果
+func (t *OIDTracker) Record(oid string, varBinds int, latency time.Duration, err error) {
+	t.mutex.Lock()
+	counter, ok := t.counters[oid]
+	if !ok {
+		counter = &oidCounter{latency: metrics.NewLatencyTracker(t.config)}
+		t.counters[oid] = counter
+	}
+	counter.requests++
+	switch {
+	case err == nil:
+		counter.varBinds += int64(varBinds)
+	case errors.Is(err, connection.ErrTimeout):
+		counter.timeouts++
+	default:
+		counter.errors++
+	}
+	t.mutex.Unlock()
+
+	if err == nil {
+		counter.latency.Record(latency)
+	}
+}
+
+// Stats 获取按OID排序的统计结果
+func (t *OIDTracker) Stats() []OIDStats {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	stats := make([]OIDStats, 0, len(t.counters))
+	for oid, counter := range t.counters {
+		entry := OIDStats{
+			OID:      oid,
+			Requests: counter.requests,
+			Timeouts: counter.timeouts,
+			Errors:   counter.errors,
+			VarBinds: counter.varBinds,
+			Latency:  counter.latency.GetMetrics(),
+		}
+		if counter.requests > 0 {
+			entry.TimeoutRate = float64(counter.timeouts) / float64(counter.requests) * 100
+			entry.ErrorRate = float64(counter.errors) / float64(counter.requests) * 100
+		}
+		stats = append(stats, entry)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return connection.CompareOID(stats[i].OID, stats[j].OID) < 0
+	})
+	return stats
+}
@@ -0,0 +1,136 @@
+package syslog
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"abc-runner/app/adapters/syslog/config"
+	"abc-runner/app/adapters/syslog/connection"
+	"abc-runner/app/adapters/syslog/operations"
+	"abc-runner/app/core/interfaces"
+)
+
+// SyslogAdapter Syslog协议适配器 - 遵循统一架构模式
+// 职责：连接管理、状态维护、健康检查
+type SyslogAdapter struct {
+	config           *config.SyslogConfig
+	sender           *connection.Sender
+	syslogOperations *operations.SyslogExecutor
+	metricsCollector interfaces.DefaultMetricsCollector
+	mu               sync.RWMutex
+	isConnected      bool
+
+	// 统计信息
+	totalOperations  int64
+	failedOperations int64
+}
+
+// NewSyslogAdapter 创建Syslog适配器
+func NewSyslogAdapter(metricsCollector interfaces.DefaultMetricsCollector) *SyslogAdapter {
+	return &SyslogAdapter{
+		metricsCollector: metricsCollector,
+	}
+}
+
+// Connect 建立与syslog服务端的连接
+func (s *SyslogAdapter) Connect(ctx context.Context, cfg interfaces.Config) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	syslogConfig, ok := cfg.(*config.SyslogConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type for Syslog adapter: expected *config.SyslogConfig, got %T", cfg)
+	}
+
+	if err := syslogConfig.Validate(); err != nil {
+		return fmt.Errorf("config validation failed: %w", err)
+	}
+	s.config = syslogConfig
+
+	sender, err := connection.NewSender(ctx, syslogConfig)
+	if err != nil {
+		return err
+	}
+	s.sender = sender
+	s.syslogOperations = operations.NewSyslogExecutor(sender,
+		operations.NewMessageBuilder(syslogConfig.SyslogSpecific), syslogConfig.BenchMark.Rate)
+
+	s.isConnected = true
+	return nil
+}
+
+// Execute 执行操作 - 使用执行器处理
+func (s *SyslogAdapter) Execute(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	if !s.isConnected {
+		return nil, fmt.Errorf("adapter not connected")
+	}
+
+	atomic.AddInt64(&s.totalOperations, 1)
+	result, err := s.syslogOperations.ExecuteOperation(ctx, operation)
+	if err != nil {
+		atomic.AddInt64(&s.failedOperations, 1)
+	}
+	return result, err
+}
+
+// Close 关闭连接
+func (s *SyslogAdapter) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sender != nil {
+		s.sender.Close()
+		s.sender = nil
+	}
+	s.isConnected = false
+	return nil
+}
+
+// GetProtocolMetrics 获取协议特定指标
+func (s *SyslogAdapter) GetProtocolMetrics() map[string]interface{} {
+	metrics := map[string]interface{}{
+		"protocol":          "syslog",
+		"total_operations":  atomic.LoadInt64(&s.totalOperations),
+		"failed_operations": atomic.LoadInt64(&s.failedOperations),
+	}
+
+	if s.config != nil {
+		metrics["transport"] = s.config.Connection.Transport
+		metrics["framing"] = s.config.SyslogSpecific.Framing
+	}
+	if stats := s.GetSendStats(); stats != nil {
+		metrics["send_stats"] = *stats
+	}
+
+	return metrics
+}
+
+// GetSendStats 获取发送统计，未连接时返回nil
+func (s *SyslogAdapter) GetSendStats() *operations.SendStats {
+	if s.syslogOperations == nil {
+		return nil
+	}
+	stats := s.syslogOperations.SendStats()
+	return &stats
+}
+
+// HealthCheck 健康检查
+// TCP/TLS在Connect时已完成建连；UDP无连接，写入失败（如ICMP端口不可达）只能在发送时发现
+func (s *SyslogAdapter) HealthCheck(ctx context.Context) error {
+	if !s.isConnected {
+		return fmt.Errorf("adapter not connected")
+	}
+	return nil
+}
+
+// GetProtocolName 获取协议名称
+func (s *SyslogAdapter) GetProtocolName() string {
+	return "syslog"
+}
+
+// GetMetricsCollector 获取指标收集器
+func (s *SyslogAdapter) GetMetricsCollector() interfaces.DefaultMetricsCollector {
+	return s.metricsCollector
+}
@@ -0,0 +1,252 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+)
+
+// MaxUDPMessageSize 单个UDP报文可承载的最大syslog消息长度（IPv4）
+const MaxUDPMessageSize = 65507
+
+// SyslogConfig Syslog协议配置
+type SyslogConfig struct {
+	Protocol       string               `yaml:"protocol" json:"protocol"`
+	Connection     ConnectionConfig     `yaml:"connection" json:"connection"`
+	BenchMark      BenchmarkConfig      `yaml:"benchmark" json:"benchmark"`
+	SyslogSpecific SyslogSpecificConfig `yaml:"syslog_specific" json:"syslog_specific"`
+}
+
+// ConnectionConfig Syslog连接配置
+type ConnectionConfig struct {
+	Address   string        `yaml:"address" json:"address"`
+	Port      int           `yaml:"port" json:"port"`
+	Transport string        `yaml:"transport" json:"transport"` // "udp"、"tcp" 或 "tls"
+	Timeout   time.Duration `yaml:"timeout" json:"timeout"`     // 建连与单次写入超时
+	TLS       TLSConfig     `yaml:"tls" json:"tls"`
+}
+
+// TLSConfig TLS配置
+type TLSConfig struct {
+	CAFile             string `yaml:"ca_file" json:"ca_file"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify" json:"insecure_skip_verify"`
+	ServerName         string `yaml:"server_name" json:"server_name"`
+}
+
+// BenchmarkConfig Syslog基准测试配置
+type BenchmarkConfig struct {
+	Total     int           `yaml:"total" json:"total"`
+	Parallels int           `yaml:"parallels" json:"parallels"` // 并发发送者数，每个发送者独占一个连接
+	TestCase  string        `yaml:"test_case" json:"test_case"` // "send"
+	Duration  time.Duration `yaml:"duration" json:"duration"`
+	Rate      int           `yaml:"rate" json:"rate"` // 每秒发送的消息数上限，0表示不限速
+}
+
+// SyslogSpecificConfig Syslog特定配置
+type SyslogSpecificConfig struct {
+	Facility       int    `yaml:"facility" json:"facility"`                 // 0-23，默认16（local0）
+	Severity       int    `yaml:"severity" json:"severity"`                 // 0-7，默认6（informational）
+	Hostname       string `yaml:"hostname" json:"hostname"`                 // 为空时使用本机主机名
+	AppName        string `yaml:"app_name" json:"app_name"`                 // APP-NAME
+	MsgID          string `yaml:"msg_id" json:"msg_id"`                     // MSGID，为空时为"-"
+	MessageSize    int    `yaml:"message_size" json:"message_size"`         // 消息长度（字节，不含帧头）
+	MaxMessageSize int    `yaml:"max_message_size" json:"max_message_size"` // 大于message_size时在两者间随机取长度
+	Framing        string `yaml:"framing" json:"framing"`                   // TCP/TLS分帧："octet-counting" 或 "lf"
+	StructuredData bool   `yaml:"structured_data" json:"structured_data"`   // 是否携带带序号的结构化数据
+}
+
+// NewDefaultSyslogConfig 创建默认Syslog配置
+func NewDefaultSyslogConfig() *SyslogConfig {
+	return &SyslogConfig{
+		Protocol: "syslog",
+		Connection: ConnectionConfig{
+			Address:   "localhost",
+			Port:      514,
+			Transport: "udp",
+			Timeout:   5 * time.Second,
+		},
+		BenchMark: BenchmarkConfig{
+			Total:     10000,
+			Parallels: 10,
+			TestCase:  "send",
+		},
+		SyslogSpecific: SyslogSpecificConfig{
+			Facility:    16,
+			Severity:    6,
+			AppName:     "abc-runner",
+			MessageSize: 256,
+			Framing:     "octet-counting",
+		},
+	}
+}
+
+// IsStream 是否为面向流的传输（TCP/TLS）
+func (c *SyslogConfig) IsStream() bool {
+	return c.Connection.Transport != "udp"
+}
+
+// GetProtocol 实现Config接口
+func (c *SyslogConfig) GetProtocol() string {
+	return c.Protocol
+}
+
+// GetConnection 实现Config接口
+func (c *SyslogConfig) GetConnection() interfaces.ConnectionConfig {
+	return &c.Connection
+}
+
+// GetBenchmark 实现Config接口
+func (c *SyslogConfig) GetBenchmark() interfaces.BenchmarkConfig {
+	return &c.BenchMark
+}
+
+// Validate 实现Config接口
+func (c *SyslogConfig) Validate() error {
+	if c.Connection.Address == "" {
+		return fmt.Errorf("connection address cannot be empty")
+	}
+	if c.Connection.Port <= 0 || c.Connection.Port > 65535 {
+		return fmt.Errorf("invalid port number: %d", c.Connection.Port)
+	}
+	switch c.Connection.Transport {
+	case "udp", "tcp", "tls":
+	default:
+		return fmt.Errorf("invalid transport: %s, valid options: udp, tcp, tls", c.Connection.Transport)
+	}
+	if c.Connection.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+
+	if c.BenchMark.Total <= 0 {
+		return fmt.Errorf("total operations must be greater than 0")
+	}
+	if c.BenchMark.Parallels <= 0 {
+		return fmt.Errorf("parallel connections must be greater than 0")
+	}
+	if c.BenchMark.TestCase != "send" {
+		return fmt.Errorf("invalid test case: %s, valid options: send", c.BenchMark.TestCase)
+	}
+	if c.BenchMark.Rate < 0 {
+		return fmt.Errorf("rate cannot be negative")
+	}
+
+	specific := c.SyslogSpecific
+	if specific.Facility < 0 || specific.Facility > 23 {
+		return fmt.Errorf("invalid facility: %d, must be between 0 and 23", specific.Facility)
+	}
+	if specific.Severity < 0 || specific.Severity > 7 {
+		return fmt.Errorf("invalid severity: %d, must be between 0 and 7", specific.Severity)
+	}
+	if specific.MessageSize <= 0 {
+		return fmt.Errorf("message size must be greater than 0")
+	}
+	if specific.MaxMessageSize != 0 && specific.MaxMessageSize < specific.MessageSize {
+		return fmt.Errorf("max message size %d is smaller than message size %d", specific.MaxMessageSize, specific.MessageSize)
+	}
+	if !c.IsStream() && c.maxSize() > MaxUDPMessageSize {
+		return fmt.Errorf("message size %d exceeds the UDP limit of %d bytes", c.maxSize(), MaxUDPMessageSize)
+	}
+	if specific.Framing != "octet-counting" && specific.Framing != "lf" {
+		return fmt.Errorf("invalid framing: %s, valid options: octet-counting, lf", specific.Framing)
+	}
+
+	return nil
+}
+
+// maxSize 最大消息长度
+func (c *SyslogConfig) maxSize() int {
+	if c.SyslogSpecific.MaxMessageSize > c.SyslogSpecific.MessageSize {
+		return c.SyslogSpecific.MaxMessageSize
+	}
+	return c.SyslogSpecific.MessageSize
+}
+
+// Clone 实现Config接口
+func (c *SyslogConfig) Clone() interfaces.Config {
+	clone := *c
+	return &clone
+}
+
+// ConnectionConfig接口实现
+
+// GetAddresses 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetAddresses() []string {
+	return []string{fmt.Sprintf("%s:%d", c.Address, c.Port)}
+}
+
+// GetCredentials 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetCredentials() map[string]string {
+	return map[string]string{}
+}
+
+// GetPoolConfig 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetPoolConfig() interfaces.PoolConfig {
+	return &EmptyPoolConfig{}
+}
+
+// GetTimeout 实现ConnectionConfig接口
+func (c *ConnectionConfig) GetTimeout() time.Duration {
+	return c.Timeout
+}
+
+// EmptyPoolConfig 空的连接池配置（连接数量由并发数决定）
+type EmptyPoolConfig struct{}
+
+func (p *EmptyPoolConfig) GetPoolSize() int                    { return 0 }
+func (p *EmptyPoolConfig) GetMinIdle() int                     { return 0 }
+func (p *EmptyPoolConfig) GetMaxIdle() int                     { return 0 }
+func (p *EmptyPoolConfig) GetIdleTimeout() time.Duration       { return 0 }
+func (p *EmptyPoolConfig) GetConnectionTimeout() time.Duration { return 0 }
+
+// BenchmarkConfig接口实现
+
+// GetTotal 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTotal() int {
+	return b.Total
+}
+
+// GetParallels 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetParallels() int {
+	return b.Parallels
+}
+
+// GetDataSize 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetDataSize() int {
+	return 0
+}
+
+// GetTTL 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTTL() time.Duration {
+	return 0
+}
+
+// GetReadPercent 实现BenchmarkConfig接口，syslog发送均为写操作
+func (b *BenchmarkConfig) GetReadPercent() int {
+	return 0
+}
+
+// GetRandomKeys 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetRandomKeys() int {
+	return 0
+}
+
+// GetTestCase 实现BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTestCase() string {
+	return b.TestCase
+}
+
+// GetDuration 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetDuration() time.Duration {
+	return b.Duration
+}
+
+// GetTimeout 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetTimeout() time.Duration {
+	return 0
+}
+
+// GetRampUp 实现execution.BenchmarkConfig接口
+func (b *BenchmarkConfig) GetRampUp() time.Duration {
+	return 0
+}
@@ -0,0 +1,198 @@
+package connection
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"abc-runner/app/adapters/syslog/config"
+)
+
+// Sender Syslog发送器
+// 维护与并发数相同的连接，每次发送独占一个连接；TCP/TLS写入失败时重连并重试一次
+type Sender struct {
+	address   string
+	transport string
+	framing   string
+	timeout   time.Duration
+	tlsConfig *tls.Config
+
+	conns      chan net.Conn
+	mutex      sync.Mutex
+	all        map[net.Conn]struct{}
+	reconnects atomic.Int64
+	closeOnce  sync.Once
+}
+
+// NewSender 创建发送器并建立全部连接
+func NewSender(ctx context.Context, cfg *config.SyslogConfig) (*Sender, error) {
+	sender := &Sender{
+		address:   net.JoinHostPort(cfg.Connection.Address, strconv.Itoa(cfg.Connection.Port)),
+		transport: cfg.Connection.Transport,
+		framing:   cfg.SyslogSpecific.Framing,
+		timeout:   cfg.Connection.Timeout,
+		conns:     make(chan net.Conn, cfg.BenchMark.Parallels),
+		all:       make(map[net.Conn]struct{}),
+	}
+
+	if sender.transport == "tls" {
+		tlsConfig, err := buildTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		sender.tlsConfig = tlsConfig
+	}
+
+	for i := 0; i < cfg.BenchMark.Parallels; i++ {
+		conn, err := sender.dial(ctx)
+		if err != nil {
+			sender.Close()
+			return nil, err
+		}
+		sender.conns <- conn
+	}
+	return sender, nil
+}
+
+// buildTLSConfig 构建TLS客户端配置
+func buildTLSConfig(cfg *config.SyslogConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         cfg.Connection.TLS.ServerName,
+		InsecureSkipVerify: cfg.Connection.TLS.InsecureSkipVerify,
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = cfg.Connection.Address
+	}
+	if cfg.Connection.TLS.CAFile != "" {
+		pem, err := os.ReadFile(cfg.Connection.TLS.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", cfg.Connection.TLS.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
+
+// dial 建立单个连接
+func (s *Sender) dial(ctx context.Context) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: s.timeout}
+	var conn net.Conn
+	var err error
+	switch s.transport {
+	case "tls":
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: s.tlsConfig}
+		conn, err = tlsDialer.DialContext(ctx, "tcp", s.address)
+	default:
+		conn, err = dialer.DialContext(ctx, s.transport, s.address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to syslog server %s over %s: %w", s.address, s.transport, err)
+	}
+
+	s.mutex.Lock()
+	s.all[conn] = struct{}{}
+	s.mutex.Unlock()
+	return conn, nil
+}
+
+// discard 关闭并移除失效连接
+func (s *Sender) discard(conn net.Conn) {
+	s.mutex.Lock()
+	delete(s.all, conn)
+	s.mutex.Unlock()
+	conn.Close()
+}
+
+// Address 目标地址
+func (s *Sender) Address() string {
+	return s.address
+}
+
+// Reconnects 写入失败后的重连次数
+func (s *Sender) Reconnects() int64 {
+	return s.reconnects.Load()
+}
+
+// Frame 按传输方式为消息分帧
+// UDP每个报文一条消息；TCP/TLS按RFC6587使用octet-counting（"LEN SP MSG"）或以LF结尾
+func (s *Sender) Frame(message []byte) []byte {
+	if s.transport == "udp" {
+		return message
+	}
+	if s.framing == "lf" {
+		return append(message, '\n')
+	}
+	frame := make([]byte, 0, len(message)+8)
+	frame = strconv.AppendInt(frame, int64(len(message)), 10)
+	frame = append(frame, ' ')
+	return append(frame, message...)
+}
+
+// Send 发送一条已分帧的消息，返回写入的字节数
+func (s *Sender) Send(ctx context.Context, frame []byte) (int, error) {
+	var conn net.Conn
+	select {
+	case conn = <-s.conns:
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+
+	n, err := s.write(conn, frame)
+	if err != nil && s.transport != "udp" {
+		// 连接被对端关闭等情况下重连并重试一次，部分写入的帧已损坏，不再续写
+		s.discard(conn)
+		var dialErr error
+		conn, dialErr = s.dial(ctx)
+		if dialErr != nil {
+			// 放回一个关闭的占位连接会导致后续写入失败后再次重连
+			s.conns <- &closedConn{}
+			return n, fmt.Errorf("write failed: %v; reconnect failed: %w", err, dialErr)
+		}
+		s.reconnects.Add(1)
+		n, err = s.write(conn, frame)
+	}
+	s.conns <- conn
+	if err != nil {
+		return n, fmt.Errorf("failed to send syslog message: %w", err)
+	}
+	return n, nil
+}
+
+// write 带超时写入完整的帧
+func (s *Sender) write(conn net.Conn, frame []byte) (int, error) {
+	conn.SetWriteDeadline(time.Now().Add(s.timeout))
+	return conn.Write(frame)
+}
+
+// Close 关闭所有连接
+func (s *Sender) Close() error {
+	s.closeOnce.Do(func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		for conn := range s.all {
+			conn.Close()
+		}
+		s.all = make(map[net.Conn]struct{})
+	})
+	return nil
+}
+
+// closedConn 重连失败后的占位连接，写入总是失败以触发下一次重连
+type closedConn struct {
+	net.Conn
+}
+
+func (c *closedConn) Write([]byte) (int, error)        { return 0, net.ErrClosed }
+func (c *closedConn) SetWriteDeadline(time.Time) error { return nil }
+func (c *closedConn) Close() error                     { return nil }
@@ -0,0 +1,125 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"abc-runner/app/adapters/syslog/connection"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/utils"
+)
+
+// SendStats 发送统计
+type SendStats struct {
+	Messages   int64   `json:"messages"`
+	Bytes      int64   `json:"bytes"` // 写入的字节数（含分帧开销）
+	MinSize    int64   `json:"min_size"`
+	MaxSize    int64   `json:"max_size"`
+	AvgSize    float64 `json:"avg_size"`
+	Reconnects int64   `json:"reconnects"`
+}
+
+// SyslogExecutor Syslog操作执行器
+type SyslogExecutor struct {
+	sender  *connection.Sender
+	builder *MessageBuilder
+	pacer   *utils.Pacer
+
+	sequence atomic.Int64
+	messages atomic.Int64
+	bytes    atomic.Int64
+
+	mutex   sync.Mutex
+	random  *rand.Rand
+	minSize int64
+	maxSize int64
+}
+
+// NewSyslogExecutor 创建Syslog操作执行器，rate大于0时限制每秒发送的消息数
+func NewSyslogExecutor(sender *connection.Sender, builder *MessageBuilder, rate int) *SyslogExecutor {
+	return &SyslogExecutor{
+		sender:  sender,
+		builder: builder,
+		pacer:   utils.NewRatePacer(float64(rate)),
+		random:  rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// ExecuteOperation 执行Syslog操作
+func (e *SyslogExecutor) ExecuteOperation(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	if operation.Type != "send" {
+		return nil, fmt.Errorf("unsupported operation type: %s", operation.Type)
+	}
+	if err := e.pacer.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	e.mutex.Lock()
+	size := e.builder.Size(e.random)
+	e.mutex.Unlock()
+
+	seq := e.sequence.Add(1)
+	startTime := time.Now()
+	frame := e.sender.Frame(e.builder.Build(seq, size, startTime))
+	written, err := e.sender.Send(ctx, frame)
+	duration := time.Since(startTime)
+
+	if err == nil {
+		e.record(int64(written))
+	}
+
+	result := &interfaces.OperationResult{
+		Success:      err == nil,
+		Duration:     duration,
+		IsRead:       false,
+		Error:        err,
+		Value:        written,
+		BytesWritten: int64(written),
+		Metadata: map[string]interface{}{
+			"protocol":       "syslog",
+			"operation_type": operation.Type,
+			"sequence":       seq,
+			"bytes":          written,
+		},
+	}
+	for k, v := range operation.Metadata {
+		result.Metadata[k] = v
+	}
+	return result, err
+}
+
+// record 记录一次成功发送
+func (e *SyslogExecutor) record(written int64) {
+	e.messages.Add(1)
+	e.bytes.Add(written)
+
+	e.mutex.Lock()
+	if e.minSize == 0 || written < e.minSize {
+		e.minSize = written
+	}
+	if written > e.maxSize {
+		e.maxSize = written
+	}
+	e.mutex.Unlock()
+}
+
+// SendStats 获取发送统计
+func (e *SyslogExecutor) SendStats() SendStats {
+	stats := SendStats{
+		Messages:   e.messages.Load(),
+		Bytes:      e.bytes.Load(),
+		Reconnects: e.sender.Reconnects(),
+	}
+	e.mutex.Lock()
+	stats.MinSize = e.minSize
+	stats.MaxSize = e.maxSize
+	e.mutex.Unlock()
+	if stats.Messages > 0 {
+		stats.AvgSize = float64(stats.Bytes) / float64(stats.Messages)
+	}
+	return stats
+}
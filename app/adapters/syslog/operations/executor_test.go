@@ -0,0 +1,149 @@
+package operations
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"abc-runner/app/adapters/syslog/config"
+	"abc-runner/app/adapters/syslog/connection"
+	"abc-runner/app/core/interfaces"
+)
+
+func testTime() time.Time {
+	return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+}
+
+func TestMessageBuilderSize(t *testing.T) {
+	cfg := config.NewDefaultSyslogConfig().SyslogSpecific
+	cfg.Hostname = "host1"
+	cfg.MessageSize = 300
+	builder := NewMessageBuilder(cfg)
+
+	message := string(builder.Build(42, 300, testTime()))
+	if len(message) != 300 {
+		t.Fatalf("message length = %d, want 300", len(message))
+	}
+	if !strings.HasPrefix(message, "<134>1 2026-01-02T03:04:05.000000Z host1 abc-runner ") ||
+		!strings.Contains(message, " - - seq=42 ") {
+		t.Fatalf("unexpected message: %q", message)
+	}
+
+	// 头部已超过目标长度时不截断
+	if short := string(builder.Build(7, 10, testTime())); !strings.HasSuffix(short, " seq=7") {
+		t.Fatalf("unexpected short message: %q", short)
+	}
+}
+
+func TestSyslogExecutorTCP(t *testing.T) {
+	for _, framing := range []string{"octet-counting", "lf"} {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("failed to listen: %v", err)
+		}
+		received := make(chan string, 10)
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			reader := bufio.NewReader(conn)
+			for {
+				message, err := readFrame(reader, framing)
+				if err != nil {
+					return
+				}
+				received <- message
+			}
+		}()
+
+		cfg := config.NewDefaultSyslogConfig()
+		cfg.Connection.Transport = "tcp"
+		cfg.SyslogSpecific.Framing = framing
+		cfg.SyslogSpecific.MessageSize = 100
+		cfg.SyslogSpecific.MaxMessageSize = 200
+		cfg.BenchMark.Parallels = 1
+		executor := newTestExecutor(t, cfg, listener.Addr())
+
+		for i := 0; i < 3; i++ {
+			if _, err := executor.ExecuteOperation(context.Background(), interfaces.Operation{Type: "send"}); err != nil {
+				t.Fatalf("%s: send failed: %v", framing, err)
+			}
+		}
+		for i := 1; i <= 3; i++ {
+			message := <-received
+			if len(message) < 100 || len(message) > 200 || !strings.Contains(message, " seq="+strconv.Itoa(i)+" ") {
+				t.Fatalf("%s: unexpected message %d: %q", framing, i, message)
+			}
+		}
+
+		stats := executor.SendStats()
+		if stats.Messages != 3 || stats.MinSize < 100 {
+			t.Fatalf("%s: unexpected stats: %+v", framing, stats)
+		}
+		listener.Close()
+	}
+}
+
+func TestSyslogExecutorUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer conn.Close()
+
+	cfg := config.NewDefaultSyslogConfig()
+	cfg.BenchMark.Parallels = 2
+	executor := newTestExecutor(t, cfg, conn.LocalAddr())
+
+	if _, err := executor.ExecuteOperation(context.Background(), interfaces.Operation{Type: "send"}); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	buffer := make([]byte, 65535)
+	n, _, err := conn.ReadFrom(buffer)
+	if err != nil || n != 256 {
+		t.Fatalf("received %d bytes (err %v), want 256", n, err)
+	}
+}
+
+func newTestExecutor(t *testing.T, cfg *config.SyslogConfig, addr net.Addr) *SyslogExecutor {
+	t.Helper()
+	host, port, _ := net.SplitHostPort(addr.String())
+	cfg.Connection.Address = host
+	cfg.Connection.Port, _ = strconv.Atoi(port)
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("invalid config: %v", err)
+	}
+
+	sender, err := connection.NewSender(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("failed to create sender: %v", err)
+	}
+	t.Cleanup(func() { sender.Close() })
+	return NewSyslogExecutor(sender, NewMessageBuilder(cfg.SyslogSpecific), 0)
+}
+
+// readFrame 按分帧方式读取一条消息
+func readFrame(reader *bufio.Reader, framing string) (string, error) {
+	if framing == "lf" {
+		line, err := reader.ReadString('\n')
+		return strings.TrimSuffix(line, "\n"), err
+	}
+	prefix, err := reader.ReadString(' ')
+	if err != nil {
+		return "", err
+	}
+	length, err := strconv.Atoi(strings.TrimSuffix(prefix, " "))
+	if err != nil {
+		return "", err
+	}
+	message := make([]byte, length)
+	_, err = io.ReadFull(reader, message)
+	return string(message), err
+}
@@ -0,0 +1,39 @@
+package operations
+
+import (
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+)
+
+// OperationFactory Syslog操作工厂
+type OperationFactory struct {
+	testCase string
+}
+
+// NewOperationFactory 创建Syslog操作工厂
+func NewOperationFactory(testCase string) *OperationFactory {
+	return &OperationFactory{testCase: testCase}
+}
+
+// CreateOperation 创建操作，消息内容由执行器在发送时生成
+func (f *OperationFactory) CreateOperation(jobID int, config execution.BenchmarkConfig) interfaces.Operation {
+	return interfaces.Operation{
+		Type: f.testCase,
+		Params: map[string]interface{}{
+			"job_id": jobID,
+		},
+		Metadata: map[string]string{
+			"operation_type": f.testCase,
+		},
+	}
+}
+
+// GetOperationType 获取操作类型
+func (f *OperationFactory) GetOperationType() string {
+	return f.testCase
+}
+
+// GetSupportedOperations 获取支持的操作类型
+func (f *OperationFactory) GetSupportedOperations() []string {
+	return []string{"send"}
+}
@@ -0,0 +1,104 @@
+package operations
+
+import (
+	"math/rand"
+	"os"
+	"strconv"
+	"time"
+
+	"abc-runner/app/adapters/syslog/config"
+)
+
+// filler 消息体填充字符
+const filler = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// MessageBuilder RFC5424消息构建器
+// 格式：<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID SD MSG，
+// MSG以"seq=N"开头并填充到目标长度；头部已超过目标长度时只保留序号
+type MessageBuilder struct {
+	priority       int
+	hostname       string
+	appName        string
+	procID         string
+	msgID          string
+	structuredData bool
+	minSize        int
+	maxSize        int
+}
+
+// NewMessageBuilder 创建消息构建器
+func NewMessageBuilder(cfg config.SyslogSpecificConfig) *MessageBuilder {
+	hostname := cfg.Hostname
+	if hostname == "" {
+		hostname, _ = os.Hostname()
+	}
+	maxSize := cfg.MaxMessageSize
+	if maxSize < cfg.MessageSize {
+		maxSize = cfg.MessageSize
+	}
+	return &MessageBuilder{
+		priority:       cfg.Facility*8 + cfg.Severity,
+		hostname:       headerField(hostname, 255),
+		appName:        headerField(cfg.AppName, 48),
+		procID:         strconv.Itoa(os.Getpid()),
+		msgID:          headerField(cfg.MsgID, 32),
+		structuredData: cfg.StructuredData,
+		minSize:        cfg.MessageSize,
+		maxSize:        maxSize,
+	}
+}
+
+// headerField 规范化头部字段：空值为NILVALUE，截断超长值
+func headerField(value string, maxLength int) string {
+	if value == "" {
+		return "-"
+	}
+	if len(value) > maxLength {
+		return value[:maxLength]
+	}
+	return value
+}
+
+// Size 在长度范围内随机选取消息长度，固定长度时直接返回
+func (b *MessageBuilder) Size(random *rand.Rand) int {
+	if b.maxSize == b.minSize {
+		return b.minSize
+	}
+	return b.minSize + random.Intn(b.maxSize-b.minSize+1)
+}
+
+// Build 构建序号为seq、长度为size的消息
+func (b *MessageBuilder) Build(seq int64, size int, now time.Time) []byte {
+	message := make([]byte, 0, size+16)
+	message = append(message, '<')
+	message = strconv.AppendInt(message, int64(b.priority), 10)
+	message = append(message, ">1 "...)
+	message = now.UTC().AppendFormat(message, "2006-01-02T15:04:05.000000Z")
+	message = append(message, ' ')
+	message = append(message, b.hostname...)
+	message = append(message, ' ')
+	message = append(message, b.appName...)
+	message = append(message, ' ')
+	message = append(message, b.procID...)
+	message = append(message, ' ')
+	message = append(message, b.msgID...)
+	message = append(message, ' ')
+	if b.structuredData {
+		// 32473为RFC5612保留给文档示例的企业号
+		message = append(message, `[abc@32473 seq="`...)
+		message = strconv.AppendInt(message, seq, 10)
+		message = append(message, `"]`...)
+	} else {
+		message = append(message, '-')
+	}
+	message = append(message, " seq="...)
+	message = strconv.AppendInt(message, seq, 10)
+
+	if len(message) < size {
+		message = append(message, ' ')
+		for i := 0; len(message) < size; i++ {
+			message = append(message, filler[i%len(filler)])
+		}
+	}
+	return message
+}
@@ -12,6 +12,8 @@ import (
 	"abc-runner/app/adapters/tcp/operations"
 
 	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/core/transport"
 )
 
 // TCPAdapter TCP协议适配器 - 遵循统一架构模式
@@ -471,6 +473,7 @@ func (t *TCPAdapter) GetProtocolMetrics() map[string]interface{} {
 		metrics["buffer_size"] = t.config.TCPSpecific.BufferSize
 		metrics["linger_timeout"] = t.config.TCPSpecific.LingerTimeout
 		metrics["reuse_address"] = t.config.TCPSpecific.ReuseAddress
+		metrics["transport"] = t.transportName()
 
 		// 连接配置指标
 		metrics["keep_alive"] = t.config.Connection.KeepAlive
@@ -488,6 +491,37 @@ func (t *TCPAdapter) GetProtocolMetrics() map[string]interface{} {
 	return metrics
 }
 
+// transportName 获取使用的传输类型
+func (t *TCPAdapter) transportName() string {
+	if t.config.TCPSpecific.Transport == "" {
+		return transport.Net
+	}
+	return t.config.TCPSpecific.Transport
+}
+
+// TransportStats 获取io_uring传输统计，未启用时返回false
+func (t *TCPAdapter) TransportStats() (transport.Stats, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.connectionPool == nil {
+		return transport.Stats{}, false
+	}
+	return t.connectionPool.TransportStats()
+}
+
+// LatencyPhases 获取连接池新建连接的建连耗时，未连接或没有新建连接时返回false
+func (t *TCPAdapter) LatencyPhases() ([]metrics.PhaseLatency, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.connectionPool == nil {
+		return nil, false
+	}
+	phases := t.connectionPool.LatencyPhases()
+	return phases, len(phases) > 0
+}
+
 // HealthCheck 健康检查
 func (t *TCPAdapter) HealthCheck(ctx context.Context) error {
 	if !t.isConnected {
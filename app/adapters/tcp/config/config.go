@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/transport"
 )
 
 // TCPConfig TCP协议配置
@@ -54,6 +55,7 @@ type TCPSpecificConfig struct {
 	BufferSize     int    `yaml:"buffer_size" json:"buffer_size"`         // 缓冲区大小
 	LingerTimeout  int    `yaml:"linger_timeout" json:"linger_timeout"`   // SO_LINGER超时
 	ReuseAddress   bool   `yaml:"reuse_address" json:"reuse_address"`     // SO_REUSEADDR
+	Transport      string `yaml:"transport" json:"transport"`             // "net", "io_uring"（实验性）
 }
 
 // NewDefaultTCPConfig 创建默认TCP配置
@@ -89,6 +91,7 @@ func NewDefaultTCPConfig() *TCPConfig {
 			BufferSize:     4096,
 			LingerTimeout:  -1,
 			ReuseAddress:   true,
+			Transport:      transport.Net,
 		},
 	}
 }
@@ -144,6 +147,10 @@ func (c *TCPConfig) Validate() error {
 			c.BenchMark.TestCase, strings.Join(validTestCases, ", "))
 	}
 
+	if err := transport.Validate(c.TCPSpecific.Transport); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -1,6 +1,7 @@
 package connection
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"sync"
@@ -8,6 +9,8 @@ import (
 	"time"
 
 	"abc-runner/app/adapters/tcp/config"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/core/transport"
 )
 
 // ConnectionPool TCP连接池
@@ -18,6 +21,8 @@ type ConnectionPool struct {
 	config      *config.TCPConfig
 	activeCount int64
 	address     string
+	ring        *transport.Ring       // 非nil时连接经io_uring收发
+	phases      *metrics.PhaseTracker // 建连耗时
 
 	// 性能统计
 	createdCount    int64 // 已创建连接数
@@ -35,6 +40,15 @@ func NewConnectionPool(cfg *config.TCPConfig) (*ConnectionPool, error) {
 		config:      cfg,
 		address:     address,
 		closed:      false,
+		phases:      metrics.NewPhaseTracker(),
+	}
+
+	if cfg.TCPSpecific.Transport == transport.IOURing {
+		ring, err := transport.NewRing(transport.DefaultRingEntries)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set up io_uring transport: %w", err)
+		}
+		pool.ring = ring
 	}
 
 	// 预创建最小空闲连接
@@ -64,7 +78,7 @@ func (p *ConnectionPool) createConnection() (net.Conn, error) {
 		KeepAlive: p.config.Connection.KeepAlivePeriod,
 	}
 
-	conn, err := dialer.Dial("tcp", p.address)
+	conn, err := p.phases.WrapDial(dialer.DialContext)(context.Background(), "tcp", p.address)
 	if err != nil {
 		return nil, fmt.Errorf("failed to dial %s: %w", p.address, err)
 	}
@@ -78,11 +92,22 @@ func (p *ConnectionPool) createConnection() (net.Conn, error) {
 		}
 	}
 
+	if p.ring != nil {
+		if conn, err = p.ring.Wrap(conn); err != nil {
+			return nil, err
+		}
+	}
+
 	atomic.AddInt64(&p.activeCount, 1)
 	atomic.AddInt64(&p.createdCount, 1)
 	return conn, nil
 }
 
+// LatencyPhases 连接池新建连接的建连耗时统计
+func (p *ConnectionPool) LatencyPhases() []metrics.PhaseLatency {
+	return p.phases.Breakdown()
+}
+
 // configureTCPConnection 配置TCP连接选项
 func (p *ConnectionPool) configureTCPConnection(conn *net.TCPConn) error {
 	// 设置NoDelay
@@ -235,6 +260,11 @@ func (p *ConnectionPool) Close() error {
 		}
 	}
 
+	// 借出未归还的连接在归还时关闭，其进行中的收发随io_uring实例关闭而失败
+	if p.ring != nil {
+		return p.ring.Close()
+	}
+
 	return nil
 }
 
@@ -255,9 +285,17 @@ func (p *ConnectionPool) AvailableConnections() int {
 	return len(p.connections)
 }
 
+// TransportStats 获取io_uring传输统计，未启用时返回false
+func (p *ConnectionPool) TransportStats() (transport.Stats, bool) {
+	if p.ring == nil {
+		return transport.Stats{}, false
+	}
+	return p.ring.Stats(), true
+}
+
 // Stats 获取连接池统计信息
 func (p *ConnectionPool) Stats() map[string]interface{} {
-	return map[string]interface{}{
+	stats := map[string]interface{}{
 		"active_connections":      p.ActiveConnections(),
 		"available_connections":   p.AvailableConnections(),
 		"pool_size":               p.config.Connection.Pool.PoolSize,
@@ -270,6 +308,10 @@ func (p *ConnectionPool) Stats() map[string]interface{} {
 		"get_timeouts":            atomic.LoadInt64(&p.getTimeouts),
 		"validation_success_rate": p.getValidationSuccessRate(),
 	}
+	if transportStats, ok := p.TransportStats(); ok {
+		stats["io_uring"] = transportStats.Map()
+	}
+	return stats
 }
 
 // getValidationSuccessRate 获取验证成功率
@@ -110,6 +110,7 @@ func (t *TCPExecutor) executeEchoTest(ctx context.Context, conn net.Conn, operat
 	result.Value = receivedData
 
 	// 记录详细指标
+	result.BytesWritten, result.BytesRead = int64(sentBytes), int64(n)
 	result.Metadata["sent_bytes"] = sentBytes
 	result.Metadata["received_bytes"] = n
 	result.Metadata["expected_bytes"] = len(testData)
@@ -144,6 +145,7 @@ func (t *TCPExecutor) executeSendOnly(ctx context.Context, conn net.Conn, operat
 	}
 
 	result.Value = sentBytes
+	result.BytesWritten = int64(sentBytes)
 	result.Metadata["sent_bytes"] = sentBytes
 	result.Metadata["data_size"] = len(testData)
 
@@ -171,6 +173,7 @@ func (t *TCPExecutor) executeReceiveOnly(ctx context.Context, conn net.Conn, ope
 
 	receivedData := buffer[:n]
 	result.Value = receivedData
+	result.BytesRead = int64(n)
 	result.Metadata["received_bytes"] = n
 	result.Metadata["buffer_size"] = bufferSize
 
@@ -209,6 +212,7 @@ func (t *TCPExecutor) executeBidirectional(ctx context.Context, conn net.Conn, o
 		"received_data": receivedData,
 	}
 
+	result.BytesWritten, result.BytesRead = int64(sentBytes), int64(n)
 	result.Metadata["sent_bytes"] = sentBytes
 	result.Metadata["received_bytes"] = n
 	result.Metadata["total_bytes"] = sentBytes + n
@@ -109,6 +109,7 @@ func (u *UDPExecutor) executePacketSend(ctx context.Context, operation interface
 	}
 
 	result.Value = n
+	result.BytesWritten = int64(n)
 	result.Metadata["sent_bytes"] = n
 	result.Metadata["packet_size"] = len(testData)
 	return nil
@@ -139,6 +140,7 @@ func (u *UDPExecutor) executePacketReceive(ctx context.Context, operation interf
 
 	receivedData := buffer[:n]
 	result.Value = receivedData
+	result.BytesRead = int64(n)
 	result.Metadata["received_bytes"] = n
 	result.Metadata["buffer_size"] = bufferSize
 	return nil
@@ -166,6 +166,9 @@ func (w *WebSocketAdapter) executeWebSocketOperation(ctx context.Context, operat
 	result.Success = opErr == nil
 	result.Error = opErr
 	result.Duration = time.Since(startTime)
+	if opErr == nil {
+		result.BytesWritten = sentPayload(operation)
+	}
 
 	// 添加操作特定元数据
 	for k, v := range operation.Metadata {
@@ -177,6 +180,22 @@ func (w *WebSocketAdapter) executeWebSocketOperation(ctx context.Context, operat
 	return result, opErr
 }
 
+// sentPayload 发送单条消息的操作写出的负载字节数，其他操作返回0
+func sentPayload(operation interfaces.Operation) int64 {
+	switch operation.Type {
+	case "send_text", "send_binary", "echo_test", "broadcast", "large_message":
+	default:
+		return 0
+	}
+	switch value := operation.Value.(type) {
+	case string:
+		return int64(len(value))
+	case []byte:
+		return int64(len(value))
+	}
+	return 0
+}
+
 // 具体操作实现方法
 
 // executeSendText 执行发送文本消息
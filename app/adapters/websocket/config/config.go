@@ -166,7 +166,7 @@ func (c *WebSocketConfig) Validate() error {
 	}
 
 	// 验证测试用例
-	validTestCases := []string{"message_exchange", "ping_pong", "broadcast", "large_message"}
+	validTestCases := []string{"message_exchange", "ping_pong", "broadcast", "large_message", "reconnect_storm"}
 	valid := false
 	for _, testCase := range validTestCases {
 		if c.BenchMark.TestCase == testCase {
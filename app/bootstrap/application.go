@@ -10,6 +10,9 @@ import (
 
 	"abc-runner/app/bootstrap/discovery"
 	"abc-runner/app/bootstrap/registry"
+	"abc-runner/app/commands"
+	"abc-runner/app/core/execution"
+	"abc-runner/app/reporting"
 )
 
 // Application 应用启动器
@@ -138,8 +141,25 @@ func (app *Application) handleCommand() error {
 	// 处理全局标志
 	help := flag.Bool("help", false, "show help information")
 	version := flag.Bool("version", false, "show version information")
+	resultFile := flag.String("result-file", reporting.DefaultResultFile, "write a machine-readable run result to this file (empty to disable)")
+	historyDir := flag.String("history-dir", reporting.DefaultHistoryDir, "record every run in this local history directory (empty to disable)")
+	notifyURL := flag.String("notify", "", "post a run summary to this webhook URL (Slack, Teams or generic JSON) when the run ends")
+	notifyFormat := flag.String("notify-format", "", "notification format: slack, teams or generic (default: detected from the URL)")
+	notifyOn := flag.String("notify-on", reporting.NotifyAlways, "when to notify: always or failure")
+	notifyReportURL := flag.String("notify-report-url", "", "base URL the report files are published under (e.g. CI artifacts), linked in the notification")
 	flag.Parse()
 
+	var notifier *reporting.Notifier
+	if *notifyURL != "" {
+		var err error
+		if notifier, err = reporting.NewNotifier(*notifyURL, *notifyFormat); err != nil {
+			return commands.NewConfigError(fmt.Errorf("invalid --notify: %w", err))
+		}
+	}
+	if *notifyOn != reporting.NotifyAlways && *notifyOn != reporting.NotifyOnFailure {
+		return commands.NewConfigError(fmt.Errorf("invalid --notify-on %q (expected always or failure)", *notifyOn))
+	}
+
 	if *help {
 		app.showGlobalHelp()
 		return nil
@@ -161,12 +181,105 @@ func (app *Application) handleCommand() error {
 	command := flag.Arg(0)
 	args := flag.Args()[1:]
 
-	// 创建执行上下文
+	// 创建执行上下文，运行期间Ctrl+C（SIGINT/SIGTERM）中断运行并仍生成报告
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
 	defer cancel()
+	ctx = execution.WithInterruptHandling(ctx)
+
+	// 使用命令路由器执行，最终报告由记录器收集后写入运行结果摘要与运行历史
+	recorder := reporting.NewResultRecorder()
+	ctx = reporting.WithResultRecorder(ctx, recorder)
+	var history *reporting.HistoryStore
+	if *historyDir != "" {
+		history = reporting.NewHistoryStore(*historyDir)
+		ctx = reporting.WithHistoryStore(ctx, history)
+	}
+	startedAt := time.Now()
+	var err error
+	if app.router.HasCommand(command) {
+		err = app.router.Execute(ctx, command, args)
+	} else {
+		err = commands.NewConfigError(fmt.Errorf("unknown command: %s", command))
+	}
+	result := app.buildResult(command, startedAt, recorder, err)
+	// 只有产生了基准测试报告的运行才写出运行结果、记入历史并发送通知；帮助、配置导出、compare等命令不产生报告
+	captured := recorder.Report() != nil
+	if captured {
+		app.writeResult(*resultFile, result)
+	}
+	// 管理运行历史本身的命令不记入历史，也不发送通知
+	if command == "runs" || command == "history" {
+		return err
+	}
+	runID := recorder.RunID()
+	if history != nil && captured {
+		runID = app.saveHistory(history, result, args, recorder)
+	}
+	if notifier != nil && captured && (*notifyOn == reporting.NotifyAlways || !result.Passed) {
+		app.notify(notifier, runID, result, recorder, *notifyReportURL)
+	}
+	return err
+}
+
+// buildResult 生成运行结果摘要
+func (app *Application) buildResult(command string, startedAt time.Time, recorder *reporting.ResultRecorder, err error) *reporting.RunResult {
+	code := commands.ExitCodeOf(err)
+	result := &reporting.RunResult{
+		Command:   command,
+		Passed:    code == commands.ExitCodeOK,
+		ExitCode:  code,
+		Status:    commands.ExitStatus(code),
+		StartedAt: startedAt,
+		Elapsed:   time.Since(startedAt).Seconds(),
+		Summary:   recorder.Summary(),
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// writeResult 写出运行结果摘要，写入失败只输出警告，不影响退出码
+func (app *Application) writeResult(path string, result *reporting.RunResult) {
+	if path == "" {
+		return
+	}
+	if writeErr := reporting.WriteRunResult(path, result); writeErr != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Failed to write run result: %v\n", writeErr)
+	}
+}
 
-	// 使用命令路由器执行
-	return app.router.Execute(ctx, command, args)
+// saveHistory 将本次运行记入运行历史并返回记录ID，写入失败只输出警告，不影响退出码
+func (app *Application) saveHistory(history *reporting.HistoryStore, result *reporting.RunResult, args []string, recorder *reporting.ResultRecorder) string {
+	record := &reporting.RunRecord{
+		ID:        recorder.RunID(),
+		RunResult: *result,
+		Args:      args,
+		Tags:      recorder.Tags(),
+		Reports:   recorder.ReportFiles(),
+		Report:    recorder.Report(),
+	}
+	if err := history.Save(record); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Failed to record run history: %v\n", err)
+	}
+	return record.ID
+}
+
+// notify 发送运行结束通知，运行ID与运行历史的记录ID一致；发送失败只输出警告，不影响退出码
+func (app *Application) notify(notifier *reporting.Notifier, runID string, result *reporting.RunResult, recorder *reporting.ResultRecorder, reportURL string) {
+	notification := &reporting.RunNotification{
+		RunID:  runID,
+		Result: result,
+		Report: recorder.Report(),
+		Tags:   recorder.Tags(),
+		Link:   reporting.ReportLink(recorder.ReportFiles(), reportURL),
+	}
+	// 运行被中断时context已取消，通知仍需发送
+	if err := notifier.Notify(context.Background(), notification); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Failed to send %s notification: %v\n", notifier.Format(), err)
+		return
+	}
+	fmt.Printf("🔔 Sent %s notification\n", notifier.Format())
 }
 
 // showGlobalHelp 显示全局帮助信息
@@ -180,16 +293,69 @@ func (app *Application) showGlobalHelp() {
 	fmt.Println("  redis, r         Redis performance testing")
 	fmt.Println("  http, h          HTTP load testing")
 	fmt.Println("  kafka, k         Kafka performance testing")
+	fmt.Println("  agent            Run a distributed benchmark agent")
+	fmt.Println("  coordinator      Run a benchmark across remote agents")
+	fmt.Println("  multi            Run one scenario against several targets with per-target results")
+	fmt.Println("  fanout           Pub/Sub fan-out scalability testing")
+	fmt.Println("  compare          Compare two JSON reports and detect regressions")
+	fmt.Println("  report merge     Merge the JSON reports of several runner instances into one")
+	fmt.Println("  churn            New-connections-per-second testing (TCP/TLS/WebSocket)")
+	fmt.Println("  maxconn          Find the max concurrent connections a target accepts")
+	fmt.Println("  drain            Measure how fast a consumer drains a pre-filled queue")
+	fmt.Println("  runs, history    List, show, chart and delete runs in the local history")
+	fmt.Println("  adapter verify   Check a protocol adapter against the adapter contract")
+	fmt.Println("  config schema    Export the JSON Schema of a configuration file")
+	fmt.Println("  server compose   Write (and start) a Docker Compose lab of the test servers")
 	fmt.Println()
 	fmt.Println("GLOBAL OPTIONS:")
 	fmt.Println("  --help, -h       Show help information")
 	fmt.Println("  --version, -v    Show version information")
+	fmt.Println("  --result-file F  Machine-readable run result (default reports/result.json,")
+	fmt.Println("                   empty to disable): passed, exit_code, status and top-line")
+	fmt.Println("                   numbers of every benchmark run, including runs that")
+	fmt.Println("                   fail their checks")
+	fmt.Println("  --history-dir D  Directory of the local run history database (SQLite")
+	fmt.Println("                   history.db; default reports/history, empty to disable),")
+	fmt.Println("                   browsed with \"abc-runner runs\"")
+	fmt.Println("  --notify URL     Post a run summary (score, RPS, P99, error rate, SLA")
+	fmt.Println("                   verdict, report link) to a webhook when a benchmark")
+	fmt.Println("                   run ends")
+	fmt.Println("  --notify-format F")
+	fmt.Println("                   slack, teams or generic JSON (default: detected from")
+	fmt.Println("                   the URL: hooks.slack.com, *.webhook.office.com)")
+	fmt.Println("  --notify-on W    always (default) or failure")
+	fmt.Println("  --notify-report-url U")
+	fmt.Println("                   Base URL the reports are published under (e.g. the CI")
+	fmt.Println("                   artifacts); the HTML report's file name is appended.")
+	fmt.Println("                   Without it the local report path is shown")
+	fmt.Println()
+	fmt.Println("EXIT CODES:")
+	fmt.Println("  0  passed                  3  SLA threshold breach or stop condition")
+	fmt.Println("  1  regression (compare)    4  invalid arguments or config")
+	fmt.Println("  2  internal error          5  target unreachable (incl. simulated runs and")
+	fmt.Println("                                --abort-consecutive-errors)")
+	fmt.Println("                             6  adapter contract violation (adapter verify)")
+	fmt.Println("  130  aborted (Ctrl+C / SIGTERM); the report covers the data collected so far")
 	fmt.Println()
 	fmt.Println("EXAMPLES:")
 	fmt.Println("  abc-runner redis --config config/redis.yaml")
 	fmt.Println("  abc-runner r -n 1000 -c 10")
 	fmt.Println("  abc-runner http --url http://localhost:8080")
 	fmt.Println("  abc-runner kafka --brokers localhost:9092")
+	fmt.Println("  abc-runner coordinator --agents host1:7070,host2:7070 redis -n 100000")
+	fmt.Println("  abc-runner multi --targets shard1:6379,shard2:6379 redis -h {host} -p {port}")
+	fmt.Println("  abc-runner fanout --transport redis -s 10,100,1000 -r 200")
+	fmt.Println("  abc-runner compare baseline.json reports/redis_report.json")
+	fmt.Println("  abc-runner report merge -o merged.json host1.json host2.json host3.json")
+	fmt.Println("  abc-runner churn --target tls://localhost:8443 -k -r 500")
+	fmt.Println("  abc-runner maxconn --target tcp://localhost:8080 --drip 10s")
+	fmt.Println("  abc-runner drain --queue kafka --name orders --group order-workers -n 50000")
+	fmt.Println("  abc-runner runs list --protocol http --tag nightly --failed")
+	fmt.Println("  abc-runner adapter verify tcp --host localhost --port 9090")
+	fmt.Println("  abc-runner server compose --with redis,kafka --up")
+	fmt.Println("  abc-runner config schema --protocol redis --out redis.schema.json")
+	fmt.Println("  abc-runner --result-file out/result.json http --url http://localhost:8080 --sla-p99 50ms")
+	fmt.Println("  abc-runner --notify https://hooks.slack.com/services/T0/B0/X --notify-on failure redis -n 100000")
 	fmt.Println()
 	fmt.Println("Use \"abc-runner <command> --help\" for more information about a command.")
 }
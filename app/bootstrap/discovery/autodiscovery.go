@@ -8,10 +8,25 @@ import (
 	"reflect"
 	"strings"
 
+	"abc-runner/app/adapters/clickhouse"
+	"abc-runner/app/adapters/dns"
+	"abc-runner/app/adapters/elasticsearch"
+	"abc-runner/app/adapters/etcd"
 	"abc-runner/app/adapters/grpc"
 	"abc-runner/app/adapters/http"
 	"abc-runner/app/adapters/kafka"
+	"abc-runner/app/adapters/mongodb"
+	"abc-runner/app/adapters/mysql"
+	"abc-runner/app/adapters/otlp"
+	"abc-runner/app/adapters/postgres"
+	"abc-runner/app/adapters/pulsar"
+	"abc-runner/app/adapters/rabbitmq"
 	"abc-runner/app/adapters/redis"
+	"abc-runner/app/adapters/remotewrite"
+	"abc-runner/app/adapters/s3"
+	"abc-runner/app/adapters/smtp"
+	"abc-runner/app/adapters/snmp"
+	"abc-runner/app/adapters/syslog"
 	"abc-runner/app/adapters/tcp"
 	"abc-runner/app/adapters/udp"
 	"abc-runner/app/adapters/websocket"
@@ -26,13 +41,28 @@ import (
 type AutoDIBuilder struct {
 	components map[string]interface{}
 	// 使用接口分离模式存储各协议工厂
-	grpcFactory      interfaces.GRPCAdapterFactory
-	tcpFactory       interfaces.TCPAdapterFactory
-	udpFactory       interfaces.UDPAdapterFactory
-	websocketFactory interfaces.WebSocketAdapterFactory
-	redisFactory     interfaces.RedisAdapterFactory
-	httpFactory      interfaces.HttpAdapterFactory
-	kafkaFactory     interfaces.KafkaAdapterFactory
+	grpcFactory        interfaces.GRPCAdapterFactory
+	tcpFactory         interfaces.TCPAdapterFactory
+	udpFactory         interfaces.UDPAdapterFactory
+	snmpFactory        interfaces.SNMPAdapterFactory
+	syslogFactory      interfaces.SyslogAdapterFactory
+	postgresFactory    interfaces.PostgresAdapterFactory
+	mysqlFactory       interfaces.MySQLAdapterFactory
+	mongoFactory       interfaces.MongoAdapterFactory
+	rabbitMQFactory    interfaces.RabbitMQAdapterFactory
+	pulsarFactory      interfaces.PulsarAdapterFactory
+	esFactory          interfaces.ElasticsearchAdapterFactory
+	s3Factory          interfaces.S3AdapterFactory
+	dnsFactory         interfaces.DNSAdapterFactory
+	etcdFactory        interfaces.EtcdAdapterFactory
+	clickHouseFactory  interfaces.ClickHouseAdapterFactory
+	smtpFactory        interfaces.SMTPAdapterFactory
+	remoteWriteFactory interfaces.RemoteWriteAdapterFactory
+	otlpFactory        interfaces.OTLPAdapterFactory
+	websocketFactory   interfaces.WebSocketAdapterFactory
+	redisFactory       interfaces.RedisAdapterFactory
+	httpFactory        interfaces.HttpAdapterFactory
+	kafkaFactory       interfaces.KafkaAdapterFactory
 	// 保留通用查找接口，向下兼容
 	factories map[string]interface{}
 }
@@ -129,6 +159,96 @@ func (builder *AutoDIBuilder) discoverProtocolAdapters() error {
 	builder.components["udp_factory"] = builder.udpFactory
 	log.Printf("✅ Registered UDP adapter factory")
 
+	// 创建并注册SNMP工厂
+	builder.snmpFactory = snmp.NewAdapterFactory(metricsCollector)
+	builder.factories["snmp"] = builder.snmpFactory
+	builder.components["snmp_factory"] = builder.snmpFactory
+	log.Printf("✅ Registered SNMP adapter factory")
+
+	// 创建并注册Syslog工厂
+	builder.syslogFactory = syslog.NewAdapterFactory(metricsCollector)
+	builder.factories["syslog"] = builder.syslogFactory
+	builder.components["syslog_factory"] = builder.syslogFactory
+	log.Printf("✅ Registered Syslog adapter factory")
+
+	// 创建并注册PostgreSQL工厂
+	builder.postgresFactory = postgres.NewAdapterFactory(metricsCollector)
+	builder.factories["postgres"] = builder.postgresFactory
+	builder.components["postgres_factory"] = builder.postgresFactory
+	log.Printf("✅ Registered PostgreSQL adapter factory")
+
+	// 创建并注册MySQL工厂
+	builder.mysqlFactory = mysql.NewAdapterFactory(metricsCollector)
+	builder.factories["mysql"] = builder.mysqlFactory
+	builder.components["mysql_factory"] = builder.mysqlFactory
+	log.Printf("✅ Registered MySQL adapter factory")
+
+	// 创建并注册MongoDB工厂
+	builder.mongoFactory = mongodb.NewAdapterFactory(metricsCollector)
+	builder.factories["mongodb"] = builder.mongoFactory
+	builder.components["mongodb_factory"] = builder.mongoFactory
+	log.Printf("✅ Registered MongoDB adapter factory")
+
+	// 创建并注册RabbitMQ工厂
+	builder.rabbitMQFactory = rabbitmq.NewAdapterFactory(metricsCollector)
+	builder.factories["rabbitmq"] = builder.rabbitMQFactory
+	builder.components["rabbitmq_factory"] = builder.rabbitMQFactory
+	log.Printf("✅ Registered RabbitMQ adapter factory")
+
+	// 创建并注册Pulsar工厂
+	builder.pulsarFactory = pulsar.NewAdapterFactory(metricsCollector)
+	builder.factories["pulsar"] = builder.pulsarFactory
+	builder.components["pulsar_factory"] = builder.pulsarFactory
+	log.Printf("✅ Registered Pulsar adapter factory")
+
+	// 创建并注册Elasticsearch工厂
+	builder.esFactory = elasticsearch.NewAdapterFactory(metricsCollector)
+	builder.factories["elasticsearch"] = builder.esFactory
+	builder.components["elasticsearch_factory"] = builder.esFactory
+	log.Printf("✅ Registered Elasticsearch adapter factory")
+
+	// 创建并注册S3工厂
+	builder.s3Factory = s3.NewAdapterFactory(metricsCollector)
+	builder.factories["s3"] = builder.s3Factory
+	builder.components["s3_factory"] = builder.s3Factory
+	log.Printf("✅ Registered S3 adapter factory")
+
+	// 创建并注册DNS工厂
+	builder.dnsFactory = dns.NewAdapterFactory(metricsCollector)
+	builder.factories["dns"] = builder.dnsFactory
+	builder.components["dns_factory"] = builder.dnsFactory
+	log.Printf("✅ Registered DNS adapter factory")
+
+	// 创建并注册etcd工厂
+	builder.etcdFactory = etcd.NewAdapterFactory(metricsCollector)
+	builder.factories["etcd"] = builder.etcdFactory
+	builder.components["etcd_factory"] = builder.etcdFactory
+	log.Printf("✅ Registered etcd adapter factory")
+
+	// 创建并注册ClickHouse工厂
+	builder.clickHouseFactory = clickhouse.NewAdapterFactory(metricsCollector)
+	builder.factories["clickhouse"] = builder.clickHouseFactory
+	builder.components["clickhouse_factory"] = builder.clickHouseFactory
+	log.Printf("✅ Registered ClickHouse adapter factory")
+
+	// 创建并注册SMTP工厂
+	builder.smtpFactory = smtp.NewAdapterFactory(metricsCollector)
+	builder.factories["smtp"] = builder.smtpFactory
+	builder.components["smtp_factory"] = builder.smtpFactory
+	log.Printf("✅ Registered SMTP adapter factory")
+
+	// 创建并注册Prometheus remote-write工厂
+	builder.remoteWriteFactory = remotewrite.NewAdapterFactory(metricsCollector)
+	builder.factories["remotewrite"] = builder.remoteWriteFactory
+	builder.components["remotewrite_factory"] = builder.remoteWriteFactory
+	log.Printf("✅ Registered remote-write adapter factory")
+
+	// 创建并注册OTLP工厂
+	builder.otlpFactory = otlp.NewAdapterFactory(metricsCollector)
+	builder.factories["otlp"] = builder.otlpFactory
+	builder.components["otlp_factory"] = builder.otlpFactory
+	log.Printf("✅ Registered OTLP adapter factory")
+
 	// 创建并注册WebSocket工厂
 	builder.websocketFactory = websocket.NewAdapterFactory(metricsCollector)
 	builder.factories["websocket"] = builder.websocketFactory
@@ -184,6 +304,111 @@ func (builder *AutoDIBuilder) registerCommandHandlers() error {
 		log.Printf("✅ Registered command handler: udp_handler")
 	}
 
+	// SNMP 命令处理器
+	if builder.snmpFactory != nil {
+		handler := commands.NewSNMPCommandHandler(builder.snmpFactory)
+		builder.components["snmp_handler"] = handler
+		log.Printf("✅ Registered command handler: snmp_handler")
+	}
+
+	// Syslog 命令处理器
+	if builder.syslogFactory != nil {
+		handler := commands.NewSyslogCommandHandler(builder.syslogFactory)
+		builder.components["syslog_handler"] = handler
+		log.Printf("✅ Registered command handler: syslog_handler")
+	}
+
+	// PostgreSQL 命令处理器
+	if builder.postgresFactory != nil {
+		handler := commands.NewPostgresCommandHandler(builder.postgresFactory)
+		builder.components["postgres_handler"] = handler
+		log.Printf("✅ Registered command handler: postgres_handler")
+	}
+
+	// MySQL 命令处理器
+	if builder.mysqlFactory != nil {
+		handler := commands.NewMySQLCommandHandler(builder.mysqlFactory)
+		builder.components["mysql_handler"] = handler
+		log.Printf("✅ Registered command handler: mysql_handler")
+	}
+
+	// MongoDB 命令处理器
+	if builder.mongoFactory != nil {
+		handler := commands.NewMongoCommandHandler(builder.mongoFactory)
+		builder.components["mongodb_handler"] = handler
+		log.Printf("✅ Registered command handler: mongodb_handler")
+	}
+
+	// RabbitMQ 命令处理器
+	if builder.rabbitMQFactory != nil {
+		handler := commands.NewRabbitMQCommandHandler(builder.rabbitMQFactory)
+		builder.components["rabbitmq_handler"] = handler
+		log.Printf("✅ Registered command handler: rabbitmq_handler")
+	}
+
+	// Pulsar 命令处理器
+	if builder.pulsarFactory != nil {
+		handler := commands.NewPulsarCommandHandler(builder.pulsarFactory)
+		builder.components["pulsar_handler"] = handler
+		log.Printf("✅ Registered command handler: pulsar_handler")
+	}
+
+	// Elasticsearch 命令处理器
+	if builder.esFactory != nil {
+		handler := commands.NewElasticsearchCommandHandler(builder.esFactory)
+		builder.components["elasticsearch_handler"] = handler
+		log.Printf("✅ Registered command handler: elasticsearch_handler")
+	}
+
+	// S3 命令处理器
+	if builder.s3Factory != nil {
+		handler := commands.NewS3CommandHandler(builder.s3Factory)
+		builder.components["s3_handler"] = handler
+		log.Printf("✅ Registered command handler: s3_handler")
+	}
+
+	// DNS 命令处理器
+	if builder.dnsFactory != nil {
+		handler := commands.NewDNSCommandHandler(builder.dnsFactory)
+		builder.components["dns_handler"] = handler
+		log.Printf("✅ Registered command handler: dns_handler")
+	}
+
+	// etcd 命令处理器
+	if builder.etcdFactory != nil {
+		handler := commands.NewEtcdCommandHandler(builder.etcdFactory)
+		builder.components["etcd_handler"] = handler
+		log.Printf("✅ Registered command handler: etcd_handler")
+	}
+
+	// ClickHouse 命令处理器
+	if builder.clickHouseFactory != nil {
+		handler := commands.NewClickHouseCommandHandler(builder.clickHouseFactory)
+		builder.components["clickhouse_handler"] = handler
+		log.Printf("✅ Registered command handler: clickhouse_handler")
+	}
+
+	// SMTP 命令处理器
+	if builder.smtpFactory != nil {
+		handler := commands.NewSMTPCommandHandler(builder.smtpFactory)
+		builder.components["smtp_handler"] = handler
+		log.Printf("✅ Registered command handler: smtp_handler")
+	}
+
+	// Prometheus remote-write 命令处理器
+	if builder.remoteWriteFactory != nil {
+		handler := commands.NewRemoteWriteCommandHandler(builder.remoteWriteFactory)
+		builder.components["remotewrite_handler"] = handler
+		log.Printf("✅ Registered command handler: remotewrite_handler")
+	}
+
+	// OTLP 命令处理器
+	if builder.otlpFactory != nil {
+		handler := commands.NewOTLPCommandHandler(builder.otlpFactory)
+		builder.components["otlp_handler"] = handler
+		log.Printf("✅ Registered command handler: otlp_handler")
+	}
+
 	// WebSocket 命令处理器
 	if builder.websocketFactory != nil {
 		handler := commands.NewWebSocketCommandHandler(builder.websocketFactory)
@@ -212,6 +437,54 @@ func (builder *AutoDIBuilder) registerCommandHandlers() error {
 		log.Printf("✅ Registered command handler: kafka_handler")
 	}
 
+	// 分布式测试命令处理器
+	builder.components["agent_handler"] = commands.NewAgentCommandHandler()
+	log.Printf("✅ Registered command handler: agent_handler")
+	builder.components["coordinator_handler"] = commands.NewCoordinatorCommandHandler()
+	log.Printf("✅ Registered command handler: coordinator_handler")
+	builder.components["multi_handler"] = commands.NewMultiTargetCommandHandler()
+	log.Printf("✅ Registered command handler: multi_handler")
+
+	// 发布订阅扇出测试命令处理器
+	builder.components["fanout_handler"] = commands.NewFanoutCommandHandler()
+	log.Printf("✅ Registered command handler: fanout_handler")
+
+	// 基线对比与回归检测命令处理器
+	builder.components["compare_handler"] = commands.NewCompareCommandHandler()
+	log.Printf("✅ Registered command handler: compare_handler")
+
+	// 新建连接速率测试命令处理器
+	builder.components["churn_handler"] = commands.NewChurnCommandHandler()
+	log.Printf("✅ Registered command handler: churn_handler")
+
+	// 最大并发连接数探测命令处理器
+	builder.components["maxconn_handler"] = commands.NewMaxConnCommandHandler()
+	log.Printf("✅ Registered command handler: maxconn_handler")
+
+	// 消息队列消费速率测试命令处理器
+	builder.components["drain_handler"] = commands.NewDrainCommandHandler()
+	log.Printf("✅ Registered command handler: drain_handler")
+
+	// 报告合并命令处理器
+	builder.components["report_handler"] = commands.NewReportCommandHandler()
+	log.Printf("✅ Registered command handler: report_handler")
+
+	// 运行历史管理命令处理器
+	builder.components["runs_handler"] = commands.NewRunsCommandHandler()
+	log.Printf("✅ Registered command handler: runs_handler")
+
+	// 协议适配器一致性检查命令处理器
+	builder.components["adapter_handler"] = commands.NewAdapterCommandHandler()
+	log.Printf("✅ Registered command handler: adapter_handler")
+
+	// 配置文件工具命令处理器
+	builder.components["config_handler"] = commands.NewConfigCommandHandler()
+	log.Printf("✅ Registered command handler: config_handler")
+
+	// 测试服务端套件命令处理器
+	builder.components["server_handler"] = commands.NewServerCommandHandler()
+	log.Printf("✅ Registered command handler: server_handler")
+
 	log.Printf("🎉 All implemented command handlers registered successfully!")
 	return nil
 }
@@ -283,7 +556,7 @@ func ScanAdapterDirectories(basePath string) ([]string, error) {
 
 // IsValidProtocolName 检查是否是有效的协议名称
 func IsValidProtocolName(name string) bool {
-	validProtocols := []string{"redis", "http", "https", "kafka", "grpc", "tcp", "udp", "websocket"}
+	validProtocols := []string{"redis", "http", "https", "kafka", "grpc", "tcp", "udp", "snmp", "syslog", "postgres", "mysql", "mongodb", "rabbitmq", "pulsar", "elasticsearch", "s3", "dns", "etcd", "clickhouse", "smtp", "remotewrite", "otlp", "websocket"}
 
 	name = strings.ToLower(name)
 	for _, valid := range validProtocols {
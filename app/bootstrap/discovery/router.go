@@ -20,6 +20,14 @@ type CommandHandler interface {
 	GetHelp() string
 }
 
+// CommandExecutorAware 需要通过路由器执行其他命令的处理器（如分布式agent）
+type CommandExecutorAware interface {
+	SetCommandExecutor(executor func(ctx context.Context, command string, args []string) error)
+}
+
+// utilityCommands 非协议类的内置命令
+var utilityCommands = []string{"agent", "coordinator", "multi", "fanout", "compare", "report", "churn", "maxconn", "drain", "runs", "adapter", "config", "server"}
+
 // NewCommandRouter 创建命令路由器
 func NewCommandRouter(builder *AutoDIBuilder) *CommandRouter {
 	return &CommandRouter{
@@ -41,6 +49,13 @@ func (r *CommandRouter) AutoRegister() error {
 		}
 	}
 	
+	// 注册内置工具命令
+	for _, command := range utilityCommands {
+		if err := r.registerUtilityCommand(command); err != nil {
+			log.Printf("Warning: failed to register command %s: %v", command, err)
+		}
+	}
+	
 	log.Printf("Command auto-registration completed. Registered %d commands", len(r.commands))
 	return nil
 }
@@ -70,6 +85,31 @@ func (r *CommandRouter) registerProtocolCommand(protocol string) error {
 	return nil
 }
 
+// registerUtilityCommand 注册非协议类命令
+func (r *CommandRouter) registerUtilityCommand(command string) error {
+	handlerName := command + "_handler"
+	
+	component, exists := r.builder.GetComponent(handlerName)
+	if !exists {
+		return fmt.Errorf("command handler not found: %s", handlerName)
+	}
+	
+	handler, ok := component.(CommandHandler)
+	if !ok {
+		return fmt.Errorf("component is not a CommandHandler: %s", handlerName)
+	}
+	
+	// 需要执行其他命令的处理器由路由器注入执行器
+	if aware, ok := handler.(CommandExecutorAware); ok {
+		aware.SetCommandExecutor(r.Execute)
+	}
+	
+	r.commands[command] = handler
+	log.Printf("✅ Registered command: %s", command)
+	r.registerCommonAliases(command)
+	return nil
+}
+
 // registerCommonAliases 注册常见别名
 func (r *CommandRouter) registerCommonAliases(protocol string) {
 	var aliases []string
@@ -85,10 +125,26 @@ func (r *CommandRouter) registerCommonAliases(protocol string) {
 		aliases = []string{"t"}
 	case "udp":
 		aliases = []string{"u"}
+	case "snmp":
+		aliases = []string{"s"}
+	case "postgres":
+		aliases = []string{"pg"}
+	case "mongodb":
+		aliases = []string{"mongo"}
+	case "rabbitmq":
+		aliases = []string{"amqp"}
+	case "elasticsearch":
+		aliases = []string{"es", "opensearch"}
+	case "s3":
+		aliases = []string{"minio"}
+	case "remotewrite":
+		aliases = []string{"prw"}
 	case "grpc":
 		aliases = []string{"g"}
 	case "websocket":
 		aliases = []string{"ws"}
+	case "runs":
+		aliases = []string{"history"}
 	}
 	
 	for _, alias := range aliases {
@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"abc-runner/app/core/distributed"
+)
+
+// AgentCommandHandler 分布式agent命令处理器
+type AgentCommandHandler struct {
+	executor distributed.CommandExecutor
+}
+
+// NewAgentCommandHandler 创建agent命令处理器
+func NewAgentCommandHandler() *AgentCommandHandler {
+	return &AgentCommandHandler{}
+}
+
+// SetCommandExecutor 设置本地命令执行器（由命令路由器注入）
+func (h *AgentCommandHandler) SetCommandExecutor(executor func(ctx context.Context, command string, args []string) error) {
+	h.executor = executor
+}
+
+// Execute 启动agent服务
+func (h *AgentCommandHandler) Execute(ctx context.Context, args []string) error {
+	// 检查帮助请求
+	for _, arg := range args {
+		if arg == "--help" || arg == "-h" || arg == "help" {
+			fmt.Println(h.GetHelp())
+			return nil
+		}
+	}
+
+	if h.executor == nil {
+		return fmt.Errorf("agent command executor not configured")
+	}
+
+	listen := distributed.DefaultAgentListen
+	agentID := ""
+	token := os.Getenv(distributed.TokenEnv)
+	allowed := distributed.DefaultAllowedCommands
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--token", "--allow", "--listen", "--id":
+			if i+1 >= len(args) {
+				return NewConfigError(fmt.Errorf("missing value for %s", args[i]))
+			}
+		default:
+			continue
+		}
+		value := args[i+1]
+		switch args[i] {
+		case "--token":
+			token = value
+		case "--allow":
+			allowed = nil
+			for _, command := range strings.Split(value, ",") {
+				if command = strings.TrimSpace(command); command != "" {
+					allowed = append(allowed, command)
+				}
+			}
+		case "--listen":
+			listen = value
+		case "--id":
+			agentID = value
+		}
+		i++
+	}
+
+	if token == "" {
+		return NewConfigError(fmt.Errorf("agent token is required (use --token or set %s)", distributed.TokenEnv))
+	}
+	if len(allowed) == 0 {
+		return NewConfigError(fmt.Errorf("--allow requires at least one command"))
+	}
+
+	agent := distributed.NewAgent(agentID, token, distributed.CommandExecutor(h.executor))
+	agent.SetAllowedCommands(allowed)
+
+	// agent为常驻服务，不受单次命令超时限制，收到中断信号时退出
+	serveCtx, stop := signal.NotifyContext(context.WithoutCancel(ctx), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("🛰️  abc-runner agent %s listening on %s\n", agent.ID(), listen)
+	if err := agent.ListenAndServe(serveCtx, listen); err != nil {
+		return fmt.Errorf("agent server failed: %w", err)
+	}
+
+	fmt.Printf("👋 Agent %s stopped\n", agent.ID())
+	return nil
+}
+
+// GetHelp 获取帮助信息
+func (h *AgentCommandHandler) GetHelp() string {
+	return fmt.Sprintf(`Distributed Benchmark Agent
+
+USAGE:
+  abc-runner agent --token TOKEN [options]
+
+DESCRIPTION:
+  Run a worker agent that executes benchmark workloads on behalf of a
+  coordinator and streams metrics snapshots back over HTTP (NDJSON).
+  Every request must carry the shared token, and only benchmark
+  commands from the allow-list are executed.
+
+OPTIONS:
+  --help, -h        Show this help message
+  --listen ADDR     Listen address (default: %s)
+  --id NAME         Agent identifier (default: hostname)
+  --token TOKEN     Shared token required from the coordinator (default: $%s)
+  --allow LIST      Comma-separated commands the agent may run
+                    (default: %s)
+
+EXAMPLES:
+  abc-runner agent --token s3cret
+  abc-runner agent --listen 0.0.0.0:7070 --id load-gen-1 --token s3cret --allow redis,http
+
+SEE ALSO:
+  abc-runner coordinator --help
+`, distributed.DefaultAgentListen, distributed.TokenEnv, strings.Join(distributed.DefaultAllowedCommands, ","))
+}
@@ -0,0 +1,31 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"abc-runner/app/core/distributed"
+)
+
+func TestAgentAndCoordinator_RejectMissingValues(t *testing.T) {
+	// 环境变量中的令牌不能掩盖缺失值的 --token
+	t.Setenv(distributed.TokenEnv, "env-token")
+
+	agent := NewAgentCommandHandler()
+	agent.SetCommandExecutor(func(ctx context.Context, command string, args []string) error { return nil })
+	for _, flag := range []string{"--token", "--allow", "--listen", "--id"} {
+		err := agent.Execute(context.Background(), []string{"--listen", "127.0.0.1:0", flag})
+		if ExitCodeOf(err) != ExitCodeConfig || !strings.Contains(err.Error(), "missing value for "+flag) {
+			t.Errorf("agent %s: expected missing value config error, got %v", flag, err)
+		}
+	}
+
+	coordinator := NewCoordinatorCommandHandler()
+	for _, flag := range []string{"--agents", "--token"} {
+		err := coordinator.Execute(context.Background(), []string{"--agents", "localhost:7070", flag})
+		if ExitCodeOf(err) != ExitCodeConfig || !strings.Contains(err.Error(), "missing value for "+flag) {
+			t.Errorf("coordinator %s: expected missing value config error, got %v", flag, err)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"abc-runner/app/core/conntest"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/reporting"
+)
+
+// ChurnCommandHandler 新建连接速率（连接抖动）测试命令处理器
+type ChurnCommandHandler struct{}
+
+// NewChurnCommandHandler 创建连接抖动测试命令处理器
+func NewChurnCommandHandler() *ChurnCommandHandler {
+	return &ChurnCommandHandler{}
+}
+
+// churnArgs 连接抖动测试命令行参数
+type churnArgs struct {
+	target  string
+	options conntest.DialerOptions
+	config  *conntest.ChurnConfig
+}
+
+// Execute 执行连接抖动测试
+func (h *ChurnCommandHandler) Execute(ctx context.Context, args []string) error {
+	for _, arg := range args {
+		if arg == "--help" || arg == "-h" || arg == "help" {
+			fmt.Println(h.GetHelp())
+			return nil
+		}
+	}
+
+	parsed, err := h.parseArgs(args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	// 解析通用运行选项
+	opts, err := parseRunOptions(ctx, "", args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	dialer, err := conntest.NewDialer(parsed.target, parsed.options)
+	if err != nil {
+		return err
+	}
+
+	metricsCollector := metrics.NewBaseCollector(opts.collectorConfig(), map[string]interface{}{
+		"protocol":  dialer.Name(),
+		"test_type": "churn",
+	})
+	defer metricsCollector.Stop()
+
+	// 测试时长由--duration控制，这里仅响应显式取消
+	runCtx := context.WithoutCancel(ctx)
+
+	cfg := parsed.config
+	rate := "unthrottled"
+	if cfg.Rate > 0 {
+		rate = fmt.Sprintf("%d conn/s", cfg.Rate)
+	}
+	fmt.Printf("🚀 Starting %s connection churn test: target=%s, rate=%s, concurrency=%d, duration=%v, hold=%v\n",
+		dialer.Name(), dialer.Target(), rate, cfg.Concurrency, cfg.Duration, cfg.Hold)
+
+	opts.applyToCollector(metricsCollector)
+	runner := conntest.NewChurnRunner(dialer, cfg, metricsCollector)
+	stats, err := runner.Run(runCtx, func(sample conntest.ChurnSample) {
+		fmt.Printf("🔌 t=%v new=%d/s failed=%d open=%d\n",
+			sample.Elapsed, sample.Established, sample.Failed, sample.Open)
+	})
+	opts.finishRun()
+	if err != nil && stats == nil {
+		return fmt.Errorf("connection churn test failed: %w", err)
+	}
+	if err != nil {
+		fmt.Printf("⚠️  Connection churn test stopped early: %v\n", err)
+	}
+
+	h.printSummary(stats)
+	return h.generateReport(metricsCollector, stats, opts)
+}
+
+// parseArgs 解析命令行参数
+func (h *ChurnCommandHandler) parseArgs(args []string) (*churnArgs, error) {
+	parsed := &churnArgs{
+		options: conntest.DialerOptions{Timeout: 5 * time.Second},
+		config:  conntest.NewDefaultChurnConfig(),
+	}
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--insecure", "-k":
+			parsed.options.InsecureSkipVerify = true
+			continue
+		case "--linger0":
+			parsed.options.Linger0 = true
+			continue
+		}
+
+		if i+1 >= len(args) {
+			break
+		}
+		value := args[i+1]
+		var err error
+
+		switch args[i] {
+		case "--target", "-t":
+			parsed.target = value
+		case "--rate", "-r":
+			parsed.config.Rate, err = strconv.Atoi(value)
+		case "--concurrency", "-c":
+			parsed.config.Concurrency, err = strconv.Atoi(value)
+		case "--duration", "-d":
+			parsed.config.Duration, err = time.ParseDuration(value)
+		case "--hold":
+			parsed.config.Hold, err = time.ParseDuration(value)
+		case "--timeout":
+			parsed.options.Timeout, err = time.ParseDuration(value)
+		case "--server-name":
+			parsed.options.ServerName = value
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", args[i], err)
+		}
+		i++
+	}
+
+	if parsed.target == "" {
+		return nil, fmt.Errorf("--target is required (e.g. tcp://localhost:6379, tls://localhost:443, ws://localhost:7070/ws)")
+	}
+	if parsed.options.Timeout <= 0 {
+		return nil, fmt.Errorf("timeout must be positive")
+	}
+	if err := parsed.config.Validate(); err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+// printSummary 输出建连速率、握手延迟与失败原因汇总
+func (h *ChurnCommandHandler) printSummary(stats *conntest.ChurnStats) {
+	fmt.Printf("\n📊 Connection Churn Summary (%s %s)\n", stats.Transport, stats.Target)
+	fmt.Printf("  Attempts:        %d (%.1f/s)\n", stats.Attempts, stats.AttemptRate)
+	fmt.Printf("  Established:     %d (%.1f/s, peak %.0f/s)\n", stats.Established, stats.ConnectionRate, stats.PeakRate)
+	fmt.Printf("  Failed:          %d (success rate %.2f%%)\n", stats.Failed, stats.SuccessRate)
+	if stats.Skipped > 0 {
+		fmt.Printf("  Skipped:         %d (concurrency limit reached; raise --concurrency)\n", stats.Skipped)
+	}
+	if stats.PeakOpen > 0 {
+		fmt.Printf("  Peak open:       %d\n", stats.PeakOpen)
+	}
+
+	fmt.Printf("\n%-12s %12s %12s %12s %12s %12s %12s\n", "latency", "avg", "p50", "p90", "p99", "p999", "max")
+	printLatency := func(name string, m metrics.LatencyMetrics) {
+		fmt.Printf("%-12s %12v %12v %12v %12v %12v %12v\n", name,
+			m.Average.Round(time.Microsecond), m.P50.Round(time.Microsecond), m.P90.Round(time.Microsecond),
+			m.P99.Round(time.Microsecond), m.P999.Round(time.Microsecond), m.Max.Round(time.Microsecond))
+	}
+	printLatency("connect", stats.Connect)
+	if stats.Handshake.P50 > 0 {
+		printLatency("handshake", stats.Handshake)
+	}
+	printLatency("total", stats.Total)
+
+	if causes := stats.FailureCauses(); len(causes) > 0 {
+		fmt.Printf("\n%-24s %10s %8s\n", "failure cause", "count", "share")
+		for _, cause := range causes {
+			count := stats.Failures[cause]
+			fmt.Printf("%-24s %10d %7.2f%%\n", cause, count, float64(count)/float64(stats.Failed)*100)
+		}
+	}
+	fmt.Println()
+}
+
+// generateReport 生成报告
+func (h *ChurnCommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}], stats *conntest.ChurnStats, opts *runOptions) error {
+	snapshot := collector.Snapshot()
+	snapshot.Protocol["churn"] = stats.ToMap()
+
+	if opts.publishSnapshot(snapshot) {
+		return nil
+	}
+
+	report := reporting.ConvertFromMetricsSnapshot(snapshot)
+	reportConfig := reporting.NewStandardReportConfig("churn_" + stats.Transport)
+	opts.applyToReport(snapshot, report, reportConfig)
+	generator := reporting.NewReportGenerator(reportConfig)
+	if err := generator.Generate(report); err != nil {
+		return err
+	}
+	return opts.resultError(report)
+}
+
+// GetHelp 获取帮助信息
+func (h *ChurnCommandHandler) GetHelp() string {
+	return `Connection Churn (New Connections per Second) Test
+
+USAGE:
+  abc-runner churn --target URL [options]
+
+DESCRIPTION:
+  Repeatedly open and close connections to measure how many new TCP, TLS
+  or WebSocket connections per second a target can accept, independent of
+  request throughput. No application data is sent; each attempt ends once
+  the handshake completes. Reports connect and handshake latency
+  percentiles and groups failures by cause (refused, reset, timeouts,
+  TLS certificate, HTTP status of a rejected upgrade, ...).
+
+  Without --rate the test runs closed-loop: --concurrency workers dial as
+  fast as the target accepts. With --rate attempts are issued on schedule;
+  attempts that would exceed --concurrency in-flight handshakes are
+  counted as skipped instead of being delayed.
+
+OPTIONS:
+  --help, -h               Show this help message
+  --target, -t URL         Target: tcp://host:port, tls://host:port,
+                           ws://host:port/path or wss://host:port/path
+                           (host:port without scheme means tcp)
+  --rate, -r N             New connection attempts per second
+                           (default: 0, unthrottled)
+  --concurrency, -c N      Max in-flight handshakes (default: 50)
+  --duration, -d DUR       Test duration (default: 10s)
+  --hold DUR               Keep each connection open before closing it
+                           (default: 0, close immediately)
+  --timeout DUR            Connect plus handshake timeout (default: 5s)
+  --insecure, -k           Skip TLS certificate verification
+  --server-name NAME       TLS SNI (default: target host)
+  --linger0                Close with RST instead of FIN so the client does
+                           not accumulate TIME_WAIT sockets
+
+EXAMPLES:
+  abc-runner churn --target tcp://localhost:6379 -c 100 -d 30s
+  abc-runner churn --target tls://localhost:8443 -k -r 500 -d 1m
+  abc-runner churn --target ws://localhost:7070/ws -r 200 --hold 1s
+
+NOTE:
+  At high rates the client itself may run out of ephemeral ports or file
+  descriptors; such failures are reported as client_port_exhausted and
+  client_fd_exhausted rather than blamed on the target.` + runOptionsHelp + "\n"
+}
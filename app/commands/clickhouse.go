@@ -0,0 +1,463 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	chConfig "abc-runner/app/adapters/clickhouse/config"
+	"abc-runner/app/adapters/clickhouse/connection"
+	"abc-runner/app/adapters/clickhouse/operations"
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/reporting"
+)
+
+// ClickHouseCommandHandler ClickHouse命令处理器
+type ClickHouseCommandHandler struct {
+	protocolName string
+	factory      interfaces.ClickHouseAdapterFactory
+}
+
+// NewClickHouseCommandHandler 创建ClickHouse命令处理器
+func NewClickHouseCommandHandler(factory interfaces.ClickHouseAdapterFactory) *ClickHouseCommandHandler {
+	if factory == nil {
+		panic("clickHouseAdapterFactory cannot be nil - dependency injection required")
+	}
+
+	return &ClickHouseCommandHandler{
+		protocolName: "clickhouse",
+		factory:      factory,
+	}
+}
+
+// Execute 执行ClickHouse命令
+func (h *ClickHouseCommandHandler) Execute(ctx context.Context, args []string) error {
+	// 检查帮助请求
+	for i, arg := range args {
+		if arg == "--help" || arg == "help" {
+			fmt.Println(h.GetHelp())
+			return nil
+		}
+		if arg == "-h" && (i+1 >= len(args) || !looksLikeHostname(args[i+1])) {
+			fmt.Println(h.GetHelp())
+			return nil
+		}
+	}
+
+	// 解析命令行参数
+	config, err := h.parseArgs(args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	// 解析通用运行选项
+	opts, err := parseRunOptions(ctx, "clickhouse", args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+	if printConfigRequested(args) {
+		return printEffectiveConfig("clickhouse", config, opts)
+	}
+
+	metricsCollector := metrics.NewBaseCollector(opts.collectorConfig(), map[string]interface{}{
+		"protocol":  "clickhouse",
+		"test_type": "performance",
+	})
+	defer metricsCollector.Stop()
+
+	adapter := h.factory.CreateClickHouseAdapter()
+	if adapter == nil {
+		return fmt.Errorf("failed to create ClickHouse adapter")
+	}
+	defer adapter.Close()
+
+	target := config.Connection.GetAddresses()[0]
+	if err := adapter.Connect(ctx, config); err != nil {
+		return NewConnectionError(fmt.Errorf("failed to connect to clickhouse %s: %w", target, err))
+	}
+	if err := adapter.HealthCheck(ctx); err != nil {
+		return NewConnectionError(fmt.Errorf("clickhouse health check failed: %w", err))
+	}
+
+	specific := config.ClickHouseSpecific
+	if chAdapter, ok := adapter.(interface {
+		ServerVersion() string
+		SeededRows() int64
+	}); ok {
+		fmt.Printf("✅ Connected to %s at %s (database: %s, pool: %d)\n",
+			chAdapter.ServerVersion(), target, config.Connection.Database, config.GetPoolSize())
+		if seeded := chAdapter.SeededRows(); seeded > 0 {
+			fmt.Printf("🛠️  Seeded %s with %d rows\n", specific.Table, seeded)
+		}
+	}
+
+	fmt.Printf("🚀 Starting ClickHouse performance test...\n")
+	fmt.Printf("Test Case: %s\n", config.BenchMark.TestCase)
+	fmt.Printf("Table: %s, Compression: %s\n", specific.Table, config.Connection.Compression)
+	if config.BenchMark.TestCase != chConfig.TestCaseQuery {
+		fmt.Printf("Batch Size: %d rows, Payload: %d bytes\n", specific.BatchSize, specific.PayloadSize)
+	}
+	if config.BenchMark.RunsQueries() {
+		if config.BenchMark.TestCase == chConfig.TestCaseMixed {
+			fmt.Printf("Query Percent: %d%%\n", config.BenchMark.ReadPercent)
+		}
+		names := make([]string, 0, len(specific.GetQueries()))
+		for _, query := range specific.GetQueries() {
+			names = append(names, fmt.Sprintf("%s(%d)", query.Name, query.Weight))
+		}
+		fmt.Printf("Queries: %s\n", strings.Join(names, ", "))
+	}
+	if config.BenchMark.Rate > 0 {
+		fmt.Printf("Rate Limit: %d ops/sec\n", config.BenchMark.Rate)
+	}
+	fmt.Printf("Operations: %d, Concurrency: %d\n", config.BenchMark.Total, config.BenchMark.Parallels)
+
+	if err := h.runPerformanceTest(ctx, adapter, config, metricsCollector, opts); err != nil {
+		return fmt.Errorf("performance test failed: %w", err)
+	}
+
+	return h.generateReport(metricsCollector, opts)
+}
+
+// GetHelp 获取帮助信息
+func (h *ClickHouseCommandHandler) GetHelp() string {
+	return `ClickHouse Insert and Query Performance Testing
+
+USAGE:
+  abc-runner clickhouse [options]
+
+DESCRIPTION:
+  Stream batched inserts into ClickHouse over the native TCP protocol and
+  run templated analytical queries against the same table. Inserts report
+  rows/sec and the compressed bytes/sec actually sent on the wire; queries
+  report latency percentiles per template together with the rows and bytes
+  the server scanned.
+
+  The test table has the columns
+    id UInt64, ts DateTime, user_id UInt32, event String,
+    value Float64, payload String
+  and is created as a MergeTree ordered by (user_id, ts) unless
+  --no-setup is given. A custom --table must have the same columns.
+
+TEST CASES:
+  insert   Insert --batch-size rows per operation
+  query    Run the query templates, chosen by weight
+  mixed    Queries and inserts by --read-percent
+
+QUERY TEMPLATES:
+  {{table}} is replaced with the test table; every other placeholder of the
+  payload templates is rendered per operation, e.g.
+    --query "by_user=SELECT count() FROM {{table}} WHERE user_id = {{randInt 0 9999}}"
+  Without --query four built-in templates run: count_by_event,
+  user_summary, top_users and value_quantiles.
+
+OPTIONS:
+  --help                   Show this help message
+  --host HOST, -h HOST     Server host (default: localhost)
+  --port PORT, -p PORT     Native protocol port (default: 9000)
+  --user USER, -u USER     User name (default: default)
+  --password PASSWORD      Password
+  --database NAME, -d NAME Database (default: default)
+  --secure                 Connect with TLS (usually port 9440)
+  --insecure, -k           Connect with TLS without verifying the certificate
+  --compression MODE       lz4 or none (default: lz4)
+  --pool-size N            Connection pool size (default: same as -c)
+  --timeout DURATION       Connect and per-operation timeout (default: 30s)
+  -t, --test-case CASE     insert, query or mixed (default: insert)
+  --table NAME             Test table (default: abc_runner_events)
+  --batch-size N           Rows per insert (default: 10000)
+  --payload-size N         Bytes in the payload column (default: 64)
+  --users N                user_id is drawn from [0, N) (default: 10000)
+  --read-percent N         Queries in the mixed case, 0-100 (default: 20)
+  --query [NAME=]SQL       Query template, repeatable; the name defaults to
+                           query-N
+  --seed-rows N            Rows inserted before query tests when the table
+                           is empty (default: 1000000)
+  --no-setup               Do not create or seed the table
+  --rate N                 Cap the operation rate at N ops/sec (default: unlimited)
+  -n COUNT                 Total operations (default: 1000)
+  -c COUNT                 Concurrent workers (default: 4)
+  --duration DURATION      Run for a fixed duration instead of -n
+
+NOTES:
+  Each insert is one INSERT ... VALUES statement carrying a single native
+  block, so -n 1000 with --batch-size 10000 inserts ten million rows. Small
+  batches from many workers create many parts and eventually fail with
+  TOO_MANY_PARTS, which shows up in the per-operation error codes.
+
+EXAMPLES:
+  abc-runner clickhouse --help
+  abc-runner clickhouse -h localhost -t insert --batch-size 50000 -c 8 --duration 60s
+  abc-runner clickhouse -h ch -t query --seed-rows 10000000 -c 16 -n 5000
+  abc-runner clickhouse -h ch -t mixed --read-percent 10 --compression none
+  abc-runner clickhouse -h ch -t query --query "SELECT uniq(user_id) FROM {{table}}"` + configLayersHelp + runOptionsHelp + "\n"
+}
+
+// parseArgs 解析命令行参数
+func (h *ClickHouseCommandHandler) parseArgs(args []string) (*chConfig.ClickHouseConfig, error) {
+	config := chConfig.NewDefaultClickHouseConfig()
+	specific := &config.ClickHouseSpecific
+
+	// 依次应用--config文件、ABC_CLICKHOUSE_*环境变量与--set，专用参数随后覆盖
+	if err := applyConfigLayers("clickhouse", args, config); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(args); i++ {
+		// 无参数的开关
+		switch args[i] {
+		case "--no-setup":
+			specific.Setup = false
+			continue
+		case "--secure":
+			config.Connection.Secure = true
+			continue
+		case "--insecure", "-k":
+			config.Connection.Secure = true
+			config.Connection.InsecureSkipVerify = true
+			continue
+		}
+
+		if i+1 >= len(args) {
+			break
+		}
+		value := args[i+1]
+		var err error
+
+		switch args[i] {
+		case "--host", "-h":
+			if !looksLikeHostname(value) {
+				continue
+			}
+			config.Connection.Address = value
+		case "--port", "-p":
+			config.Connection.Port, err = strconv.Atoi(value)
+		case "--user", "-u":
+			config.Connection.User = value
+		case "--password":
+			config.Connection.Password = value
+		case "--database", "-d":
+			config.Connection.Database = value
+		case "--compression":
+			config.Connection.Compression = strings.ToLower(value)
+		case "--pool-size":
+			config.Connection.PoolSize, err = strconv.Atoi(value)
+		case "--timeout":
+			config.Connection.Timeout, err = time.ParseDuration(value)
+		case "--test-case", "-t":
+			config.BenchMark.TestCase = strings.ToLower(value)
+		case "--table":
+			specific.Table = value
+		case "--batch-size":
+			specific.BatchSize, err = strconv.Atoi(value)
+		case "--payload-size":
+			specific.PayloadSize, err = strconv.Atoi(value)
+		case "--users":
+			specific.Users, err = strconv.Atoi(value)
+		case "--read-percent":
+			config.BenchMark.ReadPercent, err = strconv.Atoi(value)
+		case "--query":
+			specific.Queries = append(specific.Queries, parseQueryTemplate(value, len(specific.Queries)+1))
+		case "--seed-rows":
+			specific.SeedRows, err = strconv.Atoi(value)
+		case "--rate":
+			config.BenchMark.Rate, err = strconv.Atoi(value)
+		case "-n":
+			config.BenchMark.Total, err = strconv.Atoi(value)
+		case "-c":
+			config.BenchMark.Parallels, err = strconv.Atoi(value)
+		case "--duration":
+			config.BenchMark.Duration, err = time.ParseDuration(value)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", args[i], err)
+		}
+		i++
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// parseQueryTemplate 解析"[NAME=]SQL"形式的查询模板，名称只能由字母、数字、下划线与短横线组成
+func parseQueryTemplate(value string, index int) chConfig.QueryTemplate {
+	if name, sql, ok := strings.Cut(value, "="); ok && isTemplateName(name) {
+		return chConfig.QueryTemplate{Name: name, SQL: sql, Weight: 1}
+	}
+	return chConfig.QueryTemplate{Name: fmt.Sprintf("query-%d", index), SQL: value, Weight: 1}
+}
+
+func isTemplateName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !(r == '_' || r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// runPerformanceTest 运行ClickHouse性能测试
+func (h *ClickHouseCommandHandler) runPerformanceTest(
+	ctx context.Context,
+	adapter interfaces.ProtocolAdapter,
+	config *chConfig.ClickHouseConfig,
+	collector *metrics.BaseCollector[map[string]interface{}],
+	opts *runOptions,
+) error {
+	factory := operations.NewOperationFactory(config)
+	engine := execution.NewExecutionEngine(adapter, collector, factory)
+	engine.SetMaxWorkers(config.BenchMark.Parallels)
+	opts.applyToEngine(engine, collector)
+
+	testStartTime := time.Now()
+	result, err := engine.RunBenchmark(ctx, &config.BenchMark)
+	if err != nil {
+		return fmt.Errorf("benchmark execution failed: %w", err)
+	}
+	opts.finishRun()
+	actualTestDuration := time.Since(testStartTime)
+
+	fmt.Printf("\n📊 Execution Results:\n")
+	fmt.Printf("Total Jobs: %d\n", result.TotalJobs)
+	fmt.Printf("Completed Jobs: %d\n", result.CompletedJobs)
+	fmt.Printf("Success Jobs: %d\n", result.SuccessJobs)
+	fmt.Printf("Failed Jobs: %d\n", result.FailedJobs)
+	fmt.Printf("Actual Test Duration: %v\n", actualTestDuration)
+
+	protocolMetrics := map[string]interface{}{
+		"protocol":         "clickhouse",
+		"test_type":        "performance",
+		"test_case":        config.BenchMark.TestCase,
+		"target":           config.Connection.GetAddresses()[0],
+		"table":            config.ClickHouseSpecific.Table,
+		"batch_size":       config.ClickHouseSpecific.BatchSize,
+		"compression":      config.Connection.Compression,
+		"rate_limit":       config.BenchMark.Rate,
+		"actual_duration":  actualTestDuration,
+		"execution_result": result,
+	}
+
+	if statsAdapter, ok := adapter.(interface {
+		GetOperationStats() []operations.OperationStats
+		GetTransferStats() *operations.TransferStats
+		GetPoolStats() *connection.PoolStats
+		ServerVersion() string
+	}); ok {
+		protocolMetrics["server_version"] = statsAdapter.ServerVersion()
+		if stats := statsAdapter.GetOperationStats(); len(stats) > 0 {
+			printClickHouseOperationStats(stats, actualTestDuration)
+			protocolMetrics["operation_stats"] = stats
+		}
+		if transfer := statsAdapter.GetTransferStats(); transfer != nil {
+			seconds := actualTestDuration.Seconds()
+			printClickHouseTransferStats(transfer, seconds)
+			protocolMetrics["transfer"] = *transfer
+			protocolMetrics["insert_rows_per_sec"] = float64(transfer.InsertedRows) / seconds
+			protocolMetrics["compressed_bytes_per_sec"] = float64(transfer.CompressedBytes) / seconds
+		}
+		if pool := statsAdapter.GetPoolStats(); pool != nil {
+			fmt.Printf("Pool: %d connection(s), avg wait %v", pool.Size, pool.AvgWait)
+			if pool.Reconnects > 0 {
+				fmt.Printf(", %d reconnect(s)", pool.Reconnects)
+			}
+			fmt.Println()
+			protocolMetrics["pool_stats"] = *pool
+		}
+	}
+
+	collector.UpdateProtocolMetrics(protocolMetrics)
+	return nil
+}
+
+// printClickHouseOperationStats 打印按操作类型的统计表
+func printClickHouseOperationStats(stats []operations.OperationStats, duration time.Duration) {
+	fmt.Printf("\n📋 Per-Operation Statistics:\n")
+	fmt.Printf("  %-20s %10s %10s %8s %10s %10s %10s %10s %12s\n",
+		"OPERATION", "COUNT", "OPS/SEC", "ERR%", "AVG", "P50", "P95", "P99", "ROWS")
+	for _, s := range stats {
+		fmt.Printf("  %-20s %10d %10.1f %8.2f %10v %10v %10v %10v %12d\n",
+			s.Type, s.Count, float64(s.Count)/duration.Seconds(), s.ErrorRate,
+			s.Latency.Average, s.Latency.P50, s.Latency.P95, s.Latency.P99, s.Volume)
+		for code, n := range s.ErrorCodes {
+			fmt.Printf("  %-20s   %s: %d\n", "", code, n)
+		}
+	}
+}
+
+// printClickHouseTransferStats 打印插入与查询的数据量与速率
+func printClickHouseTransferStats(stats *operations.TransferStats, seconds float64) {
+	if stats.InsertedRows > 0 {
+		fmt.Printf("\n📥 Inserts:\n")
+		fmt.Printf("  Rows: %d (%.0f rows/sec)\n", stats.InsertedRows, float64(stats.InsertedRows)/seconds)
+		fmt.Printf("  Bytes Sent: %d compressed, %d raw (%.0f compressed bytes/sec, %.2f raw MB/sec)",
+			stats.CompressedBytes, stats.InsertedBytes,
+			float64(stats.CompressedBytes)/seconds, float64(stats.InsertedBytes)/seconds/1024/1024)
+		if stats.CompressedBytes > 0 {
+			fmt.Printf(", ratio %.2f", float64(stats.InsertedBytes)/float64(stats.CompressedBytes))
+		}
+		fmt.Println()
+	}
+	if stats.ReadRows > 0 || stats.ResultRows > 0 {
+		fmt.Printf("\n📤 Queries:\n")
+		fmt.Printf("  Scanned: %d rows, %d bytes (%.0f rows/sec, %.2f MB/sec)\n",
+			stats.ReadRows, stats.ReadBytes, float64(stats.ReadRows)/seconds, float64(stats.ReadBytes)/seconds/1024/1024)
+		fmt.Printf("  Returned: %d rows, %d bytes received\n", stats.ResultRows, stats.ReceivedBytes)
+	}
+}
+
+// generateReport 生成ClickHouse测试报告
+func (h *ClickHouseCommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
+	snapshot := collector.Snapshot()
+
+	// 使用实际测试时间修正吞吐量
+	if duration, ok := snapshot.Protocol["actual_duration"].(time.Duration); ok && duration > 0 {
+		snapshot.Core.Duration = duration
+		seconds := duration.Seconds()
+		snapshot.Core.Throughput.RPS = float64(snapshot.Core.Operations.Total) / seconds
+		snapshot.Core.Throughput.ReadRPS = float64(snapshot.Core.Operations.Read) / seconds
+		snapshot.Core.Throughput.WriteRPS = float64(snapshot.Core.Operations.Write) / seconds
+		snapshot.Core.Throughput.ReadMBps, snapshot.Core.Throughput.WriteMBps = metrics.Bandwidth(snapshot.Core.Operations, duration)
+	}
+
+	fmt.Printf("\n📊 ClickHouse Performance Metrics:\n")
+	fmt.Printf("=====================================\n")
+	fmt.Printf("  Total Operations: %d\n", snapshot.Core.Operations.Total)
+	fmt.Printf("  Successful: %d\n", snapshot.Core.Operations.Success)
+	fmt.Printf("  Failed: %d\n", snapshot.Core.Operations.Failed)
+	fmt.Printf("  Success Rate: %.2f%%\n", snapshot.Core.Operations.Rate)
+	fmt.Printf("  Latency avg/p95/p99: %v/%v/%v\n",
+		snapshot.Core.Latency.Average, snapshot.Core.Latency.P95, snapshot.Core.Latency.P99)
+	fmt.Printf("  Throughput: %.2f ops/sec (query %.2f, insert %.2f)\n",
+		snapshot.Core.Throughput.RPS, snapshot.Core.Throughput.ReadRPS, snapshot.Core.Throughput.WriteRPS)
+	fmt.Printf("=====================================\n")
+
+	// 存在快照接收器时（如分布式agent模式）交由接收器处理，不生成本地报告
+	if opts.publishSnapshot(snapshot) {
+		return nil
+	}
+
+	report := reporting.ConvertFromMetricsSnapshot(snapshot)
+	reportConfig := reporting.NewStandardReportConfig("clickhouse")
+	opts.applyToReport(snapshot, report, reportConfig)
+	generator := reporting.NewReportGenerator(reportConfig)
+	if err := generator.Generate(report); err != nil {
+		return err
+	}
+	return opts.resultError(report)
+}
+
+// GetProtocolName 获取协议名称
+func (h *ClickHouseCommandHandler) GetProtocolName() string {
+	return h.protocolName
+}
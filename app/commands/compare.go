@@ -0,0 +1,203 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"abc-runner/app/reporting"
+)
+
+// CompareCommandHandler 基线对比与回归检测命令处理器
+type CompareCommandHandler struct{}
+
+// NewCompareCommandHandler 创建基线对比命令处理器
+func NewCompareCommandHandler() *CompareCommandHandler {
+	return &CompareCommandHandler{}
+}
+
+// compareArgs 对比命令参数
+type compareArgs struct {
+	baseline   string
+	current    string
+	thresholds reporting.RegressionThresholds
+	jsonOutput bool
+}
+
+// Execute 执行基线对比
+func (h *CompareCommandHandler) Execute(ctx context.Context, args []string) error {
+	for _, arg := range args {
+		if arg == "--help" || arg == "-h" || arg == "help" {
+			fmt.Println(h.GetHelp())
+			return nil
+		}
+	}
+
+	parsed, err := h.parseArgs(args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	baseline, err := reporting.LoadStructuredReport(parsed.baseline)
+	if err != nil {
+		return err
+	}
+	current, err := reporting.LoadStructuredReport(parsed.current)
+	if err != nil {
+		return err
+	}
+
+	result := reporting.CompareReports(baseline, current, parsed.thresholds)
+
+	if parsed.jsonOutput {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode comparison: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		h.printComparison(parsed, baseline, current, result)
+	}
+
+	if regressions := result.Regressions(); len(regressions) > 0 {
+		names := make([]string, len(regressions))
+		for i, regression := range regressions {
+			names[i] = regression.Metric
+		}
+		return &ExitError{
+			Code: ExitCodeRegression,
+			Err:  fmt.Errorf("performance regression detected in %d metric(s): %v", len(regressions), names),
+		}
+	}
+	return nil
+}
+
+// printComparison 输出对比表格
+func (h *CompareCommandHandler) printComparison(parsed *compareArgs, baseline, current *reporting.StructuredReport, result *reporting.ComparisonResult) {
+	fmt.Printf("📊 Comparing %s (baseline) -> %s (current)\n", parsed.baseline, parsed.current)
+	fmt.Printf("   Baseline: protocol=%s, ops=%d, generated=%s\n",
+		result.BaselineProtocol, baseline.Metrics.CoreOperations.TotalOperations,
+		baseline.Context.ExecutionContext.GeneratedAt.Format(time.RFC3339))
+	fmt.Printf("   Current:  protocol=%s, ops=%d, generated=%s\n",
+		result.CurrentProtocol, current.Metrics.CoreOperations.TotalOperations,
+		current.Context.ExecutionContext.GeneratedAt.Format(time.RFC3339))
+	if result.BaselineProtocol != result.CurrentProtocol {
+		fmt.Printf("⚠️  Reports were produced by different protocols (%s vs %s)\n",
+			result.BaselineProtocol, result.CurrentProtocol)
+	}
+	fmt.Println()
+	fmt.Print(reporting.FormatComparisonTable(result))
+	fmt.Println()
+
+	if result.HasRegression() {
+		fmt.Printf("❌ Regression detected\n")
+	} else {
+		fmt.Printf("✅ No regression detected\n")
+	}
+}
+
+// parseArgs 解析命令行参数
+func (h *CompareCommandHandler) parseArgs(args []string) (*compareArgs, error) {
+	parsed := &compareArgs{thresholds: reporting.DefaultRegressionThresholds()}
+
+	var files []string
+	for i := 0; i < len(args); i++ {
+		var err error
+		switch args[i] {
+		case "--json":
+			parsed.jsonOutput = true
+			continue
+		case "--max-throughput-drop", "--max-latency-increase", "--max-error-rate-increase",
+			"--min-latency-delta", "--threshold":
+		default:
+			files = append(files, args[i])
+			continue
+		}
+
+		if i+1 >= len(args) {
+			return nil, fmt.Errorf("missing value for %s", args[i])
+		}
+		value := args[i+1]
+
+		switch args[i] {
+		case "--max-throughput-drop":
+			parsed.thresholds.MaxThroughputDrop, err = parsePercent(value)
+		case "--max-latency-increase":
+			parsed.thresholds.MaxLatencyIncrease, err = parsePercent(value)
+		case "--max-error-rate-increase":
+			parsed.thresholds.MaxErrorRateIncrease, err = parsePercent(value)
+		case "--min-latency-delta":
+			parsed.thresholds.MinLatencyDelta, err = time.ParseDuration(value)
+		case "--threshold":
+			var metric string
+			var threshold float64
+			metric, threshold, err = reporting.ParseThresholdOverride(value)
+			if err == nil {
+				parsed.thresholds.Overrides[metric] = threshold
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", args[i], err)
+		}
+		i++
+	}
+
+	if len(files) != 2 {
+		return nil, fmt.Errorf("expected BASELINE and CURRENT report files, got %d argument(s)", len(files))
+	}
+	parsed.baseline, parsed.current = files[0], files[1]
+	return parsed, nil
+}
+
+// parsePercent 解析非负百分比，允许带%后缀
+func parsePercent(value string) (float64, error) {
+	if len(value) > 0 && value[len(value)-1] == '%' {
+		value = value[:len(value)-1]
+	}
+	percent, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	if percent < 0 {
+		return 0, fmt.Errorf("must be non-negative")
+	}
+	return percent, nil
+}
+
+// GetHelp 获取帮助信息
+func (h *CompareCommandHandler) GetHelp() string {
+	return `Baseline Comparison and Regression Detection
+
+USAGE:
+  abc-runner compare [options] BASELINE.json CURRENT.json
+
+DESCRIPTION:
+  Compare two JSON reports written by abc-runner (reports/*.json) and flag
+  regressions in throughput, average and percentile latency, and error rate.
+  Prints a diff table and exits with status 1 when any metric regresses, so
+  it can gate CI pipelines.
+
+OPTIONS:
+  --help, -h                      Show this help message
+  --max-throughput-drop PCT       Allowed throughput drop in percent (default: 5)
+  --max-latency-increase PCT      Allowed increase of avg/p50/p90/p95/p99/p999
+                                  latency in percent (default: 10)
+  --max-error-rate-increase PP    Allowed error rate increase in percentage
+                                  points (default: 1)
+  --min-latency-delta DURATION    Ignore latency increases smaller than this
+                                  absolute amount, e.g. 200us (default: 0)
+  --threshold METRIC=VALUE        Per-metric override; may be repeated.
+                                  Metrics: throughput, avg, p50, p90, p95, p99,
+                                  p999, error_rate
+  --json                          Print the comparison as JSON
+
+  Metrics missing from the baseline (e.g. p999 in older reports) are skipped.
+
+EXAMPLES:
+  abc-runner compare baseline.json reports/redis_report.json
+  abc-runner compare --max-latency-increase 5 --threshold p999=25 base.json new.json
+  abc-runner compare --min-latency-delta 100us --json base.json new.json
+`
+}
@@ -0,0 +1,263 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	chConfig "abc-runner/app/adapters/clickhouse/config"
+	dnsConfig "abc-runner/app/adapters/dns/config"
+	esConfig "abc-runner/app/adapters/elasticsearch/config"
+	etcdConfig "abc-runner/app/adapters/etcd/config"
+	grpcConfig "abc-runner/app/adapters/grpc/config"
+	httpConfig "abc-runner/app/adapters/http/config"
+	kafkaConfig "abc-runner/app/adapters/kafka/config"
+	mongoConfig "abc-runner/app/adapters/mongodb/config"
+	mysqlConfig "abc-runner/app/adapters/mysql/config"
+	otlpConfig "abc-runner/app/adapters/otlp/config"
+	pgConfig "abc-runner/app/adapters/postgres/config"
+	pulsarConfig "abc-runner/app/adapters/pulsar/config"
+	rabbitConfig "abc-runner/app/adapters/rabbitmq/config"
+	redisConfig "abc-runner/app/adapters/redis/config"
+	rwConfig "abc-runner/app/adapters/remotewrite/config"
+	s3Config "abc-runner/app/adapters/s3/config"
+	smtpConfig "abc-runner/app/adapters/smtp/config"
+	snmpConfig "abc-runner/app/adapters/snmp/config"
+	syslogConfig "abc-runner/app/adapters/syslog/config"
+	tcpConfig "abc-runner/app/adapters/tcp/config"
+	udpConfig "abc-runner/app/adapters/udp/config"
+	wsConfig "abc-runner/app/adapters/websocket/config"
+	coreConfig "abc-runner/app/core/config"
+	"abc-runner/app/core/config/schema"
+	"abc-runner/app/core/metrics"
+)
+
+// ConfigCommandHandler 配置文件工具命令处理器
+type ConfigCommandHandler struct{}
+
+// NewConfigCommandHandler 创建配置文件工具命令处理器
+func NewConfigCommandHandler() *ConfigCommandHandler {
+	return &ConfigCommandHandler{}
+}
+
+// configArgs 配置命令参数
+type configArgs struct {
+	action string
+	names  []string
+	out    string
+	all    bool
+}
+
+// schemaTarget 一种配置文件：顶层键与提供默认值的配置结构体
+type schemaTarget struct {
+	section  string // 文件的顶层键，为空时结构体描述整个文件
+	defaults func() interface{}
+}
+
+// schemaTargets 可生成Schema的配置文件，协议配置以协议名为顶层键
+var schemaTargets = map[string]schemaTarget{
+	"redis":         {"redis", func() interface{} { return redisConfig.NewDefaultRedisConfig() }},
+	"http":          {"http", func() interface{} { return httpConfig.LoadDefaultHttpConfig() }},
+	"kafka":         {"kafka", func() interface{} { return kafkaConfig.LoadDefaultKafkaConfig() }},
+	"grpc":          {"grpc", func() interface{} { return grpcConfig.NewDefaultGRPCConfig() }},
+	"tcp":           {"tcp", func() interface{} { return tcpConfig.NewDefaultTCPConfig() }},
+	"udp":           {"udp", func() interface{} { return udpConfig.NewDefaultUDPConfig() }},
+	"websocket":     {"websocket", func() interface{} { return wsConfig.NewDefaultWebSocketConfig() }},
+	"otlp":          {"otlp", func() interface{} { return otlpConfig.NewDefaultOTLPConfig() }},
+	"remotewrite":   {"remotewrite", func() interface{} { return rwConfig.NewDefaultRemoteWriteConfig() }},
+	"snmp":          {"snmp", func() interface{} { return snmpConfig.NewDefaultSNMPConfig() }},
+	"syslog":        {"syslog", func() interface{} { return syslogConfig.NewDefaultSyslogConfig() }},
+	"postgres":      {"postgres", func() interface{} { return pgConfig.NewDefaultPostgresConfig() }},
+	"mysql":         {"mysql", func() interface{} { return mysqlConfig.NewDefaultMySQLConfig() }},
+	"mongodb":       {"mongodb", func() interface{} { return mongoConfig.NewDefaultMongoConfig() }},
+	"rabbitmq":      {"rabbitmq", func() interface{} { return rabbitConfig.NewDefaultRabbitMQConfig() }},
+	"pulsar":        {"pulsar", func() interface{} { return pulsarConfig.NewDefaultPulsarConfig() }},
+	"elasticsearch": {"elasticsearch", func() interface{} { return esConfig.NewDefaultElasticsearchConfig() }},
+	"s3":            {"s3", func() interface{} { return s3Config.NewDefaultS3Config() }},
+	"dns":           {"dns", func() interface{} { return dnsConfig.NewDefaultDNSConfig() }},
+	"etcd":          {"etcd", func() interface{} { return etcdConfig.NewDefaultEtcdConfig() }},
+	"clickhouse":    {"clickhouse", func() interface{} { return chConfig.NewDefaultClickHouseConfig() }},
+	"smtp":          {"smtp", func() interface{} { return smtpConfig.NewDefaultSMTPConfig() }},
+	"core":          {"", func() interface{} { return coreConfig.NewUnifiedCoreConfigLoader().GetDefaultConfig() }},
+	"metrics":       {"", func() interface{} { return metrics.DefaultMetricsConfig() }},
+}
+
+// schemaNames 可生成Schema的配置名称
+func schemaNames() []string {
+	names := make([]string, 0, len(schemaTargets))
+	for name := range schemaTargets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Execute 执行配置子命令
+func (h *ConfigCommandHandler) Execute(ctx context.Context, args []string) error {
+	for _, arg := range args {
+		if arg == "--help" || arg == "-h" || arg == "help" {
+			fmt.Println(h.GetHelp())
+			return nil
+		}
+	}
+
+	parsed, err := h.parseArgs(args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+	return h.exportSchemas(parsed)
+}
+
+// exportSchemas 生成Schema，输出到标准输出、文件或目录（--all时每种配置一个文件）
+func (h *ConfigCommandHandler) exportSchemas(parsed *configArgs) error {
+	if !parsed.all {
+		data, err := marshalSchema(parsed.names[0])
+		if err != nil {
+			return err
+		}
+		if parsed.out == "" {
+			fmt.Println(string(data))
+			return nil
+		}
+		if err := writeSchema(parsed.out, data); err != nil {
+			return err
+		}
+		fmt.Printf("✅ %s schema written to %s\n", parsed.names[0], parsed.out)
+		return nil
+	}
+
+	if err := os.MkdirAll(parsed.out, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", parsed.out, err)
+	}
+	for _, name := range parsed.names {
+		data, err := marshalSchema(name)
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(parsed.out, name+".schema.json")
+		if err := writeSchema(path, data); err != nil {
+			return err
+		}
+		fmt.Printf("✅ %s\n", path)
+	}
+	return nil
+}
+
+// marshalSchema 生成指定配置文件的Schema JSON
+func marshalSchema(name string) ([]byte, error) {
+	target := schemaTargets[name]
+	title := fmt.Sprintf("abc-runner %s configuration (config/%s.yaml)", name, name)
+	data, err := json.MarshalIndent(schema.ForFile(title, target.section, target.defaults()), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s schema: %w", name, err)
+	}
+	return data, nil
+}
+
+// writeSchema 写入Schema文件
+func writeSchema(path string, data []byte) error {
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// parseArgs 解析命令行参数
+func (h *ConfigCommandHandler) parseArgs(args []string) (*configArgs, error) {
+	parsed := &configArgs{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--protocol", "--out":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			if arg == "--out" {
+				parsed.out = args[i]
+			} else {
+				parsed.names = append(parsed.names, strings.ToLower(args[i]))
+			}
+		case "--all":
+			parsed.all = true
+		default:
+			switch {
+			case parsed.action == "":
+				parsed.action = arg
+			case !strings.HasPrefix(arg, "-"):
+				parsed.names = append(parsed.names, strings.ToLower(arg))
+			default:
+				return nil, fmt.Errorf("unknown option %s", arg)
+			}
+		}
+	}
+
+	if parsed.action != "schema" {
+		if parsed.action == "" {
+			return nil, fmt.Errorf("missing subcommand (expected schema)")
+		}
+		return nil, fmt.Errorf("unknown subcommand %q (expected schema)", parsed.action)
+	}
+	if parsed.all {
+		if len(parsed.names) > 0 {
+			return nil, fmt.Errorf("--all cannot be combined with --protocol")
+		}
+		if parsed.out == "" {
+			return nil, fmt.Errorf("--all needs --out DIR")
+		}
+		parsed.names = schemaNames()
+		return parsed, nil
+	}
+	if len(parsed.names) != 1 {
+		return nil, fmt.Errorf("expected one --protocol (one of %s) or --all", strings.Join(schemaNames(), ", "))
+	}
+	if _, ok := schemaTargets[parsed.names[0]]; !ok {
+		return nil, fmt.Errorf("unknown protocol %q (expected one of %s)", parsed.names[0], strings.Join(schemaNames(), ", "))
+	}
+	return parsed, nil
+}
+
+// GetHelp 获取帮助信息
+func (h *ConfigCommandHandler) GetHelp() string {
+	return `Configuration File Tools
+
+USAGE:
+  abc-runner config schema --protocol NAME [--out FILE]
+  abc-runner config schema --all --out DIR
+
+DESCRIPTION:
+  Prints the JSON Schema (draft 2020-12) of a configuration file, generated
+  from the Go structs the file is loaded into, so it always matches the
+  running version. Editors use it for completion and inline errors, and CI
+  can validate config files with any JSON Schema validator.
+
+  Field names follow the YAML loader: unknown keys are rejected, durations
+  must be written as strings such as 30s, 1m30s or 500ms, and the built-in
+  defaults are included as "default" values. Protocol files are described
+  with their top-level section (redis:, http:, ...).
+
+NAMES:
+  ` + strings.Join(schemaNames(), ", ") + `
+  (core and metrics describe config/core.yaml and config/metrics.yaml)
+
+OPTIONS:
+  --protocol NAME   Configuration to describe; may also be given as an argument
+  --out PATH        Write to PATH instead of standard output; with --all, the
+                    directory receiving NAME.schema.json for every configuration
+  --all             Export the schemas of all configurations
+
+EDITOR SETUP:
+  With the YAML language server (VS Code YAML extension, Neovim, ...) add a
+  first line to the config file:
+    # yaml-language-server: $schema=../schema/redis.schema.json
+
+EXAMPLES:
+  abc-runner config schema --protocol redis
+  abc-runner config schema --protocol http --out http.schema.json
+  abc-runner config schema --all --out schema/
+  check-jsonschema --schemafile schema/kafka.schema.json config/kafka.yaml`
+}
@@ -0,0 +1,373 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"abc-runner/app/core/config/schema"
+	"abc-runner/app/core/config/yamlfile"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 协议配置的来源，优先级从低到高：
+//
+//	内置默认值 < --config文件 < ABC_<协议>_<路径>环境变量 < --set 路径=值 < 专用命令行参数
+//
+// 配置文件、环境变量与--set都按配置结构体的YAML路径寻址，因此每个选项都可以通过三者设置；
+// 专用参数（如-n、--host）在此之后由各协议的参数解析写入，优先级最高。
+
+// envPrefix 协议配置环境变量的公共前缀
+const envPrefix = "ABC_"
+
+// secretKeyPattern --print-config输出时隐藏其值的键
+var secretKeyPattern = regexp.MustCompile(`(?i)(password|passwd|secret|token|api_key|apikey|private_key|credentials)`)
+
+// applyConfigLayers 将--config文件、环境变量与--set依次写入协议配置，config须为指向配置结构体的指针
+// 应在各协议设置完默认值之后、解析专用参数之前调用
+func applyConfigLayers(protocol string, args []string, config interface{}) error {
+	files, sets, err := configLayerArgs(args)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := decodeConfigFile(protocol, file, config); err != nil {
+			return err
+		}
+	}
+	return applyConfigOverrides(protocol, sets, config)
+}
+
+// applyConfigOverrides 将环境变量与--set写入已按配置文件加载的config，供自行读取--config的协议使用
+func applyConfigOverrides(protocol string, sets []string, config interface{}) error {
+	if err := applyConfigEnv(protocol, config); err != nil {
+		return err
+	}
+	for _, set := range sets {
+		// run段的共享运行选项由parseRunOptions应用
+		if isRunConfigSet(set) {
+			continue
+		}
+		if err := applyConfigSet(set, config); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// configLayerArgs 取出--config与--set参数
+func configLayerArgs(args []string) (files, sets []string, err error) {
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--config", "--set":
+			if i+1 >= len(args) {
+				return nil, nil, fmt.Errorf("missing value for %s", args[i])
+			}
+			if args[i] == "--config" {
+				files = append(files, args[i+1])
+			} else {
+				sets = append(sets, args[i+1])
+			}
+			i++
+		}
+	}
+	return files, sets, nil
+}
+
+// decodeConfigFile 读取配置文件写入config：取protocol段，文件没有该段时整个文件即协议配置；未知的键报错
+// 段中的run键为共享运行选项，由parseRunOptions读取
+func decodeConfigFile(protocol, path string, config interface{}) error {
+	section, err := readConfigSection(protocol, path)
+	if err != nil {
+		return err
+	}
+	if section != nil {
+		section.Content = withoutKey(section.Content, runConfigKey)
+	}
+	return decodeConfigNode(path, section, config)
+}
+
+// decodeRunConfigFile 读取配置文件协议段中的run键写入section，没有该键时不做修改
+func decodeRunConfigFile(protocol, path string, section *runConfigSection) error {
+	node, err := readConfigSection(protocol, path)
+	if err != nil || node == nil {
+		return err
+	}
+	run := &yaml.Node{Kind: yaml.MappingNode}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == runConfigKey {
+			run.Content = append(run.Content, node.Content[i], node.Content[i+1])
+		}
+	}
+	return decodeConfigNode(path, run, section)
+}
+
+// readConfigSection 读取配置文件中protocol段的映射节点，文件没有该段时返回整个文件，文件为空时返回nil
+func readConfigSection(protocol, path string) (*yaml.Node, error) {
+	data, err := yamlfile.Read(path)
+	if err != nil {
+		return nil, err
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	if len(document.Content) == 0 {
+		return nil, nil
+	}
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("failed to parse config %s: expected a mapping at the top level", path)
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == protocol {
+			return root.Content[i+1], nil
+		}
+	}
+	return root, nil
+}
+
+// decodeConfigNode 将配置节点按YAML严格解码到config
+func decodeConfigNode(path string, node *yaml.Node, config interface{}) error {
+	if node == nil {
+		return nil
+	}
+	data, err := yaml.Marshal(node)
+	if err != nil {
+		return fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	if err := decodeStrict(data, config); err != nil {
+		return fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return nil
+}
+
+// withoutKey 返回去掉key及其值的映射节点内容
+func withoutKey(content []*yaml.Node, key string) []*yaml.Node {
+	kept := make([]*yaml.Node, 0, len(content))
+	for i := 0; i+1 < len(content); i += 2 {
+		if content[i].Value != key {
+			kept = append(kept, content[i], content[i+1])
+		}
+	}
+	return kept
+}
+
+// applyConfigEnv 将已设置的ABC_<协议>_<路径>环境变量写入config，变量名由YAML路径大写、以下划线连接得到，
+// 如ABC_REDIS_POOL_POOL_SIZE对应redis配置的pool.pool_size；值按YAML解析，列表可写作[a, b]
+func applyConfigEnv(protocol string, config interface{}) error {
+	overlay := &yaml.Node{Kind: yaml.MappingNode}
+	var names []string
+	for _, path := range configLeafPaths(config) {
+		name := configEnvName(protocol, path)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		setOverlay(overlay, path, value)
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	data, err := yaml.Marshal(overlay)
+	if err != nil {
+		return fmt.Errorf("failed to apply environment variables %s: %w", strings.Join(names, ", "), err)
+	}
+	if err := decodeStrict(data, config); err != nil {
+		return fmt.Errorf("invalid value in environment variables %s: %w", strings.Join(names, ", "), err)
+	}
+	return nil
+}
+
+// applyConfigSet 将--set 路径=值写入config，路径为以点分隔的YAML键，值按YAML解析
+func applyConfigSet(set string, config interface{}) error {
+	path, value, ok := strings.Cut(set, "=")
+	if !ok || path == "" {
+		return fmt.Errorf("invalid --set %q, expected PATH=VALUE (e.g. pool.pool_size=20)", set)
+	}
+	overlay := &yaml.Node{Kind: yaml.MappingNode}
+	setOverlay(overlay, strings.Split(path, "."), value)
+	data, err := yaml.Marshal(overlay)
+	if err != nil {
+		return fmt.Errorf("invalid --set %q: %w", set, err)
+	}
+	if err := decodeStrict(data, config); err != nil {
+		return fmt.Errorf("invalid --set %q: %w", set, err)
+	}
+	return nil
+}
+
+// setOverlay 在映射节点中按路径写入值，值作为YAML片段解析以保留数字、布尔与列表的类型
+func setOverlay(root *yaml.Node, path []string, value string) {
+	node := root
+	for i, key := range path {
+		var child *yaml.Node
+		for j := 0; j+1 < len(node.Content); j += 2 {
+			if node.Content[j].Value == key {
+				child = node.Content[j+1]
+				break
+			}
+		}
+		if i == len(path)-1 {
+			leaf := overlayValue(value)
+			if child != nil {
+				*child = *leaf
+			} else {
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, leaf)
+			}
+			return
+		}
+		if child == nil || child.Kind != yaml.MappingNode {
+			next := &yaml.Node{Kind: yaml.MappingNode}
+			if child != nil {
+				*child = *next
+				next = child
+			} else {
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, next)
+			}
+			child = next
+		}
+		node = child
+	}
+}
+
+// overlayValue 将值解析为YAML节点，解析失败或为空时按字符串处理
+func overlayValue(value string) *yaml.Node {
+	var document yaml.Node
+	if value != "" && yaml.Unmarshal([]byte(value), &document) == nil && len(document.Content) == 1 {
+		return document.Content[0]
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// decodeStrict 按YAML解码到config，拒绝配置结构体中不存在的键
+func decodeStrict(data []byte, config interface{}) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// configLeafPaths 配置结构体中可单独设置的YAML路径：标量、列表与映射字段
+func configLeafPaths(config interface{}) [][]string {
+	var paths [][]string
+	var walk func(s *schema.Schema, prefix []string)
+	walk = func(s *schema.Schema, prefix []string) {
+		if len(s.Properties) == 0 {
+			if len(prefix) > 0 {
+				paths = append(paths, append([]string(nil), prefix...))
+			}
+			return
+		}
+		keys := make([]string, 0, len(s.Properties))
+		for key := range s.Properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			walk(s.Properties[key], append(prefix, key))
+		}
+	}
+	walk(schema.Of(config), nil)
+	return paths
+}
+
+// configEnvName 配置路径对应的环境变量名
+func configEnvName(protocol string, path []string) string {
+	name := envPrefix + protocol + "_" + strings.Join(path, "_")
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// printConfigRequested 是否指定了--print-config
+func printConfigRequested(args []string) bool {
+	for _, arg := range args {
+		if arg == "--print-config" {
+			return true
+		}
+	}
+	return false
+}
+
+// printEffectiveConfig 以配置文件格式输出合并全部来源后的协议配置，opts非nil时在run键下输出共享运行选项，
+// 密码等敏感值以******代替
+func printEffectiveConfig(protocol string, config interface{}, opts *runOptions) error {
+	var node yaml.Node
+	if err := node.Encode(config); err != nil {
+		return fmt.Errorf("failed to encode %s config: %w", protocol, err)
+	}
+	if opts != nil && node.Kind == yaml.MappingNode {
+		var run yaml.Node
+		if err := run.Encode(opts.effectiveConfig()); err != nil {
+			return fmt.Errorf("failed to encode %s run options: %w", protocol, err)
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: runConfigKey}, &run)
+	}
+	redactSecrets(&node)
+	data, err := yaml.Marshal(map[string]*yaml.Node{protocol: &node})
+	if err != nil {
+		return fmt.Errorf("failed to encode %s config: %w", protocol, err)
+	}
+	fmt.Printf("# Effective %s configuration (defaults < --config < %s%s_* env < --set < flags)\n",
+		protocol, envPrefix, strings.ToUpper(protocol))
+	fmt.Print(string(data))
+	return nil
+}
+
+// redactSecrets 隐藏映射中敏感键的非空标量值
+func redactSecrets(node *yaml.Node) {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if value.Kind == yaml.ScalarNode && value.Value != "" && secretKeyPattern.MatchString(key.Value) {
+				value.Value, value.Tag, value.Style = "******", "!!str", 0
+				continue
+			}
+			redactSecrets(value)
+		}
+		return
+	}
+	for _, child := range node.Content {
+		redactSecrets(child)
+	}
+}
+
+// configLayersHelp 协议命令共享的配置来源帮助信息
+const configLayersHelp = `
+
+CONFIGURATION SOURCES (lowest to highest precedence):
+  defaults < --config FILE < ABC_<PROTOCOL>_<PATH> env vars < --set < flags
+  --config FILE                  YAML file with a <protocol>: section (or the
+                                 bare section), see config/<protocol>.yaml and
+                                 "abc-runner config schema"; may be repeated
+  --set PATH=VALUE               Set any config option by its YAML path, e.g.
+                                 --set pool.pool_size=20; may be repeated
+  --print-config                 Print the effective merged configuration
+                                 (secrets masked) and exit without running
+  Every YAML option can also be set from the environment: the path upper-cased
+  and joined with "_", e.g. ABC_REDIS_POOL_POOL_SIZE=20 or
+  ABC_HTTP_CONNECTION_TIMEOUT=5s. Values are parsed as YAML ([a, b] for lists).
+
+  Shared run options live under the section's run: key, e.g. run.prefill,
+  run.engine, run.cores, run.abort.{error_rate,max_errors,consecutive_errors},
+  run.raw_samples, run.partial_report.{path,interval},
+  run.checkpoint.{path,interval}, run.tags, run.report_locale, run.intercept,
+  run.push.{gateway,remote_write} and run.trace.{context,sample,otlp}; set
+  them with ABC_REDIS_RUN_PREFILL=1000 or --set run.prefill=1000. The
+  matching flags take precedence; --tag and --intercept add to the lists.`
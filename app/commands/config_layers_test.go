@@ -0,0 +1,159 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	redisConfig "abc-runner/app/adapters/redis/config"
+)
+
+func TestApplyConfigLayers_Precedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "redis.yaml")
+	err := os.WriteFile(path, []byte(`
+redis:
+  benchmark:
+    total: 500
+    parallels: 5
+    read_percent: 10
+  pool:
+    pool_size: 8
+    connection_timeout: 3s
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("ABC_REDIS_BENCHMARK_PARALLELS", "6")
+	t.Setenv("ABC_REDIS_BENCHMARK_READ_PERCENT", "20")
+	t.Setenv("ABC_REDIS_POOL_CONNECTION_TIMEOUT", "4s")
+
+	config, err := (&RedisCommandHandler{}).parseArgs([]string{
+		"--config", path, "--set", "benchmark.read_percent=30", "-c", "7",
+	})
+	if err != nil {
+		t.Fatalf("parseArgs failed: %v", err)
+	}
+	// 文件 < 环境变量 < --set < 专用参数
+	if config.BenchMark.Total != 500 || config.Pool.PoolSize != 8 {
+		t.Errorf("expected values from the config file, got total=%d pool_size=%d", config.BenchMark.Total, config.Pool.PoolSize)
+	}
+	if config.Pool.ConnectionTimeout != 4*time.Second {
+		t.Errorf("expected env to override the file, got %v", config.Pool.ConnectionTimeout)
+	}
+	if config.BenchMark.ReadPercent != 30 {
+		t.Errorf("expected --set to override env, got %d", config.BenchMark.ReadPercent)
+	}
+	if config.BenchMark.Parallels != 7 {
+		t.Errorf("expected -c to override env, got %d", config.BenchMark.Parallels)
+	}
+}
+
+func TestApplyConfigLayers_Errors(t *testing.T) {
+	cases := map[string][]string{
+		"field pool_sise not found": {"--set", "pool.pool_sise=10"},
+		"expected PATH=VALUE":       {"--set", "pool.pool_size"},
+		"cannot unmarshal":          {"--set", "benchmark.total=many"},
+		"missing value for --set":   {"--set"},
+	}
+	for want, args := range cases {
+		err := applyConfigLayers("redis", args, redisConfig.NewDefaultRedisConfig())
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%v: expected error containing %q, got %v", args, want, err)
+		}
+	}
+}
+
+func TestConfigEnvNames(t *testing.T) {
+	names := map[string]bool{}
+	for _, path := range configLeafPaths(redisConfig.NewDefaultRedisConfig()) {
+		names[configEnvName("redis", path)] = true
+	}
+	for _, want := range []string{"ABC_REDIS_POOL_POOL_SIZE", "ABC_REDIS_STANDALONE_ADDR", "ABC_REDIS_BENCHMARK_READ_PERCENT"} {
+		if !names[want] {
+			t.Errorf("expected env var %s", want)
+		}
+	}
+
+	// 每个协议的默认配置都应能枚举路径并输出
+	for protocol, target := range schemaTargets {
+		if len(configLeafPaths(target.defaults())) == 0 {
+			t.Errorf("%s: no configurable paths", protocol)
+		}
+		if err := printEffectiveConfig(protocol, target.defaults(), newRunOptions(context.Background())); err != nil {
+			t.Errorf("%s: %v", protocol, err)
+		}
+	}
+}
+
+func TestParseRunOptions_ConfigLayers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "redis.yaml")
+	err := os.WriteFile(path, []byte(`
+redis:
+  benchmark:
+    total: 500
+  run:
+    prefill: 100
+    engine: percore
+    cores: 2
+    abort:
+      max_errors: 50
+    checkpoint:
+      path: run.ckpt
+      interval: 5s
+    tags: [nightly]
+    trace:
+      sample: 0.5
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("ABC_REDIS_RUN_PREFILL", "200")
+	t.Setenv("ABC_REDIS_RUN_ABORT_ERROR_RATE", "20%@10s")
+
+	args := []string{"--config", path, "--set", "run.cores=3", "--set", "benchmark.parallels=4", "--prefill", "300", "--tag", "manual"}
+	config, err := (&RedisCommandHandler{}).parseArgs(args)
+	if err != nil {
+		t.Fatalf("parseArgs failed: %v", err)
+	}
+	if config.BenchMark.Total != 500 || config.BenchMark.Parallels != 4 {
+		t.Errorf("run options must not affect the protocol config, got total=%d parallels=%d", config.BenchMark.Total, config.BenchMark.Parallels)
+	}
+
+	opts, err := parseRunOptions(context.Background(), "redis", args)
+	if err != nil {
+		t.Fatalf("parseRunOptions failed: %v", err)
+	}
+	// 文件 < 环境变量 < --set < 命令行参数，--tag追加到配置的标签之后
+	if opts.prefill != 300 || opts.engine != enginePerCore || opts.cores != 3 {
+		t.Errorf("unexpected engine options: prefill=%d engine=%s cores=%d", opts.prefill, opts.engine, opts.cores)
+	}
+	if opts.stopConditions.MaxErrors != 50 || opts.stopConditions.ErrorRate != 0.2 || opts.stopConditions.ErrorRateWindow != 10*time.Second {
+		t.Errorf("unexpected stop conditions: %+v", opts.stopConditions)
+	}
+	if opts.checkpointPath != "run.ckpt" || opts.checkpointInterval != 5*time.Second {
+		t.Errorf("unexpected checkpoint: %s every %v", opts.checkpointPath, opts.checkpointInterval)
+	}
+	if strings.Join(opts.tags, ",") != "nightly,manual" || !opts.traceContext || opts.traceSampleRatio != 0.5 {
+		t.Errorf("unexpected tags or tracing: %v, %v, %v", opts.tags, opts.traceContext, opts.traceSampleRatio)
+	}
+
+	// 打印的run段可作为配置重新加载
+	effective := opts.effectiveConfig()
+	if effective.Abort.ErrorRate != "20%@10s" || effective.Prefill != 300 || effective.Cores != 3 {
+		t.Errorf("unexpected effective run config: %+v", effective)
+	}
+	reloaded := newRunOptions(context.Background())
+	if err := reloaded.parseFlags(effective.args()); err != nil || reloaded.effectiveConfig().Abort != effective.Abort {
+		t.Errorf("effective run config does not round-trip: %v", err)
+	}
+
+	if _, err := parseRunOptions(context.Background(), "redis", []string{"--set", "run.engine=turbo"}); err == nil || !strings.Contains(err.Error(), "invalid run options in redis config") {
+		t.Errorf("expected an invalid engine from config to fail, got %v", err)
+	}
+	if _, err := parseRunOptions(context.Background(), "redis", []string{"--set", "run.prefil=1"}); err == nil || !strings.Contains(err.Error(), "field prefil not found") {
+		t.Errorf("expected an unknown run key to fail, got %v", err)
+	}
+}
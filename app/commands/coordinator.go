@@ -0,0 +1,173 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"abc-runner/app/core/distributed"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/reporting"
+)
+
+// CoordinatorCommandHandler 分布式测试协调器命令处理器
+type CoordinatorCommandHandler struct{}
+
+// NewCoordinatorCommandHandler 创建协调器命令处理器
+func NewCoordinatorCommandHandler() *CoordinatorCommandHandler {
+	return &CoordinatorCommandHandler{}
+}
+
+// Execute 执行分布式测试
+func (h *CoordinatorCommandHandler) Execute(ctx context.Context, args []string) error {
+	// 解析协调器参数，第一个非选项参数为协议命令，其后参数原样下发给agent
+	var agents []string
+	token := os.Getenv(distributed.TokenEnv)
+	command := ""
+	var commandArgs []string
+
+	for i := 0; i < len(args); i++ {
+		if command != "" {
+			commandArgs = append(commandArgs, args[i])
+			continue
+		}
+
+		switch args[i] {
+		case "--help", "-h", "help":
+			fmt.Println(h.GetHelp())
+			return nil
+		case "--agents":
+			if i+1 >= len(args) {
+				return NewConfigError(fmt.Errorf("missing value for --agents"))
+			}
+			for _, agent := range strings.Split(args[i+1], ",") {
+				if agent = strings.TrimSpace(agent); agent != "" {
+					agents = append(agents, agent)
+				}
+			}
+			i++
+		case "--token":
+			if i+1 >= len(args) {
+				return NewConfigError(fmt.Errorf("missing value for --token"))
+			}
+			token = args[i+1]
+			i++
+		default:
+			if strings.HasPrefix(args[i], "-") {
+				return fmt.Errorf("unknown coordinator option: %s", args[i])
+			}
+			command = args[i]
+		}
+	}
+
+	if len(agents) == 0 {
+		return fmt.Errorf("at least one agent is required (use --agents host1:7070,host2:7070)")
+	}
+	if token == "" {
+		return fmt.Errorf("agent token is required (use --token or set %s)", distributed.TokenEnv)
+	}
+	if command == "" {
+		return fmt.Errorf("protocol command is required (e.g. abc-runner coordinator --agents ... redis -n 10000)")
+	}
+
+	// 下发给agent的参数中的SLA等通用选项同样作用于合并后的报告
+	opts, err := parseRunOptions(ctx, "", commandArgs)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	coordinator := distributed.NewCoordinator(agents, token)
+
+	fmt.Printf("🛰️  Checking %d agent(s)...\n", len(agents))
+	if err := coordinator.CheckAgents(ctx); err != nil {
+		return fmt.Errorf("agent check failed: %w", err)
+	}
+
+	// 汇总进度输出，限制输出频率
+	var progressMutex sync.Mutex
+	var lastProgress time.Time
+	coordinator.SetProgressFunc(func(merged *metrics.DefaultMetricsSnapshot, reporting int) {
+		progressMutex.Lock()
+		defer progressMutex.Unlock()
+		if time.Since(lastProgress) < time.Second {
+			return
+		}
+		lastProgress = time.Now()
+		fmt.Printf("📡 [%d/%d agents] ops: %d, success: %.2f%%, rps: %.2f, p99: %v\n",
+			reporting, len(agents), merged.Core.Operations.Total, merged.Core.Operations.Rate,
+			merged.Core.Throughput.RPS, merged.Core.Latency.P99)
+	})
+
+	fmt.Printf("🚀 Starting distributed %s test on %d agent(s)...\n", command, len(agents))
+	results, merged, err := coordinator.Run(ctx, command, commandArgs)
+	if err != nil {
+		return fmt.Errorf("distributed test failed: %w", err)
+	}
+
+	// 输出各agent结果
+	agentSummaries := make([]map[string]interface{}, 0, len(results))
+	fmt.Printf("✅ Distributed test completed\n")
+	for _, result := range results {
+		summary := map[string]interface{}{
+			"address":  result.Address,
+			"agent_id": result.AgentID,
+			"duration": result.Duration.String(),
+		}
+		if result.Error != nil {
+			summary["error"] = result.Error.Error()
+			fmt.Printf("   ❌ %s: %v\n", result.Address, result.Error)
+		} else {
+			summary["operations"] = result.Snapshot.Core.Operations.Total
+			summary["rps"] = result.Snapshot.Core.Throughput.RPS
+			summary["p99"] = result.Snapshot.Core.Latency.P99.String()
+			fmt.Printf("   %s (%s): ops=%d, rps=%.2f, p99=%v\n", result.Address, result.AgentID,
+				result.Snapshot.Core.Operations.Total, result.Snapshot.Core.Throughput.RPS, result.Snapshot.Core.Latency.P99)
+		}
+		agentSummaries = append(agentSummaries, summary)
+	}
+
+	merged.Protocol["protocol"] = command
+	merged.Protocol["test_type"] = "distributed"
+	merged.Protocol["agents"] = agentSummaries
+
+	// 生成合并后的结构化报告
+	report := reporting.ConvertFromMetricsSnapshot(merged)
+	reportConfig := reporting.NewStandardReportConfig(command + "_distributed")
+	opts.applyToReport(merged, report, reportConfig)
+	generator := reporting.NewReportGenerator(reportConfig)
+	if err := generator.Generate(report); err != nil {
+		return err
+	}
+	return opts.resultError(report)
+}
+
+// GetHelp 获取帮助信息
+func (h *CoordinatorCommandHandler) GetHelp() string {
+	return `Distributed Benchmark Coordinator
+
+USAGE:
+  abc-runner coordinator --agents ADDR[,ADDR...] --token TOKEN <protocol> [protocol options]
+
+DESCRIPTION:
+  Distribute a benchmark across remote agents (see 'abc-runner agent'),
+  aggregate their metrics streams and produce a single merged report.
+  The total operation count (-n) is split evenly across agents, while
+  concurrency (-c) and all other options apply per agent.
+
+OPTIONS:
+  --help, -h                Show this help message
+  --agents ADDR[,ADDR...]   Agent addresses (host:port, default port 7070)
+  --token TOKEN             Shared agent token (default: $ABC_RUNNER_AGENT_TOKEN)
+
+EXAMPLES:
+  abc-runner coordinator --agents 10.0.0.1:7070,10.0.0.2:7070 --token s3cret redis --host redis.local -n 100000 -c 50
+  abc-runner coordinator --agents gen1,gen2,gen3 --token s3cret http --url http://api.local -n 30000 -c 20
+
+NOTE:
+  Agents stream their HDR latency histograms, so percentiles in the merged
+  report are computed from the combined distribution of all agents.
+`
+}
@@ -0,0 +1,440 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	dnsConfig "abc-runner/app/adapters/dns/config"
+	"abc-runner/app/adapters/dns/operations"
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/reporting"
+)
+
+// DNSCommandHandler DNS命令处理器
+type DNSCommandHandler struct {
+	protocolName string
+	factory      interfaces.DNSAdapterFactory
+}
+
+// NewDNSCommandHandler 创建DNS命令处理器
+func NewDNSCommandHandler(factory interfaces.DNSAdapterFactory) *DNSCommandHandler {
+	if factory == nil {
+		panic("dnsAdapterFactory cannot be nil - dependency injection required")
+	}
+
+	return &DNSCommandHandler{
+		protocolName: "dns",
+		factory:      factory,
+	}
+}
+
+// Execute 执行DNS命令
+func (h *DNSCommandHandler) Execute(ctx context.Context, args []string) error {
+	// 检查帮助请求
+	for _, arg := range args {
+		if arg == "--help" || arg == "help" || arg == "-h" {
+			fmt.Println(h.GetHelp())
+			return nil
+		}
+	}
+
+	// 解析命令行参数
+	config, err := h.parseArgs(args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	// 解析通用运行选项
+	opts, err := parseRunOptions(ctx, "dns", args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+	if printConfigRequested(args) {
+		return printEffectiveConfig("dns", config, opts)
+	}
+
+	metricsCollector := metrics.NewBaseCollector(opts.collectorConfig(), map[string]interface{}{
+		"protocol":  "dns",
+		"test_type": "performance",
+	})
+	defer metricsCollector.Stop()
+
+	adapter := h.factory.CreateDNSAdapter()
+	if adapter == nil {
+		return fmt.Errorf("failed to create DNS adapter")
+	}
+	defer adapter.Close()
+
+	target := config.Connection.GetTarget()
+	if err := adapter.Connect(ctx, config); err != nil {
+		return NewConnectionError(fmt.Errorf("failed to connect to DNS server %s: %w", target, err))
+	}
+	if err := adapter.HealthCheck(ctx); err != nil {
+		return NewConnectionError(fmt.Errorf("dns health check failed: %w", err))
+	}
+
+	specific := config.DNSSpecific
+	fmt.Printf("✅ Connected to %s\n", target)
+	fmt.Printf("🚀 Starting DNS performance test...\n")
+	fmt.Printf("Record Types: %s\n", strings.Join(specific.Types, ", "))
+	fmt.Printf("Names: %d (%s)\n", len(specific.Names), describeNames(specific.Names))
+	if specific.RandomSubdomain {
+		fmt.Printf("Random Subdomain: enabled\n")
+	}
+	if config.BenchMark.Rate > 0 {
+		fmt.Printf("Rate Limit: %d queries/sec\n", config.BenchMark.Rate)
+	}
+	fmt.Printf("Queries: %d, Concurrency: %d\n", config.BenchMark.Total, config.BenchMark.Parallels)
+
+	if err := h.runPerformanceTest(ctx, adapter, config, metricsCollector, opts); err != nil {
+		return fmt.Errorf("performance test failed: %w", err)
+	}
+
+	return h.generateReport(metricsCollector, opts)
+}
+
+// describeNames 描述查询名称，超过3个时只列出前3个
+func describeNames(names []string) string {
+	if len(names) <= 3 {
+		return strings.Join(names, ", ")
+	}
+	return strings.Join(names[:3], ", ") + ", ..."
+}
+
+// GetHelp 获取帮助信息
+func (h *DNSCommandHandler) GetHelp() string {
+	return `DNS Query Performance Testing
+
+USAGE:
+  abc-runner dns [options]
+
+DESCRIPTION:
+  Send A, AAAA, SRV, TXT and other queries to a DNS server over UDP, TCP,
+  DNS over TLS (DoT) or DNS over HTTPS (DoH), and report latency per record
+  type together with the response code breakdown (NOERROR, NXDOMAIN,
+  SERVFAIL, ...). Queries cycle through every record type for one name
+  before moving on to the next name.
+
+OPTIONS:
+  --help                   Show this help message
+  --server HOST[:PORT]     DNS server (default: 127.0.0.1)
+  --port PORT              Server port (default: 53, or 853 for dot)
+  --transport PROTO        udp, tcp, dot or doh (default: udp)
+  --url URL                DoH endpoint, e.g. https://dns.google/dns-query
+                           (implies --transport doh)
+  --name NAME              Name to query; repeat or separate with commas
+                           (default: example.com)
+  --names-file FILE        File with one name per line, added to --name
+  --type TYPES             Comma-separated record types: A, AAAA, SRV, TXT,
+                           CNAME, MX, NS, PTR, SOA (default: A)
+  --random-subdomain       Prefix each name with a random label so queries
+                           miss the resolver cache
+  --no-recursion           Clear the RD flag (for authoritative servers)
+  --udp-size N             EDNS0 UDP payload size, 0 to omit EDNS0
+                           (default: 1232)
+  --rate N                 Cap the query rate at N queries/sec (default: unlimited)
+  --timeout DURATION       Per-query timeout (default: 2s)
+  --insecure, -k           Skip TLS certificate verification (dot, doh)
+  --ca-file FILE           CA bundle for verifying the server certificate
+  --server-name NAME       TLS SNI and verification name for dot (default: server)
+  -n COUNT                 Total queries (default: 10000)
+  -c COUNT                 Concurrent clients (default: 10)
+  --duration DURATION      Run for a fixed duration instead of -n
+
+NOTES:
+  NXDOMAIN is a valid answer and counts as a success; SERVFAIL, REFUSED,
+  FORMERR and NOTIMP count as failures. Truncated UDP responses (TC flag)
+  are counted but not retried over TCP.
+  With udp, tcp and dot every concurrent client owns one socket or
+  connection; doh shares an HTTP/2 client between them.
+
+EXAMPLES:
+  abc-runner dns --help
+  abc-runner dns --server 127.0.0.1 --name example.com --type A,AAAA -n 100000 -c 50
+  abc-runner dns --server 10.0.0.2:5353 --transport tcp --names-file names.txt --type SRV,TXT
+  abc-runner dns --server 1.1.1.1 --transport dot --name example.com --rate 2000 --duration 60s
+  abc-runner dns --url https://dns.google/dns-query --name example.com --random-subdomain -c 20` + configLayersHelp + runOptionsHelp + "\n"
+}
+
+// parseArgs 解析命令行参数，names_file中的名称在解析时合并到names
+func (h *DNSCommandHandler) parseArgs(args []string) (*dnsConfig.DNSConfig, error) {
+	config := dnsConfig.NewDefaultDNSConfig()
+	specific := &config.DNSSpecific
+	var names []string
+
+	// 依次应用--config文件、ABC_DNS_*环境变量与--set，专用参数随后覆盖
+	if err := applyConfigLayers("dns", args, config); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(args); i++ {
+		// 无参数的开关
+		switch args[i] {
+		case "--random-subdomain":
+			specific.RandomSubdomain = true
+			continue
+		case "--no-recursion":
+			specific.RecursionDesired = false
+			continue
+		case "--insecure", "-k":
+			config.Connection.TLS.InsecureSkipVerify = true
+			continue
+		}
+
+		if i+1 >= len(args) {
+			break
+		}
+		value := args[i+1]
+		var err error
+
+		switch args[i] {
+		case "--server":
+			config.Connection.Address, config.Connection.Port, err = parseServerAddress(value, config.Connection.Port)
+		case "--port":
+			config.Connection.Port, err = strconv.Atoi(value)
+		case "--transport":
+			config.Connection.Transport = strings.ToLower(value)
+		case "--url":
+			config.Connection.URL = value
+			config.Connection.Transport = dnsConfig.TransportDoH
+		case "--name":
+			names = append(names, splitList(value)...)
+		case "--names-file":
+			specific.NamesFile = value
+		case "--type":
+			specific.Types = splitList(strings.ToUpper(value))
+		case "--udp-size":
+			specific.UDPSize, err = strconv.Atoi(value)
+		case "--rate":
+			config.BenchMark.Rate, err = strconv.Atoi(value)
+		case "--timeout":
+			config.Connection.Timeout, err = time.ParseDuration(value)
+		case "--ca-file":
+			config.Connection.TLS.CAFile = value
+		case "--server-name":
+			config.Connection.TLS.ServerName = value
+		case "-n":
+			config.BenchMark.Total, err = strconv.Atoi(value)
+		case "-c":
+			config.BenchMark.Parallels, err = strconv.Atoi(value)
+		case "--duration":
+			config.BenchMark.Duration, err = time.ParseDuration(value)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", args[i], err)
+		}
+		i++
+	}
+
+	// 指定了名称或名称文件时替换默认名称
+	if len(names) > 0 || specific.NamesFile != "" {
+		specific.Names = names
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if err := config.ResolveNames(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// parseServerAddress 解析 HOST 或 HOST:PORT（IPv6地址需写作[::1]:53），未带端口时保留原端口
+func parseServerAddress(value string, port int) (string, int, error) {
+	host, portText, err := net.SplitHostPort(value)
+	if err != nil {
+		return strings.Trim(value, "[]"), port, nil
+	}
+	port, err = strconv.Atoi(portText)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid port in %q", value)
+	}
+	return host, port, nil
+}
+
+// splitList 按逗号拆分并去掉空项
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// runPerformanceTest 运行DNS性能测试
+func (h *DNSCommandHandler) runPerformanceTest(
+	ctx context.Context,
+	adapter interfaces.ProtocolAdapter,
+	config *dnsConfig.DNSConfig,
+	collector *metrics.BaseCollector[map[string]interface{}],
+	opts *runOptions,
+) error {
+	factory := operations.NewOperationFactory(config)
+	engine := execution.NewExecutionEngine(adapter, collector, factory)
+	engine.SetMaxWorkers(config.BenchMark.Parallels)
+	opts.applyToEngine(engine, collector)
+
+	testStartTime := time.Now()
+	result, err := engine.RunBenchmark(ctx, &config.BenchMark)
+	if err != nil {
+		return fmt.Errorf("benchmark execution failed: %w", err)
+	}
+	opts.finishRun()
+	actualTestDuration := time.Since(testStartTime)
+
+	fmt.Printf("\n📊 Execution Results:\n")
+	fmt.Printf("Total Jobs: %d\n", result.TotalJobs)
+	fmt.Printf("Completed Jobs: %d\n", result.CompletedJobs)
+	fmt.Printf("Success Jobs: %d\n", result.SuccessJobs)
+	fmt.Printf("Failed Jobs: %d\n", result.FailedJobs)
+	fmt.Printf("Actual Test Duration: %v\n", actualTestDuration)
+
+	protocolMetrics := map[string]interface{}{
+		"protocol":         "dns",
+		"test_type":        "performance",
+		"test_case":        config.BenchMark.TestCase,
+		"target":           config.Connection.GetTarget(),
+		"transport":        config.Connection.Transport,
+		"record_types":     config.DNSSpecific.Types,
+		"names":            len(config.DNSSpecific.Names),
+		"random_subdomain": config.DNSSpecific.RandomSubdomain,
+		"rate_limit":       config.BenchMark.Rate,
+		"actual_duration":  actualTestDuration,
+		"execution_result": result,
+	}
+
+	if statsAdapter, ok := adapter.(interface {
+		GetOperationStats() []operations.OperationStats
+		GetResponseCodeStats() []operations.ResponseCodeStats
+	}); ok {
+		if stats := statsAdapter.GetOperationStats(); len(stats) > 0 {
+			printDNSOperationStats(stats, actualTestDuration)
+			protocolMetrics["operation_stats"] = stats
+		}
+		if codes := statsAdapter.GetResponseCodeStats(); len(codes) > 0 {
+			printDNSResponseCodes(codes)
+			protocolMetrics["response_codes"] = codes
+		}
+	}
+
+	collector.UpdateProtocolMetrics(protocolMetrics)
+	return nil
+}
+
+// printDNSOperationStats 打印按记录类型的统计表
+func printDNSOperationStats(stats []operations.OperationStats, duration time.Duration) {
+	fmt.Printf("\n📋 Per-Record-Type Statistics:\n")
+	fmt.Printf("  %-6s %10s %10s %8s %10s %10s %10s %10s %10s\n",
+		"TYPE", "COUNT", "QPS", "ERR%", "AVG", "P50", "P95", "P99", "ANSWERS")
+	for _, s := range stats {
+		answers := "-"
+		if s.Count > s.Errors {
+			answers = fmt.Sprintf("%.2f", float64(s.Volume)/float64(s.Count-s.Errors))
+		}
+		fmt.Printf("  %-6s %10d %10.1f %8.2f %10v %10v %10v %10v %10s\n",
+			s.Type, s.Count, float64(s.Count)/duration.Seconds(), s.ErrorRate,
+			s.Latency.Average, s.Latency.P50, s.Latency.P95, s.Latency.P99, answers)
+		if s.Flagged > 0 {
+			fmt.Printf("  %-6s   truncated: %d\n", "", s.Flagged)
+		}
+		for code, n := range s.ErrorCodes {
+			fmt.Printf("  %-6s   %s: %d\n", "", code, n)
+		}
+	}
+}
+
+// printDNSResponseCodes 打印按记录类型的响应码分布，常见响应码在前
+func printDNSResponseCodes(stats []operations.ResponseCodeStats) {
+	order := map[string]int{"NOERROR": 0, "NXDOMAIN": 1, "SERVFAIL": 2, "REFUSED": 3}
+	var codes []string
+	seen := make(map[string]bool)
+	for _, s := range stats {
+		for code := range s.Codes {
+			if !seen[code] {
+				seen[code] = true
+				codes = append(codes, code)
+			}
+		}
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		ri, iKnown := order[codes[i]]
+		rj, jKnown := order[codes[j]]
+		if iKnown != jKnown {
+			return iKnown
+		}
+		if iKnown {
+			return ri < rj
+		}
+		return codes[i] < codes[j]
+	})
+
+	fmt.Printf("\n🧾 Response Codes:\n")
+	fmt.Printf("  %-6s %10s", "TYPE", "RESPONSES")
+	for _, code := range codes {
+		fmt.Printf(" %10s", code)
+	}
+	fmt.Println()
+	for _, s := range stats {
+		fmt.Printf("  %-6s %10d", s.Type, s.Responses)
+		for _, code := range codes {
+			fmt.Printf(" %10s", fmt.Sprintf("%.2f%%", float64(s.Codes[code])/float64(s.Responses)*100))
+		}
+		fmt.Println()
+	}
+}
+
+// generateReport 生成DNS测试报告
+func (h *DNSCommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
+	snapshot := collector.Snapshot()
+
+	// 使用实际测试时间修正吞吐量
+	if duration, ok := snapshot.Protocol["actual_duration"].(time.Duration); ok && duration > 0 {
+		snapshot.Core.Duration = duration
+		seconds := duration.Seconds()
+		snapshot.Core.Throughput.RPS = float64(snapshot.Core.Operations.Total) / seconds
+		snapshot.Core.Throughput.ReadRPS = float64(snapshot.Core.Operations.Read) / seconds
+		snapshot.Core.Throughput.WriteRPS = float64(snapshot.Core.Operations.Write) / seconds
+		snapshot.Core.Throughput.ReadMBps, snapshot.Core.Throughput.WriteMBps = metrics.Bandwidth(snapshot.Core.Operations, duration)
+	}
+
+	fmt.Printf("\n📊 DNS Performance Metrics:\n")
+	fmt.Printf("=====================================\n")
+	fmt.Printf("  Total Queries: %d\n", snapshot.Core.Operations.Total)
+	fmt.Printf("  Successful: %d\n", snapshot.Core.Operations.Success)
+	fmt.Printf("  Failed: %d\n", snapshot.Core.Operations.Failed)
+	fmt.Printf("  Success Rate: %.2f%%\n", snapshot.Core.Operations.Rate)
+	fmt.Printf("  Latency avg/p95/p99: %v/%v/%v\n",
+		snapshot.Core.Latency.Average, snapshot.Core.Latency.P95, snapshot.Core.Latency.P99)
+	fmt.Printf("  Throughput: %.2f queries/sec\n", snapshot.Core.Throughput.RPS)
+	fmt.Printf("=====================================\n")
+
+	// 存在快照接收器时（如分布式agent模式）交由接收器处理，不生成本地报告
+	if opts.publishSnapshot(snapshot) {
+		return nil
+	}
+
+	report := reporting.ConvertFromMetricsSnapshot(snapshot)
+	reportConfig := reporting.NewStandardReportConfig("dns")
+	opts.applyToReport(snapshot, report, reportConfig)
+	generator := reporting.NewReportGenerator(reportConfig)
+	if err := generator.Generate(report); err != nil {
+		return err
+	}
+	return opts.resultError(report)
+}
+
+// GetProtocolName 获取协议名称
+func (h *DNSCommandHandler) GetProtocolName() string {
+	return h.protocolName
+}
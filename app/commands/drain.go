@@ -0,0 +1,299 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"abc-runner/app/core/drain"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/reporting"
+)
+
+// DrainCommandHandler 消息队列消费速率测试命令处理器
+type DrainCommandHandler struct{}
+
+// NewDrainCommandHandler 创建消费速率测试命令处理器
+func NewDrainCommandHandler() *DrainCommandHandler {
+	return &DrainCommandHandler{}
+}
+
+// drainArgs 消费速率测试命令行参数
+type drainArgs struct {
+	queue    string
+	target   string
+	name     string
+	group    string
+	vhost    string
+	username string
+	password string
+	db       int
+	timeout  time.Duration
+	config   *drain.Config
+}
+
+// Execute 执行消费速率测试
+func (h *DrainCommandHandler) Execute(ctx context.Context, args []string) error {
+	for _, arg := range args {
+		if arg == "--help" || arg == "-h" || arg == "help" {
+			fmt.Println(h.GetHelp())
+			return nil
+		}
+	}
+
+	parsed, err := h.parseArgs(args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	// 解析通用运行选项
+	opts, err := parseRunOptions(ctx, "", args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	queue, err := h.createQueue(ctx, parsed)
+	if err != nil {
+		return NewConnectionError(err)
+	}
+	defer queue.Close()
+
+	metricsCollector := metrics.NewBaseCollector(opts.collectorConfig(), map[string]interface{}{
+		"protocol":  queue.Name(),
+		"test_type": "drain",
+	})
+	defer metricsCollector.Stop()
+
+	// 消费时长由--drain-timeout控制，这里仅响应显式取消
+	runCtx := context.WithoutCancel(ctx)
+
+	cfg := parsed.config
+	fmt.Printf("🚀 Starting %s drain test: target=%s, queue=%s, messages=%d, payload=%dB, poll=%v, timeout=%v\n",
+		queue.Name(), parsed.target, parsed.name, cfg.Messages, cfg.PayloadSize, cfg.Interval, cfg.Timeout)
+
+	opts.applyToCollector(metricsCollector)
+	runner := drain.NewRunner(queue, cfg, metricsCollector)
+	result, err := runner.Run(runCtx, func(sample drain.Sample) {
+		fmt.Printf("📉 t=%v backlog=%d rate=%.1f msg/s\n",
+			sample.Elapsed.Round(time.Millisecond), sample.Backlog, sample.Rate)
+	})
+	opts.finishRun()
+	if err != nil && result == nil {
+		return fmt.Errorf("drain test failed: %w", err)
+	}
+	if err != nil {
+		fmt.Printf("⚠️  Drain test stopped early: %v\n", err)
+	}
+
+	h.printSummary(result)
+	return h.generateReport(metricsCollector, parsed, result, opts)
+}
+
+// parseArgs 解析命令行参数
+func (h *DrainCommandHandler) parseArgs(args []string) (*drainArgs, error) {
+	parsed := &drainArgs{
+		queue:   "redis",
+		name:    "abc-runner-drain",
+		vhost:   "/",
+		timeout: 5 * time.Second,
+		config:  drain.NewDefaultConfig(),
+	}
+
+	for i := 0; i < len(args); i++ {
+		if i+1 >= len(args) {
+			break
+		}
+		value := args[i+1]
+		var err error
+
+		switch args[i] {
+		case "--queue", "-q":
+			parsed.queue = strings.ToLower(value)
+		case "--target", "-t":
+			parsed.target = value
+		case "--name":
+			parsed.name = value
+		case "--group":
+			parsed.group = value
+		case "--vhost":
+			parsed.vhost = value
+		case "--user":
+			parsed.username = value
+		case "--password", "-a":
+			parsed.password = value
+		case "--db":
+			parsed.db, err = strconv.Atoi(value)
+		case "--messages", "-n":
+			parsed.config.Messages, err = strconv.Atoi(value)
+		case "--payload":
+			parsed.config.PayloadSize, err = strconv.Atoi(value)
+		case "--batch":
+			parsed.config.BatchSize, err = strconv.Atoi(value)
+		case "--interval":
+			parsed.config.Interval, err = time.ParseDuration(value)
+		case "--drain-timeout":
+			parsed.config.Timeout, err = time.ParseDuration(value)
+		case "--timeout":
+			parsed.timeout, err = time.ParseDuration(value)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", args[i], err)
+		}
+		i++
+	}
+
+	switch parsed.queue {
+	case "redis":
+		if parsed.target == "" {
+			parsed.target = "localhost:6379"
+		}
+	case "kafka":
+		if parsed.target == "" {
+			parsed.target = "localhost:9092"
+		}
+		if parsed.group == "" {
+			return nil, fmt.Errorf("--group is required for kafka (the consumer group being measured)")
+		}
+	case "rabbitmq", "amqp":
+		parsed.queue = "rabbitmq"
+		if parsed.target == "" {
+			parsed.target = "http://localhost:15672"
+		}
+		if parsed.username == "" {
+			parsed.username, parsed.password = "guest", "guest"
+		}
+	default:
+		return nil, fmt.Errorf("unsupported queue: %s (expected redis, kafka or rabbitmq)", parsed.queue)
+	}
+
+	if parsed.timeout <= 0 {
+		return nil, fmt.Errorf("timeout must be positive")
+	}
+	if err := parsed.config.Validate(); err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+// createQueue 根据参数创建被测队列
+func (h *DrainCommandHandler) createQueue(ctx context.Context, parsed *drainArgs) (drain.Queue, error) {
+	connectCtx, cancel := context.WithTimeout(ctx, parsed.timeout)
+	defer cancel()
+
+	switch parsed.queue {
+	case "redis":
+		return drain.NewRedisQueue(connectCtx, parsed.target, parsed.password, parsed.db, parsed.name)
+	case "kafka":
+		return drain.NewKafkaQueue(strings.Split(parsed.target, ","), parsed.name, parsed.group,
+			parsed.config.BatchSize, parsed.timeout)
+	default:
+		return drain.NewRabbitMQQueue(connectCtx, parsed.target, parsed.vhost, parsed.name,
+			parsed.username, parsed.password, parsed.timeout)
+	}
+}
+
+// printSummary 输出填充与消费速率汇总
+func (h *DrainCommandHandler) printSummary(result *drain.Result) {
+	reached := func(d time.Duration, fraction int64) string {
+		if result.FinalBacklog > result.InitialBacklog*fraction/100 {
+			return "not reached"
+		}
+		return d.Round(time.Millisecond).String()
+	}
+
+	fmt.Printf("\n📊 Drain Summary (%s)\n", result.Queue)
+	fmt.Printf("  Filled:          %d in %v", result.Filled, result.FillDuration.Round(time.Millisecond))
+	if result.FillErrors > 0 {
+		fmt.Printf(" (%d failed)", result.FillErrors)
+	}
+	fmt.Println()
+	fmt.Printf("  Backlog:         %d -> %d\n", result.InitialBacklog, result.FinalBacklog)
+	if result.Drained {
+		fmt.Printf("  Drained in:      %v\n", result.DrainDuration.Round(time.Millisecond))
+	} else {
+		fmt.Printf("  Drained:         no (stopped after %v)\n", result.DrainDuration.Round(time.Millisecond))
+	}
+	fmt.Printf("  Time to 50%%:     %s\n", reached(result.Half, 50))
+	fmt.Printf("  Time to 90%%:     %s\n", reached(result.Ninety, 10))
+	fmt.Printf("  Drain rate:      avg %.1f msg/s, peak %.1f msg/s\n", result.AvgRate, result.PeakRate)
+	fmt.Println()
+}
+
+// generateReport 生成报告
+func (h *DrainCommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}], parsed *drainArgs, result *drain.Result, opts *runOptions) error {
+	snapshot := collector.Snapshot()
+	snapshot.Protocol["target"] = parsed.target
+	snapshot.Protocol["queue_name"] = parsed.name
+	snapshot.Protocol["drain"] = result.ToMap()
+
+	if opts.publishSnapshot(snapshot) {
+		return nil
+	}
+
+	report := reporting.ConvertFromMetricsSnapshot(snapshot)
+	reportConfig := reporting.NewStandardReportConfig("drain_" + result.Queue)
+	opts.applyToReport(snapshot, report, reportConfig)
+	generator := reporting.NewReportGenerator(reportConfig)
+	if err := generator.Generate(report); err != nil {
+		return err
+	}
+	return opts.resultError(report)
+}
+
+// GetHelp 获取帮助信息
+func (h *DrainCommandHandler) GetHelp() string {
+	return `Message Queue Drain-Rate Test
+
+USAGE:
+  abc-runner drain --queue redis|kafka|rabbitmq [options]
+
+DESCRIPTION:
+  Pre-fill a queue with N messages, then poll its backlog at a fixed
+  interval while the system under test consumes it. Reports the drain
+  curve (backlog and consumption rate per sample), time to drain 50%, 90%
+  and 100% of the initial backlog, and average and peak drain rates.
+  abc-runner does not consume anything itself; start the consumer before
+  or right after the fill.
+
+  Backlog is measured as:
+    redis     LLEN of the list (fill uses RPUSH)
+    kafka     sum of consumer group lag over all partitions of the topic
+    rabbitmq  ready plus unacknowledged messages reported by the
+              management plugin HTTP API (fill publishes through the
+              default exchange, so the queue must already exist)
+
+OPTIONS:
+  --help, -h               Show this help message
+  --queue, -q TYPE         Queue type: redis (default), kafka or rabbitmq
+  --target, -t ADDR        Redis address, comma-separated Kafka brokers or
+                           RabbitMQ management URL (defaults: localhost:6379,
+                           localhost:9092, http://localhost:15672)
+  --name NAME              List key, topic or queue name
+                           (default: abc-runner-drain)
+  --group ID               Kafka consumer group to measure (required for kafka)
+  --vhost NAME             RabbitMQ virtual host (default: /)
+  --user NAME              RabbitMQ management user (default: guest)
+  --password, -a PASS      Redis or RabbitMQ password
+  --db N                   Redis database (default: 0)
+  --messages, -n N         Messages to pre-fill (default: 100000)
+  --payload N              Message size in bytes (default: 128)
+  --batch N                Messages per fill batch (default: 500)
+  --interval DUR           Backlog poll interval (default: 1s)
+  --drain-timeout DUR      Stop polling after this long even if the backlog
+                           is not empty (default: 5m)
+  --timeout DUR            Connection and request timeout (default: 5s)
+
+EXAMPLES:
+  abc-runner drain --queue redis --name jobs -n 50000
+  abc-runner drain --queue kafka -t broker1:9092,broker2:9092 --name orders --group order-workers
+  abc-runner drain --queue rabbitmq --name tasks --interval 5s --drain-timeout 10m
+
+NOTE:
+  RabbitMQ refreshes queue statistics every collect_statistics_interval
+  (5s by default); shorter poll intervals repeat the same value and show
+  a stepped curve.` + runOptionsHelp + "\n"
+}
@@ -0,0 +1,436 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	esConfig "abc-runner/app/adapters/elasticsearch/config"
+	"abc-runner/app/adapters/elasticsearch/connection"
+	"abc-runner/app/adapters/elasticsearch/operations"
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/reporting"
+)
+
+// ElasticsearchCommandHandler Elasticsearch/OpenSearch命令处理器
+type ElasticsearchCommandHandler struct {
+	protocolName string
+	factory      interfaces.ElasticsearchAdapterFactory
+}
+
+// NewElasticsearchCommandHandler 创建Elasticsearch命令处理器
+func NewElasticsearchCommandHandler(factory interfaces.ElasticsearchAdapterFactory) *ElasticsearchCommandHandler {
+	if factory == nil {
+		panic("elasticsearchAdapterFactory cannot be nil - dependency injection required")
+	}
+
+	return &ElasticsearchCommandHandler{
+		protocolName: "elasticsearch",
+		factory:      factory,
+	}
+}
+
+// Execute 执行Elasticsearch命令
+func (h *ElasticsearchCommandHandler) Execute(ctx context.Context, args []string) error {
+	// 检查帮助请求
+	for _, arg := range args {
+		if arg == "--help" || arg == "help" || arg == "-h" {
+			fmt.Println(h.GetHelp())
+			return nil
+		}
+	}
+
+	// 解析命令行参数
+	config, err := h.parseArgs(args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	// 解析通用运行选项
+	opts, err := parseRunOptions(ctx, "elasticsearch", args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+	if printConfigRequested(args) {
+		return printEffectiveConfig("elasticsearch", config, opts)
+	}
+
+	metricsCollector := metrics.NewBaseCollector(opts.collectorConfig(), map[string]interface{}{
+		"protocol":  "elasticsearch",
+		"test_type": "performance",
+	})
+	defer metricsCollector.Stop()
+
+	adapter := h.factory.CreateElasticsearchAdapter()
+	if adapter == nil {
+		return fmt.Errorf("failed to create Elasticsearch adapter")
+	}
+	defer adapter.Close()
+
+	target := strings.Join(config.Connection.Addresses, ",")
+	if err := adapter.Connect(ctx, config); err != nil {
+		return NewConnectionError(fmt.Errorf("failed to connect to elasticsearch %s: %w", target, err))
+	}
+	if err := adapter.HealthCheck(ctx); err != nil {
+		return NewConnectionError(fmt.Errorf("elasticsearch health check failed: %w", err))
+	}
+
+	specific := config.ElasticsearchSpecific
+	if infoAdapter, ok := adapter.(interface {
+		GetClusterInfo() *connection.ClusterInfo
+	}); ok {
+		if info := infoAdapter.GetClusterInfo(); info != nil {
+			fmt.Printf("✅ Connected to %s %s (cluster %s) at %s\n", info.Product(), info.Version.Number, info.ClusterName, target)
+		}
+	}
+	fmt.Printf("🚀 Starting Elasticsearch performance test...\n")
+	fmt.Printf("Test Case: %s\n", config.BenchMark.TestCase)
+	refresh := specific.Refresh
+	if refresh == "" {
+		refresh = "false"
+	}
+	fmt.Printf("Index: %s, Bulk Size: %d, Refresh: %s\n", specific.Index, specific.BulkSize, refresh)
+	if config.BenchMark.TestCase == esConfig.TestCaseMixed {
+		fmt.Printf("Read Percent: %d%%\n", config.BenchMark.ReadPercent)
+	}
+	fmt.Printf("Operations: %d, Concurrency: %d\n", config.BenchMark.Total, config.BenchMark.Parallels)
+
+	if err := h.runPerformanceTest(ctx, adapter, config, metricsCollector, opts); err != nil {
+		return fmt.Errorf("performance test failed: %w", err)
+	}
+
+	return h.generateReport(metricsCollector, opts)
+}
+
+// GetHelp 获取帮助信息
+func (h *ElasticsearchCommandHandler) GetHelp() string {
+	return `Elasticsearch / OpenSearch Performance Testing
+
+USAGE:
+  abc-runner elasticsearch [options]
+
+DESCRIPTION:
+  Bulk-index templated documents and run templated search queries against
+  an Elasticsearch or OpenSearch cluster. Indexing is reported in docs/sec
+  and MB/sec; searches report latency percentiles and hit counts. 429
+  responses and bulk items rejected with 429 are counted separately, since
+  they mean the cluster is pushing back rather than failing.
+
+  Every index operation sends one bulk request of --bulk-size documents.
+  Requests rotate over the given node addresses.
+
+OPTIONS:
+  --help                     Show this help message
+  --url URL                  Node address (repeatable or comma-separated,
+                             default: http://localhost:9200)
+  --username USER            Basic auth user
+  --password PASS            Basic auth password
+  --api-key KEY              Base64-encoded API key (instead of basic auth)
+  --header "K: V"            Extra request header (repeatable)
+  --insecure, -k             Skip TLS certificate verification
+  --timeout DURATION         Request timeout (default: 30s)
+  --test-case CASE           index (default), search or mixed
+  --index NAME               Index to write to and search (default: abc_bench)
+  --bulk-size N              Documents per bulk request (default: 500)
+  --refresh MODE             Bulk refresh: false (default), true or wait_for
+  --setup                    Create the index before the run if it is missing
+  --shards N                 Primary shards for --setup (default: 1)
+  --replicas N               Replicas for --setup (default: cluster default)
+  --teardown                 Delete the index after the run
+  --document TEMPLATE        Document template, e.g. '{"id":"{{uuid}}","n":{{seq}}}'
+  --query TEMPLATE           Search request body template
+  --data-file FILE           CSV file for {{csv.COLUMN}}; the first row names the columns
+  --read-percent N           Share of searches in a mixed test (default: 50)
+  -n COUNT                   Total operations (default: 1000)
+  -c COUNT                   Concurrent workers (default: 10)
+  --duration DURATION        Run for a fixed duration instead of -n
+
+NOTES:
+  A bulk request succeeds only when every item is indexed; partially
+  rejected requests count as failed operations and the indexed items still
+  count toward docs/sec. A search that returns failed shards is a failure;
+  one that times out with partial results is counted as timed_out.
+  Use --prefill N to index N bulk requests before a search test.
+
+EXAMPLES:
+  abc-runner elasticsearch --help
+  abc-runner elasticsearch --url http://localhost:9200 --setup --teardown -n 1000 -c 20
+  abc-runner es --url https://es1:9200,https://es2:9200 --api-key $KEY \
+    --bulk-size 1000 --duration 5m
+  abc-runner opensearch --url http://os:9200 --test-case search --prefill 200 \
+    --query '{"query":{"match":{"level":"{{pick info warn error}}"}}}' -n 10000
+  abc-runner elasticsearch --test-case mixed --read-percent 20 --duration 60s` + configLayersHelp + runOptionsHelp + "\n"
+}
+
+// parseArgs 解析命令行参数
+func (h *ElasticsearchCommandHandler) parseArgs(args []string) (*esConfig.ElasticsearchConfig, error) {
+	config := esConfig.NewDefaultElasticsearchConfig()
+	specific := &config.ElasticsearchSpecific
+	var addresses []string
+
+	// 依次应用--config文件、ABC_ELASTICSEARCH_*环境变量与--set，专用参数随后覆盖
+	if err := applyConfigLayers("elasticsearch", args, config); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(args); i++ {
+		// 无参数的开关
+		switch args[i] {
+		case "--insecure", "-k":
+			config.Connection.InsecureSkipVerify = true
+			continue
+		case "--setup":
+			specific.Setup = true
+			continue
+		case "--teardown":
+			specific.Teardown = true
+			continue
+		}
+
+		if i+1 >= len(args) {
+			break
+		}
+		value := args[i+1]
+		var err error
+
+		switch args[i] {
+		case "--url":
+			for _, address := range strings.Split(value, ",") {
+				if address = strings.TrimSpace(address); address != "" {
+					addresses = append(addresses, address)
+				}
+			}
+		case "--username":
+			config.Connection.Username = value
+		case "--password":
+			config.Connection.Password = value
+		case "--api-key":
+			config.Connection.APIKey = value
+		case "--header":
+			name, headerValue, found := strings.Cut(value, ":")
+			if !found || strings.TrimSpace(name) == "" {
+				err = fmt.Errorf("expected \"Name: value\", got %q", value)
+				break
+			}
+			config.Connection.Headers[strings.TrimSpace(name)] = strings.TrimSpace(headerValue)
+		case "--timeout":
+			config.Connection.Timeout, err = time.ParseDuration(value)
+		case "--test-case":
+			config.BenchMark.TestCase = strings.ToLower(value)
+		case "--index":
+			specific.Index = value
+		case "--bulk-size":
+			specific.BulkSize, err = strconv.Atoi(value)
+		case "--refresh":
+			specific.Refresh = strings.ToLower(value)
+		case "--shards":
+			specific.Shards, err = strconv.Atoi(value)
+		case "--replicas":
+			specific.Replicas, err = strconv.Atoi(value)
+		case "--document":
+			specific.Document.Template = value
+		case "--query":
+			specific.Query.Template = value
+		case "--data-file":
+			specific.Document.DataFile = value
+			specific.Query.DataFile = value
+		case "--read-percent":
+			config.BenchMark.ReadPercent, err = strconv.Atoi(value)
+		case "-n":
+			config.BenchMark.Total, err = strconv.Atoi(value)
+		case "-c":
+			config.BenchMark.Parallels, err = strconv.Atoi(value)
+		case "--duration":
+			config.BenchMark.Duration, err = time.ParseDuration(value)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", args[i], err)
+		}
+		i++
+	}
+
+	if len(addresses) > 0 {
+		config.Connection.Addresses = addresses
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// runPerformanceTest 运行Elasticsearch性能测试
+func (h *ElasticsearchCommandHandler) runPerformanceTest(
+	ctx context.Context,
+	adapter interfaces.ProtocolAdapter,
+	config *esConfig.ElasticsearchConfig,
+	collector *metrics.BaseCollector[map[string]interface{}],
+	opts *runOptions,
+) error {
+	factory := operations.NewOperationFactory(config)
+	engine := execution.NewExecutionEngine(adapter, collector, factory)
+	engine.SetMaxWorkers(config.BenchMark.Parallels)
+
+	// 预填充测试索引（不计入指标）
+	if err := opts.runPrefill(ctx, engine, collector, config.BenchMark.Parallels); err != nil {
+		return err
+	}
+	opts.applyToEngine(engine, collector)
+
+	testStartTime := time.Now()
+	result, err := engine.RunBenchmark(ctx, &config.BenchMark)
+	if err != nil {
+		return fmt.Errorf("benchmark execution failed: %w", err)
+	}
+	opts.finishRun()
+	actualTestDuration := time.Since(testStartTime)
+
+	fmt.Printf("\n📊 Execution Results:\n")
+	fmt.Printf("Total Jobs: %d\n", result.TotalJobs)
+	fmt.Printf("Completed Jobs: %d\n", result.CompletedJobs)
+	fmt.Printf("Success Jobs: %d\n", result.SuccessJobs)
+	fmt.Printf("Failed Jobs: %d\n", result.FailedJobs)
+	fmt.Printf("Actual Test Duration: %v\n", actualTestDuration)
+
+	specific := config.ElasticsearchSpecific
+	protocolMetrics := map[string]interface{}{
+		"protocol":         "elasticsearch",
+		"test_type":        "performance",
+		"test_case":        config.BenchMark.TestCase,
+		"target":           strings.Join(config.Connection.Addresses, ","),
+		"index":            specific.Index,
+		"bulk_size":        specific.BulkSize,
+		"actual_duration":  actualTestDuration,
+		"execution_result": result,
+	}
+
+	if statsAdapter, ok := adapter.(interface {
+		GetOperationStats() []operations.OperationStats
+		GetIndexingStats() *operations.IndexingStats
+		GetClusterInfo() *connection.ClusterInfo
+	}); ok {
+		if info := statsAdapter.GetClusterInfo(); info != nil {
+			protocolMetrics["product"] = info.Product()
+			protocolMetrics["version"] = info.Version.Number
+		}
+		if stats := statsAdapter.GetOperationStats(); len(stats) > 0 {
+			printElasticsearchOperationStats(stats, actualTestDuration)
+			protocolMetrics["operation_stats"] = stats
+		}
+		if stats := statsAdapter.GetIndexingStats(); stats != nil {
+			printElasticsearchIndexingStats(stats, actualTestDuration)
+			protocolMetrics["indexing_stats"] = *stats
+			protocolMetrics["docs_per_sec"] = float64(stats.DocsIndexed) / actualTestDuration.Seconds()
+			protocolMetrics["mb_per_sec"] = float64(stats.BytesSent) / actualTestDuration.Seconds() / 1024 / 1024
+		}
+	}
+
+	collector.UpdateProtocolMetrics(protocolMetrics)
+	return nil
+}
+
+// printElasticsearchOperationStats 打印按操作类型的统计表，index的DOCS为写入成功的文档数，search为命中总数
+func printElasticsearchOperationStats(stats []operations.OperationStats, duration time.Duration) {
+	fmt.Printf("\n📋 Per-Operation Statistics:\n")
+	fmt.Printf("  %-8s %10s %10s %8s %12s %10s %10s %10s\n",
+		"TYPE", "COUNT", "OPS/S", "ERR%", "DOCS", "P50", "P95", "P99")
+	for _, s := range stats {
+		fmt.Printf("  %-8s %10d %10.1f %8.2f %12d %10v %10v %10v\n",
+			s.Type, s.Count, float64(s.Count)/duration.Seconds(), s.ErrorRate,
+			s.Volume, s.Latency.P50, s.Latency.P95, s.Latency.P99)
+		if s.Flagged > 0 {
+			fmt.Printf("  %-8s   timed_out: %d\n", "", s.Flagged)
+		}
+		for code, n := range s.ErrorCodes {
+			fmt.Printf("  %-8s   %s: %d\n", "", code, n)
+		}
+	}
+}
+
+// printElasticsearchIndexingStats 打印写入吞吐量、背压与响应分布
+func printElasticsearchIndexingStats(stats *operations.IndexingStats, duration time.Duration) {
+	seconds := duration.Seconds()
+	if stats.BulkRequests > 0 {
+		fmt.Printf("\n📈 Indexing:\n")
+		fmt.Printf("Docs Indexed: %d / %d (%.2f docs/sec)\n",
+			stats.DocsIndexed, stats.DocsSent, float64(stats.DocsIndexed)/seconds)
+		fmt.Printf("Bytes Sent: %d (%.2f MB/sec)\n", stats.BytesSent, float64(stats.BytesSent)/seconds/1024/1024)
+		fmt.Printf("Docs Rejected (429): %d\n", stats.DocsRejected)
+		if stats.DocsFailed > 0 {
+			fmt.Printf("Docs Failed: %d\n", stats.DocsFailed)
+		}
+		errorTypes := make([]string, 0, len(stats.ItemErrors))
+		for errorType := range stats.ItemErrors {
+			errorTypes = append(errorTypes, errorType)
+		}
+		sort.Strings(errorTypes)
+		for _, errorType := range errorTypes {
+			fmt.Printf("  %s: %d\n", errorType, stats.ItemErrors[errorType])
+		}
+	}
+
+	fmt.Printf("\n🚦 Back-pressure:\n")
+	fmt.Printf("Throttled (HTTP 429): %d (%.2f%%)\n", stats.Throttled, stats.ThrottleRate)
+	if stats.TransportErrors > 0 {
+		fmt.Printf("Transport Errors: %d\n", stats.TransportErrors)
+	}
+	codes := make([]int, 0, len(stats.StatusCodes))
+	for code := range stats.StatusCodes {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		fmt.Printf("  HTTP %d: %d\n", code, stats.StatusCodes[code])
+	}
+}
+
+// generateReport 生成Elasticsearch测试报告
+func (h *ElasticsearchCommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
+	snapshot := collector.Snapshot()
+
+	// 使用实际测试时间修正吞吐量
+	if duration, ok := snapshot.Protocol["actual_duration"].(time.Duration); ok && duration > 0 {
+		snapshot.Core.Duration = duration
+		seconds := duration.Seconds()
+		snapshot.Core.Throughput.RPS = float64(snapshot.Core.Operations.Total) / seconds
+		snapshot.Core.Throughput.ReadRPS = float64(snapshot.Core.Operations.Read) / seconds
+		snapshot.Core.Throughput.WriteRPS = float64(snapshot.Core.Operations.Write) / seconds
+		snapshot.Core.Throughput.ReadMBps, snapshot.Core.Throughput.WriteMBps = metrics.Bandwidth(snapshot.Core.Operations, duration)
+	}
+
+	fmt.Printf("\n📊 Elasticsearch Performance Metrics:\n")
+	fmt.Printf("=====================================\n")
+	fmt.Printf("  Total Operations: %d\n", snapshot.Core.Operations.Total)
+	fmt.Printf("  Successful: %d\n", snapshot.Core.Operations.Success)
+	fmt.Printf("  Failed: %d\n", snapshot.Core.Operations.Failed)
+	fmt.Printf("  Success Rate: %.2f%%\n", snapshot.Core.Operations.Rate)
+	fmt.Printf("  Latency avg/p95/p99: %v/%v/%v\n",
+		snapshot.Core.Latency.Average, snapshot.Core.Latency.P95, snapshot.Core.Latency.P99)
+	fmt.Printf("  Throughput: %.2f ops/sec\n", snapshot.Core.Throughput.RPS)
+	fmt.Printf("=====================================\n")
+
+	// 存在快照接收器时（如分布式agent模式）交由接收器处理，不生成本地报告
+	if opts.publishSnapshot(snapshot) {
+		return nil
+	}
+
+	report := reporting.ConvertFromMetricsSnapshot(snapshot)
+	reportConfig := reporting.NewStandardReportConfig("elasticsearch")
+	opts.applyToReport(snapshot, report, reportConfig)
+	generator := reporting.NewReportGenerator(reportConfig)
+	if err := generator.Generate(report); err != nil {
+		return err
+	}
+	return opts.resultError(report)
+}
+
+// GetProtocolName 获取协议名称
+func (h *ElasticsearchCommandHandler) GetProtocolName() string {
+	return h.protocolName
+}
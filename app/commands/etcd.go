@@ -0,0 +1,450 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	etcdConfig "abc-runner/app/adapters/etcd/config"
+	"abc-runner/app/adapters/etcd/connection"
+	"abc-runner/app/adapters/etcd/operations"
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/reporting"
+)
+
+// EtcdCommandHandler etcd命令处理器
+type EtcdCommandHandler struct {
+	protocolName string
+	factory      interfaces.EtcdAdapterFactory
+}
+
+// NewEtcdCommandHandler 创建etcd命令处理器
+func NewEtcdCommandHandler(factory interfaces.EtcdAdapterFactory) *EtcdCommandHandler {
+	if factory == nil {
+		panic("etcdAdapterFactory cannot be nil - dependency injection required")
+	}
+
+	return &EtcdCommandHandler{
+		protocolName: "etcd",
+		factory:      factory,
+	}
+}
+
+// Execute 执行etcd命令
+func (h *EtcdCommandHandler) Execute(ctx context.Context, args []string) error {
+	// 检查帮助请求
+	for _, arg := range args {
+		if arg == "--help" || arg == "help" || arg == "-h" {
+			fmt.Println(h.GetHelp())
+			return nil
+		}
+	}
+
+	// 解析命令行参数
+	config, err := h.parseArgs(args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	// 解析通用运行选项
+	opts, err := parseRunOptions(ctx, "etcd", args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+	if printConfigRequested(args) {
+		return printEffectiveConfig("etcd", config, opts)
+	}
+
+	metricsCollector := metrics.NewBaseCollector(opts.collectorConfig(), map[string]interface{}{
+		"protocol":  "etcd",
+		"test_type": "performance",
+	})
+	defer metricsCollector.Stop()
+
+	adapter := h.factory.CreateEtcdAdapter()
+	if adapter == nil {
+		return fmt.Errorf("failed to create etcd adapter")
+	}
+	defer adapter.Close()
+
+	target := config.Connection.GetTarget()
+	if err := adapter.Connect(ctx, config); err != nil {
+		return NewConnectionError(fmt.Errorf("failed to connect to etcd %s: %w", target, err))
+	}
+	if err := adapter.HealthCheck(ctx); err != nil {
+		return NewConnectionError(fmt.Errorf("etcd health check failed: %w", err))
+	}
+
+	specific := config.EtcdSpecific
+	fmt.Printf("✅ Connected to %s\n", target)
+	if statusAdapter, ok := adapter.(interface {
+		GetMemberStatus() []connection.MemberStatus
+	}); ok {
+		printEtcdMembers(statusAdapter.GetMemberStatus())
+	}
+	if seededAdapter, ok := adapter.(interface{ Seeded() bool }); ok && seededAdapter.Seeded() {
+		fmt.Printf("🛠️  Seeded %s with %d keys\n", specific.KeyPrefix, config.BenchMark.RandomKeys)
+	}
+	fmt.Printf("🚀 Starting etcd performance test...\n")
+	fmt.Printf("Test Case: %s\n", config.BenchMark.TestCase)
+	fmt.Printf("Key Prefix: %s (%d keys), Value Size: %d bytes\n",
+		specific.KeyPrefix, config.BenchMark.RandomKeys, config.BenchMark.DataSize)
+	switch config.BenchMark.TestCase {
+	case etcdConfig.TestCaseGet, etcdConfig.TestCaseRange, etcdConfig.TestCaseMixed:
+		if config.BenchMark.TestCase == etcdConfig.TestCaseMixed {
+			fmt.Printf("Read Percent: %d%%\n", config.BenchMark.ReadPercent)
+		}
+		if config.BenchMark.TestCase == etcdConfig.TestCaseRange {
+			fmt.Printf("Range Limit: %d\n", specific.RangeLimit)
+		}
+		fmt.Printf("Serializable Reads: %d%%\n", specific.SerializablePercent)
+	case etcdConfig.TestCaseWatch:
+		fmt.Printf("Watchers: %d\n", specific.Watchers)
+	case etcdConfig.TestCaseLease:
+		fmt.Printf("Lease TTL: %ds, Keep-Alives: %d\n", specific.LeaseTTL, specific.KeepAlives)
+	}
+	if config.BenchMark.Rate > 0 {
+		fmt.Printf("Rate Limit: %d ops/sec\n", config.BenchMark.Rate)
+	}
+	fmt.Printf("Operations: %d, Concurrency: %d\n", config.BenchMark.Total, config.BenchMark.Parallels)
+
+	if err := h.runPerformanceTest(ctx, adapter, config, metricsCollector, opts); err != nil {
+		return fmt.Errorf("performance test failed: %w", err)
+	}
+
+	return h.generateReport(metricsCollector, opts)
+}
+
+// printEtcdMembers 打印健康检查时各成员的版本与leader
+func printEtcdMembers(members []connection.MemberStatus) {
+	for _, member := range members {
+		if member.Err != nil {
+			fmt.Printf("  ⚠️  %s: %v\n", member.Endpoint, member.Err)
+			continue
+		}
+		role := "follower"
+		if member.Status.Leader == member.Status.Header.MemberID {
+			role = "leader"
+		}
+		fmt.Printf("  %s: etcd %s, %s, db %d bytes\n",
+			member.Endpoint, member.Status.Version, role, member.Status.DbSize)
+	}
+}
+
+// GetHelp 获取帮助信息
+func (h *EtcdCommandHandler) GetHelp() string {
+	return `etcd v3 Performance Testing
+
+USAGE:
+  abc-runner etcd [options]
+
+DESCRIPTION:
+  Stress an etcd v3 cluster (or any control-plane store speaking the etcd
+  v3 gRPC API) with key writes, point reads, range scans, watch fan-out and
+  lease churn. Reads are reported separately as linearizable (confirmed
+  through raft) and serializable (served from the local member), so the
+  cost of consistency shows up directly in the per-operation table.
+
+TEST CASES:
+  put      Write keys, cycling through the key space
+  get      Read single keys
+  range    Scan up to --range-limit keys from a random key to the end of
+           the prefix
+  mixed    Reads and writes by --read-percent
+  watch    Open --watchers watches on the prefix, write keys and measure
+           the put-to-event delivery latency and missed events
+  lease    Grant a lease, attach a key, send --keepalives keep-alives on a
+           stream and revoke it; each step is reported separately
+
+OPTIONS:
+  --help                   Show this help message
+  --endpoints LIST         Comma-separated member addresses
+                           (default: 127.0.0.1:2379)
+  --connections N          gRPC connections per endpoint (default: 1)
+  --user NAME              Username when auth is enabled
+  --password PASSWORD      Password for --user
+  -t, --test-case CASE     put, get, range, mixed, watch or lease
+                           (default: put)
+  --key-prefix PREFIX      Prefix of the test keys (default: /abc-runner/)
+  --keys N                 Key space size (default: 1000)
+  --value-size BYTES       Value size, at least 8 (default: 256)
+  --read-percent N         Reads in the mixed case, 0-100 (default: 80)
+  --serializable-percent N Reads issued as serializable, 0-100 (default: 0)
+  --range-limit N          Maximum keys per range (default: 100)
+  --watchers N             Watchers on the prefix in the watch case (default: 10)
+  --lease-ttl SECONDS      TTL of granted leases (default: 10)
+  --keepalives N           Keep-alives per lease before revoke (default: 1)
+  --no-setup               Do not seed the key space before read tests
+  --rate N                 Cap the operation rate at N ops/sec (default: unlimited)
+  --timeout DURATION       Connect and request timeout (default: 5s)
+  --tls                    Connect with TLS
+  --ca-file FILE           CA bundle for verifying member certificates
+  --cert-file FILE         Client certificate (client-cert-auth)
+  --key-file FILE          Client private key
+  --insecure, -k           Skip TLS certificate verification
+  --server-name NAME       TLS verification name
+  -n COUNT                 Total operations (default: 10000)
+  -c COUNT                 Concurrent clients (default: 10)
+  --duration DURATION      Run for a fixed duration instead of -n
+
+NOTES:
+  get, range and mixed seed the whole key space when the prefix is empty;
+  run a put test first or pass --no-setup to read existing data only.
+  Values carry their write time in the first 8 bytes, which the watch case
+  uses to measure delivery latency; watch waits up to --timeout after the
+  run for outstanding events before counting them as missed.
+
+EXAMPLES:
+  abc-runner etcd --help
+  abc-runner etcd --endpoints 10.0.0.1:2379,10.0.0.2:2379,10.0.0.3:2379 -t put -n 100000 -c 50
+  abc-runner etcd -t get --serializable-percent 50 --keys 10000 -c 100
+  abc-runner etcd -t range --range-limit 500 --duration 60s
+  abc-runner etcd -t watch --watchers 100 --rate 500 --duration 60s
+  abc-runner etcd -t lease --lease-ttl 5 --keepalives 3 -c 20
+  abc-runner etcd --tls --ca-file ca.pem --cert-file client.pem --key-file client-key.pem` + configLayersHelp + runOptionsHelp + "\n"
+}
+
+// parseArgs 解析命令行参数
+func (h *EtcdCommandHandler) parseArgs(args []string) (*etcdConfig.EtcdConfig, error) {
+	config := etcdConfig.NewDefaultEtcdConfig()
+	specific := &config.EtcdSpecific
+
+	// 依次应用--config文件、ABC_ETCD_*环境变量与--set，专用参数随后覆盖
+	if err := applyConfigLayers("etcd", args, config); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(args); i++ {
+		// 无参数的开关
+		switch args[i] {
+		case "--no-setup":
+			specific.Setup = false
+			continue
+		case "--tls":
+			config.Connection.TLS.Enabled = true
+			continue
+		case "--insecure", "-k":
+			config.Connection.TLS.Enabled = true
+			config.Connection.TLS.InsecureSkipVerify = true
+			continue
+		}
+
+		if i+1 >= len(args) {
+			break
+		}
+		value := args[i+1]
+		var err error
+
+		switch args[i] {
+		case "--endpoints":
+			config.Connection.Endpoints = splitList(value)
+		case "--connections":
+			config.Connection.Connections, err = strconv.Atoi(value)
+		case "--user":
+			config.Connection.Username = value
+		case "--password":
+			config.Connection.Password = value
+		case "--test-case", "-t":
+			config.BenchMark.TestCase = strings.ToLower(value)
+		case "--key-prefix":
+			specific.KeyPrefix = value
+		case "--keys":
+			config.BenchMark.RandomKeys, err = strconv.Atoi(value)
+		case "--value-size":
+			config.BenchMark.DataSize, err = strconv.Atoi(value)
+		case "--read-percent":
+			config.BenchMark.ReadPercent, err = strconv.Atoi(value)
+		case "--serializable-percent":
+			specific.SerializablePercent, err = strconv.Atoi(value)
+		case "--range-limit":
+			specific.RangeLimit, err = strconv.Atoi(value)
+		case "--watchers":
+			specific.Watchers, err = strconv.Atoi(value)
+		case "--lease-ttl":
+			specific.LeaseTTL, err = strconv.Atoi(value)
+		case "--keepalives":
+			specific.KeepAlives, err = strconv.Atoi(value)
+		case "--rate":
+			config.BenchMark.Rate, err = strconv.Atoi(value)
+		case "--timeout":
+			config.Connection.Timeout, err = time.ParseDuration(value)
+		case "--ca-file":
+			config.Connection.TLS.Enabled = true
+			config.Connection.TLS.CAFile = value
+		case "--cert-file":
+			config.Connection.TLS.Enabled = true
+			config.Connection.TLS.CertFile = value
+		case "--key-file":
+			config.Connection.TLS.Enabled = true
+			config.Connection.TLS.KeyFile = value
+		case "--server-name":
+			config.Connection.TLS.ServerName = value
+		case "-n":
+			config.BenchMark.Total, err = strconv.Atoi(value)
+		case "-c":
+			config.BenchMark.Parallels, err = strconv.Atoi(value)
+		case "--duration":
+			config.BenchMark.Duration, err = time.ParseDuration(value)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", args[i], err)
+		}
+		i++
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// runPerformanceTest 运行etcd性能测试
+func (h *EtcdCommandHandler) runPerformanceTest(
+	ctx context.Context,
+	adapter interfaces.ProtocolAdapter,
+	config *etcdConfig.EtcdConfig,
+	collector *metrics.BaseCollector[map[string]interface{}],
+	opts *runOptions,
+) error {
+	factory := operations.NewOperationFactory(config)
+	engine := execution.NewExecutionEngine(adapter, collector, factory)
+	engine.SetMaxWorkers(config.BenchMark.Parallels)
+	opts.applyToEngine(engine, collector)
+
+	testStartTime := time.Now()
+	result, err := engine.RunBenchmark(ctx, &config.BenchMark)
+	if err != nil {
+		return fmt.Errorf("benchmark execution failed: %w", err)
+	}
+	opts.finishRun()
+	actualTestDuration := time.Since(testStartTime)
+
+	fmt.Printf("\n📊 Execution Results:\n")
+	fmt.Printf("Total Jobs: %d\n", result.TotalJobs)
+	fmt.Printf("Completed Jobs: %d\n", result.CompletedJobs)
+	fmt.Printf("Success Jobs: %d\n", result.SuccessJobs)
+	fmt.Printf("Failed Jobs: %d\n", result.FailedJobs)
+	fmt.Printf("Actual Test Duration: %v\n", actualTestDuration)
+
+	protocolMetrics := map[string]interface{}{
+		"protocol":             "etcd",
+		"test_type":            "performance",
+		"test_case":            config.BenchMark.TestCase,
+		"endpoints":            config.Connection.Endpoints,
+		"key_prefix":           config.EtcdSpecific.KeyPrefix,
+		"keys":                 config.BenchMark.RandomKeys,
+		"value_size":           config.BenchMark.DataSize,
+		"serializable_percent": config.EtcdSpecific.SerializablePercent,
+		"rate_limit":           config.BenchMark.Rate,
+		"actual_duration":      actualTestDuration,
+		"execution_result":     result,
+	}
+
+	if statsAdapter, ok := adapter.(interface {
+		GetOperationStats() []operations.OperationStats
+		GetWatchStats() *operations.WatchStats
+	}); ok {
+		if stats := statsAdapter.GetOperationStats(); len(stats) > 0 {
+			printEtcdOperationStats(stats, actualTestDuration)
+			protocolMetrics["operation_stats"] = stats
+		}
+		if watch := statsAdapter.GetWatchStats(); watch != nil {
+			printEtcdWatchStats(watch)
+			protocolMetrics["watch"] = watch
+		}
+	}
+
+	collector.UpdateProtocolMetrics(protocolMetrics)
+	return nil
+}
+
+// printEtcdOperationStats 打印按操作类型的统计表
+func printEtcdOperationStats(stats []operations.OperationStats, duration time.Duration) {
+	fmt.Printf("\n📋 Per-Operation Statistics:\n")
+	fmt.Printf("  %-20s %10s %10s %8s %10s %10s %10s %10s %8s\n",
+		"OPERATION", "COUNT", "OPS/SEC", "ERR%", "AVG", "P50", "P95", "P99", "KEYS")
+	for _, s := range stats {
+		keys := "-"
+		if s.Count > s.Errors && s.Volume > 0 {
+			keys = fmt.Sprintf("%.1f", float64(s.Volume)/float64(s.Count-s.Errors))
+		}
+		fmt.Printf("  %-20s %10d %10.1f %8.2f %10v %10v %10v %10v %8s\n",
+			s.Type, s.Count, float64(s.Count)/duration.Seconds(), s.ErrorRate,
+			s.Latency.Average, s.Latency.P50, s.Latency.P95, s.Latency.P99, keys)
+		if s.Flagged > 0 {
+			fmt.Printf("  %-20s   truncated by range limit: %d\n", "", s.Flagged)
+		}
+		for code, n := range s.ErrorCodes {
+			fmt.Printf("  %-20s   %s: %d\n", "", code, n)
+		}
+	}
+}
+
+// printEtcdWatchStats 打印watch扇出统计
+func printEtcdWatchStats(stats *operations.WatchStats) {
+	fmt.Printf("\n👀 Watch Fan-Out:\n")
+	fmt.Printf("  Watchers: %d\n", stats.Watchers)
+	fmt.Printf("  Events Delivered: %d / %d expected\n", stats.Delivered, stats.Expected)
+	if stats.Missed > 0 {
+		fmt.Printf("  ⚠️  Missed Events: %d\n", stats.Missed)
+	}
+	if stats.Canceled > 0 {
+		fmt.Printf("  ⚠️  Watchers Canceled by Server: %d\n", stats.Canceled)
+	}
+	if stats.Delivered > 0 {
+		fmt.Printf("  Delivery Latency avg/p50/p95/p99/max: %v/%v/%v/%v/%v\n",
+			stats.Latency.Average, stats.Latency.P50, stats.Latency.P95, stats.Latency.P99, stats.Latency.Max)
+	}
+}
+
+// generateReport 生成etcd测试报告
+func (h *EtcdCommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
+	snapshot := collector.Snapshot()
+
+	// 使用实际测试时间修正吞吐量
+	if duration, ok := snapshot.Protocol["actual_duration"].(time.Duration); ok && duration > 0 {
+		snapshot.Core.Duration = duration
+		seconds := duration.Seconds()
+		snapshot.Core.Throughput.RPS = float64(snapshot.Core.Operations.Total) / seconds
+		snapshot.Core.Throughput.ReadRPS = float64(snapshot.Core.Operations.Read) / seconds
+		snapshot.Core.Throughput.WriteRPS = float64(snapshot.Core.Operations.Write) / seconds
+		snapshot.Core.Throughput.ReadMBps, snapshot.Core.Throughput.WriteMBps = metrics.Bandwidth(snapshot.Core.Operations, duration)
+	}
+
+	fmt.Printf("\n📊 etcd Performance Metrics:\n")
+	fmt.Printf("=====================================\n")
+	fmt.Printf("  Total Operations: %d\n", snapshot.Core.Operations.Total)
+	fmt.Printf("  Successful: %d\n", snapshot.Core.Operations.Success)
+	fmt.Printf("  Failed: %d\n", snapshot.Core.Operations.Failed)
+	fmt.Printf("  Success Rate: %.2f%%\n", snapshot.Core.Operations.Rate)
+	fmt.Printf("  Latency avg/p95/p99: %v/%v/%v\n",
+		snapshot.Core.Latency.Average, snapshot.Core.Latency.P95, snapshot.Core.Latency.P99)
+	fmt.Printf("  Throughput: %.2f ops/sec (read %.2f, write %.2f)\n",
+		snapshot.Core.Throughput.RPS, snapshot.Core.Throughput.ReadRPS, snapshot.Core.Throughput.WriteRPS)
+	fmt.Printf("=====================================\n")
+
+	// 存在快照接收器时（如分布式agent模式）交由接收器处理，不生成本地报告
+	if opts.publishSnapshot(snapshot) {
+		return nil
+	}
+
+	report := reporting.ConvertFromMetricsSnapshot(snapshot)
+	reportConfig := reporting.NewStandardReportConfig("etcd")
+	opts.applyToReport(snapshot, report, reportConfig)
+	generator := reporting.NewReportGenerator(reportConfig)
+	if err := generator.Generate(report); err != nil {
+		return err
+	}
+	return opts.resultError(report)
+}
+
+// GetProtocolName 获取协议名称
+func (h *EtcdCommandHandler) GetProtocolName() string {
+	return h.protocolName
+}
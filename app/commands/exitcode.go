@@ -0,0 +1,79 @@
+package commands
+
+import "errors"
+
+// 进程退出码，CI脚本据此区分失败原因，无需解析完整报告
+const (
+	ExitCodeOK          = 0   // 运行完成且全部检查通过
+	ExitCodeRegression  = 1   // compare检测到性能回归
+	ExitCodeInternal    = 2   // 内部错误（未归类的运行失败）
+	ExitCodeThreshold   = 3   // SLA阈值未满足
+	ExitCodeConfig      = 4   // 参数或配置错误
+	ExitCodeConnection  = 5   // 无法连接被测目标
+	ExitCodeConformance = 6   // adapter verify发现违反接口契约的行为
+	ExitCodeAborted     = 130 // 运行被中断（SIGINT/SIGTERM），报告只包含中断前的数据
+)
+
+// ExitError 需要以指定退出码结束进程的错误（如检测到性能回归）
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode 进程退出码
+func (e *ExitError) ExitCode() int {
+	return e.Code
+}
+
+// NewConfigError 包装参数或配置错误
+func NewConfigError(err error) *ExitError {
+	return &ExitError{Code: ExitCodeConfig, Err: err}
+}
+
+// NewConnectionError 包装连接被测目标失败的错误
+func NewConnectionError(err error) *ExitError {
+	return &ExitError{Code: ExitCodeConnection, Err: err}
+}
+
+// ExitCodeOf 返回错误对应的退出码：nil为0，携带退出码的错误使用其退出码，其余视为内部错误
+func ExitCodeOf(err error) int {
+	if err == nil {
+		return ExitCodeOK
+	}
+	var exitErr interface{ ExitCode() int }
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return ExitCodeInternal
+}
+
+// ExitStatus 退出码对应的状态名称，写入result.json
+func ExitStatus(code int) string {
+	switch code {
+	case ExitCodeOK:
+		return "passed"
+	case ExitCodeRegression:
+		return "regression"
+	case ExitCodeInternal:
+		return "internal_error"
+	case ExitCodeThreshold:
+		return "threshold_breach"
+	case ExitCodeConfig:
+		return "config_error"
+	case ExitCodeConnection:
+		return "connection_failure"
+	case ExitCodeConformance:
+		return "conformance_failure"
+	case ExitCodeAborted:
+		return "aborted"
+	}
+	return "failed"
+}
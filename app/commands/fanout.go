@@ -0,0 +1,275 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"abc-runner/app/core/fanout"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/reporting"
+)
+
+// FanoutCommandHandler 发布订阅扇出扩展性测试命令处理器
+type FanoutCommandHandler struct{}
+
+// NewFanoutCommandHandler 创建扇出测试命令处理器
+func NewFanoutCommandHandler() *FanoutCommandHandler {
+	return &FanoutCommandHandler{}
+}
+
+// fanoutArgs 扇出测试命令行参数
+type fanoutArgs struct {
+	transport string
+	target    string
+	password  string
+	db        int
+	broadcast string
+	timeout   time.Duration
+	config    *fanout.Config
+}
+
+// Execute 执行扇出测试
+func (h *FanoutCommandHandler) Execute(ctx context.Context, args []string) error {
+	for _, arg := range args {
+		if arg == "--help" || arg == "-h" || arg == "help" {
+			fmt.Println(h.GetHelp())
+			return nil
+		}
+	}
+
+	parsed, err := h.parseArgs(args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	// 解析通用运行选项
+	opts, err := parseRunOptions(ctx, "", args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	transport, err := h.createTransport(ctx, parsed)
+	if err != nil {
+		return NewConnectionError(err)
+	}
+	defer transport.Close()
+
+	metricsCollector := metrics.NewBaseCollector(opts.collectorConfig(), map[string]interface{}{
+		"protocol":  transport.Name(),
+		"test_type": "fanout",
+	})
+	defer metricsCollector.Stop()
+
+	// 扇出测试的投递总时长可能远超单次命令的默认超时，这里仅响应显式取消
+	runCtx := context.WithoutCancel(ctx)
+
+	cfg := parsed.config
+	fmt.Printf("🚀 Starting %s fan-out test: subscribers=%v, rate=%d msg/s, duration=%v/step, payload=%dB\n",
+		transport.Name(), cfg.Subscribers, cfg.Rate, cfg.Duration, cfg.PayloadSize)
+
+	opts.applyToCollector(metricsCollector)
+	runner := fanout.NewRunner(transport, cfg, metricsCollector)
+	results, err := runner.Run(runCtx, func(step fanout.StepResult) {
+		fmt.Printf("📡 subscribers=%d published=%d delivered=%d/%d drop=%.2f%% p50=%v p99=%v max=%v\n",
+			step.Subscribers, step.Published, step.Delivered, step.Expected, step.DropRate,
+			step.LatencyP50, step.LatencyP99, step.LatencyMax)
+	})
+	opts.finishRun()
+	if err != nil && len(results) == 0 {
+		return fmt.Errorf("fan-out test failed: %w", err)
+	}
+	if err != nil {
+		fmt.Printf("⚠️  Fan-out test stopped early: %v\n", err)
+	}
+
+	h.printSummary(results)
+	return h.generateReport(metricsCollector, parsed, results, opts)
+}
+
+// parseArgs 解析命令行参数
+func (h *FanoutCommandHandler) parseArgs(args []string) (*fanoutArgs, error) {
+	parsed := &fanoutArgs{
+		transport: "redis",
+		timeout:   5 * time.Second,
+		config:    fanout.NewDefaultConfig(),
+	}
+
+	for i := 0; i < len(args); i++ {
+		if i+1 >= len(args) {
+			break
+		}
+		value := args[i+1]
+		var err error
+
+		switch args[i] {
+		case "--transport", "-t":
+			parsed.transport = strings.ToLower(value)
+		case "--target":
+			parsed.target = value
+		case "--password", "-a":
+			parsed.password = value
+		case "--db":
+			parsed.db, err = strconv.Atoi(value)
+		case "--broadcast-url":
+			parsed.broadcast = value
+		case "--channel":
+			parsed.config.Channel = value
+		case "--subscribers", "-s":
+			parsed.config.Subscribers, err = parseIntList(value)
+		case "--rate", "-r":
+			parsed.config.Rate, err = strconv.Atoi(value)
+		case "--duration", "-d":
+			parsed.config.Duration, err = time.ParseDuration(value)
+		case "--payload":
+			parsed.config.PayloadSize, err = strconv.Atoi(value)
+		case "--settle":
+			parsed.config.Settle, err = time.ParseDuration(value)
+		case "--drain":
+			parsed.config.Drain, err = time.ParseDuration(value)
+		case "--timeout":
+			parsed.timeout, err = time.ParseDuration(value)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", args[i], err)
+		}
+		i++
+	}
+
+	if parsed.target == "" {
+		switch parsed.transport {
+		case "redis":
+			parsed.target = "localhost:6379"
+		case "nats":
+			parsed.target = "localhost:4222"
+		case "websocket", "ws":
+			parsed.target = "ws://localhost:7070/ws"
+		}
+	}
+
+	if err := parsed.config.Validate(); err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+// createTransport 根据参数创建传输层
+func (h *FanoutCommandHandler) createTransport(ctx context.Context, parsed *fanoutArgs) (fanout.Transport, error) {
+	connectCtx, cancel := context.WithTimeout(ctx, parsed.timeout)
+	defer cancel()
+
+	switch parsed.transport {
+	case "redis":
+		return fanout.NewRedisTransport(connectCtx, parsed.target, parsed.password, parsed.db)
+	case "nats":
+		return fanout.NewNATSTransport(connectCtx, parsed.target, parsed.timeout)
+	case "websocket", "ws":
+		return fanout.NewWebSocketTransport(parsed.target, parsed.broadcast, parsed.timeout)
+	default:
+		return nil, fmt.Errorf("unsupported transport: %s (expected redis, nats or websocket)", parsed.transport)
+	}
+}
+
+// printSummary 输出各级结果汇总表
+func (h *FanoutCommandHandler) printSummary(results []fanout.StepResult) {
+	fmt.Printf("\n📊 Fan-out Scalability Summary\n")
+	fmt.Printf("%12s %10s %12s %10s %10s %12s %12s %12s\n",
+		"subscribers", "published", "delivered", "dropped", "drop%", "p50", "p99", "max")
+	for _, r := range results {
+		fmt.Printf("%12d %10d %12d %10d %9.2f%% %12v %12v %12v\n",
+			r.Subscribers, r.Published, r.Delivered, r.Dropped, r.DropRate,
+			r.LatencyP50.Round(time.Microsecond), r.LatencyP99.Round(time.Microsecond), r.LatencyMax.Round(time.Microsecond))
+	}
+	fmt.Println()
+}
+
+// generateReport 生成报告
+func (h *FanoutCommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}], parsed *fanoutArgs, results []fanout.StepResult, opts *runOptions) error {
+	snapshot := collector.Snapshot()
+
+	steps := make([]map[string]interface{}, 0, len(results))
+	for _, r := range results {
+		steps = append(steps, r.ToMap())
+	}
+	snapshot.Protocol["target"] = parsed.target
+	snapshot.Protocol["channel"] = parsed.config.Channel
+	snapshot.Protocol["publish_rate"] = parsed.config.Rate
+	snapshot.Protocol["payload_size"] = parsed.config.PayloadSize
+	snapshot.Protocol["fanout_steps"] = steps
+
+	if opts.publishSnapshot(snapshot) {
+		return nil
+	}
+
+	report := reporting.ConvertFromMetricsSnapshot(snapshot)
+	reportConfig := reporting.NewStandardReportConfig("fanout_" + snapshot.Protocol["protocol"].(string))
+	opts.applyToReport(snapshot, report, reportConfig)
+	generator := reporting.NewReportGenerator(reportConfig)
+	if err := generator.Generate(report); err != nil {
+		return err
+	}
+	return opts.resultError(report)
+}
+
+// parseIntList 解析逗号分隔的整数列表
+func parseIntList(value string) ([]int, error) {
+	var list []int
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, n)
+	}
+	return list, nil
+}
+
+// GetHelp 获取帮助信息
+func (h *FanoutCommandHandler) GetHelp() string {
+	return `Pub/Sub Fan-out Scalability Test
+
+USAGE:
+  abc-runner fanout [options]
+
+DESCRIPTION:
+  Hold N subscriber connections while publishing at a fixed rate, and
+  measure delivery latency percentiles and dropped-message rates as N
+  scales. Subscribers opened in one step are kept for the next, so each
+  step only adds the missing connections.
+
+OPTIONS:
+  --help, -h               Show this help message
+  --transport, -t NAME     Transport: redis (default), nats or websocket
+  --target ADDR            Redis/NATS address (host:port) or WebSocket URL
+                           (defaults: localhost:6379, localhost:4222,
+                           ws://localhost:7070/ws)
+  --password, -a PASS      Redis password
+  --db N                   Redis database (default: 0)
+  --broadcast-url URL      WebSocket broadcast endpoint (default: derived
+                           from --target as http://host:port/broadcast)
+  --channel NAME           Channel / subject name (default: abc-runner-fanout)
+  --subscribers, -s LIST   Subscriber counts per step (default: 10,100,1000)
+  --rate, -r N             Messages published per second (default: 100)
+  --duration, -d DUR       Publish duration per step (default: 10s)
+  --payload N              Message size in bytes (default: 128)
+  --settle DUR             Wait after subscribing before publishing (default: 1s)
+  --drain DUR              Max wait for in-flight messages per step (default: 2s)
+  --timeout DUR            Connection timeout (default: 5s)
+
+EXAMPLES:
+  abc-runner fanout --transport redis --target localhost:6379 -s 10,100,1000 -r 200
+  abc-runner fanout --transport nats --target localhost:4222 -s 100,500 -d 30s
+  abc-runner fanout --transport websocket --target ws://localhost:7070/ws -s 50,200
+
+NOTE:
+  Latency is measured from publish to receipt on the same host; messages
+  not received within the drain window are counted as dropped.` + runOptionsHelp + "\n"
+}
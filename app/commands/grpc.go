@@ -3,7 +3,9 @@ package commands
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"abc-runner/app/adapters/grpc/config"
@@ -51,11 +53,20 @@ func (h *GRPCCommandHandler) Execute(ctx context.Context, args []string) error {
 	// 解析命令行参数
 	config, err := h.parseArgs(args)
 	if err != nil {
-		return fmt.Errorf("failed to parse arguments: %w", err)
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	// 解析通用运行选项
+	opts, err := parseRunOptions(ctx, "grpc", args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+	if printConfigRequested(args) {
+		return printEffectiveConfig("grpc", config, opts)
 	}
 
 	// 创建指标收集器
-	metricsConfig := metrics.DefaultMetricsConfig()
+	metricsConfig := opts.collectorConfig()
 	metricsCollector := metrics.NewBaseCollector(metricsConfig, map[string]interface{}{
 		"protocol":  "grpc",
 		"test_type": "performance",
@@ -88,13 +99,13 @@ func (h *GRPCCommandHandler) Execute(ctx context.Context, args []string) error {
 	fmt.Printf("Operations: %d, Concurrency: %d, Data Size: %d bytes\n",
 		config.BenchMark.Total, config.BenchMark.Parallels, config.BenchMark.DataSize)
 
-	err = h.runPerformanceTest(ctx, adapter, config, metricsCollector)
+	err = h.runPerformanceTest(ctx, adapter, config, metricsCollector, opts)
 	if err != nil {
 		return fmt.Errorf("performance test failed: %w", err)
 	}
 
 	// 生成并显示报告
-	return h.generateReport(metricsCollector)
+	return h.generateReport(metricsCollector, opts)
 }
 
 // GetHelp 获取帮助信息
@@ -114,6 +125,9 @@ OPTIONS:
   --service NAME      gRPC service name (default: TestService)
   --method NAME       gRPC method name (default: Echo)
   --test-case TYPE    Test case type (default: unary_call)
+  --health-service NAME
+                      Service checked by health_check (default: "", the
+                      overall server health)
   -c COUNT            Concurrent connections (default: 10)
   -n COUNT            Total operations (default: 1000)
   --timeout DURATION  Operation timeout (default: 30s)
@@ -125,15 +139,21 @@ TEST CASES:
   server_stream       Server streaming call
   client_stream       Client streaming call
   bidirectional_stream Bidirectional streaming call
+  health_check        grpc.health.v1 Health/Check; only SERVING counts as success
+  reflection_list     List services via server reflection (v1, falls back to v1alpha)
   
 EXAMPLES:
   abc-runner grpc --help
   abc-runner grpc --address localhost --port 50051
   abc-runner grpc --service MyService --method GetData --test-case unary_call
   abc-runner grpc --address 192.168.1.100 --port 9090 -c 20 -n 5000
+  abc-runner grpc --test-case health_check --health-service my.pkg.MyService --timeout 1s
+  abc-runner grpc --test-case reflection_list -n 100
 
 NOTE: 
-  This implementation performs real gRPC performance testing with metrics collection.`
+  This implementation performs real gRPC performance testing with metrics collection.
+  Calls are broken down by canonical status code (OK, UNAVAILABLE, DEADLINE_EXCEEDED,
+  RESOURCE_EXHAUSTED, ...) with call count, share and latency per code.` + configLayersHelp + runOptionsHelp
 }
 
 // parseArgs 解析命令行参数
@@ -141,6 +161,11 @@ func (h *GRPCCommandHandler) parseArgs(args []string) (*config.GRPCConfig, error
 	// 创建默认配置
 	gRPCConfig := config.NewDefaultGRPCConfig()
 
+	// 依次应用--config文件、ABC_GRPC_*环境变量与--set，专用参数随后覆盖
+	if err := applyConfigLayers("grpc", args, gRPCConfig); err != nil {
+		return nil, err
+	}
+
 	// 解析参数
 	for i := 0; i < len(args); i++ {
 		switch args[i] {
@@ -168,7 +193,7 @@ func (h *GRPCCommandHandler) parseArgs(args []string) (*config.GRPCConfig, error
 			}
 		case "--test-case":
 			if i+1 < len(args) {
-				validCases := []string{"unary_call", "server_stream", "client_stream", "bidirectional_stream"}
+				validCases := []string{"unary_call", "server_stream", "client_stream", "bidirectional_stream", "health_check", "reflection_list"}
 				testCase := args[i+1]
 				for _, valid := range validCases {
 					if testCase == valid {
@@ -201,6 +226,11 @@ func (h *GRPCCommandHandler) parseArgs(args []string) (*config.GRPCConfig, error
 				}
 				i++
 			}
+		case "--health-service":
+			if i+1 < len(args) {
+				gRPCConfig.GRPCSpecific.HealthService = args[i+1]
+				i++
+			}
 		case "--tls":
 			gRPCConfig.GRPCSpecific.TLS.Enabled = true
 		case "--token":
@@ -223,6 +253,7 @@ func (h *GRPCCommandHandler) runPerformanceTest(
 	adapter interfaces.ProtocolAdapter,
 	config *config.GRPCConfig,
 	metricsCollector interfaces.DefaultMetricsCollector,
+	opts *runOptions,
 ) error {
 	// 创建操作工厂
 	operationFactory := operations.NewOperationFactory(config)
@@ -236,6 +267,7 @@ func (h *GRPCCommandHandler) runPerformanceTest(
 		config.BenchMark.Parallels*10, // job buffer
 		config.BenchMark.Parallels*10, // result buffer
 	)
+	opts.applyToEngine(engine, metricsCollector)
 
 	// 记录测试开始时间
 	testStartTime := time.Now()
@@ -245,6 +277,7 @@ func (h *GRPCCommandHandler) runPerformanceTest(
 	if err != nil {
 		return fmt.Errorf("benchmark execution failed: %w", err)
 	}
+	opts.finishRun()
 
 	// 计算实际测试时间
 	actualTestDuration := time.Since(testStartTime)
@@ -264,24 +297,80 @@ func (h *GRPCCommandHandler) runPerformanceTest(
 		fmt.Printf("Actual RPS: %.2f calls/sec\n", actualRPS)
 	}
 
+	protocolMetrics := map[string]interface{}{
+		"protocol":         "grpc",
+		"test_type":        "performance",
+		"actual_duration":  actualTestDuration,
+		"execution_result": result,
+		"service":          config.GRPCSpecific.ServiceName,
+		"method":           config.GRPCSpecific.MethodName,
+		"test_case":        config.BenchMark.TestCase,
+		"target":           config.Connection.Address,
+	}
+
+	// 按gRPC状态码的调用统计
+	if statusAdapter, ok := adapter.(interface {
+		GetStatusCodeStats() []operations.StatusCodeStats
+	}); ok {
+		if stats := statusAdapter.GetStatusCodeStats(); stats != nil {
+			h.printStatusCodeStats(stats)
+			protocolMetrics["status_codes"] = stats
+		}
+	}
+
+	// 健康检查与反射探测统计
+	if probeAdapter, ok := adapter.(interface {
+		GetProbeStats() *operations.ProbeStats
+	}); ok {
+		if stats := probeAdapter.GetProbeStats(); stats != nil {
+			h.printProbeStats(stats)
+			protocolMetrics["probe"] = stats
+		}
+	}
+
 	// 更新收集器的协议数据，包含实际测试时间
 	if baseCollector, ok := metricsCollector.(*metrics.BaseCollector[map[string]interface{}]); ok {
-		baseCollector.UpdateProtocolMetrics(map[string]interface{}{
-			"protocol":         "grpc",
-			"test_type":        "performance",
-			"actual_duration":  actualTestDuration,
-			"execution_result": result,
-			"service":          config.GRPCSpecific.ServiceName,
-			"method":           config.GRPCSpecific.MethodName,
-		})
+		baseCollector.UpdateProtocolMetrics(protocolMetrics)
 	}
 
 	return nil
 }
 
+// printStatusCodeStats 输出按gRPC状态码的调用数与延迟
+func (h *GRPCCommandHandler) printStatusCodeStats(stats []operations.StatusCodeStats) {
+	fmt.Printf("\n🔢 Status Codes:\n")
+	fmt.Printf("  %-20s %10s %8s %12s %12s %12s\n", "code", "calls", "share", "avg", "p99", "max")
+	for _, code := range stats {
+		fmt.Printf("  %-20s %10d %7.2f%% %12v %12v %12v\n", code.Code, code.Count, code.Percent,
+			code.Latency.Average.Round(time.Microsecond), code.Latency.P99.Round(time.Microsecond), code.Latency.Max.Round(time.Microsecond))
+	}
+}
+
+// printProbeStats 输出健康检查与反射探测统计
+func (h *GRPCCommandHandler) printProbeStats(stats *operations.ProbeStats) {
+	printCounts := func(title string, counts map[string]int64) {
+		keys := make([]string, 0, len(counts))
+		for key := range counts {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Printf("  %s %-18s %d\n", title, key, counts[key])
+		}
+	}
+
+	fmt.Printf("\n🩺 Probe Results: %d probes\n", stats.Probes)
+	printCounts("status:", stats.Statuses)
+	printCounts("error: ", stats.Errors)
+	if stats.ReflectionVersion != "" {
+		fmt.Printf("  reflection %s, %d services: %s\n",
+			stats.ReflectionVersion, len(stats.Services), strings.Join(stats.Services, ", "))
+	}
+}
+
 // generateReport 生成报告
 // generateReport 生成gRPC性能测试报告
-func (h *GRPCCommandHandler) generateReport(metricsCollector interfaces.DefaultMetricsCollector) error {
+func (h *GRPCCommandHandler) generateReport(metricsCollector interfaces.DefaultMetricsCollector, opts *runOptions) error {
 	snapshot := metricsCollector.Snapshot()
 	if snapshot == nil {
 		return fmt.Errorf("failed to get metrics snapshot")
@@ -311,6 +400,7 @@ func (h *GRPCCommandHandler) generateReport(metricsCollector interfaces.DefaultM
 			baseSnapshot.Core.Throughput.RPS = float64(total) / seconds
 			baseSnapshot.Core.Throughput.ReadRPS = float64(baseSnapshot.Core.Operations.Read) / seconds
 			baseSnapshot.Core.Throughput.WriteRPS = float64(baseSnapshot.Core.Operations.Write) / seconds
+			baseSnapshot.Core.Throughput.ReadMBps, baseSnapshot.Core.Throughput.WriteMBps = metrics.Bandwidth(baseSnapshot.Core.Operations, actualDuration)
 		}
 
 		// 使用更新后的数据
@@ -338,11 +428,20 @@ func (h *GRPCCommandHandler) generateReport(metricsCollector interfaces.DefaultM
 	}
 	fmt.Printf("=====================================\n")
 
+	// 存在快照接收器时（如分布式agent模式）交由接收器处理，不生成本地报告
+	if opts.publishSnapshot(snapshot) {
+		return nil
+	}
+
 	// 生成结构化文件报告（使用修正后的数据）
 	report := reporting.ConvertFromMetricsSnapshot(snapshot)
 	reportConfig := reporting.NewStandardReportConfig("grpc")
+	opts.applyToReport(snapshot, report, reportConfig)
 	generator := reporting.NewReportGenerator(reportConfig)
-	return generator.Generate(report)
+	if err := generator.Generate(report); err != nil {
+		return err
+	}
+	return opts.resultError(report)
 }
 
 // GetProtocolName 获取协议名称
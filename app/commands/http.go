@@ -2,16 +2,25 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"os/signal"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"abc-runner/app/adapters/http"
 	httpConfig "abc-runner/app/adapters/http/config"
+	"abc-runner/app/adapters/http/connection"
 	"abc-runner/app/adapters/http/operations"
 	"abc-runner/app/core/execution"
 	"abc-runner/app/core/interfaces"
 	"abc-runner/app/core/metrics"
+	"abc-runner/app/core/transport"
 	"abc-runner/app/reporting"
 )
 
@@ -44,24 +53,41 @@ func (h *HttpCommandHandler) Execute(ctx context.Context, args []string) error {
 	}
 
 	// 解析命令行参数
-	config, err := h.parseArgs(args)
+	config, workload, err := h.parseArgs(args)
 	if err != nil {
-		return fmt.Errorf("failed to parse arguments: %w", err)
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	// 解析通用运行选项
+	opts, err := parseRunOptions(ctx, "http", args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+	if printConfigRequested(args) {
+		return printEffectiveConfig("http", config, opts)
 	}
 
 	// 创建HTTP适配器
-	metricsConfig := metrics.DefaultMetricsConfig()
+	metricsConfig := opts.collectorConfig()
 	metricsCollector := metrics.NewBaseCollector(metricsConfig, map[string]interface{}{
 		"protocol":  "http",
 		"test_type": "performance",
 	})
 	defer metricsCollector.Stop()
 
+	// 代理模式：观察真实应用流量而不是生成负载
+	if config.Proxy.Enabled() {
+		return h.runProxy(ctx, config, metricsCollector, opts)
+	}
+
 	// 直接使用MetricsCollector创建HTTP适配器
 	adapter := http.NewHttpAdapter(metricsCollector)
 
 	// 连接并执行测试
-	if err := adapter.Connect(ctx, config); err != nil {
+	if err := adapter.Connect(ctx, config); errors.Is(err, connection.ErrTokenFetch) {
+		// 认证失败时不回退到模拟模式
+		return err
+	} else if err != nil {
 		fmt.Printf("⚠️  Connection failed to %s: %v\n", config.Connection.BaseURL, err)
 		fmt.Printf("🔍 Possible causes: DNS resolution failure, network issues, server unreachable, or SSL/TLS errors\n")
 		// 继续执行，但使用模拟模式
@@ -74,14 +100,117 @@ func (h *HttpCommandHandler) Execute(ctx context.Context, args []string) error {
 	fmt.Printf("🚀 Starting HTTP performance test...\n")
 	fmt.Printf("Target URL: %s\n", config.Connection.BaseURL)
 	fmt.Printf("Requests: %d, Concurrency: %d\n", config.Benchmark.Total, config.Benchmark.Parallels)
+	if config.Connection.Transport == transport.IOURing {
+		fmt.Printf("🧪 Transport: io_uring (experimental)\n")
+	}
+	if cookies := config.Benchmark.Cookies; cookies.Enabled {
+		if cookies.Scope == httpConfig.CookieScopeShared {
+			fmt.Printf("Cookies: one jar shared by all virtual users\n")
+		} else {
+			fmt.Printf("Cookies: one jar per virtual user (%d)\n", config.Benchmark.Parallels)
+		}
+	}
+	if n := len(config.Connection.Proxies); n > 0 {
+		fmt.Printf("Outbound proxies: %d (rotated per connection)\n", n)
+	}
+	if contract := config.Benchmark.Contract; contract.Spec != "" {
+		fmt.Printf("Contract: checking %.0f%% of responses against %s\n", contract.GetSampleRate()*100, contract.Spec)
+	}
+	if config.Benchmark.TestCase == "weighted" {
+		fmt.Printf("Endpoints: %d weighted request templates\n", len(config.Requests))
+	}
+	if config.Benchmark.TestCase == "stream" {
+		stream := config.Benchmark.Stream
+		fmt.Printf("Streams: %s %s, kept open up to %v", stream.Mode, stream.Path, stream.Duration)
+		if stream.MaxEvents > 0 {
+			fmt.Printf(" or %d events", stream.MaxEvents)
+		}
+		fmt.Printf("\n")
+	}
+	if config.Benchmark.TestCase == "scenario" {
+		fmt.Printf("Scenario: %d chained steps per operation\n", len(config.Benchmark.Scenario.Steps))
+	}
+	if config.Benchmark.TestCase == "graphql" {
+		fmt.Printf("GraphQL: %d operation(s) posted to %s\n", len(config.Benchmark.GraphQL.Operations), config.Benchmark.GraphQL.Path)
+	}
+	if config.Benchmark.TestCase == "replay" {
+		fmt.Printf("Replay: %d distinct requests", len(config.Requests))
+		if config.Benchmark.RampUp > 0 {
+			fmt.Printf(", paced over %v (%.1f req/s)", config.Benchmark.RampUp, float64(config.Benchmark.Total)/config.Benchmark.RampUp.Seconds())
+		}
+		fmt.Printf("\n")
+		if workload != nil && workload.Traffic.Profile != nil {
+			h.printWorkloadProfile("📐 Recorded workload profile", workload.Traffic.Profile)
+		}
+	}
 
-	err = h.runPerformanceTest(ctx, adapter, config, metricsCollector)
+	err = h.runPerformanceTest(ctx, adapter, config, metricsCollector, opts)
 	if err != nil {
 		return fmt.Errorf("performance test failed: %w", err)
 	}
 
+	if stats, ok := adapter.TransportStats(); ok {
+		printTransportStats(stats)
+	}
+	if phases, ok := adapter.LatencyPhases(); ok {
+		h.reportLatencyPhases(phases, metricsCollector)
+	}
+	if config.Benchmark.TestCase == "cache_mix" {
+		h.reportCacheStats(adapter, metricsCollector)
+	}
+	if config.Benchmark.TestCase == "page_load" {
+		h.reportPageLoadStats(adapter, metricsCollector)
+	}
+	if config.Benchmark.TestCase == "webhook" {
+		h.reportWebhookStats(adapter, metricsCollector)
+	}
+	if config.Benchmark.TestCase == "stream" {
+		h.reportStreamStats(adapter, metricsCollector)
+	}
+	if config.Benchmark.TestCase == "weighted" || config.Benchmark.TestCase == "replay" {
+		if stats, ok := adapter.EndpointStats(); ok {
+			h.reportEndpointStats("🧭 Endpoint results", "endpoints", stats, metricsCollector)
+		}
+	}
+	if config.Benchmark.TestCase == "replay" {
+		if profile, ok := adapter.WorkloadProfile(); ok {
+			h.printWorkloadProfile("📐 Replayed workload profile", profile)
+			protocol := metricsCollector.Snapshot().Protocol
+			if protocol == nil {
+				protocol = make(map[string]interface{})
+			}
+			protocol["workload_profile"] = profile
+			metricsCollector.UpdateProtocolMetrics(protocol)
+		}
+	}
+	if config.Benchmark.TestCase == "scenario" {
+		if stats, ok := adapter.ScenarioStepStats(); ok {
+			h.reportEndpointStats("🧭 Scenario step results", "scenario_steps", stats, metricsCollector)
+		}
+	}
+	if config.Benchmark.TestCase == "graphql" {
+		if stats, ok := adapter.GraphQLStats(); ok {
+			h.reportGraphQLStats(stats, metricsCollector)
+		}
+	}
+	if stats, ok := adapter.CookieStats(); ok {
+		h.reportCookieStats(stats, metricsCollector)
+	}
+	if stats, ok := adapter.ProxyStats(); ok {
+		h.reportProxyStats(stats, metricsCollector)
+	}
+	if stats, ok := adapter.OAuth2Stats(); ok {
+		h.reportOAuth2Stats(stats, metricsCollector)
+	}
+	if stats, ok := adapter.ValidationStats(); ok {
+		h.reportValidationStats(stats, metricsCollector)
+	}
+	if stats, ok := adapter.ContractStats(); ok {
+		h.reportContractStats(stats, metricsCollector)
+	}
+
 	// 生成并显示报告
-	return h.generateReport(metricsCollector)
+	return h.generateReport(metricsCollector, opts)
 }
 
 // GetHelp 获取帮助信息
@@ -100,19 +229,269 @@ OPTIONS:
   --method GET   HTTP method (GET, POST, PUT, DELETE)
   -n COUNT       Number of requests (default: 1000)
   -c COUNT       Concurrent connections (default: 10)
-  
+  --preset NAME  Use a predefined workload: cache (CDN / reverse proxy),
+                 page (browser-like page load), webhook (async API with callback),
+                 stream (Server-Sent Events or chunked streaming responses),
+                 graphql (GraphQL queries and mutations)
+  --config FILE  Load an HTTP config file (see config/http.yaml); options given
+                 on the command line override the file
+  --body-template T  Send bodies built from template T instead of generated JSON
+                 (switches the default GET test to POST). Placeholders: {{seq}}
+                 (request number), {{uuid}}, {{timestamp [s|ms|ns|rfc3339]}},
+                 {{randInt [MIN MAX]}}, {{randFloat [MIN MAX]}}, {{randString [N]}},
+                 {{pick A B C}}, {{name}}, {{email}} and {{csv.COLUMN}}. With
+                 test_case weighted, paths and bodies under requests: may use
+                 the same placeholders
+  --data-file FILE  CSV file for {{csv.COLUMN}}; the first row names the
+                 columns, request N uses row N modulo the row count
+  --assert-status LIST   Responses must have one of these status codes, e.g.
+                 200,201 or 2xx or 200-299 (then non-2xx codes may pass)
+  --assert-header 'NAME[: TEXT]'  Header NAME must be present (and contain TEXT)
+  --assert-body TEXT     Response body must contain TEXT
+  --assert-json 'PATH[=VALUE]'  JSON body must have PATH, e.g. $.data.id or
+                 $.items[0].state (equal to VALUE when given)
+  --contract FILE  Check sampled responses against an OpenAPI 3.x or Swagger 2.0
+                 document and report drift per operation: undocumented paths
+                 and status codes, undeclared content types, and JSON bodies
+                 that break the schema (types, required and undocumented
+                 properties when additionalProperties is false, enums,
+                 formats, bounds). Violations are reported only and do not
+                 fail requests
+  --contract-sample RATE  Fraction of responses to check, 0-1 (default: 0.1)
+  --cookies      Keep cookies: Set-Cookie values are stored and sent on later
+                 requests, redirects and scenario steps, so login sessions and
+                 load balancer affinity cookies stick to each virtual user
+  --cookie-scope SCOPE  worker (default): one cookie jar per virtual user
+                 (worker); shared: all virtual users share one jar. Implies
+                 --cookies
+  --transport KIND  Network transport: net or io_uring (default: net). io_uring
+                 is experimental, Linux only, and requires a build with
+                 -tags uring; connections are still dialed by Go, reads and
+                 writes are batched through the ring
+  --via-proxy URL   Send requests through an outbound proxy: http://, https://,
+                 socks5:// or socks5h:// (DNS resolved by the proxy), with
+                 optional user:password@. Repeat or separate with commas to
+                 rotate across several proxies; each new connection goes to
+                 the next proxy and per-proxy results are reported
+  --proxy-list FILE  Read outbound proxies from FILE, one URL per line
+                 (connection.proxies in --config)
+
+OAUTH2 AUTHENTICATION (auth.type: oauth2 in --config, or):
+  --oauth2-token-url URL       Token endpoint; enables OAuth2 bearer authentication
+  --oauth2-client-id ID        Client ID (client_credentials grant by default)
+  --oauth2-client-secret S     Client secret, sent with HTTP Basic authentication
+                               (auth.oauth2.auth_style: body sends it in the form)
+  --oauth2-scope LIST          Scopes to request, space or comma separated
+  --oauth2-refresh-token T     Use the refresh_token grant with token T
+
+  A token is fetched before the run, refreshed shortly before it expires
+  (auth.oauth2.refresh_before, default 30s) and after a 401 response, which
+  is then retried once with the new token. Time spent on the token endpoint
+  is excluded from request latencies; token fetches and refreshes are
+  reported separately.
+
+RESPONSE ASSERTIONS:
+  The --assert-* options may be repeated and apply to every response; in
+  --config they are written under benchmark.assert, and requests: templates
+  and scenario steps may add their own assert: lists (status, header, json or
+  body_contains, with equals, contains or matches for header and json).
+  Responses that fail an assertion count as validation_failed errors, apart
+  from transport errors and unexpected status codes; the report lists the
+  failures per assertion with the first few response bodies.
+
+MULTI-ENDPOINT SCENARIOS (test_case: weighted in --config):
+  Every request template under requests: (name, method, path or full URL,
+  headers, body, content_type, weight) is executed in proportion to its weight
+  within one run; test_case defaults to weighted when the file does not set
+  it. Latency percentiles and status codes are reported per template, named
+  by name: or by method and path (numeric and UUID segments become {id}).
+
+CACHE PRESET OPTIONS (--preset cache):
+  --cacheable-percent N    Share of requests to cacheable URLs (default: 80)
+  --cache-objects N        Number of distinct cacheable objects (default: 100)
+  --cacheable-path PATH    Cacheable URL prefix (default: /static/object)
+  --uncacheable-path PATH  Uncacheable URL prefix, requested with a unique
+                           query string and no-cache (default: /api/dynamic)
+
+  Cache status is read from Cache-Status, X-Cache, X-Cache-Status and
+  CF-Cache-Status, falling back to Age > 0; hit ratio and origin offload
+  (requests and bytes served from cache) are added to the report.
+
+PAGE PRESET OPTIONS (--preset page):
+  --page-path PATH         Page to load (default: /)
+  --page-assets LIST       Comma-separated extra assets, resolved against the page
+  --page-parallelism N     Concurrent asset requests per page (default: 6)
+  --max-assets N           Maximum assets per page, 0 for no limit (default: 100)
+  --no-discover            Do not parse the page for scripts, stylesheets and images
+
+  Each operation loads the page and then its assets; latency percentiles in
+  the report are page-load times (document plus all assets). Document and
+  per-asset request latencies are reported separately.
+
+WEBHOOK PRESET OPTIONS (--preset webhook):
+  --submit-path PATH       Path jobs are POSTed to (default: /jobs)
+  --callback-listen ADDR   Address of the built-in callback receiver (default: :8099)
+  --callback-url URL       Callback URL sent to the service, when the receiver is
+                           reached through another host name or a proxy
+                           (default: http://localhost:PORT/callback)
+  --id-header NAME         Header carrying the correlation ID (default: X-Correlation-ID)
+  --id-field PATH          JSON field of the callback body holding the correlation
+                           ID, dotted for nested fields (default: correlation_id)
+  --callback-timeout D     How long to wait for each callback (default: 30s)
+
+  Each job is submitted with a unique correlation ID in the ID header and in
+  the body ("correlation_id"), together with the callback URL (X-Callback-URL
+  header and "callback_url" field). The service must echo the ID in the
+  callback's header or JSON body. Latency percentiles in the report are
+  submit-to-callback times; submit latency, callback delay after the submit
+  response, timeouts and late or unknown callbacks are reported separately.
+  -c is the number of jobs in flight.
+
+STREAM PRESET OPTIONS (--preset stream):
+  --stream-path PATH       Stream to open (default: /events)
+  --stream-mode MODE       sse counts Server-Sent Events (a blank line ends an event;
+                           comment-only keep-alives are not counted), chunked counts
+                           every piece of data as it arrives (default: sse)
+  --stream-duration D      How long to keep each stream open (default: 10s)
+  --max-events N           Close a stream after N events, 0 for no limit (default: 0)
+  --idle-timeout D         Fail a stream that goes D without an event, 0 to wait
+                           until --stream-duration (default: 0)
+
+  Each operation opens one stream and reads it until the duration, the event
+  limit or the end of the response; -c is the number of streams open at once
+  and -n the number of streams. Latency percentiles in the report are stream
+  lifetimes. Time to first byte, time to first event, inter-event latency,
+  events/sec (overall and per stream) and why streams ended are reported
+  separately. A stream is successful when it ends normally, reaches the
+  duration or the event limit; errors, idle timeouts and non-2xx responses
+  fail it.
+
+GRAPHQL (--preset graphql, or test_case: graphql in --config):
+  --query DOC              GraphQL query or mutation to send; repeat for a mix of
+                           operations (implies --preset graphql)
+  --query-file FILE        Read the query or mutation from FILE
+  --variables JSON         Variables of the preceding query, a JSON object that may
+                           use the --body-template placeholders, e.g.
+                           '{"id": {{randInt 1 1000}}}'
+  --operation-name NAME    Operation of the preceding query to run when its document
+                           defines several; also its name in the report
+  --graphql-path PATH      GraphQL endpoint (default: /graphql)
+
+  Requests are POSTed as JSON with query, operationName and variables, and
+  picked in proportion to graphql.operations[].weight in --config (evenly
+  from the command line). A response with a non-empty errors array is a
+  graphql_error failure even with HTTP 200. Latency percentiles, status
+  codes, GraphQL errors, responses with partial data and errors per
+  extensions.code are reported per operation name rather than per URL.
+  Subscriptions are not supported.
+
+PROXY MODE (observe a real application instead of generating load):
+  --proxy ADDR             Listen on ADDR and reverse-proxy every request to --url;
+                           the latency seen by the clients and the request and
+                           response sizes are recorded and reported like a
+                           benchmark, with "METHOD /path" as the operation type
+                           (numeric and UUID path segments become {id})
+  --forward                Act as a forward proxy instead: point the application's
+                           HTTP_PROXY at ADDR. HTTPS requests (CONNECT) are
+                           tunnelled and counted but cannot be timed
+  --proxy-duration DUR     Stop after DUR (default: run until interrupted)
+  --export-workload FILE   Write the observed traffic as a replayable workload:
+                           every distinct request with its headers (credentials
+                           are left out), a body sample and its observed count
+                           as weight, plus per-endpoint sizes and latencies
+
+REPLAY:
+  --workload FILE          Replay a workload exported by --export-workload: requests
+                           are picked by weight, and the observed request count,
+                           peak concurrency and duration become -n, -c and the
+                           pacing, reproducing the recorded mix and rate. --url,
+                           -n and -c override the recorded values
+
+  Both modes print a workload profile (also written to the report and to the
+  exported workload): operation mix, URL popularity curve with a fitted Zipf
+  exponent, request/response size distribution and inter-arrival times with
+  their coefficient of variation (≈1 Poisson-like, <1 paced, >1 bursty). A
+  replay shows the recorded profile first and the replayed one at the end.
+
+REQUEST CHAINING (test_case: scenario in --config):
+  Each operation is one user journey running benchmark.scenario.steps in
+  order, e.g. login, then authorized calls. A step is a request template plus
+  extract rules that capture values from its response into variables:
+    extract:
+      - {var: token, json: $.data.token}      JSON path ($.a.b[0].c)
+      - {var: session, header: X-Session-ID}  response header
+      - {var: order, regex: 'order-(\d+)'}    first capture group of the body
+  Variables are injected as ${name} into later paths, headers and bodies;
+  scenario.variables sets initial values and ${job_id} and ${rand} are
+  built in. A failed step or extraction ends the journey as a failed
+  operation. Latency percentiles in the report are journey times; every step
+  gets its own latency and status-code breakdown. test_case defaults to
+  scenario when the file defines steps.
+
+IMPORT (record real traffic, replay it as a load test):
+  --from-har FILE          Convert the requests of a HAR capture (browser dev
+                           tools "Save all as HAR", or a proxy export) into a
+                           scenario: every operation replays them in recorded
+                           order with their headers and bodies
+  --har-include REGEX      Only import requests whose URL matches REGEX, e.g.
+                           '/api/' to leave out scripts, styles and images
+  --from-curl 'curl ...'   Convert a curl command line ("Copy as cURL") into a
+                           scenario step; repeat for several chained steps
+
+  The origin of the first request becomes the target URL; requests to the
+  same origin are replayed as paths, so --url points the recorded traffic at
+  another environment, while requests to other hosts keep their full URL.
+  Non-HTTP(S) entries, binary bodies and methods other than GET, POST, PUT,
+  PATCH, DELETE, HEAD and OPTIONS are skipped. Extract rules can be added by
+  copying the steps into benchmark.scenario in a --config file.
+
+  --from-openapi FILE      Turn every operation of an OpenAPI 3.x or Swagger
+                           2.0 document (JSON or YAML) into a weighted request
+                           (test_case: weighted), reported per operationId
+  --openapi-include REGEX  Only import operations whose operationId or
+                           "METHOD /path" matches REGEX
+  --openapi-weight ID=N    Weight of an operation, repeatable (default: the
+                           operation's x-weight extension, else 1; 0 leaves
+                           the operation out)
+
+  Path parameters, required query parameters and required headers take the
+  document's example, default or first enum value; without one, path and
+  query values are randomized per request ({{uuid}}, {{randInt MIN MAX}},
+  ...). JSON bodies use the media type example or are generated from the
+  schema (readOnly properties left out). Deprecated operations and bodies
+  that are neither JSON nor form-encoded are skipped. The first server (or
+  host and basePath) becomes the target URL; --url overrides its origin.
+
 EXAMPLES:
   abc-runner http --help
   abc-runner http --url http://cn.bing.com
   abc-runner http --url http://cn.bing.com -n 100 -c 5
+  abc-runner http --url http://cdn.example.com --preset cache --cacheable-percent 90 --cache-objects 500 -n 10000 -c 50
+  abc-runner http --url http://shop.example.com --preset page --page-path /index.html --page-parallelism 6 -n 500 -c 20
+  abc-runner http --url http://jobs.internal:8080 --preset webhook --submit-path /v1/jobs --callback-url http://runner-host:8099/callback -n 1000 -c 50
+  abc-runner http --url http://push.internal:8080 --preset stream --stream-path /v1/events --stream-duration 30s -n 200 -c 200
+  abc-runner http --url http://api.internal:8080 --query 'query GetUser($id: ID!) { user(id: $id) { id name } }' \
+    --variables '{"id": "{{randInt 1 10000}}"}' --query-file create-order.graphql -n 10000 -c 50
+  abc-runner http --config config/http.yaml --url http://api.internal:8080 -n 10000 -c 50
+  abc-runner http --url http://api.internal:8080 --body-template '{"id":"{{uuid}}","sku":"{{csv.sku}}","qty":{{randInt 1 5}}}' \
+    --data-file skus.csv -n 10000 -c 50
+  abc-runner http --url http://api.internal:8080 --assert-status 200 --assert-json '$.status=ok' -n 1000 -c 20
+  abc-runner http --url http://api.internal:8080 --oauth2-token-url http://auth.internal/oauth/token \
+    --oauth2-client-id bench --oauth2-client-secret s3cret --oauth2-scope orders.read -n 10000 -c 50
+  abc-runner http --url https://api.example.com --via-proxy http://egress-1:3128,socks5h://egress-2:1080 -n 10000 -c 50
+  abc-runner http --url http://api.internal:8080 --proxy :9080 --proxy-duration 10m --export-workload api-workload.yaml
+  abc-runner http --workload api-workload.yaml --url http://api.staging:8080
+  abc-runner http --from-har checkout.har --har-include '/api/' --url https://shop.staging -n 2000 -c 20
+  abc-runner http --from-openapi openapi.yaml --openapi-weight listPets=8 --openapi-weight createPet=1 -n 10000 -c 50
+  abc-runner http --from-openapi openapi.yaml --contract openapi.yaml --contract-sample 0.2 -n 10000 -c 50
+  abc-runner http --from-curl "curl -X POST https://api.internal/orders -H 'Content-Type: application/json' -d '{\"sku\":\"A1\"}'" -n 1000 -c 10
 
 NOTE: 
-  This implementation performs real HTTP performance testing with metrics collection.
-`
+  This implementation performs real HTTP performance testing with metrics collection.` + configLayersHelp + runOptionsHelp + "\n"
 }
 
-// parseArgs 解析命令行参数
-func (h *HttpCommandHandler) parseArgs(args []string) (*httpConfig.HttpAdapterConfig, error) {
+// parseArgs 解析命令行参数，使用--workload时一并返回回放的工作负载
+func (h *HttpCommandHandler) parseArgs(args []string) (*httpConfig.HttpAdapterConfig, *httpConfig.HttpWorkload, error) {
 	// 创建默认配置
 	config := httpConfig.LoadDefaultHttpConfig()
 
@@ -138,12 +517,163 @@ func (h *HttpCommandHandler) parseArgs(args []string) (*httpConfig.HttpAdapterCo
 		},
 	}
 
+	// 指定配置文件时以文件为基础，命令行参数覆盖文件中的值；
+	// 未指定test_case时，定义了scenario.steps则执行请求链场景，定义了graphql.operations则执行GraphQL测试，否则按权重执行各请求模板
+	for i := 0; i < len(args)-1; i++ {
+		if args[i] == "--config" {
+			loaded, err := httpConfig.LoadHttpConfigFile(args[i+1])
+			if err != nil {
+				return nil, nil, err
+			}
+			config = loaded
+			if config.Benchmark.TestCase == "" && len(config.Benchmark.Scenario.Steps) > 0 {
+				config.Benchmark.TestCase = "scenario"
+			} else if config.Benchmark.TestCase == "" && len(config.Benchmark.GraphQL.Operations) > 0 {
+				config.Benchmark.TestCase = "graphql"
+			} else if config.Benchmark.TestCase == "" {
+				config.Benchmark.TestCase = "weighted"
+			}
+			if config.Benchmark.TestCase == "scenario" {
+				if err := config.Benchmark.Scenario.Validate(); err != nil {
+					return nil, nil, err
+				}
+			}
+		}
+	}
+
+	// 环境变量与--set覆盖配置文件，专用参数随后覆盖
+	_, sets, err := configLayerArgs(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := applyConfigOverrides("http", sets, config); err != nil {
+		return nil, nil, err
+	}
+
+	// 从HAR记录或curl命令导入请求链场景，或从OpenAPI文档导入加权请求模板，替换配置文件中的请求；记录的地址可被--url覆盖
+	imported, err := h.importScenario(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	if imported != nil {
+		imported.Apply(config)
+		mode := "a scenario"
+		if config.Benchmark.TestCase == "weighted" {
+			mode = "weighted requests"
+		}
+		fmt.Printf("📥 Imported %d request(s) from %s as %s", imported.Len(), imported.Source, mode)
+		if imported.Skipped > 0 {
+			fmt.Printf(" (%d skipped)", imported.Skipped)
+		}
+		fmt.Printf("\n")
+	}
+
+	// 回放的工作负载及显式指定、优先于工作负载记录值的参数
+	var workload *httpConfig.HttpWorkload
+	urlSet, totalSet, parallelsSet := false, false, false
+	oauth2Set := false
+	var proxies []string
+	var graphQLOperations []httpConfig.HttpGraphQLOperation
+
 	// 解析参数
 	for i := 0; i < len(args); i++ {
 		switch args[i] {
 		case "--url":
 			if i+1 < len(args) {
 				config.Connection.BaseURL = args[i+1]
+				urlSet = true
+				i++
+			}
+		case "--body-template":
+			if i+1 < len(args) {
+				config.Benchmark.Payload.Template = args[i+1]
+				i++
+			}
+		case "--data-file":
+			if i+1 < len(args) {
+				config.Benchmark.Payload.DataFile = args[i+1]
+				i++
+			}
+		case "--assert-status":
+			if i+1 < len(args) {
+				config.Benchmark.Assert = append(config.Benchmark.Assert, httpConfig.HttpAssertion{Status: args[i+1]})
+				i++
+			}
+		case "--assert-header":
+			if i+1 < len(args) {
+				name, contains, _ := strings.Cut(args[i+1], ":")
+				config.Benchmark.Assert = append(config.Benchmark.Assert, httpConfig.HttpAssertion{
+					Header:   strings.TrimSpace(name),
+					Contains: strings.TrimSpace(contains),
+				})
+				i++
+			}
+		case "--assert-body":
+			if i+1 < len(args) {
+				config.Benchmark.Assert = append(config.Benchmark.Assert, httpConfig.HttpAssertion{BodyContains: args[i+1]})
+				i++
+			}
+		case "--assert-json":
+			if i+1 < len(args) {
+				path, equals, _ := strings.Cut(args[i+1], "=")
+				config.Benchmark.Assert = append(config.Benchmark.Assert, httpConfig.HttpAssertion{JSON: path, Equals: equals})
+				i++
+			}
+		case "--contract":
+			if i+1 < len(args) {
+				config.Benchmark.Contract.Spec = args[i+1]
+				i++
+			}
+		case "--contract-sample":
+			if i+1 < len(args) {
+				rate, err := strconv.ParseFloat(args[i+1], 64)
+				if err != nil || rate <= 0 || rate > 1 {
+					return nil, nil, fmt.Errorf("invalid value for --contract-sample: %q (expected a fraction between 0 and 1)", args[i+1])
+				}
+				config.Benchmark.Contract.SampleRate = rate
+				i++
+			}
+		case "--cookies":
+			config.Benchmark.Cookies.Enabled = true
+		case "--cookie-scope":
+			if i+1 >= len(args) {
+				return nil, nil, fmt.Errorf("missing value for --cookie-scope")
+			}
+			config.Benchmark.Cookies.Enabled = true
+			config.Benchmark.Cookies.Scope = args[i+1]
+			if err := config.Benchmark.Cookies.Validate(); err != nil {
+				return nil, nil, err
+			}
+			i++
+		case "--oauth2-token-url":
+			if i+1 < len(args) {
+				config.Auth.Type = "oauth2"
+				config.Auth.OAuth2.TokenURL = args[i+1]
+				i++
+			}
+		case "--oauth2-client-id":
+			if i+1 < len(args) {
+				oauth2Set = true
+				config.Auth.OAuth2.ClientID = args[i+1]
+				i++
+			}
+		case "--oauth2-client-secret":
+			if i+1 < len(args) {
+				oauth2Set = true
+				config.Auth.OAuth2.ClientSecret = args[i+1]
+				i++
+			}
+		case "--oauth2-scope":
+			if i+1 < len(args) {
+				oauth2Set = true
+				config.Auth.OAuth2.Scopes = append(config.Auth.OAuth2.Scopes, strings.Fields(strings.ReplaceAll(args[i+1], ",", " "))...)
+				i++
+			}
+		case "--oauth2-refresh-token":
+			if i+1 < len(args) {
+				oauth2Set = true
+				config.Auth.OAuth2.GrantType = "refresh_token"
+				config.Auth.OAuth2.RefreshToken = args[i+1]
 				i++
 			}
 		case "--method":
@@ -155,6 +685,7 @@ func (h *HttpCommandHandler) parseArgs(args []string) (*httpConfig.HttpAdapterCo
 			if i+1 < len(args) {
 				if count, err := strconv.Atoi(args[i+1]); err == nil {
 					config.Benchmark.Total = count
+					totalSet = true
 				}
 				i++
 			}
@@ -162,27 +693,414 @@ func (h *HttpCommandHandler) parseArgs(args []string) (*httpConfig.HttpAdapterCo
 			if i+1 < len(args) {
 				if count, err := strconv.Atoi(args[i+1]); err == nil {
 					config.Benchmark.Parallels = count
+					parallelsSet = true
+				}
+				i++
+			}
+		case "--preset":
+			if i+1 < len(args) {
+				switch args[i+1] {
+				case "cache":
+					config.Benchmark.TestCase = "cache_mix"
+				case "page":
+					config.Benchmark.TestCase = "page_load"
+				case "webhook":
+					config.Benchmark.TestCase = "webhook"
+				case "stream":
+					config.Benchmark.TestCase = "stream"
+				case "graphql":
+					config.Benchmark.TestCase = "graphql"
+				default:
+					return nil, nil, fmt.Errorf("unknown preset %q (expected cache, page, webhook, stream or graphql)", args[i+1])
 				}
 				i++
 			}
+		case "--cacheable-percent":
+			if i+1 < len(args) {
+				if percent, err := strconv.Atoi(args[i+1]); err == nil && percent >= 0 && percent <= 100 {
+					config.Benchmark.Cache.CacheablePercent = percent
+				}
+				i++
+			}
+		case "--cache-objects":
+			if i+1 < len(args) {
+				if count, err := strconv.Atoi(args[i+1]); err == nil && count > 0 {
+					config.Benchmark.Cache.Objects = count
+				}
+				i++
+			}
+		case "--cacheable-path":
+			if i+1 < len(args) {
+				config.Benchmark.Cache.CacheablePath = strings.TrimRight(args[i+1], "/")
+				i++
+			}
+		case "--uncacheable-path":
+			if i+1 < len(args) {
+				config.Benchmark.Cache.UncacheablePath = strings.TrimRight(args[i+1], "/")
+				i++
+			}
+		case "--page-path":
+			if i+1 < len(args) {
+				config.Benchmark.Page.Path = args[i+1]
+				i++
+			}
+		case "--page-assets":
+			if i+1 < len(args) {
+				for _, asset := range strings.Split(args[i+1], ",") {
+					if asset = strings.TrimSpace(asset); asset != "" {
+						config.Benchmark.Page.Assets = append(config.Benchmark.Page.Assets, asset)
+					}
+				}
+				i++
+			}
+		case "--page-parallelism":
+			if i+1 < len(args) {
+				if count, err := strconv.Atoi(args[i+1]); err == nil && count > 0 {
+					config.Benchmark.Page.Parallelism = count
+				}
+				i++
+			}
+		case "--max-assets":
+			if i+1 < len(args) {
+				if count, err := strconv.Atoi(args[i+1]); err == nil && count >= 0 {
+					config.Benchmark.Page.MaxAssets = count
+				}
+				i++
+			}
+		case "--no-discover":
+			config.Benchmark.Page.Discover = false
+		case "--submit-path":
+			if i+1 < len(args) {
+				config.Benchmark.Webhook.SubmitPath = args[i+1]
+				i++
+			}
+		case "--callback-listen":
+			if i+1 < len(args) {
+				config.Benchmark.Webhook.Listen = args[i+1]
+				i++
+			}
+		case "--callback-url":
+			if i+1 < len(args) {
+				config.Benchmark.Webhook.CallbackURL = args[i+1]
+				i++
+			}
+		case "--id-header":
+			if i+1 < len(args) {
+				config.Benchmark.Webhook.IDHeader = args[i+1]
+				i++
+			}
+		case "--id-field":
+			if i+1 < len(args) {
+				config.Benchmark.Webhook.IDField = args[i+1]
+				i++
+			}
+		case "--callback-timeout":
+			if i+1 < len(args) {
+				timeout, err := time.ParseDuration(args[i+1])
+				if err != nil || timeout <= 0 {
+					return nil, nil, fmt.Errorf("invalid value for --callback-timeout: %q", args[i+1])
+				}
+				config.Benchmark.Webhook.Timeout = timeout
+				i++
+			}
+		case "--stream-path":
+			if i+1 < len(args) {
+				config.Benchmark.Stream.Path = args[i+1]
+				i++
+			}
+		case "--stream-mode":
+			if i+1 < len(args) {
+				if args[i+1] != "sse" && args[i+1] != "chunked" {
+					return nil, nil, fmt.Errorf("invalid value for --stream-mode: %q (expected sse or chunked)", args[i+1])
+				}
+				config.Benchmark.Stream.Mode = args[i+1]
+				i++
+			}
+		case "--stream-duration", "--idle-timeout":
+			if i+1 < len(args) {
+				duration, err := time.ParseDuration(args[i+1])
+				if err != nil || duration < 0 || (duration == 0 && args[i] == "--stream-duration") {
+					return nil, nil, fmt.Errorf("invalid value for %s: %q", args[i], args[i+1])
+				}
+				if args[i] == "--stream-duration" {
+					config.Benchmark.Stream.Duration = duration
+				} else {
+					config.Benchmark.Stream.IdleTimeout = duration
+				}
+				i++
+			}
+		case "--max-events":
+			if i+1 < len(args) {
+				if count, err := strconv.Atoi(args[i+1]); err == nil && count >= 0 {
+					config.Benchmark.Stream.MaxEvents = count
+				}
+				i++
+			}
+		case "--query", "--query-file":
+			if i+1 >= len(args) {
+				return nil, nil, fmt.Errorf("missing value for %s", args[i])
+			}
+			query := args[i+1]
+			if args[i] == "--query-file" {
+				data, err := os.ReadFile(args[i+1])
+				if err != nil {
+					return nil, nil, fmt.Errorf("failed to read query file: %w", err)
+				}
+				query = string(data)
+			}
+			graphQLOperations = append(graphQLOperations, httpConfig.HttpGraphQLOperation{Query: query})
+			i++
+		case "--variables", "--operation-name":
+			if i+1 >= len(args) {
+				return nil, nil, fmt.Errorf("missing value for %s", args[i])
+			}
+			if len(graphQLOperations) == 0 {
+				return nil, nil, fmt.Errorf("%s must follow --query or --query-file", args[i])
+			}
+			if args[i] == "--variables" {
+				graphQLOperations[len(graphQLOperations)-1].Variables = args[i+1]
+			} else {
+				graphQLOperations[len(graphQLOperations)-1].Name = args[i+1]
+			}
+			i++
+		case "--graphql-path":
+			if i+1 >= len(args) {
+				return nil, nil, fmt.Errorf("missing value for --graphql-path")
+			}
+			config.Benchmark.GraphQL.Path = args[i+1]
+			i++
+		case "--config", "--from-har", "--har-include", "--from-curl", "--from-openapi", "--openapi-include", "--openapi-weight":
+			i++
+		case "--proxy":
+			if i+1 >= len(args) {
+				return nil, nil, fmt.Errorf("missing value for --proxy")
+			}
+			config.Proxy.Listen = args[i+1]
+			i++
+		case "--forward":
+			config.Proxy.Forward = true
+		case "--proxy-duration":
+			if i+1 >= len(args) {
+				return nil, nil, fmt.Errorf("missing value for --proxy-duration")
+			}
+			duration, err := time.ParseDuration(args[i+1])
+			if err != nil || duration <= 0 {
+				return nil, nil, fmt.Errorf("invalid value for --proxy-duration: %q", args[i+1])
+			}
+			config.Proxy.Duration = duration
+			i++
+		case "--export-workload":
+			if i+1 >= len(args) {
+				return nil, nil, fmt.Errorf("missing value for --export-workload")
+			}
+			config.Proxy.ExportWorkload = args[i+1]
+			i++
+		case "--transport":
+			if i+1 >= len(args) {
+				return nil, nil, fmt.Errorf("missing value for --transport")
+			}
+			if err := transport.Validate(args[i+1]); err != nil {
+				return nil, nil, err
+			}
+			config.Connection.Transport = args[i+1]
+			i++
+		case "--via-proxy":
+			if i+1 >= len(args) {
+				return nil, nil, fmt.Errorf("missing value for --via-proxy")
+			}
+			for _, proxy := range strings.Split(args[i+1], ",") {
+				if proxy = strings.TrimSpace(proxy); proxy != "" {
+					proxies = append(proxies, proxy)
+				}
+			}
+			i++
+		case "--proxy-list":
+			if i+1 >= len(args) {
+				return nil, nil, fmt.Errorf("missing value for --proxy-list")
+			}
+			listed, err := loadProxyList(args[i+1])
+			if err != nil {
+				return nil, nil, err
+			}
+			proxies = append(proxies, listed...)
+			i++
+		case "--workload":
+			if i+1 >= len(args) {
+				return nil, nil, fmt.Errorf("missing value for --workload")
+			}
+			loaded, err := httpConfig.LoadHttpWorkload(args[i+1])
+			if err != nil {
+				return nil, nil, err
+			}
+			workload = loaded
+			i++
+		}
+	}
+
+	// 命令行指定的GraphQL操作替换配置文件中的操作
+	if len(graphQLOperations) > 0 {
+		config.Benchmark.TestCase = "graphql"
+		config.Benchmark.GraphQL.Operations = graphQLOperations
+	}
+	if config.Benchmark.TestCase == "graphql" {
+		if len(config.Benchmark.GraphQL.Operations) == 0 {
+			return nil, nil, fmt.Errorf("the graphql preset needs --query or --query-file (or graphql.operations in --config)")
+		}
+		if err := config.Benchmark.GraphQL.Validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// 命令行指定的出站代理替换配置文件中的列表
+	if len(proxies) > 0 {
+		config.Connection.Proxies = proxies
+	}
+	for _, proxy := range config.Connection.Proxies {
+		if err := httpConfig.ValidateProxyURL(proxy); err != nil {
+			return nil, nil, fmt.Errorf("invalid outbound proxy: %w", err)
+		}
+	}
+
+	if !config.Proxy.Enabled() && (config.Proxy.Forward || config.Proxy.Duration > 0 || config.Proxy.ExportWorkload != "") {
+		return nil, nil, fmt.Errorf("--forward, --proxy-duration and --export-workload require --proxy")
+	}
+
+	if config.Benchmark.Payload.Enabled() && config.Benchmark.TestCase == "get_only" {
+		config.Benchmark.TestCase = "post_only"
+	}
+	if err := config.Benchmark.Payload.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid --body-template: %w", err)
+	}
+
+	if config.Auth.Type != "oauth2" && oauth2Set {
+		return nil, nil, fmt.Errorf("--oauth2-* options require --oauth2-token-url")
+	}
+	if config.Auth.Type == "oauth2" {
+		if err := config.Auth.OAuth2.Validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, assertion := range config.Benchmark.Assert {
+		if err := assertion.Validate(); err != nil {
+			return nil, nil, fmt.Errorf("invalid assertion %s: %w", assertion.String(), err)
+		}
+	}
+
+	// 契约文档在运行前加载一次以尽早报告错误
+	if spec := config.Benchmark.Contract.Spec; spec != "" {
+		if _, err := httpConfig.LoadOpenAPIContract(spec); err != nil {
+			return nil, nil, fmt.Errorf("invalid --contract: %w", err)
+		}
+	}
+
+	if workload != nil {
+		if config.Proxy.Enabled() {
+			return nil, nil, fmt.Errorf("--workload cannot be combined with --proxy")
+		}
+		if imported != nil {
+			return nil, nil, fmt.Errorf("--workload cannot be combined with --from-har, --from-curl or --from-openapi")
+		}
+		baseURL, total, parallels := config.Connection.BaseURL, config.Benchmark.Total, config.Benchmark.Parallels
+		workload.Apply(config)
+		if urlSet {
+			config.Connection.BaseURL = baseURL
+		}
+		if totalSet {
+			config.Benchmark.Total = total
+		}
+		if parallelsSet {
+			config.Benchmark.Parallels = parallels
+		}
+	}
+
+	return config, workload, nil
+}
+
+// importScenario 按--from-har或--from-curl导入请求链场景，或按--from-openapi导入加权请求模板，均未指定时返回nil
+func (h *HttpCommandHandler) importScenario(args []string) (*httpConfig.HttpImport, error) {
+	var harFile, openAPIFile string
+	var include, openAPIInclude *regexp.Regexp
+	var curls []string
+	var weights map[string]int
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--from-har", "--har-include", "--from-curl", "--from-openapi", "--openapi-include", "--openapi-weight":
+		default:
+			continue
+		}
+		if i+1 >= len(args) {
+			return nil, fmt.Errorf("missing value for %s", args[i])
+		}
+		switch args[i] {
+		case "--from-har":
+			harFile = args[i+1]
+		case "--har-include":
+			pattern, err := regexp.Compile(args[i+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for --har-include: %w", err)
+			}
+			include = pattern
+		case "--from-curl":
+			curls = append(curls, args[i+1])
+		case "--from-openapi":
+			openAPIFile = args[i+1]
+		case "--openapi-include":
+			pattern, err := regexp.Compile(args[i+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for --openapi-include: %w", err)
+			}
+			openAPIInclude = pattern
+		case "--openapi-weight":
+			name, value, ok := strings.Cut(args[i+1], "=")
+			weight, err := strconv.Atoi(value)
+			if !ok || name == "" || err != nil || weight < 0 {
+				return nil, fmt.Errorf("invalid value for --openapi-weight: %q (expected operationId=N)", args[i+1])
+			}
+			if weights == nil {
+				weights = make(map[string]int)
+			}
+			weights[name] = weight
 		}
+		i++
 	}
 
-	return config, nil
+	sources := 0
+	for _, set := range []bool{harFile != "", len(curls) > 0, openAPIFile != ""} {
+		if set {
+			sources++
+		}
+	}
+	switch {
+	case sources > 1:
+		return nil, fmt.Errorf("--from-har, --from-curl and --from-openapi cannot be combined")
+	case include != nil && harFile == "":
+		return nil, fmt.Errorf("--har-include requires --from-har")
+	case (openAPIInclude != nil || weights != nil) && openAPIFile == "":
+		return nil, fmt.Errorf("--openapi-include and --openapi-weight require --from-openapi")
+	case openAPIFile != "":
+		return httpConfig.ImportOpenAPI(openAPIFile, openAPIInclude, weights)
+	case harFile != "":
+		return httpConfig.ImportHAR(harFile, include)
+	case len(curls) > 0:
+		return httpConfig.ImportCurl(curls)
+	}
+	return nil, nil
 }
 
 // runPerformanceTest 运行性能测试 - 使用新的ExecutionEngine
-func (h *HttpCommandHandler) runPerformanceTest(ctx context.Context, adapter interfaces.ProtocolAdapter, config *httpConfig.HttpAdapterConfig, collector *metrics.BaseCollector[map[string]interface{}]) error {
+func (h *HttpCommandHandler) runPerformanceTest(ctx context.Context, adapter interfaces.ProtocolAdapter, config *httpConfig.HttpAdapterConfig, collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
 	// 执行健康检查
 	if err := adapter.HealthCheck(ctx); err != nil {
 		fmt.Printf("⚠️  Health check failed: %v\n", err)
 		fmt.Printf("🔄 Switching to simulation mode - this will generate mock test data instead of real HTTP requests\n")
 		// 在模拟模式下生成测试数据
+		opts.markSimulated(err)
 		return h.runSimulationTest(config, collector)
 	}
 
 	// 健康检查通过，使用新的ExecutionEngine执行真实测试
-	return h.runConcurrentTest(ctx, adapter, config, collector)
+	return h.runConcurrentTest(ctx, adapter, config, collector, opts)
 }
 
 // runSimulationTest 运行模拟测试
@@ -219,7 +1137,7 @@ func (h *HttpCommandHandler) runSimulationTest(config *httpConfig.HttpAdapterCon
 }
 
 // runConcurrentTest 使用ExecutionEngine运行并发测试
-func (h *HttpCommandHandler) runConcurrentTest(ctx context.Context, adapter interfaces.ProtocolAdapter, config *httpConfig.HttpAdapterConfig, collector *metrics.BaseCollector[map[string]interface{}]) error {
+func (h *HttpCommandHandler) runConcurrentTest(ctx context.Context, adapter interfaces.ProtocolAdapter, config *httpConfig.HttpAdapterConfig, collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
 	fmt.Printf("📊 Running concurrent HTTP performance test with ExecutionEngine...\n")
 
 	// 创建基准配置适配器
@@ -232,8 +1150,9 @@ func (h *HttpCommandHandler) runConcurrentTest(ctx context.Context, adapter inte
 	engine := execution.NewExecutionEngine(adapter, collector, operationFactory)
 
 	// 配置执行引擎参数
-	engine.SetMaxWorkers(100)         // 设置最大工作协程数
-	engine.SetBufferSizes(1000, 1000) // 设置缓冲区大小
+	engine.SetMaxWorkers(100)             // 设置最大工作协程数
+	engine.SetBufferSizes(1000, 1000)     // 设置缓冲区大小
+	opts.applyToEngine(engine, collector) // 应用通用运行选项
 
 	// 记录测试开始时间
 	testStartTime := time.Now()
@@ -243,6 +1162,7 @@ func (h *HttpCommandHandler) runConcurrentTest(ctx context.Context, adapter inte
 	if err != nil {
 		return fmt.Errorf("benchmark execution failed: %w", err)
 	}
+	opts.finishRun()
 
 	// 计算实际测试时间
 	actualTestDuration := time.Since(testStartTime)
@@ -268,13 +1188,504 @@ func (h *HttpCommandHandler) runConcurrentTest(ctx context.Context, adapter inte
 		"test_type":        "performance",
 		"actual_duration":  actualTestDuration,
 		"execution_result": result,
+		"test_case":        config.Benchmark.TestCase,
+		"target":           config.Connection.BaseURL,
 	})
 
 	return nil
 }
 
+// reportCacheStats 输出缓存命中统计并写入协议指标
+func (h *HttpCommandHandler) reportCacheStats(adapter *http.HttpAdapter, collector *metrics.BaseCollector[map[string]interface{}]) {
+	stats, ok := adapter.CacheStats()
+	if !ok || stats.Requests == 0 {
+		return
+	}
+
+	fmt.Printf("🗄️  Cache results\n")
+	fmt.Printf("   Requests: %d (cacheable: %d), Hits: %d, Misses: %d, Unknown: %d\n",
+		stats.Requests, stats.Cacheable, stats.Hits, stats.Misses, stats.Unknown)
+	fmt.Printf("   Hit ratio: %.2f%%, Cacheable hit ratio: %.2f%%\n", stats.HitRatio, stats.CacheableHitRatio)
+	fmt.Printf("   Origin offload: %.2f%% of requests, %.2f%% of bytes\n", stats.RequestOffload, stats.ByteOffload)
+	fmt.Printf("   Avg latency hit: %v, miss: %v, Avg Age: %v\n", stats.HitLatency, stats.MissLatency, stats.AverageAge)
+	if stats.UncacheableHits > 0 {
+		fmt.Printf("   ⚠️  %d uncacheable requests were served from cache\n", stats.UncacheableHits)
+	}
+	if stats.Unknown == stats.Requests {
+		fmt.Printf("   ⚠️  No cache status headers found; is the target a cache server?\n")
+	}
+
+	// 在已有协议数据（如实际测试时长）基础上追加缓存统计
+	protocol := collector.Snapshot().Protocol
+	if protocol == nil {
+		protocol = make(map[string]interface{})
+	}
+	protocol["test_type"] = "cache"
+	protocol["cache"] = stats.ToMap()
+	collector.UpdateProtocolMetrics(protocol)
+}
+
+// reportPageLoadStats 输出页面加载统计并写入协议指标
+func (h *HttpCommandHandler) reportPageLoadStats(adapter *http.HttpAdapter, collector *metrics.BaseCollector[map[string]interface{}]) {
+	stats, ok := adapter.PageLoadStats()
+	if !ok || stats.Pages == 0 {
+		return
+	}
+
+	fmt.Printf("🌐 Page load results\n")
+	fmt.Printf("   Pages: %d (failed: %d), Assets: %d (failed: %d, %.1f per page), Bytes: %d\n",
+		stats.Pages, stats.FailedPages, stats.Assets, stats.FailedAssets, stats.AssetsPerPage, stats.Bytes)
+	fmt.Printf("   Page load  P50: %v, P90: %v, P95: %v, P99: %v, Max: %v\n",
+		stats.PageLoad.P50, stats.PageLoad.P90, stats.PageLoad.P95, stats.PageLoad.P99, stats.PageLoad.Max)
+	fmt.Printf("   Document   P50: %v, P95: %v, P99: %v\n", stats.Document.P50, stats.Document.P95, stats.Document.P99)
+	if stats.Assets > 0 {
+		fmt.Printf("   Asset      P50: %v, P95: %v, P99: %v\n", stats.Asset.P50, stats.Asset.P95, stats.Asset.P99)
+	} else {
+		fmt.Printf("   ⚠️  No assets were loaded; use --page-assets or check that the page is HTML\n")
+	}
+
+	// 在已有协议数据（如实际测试时长）基础上追加页面加载统计
+	protocol := collector.Snapshot().Protocol
+	if protocol == nil {
+		protocol = make(map[string]interface{})
+	}
+	protocol["test_type"] = "page_load"
+	protocol["page_load"] = stats.ToMap()
+	collector.UpdateProtocolMetrics(protocol)
+}
+
+// reportWebhookStats 输出异步回调统计并写入协议指标
+func (h *HttpCommandHandler) reportWebhookStats(adapter *http.HttpAdapter, collector *metrics.BaseCollector[map[string]interface{}]) {
+	stats, ok := adapter.WebhookStats()
+	if !ok || stats.Submitted+stats.SubmitFailed == 0 {
+		return
+	}
+
+	fmt.Printf("🔔 Webhook results\n")
+	fmt.Printf("   Jobs submitted: %d (failed: %d), Callbacks: %d (%.2f%%), Timed out: %d\n",
+		stats.Submitted, stats.SubmitFailed, stats.Callbacks, stats.CallbackRate, stats.TimedOut)
+	fmt.Printf("   Submit-to-callback P50: %v, P90: %v, P95: %v, P99: %v, Max: %v\n",
+		stats.EndToEnd.P50, stats.EndToEnd.P90, stats.EndToEnd.P95, stats.EndToEnd.P99, stats.EndToEnd.Max)
+	fmt.Printf("   Submit request     P50: %v, P95: %v, P99: %v\n", stats.Submit.P50, stats.Submit.P95, stats.Submit.P99)
+	fmt.Printf("   Callback after ack P50: %v, P95: %v, P99: %v\n", stats.AfterAck.P50, stats.AfterAck.P95, stats.AfterAck.P99)
+	if stats.Late+stats.Unmatched+stats.Invalid > 0 {
+		fmt.Printf("   ⚠️  Late callbacks: %d, unknown or duplicate IDs: %d, without an ID: %d\n",
+			stats.Late, stats.Unmatched, stats.Invalid)
+	}
+	if stats.Submitted > 0 && stats.Callbacks == 0 {
+		fmt.Printf("   ⚠️  No callbacks were matched; check --callback-url and that the service echoes the correlation ID\n")
+	}
+
+	// 在已有协议数据（如实际测试时长）基础上追加异步回调统计
+	protocol := collector.Snapshot().Protocol
+	if protocol == nil {
+		protocol = make(map[string]interface{})
+	}
+	protocol["test_type"] = "webhook"
+	protocol["webhook"] = stats.ToMap()
+	collector.UpdateProtocolMetrics(protocol)
+}
+
+// reportStreamStats 输出流式响应统计并写入协议指标
+func (h *HttpCommandHandler) reportStreamStats(adapter *http.HttpAdapter, collector *metrics.BaseCollector[map[string]interface{}]) {
+	stats, ok := adapter.StreamStats()
+	if !ok || stats.Streams == 0 {
+		return
+	}
+
+	reasons := make([]string, 0, len(stats.Endings))
+	for reason := range stats.Endings {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	endings := make([]string, 0, len(reasons))
+	for _, reason := range reasons {
+		endings = append(endings, fmt.Sprintf("%s×%d", reason, stats.Endings[reason]))
+	}
+
+	fmt.Printf("📡 Stream results\n")
+	fmt.Printf("   Streams: %d (failed: %d), Events: %d, Bytes: %d\n", stats.Streams, stats.FailedStreams, stats.Events, stats.Bytes)
+	fmt.Printf("   Events/sec: %.1f overall, %.2f per stream\n", stats.EventsPerSec, stats.StreamEventsPerSec)
+	fmt.Printf("   Time to first byte  P50: %v, P95: %v, P99: %v\n", stats.TTFB.P50, stats.TTFB.P95, stats.TTFB.P99)
+	if stats.Events > 0 {
+		fmt.Printf("   Time to first event P50: %v, P95: %v, P99: %v\n", stats.FirstEvent.P50, stats.FirstEvent.P95, stats.FirstEvent.P99)
+		fmt.Printf("   Inter-event latency P50: %v, P90: %v, P99: %v, Max: %v\n",
+			stats.InterEvent.P50, stats.InterEvent.P90, stats.InterEvent.P99, stats.InterEvent.Max)
+	} else {
+		fmt.Printf("   ⚠️  No events were received; check --stream-path and --stream-mode\n")
+	}
+	fmt.Printf("   Stream lifetime     P50: %v, P95: %v, Max: %v\n", stats.Lifetime.P50, stats.Lifetime.P95, stats.Lifetime.Max)
+	fmt.Printf("   Ended by: %s\n", strings.Join(endings, " "))
+
+	// 在已有协议数据（如实际测试时长）基础上追加流式响应统计
+	protocol := collector.Snapshot().Protocol
+	if protocol == nil {
+		protocol = make(map[string]interface{})
+	}
+	protocol["test_type"] = "stream"
+	protocol["stream"] = stats.ToMap()
+	collector.UpdateProtocolMetrics(protocol)
+}
+
+// reportLatencyPhases 输出按阶段的延迟分解并写入协议指标
+func (h *HttpCommandHandler) reportLatencyPhases(phases []metrics.PhaseLatency, collector *metrics.BaseCollector[map[string]interface{}]) {
+	printLatencyPhases(phases)
+
+	protocol := collector.Snapshot().Protocol
+	if protocol == nil {
+		protocol = make(map[string]interface{})
+	}
+	protocol["latency_phases"] = phases
+	collector.UpdateProtocolMetrics(protocol)
+}
+
+// loadProxyList 读取出站代理列表文件，每行一个代理地址，忽略空行与#注释
+func loadProxyList(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read proxy list: %w", err)
+	}
+	var proxies []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			proxies = append(proxies, line)
+		}
+	}
+	if len(proxies) == 0 {
+		return nil, fmt.Errorf("proxy list %s is empty", path)
+	}
+	return proxies, nil
+}
+
+// reportEndpointStats 输出按请求模板或场景步骤的统计，并以key写入协议指标
+func (h *HttpCommandHandler) reportEndpointStats(title, key string, stats []operations.EndpointStats, collector *metrics.BaseCollector[map[string]interface{}]) {
+	if len(stats) == 0 {
+		return
+	}
+
+	fmt.Printf("%s\n", title)
+	endpoints := make([]map[string]interface{}, 0, len(stats))
+	for _, endpoint := range stats {
+		endpoints = append(endpoints, endpoint.ToMap())
+		label := fmt.Sprintf("%-32s", endpoint.Name)
+		if key == "endpoints" {
+			label += fmt.Sprintf(" weight %-4d", endpoint.Weight)
+		}
+		if endpoint.Requests == 0 {
+			fmt.Printf("   %s no requests\n", label)
+			continue
+		}
+		codes := make([]int, 0, len(endpoint.StatusCodes))
+		for code := range endpoint.StatusCodes {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		statuses := make([]string, 0, len(codes))
+		for _, code := range codes {
+			label := strconv.Itoa(code)
+			if code == 0 {
+				label = "no response"
+			}
+			statuses = append(statuses, fmt.Sprintf("%s×%d", label, endpoint.StatusCodes[code]))
+		}
+		fmt.Printf("   %s requests %-7d errors %-5d P50 %v, P95 %v, P99 %v  [%s]\n",
+			label, endpoint.Requests, endpoint.Errors,
+			endpoint.Latency.P50, endpoint.Latency.P95, endpoint.Latency.P99, strings.Join(statuses, " "))
+	}
+
+	// 在已有协议数据（如实际测试时长）基础上追加统计
+	protocol := collector.Snapshot().Protocol
+	if protocol == nil {
+		protocol = make(map[string]interface{})
+	}
+	protocol[key] = endpoints
+	collector.UpdateProtocolMetrics(protocol)
+}
+
+// reportGraphQLStats 输出按GraphQL操作的统计，并写入协议指标
+func (h *HttpCommandHandler) reportGraphQLStats(stats []operations.GraphQLOperationStats, collector *metrics.BaseCollector[map[string]interface{}]) {
+	if len(stats) == 0 {
+		return
+	}
+
+	fmt.Printf("🔷 GraphQL operation results\n")
+	results := make([]map[string]interface{}, 0, len(stats))
+	for _, operation := range stats {
+		results = append(results, operation.ToMap())
+		label := fmt.Sprintf("%-32s %-8s", operation.Name, operation.Type)
+		if operation.Requests == 0 {
+			fmt.Printf("   %s no requests\n", label)
+			continue
+		}
+		codes := make([]int, 0, len(operation.StatusCodes))
+		for code := range operation.StatusCodes {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		statuses := make([]string, 0, len(codes))
+		for _, code := range codes {
+			label := strconv.Itoa(code)
+			if code == 0 {
+				label = "no response"
+			}
+			statuses = append(statuses, fmt.Sprintf("%s×%d", label, operation.StatusCodes[code]))
+		}
+		fmt.Printf("   %s requests %-7d errors %-5d P50 %v, P95 %v, P99 %v  [%s]\n",
+			label, operation.Requests, operation.Errors,
+			operation.Latency.P50, operation.Latency.P95, operation.Latency.P99, strings.Join(statuses, " "))
+		if operation.GraphQLErrors > 0 {
+			names := make([]string, 0, len(operation.ErrorCodes))
+			for code := range operation.ErrorCodes {
+				names = append(names, code)
+			}
+			sort.Strings(names)
+			errorCodes := make([]string, 0, len(names))
+			for _, code := range names {
+				errorCodes = append(errorCodes, fmt.Sprintf("%s×%d", code, operation.ErrorCodes[code]))
+			}
+			fmt.Printf("   %-41s graphql errors %d (with partial data %d): %s\n",
+				"", operation.GraphQLErrors, operation.PartialData, strings.Join(errorCodes, " "))
+		}
+	}
+
+	// 在已有协议数据（如实际测试时长）基础上追加统计
+	protocol := collector.Snapshot().Protocol
+	if protocol == nil {
+		protocol = make(map[string]interface{})
+	}
+	protocol["test_type"] = "graphql"
+	protocol["graphql_operations"] = results
+	collector.UpdateProtocolMetrics(protocol)
+}
+
+// reportCookieStats 输出cookie会话统计，并写入协议指标
+func (h *HttpCommandHandler) reportCookieStats(stats operations.CookieStats, collector *metrics.BaseCollector[map[string]interface{}]) {
+	fmt.Printf("🍪 Cookies (%s scope)\n", stats.Scope)
+	fmt.Printf("   Jars: %d, cookies received: %d, requests sent with cookies: %d\n",
+		stats.Jars, stats.CookiesReceived, stats.RequestsWithCookies)
+
+	protocol := collector.Snapshot().Protocol
+	if protocol == nil {
+		protocol = make(map[string]interface{})
+	}
+	protocol["cookies"] = stats.ToMap()
+	collector.UpdateProtocolMetrics(protocol)
+}
+
+// reportProxyStats 输出各出站代理的统计，并写入协议指标
+func (h *HttpCommandHandler) reportProxyStats(stats []connection.ProxyStats, collector *metrics.BaseCollector[map[string]interface{}]) {
+	fmt.Printf("🌐 Outbound proxies\n")
+	proxies := make([]map[string]interface{}, 0, len(stats))
+	for _, proxy := range stats {
+		proxies = append(proxies, proxy.ToMap())
+		if proxy.Requests == 0 {
+			fmt.Printf("   %-40s no requests\n", proxy.Proxy)
+			continue
+		}
+		codes := make([]int, 0, len(proxy.StatusCodes))
+		for code := range proxy.StatusCodes {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		statuses := make([]string, 0, len(codes))
+		for _, code := range codes {
+			statuses = append(statuses, fmt.Sprintf("%d×%d", code, proxy.StatusCodes[code]))
+		}
+		fmt.Printf("   %-40s conns %-5d requests %-7d errors %-5d failed %-5d P50 %v, P95 %v, P99 %v  [%s]\n",
+			proxy.Proxy, proxy.Connections, proxy.Requests, proxy.Errors, proxy.Failed,
+			proxy.Latency.P50, proxy.Latency.P95, proxy.Latency.P99, strings.Join(statuses, " "))
+		if proxy.LastError != "" {
+			fmt.Printf("      last error: %s\n", proxy.LastError)
+		}
+	}
+
+	protocol := collector.Snapshot().Protocol
+	if protocol == nil {
+		protocol = make(map[string]interface{})
+	}
+	protocol["proxies"] = proxies
+	collector.UpdateProtocolMetrics(protocol)
+}
+
+// reportOAuth2Stats 输出OAuth2令牌获取与刷新统计，并写入协议指标
+func (h *HttpCommandHandler) reportOAuth2Stats(stats connection.OAuth2Stats, collector *metrics.BaseCollector[map[string]interface{}]) {
+	fmt.Printf("🔑 OAuth2 tokens (%s)\n", stats.Grant)
+	fmt.Printf("   Token requests: %d (avg %v, max %v), refreshes: %d (expiry %d, 401 %d), failures: %d\n",
+		stats.Fetches, stats.AverageLatency, stats.MaxLatency, stats.Refreshes, stats.ExpiryRefreshes, stats.UnauthorizedRefreshes, stats.Failures)
+	if stats.RetriedRequests > 0 {
+		fmt.Printf("   Requests retried after 401: %d\n", stats.RetriedRequests)
+	}
+	if stats.LastError != "" {
+		fmt.Printf("   Last error: %s\n", stats.LastError)
+	}
+
+	protocol := collector.Snapshot().Protocol
+	if protocol == nil {
+		protocol = make(map[string]interface{})
+	}
+	protocol["oauth2"] = stats.ToMap()
+	collector.UpdateProtocolMetrics(protocol)
+}
+
+// reportValidationStats 输出响应断言统计与失败样本，并写入协议指标
+func (h *HttpCommandHandler) reportValidationStats(stats operations.ValidationStats, collector *metrics.BaseCollector[map[string]interface{}]) {
+	fmt.Printf("✅ Response validation\n")
+	fmt.Printf("   Passed: %d / %d, validation failed: %d, status errors: %d, transport errors: %d\n",
+		stats.Passed, stats.Requests, stats.ValidationFailed, stats.StatusErrors, stats.TransportErrors)
+	for _, assertion := range stats.Assertions {
+		fmt.Printf("   ✗ %s: %d failures\n", assertion.Assertion, assertion.Failures)
+		for _, sample := range assertion.Samples {
+			fmt.Printf("      %s → %d (%s): %s\n", sample.Request, sample.StatusCode, sample.Reason, strings.Join(strings.Fields(sample.Body), " "))
+		}
+	}
+
+	protocol := collector.Snapshot().Protocol
+	if protocol == nil {
+		protocol = make(map[string]interface{})
+	}
+	protocol["validation"] = stats.ToMap()
+	collector.UpdateProtocolMetrics(protocol)
+}
+
+// runProxy 运行代理模式，直到中断或达到--proxy-duration后生成报告，按需导出工作负载
+func (h *HttpCommandHandler) runProxy(ctx context.Context, config *httpConfig.HttpAdapterConfig, collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
+	proxy, err := operations.NewHttpProxy(config, collector)
+	if err != nil {
+		return NewConfigError(err)
+	}
+	if err := proxy.CheckUpstream(ctx); err != nil {
+		return NewConnectionError(err)
+	}
+	if err := proxy.Listen(); err != nil {
+		return err
+	}
+	opts.applyToCollector(collector)
+
+	// 代理为常驻服务，不受单次命令超时限制，收到中断信号或达到时长时停止
+	serveCtx, stop := signal.NotifyContext(context.WithoutCancel(ctx), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if config.Proxy.Duration > 0 {
+		var cancel context.CancelFunc
+		serveCtx, cancel = context.WithTimeout(serveCtx, config.Proxy.Duration)
+		defer cancel()
+	}
+
+	if config.Proxy.Forward {
+		fmt.Printf("🔀 HTTP forward proxy listening on %s (set HTTP_PROXY=http://%s)\n", proxy.Addr(), proxy.Addr())
+	} else {
+		fmt.Printf("🔀 HTTP reverse proxy listening on %s, forwarding to %s\n", proxy.Addr(), config.Connection.BaseURL)
+	}
+	if config.Proxy.Duration > 0 {
+		fmt.Printf("   Running for %v (Ctrl+C to stop earlier)\n", config.Proxy.Duration)
+	} else {
+		fmt.Printf("   Press Ctrl+C to stop and generate the report\n")
+	}
+
+	start := time.Now()
+	if err := proxy.Serve(serveCtx); err != nil {
+		return fmt.Errorf("http proxy failed: %w", err)
+	}
+	elapsed := time.Since(start)
+	opts.finishRun()
+
+	stats := proxy.Stats()
+	fmt.Printf("✅ HTTP proxy stopped after %v\n", elapsed.Round(time.Millisecond))
+	fmt.Printf("   Requests: %d (%.1f req/s), errors: %d, peak concurrency: %d\n",
+		stats.Requests, float64(stats.Requests)/elapsed.Seconds(), stats.Errors, stats.PeakConcurrency)
+	fmt.Printf("   Endpoints: %d, Bytes: %d in, %d out\n", stats.Endpoints, stats.BytesIn, stats.BytesOut)
+	if stats.Tunnels > 0 {
+		fmt.Printf("   CONNECT tunnels: %d (encrypted, not timed)\n", stats.Tunnels)
+	}
+	profile := proxy.Profile()
+	if profile != nil {
+		h.printWorkloadProfile("📐 Observed workload profile", profile)
+	}
+
+	if path := config.Proxy.ExportWorkload; path != "" {
+		if stats.Requests-stats.DroppedRequests == 0 {
+			fmt.Printf("⚠️  No requests observed, workload not exported\n")
+		} else if err := httpConfig.SaveHttpWorkload(path, proxy.Workload(elapsed)); err != nil {
+			return err
+		} else {
+			fmt.Printf("✅ Workload saved to: %s\n", path)
+			if stats.DroppedRequests > 0 {
+				fmt.Printf("   %d requests beyond the distinct-request limit were left out\n", stats.DroppedRequests)
+			}
+		}
+	}
+
+	protocol := map[string]interface{}{
+		"protocol":        "http",
+		"test_type":       "proxy",
+		"actual_duration": elapsed,
+		"proxy":           stats,
+	}
+	if profile != nil {
+		protocol["workload_profile"] = profile
+	}
+	collector.UpdateProtocolMetrics(protocol)
+	return h.generateReport(collector, opts)
+}
+
+// printWorkloadProfile 输出工作负载特征：操作构成、URL热度、负载大小与到达间隔
+func (h *HttpCommandHandler) printWorkloadProfile(title string, profile *httpConfig.HttpWorkloadProfile) {
+	fmt.Printf("%s (%d requests)\n", title, profile.Requests)
+
+	mix := make([]string, 0, 5)
+	for i, operation := range profile.Operations {
+		if i == 5 {
+			mix = append(mix, fmt.Sprintf("+%d more", len(profile.Operations)-i))
+			break
+		}
+		mix = append(mix, fmt.Sprintf("%s %.1f%%", operation.Name, operation.Percent))
+	}
+	fmt.Printf("   Operation mix:  %s\n", strings.Join(mix, ", "))
+
+	popularity := profile.Popularity
+	curve := make([]string, 0, len(popularity.Curve))
+	for _, point := range popularity.Curve {
+		curve = append(curve, fmt.Sprintf("top %g%% → %.1f%%", point.URLPercent, point.RequestPercent))
+	}
+	fmt.Printf("   URL popularity: %d distinct URLs, %s of requests", popularity.DistinctURLs, strings.Join(curve, ", "))
+	if popularity.DistinctURLs > 1 {
+		fmt.Printf(" (Zipf s=%.2f)", popularity.ZipfExponent)
+	}
+	fmt.Printf("\n")
+	hottest := make([]string, 0, 3)
+	for _, url := range popularity.Top[:min(3, len(popularity.Top))] {
+		hottest = append(hottest, fmt.Sprintf("%s %.1f%%", url.Name, url.Percent))
+	}
+	fmt.Printf("   Hottest URLs:   %s\n", strings.Join(hottest, ", "))
+
+	for _, size := range []struct {
+		label        string
+		distribution httpConfig.HttpSizeDistribution
+	}{{"Request size: ", profile.RequestSize}, {"Response size:", profile.ResponseSize}} {
+		buckets := make([]string, 0, len(size.distribution.Histogram))
+		for _, bucket := range size.distribution.Histogram {
+			buckets = append(buckets, fmt.Sprintf("%s: %.1f%%", bucket.Range, bucket.Percent))
+		}
+		fmt.Printf("   %s  mean %dB, P50 %dB, P90 %dB, P99 %dB, max %dB  [%s]\n", size.label,
+			size.distribution.Mean, size.distribution.P50, size.distribution.P90, size.distribution.P99,
+			size.distribution.Max, strings.Join(buckets, ", "))
+	}
+
+	arrival := profile.InterArrival
+	if arrival.Mean > 0 {
+		pattern := "Poisson-like"
+		if arrival.CV < 0.7 {
+			pattern = "evenly paced"
+		} else if arrival.CV > 1.3 {
+			pattern = "bursty"
+		}
+		fmt.Printf("   Inter-arrival:  mean %v, P50 %v, P90 %v, P99 %v, max %v, CV %.2f (%s)\n",
+			arrival.Mean.Round(time.Microsecond), arrival.P50.Round(time.Microsecond), arrival.P90.Round(time.Microsecond),
+			arrival.P99.Round(time.Microsecond), arrival.Max.Round(time.Microsecond), arrival.CV, pattern)
+	}
+}
+
 // generateReport 生成报告
-func (h *HttpCommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}]) error {
+func (h *HttpCommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
 	// 获取指标快照
 	snapshot := collector.Snapshot()
 
@@ -300,6 +1711,12 @@ func (h *HttpCommandHandler) generateReport(collector *metrics.BaseCollector[map
 		snapshot.Core.Throughput.RPS = float64(total) / seconds
 		snapshot.Core.Throughput.ReadRPS = float64(snapshot.Core.Operations.Read) / seconds
 		snapshot.Core.Throughput.WriteRPS = float64(snapshot.Core.Operations.Write) / seconds
+		snapshot.Core.Throughput.ReadMBps, snapshot.Core.Throughput.WriteMBps = metrics.Bandwidth(snapshot.Core.Operations, actualDuration)
+	}
+
+	// 存在快照接收器时（如分布式agent模式）交由接收器处理，不生成本地报告
+	if opts.publishSnapshot(snapshot) {
+		return nil
 	}
 
 	// 转换为结构化报告
@@ -307,9 +1724,36 @@ func (h *HttpCommandHandler) generateReport(collector *metrics.BaseCollector[map
 
 	// 使用标准报告配置
 	reportConfig := reporting.NewStandardReportConfig("http")
+	opts.applyToReport(snapshot, report, reportConfig)
 
 	generator := reporting.NewReportGenerator(reportConfig)
 
 	// 生成并显示报告
-	return generator.Generate(report)
+	if err := generator.Generate(report); err != nil {
+		return err
+	}
+	return opts.resultError(report)
+}
+
+// reportContractStats 输出API契约校验统计与各操作的违例，并写入协议指标
+func (h *HttpCommandHandler) reportContractStats(stats operations.ContractStats, collector *metrics.BaseCollector[map[string]interface{}]) {
+	fmt.Printf("📜 API contract (%s, %.0f%% sampled)\n", stats.Spec, stats.SampleRate*100)
+	fmt.Printf("   Checked: %d, conforming: %d, with violations: %d\n", stats.Sampled, stats.Conforming, stats.Violating)
+	for _, endpoint := range stats.Endpoints {
+		if endpoint.Violating == 0 {
+			fmt.Printf("   ✓ %-32s %d checked\n", endpoint.Operation, endpoint.Sampled)
+			continue
+		}
+		fmt.Printf("   ✗ %-32s %d of %d checked responses violate the contract\n", endpoint.Operation, endpoint.Violating, endpoint.Sampled)
+		for _, violation := range endpoint.Violations {
+			fmt.Printf("      %s ×%d (e.g. %s)\n", violation.Violation, violation.Count, violation.Sample)
+		}
+	}
+
+	protocol := collector.Snapshot().Protocol
+	if protocol == nil {
+		protocol = make(map[string]interface{})
+	}
+	protocol["contract"] = stats.ToMap()
+	collector.UpdateProtocolMetrics(protocol)
 }
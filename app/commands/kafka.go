@@ -4,15 +4,19 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
 	"strconv"
 	"strings"
 	"time"
 
 	"abc-runner/app/adapters/kafka"
 	kafkaConfig "abc-runner/app/adapters/kafka/config"
+	"abc-runner/app/adapters/kafka/connection"
+	"abc-runner/app/adapters/kafka/operations"
 	"abc-runner/app/core/execution"
 	"abc-runner/app/core/interfaces"
 	"abc-runner/app/core/metrics"
+	"abc-runner/app/core/utils"
 	"abc-runner/app/reporting"
 )
 
@@ -47,11 +51,20 @@ func (k *KafkaCommandHandler) Execute(ctx context.Context, args []string) error
 	// 解析命令行参数
 	config, err := k.parseArgs(args)
 	if err != nil {
-		return fmt.Errorf("failed to parse arguments: %w", err)
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	// 解析通用运行选项
+	opts, err := parseRunOptions(ctx, "kafka", args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+	if printConfigRequested(args) {
+		return printEffectiveConfig("kafka", config, opts)
 	}
 
 	// 创建Kafka适配器
-	metricsConfig := metrics.DefaultMetricsConfig()
+	metricsConfig := opts.collectorConfig()
 	metricsCollector := metrics.NewBaseCollector(metricsConfig, map[string]interface{}{
 		"protocol":  "kafka",
 		"test_type": "performance",
@@ -62,25 +75,94 @@ func (k *KafkaCommandHandler) Execute(ctx context.Context, args []string) error
 	adapter := kafka.NewKafkaAdapter(metricsCollector)
 
 	// 连接并执行测试
-	if err := adapter.Connect(ctx, config); err != nil {
-		log.Printf("Warning: failed to connect to %v: %v", config.Brokers, err)
+	connectErr := adapter.Connect(ctx, config)
+	if connectErr != nil {
+		log.Printf("Warning: failed to connect to %v: %v", config.Brokers, connectErr)
 		// 继续执行，但使用模拟模式
 	}
 	defer adapter.Close()
 
+	// 自动创建测试主题，测试结束后删除本次创建的主题
+	if config.Benchmark.Topic.Auto && connectErr == nil {
+		created, err := adapter.SetupTopic(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to set up topic %s: %w", config.Benchmark.DefaultTopic, err)
+		}
+		if created {
+			fmt.Printf("Created topic %s (partitions: %d, replication factor: %d)\n", config.Benchmark.DefaultTopic,
+				config.Benchmark.Topic.GetPartitions(), config.Benchmark.Topic.GetReplicationFactor())
+			defer k.teardownTopic(adapter, config)
+		} else {
+			fmt.Printf("Topic %s already exists, it will be kept after the test\n", config.Benchmark.DefaultTopic)
+		}
+	}
+
+	// 注册或获取schema，生产的消息按Confluent线格式序列化
+	var schema *connection.RegisteredSchema
+	if config.SchemaRegistry.Enabled() && connectErr == nil {
+		schema, err = adapter.SetupSchema(ctx)
+		if err != nil {
+			return NewConnectionError(fmt.Errorf("schema registry setup failed: %w", err))
+		}
+	}
+
 	// 执行性能测试
 	fmt.Printf("🚀 Starting Kafka performance test...\n")
 	fmt.Printf("Brokers: %s\n", strings.Join(config.Brokers, ","))
+	fmt.Printf("Security: %s\n", config.Security.Describe())
 	fmt.Printf("Topic: %s\n", config.Benchmark.DefaultTopic)
+	if schema != nil {
+		fmt.Printf("Schema: %s, subject %s, id %d (%s)\n", strings.ToLower(schema.SchemaType),
+			config.SchemaRegistry.GetSubject(config.Benchmark.DefaultTopic), schema.ID, config.SchemaRegistry.URL)
+	}
 	fmt.Printf("Messages: %d, Concurrency: %d, Mode: %s\n", config.Benchmark.Total, config.Benchmark.Parallels, config.Benchmark.TestType)
 
-	err = k.runPerformanceTest(ctx, adapter, config, metricsCollector)
+	if config.Benchmark.TestType == "commit" {
+		if err := k.runCommitTest(ctx, adapter, config, metricsCollector, opts); err != nil {
+			return fmt.Errorf("offset commit test failed: %w", err)
+		}
+		return k.generateReport(metricsCollector, opts)
+	}
+
+	if config.Benchmark.TestType == "relay" {
+		if err := k.runRelayTest(ctx, adapter, config, metricsCollector, opts); err != nil {
+			return fmt.Errorf("cross-cluster relay test failed: %w", err)
+		}
+		return k.generateReport(metricsCollector, opts)
+	}
+
+	if config.Benchmark.TestType == "e2e" {
+		if err := k.runEndToEndTest(ctx, adapter, config, metricsCollector, opts); err != nil {
+			return fmt.Errorf("end-to-end latency test failed: %w", err)
+		}
+		return k.generateReport(metricsCollector, opts)
+	}
+
+	if config.Benchmark.TestType == "coldread" {
+		if err := k.runColdReadTest(ctx, adapter, config, metricsCollector, opts); err != nil {
+			return fmt.Errorf("cold read test failed: %w", err)
+		}
+		return k.generateReport(metricsCollector, opts)
+	}
+
+	err = k.runPerformanceTest(ctx, adapter, config, metricsCollector, opts)
 	if err != nil {
 		return fmt.Errorf("performance test failed: %w", err)
 	}
 
 	// 生成并显示报告
-	return k.generateReport(metricsCollector)
+	return k.generateReport(metricsCollector, opts)
+}
+
+// teardownTopic 删除测试前自动创建的主题，测试被中断时同样执行
+func (k *KafkaCommandHandler) teardownTopic(adapter *kafka.KafkaAdapter, config *kafkaConfig.KafkaAdapterConfig) {
+	ctx, cancel := context.WithTimeout(context.Background(), config.Benchmark.GetTimeout())
+	defer cancel()
+	if err := adapter.DeleteTopic(ctx, config.Benchmark.DefaultTopic); err != nil {
+		log.Printf("Warning: failed to delete topic %s: %v", config.Benchmark.DefaultTopic, err)
+		return
+	}
+	fmt.Printf("Deleted topic %s\n", config.Benchmark.DefaultTopic)
 }
 
 // GetHelp 获取帮助信息
@@ -97,18 +179,113 @@ OPTIONS:
   --help, -h         Show this help message
   --brokers BROKERS  Kafka broker addresses (default: localhost:9092)
   --topic TOPIC      Topic name (default: test-topic)
-  --mode MODE        Test mode: producer, consumer, or both (default: producer)
+  --mode MODE        Test mode: producer, consumer, both, commit, relay, e2e or
+                     coldread (default: producer)
   -n COUNT           Number of messages (default: 1000)
   -c COUNT           Concurrent producers/consumers (default: 1)
-  
+  --value-template T Build produced message values from template T. Placeholders:
+                     {{seq}} (message number), {{uuid}}, {{timestamp [s|ms|ns|rfc3339]}},
+                     {{randInt [MIN MAX]}}, {{randFloat [MIN MAX]}}, {{randString [N]}},
+                     {{pick A B C}}, {{name}}, {{email}} and {{csv.COLUMN}}
+  --data-file FILE   CSV file for {{csv.COLUMN}}; the first row names the columns,
+                     message N uses row N modulo the row count
+
+SECURITY (managed Kafka such as MSK or Confluent Cloud):
+  --sasl-mechanism M     SASL mechanism: PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512
+  --sasl-user NAME       SASL username (implies SASL, default mechanism: PLAIN)
+  --sasl-password PASS   SASL password (default: $KAFKA_SASL_PASSWORD)
+  --tls                  Connect over TLS (implied by the options below)
+  --cacert FILE          CA certificate used to verify the brokers (default:
+                         system roots)
+  --cert FILE            Client certificate for mutual TLS (requires --key)
+  --key FILE             Client private key for mutual TLS
+  --sni NAME             Server name for SNI and certificate verification
+                         (default: host part of the broker address)
+  --insecure             Skip broker certificate verification
+
+SCHEMA REGISTRY (--mode producer):
+  Produced values are serialized against a Confluent-compatible Schema Registry
+  in the wire format consumers expect (magic byte + schema id). A JSON object
+  value (e.g. from --value-template) is encoded as is; any other value is
+  replaced by data generated from the schema. Serialization time is reported
+  separately and excluded from produce latency.
+  --schema-registry URL  Schema Registry URL (enables serialization)
+  --schema FILE          Register FILE (.avsc, .proto or .json) under the subject;
+                         without it the latest registered version is used
+  --schema-format F      avro, protobuf or json (default: from the registry or
+                         the --schema file extension)
+  --subject NAME         Subject name (default: <topic>-value)
+  --registry-auth K:S    Basic auth key and secret for the registry
+  Protobuf uses the first message of the file; imports are not resolved.
+  JSON Schema payloads are not validated against the schema.
+
+BATCH OPTIONS (--mode producer/consumer):
+  --batch-size N     Messages per produce/consume call; above 1 each call writes or
+                     fetches N messages and commits their offsets once, and
+                     per-batch latency, batch-size distribution and throughput
+                     are reported (default: 1)
+  --linger DUR       Max wait for a partition batch to fill before it is sent
+                     (default: 5ms)
+  --compression C    Producer compression: none, gzip, snappy, lz4 or zstd
+                     (default: snappy)
+
+OFFSET COMMIT OPTIONS (--mode commit):
+  --group ID             Consumer group prefix (a unique suffix is added per run)
+  --commit MODE          Commit mode: auto or manual (default: manual)
+  --commit-interval DUR  Auto commit interval, 0 commits every message (default: 1s)
+  --commit-batch N       Manual mode: commit every N messages (default: 1)
+  --commit-async         Manual mode: commit asynchronously (default: sync)
+  --restarts N           Simulated consumer restarts during the run (default: 0)
+
+TOPIC SETUP:
+  --create-topic             Create --topic before the run and delete it afterwards
+                             (an existing topic is used as is and kept)
+  --partitions N             Partitions of the created topic, -1 for the broker
+                             default (default: 1)
+  --replication-factor N     Replication factor of the created topic, -1 for the
+                             broker default (default: 1)
+
+CROSS-CLUSTER RELAY OPTIONS (--mode relay):
+  --target-brokers LIST  Brokers of the cluster the topic is replicated to (required)
+  --target-topic NAME    Replicated topic name on the target cluster
+                         (default: same as --topic; MirrorMaker 2 uses <source-alias>.<topic>)
+  --rate N               Messages produced per second (default: 100)
+  --window DUR           Lag time-series window size (default: 1s)
+  --drain DUR            Max wait for replication after producing ends (default: 30s)
+
+END-TO-END LATENCY OPTIONS (--mode e2e):
+  Produces -n messages at a fixed rate while consuming the same topic, and
+  reports the delay from send to consumer receipt next to the produce (ack)
+  latency. Each message carries its send time and a correlation ID in the
+  abc-e2e-sent-at and abc-e2e-id headers; both ends are timed on this host.
+  --rate N               Messages produced per second (default: 100)
+  --window DUR           Latency time-series window size (default: 1s)
+  --drain DUR            Max wait for delivery after producing ends (default: 30s)
+
+COLD READ OPTIONS (--mode coldread):
+  Alternates fetches of messages older than --cold-age with fetches from the
+  partition tail (-n fetches in total) and reports the latency penalty of cold
+  reads, e.g. to quantify tiered-storage remote reads. Needs existing old data.
+  --cold-age DUR         Minimum age of cold-read messages (default: 24h)
+  --read-batch N         Messages read per fetch (default: 100)
+
 EXAMPLES:
   abc-runner kafka --help
   abc-runner kafka --brokers localhost:9092 --topic test
   abc-runner kafka --brokers localhost:9092 --topic my-topic --mode producer -n 500 -c 3
+  abc-runner kafka --brokers b1:9092,b2:9092,b3:9092 --topic bench --create-topic --partitions 12 --replication-factor 3 -n 100000 -c 12
+  abc-runner kafka --brokers pkc-xxxxx.us-east-1.aws.confluent.cloud:9092 --tls --sasl-user API_KEY --sasl-password API_SECRET --topic bench
+  abc-runner kafka --brokers b-1.msk.example.com:9096 --tls --sasl-mechanism SCRAM-SHA-512 --sasl-user bench --topic bench
+  abc-runner kafka --brokers localhost:9092 --topic bulk --batch-size 500 --linger 10ms --compression zstd -n 100000 -c 4
+  abc-runner kafka --topic orders --schema-registry http://localhost:8081 --schema order.avsc -n 100000 -c 8
+  abc-runner kafka --topic events --value-template '{"event":"{{pick click view buy}}","user":"{{uuid}}","ts":{{timestamp}}}'
+  abc-runner kafka --brokers localhost:9092 --topic commit-test --mode commit -n 10000 --commit manual --commit-batch 100 --commit-async --restarts 3
+  abc-runner kafka --brokers dc1:9092 --topic orders --mode relay --target-brokers dc2:9092 --target-topic dc1.orders -n 6000 --rate 100
+  abc-runner kafka --brokers localhost:9092 --topic e2e --create-topic --partitions 6 --mode e2e -n 30000 --rate 500
+  abc-runner kafka --brokers localhost:9092 --topic events --mode coldread --cold-age 72h -n 2000 -c 4
 
 NOTE: 
-  This implementation performs real Kafka performance testing with metrics collection.
-`
+  This implementation performs real Kafka performance testing with metrics collection.` + configLayersHelp + runOptionsHelp + "\n"
 }
 
 // parseArgs 解析命令行参数
@@ -122,6 +299,13 @@ func (k *KafkaCommandHandler) parseArgs(args []string) (*kafkaConfig.KafkaAdapte
 	config.Benchmark.TestType = "producer"
 	config.Benchmark.MessageSize = 1024
 	config.Benchmark.Timeout = 30 * time.Second
+	config.Consumer.AutoCommitInterval = time.Second
+	config.Producer.LingerMs = 5 * time.Millisecond
+
+	// 依次应用--config文件、ABC_KAFKA_*环境变量与--set，专用参数随后覆盖
+	if err := applyConfigLayers("kafka", args, config); err != nil {
+		return nil, err
+	}
 
 	// 解析参数
 	for i := 0; i < len(args); i++ {
@@ -139,7 +323,7 @@ func (k *KafkaCommandHandler) parseArgs(args []string) (*kafkaConfig.KafkaAdapte
 		case "--mode":
 			if i+1 < len(args) {
 				mode := args[i+1]
-				if mode == "producer" || mode == "consumer" || mode == "both" {
+				if mode == "producer" || mode == "consumer" || mode == "both" || mode == "commit" || mode == "relay" || mode == "e2e" || mode == "coldread" {
 					config.Benchmark.TestType = mode
 				}
 				i++
@@ -158,23 +342,255 @@ func (k *KafkaCommandHandler) parseArgs(args []string) (*kafkaConfig.KafkaAdapte
 				}
 				i++
 			}
+		case "--group":
+			if i+1 < len(args) {
+				config.Consumer.GroupID = args[i+1]
+				i++
+			}
+		case "--commit":
+			if i+1 < len(args) {
+				switch args[i+1] {
+				case "auto":
+					config.Consumer.EnableAutoCommit = true
+				case "manual":
+					config.Consumer.EnableAutoCommit = false
+				default:
+					return nil, fmt.Errorf("invalid --commit mode %q (expected auto or manual)", args[i+1])
+				}
+				i++
+			}
+		case "--commit-interval":
+			if i+1 < len(args) {
+				interval, err := time.ParseDuration(args[i+1])
+				if err != nil {
+					return nil, fmt.Errorf("invalid --commit-interval: %w", err)
+				}
+				config.Consumer.AutoCommitInterval = interval
+				i++
+			}
+		case "--commit-batch":
+			if i+1 < len(args) {
+				if count, err := strconv.Atoi(args[i+1]); err == nil && count > 0 {
+					config.Consumer.CommitBatch = count
+				}
+				i++
+			}
+		case "--commit-async":
+			config.Consumer.CommitSync = false
+		case "--restarts":
+			if i+1 < len(args) {
+				if count, err := strconv.Atoi(args[i+1]); err == nil && count >= 0 {
+					config.Benchmark.Restarts = count
+				}
+				i++
+			}
+		case "--value-template":
+			if i+1 < len(args) {
+				config.Benchmark.Payload.Template = args[i+1]
+				i++
+			}
+		case "--data-file":
+			if i+1 < len(args) {
+				config.Benchmark.Payload.DataFile = args[i+1]
+				i++
+			}
+		case "--create-topic":
+			config.Benchmark.Topic.Auto = true
+		case "--partitions", "--replication-factor":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", args[i])
+			}
+			count, err := strconv.Atoi(args[i+1])
+			if err != nil || (count <= 0 && count != -1) {
+				return nil, fmt.Errorf("invalid value for %s: %q (expected a positive number or -1)", args[i], args[i+1])
+			}
+			if args[i] == "--partitions" {
+				config.Benchmark.Topic.Partitions = count
+			} else {
+				config.Benchmark.Topic.ReplicationFactor = count
+			}
+			i++
+		case "--target-brokers":
+			if i+1 < len(args) {
+				config.Relay.TargetBrokers = strings.Split(args[i+1], ",")
+				i++
+			}
+		case "--target-topic":
+			if i+1 < len(args) {
+				config.Relay.TargetTopic = args[i+1]
+				i++
+			}
+		case "--rate":
+			if i+1 < len(args) {
+				if rate, err := strconv.Atoi(args[i+1]); err == nil && rate > 0 {
+					config.Relay.Rate = rate
+					config.EndToEnd.Rate = rate
+				}
+				i++
+			}
+		case "--window":
+			if i+1 < len(args) {
+				window, err := time.ParseDuration(args[i+1])
+				if err != nil {
+					return nil, fmt.Errorf("invalid --window: %w", err)
+				}
+				config.Relay.Window = window
+				config.EndToEnd.Window = window
+				i++
+			}
+		case "--drain":
+			if i+1 < len(args) {
+				drain, err := time.ParseDuration(args[i+1])
+				if err != nil {
+					return nil, fmt.Errorf("invalid --drain: %w", err)
+				}
+				config.Relay.Drain = drain
+				config.EndToEnd.Drain = drain
+				i++
+			}
+		case "--sasl-mechanism", "--sasl-user", "--sasl-password":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", args[i])
+			}
+			switch args[i] {
+			case "--sasl-mechanism":
+				config.Security.SASL.Mechanism = strings.ToUpper(args[i+1])
+			case "--sasl-user":
+				config.Security.SASL.Username = args[i+1]
+			case "--sasl-password":
+				config.Security.SASL.Password = args[i+1]
+			}
+			config.Security.SASL.Enabled = true
+			i++
+		case "--tls":
+			config.Security.TLS.Enabled = true
+		case "--cacert", "--cert", "--key", "--sni":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", args[i])
+			}
+			switch args[i] {
+			case "--cacert":
+				config.Security.TLS.CaFile = args[i+1]
+			case "--cert":
+				config.Security.TLS.CertFile = args[i+1]
+			case "--key":
+				config.Security.TLS.KeyFile = args[i+1]
+			case "--sni":
+				config.Security.TLS.ServerName = args[i+1]
+			}
+			config.Security.TLS.Enabled = true
+			i++
+		case "--insecure":
+			config.Security.TLS.Enabled = true
+			config.Security.TLS.VerifySSL = false
+		case "--schema-registry", "--schema", "--schema-format", "--subject", "--registry-auth":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", args[i])
+			}
+			switch args[i] {
+			case "--schema-registry":
+				config.SchemaRegistry.URL = args[i+1]
+			case "--schema":
+				config.SchemaRegistry.SchemaFile = args[i+1]
+			case "--schema-format":
+				config.SchemaRegistry.Format = strings.ToLower(args[i+1])
+			case "--subject":
+				config.SchemaRegistry.Subject = args[i+1]
+			case "--registry-auth":
+				username, password, ok := strings.Cut(args[i+1], ":")
+				if !ok {
+					return nil, fmt.Errorf("invalid --registry-auth: expected KEY:SECRET")
+				}
+				config.SchemaRegistry.Username = username
+				config.SchemaRegistry.Password = password
+			}
+			i++
+		case "--batch-size":
+			if i+1 < len(args) {
+				size, err := strconv.Atoi(args[i+1])
+				if err != nil || size <= 0 {
+					return nil, fmt.Errorf("invalid --batch-size: %q (expected a positive number)", args[i+1])
+				}
+				config.Benchmark.BatchSize = size
+				i++
+			}
+		case "--linger":
+			if i+1 < len(args) {
+				linger, err := time.ParseDuration(args[i+1])
+				if err != nil || linger <= 0 {
+					return nil, fmt.Errorf("invalid --linger: %q (expected a positive duration)", args[i+1])
+				}
+				config.Producer.LingerMs = linger
+				i++
+			}
+		case "--compression":
+			if i+1 < len(args) {
+				switch args[i+1] {
+				case "none", "gzip", "snappy", "lz4", "zstd":
+					config.Producer.Compression = args[i+1]
+				default:
+					return nil, fmt.Errorf("invalid --compression %q (expected none, gzip, snappy, lz4 or zstd)", args[i+1])
+				}
+				i++
+			}
+		case "--cold-age":
+			if i+1 < len(args) {
+				age, err := time.ParseDuration(args[i+1])
+				if err != nil || age <= 0 {
+					return nil, fmt.Errorf("invalid --cold-age: %q (expected a positive duration)", args[i+1])
+				}
+				config.ColdRead.Age = age
+				i++
+			}
+		case "--read-batch":
+			if i+1 < len(args) {
+				batch, err := strconv.Atoi(args[i+1])
+				if err != nil || batch <= 0 {
+					return nil, fmt.Errorf("invalid --read-batch: %q (expected a positive number)", args[i+1])
+				}
+				config.ColdRead.Batch = batch
+				i++
+			}
 		}
 	}
 
+	if config.Security.SASL.Enabled {
+		if config.Security.SASL.Mechanism == "" {
+			config.Security.SASL.Mechanism = "PLAIN"
+		}
+		if config.Security.SASL.Password == "" {
+			config.Security.SASL.Password = os.Getenv("KAFKA_SASL_PASSWORD")
+		}
+	}
+	// 安全设置有误时直接报错，避免连接失败后进入模拟模式
+	if err := config.Security.Validate(); err != nil {
+		return nil, err
+	}
+	if err := config.SchemaRegistry.Validate(); err != nil {
+		return nil, err
+	}
+	if config.Benchmark.TestType == "relay" && len(config.Relay.TargetBrokers) == 0 {
+		return nil, fmt.Errorf("--mode relay requires --target-brokers")
+	}
+	if err := config.Benchmark.Payload.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid --value-template: %w", err)
+	}
+
 	return config, nil
 }
 
 // runPerformanceTest 运行性能测试 - 使用新的ExecutionEngine
-func (k *KafkaCommandHandler) runPerformanceTest(ctx context.Context, adapter interfaces.ProtocolAdapter, config *kafkaConfig.KafkaAdapterConfig, collector *metrics.BaseCollector[map[string]interface{}]) error {
+func (k *KafkaCommandHandler) runPerformanceTest(ctx context.Context, adapter interfaces.ProtocolAdapter, config *kafkaConfig.KafkaAdapterConfig, collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
 	// 执行健康检查
 	if err := adapter.HealthCheck(ctx); err != nil {
 		log.Printf("Health check failed, running in simulation mode: %v", err)
 		// 在模拟模式下生成测试数据
+		opts.markSimulated(err)
 		return k.runSimulationTest(config, collector)
 	}
 
 	// 使用新的ExecutionEngine执行真实测试
-	return k.runConcurrentTest(ctx, adapter, config, collector)
+	return k.runConcurrentTest(ctx, adapter, config, collector, opts)
 }
 
 // runSimulationTest 运行模拟测试
@@ -216,14 +632,14 @@ func (k *KafkaCommandHandler) runSimulationTest(config *kafkaConfig.KafkaAdapter
 
 // runConcurrentTest 使用ExecutionEngine运行并发测试
 // runConcurrentTest 使用ExecutionEngine运行并发测试
-func (k *KafkaCommandHandler) runConcurrentTest(ctx context.Context, adapter interfaces.ProtocolAdapter, config *kafkaConfig.KafkaAdapterConfig, collector *metrics.BaseCollector[map[string]interface{}]) error {
+func (k *KafkaCommandHandler) runConcurrentTest(ctx context.Context, adapter interfaces.ProtocolAdapter, config *kafkaConfig.KafkaAdapterConfig, collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
 	fmt.Printf("📊 Running concurrent Kafka performance test with ExecutionEngine...\n")
 
 	// 创建基准配置适配器
 	benchmarkConfig := kafkaConfig.NewBenchmarkConfigAdapter(&config.Benchmark)
 
 	// 创建操作工厂
-	operationFactory := &SimpleKafkaOperationFactory{config: config}
+	operationFactory := newSimpleKafkaOperationFactory(config)
 
 	// 创建执行引擎
 	engine := execution.NewExecutionEngine(adapter, collector, operationFactory)
@@ -232,6 +648,12 @@ func (k *KafkaCommandHandler) runConcurrentTest(ctx context.Context, adapter int
 	engine.SetMaxWorkers(100)         // 设置最大工作协程数
 	engine.SetBufferSizes(1000, 1000) // 设置缓冲区大小
 
+	// 预填充测试主题（不计入指标）
+	if err := opts.runPrefill(ctx, engine, collector, config.Benchmark.Parallels); err != nil {
+		return err
+	}
+	opts.applyToEngine(engine, collector) // 应用通用运行选项
+
 	// 记录测试开始时间
 	testStartTime := time.Now()
 
@@ -240,6 +662,7 @@ func (k *KafkaCommandHandler) runConcurrentTest(ctx context.Context, adapter int
 	if err != nil {
 		return fmt.Errorf("benchmark execution failed: %w", err)
 	}
+	opts.finishRun()
 
 	// 计算实际测试时间
 	actualTestDuration := time.Since(testStartTime)
@@ -254,19 +677,269 @@ func (k *KafkaCommandHandler) runConcurrentTest(ctx context.Context, adapter int
 	fmt.Printf("   Actual Test Duration: %v\n", actualTestDuration)
 	if result.CompletedJobs > 0 {
 		fmt.Printf("   Success Rate: %.2f%%\n", float64(result.SuccessJobs)/float64(result.CompletedJobs)*100)
-		// 计算正确的QPS（基于实际测试时间）
+		// 计算正确的QPS（基于实际测试时间），批量读写时每个任务为一批消息
 		actualQPS := float64(result.CompletedJobs) / actualTestDuration.Seconds()
-		fmt.Printf("   Actual QPS: %.2f messages/sec\n", actualQPS)
+		unit := "messages"
+		if config.Benchmark.GetBatchSize() > 1 {
+			unit = "batches"
+		}
+		fmt.Printf("   Actual QPS: %.2f %s/sec\n", actualQPS, unit)
 	}
 
 	// 更新收集器的协议数据，包含实际测试时间
-	collector.UpdateProtocolMetrics(map[string]interface{}{
+	protocolMetrics := map[string]interface{}{
 		"protocol":         "kafka",
 		"test_type":        "performance",
 		"actual_duration":  actualTestDuration,
 		"execution_result": result,
+	}
+	if kafkaAdapter, ok := adapter.(*kafka.KafkaAdapter); ok {
+		if stats, ok := kafkaAdapter.BatchStats(); ok {
+			printBatchStats(stats)
+			protocolMetrics["batch"] = stats.ToMap()
+		}
+		if stats, ok := kafkaAdapter.SerializationStats(); ok {
+			printSerializationStats(stats)
+			protocolMetrics["serialization"] = stats.ToMap()
+		}
+		if phases, ok := kafkaAdapter.LatencyPhases(); ok {
+			printLatencyPhases(phases)
+			protocolMetrics["latency_phases"] = phases
+		}
+	}
+	collector.UpdateProtocolMetrics(protocolMetrics)
+
+	return nil
+}
+
+// printBatchStats 输出批量生产/消费的批次延迟、批次大小分布与吞吐
+func printBatchStats(stats operations.BatchStats) {
+	directions := []struct {
+		name  string
+		stats *operations.BatchDirectionStats
+	}{{"produce", stats.Produce}, {"consume", stats.Consume}}
+
+	fmt.Printf("\n📦 Batches (%d messages per batch):\n", stats.BatchSize)
+	for _, direction := range directions {
+		if direction.stats == nil {
+			continue
+		}
+		s := direction.stats
+		fmt.Printf("   %s: %d batches, %d errors, %d messages, avg size %.1f\n",
+			direction.name, s.Batches, s.Errors, s.Messages, s.AvgSize)
+		fmt.Printf("     Batch latency avg: %v, p50: %v, p99: %v, max: %v\n",
+			s.Latency.Average, s.Latency.P50, s.Latency.P99, s.Latency.Max)
+		fmt.Printf("     Throughput: %.2f MB/s, %.0f messages/sec\n", s.BytesPerSec/1024/1024, s.MsgsPerSec)
+		for _, bucket := range s.Sizes {
+			sizes := strconv.Itoa(bucket.Max)
+			if bucket.Min != bucket.Max {
+				sizes = fmt.Sprintf("%d-%d", bucket.Min, bucket.Max)
+			}
+			fmt.Printf("     %12s messages: %d\n", sizes, bucket.Count)
+		}
+	}
+	fmt.Println()
+}
+
+// printSerializationStats 输出Schema Registry序列化耗时与线上消息大小，序列化耗时不计入生产延迟
+func printSerializationStats(stats operations.SerializationStats) {
+	fmt.Printf("\n🧬 Serialization (%s, subject %s, schema id %d):\n", stats.Format, stats.Subject, stats.SchemaID)
+	fmt.Printf("   Messages: %d (%d generated from the schema), Errors: %d\n", stats.Messages, stats.Generated, stats.Errors)
+	fmt.Printf("   Latency avg: %v, p50: %v, p99: %v, max: %v\n",
+		stats.Latency.Average, stats.Latency.P50, stats.Latency.P99, stats.Latency.Max)
+	fmt.Printf("   Wire size avg: %.1f bytes, total: %d bytes\n", stats.AvgWireSize, stats.WireBytes)
+	fmt.Println()
+}
+
+// runCommitTest 运行偏移提交策略测试
+func (k *KafkaCommandHandler) runCommitTest(ctx context.Context, adapter *kafka.KafkaAdapter, config *kafkaConfig.KafkaAdapterConfig, collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
+	if err := adapter.HealthCheck(ctx); err != nil {
+		return NewConnectionError(fmt.Errorf("kafka is not reachable (commit mode has no simulation): %w", err))
+	}
+
+	fmt.Printf("📊 Running Kafka offset commit test (restarts: %d)...\n", config.Benchmark.Restarts)
+
+	opts.applyToCollector(collector)
+	stats, err := adapter.RunCommitBenchmark(ctx)
+	opts.finishRun()
+	if stats == nil {
+		return err
+	}
+
+	fmt.Printf("✅ Offset commit test completed\n")
+	fmt.Printf("   Strategy: %s\n", stats.Strategy)
+	fmt.Printf("   Produced: %d, Consumed: %d, Unique: %d\n", stats.Produced, stats.Consumed, stats.Unique)
+	fmt.Printf("   Duplicates after %d restart(s): %d (%.2f%%)\n", stats.Restarts, stats.Duplicates, stats.DuplicateRate)
+	if stats.Commits > 0 || stats.CommitErrors > 0 {
+		fmt.Printf("   Commits: %d, Errors: %d, Latency avg: %v, p99: %v, max: %v\n",
+			stats.Commits, stats.CommitErrors, stats.CommitLatency.Average, stats.CommitLatency.P99, stats.CommitLatency.Max)
+	} else {
+		fmt.Printf("   Commits are issued by the client in auto mode; commit latency is not observable\n")
+	}
+
+	collector.UpdateProtocolMetrics(map[string]interface{}{
+		"protocol":        "kafka",
+		"test_type":       "offset_commit",
+		"actual_duration": stats.Duration,
+		"offset_commit":   stats.ToMap(),
+	})
+
+	if err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	}
+	return nil
+}
+
+// runRelayTest 运行跨集群复制延迟测试
+func (k *KafkaCommandHandler) runRelayTest(ctx context.Context, adapter *kafka.KafkaAdapter, config *kafkaConfig.KafkaAdapterConfig, collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
+	if err := adapter.HealthCheck(ctx); err != nil {
+		return NewConnectionError(fmt.Errorf("source cluster is not reachable (relay mode has no simulation): %w", err))
+	}
+
+	fmt.Printf("📊 Running Kafka cross-cluster relay test: %s → %s, rate=%d msg/s...\n",
+		strings.Join(config.Brokers, ","), strings.Join(config.Relay.TargetBrokers, ","), config.Relay.Rate)
+
+	// 测试时长由消息数与发送速率决定，可能超过命令的默认超时，这里仅响应显式取消
+	runCtx := context.WithoutCancel(ctx)
+
+	opts.applyToCollector(collector)
+	stats, err := adapter.RunRelayBenchmark(runCtx)
+	opts.finishRun()
+	if stats == nil {
+		return err
+	}
+
+	fmt.Printf("✅ Cross-cluster relay test completed (%s → %s)\n", stats.SourceTopic, stats.TargetTopic)
+	fmt.Printf("   Sent: %d, Received: %d, Lost: %d (%.2f%%), Duplicates: %d, Send errors: %d\n",
+		stats.Sent, stats.Received, stats.Lost, stats.LossRate, stats.Duplicates, stats.SendErrors)
+	fmt.Printf("   Replication lag avg: %v, p50: %v, p99: %v, p99.9: %v, max: %v\n",
+		stats.Lag.Average, stats.Lag.P50, stats.Lag.P99, stats.Lag.P999, stats.Lag.Max)
+	fmt.Printf("\n%10s %8s %10s %12s %12s %12s\n", "window", "sent", "received", "lag p50", "lag p99", "lag max")
+	for _, window := range stats.Windows {
+		fmt.Printf("%10v %8d %10d %12v %12v %12v\n", window.Start, window.Sent, window.Received,
+			window.LagP50.Round(time.Microsecond), window.LagP99.Round(time.Microsecond), window.LagMax.Round(time.Microsecond))
+	}
+	fmt.Println()
+
+	collector.UpdateProtocolMetrics(map[string]interface{}{
+		"protocol":        "kafka",
+		"test_type":       "relay",
+		"actual_duration": stats.Duration,
+		"relay":           stats.ToMap(),
 	})
 
+	if err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	}
+	return nil
+}
+
+// runEndToEndTest 运行端到端延迟测试
+func (k *KafkaCommandHandler) runEndToEndTest(ctx context.Context, adapter *kafka.KafkaAdapter, config *kafkaConfig.KafkaAdapterConfig, collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
+	if err := adapter.HealthCheck(ctx); err != nil {
+		return NewConnectionError(fmt.Errorf("kafka is not reachable (e2e mode has no simulation): %w", err))
+	}
+
+	fmt.Printf("📊 Running Kafka end-to-end latency test: rate=%d msg/s, acks=%s...\n",
+		config.EndToEnd.Rate, config.Producer.Acks)
+
+	// 测试时长由消息数与发送速率决定，可能超过命令的默认超时，这里仅响应显式取消
+	runCtx := context.WithoutCancel(ctx)
+
+	opts.applyToCollector(collector)
+	stats, err := adapter.RunEndToEndBenchmark(runCtx)
+	opts.finishRun()
+	if stats == nil {
+		return err
+	}
+
+	fmt.Printf("✅ End-to-end latency test completed (%s)\n", stats.Topic)
+	fmt.Printf("   Sent: %d, Received: %d, Lost: %d (%.2f%%), Duplicates: %d, Send errors: %d\n",
+		stats.Sent, stats.Received, stats.Lost, stats.LossRate, stats.Duplicates, stats.SendErrors)
+	fmt.Printf("\n%-12s %12s %12s %12s %12s %12s %12s\n", "latency", "avg", "p50", "p90", "p99", "p999", "max")
+	for _, row := range []struct {
+		name    string
+		latency metrics.LatencyMetrics
+	}{{"produce", stats.Produce}, {"end-to-end", stats.EndToEnd}} {
+		fmt.Printf("%-12s %12v %12v %12v %12v %12v %12v\n", row.name,
+			row.latency.Average.Round(time.Microsecond), row.latency.P50.Round(time.Microsecond), row.latency.P90.Round(time.Microsecond),
+			row.latency.P99.Round(time.Microsecond), row.latency.P999.Round(time.Microsecond), row.latency.Max.Round(time.Microsecond))
+	}
+	if len(stats.Partitions) > 1 {
+		fmt.Printf("\n%10s %10s %12s %12s %12s\n", "partition", "received", "p50", "p99", "max")
+		for _, partition := range stats.Partitions {
+			fmt.Printf("%10d %10d %12v %12v %12v\n", partition.Partition, partition.Received,
+				partition.Latency.P50.Round(time.Microsecond), partition.Latency.P99.Round(time.Microsecond),
+				partition.Latency.Max.Round(time.Microsecond))
+		}
+	}
+	fmt.Printf("\n%10s %8s %10s %12s %12s %12s\n", "window", "sent", "received", "e2e p50", "e2e p99", "e2e max")
+	for _, window := range stats.Windows {
+		fmt.Printf("%10v %8d %10d %12v %12v %12v\n", window.Start, window.Sent, window.Received,
+			window.LagP50.Round(time.Microsecond), window.LagP99.Round(time.Microsecond), window.LagMax.Round(time.Microsecond))
+	}
+	fmt.Println()
+
+	collector.UpdateProtocolMetrics(map[string]interface{}{
+		"protocol":        "kafka",
+		"test_type":       "e2e",
+		"actual_duration": stats.Duration,
+		"end_to_end":      stats.ToMap(),
+	})
+
+	if err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	}
+	return nil
+}
+
+// runColdReadTest 运行冷数据读取（分层存储）测试
+func (k *KafkaCommandHandler) runColdReadTest(ctx context.Context, adapter *kafka.KafkaAdapter, config *kafkaConfig.KafkaAdapterConfig, collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
+	if err := adapter.HealthCheck(ctx); err != nil {
+		return NewConnectionError(fmt.Errorf("kafka is not reachable (coldread mode has no simulation): %w", err))
+	}
+
+	fmt.Printf("📊 Running Kafka cold read test: messages older than %v vs. partition tail, %d per fetch...\n",
+		config.ColdRead.Age, config.ColdRead.Batch)
+
+	opts.applyToCollector(collector)
+	stats, err := adapter.RunColdReadBenchmark(ctx)
+	opts.finishRun()
+	if stats == nil {
+		return err
+	}
+
+	fmt.Printf("✅ Cold read test completed (%d of %d partitions hold messages older than %v)\n",
+		stats.ColdPartitions, stats.Partitions, stats.Age)
+	fmt.Printf("\n%6s %7s %7s %10s %12s %12s %12s %12s %10s\n",
+		"tier", "reads", "errors", "messages", "avg age", "p50", "p99", "max", "MB/s")
+	for _, tier := range []struct {
+		name  string
+		stats operations.ColdReadTier
+	}{{"cold", stats.Cold}, {"tail", stats.Tail}} {
+		fmt.Printf("%6s %7d %7d %10d %12v %12v %12v %12v %10.2f\n", tier.name,
+			tier.stats.Reads, tier.stats.Errors, tier.stats.Messages, tier.stats.MessageAge.Round(time.Second),
+			tier.stats.Latency.P50.Round(time.Microsecond), tier.stats.Latency.P99.Round(time.Microsecond),
+			tier.stats.Latency.Max.Round(time.Microsecond), tier.stats.Throughput)
+	}
+	fmt.Println()
+	if stats.PenaltyP50 > 0 {
+		fmt.Printf("   Cold read penalty: p50 %.2fx, p99 %.2fx\n", stats.PenaltyP50, stats.PenaltyP99)
+	}
+	if stats.Wraps > 0 {
+		fmt.Printf("   ⚠️  Cold ranges were re-read %d time(s); re-reads may be served from broker cache\n", stats.Wraps)
+	}
+
+	collector.UpdateProtocolMetrics(map[string]interface{}{
+		"protocol":        "kafka",
+		"test_type":       "coldread",
+		"actual_duration": stats.Duration,
+		"cold_read":       stats.ToMap(),
+	})
+
+	if err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	}
 	return nil
 }
 
@@ -335,7 +1008,7 @@ func (k *KafkaCommandHandler) runConsumerTest(ctx context.Context, adapter inter
 
 // generateReport 生成报告
 // generateReport 生成报告
-func (k *KafkaCommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}]) error {
+func (k *KafkaCommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
 	// 获取指标快照
 	snapshot := collector.Snapshot()
 
@@ -361,6 +1034,12 @@ func (k *KafkaCommandHandler) generateReport(collector *metrics.BaseCollector[ma
 		snapshot.Core.Throughput.RPS = float64(total) / seconds
 		snapshot.Core.Throughput.ReadRPS = float64(snapshot.Core.Operations.Read) / seconds
 		snapshot.Core.Throughput.WriteRPS = float64(snapshot.Core.Operations.Write) / seconds
+		snapshot.Core.Throughput.ReadMBps, snapshot.Core.Throughput.WriteMBps = metrics.Bandwidth(snapshot.Core.Operations, actualDuration)
+	}
+
+	// 存在快照接收器时（如分布式agent模式）交由接收器处理，不生成本地报告
+	if opts.publishSnapshot(snapshot) {
+		return nil
 	}
 
 	// 转换为结构化报告
@@ -368,25 +1047,53 @@ func (k *KafkaCommandHandler) generateReport(collector *metrics.BaseCollector[ma
 
 	// 使用标准报告配置
 	reportConfig := reporting.NewStandardReportConfig("kafka")
+	opts.applyToReport(snapshot, report, reportConfig)
 
 	generator := reporting.NewReportGenerator(reportConfig)
 
 	// 生成并显示报告
-	return generator.Generate(report)
+	if err := generator.Generate(report); err != nil {
+		return err
+	}
+	return opts.resultError(report)
 }
 
 // SimpleKafkaOperationFactory 简单的Kafka操作工厂
 type SimpleKafkaOperationFactory struct {
-	config *kafkaConfig.KafkaAdapterConfig
+	config  *kafkaConfig.KafkaAdapterConfig
+	payload *utils.PayloadTemplate // 消息内容模板，未配置时使用固定格式的测试消息
+}
+
+// newSimpleKafkaOperationFactory 创建操作工厂，模板在解析参数时已验证
+func newSimpleKafkaOperationFactory(config *kafkaConfig.KafkaAdapterConfig) *SimpleKafkaOperationFactory {
+	factory := &SimpleKafkaOperationFactory{config: config}
+	if config.Benchmark.Payload.Enabled() {
+		if payload, err := config.Benchmark.Payload.Compile(); err == nil {
+			factory.payload = payload
+		}
+	}
+	return factory
+}
+
+// messageValue 第seq条消息的内容
+func (f *SimpleKafkaOperationFactory) messageValue(seq int) string {
+	if f.payload != nil {
+		return f.payload.Render(seq)
+	}
+	return fmt.Sprintf("kafka_test_message_%d_size_%d", seq, f.config.Benchmark.MessageSize)
 }
 
 // CreateOperation 创建操作
 func (f *SimpleKafkaOperationFactory) CreateOperation(jobID int, config execution.BenchmarkConfig) interfaces.Operation {
+	if f.config.Benchmark.GetBatchSize() > 1 {
+		return f.createBatchOperation(jobID)
+	}
+
 	// 生成键
 	key := fmt.Sprintf("kafka_%s_%d", f.config.Benchmark.TestType, jobID)
 
 	// 生成测试数据
-	testData := fmt.Sprintf("kafka_test_message_%d_size_%d", jobID, f.config.Benchmark.MessageSize)
+	testData := f.messageValue(jobID)
 
 	// 创建操作
 	operation := interfaces.Operation{
@@ -409,6 +1116,69 @@ func (f *SimpleKafkaOperationFactory) CreateOperation(jobID int, config executio
 	return operation
 }
 
+// createBatchOperation 创建批量操作：第jobID批包含消息jobID*N到jobID*N+N-1，最后一批可能不足N条
+func (f *SimpleKafkaOperationFactory) createBatchOperation(jobID int) interfaces.Operation {
+	batchSize := f.config.Benchmark.GetBatchSize()
+	first := jobID * batchSize
+	count := min(batchSize, f.config.Benchmark.Total-first)
+	if count <= 0 {
+		count = batchSize
+	}
+
+	params := map[string]interface{}{
+		"topic":        f.config.Benchmark.DefaultTopic,
+		"message_size": f.config.Benchmark.MessageSize,
+		"job_id":       jobID,
+	}
+	opType := "produce_batch"
+	if f.getOperationType() == "consume" {
+		opType = "consume_batch"
+		params["max_messages"] = count
+		params["timeout"] = f.config.Benchmark.GetTimeout()
+	} else {
+		messages := make([]*operations.Message, count)
+		for i := range messages {
+			seq := first + i
+			messages[i] = &operations.Message{
+				Key:   fmt.Sprintf("kafka_%s_%d", f.config.Benchmark.TestType, seq),
+				Value: f.messageValue(seq),
+			}
+		}
+		params["messages"] = messages
+	}
+
+	return interfaces.Operation{
+		Type:   opType,
+		Key:    fmt.Sprintf("kafka_%s_batch_%d", f.config.Benchmark.TestType, jobID),
+		Params: params,
+		Metadata: map[string]string{
+			"protocol":  "kafka",
+			"test_type": f.config.Benchmark.TestType,
+			"topic":     f.config.Benchmark.DefaultTopic,
+		},
+	}
+}
+
+// CreatePrepareOperation 创建预填充操作：向测试主题写入一条消息，供消费测试读取
+func (f *SimpleKafkaOperationFactory) CreatePrepareOperation(index int) interfaces.Operation {
+	return interfaces.Operation{
+		Type:  "produce",
+		Key:   fmt.Sprintf("kafka_prefill_%d", index),
+		Value: fmt.Sprintf("kafka_prefill_message_%d_size_%d", index, f.config.Benchmark.MessageSize),
+		Params: map[string]interface{}{
+			"topic":        f.config.Benchmark.DefaultTopic,
+			"partition":    index % 3,
+			"message_size": f.config.Benchmark.MessageSize,
+			"job_id":       index,
+		},
+		Metadata: map[string]string{
+			"protocol":  "kafka",
+			"test_type": "prefill",
+			"topic":     f.config.Benchmark.DefaultTopic,
+		},
+	}
+}
+
 // getOperationType 获取操作类型
 func (f *SimpleKafkaOperationFactory) getOperationType() string {
 	switch f.config.Benchmark.TestType {
@@ -424,5 +1194,8 @@ func (f *SimpleKafkaOperationFactory) getOperationType() string {
 	}
 }
 
-// 确保实现了execution.OperationFactory接口
-var _ execution.OperationFactory = (*SimpleKafkaOperationFactory)(nil)
+// 确保实现了execution.OperationFactory与execution.PrepareFactory接口
+var (
+	_ execution.OperationFactory = (*SimpleKafkaOperationFactory)(nil)
+	_ execution.PrepareFactory   = (*SimpleKafkaOperationFactory)(nil)
+)
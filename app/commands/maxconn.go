@@ -0,0 +1,272 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"abc-runner/app/core/conntest"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/reporting"
+)
+
+// MaxConnCommandHandler 最大并发连接数探测命令处理器
+type MaxConnCommandHandler struct{}
+
+// NewMaxConnCommandHandler 创建最大并发连接数探测命令处理器
+func NewMaxConnCommandHandler() *MaxConnCommandHandler {
+	return &MaxConnCommandHandler{}
+}
+
+// maxConnArgs 最大并发连接数探测命令行参数
+type maxConnArgs struct {
+	target  string
+	options conntest.DialerOptions
+	config  *conntest.CeilingConfig
+}
+
+// Execute 执行最大并发连接数探测
+func (h *MaxConnCommandHandler) Execute(ctx context.Context, args []string) error {
+	for _, arg := range args {
+		if arg == "--help" || arg == "-h" || arg == "help" {
+			fmt.Println(h.GetHelp())
+			return nil
+		}
+	}
+
+	parsed, err := h.parseArgs(args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	// 解析通用运行选项
+	opts, err := parseRunOptions(ctx, "", args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	dialer, err := conntest.NewDialer(parsed.target, parsed.options)
+	if err != nil {
+		return err
+	}
+
+	metricsCollector := metrics.NewBaseCollector(opts.collectorConfig(), map[string]interface{}{
+		"protocol":  dialer.Name(),
+		"test_type": "maxconn",
+	})
+	defer metricsCollector.Stop()
+
+	// 爬升时长由目标决定，这里仅响应显式取消
+	runCtx := context.WithoutCancel(ctx)
+
+	cfg := parsed.config
+	mode := "idle"
+	if cfg.Drip > 0 {
+		mode = fmt.Sprintf("slow-drip every %v", cfg.Drip)
+	}
+	fmt.Printf("🚀 Starting %s connection ceiling search: target=%s, rate=%d conn/s, max=%d, mode=%s\n",
+		dialer.Name(), dialer.Target(), cfg.Rate, cfg.MaxConnections, mode)
+
+	opts.applyToCollector(metricsCollector)
+	runner := conntest.NewCeilingRunner(dialer, cfg, metricsCollector)
+	result, err := runner.Run(runCtx, func(sample conntest.CeilingSample) {
+		fmt.Printf("🔗 t=%v open=%d new=%d failed=%d dropped=%d\n",
+			sample.Elapsed, sample.Open, sample.Established, sample.Failed, sample.Dropped)
+	}, func(open int64) {
+		fmt.Printf("👀 Ramp stopped with %d open connections; observing for %v...\n", open, cfg.Observe)
+	})
+	opts.finishRun()
+	if err != nil && result == nil {
+		return fmt.Errorf("connection ceiling search failed: %w", err)
+	}
+	if err != nil {
+		fmt.Printf("⚠️  Connection ceiling search stopped early: %v\n", err)
+	}
+
+	h.printSummary(result)
+	return h.generateReport(metricsCollector, result, opts)
+}
+
+// parseArgs 解析命令行参数
+func (h *MaxConnCommandHandler) parseArgs(args []string) (*maxConnArgs, error) {
+	parsed := &maxConnArgs{
+		options: conntest.DialerOptions{Timeout: 5 * time.Second},
+		config:  conntest.NewDefaultCeilingConfig(),
+	}
+
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--insecure" || args[i] == "-k" {
+			parsed.options.InsecureSkipVerify = true
+			continue
+		}
+
+		if i+1 >= len(args) {
+			break
+		}
+		value := args[i+1]
+		var err error
+
+		switch args[i] {
+		case "--target", "-t":
+			parsed.target = value
+		case "--rate", "-r":
+			parsed.config.Rate, err = strconv.Atoi(value)
+		case "--concurrency", "-c":
+			parsed.config.Concurrency, err = strconv.Atoi(value)
+		case "--max":
+			parsed.config.MaxConnections, err = strconv.Atoi(value)
+		case "--max-failures":
+			parsed.config.MaxFailures, err = strconv.Atoi(value)
+		case "--max-duration":
+			parsed.config.MaxDuration, err = time.ParseDuration(value)
+		case "--observe":
+			parsed.config.Observe, err = time.ParseDuration(value)
+		case "--drip":
+			parsed.config.Drip, err = time.ParseDuration(value)
+		case "--drip-payload":
+			parsed.config.DripPayload, err = unquotePayload(value)
+		case "--timeout":
+			parsed.options.Timeout, err = time.ParseDuration(value)
+		case "--server-name":
+			parsed.options.ServerName = value
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", args[i], err)
+		}
+		i++
+	}
+
+	if parsed.target == "" {
+		return nil, fmt.Errorf("--target is required (e.g. tcp://localhost:80, tls://localhost:443, ws://localhost:7070/ws)")
+	}
+	if parsed.options.Timeout <= 0 {
+		return nil, fmt.Errorf("timeout must be positive")
+	}
+	if err := parsed.config.Validate(); err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+// unquotePayload 解析带Go转义序列的滴灌数据，如 "\r\n"
+func unquotePayload(value string) ([]byte, error) {
+	unquoted, err := strconv.Unquote(`"` + value + `"`)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(unquoted), nil
+}
+
+// printSummary 输出探测结果
+func (h *MaxConnCommandHandler) printSummary(result *conntest.CeilingResult) {
+	fmt.Printf("\n📊 Connection Ceiling Summary (%s %s)\n", result.Transport, result.Target)
+	fmt.Printf("  Ceiling:         %d concurrent connections\n", result.Ceiling)
+	fmt.Printf("  Stopped by:      %s after %v\n", result.StopReason, result.RampDuration.Round(time.Millisecond))
+	fmt.Printf("  Behavior:        %s\n", conntest.DescribeBehavior(result.Behavior))
+	fmt.Printf("  Attempts:        %d (established %d, failed %d)\n", result.Attempts, result.Established, result.Failed)
+	if result.FirstFailureOpen >= 0 {
+		fmt.Printf("  First failure:   %s with %d connections open\n", result.FirstFailureCause, result.FirstFailureOpen)
+	}
+	fmt.Printf("  Dropped by peer: %d (within 1s of opening: %d, while observing: %d)\n",
+		result.Dropped, result.EarlyDropped, result.ObserveDropped)
+	fmt.Printf("  Open at end:     %d\n", result.OpenAfterObserve)
+	fmt.Printf("  Connect latency: p50=%v p99=%v max=%v\n",
+		result.Total.P50.Round(time.Microsecond), result.Total.P99.Round(time.Microsecond), result.Total.Max.Round(time.Microsecond))
+	if result.Dropped > result.EarlyDropped {
+		fmt.Printf("  Dropped after:   p50=%v max=%v (for idle connections, the target's idle timeout)\n",
+			result.DropAfter.P50.Round(time.Millisecond), result.DropAfter.Max.Round(time.Millisecond))
+	}
+
+	printCounts := func(title string, counts map[string]int64) {
+		if len(counts) == 0 {
+			return
+		}
+		keys := make([]string, 0, len(counts))
+		for key := range counts {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool { return counts[keys[i]] > counts[keys[j]] })
+		fmt.Printf("\n%-24s %10s\n", title, "count")
+		for _, key := range keys {
+			fmt.Printf("%-24s %10d\n", key, counts[key])
+		}
+	}
+	printCounts("failure cause", result.Failures)
+	printCounts("drop cause", result.DropCauses)
+	fmt.Println()
+}
+
+// generateReport 生成报告
+func (h *MaxConnCommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}], result *conntest.CeilingResult, opts *runOptions) error {
+	snapshot := collector.Snapshot()
+	snapshot.Protocol["ceiling"] = result.ToMap()
+
+	if opts.publishSnapshot(snapshot) {
+		return nil
+	}
+
+	report := reporting.ConvertFromMetricsSnapshot(snapshot)
+	reportConfig := reporting.NewStandardReportConfig("maxconn_" + result.Transport)
+	opts.applyToReport(snapshot, report, reportConfig)
+	generator := reporting.NewReportGenerator(reportConfig)
+	if err := generator.Generate(report); err != nil {
+		return err
+	}
+	return opts.resultError(report)
+}
+
+// GetHelp 获取帮助信息
+func (h *MaxConnCommandHandler) GetHelp() string {
+	return `Max Concurrent Connections Ceiling Finder
+
+USAGE:
+  abc-runner maxconn --target URL [options]
+
+DESCRIPTION:
+  Keep opening connections at a fixed rate and hold them open (idle, or
+  slowly dripping bytes) until the target starts failing, then report the
+  highest number of simultaneously open connections and how the target
+  behaved at its ceiling: refusing, timing out, resetting new connections,
+  or evicting existing ones. Useful for proxy and load-balancer capacity
+  planning.
+
+  The ramp stops after --max-failures consecutive failed attempts (a
+  connection closed by the target within 1s of opening counts as failed),
+  when --max connections are open, or after --max-duration. All
+  connections are then held for --observe to see whether the target sheds
+  them, and finally closed.
+
+OPTIONS:
+  --help, -h               Show this help message
+  --target, -t URL         Target: tcp://host:port, tls://host:port,
+                           ws://host:port/path or wss://host:port/path
+                           (host:port without scheme means tcp)
+  --rate, -r N             New connections per second (default: 500)
+  --concurrency, -c N      Max in-flight handshakes (default: 50)
+  --max N                  Stop once N connections are open (default: 100000)
+  --max-failures N         Consecutive failures that mark the ceiling
+                           (default: 20)
+  --max-duration DUR       Maximum ramp time (default: 5m)
+  --observe DUR            Hold all connections after the ramp (default: 5s)
+  --drip DUR               Send --drip-payload on every connection at this
+                           interval (default: 0, connections stay idle)
+  --drip-payload STR       Bytes to drip, Go escapes allowed (default: "\r\n";
+                           WebSocket targets receive it as a ping)
+  --timeout DUR            Connect plus handshake timeout (default: 5s)
+  --insecure, -k           Skip TLS certificate verification
+  --server-name NAME       TLS SNI (default: target host)
+
+EXAMPLES:
+  abc-runner maxconn --target tcp://localhost:8080 -r 1000
+  abc-runner maxconn --target tls://lb.example.com:443 -k --drip 10s
+  abc-runner maxconn --target ws://localhost:7070/ws --max 20000 --observe 30s
+
+NOTE:
+  Every held connection uses a client file descriptor. Raise the limit
+  (ulimit -n) above --max, otherwise the result reports client_limit
+  instead of the target's ceiling.` + runOptionsHelp + "\n"
+}
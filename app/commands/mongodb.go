@@ -0,0 +1,475 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	mongoConfig "abc-runner/app/adapters/mongodb/config"
+	"abc-runner/app/adapters/mongodb/connection"
+	"abc-runner/app/adapters/mongodb/operations"
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/reporting"
+)
+
+// MongoCommandHandler MongoDB命令处理器
+type MongoCommandHandler struct {
+	protocolName string
+	factory      interfaces.MongoAdapterFactory
+}
+
+// NewMongoCommandHandler 创建MongoDB命令处理器
+func NewMongoCommandHandler(factory interfaces.MongoAdapterFactory) *MongoCommandHandler {
+	if factory == nil {
+		panic("mongoAdapterFactory cannot be nil - dependency injection required")
+	}
+
+	return &MongoCommandHandler{
+		protocolName: "mongodb",
+		factory:      factory,
+	}
+}
+
+// Execute 执行MongoDB命令
+func (h *MongoCommandHandler) Execute(ctx context.Context, args []string) error {
+	// 检查帮助请求
+	for i, arg := range args {
+		if arg == "--help" || arg == "help" {
+			fmt.Println(h.GetHelp())
+			return nil
+		}
+		if arg == "-h" && (i == 0 || (i > 0 && args[i-1] != "mongodb")) {
+			if i+1 < len(args) && !looksLikeHostname(args[i+1]) {
+				fmt.Println(h.GetHelp())
+				return nil
+			}
+		}
+	}
+
+	// 解析命令行参数
+	config, err := h.parseArgs(args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	// 解析通用运行选项
+	opts, err := parseRunOptions(ctx, "mongodb", args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+	if printConfigRequested(args) {
+		return printEffectiveConfig("mongodb", config, opts)
+	}
+
+	metricsCollector := metrics.NewBaseCollector(opts.collectorConfig(), map[string]interface{}{
+		"protocol":  "mongodb",
+		"test_type": "performance",
+	})
+	defer metricsCollector.Stop()
+
+	adapter := h.factory.CreateMongoAdapter()
+	if adapter == nil {
+		return fmt.Errorf("failed to create MongoDB adapter")
+	}
+	defer adapter.Close()
+
+	target := config.Connection.GetAddresses()[0]
+	if err := adapter.Connect(ctx, config); err != nil {
+		return NewConnectionError(fmt.Errorf("failed to connect to mongodb %s: %w", target, err))
+	}
+	if err := adapter.HealthCheck(ctx); err != nil {
+		return NewConnectionError(fmt.Errorf("mongodb health check failed: %w", err))
+	}
+
+	specific := config.MongoSpecific
+	if mongoAdapter, ok := adapter.(interface {
+		ServerVersion() string
+		Seeded() bool
+	}); ok {
+		fmt.Printf("✅ Connected to MongoDB %s at %s (database: %s, pool: %d)\n",
+			mongoAdapter.ServerVersion(), target, config.Connection.Database, config.GetPoolSize())
+		if mongoAdapter.Seeded() {
+			fmt.Printf("🛠️  Seeded collection %s with %d documents\n", specific.Collection, specific.Documents)
+		}
+	}
+
+	fmt.Printf("🚀 Starting MongoDB performance test...\n")
+	fmt.Printf("Test Case: %s\n", config.BenchMark.TestCase)
+	if config.BenchMark.TestCase == mongoConfig.TestCaseMixed {
+		fmt.Printf("Mix: find=%d insert=%d update=%d aggregate=%d\n",
+			specific.Mix.Find, specific.Mix.Insert, specific.Mix.Update, specific.Mix.Aggregate)
+	}
+	fmt.Printf("Collection: %s (%d documents, key field %s, %s keys)\n",
+		specific.Collection, specific.Documents, specific.KeyField, specific.KeyDistribution)
+	writeConcern := specific.WriteConcern.W
+	if writeConcern == "" {
+		writeConcern = "server default"
+	}
+	if specific.WriteConcern.Journal {
+		writeConcern += ", journaled"
+	}
+	fmt.Printf("Read Preference: %s, Write Concern: %s\n", specific.ReadPreference, writeConcern)
+	fmt.Printf("Operations: %d, Concurrency: %d\n", config.BenchMark.Total, config.BenchMark.Parallels)
+
+	if err := h.runPerformanceTest(ctx, adapter, config, metricsCollector, opts); err != nil {
+		return fmt.Errorf("performance test failed: %w", err)
+	}
+
+	return h.generateReport(metricsCollector, opts)
+}
+
+// GetHelp 获取帮助信息
+func (h *MongoCommandHandler) GetHelp() string {
+	return `MongoDB Performance Testing
+
+USAGE:
+  abc-runner mongodb [options]
+
+DESCRIPTION:
+  Run find, insert, update and aggregate workloads against a MongoDB
+  collection and report latency per operation type along with
+  connection-pool checkout statistics.
+
+  Every document carries an integer key field. find, update and aggregate
+  pick keys from [0, --documents) using --key-distribution; inserts use
+  new keys starting at --documents. --setup seeds an empty collection with
+  keys 0..N-1 and indexes the key field.
+
+OPTIONS:
+  --help                     Show this help message
+  --uri URI                  Connection string (overrides host, port and credentials
+                             given in it, e.g. mongodb://user:pass@h1,h2/?replicaSet=rs0)
+  --host HOST, -h HOST       Server host (default: localhost)
+  --port PORT, -p PORT       Server port (default: 27017)
+  --username USER, -u USER   User name
+  --password PASSWORD        Password
+  --auth-source DB           Authentication database (default: admin)
+  --database NAME, -d NAME   Database name (default: test)
+  --replica-set NAME         Replica set name
+  --tls                      Connect with TLS
+  --pool-size N              Maximum pooled connections (default: same as -c)
+  --timeout DURATION         Connect, server selection and operation timeout (default: 5s)
+  --test-case CASE           find, insert, update, aggregate or mixed (default: find)
+  --mix SPEC                 Weights for mixed, e.g. find=70,insert=10,update=15,aggregate=5
+                             (default: the weights shown; unlisted types get 0)
+  --collection NAME          Test collection (default: abc_bench)
+  --documents N              Key space size (default: 10000)
+  --setup                    Seed the collection with N documents if it is empty
+  --key-field NAME           Field holding the integer key (default: key; _id is allowed)
+  --no-index                 Do not create an index on the key field during --setup
+  --key-distribution D       uniform (default), sequential, zipfian or gaussian
+  --zipf-skew S              Zipfian skew, between 0 and 1 (default: 0.99)
+  --gaussian-mean M          Hot spot position as a fraction of the key space (default: 0.5)
+  --gaussian-stddev S        Hot spot width as a fraction of the key space (default: 0.05)
+  --document TEMPLATE        Document template for inserts and --setup, as Extended
+                             JSON with payload placeholders such as {{name}},
+                             {{randInt 0 100}} or {{csv.COLUMN}}; the key field is added
+  --data-file FILE           CSV file for {{csv.COLUMN}}; the first row names the columns
+  --update TEMPLATE          Update document for update, e.g. '{"$inc": {"score": 1}}'
+  --pipeline JSON            Aggregation pipeline (Extended JSON array); {{key}} is
+                             replaced by the operation's key (default: match keys >= key,
+                             sort, limit 100 and average score)
+  --write-concern W          majority or the number of acknowledging members (0 = none)
+  --journal                  Require journaled writes
+  --read-preference MODE     primary (default), primaryPreferred, secondary,
+                             secondaryPreferred or nearest
+  -n COUNT                   Total operations (default: 1000)
+  -c COUNT                   Concurrent workers (default: 10)
+  --duration DURATION        Run for a fixed duration instead of -n
+
+NOTES:
+  A find that matches no document counts as a success with 0 documents;
+  update reports the number of matched documents. Document rendering is
+  not part of the measured latency. Operation latency includes waiting for
+  a pooled connection; a pool smaller than -c shows up in the pool wait
+  statistics.
+
+EXAMPLES:
+  abc-runner mongodb --help
+  abc-runner mongodb -h localhost --setup -n 10000 -c 20
+  abc-runner mongodb --uri mongodb://db1,db2,db3/?replicaSet=rs0 --test-case mixed \
+    --read-preference secondaryPreferred --write-concern majority --duration 60s
+  abc-runner mongodb -h db --test-case insert --journal \
+    --document '{"user": "{{name}}", "sku": "{{csv.sku}}", "qty": {{randInt 1 5}}}' --data-file skus.csv
+  abc-runner mongodb -h db --test-case find --key-distribution zipfian --no-index` + configLayersHelp + runOptionsHelp + "\n"
+}
+
+// parseArgs 解析命令行参数
+func (h *MongoCommandHandler) parseArgs(args []string) (*mongoConfig.MongoConfig, error) {
+	config := mongoConfig.NewDefaultMongoConfig()
+
+	// 依次应用--config文件、ABC_MONGODB_*环境变量与--set，专用参数随后覆盖
+	if err := applyConfigLayers("mongodb", args, config); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(args); i++ {
+		// 无参数的开关
+		switch args[i] {
+		case "--tls":
+			config.Connection.TLS = true
+			continue
+		case "--setup":
+			config.MongoSpecific.Setup = true
+			continue
+		case "--no-index":
+			config.MongoSpecific.Index = false
+			continue
+		case "--journal":
+			config.MongoSpecific.WriteConcern.Journal = true
+			continue
+		}
+
+		if i+1 >= len(args) {
+			break
+		}
+		value := args[i+1]
+		var err error
+
+		switch args[i] {
+		case "--uri":
+			config.Connection.URI = value
+		case "--host", "-h":
+			if !looksLikeHostname(value) {
+				continue
+			}
+			config.Connection.Address = value
+		case "--port", "-p":
+			config.Connection.Port, err = strconv.Atoi(value)
+		case "--username", "-u":
+			config.Connection.Username = value
+		case "--password":
+			config.Connection.Password = value
+		case "--auth-source":
+			config.Connection.AuthSource = value
+		case "--database", "-d":
+			config.Connection.Database = value
+		case "--replica-set":
+			config.Connection.ReplicaSet = value
+		case "--pool-size":
+			config.Connection.PoolSize, err = strconv.Atoi(value)
+		case "--timeout":
+			config.Connection.Timeout, err = time.ParseDuration(value)
+		case "--test-case":
+			config.BenchMark.TestCase = strings.ToLower(value)
+		case "--mix":
+			config.MongoSpecific.Mix, err = parseOperationMix(value)
+		case "--collection":
+			config.MongoSpecific.Collection = value
+		case "--documents":
+			config.MongoSpecific.Documents, err = strconv.Atoi(value)
+		case "--key-field":
+			config.MongoSpecific.KeyField = value
+		case "--key-distribution":
+			config.MongoSpecific.KeyDistribution.Type = strings.ToLower(value)
+		case "--zipf-skew":
+			config.MongoSpecific.KeyDistribution.Skew, err = strconv.ParseFloat(value, 64)
+		case "--gaussian-mean":
+			config.MongoSpecific.KeyDistribution.Mean, err = strconv.ParseFloat(value, 64)
+		case "--gaussian-stddev":
+			config.MongoSpecific.KeyDistribution.StdDev, err = strconv.ParseFloat(value, 64)
+		case "--document":
+			config.MongoSpecific.Document.Template = value
+		case "--data-file":
+			config.MongoSpecific.Document.DataFile = value
+		case "--update":
+			config.MongoSpecific.Update = value
+		case "--pipeline":
+			config.MongoSpecific.Pipeline = value
+		case "--write-concern":
+			config.MongoSpecific.WriteConcern.W = strings.ToLower(value)
+		case "--read-preference":
+			config.MongoSpecific.ReadPreference = value
+		case "-n":
+			config.BenchMark.Total, err = strconv.Atoi(value)
+		case "-c":
+			config.BenchMark.Parallels, err = strconv.Atoi(value)
+		case "--duration":
+			config.BenchMark.Duration, err = time.ParseDuration(value)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", args[i], err)
+		}
+		i++
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	// 各模板渲染一次，在连接前报告无法解析为Extended JSON的模板
+	builder, err := operations.NewDocumentBuilder(config)
+	if err != nil {
+		return nil, err
+	}
+	if err := builder.Check(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// parseOperationMix 解析"find=70,insert=10,update=15,aggregate=5"形式的权重，未列出的类型权重为0
+func parseOperationMix(value string) (mongoConfig.MixConfig, error) {
+	var mix mongoConfig.MixConfig
+	for _, part := range strings.Split(value, ",") {
+		name, weightStr, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return mix, fmt.Errorf("expected type=weight, got %q", part)
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(weightStr))
+		if err != nil {
+			return mix, err
+		}
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case mongoConfig.OperationFind:
+			mix.Find = weight
+		case mongoConfig.OperationInsert:
+			mix.Insert = weight
+		case mongoConfig.OperationUpdate:
+			mix.Update = weight
+		case mongoConfig.OperationAggregate:
+			mix.Aggregate = weight
+		default:
+			return mix, fmt.Errorf("unknown operation type %q", name)
+		}
+	}
+	return mix, nil
+}
+
+// runPerformanceTest 运行MongoDB性能测试
+func (h *MongoCommandHandler) runPerformanceTest(
+	ctx context.Context,
+	adapter interfaces.ProtocolAdapter,
+	config *mongoConfig.MongoConfig,
+	collector *metrics.BaseCollector[map[string]interface{}],
+	opts *runOptions,
+) error {
+	factory := operations.NewOperationFactory(config)
+	engine := execution.NewExecutionEngine(adapter, collector, factory)
+	engine.SetMaxWorkers(config.BenchMark.Parallels)
+	opts.applyToEngine(engine, collector)
+
+	testStartTime := time.Now()
+	result, err := engine.RunBenchmark(ctx, &config.BenchMark)
+	if err != nil {
+		return fmt.Errorf("benchmark execution failed: %w", err)
+	}
+	opts.finishRun()
+	actualTestDuration := time.Since(testStartTime)
+
+	fmt.Printf("\n📊 Execution Results:\n")
+	fmt.Printf("Total Jobs: %d\n", result.TotalJobs)
+	fmt.Printf("Completed Jobs: %d\n", result.CompletedJobs)
+	fmt.Printf("Success Jobs: %d\n", result.SuccessJobs)
+	fmt.Printf("Failed Jobs: %d\n", result.FailedJobs)
+	fmt.Printf("Actual Test Duration: %v\n", actualTestDuration)
+
+	specific := config.MongoSpecific
+	protocolMetrics := map[string]interface{}{
+		"protocol":         "mongodb",
+		"test_type":        "performance",
+		"test_case":        config.BenchMark.TestCase,
+		"target":           config.Connection.GetAddresses()[0],
+		"collection":       specific.Collection,
+		"key_distribution": specific.KeyDistribution.String(),
+		"read_preference":  specific.ReadPreference,
+		"write_concern":    specific.WriteConcern,
+		"actual_duration":  actualTestDuration,
+		"execution_result": result,
+	}
+	if config.BenchMark.TestCase == mongoConfig.TestCaseMixed {
+		protocolMetrics["mix"] = specific.Mix
+	}
+
+	if statsAdapter, ok := adapter.(interface {
+		GetOperationStats() []operations.OperationStats
+		GetPoolStats() *connection.PoolStats
+		ServerVersion() string
+	}); ok {
+		protocolMetrics["server_version"] = statsAdapter.ServerVersion()
+		if stats := statsAdapter.GetOperationStats(); len(stats) > 0 {
+			printMongoOperationStats(stats, actualTestDuration)
+			protocolMetrics["operation_stats"] = stats
+		}
+		if pool := statsAdapter.GetPoolStats(); pool != nil {
+			fmt.Printf("Pool: %d connection(s) created (max %d), %d checkout(s), avg/max wait %v/%v",
+				pool.Created, pool.MaxSize, pool.CheckedOut, pool.AvgWait, pool.MaxWait)
+			if pool.CheckOutFailed > 0 {
+				fmt.Printf(", %d failed checkout(s)", pool.CheckOutFailed)
+			}
+			if pool.Cleared > 0 {
+				fmt.Printf(", cleared %d time(s)", pool.Cleared)
+			}
+			fmt.Println()
+			protocolMetrics["pool_stats"] = *pool
+		}
+	}
+
+	collector.UpdateProtocolMetrics(protocolMetrics)
+	return nil
+}
+
+// printMongoOperationStats 打印按操作类型的统计表
+func printMongoOperationStats(stats []operations.OperationStats, duration time.Duration) {
+	fmt.Printf("\n📋 Per-Operation Statistics:\n")
+	fmt.Printf("  %-10s %10s %10s %8s %10s %10s %10s %10s\n",
+		"TYPE", "COUNT", "OPS", "ERR%", "DOCS", "P50", "P99", "MAX")
+	for _, s := range stats {
+		fmt.Printf("  %-10s %10d %10.1f %8.2f %10d %10v %10v %10v\n",
+			s.Type, s.Count, float64(s.Count)/duration.Seconds(), s.ErrorRate, s.Volume,
+			s.Latency.P50, s.Latency.P99, s.Latency.Max)
+		for code, n := range s.ErrorCodes {
+			fmt.Printf("  %-10s   %s: %d\n", "", code, n)
+		}
+	}
+}
+
+// generateReport 生成MongoDB测试报告
+func (h *MongoCommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
+	snapshot := collector.Snapshot()
+
+	// 使用实际测试时间修正吞吐量
+	if duration, ok := snapshot.Protocol["actual_duration"].(time.Duration); ok && duration > 0 {
+		snapshot.Core.Duration = duration
+		seconds := duration.Seconds()
+		snapshot.Core.Throughput.RPS = float64(snapshot.Core.Operations.Total) / seconds
+		snapshot.Core.Throughput.ReadRPS = float64(snapshot.Core.Operations.Read) / seconds
+		snapshot.Core.Throughput.WriteRPS = float64(snapshot.Core.Operations.Write) / seconds
+		snapshot.Core.Throughput.ReadMBps, snapshot.Core.Throughput.WriteMBps = metrics.Bandwidth(snapshot.Core.Operations, duration)
+	}
+
+	fmt.Printf("\n📊 MongoDB Performance Metrics:\n")
+	fmt.Printf("=====================================\n")
+	fmt.Printf("  Total Operations: %d\n", snapshot.Core.Operations.Total)
+	fmt.Printf("  Successful: %d\n", snapshot.Core.Operations.Success)
+	fmt.Printf("  Failed: %d\n", snapshot.Core.Operations.Failed)
+	fmt.Printf("  Success Rate: %.2f%%\n", snapshot.Core.Operations.Rate)
+	fmt.Printf("  Latency avg/p95/p99: %v/%v/%v\n",
+		snapshot.Core.Latency.Average, snapshot.Core.Latency.P95, snapshot.Core.Latency.P99)
+	fmt.Printf("  Throughput: %.2f ops/sec\n", snapshot.Core.Throughput.RPS)
+	fmt.Printf("=====================================\n")
+
+	// 存在快照接收器时（如分布式agent模式）交由接收器处理，不生成本地报告
+	if opts.publishSnapshot(snapshot) {
+		return nil
+	}
+
+	report := reporting.ConvertFromMetricsSnapshot(snapshot)
+	reportConfig := reporting.NewStandardReportConfig("mongodb")
+	opts.applyToReport(snapshot, report, reportConfig)
+	generator := reporting.NewReportGenerator(reportConfig)
+	if err := generator.Generate(report); err != nil {
+		return err
+	}
+	return opts.resultError(report)
+}
+
+// GetProtocolName 获取协议名称
+func (h *MongoCommandHandler) GetProtocolName() string {
+	return h.protocolName
+}
@@ -0,0 +1,329 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/reporting"
+)
+
+// 协议参数中引用目标的占位符
+const (
+	targetPlaceholder = "{target}" // 完整目标，如 redis-1:6379 或 http://api-1:8080
+	hostPlaceholder   = "{host}"   // 目标的主机部分
+	portPlaceholder   = "{port}"   // 目标的端口部分
+)
+
+// MultiTargetCommandHandler 多目标测试命令处理器
+// 对同一协议的多个实例（如Redis分片）同时运行同一场景，生成含各目标汇总与聚合指标的单个报告
+type MultiTargetCommandHandler struct {
+	executor func(ctx context.Context, command string, args []string) error
+}
+
+// NewMultiTargetCommandHandler 创建多目标测试命令处理器
+func NewMultiTargetCommandHandler() *MultiTargetCommandHandler {
+	return &MultiTargetCommandHandler{}
+}
+
+// SetCommandExecutor 设置本地命令执行器（由命令路由器注入）
+func (h *MultiTargetCommandHandler) SetCommandExecutor(executor func(ctx context.Context, command string, args []string) error) {
+	h.executor = executor
+}
+
+// targetRun 单个目标的运行结果
+type targetRun struct {
+	target   string
+	snapshot *metrics.DefaultMetricsSnapshot
+	err      error
+}
+
+// targetSink 只保留最终快照的接收器
+type targetSink struct {
+	mutex    sync.Mutex
+	snapshot *metrics.DefaultMetricsSnapshot
+}
+
+// OnProgress 忽略中间快照
+func (s *targetSink) OnProgress(snapshot *metrics.DefaultMetricsSnapshot) {}
+
+// OnFinal 保存最终快照
+func (s *targetSink) OnFinal(snapshot *metrics.DefaultMetricsSnapshot) {
+	s.mutex.Lock()
+	s.snapshot = snapshot
+	s.mutex.Unlock()
+}
+
+// Execute 执行多目标测试
+func (h *MultiTargetCommandHandler) Execute(ctx context.Context, args []string) error {
+	// 解析多目标参数，第一个非选项参数为协议命令，其后参数按目标展开后执行
+	var targets []string
+	command := ""
+	var commandArgs []string
+
+	for i := 0; i < len(args); i++ {
+		if command != "" {
+			commandArgs = append(commandArgs, args[i])
+			continue
+		}
+
+		switch args[i] {
+		case "--help", "-h", "help":
+			fmt.Println(h.GetHelp())
+			return nil
+		case "--targets":
+			if i+1 < len(args) {
+				for _, target := range strings.Split(args[i+1], ",") {
+					if target = strings.TrimSpace(target); target != "" {
+						targets = append(targets, target)
+					}
+				}
+				i++
+			}
+		default:
+			if strings.HasPrefix(args[i], "-") {
+				return NewConfigError(fmt.Errorf("unknown multi option: %s", args[i]))
+			}
+			command = args[i]
+		}
+	}
+
+	if h.executor == nil {
+		return fmt.Errorf("multi command executor not configured")
+	}
+	if len(targets) == 0 {
+		return NewConfigError(fmt.Errorf("at least one target is required (use --targets host1:6379,host2:6379)"))
+	}
+	if command == "" {
+		return NewConfigError(fmt.Errorf("protocol command is required (e.g. abc-runner multi --targets ... redis -h {host} -p {port})"))
+	}
+	if !referencesTarget(commandArgs) {
+		return NewConfigError(fmt.Errorf("protocol options must reference the target with %s, %s or %s",
+			targetPlaceholder, hostPlaceholder, portPlaceholder))
+	}
+
+	targetArgs := make([][]string, len(targets))
+	for i, target := range targets {
+		expanded, err := expandTargetArgs(commandArgs, target)
+		if err != nil {
+			return NewConfigError(err)
+		}
+		targetArgs[i] = expanded
+	}
+
+	// SLA等通用选项作用于聚合后的报告
+	opts, err := parseRunOptions(ctx, "", commandArgs)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+	if opts.checkpointPath != "" {
+		return NewConfigError(fmt.Errorf("--checkpoint and --resume are not supported with multi (each target would share one checkpoint)"))
+	}
+
+	fmt.Printf("🚀 Starting %s test on %d target(s): %s\n", command, len(targets), strings.Join(targets, ", "))
+	runs := h.runTargets(ctx, command, targets, targetArgs)
+
+	summaries := make([]metrics.TargetSummary, len(runs))
+	var snapshots []*metrics.DefaultMetricsSnapshot
+	var failed []string
+	for i, run := range runs {
+		summaries[i] = metrics.SummarizeTarget(run.target, run.snapshot, run.err)
+		if run.err != nil {
+			failed = append(failed, run.target)
+			continue
+		}
+		snapshots = append(snapshots, run.snapshot)
+		// 任一目标被中断时聚合报告同样标记为中断
+		if reason, ok := run.snapshot.Protocol["aborted"].(string); ok {
+			opts.aborted = reason
+		}
+		if condition, ok := run.snapshot.Protocol["stop_condition"].(string); ok {
+			opts.stopCondition = metrics.StopCondition(condition)
+		}
+	}
+	if len(snapshots) == 0 {
+		return NewConnectionError(fmt.Errorf("all targets failed: %s", runs[0].err))
+	}
+	metrics.CompareTargets(summaries)
+
+	// 各目标的最终快照携带延迟直方图，聚合分位数由合并后的直方图计算
+	merged := metrics.MergeSnapshots(snapshots...)
+	// 各目标在同一进程内运行，系统指标取最后一个快照而不是累加
+	merged.System = snapshots[len(snapshots)-1].System
+	merged.Protocol["protocol"] = command
+	merged.Protocol["test_type"] = "multi_target"
+	merged.Protocol["targets"] = summaries
+	if testCase, ok := snapshots[0].Protocol["test_case"]; ok {
+		merged.Protocol["test_case"] = testCase
+	}
+
+	h.printSummaries(summaries, merged)
+
+	report := reporting.ConvertFromMetricsSnapshot(merged)
+	report.Targets = summaries
+	reportConfig := reporting.NewStandardReportConfig(command + "_multi")
+	opts.applyToReport(merged, report, reportConfig)
+	generator := reporting.NewReportGenerator(reportConfig)
+	if err := generator.Generate(report); err != nil {
+		return err
+	}
+	if err := opts.resultError(report); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return NewConnectionError(fmt.Errorf("%d of %d target(s) failed: %s", len(failed), len(targets), strings.Join(failed, ", ")))
+	}
+	return nil
+}
+
+// runTargets 对各目标同时运行协议命令，各次运行的最终快照由接收器收集而不生成单独的报告
+func (h *MultiTargetCommandHandler) runTargets(ctx context.Context, command string, targets []string, targetArgs [][]string) []targetRun {
+	runs := make([]targetRun, len(targets))
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		go func(index int, target string) {
+			defer wg.Done()
+			sink := &targetSink{}
+			err := h.executor(metrics.WithSnapshotSink(ctx, sink), command, targetArgs[index])
+
+			sink.mutex.Lock()
+			snapshot := sink.snapshot
+			sink.mutex.Unlock()
+			if err == nil && snapshot == nil {
+				err = fmt.Errorf("finished without producing a metrics snapshot")
+			}
+			if err == nil {
+				// 目标不可达时部分协议以模拟数据运行，不计入聚合结果
+				if reason, ok := snapshot.Protocol["simulated"].(string); ok {
+					err = fmt.Errorf("target unreachable, results are simulated: %s", reason)
+				}
+			}
+			if err != nil {
+				fmt.Printf("❌ %s: %v\n", target, err)
+			}
+			runs[index] = targetRun{target: target, snapshot: snapshot, err: err}
+		}(i, target)
+	}
+	wg.Wait()
+	return runs
+}
+
+// printSummaries 输出各目标与聚合结果
+func (h *MultiTargetCommandHandler) printSummaries(summaries []metrics.TargetSummary, merged *metrics.DefaultMetricsSnapshot) {
+	fmt.Printf("\n🎯 Per-Target Results:\n")
+	fmt.Printf("   %-24s %10s %8s %12s %8s %12s %12s\n", "TARGET", "OPS", "SHARE", "RPS", "ERR%", "P50", "P99")
+	for _, summary := range summaries {
+		if summary.Error != "" {
+			fmt.Printf("❌ %-24s %s\n", summary.Target, summary.Error)
+			continue
+		}
+		marker := "  "
+		if summary.Hotspot {
+			marker = "🔥"
+		}
+		fmt.Printf("%s %-24s %10d %7.2f%% %12.2f %8.2f %12v %12v\n", marker, summary.Target,
+			summary.Operations, summary.Share, summary.RPS, summary.ErrorRate, summary.P50, summary.P99)
+		for _, reason := range summary.Reasons {
+			fmt.Printf("     ↳ %s\n", reason)
+		}
+	}
+
+	core := merged.Core
+	fmt.Printf("\n📊 Aggregate (%d target(s)):\n", merged.Protocol["merged_sources"])
+	fmt.Printf("=====================================\n")
+	fmt.Printf("  Total Operations: %d\n", core.Operations.Total)
+	fmt.Printf("  Successful: %d\n", core.Operations.Success)
+	fmt.Printf("  Failed: %d\n", core.Operations.Failed)
+	fmt.Printf("  Success Rate: %.2f%%\n", core.Operations.Rate)
+	fmt.Printf("  Latency avg/p95/p99: %v/%v/%v\n", core.Latency.Average, core.Latency.P95, core.Latency.P99)
+	fmt.Printf("  Throughput: %.2f ops/sec\n", core.Throughput.RPS)
+	fmt.Printf("=====================================\n")
+}
+
+// referencesTarget 协议参数中是否引用了目标占位符
+func referencesTarget(args []string) bool {
+	for _, arg := range args {
+		if strings.Contains(arg, targetPlaceholder) || strings.Contains(arg, hostPlaceholder) || strings.Contains(arg, portPlaceholder) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandTargetArgs 将协议参数中的占位符替换为目标
+// {host}与{port}取自host:port形式的目标，或URL形式目标的主机与端口
+func expandTargetArgs(args []string, target string) ([]string, error) {
+	var host, port string
+	var splitErr error
+	if u, err := url.Parse(target); err == nil && u.Scheme != "" && u.Host != "" {
+		host, port = u.Hostname(), u.Port()
+	} else {
+		host, port, splitErr = net.SplitHostPort(target)
+	}
+
+	expanded := make([]string, len(args))
+	for i, arg := range args {
+		if strings.Contains(arg, hostPlaceholder) || strings.Contains(arg, portPlaceholder) {
+			if splitErr != nil {
+				return nil, fmt.Errorf("target %s must be host:port or a URL to use %s or %s", target, hostPlaceholder, portPlaceholder)
+			}
+			if port == "" && strings.Contains(arg, portPlaceholder) {
+				return nil, fmt.Errorf("target %s has no port for %s", target, portPlaceholder)
+			}
+		}
+		arg = strings.ReplaceAll(arg, targetPlaceholder, target)
+		arg = strings.ReplaceAll(arg, hostPlaceholder, host)
+		expanded[i] = strings.ReplaceAll(arg, portPlaceholder, port)
+	}
+	return expanded, nil
+}
+
+// GetHelp 获取帮助信息
+func (h *MultiTargetCommandHandler) GetHelp() string {
+	return `Multi-Target Benchmark
+
+USAGE:
+  abc-runner multi --targets TARGET[,TARGET...] <protocol> [protocol options]
+
+DESCRIPTION:
+  Run the same scenario against several instances of one protocol at the
+  same time (e.g. the shards of a Redis cluster or the replicas behind a
+  load balancer) and produce a single report with a section per target plus
+  the aggregate, so a hot or degraded instance stands out.
+
+  Protocol options reference the current target with placeholders:
+    {target}   the target as given, e.g. redis-1:6379 or http://api-1:8080
+    {host}     its host part
+    {port}     its port part
+  -n, -c and all other protocol options apply to every target.
+
+OPTIONS:
+  --help, -h                   Show this help message
+  --targets TARGET[,TARGET...] Targets to run against, concurrently
+
+HOTSPOTS:
+  A target is flagged 🔥 when, compared with the median of the other
+  targets, its p99 latency or error rate (at least 1%) is 2x higher or its
+  throughput is 2x lower.
+
+EXAMPLES:
+  abc-runner multi --targets shard1:6379,shard2:6379,shard3:6379 redis -h {host} -p {port} -n 100000 -c 20
+  abc-runner multi --targets http://api-1:8080,http://api-2:8080 http --url {target}/health --duration 30s
+  abc-runner multi --targets pg-1:5432,pg-2:5432 postgres -h {host} -p {port} --sla-p99 20ms
+
+NOTES:
+  All targets run in this process; each run prints its own progress and the
+  report, SLA checks (--sla, --sla-*) and exit code cover the aggregate.
+  Aggregate latency percentiles are computed from the merged HDR latency
+  histograms of all targets. Options that write a file (--raw-samples,
+  --schedule-trace, --partial-report) are applied by every target's run;
+  use a placeholder in the file name to keep them apart, e.g.
+  --raw-samples raw-{host}-{port}.csv. The exit code reports an unreachable
+  target even when the others succeed.
+`
+}
@@ -0,0 +1,390 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	mysqlConfig "abc-runner/app/adapters/mysql/config"
+	"abc-runner/app/adapters/mysql/connection"
+	"abc-runner/app/adapters/mysql/operations"
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/reporting"
+)
+
+// MySQLCommandHandler MySQL命令处理器
+type MySQLCommandHandler struct {
+	protocolName string
+	factory      interfaces.MySQLAdapterFactory
+}
+
+// NewMySQLCommandHandler 创建MySQL命令处理器
+func NewMySQLCommandHandler(factory interfaces.MySQLAdapterFactory) *MySQLCommandHandler {
+	if factory == nil {
+		panic("mysqlAdapterFactory cannot be nil - dependency injection required")
+	}
+
+	return &MySQLCommandHandler{
+		protocolName: "mysql",
+		factory:      factory,
+	}
+}
+
+// Execute 执行MySQL命令
+func (h *MySQLCommandHandler) Execute(ctx context.Context, args []string) error {
+	// 检查帮助请求
+	for i, arg := range args {
+		if arg == "--help" || arg == "help" {
+			fmt.Println(h.GetHelp())
+			return nil
+		}
+		if arg == "-h" && (i == 0 || (i > 0 && args[i-1] != "mysql")) {
+			if i+1 < len(args) && !looksLikeHostname(args[i+1]) {
+				fmt.Println(h.GetHelp())
+				return nil
+			}
+		}
+	}
+
+	// 解析命令行参数
+	config, err := h.parseArgs(args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	// 解析通用运行选项
+	opts, err := parseRunOptions(ctx, "mysql", args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+	if printConfigRequested(args) {
+		return printEffectiveConfig("mysql", config, opts)
+	}
+
+	metricsCollector := metrics.NewBaseCollector(opts.collectorConfig(), map[string]interface{}{
+		"protocol":  "mysql",
+		"test_type": "performance",
+	})
+	defer metricsCollector.Stop()
+
+	adapter := h.factory.CreateMySQLAdapter()
+	if adapter == nil {
+		return fmt.Errorf("failed to create MySQL adapter")
+	}
+	defer adapter.Close()
+
+	target := config.Connection.GetAddresses()[0]
+	if err := adapter.Connect(ctx, config); err != nil {
+		return NewConnectionError(fmt.Errorf("failed to connect to mysql %s: %w", target, err))
+	}
+	if err := adapter.HealthCheck(ctx); err != nil {
+		return NewConnectionError(fmt.Errorf("mysql health check failed: %w", err))
+	}
+
+	schema := config.MySQLSpecific.Schema
+	if mysqlAdapter, ok := adapter.(interface {
+		ServerVersion() string
+		TableCreated() bool
+	}); ok {
+		fmt.Printf("✅ Connected to MySQL %s at %s (database: %s, pool: %d)\n",
+			mysqlAdapter.ServerVersion(), target, config.Connection.Database, config.GetPoolSize())
+		if mysqlAdapter.TableCreated() {
+			fmt.Printf("🛠️  Created table %s with %d rows\n", schema.Table, config.MySQLSpecific.Rows)
+		}
+	}
+
+	mode := "client-side interpolation"
+	if config.MySQLSpecific.Prepared {
+		mode = "prepared"
+	}
+
+	fmt.Printf("🚀 Starting MySQL performance test...\n")
+	fmt.Printf("Test Case: %s (%s)\n", config.BenchMark.TestCase, mode)
+	if config.BenchMark.TestCase == mysqlConfig.TestCaseMixed {
+		fmt.Printf("Mix: %d%% read, %d%% write\n", config.BenchMark.ReadPercent, 100-config.BenchMark.ReadPercent)
+	}
+	fmt.Printf("Table: %s (%s, %d payload column(s), %d bytes per row)\n",
+		schema.Table, schema.Engine, schema.PayloadColumns, schema.RowSize)
+	fmt.Printf("Operations: %d, Concurrency: %d\n", config.BenchMark.Total, config.BenchMark.Parallels)
+
+	if err := h.runPerformanceTest(ctx, adapter, config, metricsCollector, opts); err != nil {
+		return fmt.Errorf("performance test failed: %w", err)
+	}
+
+	return h.generateReport(metricsCollector, opts)
+}
+
+// GetHelp 获取帮助信息
+func (h *MySQLCommandHandler) GetHelp() string {
+	return `MySQL Query Performance Testing
+
+USAGE:
+  abc-runner mysql [options]
+
+DESCRIPTION:
+  Run primary-key reads, full-row updates and inserts against MySQL over a
+  database/sql connection pool and report latency per operation type along
+  with connection-pool wait statistics.
+
+  The test table has the form
+  (id BIGINT AUTO_INCREMENT PRIMARY KEY, k INT, c0 VARCHAR, c1 VARCHAR, ...)
+  where --row-size bytes of payload are split across --payload-columns
+  columns; --setup creates and fills it.
+
+OPTIONS:
+  --help                     Show this help message
+  --host HOST, -h HOST       Server host (default: localhost)
+  --port PORT, -P PORT       Server port (default: 3306)
+  --user USER, -u USER       User name (default: root)
+  --password PASSWORD        Password
+  --database NAME, -D NAME   Database name (default: test)
+  --tls MODE                 false, true, skip-verify or preferred (default: false)
+  --pool-size N              Maximum open connections (default: same as -c)
+  --max-idle N               Maximum idle connections (default: same as pool size)
+  --conn-max-lifetime DUR    Recycle connections older than DUR (default: never)
+  --timeout DURATION         Connect, read and write timeout (default: 5s)
+  --test-case CASE           read, write, insert or mixed (default: read)
+  --read-percent N           Percentage of reads for mixed, rest are writes (default: 80)
+  --prepared                 Use server-side prepared statements
+  --table NAME               Test table (default: abc_bench)
+  --engine NAME              Storage engine for --setup (default: InnoDB)
+  --payload-columns N        Number of VARCHAR payload columns (default: 1)
+  --row-size N               Payload bytes per row (default: 100)
+  --index-k                  Add a secondary index on k for --setup
+  --rows N                   ids are drawn uniformly from [1, N] (default: 10000)
+  --setup                    Create and fill the table if it does not exist
+  -n COUNT                   Total operations (default: 1000)
+  -c COUNT                   Concurrent workers (default: 10)
+  --duration DURATION        Run for a fixed duration instead of -n
+
+NOTES:
+  Without --prepared, parameters are interpolated on the client and each
+  operation is a single text-protocol round trip. With --prepared, each
+  statement is prepared once per connection and executed with the binary
+  protocol. Operation latency includes waiting for a pooled connection;
+  a pool smaller than -c shows up in the pool wait statistics.
+
+EXAMPLES:
+  abc-runner mysql --help
+  abc-runner mysql -h localhost -u root --password secret --setup -n 10000 -c 20
+  abc-runner mysql -h db --test-case mixed --read-percent 95 --prepared --duration 60s
+  abc-runner mysql -h db --setup --table wide --payload-columns 8 --row-size 2048 --test-case write
+  abc-runner mysql -h db --test-case insert --pool-size 8 -c 64` + configLayersHelp + runOptionsHelp + "\n"
+}
+
+// parseArgs 解析命令行参数
+func (h *MySQLCommandHandler) parseArgs(args []string) (*mysqlConfig.MySQLConfig, error) {
+	config := mysqlConfig.NewDefaultMySQLConfig()
+
+	// 依次应用--config文件、ABC_MYSQL_*环境变量与--set，专用参数随后覆盖
+	if err := applyConfigLayers("mysql", args, config); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(args); i++ {
+		// 无参数的开关
+		switch args[i] {
+		case "--prepared":
+			config.MySQLSpecific.Prepared = true
+			continue
+		case "--setup":
+			config.MySQLSpecific.Setup = true
+			continue
+		case "--index-k":
+			config.MySQLSpecific.Schema.IndexK = true
+			continue
+		}
+
+		if i+1 >= len(args) {
+			break
+		}
+		value := args[i+1]
+		var err error
+
+		switch args[i] {
+		case "--host", "-h":
+			if !looksLikeHostname(value) {
+				continue
+			}
+			config.Connection.Address = value
+		case "--port", "-P":
+			config.Connection.Port, err = strconv.Atoi(value)
+		case "--user", "-u":
+			config.Connection.User = value
+		case "--password":
+			config.Connection.Password = value
+		case "--database", "-D":
+			config.Connection.Database = value
+		case "--tls":
+			config.Connection.TLS = strings.ToLower(value)
+		case "--pool-size":
+			config.Connection.PoolSize, err = strconv.Atoi(value)
+		case "--max-idle":
+			config.Connection.MaxIdle, err = strconv.Atoi(value)
+		case "--conn-max-lifetime":
+			config.Connection.ConnMaxLifetime, err = time.ParseDuration(value)
+		case "--timeout":
+			config.Connection.Timeout, err = time.ParseDuration(value)
+		case "--test-case":
+			config.BenchMark.TestCase = strings.ToLower(value)
+		case "--read-percent":
+			config.BenchMark.ReadPercent, err = strconv.Atoi(value)
+		case "--table":
+			config.MySQLSpecific.Schema.Table = value
+		case "--engine":
+			config.MySQLSpecific.Schema.Engine = value
+		case "--payload-columns":
+			config.MySQLSpecific.Schema.PayloadColumns, err = strconv.Atoi(value)
+		case "--row-size":
+			config.MySQLSpecific.Schema.RowSize, err = strconv.Atoi(value)
+		case "--rows":
+			config.MySQLSpecific.Rows, err = strconv.Atoi(value)
+		case "-n":
+			config.BenchMark.Total, err = strconv.Atoi(value)
+		case "-c":
+			config.BenchMark.Parallels, err = strconv.Atoi(value)
+		case "--duration":
+			config.BenchMark.Duration, err = time.ParseDuration(value)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", args[i], err)
+		}
+		i++
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// runPerformanceTest 运行MySQL性能测试
+func (h *MySQLCommandHandler) runPerformanceTest(
+	ctx context.Context,
+	adapter interfaces.ProtocolAdapter,
+	config *mysqlConfig.MySQLConfig,
+	collector *metrics.BaseCollector[map[string]interface{}],
+	opts *runOptions,
+) error {
+	factory := operations.NewOperationFactory(config)
+	engine := execution.NewExecutionEngine(adapter, collector, factory)
+	engine.SetMaxWorkers(config.BenchMark.Parallels)
+	opts.applyToEngine(engine, collector)
+
+	testStartTime := time.Now()
+	result, err := engine.RunBenchmark(ctx, &config.BenchMark)
+	if err != nil {
+		return fmt.Errorf("benchmark execution failed: %w", err)
+	}
+	opts.finishRun()
+	actualTestDuration := time.Since(testStartTime)
+
+	fmt.Printf("\n📊 Execution Results:\n")
+	fmt.Printf("Total Jobs: %d\n", result.TotalJobs)
+	fmt.Printf("Completed Jobs: %d\n", result.CompletedJobs)
+	fmt.Printf("Success Jobs: %d\n", result.SuccessJobs)
+	fmt.Printf("Failed Jobs: %d\n", result.FailedJobs)
+	fmt.Printf("Actual Test Duration: %v\n", actualTestDuration)
+
+	protocolMetrics := map[string]interface{}{
+		"protocol":         "mysql",
+		"test_type":        "performance",
+		"test_case":        config.BenchMark.TestCase,
+		"target":           config.Connection.GetAddresses()[0],
+		"prepared":         config.MySQLSpecific.Prepared,
+		"read_percent":     config.BenchMark.GetReadPercent(),
+		"schema":           config.MySQLSpecific.Schema,
+		"actual_duration":  actualTestDuration,
+		"execution_result": result,
+	}
+
+	if statsAdapter, ok := adapter.(interface {
+		GetQueryStats() []operations.QueryStats
+		GetPoolStats() *connection.PoolStats
+		ServerVersion() string
+	}); ok {
+		protocolMetrics["server_version"] = statsAdapter.ServerVersion()
+		if stats := statsAdapter.GetQueryStats(); len(stats) > 0 {
+			printMySQLQueryStats(stats, actualTestDuration)
+			protocolMetrics["query_stats"] = stats
+		}
+		if pool := statsAdapter.GetPoolStats(); pool != nil {
+			fmt.Printf("Pool: %d/%d open, %d wait(s) totalling %v",
+				pool.Open, pool.MaxOpen, pool.WaitCount, pool.WaitDuration)
+			if closed := pool.MaxIdleClosed + pool.MaxLifetimeClosed; closed > 0 {
+				fmt.Printf(", %d connection(s) recycled", closed)
+			}
+			fmt.Println()
+			protocolMetrics["pool_stats"] = *pool
+		}
+	}
+
+	collector.UpdateProtocolMetrics(protocolMetrics)
+	return nil
+}
+
+// printMySQLQueryStats 打印按操作类型的统计表
+func printMySQLQueryStats(stats []operations.QueryStats, duration time.Duration) {
+	fmt.Printf("\n📋 Per-Operation Statistics:\n")
+	fmt.Printf("  %-8s %10s %10s %8s %10s %10s %10s %10s\n",
+		"TYPE", "COUNT", "QPS", "ERR%", "ROWS", "P50", "P99", "MAX")
+	for _, s := range stats {
+		fmt.Printf("  %-8s %10d %10.1f %8.2f %10d %10v %10v %10v\n",
+			s.Type, s.Count, float64(s.Count)/duration.Seconds(), s.ErrorRate, s.Volume,
+			s.Latency.P50, s.Latency.P99, s.Latency.Max)
+		for code, n := range s.ErrorCodes {
+			fmt.Printf("  %-8s   %s: %d\n", "", code, n)
+		}
+	}
+}
+
+// generateReport 生成MySQL测试报告
+func (h *MySQLCommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
+	snapshot := collector.Snapshot()
+
+	// 使用实际测试时间修正吞吐量
+	if duration, ok := snapshot.Protocol["actual_duration"].(time.Duration); ok && duration > 0 {
+		snapshot.Core.Duration = duration
+		seconds := duration.Seconds()
+		snapshot.Core.Throughput.RPS = float64(snapshot.Core.Operations.Total) / seconds
+		snapshot.Core.Throughput.ReadRPS = float64(snapshot.Core.Operations.Read) / seconds
+		snapshot.Core.Throughput.WriteRPS = float64(snapshot.Core.Operations.Write) / seconds
+		snapshot.Core.Throughput.ReadMBps, snapshot.Core.Throughput.WriteMBps = metrics.Bandwidth(snapshot.Core.Operations, duration)
+	}
+
+	fmt.Printf("\n📊 MySQL Performance Metrics:\n")
+	fmt.Printf("=====================================\n")
+	fmt.Printf("  Total Operations: %d\n", snapshot.Core.Operations.Total)
+	fmt.Printf("  Successful: %d\n", snapshot.Core.Operations.Success)
+	fmt.Printf("  Failed: %d\n", snapshot.Core.Operations.Failed)
+	fmt.Printf("  Success Rate: %.2f%%\n", snapshot.Core.Operations.Rate)
+	fmt.Printf("  Latency avg/p95/p99: %v/%v/%v\n",
+		snapshot.Core.Latency.Average, snapshot.Core.Latency.P95, snapshot.Core.Latency.P99)
+	fmt.Printf("  Throughput: %.2f ops/sec\n", snapshot.Core.Throughput.RPS)
+	fmt.Printf("=====================================\n")
+
+	// 存在快照接收器时（如分布式agent模式）交由接收器处理，不生成本地报告
+	if opts.publishSnapshot(snapshot) {
+		return nil
+	}
+
+	report := reporting.ConvertFromMetricsSnapshot(snapshot)
+	reportConfig := reporting.NewStandardReportConfig("mysql")
+	opts.applyToReport(snapshot, report, reportConfig)
+	generator := reporting.NewReportGenerator(reportConfig)
+	if err := generator.Generate(report); err != nil {
+		return err
+	}
+	return opts.resultError(report)
+}
+
+// GetProtocolName 获取协议名称
+func (h *MySQLCommandHandler) GetProtocolName() string {
+	return h.protocolName
+}
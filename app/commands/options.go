@@ -0,0 +1,1295 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/core/tui"
+	"abc-runner/app/reporting"
+
+	"golang.org/x/term"
+)
+
+// snapshotProgressInterval 向快照接收器推送中间快照的间隔
+const snapshotProgressInterval = time.Second
+
+// 执行引擎类型
+const (
+	engineShared  = "shared"
+	enginePerCore = "percore"
+)
+
+// runOptions 各协议命令共享的运行选项
+type runOptions struct {
+	// 调度追踪导出
+	scheduleTracePath   string
+	scheduleTraceFormat string
+
+	scheduleTracer *execution.ScheduleTracer
+
+	// 为每个操作生成请求ID并注入请求（HTTP请求头、gRPC metadata、Kafka消息头）
+	requestIDs      bool
+	requestIDPrefix string
+
+	// 为每个操作生成W3C trace-context并以traceparent注入请求，按采样率设置sampled标志；
+	// 设置traceOTLPEndpoint时将采样的客户端span以OTLP导出，与目标端的span同属一个trace
+	traceContext      bool
+	traceSampleRatio  float64
+	traceOTLPEndpoint string
+
+	// 原始样本导出（每个操作一行，经内存映射分段文件落盘并在后台压缩）
+	rawSamplesPath string
+	rawSamples     *metrics.SampleSpool
+
+	// 部分报告（运行期间周期性写入，结束时各间隔并入标准报告）
+	partialReportPath     string
+	partialReportInterval time.Duration
+	partialReporter       *metrics.PartialReporter
+	partialReport         *metrics.PartialReport
+
+	// 检查点：按间隔将累计快照与执行进度写入文件；--resume从检查点续跑，报告合并各运行段
+	checkpointPath     string
+	checkpointInterval time.Duration
+	checkpointWriter   *metrics.CheckpointWriter
+	resumeFrom         *metrics.Checkpoint
+	checkpoint         *metrics.Checkpoint // 运行结束时写入的最终检查点
+
+	// Prometheus /metrics 端点
+	metricsAddr     string
+	metricsExporter *metrics.PrometheusExporter
+
+	// 指标配置文件（OTLP导出、实时数据点输出等）
+	metricsConfig *metrics.MetricsConfig
+	otlpExporter  *metrics.OTLPExporter
+	liveExporter  *metrics.LiveExporter
+
+	// 运行历史中的标签
+	tags []string
+
+	// 控制台与HTML报告的语言
+	reportLocale string
+
+	// 运行结束后将最终结果推送到Pushgateway或remote-write端点，附带协议、目标、git SHA与运行ID标签；
+	// 运行ID同时用作运行历史的记录ID
+	pushGateway     string
+	pushRemoteWrite string
+	runID           string
+	gitSHA          string
+	finalSnapshot   *metrics.DefaultMetricsSnapshot // 报告所依据的最终快照（续跑时为合并后的快照）
+
+	// 操作拦截器：--intercept描述与嵌入调用方经context传入的拦截器，运行结束后关闭需要关闭的拦截器
+	interceptorSpecs []string
+	interceptors     []execution.Interceptor
+
+	// SLA规则（来自--sla系列选项与指标配置文件，设置时输出JUnit XML）
+	sla      []metrics.SLARule
+	cliRules []metrics.SLARule // 解析过程中收集的--sla系列规则
+
+	resumePath string // --resume指定的检查点文件
+
+	// 执行引擎：shared（共享工作池，默认）或percore（按核执行）及其核数
+	engine string
+	cores  int
+
+	// 测量开始前预填充的条目数（键、消息等），0表示不预填充
+	prefill int
+
+	// 测量开始前测量主机噪声基底的时长，0表示不测量；结果写入报告上下文
+	noiseFloorDuration time.Duration
+	noiseFloor         *metrics.NoiseFloor
+
+	// 实时终端面板：标准输出为终端且未交给快照接收器时默认开启，--no-tui关闭
+	noTUI         bool
+	withDashboard bool
+	dashboard     *tui.Dashboard
+
+	// 阶段汇总：每个间隔输出一行（累计操作数、滚动RPS与P99、错误数）到标准输出与日志文件，0表示关闭
+	interimInterval time.Duration
+	interimReporter *metrics.InterimReporter
+
+	// 熔断式停止条件：满足时中断运行并以失败退出码结束；
+	// 不经本命令的引擎运行时（如多目标聚合）由命令设置stopCondition
+	stopConditions metrics.StopConditions
+	breaker        *metrics.ErrorBreaker
+	stopCondition  metrics.StopCondition
+
+	// 执行基准测试的引擎，运行结束后据此判断运行是否被中断；
+	// 不经本命令的引擎运行时（如多目标聚合）由命令设置aborted
+	runEngine *execution.ExecutionEngine
+	aborted   string
+
+	// 连接失败后以模拟数据运行时的连接错误，运行结束后以连接失败退出码返回
+	simulated error
+
+	// 快照接收器与运行结果记录器（由调用方通过context注入）
+	ctx             context.Context
+	snapshotSink    metrics.SnapshotSink
+	histogramSource latencyHistogramSource // 导出延迟直方图的收集器，供接收方与合并报告精确合并分位数
+	resultRecorder  *reporting.ResultRecorder
+	stopProgress    chan struct{}
+	stopOnce        sync.Once
+}
+
+// parseRunOptions 解析共享运行选项：先应用协议配置run段的配置文件、环境变量与--set（见runConfig），再应用命令行参数
+// 协议特定的参数由各命令自行解析，这里只识别通用选项；protocol为空时（如多目标与分布式命令）只解析命令行参数
+func parseRunOptions(ctx context.Context, protocol string, args []string) (*runOptions, error) {
+	opts := newRunOptions(ctx)
+	if protocol != "" {
+		config, err := loadRunConfig(protocol, args)
+		if err != nil {
+			return nil, err
+		}
+		if err := opts.parseFlags(config.args()); err != nil {
+			return nil, fmt.Errorf("invalid run options in %s config: %w", protocol, err)
+		}
+	}
+	if err := opts.parseFlags(args); err != nil {
+		return nil, err
+	}
+	if err := opts.finish(); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
+// newRunOptions 创建带默认值的运行选项
+func newRunOptions(ctx context.Context) *runOptions {
+	opts := &runOptions{
+		scheduleTraceFormat: execution.TraceFormatChrome,
+		traceSampleRatio:    1,
+		interimInterval:     metrics.DefaultInterimInterval,
+		ctx:                 ctx,
+	}
+	if sink, ok := metrics.SnapshotSinkFromContext(ctx); ok {
+		opts.snapshotSink = sink
+	}
+	if recorder, ok := reporting.ResultRecorderFromContext(ctx); ok {
+		opts.resultRecorder = recorder
+	}
+	return opts
+}
+
+// parseFlags 解析参数中的通用选项，可多次调用，后解析的值覆盖之前的值，--tag等可重复的选项累加
+func (o *runOptions) parseFlags(args []string) error {
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--schedule-trace":
+			if i+1 >= len(args) {
+				return fmt.Errorf("missing value for --schedule-trace")
+			}
+			o.scheduleTracePath = args[i+1]
+			i++
+		case "--schedule-trace-format":
+			if i+1 >= len(args) {
+				return fmt.Errorf("missing value for --schedule-trace-format")
+			}
+			format := args[i+1]
+			if format != execution.TraceFormatChrome && format != execution.TraceFormatOTLP {
+				return fmt.Errorf("invalid --schedule-trace-format %q (expected chrome or otlp)", format)
+			}
+			o.scheduleTraceFormat = format
+			i++
+		case "--request-id":
+			o.requestIDs = true
+		case "--no-tui":
+			o.noTUI = true
+		case "--progress-interval":
+			if i+1 >= len(args) {
+				return fmt.Errorf("missing value for --progress-interval")
+			}
+			interval, err := time.ParseDuration(args[i+1])
+			if err != nil || interval < 0 {
+				return fmt.Errorf("invalid value for --progress-interval: %s", args[i+1])
+			}
+			o.interimInterval = interval
+			i++
+		case "--abort-error-rate":
+			if i+1 >= len(args) {
+				return fmt.Errorf("missing value for --abort-error-rate")
+			}
+			rate, window, err := metrics.ParseErrorRateCondition(args[i+1])
+			if err != nil {
+				return fmt.Errorf("invalid value for --abort-error-rate: %w", err)
+			}
+			o.stopConditions.ErrorRate = rate
+			o.stopConditions.ErrorRateWindow = window
+			i++
+		case "--abort-max-errors", "--abort-consecutive-errors":
+			if i+1 >= len(args) {
+				return fmt.Errorf("missing value for %s", args[i])
+			}
+			limit, err := strconv.ParseInt(args[i+1], 10, 64)
+			if err != nil || limit <= 0 {
+				return fmt.Errorf("invalid value for %s: %q (expected a positive integer)", args[i], args[i+1])
+			}
+			if args[i] == "--abort-max-errors" {
+				o.stopConditions.MaxErrors = limit
+			} else {
+				o.stopConditions.ConsecutiveErrors = limit
+			}
+			i++
+		case "--trace-context":
+			o.traceContext = true
+		case "--trace-sample":
+			if i+1 >= len(args) {
+				return fmt.Errorf("missing value for --trace-sample")
+			}
+			ratio, err := strconv.ParseFloat(args[i+1], 64)
+			if err != nil || ratio < 0 || ratio > 1 {
+				return fmt.Errorf("invalid value for --trace-sample: %q (expected a ratio between 0 and 1)", args[i+1])
+			}
+			o.traceSampleRatio = ratio
+			o.traceContext = true
+			i++
+		case "--trace-otlp":
+			if i+1 >= len(args) {
+				return fmt.Errorf("missing value for --trace-otlp")
+			}
+			o.traceOTLPEndpoint = args[i+1]
+			o.traceContext = true
+			i++
+		case "--raw-samples":
+			if i+1 >= len(args) {
+				return fmt.Errorf("missing value for --raw-samples")
+			}
+			o.rawSamplesPath = args[i+1]
+			i++
+		case "--partial-report":
+			if i+1 >= len(args) {
+				return fmt.Errorf("missing value for --partial-report")
+			}
+			o.partialReportPath = args[i+1]
+			i++
+		case "--partial-interval":
+			if i+1 >= len(args) {
+				return fmt.Errorf("missing value for --partial-interval")
+			}
+			interval, err := time.ParseDuration(args[i+1])
+			if err != nil || interval <= 0 {
+				return fmt.Errorf("invalid value for --partial-interval: %q (expected a positive duration)", args[i+1])
+			}
+			o.partialReportInterval = interval
+			i++
+		case "--checkpoint":
+			if i+1 >= len(args) {
+				return fmt.Errorf("missing value for --checkpoint")
+			}
+			o.checkpointPath = args[i+1]
+			i++
+		case "--checkpoint-interval":
+			if i+1 >= len(args) {
+				return fmt.Errorf("missing value for --checkpoint-interval")
+			}
+			interval, err := time.ParseDuration(args[i+1])
+			if err != nil || interval <= 0 {
+				return fmt.Errorf("invalid value for --checkpoint-interval: %q (expected a positive duration)", args[i+1])
+			}
+			o.checkpointInterval = interval
+			i++
+		case "--resume":
+			if i+1 >= len(args) {
+				return fmt.Errorf("missing value for --resume")
+			}
+			checkpoint, err := metrics.LoadCheckpoint(args[i+1])
+			if err != nil {
+				return err
+			}
+			if checkpoint.Complete {
+				return fmt.Errorf("checkpoint %s is from a run that already completed", args[i+1])
+			}
+			o.resumeFrom = checkpoint
+			o.resumePath = args[i+1]
+			i++
+		case "--metrics-addr":
+			if i+1 >= len(args) {
+				return fmt.Errorf("missing value for --metrics-addr")
+			}
+			o.metricsAddr = args[i+1]
+			i++
+		case "--metrics-config":
+			if i+1 >= len(args) {
+				return fmt.Errorf("missing value for --metrics-config")
+			}
+			config, err := loadMetricsConfig(args[i+1])
+			if err != nil {
+				return err
+			}
+			o.metricsConfig = config
+			i++
+		case "--prefill":
+			if i+1 >= len(args) {
+				return fmt.Errorf("missing value for --prefill")
+			}
+			count, err := strconv.Atoi(args[i+1])
+			if err != nil || count <= 0 {
+				return fmt.Errorf("invalid value for --prefill: %q (expected a positive integer)", args[i+1])
+			}
+			o.prefill = count
+			i++
+		case "--noise-floor":
+			if i+1 >= len(args) {
+				return fmt.Errorf("missing value for --noise-floor")
+			}
+			duration, err := time.ParseDuration(args[i+1])
+			if err != nil || duration <= 0 {
+				return fmt.Errorf("invalid value for --noise-floor: %q (expected a positive duration)", args[i+1])
+			}
+			o.noiseFloorDuration = duration
+			i++
+		case "--engine":
+			if i+1 >= len(args) {
+				return fmt.Errorf("missing value for --engine")
+			}
+			engine := args[i+1]
+			if engine != engineShared && engine != enginePerCore {
+				return fmt.Errorf("invalid --engine %q (expected %s or %s)", engine, engineShared, enginePerCore)
+			}
+			o.engine = engine
+			i++
+		case "--cores":
+			if i+1 >= len(args) {
+				return fmt.Errorf("missing value for --cores")
+			}
+			cores, err := strconv.Atoi(args[i+1])
+			if err != nil || cores <= 0 {
+				return fmt.Errorf("invalid value for --cores: %q (expected a positive integer)", args[i+1])
+			}
+			o.cores = cores
+			i++
+		case "--push-gateway", "--push-remote-write", "--run-id", "--git-sha":
+			if i+1 >= len(args) || args[i+1] == "" {
+				return fmt.Errorf("missing value for %s", args[i])
+			}
+			switch args[i] {
+			case "--push-gateway":
+				o.pushGateway = args[i+1]
+			case "--push-remote-write":
+				o.pushRemoteWrite = args[i+1]
+			case "--run-id":
+				if !reporting.ValidRunID(args[i+1]) {
+					return fmt.Errorf("invalid --run-id %q (letters, digits, '.', '_' and '-' only)", args[i+1])
+				}
+				o.runID = args[i+1]
+			case "--git-sha":
+				o.gitSHA = args[i+1]
+			}
+			i++
+		case "--tag":
+			if i+1 >= len(args) {
+				return fmt.Errorf("missing value for --tag")
+			}
+			o.tags = append(o.tags, args[i+1])
+			i++
+		case "--report-locale":
+			if i+1 >= len(args) {
+				return fmt.Errorf("missing value for --report-locale")
+			}
+			locale, err := reporting.NormalizeLocale(args[i+1])
+			if err != nil {
+				return fmt.Errorf("invalid value for --report-locale: %w", err)
+			}
+			o.reportLocale = locale
+			i++
+		case "--intercept":
+			if i+1 >= len(args) {
+				return fmt.Errorf("missing value for --intercept")
+			}
+			// 请求日志在运行开始时才创建文件，其余描述在此校验
+			spec := args[i+1]
+			if !strings.HasPrefix(spec, "log:") {
+				if _, err := execution.ParseInterceptor(spec); err != nil {
+					return fmt.Errorf("invalid value for --intercept: %w", err)
+				}
+			}
+			o.interceptorSpecs = append(o.interceptorSpecs, spec)
+			i++
+		case "--sla", "--sla-p99", "--sla-min-rps", "--sla-max-error-rate":
+			if i+1 >= len(args) {
+				return fmt.Errorf("missing value for %s", args[i])
+			}
+			rule, err := metrics.ParseSLARule(slaExpression(args[i], args[i+1]))
+			if err != nil {
+				return fmt.Errorf("invalid value for %s: %w", args[i], err)
+			}
+			o.cliRules = append(o.cliRules, rule)
+			i++
+		}
+	}
+	return nil
+}
+
+// finish 合并SLA规则并校验选项间的依赖，在全部参数解析完成后调用
+func (o *runOptions) finish() error {
+	// 配置文件中的规则在前，命令行规则在后
+	if o.metricsConfig != nil {
+		rules, err := metrics.ParseSLARules(o.metricsConfig.SLA)
+		if err != nil {
+			return err
+		}
+		o.sla = rules
+	}
+	o.sla = append(o.sla, o.cliRules...)
+
+	// --trace-otlp启用OTLP导出中的span，与--metrics-config中的otlp导出设置合并
+	if o.traceOTLPEndpoint != "" {
+		if o.metricsConfig == nil {
+			o.metricsConfig = metrics.DefaultMetricsConfig()
+		}
+		otlp := &o.metricsConfig.Export.OTLP
+		otlp.Enabled = true
+		otlp.Endpoint = o.traceOTLPEndpoint
+		otlp.Traces = true
+	}
+
+	if o.partialReportInterval > 0 && o.partialReportPath == "" {
+		return fmt.Errorf("--partial-interval requires --partial-report")
+	}
+	// 续跑时默认继续更新同一个检查点
+	if o.resumeFrom != nil && o.checkpointPath == "" {
+		o.checkpointPath = o.resumePath
+	}
+	if o.checkpointInterval > 0 && o.checkpointPath == "" {
+		return fmt.Errorf("--checkpoint-interval requires --checkpoint")
+	}
+	if o.cores > 0 {
+		if o.engine == engineShared {
+			return fmt.Errorf("--cores requires --engine %s", enginePerCore)
+		}
+		o.engine = enginePerCore
+	}
+	if o.engine == enginePerCore && o.cores == 0 {
+		o.cores = execution.DefaultCores()
+	}
+	if o.resultRecorder != nil && len(o.tags) > 0 {
+		o.resultRecorder.SetTags(o.tags)
+	}
+	o.prepareResultPush()
+	return nil
+}
+
+// slaExpression 将SLA选项转换为规则表达式，--sla-*简写等价于对应的 <= 或 >= 规则
+func slaExpression(flag, value string) string {
+	switch flag {
+	case "--sla-p99":
+		return "p99 <= " + value
+	case "--sla-min-rps":
+		return "rps >= " + value
+	case "--sla-max-error-rate":
+		return "error_rate <= " + value
+	}
+	return value
+}
+
+// runPrefill 在测量开始前执行预填充阶段，应在applyToEngine之前调用
+// 预填充操作不计入指标；部分执行器自行记录指标，因此完成后重置收集器，使测量从零开始
+func (o *runOptions) runPrefill(ctx context.Context, engine *execution.ExecutionEngine, collector interfaces.DefaultMetricsCollector, parallels int) error {
+	if o == nil || o.prefill <= 0 {
+		return nil
+	}
+
+	fmt.Printf("📦 Prefilling %d items before measurement...\n", o.prefill)
+	result, err := engine.RunPrepare(ctx, o.prefill, parallels)
+	if err != nil {
+		return fmt.Errorf("prefill failed: %w", err)
+	}
+	if result.Success == 0 {
+		return fmt.Errorf("prefill failed: all %d operations failed: %w", result.Failed, result.FirstError)
+	}
+	collector.Reset()
+
+	fmt.Printf("   Prefilled %d of %d items in %v (%.0f items/sec), excluded from metrics\n",
+		result.Success, result.Total, result.Duration.Round(time.Millisecond), float64(result.Success)/result.Duration.Seconds())
+	if result.Failed > 0 {
+		fmt.Printf("   ⚠️  %d prefill operations failed, first error: %v\n", result.Failed, result.FirstError)
+	}
+	return nil
+}
+
+// applyToEngine 将运行选项应用到执行引擎
+func (o *runOptions) applyToEngine(engine *execution.ExecutionEngine, collector interfaces.DefaultMetricsCollector) {
+	if o == nil {
+		return
+	}
+	o.runEngine = engine
+	if o.resumeFrom != nil {
+		engine.SetResume(o.resumeFrom.Jobs, o.resumeFrom.Elapsed)
+	}
+	if o.scheduleTracePath != "" {
+		o.scheduleTracer = execution.NewScheduleTracer(0)
+		engine.SetScheduleTracer(o.scheduleTracer)
+	}
+	if o.requestIDs {
+		generator := execution.NewRequestIDGenerator()
+		engine.SetRequestIDs(generator)
+		o.requestIDPrefix = generator.Prefix()
+		fmt.Printf("🔖 Request IDs: %s-<n> (HTTP %s header, gRPC and Kafka %s)\n",
+			o.requestIDPrefix, execution.RequestIDHeader, execution.RequestIDMetadata)
+	}
+	if o.traceContext {
+		engine.SetTraceContexts(execution.NewTraceContextGenerator(o.traceSampleRatio))
+		fmt.Printf("🧵 W3C trace context: %s header/metadata on every operation, %.0f%% sampled\n",
+			execution.TraceparentHeader, o.traceSampleRatio*100)
+	}
+	o.applyInterceptors(engine)
+	if o.engine == enginePerCore {
+		engine.SetCores(o.cores)
+		fmt.Printf("⚙️  Thread-per-core engine: %d cores, one pinned thread per core with its own connections and metrics shard\n", o.cores)
+	}
+	o.withDashboard = o.tuiEnabled()
+	o.applyToCollector(collector)
+	if o.withDashboard {
+		o.startDashboard(engine, collector)
+	}
+}
+
+// tuiEnabled 是否显示实时终端面板：CI、重定向输出与嵌入调用时保持纯文本输出
+func (o *runOptions) tuiEnabled() bool {
+	return !o.noTUI && o.snapshotSink == nil && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// startDashboard 开始刷新实时终端面板
+func (o *runOptions) startDashboard(engine *execution.ExecutionEngine, collector interfaces.DefaultMetricsCollector) {
+	source := tui.Source{
+		Snapshot: collector.Snapshot,
+		Progress: engine.Progress,
+	}
+	if histogram, ok := collector.(latencyHistogramSource); ok {
+		source.Histogram = histogram.LatencyHistogram
+	}
+	o.dashboard = tui.New(os.Stdout, source)
+	o.dashboard.Start()
+}
+
+// applyInterceptors 为执行引擎配置拦截器：context中的拦截器位于最外层，其后按--intercept的顺序
+func (o *runOptions) applyInterceptors(engine *execution.ExecutionEngine) {
+	interceptors := execution.InterceptorsFromContext(o.ctx)
+	for _, spec := range o.interceptorSpecs {
+		interceptor, err := execution.ParseInterceptor(spec)
+		if err != nil {
+			fmt.Printf("⚠️  Interceptor %s disabled: %v\n", spec, err)
+			continue
+		}
+		interceptors = append(interceptors, interceptor)
+		o.interceptors = append(o.interceptors, interceptor)
+	}
+	if len(interceptors) == 0 {
+		return
+	}
+	engine.AddInterceptors(interceptors...)
+	if len(o.interceptorSpecs) > 0 {
+		fmt.Printf("🧩 Interceptors: %s\n", strings.Join(o.interceptorSpecs, ", "))
+	}
+}
+
+// closeInterceptors 关闭--intercept创建的拦截器（如写完请求日志）
+func (o *runOptions) closeInterceptors() {
+	for _, interceptor := range o.interceptors {
+		closer, ok := interceptor.(io.Closer)
+		if !ok {
+			continue
+		}
+		if err := closer.Close(); err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+			continue
+		}
+		if logger, ok := interceptor.(*execution.RequestLogger); ok {
+			fmt.Printf("✅ Request log saved to: %s (%d operations)\n", logger.Path(), logger.Count())
+		}
+	}
+	o.interceptors = nil
+}
+
+// applyToCollector 将与执行引擎无关的运行选项应用到指标收集器
+func (o *runOptions) applyToCollector(collector interfaces.DefaultMetricsCollector) {
+	if o == nil {
+		return
+	}
+	if o.noiseFloorDuration > 0 {
+		o.measureNoiseFloor()
+	}
+	if source, ok := collector.(latencyHistogramSource); ok {
+		o.histogramSource = source
+	}
+	if o.snapshotSink != nil {
+		o.startProgress(collector)
+	}
+	// 交给快照接收器时由接收方获取中间快照，不输出阶段汇总
+	if o.interimInterval > 0 && o.snapshotSink == nil {
+		o.startInterim(collector)
+	}
+	if o.stopConditions.Enabled() {
+		o.startBreaker(collector)
+	}
+	if o.checkpointPath != "" {
+		o.startCheckpoint(collector)
+	}
+	if o.rawSamplesPath != "" {
+		o.startRawSamples(collector)
+	}
+	if o.partialReportPath != "" {
+		o.startPartialReport(collector)
+	}
+	if o.metricsAddr != "" {
+		o.startMetricsEndpoint(collector)
+	}
+	if o.metricsConfig != nil && o.metricsConfig.Export.OTLP.Enabled {
+		o.startOTLPExport(collector)
+	}
+	if o.metricsConfig != nil && len(o.metricsConfig.Export.Live.Sinks) > 0 {
+		o.startLiveExport(collector)
+	}
+}
+
+// measureNoiseFloor 在测量开始前空载运行，记录主机调度与计时噪声；失败时只输出警告
+func (o *runOptions) measureNoiseFloor() {
+	ctx := o.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	fmt.Printf("🔇 Measuring host noise floor for %v...\n", o.noiseFloorDuration)
+	floor, err := metrics.MeasureNoiseFloor(ctx, o.noiseFloorDuration)
+	if err != nil {
+		fmt.Printf("⚠️  Noise floor measurement failed: %v\n", err)
+		return
+	}
+	o.noiseFloor = floor
+	fmt.Printf("   Noise floor: %v (wakeup p50/p99 %v/%v, timer p99 %v, sleep overshoot p50 %v, loopback p50 %v)\n",
+		floor.Floor, floor.Wakeup.P50, floor.Wakeup.P99, floor.Timer.P99, floor.SleepOvershoot.P50, floor.Loopback.P50)
+}
+
+// collectorConfig 返回创建指标收集器使用的配置，未指定--metrics-config时使用默认配置
+func (o *runOptions) collectorConfig() *metrics.MetricsConfig {
+	if o == nil || o.metricsConfig == nil {
+		return metrics.DefaultMetricsConfig()
+	}
+	return o.metricsConfig
+}
+
+// applyToReport 将运行选项的结果写入最终报告：并入部分报告的各间隔，
+// 根据最终快照判定SLA规则，设置了规则时额外输出JUnit XML
+func (o *runOptions) applyToReport(snapshot *metrics.DefaultMetricsSnapshot, report *reporting.StructuredReport, config *reporting.RenderConfig) {
+	if o == nil {
+		return
+	}
+	if o.resultRecorder != nil {
+		config.OnFileWritten = o.resultRecorder.AddReportFile
+	}
+	config.Locale = o.reportLocale
+	// 续跑时报告覆盖检查点中的全部运行段
+	if merged := o.resumedSnapshot(snapshot); merged != snapshot {
+		*report = *reporting.ConvertFromMetricsSnapshot(merged)
+		snapshot = merged
+		fmt.Printf("♻️  Report covers %d segment(s): %d operations over %v\n",
+			o.checkpoint.Segments, merged.Core.Operations.Total, merged.Core.Duration.Round(time.Second))
+	} else if report.Metrics.LatencyAnalysis.Histogram == nil {
+		// 续跑合并后的快照已带有全部运行段的直方图，只为单段运行附加收集器的直方图
+		o.attachHistogram(snapshot)
+		report.Metrics.LatencyAnalysis.Histogram = snapshot.LatencyHistogram
+	}
+	if o.partialReport != nil {
+		report.Intervals = o.partialReport.Intervals
+	}
+	o.finalSnapshot = snapshot
+	report.Context.NoiseFloor = o.noiseFloor
+	report.Context.RequestIDPrefix = o.requestIDPrefix
+	report.Context.Aborted = o.abortReason()
+	if len(o.sla) == 0 {
+		return
+	}
+	report.SLA = metrics.EvaluateSLA(snapshot.Core, o.sla)
+	config.OutputFormats = append(config.OutputFormats, "junit")
+}
+
+// markSimulated 记录目标不可达、改为生成模拟数据的原因
+func (o *runOptions) markSimulated(err error) {
+	if o != nil {
+		o.simulated = err
+	}
+}
+
+// abortReason 运行被中断时的原因，未中断或未经执行引擎运行时为空
+func (o *runOptions) abortReason() string {
+	if o.aborted != "" || o.runEngine == nil {
+		return o.aborted
+	}
+	return o.runEngine.AbortReason()
+}
+
+// resultError 将最终报告交给运行结果记录器并推送最终结果，返回决定退出码的错误，应在报告生成后调用
+func (o *runOptions) resultError(report *reporting.StructuredReport) error {
+	if o == nil {
+		return nil
+	}
+	if o.resultRecorder != nil {
+		o.resultRecorder.Record(report)
+	}
+	err := o.exitError(report)
+	o.pushResults(err == nil)
+	return err
+}
+
+// exitError 决定退出码的错误：以模拟数据运行时返回连接失败，停止条件触发时返回阈值未满足（连续失败时为连接失败），
+// 运行被中断时返回中断，存在未通过的SLA断言时返回阈值未满足
+func (o *runOptions) exitError(report *reporting.StructuredReport) error {
+	if o.simulated != nil {
+		return NewConnectionError(fmt.Errorf("target unreachable, results are simulated: %w", o.simulated))
+	}
+	if condition := o.tripped(); condition != "" {
+		// 连续失败通常意味着目标已不可达，其余条件视为阈值未满足
+		code := ExitCodeThreshold
+		if condition == metrics.StopOnConsecutiveErrors {
+			code = ExitCodeConnection
+		}
+		return &ExitError{
+			Code: code,
+			Err:  fmt.Errorf("run stopped early (%s) after %d operations, the report covers the data collected so far", o.abortReason(), report.Metrics.CoreOperations.TotalOperations),
+		}
+	}
+	if reason := o.abortReason(); reason != "" {
+		return &ExitError{
+			Code: ExitCodeAborted,
+			Err:  fmt.Errorf("run aborted (%s) after %d operations, the report covers the data collected so far", reason, report.Metrics.CoreOperations.TotalOperations),
+		}
+	}
+
+	failures := metrics.SLAFailures(report.SLA)
+	if failures == 0 {
+		return nil
+	}
+
+	var violated []string
+	for _, assertion := range report.SLA {
+		if !assertion.Passed {
+			violated = append(violated, assertion.Name)
+		}
+	}
+	return &ExitError{
+		Code: ExitCodeThreshold,
+		Err:  fmt.Errorf("SLA violated: %d of %d assertion(s) failed: %s", failures, len(report.SLA), strings.Join(violated, "; ")),
+	}
+}
+
+// loadMetricsConfig 加载指标配置文件
+func loadMetricsConfig(path string) (*metrics.MetricsConfig, error) {
+	// ConfigManager在文件不存在时会写出默认配置，这里要求文件必须存在
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("metrics config %s not found: %w", path, err)
+	}
+	manager := metrics.NewConfigManager(path)
+	if err := manager.LoadConfig(); err != nil {
+		return nil, fmt.Errorf("failed to load metrics config %s: %w", path, err)
+	}
+	return manager.GetConfig(), nil
+}
+
+// startOTLPExport 启动OTLP指标与span导出
+func (o *runOptions) startOTLPExport(collector interfaces.DefaultMetricsCollector) {
+	config := o.metricsConfig.Export.OTLP
+	exporter := metrics.NewOTLPExporter(config, collector)
+	exporter.SetTraceContextOnly(o.traceContext)
+	if config.Traces {
+		if observable, ok := collector.(interface{ AddObserver(metrics.ResultObserver) }); ok {
+			observable.AddObserver(exporter)
+		}
+	}
+
+	exporter.Start()
+	o.otlpExporter = exporter
+	fmt.Printf("📡 Exporting OTLP metrics to %s every %v (traces: %v)\n", config.Endpoint, config.Interval, config.Traces)
+}
+
+// startLiveExport 启动实时数据点输出（InfluxDB、StatsD），创建失败时只输出警告
+func (o *runOptions) startLiveExport(collector interfaces.DefaultMetricsCollector) {
+	observable, ok := collector.(interface{ AddObserver(metrics.ResultObserver) })
+	if !ok {
+		return
+	}
+	protocol := ""
+	if snapshot := collector.Snapshot(); snapshot != nil {
+		protocol, _ = snapshot.Protocol["protocol"].(string)
+	}
+	config := o.metricsConfig.Export.Live
+	exporter, err := metrics.NewLiveExporter(config, protocol)
+	if err != nil {
+		fmt.Printf("⚠️  Live export disabled: %v\n", err)
+		return
+	}
+	exporter.Start()
+	observable.AddObserver(exporter)
+	o.liveExporter = exporter
+	fmt.Printf("📡 Sending a datapoint every %v to %s (flushed every %v)\n",
+		config.Interval, strings.Join(exporter.Sinks(), ", "), config.FlushInterval)
+}
+
+// startMetricsEndpoint 启动Prometheus /metrics 端点
+func (o *runOptions) startMetricsEndpoint(collector interfaces.DefaultMetricsCollector) {
+	exporter := metrics.NewPrometheusExporter(collector)
+	if observable, ok := collector.(interface{ AddObserver(metrics.ResultObserver) }); ok {
+		observable.AddObserver(exporter)
+	}
+
+	if err := exporter.Start(o.metricsAddr); err != nil {
+		fmt.Printf("⚠️  Failed to start metrics endpoint: %v\n", err)
+		return
+	}
+	o.metricsExporter = exporter
+	fmt.Printf("📈 Prometheus metrics available at http://%s/metrics\n", exporter.Addr())
+}
+
+// startProgress 周期性向快照接收器推送中间快照
+func (o *runOptions) startProgress(collector interfaces.DefaultMetricsCollector) {
+	o.stopProgress = make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(snapshotProgressInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if snapshot := collector.Snapshot(); snapshot != nil {
+					o.attachHistogram(snapshot)
+					o.snapshotSink.OnProgress(snapshot)
+				}
+			case <-o.stopProgress:
+				return
+			case <-o.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// stopProgressLoop 停止中间快照推送
+func (o *runOptions) stopProgressLoop() {
+	if o.stopProgress == nil {
+		return
+	}
+	o.stopOnce.Do(func() { close(o.stopProgress) })
+}
+
+// publishSnapshot 将最终快照交给快照接收器，返回是否已被接收器处理
+func (o *runOptions) publishSnapshot(snapshot *metrics.DefaultMetricsSnapshot) bool {
+	if o == nil || o.snapshotSink == nil {
+		return false
+	}
+	o.stopProgressLoop()
+	snapshot = o.resumedSnapshot(snapshot)
+	// 接收器不经过resultError，以协议指标标记模拟数据，由接收方决定如何处理
+	if o.simulated != nil {
+		if snapshot.Protocol == nil {
+			snapshot.Protocol = make(map[string]interface{})
+		}
+		snapshot.Protocol["simulated"] = o.simulated.Error()
+	}
+	if reason := o.abortReason(); reason != "" {
+		if snapshot.Protocol == nil {
+			snapshot.Protocol = make(map[string]interface{})
+		}
+		snapshot.Protocol["aborted"] = reason
+		if condition := o.tripped(); condition != "" {
+			snapshot.Protocol["stop_condition"] = string(condition)
+		}
+	}
+	o.attachHistogram(snapshot)
+	o.snapshotSink.OnFinal(snapshot)
+	return true
+}
+
+// latencyHistogramSource 可导出延迟直方图的收集器
+type latencyHistogramSource interface {
+	LatencyHistogram() *metrics.LatencyHistogram
+}
+
+// attachHistogram 为交给接收器或写入报告的快照附加延迟直方图，供接收方精确合并分位数
+func (o *runOptions) attachHistogram(snapshot *metrics.DefaultMetricsSnapshot) {
+	if o.histogramSource != nil && snapshot.LatencyHistogram == nil {
+		snapshot.LatencyHistogram = o.histogramSource.LatencyHistogram()
+	}
+}
+
+// finishRun 基准测试结束后处理运行选项（导出调度追踪等）
+func (o *runOptions) finishRun() {
+	if o == nil {
+		return
+	}
+	if o.dashboard != nil {
+		o.dashboard.Stop()
+		o.dashboard = nil
+	}
+	o.stopProgressLoop()
+	if o.interimReporter != nil {
+		o.interimReporter.Close()
+		o.interimReporter = nil
+	}
+	o.printStopSummary()
+	if o.metricsExporter != nil {
+		o.metricsExporter.Stop()
+		o.metricsExporter = nil
+	}
+	if o.otlpExporter != nil {
+		if err := o.otlpExporter.Stop(); err != nil {
+			fmt.Printf("⚠️  Final OTLP export failed: %v\n", err)
+		}
+		if dropped := o.otlpExporter.DroppedSpans(); dropped > 0 {
+			fmt.Printf("   OTLP spans dropped (queue full): %d\n", dropped)
+		}
+		o.otlpExporter = nil
+	}
+	if o.liveExporter != nil {
+		if undelivered := o.liveExporter.Stop(); undelivered > 0 {
+			fmt.Printf("⚠️  Live export: %d datapoint(s) could not be delivered\n", undelivered)
+		}
+		o.liveExporter = nil
+	}
+	o.exportScheduleTrace()
+	o.closeInterceptors()
+	o.closeRawSamples()
+	o.closePartialReport()
+	o.closeCheckpoint()
+}
+
+// startInterim 开始输出阶段汇总，实时面板显示时只写入日志文件
+func (o *runOptions) startInterim(collector interfaces.DefaultMetricsCollector) {
+	observable, ok := collector.(interface{ AddObserver(metrics.ResultObserver) })
+	if !ok {
+		return
+	}
+	toStdout := !o.withDashboard
+	o.interimReporter = metrics.NewInterimReporter(o.interimInterval, func(summary metrics.InterimSummary) {
+		line := summary.String()
+		log.Printf("Progress %s", line)
+		if toStdout {
+			fmt.Printf("⏱️  %s\n", line)
+		}
+	})
+	observable.AddObserver(o.interimReporter)
+}
+
+// startBreaker 开始检查停止条件，满足时中断执行引擎；未经执行引擎运行时不检查
+func (o *runOptions) startBreaker(collector interfaces.DefaultMetricsCollector) {
+	observable, ok := collector.(interface{ AddObserver(metrics.ResultObserver) })
+	if !ok || o.runEngine == nil {
+		return
+	}
+	engine := o.runEngine
+	o.breaker = metrics.NewErrorBreaker(o.stopConditions, func(trip metrics.StopTrip) {
+		log.Printf("Stop condition tripped after %v: %s", trip.Elapsed.Round(time.Millisecond), trip.Reason)
+		engine.Abort("stop condition: " + trip.Reason)
+	})
+	observable.AddObserver(o.breaker)
+}
+
+// printStopSummary 停止条件触发时输出失败摘要，在实时面板停止后调用以免打乱面板
+func (o *runOptions) printStopSummary() {
+	if o.breaker == nil {
+		return
+	}
+	trip := o.breaker.Tripped()
+	if trip == nil {
+		return
+	}
+	fmt.Printf("🛑 Stop condition tripped after %v: %s\n", trip.Elapsed.Round(time.Millisecond), trip.Reason)
+	fmt.Printf("   %d operations, %d failed", trip.Total, trip.Failed)
+	if trip.LastError != "" {
+		fmt.Printf(", last error: %s", trip.LastError)
+	}
+	fmt.Println()
+}
+
+// tripped 触发的停止条件，未触发时为空
+func (o *runOptions) tripped() metrics.StopCondition {
+	if o.stopCondition != "" || o.breaker == nil {
+		return o.stopCondition
+	}
+	if trip := o.breaker.Tripped(); trip != nil {
+		return trip.Condition
+	}
+	return ""
+}
+
+// startCheckpoint 开始按间隔写入检查点
+func (o *runOptions) startCheckpoint(collector interfaces.DefaultMetricsCollector) {
+	histogram, _ := collector.(latencyHistogramSource)
+	source := func() (*metrics.DefaultMetricsSnapshot, int64) {
+		snapshot := collector.Snapshot()
+		if snapshot != nil && snapshot.LatencyHistogram == nil && histogram != nil {
+			snapshot.LatencyHistogram = histogram.LatencyHistogram()
+		}
+		var jobs int64
+		if o.runEngine != nil {
+			jobs = o.runEngine.Progress().Completed
+		}
+		return snapshot, jobs
+	}
+	writer, err := metrics.NewCheckpointWriter(o.checkpointPath, o.checkpointInterval, o.resumeFrom, source)
+	if err != nil {
+		fmt.Printf("⚠️  Checkpoints disabled: %v\n", err)
+		return
+	}
+	o.checkpointWriter = writer
+	if base := o.resumeFrom; base != nil {
+		fmt.Printf("♻️  Resuming from checkpoint %s: segment %d, %d operations over %v already measured\n",
+			o.checkpointPath, base.Segments+1, base.Snapshot.Core.Operations.Total, base.Elapsed.Round(time.Second))
+	}
+	fmt.Printf("💾 Writing checkpoint to %s every %v\n", o.checkpointPath, writer.Interval())
+}
+
+// closeCheckpoint 写入最终检查点，运行未完整结束时提示续跑方式
+func (o *runOptions) closeCheckpoint() {
+	if o.checkpointWriter == nil {
+		return
+	}
+	complete := o.abortReason() == "" && (o.ctx == nil || o.ctx.Err() == nil)
+	checkpoint, err := o.checkpointWriter.Close(complete)
+	o.checkpointWriter = nil
+	if err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+		return
+	}
+	o.checkpoint = checkpoint
+	fmt.Printf("✅ Checkpoint saved to: %s (%d segment(s), %d operations)\n",
+		o.checkpointPath, checkpoint.Segments, checkpoint.Snapshot.Core.Operations.Total)
+	if !complete {
+		fmt.Printf("   Resume the run with: --resume %s\n", o.checkpointPath)
+	}
+}
+
+// resumedSnapshot 续跑时返回与检查点中各运行段合并后的快照，否则返回snapshot
+func (o *runOptions) resumedSnapshot(snapshot *metrics.DefaultMetricsSnapshot) *metrics.DefaultMetricsSnapshot {
+	if o.resumeFrom == nil || o.checkpoint == nil {
+		return snapshot
+	}
+	return o.checkpoint.Snapshot
+}
+
+// startRawSamples 开始导出原始样本
+func (o *runOptions) startRawSamples(collector interfaces.DefaultMetricsCollector) {
+	observable, ok := collector.(interface{ AddObserver(metrics.ResultObserver) })
+	if !ok {
+		return
+	}
+	spool, err := metrics.NewSampleSpool(o.rawSamplesPath, 0)
+	if err != nil {
+		fmt.Printf("⚠️  Raw sample export disabled: %v\n", err)
+		return
+	}
+	observable.AddObserver(spool)
+	o.rawSamples = spool
+}
+
+// closeRawSamples 写完剩余的原始样本并输出导出结果
+func (o *runOptions) closeRawSamples() {
+	if o.rawSamples == nil {
+		return
+	}
+	summary, err := o.rawSamples.Close()
+	o.rawSamples = nil
+	if err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+		return
+	}
+	fmt.Printf("✅ Raw samples (gzip CSV) saved to: %s\n", o.rawSamplesPath)
+	fmt.Printf("   Samples: %d, Spool segments: %d, Compressed size: %d bytes\n", summary.Samples, summary.Segments, summary.Bytes)
+	if summary.Dropped > 0 {
+		fmt.Printf("   Samples not written: %d\n", summary.Dropped)
+	}
+}
+
+// startPartialReport 开始周期性写入部分报告
+func (o *runOptions) startPartialReport(collector interfaces.DefaultMetricsCollector) {
+	observable, ok := collector.(interface{ AddObserver(metrics.ResultObserver) })
+	if !ok {
+		return
+	}
+	reporter, err := metrics.NewPartialReporter(o.partialReportPath, o.partialReportInterval)
+	if err != nil {
+		fmt.Printf("⚠️  Partial reports disabled: %v\n", err)
+		return
+	}
+	observable.AddObserver(reporter)
+	o.partialReporter = reporter
+	fmt.Printf("💾 Writing partial report to %s every %v\n", o.partialReportPath, reporter.Interval())
+}
+
+// closePartialReport 写入最后一个间隔，保留各间隔供最终报告合并
+func (o *runOptions) closePartialReport() {
+	if o.partialReporter == nil {
+		return
+	}
+	report, err := o.partialReporter.Close()
+	o.partialReporter = nil
+	if err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	}
+	o.partialReport = report
+	fmt.Printf("✅ Partial report completed: %s (%d intervals)\n", o.partialReportPath, len(report.Intervals))
+}
+
+// exportScheduleTrace 导出调度追踪并输出滞后汇总
+func (o *runOptions) exportScheduleTrace() {
+	if o.scheduleTracer == nil {
+		return
+	}
+
+	summary := o.scheduleTracer.Summary()
+	if err := o.scheduleTracer.ExportToFile(o.scheduleTracePath, o.scheduleTraceFormat); err != nil {
+		fmt.Printf("⚠️  Failed to export schedule trace: %v\n", err)
+		return
+	}
+
+	fmt.Printf("✅ Schedule trace (%s) saved to: %s\n", o.scheduleTraceFormat, o.scheduleTracePath)
+	fmt.Printf("   Scheduled ops: %d, Avg lag: %v, P99 lag: %v, Max lag: %v\n",
+		summary.Events, summary.AverageLag, summary.P99Lag, summary.MaxLag)
+	if summary.Dropped > 0 {
+		fmt.Printf("   Dropped events: %d (trace capacity exceeded)\n", summary.Dropped)
+	}
+}
+
+// runOptionsHelp 共享运行选项的帮助信息
+const runOptionsHelp = `
+
+COMMON OPTIONS:
+  --prefill N                    Load N items before the measured run, excluded
+                                 from metrics (redis: SET key_0..key_N-1 with
+                                 data_size values; kafka: N messages to the
+                                 topic), so reads do not measure misses
+  --engine shared|percore        Execution engine (default: shared). percore pins
+                                 the workers of each core to one CPU with its own
+                                 connections (redis standard mode) and a local
+                                 metrics shard merged at snapshot time, avoiding
+                                 the shared job queue; --parallels is split
+                                 across cores
+  --cores N                      Cores for the percore engine (default: all
+                                 CPUs available to the process; implies
+                                 --engine percore)
+  --no-tui                       Plain output without the live dashboard. The
+                                 dashboard (throughput sparkline, error rate,
+                                 latency percentiles and histogram, busy
+                                 workers, ETA; redrawn every second) is shown
+                                 only when stdout is a terminal, so CI logs and
+                                 redirected output stay plain either way
+  --progress-interval DUR        Print a summary line every DUR during the run
+                                 (elapsed, completed ops, RPS and p99 over the
+                                 last interval, errors) and write it to the log
+                                 file (default: 10s, 0 disables). With the live
+                                 dashboard the line only goes to the log file
+  --abort-error-rate PCT[@WIN]   Stop the run when more than PCT of the
+                                 operations in the last WIN failed (e.g.
+                                 20%@10s; WIN defaults to 10s, checked once the
+                                 run is WIN old and the window has at least 20
+                                 operations). Exits with code 3
+  --abort-max-errors N           Stop the run after N failed operations in
+                                 total. Exits with code 3
+  --abort-consecutive-errors N   Stop the run after N failed operations in a
+                                 row, e.g. when the target went down. Exits
+                                 with code 5
+
+  A tripped stop condition (any of the --abort-* options above) prints a
+  failure summary (condition, operations, last error) and the report covers
+  the data collected until then.
+
+  --schedule-trace FILE          Export scheduled-vs-actual operation start times
+  --schedule-trace-format FMT    Trace format: chrome (default) or otlp
+  --request-id                   Give every operation a unique ID (PREFIX-N, the
+                                 prefix is random per run) and send it as the
+                                 X-Request-ID header (HTTP), x-request-id
+                                 metadata (gRPC) or message header (Kafka), so
+                                 slow requests can be found in target-side logs
+                                 and traces. The ID is included in --raw-samples
+  --trace-context                Start a new W3C trace for every operation and
+                                 send it as the traceparent header (HTTP),
+                                 metadata (gRPC) or message header (Kafka), so
+                                 load-test requests show up in the target's
+                                 tracing backend
+  --trace-sample RATIO           Fraction of traces marked sampled in traceparent
+                                 (0-1, default: 1; implies --trace-context)
+  --trace-otlp ENDPOINT          Also export a client span for every sampled
+                                 operation, with the propagated trace and span
+                                 IDs, to the OTLP/HTTP ENDPOINT (e.g.
+                                 http://localhost:4318; implies --trace-context,
+                                 metrics are pushed there as well)
+  --raw-samples FILE             Export every operation (start time, type,
+                                 latency, success, read, status code, request
+                                 ID) as gzip-compressed CSV. Samples are
+                                 spooled to memory-mapped segment files next to
+                                 FILE and compressed in the background, so
+                                 memory stays flat even for runs with millions
+                                 of operations
+  --partial-report FILE          Rewrite FILE (JSON) every --partial-interval with
+                                 per-interval and cumulative counts, throughput
+                                 and latency percentiles, so a crash or OOM kill
+                                 keeps everything up to the last interval. The
+                                 intervals are merged into the final report
+  --partial-interval DUR         Partial report interval (default: 10s)
+  --intercept KIND:ARG           Wrap every operation in an interceptor,
+                                 repeatable, applied outermost first:
+                                   label:KEY=VALUE    add KEY=VALUE to each
+                                                      result's metadata
+                                   header:NAME=VALUE  send a header (HTTP), metadata
+                                                      (gRPC) or message header
+                                                      (Kafka), e.g. an auth token
+                                   delay:DUR[@RATIO]  wait DUR before a RATIO
+                                                      (0-1, default 1) of
+                                                      operations, counted in latency
+                                   error:RATIO        fail a RATIO of operations
+                                                      without sending them
+                                   log:FILE           write every operation as a
+                                                      JSON line to FILE
+  --report-locale LOCALE         Language of the console and HTML reports: zh
+                                 (default) or en; region tags such as en-US
+                                 are accepted. JSON, CSV and JUnit output is
+                                 not localized
+  --tag TAG                      Tag the run in the local history, repeatable
+                                 (filter with "abc-runner runs list --tag TAG")
+  --push-gateway URL             Push the final results (operations, RPS, error
+                                 rate, latency percentiles, bytes, passed) to
+                                 a Prometheus Pushgateway when the run ends,
+                                 grouped by protocol and target, with git_sha
+                                 and run_id labels
+  --push-remote-write URL        Write the same final results to a Prometheus
+                                 remote-write endpoint (e.g.
+                                 http://prometheus:9090/api/v1/write), one
+                                 sample per series at the end of the run
+  --run-id ID                    Run ID label of the pushed results and ID of
+                                 the run in the local history (default:
+                                 start time plus a random suffix)
+  --git-sha SHA                  Git SHA label of the pushed results (default:
+                                 $GITHUB_SHA, $CI_COMMIT_SHA, $GIT_COMMIT or
+                                 git rev-parse HEAD in the working directory)
+  --checkpoint FILE              Write the cumulative metrics (with the latency
+                                 histogram) and progress to FILE every
+                                 --checkpoint-interval, so a long soak test cut
+                                 short by a crash or node maintenance can be
+                                 resumed
+  --checkpoint-interval DUR      Checkpoint interval (default: 1m)
+  --resume FILE                  Continue the run saved in checkpoint FILE: job
+                                 numbering continues and the remaining -n or
+                                 --duration is run, then the report merges all
+                                 segments. Keeps updating FILE unless
+                                 --checkpoint is given
+  --metrics-addr ADDR            Expose live Prometheus metrics at http://ADDR/metrics
+  --metrics-config FILE          Metrics config file (e.g. OTLP export, live
+                                 InfluxDB/StatsD datapoints, see
+                                 config/metrics.yaml)
+  --sla EXPR                     SLA rule checked against the final metrics,
+                                 repeatable: "p99 < 20ms", "error_rate < 1%",
+                                 "rps > 5000" (metrics: p50 p90 p95 p99 p999
+                                 avg max rps error_rate success_rate;
+                                 operators: < <= > >=). Rules may also be
+                                 listed under sla: in --metrics-config
+  --sla-p99 DURATION             Shorthand for --sla "p99 <= DURATION"
+  --sla-min-rps N                Shorthand for --sla "rps >= N"
+  --sla-max-error-rate PCT       Shorthand for --sla "error_rate <= PCT"
+                                 SLA results appear in every report format
+                                 plus a JUnit XML report (reports/*.xml);
+                                 any violation exits with code 3`
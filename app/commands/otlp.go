@@ -0,0 +1,395 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	otlpConfig "abc-runner/app/adapters/otlp/config"
+	"abc-runner/app/adapters/otlp/operations"
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/reporting"
+)
+
+// OTLPCommandHandler OpenTelemetry OTLP命令处理器
+type OTLPCommandHandler struct {
+	protocolName string
+	factory      interfaces.OTLPAdapterFactory
+}
+
+// NewOTLPCommandHandler 创建OTLP命令处理器
+func NewOTLPCommandHandler(factory interfaces.OTLPAdapterFactory) *OTLPCommandHandler {
+	if factory == nil {
+		panic("otlpAdapterFactory cannot be nil - dependency injection required")
+	}
+
+	return &OTLPCommandHandler{
+		protocolName: "otlp",
+		factory:      factory,
+	}
+}
+
+// Execute 执行OTLP命令
+func (h *OTLPCommandHandler) Execute(ctx context.Context, args []string) error {
+	// 检查帮助请求
+	for _, arg := range args {
+		if arg == "--help" || arg == "help" || arg == "-h" {
+			fmt.Println(h.GetHelp())
+			return nil
+		}
+	}
+
+	// 解析命令行参数
+	config, err := h.parseArgs(args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	// 解析通用运行选项
+	opts, err := parseRunOptions(ctx, "otlp", args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+	if printConfigRequested(args) {
+		return printEffectiveConfig("otlp", config, opts)
+	}
+
+	metricsCollector := metrics.NewBaseCollector(opts.collectorConfig(), map[string]interface{}{
+		"protocol":  "otlp",
+		"test_type": "performance",
+	})
+	defer metricsCollector.Stop()
+
+	adapter := h.factory.CreateOTLPAdapter()
+	if adapter == nil {
+		return fmt.Errorf("failed to create OTLP adapter")
+	}
+	defer adapter.Close()
+
+	if err := adapter.Connect(ctx, config); err != nil {
+		return fmt.Errorf("failed to set up OTLP exporter: %w", err)
+	}
+	if err := adapter.HealthCheck(ctx); err != nil {
+		fmt.Printf("⚠️  Health check failed: %v\n", err)
+		fmt.Printf("🔍 Possible causes: wrong endpoint or transport (OTLP/HTTP is usually :4318, OTLP/gRPC :4317), missing auth headers, or receiver not enabled\n")
+	} else {
+		fmt.Printf("✅ OTLP endpoint %s accepted an empty export\n", config.Connection.Endpoint)
+	}
+
+	rateDesc := "unlimited"
+	if config.BenchMark.Rate > 0 {
+		rateDesc = fmt.Sprintf("%d %s/s", config.BenchMark.Rate, config.ItemName())
+	}
+	fmt.Printf("🚀 Starting OTLP %s export test...\n", config.BenchMark.TestCase)
+	fmt.Printf("Endpoint: %s (OTLP/%s, compression: %s)\n",
+		config.Connection.Endpoint, strings.ToUpper(config.Connection.Transport), config.Connection.Compression)
+	fmt.Printf("Batch: %d %s/request, Services: %d, Rate: %s\n",
+		config.OTLPSpecific.BatchSize, config.ItemName(), config.OTLPSpecific.Services, rateDesc)
+	fmt.Printf("Requests: %d, Concurrency: %d\n", config.BenchMark.Total, config.BenchMark.Parallels)
+
+	if err := h.runPerformanceTest(ctx, adapter, config, metricsCollector, opts); err != nil {
+		return fmt.Errorf("performance test failed: %w", err)
+	}
+
+	return h.generateReport(metricsCollector, opts)
+}
+
+// GetHelp 获取帮助信息
+func (h *OTLPCommandHandler) GetHelp() string {
+	return `OpenTelemetry OTLP Ingestion Testing
+
+USAGE:
+  abc-runner otlp [options]
+
+DESCRIPTION:
+  Send OTLP trace or metric export batches to an OpenTelemetry Collector or
+  an OTLP-native backend (Tempo, Jaeger, Mimir, Elastic APM, ...). Each
+  request carries one batch; export latency is measured per request and the
+  accepted span (or data point) throughput is reported. Retryable failures
+  (HTTP 429/502/503/504, gRPC RESOURCE_EXHAUSTED/UNAVAILABLE/...) are counted
+  separately from permanent ones, and partial_success rejections reported by
+  the receiver are subtracted from the accepted items.
+
+OPTIONS:
+  --help                   Show this help message
+  --endpoint URL|HOST:PORT OTLP/HTTP base URL (default: http://localhost:4318,
+                           /v1/traces or /v1/metrics is appended) or OTLP/gRPC
+                           address (default: localhost:4317)
+  --grpc                   Use OTLP/gRPC instead of OTLP/HTTP protobuf
+  --signal traces|metrics  Signal to export (default: traces)
+  --compression gzip|none  Request compression (default: gzip)
+  --header "K: V"          Extra HTTP header or gRPC metadata (repeatable)
+  --tls                    Use TLS for OTLP/gRPC (OTLP/HTTP follows the URL scheme)
+  --insecure, -k           Skip TLS certificate verification
+  --timeout DURATION       Export timeout (default: 10s)
+  --batch-size N           Spans or data points per request (default: 512)
+  --rate N                 Cap throughput at N spans (or data points) per second
+  --service-name NAME      service.name resource attribute (default: abc-runner)
+  --services N             Rotate requests across N services (default: 1)
+  --attributes N           Extra attributes per span or data point (default: 5)
+  --attribute-size N       Bytes per extra attribute value (default: 16)
+  -n COUNT                 Total export requests (default: 1000)
+  -c COUNT                 Concurrent exporters (default: 10)
+  --duration DURATION      Run for a fixed duration instead of -n
+
+TRACES:
+  --spans-per-trace N      Spans per trace: one SERVER root plus children (default: 8)
+  --error-percent P        Percentage of traces whose root span has status ERROR
+
+METRICS:
+  --series N               Time series cardinality (default: 10000)
+  --metric-names N         Distinct gauge metric names (default: 10)
+
+EXAMPLES:
+  abc-runner otlp --help
+  abc-runner otlp --endpoint http://localhost:4318 -n 1000
+  abc-runner otlp --grpc --endpoint collector:4317 --batch-size 1024 --rate 200000 --duration 5m
+  abc-runner otlp --signal metrics --series 100000 --batch-size 2000 -c 20
+  abc-runner otlp --endpoint https://otlp.example.com --header "Authorization: Bearer TOKEN"` + configLayersHelp + runOptionsHelp + "\n"
+}
+
+// parseArgs 解析命令行参数
+func (h *OTLPCommandHandler) parseArgs(args []string) (*otlpConfig.OTLPConfig, error) {
+	config := otlpConfig.NewDefaultOTLPConfig()
+	specific := &config.OTLPSpecific
+
+	// 依次应用--config文件、ABC_OTLP_*环境变量与--set，专用参数随后覆盖
+	if err := applyConfigLayers("otlp", args, config); err != nil {
+		return nil, err
+	}
+	// 配置中改过的地址不再替换为gRPC默认地址
+	endpointSet := config.Connection.Endpoint != otlpConfig.NewDefaultOTLPConfig().Connection.Endpoint
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--grpc":
+			config.Connection.Transport = otlpConfig.TransportGRPC
+			continue
+		case "--tls":
+			config.Connection.TLS = true
+			continue
+		case "--insecure", "-k":
+			config.Connection.InsecureSkipVerify = true
+			continue
+		}
+
+		if i+1 >= len(args) {
+			break
+		}
+		value := args[i+1]
+		var err error
+
+		switch args[i] {
+		case "--endpoint":
+			config.Connection.Endpoint = value
+			endpointSet = true
+		case "--signal":
+			config.BenchMark.TestCase = value
+		case "--compression":
+			config.Connection.Compression = value
+		case "--header":
+			name, headerValue, found := strings.Cut(value, ":")
+			if !found || strings.TrimSpace(name) == "" {
+				err = fmt.Errorf("expected \"Name: value\", got %q", value)
+				break
+			}
+			config.Connection.Headers[strings.TrimSpace(name)] = strings.TrimSpace(headerValue)
+		case "--timeout":
+			config.Connection.Timeout, err = time.ParseDuration(value)
+		case "--batch-size":
+			specific.BatchSize, err = strconv.Atoi(value)
+		case "--rate":
+			config.BenchMark.Rate, err = strconv.Atoi(value)
+		case "--service-name":
+			specific.ServiceName = value
+		case "--services":
+			specific.Services, err = strconv.Atoi(value)
+		case "--attributes":
+			specific.Attributes, err = strconv.Atoi(value)
+		case "--attribute-size":
+			specific.AttributeSize, err = strconv.Atoi(value)
+		case "--spans-per-trace":
+			specific.SpansPerTrace, err = strconv.Atoi(value)
+		case "--error-percent":
+			specific.ErrorPercent, err = strconv.Atoi(value)
+		case "--series":
+			specific.Series, err = strconv.Atoi(value)
+		case "--metric-names":
+			specific.MetricNames, err = strconv.Atoi(value)
+		case "-n":
+			config.BenchMark.Total, err = strconv.Atoi(value)
+		case "-c":
+			config.BenchMark.Parallels, err = strconv.Atoi(value)
+		case "--duration":
+			config.BenchMark.Duration, err = time.ParseDuration(value)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", args[i], err)
+		}
+		i++
+	}
+
+	if config.Connection.Transport == otlpConfig.TransportGRPC && !endpointSet {
+		config.Connection.Endpoint = otlpConfig.DefaultGRPCEndpoint
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// runPerformanceTest 运行OTLP导出测试
+func (h *OTLPCommandHandler) runPerformanceTest(
+	ctx context.Context,
+	adapter interfaces.ProtocolAdapter,
+	config *otlpConfig.OTLPConfig,
+	collector *metrics.BaseCollector[map[string]interface{}],
+	opts *runOptions,
+) error {
+	factory := operations.NewOperationFactory(config.BenchMark.TestCase)
+	engine := execution.NewExecutionEngine(adapter, collector, factory)
+	engine.SetMaxWorkers(config.BenchMark.Parallels)
+	opts.applyToEngine(engine, collector)
+
+	testStartTime := time.Now()
+	result, err := engine.RunBenchmark(ctx, &config.BenchMark)
+	if err != nil {
+		return fmt.Errorf("benchmark execution failed: %w", err)
+	}
+	opts.finishRun()
+	actualTestDuration := time.Since(testStartTime)
+
+	fmt.Printf("\n📊 Execution Results:\n")
+	fmt.Printf("Total Jobs: %d\n", result.TotalJobs)
+	fmt.Printf("Completed Jobs: %d\n", result.CompletedJobs)
+	fmt.Printf("Success Jobs: %d\n", result.SuccessJobs)
+	fmt.Printf("Failed Jobs: %d\n", result.FailedJobs)
+	fmt.Printf("Actual Test Duration: %v\n", actualTestDuration)
+
+	protocolMetrics := map[string]interface{}{
+		"protocol":         "otlp",
+		"test_type":        "performance",
+		"signal":           config.BenchMark.TestCase,
+		"transport":        config.Connection.Transport,
+		"batch_size":       config.OTLPSpecific.BatchSize,
+		"actual_duration":  actualTestDuration,
+		"execution_result": result,
+	}
+
+	if statsAdapter, ok := adapter.(interface {
+		GetExportStats() *operations.ExportStats
+	}); ok {
+		if stats := statsAdapter.GetExportStats(); stats != nil {
+			h.printExportStats(stats, config, actualTestDuration)
+			itemsPerSec := float64(stats.ItemsAccepted) / actualTestDuration.Seconds()
+			protocolMetrics["export_stats"] = *stats
+			if config.BenchMark.TestCase == "metrics" {
+				protocolMetrics["data_points_per_sec"] = itemsPerSec
+			} else {
+				protocolMetrics["spans_per_sec"] = itemsPerSec
+			}
+		}
+	}
+
+	collector.UpdateProtocolMetrics(protocolMetrics)
+	return nil
+}
+
+// printExportStats 输出导出统计与响应分布
+func (h *OTLPCommandHandler) printExportStats(stats *operations.ExportStats, config *otlpConfig.OTLPConfig, duration time.Duration) {
+	seconds := duration.Seconds()
+	items := config.ItemName()
+	fmt.Printf("\n📈 Ingestion:\n")
+	fmt.Printf("%s Accepted: %d / %d (%.2f %s/sec)\n", strings.ToUpper(items[:1])+items[1:],
+		stats.ItemsAccepted, stats.ItemsSent, float64(stats.ItemsAccepted)/seconds, items)
+	if stats.ItemsRejected > 0 {
+		fmt.Printf("Rejected by receiver (partial success): %d in %d requests\n", stats.ItemsRejected, stats.PartialSuccess)
+	}
+	if stats.BytesSent > 0 {
+		fmt.Printf("Bytes Sent: %d compressed, %d raw (ratio %.2f, %.2f MB/sec)\n",
+			stats.BytesSent, stats.UncompressedBytes, float64(stats.UncompressedBytes)/float64(stats.BytesSent),
+			float64(stats.BytesSent)/seconds/1024/1024)
+	} else {
+		fmt.Printf("Bytes Sent: %d raw (%.2f MB/sec)\n", stats.UncompressedBytes, float64(stats.UncompressedBytes)/seconds/1024/1024)
+	}
+	fmt.Printf("Retryable Failures: %d (%.2f%%)\n", stats.Retryable, stats.RetryableRate)
+	if stats.Permanent > 0 {
+		fmt.Printf("Permanent Failures: %d\n", stats.Permanent)
+	}
+	if stats.TransportErrors > 0 {
+		fmt.Printf("Transport Errors: %d\n", stats.TransportErrors)
+	}
+
+	statuses := make([]string, 0, len(stats.Statuses))
+	for status := range stats.Statuses {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		line := fmt.Sprintf("  %s: %d", status, stats.Statuses[status])
+		if message := stats.ErrorSamples[status]; message != "" {
+			line += fmt.Sprintf(" (%s)", message)
+		}
+		fmt.Println(line)
+	}
+	if message := stats.ErrorSamples["partial_success"]; message != "" {
+		fmt.Printf("  partial success: %s\n", message)
+	}
+}
+
+// generateReport 生成OTLP测试报告
+func (h *OTLPCommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
+	snapshot := collector.Snapshot()
+
+	// 使用实际测试时间修正吞吐量
+	if duration, ok := snapshot.Protocol["actual_duration"].(time.Duration); ok && duration > 0 {
+		snapshot.Core.Duration = duration
+		seconds := duration.Seconds()
+		snapshot.Core.Throughput.RPS = float64(snapshot.Core.Operations.Total) / seconds
+		snapshot.Core.Throughput.WriteRPS = float64(snapshot.Core.Operations.Write) / seconds
+		snapshot.Core.Throughput.ReadMBps, snapshot.Core.Throughput.WriteMBps = metrics.Bandwidth(snapshot.Core.Operations, duration)
+	}
+
+	fmt.Printf("\n📊 OTLP Performance Metrics:\n")
+	fmt.Printf("=====================================\n")
+	fmt.Printf("  Total Requests: %d\n", snapshot.Core.Operations.Total)
+	fmt.Printf("  Successful Requests: %d\n", snapshot.Core.Operations.Success)
+	fmt.Printf("  Failed Requests: %d\n", snapshot.Core.Operations.Failed)
+	fmt.Printf("  Success Rate: %.2f%%\n", snapshot.Core.Operations.Rate)
+	fmt.Printf("  Export Latency avg/p95/p99: %v/%v/%v\n",
+		snapshot.Core.Latency.Average, snapshot.Core.Latency.P95, snapshot.Core.Latency.P99)
+	fmt.Printf("  Throughput: %.2f requests/sec\n", snapshot.Core.Throughput.RPS)
+	if spans, ok := snapshot.Protocol["spans_per_sec"].(float64); ok {
+		fmt.Printf("  Span Throughput: %.2f spans/sec\n", spans)
+	}
+	if points, ok := snapshot.Protocol["data_points_per_sec"].(float64); ok {
+		fmt.Printf("  Data Point Throughput: %.2f data points/sec\n", points)
+	}
+	fmt.Printf("=====================================\n")
+
+	// 存在快照接收器时（如分布式agent模式）交由接收器处理，不生成本地报告
+	if opts.publishSnapshot(snapshot) {
+		return nil
+	}
+
+	report := reporting.ConvertFromMetricsSnapshot(snapshot)
+	reportConfig := reporting.NewStandardReportConfig("otlp")
+	opts.applyToReport(snapshot, report, reportConfig)
+	generator := reporting.NewReportGenerator(reportConfig)
+	if err := generator.Generate(report); err != nil {
+		return err
+	}
+	return opts.resultError(report)
+}
+
+// GetProtocolName 获取协议名称
+func (h *OTLPCommandHandler) GetProtocolName() string {
+	return h.protocolName
+}
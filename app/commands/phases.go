@@ -0,0 +1,20 @@
+package commands
+
+import (
+	"fmt"
+
+	"abc-runner/app/core/metrics"
+)
+
+// printLatencyPhases 输出按阶段的延迟分解，说明时间花在建连、TLS、服务端处理还是传输上
+func printLatencyPhases(phases []metrics.PhaseLatency) {
+	if len(phases) == 0 {
+		return
+	}
+	fmt.Printf("⏱️  Latency by phase (share of the time spent in all phases):\n")
+	for _, phase := range phases {
+		latency := phase.Latency
+		fmt.Printf("   %-10s %8d  avg %-10v p50 %-10v p99 %-10v max %-10v %5.1f%%\n",
+			phase.Phase, phase.Count, latency.Average, latency.P50, latency.P99, latency.Max, phase.Share*100)
+	}
+}
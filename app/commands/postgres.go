@@ -0,0 +1,415 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	pgConfig "abc-runner/app/adapters/postgres/config"
+	"abc-runner/app/adapters/postgres/connection"
+	"abc-runner/app/adapters/postgres/operations"
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/reporting"
+)
+
+// PostgresCommandHandler PostgreSQL命令处理器
+type PostgresCommandHandler struct {
+	protocolName string
+	factory      interfaces.PostgresAdapterFactory
+}
+
+// NewPostgresCommandHandler 创建PostgreSQL命令处理器
+func NewPostgresCommandHandler(factory interfaces.PostgresAdapterFactory) *PostgresCommandHandler {
+	if factory == nil {
+		panic("postgresAdapterFactory cannot be nil - dependency injection required")
+	}
+
+	return &PostgresCommandHandler{
+		protocolName: "postgres",
+		factory:      factory,
+	}
+}
+
+// Execute 执行PostgreSQL命令
+func (h *PostgresCommandHandler) Execute(ctx context.Context, args []string) error {
+	// 检查帮助请求
+	for i, arg := range args {
+		if arg == "--help" || arg == "help" {
+			fmt.Println(h.GetHelp())
+			return nil
+		}
+		if arg == "-h" && (i == 0 || (i > 0 && args[i-1] != "postgres")) {
+			if i+1 < len(args) && !looksLikeHostname(args[i+1]) {
+				fmt.Println(h.GetHelp())
+				return nil
+			}
+		}
+	}
+
+	// 解析命令行参数
+	config, err := h.parseArgs(args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	// 解析通用运行选项
+	opts, err := parseRunOptions(ctx, "postgres", args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+	if printConfigRequested(args) {
+		return printEffectiveConfig("postgres", config, opts)
+	}
+
+	metricsCollector := metrics.NewBaseCollector(opts.collectorConfig(), map[string]interface{}{
+		"protocol":  "postgres",
+		"test_type": "performance",
+	})
+	defer metricsCollector.Stop()
+
+	adapter := h.factory.CreatePostgresAdapter()
+	if adapter == nil {
+		return fmt.Errorf("failed to create PostgreSQL adapter")
+	}
+	defer adapter.Close()
+
+	target := config.Connection.GetAddresses()[0]
+	if err := adapter.Connect(ctx, config); err != nil {
+		return NewConnectionError(fmt.Errorf("failed to connect to postgres %s: %w", target, err))
+	}
+	if err := adapter.HealthCheck(ctx); err != nil {
+		return NewConnectionError(fmt.Errorf("postgres health check failed: %w", err))
+	}
+
+	if pgAdapter, ok := adapter.(interface {
+		ServerVersion() string
+		TableCreated() bool
+	}); ok {
+		fmt.Printf("✅ Connected to PostgreSQL %s at %s (database: %s, pool: %d)\n",
+			pgAdapter.ServerVersion(), target, config.Connection.Database, config.GetPoolSize())
+		if pgAdapter.TableCreated() {
+			fmt.Printf("🛠️  Created table %s with %d rows\n", config.PostgresSpecific.Table, config.PostgresSpecific.Rows)
+		}
+	}
+
+	mode := "unprepared"
+	if config.PostgresSpecific.Prepared {
+		mode = "prepared"
+	}
+	if config.PostgresSpecific.Transaction {
+		mode += fmt.Sprintf(", %d statement(s) per transaction", config.PostgresSpecific.TxStatements)
+	}
+
+	fmt.Printf("🚀 Starting PostgreSQL performance test...\n")
+	fmt.Printf("Test Case: %s (%s)\n", config.BenchMark.TestCase, mode)
+	if config.BenchMark.TestCase == "mixed" {
+		mix := config.PostgresSpecific.Mix
+		fmt.Printf("Mix: select=%d, insert=%d, update=%d\n", mix.Select, mix.Insert, mix.Update)
+	}
+	fmt.Printf("Operations: %d, Concurrency: %d\n", config.BenchMark.Total, config.BenchMark.Parallels)
+
+	if err := h.runPerformanceTest(ctx, adapter, config, metricsCollector, opts); err != nil {
+		return fmt.Errorf("performance test failed: %w", err)
+	}
+
+	return h.generateReport(metricsCollector, opts)
+}
+
+// GetHelp 获取帮助信息
+func (h *PostgresCommandHandler) GetHelp() string {
+	return `PostgreSQL Query Performance Testing
+
+USAGE:
+  abc-runner postgres [options]
+
+DESCRIPTION:
+  Run parameterized SELECT, INSERT and UPDATE workloads against PostgreSQL
+  over a fixed-size connection pool and report latency per query type, as
+  a replacement for pgbench with the unified abc-runner reports.
+
+  The default queries run against a table of the form
+  (id BIGSERIAL PRIMARY KEY, k INTEGER, payload TEXT); --setup creates and
+  fills it. Custom queries must keep the same parameters:
+    select: $1=id            insert: $1=k, $2=payload
+    update: $1=delta, $2=id
+
+OPTIONS:
+  --help                   Show this help message
+  --host HOST, -h HOST     Server host (default: localhost)
+  --port PORT, -p PORT     Server port (default: 5432)
+  --user USER, -U USER     User name (default: postgres)
+  --password PASSWORD      Password for cleartext, MD5 or SCRAM-SHA-256 auth
+  --dbname NAME, -d NAME   Database name (default: postgres)
+  --sslmode MODE           disable, prefer or require (default: disable)
+  --pool-size N            Connection pool size (default: same as -c)
+  --timeout DURATION       Connect and per-statement timeout (default: 5s)
+  --test-case CASE         select, insert, update or mixed (default: select)
+  --mix SPEC               Weights for mixed, e.g. select=80,insert=10,update=10
+  --prepared               Prepare each query once per connection and reuse it
+  --transaction            Wrap each operation in BEGIN/COMMIT
+  --tx-statements N        Statements per transaction (default: 1)
+  --table NAME             Table used by the default queries (default: abc_bench)
+  --rows N                 ids are drawn uniformly from [1, N] (default: 10000)
+  --setup                  Create and fill the table if it does not exist
+  --payload-size N         Payload bytes for inserts and setup (default: 100)
+  --select-query SQL       Custom SELECT query
+  --insert-query SQL       Custom INSERT query
+  --update-query SQL       Custom UPDATE query
+  -n COUNT                 Total operations (default: 1000)
+  -c COUNT                 Concurrent workers (default: 10)
+  --duration DURATION      Run for a fixed duration instead of -n
+
+NOTES:
+  Operation latency includes waiting for a pooled connection. A pool smaller
+  than -c shows up as a high average pool wait. In transaction mode each
+  operation is one transaction and the per-type table has an extra
+  "transaction" row covering BEGIN to COMMIT.
+
+EXAMPLES:
+  abc-runner postgres --help
+  abc-runner postgres -h localhost -U postgres --password secret --setup -n 10000 -c 20
+  abc-runner postgres -h db --test-case mixed --mix select=90,update=10 --prepared --duration 60s
+  abc-runner postgres -h db --test-case update --transaction --tx-statements 5 -c 50
+  abc-runner postgres -h db -d app --select-query "SELECT * FROM users WHERE id = $1" --rows 1000000` + configLayersHelp + runOptionsHelp + "\n"
+}
+
+// parseArgs 解析命令行参数
+func (h *PostgresCommandHandler) parseArgs(args []string) (*pgConfig.PostgresConfig, error) {
+	config := pgConfig.NewDefaultPostgresConfig()
+
+	// 依次应用--config文件、ABC_POSTGRES_*环境变量与--set，专用参数随后覆盖
+	if err := applyConfigLayers("postgres", args, config); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(args); i++ {
+		// 无参数的开关
+		switch args[i] {
+		case "--prepared":
+			config.PostgresSpecific.Prepared = true
+			continue
+		case "--transaction":
+			config.PostgresSpecific.Transaction = true
+			continue
+		case "--setup":
+			config.PostgresSpecific.Setup = true
+			continue
+		}
+
+		if i+1 >= len(args) {
+			break
+		}
+		value := args[i+1]
+		var err error
+
+		switch args[i] {
+		case "--host", "-h":
+			if !looksLikeHostname(value) {
+				continue
+			}
+			config.Connection.Address = value
+		case "--port", "-p":
+			config.Connection.Port, err = strconv.Atoi(value)
+		case "--user", "-U":
+			config.Connection.User = value
+		case "--password":
+			config.Connection.Password = value
+		case "--dbname", "-d":
+			config.Connection.Database = value
+		case "--sslmode":
+			config.Connection.SSLMode = strings.ToLower(value)
+		case "--pool-size":
+			config.Connection.PoolSize, err = strconv.Atoi(value)
+		case "--timeout":
+			config.Connection.Timeout, err = time.ParseDuration(value)
+		case "--test-case":
+			config.BenchMark.TestCase = strings.ToLower(value)
+		case "--mix":
+			config.PostgresSpecific.Mix, err = parseQueryMix(value)
+		case "--tx-statements":
+			config.PostgresSpecific.TxStatements, err = strconv.Atoi(value)
+		case "--table":
+			config.PostgresSpecific.Table = value
+		case "--rows":
+			config.PostgresSpecific.Rows, err = strconv.Atoi(value)
+		case "--payload-size":
+			config.PostgresSpecific.PayloadSize, err = strconv.Atoi(value)
+		case "--select-query":
+			config.PostgresSpecific.Queries.Select = value
+		case "--insert-query":
+			config.PostgresSpecific.Queries.Insert = value
+		case "--update-query":
+			config.PostgresSpecific.Queries.Update = value
+		case "-n":
+			config.BenchMark.Total, err = strconv.Atoi(value)
+		case "-c":
+			config.BenchMark.Parallels, err = strconv.Atoi(value)
+		case "--duration":
+			config.BenchMark.Duration, err = time.ParseDuration(value)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", args[i], err)
+		}
+		i++
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// parseQueryMix 解析"select=80,insert=10,update=10"形式的权重，未列出的类型权重为0
+func parseQueryMix(value string) (pgConfig.MixConfig, error) {
+	var mix pgConfig.MixConfig
+	for _, part := range strings.Split(value, ",") {
+		name, weightStr, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return mix, fmt.Errorf("expected type=weight, got %q", part)
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(weightStr))
+		if err != nil {
+			return mix, err
+		}
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case pgConfig.QuerySelect:
+			mix.Select = weight
+		case pgConfig.QueryInsert:
+			mix.Insert = weight
+		case pgConfig.QueryUpdate:
+			mix.Update = weight
+		default:
+			return mix, fmt.Errorf("unknown query type %q", name)
+		}
+	}
+	return mix, nil
+}
+
+// runPerformanceTest 运行PostgreSQL性能测试
+func (h *PostgresCommandHandler) runPerformanceTest(
+	ctx context.Context,
+	adapter interfaces.ProtocolAdapter,
+	config *pgConfig.PostgresConfig,
+	collector *metrics.BaseCollector[map[string]interface{}],
+	opts *runOptions,
+) error {
+	factory := operations.NewOperationFactory(config)
+	engine := execution.NewExecutionEngine(adapter, collector, factory)
+	engine.SetMaxWorkers(config.BenchMark.Parallels)
+	opts.applyToEngine(engine, collector)
+
+	testStartTime := time.Now()
+	result, err := engine.RunBenchmark(ctx, &config.BenchMark)
+	if err != nil {
+		return fmt.Errorf("benchmark execution failed: %w", err)
+	}
+	opts.finishRun()
+	actualTestDuration := time.Since(testStartTime)
+
+	fmt.Printf("\n📊 Execution Results:\n")
+	fmt.Printf("Total Jobs: %d\n", result.TotalJobs)
+	fmt.Printf("Completed Jobs: %d\n", result.CompletedJobs)
+	fmt.Printf("Success Jobs: %d\n", result.SuccessJobs)
+	fmt.Printf("Failed Jobs: %d\n", result.FailedJobs)
+	fmt.Printf("Actual Test Duration: %v\n", actualTestDuration)
+
+	protocolMetrics := map[string]interface{}{
+		"protocol":         "postgres",
+		"test_type":        "performance",
+		"test_case":        config.BenchMark.TestCase,
+		"target":           config.Connection.GetAddresses()[0],
+		"prepared":         config.PostgresSpecific.Prepared,
+		"transaction":      config.PostgresSpecific.Transaction,
+		"actual_duration":  actualTestDuration,
+		"execution_result": result,
+	}
+
+	if statsAdapter, ok := adapter.(interface {
+		GetQueryStats() []operations.QueryStats
+		GetPoolStats() *connection.PoolStats
+		ServerVersion() string
+	}); ok {
+		protocolMetrics["server_version"] = statsAdapter.ServerVersion()
+		if stats := statsAdapter.GetQueryStats(); len(stats) > 0 {
+			printQueryStats(stats, actualTestDuration)
+			protocolMetrics["query_stats"] = stats
+		}
+		if pool := statsAdapter.GetPoolStats(); pool != nil {
+			fmt.Printf("Pool: %d connection(s), avg wait %v", pool.Size, pool.AvgWait)
+			if pool.Reconnects > 0 {
+				fmt.Printf(", %d reconnect(s)", pool.Reconnects)
+			}
+			fmt.Println()
+			protocolMetrics["pool_stats"] = *pool
+		}
+	}
+
+	collector.UpdateProtocolMetrics(protocolMetrics)
+	return nil
+}
+
+// printQueryStats 打印按查询类型的统计表
+func printQueryStats(stats []operations.QueryStats, duration time.Duration) {
+	fmt.Printf("\n📋 Per-Query Statistics:\n")
+	fmt.Printf("  %-12s %10s %10s %8s %10s %10s %10s %10s\n",
+		"TYPE", "COUNT", "QPS", "ERR%", "ROWS", "P50", "P99", "MAX")
+	for _, s := range stats {
+		fmt.Printf("  %-12s %10d %10.1f %8.2f %10d %10v %10v %10v\n",
+			s.Type, s.Count, float64(s.Count)/duration.Seconds(), s.ErrorRate, s.Volume,
+			s.Latency.P50, s.Latency.P99, s.Latency.Max)
+		for code, n := range s.ErrorCodes {
+			fmt.Printf("  %-12s   %s: %d\n", "", code, n)
+		}
+	}
+}
+
+// generateReport 生成PostgreSQL测试报告
+func (h *PostgresCommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
+	snapshot := collector.Snapshot()
+
+	// 使用实际测试时间修正吞吐量
+	if duration, ok := snapshot.Protocol["actual_duration"].(time.Duration); ok && duration > 0 {
+		snapshot.Core.Duration = duration
+		seconds := duration.Seconds()
+		snapshot.Core.Throughput.RPS = float64(snapshot.Core.Operations.Total) / seconds
+		snapshot.Core.Throughput.ReadRPS = float64(snapshot.Core.Operations.Read) / seconds
+		snapshot.Core.Throughput.WriteRPS = float64(snapshot.Core.Operations.Write) / seconds
+		snapshot.Core.Throughput.ReadMBps, snapshot.Core.Throughput.WriteMBps = metrics.Bandwidth(snapshot.Core.Operations, duration)
+	}
+
+	fmt.Printf("\n📊 PostgreSQL Performance Metrics:\n")
+	fmt.Printf("=====================================\n")
+	fmt.Printf("  Total Operations: %d\n", snapshot.Core.Operations.Total)
+	fmt.Printf("  Successful: %d\n", snapshot.Core.Operations.Success)
+	fmt.Printf("  Failed: %d\n", snapshot.Core.Operations.Failed)
+	fmt.Printf("  Success Rate: %.2f%%\n", snapshot.Core.Operations.Rate)
+	fmt.Printf("  Latency avg/p95/p99: %v/%v/%v\n",
+		snapshot.Core.Latency.Average, snapshot.Core.Latency.P95, snapshot.Core.Latency.P99)
+	fmt.Printf("  Throughput: %.2f ops/sec\n", snapshot.Core.Throughput.RPS)
+	fmt.Printf("=====================================\n")
+
+	// 存在快照接收器时（如分布式agent模式）交由接收器处理，不生成本地报告
+	if opts.publishSnapshot(snapshot) {
+		return nil
+	}
+
+	report := reporting.ConvertFromMetricsSnapshot(snapshot)
+	reportConfig := reporting.NewStandardReportConfig("postgres")
+	opts.applyToReport(snapshot, report, reportConfig)
+	generator := reporting.NewReportGenerator(reportConfig)
+	if err := generator.Generate(report); err != nil {
+		return err
+	}
+	return opts.resultError(report)
+}
+
+// GetProtocolName 获取协议名称
+func (h *PostgresCommandHandler) GetProtocolName() string {
+	return h.protocolName
+}
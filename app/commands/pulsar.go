@@ -0,0 +1,488 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	pulsarConfig "abc-runner/app/adapters/pulsar/config"
+	"abc-runner/app/adapters/pulsar/operations"
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/reporting"
+)
+
+// PulsarCommandHandler Pulsar命令处理器
+type PulsarCommandHandler struct {
+	protocolName string
+	factory      interfaces.PulsarAdapterFactory
+}
+
+// NewPulsarCommandHandler 创建Pulsar命令处理器
+func NewPulsarCommandHandler(factory interfaces.PulsarAdapterFactory) *PulsarCommandHandler {
+	if factory == nil {
+		panic("pulsarAdapterFactory cannot be nil - dependency injection required")
+	}
+
+	return &PulsarCommandHandler{
+		protocolName: "pulsar",
+		factory:      factory,
+	}
+}
+
+// Execute 执行Pulsar命令
+func (h *PulsarCommandHandler) Execute(ctx context.Context, args []string) error {
+	// 检查帮助请求
+	for i, arg := range args {
+		if arg == "--help" || arg == "help" {
+			fmt.Println(h.GetHelp())
+			return nil
+		}
+		if arg == "-h" && (i == 0 || (i > 0 && args[i-1] != "pulsar")) {
+			if i+1 < len(args) && !looksLikeHostname(args[i+1]) {
+				fmt.Println(h.GetHelp())
+				return nil
+			}
+		}
+	}
+
+	// 解析命令行参数
+	config, err := h.parseArgs(args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	// 解析通用运行选项
+	opts, err := parseRunOptions(ctx, "pulsar", args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+	if printConfigRequested(args) {
+		return printEffectiveConfig("pulsar", config, opts)
+	}
+
+	metricsCollector := metrics.NewBaseCollector(opts.collectorConfig(), map[string]interface{}{
+		"protocol":  "pulsar",
+		"test_type": "performance",
+	})
+	defer metricsCollector.Stop()
+
+	adapter := h.factory.CreatePulsarAdapter()
+	if adapter == nil {
+		return fmt.Errorf("failed to create Pulsar adapter")
+	}
+	defer adapter.Close()
+
+	target := config.Connection.GetServiceURL()
+	if err := adapter.Connect(ctx, config); err != nil {
+		return NewConnectionError(fmt.Errorf("failed to connect to pulsar %s: %w", target, err))
+	}
+	if err := adapter.HealthCheck(ctx); err != nil {
+		return NewConnectionError(fmt.Errorf("pulsar health check failed: %w", err))
+	}
+
+	specific := config.PulsarSpecific
+	fmt.Printf("✅ Connected to Pulsar at %s\n", target)
+	fmt.Printf("🚀 Starting Pulsar performance test...\n")
+	fmt.Printf("Test Case: %s\n", config.BenchMark.TestCase)
+	fmt.Printf("Topic: %s\n", config.GetTopic())
+	if config.BenchMark.TestCase == pulsarConfig.OperationConsume {
+		fmt.Printf("Subscription: %s (%s), Consumers: %d, Receiver Queue: %d\n", specific.Consumer.Subscription,
+			specific.Consumer.SubscriptionType, specific.Consumer.Consumers, specific.Consumer.ReceiverQueueSize)
+	} else {
+		fmt.Printf("Producers: %d, Send Mode: %s, Batching: %s, Compression: %s\n", specific.Producer.Producers,
+			specific.Producer.SendMode, describeBatching(specific.Producer), specific.Producer.Compression)
+	}
+	fmt.Printf("Operations: %d, Concurrency: %d\n", config.BenchMark.Total, config.BenchMark.Parallels)
+
+	if err := h.runPerformanceTest(ctx, adapter, config, metricsCollector, opts); err != nil {
+		return fmt.Errorf("performance test failed: %w", err)
+	}
+
+	return h.generateReport(metricsCollector, opts)
+}
+
+// describeBatching 描述批量发送配置
+func describeBatching(producer pulsarConfig.ProducerConfig) string {
+	if !producer.Batching {
+		return "off"
+	}
+	return fmt.Sprintf("up to %d messages or %v", producer.BatchingMaxMessages, producer.BatchingMaxDelay)
+}
+
+// GetHelp 获取帮助信息
+func (h *PulsarCommandHandler) GetHelp() string {
+	return `Apache Pulsar Performance Testing
+
+USAGE:
+  abc-runner pulsar [options]
+
+DESCRIPTION:
+  Produce to a topic or consume from a subscription and report
+  per-operation latency, end-to-end latency from send to receive, how the
+  subscription type spread messages over the consumers, and the
+  subscription backlog sampled during the run.
+
+  Producers are shared by the concurrent workers in rotation. A consume
+  test subscribes --consumers consumers to the same subscription before the
+  run; the subscription type decides which consumer receives each message.
+
+OPTIONS:
+  --help                        Show this help message
+  --url URL                     pulsar:// or pulsar+ssl:// service URL (overrides
+                                host and port)
+  --host HOST, -h HOST          Broker host (default: localhost)
+  --port PORT, -p PORT          Broker port (default: 6650)
+  --tls                         Connect with TLS (pulsar+ssl)
+  --token TOKEN                 JWT authentication token
+  --admin-url URL               Admin REST API for backlog sampling
+                                (default: http://HOST:8080)
+  --timeout DURATION            Connect, subscribe and send timeout (default: 10s)
+  --test-case CASE              produce or consume (default: produce)
+  --topic TOPIC                 Topic; short names expand to
+                                persistent://public/default/NAME (default: abc_bench)
+  --producers N                 Producers shared by the workers (default: 1)
+  --send-mode MODE              sync (default) or async
+  --max-pending N               Messages awaiting acknowledgement per producer
+                                before an async send blocks (default: 1000)
+  --no-batching                 Send every message in its own request
+  --batch-max-messages N        Messages per batch (default: 1000)
+  --batch-max-delay DURATION    Longest wait before a partial batch is sent
+                                (default: 10ms)
+  --compression CODEC           none (default), lz4, zlib or zstd
+  --keys N                      Rotate over N message keys (default: no key)
+  --message-size BYTES          Random message body size (default: 1024)
+  --payload TEMPLATE            Message body template, e.g. '{"id":"{{uuid}}","seq":{{seq}}}'
+  --data-file FILE              CSV file for {{csv.COLUMN}}; the first row names the columns
+  --subscription NAME           Subscription name (default: abc-bench)
+  --subscription-type TYPE      exclusive, shared (default), failover or key_shared
+  --consumers N                 Consumers on the subscription (default: 1)
+  --receiver-queue N            Messages each consumer prefetches (default: 1000)
+  --initial-position POS        earliest (default) or latest, for a new subscription
+  --receive-timeout DURATION    Longest wait for a message before a consume fails
+                                (default: 5s)
+  --backlog-interval DURATION   Sample the subscription backlog at this interval
+                                (default: off)
+  -n COUNT                      Total operations (default: 1000)
+  -c COUNT                      Concurrent workers (default: 10)
+  --duration DURATION           Run for a fixed duration instead of -n
+
+NOTES:
+  A sync send completes when the broker acknowledges the message. An async
+  send completes once the message is queued; acknowledgements are reported
+  separately as produce_ack and the run waits for all of them before the
+  duration is measured. With batching, send latency includes the time a
+  message waits for its batch.
+  Every message carries its send time, so a consume reports end-to-end
+  latency; run a consume test alongside a produce test on the same topic,
+  or use --prefill N to send N messages before measurement.
+  An exclusive subscription allows one consumer; failover delivers to one
+  active consumer per partition; key_shared keeps each key on one consumer
+  (combine with --keys on the producing side).
+  Backlog sampling reads the subscription's msgBacklog from the admin API;
+  a positive growth rate means consumers are falling behind.
+
+EXAMPLES:
+  abc-runner pulsar --help
+  abc-runner pulsar -h localhost -n 100000 -c 20 --send-mode async --compression lz4
+  abc-runner pulsar -h broker --test-case consume --prefill 100000 -n 100000 \
+    --subscription-type shared --consumers 4 --backlog-interval 1s
+  abc-runner pulsar -h broker --test-case consume --subscription-type key_shared \
+    --consumers 3 --prefill 30000 --keys 100 -n 30000
+  abc-runner pulsar --url pulsar+ssl://broker:6651 --token $TOKEN \
+    --topic persistent://tenant/ns/orders --no-batching --duration 60s` + configLayersHelp + runOptionsHelp + "\n"
+}
+
+// parseArgs 解析命令行参数
+func (h *PulsarCommandHandler) parseArgs(args []string) (*pulsarConfig.PulsarConfig, error) {
+	config := pulsarConfig.NewDefaultPulsarConfig()
+	specific := &config.PulsarSpecific
+
+	// 依次应用--config文件、ABC_PULSAR_*环境变量与--set，专用参数随后覆盖
+	if err := applyConfigLayers("pulsar", args, config); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(args); i++ {
+		// 无参数的开关
+		switch args[i] {
+		case "--tls":
+			config.Connection.TLS = true
+			continue
+		case "--no-batching":
+			specific.Producer.Batching = false
+			continue
+		}
+
+		if i+1 >= len(args) {
+			break
+		}
+		value := args[i+1]
+		var err error
+
+		switch args[i] {
+		case "--url":
+			config.Connection.ServiceURL = value
+		case "--host", "-h":
+			if !looksLikeHostname(value) {
+				continue
+			}
+			config.Connection.Address = value
+		case "--port", "-p":
+			config.Connection.Port, err = strconv.Atoi(value)
+		case "--token":
+			config.Connection.Token = value
+		case "--admin-url":
+			config.Connection.AdminURL = value
+		case "--timeout":
+			config.Connection.Timeout, err = time.ParseDuration(value)
+		case "--test-case":
+			config.BenchMark.TestCase = strings.ToLower(value)
+		case "--topic":
+			specific.Topic = value
+		case "--producers":
+			specific.Producer.Producers, err = strconv.Atoi(value)
+		case "--send-mode":
+			specific.Producer.SendMode = strings.ToLower(value)
+		case "--max-pending":
+			specific.Producer.MaxPending, err = strconv.Atoi(value)
+		case "--batch-max-messages":
+			specific.Producer.BatchingMaxMessages, err = strconv.Atoi(value)
+		case "--batch-max-delay":
+			specific.Producer.BatchingMaxDelay, err = time.ParseDuration(value)
+		case "--compression":
+			specific.Producer.Compression = strings.ToLower(value)
+		case "--keys":
+			specific.Producer.Keys, err = strconv.Atoi(value)
+		case "--message-size":
+			specific.Producer.MessageSize, err = strconv.Atoi(value)
+		case "--payload":
+			specific.Producer.Payload.Template = value
+		case "--data-file":
+			specific.Producer.Payload.DataFile = value
+		case "--subscription":
+			specific.Consumer.Subscription = value
+		case "--subscription-type":
+			specific.Consumer.SubscriptionType = strings.ReplaceAll(strings.ToLower(value), "-", "_")
+		case "--consumers":
+			specific.Consumer.Consumers, err = strconv.Atoi(value)
+		case "--receiver-queue":
+			specific.Consumer.ReceiverQueueSize, err = strconv.Atoi(value)
+		case "--initial-position":
+			specific.Consumer.InitialPosition = strings.ToLower(value)
+		case "--receive-timeout":
+			specific.Consumer.ReceiveTimeout, err = time.ParseDuration(value)
+		case "--backlog-interval":
+			specific.BacklogSampleInterval, err = time.ParseDuration(value)
+		case "-n":
+			config.BenchMark.Total, err = strconv.Atoi(value)
+		case "-c":
+			config.BenchMark.Parallels, err = strconv.Atoi(value)
+		case "--duration":
+			config.BenchMark.Duration, err = time.ParseDuration(value)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", args[i], err)
+		}
+		i++
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// runPerformanceTest 运行Pulsar性能测试
+func (h *PulsarCommandHandler) runPerformanceTest(
+	ctx context.Context,
+	adapter interfaces.ProtocolAdapter,
+	config *pulsarConfig.PulsarConfig,
+	collector *metrics.BaseCollector[map[string]interface{}],
+	opts *runOptions,
+) error {
+	factory := operations.NewOperationFactory(config)
+	engine := execution.NewExecutionEngine(adapter, collector, factory)
+	engine.SetMaxWorkers(config.BenchMark.Parallels)
+
+	// 预填充测试主题（不计入指标）
+	if err := opts.runPrefill(ctx, engine, collector, config.BenchMark.Parallels); err != nil {
+		return err
+	}
+	opts.applyToEngine(engine, collector)
+
+	sampler, _ := adapter.(interface {
+		StartBacklogSampling(ctx context.Context)
+		StopBacklogSampling() *operations.BacklogStats
+	})
+	if sampler != nil {
+		sampler.StartBacklogSampling(ctx)
+	}
+
+	testStartTime := time.Now()
+	result, err := engine.RunBenchmark(ctx, &config.BenchMark)
+	if err != nil {
+		return fmt.Errorf("benchmark execution failed: %w", err)
+	}
+	// async发送的确认计入测试时长
+	if flusher, ok := adapter.(interface{ FlushProducers() error }); ok {
+		if err := flusher.FlushProducers(); err != nil {
+			fmt.Printf("⚠️  Failed to flush producers: %v\n", err)
+		}
+	}
+	opts.finishRun()
+	actualTestDuration := time.Since(testStartTime)
+
+	var backlog *operations.BacklogStats
+	if sampler != nil {
+		backlog = sampler.StopBacklogSampling()
+	}
+
+	fmt.Printf("\n📊 Execution Results:\n")
+	fmt.Printf("Total Jobs: %d\n", result.TotalJobs)
+	fmt.Printf("Completed Jobs: %d\n", result.CompletedJobs)
+	fmt.Printf("Success Jobs: %d\n", result.SuccessJobs)
+	fmt.Printf("Failed Jobs: %d\n", result.FailedJobs)
+	fmt.Printf("Actual Test Duration: %v\n", actualTestDuration)
+
+	specific := config.PulsarSpecific
+	protocolMetrics := map[string]interface{}{
+		"protocol":         "pulsar",
+		"test_type":        "performance",
+		"test_case":        config.BenchMark.TestCase,
+		"target":           config.Connection.GetServiceURL(),
+		"topic":            config.GetTopic(),
+		"actual_duration":  actualTestDuration,
+		"execution_result": result,
+	}
+	if config.BenchMark.TestCase == pulsarConfig.OperationConsume {
+		protocolMetrics["subscription"] = specific.Consumer.Subscription
+		protocolMetrics["subscription_type"] = specific.Consumer.SubscriptionType
+		protocolMetrics["consumers"] = specific.Consumer.Consumers
+	} else {
+		protocolMetrics["producers"] = specific.Producer.Producers
+		protocolMetrics["send_mode"] = specific.Producer.SendMode
+		protocolMetrics["batching"] = specific.Producer.Batching
+		protocolMetrics["compression"] = specific.Producer.Compression
+	}
+
+	if statsAdapter, ok := adapter.(interface {
+		GetOperationStats() []operations.OperationStats
+		GetConsumerStats() []operations.ConsumerStats
+		GetEndToEndLatency() *metrics.LatencyMetrics
+	}); ok {
+		if stats := statsAdapter.GetOperationStats(); len(stats) > 0 {
+			printPulsarOperationStats(stats, actualTestDuration)
+			protocolMetrics["operation_stats"] = stats
+		}
+		if latency := statsAdapter.GetEndToEndLatency(); latency != nil {
+			fmt.Printf("\n⏱️  End-to-End Latency (send to receive): avg %v, p50 %v, p99 %v, max %v\n",
+				latency.Average, latency.P50, latency.P99, latency.Max)
+			protocolMetrics["end_to_end_latency"] = *latency
+		}
+		if stats := statsAdapter.GetConsumerStats(); len(stats) > 0 {
+			printPulsarConsumerStats(stats, specific.Consumer.SubscriptionType)
+			protocolMetrics["consumer_stats"] = stats
+		}
+	}
+	if backlog != nil {
+		printPulsarBacklogStats(backlog)
+		protocolMetrics["backlog"] = *backlog
+	}
+
+	collector.UpdateProtocolMetrics(protocolMetrics)
+	return nil
+}
+
+// printPulsarOperationStats 打印按操作类型的统计表
+func printPulsarOperationStats(stats []operations.OperationStats, duration time.Duration) {
+	fmt.Printf("\n📋 Per-Operation Statistics:\n")
+	fmt.Printf("  %-12s %10s %10s %8s %12s %10s %10s %10s\n",
+		"TYPE", "COUNT", "MSG/S", "ERR%", "MB/S", "P50", "P99", "MAX")
+	for _, s := range stats {
+		fmt.Printf("  %-12s %10d %10.1f %8.2f %12.2f %10v %10v %10v\n",
+			s.Type, s.Count, float64(s.Count)/duration.Seconds(), s.ErrorRate,
+			float64(s.Volume)/duration.Seconds()/1024/1024, s.Latency.P50, s.Latency.P99, s.Latency.Max)
+		if s.Flagged > 0 {
+			fmt.Printf("  %-12s   redelivered: %d\n", "", s.Flagged)
+		}
+		for code, n := range s.ErrorCodes {
+			fmt.Printf("  %-12s   %s: %d\n", "", code, n)
+		}
+	}
+}
+
+// printPulsarConsumerStats 打印各消费者收到的消息数
+func printPulsarConsumerStats(stats []operations.ConsumerStats, subscriptionType string) {
+	fmt.Printf("\n👥 Consumers (%s subscription):\n", subscriptionType)
+	fmt.Printf("  %-24s %10s %8s\n", "CONSUMER", "RECEIVED", "SHARE")
+	for _, s := range stats {
+		fmt.Printf("  %-24s %10d %7.2f%%\n", s.Name, s.Received, s.Share)
+	}
+}
+
+// printPulsarBacklogStats 打印订阅积压采样统计
+func printPulsarBacklogStats(s *operations.BacklogStats) {
+	fmt.Printf("\n📥 Subscription Backlog (%s):\n", s.Subscription)
+	if s.Samples == 0 {
+		fmt.Printf("  sampling failed: %s\n", s.LastError)
+		return
+	}
+	fmt.Printf("  %8s %10s %10s %10s %10s %12s %10s\n",
+		"SAMPLES", "FIRST", "LAST", "MAX", "AVG", "GROWTH/S", "CONSUMERS")
+	fmt.Printf("  %8d %10d %10d %10d %10.1f %12.1f %10d\n",
+		s.Samples, s.First, s.Last, s.Max, s.Average, s.Growth, s.Consumers)
+	if s.Errors > 0 {
+		fmt.Printf("  %d sample(s) failed: %s\n", s.Errors, s.LastError)
+	}
+}
+
+// generateReport 生成Pulsar测试报告
+func (h *PulsarCommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
+	snapshot := collector.Snapshot()
+
+	// 使用实际测试时间修正吞吐量
+	if duration, ok := snapshot.Protocol["actual_duration"].(time.Duration); ok && duration > 0 {
+		snapshot.Core.Duration = duration
+		seconds := duration.Seconds()
+		snapshot.Core.Throughput.RPS = float64(snapshot.Core.Operations.Total) / seconds
+		snapshot.Core.Throughput.ReadRPS = float64(snapshot.Core.Operations.Read) / seconds
+		snapshot.Core.Throughput.WriteRPS = float64(snapshot.Core.Operations.Write) / seconds
+		snapshot.Core.Throughput.ReadMBps, snapshot.Core.Throughput.WriteMBps = metrics.Bandwidth(snapshot.Core.Operations, duration)
+	}
+
+	fmt.Printf("\n📊 Pulsar Performance Metrics:\n")
+	fmt.Printf("=====================================\n")
+	fmt.Printf("  Total Operations: %d\n", snapshot.Core.Operations.Total)
+	fmt.Printf("  Successful: %d\n", snapshot.Core.Operations.Success)
+	fmt.Printf("  Failed: %d\n", snapshot.Core.Operations.Failed)
+	fmt.Printf("  Success Rate: %.2f%%\n", snapshot.Core.Operations.Rate)
+	fmt.Printf("  Latency avg/p95/p99: %v/%v/%v\n",
+		snapshot.Core.Latency.Average, snapshot.Core.Latency.P95, snapshot.Core.Latency.P99)
+	fmt.Printf("  Throughput: %.2f ops/sec\n", snapshot.Core.Throughput.RPS)
+	fmt.Printf("=====================================\n")
+
+	// 存在快照接收器时（如分布式agent模式）交由接收器处理，不生成本地报告
+	if opts.publishSnapshot(snapshot) {
+		return nil
+	}
+
+	report := reporting.ConvertFromMetricsSnapshot(snapshot)
+	reportConfig := reporting.NewStandardReportConfig("pulsar")
+	opts.applyToReport(snapshot, report, reportConfig)
+	generator := reporting.NewReportGenerator(reportConfig)
+	if err := generator.Generate(report); err != nil {
+		return err
+	}
+	return opts.resultError(report)
+}
+
+// GetProtocolName 获取协议名称
+func (h *PulsarCommandHandler) GetProtocolName() string {
+	return h.protocolName
+}
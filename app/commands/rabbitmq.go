@@ -0,0 +1,504 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	rabbitConfig "abc-runner/app/adapters/rabbitmq/config"
+	"abc-runner/app/adapters/rabbitmq/connection"
+	"abc-runner/app/adapters/rabbitmq/operations"
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/reporting"
+)
+
+// RabbitMQCommandHandler RabbitMQ命令处理器
+type RabbitMQCommandHandler struct {
+	protocolName string
+	factory      interfaces.RabbitMQAdapterFactory
+}
+
+// NewRabbitMQCommandHandler 创建RabbitMQ命令处理器
+func NewRabbitMQCommandHandler(factory interfaces.RabbitMQAdapterFactory) *RabbitMQCommandHandler {
+	if factory == nil {
+		panic("rabbitMQAdapterFactory cannot be nil - dependency injection required")
+	}
+
+	return &RabbitMQCommandHandler{
+		protocolName: "rabbitmq",
+		factory:      factory,
+	}
+}
+
+// Execute 执行RabbitMQ命令
+func (h *RabbitMQCommandHandler) Execute(ctx context.Context, args []string) error {
+	// 检查帮助请求
+	for i, arg := range args {
+		if arg == "--help" || arg == "help" {
+			fmt.Println(h.GetHelp())
+			return nil
+		}
+		if arg == "-h" && (i == 0 || (i > 0 && args[i-1] != "rabbitmq")) {
+			if i+1 < len(args) && !looksLikeHostname(args[i+1]) {
+				fmt.Println(h.GetHelp())
+				return nil
+			}
+		}
+	}
+
+	// 解析命令行参数
+	config, err := h.parseArgs(args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	// 解析通用运行选项
+	opts, err := parseRunOptions(ctx, "rabbitmq", args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+	if printConfigRequested(args) {
+		return printEffectiveConfig("rabbitmq", config, opts)
+	}
+
+	metricsCollector := metrics.NewBaseCollector(opts.collectorConfig(), map[string]interface{}{
+		"protocol":  "rabbitmq",
+		"test_type": "performance",
+	})
+	defer metricsCollector.Stop()
+
+	adapter := h.factory.CreateRabbitMQAdapter()
+	if adapter == nil {
+		return fmt.Errorf("failed to create RabbitMQ adapter")
+	}
+	defer adapter.Close()
+
+	target := config.Connection.GetAddresses()[0]
+	if err := adapter.Connect(ctx, config); err != nil {
+		return NewConnectionError(fmt.Errorf("failed to connect to rabbitmq %s: %w", target, err))
+	}
+	if err := adapter.HealthCheck(ctx); err != nil {
+		return NewConnectionError(fmt.Errorf("rabbitmq health check failed: %w", err))
+	}
+
+	specific := config.RabbitMQSpecific
+	if rabbitAdapter, ok := adapter.(interface{ ServerVersion() string }); ok {
+		fmt.Printf("✅ Connected to RabbitMQ %s at %s (%d connection(s), %d channel(s))\n",
+			rabbitAdapter.ServerVersion(), target, config.Connection.Connections, config.BenchMark.Parallels)
+	}
+
+	fmt.Printf("🚀 Starting RabbitMQ performance test...\n")
+	fmt.Printf("Test Case: %s\n", config.BenchMark.TestCase)
+	exchange := specific.Exchange.Name
+	if exchange == "" {
+		exchange = "(default)"
+	}
+	fmt.Printf("Exchange: %s (%s), Queues: %s\n", exchange, specific.Exchange.Type, strings.Join(specific.Queue.Names, ", "))
+	if config.BenchMark.TestCase == rabbitConfig.OperationConsume {
+		fmt.Printf("Ack Mode: %s, Prefetch: %d\n", describeAckMode(specific.Consumer), specific.Consumer.Prefetch)
+	} else {
+		fmt.Printf("Publisher Confirms: %v, Persistent: %v, Mandatory: %v\n",
+			specific.Publisher.Confirms, specific.Publisher.Persistent, specific.Publisher.Mandatory)
+	}
+	fmt.Printf("Operations: %d, Concurrency: %d\n", config.BenchMark.Total, config.BenchMark.Parallels)
+
+	if err := h.runPerformanceTest(ctx, adapter, config, metricsCollector, opts); err != nil {
+		return fmt.Errorf("performance test failed: %w", err)
+	}
+
+	return h.generateReport(metricsCollector, opts)
+}
+
+// describeAckMode 描述确认模式，batch模式附带批大小
+func describeAckMode(consumer rabbitConfig.ConsumerConfig) string {
+	if consumer.AckMode == rabbitConfig.AckModeBatch {
+		return fmt.Sprintf("batch of %d", consumer.AckBatch)
+	}
+	return consumer.AckMode
+}
+
+// GetHelp 获取帮助信息
+func (h *RabbitMQCommandHandler) GetHelp() string {
+	return `RabbitMQ (AMQP 0.9.1) Performance Testing
+
+USAGE:
+  abc-runner rabbitmq [options]
+
+DESCRIPTION:
+  Publish to an exchange or consume from queues over AMQP 0.9.1 and report
+  per-operation latency, publisher confirm and consumer ack behaviour, and
+  the depth of each queue sampled during the run.
+
+  Each concurrent worker owns one channel; channels are spread over
+  --connections TCP connections. Exchanges and queues are declared and
+  bound before the run unless --no-declare is given. Without a routing key
+  each queue is bound with its own name and publishes rotate over the
+  queues; consumers are assigned to the queues in the same rotation.
+
+OPTIONS:
+  --help                     Show this help message
+  --uri URI                  amqp:// or amqps:// connection string (overrides host,
+                             port, credentials and vhost)
+  --host HOST, -h HOST       Broker host (default: localhost)
+  --port PORT, -p PORT       Broker port (default: 5672)
+  --username USER, -u USER   User name (default: guest)
+  --password PASSWORD        Password (default: guest)
+  --vhost VHOST              Virtual host (default: /)
+  --tls                      Connect with TLS (amqps)
+  --connections N            TCP connections to spread the channels over (default: 1)
+  --heartbeat DURATION       Heartbeat interval (default: 10s)
+  --timeout DURATION         Connect and handshake timeout (default: 5s)
+  --test-case CASE           publish or consume (default: publish)
+  --exchange NAME            Exchange to publish to; "" uses the default exchange
+                             (default: abc_bench)
+  --exchange-type TYPE       direct (default), fanout, topic or headers
+  --queues Q1,Q2             Test queues (default: abc_bench)
+  --queue-type TYPE          classic or quorum (quorum requires --durable)
+  --max-length N             Queue x-max-length (default: unlimited)
+  --routing-key KEY          Publish and bind with this key instead of the queue names
+  --durable                  Declare durable exchange and queues
+  --no-declare               Use existing exchange, queues and bindings
+  --purge                    Purge the queues before the run (before --prefill)
+  --no-confirms              Publish without waiting for publisher confirms
+  --persistent               Publish persistent messages (delivery mode 2)
+  --mandatory                Count messages the broker returns as unroutable
+  --message-size BYTES       Random message body size (default: 1024)
+  --payload TEMPLATE         Message body template, e.g. '{"id":"{{uuid}}","seq":{{seq}}}'
+  --data-file FILE           CSV file for {{csv.COLUMN}}; the first row names the columns
+  --ack-mode MODE            auto (no-ack), manual (default) or batch
+  --ack-batch N              Messages per multiple ack in batch mode (default: 10)
+  --prefetch N               Unacknowledged messages per channel, 0 = unlimited
+                             (default: 100)
+  --receive-timeout DURATION Longest wait for a message before a consume fails
+                             (default: 5s)
+  --depth-interval DURATION  Sample queue depths at this interval during the run
+                             (default: off)
+  -n COUNT                   Total operations (default: 1000)
+  -c COUNT                   Concurrent workers and channels (default: 10)
+  --duration DURATION        Run for a fixed duration instead of -n
+
+NOTES:
+  With publisher confirms (the default) a publish completes when the broker
+  confirms it and a nack counts as a failure. A consume waits for one
+  delivery and acks it according to --ack-mode; batch mode acks every
+  --ack-batch deliveries with multiple=true and flushes the rest on exit.
+  A consume test needs messages in the queues: use --prefill N to publish
+  N messages before measurement, or run a publish test alongside it.
+  Queue depth is the broker's ready-message count; a positive growth rate
+  means consumers are falling behind.
+
+EXAMPLES:
+  abc-runner rabbitmq --help
+  abc-runner rabbitmq -h localhost -n 100000 -c 20 --persistent --durable
+  abc-runner rabbitmq -h mq --test-case consume --prefill 100000 -n 100000 \
+    --ack-mode batch --ack-batch 50 --prefetch 200 --depth-interval 1s
+  abc-runner rabbitmq --uri amqp://user:pass@mq:5672/orders --exchange events \
+    --exchange-type topic --routing-key order.created --queues audit,billing --no-declare
+  abc-runner rabbitmq -h mq --queues q1,q2,q3 --queue-type quorum --durable \
+    --duration 60s --depth-interval 500ms` + configLayersHelp + runOptionsHelp + "\n"
+}
+
+// parseArgs 解析命令行参数
+func (h *RabbitMQCommandHandler) parseArgs(args []string) (*rabbitConfig.RabbitMQConfig, error) {
+	config := rabbitConfig.NewDefaultRabbitMQConfig()
+	specific := &config.RabbitMQSpecific
+
+	// 依次应用--config文件、ABC_RABBITMQ_*环境变量与--set，专用参数随后覆盖
+	if err := applyConfigLayers("rabbitmq", args, config); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(args); i++ {
+		// 无参数的开关
+		switch args[i] {
+		case "--tls":
+			config.Connection.TLS = true
+			continue
+		case "--durable":
+			specific.Exchange.Durable = true
+			specific.Queue.Durable = true
+			continue
+		case "--no-declare":
+			specific.Exchange.Declare = false
+			specific.Queue.Declare = false
+			continue
+		case "--purge":
+			specific.Queue.Purge = true
+			continue
+		case "--no-confirms":
+			specific.Publisher.Confirms = false
+			continue
+		case "--persistent":
+			specific.Publisher.Persistent = true
+			continue
+		case "--mandatory":
+			specific.Publisher.Mandatory = true
+			continue
+		}
+
+		if i+1 >= len(args) {
+			break
+		}
+		value := args[i+1]
+		var err error
+
+		switch args[i] {
+		case "--uri":
+			config.Connection.URI = value
+		case "--host", "-h":
+			if !looksLikeHostname(value) {
+				continue
+			}
+			config.Connection.Address = value
+		case "--port", "-p":
+			config.Connection.Port, err = strconv.Atoi(value)
+		case "--username", "-u":
+			config.Connection.Username = value
+		case "--password":
+			config.Connection.Password = value
+		case "--vhost":
+			config.Connection.VHost = value
+		case "--connections":
+			config.Connection.Connections, err = strconv.Atoi(value)
+		case "--heartbeat":
+			config.Connection.Heartbeat, err = time.ParseDuration(value)
+		case "--timeout":
+			config.Connection.Timeout, err = time.ParseDuration(value)
+		case "--test-case":
+			config.BenchMark.TestCase = strings.ToLower(value)
+		case "--exchange":
+			specific.Exchange.Name = value
+		case "--exchange-type":
+			specific.Exchange.Type = strings.ToLower(value)
+		case "--queues":
+			specific.Queue.Names = nil
+			for _, name := range strings.Split(value, ",") {
+				specific.Queue.Names = append(specific.Queue.Names, strings.TrimSpace(name))
+			}
+		case "--queue-type":
+			specific.Queue.Type = strings.ToLower(value)
+		case "--max-length":
+			specific.Queue.MaxLength, err = strconv.Atoi(value)
+		case "--routing-key":
+			specific.RoutingKey = value
+		case "--message-size":
+			specific.Publisher.MessageSize, err = strconv.Atoi(value)
+		case "--payload":
+			specific.Publisher.Payload.Template = value
+		case "--data-file":
+			specific.Publisher.Payload.DataFile = value
+		case "--ack-mode":
+			specific.Consumer.AckMode = strings.ToLower(value)
+		case "--ack-batch":
+			specific.Consumer.AckBatch, err = strconv.Atoi(value)
+		case "--prefetch":
+			specific.Consumer.Prefetch, err = strconv.Atoi(value)
+		case "--receive-timeout":
+			specific.Consumer.ReceiveTimeout, err = time.ParseDuration(value)
+		case "--depth-interval":
+			specific.DepthSampleInterval, err = time.ParseDuration(value)
+		case "-n":
+			config.BenchMark.Total, err = strconv.Atoi(value)
+		case "-c":
+			config.BenchMark.Parallels, err = strconv.Atoi(value)
+		case "--duration":
+			config.BenchMark.Duration, err = time.ParseDuration(value)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", args[i], err)
+		}
+		i++
+	}
+
+	// 默认交换机不可声明，--exchange ""时同时关闭交换机声明
+	if specific.Exchange.Name == "" {
+		specific.Exchange.Declare = false
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// runPerformanceTest 运行RabbitMQ性能测试
+func (h *RabbitMQCommandHandler) runPerformanceTest(
+	ctx context.Context,
+	adapter interfaces.ProtocolAdapter,
+	config *rabbitConfig.RabbitMQConfig,
+	collector *metrics.BaseCollector[map[string]interface{}],
+	opts *runOptions,
+) error {
+	factory := operations.NewOperationFactory(config)
+	engine := execution.NewExecutionEngine(adapter, collector, factory)
+	engine.SetMaxWorkers(config.BenchMark.Parallels)
+
+	// 预填充测试队列（不计入指标）
+	if err := opts.runPrefill(ctx, engine, collector, config.BenchMark.Parallels); err != nil {
+		return err
+	}
+	opts.applyToEngine(engine, collector)
+
+	sampler, _ := adapter.(interface {
+		StartDepthSampling(ctx context.Context)
+		StopDepthSampling() []operations.QueueDepthStats
+	})
+	if sampler != nil {
+		sampler.StartDepthSampling(ctx)
+	}
+
+	testStartTime := time.Now()
+	result, err := engine.RunBenchmark(ctx, &config.BenchMark)
+	if err != nil {
+		return fmt.Errorf("benchmark execution failed: %w", err)
+	}
+	opts.finishRun()
+	actualTestDuration := time.Since(testStartTime)
+
+	var depthStats []operations.QueueDepthStats
+	if sampler != nil {
+		depthStats = sampler.StopDepthSampling()
+	}
+
+	fmt.Printf("\n📊 Execution Results:\n")
+	fmt.Printf("Total Jobs: %d\n", result.TotalJobs)
+	fmt.Printf("Completed Jobs: %d\n", result.CompletedJobs)
+	fmt.Printf("Success Jobs: %d\n", result.SuccessJobs)
+	fmt.Printf("Failed Jobs: %d\n", result.FailedJobs)
+	fmt.Printf("Actual Test Duration: %v\n", actualTestDuration)
+
+	specific := config.RabbitMQSpecific
+	protocolMetrics := map[string]interface{}{
+		"protocol":           "rabbitmq",
+		"test_type":          "performance",
+		"test_case":          config.BenchMark.TestCase,
+		"target":             config.Connection.GetAddresses()[0],
+		"exchange":           specific.Exchange.Name,
+		"exchange_type":      specific.Exchange.Type,
+		"queues":             specific.Queue.Names,
+		"publisher_confirms": specific.Publisher.Confirms,
+		"persistent":         specific.Publisher.Persistent,
+		"actual_duration":    actualTestDuration,
+		"execution_result":   result,
+	}
+	if config.BenchMark.TestCase == rabbitConfig.OperationConsume {
+		protocolMetrics["ack_mode"] = specific.Consumer.AckMode
+		protocolMetrics["prefetch"] = specific.Consumer.Prefetch
+	}
+
+	if statsAdapter, ok := adapter.(interface {
+		GetOperationStats() []operations.OperationStats
+		GetClientStats() *connection.ClientStats
+		ServerVersion() string
+	}); ok {
+		protocolMetrics["server_version"] = statsAdapter.ServerVersion()
+		if stats := statsAdapter.GetOperationStats(); len(stats) > 0 {
+			printRabbitMQOperationStats(stats, actualTestDuration)
+			protocolMetrics["operation_stats"] = stats
+		}
+		if client := statsAdapter.GetClientStats(); client != nil {
+			fmt.Printf("Client: %d connection(s), %d channel(s)", client.Connections, client.Channels)
+			if client.Returned > 0 {
+				fmt.Printf(", %d message(s) returned unroutable", client.Returned)
+			}
+			if client.Blocked > 0 {
+				fmt.Printf(", publishing blocked %d time(s) by a resource alarm", client.Blocked)
+			}
+			fmt.Println()
+			protocolMetrics["client_stats"] = *client
+		}
+	}
+	if len(depthStats) > 0 {
+		printQueueDepthStats(depthStats)
+		protocolMetrics["queue_depth"] = depthStats
+	}
+
+	collector.UpdateProtocolMetrics(protocolMetrics)
+	return nil
+}
+
+// printRabbitMQOperationStats 打印按操作类型的统计表
+func printRabbitMQOperationStats(stats []operations.OperationStats, duration time.Duration) {
+	fmt.Printf("\n📋 Per-Operation Statistics:\n")
+	fmt.Printf("  %-10s %10s %10s %8s %12s %10s %10s %10s\n",
+		"TYPE", "COUNT", "MSG/S", "ERR%", "MB/S", "P50", "P99", "MAX")
+	for _, s := range stats {
+		fmt.Printf("  %-10s %10d %10.1f %8.2f %12.2f %10v %10v %10v\n",
+			s.Type, s.Count, float64(s.Count)/duration.Seconds(), s.ErrorRate,
+			float64(s.Volume)/duration.Seconds()/1024/1024, s.Latency.P50, s.Latency.P99, s.Latency.Max)
+		if s.Flagged > 0 {
+			fmt.Printf("  %-10s   redelivered: %d\n", "", s.Flagged)
+		}
+		for code, n := range s.ErrorCodes {
+			fmt.Printf("  %-10s   %s: %d\n", "", code, n)
+		}
+	}
+}
+
+// printQueueDepthStats 打印队列深度采样统计
+func printQueueDepthStats(stats []operations.QueueDepthStats) {
+	fmt.Printf("\n📥 Queue Depth (ready messages):\n")
+	fmt.Printf("  %-20s %8s %10s %10s %10s %10s %12s %10s\n",
+		"QUEUE", "SAMPLES", "FIRST", "LAST", "MAX", "AVG", "GROWTH/S", "CONSUMERS")
+	for _, s := range stats {
+		if s.Samples == 0 {
+			fmt.Printf("  %-20s sampling failed: %s\n", s.Queue, s.LastError)
+			continue
+		}
+		fmt.Printf("  %-20s %8d %10d %10d %10d %10.1f %12.1f %10d\n",
+			s.Queue, s.Samples, s.First, s.Last, s.Max, s.Average, s.Growth, s.Consumers)
+		if s.Errors > 0 {
+			fmt.Printf("  %-20s   %d sample(s) failed: %s\n", "", s.Errors, s.LastError)
+		}
+	}
+}
+
+// generateReport 生成RabbitMQ测试报告
+func (h *RabbitMQCommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
+	snapshot := collector.Snapshot()
+
+	// 使用实际测试时间修正吞吐量
+	if duration, ok := snapshot.Protocol["actual_duration"].(time.Duration); ok && duration > 0 {
+		snapshot.Core.Duration = duration
+		seconds := duration.Seconds()
+		snapshot.Core.Throughput.RPS = float64(snapshot.Core.Operations.Total) / seconds
+		snapshot.Core.Throughput.ReadRPS = float64(snapshot.Core.Operations.Read) / seconds
+		snapshot.Core.Throughput.WriteRPS = float64(snapshot.Core.Operations.Write) / seconds
+		snapshot.Core.Throughput.ReadMBps, snapshot.Core.Throughput.WriteMBps = metrics.Bandwidth(snapshot.Core.Operations, duration)
+	}
+
+	fmt.Printf("\n📊 RabbitMQ Performance Metrics:\n")
+	fmt.Printf("=====================================\n")
+	fmt.Printf("  Total Operations: %d\n", snapshot.Core.Operations.Total)
+	fmt.Printf("  Successful: %d\n", snapshot.Core.Operations.Success)
+	fmt.Printf("  Failed: %d\n", snapshot.Core.Operations.Failed)
+	fmt.Printf("  Success Rate: %.2f%%\n", snapshot.Core.Operations.Rate)
+	fmt.Printf("  Latency avg/p95/p99: %v/%v/%v\n",
+		snapshot.Core.Latency.Average, snapshot.Core.Latency.P95, snapshot.Core.Latency.P99)
+	fmt.Printf("  Throughput: %.2f ops/sec\n", snapshot.Core.Throughput.RPS)
+	fmt.Printf("=====================================\n")
+
+	// 存在快照接收器时（如分布式agent模式）交由接收器处理，不生成本地报告
+	if opts.publishSnapshot(snapshot) {
+		return nil
+	}
+
+	report := reporting.ConvertFromMetricsSnapshot(snapshot)
+	reportConfig := reporting.NewStandardReportConfig("rabbitmq")
+	opts.applyToReport(snapshot, report, reportConfig)
+	generator := reporting.NewReportGenerator(reportConfig)
+	if err := generator.Generate(report); err != nil {
+		return err
+	}
+	return opts.resultError(report)
+}
+
+// GetProtocolName 获取协议名称
+func (h *RabbitMQCommandHandler) GetProtocolName() string {
+	return h.protocolName
+}
@@ -10,8 +10,12 @@ import (
 	"abc-runner/app/reporting"
 	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"sort"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 )
 
@@ -52,36 +56,85 @@ func (r *RedisCommandHandler) Execute(ctx context.Context, args []string) error
 	// 解析命令行参数
 	config, err := r.parseArgs(args)
 	if err != nil {
-		return fmt.Errorf("failed to parse arguments: %w", err)
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+	// 解析通用运行选项
+	opts, err := parseRunOptions(ctx, "redis", args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+	if printConfigRequested(args) {
+		return printEffectiveConfig("redis", config, opts)
 	}
 	// 创建Redis适配器
-	metricsConfig := metrics.DefaultMetricsConfig()
+	metricsConfig := opts.collectorConfig()
 	metricsCollector := metrics.NewBaseCollector(metricsConfig, map[string]interface{}{
 		"protocol":  "redis",
 		"test_type": "performance",
 	})
 	defer metricsCollector.Stop()
+	// 代理模式：不生成负载，记录真实客户端经过代理的命令延迟
+	if config.Proxy.Enabled() {
+		return r.runProxy(ctx, config, metricsCollector, opts)
+	}
 	// 直接使用MetricsCollector创建Redis适配器
 	adapter := redis.NewRedisAdapter(metricsCollector)
 	// 连接并执行测试
+	target := config.Standalone.Addr
+	if config.Mode == "sentinel" {
+		target = fmt.Sprintf("master %s via sentinels %s", config.Sentinel.MasterName, strings.Join(config.Sentinel.Addrs, ","))
+	}
+	if config.TLS.Enabled {
+		target += " over TLS"
+		if config.TLS.CertFile != "" {
+			target += " (mutual)"
+		}
+	}
 	if err := adapter.Connect(ctx, config); err != nil {
-		fmt.Printf("⚠️  Connection failed to %s (DB: %d): %v\n", config.Standalone.Addr, config.Standalone.Db, err)
+		fmt.Printf("⚠️  Connection failed to %s (DB: %d): %v\n", target, config.Standalone.Db, err)
 		fmt.Printf("🔍 Possible causes: Redis server not running, wrong host/port, authentication failure, or network issues\n")
 		// 继续执行，但使用模拟模式
 	} else {
-		fmt.Printf("✅ Successfully connected to Redis at %s (DB: %d)\n", config.Standalone.Addr, config.Standalone.Db)
+		fmt.Printf("✅ Successfully connected to Redis at %s (DB: %d)\n", target, config.Standalone.Db)
 	}
 	defer adapter.Close()
 	// 执行性能测试
 	fmt.Printf("🚀 Starting Redis performance test...\n")
-	fmt.Printf("Target: %s (DB: %d)\n", config.Standalone.Addr, config.Standalone.Db)
+	fmt.Printf("Target: %s (DB: %d)\n", target, config.Standalone.Db)
 	fmt.Printf("Operations: %d, Concurrency: %d\n", config.BenchMark.Total, config.BenchMark.Parallels)
-	err = r.runPerformanceTest(ctx, adapter, config, metricsCollector)
+	if pipeline := config.BenchMark.GetPipeline(); pipeline > 1 {
+		fmt.Printf("Pipeline: %d commands per round-trip\n", pipeline)
+	}
+	if config.BenchMark.Verify {
+		fmt.Printf("Verify: checksummed values, GET/HGET results are validated\n")
+	}
+	if config.Script.Enabled() {
+		fmt.Printf("Script: %s, KEYS=%v, ARGV=%v\n", config.Script.File, config.Script.Keys, config.Script.Args)
+	}
+	if failover := config.BenchMark.Failover; failover.After > 0 {
+		fmt.Printf("Failover: SENTINEL FAILOVER %s after %v\n", config.Sentinel.MasterName, failover.After)
+	} else if failover.Track {
+		fmt.Printf("Failover: tracking master switches of %s\n", config.Sentinel.MasterName)
+	}
+	if config.BenchMark.IsLeaderboard() {
+		leaderboard := config.BenchMark.Leaderboard
+		fmt.Printf("Leaderboard: %d board(s) x %d members (%s), top %d, %d%% rank queries, %d%% of updates ZADD\n",
+			leaderboard.GetBoards(), leaderboard.GetMembers(), leaderboard.MemberDistribution(), leaderboard.GetTopN(),
+			config.BenchMark.ReadPercent, leaderboard.AddPercent)
+	}
+	if notifications := config.BenchMark.Notifications; notifications.Enabled {
+		db := config.Standalone.Db
+		if config.Mode == "sentinel" {
+			db = config.Sentinel.Db
+		}
+		fmt.Printf("Notifications: subscribed to __keyspace@%d__:*, timeout %v\n", db, notifications.GetTimeout())
+	}
+	err = r.runPerformanceTest(ctx, adapter, config, metricsCollector, opts)
 	if err != nil {
 		return fmt.Errorf("performance test failed: %w", err)
 	}
 	// 生成并显示报告
-	return r.generateReport(metricsCollector)
+	return r.generateReport(metricsCollector, opts)
 }
 
 // GetHelp 获取帮助信息
@@ -99,15 +152,138 @@ OPTIONS:
   --auth PASSWORD Redis password
   -n COUNT        Number of operations (default: 1000)
   -c COUNT        Concurrent connections (default: 10)
+  -r KEYSPACE     Use keys from a keyspace of KEYSPACE keys
+  --read-percent P  Percentage of GET operations (default: 50)
+  --key-distribution D  How keys are picked from the -r keyspace:
+                  sequential (default, cycles through the keyspace in order),
+                  uniform, zipfian (a few keys get most of the traffic) or
+                  gaussian (traffic concentrated around a hot spot)
+  --zipf-skew S   Zipfian skew, between 0 and 1 (default: 0.99)
+  --gaussian-mean M    Hot spot position as a fraction of the keyspace (default: 0.5)
+  --gaussian-stddev S  Hot spot width as a fraction of the keyspace (default: 0.05)
+  --pipeline N, -P N  Send N commands per round-trip using a go-redis pipeline
+                  (default: 1, no pipelining). -n still counts commands; each
+                  operation in the report is one batch, and per-batch and
+                  per-command (batch latency / N) latencies are reported
+                  separately. Not supported with --resp3.
+  --verify        Data verification: SET/HSET write values carrying the key and
+                  a CRC32C checksum, GET/HGET check what comes back. Corrupted
+                  values and values belonging to another key are counted
+                  separately and do not fail the operation; missing keys and
+                  values written without --verify are reported as such. Values
+                  are padded to data_size but never shorter than the header.
+  --value-template T  Generate SET values from template T
+                  instead of data_size random bytes. Placeholders: {{seq}}
+                  (operation number), {{uuid}}, {{timestamp [s|ms|ns|rfc3339]}},
+                  {{randInt [MIN MAX]}}, {{randFloat [MIN MAX]}},
+                  {{randString [N]}}, {{pick A B C}}, {{name}}, {{email}} and
+                  {{csv.COLUMN}} (see --data-file). Ignored with --verify.
+  --data-file FILE  CSV file for {{csv.COLUMN}}; the first row names the
+                  columns, operation N uses row N modulo the row count
+
+LUA SCRIPTS (EVAL/EVALSHA):
+  --script FILE         Benchmark a Lua script: every operation runs FILE instead
+                        of GET/SET. The script is registered with SCRIPT LOAD at
+                        startup and invoked with EVALSHA (EVAL if the server
+                        script cache was flushed). Not supported with --resp3.
+  --script-key T        KEYS template (repeatable, in order)
+  --script-arg T        ARGV template (repeatable, in order)
+  --script-eval         Send the full script with EVAL on every call
+  --script-read-only    Count script calls as reads (default: writes)
+                        Templates may use {id} (operation number), {key} (key
+                        from -r keyspace), {value} (data_size bytes) and {rand}.
+
+SENTINEL AND FAILOVER TESTING:
+  --sentinel-addrs LIST        Comma-separated sentinel addresses; switches to
+                               sentinel mode (--auth and --db apply to the master)
+  --sentinel-master-name NAME  Monitored master name (default: mymaster)
+  --sentinel-auth PASSWORD     Password of the sentinels themselves
+  --failover-after DUR         Force a failover with SENTINEL FAILOVER this long
+                               after measurement starts
+  --track-failover             Track an externally caused failover (e.g. killing
+                               the master) without triggering one
+                               Failover tracking records master switches
+                               (+switch-master), reconnects, the error spike
+                               (first to last failed command) and the time to
+                               recover (failover start to first success after
+                               the last error).
+
+LEADERBOARD PRESET (--preset leaderboard):
+  Sorted-set leaderboards (leaderboard:N) with players picked with a zipfian
+  distribution: score updates are ZINCRBY, or ZADD with a new score, and rank
+  queries read the top entries with ZREVRANGE ... WITHSCORES. --read-percent
+  is the share of rank queries; update and rank-query latencies are reported
+  separately. Use --prefill (boards x members) to populate the boards first.
+  Not supported with --resp3, --pipeline or --script.
+  --boards N            Number of leaderboards (default: 1)
+  --members N           Players per leaderboard (default: 10000)
+  --top-n N             Entries returned by a rank query (default: 10)
+  --zadd-percent P      Share of score updates sent as ZADD (default: 10)
+  --zipf-skew S         Also sets the player skew (default: 0.99)
+
+KEYSPACE NOTIFICATIONS (standalone and sentinel, RESP2):
+  --keyspace-notify     Subscribe to __keyspace@<db>__:* while the write
+                        workload runs and pair each notification with the
+                        write that caused it (SET, INCR/DECR, HSET, LPUSH,
+                        RPUSH); reports delivery latency, late and lost
+                        notifications. Requires notify-keyspace-events to
+                        contain K, $, h and l (or A).
+  --configure-notify    Enable the missing classes with CONFIG SET for the
+                        run and restore the original value afterwards
+                        (implies --keyspace-notify)
+  --notify-timeout DUR  Count a notification as lost when it has not arrived
+                        this long after the write (default: 1s)
+
+PROXY MODE (observe a real application instead of generating load):
+  --proxy ADDR          Listen on ADDR and forward every connection to the
+                        Redis given by --host/--port (TLS options apply to the
+                        upstream side); the per-command latency seen by the
+                        clients is recorded and reported like a benchmark,
+                        with the command name as the operation type
+  --proxy-duration DUR  Stop after DUR (default: run until interrupted)
+  --inject-latency DUR  Delay every reply by DUR before passing it on, to see
+                        how the application copes with a slower Redis
+                        Replies after SUBSCRIBE/PSUBSCRIBE/SSUBSCRIBE/MONITOR
+                        are passed through untimed.
+
+TLS (managed / TLS-terminating endpoints, all modes including sentinels):
+  --tls                 Connect over TLS (implied by the options below)
+  --cacert FILE         CA certificate used to verify the server (default:
+                        system roots)
+  --cert FILE           Client certificate for mutual TLS (requires --key)
+  --key FILE            Client private key for mutual TLS
+  --sni NAME            Server name for SNI and certificate verification
+                        (default: host part of the address)
+  --insecure            Skip server certificate verification
+
+RESP3 / CLIENT-SIDE CACHING (standalone only, Redis 6.0+):
+  --resp3               Use the RESP3 protocol
+  --client-tracking     Enable client-side caching with CLIENT TRACKING (implies --resp3)
+  --tracking-bcast      Use broadcasting mode for invalidation messages
+  --tracking-prefix P   Key prefix to track in broadcasting mode (repeatable)
+  --tracking-noloop     Do not receive invalidations for the client's own writes
   
 EXAMPLES:
   abc-runner redis --help
   abc-runner redis --host localhost --port 6379
   abc-runner redis --host localhost --auth mypassword
   abc-runner redis -h localhost -a pwd@redis -n 100 -c 2
+  abc-runner redis --client-tracking -r 1000 --read-percent 90 -n 100000
+  abc-runner redis --pipeline 16 -n 1000000 -c 50
+  abc-runner redis --sentinel-addrs s1:26379,s2:26379,s3:26379 --failover-after 10s -n 1000000
+  abc-runner redis --preset leaderboard --boards 4 --members 100000 --prefill 400000 --read-percent 30 -n 1000000
+  abc-runner redis --keyspace-notify --configure-notify -t set,incr -r 10000 -n 100000
+  abc-runner redis --host proxy --verify -r 10000 --read-percent 80 -n 100000
+  abc-runner redis -n 100000 --value-template '{"id":"{{uuid}}","user":"{{csv.user}}","ts":{{timestamp}}}' \
+    --data-file users.csv
+  abc-runner redis --proxy :6380 --host localhost --port 6379 --inject-latency 2ms
+  abc-runner redis --host cache.example.com --port 6380 --tls --cacert ca.pem \
+    --cert client.pem --key client-key.pem -a secret
+  abc-runner redis --client-tracking -r 100000 --key-distribution zipfian --read-percent 95
+  abc-runner redis --script config/examples/rate_limit.lua --script-key 'rl:{key}' \
+    --script-arg 100 --script-arg 60 -r 1000 -n 100000
 NOTE: 
-  This implementation performs real Redis performance testing with metrics collection.
-`
+  This implementation performs real Redis performance testing with metrics collection.` + configLayersHelp + runOptionsHelp + "\n"
 }
 
 // parseArgs 解析命令行参数
@@ -120,6 +296,11 @@ func (r *RedisCommandHandler) parseArgs(args []string) (*redisConfig.RedisConfig
 	config.BenchMark.Total = 1000
 	config.BenchMark.Parallels = 10
 	config.Pool.ConnectionTimeout = 30 * time.Second
+	// 依次应用--config文件、ABC_REDIS_*环境变量与--set，专用参数随后覆盖
+	if err := applyConfigLayers("redis", args, config); err != nil {
+		return nil, err
+	}
+
 	// 解析参数
 	for i := 0; i < len(args); i++ {
 		switch args[i] {
@@ -169,22 +350,263 @@ func (r *RedisCommandHandler) parseArgs(args []string) (*redisConfig.RedisConfig
 				}
 				i++
 			}
+		case "-r":
+			if i+1 < len(args) {
+				if keys, err := strconv.Atoi(args[i+1]); err == nil {
+					config.BenchMark.RandomKeys = keys
+				}
+				i++
+			}
+		case "--read-percent":
+			if i+1 < len(args) {
+				if percent, err := strconv.Atoi(args[i+1]); err == nil {
+					config.BenchMark.ReadPercent = percent
+				}
+				i++
+			}
+		case "--preset":
+			if i+1 < len(args) {
+				if args[i+1] != redisConfig.CaseLeaderboard {
+					return nil, fmt.Errorf("unknown preset %q (expected leaderboard)", args[i+1])
+				}
+				config.BenchMark.Case = redisConfig.CaseLeaderboard
+				i++
+			}
+		case "--boards", "--members", "--top-n", "--zadd-percent":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", args[i])
+			}
+			value, err := strconv.Atoi(args[i+1])
+			if err != nil || value < 0 || (value == 0 && args[i] != "--zadd-percent") || (args[i] == "--zadd-percent" && value > 100) {
+				return nil, fmt.Errorf("invalid value for %s: %q", args[i], args[i+1])
+			}
+			switch args[i] {
+			case "--boards":
+				config.BenchMark.Leaderboard.Boards = value
+			case "--members":
+				config.BenchMark.Leaderboard.Members = value
+			case "--top-n":
+				config.BenchMark.Leaderboard.TopN = value
+			default:
+				config.BenchMark.Leaderboard.AddPercent = value
+			}
+			i++
+		case "--key-distribution":
+			if i+1 < len(args) {
+				config.BenchMark.KeyDistribution.Type = args[i+1]
+				i++
+			}
+		case "--zipf-skew", "--gaussian-mean", "--gaussian-stddev":
+			if i+1 < len(args) {
+				value, err := strconv.ParseFloat(args[i+1], 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid value for %s: %q (expected a number)", args[i], args[i+1])
+				}
+				switch args[i] {
+				case "--zipf-skew":
+					config.BenchMark.KeyDistribution.Skew = value
+					config.BenchMark.Leaderboard.Skew = value
+				case "--gaussian-mean":
+					config.BenchMark.KeyDistribution.Mean = value
+				default:
+					config.BenchMark.KeyDistribution.StdDev = value
+				}
+				i++
+			}
+		case "--pipeline", "-P":
+			if i+1 < len(args) {
+				size, err := strconv.Atoi(args[i+1])
+				if err != nil || size < 1 {
+					return nil, fmt.Errorf("invalid value for %s: %q (expected a positive integer)", args[i], args[i+1])
+				}
+				config.BenchMark.Pipeline = size
+				i++
+			}
+		case "--verify":
+			config.BenchMark.Verify = true
+		case "--value-template":
+			if i+1 < len(args) {
+				config.BenchMark.Payload.Template = args[i+1]
+				i++
+			}
+		case "--data-file":
+			if i+1 < len(args) {
+				config.BenchMark.Payload.DataFile = args[i+1]
+				i++
+			}
+		case "--script":
+			if i+1 < len(args) {
+				if _, err := os.Stat(args[i+1]); err != nil {
+					return nil, fmt.Errorf("invalid value for --script: %w", err)
+				}
+				config.Script.File = args[i+1]
+				i++
+			}
+		case "--script-key":
+			if i+1 < len(args) {
+				config.Script.Keys = append(config.Script.Keys, args[i+1])
+				i++
+			}
+		case "--script-arg":
+			if i+1 < len(args) {
+				config.Script.Args = append(config.Script.Args, args[i+1])
+				i++
+			}
+		case "--script-eval":
+			config.Script.UseEval = true
+		case "--script-read-only":
+			config.Script.ReadOnly = true
+		case "--resp3":
+			config.Client.Protocol = 3
+		case "--client-tracking":
+			config.Client.Protocol = 3
+			config.Client.Tracking.Enabled = true
+		case "--tracking-bcast":
+			config.Client.Tracking.BCast = true
+		case "--tracking-prefix":
+			if i+1 < len(args) {
+				config.Client.Tracking.Prefixes = append(config.Client.Tracking.Prefixes, args[i+1])
+				i++
+			}
+		case "--tracking-noloop":
+			config.Client.Tracking.NoLoop = true
+		case "--sentinel-addrs":
+			if i+1 < len(args) {
+				config.Mode = "sentinel"
+				config.Sentinel.Addrs = strings.Split(args[i+1], ",")
+				i++
+			}
+		case "--sentinel-master-name":
+			if i+1 < len(args) {
+				config.Sentinel.MasterName = args[i+1]
+				i++
+			}
+		case "--sentinel-auth":
+			if i+1 < len(args) {
+				config.Sentinel.SentinelPassword = args[i+1]
+				i++
+			}
+		case "--failover-after":
+			if i+1 < len(args) {
+				after, err := time.ParseDuration(args[i+1])
+				if err != nil || after <= 0 {
+					return nil, fmt.Errorf("invalid value for --failover-after: %q (expected a positive duration)", args[i+1])
+				}
+				config.BenchMark.Failover.After = after
+				i++
+			}
+		case "--track-failover":
+			config.BenchMark.Failover.Track = true
+		case "--keyspace-notify":
+			config.BenchMark.Notifications.Enabled = true
+		case "--configure-notify":
+			config.BenchMark.Notifications.Enabled = true
+			config.BenchMark.Notifications.Configure = true
+		case "--notify-timeout":
+			if i+1 < len(args) {
+				timeout, err := time.ParseDuration(args[i+1])
+				if err != nil || timeout <= 0 {
+					return nil, fmt.Errorf("invalid value for --notify-timeout: %q (expected a positive duration)", args[i+1])
+				}
+				config.BenchMark.Notifications.Timeout = timeout
+				i++
+			}
+		case "--tls":
+			config.TLS.Enabled = true
+		case "--cacert", "--cert", "--key", "--sni":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", args[i])
+			}
+			switch args[i] {
+			case "--cacert":
+				config.TLS.CAFile = args[i+1]
+			case "--cert":
+				config.TLS.CertFile = args[i+1]
+			case "--key":
+				config.TLS.KeyFile = args[i+1]
+			case "--sni":
+				config.TLS.ServerName = args[i+1]
+			}
+			config.TLS.Enabled = true
+			i++
+		case "--insecure":
+			config.TLS.Enabled = true
+			config.TLS.InsecureSkipVerify = true
+		case "--proxy":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for --proxy")
+			}
+			config.Proxy.Listen = args[i+1]
+			i++
+		case "--proxy-duration", "--inject-latency":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", args[i])
+			}
+			value, err := time.ParseDuration(args[i+1])
+			if err != nil || value < 0 {
+				return nil, fmt.Errorf("invalid value for %s: %q (expected a duration)", args[i], args[i+1])
+			}
+			if args[i] == "--proxy-duration" {
+				config.Proxy.Duration = value
+			} else {
+				config.Proxy.InjectLatency = value
+			}
+			i++
+		}
+	}
+	// 哨兵模式下数据节点沿用--auth与--db
+	if config.Mode == "sentinel" {
+		if config.Sentinel.MasterName == "" {
+			config.Sentinel.MasterName = "mymaster"
+		}
+		config.Sentinel.Password = config.Standalone.Password
+		config.Sentinel.Db = config.Standalone.Db
+	}
+	if config.BenchMark.Failover.Enabled() && config.Mode != "sentinel" {
+		return nil, fmt.Errorf("--failover-after and --track-failover require --sentinel-addrs")
+	}
+	if config.BenchMark.IsLeaderboard() {
+		if config.UseRESP3() || config.BenchMark.GetPipeline() > 1 || config.Script.Enabled() {
+			return nil, fmt.Errorf("--preset leaderboard is not supported with --resp3, --pipeline or --script")
+		}
+		if err := config.BenchMark.Leaderboard.Validate(); err != nil {
+			return nil, err
 		}
 	}
+	if config.BenchMark.Notifications.Enabled && (config.Mode == "cluster" || config.UseRESP3()) {
+		return nil, fmt.Errorf("--keyspace-notify is not supported in cluster mode or with --resp3")
+	}
+	if (config.TLS.CertFile == "") != (config.TLS.KeyFile == "") {
+		return nil, fmt.Errorf("--cert and --key must be used together")
+	}
+	if !config.Proxy.Enabled() && (config.Proxy.Duration > 0 || config.Proxy.InjectLatency > 0) {
+		return nil, fmt.Errorf("--proxy-duration and --inject-latency require --proxy")
+	}
+	if config.Proxy.Enabled() && config.Mode == "sentinel" {
+		return nil, fmt.Errorf("--proxy forwards to a single Redis and cannot be combined with --sentinel-addrs")
+	}
+	// 分布参数有误时直接报错，避免连接失败后进入模拟模式
+	if err := config.BenchMark.KeyDistribution.Validate(config.BenchMark.RandomKeys); err != nil {
+		return nil, err
+	}
+	if err := config.BenchMark.Payload.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid --value-template: %w", err)
+	}
 	return config, nil
 }
 
 // runPerformanceTest 运行性能测试 - 使用新的ExecutionEngine
-func (r *RedisCommandHandler) runPerformanceTest(ctx context.Context, adapter interfaces.ProtocolAdapter, config *redisConfig.RedisConfig, collector *metrics.BaseCollector[map[string]interface{}]) error {
+func (r *RedisCommandHandler) runPerformanceTest(ctx context.Context, adapter interfaces.ProtocolAdapter, config *redisConfig.RedisConfig, collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
 	// 执行健康检查
 	if err := adapter.HealthCheck(ctx); err != nil {
 		fmt.Printf("⚠️  Health check failed: %v\n", err)
 		fmt.Printf("🔄 Switching to simulation mode - this will generate mock test data instead of real Redis operations\n")
 		// 在模拟模式下生成测试数据
+		opts.markSimulated(err)
 		return r.runSimulationTest(config, collector)
 	}
 	// 使用新的ExecutionEngine执行真实测试
-	return r.runConcurrentTest(ctx, adapter, config, collector)
+	return r.runConcurrentTest(ctx, adapter, config, collector, opts)
 }
 
 // runSimulationTest 运行模拟测试 (保持不变，用于连接失败时的后备方案)
@@ -226,7 +648,7 @@ func (r *RedisCommandHandler) runSimulationTest(config *redisConfig.RedisConfig,
 
 // runConcurrentTest 使用ExecutionEngine运行并发测试
 // runConcurrentTest 使用ExecutionEngine运行并发测试
-func (r *RedisCommandHandler) runConcurrentTest(ctx context.Context, adapter interfaces.ProtocolAdapter, config *redisConfig.RedisConfig, collector *metrics.BaseCollector[map[string]interface{}]) error {
+func (r *RedisCommandHandler) runConcurrentTest(ctx context.Context, adapter interfaces.ProtocolAdapter, config *redisConfig.RedisConfig, collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
 	fmt.Printf("📊 Running concurrent Redis performance test with ExecutionEngine...\n")
 
 	// 创建基准配置适配器
@@ -242,6 +664,17 @@ func (r *RedisCommandHandler) runConcurrentTest(ctx context.Context, adapter int
 	engine.SetMaxWorkers(100)         // 设置最大工作协程数
 	engine.SetBufferSizes(1000, 1000) // 设置缓冲区大小
 
+	// 预填充数据集（不计入指标）
+	if err := opts.runPrefill(ctx, engine, collector, config.BenchMark.Parallels); err != nil {
+		return err
+	}
+	opts.applyToEngine(engine, collector) // 应用通用运行选项
+
+	// 故障转移跟踪从测量开始计时
+	if failoverAdapter, ok := adapter.(interface{ StartFailover() }); ok {
+		failoverAdapter.StartFailover()
+	}
+
 	// 记录测试开始时间
 	testStartTime := time.Now()
 
@@ -250,6 +683,7 @@ func (r *RedisCommandHandler) runConcurrentTest(ctx context.Context, adapter int
 	if err != nil {
 		return fmt.Errorf("benchmark execution failed: %w", err)
 	}
+	opts.finishRun()
 
 	// 计算实际测试时间
 	actualTestDuration := time.Since(testStartTime)
@@ -270,19 +704,261 @@ func (r *RedisCommandHandler) runConcurrentTest(ctx context.Context, adapter int
 	}
 
 	// 更新收集器的协议数据，包含实际测试时间
-	collector.UpdateProtocolMetrics(map[string]interface{}{
+	protocolMetrics := map[string]interface{}{
 		"protocol":         "redis",
 		"test_type":        "performance",
 		"actual_duration":  actualTestDuration,
 		"execution_result": result,
-	})
+		"test_case":        config.BenchMark.GetTestCase(),
+	}
+	if addrs := config.GetConnection().GetAddresses(); len(addrs) > 0 {
+		protocolMetrics["target"] = addrs[0]
+	}
+
+	// 键访问分布
+	if config.BenchMark.RandomKeys > 0 {
+		distribution := config.BenchMark.KeyDistribution
+		fmt.Printf("   Key Distribution: %s over %d keys\n", distribution, config.BenchMark.RandomKeys)
+		protocolMetrics["key_distribution"] = map[string]interface{}{
+			"type":      distribution.GetType(),
+			"key_space": config.BenchMark.RandomKeys,
+			"summary":   distribution.String(),
+		}
+	}
+
+	// Lua脚本信息
+	if scriptAdapter, ok := adapter.(interface {
+		GetScriptInfo() map[string]interface{}
+	}); ok {
+		if info := scriptAdapter.GetScriptInfo(); info != nil {
+			fmt.Printf("   Script: %s (%s %s)\n", info["file"], info["mode"], info["sha"])
+			protocolMetrics["script"] = info
+		}
+	}
+
+	// 客户端缓存（client tracking）统计
+	if cacheAdapter, ok := adapter.(interface {
+		GetClientCacheStats() *redisOperations.ClientCacheStats
+	}); ok {
+		if stats := cacheAdapter.GetClientCacheStats(); stats != nil {
+			fmt.Printf("   Client Cache: hits=%d, misses=%d, hit ratio=%.2f%%\n", stats.Hits, stats.Misses, stats.HitRatio)
+			fmt.Printf("   Invalidations: %d (%.2f/sec, %d keys, %d flushes)\n",
+				stats.Invalidations, stats.InvalidationRate, stats.InvalidatedKeys, stats.FlushInvalidations)
+			fmt.Printf("   Stale Reads: %d, Stale Window avg/p99/max: %v/%v/%v (%d samples)\n",
+				stats.StaleReads, stats.StaleWindowAvg, stats.StaleWindowP99, stats.StaleWindowMax, stats.StaleWindowSamples)
+			protocolMetrics["client_cache"] = stats
+		}
+	}
+
+	// 新建连接的建连与TLS握手耗时
+	if phaseAdapter, ok := adapter.(interface {
+		GetLatencyPhases() []metrics.PhaseLatency
+	}); ok {
+		if phases := phaseAdapter.GetLatencyPhases(); len(phases) > 0 {
+			printLatencyPhases(phases)
+			protocolMetrics["latency_phases"] = phases
+		}
+	}
+
+	// pipeline批次与单命令统计
+	if pipelineAdapter, ok := adapter.(interface {
+		GetPipelineStats() *redisOperations.PipelineStats
+	}); ok {
+		if stats := pipelineAdapter.GetPipelineStats(); stats != nil {
+			fmt.Printf("   Pipeline: %d commands in %d batches (%.1f per batch), %.2f commands/sec, failed commands: %d\n",
+				stats.Commands, stats.Batches, stats.BatchSize, stats.CommandRate, stats.FailedCommands)
+			fmt.Printf("   Batch latency   avg/p50/p99/max: %v/%v/%v/%v\n",
+				stats.BatchLatency.Average, stats.BatchLatency.P50, stats.BatchLatency.P99, stats.BatchLatency.Max)
+			fmt.Printf("   Command latency avg/p50/p99/max: %v/%v/%v/%v\n",
+				stats.CommandLatency.Average, stats.CommandLatency.P50, stats.CommandLatency.P99, stats.CommandLatency.Max)
+			protocolMetrics["pipeline"] = stats
+		}
+	}
+
+	// 集群按节点、重定向与热点槽统计
+	if clusterAdapter, ok := adapter.(interface {
+		GetClusterStats() *redisOperations.ClusterStats
+	}); ok {
+		if stats := clusterAdapter.GetClusterStats(); stats != nil {
+			fmt.Printf("   Cluster: %d nodes, %d active slots, redirects: MOVED=%d, ASK=%d (%.2f%%)\n",
+				len(stats.Nodes), stats.ActiveSlots, stats.Moved, stats.Ask, stats.RedirectRate)
+			for _, node := range stats.Nodes {
+				fmt.Printf("     %-22s commands=%-8d share=%6.2f%%  moved=%-6d ask=%-6d errors=%d\n",
+					node.Addr, node.Commands, node.Share, node.Moved, node.Ask, node.Errors)
+			}
+			if len(stats.TopSlots) > 0 {
+				fmt.Printf("   Hot slots:\n")
+				for _, slot := range stats.TopSlots {
+					fmt.Printf("     slot %-5d %-22s commands=%-8d share=%6.2f%%  hot key=%s (%d)\n",
+						slot.Slot, slot.Node, slot.Commands, slot.Share, slot.HotKey, slot.HotKeyCount)
+				}
+			}
+			if len(stats.HotKeys) > 0 {
+				fmt.Printf("   Hot keys:\n")
+				for _, key := range stats.HotKeys {
+					fmt.Printf("     %-32s slot=%-5d commands=%d\n", key.Key, key.Slot, key.Commands)
+				}
+				if stats.KeysTruncated {
+					fmt.Printf("     (key tracking limit reached, hot keys are approximate)\n")
+				}
+			}
+			protocolMetrics["cluster"] = stats
+		}
+	}
+
+	// 数据校验统计
+	if verifyAdapter, ok := adapter.(interface {
+		GetVerifyStats() *redisOperations.VerifyStats
+	}); ok {
+		if stats := verifyAdapter.GetVerifyStats(); stats != nil {
+			fmt.Printf("   Verify: %d checksummed writes, %d reads checked: valid=%d, missing=%d, unverified=%d\n",
+				stats.Writes, stats.Reads, stats.Valid, stats.Missing, stats.Unverified)
+			fmt.Printf("   Verify failures: corrupted=%d, wrong key=%d (%.4f%% of reads)\n",
+				stats.Corrupted, stats.Mismatched, stats.ErrorRate)
+			for _, sample := range stats.Samples {
+				fmt.Printf("     %s\n", sample)
+			}
+			protocolMetrics["verify"] = stats
+		}
+	}
+
+	// 故障转移统计
+	if failoverAdapter, ok := adapter.(interface {
+		GetFailoverStats() *redisOperations.FailoverStats
+	}); ok {
+		if stats := failoverAdapter.GetFailoverStats(); stats != nil {
+			fmt.Printf("   Failover: master %s %s -> %s, %d switch(es)\n",
+				stats.MasterName, stats.InitialMaster, stats.FinalMaster, len(stats.Switches))
+			if stats.Triggered {
+				fmt.Printf("     triggered at %v", stats.TriggeredAt.Round(time.Millisecond))
+				if stats.TriggerError != "" {
+					fmt.Printf(" (SENTINEL FAILOVER failed: %s)", stats.TriggerError)
+				}
+				fmt.Println()
+			}
+			for _, sw := range stats.Switches {
+				fmt.Printf("     +switch-master at %v: %s -> %s\n", sw.At.Round(time.Millisecond), sw.From, sw.To)
+			}
+			fmt.Printf("     connections=%d, reconnects=%d, failed commands=%d of %d\n",
+				stats.Connections, stats.Reconnects, stats.Errors, stats.Commands)
+			switch {
+			case stats.Errors == 0:
+				fmt.Printf("     no client-visible errors\n")
+			case stats.Recovered:
+				fmt.Printf("     error spike %v, recovered after %v\n",
+					stats.ErrorSpike.Round(time.Millisecond), stats.TimeToRecover.Round(time.Millisecond))
+			default:
+				fmt.Printf("     error spike %v, not recovered before the test ended\n", stats.ErrorSpike.Round(time.Millisecond))
+			}
+			protocolMetrics["failover"] = stats
+		}
+	}
+
+	// 排行榜负载统计
+	if leaderboardAdapter, ok := adapter.(interface {
+		GetLeaderboardStats() *redisOperations.LeaderboardStats
+	}); ok {
+		if stats := leaderboardAdapter.GetLeaderboardStats(); stats != nil {
+			fmt.Printf("   Leaderboard: %d board(s) x %d members, top %d\n", stats.Boards, stats.Members, stats.TopN)
+			fmt.Printf("     Score updates: %d (ZADD %d, ZINCRBY %d, failed %d), avg/p50/p99/max: %v/%v/%v/%v\n",
+				stats.Updates, stats.Adds, stats.Increments, stats.UpdateErrors,
+				stats.UpdateLatency.Average, stats.UpdateLatency.P50, stats.UpdateLatency.P99, stats.UpdateLatency.Max)
+			fmt.Printf("     Rank queries:  %d (failed %d, %d entries read), avg/p50/p99/max: %v/%v/%v/%v\n",
+				stats.Queries, stats.QueryErrors, stats.EntriesRead,
+				stats.QueryLatency.Average, stats.QueryLatency.P50, stats.QueryLatency.P99, stats.QueryLatency.Max)
+			protocolMetrics["leaderboard"] = stats
+		}
+	}
+
+	// 键空间通知统计
+	if keyspaceAdapter, ok := adapter.(interface {
+		GetKeyspaceStats() *redisOperations.KeyspaceStats
+	}); ok {
+		if stats := keyspaceAdapter.GetKeyspaceStats(); stats != nil {
+			fmt.Printf("   Keyspace notifications (%s, flags %q", stats.Channel, stats.Flags)
+			if stats.Configured {
+				fmt.Printf(", set for this run")
+			}
+			fmt.Printf("):\n")
+			fmt.Printf("     writes=%d, delivered=%d, late=%d, lost=%d (%.2f%%), pending=%d\n",
+				stats.Writes, stats.Delivered, stats.Late, stats.Lost, stats.LossRate, stats.Pending)
+			if stats.Delivered+stats.Late > 0 {
+				fmt.Printf("     delivery latency avg=%v p50=%v p99=%v max=%v\n",
+					stats.Latency.Average, stats.Latency.P50, stats.Latency.P99, stats.Latency.Max)
+			}
+			events := make([]string, 0, len(stats.Events))
+			for event, count := range stats.Events {
+				events = append(events, fmt.Sprintf("%s=%d", event, count))
+			}
+			sort.Strings(events)
+			fmt.Printf("     events: %s; unmatched=%d, subscriber disconnects=%d\n",
+				strings.Join(events, " "), stats.Unmatched, stats.Disconnects)
+			protocolMetrics["keyspace_notifications"] = stats
+		}
+	}
+
+	collector.UpdateProtocolMetrics(protocolMetrics)
 
 	return nil
 }
 
+// runProxy 运行代理模式，直到中断或达到--proxy-duration后生成报告
+func (r *RedisCommandHandler) runProxy(ctx context.Context, config *redisConfig.RedisConfig, collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
+	proxy, err := redisOperations.NewRedisProxy(config, collector)
+	if err != nil {
+		return NewConfigError(err)
+	}
+	if err := proxy.CheckUpstream(ctx); err != nil {
+		return NewConnectionError(err)
+	}
+	if err := proxy.Listen(); err != nil {
+		return err
+	}
+	opts.applyToCollector(collector)
+
+	// 代理为常驻服务，不受单次命令超时限制，收到中断信号或达到时长时停止
+	serveCtx, stop := signal.NotifyContext(context.WithoutCancel(ctx), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if config.Proxy.Duration > 0 {
+		var cancel context.CancelFunc
+		serveCtx, cancel = context.WithTimeout(serveCtx, config.Proxy.Duration)
+		defer cancel()
+	}
+
+	fmt.Printf("🔀 Redis proxy listening on %s, forwarding to %s\n", proxy.Addr(), config.Standalone.Addr)
+	if config.Proxy.InjectLatency > 0 {
+		fmt.Printf("   Injecting %v before every reply\n", config.Proxy.InjectLatency)
+	}
+	if config.Proxy.Duration > 0 {
+		fmt.Printf("   Running for %v (Ctrl+C to stop earlier)\n", config.Proxy.Duration)
+	} else {
+		fmt.Printf("   Press Ctrl+C to stop and generate the report\n")
+	}
+
+	start := time.Now()
+	if err := proxy.Serve(serveCtx); err != nil {
+		return fmt.Errorf("redis proxy failed: %w", err)
+	}
+	elapsed := time.Since(start)
+	opts.finishRun()
+
+	stats := proxy.Stats()
+	fmt.Printf("✅ Redis proxy stopped after %v\n", elapsed.Round(time.Millisecond))
+	fmt.Printf("   Client connections: %d (upstream dial errors: %d)\n", stats.Connections, stats.DialErrors)
+	fmt.Printf("   Commands: %d timed, %d error replies, %d untracked replies\n", stats.Commands, stats.ErrorReplies, stats.Untracked)
+	fmt.Printf("   Bytes: %d in, %d out\n", stats.BytesIn, stats.BytesOut)
+
+	collector.UpdateProtocolMetrics(map[string]interface{}{
+		"protocol":        "redis",
+		"test_type":       "proxy",
+		"actual_duration": elapsed,
+		"proxy":           stats,
+	})
+	return r.generateReport(collector, opts)
+}
+
 // generateReport 生成报告
-// generateReport 生成报告
-func (r *RedisCommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}]) error {
+func (r *RedisCommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
 	// 获取指标快照
 	snapshot := collector.Snapshot()
 
@@ -308,13 +984,23 @@ func (r *RedisCommandHandler) generateReport(collector *metrics.BaseCollector[ma
 		snapshot.Core.Throughput.RPS = float64(total) / seconds
 		snapshot.Core.Throughput.ReadRPS = float64(snapshot.Core.Operations.Read) / seconds
 		snapshot.Core.Throughput.WriteRPS = float64(snapshot.Core.Operations.Write) / seconds
+		snapshot.Core.Throughput.ReadMBps, snapshot.Core.Throughput.WriteMBps = metrics.Bandwidth(snapshot.Core.Operations, actualDuration)
+	}
+
+	// 存在快照接收器时（如分布式agent模式）交由接收器处理，不生成本地报告
+	if opts.publishSnapshot(snapshot) {
+		return nil
 	}
 
 	// 转换为结构化报告
 	report := reporting.ConvertFromMetricsSnapshot(snapshot)
 	// 使用标准报告配置
 	reportConfig := reporting.NewStandardReportConfig("redis")
+	opts.applyToReport(snapshot, report, reportConfig)
 	generator := reporting.NewReportGenerator(reportConfig)
 	// 生成并显示报告
-	return generator.Generate(report)
+	if err := generator.Generate(report); err != nil {
+		return err
+	}
+	return opts.resultError(report)
 }
@@ -0,0 +1,359 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	rwConfig "abc-runner/app/adapters/remotewrite/config"
+	"abc-runner/app/adapters/remotewrite/operations"
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/reporting"
+)
+
+// RemoteWriteCommandHandler Prometheus remote-write命令处理器
+type RemoteWriteCommandHandler struct {
+	protocolName string
+	factory      interfaces.RemoteWriteAdapterFactory
+}
+
+// NewRemoteWriteCommandHandler 创建remote-write命令处理器
+func NewRemoteWriteCommandHandler(factory interfaces.RemoteWriteAdapterFactory) *RemoteWriteCommandHandler {
+	if factory == nil {
+		panic("remoteWriteAdapterFactory cannot be nil - dependency injection required")
+	}
+
+	return &RemoteWriteCommandHandler{
+		protocolName: "remotewrite",
+		factory:      factory,
+	}
+}
+
+// Execute 执行remote-write命令
+func (h *RemoteWriteCommandHandler) Execute(ctx context.Context, args []string) error {
+	// 检查帮助请求
+	for _, arg := range args {
+		if arg == "--help" || arg == "help" || arg == "-h" {
+			fmt.Println(h.GetHelp())
+			return nil
+		}
+	}
+
+	// 解析命令行参数
+	config, err := h.parseArgs(args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	// 解析通用运行选项
+	opts, err := parseRunOptions(ctx, "remotewrite", args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+	if printConfigRequested(args) {
+		return printEffectiveConfig("remotewrite", config, opts)
+	}
+
+	metricsCollector := metrics.NewBaseCollector(opts.collectorConfig(), map[string]interface{}{
+		"protocol":  "remotewrite",
+		"test_type": "performance",
+	})
+	defer metricsCollector.Stop()
+
+	adapter := h.factory.CreateRemoteWriteAdapter()
+	if adapter == nil {
+		return fmt.Errorf("failed to create remote-write adapter")
+	}
+	defer adapter.Close()
+
+	if err := adapter.Connect(ctx, config); err != nil {
+		return fmt.Errorf("failed to set up remote-write client: %w", err)
+	}
+	if err := adapter.HealthCheck(ctx); err != nil {
+		fmt.Printf("⚠️  Health check failed: %v\n", err)
+		fmt.Printf("🔍 Possible causes: wrong push path, missing tenant header or credentials, or receiver not running\n")
+	} else {
+		fmt.Printf("✅ Remote-write endpoint %s accepted an empty write\n", config.Connection.URL)
+	}
+
+	rateDesc := "unlimited"
+	if config.BenchMark.SampleRate > 0 {
+		rateDesc = fmt.Sprintf("%d samples/s", config.BenchMark.SampleRate)
+	}
+	fmt.Printf("🚀 Starting Prometheus remote-write ingestion test...\n")
+	fmt.Printf("Endpoint: %s\n", config.Connection.URL)
+	fmt.Printf("Series: %d (%d batches), Samples/request: %d, Sample rate: %s\n",
+		config.RemoteWriteSpecific.Series, config.Batches(), config.SamplesPerRequest(), rateDesc)
+	fmt.Printf("Requests: %d, Concurrency: %d\n", config.BenchMark.Total, config.BenchMark.Parallels)
+
+	if err := h.runPerformanceTest(ctx, adapter, config, metricsCollector, opts); err != nil {
+		return fmt.Errorf("performance test failed: %w", err)
+	}
+
+	return h.generateReport(metricsCollector, opts)
+}
+
+// GetHelp 获取帮助信息
+func (h *RemoteWriteCommandHandler) GetHelp() string {
+	return `Prometheus Remote-Write Ingestion Testing
+
+USAGE:
+  abc-runner remotewrite [options]
+
+DESCRIPTION:
+  Push snappy-compressed remote-write (1.0) protobuf batches to a receiver
+  such as Mimir, Cortex, Thanos Receive, VictoriaMetrics or Prometheus with
+  --web.enable-remote-write-receiver. Series are split into fixed batches;
+  each request writes one batch, and requests rotate across batches so every
+  series is refreshed. Ingestion latency is measured per request, and 429
+  (throttled) and 5xx responses are reported separately.
+
+OPTIONS:
+  --help                     Show this help message
+  --url URL                  Push endpoint (default: http://localhost:9090/api/v1/write)
+                             Mimir/Cortex: /api/v1/push, Thanos: /api/v1/receive,
+                             VictoriaMetrics: /api/v1/write
+  --series N                 Series cardinality (default: 10000)
+  --batch-size N             Series per request (default: 500)
+  --samples-per-series N     Samples per series in one request (default: 1)
+  --metric-names N           Distinct metric names (default: 10)
+  --extra-labels N           Extra labels per series (default: 3)
+  --metric-prefix NAME       Metric name prefix (default: abc_runner_metric)
+  --job NAME                 job label value (default: abc-runner)
+  --sample-rate N            Cap ingestion at N samples/sec (default: unlimited)
+  --tenant ID                Send X-Scope-OrgID (Mimir/Cortex multi-tenancy)
+  --header "K: V"            Extra request header (repeatable)
+  --username USER            Basic auth user
+  --password PASS            Basic auth password
+  --bearer-token TOKEN       Bearer token
+  --insecure, -k             Skip TLS certificate verification
+  --timeout DURATION         Request timeout (default: 30s)
+  -n COUNT                   Total requests (default: 1000)
+  -c COUNT                   Concurrent writers (default: 10)
+  --duration DURATION        Run for a fixed duration instead of -n
+
+NOTES:
+  Timestamps within a batch strictly increase. With fewer batches than
+  writers (series / batch-size < -c), two in-flight requests can carry the
+  same series and the later-arriving one may be rejected as out of order.
+  Use a distinct --job per run to keep data from different runs apart.
+
+EXAMPLES:
+  abc-runner remotewrite --help
+  abc-runner remotewrite --url http://localhost:9090/api/v1/write -n 1000
+  abc-runner remotewrite --url http://mimir:8080/api/v1/push --tenant bench \
+    --series 100000 --batch-size 2000 --sample-rate 200000 --duration 5m
+  abc-runner remotewrite --url http://vm:8428/api/v1/write --series 1000000 -c 50` + configLayersHelp + runOptionsHelp + "\n"
+}
+
+// parseArgs 解析命令行参数
+func (h *RemoteWriteCommandHandler) parseArgs(args []string) (*rwConfig.RemoteWriteConfig, error) {
+	config := rwConfig.NewDefaultRemoteWriteConfig()
+	specific := &config.RemoteWriteSpecific
+
+	// 依次应用--config文件、ABC_REMOTEWRITE_*环境变量与--set，专用参数随后覆盖
+	if err := applyConfigLayers("remotewrite", args, config); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--insecure" || args[i] == "-k" {
+			config.Connection.InsecureSkipVerify = true
+			continue
+		}
+
+		if i+1 >= len(args) {
+			break
+		}
+		value := args[i+1]
+		var err error
+
+		switch args[i] {
+		case "--url":
+			config.Connection.URL = value
+		case "--series":
+			specific.Series, err = strconv.Atoi(value)
+		case "--batch-size":
+			specific.BatchSize, err = strconv.Atoi(value)
+		case "--samples-per-series":
+			specific.SamplesPerSeries, err = strconv.Atoi(value)
+		case "--metric-names":
+			specific.MetricNames, err = strconv.Atoi(value)
+		case "--extra-labels":
+			specific.ExtraLabels, err = strconv.Atoi(value)
+		case "--metric-prefix":
+			specific.MetricPrefix = value
+		case "--job":
+			specific.Job = value
+		case "--sample-rate":
+			config.BenchMark.SampleRate, err = strconv.Atoi(value)
+		case "--tenant":
+			config.Connection.Tenant = value
+		case "--header":
+			name, headerValue, found := strings.Cut(value, ":")
+			if !found || strings.TrimSpace(name) == "" {
+				err = fmt.Errorf("expected \"Name: value\", got %q", value)
+				break
+			}
+			config.Connection.Headers[strings.TrimSpace(name)] = strings.TrimSpace(headerValue)
+		case "--username":
+			config.Connection.Username = value
+		case "--password":
+			config.Connection.Password = value
+		case "--bearer-token":
+			config.Connection.BearerToken = value
+		case "--timeout":
+			config.Connection.Timeout, err = time.ParseDuration(value)
+		case "-n":
+			config.BenchMark.Total, err = strconv.Atoi(value)
+		case "-c":
+			config.BenchMark.Parallels, err = strconv.Atoi(value)
+		case "--duration":
+			config.BenchMark.Duration, err = time.ParseDuration(value)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", args[i], err)
+		}
+		i++
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// runPerformanceTest 运行remote-write写入测试
+func (h *RemoteWriteCommandHandler) runPerformanceTest(
+	ctx context.Context,
+	adapter interfaces.ProtocolAdapter,
+	config *rwConfig.RemoteWriteConfig,
+	collector *metrics.BaseCollector[map[string]interface{}],
+	opts *runOptions,
+) error {
+	factory := operations.NewOperationFactory(config.BenchMark.TestCase, config.Batches())
+	engine := execution.NewExecutionEngine(adapter, collector, factory)
+	engine.SetMaxWorkers(config.BenchMark.Parallels)
+	opts.applyToEngine(engine, collector)
+
+	testStartTime := time.Now()
+	result, err := engine.RunBenchmark(ctx, &config.BenchMark)
+	if err != nil {
+		return fmt.Errorf("benchmark execution failed: %w", err)
+	}
+	opts.finishRun()
+	actualTestDuration := time.Since(testStartTime)
+
+	fmt.Printf("\n📊 Execution Results:\n")
+	fmt.Printf("Total Jobs: %d\n", result.TotalJobs)
+	fmt.Printf("Completed Jobs: %d\n", result.CompletedJobs)
+	fmt.Printf("Success Jobs: %d\n", result.SuccessJobs)
+	fmt.Printf("Failed Jobs: %d\n", result.FailedJobs)
+	fmt.Printf("Actual Test Duration: %v\n", actualTestDuration)
+
+	protocolMetrics := map[string]interface{}{
+		"protocol":         "remotewrite",
+		"test_type":        "performance",
+		"series":           config.RemoteWriteSpecific.Series,
+		"actual_duration":  actualTestDuration,
+		"execution_result": result,
+	}
+
+	if statsAdapter, ok := adapter.(interface {
+		GetIngestStats() *operations.IngestStats
+	}); ok {
+		if stats := statsAdapter.GetIngestStats(); stats != nil {
+			h.printIngestStats(stats, actualTestDuration)
+			protocolMetrics["ingest_stats"] = *stats
+			protocolMetrics["samples_per_sec"] = float64(stats.SamplesAccepted) / actualTestDuration.Seconds()
+		}
+	}
+
+	collector.UpdateProtocolMetrics(protocolMetrics)
+	return nil
+}
+
+// printIngestStats 输出写入统计与响应分布
+func (h *RemoteWriteCommandHandler) printIngestStats(stats *operations.IngestStats, duration time.Duration) {
+	seconds := duration.Seconds()
+	fmt.Printf("\n📈 Ingestion:\n")
+	fmt.Printf("Samples Accepted: %d / %d (%.2f samples/sec)\n",
+		stats.SamplesAccepted, stats.SamplesSent, float64(stats.SamplesAccepted)/seconds)
+	if stats.UncompressedBytes > 0 {
+		fmt.Printf("Bytes Sent: %d compressed, %d raw (ratio %.2f, %.2f MB/sec)\n",
+			stats.BytesSent, stats.UncompressedBytes, float64(stats.UncompressedBytes)/float64(stats.BytesSent),
+			float64(stats.BytesSent)/seconds/1024/1024)
+	}
+	fmt.Printf("Throttled (429): %d (%.2f%%)\n", stats.Throttled, stats.ThrottleRate)
+	fmt.Printf("Server Errors (5xx): %d (%.2f%%)\n", stats.ServerErrors, stats.ServerErrorRate)
+	if stats.ClientErrors > 0 {
+		fmt.Printf("Client Errors (4xx): %d\n", stats.ClientErrors)
+	}
+	if stats.TransportErrors > 0 {
+		fmt.Printf("Transport Errors: %d\n", stats.TransportErrors)
+	}
+
+	codes := make([]int, 0, len(stats.StatusCodes))
+	for code := range stats.StatusCodes {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		line := fmt.Sprintf("  HTTP %d: %d", code, stats.StatusCodes[code])
+		if message := stats.ErrorSamples[code]; message != "" {
+			line += fmt.Sprintf(" (%s)", message)
+		}
+		fmt.Println(line)
+	}
+}
+
+// generateReport 生成remote-write测试报告
+func (h *RemoteWriteCommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
+	snapshot := collector.Snapshot()
+
+	// 使用实际测试时间修正吞吐量
+	if duration, ok := snapshot.Protocol["actual_duration"].(time.Duration); ok && duration > 0 {
+		snapshot.Core.Duration = duration
+		seconds := duration.Seconds()
+		snapshot.Core.Throughput.RPS = float64(snapshot.Core.Operations.Total) / seconds
+		snapshot.Core.Throughput.WriteRPS = float64(snapshot.Core.Operations.Write) / seconds
+		snapshot.Core.Throughput.ReadMBps, snapshot.Core.Throughput.WriteMBps = metrics.Bandwidth(snapshot.Core.Operations, duration)
+	}
+
+	fmt.Printf("\n📊 Remote-Write Performance Metrics:\n")
+	fmt.Printf("=====================================\n")
+	fmt.Printf("  Total Requests: %d\n", snapshot.Core.Operations.Total)
+	fmt.Printf("  Successful Requests: %d\n", snapshot.Core.Operations.Success)
+	fmt.Printf("  Failed Requests: %d\n", snapshot.Core.Operations.Failed)
+	fmt.Printf("  Success Rate: %.2f%%\n", snapshot.Core.Operations.Rate)
+	fmt.Printf("  Ingestion Latency avg/p95/p99: %v/%v/%v\n",
+		snapshot.Core.Latency.Average, snapshot.Core.Latency.P95, snapshot.Core.Latency.P99)
+	fmt.Printf("  Throughput: %.2f requests/sec\n", snapshot.Core.Throughput.RPS)
+	fmt.Printf("=====================================\n")
+
+	// 存在快照接收器时（如分布式agent模式）交由接收器处理，不生成本地报告
+	if opts.publishSnapshot(snapshot) {
+		return nil
+	}
+
+	report := reporting.ConvertFromMetricsSnapshot(snapshot)
+	reportConfig := reporting.NewStandardReportConfig("remotewrite")
+	opts.applyToReport(snapshot, report, reportConfig)
+	generator := reporting.NewReportGenerator(reportConfig)
+	if err := generator.Generate(report); err != nil {
+		return err
+	}
+	return opts.resultError(report)
+}
+
+// GetProtocolName 获取协议名称
+func (h *RemoteWriteCommandHandler) GetProtocolName() string {
+	return h.protocolName
+}
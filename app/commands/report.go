@@ -0,0 +1,196 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"abc-runner/app/reporting"
+)
+
+// ReportCommandHandler 报告工具命令处理器
+type ReportCommandHandler struct{}
+
+// NewReportCommandHandler 创建报告工具命令处理器
+func NewReportCommandHandler() *ReportCommandHandler {
+	return &ReportCommandHandler{}
+}
+
+// reportArgs 报告命令参数
+type reportArgs struct {
+	action string
+	files  []string
+	output string
+	locale string
+}
+
+// Execute 执行报告子命令
+func (h *ReportCommandHandler) Execute(ctx context.Context, args []string) error {
+	for _, arg := range args {
+		if arg == "--help" || arg == "-h" || arg == "help" {
+			fmt.Println(h.GetHelp())
+			return nil
+		}
+	}
+
+	parsed, err := h.parseArgs(args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+	return h.merge(ctx, parsed)
+}
+
+// merge 合并多个JSON报告，写入--output指定的文件，未指定时按标准报告配置输出全部格式
+func (h *ReportCommandHandler) merge(ctx context.Context, parsed *reportArgs) error {
+	reports := make([]*reporting.StructuredReport, len(parsed.files))
+	for i, file := range parsed.files {
+		report, err := reporting.LoadStructuredReport(file)
+		if err != nil {
+			return NewConfigError(err)
+		}
+		reports[i] = report
+	}
+
+	merged, err := reporting.MergeReports(reports, parsed.files)
+	if err != nil {
+		return NewConfigError(err)
+	}
+
+	fmt.Printf("🔗 Merged %d reports: %d operations, %.2f ops/sec\n", len(reports),
+		merged.Metrics.CoreOperations.TotalOperations, merged.Metrics.CoreOperations.OperationsPerSecond)
+	if !merged.PercentilesExact() {
+		var missing []string
+		for _, source := range merged.Context.MergedFrom {
+			if !source.Histogram {
+				missing = append(missing, source.File)
+			}
+		}
+		if len(missing) > 0 {
+			fmt.Printf("⚠️  No latency histogram in %s (written by an older version); percentiles are weighted approximations\n", strings.Join(missing, ", "))
+		} else {
+			fmt.Printf("⚠️  Latency histograms have different ranges; percentiles are weighted approximations\n")
+		}
+	}
+
+	recorder, _ := reporting.ResultRecorderFromContext(ctx)
+	if parsed.output != "" {
+		data, err := json.MarshalIndent(merged, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode merged report: %w", err)
+		}
+		if dir := filepath.Dir(parsed.output); dir != "." {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return fmt.Errorf("failed to create %s: %w", dir, err)
+			}
+		}
+		if err := os.WriteFile(parsed.output, data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", parsed.output, err)
+		}
+		fmt.Printf("✅ Merged report saved to: %s\n", parsed.output)
+		if recorder != nil {
+			recorder.AddReportFile(parsed.output)
+			recorder.Record(merged)
+		}
+		return nil
+	}
+
+	protocol := merged.Context.TestConfiguration.Protocol
+	reportConfig := reporting.NewStandardReportConfig(protocol + "_merged")
+	reportConfig.Locale = parsed.locale
+	if recorder != nil {
+		reportConfig.OnFileWritten = recorder.AddReportFile
+	}
+	if err := reporting.NewReportGenerator(reportConfig).Generate(merged); err != nil {
+		return err
+	}
+	if recorder != nil {
+		recorder.Record(merged)
+	}
+	return nil
+}
+
+// parseArgs 解析命令行参数
+func (h *ReportCommandHandler) parseArgs(args []string) (*reportArgs, error) {
+	parsed := &reportArgs{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--output", "-o", "--report-locale":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			if arg == "--report-locale" {
+				locale, err := reporting.NormalizeLocale(args[i])
+				if err != nil {
+					return nil, err
+				}
+				parsed.locale = locale
+			} else {
+				parsed.output = args[i]
+			}
+		default:
+			switch {
+			case parsed.action == "":
+				parsed.action = arg
+			case !strings.HasPrefix(arg, "-"):
+				parsed.files = append(parsed.files, arg)
+			default:
+				return nil, fmt.Errorf("unknown option %s", arg)
+			}
+		}
+	}
+
+	if parsed.action != "merge" {
+		if parsed.action == "" {
+			return nil, fmt.Errorf("missing subcommand (expected merge)")
+		}
+		return nil, fmt.Errorf("unknown subcommand %q (expected merge)", parsed.action)
+	}
+	if len(parsed.files) < 2 {
+		return nil, fmt.Errorf("expected at least 2 report files, got %d", len(parsed.files))
+	}
+	return parsed, nil
+}
+
+// GetHelp 获取帮助信息
+func (h *ReportCommandHandler) GetHelp() string {
+	return `Report Tools
+
+USAGE:
+  abc-runner report merge [options] REPORT.json REPORT.json [REPORT.json...]
+
+DESCRIPTION:
+  Combines the JSON reports of several runner instances that tested the
+  same target at the same time (e.g. started by hand on different hosts)
+  into one report, as if a single instance had produced the whole load.
+
+  Operation, error and byte counters are added up and throughput is summed.
+  Percentiles are computed from the merged latency histograms, which
+  reports written by this version include; when a report has no histogram
+  (older versions) percentiles fall back to an operation-weighted average
+  and a warning is printed. Time series are aligned by wall-clock time on
+  their sampling interval, with operations, errors and RPS summed per point
+  and P95 weighted by each point's operations.
+
+  All reports must come from the same protocol. The merged report lists its
+  sources under context.merged_from; SLA results, partial-report intervals
+  and per-target summaries of the inputs are not carried over. Merged
+  reports can be merged again.
+
+OPTIONS:
+  --help, -h             Show this help message
+  --output, -o FILE      Write the merged report as JSON to FILE. Without it,
+                         console, JSON, CSV and HTML reports are written to
+                         reports/ with the prefix PROTOCOL_merged
+  --report-locale L      Language of the console and HTML reports (zh, en)
+
+EXAMPLES:
+  abc-runner report merge host1/redis.json host2/redis.json host3/redis.json
+  abc-runner report merge -o reports/merged.json a.json b.json
+  abc-runner compare baseline.json reports/merged.json
+`
+}
@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/reporting"
+)
+
+// gitSHATimeout 执行git rev-parse的超时
+const gitSHATimeout = 2 * time.Second
+
+// gitSHAEnvVars 按顺序检查的CI环境变量
+var gitSHAEnvVars = []string{"GITHUB_SHA", "CI_COMMIT_SHA", "GIT_COMMIT"}
+
+// prepareResultPush 确定运行ID与git SHA：推送最终结果时未指定运行ID则生成一个，
+// 运行ID交给运行结果记录器，使运行历史与推送的结果使用同一个ID
+func (o *runOptions) prepareResultPush() {
+	pushing := o.pushGateway != "" || o.pushRemoteWrite != ""
+	if pushing && o.runID == "" {
+		o.runID = reporting.NewRunID(time.Now())
+	}
+	if pushing && o.gitSHA == "" {
+		o.gitSHA = detectGitSHA()
+	}
+	if o.resultRecorder != nil && o.runID != "" {
+		o.resultRecorder.SetRunID(o.runID)
+	}
+}
+
+// detectGitSHA 从CI环境变量或当前目录的git仓库获取提交SHA，都无法获取时为空
+func detectGitSHA() string {
+	for _, name := range gitSHAEnvVars {
+		if sha := strings.TrimSpace(os.Getenv(name)); sha != "" {
+			return sha
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), gitSHATimeout)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, "git", "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// pushResults 将最终快照推送到Pushgateway与remote-write端点，推送失败只输出警告，不影响退出码
+func (o *runOptions) pushResults(passed bool) {
+	if (o.pushGateway == "" && o.pushRemoteWrite == "") || o.finalSnapshot == nil {
+		return
+	}
+	snapshot := o.finalSnapshot
+	protocol, _ := snapshot.Protocol["protocol"].(string)
+	target, _ := snapshot.Protocol["target"].(string)
+	result := metrics.FinalResult{
+		Labels: metrics.RunLabels{
+			Protocol: protocol,
+			Target:   target,
+			GitSHA:   o.gitSHA,
+			RunID:    o.runID,
+		},
+		Core:   snapshot.Core,
+		Passed: passed,
+	}
+
+	// 运行被中断时context已取消，结果仍需推送
+	ctx := context.Background()
+	if o.ctx != nil {
+		ctx = context.WithoutCancel(o.ctx)
+	}
+	if err := metrics.NewResultPusher(o.pushGateway, o.pushRemoteWrite).Push(ctx, result); err != nil {
+		fmt.Printf("⚠️  Failed to push final results: %v\n", err)
+		return
+	}
+	fmt.Printf("📤 Pushed final results (run_id=%s)\n", o.runID)
+}
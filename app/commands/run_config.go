@@ -0,0 +1,186 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// runConfigKey 协议配置段中共享运行选项的键
+const runConfigKey = "run"
+
+// runConfig 共享运行选项在配置文件、环境变量与--set中的形式，位于协议配置段的run键下，
+// 如redis.run.prefill、ABC_REDIS_RUN_PREFILL、--set run.prefill=1000；对应的命令行参数优先
+type runConfig struct {
+	Prefill       int             `yaml:"prefill"`
+	Engine        string          `yaml:"engine"`
+	Cores         int             `yaml:"cores"`
+	Abort         runAbortConfig  `yaml:"abort"`
+	RawSamples    string          `yaml:"raw_samples"`
+	PartialReport runPeriodicFile `yaml:"partial_report"`
+	Checkpoint    runPeriodicFile `yaml:"checkpoint"`
+	Tags          []string        `yaml:"tags"`
+	ReportLocale  string          `yaml:"report_locale"`
+	Intercept     []string        `yaml:"intercept"`
+	Push          runPushConfig   `yaml:"push"`
+	Trace         runTraceConfig  `yaml:"trace"`
+}
+
+// runAbortConfig 熔断式停止条件，对应--abort-*
+type runAbortConfig struct {
+	ErrorRate         string `yaml:"error_rate"` // PCT[@WINDOW]，如20%@10s
+	MaxErrors         int64  `yaml:"max_errors"`
+	ConsecutiveErrors int64  `yaml:"consecutive_errors"`
+}
+
+// runPeriodicFile 运行期间周期性写入的文件，对应--partial-report/--partial-interval与--checkpoint/--checkpoint-interval
+type runPeriodicFile struct {
+	Path     string        `yaml:"path"`
+	Interval time.Duration `yaml:"interval"`
+}
+
+// runPushConfig 结果推送端点，对应--push-*
+type runPushConfig struct {
+	Gateway     string `yaml:"gateway"`
+	RemoteWrite string `yaml:"remote_write"`
+}
+
+// runTraceConfig W3C trace-context，对应--trace-*
+type runTraceConfig struct {
+	Context bool    `yaml:"context"`
+	Sample  float64 `yaml:"sample"`
+	OTLP    string  `yaml:"otlp"`
+}
+
+// runConfigSection 以协议配置中的路径寻址run段，使环境变量名与--set路径带有run前缀
+type runConfigSection struct {
+	Run runConfig `yaml:"run"`
+}
+
+// defaultRunConfig 运行选项的默认值，与newRunOptions一致
+func defaultRunConfig() runConfig {
+	return runConfig{Trace: runTraceConfig{Sample: 1}}
+}
+
+// loadRunConfig 依次应用--config文件中协议段的run键、ABC_<协议>_RUN_*环境变量与--set run.*
+func loadRunConfig(protocol string, args []string) (runConfig, error) {
+	section := runConfigSection{Run: defaultRunConfig()}
+	files, sets, err := configLayerArgs(args)
+	if err != nil {
+		return section.Run, err
+	}
+	for _, file := range files {
+		if err := decodeRunConfigFile(protocol, file, &section); err != nil {
+			return section.Run, err
+		}
+	}
+	if err := applyConfigEnv(protocol, &section); err != nil {
+		return section.Run, err
+	}
+	for _, set := range sets {
+		if isRunConfigSet(set) {
+			if err := applyConfigSet(set, &section); err != nil {
+				return section.Run, err
+			}
+		}
+	}
+	return section.Run, nil
+}
+
+// isRunConfigSet --set是否作用于run段
+func isRunConfigSet(set string) bool {
+	path, _, _ := strings.Cut(set, "=")
+	return path == runConfigKey || strings.HasPrefix(path, runConfigKey+".")
+}
+
+// args 将与默认值不同的选项转换为等价的命令行参数，使配置与参数经过相同的校验
+func (c runConfig) args() []string {
+	var args []string
+	add := func(flag, value string) {
+		args = append(args, flag, value)
+	}
+	if c.Prefill != 0 {
+		add("--prefill", strconv.Itoa(c.Prefill))
+	}
+	if c.Engine != "" {
+		add("--engine", c.Engine)
+	}
+	if c.Cores != 0 {
+		add("--cores", strconv.Itoa(c.Cores))
+	}
+	if c.Abort.ErrorRate != "" {
+		add("--abort-error-rate", c.Abort.ErrorRate)
+	}
+	if c.Abort.MaxErrors != 0 {
+		add("--abort-max-errors", strconv.FormatInt(c.Abort.MaxErrors, 10))
+	}
+	if c.Abort.ConsecutiveErrors != 0 {
+		add("--abort-consecutive-errors", strconv.FormatInt(c.Abort.ConsecutiveErrors, 10))
+	}
+	if c.RawSamples != "" {
+		add("--raw-samples", c.RawSamples)
+	}
+	if c.PartialReport.Path != "" {
+		add("--partial-report", c.PartialReport.Path)
+	}
+	if c.PartialReport.Interval != 0 {
+		add("--partial-interval", c.PartialReport.Interval.String())
+	}
+	if c.Checkpoint.Path != "" {
+		add("--checkpoint", c.Checkpoint.Path)
+	}
+	if c.Checkpoint.Interval != 0 {
+		add("--checkpoint-interval", c.Checkpoint.Interval.String())
+	}
+	for _, tag := range c.Tags {
+		add("--tag", tag)
+	}
+	if c.ReportLocale != "" {
+		add("--report-locale", c.ReportLocale)
+	}
+	for _, spec := range c.Intercept {
+		add("--intercept", spec)
+	}
+	if c.Push.Gateway != "" {
+		add("--push-gateway", c.Push.Gateway)
+	}
+	if c.Push.RemoteWrite != "" {
+		add("--push-remote-write", c.Push.RemoteWrite)
+	}
+	if c.Trace.Context {
+		args = append(args, "--trace-context")
+	}
+	if c.Trace.Sample != 1 {
+		add("--trace-sample", strconv.FormatFloat(c.Trace.Sample, 'g', -1, 64))
+	}
+	if c.Trace.OTLP != "" {
+		add("--trace-otlp", c.Trace.OTLP)
+	}
+	return args
+}
+
+// effectiveConfig 合并全部来源后的运行选项，供--print-config输出
+func (o *runOptions) effectiveConfig() runConfig {
+	config := runConfig{
+		Prefill:      o.prefill,
+		Engine:       o.engine,
+		Cores:        o.cores,
+		RawSamples:   o.rawSamplesPath,
+		Tags:         o.tags,
+		ReportLocale: o.reportLocale,
+		Intercept:    o.interceptorSpecs,
+		Abort: runAbortConfig{
+			MaxErrors:         o.stopConditions.MaxErrors,
+			ConsecutiveErrors: o.stopConditions.ConsecutiveErrors,
+		},
+		PartialReport: runPeriodicFile{Path: o.partialReportPath, Interval: o.partialReportInterval},
+		Checkpoint:    runPeriodicFile{Path: o.checkpointPath, Interval: o.checkpointInterval},
+		Push:          runPushConfig{Gateway: o.pushGateway, RemoteWrite: o.pushRemoteWrite},
+		Trace:         runTraceConfig{Context: o.traceContext, Sample: o.traceSampleRatio, OTLP: o.traceOTLPEndpoint},
+	}
+	if o.stopConditions.ErrorRate > 0 {
+		config.Abort.ErrorRate = fmt.Sprintf("%g%%@%s", o.stopConditions.ErrorRate*100, o.stopConditions.ErrorRateWindow)
+	}
+	return config
+}
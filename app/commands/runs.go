@@ -0,0 +1,491 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"abc-runner/app/reporting"
+)
+
+// RunsCommandHandler 本地运行历史管理命令处理器
+type RunsCommandHandler struct{}
+
+// NewRunsCommandHandler 创建运行历史管理命令处理器
+func NewRunsCommandHandler() *RunsCommandHandler {
+	return &RunsCommandHandler{}
+}
+
+// chartBarWidth 指标图表中条形的最大宽度
+const chartBarWidth = 30
+
+// historyMetricUnits 各指标的单位
+var historyMetricUnits = map[string]string{
+	"rps": "ops/sec", "error_rate": "%", "read_mbps": "MB/s", "write_mbps": "MB/s",
+	"avg": "ms", "p50": "ms", "p90": "ms", "p95": "ms", "p99": "ms", "max": "ms",
+}
+
+// sparkLevels 走势图使用的字符，从低到高
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// runsArgs 运行历史命令参数
+type runsArgs struct {
+	action     string
+	metric     string
+	ids        []string
+	filter     reporting.HistoryFilter
+	dir        string
+	limit      int
+	jsonOutput bool
+	reports    bool
+	all        bool
+	dryRun     bool
+}
+
+// Execute 执行运行历史子命令
+func (h *RunsCommandHandler) Execute(ctx context.Context, args []string) error {
+	for _, arg := range args {
+		if arg == "--help" || arg == "-h" || arg == "help" {
+			fmt.Println(h.GetHelp())
+			return nil
+		}
+	}
+
+	parsed, err := h.parseArgs(args, time.Now())
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	store := reporting.NewHistoryStore(reporting.DefaultHistoryDir)
+	if parsed.dir != "" {
+		store = reporting.NewHistoryStore(parsed.dir)
+	} else if fromCtx, ok := reporting.HistoryStoreFromContext(ctx); ok {
+		store = fromCtx
+	}
+
+	switch parsed.action {
+	case "list":
+		return h.list(store, parsed)
+	case "show":
+		return h.show(store, parsed)
+	case "chart":
+		return h.chart(store, parsed)
+	default:
+		return h.delete(store, parsed)
+	}
+}
+
+// list 列出满足条件的运行
+func (h *RunsCommandHandler) list(store *reporting.HistoryStore, parsed *runsArgs) error {
+	records, err := store.List(parsed.filter)
+	if err != nil {
+		return err
+	}
+	if parsed.limit > 0 && len(records) > parsed.limit {
+		records = records[:parsed.limit]
+	}
+
+	if parsed.jsonOutput {
+		return printJSON(records)
+	}
+	if len(records) == 0 {
+		fmt.Printf("No runs found in %s\n", store.Path())
+		return nil
+	}
+
+	fmt.Printf("%-22s %-19s %-12s %-18s %10s %10s %8s  %s\n",
+		"ID", "STARTED", "PROTOCOL", "STATUS", "RPS", "P99(ms)", "ERR%", "TAGS")
+	for _, record := range records {
+		rps, p99, errorRate := "-", "-", "-"
+		if record.Summary != nil {
+			rps = fmt.Sprintf("%.1f", record.Summary.RPS)
+			p99 = fmt.Sprintf("%.2f", record.Summary.P99)
+			errorRate = fmt.Sprintf("%.2f", record.Summary.ErrorRate)
+		}
+		fmt.Printf("%-22s %-19s %-12s %-18s %10s %10s %8s  %s\n",
+			record.ID, record.StartedAt.Local().Format("2006-01-02 15:04:05"), record.Protocol(),
+			record.Status, rps, p99, errorRate, strings.Join(record.Tags, ","))
+	}
+	fmt.Printf("\n%d run(s)\n", len(records))
+	return nil
+}
+
+// show 输出单次运行的详细信息
+func (h *RunsCommandHandler) show(store *reporting.HistoryStore, parsed *runsArgs) error {
+	record, err := store.Load(parsed.ids[0])
+	if err != nil {
+		return NewConfigError(err)
+	}
+	if parsed.jsonOutput {
+		return printJSON(record)
+	}
+
+	fmt.Printf("Run:        %s\n", record.ID)
+	fmt.Printf("Command:    abc-runner %s %s\n", record.Command, strings.Join(record.Args, " "))
+	fmt.Printf("Started:    %s\n", record.StartedAt.Local().Format(time.RFC3339))
+	fmt.Printf("Elapsed:    %.1fs\n", record.Elapsed)
+	fmt.Printf("Status:     %s (exit code %d)\n", record.Status, record.ExitCode)
+	if record.Error != "" {
+		fmt.Printf("Error:      %s\n", record.Error)
+	}
+	if len(record.Tags) > 0 {
+		fmt.Printf("Tags:       %s\n", strings.Join(record.Tags, ", "))
+	}
+	if summary := record.Summary; summary != nil {
+		fmt.Println()
+		fmt.Printf("Protocol:   %s\n", summary.Protocol)
+		fmt.Printf("Duration:   %.1fs\n", summary.Duration)
+		fmt.Printf("Operations: %d total, %d successful, %d failed (%.2f%% errors)\n",
+			summary.Total, summary.Successful, summary.Failed, summary.ErrorRate)
+		fmt.Printf("Throughput: %.1f ops/sec\n", summary.RPS)
+		fmt.Printf("Latency:    avg %.2fms, p50 %.2fms, p95 %.2fms, p99 %.2fms, max %.2fms\n",
+			summary.AvgLatency, summary.P50, summary.P95, summary.P99, summary.MaxLatency)
+		if summary.SLAChecked > 0 {
+			fmt.Printf("SLA:        %d/%d rule(s) passed\n", summary.SLAChecked-summary.SLAFailed, summary.SLAChecked)
+		}
+	}
+	if len(record.Reports) > 0 {
+		fmt.Println()
+		fmt.Println("Reports:")
+		for _, report := range record.Reports {
+			fmt.Printf("  %s\n", report)
+		}
+	}
+	return nil
+}
+
+// chartPoint 图表中一次运行的指标值
+type chartPoint struct {
+	ID        string    `json:"id"`
+	StartedAt time.Time `json:"started_at"`
+	Value     float64   `json:"value"`
+}
+
+// chart 按时间从旧到新绘制满足条件的运行的指标，并给出最后一次相对首次与中位数的变化
+func (h *RunsCommandHandler) chart(store *reporting.HistoryStore, parsed *runsArgs) error {
+	records, err := store.List(parsed.filter)
+	if err != nil {
+		return err
+	}
+	var points []chartPoint
+	for _, record := range records {
+		if parsed.limit > 0 && len(points) == parsed.limit {
+			break
+		}
+		if value, ok := record.Metric(parsed.metric); ok {
+			points = append(points, chartPoint{ID: record.ID, StartedAt: record.StartedAt, Value: value})
+		}
+	}
+	slices.Reverse(points)
+
+	if parsed.jsonOutput {
+		return printJSON(points)
+	}
+	if len(points) == 0 {
+		fmt.Printf("No runs with %s found in %s\n", parsed.metric, store.Path())
+		return nil
+	}
+
+	values := make([]float64, len(points))
+	for i, point := range points {
+		values[i] = point.Value
+	}
+	peak := slices.Max(values)
+	first := values[0]
+
+	fmt.Printf("%s (%s) across %d run(s), oldest first\n\n", parsed.metric, historyMetricUnits[parsed.metric], len(points))
+	for _, point := range points {
+		filled := 0
+		if peak > 0 {
+			filled = int(point.Value / peak * chartBarWidth)
+		}
+		fmt.Printf("%-22s %-19s %10.2f  %s%s  %s\n",
+			point.ID, point.StartedAt.Local().Format("2006-01-02 15:04:05"), point.Value,
+			strings.Repeat("█", filled), strings.Repeat(" ", chartBarWidth-filled), formatChange(point.Value, first))
+	}
+
+	last := values[len(values)-1]
+	fmt.Printf("\nTrend:  %s\n", sparkline(values, peak))
+	fmt.Printf("Range:  min %.2f, median %.2f, max %.2f\n", slices.Min(values), median(values), peak)
+	if len(values) > 1 {
+		fmt.Printf("Drift:  last %.2f is %s vs first run, %s vs median of earlier runs\n",
+			last, formatChange(last, first), formatChange(last, median(values[:len(values)-1])))
+	}
+	return nil
+}
+
+// formatChange 相对基准值的百分比变化，基准为0时无法计算
+func formatChange(value, base float64) string {
+	if base == 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%+.1f%%", (value-base)/base*100)
+}
+
+// median 中位数，不修改输入
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// sparkline 按峰值缩放的走势图
+func sparkline(values []float64, peak float64) string {
+	runes := make([]rune, len(values))
+	for i, value := range values {
+		level := 0
+		if peak > 0 && value > 0 {
+			level = int(value / peak * float64(len(sparkLevels)-1))
+		}
+		runes[i] = sparkLevels[level]
+	}
+	return string(runes)
+}
+
+// delete 删除指定ID或满足条件的运行
+func (h *RunsCommandHandler) delete(store *reporting.HistoryStore, parsed *runsArgs) error {
+	var records []*reporting.RunRecord
+	if len(parsed.ids) > 0 {
+		for _, id := range parsed.ids {
+			record, err := store.Load(id)
+			if err != nil {
+				return NewConfigError(err)
+			}
+			records = append(records, record)
+		}
+	} else {
+		var err error
+		if records, err = store.List(parsed.filter); err != nil {
+			return err
+		}
+	}
+
+	if len(records) == 0 {
+		fmt.Println("No runs matched")
+		return nil
+	}
+	for _, record := range records {
+		if parsed.dryRun {
+			fmt.Printf("Would delete %s (%s, %s)\n", record.ID, record.Protocol(), record.Status)
+			continue
+		}
+		if err := store.Delete(record, parsed.reports); err != nil {
+			return err
+		}
+		fmt.Printf("🗑️  Deleted %s\n", record.ID)
+	}
+	if parsed.dryRun {
+		fmt.Printf("%d run(s) would be deleted\n", len(records))
+	} else {
+		fmt.Printf("✅ Deleted %d run(s)\n", len(records))
+	}
+	return nil
+}
+
+// printJSON 以缩进JSON输出
+func printJSON(value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode runs: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// parseArgs 解析命令行参数，now用于解析相对时间
+func (h *RunsCommandHandler) parseArgs(args []string, now time.Time) (*runsArgs, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("expected a subcommand: list, show, chart or delete")
+	}
+	parsed := &runsArgs{action: args[0]}
+	switch parsed.action {
+	case "list", "ls":
+		parsed.action = "list"
+	case "show", "chart":
+	case "delete", "rm":
+		parsed.action = "delete"
+	default:
+		return nil, fmt.Errorf("unknown subcommand %q (expected list, show, chart or delete)", args[0])
+	}
+
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--json":
+			parsed.jsonOutput = true
+			continue
+		case "--passed":
+			parsed.filter.Status = reporting.HistoryPassed
+			continue
+		case "--failed":
+			parsed.filter.Status = reporting.HistoryFailed
+			continue
+		case "--reports":
+			parsed.reports = true
+			continue
+		case "--all":
+			parsed.all = true
+			continue
+		case "--dry-run":
+			parsed.dryRun = true
+			continue
+		case "--protocol", "-p", "--tag", "--since", "--until", "--limit", "--dir":
+		default:
+			if strings.HasPrefix(args[i], "-") {
+				return nil, fmt.Errorf("unknown option %s", args[i])
+			}
+			parsed.ids = append(parsed.ids, args[i])
+			continue
+		}
+
+		if i+1 >= len(args) {
+			return nil, fmt.Errorf("missing value for %s", args[i])
+		}
+		value := args[i+1]
+
+		var err error
+		switch args[i] {
+		case "--protocol", "-p":
+			parsed.filter.Protocol = value
+		case "--tag":
+			parsed.filter.Tags = append(parsed.filter.Tags, value)
+		case "--since":
+			parsed.filter.Since, err = parseHistoryTime(value, now)
+		case "--until":
+			parsed.filter.Until, err = parseHistoryTime(value, now)
+		case "--limit":
+			parsed.limit, err = strconv.Atoi(value)
+			if err == nil && parsed.limit <= 0 {
+				err = fmt.Errorf("must be positive")
+			}
+		case "--dir":
+			parsed.dir = value
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", args[i], err)
+		}
+		i++
+	}
+
+	switch parsed.action {
+	case "list":
+		if len(parsed.ids) > 0 {
+			return nil, fmt.Errorf("unexpected argument %q for list", parsed.ids[0])
+		}
+	case "show":
+		if len(parsed.ids) != 1 {
+			return nil, fmt.Errorf("expected exactly one run ID, got %d argument(s)", len(parsed.ids))
+		}
+	case "chart":
+		if len(parsed.ids) != 1 {
+			return nil, fmt.Errorf("expected exactly one metric (%s), got %d argument(s)",
+				strings.Join(reporting.HistoryMetrics, ", "), len(parsed.ids))
+		}
+		parsed.metric, parsed.ids = strings.ToLower(parsed.ids[0]), nil
+		if !slices.Contains(reporting.HistoryMetrics, parsed.metric) {
+			return nil, fmt.Errorf("unknown metric %q (expected one of %s)",
+				parsed.metric, strings.Join(reporting.HistoryMetrics, ", "))
+		}
+	case "delete":
+		hasFilter := !parsed.filter.Empty()
+		if len(parsed.ids) > 0 && (hasFilter || parsed.all) {
+			return nil, fmt.Errorf("run IDs cannot be combined with filters or --all")
+		}
+		if len(parsed.ids) == 0 && !hasFilter && !parsed.all {
+			return nil, fmt.Errorf("specify run IDs, at least one filter, or --all")
+		}
+	}
+	return parsed, nil
+}
+
+// parseHistoryTime 解析日期时间：2006-01-02、2006-01-02T15:04、RFC3339，
+// 或相对于now的时长（如24h、7d）
+func parseHistoryTime(value string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	for _, layout := range []string{"2006-01-02", "2006-01-02T15:04", "2006-01-02 15:04"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("expected a date (2006-01-02), RFC3339 time or age (24h, 7d), got %q", value)
+}
+
+// GetHelp 获取帮助信息
+func (h *RunsCommandHandler) GetHelp() string {
+	return `Run History
+
+USAGE:
+  abc-runner runs list [filters] [--limit N] [--json]
+  abc-runner runs show ID [--json]
+  abc-runner runs chart METRIC [filters] [--limit N] [--json]
+  abc-runner runs delete (ID... | filters | --all) [--reports] [--dry-run]
+
+  "abc-runner history" is an alias of "abc-runner runs".
+
+DESCRIPTION:
+  Every run is recorded in the local history, a SQLite database
+  (reports/history/history.db, see the global --history-dir option), with its
+  command line, tags, top-line numbers, the report files it wrote and the full
+  structured report. Runs are listed newest first; IDs may be abbreviated to
+  any unique prefix.
+
+  chart plots one metric across the matching runs, oldest first, and reports
+  how the latest run compares with the first run and with the median of the
+  earlier runs, to spot long-term performance drift. Filter by protocol and
+  tag so that only comparable runs are charted.
+
+METRICS:
+  rps                     Throughput (ops/sec)
+  avg, p50, p90, p95,     Latency (ms); p90 needs runs recorded with the
+  p99, max                full report
+  error_rate              Error rate (%)
+  read_mbps, write_mbps   Bandwidth (MB/s); needs the full report
+
+FILTERS:
+  --protocol, -p NAME     Protocol or command name (redis, http, compare, ...)
+  --tag TAG               Runs tagged TAG (set with --tag on a test run);
+                          repeat to require several tags
+  --since TIME            Runs started at or after TIME
+  --until TIME            Runs started before TIME
+                          TIME is a date (2026-01-31), a local time
+                          (2026-01-31T14:00), RFC3339, or an age (12h, 7d)
+  --passed                Only runs that passed
+  --failed                Only runs that failed (any non-zero exit code)
+
+OPTIONS:
+  --help, -h              Show this help message
+  --dir DIR               History directory holding history.db (default:
+                          reports/history)
+  --limit N               Show at most N runs (list), chart the N most
+                          recent runs (chart)
+  --json                  Print records as JSON (list, show, chart)
+  --all                   Delete every run (delete)
+  --reports               Also delete the report files of deleted runs
+  --dry-run               Show what would be deleted without deleting
+
+EXAMPLES:
+  abc-runner runs list --protocol redis --tag nightly --since 7d
+  abc-runner runs list --failed --limit 10
+  abc-runner runs show 20260131-140502
+  abc-runner history chart p99 --protocol redis --tag nightly --since 30d
+  abc-runner runs delete --until 30d --reports
+`
+}
@@ -0,0 +1,431 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	s3Config "abc-runner/app/adapters/s3/config"
+	"abc-runner/app/adapters/s3/operations"
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/reporting"
+)
+
+// S3CommandHandler S3兼容对象存储命令处理器
+type S3CommandHandler struct {
+	protocolName string
+	factory      interfaces.S3AdapterFactory
+}
+
+// NewS3CommandHandler 创建S3命令处理器
+func NewS3CommandHandler(factory interfaces.S3AdapterFactory) *S3CommandHandler {
+	if factory == nil {
+		panic("s3AdapterFactory cannot be nil - dependency injection required")
+	}
+
+	return &S3CommandHandler{
+		protocolName: "s3",
+		factory:      factory,
+	}
+}
+
+// Execute 执行S3命令
+func (h *S3CommandHandler) Execute(ctx context.Context, args []string) error {
+	// 检查帮助请求
+	for _, arg := range args {
+		if arg == "--help" || arg == "help" || arg == "-h" {
+			fmt.Println(h.GetHelp())
+			return nil
+		}
+	}
+
+	// 解析命令行参数
+	config, err := h.parseArgs(args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	// 解析通用运行选项
+	opts, err := parseRunOptions(ctx, "s3", args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+	if printConfigRequested(args) {
+		return printEffectiveConfig("s3", config, opts)
+	}
+
+	metricsCollector := metrics.NewBaseCollector(opts.collectorConfig(), map[string]interface{}{
+		"protocol":  "s3",
+		"test_type": "performance",
+	})
+	defer metricsCollector.Stop()
+
+	adapter := h.factory.CreateS3Adapter()
+	if adapter == nil {
+		return fmt.Errorf("failed to create S3 adapter")
+	}
+	defer func() {
+		if err := adapter.Close(); err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+		}
+	}()
+
+	endpoint := config.Connection.Endpoint
+	if err := adapter.Connect(ctx, config); err != nil {
+		return NewConnectionError(fmt.Errorf("failed to connect to s3 %s: %w", endpoint, err))
+	}
+	if err := adapter.HealthCheck(ctx); err != nil {
+		return NewConnectionError(fmt.Errorf("s3 health check failed: %w", err))
+	}
+
+	specific := config.S3Specific
+	fmt.Printf("✅ Connected to %s, bucket %s\n", endpoint, specific.Bucket)
+	fmt.Printf("🚀 Starting S3 performance test...\n")
+	fmt.Printf("Test Case: %s\n", config.BenchMark.TestCase)
+	fmt.Printf("Prefix: %s, Objects: %d\n", specific.Prefix, specific.Objects)
+	if config.BenchMark.TestCase != s3Config.OperationList {
+		fmt.Printf("Object Sizes: %s\n", describeObjectSizes(specific.ObjectSizes))
+		fmt.Printf("Multipart: objects of %s or more, %s parts, %d parts in parallel\n",
+			s3Config.FormatSize(specific.MultipartThreshold), s3Config.FormatSize(specific.PartSize), specific.PartConcurrency)
+	}
+	if config.BenchMark.TestCase == s3Config.TestCaseMixed {
+		fmt.Printf("Read Percent: %d%%\n", config.BenchMark.ReadPercent)
+	}
+	fmt.Printf("Operations: %d, Concurrency: %d\n", config.BenchMark.Total, config.BenchMark.Parallels)
+
+	if err := h.runPerformanceTest(ctx, adapter, config, metricsCollector, opts); err != nil {
+		return fmt.Errorf("performance test failed: %w", err)
+	}
+
+	return h.generateReport(metricsCollector, opts)
+}
+
+// describeObjectSizes 描述对象大小分布，如 "4KiB (60%), 1MiB-4MiB (40%)"
+func describeObjectSizes(sizes []s3Config.ObjectSize) string {
+	if len(sizes) == 1 {
+		return sizes[0].String()
+	}
+	total := 0
+	for _, size := range sizes {
+		total += size.Weight
+	}
+	parts := make([]string, len(sizes))
+	for i, size := range sizes {
+		parts[i] = fmt.Sprintf("%s (%.0f%%)", size, float64(size.Weight)/float64(total)*100)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// GetHelp 获取帮助信息
+func (h *S3CommandHandler) GetHelp() string {
+	return `S3-Compatible Object Storage Performance Testing
+
+USAGE:
+  abc-runner s3 [options]
+
+DESCRIPTION:
+  Upload, download, delete and list objects on AWS S3, MinIO or any other
+  S3-compatible store, and report bytes/sec per operation and
+  time-to-first-byte for downloads. Requests are signed with AWS Signature
+  Version 4; without credentials they are sent anonymously.
+
+  Operation N works on object N modulo --objects under --prefix. Object
+  sizes are drawn from a weighted distribution; objects at or above the
+  multipart threshold are uploaded in parts, several parts at a time.
+
+OPTIONS:
+  --help                       Show this help message
+  --endpoint URL               Service endpoint (default: http://localhost:9000)
+                               AWS: https://s3.REGION.amazonaws.com
+  --region REGION              Signing region (default: us-east-1)
+  --access-key KEY             Access key (default: $AWS_ACCESS_KEY_ID)
+  --secret-key KEY             Secret key (default: $AWS_SECRET_ACCESS_KEY)
+  --session-token TOKEN        Session token (default: $AWS_SESSION_TOKEN)
+  --virtual-hosted             Address buckets as BUCKET.endpoint instead of
+                               endpoint/BUCKET
+  --insecure, -k               Skip TLS certificate verification
+  --timeout DURATION           Request timeout (default: 60s)
+  --test-case CASE             put (default), get, delete, list or mixed
+  --bucket NAME                Bucket (default: abc-bench)
+  --prefix PREFIX              Object key prefix (default: abc-bench/)
+  --objects N                  Object key space (default: 1000)
+  --object-size SPEC           Object size distribution (default: 64KiB), e.g.
+                               4KiB:60,1MiB:30,16MiB-64MiB:10
+  --multipart-threshold SIZE   Upload objects this large in parts (default: 64MiB)
+  --part-size SIZE             Part size, at least 5MiB (default: 16MiB)
+  --part-concurrency N         Parts uploaded in parallel per object (default: 4)
+  --list-max-keys N            Keys per list page, at most 1000 (default: 1000)
+  --read-percent N             Share of gets in a mixed test (default: 50)
+  --create-bucket              Create the bucket before the run if it is missing
+  --cleanup                    Delete every object under the prefix after the run
+  -n COUNT                     Total operations (default: 1000)
+  -c COUNT                     Concurrent workers (default: 10)
+  --duration DURATION          Run for a fixed duration instead of -n
+
+NOTES:
+  Sizes accept B, KiB, MiB and GiB (KB, MB and GB are read as the same
+  binary units). In a size distribution each entry is SIZE or MIN-MAX with
+  an optional :WEIGHT; a range picks a size uniformly within it.
+  A multipart upload counts as one operation; its latency covers creating
+  the upload, all parts and completing it. A failed part aborts the upload.
+  get and mixed need existing objects: use --prefill N to upload N objects
+  before measurement (N should match --objects).
+  Time to first byte is measured from sending a get to the first byte of
+  the response, before the body is read.
+
+EXAMPLES:
+  abc-runner s3 --help
+  abc-runner s3 --access-key minioadmin --secret-key minioadmin --create-bucket -n 1000
+  abc-runner s3 --test-case get --prefill 1000 --objects 1000 --object-size 1MiB -c 32
+  abc-runner s3 --endpoint https://s3.eu-west-1.amazonaws.com --region eu-west-1 \
+    --virtual-hosted --bucket my-bench --object-size 4KiB:60,1MiB:30,64MiB-256MiB:10 \
+    --part-concurrency 8 --duration 5m --cleanup
+  abc-runner s3 --test-case list --list-max-keys 100 -n 500` + configLayersHelp + runOptionsHelp + "\n"
+}
+
+// parseArgs 解析命令行参数，未指定凭证时读取AWS环境变量
+func (h *S3CommandHandler) parseArgs(args []string) (*s3Config.S3Config, error) {
+	config := s3Config.NewDefaultS3Config()
+	specific := &config.S3Specific
+
+	// 依次应用--config文件、ABC_S3_*环境变量与--set，专用参数随后覆盖
+	if err := applyConfigLayers("s3", args, config); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(args); i++ {
+		// 无参数的开关
+		switch args[i] {
+		case "--virtual-hosted":
+			config.Connection.PathStyle = false
+			continue
+		case "--insecure", "-k":
+			config.Connection.InsecureSkipVerify = true
+			continue
+		case "--create-bucket":
+			specific.CreateBucket = true
+			continue
+		case "--cleanup":
+			specific.Cleanup = true
+			continue
+		}
+
+		if i+1 >= len(args) {
+			break
+		}
+		value := args[i+1]
+		var err error
+
+		switch args[i] {
+		case "--endpoint":
+			config.Connection.Endpoint = value
+		case "--region":
+			config.Connection.Region = value
+		case "--access-key":
+			config.Connection.AccessKey = value
+		case "--secret-key":
+			config.Connection.SecretKey = value
+		case "--session-token":
+			config.Connection.SessionToken = value
+		case "--timeout":
+			config.Connection.Timeout, err = time.ParseDuration(value)
+		case "--test-case":
+			config.BenchMark.TestCase = strings.ToLower(value)
+		case "--bucket":
+			specific.Bucket = value
+		case "--prefix":
+			specific.Prefix = value
+		case "--objects":
+			specific.Objects, err = strconv.Atoi(value)
+		case "--object-size":
+			specific.ObjectSizes, err = s3Config.ParseObjectSizes(value)
+		case "--multipart-threshold":
+			specific.MultipartThreshold, err = s3Config.ParseSize(value)
+		case "--part-size":
+			specific.PartSize, err = s3Config.ParseSize(value)
+		case "--part-concurrency":
+			specific.PartConcurrency, err = strconv.Atoi(value)
+		case "--list-max-keys":
+			specific.ListMaxKeys, err = strconv.Atoi(value)
+		case "--read-percent":
+			config.BenchMark.ReadPercent, err = strconv.Atoi(value)
+		case "-n":
+			config.BenchMark.Total, err = strconv.Atoi(value)
+		case "-c":
+			config.BenchMark.Parallels, err = strconv.Atoi(value)
+		case "--duration":
+			config.BenchMark.Duration, err = time.ParseDuration(value)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", args[i], err)
+		}
+		i++
+	}
+
+	if config.Connection.AccessKey == "" && config.Connection.SecretKey == "" {
+		config.Connection.AccessKey = os.Getenv("AWS_ACCESS_KEY_ID")
+		config.Connection.SecretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		if config.Connection.SessionToken == "" {
+			config.Connection.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+		}
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// runPerformanceTest 运行S3性能测试
+func (h *S3CommandHandler) runPerformanceTest(
+	ctx context.Context,
+	adapter interfaces.ProtocolAdapter,
+	config *s3Config.S3Config,
+	collector *metrics.BaseCollector[map[string]interface{}],
+	opts *runOptions,
+) error {
+	factory := operations.NewOperationFactory(config)
+	engine := execution.NewExecutionEngine(adapter, collector, factory)
+	engine.SetMaxWorkers(config.BenchMark.Parallels)
+
+	// 预填充测试对象（不计入指标）
+	if err := opts.runPrefill(ctx, engine, collector, config.BenchMark.Parallels); err != nil {
+		return err
+	}
+	opts.applyToEngine(engine, collector)
+
+	testStartTime := time.Now()
+	result, err := engine.RunBenchmark(ctx, &config.BenchMark)
+	if err != nil {
+		return fmt.Errorf("benchmark execution failed: %w", err)
+	}
+	opts.finishRun()
+	actualTestDuration := time.Since(testStartTime)
+
+	fmt.Printf("\n📊 Execution Results:\n")
+	fmt.Printf("Total Jobs: %d\n", result.TotalJobs)
+	fmt.Printf("Completed Jobs: %d\n", result.CompletedJobs)
+	fmt.Printf("Success Jobs: %d\n", result.SuccessJobs)
+	fmt.Printf("Failed Jobs: %d\n", result.FailedJobs)
+	fmt.Printf("Actual Test Duration: %v\n", actualTestDuration)
+
+	specific := config.S3Specific
+	protocolMetrics := map[string]interface{}{
+		"protocol":            "s3",
+		"test_type":           "performance",
+		"test_case":           config.BenchMark.TestCase,
+		"target":              config.Connection.Endpoint,
+		"bucket":              specific.Bucket,
+		"prefix":              specific.Prefix,
+		"object_sizes":        describeObjectSizes(specific.ObjectSizes),
+		"multipart_threshold": specific.MultipartThreshold,
+		"part_size":           specific.PartSize,
+		"actual_duration":     actualTestDuration,
+		"execution_result":    result,
+	}
+
+	if statsAdapter, ok := adapter.(interface {
+		GetOperationStats() []operations.OperationStats
+		GetFirstByteLatency() *metrics.LatencyMetrics
+		GetUploadedParts() int64
+	}); ok {
+		if stats := statsAdapter.GetOperationStats(); len(stats) > 0 {
+			printS3OperationStats(stats, actualTestDuration, statsAdapter.GetUploadedParts())
+			protocolMetrics["operation_stats"] = stats
+			var bytes int64
+			for _, s := range stats {
+				if s.Type == s3Config.OperationPut || s.Type == s3Config.OperationGet {
+					bytes += s.Volume
+				}
+			}
+			protocolMetrics["bytes_per_sec"] = float64(bytes) / actualTestDuration.Seconds()
+		}
+		if latency := statsAdapter.GetFirstByteLatency(); latency != nil {
+			fmt.Printf("\n⏱️  Time to First Byte (get): avg %v, p50 %v, p95 %v, p99 %v, max %v\n",
+				latency.Average, latency.P50, latency.P95, latency.P99, latency.Max)
+			protocolMetrics["first_byte_latency"] = *latency
+		}
+	}
+
+	collector.UpdateProtocolMetrics(protocolMetrics)
+	return nil
+}
+
+// printS3OperationStats 打印按操作类型的统计表，list的数据量为返回的对象数，不计入MB/S
+func printS3OperationStats(stats []operations.OperationStats, duration time.Duration, parts int64) {
+	fmt.Printf("\n📋 Per-Operation Statistics:\n")
+	fmt.Printf("  %-8s %10s %10s %8s %12s %10s %10s %10s\n",
+		"TYPE", "COUNT", "OPS/S", "ERR%", "MB/S", "P50", "P95", "P99")
+	for _, s := range stats {
+		throughput := "-"
+		if s.Type == s3Config.OperationPut || s.Type == s3Config.OperationGet {
+			throughput = fmt.Sprintf("%.2f", float64(s.Volume)/duration.Seconds()/1024/1024)
+		}
+		fmt.Printf("  %-8s %10d %10.1f %8.2f %12s %10v %10v %10v\n",
+			s.Type, s.Count, float64(s.Count)/duration.Seconds(), s.ErrorRate,
+			throughput, s.Latency.P50, s.Latency.P95, s.Latency.P99)
+		if s.Type == s3Config.OperationList && s.Count > s.Errors {
+			fmt.Printf("  %-8s   keys/page: %.1f\n", "", float64(s.Volume)/float64(s.Count-s.Errors))
+		}
+		if s.Flagged > 0 {
+			fmt.Printf("  %-8s   multipart: %d (%d parts)\n", "", s.Flagged, parts)
+		}
+		for code, n := range s.ErrorCodes {
+			fmt.Printf("  %-8s   %s: %d\n", "", code, n)
+		}
+	}
+}
+
+// generateReport 生成S3测试报告
+func (h *S3CommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
+	snapshot := collector.Snapshot()
+
+	// 使用实际测试时间修正吞吐量
+	if duration, ok := snapshot.Protocol["actual_duration"].(time.Duration); ok && duration > 0 {
+		snapshot.Core.Duration = duration
+		seconds := duration.Seconds()
+		snapshot.Core.Throughput.RPS = float64(snapshot.Core.Operations.Total) / seconds
+		snapshot.Core.Throughput.ReadRPS = float64(snapshot.Core.Operations.Read) / seconds
+		snapshot.Core.Throughput.WriteRPS = float64(snapshot.Core.Operations.Write) / seconds
+		snapshot.Core.Throughput.ReadMBps, snapshot.Core.Throughput.WriteMBps = metrics.Bandwidth(snapshot.Core.Operations, duration)
+	}
+
+	fmt.Printf("\n📊 S3 Performance Metrics:\n")
+	fmt.Printf("=====================================\n")
+	fmt.Printf("  Total Operations: %d\n", snapshot.Core.Operations.Total)
+	fmt.Printf("  Successful: %d\n", snapshot.Core.Operations.Success)
+	fmt.Printf("  Failed: %d\n", snapshot.Core.Operations.Failed)
+	fmt.Printf("  Success Rate: %.2f%%\n", snapshot.Core.Operations.Rate)
+	fmt.Printf("  Latency avg/p95/p99: %v/%v/%v\n",
+		snapshot.Core.Latency.Average, snapshot.Core.Latency.P95, snapshot.Core.Latency.P99)
+	fmt.Printf("  Throughput: %.2f ops/sec\n", snapshot.Core.Throughput.RPS)
+	if bytesPerSec, ok := snapshot.Protocol["bytes_per_sec"].(float64); ok {
+		fmt.Printf("  Data Throughput: %.2f MB/sec\n", bytesPerSec/1024/1024)
+	}
+	fmt.Printf("=====================================\n")
+
+	// 存在快照接收器时（如分布式agent模式）交由接收器处理，不生成本地报告
+	if opts.publishSnapshot(snapshot) {
+		return nil
+	}
+
+	report := reporting.ConvertFromMetricsSnapshot(snapshot)
+	reportConfig := reporting.NewStandardReportConfig("s3")
+	opts.applyToReport(snapshot, report, reportConfig)
+	generator := reporting.NewReportGenerator(reportConfig)
+	if err := generator.Generate(report); err != nil {
+		return err
+	}
+	return opts.resultError(report)
+}
+
+// GetProtocolName 获取协议名称
+func (h *S3CommandHandler) GetProtocolName() string {
+	return h.protocolName
+}
@@ -0,0 +1,483 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ServerCommandHandler 测试服务端套件命令处理器
+type ServerCommandHandler struct{}
+
+// NewServerCommandHandler 创建测试服务端套件命令处理器
+func NewServerCommandHandler() *ServerCommandHandler {
+	return &ServerCommandHandler{}
+}
+
+// labService 实验环境中的一个服务
+type labService struct {
+	name    string
+	port    int    // 容器内端口，也是默认的主机端口
+	udp     bool   // 端口协议为UDP
+	binary  string // servers模块中的服务端程序，为空时使用image
+	image   string // 第三方镜像
+	health  []string
+	example string // 对应的abc-runner命令，%d为主机端口
+}
+
+// labServers servers模块提供的测试服务端
+var labServers = []labService{
+	{name: "http", port: 8080, binary: "http-server",
+		health:  []string{"CMD", "wget", "-q", "-O", "/dev/null", "http://localhost:8080/health"},
+		example: "abc-runner http --url http://localhost:%d -n 10000 -c 50"},
+	{name: "tcp", port: 9090, binary: "tcp-server",
+		health:  []string{"CMD", "nc", "-z", "localhost", "9090"},
+		example: "abc-runner tcp --host localhost --port %d -n 10000 -c 50"},
+	{name: "udp", port: 9091, udp: true, binary: "udp-server",
+		example: "abc-runner udp --host localhost --port %d -n 10000 -c 50"},
+	{name: "grpc", port: 50051, binary: "grpc-server",
+		health:  []string{"CMD", "nc", "-z", "localhost", "50051"},
+		example: "abc-runner grpc --address localhost --port %d -n 10000 -c 50"},
+	{name: "websocket", port: 7070, binary: "websocket-server",
+		health:  []string{"CMD", "nc", "-z", "localhost", "7070"},
+		example: "abc-runner websocket --url ws://localhost:%d/ws -c 20"},
+}
+
+// labExtras 可选的第三方测试目标
+var labExtras = []labService{
+	{name: "redis", port: 6379, image: "redis:7-alpine",
+		health:  []string{"CMD", "redis-cli", "ping"},
+		example: "abc-runner redis --host localhost --port %d -n 100000 -c 50"},
+	{name: "kafka", port: 9092, image: "apache/kafka:3.8.0",
+		health:  []string{"CMD-SHELL", "/opt/kafka/bin/kafka-topics.sh --bootstrap-server localhost:9092 --list"},
+		example: "abc-runner kafka --brokers localhost:%d --topic bench --create-topic -n 10000"},
+}
+
+// labDockerfile 构建servers模块全部服务端程序的镜像，默认配置随镜像放在/app/config/servers
+const labDockerfile = `FROM golang:1.21-alpine AS build
+WORKDIR /src
+COPY . .
+RUN CGO_ENABLED=0 go build -o /out/ ./cmd/...
+
+FROM alpine:3.20
+WORKDIR /app
+COPY --from=build /out/ /usr/local/bin/
+COPY config ./config
+`
+
+// composeArgs server compose命令参数
+type composeArgs struct {
+	services      []labService
+	ports         map[string]int // 服务名 → 主机端口
+	bind          string
+	advertiseHost string
+	logLevel      string
+	source        string
+	image         string
+	build         bool
+	configDir     string
+	project       string
+	out           string
+	up            bool
+	down          bool
+}
+
+// composeFile docker-compose文件，字段顺序即输出顺序
+type composeFile struct {
+	Name     string                    `yaml:"name"`
+	Services map[string]composeService `yaml:"services"`
+}
+
+// composeService docker-compose服务
+type composeService struct {
+	Image       string            `yaml:"image"`
+	Build       *composeBuild     `yaml:"build,omitempty"`
+	Command     []string          `yaml:"command,omitempty"`
+	Environment map[string]string `yaml:"environment,omitempty"`
+	Ports       []string          `yaml:"ports"`
+	Volumes     []string          `yaml:"volumes,omitempty"`
+	Healthcheck *composeHealth    `yaml:"healthcheck,omitempty"`
+	Restart     string            `yaml:"restart"`
+}
+
+// composeBuild 镜像构建配置，Dockerfile内联在文件中（需要Compose 2.17及以上）
+type composeBuild struct {
+	Context          string `yaml:"context"`
+	DockerfileInline string `yaml:"dockerfile_inline"`
+}
+
+// composeHealth 健康检查
+type composeHealth struct {
+	Test     []string `yaml:"test"`
+	Interval string   `yaml:"interval"`
+	Timeout  string   `yaml:"timeout"`
+	Retries  int      `yaml:"retries"`
+}
+
+// Execute 执行server子命令
+func (h *ServerCommandHandler) Execute(ctx context.Context, args []string) error {
+	for _, arg := range args {
+		if arg == "--help" || arg == "-h" || arg == "help" {
+			fmt.Println(h.GetHelp())
+			return nil
+		}
+	}
+	if len(args) == 0 || args[0] != "compose" {
+		if len(args) == 0 {
+			return NewConfigError(fmt.Errorf("missing subcommand (expected compose)"))
+		}
+		return NewConfigError(fmt.Errorf("unknown subcommand %q (expected compose)", args[0]))
+	}
+
+	parsed, err := h.parseComposeArgs(args[1:])
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	if parsed.down {
+		return h.runCompose(ctx, parsed, "down")
+	}
+
+	data, err := h.renderCompose(parsed)
+	if err != nil {
+		return err
+	}
+	if parsed.out == "-" {
+		fmt.Print(string(data))
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(parsed.out), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(parsed.out), err)
+	}
+	if err := os.WriteFile(parsed.out, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", parsed.out, err)
+	}
+	fmt.Printf("✅ Compose file written to %s\n", parsed.out)
+
+	if parsed.up {
+		upArgs := []string{"up", "-d", "--wait"}
+		if parsed.build {
+			upArgs = append(upArgs, "--build")
+		}
+		if err := h.runCompose(ctx, parsed, upArgs...); err != nil {
+			return err
+		}
+	}
+	h.printEndpoints(parsed)
+	return nil
+}
+
+// parseComposeArgs 解析server compose参数
+func (h *ServerCommandHandler) parseComposeArgs(args []string) (*composeArgs, error) {
+	parsed := &composeArgs{
+		ports:         make(map[string]int),
+		advertiseHost: "localhost",
+		logLevel:      "info",
+		source:        "servers",
+		image:         "abc-runner-servers:latest",
+		build:         true,
+		project:       "abc-runner-lab",
+		out:           "docker-compose.yml",
+	}
+	protocols := "all"
+	var extras []string
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--up":
+			parsed.up = true
+			continue
+		case "--down":
+			parsed.down = true
+			continue
+		case "--no-build":
+			parsed.build = false
+			continue
+		}
+
+		if i+1 >= len(args) {
+			return nil, fmt.Errorf("missing value for %s", args[i])
+		}
+		value := args[i+1]
+		switch args[i] {
+		case "--protocols":
+			protocols = value
+		case "--with":
+			extras = strings.Split(value, ",")
+		case "--port":
+			name, port, ok := strings.Cut(value, "=")
+			number, err := strconv.Atoi(port)
+			if !ok || err != nil || number <= 0 || number > 65535 {
+				return nil, fmt.Errorf("invalid --port %q (expected SERVICE=PORT)", value)
+			}
+			parsed.ports[strings.ToLower(name)] = number
+		case "--bind":
+			parsed.bind = value
+		case "--advertise-host":
+			parsed.advertiseHost = value
+		case "--log-level":
+			parsed.logLevel = value
+		case "--source":
+			parsed.source = value
+		case "--image":
+			parsed.image = value
+		case "--config-dir":
+			parsed.configDir = value
+		case "--project", "-p":
+			parsed.project = value
+		case "--out", "-o":
+			parsed.out = value
+		default:
+			return nil, fmt.Errorf("unknown option %s", args[i])
+		}
+		i++
+	}
+
+	if parsed.up && parsed.down {
+		return nil, fmt.Errorf("--up and --down cannot be combined")
+	}
+	if (parsed.up || parsed.down) && parsed.out == "-" {
+		return nil, fmt.Errorf("--up and --down need a compose file, not standard output")
+	}
+
+	if protocols == "all" {
+		parsed.services = append(parsed.services, labServers...)
+	} else if protocols != "none" {
+		for _, name := range strings.Split(protocols, ",") {
+			service, ok := findLabService(labServers, strings.TrimSpace(strings.ToLower(name)))
+			if !ok {
+				return nil, fmt.Errorf("unknown protocol %q (expected %s)", name, labServiceNames(labServers))
+			}
+			parsed.services = append(parsed.services, service)
+		}
+	}
+	for _, name := range extras {
+		service, ok := findLabService(labExtras, strings.TrimSpace(strings.ToLower(name)))
+		if !ok {
+			return nil, fmt.Errorf("unknown --with target %q (expected %s)", name, labServiceNames(labExtras))
+		}
+		parsed.services = append(parsed.services, service)
+	}
+	if len(parsed.services) == 0 {
+		return nil, fmt.Errorf("no services selected")
+	}
+
+	// 主机端口不能冲突，--port只能指向已选择的服务
+	used := make(map[string]string)
+	for _, service := range parsed.services {
+		key := fmt.Sprintf("%d/%t", parsed.hostPort(service), service.udp)
+		if other, ok := used[key]; ok {
+			return nil, fmt.Errorf("%s and %s both publish port %d", other, service.name, parsed.hostPort(service))
+		}
+		used[key] = service.name
+	}
+	for name := range parsed.ports {
+		if _, ok := findLabService(parsed.services, name); !ok {
+			return nil, fmt.Errorf("--port %s: service %q is not selected", name, name)
+		}
+	}
+	return parsed, nil
+}
+
+// hostPort 服务发布到主机的端口
+func (a *composeArgs) hostPort(service labService) int {
+	if port, ok := a.ports[service.name]; ok {
+		return port
+	}
+	return service.port
+}
+
+// composePath 将当前目录下的相对路径转换为相对compose文件所在目录的路径
+func (a *composeArgs) composePath(path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	rel := path
+	if a.out != "-" {
+		var err error
+		if rel, err = filepath.Rel(filepath.Dir(a.out), path); err != nil {
+			return path
+		}
+	}
+	// compose要求相对路径以.开头，否则视为命名卷
+	if !strings.HasPrefix(rel, ".") {
+		rel = "./" + rel
+	}
+	return filepath.ToSlash(rel)
+}
+
+// renderCompose 生成docker-compose文件
+func (h *ServerCommandHandler) renderCompose(parsed *composeArgs) ([]byte, error) {
+	file := composeFile{Name: parsed.project, Services: make(map[string]composeService)}
+
+	// 构建上下文与配置目录相对于compose文件所在目录
+	buildContext := parsed.composePath(parsed.source)
+	configDir := parsed.composePath(parsed.configDir)
+
+	built := false
+	for _, service := range parsed.services {
+		port := fmt.Sprintf("%d:%d", parsed.hostPort(service), service.port)
+		if parsed.bind != "" {
+			port = parsed.bind + ":" + port
+		}
+		if service.udp {
+			port += "/udp"
+		}
+
+		result := composeService{
+			Image:   service.image,
+			Ports:   []string{port},
+			Restart: "unless-stopped",
+		}
+		if service.binary != "" {
+			result.Image = parsed.image
+			// 只有第一个服务构建镜像，其余服务复用，避免重复构建
+			if parsed.build && !built {
+				result.Build = &composeBuild{Context: buildContext, DockerfileInline: labDockerfile}
+				built = true
+			}
+			result.Command = []string{service.binary, "--host", "0.0.0.0", "--port", strconv.Itoa(service.port), "--log-level", parsed.logLevel}
+			if parsed.configDir != "" {
+				result.Volumes = []string{configDir + ":/app/config/servers:ro"}
+			}
+		}
+		if service.name == "kafka" {
+			result.Environment = kafkaLabEnvironment(parsed.advertiseHost, parsed.hostPort(service))
+		}
+		if len(service.health) > 0 {
+			result.Healthcheck = &composeHealth{Test: service.health, Interval: "10s", Timeout: "5s", Retries: 5}
+		}
+		file.Services[service.name] = result
+	}
+
+	data, err := yaml.Marshal(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode compose file: %w", err)
+	}
+	header := "# Generated by abc-runner server compose\n"
+	return append([]byte(header), data...), nil
+}
+
+// kafkaLabEnvironment 单节点KRaft模式Kafka的配置，对外公布的地址为advertiseHost:port
+func kafkaLabEnvironment(advertiseHost string, port int) map[string]string {
+	return map[string]string{
+		"KAFKA_NODE_ID":                                  "1",
+		"KAFKA_PROCESS_ROLES":                            "broker,controller",
+		"KAFKA_LISTENERS":                                "PLAINTEXT://:9092,CONTROLLER://:9093",
+		"KAFKA_ADVERTISED_LISTENERS":                     fmt.Sprintf("PLAINTEXT://%s:%d", advertiseHost, port),
+		"KAFKA_CONTROLLER_LISTENER_NAMES":                "CONTROLLER",
+		"KAFKA_LISTENER_SECURITY_PROTOCOL_MAP":           "CONTROLLER:PLAINTEXT,PLAINTEXT:PLAINTEXT",
+		"KAFKA_CONTROLLER_QUORUM_VOTERS":                 "1@localhost:9093",
+		"KAFKA_OFFSETS_TOPIC_REPLICATION_FACTOR":         "1",
+		"KAFKA_TRANSACTION_STATE_LOG_REPLICATION_FACTOR": "1",
+		"KAFKA_TRANSACTION_STATE_LOG_MIN_ISR":            "1",
+		"KAFKA_GROUP_INITIAL_REBALANCE_DELAY_MS":         "0",
+	}
+}
+
+// runCompose 执行docker compose，优先使用Compose插件，其次使用独立的docker-compose
+func (h *ServerCommandHandler) runCompose(ctx context.Context, parsed *composeArgs, args ...string) error {
+	var command []string
+	if _, err := exec.LookPath("docker"); err == nil && exec.CommandContext(ctx, "docker", "compose", "version").Run() == nil {
+		command = []string{"docker", "compose"}
+	} else if _, err := exec.LookPath("docker-compose"); err == nil {
+		command = []string{"docker-compose"}
+	} else {
+		return fmt.Errorf("docker compose is not available; start the stack manually with: docker compose -f %s up -d", parsed.out)
+	}
+
+	command = append(command, "-f", parsed.out)
+	command = append(command, args...)
+	fmt.Printf("🐳 %s\n", strings.Join(command, " "))
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w", strings.Join(command[:len(command)-len(args)], " "), err)
+	}
+	return nil
+}
+
+// printEndpoints 输出各服务的地址与对应的测试命令
+func (h *ServerCommandHandler) printEndpoints(parsed *composeArgs) {
+	fmt.Printf("\n%-10s %-10s %s\n", "service", "port", "try")
+	for _, service := range parsed.services {
+		port := parsed.hostPort(service)
+		protocol := "tcp"
+		if service.udp {
+			protocol = "udp"
+		}
+		fmt.Printf("%-10s %-10s %s\n", service.name, fmt.Sprintf("%d/%s", port, protocol), fmt.Sprintf(service.example, port))
+	}
+	if !parsed.up {
+		fmt.Printf("\nStart the stack with: docker compose -f %s up -d --wait\n", parsed.out)
+	}
+}
+
+// findLabService 按名称查找服务
+func findLabService(services []labService, name string) (labService, bool) {
+	for _, service := range services {
+		if service.name == name {
+			return service, true
+		}
+	}
+	return labService{}, false
+}
+
+// labServiceNames 服务名称列表
+func labServiceNames(services []labService) string {
+	names := make([]string, 0, len(services))
+	for _, service := range services {
+		names = append(names, service.name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// GetHelp 获取帮助信息
+func (h *ServerCommandHandler) GetHelp() string {
+	return `Test Server Suite
+
+USAGE:
+  abc-runner server compose [options]
+
+DESCRIPTION:
+  Writes a Docker Compose file that runs the bundled test servers (servers/
+  module) as a lab target environment, one service per protocol, and with
+  --up starts it. The servers image is built from --source with an inline
+  Dockerfile (Docker Compose 2.17 or newer); Redis and Kafka can be added
+  as extra targets. After writing the file the published endpoints are
+  listed together with a matching abc-runner command.
+
+OPTIONS:
+  --protocols LIST     Test servers to include: ` + labServiceNames(labServers) + `,
+                       all or none (default: all)
+  --with LIST          Extra targets: ` + labServiceNames(labExtras) + `
+  --port SERVICE=PORT  Publish SERVICE on host port PORT (repeatable;
+                       default: the service's standard port)
+  --bind ADDR          Host address ports are published on (default: all)
+  --advertise-host H   Host name Kafka advertises to clients (default: localhost)
+  --log-level LEVEL    Test server log level (default: info)
+  --config-dir DIR     Mount DIR (e.g. servers/config/servers) as the server
+                       configuration, to tune responses, delays and limits
+  --source DIR         servers module used as build context (default: servers)
+  --image NAME         Test server image name (default: abc-runner-servers:latest)
+  --no-build           Use --image as is instead of building it
+  --project, -p NAME   Compose project name (default: abc-runner-lab)
+  --out, -o FILE       Compose file to write, - for standard output
+                       (default: docker-compose.yml)
+  --up                 Start the stack and wait until it is healthy
+  --down               Stop and remove the stack described by --out
+
+EXAMPLES:
+  abc-runner server compose --up
+  abc-runner server compose --protocols http,grpc --with redis,kafka -o lab.yml --up
+  abc-runner server compose --port http=18080 --bind 127.0.0.1 -o -
+  abc-runner server compose --config-dir servers/config/servers --log-level debug --up
+  abc-runner server compose -o lab.yml --down`
+}
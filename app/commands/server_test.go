@@ -0,0 +1,155 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestServerCompose_RendersEachBackend(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		service   string
+		image     string
+		port      string
+		command   string // 以空格连接的command，为空时不应设置
+		health    bool
+		buildHere bool
+		env       map[string]string
+	}{
+		{name: "http", args: []string{"--protocols", "http"}, service: "http",
+			image: "abc-runner-servers:latest", port: "8080:8080",
+			command: "http-server --host 0.0.0.0 --port 8080 --log-level info", health: true, buildHere: true},
+		{name: "tcp", args: []string{"--protocols", "tcp", "--port", "tcp=19090"}, service: "tcp",
+			image: "abc-runner-servers:latest", port: "19090:9090",
+			command: "tcp-server --host 0.0.0.0 --port 9090 --log-level info", health: true, buildHere: true},
+		{name: "udp", args: []string{"--protocols", "udp", "--bind", "127.0.0.1"}, service: "udp",
+			image: "abc-runner-servers:latest", port: "127.0.0.1:9091:9091/udp",
+			command: "udp-server --host 0.0.0.0 --port 9091 --log-level info", buildHere: true},
+		{name: "grpc", args: []string{"--protocols", "grpc", "--log-level", "debug"}, service: "grpc",
+			image: "abc-runner-servers:latest", port: "50051:50051",
+			command: "grpc-server --host 0.0.0.0 --port 50051 --log-level debug", health: true, buildHere: true},
+		{name: "websocket", args: []string{"--protocols", "websocket", "--no-build", "--image", "lab:dev"}, service: "websocket",
+			image: "lab:dev", port: "7070:7070",
+			command: "websocket-server --host 0.0.0.0 --port 7070 --log-level info", health: true},
+		{name: "redis", args: []string{"--protocols", "none", "--with", "redis"}, service: "redis",
+			image: "redis:7-alpine", port: "6379:6379", health: true},
+		{name: "kafka", args: []string{"--protocols", "none", "--with", "kafka", "--advertise-host", "lab.local", "--port", "kafka=19092"}, service: "kafka",
+			image: "apache/kafka:3.8.0", port: "19092:9092", health: true,
+			env: map[string]string{"KAFKA_ADVERTISED_LISTENERS": "PLAINTEXT://lab.local:19092"}},
+	}
+
+	handler := NewServerCommandHandler()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := handler.parseComposeArgs(append(tt.args, "--out", "-"))
+			if err != nil {
+				t.Fatalf("parse failed: %v", err)
+			}
+			data, err := handler.renderCompose(parsed)
+			if err != nil {
+				t.Fatalf("render failed: %v", err)
+			}
+
+			var file composeFile
+			if err := yaml.Unmarshal(data, &file); err != nil {
+				t.Fatalf("generated compose is not valid YAML: %v\n%s", err, data)
+			}
+			if file.Name != "abc-runner-lab" || len(file.Services) != 1 {
+				t.Fatalf("unexpected compose file: %+v", file)
+			}
+			service, ok := file.Services[tt.service]
+			if !ok {
+				t.Fatalf("service %s missing:\n%s", tt.service, data)
+			}
+
+			if service.Image != tt.image {
+				t.Errorf("image = %q, want %q", service.Image, tt.image)
+			}
+			if len(service.Ports) != 1 || service.Ports[0] != tt.port {
+				t.Errorf("ports = %v, want [%s]", service.Ports, tt.port)
+			}
+			if got := strings.Join(service.Command, " "); got != tt.command {
+				t.Errorf("command = %q, want %q", got, tt.command)
+			}
+			if (service.Healthcheck != nil) != tt.health {
+				t.Errorf("healthcheck = %+v, want present=%v", service.Healthcheck, tt.health)
+			}
+			if (service.Build != nil) != tt.buildHere {
+				t.Errorf("build = %+v, want present=%v", service.Build, tt.buildHere)
+			}
+			for key, want := range tt.env {
+				if got := service.Environment[key]; got != want {
+					t.Errorf("environment %s = %q, want %q", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestServerCompose_BuildsImageOnce(t *testing.T) {
+	handler := NewServerCommandHandler()
+	parsed, err := handler.parseComposeArgs([]string{"--out", "lab/docker-compose.yml", "--config-dir", "config/servers"})
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	data, err := handler.renderCompose(parsed)
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+
+	var file composeFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		t.Fatalf("generated compose is not valid YAML: %v", err)
+	}
+	if len(file.Services) != len(labServers) {
+		t.Fatalf("expected %d services, got %d", len(labServers), len(file.Services))
+	}
+	builds := 0
+	for name, service := range file.Services {
+		if service.Build != nil {
+			builds++
+			if service.Build.Context != "../servers" {
+				t.Errorf("%s build context = %q, want relative to the compose file", name, service.Build.Context)
+			}
+		}
+		if len(service.Volumes) != 1 || service.Volumes[0] != "../config/servers:/app/config/servers:ro" {
+			t.Errorf("%s volumes = %v", name, service.Volumes)
+		}
+	}
+	if builds != 1 {
+		t.Errorf("expected exactly one service to build the image, got %d", builds)
+	}
+}
+
+func TestServerCompose_RejectsInvalidFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"unknown option", []string{"--frobnicate", "x"}, "unknown option"},
+		{"missing value", []string{"--protocols"}, "missing value"},
+		{"unknown protocol", []string{"--protocols", "smtp"}, "unknown protocol"},
+		{"unknown extra", []string{"--with", "mysql"}, "unknown --with target"},
+		{"no services", []string{"--protocols", "none"}, "no services selected"},
+		{"malformed port", []string{"--port", "http"}, "invalid --port"},
+		{"port out of range", []string{"--port", "http=70000"}, "invalid --port"},
+		{"port for unselected service", []string{"--protocols", "http", "--port", "tcp=9000"}, "is not selected"},
+		{"port conflict", []string{"--protocols", "http,tcp", "--port", "tcp=8080"}, "both publish port 8080"},
+		{"up and down", []string{"--up", "--down"}, "cannot be combined"},
+		{"up to stdout", []string{"--up", "--out", "-"}, "need a compose file"},
+	}
+
+	handler := NewServerCommandHandler()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handler.parseComposeArgs(tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
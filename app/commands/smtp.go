@@ -0,0 +1,441 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	smtpConfig "abc-runner/app/adapters/smtp/config"
+	"abc-runner/app/adapters/smtp/connection"
+	"abc-runner/app/adapters/smtp/operations"
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/reporting"
+)
+
+// SMTPCommandHandler SMTP命令处理器
+type SMTPCommandHandler struct {
+	protocolName string
+	factory      interfaces.SMTPAdapterFactory
+}
+
+// NewSMTPCommandHandler 创建SMTP命令处理器
+func NewSMTPCommandHandler(factory interfaces.SMTPAdapterFactory) *SMTPCommandHandler {
+	if factory == nil {
+		panic("smtpAdapterFactory cannot be nil - dependency injection required")
+	}
+
+	return &SMTPCommandHandler{
+		protocolName: "smtp",
+		factory:      factory,
+	}
+}
+
+// Execute 执行SMTP命令
+func (h *SMTPCommandHandler) Execute(ctx context.Context, args []string) error {
+	// 检查帮助请求
+	for i, arg := range args {
+		if arg == "--help" || arg == "help" {
+			fmt.Println(h.GetHelp())
+			return nil
+		}
+		if arg == "-h" && (i+1 >= len(args) || !looksLikeHostname(args[i+1])) {
+			fmt.Println(h.GetHelp())
+			return nil
+		}
+	}
+
+	// 解析命令行参数
+	config, err := h.parseArgs(args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	// 解析通用运行选项
+	opts, err := parseRunOptions(ctx, "smtp", args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+	if printConfigRequested(args) {
+		return printEffectiveConfig("smtp", config, opts)
+	}
+
+	metricsCollector := metrics.NewBaseCollector(opts.collectorConfig(), map[string]interface{}{
+		"protocol":  "smtp",
+		"test_type": "performance",
+	})
+	defer metricsCollector.Stop()
+
+	adapter := h.factory.CreateSMTPAdapter()
+	if adapter == nil {
+		return fmt.Errorf("failed to create SMTP adapter")
+	}
+	defer adapter.Close()
+
+	target := config.Connection.GetAddresses()[0]
+	if err := adapter.Connect(ctx, config); err != nil {
+		return NewConnectionError(fmt.Errorf("failed to connect to SMTP server %s: %w", target, err))
+	}
+	if err := adapter.HealthCheck(ctx); err != nil {
+		return NewConnectionError(fmt.Errorf("SMTP health check failed: %w", err))
+	}
+
+	if smtpAdapter, ok := adapter.(interface {
+		Greeting() string
+		Extensions() []string
+	}); ok {
+		fmt.Printf("✅ Connected to %s: %s\n", target, firstLine(smtpAdapter.Greeting()))
+		if extensions := smtpAdapter.Extensions(); len(extensions) > 0 {
+			fmt.Printf("Extensions: %s\n", strings.Join(extensions, ", "))
+		}
+	}
+
+	specific := config.SMTPSpecific
+	sizeDesc := fmt.Sprintf("%d bytes", specific.MessageSize)
+	if specific.MaxMessageSize > specific.MessageSize {
+		sizeDesc = fmt.Sprintf("%d-%d bytes", specific.MessageSize, specific.MaxMessageSize)
+	}
+	rateDesc := "unlimited"
+	if config.BenchMark.Rate > 0 {
+		rateDesc = fmt.Sprintf("%d msg/s", config.BenchMark.Rate)
+	}
+
+	fmt.Printf("🚀 Starting SMTP send test...\n")
+	fmt.Printf("Security: %s", config.Connection.Security)
+	if config.Connection.Username != "" {
+		fmt.Printf(", AUTH as %s (%s)", config.Connection.Username, config.Connection.AuthMechanism)
+	}
+	fmt.Printf("\nEnvelope: <%s> → %s\n", specific.From, strings.Join(specific.To, ", "))
+	if specific.MessagesPerConnection > 0 {
+		fmt.Printf("Messages per Connection: %d\n", specific.MessagesPerConnection)
+	}
+	fmt.Printf("Messages: %d, Concurrency: %d, Size: %s, Rate: %s\n",
+		config.BenchMark.Total, config.BenchMark.Parallels, sizeDesc, rateDesc)
+
+	if err := h.runPerformanceTest(ctx, adapter, config, metricsCollector, opts); err != nil {
+		return fmt.Errorf("performance test failed: %w", err)
+	}
+
+	return h.generateReport(metricsCollector, opts)
+}
+
+// firstLine 多行问候语的第一行
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(text, "\n")
+	return line
+}
+
+// GetHelp 获取帮助信息
+func (h *SMTPCommandHandler) GetHelp() string {
+	return `SMTP Send-Throughput Performance Testing
+
+USAGE:
+  abc-runner smtp [options]
+
+DESCRIPTION:
+  Send generated plain-text messages to an MTA and measure how many are
+  accepted, deferred (4xx) or rejected (5xx), together with the latency of
+  each message from MAIL FROM to the final reply after DATA. Every
+  concurrent sender keeps its own SMTP session and reuses it with RSET
+  semantics; --messages-per-conn closes and reopens sessions to include
+  connection, TLS and AUTH cost in the measurement.
+
+OPTIONS:
+  --help                   Show this help message
+  --host HOST, -h HOST     SMTP server host (default: localhost)
+  --port PORT, -p PORT     SMTP server port (default: 25)
+  --security MODE          none, starttls or tls (implicit TLS, usually
+                           port 465) (default: none)
+  --starttls               Same as --security starttls
+  --tls                    Same as --security tls
+  --insecure, -k           Skip TLS certificate verification
+  --user USER, -u USER     Authenticate as USER
+  --password PASSWORD      Password for AUTH
+  --auth MECHANISM         auto, plain, login or cram-md5 (default: auto)
+  --helo NAME              EHLO name (default: local hostname)
+  --timeout DURATION       Connect and per-command timeout (default: 30s)
+  --from ADDRESS           Envelope sender, "" for the null sender
+                           (default: abc-runner@example.com)
+  --to ADDRESS             Envelope recipient, repeatable; placeholders such
+                           as user{{randInt 1 1000}}@example.com are rendered
+                           per message (default: bench@example.com)
+  --subject TEXT           Subject, the message number is appended
+                           (default: abc-runner test message)
+  --message-size N[-M]     Message size in bytes including headers. A range
+                           picks a random size per message (default: 4096)
+  --messages-per-conn N    Reopen a session after N messages (default: 0,
+                           keep sessions open)
+  --rate N                 Cap the send rate at N messages/sec (default: unlimited)
+  -n COUNT                 Total messages (default: 1000)
+  -c COUNT                 Concurrent senders/sessions (default: 10)
+  --duration DURATION      Run for a fixed duration instead of -n
+
+NOTES:
+  A message counts as accepted when the server answers 250 after DATA. If
+  some recipients are rejected the message is still sent to the others and
+  counted as partial. PLAIN and LOGIN send the password in clear text unless
+  the session uses TLS.
+
+EXAMPLES:
+  abc-runner smtp --help
+  abc-runner smtp -h localhost -p 2525 -n 10000 -c 20
+  abc-runner smtp -h mta --starttls -k -u bench --password secret --duration 60s
+  abc-runner smtp -h mta --to "user{{randInt 1 5000}}@example.com" --message-size 2000-50000
+  abc-runner smtp -h mta --messages-per-conn 1 --rate 200 --duration 5m` + configLayersHelp + runOptionsHelp + "\n"
+}
+
+// parseArgs 解析命令行参数
+func (h *SMTPCommandHandler) parseArgs(args []string) (*smtpConfig.SMTPConfig, error) {
+	config := smtpConfig.NewDefaultSMTPConfig()
+	specific := &config.SMTPSpecific
+	defaultTo := true
+
+	// 依次应用--config文件、ABC_SMTP_*环境变量与--set，专用参数随后覆盖
+	if err := applyConfigLayers("smtp", args, config); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(args); i++ {
+		// 无参数的开关
+		switch args[i] {
+		case "--starttls":
+			config.Connection.Security = smtpConfig.SecurityStartTLS
+			continue
+		case "--tls":
+			config.Connection.Security = smtpConfig.SecurityTLS
+			continue
+		case "--insecure", "-k":
+			config.Connection.InsecureSkipVerify = true
+			continue
+		}
+
+		if i+1 >= len(args) {
+			break
+		}
+		value := args[i+1]
+		var err error
+
+		switch args[i] {
+		case "--host", "-h":
+			if !looksLikeHostname(value) {
+				continue
+			}
+			config.Connection.Address = value
+		case "--port", "-p":
+			config.Connection.Port, err = strconv.Atoi(value)
+		case "--security":
+			config.Connection.Security = strings.ToLower(value)
+		case "--user", "-u":
+			config.Connection.Username = value
+		case "--password":
+			config.Connection.Password = value
+		case "--auth":
+			config.Connection.AuthMechanism = strings.ToLower(value)
+		case "--helo":
+			config.Connection.HeloName = value
+		case "--timeout":
+			config.Connection.Timeout, err = time.ParseDuration(value)
+		case "--from":
+			specific.From = value
+		case "--to":
+			// 第一个--to替换默认收件人
+			if defaultTo {
+				specific.To = nil
+				defaultTo = false
+			}
+			specific.To = append(specific.To, value)
+		case "--subject":
+			specific.Subject = value
+		case "--message-size":
+			specific.MessageSize, specific.MaxMessageSize, err = parseSizeRange(value)
+		case "--messages-per-conn":
+			specific.MessagesPerConnection, err = strconv.Atoi(value)
+		case "--rate":
+			config.BenchMark.Rate, err = strconv.Atoi(value)
+		case "-n":
+			config.BenchMark.Total, err = strconv.Atoi(value)
+		case "-c":
+			config.BenchMark.Parallels, err = strconv.Atoi(value)
+		case "--duration":
+			config.BenchMark.Duration, err = time.ParseDuration(value)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", args[i], err)
+		}
+		i++
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// runPerformanceTest 运行SMTP发送测试
+func (h *SMTPCommandHandler) runPerformanceTest(
+	ctx context.Context,
+	adapter interfaces.ProtocolAdapter,
+	config *smtpConfig.SMTPConfig,
+	collector *metrics.BaseCollector[map[string]interface{}],
+	opts *runOptions,
+) error {
+	factory := operations.NewOperationFactory(config)
+	engine := execution.NewExecutionEngine(adapter, collector, factory)
+	engine.SetMaxWorkers(config.BenchMark.Parallels)
+	opts.applyToEngine(engine, collector)
+
+	testStartTime := time.Now()
+	result, err := engine.RunBenchmark(ctx, &config.BenchMark)
+	if err != nil {
+		return fmt.Errorf("benchmark execution failed: %w", err)
+	}
+	opts.finishRun()
+	actualTestDuration := time.Since(testStartTime)
+
+	fmt.Printf("\n📊 Execution Results:\n")
+	fmt.Printf("Total Jobs: %d\n", result.TotalJobs)
+	fmt.Printf("Completed Jobs: %d\n", result.CompletedJobs)
+	fmt.Printf("Success Jobs: %d\n", result.SuccessJobs)
+	fmt.Printf("Failed Jobs: %d\n", result.FailedJobs)
+	fmt.Printf("Actual Test Duration: %v\n", actualTestDuration)
+
+	protocolMetrics := map[string]interface{}{
+		"protocol":         "smtp",
+		"test_type":        "performance",
+		"target":           config.Connection.GetAddresses()[0],
+		"security":         config.Connection.Security,
+		"rate_limit":       config.BenchMark.Rate,
+		"actual_duration":  actualTestDuration,
+		"execution_result": result,
+	}
+
+	if statsAdapter, ok := adapter.(interface {
+		GetDeliveryStats() *operations.DeliveryStats
+		GetOperationStats() []operations.OperationStats
+		GetPoolStats() *connection.PoolStats
+	}); ok {
+		seconds := actualTestDuration.Seconds()
+		if delivery := statsAdapter.GetDeliveryStats(); delivery != nil {
+			printSMTPDeliveryStats(delivery, seconds)
+			protocolMetrics["delivery"] = *delivery
+			protocolMetrics["accepted_per_sec"] = float64(delivery.Accepted) / seconds
+			protocolMetrics["deferred_per_sec"] = float64(delivery.Deferred) / seconds
+			protocolMetrics["rejected_per_sec"] = float64(delivery.Rejected) / seconds
+		}
+		if stats := statsAdapter.GetOperationStats(); len(stats) > 0 {
+			for _, s := range stats {
+				fmt.Printf("Send Latency avg/p50/p95/p99: %v/%v/%v/%v\n",
+					s.Latency.Average, s.Latency.P50, s.Latency.P95, s.Latency.P99)
+				if s.Flagged > 0 {
+					fmt.Printf("Partially Rejected: %d message(s)\n", s.Flagged)
+				}
+				printSMTPErrorCodes(s.ErrorCodes)
+			}
+			protocolMetrics["operation_stats"] = stats
+		}
+		if pool := statsAdapter.GetPoolStats(); pool != nil {
+			fmt.Printf("Sessions: %d opened for %d sender(s), %d after failures\n",
+				pool.Connections, pool.Size, pool.Reconnects)
+			protocolMetrics["pool_stats"] = *pool
+		}
+	}
+
+	collector.UpdateProtocolMetrics(protocolMetrics)
+	return nil
+}
+
+// printSMTPDeliveryStats 打印按结果分类的消息数与速率
+func printSMTPDeliveryStats(stats *operations.DeliveryStats, seconds float64) {
+	total := stats.Accepted + stats.Deferred + stats.Rejected + stats.Failed
+	if total == 0 {
+		return
+	}
+	fmt.Printf("\n📬 Delivery:\n")
+	for _, row := range []struct {
+		name  string
+		count int64
+	}{
+		{"accepted", stats.Accepted},
+		{"deferred (4xx)", stats.Deferred},
+		{"rejected (5xx)", stats.Rejected},
+		{"failed", stats.Failed},
+	} {
+		fmt.Printf("  %-16s %10d %10.2f msg/sec %7.2f%%\n",
+			row.name, row.count, float64(row.count)/seconds, float64(row.count)*100/float64(total))
+	}
+	if stats.RejectedRecipients > 0 {
+		fmt.Printf("  Rejected Recipients: %d\n", stats.RejectedRecipients)
+	}
+	fmt.Printf("  Accepted Bytes: %d (%.2f MB/sec)\n", stats.Bytes, float64(stats.Bytes)/seconds/1024/1024)
+}
+
+// printSMTPErrorCodes 按次数从多到少打印错误码
+func printSMTPErrorCodes(codes map[string]int64) {
+	if len(codes) == 0 {
+		return
+	}
+	names := make([]string, 0, len(codes))
+	for code := range codes {
+		names = append(names, code)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if codes[names[i]] != codes[names[j]] {
+			return codes[names[i]] > codes[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	fmt.Printf("Errors:\n")
+	for _, code := range names {
+		fmt.Printf("  %-20s %d\n", code, codes[code])
+	}
+}
+
+// generateReport 生成SMTP测试报告
+func (h *SMTPCommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
+	snapshot := collector.Snapshot()
+
+	// 使用实际测试时间修正吞吐量
+	if duration, ok := snapshot.Protocol["actual_duration"].(time.Duration); ok && duration > 0 {
+		snapshot.Core.Duration = duration
+		seconds := duration.Seconds()
+		snapshot.Core.Throughput.RPS = float64(snapshot.Core.Operations.Total) / seconds
+		snapshot.Core.Throughput.WriteRPS = float64(snapshot.Core.Operations.Write) / seconds
+		snapshot.Core.Throughput.ReadMBps, snapshot.Core.Throughput.WriteMBps = metrics.Bandwidth(snapshot.Core.Operations, duration)
+	}
+
+	fmt.Printf("\n📊 SMTP Performance Metrics:\n")
+	fmt.Printf("=====================================\n")
+	fmt.Printf("  Total Messages: %d\n", snapshot.Core.Operations.Total)
+	fmt.Printf("  Accepted: %d\n", snapshot.Core.Operations.Success)
+	fmt.Printf("  Not Accepted: %d\n", snapshot.Core.Operations.Failed)
+	fmt.Printf("  Acceptance Rate: %.2f%%\n", snapshot.Core.Operations.Rate)
+	fmt.Printf("  Send Latency avg/p95/p99: %v/%v/%v\n",
+		snapshot.Core.Latency.Average, snapshot.Core.Latency.P95, snapshot.Core.Latency.P99)
+	fmt.Printf("  Throughput: %.2f msg/sec\n", snapshot.Core.Throughput.RPS)
+	fmt.Printf("=====================================\n")
+
+	// 存在快照接收器时（如分布式agent模式）交由接收器处理，不生成本地报告
+	if opts.publishSnapshot(snapshot) {
+		return nil
+	}
+
+	report := reporting.ConvertFromMetricsSnapshot(snapshot)
+	reportConfig := reporting.NewStandardReportConfig("smtp")
+	opts.applyToReport(snapshot, report, reportConfig)
+	generator := reporting.NewReportGenerator(reportConfig)
+	if err := generator.Generate(report); err != nil {
+		return err
+	}
+	return opts.resultError(report)
+}
+
+// GetProtocolName 获取协议名称
+func (h *SMTPCommandHandler) GetProtocolName() string {
+	return h.protocolName
+}
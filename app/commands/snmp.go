@@ -0,0 +1,320 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	snmpConfig "abc-runner/app/adapters/snmp/config"
+	"abc-runner/app/adapters/snmp/operations"
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/reporting"
+)
+
+// SNMPCommandHandler SNMP命令处理器
+type SNMPCommandHandler struct {
+	protocolName string
+	factory      interfaces.SNMPAdapterFactory
+}
+
+// NewSNMPCommandHandler 创建SNMP命令处理器
+func NewSNMPCommandHandler(factory interfaces.SNMPAdapterFactory) *SNMPCommandHandler {
+	if factory == nil {
+		panic("snmpAdapterFactory cannot be nil - dependency injection required")
+	}
+
+	return &SNMPCommandHandler{
+		protocolName: "snmp",
+		factory:      factory,
+	}
+}
+
+// Execute 执行SNMP命令
+func (h *SNMPCommandHandler) Execute(ctx context.Context, args []string) error {
+	// 检查帮助请求
+	for i, arg := range args {
+		if arg == "--help" || arg == "help" {
+			fmt.Println(h.GetHelp())
+			return nil
+		}
+		if arg == "-h" && (i == 0 || (i > 0 && args[i-1] != "snmp")) {
+			if i+1 < len(args) && !looksLikeHostname(args[i+1]) {
+				fmt.Println(h.GetHelp())
+				return nil
+			}
+		}
+	}
+
+	// 解析命令行参数
+	config, err := h.parseArgs(args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	// 解析通用运行选项
+	opts, err := parseRunOptions(ctx, "snmp", args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+	if printConfigRequested(args) {
+		return printEffectiveConfig("snmp", config, opts)
+	}
+
+	metricsCollector := metrics.NewBaseCollector(opts.collectorConfig(), map[string]interface{}{
+		"protocol":  "snmp",
+		"test_type": "performance",
+	})
+	defer metricsCollector.Stop()
+
+	adapter := h.factory.CreateSNMPAdapter()
+	if adapter == nil {
+		return fmt.Errorf("failed to create SNMP adapter")
+	}
+	defer adapter.Close()
+
+	if err := adapter.Connect(ctx, config); err != nil {
+		return fmt.Errorf("failed to set up SNMP client for %s:%d: %w",
+			config.Connection.Address, config.Connection.Port, err)
+	}
+	if err := adapter.HealthCheck(ctx); err != nil {
+		fmt.Printf("⚠️  Health check failed: %v\n", err)
+		fmt.Printf("🔍 Possible causes: agent not running, wrong community or version, ACL, or firewall\n")
+	} else {
+		fmt.Printf("✅ SNMP agent %s:%d is responding\n", config.Connection.Address, config.Connection.Port)
+	}
+
+	fmt.Printf("🚀 Starting SNMP %s polling test...\n", strings.ToUpper(config.BenchMark.TestCase))
+	fmt.Printf("Target: %s:%d (v%s, community %q)\n",
+		config.Connection.Address, config.Connection.Port, config.SNMPSpecific.Version, config.SNMPSpecific.Community)
+	fmt.Printf("OIDs: %s\n", strings.Join(config.GetOIDs(), ", "))
+	fmt.Printf("Operations: %d, Concurrency: %d, Timeout: %v, Retries: %d\n",
+		config.BenchMark.Total, config.BenchMark.Parallels, config.Connection.Timeout, config.Connection.Retries)
+
+	if err := h.runPerformanceTest(ctx, adapter, config, metricsCollector, opts); err != nil {
+		return fmt.Errorf("performance test failed: %w", err)
+	}
+
+	return h.generateReport(metricsCollector, opts)
+}
+
+// GetHelp 获取帮助信息
+func (h *SNMPCommandHandler) GetHelp() string {
+	return `SNMP Polling Performance Testing
+
+USAGE:
+  abc-runner snmp [options]
+
+DESCRIPTION:
+  Stress the management plane of network devices with SNMP v1/v2c GET or
+  WALK polling, and report latency and timeout rates per OID. Every
+  concurrent worker uses its own UDP socket; responses are matched by
+  request-id and late responses to timed-out requests are discarded.
+
+OPTIONS:
+  --help                Show this help message
+  --host HOST, -h HOST  SNMP agent host (default: localhost)
+  --port PORT, -p PORT  SNMP agent port (default: 161)
+  --community NAME      Community string (default: public)
+  --version VER         SNMP version: 1 or 2c (default: 2c)
+  --oid OID             OID to poll (repeatable or comma-separated; operations
+                        rotate across OIDs). Default: sysUpTime.0
+                        (1.3.6.1.2.1.1.3.0) for get, system (1.3.6.1.2.1.1)
+                        for walk
+  --test-case TYPE      get or walk (default: get)
+  --max-repetitions N   Rows per GETBULK request when walking with v2c
+                        (default: 10)
+  --timeout DURATION    Per-request response timeout (default: 2s)
+  --retries N           Retransmissions after a timeout (default: 0)
+  -n COUNT              Total operations (default: 1000)
+  -c COUNT              Concurrent workers (default: 10)
+  --duration DURATION   Run for a fixed duration instead of -n
+
+TEST CASES:
+  get                   One GetRequest per operation for a single OID
+  walk                  Walk the subtree under the OID (GETBULK for v2c,
+                        GETNEXT for v1); latency is the whole walk
+
+EXAMPLES:
+  abc-runner snmp --help
+  abc-runner snmp --host 192.168.1.1 --community public -n 10000 -c 20
+  abc-runner snmp -h switch1 --oid 1.3.6.1.2.1.1.3.0,1.3.6.1.2.1.2.1.0 --timeout 500ms
+  abc-runner snmp -h router1 --test-case walk --oid 1.3.6.1.2.1.2.2 --max-repetitions 25
+  abc-runner snmp -h legacy-device --version 1 --test-case walk -n 100` + configLayersHelp + runOptionsHelp + "\n"
+}
+
+// parseArgs 解析命令行参数
+func (h *SNMPCommandHandler) parseArgs(args []string) (*snmpConfig.SNMPConfig, error) {
+	config := snmpConfig.NewDefaultSNMPConfig()
+
+	// 依次应用--config文件、ABC_SNMP_*环境变量与--set，专用参数随后覆盖
+	if err := applyConfigLayers("snmp", args, config); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(args); i++ {
+		if i+1 >= len(args) {
+			break
+		}
+		value := args[i+1]
+		var err error
+
+		switch args[i] {
+		case "--host", "-h":
+			if !looksLikeHostname(value) {
+				continue
+			}
+			config.Connection.Address = value
+		case "--port", "-p":
+			config.Connection.Port, err = strconv.Atoi(value)
+		case "--community":
+			config.SNMPSpecific.Community = value
+		case "--version":
+			config.SNMPSpecific.Version = strings.TrimPrefix(strings.ToLower(value), "v")
+		case "--oid":
+			for _, oid := range strings.Split(value, ",") {
+				if oid = strings.TrimSpace(oid); oid != "" {
+					config.SNMPSpecific.OIDs = append(config.SNMPSpecific.OIDs, oid)
+				}
+			}
+		case "--test-case":
+			config.BenchMark.TestCase = value
+		case "--max-repetitions":
+			config.SNMPSpecific.MaxRepetitions, err = strconv.Atoi(value)
+		case "--timeout":
+			config.Connection.Timeout, err = time.ParseDuration(value)
+		case "--retries":
+			config.Connection.Retries, err = strconv.Atoi(value)
+		case "-n":
+			config.BenchMark.Total, err = strconv.Atoi(value)
+		case "-c":
+			config.BenchMark.Parallels, err = strconv.Atoi(value)
+		case "--duration":
+			config.BenchMark.Duration, err = time.ParseDuration(value)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", args[i], err)
+		}
+		i++
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// runPerformanceTest 运行SNMP轮询测试
+func (h *SNMPCommandHandler) runPerformanceTest(
+	ctx context.Context,
+	adapter interfaces.ProtocolAdapter,
+	config *snmpConfig.SNMPConfig,
+	collector *metrics.BaseCollector[map[string]interface{}],
+	opts *runOptions,
+) error {
+	factory := operations.NewOperationFactory(config.BenchMark.TestCase, config.GetOIDs())
+	engine := execution.NewExecutionEngine(adapter, collector, factory)
+	engine.SetMaxWorkers(config.BenchMark.Parallels)
+	opts.applyToEngine(engine, collector)
+
+	testStartTime := time.Now()
+	result, err := engine.RunBenchmark(ctx, &config.BenchMark)
+	if err != nil {
+		return fmt.Errorf("benchmark execution failed: %w", err)
+	}
+	opts.finishRun()
+	actualTestDuration := time.Since(testStartTime)
+
+	fmt.Printf("\n📊 Execution Results:\n")
+	fmt.Printf("Total Jobs: %d\n", result.TotalJobs)
+	fmt.Printf("Completed Jobs: %d\n", result.CompletedJobs)
+	fmt.Printf("Success Jobs: %d\n", result.SuccessJobs)
+	fmt.Printf("Failed Jobs: %d\n", result.FailedJobs)
+	fmt.Printf("Actual Test Duration: %v\n", actualTestDuration)
+	if result.CompletedJobs > 0 {
+		fmt.Printf("Actual RPS: %.2f polls/sec\n", float64(result.CompletedJobs)/actualTestDuration.Seconds())
+	}
+
+	protocolMetrics := map[string]interface{}{
+		"protocol":         "snmp",
+		"test_type":        "performance",
+		"test_case":        config.BenchMark.TestCase,
+		"version":          config.SNMPSpecific.Version,
+		"actual_duration":  actualTestDuration,
+		"execution_result": result,
+	}
+
+	// 按OID的延迟与超时统计
+	if oidAdapter, ok := adapter.(interface {
+		GetOIDStats() []operations.OIDStats
+	}); ok {
+		if stats := oidAdapter.GetOIDStats(); len(stats) > 0 {
+			h.printOIDStats(stats)
+			protocolMetrics["oid_stats"] = stats
+		}
+	}
+
+	collector.UpdateProtocolMetrics(protocolMetrics)
+	return nil
+}
+
+// printOIDStats 输出按OID的统计表
+func (h *SNMPCommandHandler) printOIDStats(stats []operations.OIDStats) {
+	fmt.Printf("\n%-32s %9s %9s %9s %8s %9s %10s %10s %10s\n",
+		"oid", "requests", "timeouts", "timeout%", "errors", "varbinds", "p50", "p99", "max")
+	for _, entry := range stats {
+		fmt.Printf("%-32s %9d %9d %8.2f%% %8d %9d %10v %10v %10v\n",
+			entry.OID, entry.Requests, entry.Timeouts, entry.TimeoutRate, entry.Errors, entry.VarBinds,
+			entry.Latency.P50.Round(time.Microsecond), entry.Latency.P99.Round(time.Microsecond),
+			entry.Latency.Max.Round(time.Microsecond))
+	}
+}
+
+// generateReport 生成SNMP轮询测试报告
+func (h *SNMPCommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
+	snapshot := collector.Snapshot()
+
+	// 使用实际测试时间修正吞吐量
+	if duration, ok := snapshot.Protocol["actual_duration"].(time.Duration); ok && duration > 0 {
+		snapshot.Core.Duration = duration
+		seconds := duration.Seconds()
+		snapshot.Core.Throughput.RPS = float64(snapshot.Core.Operations.Total) / seconds
+		snapshot.Core.Throughput.ReadRPS = float64(snapshot.Core.Operations.Read) / seconds
+		snapshot.Core.Throughput.ReadMBps, snapshot.Core.Throughput.WriteMBps = metrics.Bandwidth(snapshot.Core.Operations, duration)
+	}
+
+	fmt.Printf("\n📊 SNMP Performance Metrics:\n")
+	fmt.Printf("=====================================\n")
+	fmt.Printf("  Total Operations: %d\n", snapshot.Core.Operations.Total)
+	fmt.Printf("  Successful Operations: %d\n", snapshot.Core.Operations.Success)
+	fmt.Printf("  Failed Operations: %d\n", snapshot.Core.Operations.Failed)
+	fmt.Printf("  Success Rate: %.2f%%\n", snapshot.Core.Operations.Rate)
+	fmt.Printf("  Latency avg/p95/p99: %v/%v/%v\n",
+		snapshot.Core.Latency.Average, snapshot.Core.Latency.P95, snapshot.Core.Latency.P99)
+	fmt.Printf("  Throughput: %.2f polls/sec\n", snapshot.Core.Throughput.RPS)
+	fmt.Printf("=====================================\n")
+
+	// 存在快照接收器时（如分布式agent模式）交由接收器处理，不生成本地报告
+	if opts.publishSnapshot(snapshot) {
+		return nil
+	}
+
+	report := reporting.ConvertFromMetricsSnapshot(snapshot)
+	reportConfig := reporting.NewStandardReportConfig("snmp")
+	opts.applyToReport(snapshot, report, reportConfig)
+	generator := reporting.NewReportGenerator(reportConfig)
+	if err := generator.Generate(report); err != nil {
+		return err
+	}
+	return opts.resultError(report)
+}
+
+// GetProtocolName 获取协议名称
+func (h *SNMPCommandHandler) GetProtocolName() string {
+	return h.protocolName
+}
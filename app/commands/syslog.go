@@ -0,0 +1,348 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	syslogConfig "abc-runner/app/adapters/syslog/config"
+	"abc-runner/app/adapters/syslog/operations"
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/reporting"
+)
+
+// SyslogCommandHandler Syslog命令处理器
+type SyslogCommandHandler struct {
+	protocolName string
+	factory      interfaces.SyslogAdapterFactory
+}
+
+// NewSyslogCommandHandler 创建Syslog命令处理器
+func NewSyslogCommandHandler(factory interfaces.SyslogAdapterFactory) *SyslogCommandHandler {
+	if factory == nil {
+		panic("syslogAdapterFactory cannot be nil - dependency injection required")
+	}
+
+	return &SyslogCommandHandler{
+		protocolName: "syslog",
+		factory:      factory,
+	}
+}
+
+// Execute 执行Syslog命令
+func (h *SyslogCommandHandler) Execute(ctx context.Context, args []string) error {
+	// 检查帮助请求
+	for i, arg := range args {
+		if arg == "--help" || arg == "help" {
+			fmt.Println(h.GetHelp())
+			return nil
+		}
+		if arg == "-h" && (i == 0 || (i > 0 && args[i-1] != "syslog")) {
+			if i+1 < len(args) && !looksLikeHostname(args[i+1]) {
+				fmt.Println(h.GetHelp())
+				return nil
+			}
+		}
+	}
+
+	// 解析命令行参数
+	config, err := h.parseArgs(args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	// 解析通用运行选项
+	opts, err := parseRunOptions(ctx, "syslog", args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+	if printConfigRequested(args) {
+		return printEffectiveConfig("syslog", config, opts)
+	}
+
+	metricsCollector := metrics.NewBaseCollector(opts.collectorConfig(), map[string]interface{}{
+		"protocol":  "syslog",
+		"test_type": "performance",
+	})
+	defer metricsCollector.Stop()
+
+	adapter := h.factory.CreateSyslogAdapter()
+	if adapter == nil {
+		return fmt.Errorf("failed to create Syslog adapter")
+	}
+	defer adapter.Close()
+
+	if err := adapter.Connect(ctx, config); err != nil {
+		return NewConnectionError(fmt.Errorf("failed to connect to syslog server %s:%d: %w",
+			config.Connection.Address, config.Connection.Port, err))
+	}
+	fmt.Printf("✅ Syslog sender ready: %d %s connection(s) to %s:%d\n", config.BenchMark.Parallels,
+		strings.ToUpper(config.Connection.Transport), config.Connection.Address, config.Connection.Port)
+
+	sizeDesc := fmt.Sprintf("%d bytes", config.SyslogSpecific.MessageSize)
+	if config.SyslogSpecific.MaxMessageSize > config.SyslogSpecific.MessageSize {
+		sizeDesc = fmt.Sprintf("%d-%d bytes", config.SyslogSpecific.MessageSize, config.SyslogSpecific.MaxMessageSize)
+	}
+	rateDesc := "unlimited"
+	if config.BenchMark.Rate > 0 {
+		rateDesc = fmt.Sprintf("%d msg/s", config.BenchMark.Rate)
+	}
+
+	fmt.Printf("🚀 Starting Syslog ingestion test...\n")
+	fmt.Printf("Transport: %s", config.Connection.Transport)
+	if config.IsStream() {
+		fmt.Printf(" (framing: %s)", config.SyslogSpecific.Framing)
+	}
+	fmt.Printf("\nMessages: %d, Concurrency: %d, Size: %s, Rate: %s\n",
+		config.BenchMark.Total, config.BenchMark.Parallels, sizeDesc, rateDesc)
+
+	if err := h.runPerformanceTest(ctx, adapter, config, metricsCollector, opts); err != nil {
+		return fmt.Errorf("performance test failed: %w", err)
+	}
+
+	return h.generateReport(metricsCollector, opts)
+}
+
+// GetHelp 获取帮助信息
+func (h *SyslogCommandHandler) GetHelp() string {
+	return `Syslog Log-Ingestion Performance Testing
+
+USAGE:
+  abc-runner syslog [options]
+
+DESCRIPTION:
+  Emit RFC5424 syslog messages over UDP, TCP or TLS at high rates to
+  benchmark log pipelines such as rsyslog, syslog-ng, Vector or Logstash.
+  Every concurrent sender owns one connection. Each message carries a
+  sequence number (seq=N) so the receiving side can detect loss.
+
+OPTIONS:
+  --help                   Show this help message
+  --host HOST, -h HOST     Syslog server host (default: localhost)
+  --port PORT, -p PORT     Syslog server port (default: 514)
+  --transport PROTO        udp, tcp or tls (default: udp)
+  --framing MODE           TCP/TLS framing per RFC6587: octet-counting or lf
+                           (default: octet-counting)
+  --message-size N[-M]     Message size in bytes, excluding framing. A range
+                           picks a random size per message (default: 256)
+  --facility N             Facility 0-23 (default: 16, local0)
+  --severity N             Severity 0-7 (default: 6, informational)
+  --hostname NAME          HOSTNAME header field (default: local hostname)
+  --app-name NAME          APP-NAME header field (default: abc-runner)
+  --msg-id ID              MSGID header field (default: -)
+  --structured-data        Add [abc@32473 seq="N"] structured data
+  --rate N                 Cap the send rate at N messages/sec (default: unlimited)
+  --timeout DURATION       Connect and write timeout (default: 5s)
+  --insecure, -k           Skip TLS certificate verification
+  --ca-file FILE           CA bundle for verifying the server certificate
+  --server-name NAME       TLS SNI and verification name (default: host)
+  -n COUNT                 Total messages (default: 10000)
+  -c COUNT                 Concurrent senders/connections (default: 10)
+  --duration DURATION      Run for a fixed duration instead of -n
+
+NOTES:
+  UDP sends are fire-and-forget: a successful send means the datagram left
+  this host, not that the server accepted it. Compare the message count with
+  what the pipeline received to measure loss.
+
+EXAMPLES:
+  abc-runner syslog --help
+  abc-runner syslog -h localhost -p 514 -n 100000 -c 20
+  abc-runner syslog -h vector --transport tcp -p 6514 --message-size 200-2000 --duration 60s
+  abc-runner syslog -h logstash --transport tcp --framing lf --rate 5000 --duration 5m
+  abc-runner syslog -h rsyslog --transport tls -p 6514 --ca-file ca.pem -c 50` + configLayersHelp + runOptionsHelp + "\n"
+}
+
+// parseArgs 解析命令行参数
+func (h *SyslogCommandHandler) parseArgs(args []string) (*syslogConfig.SyslogConfig, error) {
+	config := syslogConfig.NewDefaultSyslogConfig()
+
+	// 依次应用--config文件、ABC_SYSLOG_*环境变量与--set，专用参数随后覆盖
+	if err := applyConfigLayers("syslog", args, config); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(args); i++ {
+		// 无参数的开关
+		switch args[i] {
+		case "--structured-data":
+			config.SyslogSpecific.StructuredData = true
+			continue
+		case "--insecure", "-k":
+			config.Connection.TLS.InsecureSkipVerify = true
+			continue
+		}
+
+		if i+1 >= len(args) {
+			break
+		}
+		value := args[i+1]
+		var err error
+
+		switch args[i] {
+		case "--host", "-h":
+			if !looksLikeHostname(value) {
+				continue
+			}
+			config.Connection.Address = value
+		case "--port", "-p":
+			config.Connection.Port, err = strconv.Atoi(value)
+		case "--transport":
+			config.Connection.Transport = strings.ToLower(value)
+		case "--framing":
+			config.SyslogSpecific.Framing = strings.ToLower(value)
+		case "--message-size":
+			config.SyslogSpecific.MessageSize, config.SyslogSpecific.MaxMessageSize, err = parseSizeRange(value)
+		case "--facility":
+			config.SyslogSpecific.Facility, err = strconv.Atoi(value)
+		case "--severity":
+			config.SyslogSpecific.Severity, err = strconv.Atoi(value)
+		case "--hostname":
+			config.SyslogSpecific.Hostname = value
+		case "--app-name":
+			config.SyslogSpecific.AppName = value
+		case "--msg-id":
+			config.SyslogSpecific.MsgID = value
+		case "--rate":
+			config.BenchMark.Rate, err = strconv.Atoi(value)
+		case "--timeout":
+			config.Connection.Timeout, err = time.ParseDuration(value)
+		case "--ca-file":
+			config.Connection.TLS.CAFile = value
+		case "--server-name":
+			config.Connection.TLS.ServerName = value
+		case "-n":
+			config.BenchMark.Total, err = strconv.Atoi(value)
+		case "-c":
+			config.BenchMark.Parallels, err = strconv.Atoi(value)
+		case "--duration":
+			config.BenchMark.Duration, err = time.ParseDuration(value)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", args[i], err)
+		}
+		i++
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// parseSizeRange 解析"N"或"N-M"形式的长度范围，固定长度时max为0
+func parseSizeRange(value string) (int, int, error) {
+	minPart, maxPart, isRange := strings.Cut(value, "-")
+	minSize, err := strconv.Atoi(strings.TrimSpace(minPart))
+	if err != nil || !isRange {
+		return minSize, 0, err
+	}
+	maxSize, err := strconv.Atoi(strings.TrimSpace(maxPart))
+	return minSize, maxSize, err
+}
+
+// runPerformanceTest 运行Syslog发送测试
+func (h *SyslogCommandHandler) runPerformanceTest(
+	ctx context.Context,
+	adapter interfaces.ProtocolAdapter,
+	config *syslogConfig.SyslogConfig,
+	collector *metrics.BaseCollector[map[string]interface{}],
+	opts *runOptions,
+) error {
+	factory := operations.NewOperationFactory(config.BenchMark.TestCase)
+	engine := execution.NewExecutionEngine(adapter, collector, factory)
+	engine.SetMaxWorkers(config.BenchMark.Parallels)
+	opts.applyToEngine(engine, collector)
+
+	testStartTime := time.Now()
+	result, err := engine.RunBenchmark(ctx, &config.BenchMark)
+	if err != nil {
+		return fmt.Errorf("benchmark execution failed: %w", err)
+	}
+	opts.finishRun()
+	actualTestDuration := time.Since(testStartTime)
+
+	fmt.Printf("\n📊 Execution Results:\n")
+	fmt.Printf("Total Jobs: %d\n", result.TotalJobs)
+	fmt.Printf("Completed Jobs: %d\n", result.CompletedJobs)
+	fmt.Printf("Success Jobs: %d\n", result.SuccessJobs)
+	fmt.Printf("Failed Jobs: %d\n", result.FailedJobs)
+	fmt.Printf("Actual Test Duration: %v\n", actualTestDuration)
+
+	protocolMetrics := map[string]interface{}{
+		"protocol":         "syslog",
+		"test_type":        "performance",
+		"transport":        config.Connection.Transport,
+		"actual_duration":  actualTestDuration,
+		"execution_result": result,
+	}
+
+	if statsAdapter, ok := adapter.(interface {
+		GetSendStats() *operations.SendStats
+	}); ok {
+		if stats := statsAdapter.GetSendStats(); stats != nil {
+			seconds := actualTestDuration.Seconds()
+			fmt.Printf("Messages Sent: %d (%.2f msg/sec)\n", stats.Messages, float64(stats.Messages)/seconds)
+			fmt.Printf("Bytes Sent: %d (%.2f MB/sec)\n", stats.Bytes, float64(stats.Bytes)/seconds/1024/1024)
+			fmt.Printf("Frame Size min/avg/max: %d/%.0f/%d bytes\n", stats.MinSize, stats.AvgSize, stats.MaxSize)
+			if stats.Reconnects > 0 {
+				fmt.Printf("Reconnects: %d\n", stats.Reconnects)
+			}
+			protocolMetrics["send_stats"] = *stats
+			protocolMetrics["throughput_bytes_per_sec"] = float64(stats.Bytes) / seconds
+		}
+	}
+
+	collector.UpdateProtocolMetrics(protocolMetrics)
+	return nil
+}
+
+// generateReport 生成Syslog测试报告
+func (h *SyslogCommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
+	snapshot := collector.Snapshot()
+
+	// 使用实际测试时间修正吞吐量
+	if duration, ok := snapshot.Protocol["actual_duration"].(time.Duration); ok && duration > 0 {
+		snapshot.Core.Duration = duration
+		seconds := duration.Seconds()
+		snapshot.Core.Throughput.RPS = float64(snapshot.Core.Operations.Total) / seconds
+		snapshot.Core.Throughput.WriteRPS = float64(snapshot.Core.Operations.Write) / seconds
+		snapshot.Core.Throughput.ReadMBps, snapshot.Core.Throughput.WriteMBps = metrics.Bandwidth(snapshot.Core.Operations, duration)
+	}
+
+	fmt.Printf("\n📊 Syslog Performance Metrics:\n")
+	fmt.Printf("=====================================\n")
+	fmt.Printf("  Total Messages: %d\n", snapshot.Core.Operations.Total)
+	fmt.Printf("  Successful Sends: %d\n", snapshot.Core.Operations.Success)
+	fmt.Printf("  Failed Sends: %d\n", snapshot.Core.Operations.Failed)
+	fmt.Printf("  Success Rate: %.2f%%\n", snapshot.Core.Operations.Rate)
+	fmt.Printf("  Send Latency avg/p95/p99: %v/%v/%v\n",
+		snapshot.Core.Latency.Average, snapshot.Core.Latency.P95, snapshot.Core.Latency.P99)
+	fmt.Printf("  Throughput: %.2f msg/sec\n", snapshot.Core.Throughput.RPS)
+	fmt.Printf("=====================================\n")
+
+	// 存在快照接收器时（如分布式agent模式）交由接收器处理，不生成本地报告
+	if opts.publishSnapshot(snapshot) {
+		return nil
+	}
+
+	report := reporting.ConvertFromMetricsSnapshot(snapshot)
+	reportConfig := reporting.NewStandardReportConfig("syslog")
+	opts.applyToReport(snapshot, report, reportConfig)
+	generator := reporting.NewReportGenerator(reportConfig)
+	if err := generator.Generate(report); err != nil {
+		return err
+	}
+	return opts.resultError(report)
+}
+
+// GetProtocolName 获取协议名称
+func (h *SyslogCommandHandler) GetProtocolName() string {
+	return h.protocolName
+}
@@ -12,6 +12,7 @@ import (
 	"abc-runner/app/core/execution"
 	"abc-runner/app/core/interfaces"
 	"abc-runner/app/core/metrics"
+	"abc-runner/app/core/transport"
 	"abc-runner/app/reporting"
 )
 
@@ -52,11 +53,20 @@ func (t *TCPCommandHandler) Execute(ctx context.Context, args []string) error {
 	// 解析命令行参数
 	config, err := t.parseArgs(args)
 	if err != nil {
-		return fmt.Errorf("failed to parse arguments: %w", err)
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	// 解析通用运行选项
+	opts, err := parseRunOptions(ctx, "tcp", args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+	if printConfigRequested(args) {
+		return printEffectiveConfig("tcp", config, opts)
 	}
 
 	// 创建TCP适配器
-	metricsConfig := metrics.DefaultMetricsConfig()
+	metricsConfig := opts.collectorConfig()
 	metricsCollector := metrics.NewBaseCollector(metricsConfig, map[string]interface{}{
 		"protocol":  "tcp",
 		"test_type": "performance",
@@ -80,14 +90,17 @@ func (t *TCPCommandHandler) Execute(ctx context.Context, args []string) error {
 	fmt.Printf("Test Case: %s\n", config.BenchMark.TestCase)
 	fmt.Printf("Operations: %d, Concurrency: %d, Data Size: %d bytes\n",
 		config.BenchMark.Total, config.BenchMark.Parallels, config.BenchMark.DataSize)
+	if config.TCPSpecific.Transport == transport.IOURing {
+		fmt.Printf("🧪 Transport: io_uring (experimental)\n")
+	}
 
-	err = t.runPerformanceTest(ctx, adapter, config, metricsCollector)
+	err = t.runPerformanceTest(ctx, adapter, config, metricsCollector, opts)
 	if err != nil {
 		return fmt.Errorf("performance test failed: %w", err)
 	}
 
 	// 生成并显示报告
-	return t.generateReport(metricsCollector)
+	return t.generateReport(metricsCollector, opts)
 }
 
 // GetHelp 获取帮助信息
@@ -111,6 +124,9 @@ OPTIONS:
   --duration DURATION Test duration (default: 60s)
   --no-delay          Disable Nagle algorithm (default: true)
   --keep-alive        Enable TCP keep-alive (default: true)
+  --transport KIND    Network transport: net, io_uring (default: net)
+                      io_uring is experimental, Linux only, and requires
+                      a build with -tags uring
   
 TEST CASES:
   echo_test           Send data and verify echo response
@@ -123,9 +139,10 @@ EXAMPLES:
   abc-runner tcp --host localhost --port 9090
   abc-runner tcp --host 192.168.1.100 --port 9090 --test-case echo_test
   abc-runner tcp -h localhost -p 9090 -n 5000 -c 20 --data-size 2048
+  abc-runner tcp --host localhost --port 9090 --transport io_uring
 
 NOTE: 
-  This implementation performs real TCP performance testing with metrics collection.`
+  This implementation performs real TCP performance testing with metrics collection.` + configLayersHelp + runOptionsHelp
 }
 
 // parseArgs 解析命令行参数
@@ -133,6 +150,11 @@ func (t *TCPCommandHandler) parseArgs(args []string) (*tcpConfig.TCPConfig, erro
 	// 创建默认配置
 	config := tcpConfig.NewDefaultTCPConfig()
 
+	// 依次应用--config文件、ABC_TCP_*环境变量与--set，专用参数随后覆盖
+	if err := applyConfigLayers("tcp", args, config); err != nil {
+		return nil, err
+	}
+
 	// 解析参数
 	for i := 0; i < len(args); i++ {
 		switch args[i] {
@@ -192,28 +214,39 @@ func (t *TCPCommandHandler) parseArgs(args []string) (*tcpConfig.TCPConfig, erro
 			config.TCPSpecific.NoDelay = true
 		case "--keep-alive":
 			config.Connection.KeepAlive = true
+		case "--transport":
+			if i+1 < len(args) {
+				config.TCPSpecific.Transport = args[i+1]
+				i++
+			}
 		}
 	}
 
+	// 不可用的传输直接报错，而不是连接失败后退回模拟模式
+	if err := transport.Validate(config.TCPSpecific.Transport); err != nil {
+		return nil, err
+	}
+
 	return config, nil
 }
 
 // runPerformanceTest 运行性能测试
-func (t *TCPCommandHandler) runPerformanceTest(ctx context.Context, adapter interfaces.ProtocolAdapter, config *tcpConfig.TCPConfig, collector *metrics.BaseCollector[map[string]interface{}]) error {
+func (t *TCPCommandHandler) runPerformanceTest(ctx context.Context, adapter interfaces.ProtocolAdapter, config *tcpConfig.TCPConfig, collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
 	// 执行健康检查
 	if err := adapter.HealthCheck(ctx); err != nil {
 		fmt.Printf("⚠️  Health check failed: %v\n", err)
 		fmt.Printf("🔄 Switching to simulation mode - this will generate mock test data instead of real TCP operations\n")
+		opts.markSimulated(err)
 		return t.runSimulationTest(config, collector)
 	}
 
 	// 使用新的TCP特定组件执行真实测试
-	return t.runConcurrentTest(ctx, adapter, config, collector)
+	return t.runConcurrentTest(ctx, adapter, config, collector, opts)
 }
 
 // runConcurrentTest 使用ExecutionEngine运行并发测试
 // runConcurrentTest 使用ExecutionEngine运行并发测试
-func (t *TCPCommandHandler) runConcurrentTest(ctx context.Context, adapter interfaces.ProtocolAdapter, config *tcpConfig.TCPConfig, collector *metrics.BaseCollector[map[string]interface{}]) error {
+func (t *TCPCommandHandler) runConcurrentTest(ctx context.Context, adapter interfaces.ProtocolAdapter, config *tcpConfig.TCPConfig, collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
 	// 创建基准配置适配器
 	benchmarkConfig := tcpConfig.NewBenchmarkConfigAdapter(config.GetBenchmark())
 
@@ -224,8 +257,9 @@ func (t *TCPCommandHandler) runConcurrentTest(ctx context.Context, adapter inter
 	engine := execution.NewExecutionEngine(adapter, collector, operationFactory)
 
 	// 配置执行引擎参数（根据设计文档优化）
-	engine.SetMaxWorkers(200)         // 提高最大工作协程数支持TCP并发
-	engine.SetBufferSizes(2000, 2000) // 增大缓冲区减少任务调度延迟
+	engine.SetMaxWorkers(200)             // 提高最大工作协程数支持TCP并发
+	engine.SetBufferSizes(2000, 2000)     // 增大缓冲区减少任务调度延迟
+	opts.applyToEngine(engine, collector) // 应用通用运行选项
 
 	// 记录测试开始时间
 	testStartTime := time.Now()
@@ -235,6 +269,7 @@ func (t *TCPCommandHandler) runConcurrentTest(ctx context.Context, adapter inter
 	if err != nil {
 		return fmt.Errorf("benchmark execution failed: %w", err)
 	}
+	opts.finishRun()
 
 	// 计算实际测试时间
 	actualTestDuration := time.Since(testStartTime)
@@ -254,19 +289,38 @@ func (t *TCPCommandHandler) runConcurrentTest(ctx context.Context, adapter inter
 		actualQPS := float64(result.CompletedJobs) / actualTestDuration.Seconds()
 		fmt.Printf("   Actual QPS: %.2f connections/sec\n", actualQPS)
 	}
+	if stats, ok := adapter.(*tcp.TCPAdapter).TransportStats(); ok {
+		printTransportStats(stats)
+	}
 
 	// 更新收集器的协议数据，包含实际测试时间
-	collector.UpdateProtocolMetrics(map[string]interface{}{
+	protocolMetrics := map[string]interface{}{
 		"protocol":         "tcp",
 		"test_type":        "performance",
 		"actual_duration":  actualTestDuration,
 		"execution_result": result,
 		"test_case":        config.BenchMark.TestCase,
-	})
+		"target":           config.Connection.Address,
+	}
+	if phases, ok := adapter.(*tcp.TCPAdapter).LatencyPhases(); ok {
+		printLatencyPhases(phases)
+		protocolMetrics["latency_phases"] = phases
+	}
+	collector.UpdateProtocolMetrics(protocolMetrics)
 
 	return nil
 }
 
+// printTransportStats 输出io_uring传输的批量提交统计
+func printTransportStats(stats transport.Stats) {
+	fmt.Printf("   io_uring: %d ops over %d connections, %d submit calls, %d reap calls\n",
+		stats.Operations, stats.Connections, stats.SubmitCalls, stats.ReapCalls)
+	if stats.SubmitCalls > 0 && stats.ReapCalls > 0 {
+		fmt.Printf("   io_uring batching: %.1f ops/submit, %.1f completions/reap\n",
+			float64(stats.Operations)/float64(stats.SubmitCalls), float64(stats.Completions)/float64(stats.ReapCalls))
+	}
+}
+
 // runSimulationTest 运行模拟测试
 func (t *TCPCommandHandler) runSimulationTest(config *tcpConfig.TCPConfig, collector *metrics.BaseCollector[map[string]interface{}]) error {
 	fmt.Printf("🎭 Running TCP simulation test...\n")
@@ -306,7 +360,7 @@ func (t *TCPCommandHandler) runSimulationTest(config *tcpConfig.TCPConfig, colle
 
 // generateReport 生成报告
 // generateReport 生成TCP性能测试报告
-func (t *TCPCommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}]) error {
+func (t *TCPCommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
 	snapshot := collector.Snapshot()
 
 	// 从协议数据中获取实际测试时间
@@ -331,6 +385,7 @@ func (t *TCPCommandHandler) generateReport(collector *metrics.BaseCollector[map[
 		snapshot.Core.Throughput.RPS = float64(total) / seconds
 		snapshot.Core.Throughput.ReadRPS = float64(snapshot.Core.Operations.Read) / seconds
 		snapshot.Core.Throughput.WriteRPS = float64(snapshot.Core.Operations.Write) / seconds
+		snapshot.Core.Throughput.ReadMBps, snapshot.Core.Throughput.WriteMBps = metrics.Bandwidth(snapshot.Core.Operations, actualDuration)
 	}
 
 	fmt.Printf("\n📊 TCP Performance Test Results:\n")
@@ -377,11 +432,20 @@ func (t *TCPCommandHandler) generateReport(collector *metrics.BaseCollector[map[
 	fmt.Printf("\nTest Duration: %v (Actual: %v)\n", core.Duration, actualDuration)
 	fmt.Printf("=====================================\n")
 
+	// 存在快照接收器时（如分布式agent模式）交由接收器处理，不生成本地报告
+	if opts.publishSnapshot(snapshot) {
+		return nil
+	}
+
 	// 生成结构化文件报告（使用修正后的数据）
 	report := reporting.ConvertFromMetricsSnapshot(snapshot)
 	reportConfig := reporting.NewStandardReportConfig("tcp")
+	opts.applyToReport(snapshot, report, reportConfig)
 	generator := reporting.NewReportGenerator(reportConfig)
-	return generator.Generate(report)
+	if err := generator.Generate(report); err != nil {
+		return err
+	}
+	return opts.resultError(report)
 }
 
 // generateTestData 生成测试数据
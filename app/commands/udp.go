@@ -53,11 +53,20 @@ func (u *UDPCommandHandler) Execute(ctx context.Context, args []string) error {
 	// 解析命令行参数
 	config, err := u.parseArgs(args)
 	if err != nil {
-		return fmt.Errorf("failed to parse arguments: %w", err)
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	// 解析通用运行选项
+	opts, err := parseRunOptions(ctx, "udp", args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+	if printConfigRequested(args) {
+		return printEffectiveConfig("udp", config, opts)
 	}
 
 	// 创建UDP适配器
-	metricsConfig := metrics.DefaultMetricsConfig()
+	metricsConfig := opts.collectorConfig()
 	metricsCollector := metrics.NewBaseCollector(metricsConfig, map[string]interface{}{
 		"protocol":  "udp",
 		"test_type": "performance",
@@ -87,13 +96,13 @@ func (u *UDPCommandHandler) Execute(ctx context.Context, args []string) error {
 		fmt.Printf("Multicast Group: %s, TTL: %d\n", config.UDPSpecific.MulticastGroup, config.UDPSpecific.TTL)
 	}
 
-	err = u.runPerformanceTest(ctx, adapter, config, metricsCollector)
+	err = u.runPerformanceTest(ctx, adapter, config, metricsCollector, opts)
 	if err != nil {
 		return fmt.Errorf("performance test failed: %w", err)
 	}
 
 	// 生成并显示报告
-	return u.generateReport(metricsCollector)
+	return u.generateReport(metricsCollector, opts)
 }
 
 // GetHelp 获取帮助信息
@@ -140,7 +149,7 @@ EXAMPLES:
 
 NOTE: 
   UDP testing supports unicast, broadcast, and multicast modes.
-  For multicast testing, ensure proper network configuration.`
+  For multicast testing, ensure proper network configuration.` + configLayersHelp + runOptionsHelp
 }
 
 // parseArgs 解析命令行参数
@@ -148,6 +157,11 @@ func (u *UDPCommandHandler) parseArgs(args []string) (*udpConfig.UDPConfig, erro
 	// 创建默认配置
 	config := udpConfig.NewDefaultUDPConfig()
 
+	// 依次应用--config文件、ABC_UDP_*环境变量与--set，专用参数随后覆盖
+	if err := applyConfigLayers("udp", args, config); err != nil {
+		return nil, err
+	}
+
 	// 解析参数
 	for i := 0; i < len(args); i++ {
 		switch args[i] {
@@ -245,11 +259,12 @@ func (u *UDPCommandHandler) parseArgs(args []string) (*udpConfig.UDPConfig, erro
 
 // runPerformanceTest 运行性能测试
 // runPerformanceTest 运行UDP性能测试
-func (u *UDPCommandHandler) runPerformanceTest(ctx context.Context, adapter interfaces.ProtocolAdapter, config *udpConfig.UDPConfig, collector *metrics.BaseCollector[map[string]interface{}]) error {
+func (u *UDPCommandHandler) runPerformanceTest(ctx context.Context, adapter interfaces.ProtocolAdapter, config *udpConfig.UDPConfig, collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
 	// 执行健康检查
 	if err := adapter.HealthCheck(ctx); err != nil {
 		fmt.Printf("⚠️  Health check failed: %v\n", err)
 		fmt.Printf("🔄 Switching to simulation mode - this will generate mock UDP test data\n")
+		opts.markSimulated(err)
 		return u.runSimulationTest(config, collector)
 	}
 
@@ -257,6 +272,7 @@ func (u *UDPCommandHandler) runPerformanceTest(ctx context.Context, adapter inte
 	factory := operations.NewSimpleOperationFactory(config.BenchMark.TestCase, config.BenchMark.DataSize)
 	benchConfig := udpConfig.NewSimpleBenchmarkConfig(config.BenchMark.Total, config.BenchMark.Parallels, config.BenchMark.Duration)
 	engine := execution.NewExecutionEngine(adapter, collector, factory)
+	opts.applyToEngine(engine, collector)
 
 	// 执行测试
 	fmt.Printf("📊 Sending %d packets with %d concurrent workers...\n",
@@ -270,6 +286,7 @@ func (u *UDPCommandHandler) runPerformanceTest(ctx context.Context, adapter inte
 	if err != nil {
 		return fmt.Errorf("benchmark execution failed: %w", err)
 	}
+	opts.finishRun()
 
 	fmt.Printf("✅ Test completed in %v (Actual: %v)\n", result.TotalDuration, actualTestDuration)
 	fmt.Printf("📈 Processed %d packets (%d successful, %d failed)\n",
@@ -288,6 +305,7 @@ func (u *UDPCommandHandler) runPerformanceTest(ctx context.Context, adapter inte
 		"actual_duration":  actualTestDuration,
 		"execution_result": result,
 		"test_case":        config.BenchMark.TestCase,
+		"target":           config.Connection.Address,
 	})
 
 	return nil
@@ -335,7 +353,7 @@ func (u *UDPCommandHandler) runSimulationTest(config *udpConfig.UDPConfig, colle
 
 // generateReport 生成报告
 // generateReport 生成UDP性能测试报告
-func (u *UDPCommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}]) error {
+func (u *UDPCommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
 	snapshot := collector.Snapshot()
 
 	// 从协议数据中获取实际测试时间
@@ -360,6 +378,7 @@ func (u *UDPCommandHandler) generateReport(collector *metrics.BaseCollector[map[
 		snapshot.Core.Throughput.RPS = float64(total) / seconds
 		snapshot.Core.Throughput.ReadRPS = float64(snapshot.Core.Operations.Read) / seconds
 		snapshot.Core.Throughput.WriteRPS = float64(snapshot.Core.Operations.Write) / seconds
+		snapshot.Core.Throughput.ReadMBps, snapshot.Core.Throughput.WriteMBps = metrics.Bandwidth(snapshot.Core.Operations, actualDuration)
 	}
 
 	fmt.Printf("\n📊 UDP Performance Test Results:\n")
@@ -406,11 +425,20 @@ func (u *UDPCommandHandler) generateReport(collector *metrics.BaseCollector[map[
 	fmt.Printf("\nTest Duration: %v (Actual: %v)\n", core.Duration, actualDuration)
 	fmt.Printf("=====================================\n")
 
+	// 存在快照接收器时（如分布式agent模式）交由接收器处理，不生成本地报告
+	if opts.publishSnapshot(snapshot) {
+		return nil
+	}
+
 	// 生成结构化文件报告（使用修正后的数据）
 	report := reporting.ConvertFromMetricsSnapshot(snapshot)
 	reportConfig := reporting.NewStandardReportConfig("udp")
+	opts.applyToReport(snapshot, report, reportConfig)
 	generator := reporting.NewReportGenerator(reportConfig)
-	return generator.Generate(report)
+	if err := generator.Generate(report); err != nil {
+		return err
+	}
+	return opts.resultError(report)
 }
 
 // generatePacketData 生成数据包数据
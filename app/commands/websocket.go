@@ -50,11 +50,29 @@ func (h *WebSocketCommandHandler) Execute(ctx context.Context, args []string) er
 	// 解析命令行参数并创建配置
 	wsConfig, err := h.parseArgsToConfig(args)
 	if err != nil {
-		return fmt.Errorf("failed to parse arguments: %w", err)
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+
+	// 解析通用运行选项
+	opts, err := parseRunOptions(ctx, "websocket", args)
+	if err != nil {
+		return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+	}
+	if printConfigRequested(args) {
+		return printEffectiveConfig("websocket", wsConfig, opts)
+	}
+
+	// 重连风暴直接建立真实连接，不经过适配器
+	if wsConfig.BenchMark.TestCase == "reconnect_storm" {
+		stormConfig, err := h.parseStormArgs(args, wsConfig)
+		if err != nil {
+			return NewConfigError(fmt.Errorf("failed to parse arguments: %w", err))
+		}
+		return h.runReconnectStorm(ctx, wsConfig, stormConfig, opts)
 	}
 
 	// 创建指标收集器
-	metricsConfig := metrics.DefaultMetricsConfig()
+	metricsConfig := opts.collectorConfig()
 	collector := metrics.NewBaseCollector(metricsConfig, map[string]interface{}{
 		"protocol":  "websocket",
 		"test_type": "performance",
@@ -75,7 +93,8 @@ func (h *WebSocketCommandHandler) Execute(ctx context.Context, args []string) er
 		fmt.Printf("⚠️  Connection failed to %s: %v\n", wsConfig.Connection.URL, err)
 		fmt.Printf("🔍 Possible causes: WebSocket server not running, wrong URL, or network issues\n")
 		// 如果连接失败，运行模拟测试
-		return h.runSimulationTest(wsConfig, collector)
+		opts.markSimulated(err)
+		return h.runSimulationTest(wsConfig, collector, opts)
 	}
 
 	fmt.Printf("✅ Successfully connected to WebSocket server\n")
@@ -84,11 +103,12 @@ func (h *WebSocketCommandHandler) Execute(ctx context.Context, args []string) er
 	if err := adapter.HealthCheck(ctx); err != nil {
 		fmt.Printf("⚠️  Health check failed: %v\n", err)
 		fmt.Printf("🔄 Switching to simulation mode - this will generate mock test data instead of real WebSocket operations\n")
-		return h.runSimulationTest(wsConfig, collector)
+		opts.markSimulated(err)
+		return h.runSimulationTest(wsConfig, collector, opts)
 	}
 
 	// 健康检查通过，使用新的ExecutionEngine执行真实测试
-	return h.runConcurrentTest(ctx, adapter, wsConfig, collector)
+	return h.runConcurrentTest(ctx, adapter, wsConfig, collector, opts)
 }
 
 // GetHelp 获取帮助信息
@@ -117,15 +137,36 @@ TEST CASES:
   ping_pong          Ping-pong heartbeat test
   broadcast          Broadcast message test
   large_message      Large message transfer test
-  
+  reconnect_storm    Hold -c real connections, drop a fraction of them at
+                     once and measure how long clients take to reconnect
+                     under the configured backoff policy
+
+RECONNECT STORM OPTIONS:
+  --storm-fraction F        Share of connected clients dropped per storm
+                            (default: 0.5)
+  --storms N                Number of storms (default: 1)
+  --storm-interval DUR      Hold connections before each storm (default: 5s)
+  --reconnect-timeout DUR   Give up on clients not reconnected within this
+                            time after a storm (default: 60s)
+  --backoff-initial DUR     Delay before the first reconnect (default: 500ms)
+  --backoff-max DUR         Backoff cap (default: 30s)
+  --backoff-multiplier X    Backoff growth per attempt (default: 2)
+  --backoff-jitter MODE     none, full ([0, delay]) or equal
+                            ([delay/2, delay]) (default: full)
+  --max-reconnect-attempts N
+                            Attempts per client before giving up
+                            (default: 0, until --reconnect-timeout)
+
 EXAMPLES:
   abc-runner websocket --help
   abc-runner websocket --url ws://localhost:8080/ws
   abc-runner websocket --url wss://example.com/ws --test-case ping_pong
   abc-runner websocket --url ws://192.168.1.100:8080/ws -c 20 --duration 60s
+  abc-runner websocket --url ws://localhost:8080/ws --test-case reconnect_storm -c 1000 \
+    --storm-fraction 0.8 --storms 3 --backoff-jitter none
 
 NOTE: 
-  This implementation performs real WebSocket performance testing with metrics collection.`
+  This implementation performs real WebSocket performance testing with metrics collection.` + configLayersHelp + runOptionsHelp
 }
 
 // parseArgsToConfig 解析命令行参数并创建WebSocket配置
@@ -133,6 +174,11 @@ func (h *WebSocketCommandHandler) parseArgsToConfig(args []string) (*config.WebS
 	// 创建默认配置
 	wsConfig := config.NewDefaultWebSocketConfig()
 
+	// 依次应用--config文件、ABC_WEBSOCKET_*环境变量与--set，专用参数随后覆盖
+	if err := applyConfigLayers("websocket", args, wsConfig); err != nil {
+		return nil, err
+	}
+
 	// 解析参数
 	for i := 0; i < len(args); i++ {
 		switch args[i] {
@@ -143,7 +189,7 @@ func (h *WebSocketCommandHandler) parseArgsToConfig(args []string) (*config.WebS
 			}
 		case "--test-case":
 			if i+1 < len(args) {
-				validCases := []string{"message_exchange", "ping_pong", "broadcast", "large_message"}
+				validCases := []string{"message_exchange", "ping_pong", "broadcast", "large_message", "reconnect_storm"}
 				testCase := args[i+1]
 				for _, valid := range validCases {
 					if testCase == valid {
@@ -216,7 +262,7 @@ func (h *WebSocketCommandHandler) parseArgs(args []string) (map[string]interface
 			}
 		case "--test-case":
 			if i+1 < len(args) {
-				validCases := []string{"message_exchange", "ping_pong", "broadcast", "large_message"}
+				validCases := []string{"message_exchange", "ping_pong", "broadcast", "large_message", "reconnect_storm"}
 				testCase := args[i+1]
 				for _, valid := range validCases {
 					if testCase == valid {
@@ -273,7 +319,7 @@ func (h *WebSocketCommandHandler) parseArgs(args []string) (map[string]interface
 }
 
 // runSimulationTest 运行模拟测试
-func (h *WebSocketCommandHandler) runSimulationTest(config *config.WebSocketConfig, collector *metrics.BaseCollector[map[string]interface{}]) error {
+func (h *WebSocketCommandHandler) runSimulationTest(config *config.WebSocketConfig, collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
 	fmt.Printf("🎭 Running WebSocket simulation test...\n")
 
 	// 生成模拟数据
@@ -302,11 +348,11 @@ func (h *WebSocketCommandHandler) runSimulationTest(config *config.WebSocketConf
 	}
 
 	fmt.Printf("✅ WebSocket simulation test completed\n")
-	return h.generateReport(collector)
+	return h.generateReport(collector, opts)
 }
 
 // runConcurrentTest 使用ExecutionEngine运行并发测试
-func (h *WebSocketCommandHandler) runConcurrentTest(ctx context.Context, adapter interfaces.ProtocolAdapter, wsConfig *config.WebSocketConfig, collector *metrics.BaseCollector[map[string]interface{}]) error {
+func (h *WebSocketCommandHandler) runConcurrentTest(ctx context.Context, adapter interfaces.ProtocolAdapter, wsConfig *config.WebSocketConfig, collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
 	fmt.Printf("📊 Running concurrent WebSocket performance test with ExecutionEngine...\n")
 
 	// 创建基准配置适配器
@@ -319,8 +365,9 @@ func (h *WebSocketCommandHandler) runConcurrentTest(ctx context.Context, adapter
 	engine := execution.NewExecutionEngine(adapter, collector, operationFactory)
 
 	// 配置执行引擎参数
-	engine.SetMaxWorkers(100)         // 设置最大工作协程数
-	engine.SetBufferSizes(1000, 1000) // 设置缓冲区大小
+	engine.SetMaxWorkers(100)             // 设置最大工作协程数
+	engine.SetBufferSizes(1000, 1000)     // 设置缓冲区大小
+	opts.applyToEngine(engine, collector) // 应用通用运行选项
 
 	// 记录测试开始时间
 	testStartTime := time.Now()
@@ -330,6 +377,7 @@ func (h *WebSocketCommandHandler) runConcurrentTest(ctx context.Context, adapter
 	if err != nil {
 		return fmt.Errorf("benchmark execution failed: %w", err)
 	}
+	opts.finishRun()
 
 	// 计算实际测试时间
 	actualTestDuration := time.Since(testStartTime)
@@ -354,15 +402,16 @@ func (h *WebSocketCommandHandler) runConcurrentTest(ctx context.Context, adapter
 		"protocol":         "websocket",
 		"test_type":        "performance",
 		"test_case":        wsConfig.BenchMark.TestCase,
+		"target":           wsConfig.Connection.URL,
 		"actual_duration":  actualTestDuration,
 		"execution_result": result,
 	})
 
-	return h.generateReport(collector)
+	return h.generateReport(collector, opts)
 }
 
 // generateReport 生成报告
-func (h *WebSocketCommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}]) error {
+func (h *WebSocketCommandHandler) generateReport(collector *metrics.BaseCollector[map[string]interface{}], opts *runOptions) error {
 	// 获取指标快照
 	snapshot := collector.Snapshot()
 
@@ -388,6 +437,12 @@ func (h *WebSocketCommandHandler) generateReport(collector *metrics.BaseCollecto
 		snapshot.Core.Throughput.RPS = float64(total) / seconds
 		snapshot.Core.Throughput.ReadRPS = float64(snapshot.Core.Operations.Read) / seconds
 		snapshot.Core.Throughput.WriteRPS = float64(snapshot.Core.Operations.Write) / seconds
+		snapshot.Core.Throughput.ReadMBps, snapshot.Core.Throughput.WriteMBps = metrics.Bandwidth(snapshot.Core.Operations, actualDuration)
+	}
+
+	// 存在快照接收器时（如分布式agent模式）交由接收器处理，不生成本地报告
+	if opts.publishSnapshot(snapshot) {
+		return nil
 	}
 
 	// 生成结构化报告（使用修正后的数据）
@@ -395,11 +450,15 @@ func (h *WebSocketCommandHandler) generateReport(collector *metrics.BaseCollecto
 
 	// 使用标准报告配置
 	reportConfig := reporting.NewStandardReportConfig("websocket")
+	opts.applyToReport(snapshot, report, reportConfig)
 
 	generator := reporting.NewReportGenerator(reportConfig)
 
 	// 生成并显示报告
-	return generator.Generate(report)
+	if err := generator.Generate(report); err != nil {
+		return err
+	}
+	return opts.resultError(report)
 }
 
 // GetProtocolName 获取协议名称
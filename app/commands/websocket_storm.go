@@ -0,0 +1,151 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"abc-runner/app/adapters/websocket/config"
+	"abc-runner/app/core/conntest"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/reporting"
+)
+
+// parseStormArgs 解析重连风暴参数，客户端数取-c
+func (h *WebSocketCommandHandler) parseStormArgs(args []string, wsConfig *config.WebSocketConfig) (*conntest.StormConfig, error) {
+	cfg := conntest.NewDefaultStormConfig()
+	cfg.Clients = wsConfig.BenchMark.Parallels
+
+	for i := 0; i+1 < len(args); i++ {
+		value := args[i+1]
+		var err error
+
+		switch args[i] {
+		case "--storm-fraction":
+			cfg.Fraction, err = strconv.ParseFloat(value, 64)
+		case "--storms":
+			cfg.Storms, err = strconv.Atoi(value)
+		case "--storm-interval":
+			cfg.Interval, err = time.ParseDuration(value)
+		case "--reconnect-timeout":
+			cfg.Timeout, err = time.ParseDuration(value)
+		case "--backoff-initial":
+			cfg.Backoff.Initial, err = time.ParseDuration(value)
+		case "--backoff-max":
+			cfg.Backoff.Max, err = time.ParseDuration(value)
+		case "--backoff-multiplier":
+			cfg.Backoff.Multiplier, err = strconv.ParseFloat(value, 64)
+		case "--backoff-jitter":
+			cfg.Backoff.Jitter = value
+		case "--max-reconnect-attempts":
+			cfg.Backoff.MaxAttempts, err = strconv.Atoi(value)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", args[i], err)
+		}
+		i++
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// runReconnectStorm 执行重连风暴测试
+func (h *WebSocketCommandHandler) runReconnectStorm(ctx context.Context, wsConfig *config.WebSocketConfig, cfg *conntest.StormConfig, opts *runOptions) error {
+	dialer, err := conntest.NewDialer(wsConfig.Connection.URL, conntest.DialerOptions{Timeout: wsConfig.Connection.Timeout})
+	if err != nil {
+		return NewConfigError(err)
+	}
+
+	collector := metrics.NewBaseCollector(opts.collectorConfig(), map[string]interface{}{
+		"protocol":  "websocket",
+		"test_type": "reconnect_storm",
+	})
+	defer collector.Stop()
+
+	fmt.Printf("🌪️  Starting WebSocket reconnect storm: target=%s, clients=%d, fraction=%.2f, storms=%d, interval=%v\n",
+		dialer.Target(), cfg.Clients, cfg.Fraction, cfg.Storms, cfg.Interval)
+	fmt.Printf("   Backoff: %s\n", cfg.Backoff)
+
+	opts.applyToCollector(collector)
+	runner := conntest.NewStormRunner(dialer, cfg, collector)
+	stats, err := runner.Run(ctx, func(sample conntest.StormSample) {
+		fmt.Printf("🔌 t=%v storm=%d attempts=%d reconnected=%d failed=%d connected=%d\n",
+			sample.Elapsed, sample.Storm, sample.Attempts, sample.Reconnected, sample.Failed, sample.Connected)
+	})
+	opts.finishRun()
+	if err != nil && stats == nil {
+		return NewConnectionError(fmt.Errorf("reconnect storm failed: %w", err))
+	}
+	if err != nil {
+		fmt.Printf("⚠️  Reconnect storm stopped early: %v\n", err)
+	}
+
+	h.printStormSummary(stats)
+
+	snapshot := collector.Snapshot()
+	snapshot.Protocol["reconnect_storm"] = stats.ToMap()
+	if opts.publishSnapshot(snapshot) {
+		return nil
+	}
+
+	report := reporting.ConvertFromMetricsSnapshot(snapshot)
+	reportConfig := reporting.NewStandardReportConfig("websocket_reconnect_storm")
+	opts.applyToReport(snapshot, report, reportConfig)
+	generator := reporting.NewReportGenerator(reportConfig)
+	if err := generator.Generate(report); err != nil {
+		return err
+	}
+	return opts.resultError(report)
+}
+
+// printStormSummary 输出各次风暴的恢复情况、重连耗时分布与失败原因
+func (h *WebSocketCommandHandler) printStormSummary(stats *conntest.StormStats) {
+	fmt.Printf("\n📊 Reconnect Storm Summary (%s)\n", stats.Target)
+	fmt.Printf("  Backoff:         %s\n", stats.Backoff)
+	fmt.Printf("  Established:     %d/%d\n", stats.Established, stats.Clients)
+	fmt.Printf("  Disconnected:    %d\n", stats.Disconnected)
+	fmt.Printf("  Reconnected:     %d (success rate %.2f%%)\n", stats.Reconnected, stats.SuccessRate)
+	if stats.GaveUp > 0 {
+		fmt.Printf("  Gave up:         %d\n", stats.GaveUp)
+	}
+	fmt.Printf("  Attempts:        %d (%.2f per reconnect)\n", stats.Attempts, stats.AttemptsPerReconnect)
+
+	fmt.Printf("\n%-8s %12s %12s %8s %10s %14s %12s %12s\n", "storm", "disconnected", "reconnected", "gave up", "attempts", "recovery", "p50", "p99")
+	for _, round := range stats.Rounds {
+		fmt.Printf("%-8d %12d %12d %8d %10d %14v %12v %12v\n", round.Storm, round.Disconnected, round.Reconnected,
+			round.GaveUp, round.Attempts, round.RecoveryTime.Round(time.Millisecond),
+			round.Reconnect.P50.Round(time.Millisecond), round.Reconnect.P99.Round(time.Millisecond))
+	}
+
+	fmt.Printf("\n%-12s %12s %12s %12s %12s %12s %12s\n", "latency", "avg", "p50", "p90", "p99", "p999", "max")
+	printLatency := func(name string, m metrics.LatencyMetrics) {
+		fmt.Printf("%-12s %12v %12v %12v %12v %12v %12v\n", name,
+			m.Average.Round(time.Microsecond), m.P50.Round(time.Microsecond), m.P90.Round(time.Microsecond),
+			m.P99.Round(time.Microsecond), m.P999.Round(time.Microsecond), m.Max.Round(time.Microsecond))
+	}
+	printLatency("reconnect", stats.Reconnect)
+	printLatency("handshake", stats.Handshake)
+
+	if len(stats.AttemptDistribution) > 0 {
+		fmt.Printf("\n%-12s %10s %8s\n", "attempts", "clients", "share")
+		for _, bucket := range stats.AttemptDistribution {
+			fmt.Printf("%-12d %10d %7.2f%%\n", bucket.Attempts, bucket.Count, float64(bucket.Count)/float64(stats.Reconnected)*100)
+		}
+	}
+
+	if causes := stats.FailureCauses(); len(causes) > 0 {
+		failed := stats.Attempts - stats.Reconnected
+		fmt.Printf("\n%-24s %10s %8s\n", "failure cause", "count", "share")
+		for _, cause := range causes {
+			count := stats.Failures[cause]
+			fmt.Printf("%-24s %10d %7.2f%%\n", cause, count, float64(count)/float64(failed)*100)
+		}
+	}
+	fmt.Println()
+}
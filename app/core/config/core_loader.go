@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"abc-runner/app/core/config/unified"
+	"abc-runner/app/core/config/yamlfile"
 
 	"gopkg.in/yaml.v2"
 )
@@ -31,8 +32,8 @@ func (u *UnifiedCoreConfigLoader) LoadFromFile(filePath string) (*CoreConfig, er
 		return u.GetDefaultConfig(), nil
 	}
 
-	// 读取文件内容
-	data, err := os.ReadFile(filePath)
+	// 读取文件内容，合并include:引用的片段文件并替换环境变量
+	data, err := yamlfile.Read(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read core config file: %w", err)
 	}
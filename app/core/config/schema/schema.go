@@ -0,0 +1,222 @@
+// Package schema 由Go配置结构体生成配置文件的JSON Schema，供编辑器补全与CI校验使用
+//
+// 字段名按gopkg.in/yaml.v3的规则确定：取yaml标签，未设置标签时为字段名的小写形式；
+// 与加载配置时的解析保持一致，Schema始终从结构体推导，无需单独维护。
+package schema
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Draft 生成的Schema遵循的JSON Schema版本
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// DurationPattern time.Duration在配置文件中的写法，如 30s、1m30s、500ms（yaml.v3不接受整数）
+const DurationPattern = `^[-+]?(0|(([0-9]+(\.[0-9]*)?|\.[0-9]+)(ns|us|µs|μs|ms|s|m|h))+)$`
+
+var (
+	durationType    = reflect.TypeOf(time.Duration(0))
+	timeType        = reflect.TypeOf(time.Time{})
+	unmarshalerType = reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()
+)
+
+// Schema JSON Schema节点
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"` // false或*Schema
+}
+
+// ForFile 生成配置文件的Schema
+// section为文件的顶层键（如redis），为空时结构体即整个文件；defaults为默认配置，其非零字段值作为default
+func ForFile(title, section string, defaults interface{}) *Schema {
+	root := Of(defaults)
+	if section != "" {
+		root = &Schema{
+			Type:                 "object",
+			Properties:           map[string]*Schema{section: root},
+			Required:             []string{section},
+			AdditionalProperties: false,
+		}
+	}
+	root.Schema = Draft
+	root.Title = title
+	return root
+}
+
+// Of 由Go值的类型生成Schema，值中的非零字段作为default
+func Of(v interface{}) *Schema {
+	value := reflect.ValueOf(v)
+	if !value.IsValid() {
+		return &Schema{}
+	}
+	return (&generator{visiting: make(map[reflect.Type]bool)}).schema(value.Type(), value)
+}
+
+// generator 遍历类型生成Schema，visiting防止递归类型无限展开
+type generator struct {
+	visiting map[reflect.Type]bool
+}
+
+// schema 生成类型t的Schema，value有效时取其值作为default
+func (g *generator) schema(t reflect.Type, value reflect.Value) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		if value.IsValid() {
+			value = value.Elem()
+		}
+	}
+
+	switch {
+	case t == durationType:
+		s := &Schema{Type: "string", Pattern: DurationPattern}
+		if value.IsValid() && value.Int() != 0 {
+			s.Default = time.Duration(value.Int()).String()
+		}
+		return s
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case reflect.PtrTo(t).Implements(unmarshalerType):
+		// 自定义解析的类型无法从结构推导，接受任意值
+		return &Schema{}
+	}
+
+	s := &Schema{}
+	switch t.Kind() {
+	case reflect.Bool:
+		s.Type = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s.Type = "integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		minimum := 0.0
+		s.Type, s.Minimum = "integer", &minimum
+	case reflect.Float32, reflect.Float64:
+		s.Type = "number"
+	case reflect.String:
+		s.Type = "string"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string"}
+		}
+		s.Type = "array"
+		s.Items = g.schema(t.Elem(), reflect.Value{})
+		if value.IsValid() && value.Len() > 0 && isScalar(t.Elem()) {
+			s.Default = value.Interface()
+		}
+		return s
+	case reflect.Map:
+		s.Type = "object"
+		s.AdditionalProperties = g.schema(t.Elem(), reflect.Value{})
+		return s
+	case reflect.Struct:
+		return g.object(t, value)
+	default:
+		// interface{}等任意值
+		return s
+	}
+
+	if value.IsValid() && !value.IsZero() {
+		s.Default = value.Interface()
+	}
+	return s
+}
+
+// object 生成结构体的Schema，不允许未知字段
+func (g *generator) object(t reflect.Type, value reflect.Value) *Schema {
+	if g.visiting[t] {
+		return &Schema{Type: "object"}
+	}
+	g.visiting[t] = true
+	defer delete(g.visiting, t)
+
+	s := &Schema{
+		Type:                 "object",
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: false,
+	}
+	g.addFields(s, t, value)
+	return s
+}
+
+// addFields 将结构体字段加入Schema，inline字段展开到当前对象
+func (g *generator) addFields(s *Schema, t reflect.Type, value reflect.Value) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		name, inline, skip := yamlField(field)
+		if skip {
+			continue
+		}
+		var fieldValue reflect.Value
+		if value.IsValid() {
+			fieldValue = value.Field(i)
+		}
+
+		if inline {
+			fieldType := field.Type
+			for fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+				if fieldValue.IsValid() {
+					fieldValue = fieldValue.Elem()
+				}
+			}
+			switch fieldType.Kind() {
+			case reflect.Struct:
+				g.addFields(s, fieldType, fieldValue)
+			case reflect.Map:
+				s.AdditionalProperties = g.schema(fieldType.Elem(), reflect.Value{})
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		s.Properties[name] = g.schema(field.Type, fieldValue)
+	}
+}
+
+// yamlField 按yaml.v3的规则解析字段名与inline标记
+func yamlField(field reflect.StructField) (name string, inline, skip bool) {
+	tag := field.Tag.Get("yaml")
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	for _, flag := range parts[1:] {
+		if flag == "inline" {
+			inline = true
+		}
+	}
+	name = parts[0]
+	if name == "" {
+		name = strings.ToLower(field.Name)
+	}
+	return name, inline, false
+}
+
+// isScalar 判断类型是否为标量，标量切片的默认值才写入Schema
+func isScalar(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return t != durationType
+	}
+	return false
+}
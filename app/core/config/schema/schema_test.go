@@ -0,0 +1,117 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testRequest struct {
+	Method  string            `yaml:"method"`
+	Headers map[string]string `yaml:"headers"`
+}
+
+type testStep struct {
+	testRequest `yaml:",inline"`
+	Extract     []string `yaml:"extract"`
+}
+
+type testNode struct {
+	Name     string      `yaml:"name"`
+	Children []*testNode `yaml:"children"`
+}
+
+type testConfig struct {
+	Address    string        `yaml:"address" json:"address"`
+	Port       int           `yaml:"port"`
+	Timeout    time.Duration `yaml:"timeout"`
+	Retries    uint          `yaml:"retries"`
+	Ratio      float64       `json:"ratio"` // yaml.v3不读取json标签，字段名为ratio
+	SampleRate float64       // 无标签时为小写字段名samplerate
+	Enabled    bool          `yaml:"enabled"`
+	Sizes      []int         `yaml:"sizes"`
+	Steps      []testStep    `yaml:"steps"`
+	Tree       testNode      `yaml:"tree"`
+	Extra      interface{}   `yaml:"extra"`
+	Internal   string        `yaml:"-"`
+	hidden     string
+}
+
+func TestOf(t *testing.T) {
+	s := Of(&testConfig{Address: "localhost", Timeout: 30 * time.Second, Sizes: []int{1, 10}, hidden: "x"})
+
+	if s.Type != "object" || s.AdditionalProperties != false {
+		t.Fatalf("expected a closed object, got %+v", s)
+	}
+	var names []string
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	expected := []string{"address", "port", "timeout", "retries", "ratio", "samplerate", "enabled", "sizes", "steps", "tree", "extra"}
+	if len(names) != len(expected) {
+		t.Errorf("properties = %v, expected %v", names, expected)
+	}
+	for _, name := range expected {
+		if s.Properties[name] == nil {
+			t.Errorf("missing property %q", name)
+		}
+	}
+
+	if p := s.Properties["address"]; p.Type != "string" || p.Default != "localhost" {
+		t.Errorf("address = %+v", p)
+	}
+	if p := s.Properties["port"]; p.Type != "integer" || p.Default != nil {
+		t.Errorf("expected no default for a zero port, got %+v", p)
+	}
+	if p := s.Properties["timeout"]; p.Type != "string" || p.Pattern != DurationPattern || p.Default != "30s" {
+		t.Errorf("timeout = %+v", p)
+	}
+	if p := s.Properties["retries"]; p.Minimum == nil || *p.Minimum != 0 {
+		t.Errorf("expected a minimum for an unsigned field, got %+v", p)
+	}
+	if p := s.Properties["sizes"]; p.Type != "array" || p.Items.Type != "integer" || !reflect.DeepEqual(p.Default, []int{1, 10}) {
+		t.Errorf("sizes = %+v", p)
+	}
+	if p := s.Properties["extra"]; p.Type != "" {
+		t.Errorf("expected any value for interface{}, got %+v", p)
+	}
+
+	step := s.Properties["steps"].Items
+	if step.Properties["method"] == nil || step.Properties["extract"] == nil || len(step.Properties) != 3 {
+		t.Errorf("expected inline fields to be flattened, got %+v", step.Properties)
+	}
+	if headers := step.Properties["headers"]; headers.Type != "object" || headers.AdditionalProperties.(*Schema).Type != "string" {
+		t.Errorf("headers = %+v", headers)
+	}
+	if child := s.Properties["tree"].Properties["children"].Items; child.Type != "object" || child.Properties != nil {
+		t.Errorf("expected recursion to stop at the repeated type, got %+v", child)
+	}
+}
+
+func TestForFile(t *testing.T) {
+	s := ForFile("test configuration", "test", testConfig{Port: 9090})
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if doc["$schema"] != Draft || doc["title"] != "test configuration" || doc["additionalProperties"] != false {
+		t.Errorf("unexpected root: %s", data)
+	}
+	if required, _ := doc["required"].([]interface{}); len(required) != 1 || required[0] != "test" {
+		t.Errorf("expected the section to be required, got %v", doc["required"])
+	}
+	port := doc["properties"].(map[string]interface{})["test"].(map[string]interface{})["properties"].(map[string]interface{})["port"]
+	if port.(map[string]interface{})["default"] != float64(9090) {
+		t.Errorf("expected the default port in the schema, got %v", port)
+	}
+
+	if whole := ForFile("whole file", "", testConfig{}); whole.Properties["port"] == nil || whole.Schema != Draft {
+		t.Errorf("expected the struct to describe the whole file, got %+v", whole)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"strings"
 
+	"abc-runner/app/core/config/yamlfile"
 	"abc-runner/app/core/interfaces"
 	"abc-runner/app/core/utils"
 )
@@ -55,9 +56,10 @@ func NewYAMLConfigSource(filePath string, parser ConfigParser) *YAMLConfigSource
 
 // Load 加载配置
 func (y *YAMLConfigSource) Load() (interfaces.Config, error) {
-	data, err := os.ReadFile(y.FilePath)
+	// 合并include:引用的片段文件并替换环境变量
+	data, err := yamlfile.Read(y.FilePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file %s: %w", y.FilePath, err)
+		return nil, err
 	}
 
 	return y.parser.Parse(data)
@@ -0,0 +1,58 @@
+package yamlfile
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TestRead_Fixtures 按testdata中的用例验证预处理结果；servers模块的预处理副本使用同一组用例，
+// 每个用例目录包含入口文件main.yaml、可选的环境变量文件env（KEY=VALUE），
+// 以及期望结果want.yaml或期望错误信息片段error
+func TestRead_Fixtures(t *testing.T) {
+	cases, err := os.ReadDir("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range cases {
+		dir := filepath.Join("testdata", entry.Name())
+		t.Run(entry.Name(), func(t *testing.T) {
+			if env, err := os.ReadFile(filepath.Join(dir, "env")); err == nil {
+				for _, line := range strings.Split(strings.TrimSpace(string(env)), "\n") {
+					key, value, _ := strings.Cut(line, "=")
+					t.Setenv(key, value)
+				}
+			}
+
+			data, err := Read(filepath.Join(dir, "main.yaml"))
+			if want, readErr := os.ReadFile(filepath.Join(dir, "error")); readErr == nil {
+				if err == nil || !strings.Contains(err.Error(), strings.TrimSpace(string(want))) {
+					t.Fatalf("expected error containing %q, got %v", strings.TrimSpace(string(want)), err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Read failed: %v", err)
+			}
+
+			want, err := os.ReadFile(filepath.Join(dir, "want.yaml"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got, expected interface{}
+			if err := yaml.Unmarshal(data, &got); err != nil {
+				t.Fatalf("failed to decode result: %v\n%s", err, data)
+			}
+			if err := yaml.Unmarshal(want, &expected); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("unexpected result:\n%s\nwant:\n%s", data, want)
+			}
+		})
+	}
+}
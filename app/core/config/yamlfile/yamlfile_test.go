@@ -0,0 +1,128 @@
+package yamlfile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("YAMLFILE_HOST", "db.internal")
+	t.Setenv("YAMLFILE_EMPTY", "")
+
+	cases := map[string]string{
+		"${YAMLFILE_HOST}":               "db.internal",
+		"${YAMLFILE_HOST:-localhost}:80": "db.internal:80",
+		"${YAMLFILE_EMPTY:-fallback}":    "fallback",
+		"${YAMLFILE_UNSET:-}":            "",
+		"${YAMLFILE_UNSET:-a b}":         "a b",
+		"/orders/${order_id}":            "/orders/${order_id}", // 未设置且无默认值时保留，供HTTP场景变量使用
+		"$${YAMLFILE_HOST}":              "${YAMLFILE_HOST}",
+		"plain":                          "plain",
+	}
+	for input, want := range cases {
+		if got := ExpandEnv(input); got != want {
+			t.Errorf("ExpandEnv(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestRead_IncludesAndEnv(t *testing.T) {
+	t.Setenv("YAMLFILE_PORT", "6380")
+	t.Setenv("YAMLFILE_PASSWORD", "p#ss: word")
+	dir := t.TempDir()
+
+	writeFile(t, dir, "common/base.yaml", `
+redis:
+  mode: standalone
+  standalone:
+    addr: localhost:6379
+    db: 0
+  benchmark:
+    total: 1000
+    parallels: 10
+`)
+	writeFile(t, dir, "common/secrets.yaml", `
+redis:
+  standalone:
+    password: "${YAMLFILE_PASSWORD}"
+`)
+	path := writeFile(t, dir, "redis.yaml", `
+include:
+  - common/base.yaml
+  - common/secrets.yaml
+redis:
+  standalone:
+    addr: ${YAMLFILE_HOST:-cache}:${YAMLFILE_PORT}
+  benchmark:
+    total: ${YAMLFILE_TOTAL:-5000}
+`)
+
+	data, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	var config struct {
+		Redis struct {
+			Mode       string `yaml:"mode"`
+			Standalone struct {
+				Addr     string `yaml:"addr"`
+				Password string `yaml:"password"`
+				DB       int    `yaml:"db"`
+			} `yaml:"standalone"`
+			Benchmark struct {
+				Total     int `yaml:"total"`
+				Parallels int `yaml:"parallels"`
+			} `yaml:"benchmark"`
+		} `yaml:"redis"`
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		t.Fatalf("failed to decode result: %v\n%s", err, data)
+	}
+	redis := config.Redis
+	if redis.Mode != "standalone" || redis.Standalone.Addr != "cache:6380" || redis.Standalone.Password != "p#ss: word" {
+		t.Errorf("unexpected merged config: %+v", redis)
+	}
+	// 片段中的键保留，当前文件的键覆盖片段，替换后的${...}按整数解析
+	if redis.Benchmark.Total != 5000 || redis.Benchmark.Parallels != 10 {
+		t.Errorf("unexpected benchmark section: %+v", redis.Benchmark)
+	}
+	if strings.Contains(string(data), IncludeKey+":") {
+		t.Errorf("expected include: to be removed:\n%s", data)
+	}
+}
+
+func TestRead_Errors(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.yaml", "include: b.yaml\nkey: a\n")
+	writeFile(t, dir, "b.yaml", "include: a.yaml\nkey: b\n")
+	if _, err := Read(filepath.Join(dir, "a.yaml")); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("expected include cycle error, got %v", err)
+	}
+
+	path := writeFile(t, dir, "missing.yaml", "include: nothere.yaml\n")
+	if _, err := Read(path); err == nil {
+		t.Errorf("expected error for missing include")
+	}
+
+	writeFile(t, dir, "list.yaml", "- a\n- b\n")
+	path = writeFile(t, dir, "bad.yaml", "include: list.yaml\n")
+	if _, err := Read(path); err == nil {
+		t.Errorf("expected error for non-mapping include")
+	}
+}
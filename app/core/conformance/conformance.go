@@ -0,0 +1,467 @@
+// Package conformance 协议适配器一致性检查
+// 按interfaces.ProtocolAdapter的接口契约检查任意适配器实现：连接、执行、健康检查、指标与关闭，
+// 包括取消的上下文、并发调用和关闭前后的调用，用于在使用自研适配器之前验证其行为
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+)
+
+// 默认检查参数
+const (
+	DefaultWorkers    = 8
+	DefaultOperations = 50
+	DefaultTimeout    = 10 * time.Second
+)
+
+// Status 检查结果状态
+type Status string
+
+const (
+	StatusPass Status = "pass" // 符合契约
+	StatusWarn Status = "warn" // 允许但不推荐的行为，不视为失败
+	StatusFail Status = "fail" // 违反契约
+	StatusSkip Status = "skip" // 因前置检查失败而未执行
+)
+
+// 检查项名称
+const (
+	CheckProtocolName         = "protocol_name"
+	CheckExecuteBeforeConnect = "execute_before_connect"
+	CheckHealthBeforeConnect  = "health_before_connect"
+	CheckConnectCancelled     = "connect_cancelled"
+	CheckConnect              = "connect"
+	CheckHealthCheck          = "health_check"
+	CheckMetricsCollector     = "metrics_collector"
+	CheckExecute              = "execute"
+	CheckExecuteConcurrent    = "execute_concurrent"
+	CheckExecuteCancelled     = "execute_cancelled"
+	CheckProtocolMetrics      = "protocol_metrics"
+	CheckClose                = "close"
+	CheckCloseTwice           = "close_twice"
+	CheckExecuteAfterClose    = "execute_after_close"
+	CheckHealthAfterClose     = "health_after_close"
+)
+
+// Target 待检查的适配器
+type Target struct {
+	New        func() interfaces.ProtocolAdapter // 每次调用返回一个未连接的新适配器
+	Config     interfaces.Config                 // 传给Connect的配置
+	Operation  func(i int) interfaces.Operation  // 第i个操作，应能在已连接的目标上成功执行；会被并发调用
+	Workers    int                               // 并发检查的协程数，默认8
+	Operations int                               // 每个协程执行的操作数，默认50
+	Timeout    time.Duration                     // 单次调用的最长等待时间，默认10s
+}
+
+// CheckResult 单项检查结果
+type CheckResult struct {
+	Name     string        `json:"name"`
+	Status   Status        `json:"status"`
+	Detail   string        `json:"detail,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Report 一致性检查报告
+type Report struct {
+	Protocol string        `json:"protocol"`
+	Checks   []CheckResult `json:"checks"`
+}
+
+// Passed 没有失败的检查项
+func (r *Report) Passed() bool {
+	return r.Count(StatusFail) == 0
+}
+
+// Count 指定状态的检查项数
+func (r *Report) Count(status Status) int {
+	count := 0
+	for _, check := range r.Checks {
+		if check.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Check 按名称查找检查结果
+func (r *Report) Check(name string) (CheckResult, bool) {
+	for _, check := range r.Checks {
+		if check.Name == name {
+			return check, true
+		}
+	}
+	return CheckResult{}, false
+}
+
+var (
+	errTimeout  = errors.New("call did not return") // 调用未在Target.Timeout内返回
+	errContract = errors.New("contract violation")  // 返回值不符合接口契约
+)
+
+// runner 执行一次检查
+type runner struct {
+	target Target
+	report *Report
+}
+
+// Run 按接口契约检查适配器，只在Target无效时返回错误；违反契约记录在报告中
+// 超时的调用无法中止，其协程会一直保留到调用返回；适配器内部协程的panic无法捕获
+func Run(ctx context.Context, target Target) (*Report, error) {
+	if target.New == nil || target.Config == nil || target.Operation == nil {
+		return nil, fmt.Errorf("conformance target requires New, Config and Operation")
+	}
+	if target.Workers <= 0 {
+		target.Workers = DefaultWorkers
+	}
+	if target.Operations <= 0 {
+		target.Operations = DefaultOperations
+	}
+	if target.Timeout <= 0 {
+		target.Timeout = DefaultTimeout
+	}
+
+	r := &runner{target: target, report: &Report{}}
+	r.beforeConnect(ctx)
+	r.connectCancelled(ctx)
+
+	adapter := target.New()
+	if !r.connect(ctx, adapter) {
+		for _, name := range []string{CheckHealthCheck, CheckMetricsCollector, CheckExecute, CheckExecuteConcurrent,
+			CheckExecuteCancelled, CheckProtocolMetrics, CheckClose, CheckCloseTwice, CheckExecuteAfterClose, CheckHealthAfterClose} {
+			r.record(name, StatusSkip, "connect failed", 0)
+		}
+		r.call(func() error { return adapter.Close() })
+		return r.report, nil
+	}
+	r.connected(ctx, adapter)
+	r.closed(ctx, adapter)
+	return r.report, nil
+}
+
+// record 记录检查结果
+func (r *runner) record(name string, status Status, detail string, duration time.Duration) {
+	r.report.Checks = append(r.report.Checks, CheckResult{Name: name, Status: status, Detail: detail, Duration: duration})
+}
+
+// check 执行一项检查，fn返回状态与说明；fn中的panic与超时记为失败
+func (r *runner) check(name string, fn func() (Status, string)) Status {
+	start := time.Now()
+	// 超时后fn仍可能写入结果，只在调用返回时读取
+	var returned CheckResult
+	status, detail := StatusFail, ""
+	if err := r.call(func() error {
+		returned.Status, returned.Detail = fn()
+		return nil
+	}); err != nil {
+		detail = err.Error()
+	} else {
+		status, detail = returned.Status, returned.Detail
+	}
+	r.record(name, status, detail, time.Since(start))
+	return status
+}
+
+// call 在Target.Timeout内执行fn，把panic转换为错误
+func (r *runner) call(fn func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- protect(fn)
+	}()
+	timer := time.NewTimer(r.target.Timeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		return fmt.Errorf("%w within %v", errTimeout, r.target.Timeout)
+	}
+}
+
+// protect 执行fn并把panic转换为错误
+func protect(fn func() error) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v\n%s", recovered, firstFrames(debug.Stack()))
+		}
+	}()
+	return fn()
+}
+
+// firstFrames 截取panic堆栈的开头部分
+func firstFrames(stack []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(stack)), "\n")
+	if len(lines) > 12 {
+		lines = lines[:12]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// execute 执行一个操作并检查返回值契约：返回nil错误时结果不能为nil，耗时不能为负
+func execute(ctx context.Context, adapter interfaces.ProtocolAdapter, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	result, err := adapter.Execute(ctx, operation)
+	if err == nil && result == nil {
+		return nil, fmt.Errorf("%w: Execute returned neither a result nor an error", errContract)
+	}
+	if result != nil && result.Duration < 0 {
+		return nil, fmt.Errorf("%w: Execute returned a negative duration %v", errContract, result.Duration)
+	}
+	return result, err
+}
+
+// rejected 操作是否被拒绝（返回错误或不成功的结果）
+func rejected(result *interfaces.OperationResult, err error) bool {
+	return err != nil || result == nil || !result.Success
+}
+
+// describeError 被拒绝的操作的原因
+func describeError(result *interfaces.OperationResult, err error) error {
+	switch {
+	case err != nil:
+		return err
+	case result != nil && result.Error != nil:
+		return result.Error
+	}
+	return errors.New("operation reported failure without an error")
+}
+
+// describeRejection 被拒绝的操作的原因说明
+func describeRejection(result *interfaces.OperationResult, err error) string {
+	return describeError(result, err).Error()
+}
+
+// operationsStatus 按成功的操作数评估执行检查：全部失败为失败，部分失败为警告
+func operationsStatus(succeeded, total int, firstErr error) (Status, string) {
+	switch {
+	case succeeded == 0:
+		return StatusFail, fmt.Sprintf("no operation succeeded: %v", firstErr)
+	case succeeded < total:
+		return StatusWarn, fmt.Sprintf("%d/%d operations succeeded, first failure: %v", succeeded, total, firstErr)
+	}
+	return StatusPass, fmt.Sprintf("%d operations", total)
+}
+
+// beforeConnect 未连接的适配器：协议名称、执行与健康检查都不能panic，且不能报告成功
+func (r *runner) beforeConnect(ctx context.Context) {
+	adapter := r.target.New()
+	defer r.call(func() error { return adapter.Close() })
+
+	r.check(CheckProtocolName, func() (Status, string) {
+		name := adapter.GetProtocolName()
+		if name == "" {
+			return StatusFail, "GetProtocolName returned an empty name"
+		}
+		r.report.Protocol = name
+		return StatusPass, name
+	})
+
+	r.check(CheckExecuteBeforeConnect, func() (Status, string) {
+		result, err := adapter.Execute(ctx, r.target.Operation(0))
+		if !rejected(result, err) {
+			return StatusFail, "Execute succeeded on an adapter that is not connected"
+		}
+		return StatusPass, describeRejection(result, err)
+	})
+
+	r.check(CheckHealthBeforeConnect, func() (Status, string) {
+		if err := adapter.HealthCheck(ctx); err != nil {
+			return StatusPass, err.Error()
+		}
+		return StatusFail, "HealthCheck returned nil on an adapter that is not connected"
+	})
+}
+
+// connectCancelled 以已取消的上下文连接必须及时返回
+func (r *runner) connectCancelled(ctx context.Context) {
+	adapter := r.target.New()
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	r.check(CheckConnectCancelled, func() (Status, string) {
+		if err := adapter.Connect(cancelled, r.target.Config); err != nil {
+			return StatusPass, err.Error()
+		}
+		return StatusWarn, "Connect ignored the cancelled context and succeeded"
+	})
+	r.call(func() error { return adapter.Close() })
+}
+
+// connect 连接被测目标，失败时后续检查跳过
+func (r *runner) connect(ctx context.Context, adapter interfaces.ProtocolAdapter) bool {
+	return r.check(CheckConnect, func() (Status, string) {
+		if err := adapter.Connect(ctx, r.target.Config); err != nil {
+			return StatusFail, err.Error()
+		}
+		return StatusPass, ""
+	}) == StatusPass
+}
+
+// connected 已连接的适配器：健康检查、顺序与并发执行、取消、指标
+func (r *runner) connected(ctx context.Context, adapter interfaces.ProtocolAdapter) {
+	r.check(CheckHealthCheck, func() (Status, string) {
+		if err := adapter.HealthCheck(ctx); err != nil {
+			return StatusFail, err.Error()
+		}
+		return StatusPass, ""
+	})
+
+	r.check(CheckMetricsCollector, func() (Status, string) {
+		if adapter.GetMetricsCollector() == nil {
+			return StatusFail, "GetMetricsCollector returned nil"
+		}
+		return StatusPass, ""
+	})
+
+	r.check(CheckExecute, func() (Status, string) {
+		succeeded := 0
+		var firstErr error
+		for i := 0; i < r.target.Operations; i++ {
+			result, err := execute(ctx, adapter, r.target.Operation(i))
+			if errors.Is(err, errContract) {
+				return StatusFail, err.Error()
+			}
+			if !rejected(result, err) {
+				succeeded++
+			} else if firstErr == nil {
+				firstErr = describeError(result, err)
+			}
+		}
+		return operationsStatus(succeeded, r.target.Operations, firstErr)
+	})
+
+	r.check(CheckExecuteConcurrent, func() (Status, string) {
+		return r.concurrent(ctx, adapter)
+	})
+
+	r.check(CheckExecuteCancelled, func() (Status, string) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		result, err := execute(cancelled, adapter, r.target.Operation(0))
+		if errors.Is(err, errContract) {
+			return StatusFail, err.Error()
+		}
+		if !rejected(result, err) {
+			return StatusWarn, "Execute ignored the cancelled context and succeeded"
+		}
+		return StatusPass, describeRejection(result, err)
+	})
+
+	r.check(CheckProtocolMetrics, func() (Status, string) {
+		metrics := adapter.GetProtocolMetrics()
+		if metrics == nil {
+			return StatusFail, "GetProtocolMetrics returned nil"
+		}
+		if _, err := json.Marshal(metrics); err != nil {
+			return StatusFail, fmt.Sprintf("protocol metrics are not JSON-serializable: %v", err)
+		}
+		return StatusPass, fmt.Sprintf("%d metrics", len(metrics))
+	})
+}
+
+// concurrent 多个协程并发执行操作，同时读取指标并做健康检查
+func (r *runner) concurrent(ctx context.Context, adapter interfaces.ProtocolAdapter) (Status, string) {
+	var succeeded int64
+	var mu sync.Mutex
+	var firstErr, contractErr error
+	var wg sync.WaitGroup
+
+	stop := make(chan struct{})
+	observerDone := make(chan error, 1)
+	go func() {
+		observerDone <- protect(func() error {
+			for {
+				select {
+				case <-stop:
+					return nil
+				default:
+				}
+				adapter.GetProtocolMetrics()
+				adapter.HealthCheck(ctx)
+				time.Sleep(time.Millisecond)
+			}
+		})
+	}()
+
+	for worker := 0; worker < r.target.Workers; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			err := protect(func() error {
+				for i := 0; i < r.target.Operations; i++ {
+					result, err := execute(ctx, adapter, r.target.Operation(worker*r.target.Operations+i))
+					if errors.Is(err, errContract) {
+						return err
+					}
+					if !rejected(result, err) {
+						atomic.AddInt64(&succeeded, 1)
+					} else {
+						mu.Lock()
+						if firstErr == nil {
+							firstErr = describeError(result, err)
+						}
+						mu.Unlock()
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				mu.Lock()
+				if contractErr == nil {
+					contractErr = err
+				}
+				mu.Unlock()
+			}
+		}(worker)
+	}
+	wg.Wait()
+	close(stop)
+
+	if err := <-observerDone; err != nil {
+		return StatusFail, fmt.Sprintf("concurrent GetProtocolMetrics/HealthCheck: %v", err)
+	}
+	if contractErr != nil {
+		return StatusFail, contractErr.Error()
+	}
+	return operationsStatus(int(succeeded), r.target.Workers*r.target.Operations, firstErr)
+}
+
+// closed 关闭适配器：Close返回nil，重复关闭无害，关闭后的调用不能panic或报告成功
+func (r *runner) closed(ctx context.Context, adapter interfaces.ProtocolAdapter) {
+	r.check(CheckClose, func() (Status, string) {
+		if err := adapter.Close(); err != nil {
+			return StatusFail, err.Error()
+		}
+		return StatusPass, ""
+	})
+
+	r.check(CheckCloseTwice, func() (Status, string) {
+		if err := adapter.Close(); err != nil {
+			return StatusWarn, fmt.Sprintf("second Close returned %v", err)
+		}
+		return StatusPass, ""
+	})
+
+	r.check(CheckExecuteAfterClose, func() (Status, string) {
+		result, err := adapter.Execute(ctx, r.target.Operation(0))
+		if !rejected(result, err) {
+			return StatusFail, "Execute succeeded after Close"
+		}
+		return StatusPass, describeRejection(result, err)
+	})
+
+	r.check(CheckHealthAfterClose, func() (Status, string) {
+		if err := adapter.HealthCheck(ctx); err != nil {
+			return StatusPass, err.Error()
+		}
+		return StatusFail, "HealthCheck returned nil after Close"
+	})
+}
@@ -0,0 +1,189 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+)
+
+// fakeConfig 测试适配器不读取配置
+type fakeConfig struct {
+	interfaces.Config
+}
+
+// fakeAdapter 可配置违反契约方式的测试适配器
+type fakeAdapter struct {
+	mu         sync.Mutex
+	connected  bool
+	executed   int64
+	collector  interfaces.DefaultMetricsCollector
+	connectErr error
+
+	// 违反契约的行为
+	panicBeforeConnect bool // 未连接时Execute访问nil连接
+	succeedAfterClose  bool // 关闭后Execute仍报告成功
+	hangAfterClose     bool // 关闭后HealthCheck不返回
+	closeTwiceErr      bool // 重复关闭返回错误
+}
+
+func (a *fakeAdapter) Connect(ctx context.Context, config interfaces.Config) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if a.connectErr != nil {
+		return a.connectErr
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.connected = true
+	return nil
+}
+
+func (a *fakeAdapter) Execute(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	a.mu.Lock()
+	connected := a.connected
+	a.executed++
+	a.mu.Unlock()
+
+	if !connected && a.panicBeforeConnect {
+		var conn *fakeAdapter
+		conn.executed++
+	}
+	if !connected && !a.succeedAfterClose {
+		return nil, errors.New("not connected")
+	}
+	if err := ctx.Err(); err != nil {
+		return &interfaces.OperationResult{Success: false, Error: err}, err
+	}
+	return &interfaces.OperationResult{Success: true, Duration: time.Microsecond, Value: operation.Key}, nil
+}
+
+func (a *fakeAdapter) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.connected && a.closeTwiceErr {
+		return errors.New("already closed")
+	}
+	a.connected = false
+	return nil
+}
+
+func (a *fakeAdapter) HealthCheck(ctx context.Context) error {
+	a.mu.Lock()
+	connected := a.connected
+	a.mu.Unlock()
+	if connected {
+		return nil
+	}
+	if a.hangAfterClose {
+		select {}
+	}
+	return errors.New("not connected")
+}
+
+func (a *fakeAdapter) GetProtocolName() string {
+	return "fake"
+}
+
+func (a *fakeAdapter) GetProtocolMetrics() map[string]interface{} {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return map[string]interface{}{"executed": a.executed}
+}
+
+func (a *fakeAdapter) GetMetricsCollector() interfaces.DefaultMetricsCollector {
+	return a.collector
+}
+
+func fakeTarget(configure func(*fakeAdapter)) Target {
+	collector := metrics.NewBaseCollector(metrics.DefaultMetricsConfig(), map[string]interface{}{})
+	return Target{
+		New: func() interfaces.ProtocolAdapter {
+			adapter := &fakeAdapter{collector: collector}
+			if configure != nil {
+				configure(adapter)
+			}
+			return adapter
+		},
+		Config: fakeConfig{},
+		Operation: func(i int) interfaces.Operation {
+			return interfaces.Operation{Type: "get", Key: fmt.Sprintf("key:%d", i)}
+		},
+		Workers:    4,
+		Operations: 20,
+		Timeout:    200 * time.Millisecond,
+	}
+}
+
+func TestRun_ConformingAdapter(t *testing.T) {
+	report := Verify(t, fakeTarget(nil))
+
+	if report.Protocol != "fake" || len(report.Checks) != 15 || !report.Passed() || report.Count(StatusPass) != 15 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	if check, _ := report.Check(CheckExecuteConcurrent); check.Detail != "80 operations" {
+		t.Errorf("concurrent check detail = %q, want 80 operations", check.Detail)
+	}
+}
+
+func TestRun_ContractViolations(t *testing.T) {
+	report, err := Run(context.Background(), fakeTarget(func(a *fakeAdapter) {
+		a.panicBeforeConnect = true
+		a.succeedAfterClose = true
+		a.hangAfterClose = true
+		a.closeTwiceErr = true
+	}))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if report.Passed() {
+		t.Fatal("expected the report to fail")
+	}
+
+	want := map[string]Status{
+		CheckExecuteBeforeConnect: StatusFail,
+		CheckHealthBeforeConnect:  StatusFail,
+		CheckConnect:              StatusPass,
+		CheckExecute:              StatusPass,
+		CheckCloseTwice:           StatusWarn,
+		CheckExecuteAfterClose:    StatusFail,
+		CheckHealthAfterClose:     StatusFail,
+	}
+	for name, status := range want {
+		if check, ok := report.Check(name); !ok || check.Status != status {
+			t.Errorf("%s = %+v, want %s", name, check, status)
+		}
+	}
+	if check, _ := report.Check(CheckExecuteBeforeConnect); !strings.Contains(check.Detail, "panic: runtime error") {
+		t.Errorf("expected the panic to be reported, got %q", check.Detail)
+	}
+	if check, _ := report.Check(CheckHealthBeforeConnect); !strings.Contains(check.Detail, "did not return within 200ms") {
+		t.Errorf("expected a timeout, got %q", check.Detail)
+	}
+}
+
+func TestRun_ConnectFailure(t *testing.T) {
+	report, err := Run(context.Background(), fakeTarget(func(a *fakeAdapter) {
+		a.connectErr = errors.New("connection refused")
+	}))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if check, _ := report.Check(CheckConnect); check.Status != StatusFail || check.Detail != "connection refused" {
+		t.Errorf("unexpected connect check: %+v", check)
+	}
+	if report.Count(StatusSkip) != 10 {
+		t.Errorf("expected the checks after connect to be skipped, got %d skipped", report.Count(StatusSkip))
+	}
+
+	if _, err := Run(context.Background(), Target{}); err == nil {
+		t.Error("expected an error for an empty target")
+	}
+}
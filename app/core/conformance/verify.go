@@ -0,0 +1,24 @@
+package conformance
+
+import (
+	"context"
+	"testing"
+)
+
+// Verify 在Go测试中检查适配器：失败的检查项报告为测试错误，警告写入测试日志
+func Verify(t testing.TB, target Target) *Report {
+	t.Helper()
+	report, err := Run(context.Background(), target)
+	if err != nil {
+		t.Fatalf("conformance: %v", err)
+	}
+	for _, check := range report.Checks {
+		switch check.Status {
+		case StatusFail:
+			t.Errorf("conformance check %s failed: %s", check.Name, check.Detail)
+		case StatusWarn:
+			t.Logf("conformance check %s warning: %s", check.Name, check.Detail)
+		}
+	}
+	return report
+}
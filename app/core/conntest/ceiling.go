@@ -0,0 +1,605 @@
+package conntest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+)
+
+// earlyDropWindow 建立后在该时间内被断开的连接视为目标拒绝了该连接
+const earlyDropWindow = time.Second
+
+// 爬升停止原因
+const (
+	StopFailures       = "failures"        // 连续失败达到阈值，即到达上限
+	StopMaxConnections = "max_connections" // 达到配置的最大连接数
+	StopDuration       = "duration"        // 达到最长爬升时间
+	StopCanceled       = "canceled"        // 被调用方取消
+)
+
+// 目标到达上限时的表现
+const (
+	BehaviorNone        = "none"         // 未触及上限
+	BehaviorRefuse      = "refuse"       // 拒绝新连接（RST/ECONNREFUSED）
+	BehaviorTimeout     = "timeout"      // 不再接受新连接，建连超时（通常是accept队列已满）
+	BehaviorReset       = "reset"        // 接受后立即重置或关闭新连接
+	BehaviorEvict       = "evict"        // 接受新连接，但断开已有连接
+	BehaviorClientLimit = "client_limit" // 客户端先耗尽文件描述符或临时端口
+)
+
+// behaviorDescriptions 上限表现的说明
+var behaviorDescriptions = map[string]string{
+	BehaviorNone:        "no ceiling reached before the ramp stopped",
+	BehaviorRefuse:      "target refuses new connections at the ceiling",
+	BehaviorTimeout:     "target stops accepting; new connections time out (accept backlog full)",
+	BehaviorReset:       "target accepts, then resets or closes new connections",
+	BehaviorEvict:       "target keeps accepting but drops existing connections",
+	BehaviorClientLimit: "client ran out of file descriptors or ephemeral ports first; raise ulimit -n or add source addresses",
+}
+
+// DescribeBehavior 获取上限表现的说明
+func DescribeBehavior(behavior string) string {
+	if description, ok := behaviorDescriptions[behavior]; ok {
+		return description
+	}
+	return behavior
+}
+
+// CeilingConfig 最大并发连接数探测配置
+type CeilingConfig struct {
+	Rate           int           // 每秒新建连接数
+	Concurrency    int           // 同时进行握手的最大连接数
+	MaxConnections int           // 连接数上限，达到后停止爬升
+	MaxFailures    int           // 连续失败次数达到该值时判定到达上限
+	MaxDuration    time.Duration // 最长爬升时间
+	Observe        time.Duration // 停止爬升后保持全部连接的观察时间
+	Drip           time.Duration // 慢速滴灌间隔，0表示保持空闲
+	DripPayload    []byte        // 每次滴灌发送的数据
+}
+
+// NewDefaultCeilingConfig 创建默认最大并发连接数探测配置
+func NewDefaultCeilingConfig() *CeilingConfig {
+	return &CeilingConfig{
+		Rate:           500,
+		Concurrency:    50,
+		MaxConnections: 100000,
+		MaxFailures:    20,
+		MaxDuration:    5 * time.Minute,
+		Observe:        5 * time.Second,
+		// 多数HTTP服务端会忽略请求行之前的空行
+		DripPayload: []byte("\r\n"),
+	}
+}
+
+// Validate 验证配置
+func (c *CeilingConfig) Validate() error {
+	if c.Rate <= 0 {
+		return fmt.Errorf("rate must be positive")
+	}
+	if c.Concurrency <= 0 {
+		return fmt.Errorf("concurrency must be positive")
+	}
+	if c.MaxConnections <= 0 {
+		return fmt.Errorf("max connections must be positive")
+	}
+	if c.MaxFailures <= 0 {
+		return fmt.Errorf("max failures must be positive")
+	}
+	if c.MaxDuration <= 0 {
+		return fmt.Errorf("max duration must be positive")
+	}
+	if c.Observe < 0 {
+		return fmt.Errorf("observe cannot be negative")
+	}
+	if c.Drip < 0 {
+		return fmt.Errorf("drip interval cannot be negative")
+	}
+	if c.Drip > 0 && len(c.DripPayload) == 0 {
+		return fmt.Errorf("drip payload cannot be empty")
+	}
+	return nil
+}
+
+// CeilingSample 爬升过程中每秒的连接状态
+type CeilingSample struct {
+	Elapsed     time.Duration `json:"elapsed"`
+	Open        int64         `json:"open"`
+	Established int64         `json:"established"` // 本秒新建成功数
+	Failed      int64         `json:"failed"`      // 本秒新建失败数
+	Dropped     int64         `json:"dropped"`     // 本秒被目标断开的已有连接数
+}
+
+// CeilingResult 最大并发连接数探测结果
+type CeilingResult struct {
+	Transport         string                 `json:"transport"`
+	Target            string                 `json:"target"`
+	Ceiling           int64                  `json:"ceiling"` // 同时保持的连接数峰值
+	StopReason        string                 `json:"stop_reason"`
+	Behavior          string                 `json:"behavior"`
+	RampDuration      time.Duration          `json:"ramp_duration"`
+	Attempts          int64                  `json:"attempts"`
+	Established       int64                  `json:"established"`
+	Failed            int64                  `json:"failed"`
+	FirstFailureOpen  int64                  `json:"first_failure_open"` // 首次失败时的连接数，-1表示无失败
+	FirstFailureCause string                 `json:"first_failure_cause"`
+	Dropped           int64                  `json:"dropped"`         // 被目标断开的连接总数
+	EarlyDropped      int64                  `json:"early_dropped"`   // 建立后1秒内即被断开的连接数
+	ObserveDropped    int64                  `json:"observe_dropped"` // 观察期内被断开的连接数
+	OpenAfterObserve  int64                  `json:"open_after_observe"`
+	Connect           metrics.LatencyMetrics `json:"connect"`
+	Total             metrics.LatencyMetrics `json:"total"`
+	DropAfter         metrics.LatencyMetrics `json:"drop_after"` // 连接从建立到被断开的存活时间
+	Failures          map[string]int64       `json:"failures"`
+	DropCauses        map[string]int64       `json:"drop_causes"`
+	Samples           []CeilingSample        `json:"samples"`
+}
+
+// ToMap 转换为报告使用的map
+func (r *CeilingResult) ToMap() map[string]interface{} {
+	toMap := func(counts map[string]int64) map[string]interface{} {
+		result := make(map[string]interface{}, len(counts))
+		for key, count := range counts {
+			result[key] = count
+		}
+		return result
+	}
+
+	result := map[string]interface{}{
+		"transport":           r.Transport,
+		"target":              r.Target,
+		"ceiling":             r.Ceiling,
+		"stop_reason":         r.StopReason,
+		"behavior":            r.Behavior,
+		"ramp_duration":       r.RampDuration.String(),
+		"attempts":            r.Attempts,
+		"established":         r.Established,
+		"failed":              r.Failed,
+		"first_failure_open":  r.FirstFailureOpen,
+		"first_failure_cause": r.FirstFailureCause,
+		"dropped":             r.Dropped,
+		"early_dropped":       r.EarlyDropped,
+		"observe_dropped":     r.ObserveDropped,
+		"open_after_observe":  r.OpenAfterObserve,
+		"connect":             latencyToMap(r.Connect),
+		"total":               latencyToMap(r.Total),
+		"failures":            toMap(r.Failures),
+		"drop_causes":         toMap(r.DropCauses),
+	}
+	if r.Dropped > 0 {
+		result["drop_after"] = latencyToMap(r.DropAfter)
+	}
+	return result
+}
+
+// heldConn 探测过程中保持的连接
+type heldConn struct {
+	closer  io.Closer
+	opened  time.Time
+	closing atomic.Bool
+	dropped atomic.Bool
+	dripped atomic.Bool // 已收到过滴灌数据，此后的断开不视为拒绝新连接
+}
+
+// CeilingRunner 最大并发连接数探测执行器
+// 持续新建并保持连接，直到目标开始拒绝、超时或断开连接，记录此时的连接数与失败表现
+type CeilingRunner struct {
+	dialer    Dialer
+	config    *CeilingConfig
+	collector *metrics.BaseCollector[map[string]interface{}]
+
+	attempts    atomic.Int64
+	established atomic.Int64
+	failed      atomic.Int64
+	dropped     atomic.Int64
+	early       atomic.Int64
+	open        atomic.Int64
+	peakOpen    atomic.Int64
+	consecutive atomic.Int64
+	observing   atomic.Bool
+
+	observeDropped atomic.Int64
+
+	connect   *metrics.LatencyTracker
+	total     *metrics.LatencyTracker
+	dropAfter *metrics.LatencyTracker
+
+	mutex             sync.Mutex
+	conns             map[*heldConn]struct{}
+	failures          map[string]int64
+	dropCauses        map[string]int64
+	firstFailureOpen  int64
+	firstFailureCause string
+
+	monitors sync.WaitGroup
+}
+
+// NewCeilingRunner 创建最大并发连接数探测执行器，collector可为nil
+func NewCeilingRunner(dialer Dialer, config *CeilingConfig, collector *metrics.BaseCollector[map[string]interface{}]) *CeilingRunner {
+	latencyConfig := metrics.LatencyConfig{
+		SamplingRate: 1.0,
+	}
+	return &CeilingRunner{
+		dialer:           dialer,
+		config:           config,
+		collector:        collector,
+		connect:          metrics.NewLatencyTracker(latencyConfig),
+		total:            metrics.NewLatencyTracker(latencyConfig),
+		dropAfter:        metrics.NewLatencyTracker(latencyConfig),
+		conns:            make(map[*heldConn]struct{}),
+		failures:         make(map[string]int64),
+		dropCauses:       make(map[string]int64),
+		firstFailureOpen: -1,
+	}
+}
+
+// Run 执行探测：爬升、观察，最后关闭全部连接
+// onSample每秒回调一次，onObserve在进入观察阶段时以当前连接数回调
+func (r *CeilingRunner) Run(ctx context.Context, onSample func(CeilingSample), onObserve func(open int64)) (*CeilingResult, error) {
+	if err := r.config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid ceiling config: %w", err)
+	}
+	defer r.closeAll()
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	start := time.Now()
+	samples := make(chan []CeilingSample, 1)
+	go func() {
+		samples <- r.sample(runCtx, start, onSample)
+	}()
+
+	if r.config.Drip > 0 {
+		go r.drip(runCtx)
+	}
+
+	reason := r.ramp(runCtx, start)
+	rampDuration := time.Since(start)
+
+	if reason != StopCanceled && r.config.Observe > 0 {
+		r.observing.Store(true)
+		if onObserve != nil {
+			onObserve(r.open.Load())
+		}
+		if err := sleepContext(runCtx, r.config.Observe); err != nil {
+			reason = StopCanceled
+		}
+	}
+	cancel()
+
+	result := r.result(reason, rampDuration, <-samples)
+	if reason == StopCanceled {
+		return result, ctx.Err()
+	}
+	return result, nil
+}
+
+// ramp 按速率新建连接，直到满足停止条件
+func (r *CeilingRunner) ramp(ctx context.Context, start time.Time) string {
+	slots := make(chan struct{}, r.config.Concurrency)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	// 先于等待取消在途握手，停止后不再计入新的结果
+	rampCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ticker := time.NewTicker(pacingInterval)
+	defer ticker.Stop()
+
+	var issued int64
+	for {
+		select {
+		case <-ctx.Done():
+			return StopCanceled
+		case now := <-ticker.C:
+			switch {
+			case r.consecutive.Load() >= int64(r.config.MaxFailures):
+				return StopFailures
+			case r.open.Load() >= int64(r.config.MaxConnections):
+				return StopMaxConnections
+			case now.Sub(start) >= r.config.MaxDuration:
+				return StopDuration
+			}
+
+			due := int64(now.Sub(start).Seconds() * float64(r.config.Rate))
+			for issued < due {
+				// 在途握手已满时放弃本轮剩余的发起，实际速率受限于握手并发而不会积压
+				select {
+				case slots <- struct{}{}:
+				default:
+					issued = due
+					continue
+				}
+				issued++
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer func() { <-slots }()
+					r.attempt(rampCtx)
+				}()
+			}
+		}
+	}
+}
+
+// attempt 新建一个连接并加入保持集合
+func (r *CeilingRunner) attempt(ctx context.Context) {
+	conn, timing, err := r.dialer.Dial(ctx)
+	if err != nil && ctx.Err() != nil {
+		return
+	}
+
+	r.attempts.Add(1)
+	if err != nil {
+		r.failed.Add(1)
+		r.consecutive.Add(1)
+		cause := ClassifyError(err)
+		r.mutex.Lock()
+		r.failures[cause]++
+		if r.firstFailureOpen < 0 {
+			r.firstFailureOpen = r.open.Load()
+			r.firstFailureCause = cause
+		}
+		r.mutex.Unlock()
+		r.record(false, timing.Total, err, cause)
+		return
+	}
+
+	r.established.Add(1)
+	r.connect.Record(timing.Connect)
+	r.total.Record(timing.Total)
+	r.record(true, timing.Total, nil, "")
+
+	held := &heldConn{closer: conn, opened: time.Now()}
+	r.mutex.Lock()
+	r.conns[held] = struct{}{}
+	r.mutex.Unlock()
+
+	open := r.open.Add(1)
+	for {
+		peak := r.peakOpen.Load()
+		if open <= peak || r.peakOpen.CompareAndSwap(peak, open) {
+			break
+		}
+	}
+
+	// 连接存活超过earlyDropWindow才视为成功，接受后立即关闭新连接的目标同样能被识别为到达上限
+	time.AfterFunc(earlyDropWindow, func() {
+		if !held.dropped.Load() {
+			r.consecutive.Store(0)
+		}
+	})
+
+	r.monitors.Add(1)
+	go r.monitor(held)
+}
+
+// monitor 阻塞读取连接，连接被目标断开时记录断开原因与存活时间
+func (r *CeilingRunner) monitor(held *heldConn) {
+	defer r.monitors.Done()
+
+	var err error
+	switch conn := held.closer.(type) {
+	case *websocket.Conn:
+		for err == nil {
+			_, _, err = conn.NextReader()
+		}
+	case net.Conn:
+		buf := make([]byte, 512)
+		for err == nil {
+			_, err = conn.Read(buf)
+		}
+	default:
+		return
+	}
+
+	if held.closing.Load() {
+		return
+	}
+	held.dropped.Store(true)
+	held.closer.Close()
+
+	r.mutex.Lock()
+	delete(r.conns, held)
+	r.dropCauses[dropCause(err)]++
+	r.mutex.Unlock()
+
+	lifetime := time.Since(held.opened)
+	r.open.Add(-1)
+	r.dropped.Add(1)
+	if lifetime < earlyDropWindow && !held.dripped.Load() {
+		r.early.Add(1)
+		r.consecutive.Add(1)
+	}
+	if r.observing.Load() {
+		r.observeDropped.Add(1)
+	}
+	r.dropAfter.Record(lifetime)
+}
+
+// dropCause 归类已建立连接被断开的原因
+func dropCause(err error) string {
+	var closeErr *websocket.CloseError
+	switch {
+	case errors.Is(err, io.EOF), errors.As(err, &closeErr):
+		return "closed"
+	default:
+		return ClassifyError(err)
+	}
+}
+
+// drip 周期性向全部连接发送少量数据，模拟慢速客户端
+func (r *CeilingRunner) drip(ctx context.Context) {
+	ticker := time.NewTicker(r.config.Drip)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.mutex.Lock()
+			conns := make([]*heldConn, 0, len(r.conns))
+			for held := range r.conns {
+				conns = append(conns, held)
+			}
+			r.mutex.Unlock()
+
+			deadline := time.Now().Add(r.config.Drip)
+			for _, held := range conns {
+				if ctx.Err() != nil {
+					return
+				}
+				// 写入失败意味着连接已断开，由monitor统计
+				held.dripped.Store(true)
+				switch conn := held.closer.(type) {
+				case *websocket.Conn:
+					conn.WriteControl(websocket.PingMessage, r.config.DripPayload, deadline)
+				case net.Conn:
+					conn.SetWriteDeadline(deadline)
+					conn.Write(r.config.DripPayload)
+				}
+			}
+		}
+	}
+}
+
+// record 将建连结果写入收集器
+func (r *CeilingRunner) record(success bool, duration time.Duration, err error, cause string) {
+	if r.collector == nil {
+		return
+	}
+	metadata := map[string]interface{}{"operation_type": "connect"}
+	if cause != "" {
+		metadata["failure_cause"] = cause
+	}
+	r.collector.Record(&interfaces.OperationResult{
+		Success:  success,
+		Duration: duration,
+		Error:    err,
+		Metadata: metadata,
+	})
+}
+
+// sample 每秒统计一次连接状态
+func (r *CeilingRunner) sample(ctx context.Context, start time.Time, onSample func(CeilingSample)) []CeilingSample {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	var samples []CeilingSample
+	var lastEstablished, lastFailed, lastDropped int64
+	for {
+		select {
+		case <-ctx.Done():
+			return samples
+		case now := <-ticker.C:
+			established, failed, dropped := r.established.Load(), r.failed.Load(), r.dropped.Load()
+			sample := CeilingSample{
+				Elapsed:     now.Sub(start).Round(time.Second),
+				Open:        r.open.Load(),
+				Established: established - lastEstablished,
+				Failed:      failed - lastFailed,
+				Dropped:     dropped - lastDropped,
+			}
+			lastEstablished, lastFailed, lastDropped = established, failed, dropped
+			samples = append(samples, sample)
+			if onSample != nil {
+				onSample(sample)
+			}
+		}
+	}
+}
+
+// closeAll 关闭全部保持的连接并等待监控协程退出
+func (r *CeilingRunner) closeAll() {
+	r.mutex.Lock()
+	conns := r.conns
+	r.conns = make(map[*heldConn]struct{})
+	r.mutex.Unlock()
+
+	for held := range conns {
+		held.closing.Store(true)
+		held.closer.Close()
+	}
+	r.monitors.Wait()
+}
+
+// result 汇总探测结果
+func (r *CeilingRunner) result(reason string, rampDuration time.Duration, samples []CeilingSample) *CeilingResult {
+	result := &CeilingResult{
+		Transport:        r.dialer.Name(),
+		Target:           r.dialer.Target(),
+		Ceiling:          r.peakOpen.Load(),
+		StopReason:       reason,
+		RampDuration:     rampDuration,
+		Attempts:         r.attempts.Load(),
+		Established:      r.established.Load(),
+		Failed:           r.failed.Load(),
+		Dropped:          r.dropped.Load(),
+		EarlyDropped:     r.early.Load(),
+		ObserveDropped:   r.observeDropped.Load(),
+		OpenAfterObserve: r.open.Load(),
+		Connect:          r.connect.GetMetrics(),
+		Total:            r.total.GetMetrics(),
+		DropAfter:        r.dropAfter.GetMetrics(),
+		Failures:         make(map[string]int64),
+		DropCauses:       make(map[string]int64),
+		Samples:          samples,
+	}
+
+	r.mutex.Lock()
+	for cause, count := range r.failures {
+		result.Failures[cause] = count
+	}
+	for cause, count := range r.dropCauses {
+		result.DropCauses[cause] = count
+	}
+	result.FirstFailureOpen = r.firstFailureOpen
+	result.FirstFailureCause = r.firstFailureCause
+	r.mutex.Unlock()
+
+	result.Behavior = classifyBehavior(result)
+	return result
+}
+
+// classifyBehavior 根据失败原因与断开情况判定目标到达上限时的表现
+func classifyBehavior(result *CeilingResult) string {
+	if result.Failed == 0 {
+		switch {
+		case result.EarlyDropped > 0 && result.EarlyDropped*2 >= result.Dropped:
+			return BehaviorReset
+		case result.Dropped > 0:
+			return BehaviorEvict
+		}
+		return BehaviorNone
+	}
+
+	dominant, dominantCount := "", int64(0)
+	for cause, count := range result.Failures {
+		if count > dominantCount || (count == dominantCount && cause < dominant) {
+			dominant, dominantCount = cause, count
+		}
+	}
+
+	switch dominant {
+	case "client_fd_exhausted", "client_port_exhausted":
+		return BehaviorClientLimit
+	case "refused":
+		return BehaviorRefuse
+	case "connect_timeout", "handshake_timeout":
+		return BehaviorTimeout
+	default:
+		return BehaviorReset
+	}
+}
@@ -0,0 +1,140 @@
+package conntest
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// startLimitedServer 启动最多保持limit个连接的TCP服务，超出的新连接立即关闭
+// limit<=0表示不限制；received统计收到的字节数
+func startLimitedServer(t *testing.T, limit int, received *atomic.Int64) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	var mutex sync.Mutex
+	var conns []net.Conn
+	t.Cleanup(func() {
+		listener.Close()
+		mutex.Lock()
+		for _, conn := range conns {
+			conn.Close()
+		}
+		mutex.Unlock()
+	})
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			mutex.Lock()
+			if limit > 0 && len(conns) >= limit {
+				mutex.Unlock()
+				conn.Close()
+				continue
+			}
+			conns = append(conns, conn)
+			mutex.Unlock()
+
+			go func() {
+				buf := make([]byte, 64)
+				for {
+					n, err := conn.Read(buf)
+					received.Add(int64(n))
+					if err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func runCeiling(t *testing.T, target string, config *CeilingConfig) *CeilingResult {
+	dialer, err := NewDialer(target, DialerOptions{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("NewDialer(%q): %v", target, err)
+	}
+	result, err := NewCeilingRunner(dialer, config, nil).Run(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	return result
+}
+
+func TestCeilingRunner_ServerLimit(t *testing.T) {
+	var received atomic.Int64
+	address := startLimitedServer(t, 10, &received)
+
+	config := NewDefaultCeilingConfig()
+	config.Rate = 200
+	config.Concurrency = 5
+	config.MaxFailures = 5
+	config.MaxDuration = 10 * time.Second
+	config.Observe = 0
+	result := runCeiling(t, address, config)
+
+	if result.StopReason != StopFailures {
+		t.Errorf("stop reason = %s, want %s", result.StopReason, StopFailures)
+	}
+	if result.Ceiling < 10 || result.Ceiling > 20 {
+		t.Errorf("ceiling = %d, want about 10", result.Ceiling)
+	}
+	if result.Behavior != BehaviorReset || result.EarlyDropped == 0 {
+		t.Errorf("behavior = %s, early dropped = %d, drop causes = %v", result.Behavior, result.EarlyDropped, result.DropCauses)
+	}
+}
+
+func TestCeilingRunner_MaxConnectionsWithDrip(t *testing.T) {
+	var received atomic.Int64
+	address := startLimitedServer(t, 0, &received)
+
+	config := NewDefaultCeilingConfig()
+	config.Rate = 300
+	config.MaxConnections = 30
+	config.Observe = 300 * time.Millisecond
+	config.Drip = 50 * time.Millisecond
+	result := runCeiling(t, "tcp://"+address, config)
+
+	if result.StopReason != StopMaxConnections {
+		t.Errorf("stop reason = %s, want %s", result.StopReason, StopMaxConnections)
+	}
+	if result.Ceiling < 30 || result.Failed != 0 || result.Dropped != 0 {
+		t.Errorf("ceiling = %d, failed = %d, dropped = %d", result.Ceiling, result.Failed, result.Dropped)
+	}
+	if result.Behavior != BehaviorNone || result.OpenAfterObserve != result.Ceiling {
+		t.Errorf("behavior = %s, open after observe = %d", result.Behavior, result.OpenAfterObserve)
+	}
+	if received.Load() == 0 {
+		t.Errorf("server received no drip data")
+	}
+}
+
+func TestCeilingRunner_Refused(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	config := NewDefaultCeilingConfig()
+	config.MaxFailures = 3
+	config.Observe = 0
+	result := runCeiling(t, address, config)
+
+	if result.Ceiling != 0 || result.Behavior != BehaviorRefuse {
+		t.Errorf("ceiling = %d, behavior = %s, failures = %v", result.Ceiling, result.Behavior, result.Failures)
+	}
+	if result.FirstFailureOpen != 0 || result.FirstFailureCause != "refused" {
+		t.Errorf("first failure at %d (%s)", result.FirstFailureOpen, result.FirstFailureCause)
+	}
+}
@@ -0,0 +1,415 @@
+package conntest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+)
+
+// pacingInterval 开环模式下检查发起进度的间隔
+const pacingInterval = time.Millisecond
+
+// ChurnConfig 新建连接速率（连接抖动）测试配置
+type ChurnConfig struct {
+	Rate        int           // 每秒发起的新建连接数，0表示以Concurrency个并发尽可能快地建连
+	Concurrency int           // 同时进行握手的最大连接数
+	Duration    time.Duration // 测试持续时间
+	Hold        time.Duration // 建连成功后保持连接的时间，0表示立即关闭
+}
+
+// NewDefaultChurnConfig 创建默认连接抖动测试配置
+func NewDefaultChurnConfig() *ChurnConfig {
+	return &ChurnConfig{
+		Rate:        0,
+		Concurrency: 50,
+		Duration:    10 * time.Second,
+	}
+}
+
+// Validate 验证配置
+func (c *ChurnConfig) Validate() error {
+	if c.Rate < 0 {
+		return fmt.Errorf("rate cannot be negative")
+	}
+	if c.Concurrency <= 0 {
+		return fmt.Errorf("concurrency must be positive")
+	}
+	if c.Duration <= 0 {
+		return fmt.Errorf("duration must be positive")
+	}
+	if c.Hold < 0 {
+		return fmt.Errorf("hold cannot be negative")
+	}
+	return nil
+}
+
+// ChurnSample 每秒的建连统计
+type ChurnSample struct {
+	Elapsed     time.Duration `json:"elapsed"`
+	Attempts    int64         `json:"attempts"`
+	Established int64         `json:"established"`
+	Failed      int64         `json:"failed"`
+	Open        int64         `json:"open"`
+}
+
+// ChurnStats 连接抖动测试结果
+type ChurnStats struct {
+	Transport      string                 `json:"transport"`
+	Target         string                 `json:"target"`
+	Duration       time.Duration          `json:"duration"`
+	Attempts       int64                  `json:"attempts"`
+	Established    int64                  `json:"established"`
+	Failed         int64                  `json:"failed"`
+	Skipped        int64                  `json:"skipped"` // 因并发上限未能发起的尝试（仅开环模式）
+	SuccessRate    float64                `json:"success_rate"`
+	AttemptRate    float64                `json:"attempt_rate"`    // 每秒发起的建连数
+	ConnectionRate float64                `json:"connection_rate"` // 每秒成功建立的连接数
+	PeakRate       float64                `json:"peak_rate"`       // 单秒成功建连数的峰值
+	PeakOpen       int64                  `json:"peak_open"`       // 同时保持的连接数峰值
+	Connect        metrics.LatencyMetrics `json:"connect"`         // TCP建连延迟
+	Handshake      metrics.LatencyMetrics `json:"handshake"`       // TLS/WebSocket握手延迟
+	Total          metrics.LatencyMetrics `json:"total"`           // 完整建连延迟
+	Failures       map[string]int64       `json:"failures"`        // 按原因统计的失败数
+	Samples        []ChurnSample          `json:"samples"`
+}
+
+// FailureCauses 按次数从高到低排列的失败原因
+func (s *ChurnStats) FailureCauses() []string {
+	return sortCauses(s.Failures)
+}
+
+// sortCauses 按次数从高到低排列失败原因，次数相同时按名称排列
+func sortCauses(failures map[string]int64) []string {
+	causes := make([]string, 0, len(failures))
+	for cause := range failures {
+		causes = append(causes, cause)
+	}
+	sort.Slice(causes, func(i, j int) bool {
+		if failures[causes[i]] != failures[causes[j]] {
+			return failures[causes[i]] > failures[causes[j]]
+		}
+		return causes[i] < causes[j]
+	})
+	return causes
+}
+
+// ToMap 转换为报告使用的map
+func (s *ChurnStats) ToMap() map[string]interface{} {
+	failures := make(map[string]interface{}, len(s.Failures))
+	for cause, count := range s.Failures {
+		failures[cause] = count
+	}
+	result := map[string]interface{}{
+		"transport":       s.Transport,
+		"target":          s.Target,
+		"duration":        s.Duration.String(),
+		"attempts":        s.Attempts,
+		"established":     s.Established,
+		"failed":          s.Failed,
+		"skipped":         s.Skipped,
+		"success_rate":    s.SuccessRate,
+		"attempt_rate":    s.AttemptRate,
+		"connection_rate": s.ConnectionRate,
+		"peak_rate":       s.PeakRate,
+		"peak_open":       s.PeakOpen,
+		"connect":         latencyToMap(s.Connect),
+		"total":           latencyToMap(s.Total),
+		"failures":        failures,
+	}
+	if s.Handshake.P50 > 0 {
+		result["handshake"] = latencyToMap(s.Handshake)
+	}
+	return result
+}
+
+// latencyToMap 将延迟指标转换为报告使用的map
+func latencyToMap(m metrics.LatencyMetrics) map[string]interface{} {
+	return map[string]interface{}{
+		"avg":  m.Average.String(),
+		"min":  m.Min.String(),
+		"max":  m.Max.String(),
+		"p50":  m.P50.String(),
+		"p90":  m.P90.String(),
+		"p95":  m.P95.String(),
+		"p99":  m.P99.String(),
+		"p999": m.P999.String(),
+	}
+}
+
+// ChurnRunner 新建连接速率测试执行器
+// 反复建立并关闭连接，只衡量目标接受新连接的能力，不发送任何业务请求
+type ChurnRunner struct {
+	dialer    Dialer
+	config    *ChurnConfig
+	collector *metrics.BaseCollector[map[string]interface{}]
+
+	attempts    atomic.Int64
+	established atomic.Int64
+	failed      atomic.Int64
+	skipped     atomic.Int64
+	open        atomic.Int64
+	peakOpen    atomic.Int64
+
+	connect   *metrics.LatencyTracker
+	handshake *metrics.LatencyTracker
+	total     *metrics.LatencyTracker
+
+	failuresMutex sync.Mutex
+	failures      map[string]int64
+
+	holds sync.WaitGroup
+}
+
+// NewChurnRunner 创建连接抖动测试执行器，collector可为nil
+func NewChurnRunner(dialer Dialer, config *ChurnConfig, collector *metrics.BaseCollector[map[string]interface{}]) *ChurnRunner {
+	latencyConfig := metrics.LatencyConfig{
+		SamplingRate: 1.0,
+	}
+	return &ChurnRunner{
+		dialer:    dialer,
+		config:    config,
+		collector: collector,
+		connect:   metrics.NewLatencyTracker(latencyConfig),
+		handshake: metrics.NewLatencyTracker(latencyConfig),
+		total:     metrics.NewLatencyTracker(latencyConfig),
+		failures:  make(map[string]int64),
+	}
+}
+
+// Run 在配置的时长内持续建连，onSample每秒回调一次
+func (r *ChurnRunner) Run(ctx context.Context, onSample func(ChurnSample)) (*ChurnStats, error) {
+	if err := r.config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid churn config: %w", err)
+	}
+
+	// 以定时取消而非截止时间结束测试：截止时间会传递给在途握手，
+	// 使其在ctx.Err()生效前以超时失败，被误计为connect_timeout
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := time.AfterFunc(r.config.Duration, cancel)
+	defer stop.Stop()
+
+	start := time.Now()
+	samples := make(chan []ChurnSample, 1)
+	go func() {
+		samples <- r.sample(runCtx, start, onSample)
+	}()
+
+	if r.config.Rate > 0 {
+		r.runOpenLoop(runCtx, start)
+	} else {
+		r.runClosedLoop(runCtx)
+	}
+	duration := time.Since(start)
+	cancel()
+
+	stats := r.stats(duration, <-samples)
+	r.holds.Wait()
+
+	if ctx.Err() != nil {
+		return stats, ctx.Err()
+	}
+	return stats, nil
+}
+
+// runClosedLoop 以固定并发尽可能快地建连
+func (r *ChurnRunner) runClosedLoop(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < r.config.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				r.attempt(ctx)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// runOpenLoop 按目标速率发起建连，不受目标响应快慢影响
+// 在途握手达到并发上限时记为跳过，而不是推迟发起，避免掩盖目标的排队延迟
+func (r *ChurnRunner) runOpenLoop(ctx context.Context, start time.Time) {
+	slots := make(chan struct{}, r.config.Concurrency)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	ticker := time.NewTicker(pacingInterval)
+	defer ticker.Stop()
+
+	var issued int64
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			due := int64(now.Sub(start).Seconds() * float64(r.config.Rate))
+			for ; issued < due; issued++ {
+				select {
+				case slots <- struct{}{}:
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						defer func() { <-slots }()
+						r.attempt(ctx)
+					}()
+				default:
+					r.skipped.Add(1)
+				}
+			}
+		}
+	}
+}
+
+// attempt 发起一次建连并按配置保持或立即关闭
+func (r *ChurnRunner) attempt(ctx context.Context) {
+	conn, timing, err := r.dialer.Dial(ctx)
+	// 测试结束时被取消的握手不计入统计
+	if err != nil && ctx.Err() != nil {
+		return
+	}
+
+	r.attempts.Add(1)
+	if err != nil {
+		r.failed.Add(1)
+		cause := ClassifyError(err)
+		r.recordFailure(cause)
+		r.record(false, timing.Total, err, cause)
+		return
+	}
+
+	r.established.Add(1)
+	r.connect.Record(timing.Connect)
+	if timing.Handshake > 0 {
+		r.handshake.Record(timing.Handshake)
+	}
+	r.total.Record(timing.Total)
+	r.record(true, timing.Total, nil, "")
+	r.hold(conn)
+}
+
+// hold 保持连接指定时间后关闭
+func (r *ChurnRunner) hold(conn io.Closer) {
+	if r.config.Hold <= 0 {
+		conn.Close()
+		return
+	}
+
+	open := r.open.Add(1)
+	for {
+		peak := r.peakOpen.Load()
+		if open <= peak || r.peakOpen.CompareAndSwap(peak, open) {
+			break
+		}
+	}
+
+	r.holds.Add(1)
+	time.AfterFunc(r.config.Hold, func() {
+		conn.Close()
+		r.open.Add(-1)
+		r.holds.Done()
+	})
+}
+
+// recordFailure 按原因累计失败
+func (r *ChurnRunner) recordFailure(cause string) {
+	r.failuresMutex.Lock()
+	r.failures[cause]++
+	r.failuresMutex.Unlock()
+}
+
+// record 将建连结果写入收集器，建连延迟即操作耗时
+func (r *ChurnRunner) record(success bool, duration time.Duration, err error, cause string) {
+	if r.collector == nil {
+		return
+	}
+	metadata := map[string]interface{}{"operation_type": "connect"}
+	if cause != "" {
+		metadata["failure_cause"] = cause
+	}
+	r.collector.Record(&interfaces.OperationResult{
+		Success:  success,
+		Duration: duration,
+		Error:    err,
+		Metadata: metadata,
+	})
+}
+
+// sample 每秒统计一次增量，直到测试结束
+func (r *ChurnRunner) sample(ctx context.Context, start time.Time, onSample func(ChurnSample)) []ChurnSample {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	var samples []ChurnSample
+	var lastAttempts, lastEstablished, lastFailed int64
+	for {
+		select {
+		case <-ctx.Done():
+			return samples
+		case now := <-ticker.C:
+			attempts, established, failed := r.attempts.Load(), r.established.Load(), r.failed.Load()
+			sample := ChurnSample{
+				Elapsed:     now.Sub(start).Round(time.Second),
+				Attempts:    attempts - lastAttempts,
+				Established: established - lastEstablished,
+				Failed:      failed - lastFailed,
+				Open:        r.open.Load(),
+			}
+			lastAttempts, lastEstablished, lastFailed = attempts, established, failed
+			samples = append(samples, sample)
+			if onSample != nil {
+				onSample(sample)
+			}
+		}
+	}
+}
+
+// stats 汇总测试结果
+func (r *ChurnRunner) stats(duration time.Duration, samples []ChurnSample) *ChurnStats {
+	stats := &ChurnStats{
+		Transport:   r.dialer.Name(),
+		Target:      r.dialer.Target(),
+		Duration:    duration,
+		Attempts:    r.attempts.Load(),
+		Established: r.established.Load(),
+		Failed:      r.failed.Load(),
+		Skipped:     r.skipped.Load(),
+		PeakOpen:    r.peakOpen.Load(),
+		Connect:     r.connect.GetMetrics(),
+		Handshake:   r.handshake.GetMetrics(),
+		Total:       r.total.GetMetrics(),
+		Samples:     samples,
+		Failures:    make(map[string]int64),
+	}
+
+	r.failuresMutex.Lock()
+	for cause, count := range r.failures {
+		stats.Failures[cause] = count
+	}
+	r.failuresMutex.Unlock()
+
+	if stats.Attempts > 0 {
+		stats.SuccessRate = float64(stats.Established) / float64(stats.Attempts) * 100
+	}
+	if seconds := duration.Seconds(); seconds > 0 {
+		stats.AttemptRate = float64(stats.Attempts) / seconds
+		stats.ConnectionRate = float64(stats.Established) / seconds
+	}
+	for _, sample := range samples {
+		if rate := float64(sample.Established); rate > stats.PeakRate {
+			stats.PeakRate = rate
+		}
+	}
+	// 不足一秒的测试没有完整采样，以平均速率代替峰值
+	if len(samples) == 0 {
+		stats.PeakRate = stats.ConnectionRate
+	}
+	return stats
+}
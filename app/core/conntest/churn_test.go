@@ -0,0 +1,163 @@
+package conntest
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// startTCPServer 启动接受连接后立即关闭的TCP服务
+func startTCPServer(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func runChurn(t *testing.T, target string, options DialerOptions, config *ChurnConfig) *ChurnStats {
+	dialer, err := NewDialer(target, options)
+	if err != nil {
+		t.Fatalf("NewDialer(%q): %v", target, err)
+	}
+	stats, err := NewChurnRunner(dialer, config, nil).Run(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	return stats
+}
+
+func TestChurnRunner_TCPOpenLoop(t *testing.T) {
+	address := startTCPServer(t)
+	stats := runChurn(t, "tcp://"+address, DialerOptions{Linger0: true}, &ChurnConfig{
+		Rate:        200,
+		Concurrency: 20,
+		Duration:    1200 * time.Millisecond,
+		Hold:        50 * time.Millisecond,
+	})
+
+	if stats.Transport != "tcp" {
+		t.Errorf("transport = %q, want tcp", stats.Transport)
+	}
+	if stats.Established < 150 || stats.Established > 260 {
+		t.Errorf("established = %d, want about 240 at 200/s", stats.Established)
+	}
+	if stats.Failed != 0 {
+		t.Errorf("failed = %d (%v), want 0", stats.Failed, stats.Failures)
+	}
+	if stats.Connect.P50 <= 0 || stats.Handshake.P50 != 0 {
+		t.Errorf("connect p50 = %v, handshake p50 = %v", stats.Connect.P50, stats.Handshake.P50)
+	}
+	if len(stats.Samples) != 1 || stats.PeakRate <= 0 || stats.PeakOpen <= 0 {
+		t.Errorf("samples = %v, peak rate = %v, peak open = %d", stats.Samples, stats.PeakRate, stats.PeakOpen)
+	}
+}
+
+func TestChurnRunner_Refused(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	stats := runChurn(t, address, DialerOptions{Timeout: time.Second}, &ChurnConfig{
+		Rate:        500,
+		Concurrency: 2,
+		Duration:    200 * time.Millisecond,
+	})
+	if stats.Established != 0 || stats.Failed == 0 {
+		t.Fatalf("established = %d, failed = %d", stats.Established, stats.Failed)
+	}
+	if stats.Failures["refused"] != stats.Failed {
+		t.Errorf("failures = %v, want all refused", stats.Failures)
+	}
+	if causes := stats.FailureCauses(); len(causes) != 1 || causes[0] != "refused" {
+		t.Errorf("causes = %v", causes)
+	}
+}
+
+func TestChurnRunner_TLSHandshake(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+	address := strings.TrimPrefix(server.URL, "https://")
+
+	stats := runChurn(t, "tls://"+address, DialerOptions{InsecureSkipVerify: true, Linger0: true}, &ChurnConfig{
+		Concurrency: 4,
+		Duration:    200 * time.Millisecond,
+	})
+	if stats.Established == 0 || stats.Failed != 0 {
+		t.Fatalf("established = %d, failures = %v", stats.Established, stats.Failures)
+	}
+	if stats.Handshake.P50 <= 0 || stats.Total.P50 < stats.Connect.P50 {
+		t.Errorf("connect = %v, handshake = %v, total = %v", stats.Connect.P50, stats.Handshake.P50, stats.Total.P50)
+	}
+
+	// 未跳过证书校验时归类为证书错误
+	stats = runChurn(t, "tls://"+address, DialerOptions{Linger0: true}, &ChurnConfig{
+		Concurrency: 1,
+		Duration:    500 * time.Millisecond,
+	})
+	if stats.Failed == 0 || stats.Failures["tls_certificate"] != stats.Failed {
+		t.Errorf("failures = %v, want tls_certificate", stats.Failures)
+	}
+}
+
+func TestChurnRunner_WebSocket(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ws" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err == nil {
+			conn.Close()
+		}
+	}))
+	defer server.Close()
+	base := "ws://" + strings.TrimPrefix(server.URL, "http://")
+
+	stats := runChurn(t, base+"/ws", DialerOptions{Linger0: true}, &ChurnConfig{
+		Concurrency: 4,
+		Duration:    200 * time.Millisecond,
+	})
+	if stats.Transport != "websocket" || stats.Established == 0 || stats.Failed != 0 {
+		t.Fatalf("transport = %s, established = %d, failures = %v", stats.Transport, stats.Established, stats.Failures)
+	}
+	if stats.Handshake.P50 <= 0 {
+		t.Errorf("handshake p50 = %v", stats.Handshake.P50)
+	}
+
+	stats = runChurn(t, base+"/denied", DialerOptions{Linger0: true}, &ChurnConfig{
+		Concurrency: 1,
+		Duration:    300 * time.Millisecond,
+	})
+	if stats.Failed == 0 || stats.Failures["http_403"] != stats.Failed {
+		t.Errorf("failures = %v, want http_403", stats.Failures)
+	}
+}
+
+func TestNewDialer_InvalidTargets(t *testing.T) {
+	for _, target := range []string{"udp://127.0.0.1:1", "tcp://127.0.0.1", "tls://", "127.0.0.1"} {
+		if _, err := NewDialer(target, DialerOptions{}); err == nil {
+			t.Errorf("NewDialer(%q) succeeded, want error", target)
+		}
+	}
+}
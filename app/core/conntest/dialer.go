@@ -0,0 +1,330 @@
+package conntest
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// DialTiming 单次建连的耗时拆分
+type DialTiming struct {
+	Connect   time.Duration // TCP三次握手
+	Handshake time.Duration // TLS/WebSocket握手，纯TCP时为0
+	Total     time.Duration // 从发起到连接可用
+}
+
+// Dialer 建立单个连接的抽象
+// 返回的连接已完成全部握手，可直接用于收发或关闭
+type Dialer interface {
+	Name() string
+	Target() string
+	Dial(ctx context.Context) (io.Closer, DialTiming, error)
+}
+
+// DialerOptions 建连选项
+type DialerOptions struct {
+	Timeout            time.Duration // 单次建连超时（含握手）
+	InsecureSkipVerify bool          // 跳过TLS证书校验
+	ServerName         string        // TLS SNI，默认取目标主机名
+	Linger0            bool          // 关闭时发送RST而不是FIN，避免客户端积累TIME_WAIT
+}
+
+// NewDialer 根据目标地址创建Dialer
+// 支持 tcp://host:port、tls://host:port、ws://host/path、wss://host/path，未带scheme时按tcp处理
+func NewDialer(target string, options DialerOptions) (Dialer, error) {
+	if options.Timeout <= 0 {
+		options.Timeout = 5 * time.Second
+	}
+	if !strings.Contains(target, "://") {
+		target = "tcp://" + target
+	}
+
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target %q: %w", target, err)
+	}
+	if parsed.Host == "" {
+		return nil, fmt.Errorf("invalid target %q: missing host", target)
+	}
+
+	base := baseDialer{
+		target:  target,
+		options: options,
+		net:     &net.Dialer{Timeout: options.Timeout},
+	}
+	switch parsed.Scheme {
+	case "tcp":
+		if parsed.Port() == "" {
+			return nil, fmt.Errorf("invalid target %q: missing port", target)
+		}
+		return &tcpDialer{baseDialer: base, address: parsed.Host}, nil
+	case "tls":
+		if parsed.Port() == "" {
+			return nil, fmt.Errorf("invalid target %q: missing port", target)
+		}
+		return &tlsDialer{baseDialer: base, address: parsed.Host, config: base.tlsConfig(parsed.Hostname())}, nil
+	case "ws", "wss":
+		return newWebSocketDialer(base, parsed), nil
+	default:
+		return nil, fmt.Errorf("unsupported target scheme %q (expected tcp, tls, ws or wss)", parsed.Scheme)
+	}
+}
+
+// baseDialer 各类Dialer共用的TCP建连逻辑
+type baseDialer struct {
+	target  string
+	options DialerOptions
+	net     *net.Dialer
+}
+
+// Target 获取目标地址
+func (d *baseDialer) Target() string {
+	return d.target
+}
+
+// connect 建立TCP连接并应用连接选项
+func (d *baseDialer) connect(ctx context.Context, network, address string) (net.Conn, error) {
+	conn, err := d.net.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	if tcpConn, ok := conn.(*net.TCPConn); ok && d.options.Linger0 {
+		tcpConn.SetLinger(0)
+	}
+	return conn, nil
+}
+
+// tlsConfig 构建TLS客户端配置
+func (d *baseDialer) tlsConfig(host string) *tls.Config {
+	serverName := d.options.ServerName
+	if serverName == "" {
+		serverName = host
+	}
+	return &tls.Config{
+		ServerName:         serverName,
+		InsecureSkipVerify: d.options.InsecureSkipVerify,
+		// 每次建连都做完整握手，衡量的是服务端接受新连接的能力
+		SessionTicketsDisabled: true,
+	}
+}
+
+// tcpDialer 纯TCP建连
+type tcpDialer struct {
+	baseDialer
+	address string
+}
+
+// Name 获取传输名称
+func (d *tcpDialer) Name() string {
+	return "tcp"
+}
+
+// Dial 建立TCP连接
+func (d *tcpDialer) Dial(ctx context.Context) (io.Closer, DialTiming, error) {
+	start := time.Now()
+	conn, err := d.connect(ctx, "tcp", d.address)
+	elapsed := time.Since(start)
+	return conn, DialTiming{Connect: elapsed, Total: elapsed}, err
+}
+
+// tlsDialer TCP建连后完成TLS握手
+type tlsDialer struct {
+	baseDialer
+	address string
+	config  *tls.Config
+}
+
+// Name 获取传输名称
+func (d *tlsDialer) Name() string {
+	return "tls"
+}
+
+// Dial 建立TLS连接
+func (d *tlsDialer) Dial(ctx context.Context) (io.Closer, DialTiming, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.options.Timeout)
+	defer cancel()
+
+	var timing DialTiming
+	start := time.Now()
+	raw, err := d.connect(ctx, "tcp", d.address)
+	timing.Connect = time.Since(start)
+	if err != nil {
+		timing.Total = timing.Connect
+		return nil, timing, err
+	}
+
+	conn := tls.Client(raw, d.config)
+	err = conn.HandshakeContext(ctx)
+	timing.Total = time.Since(start)
+	timing.Handshake = timing.Total - timing.Connect
+	if err != nil {
+		raw.Close()
+		return nil, timing, &HandshakeError{Err: err}
+	}
+	return conn, timing, nil
+}
+
+// webSocketDialer 完成（可选TLS及）WebSocket升级握手
+type webSocketDialer struct {
+	baseDialer
+	url    string
+	scheme string
+	dialer *websocket.Dialer
+}
+
+func newWebSocketDialer(base baseDialer, parsed *url.URL) *webSocketDialer {
+	return &webSocketDialer{
+		baseDialer: base,
+		url:        parsed.String(),
+		scheme:     parsed.Scheme,
+		dialer: &websocket.Dialer{
+			HandshakeTimeout: base.options.Timeout,
+			TLSClientConfig:  base.tlsConfig(parsed.Hostname()),
+		},
+	}
+}
+
+// Name 获取传输名称
+func (d *webSocketDialer) Name() string {
+	if d.scheme == "wss" {
+		return "wss"
+	}
+	return "websocket"
+}
+
+// Dial 建立WebSocket连接
+func (d *webSocketDialer) Dial(ctx context.Context) (io.Closer, DialTiming, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.options.Timeout)
+	defer cancel()
+
+	var timing DialTiming
+	connected := false
+	start := time.Now()
+
+	// 复制Dialer以便在本次建连中记录TCP建连耗时
+	dialer := *d.dialer
+	dialer.NetDialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		conn, err := d.connect(ctx, network, address)
+		timing.Connect = time.Since(start)
+		connected = err == nil
+		return conn, err
+	}
+
+	conn, resp, err := dialer.DialContext(ctx, d.url, nil)
+	if resp != nil && resp.Body != nil {
+		resp.Body.Close()
+	}
+	timing.Total = time.Since(start)
+	if connected {
+		timing.Handshake = timing.Total - timing.Connect
+	}
+	if err != nil {
+		if connected {
+			status := 0
+			if resp != nil {
+				status = resp.StatusCode
+			}
+			err = &HandshakeError{Err: err, Status: status}
+		}
+		return nil, timing, err
+	}
+	return conn, timing, nil
+}
+
+// HandshakeError TCP连接建立后的握手（TLS或WebSocket升级）失败
+type HandshakeError struct {
+	Err    error
+	Status int // WebSocket升级失败时的HTTP状态码
+}
+
+func (e *HandshakeError) Error() string {
+	if e.Status != 0 {
+		return fmt.Sprintf("handshake failed with HTTP %d: %v", e.Status, e.Err)
+	}
+	return fmt.Sprintf("handshake failed: %v", e.Err)
+}
+
+func (e *HandshakeError) Unwrap() error {
+	return e.Err
+}
+
+// ClassifyError 将建连错误归类为失败原因
+// 客户端资源耗尽（文件描述符、临时端口）单独归类，避免误判为目标端的问题
+func ClassifyError(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var handshakeErr *HandshakeError
+	isHandshake := errors.As(err, &handshakeErr)
+
+	var certErr *tls.CertificateVerificationError
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var dnsErr *net.DNSError
+	var netErr net.Error
+
+	switch {
+	case errors.Is(err, syscall.EMFILE), errors.Is(err, syscall.ENFILE):
+		return "client_fd_exhausted"
+	case errors.Is(err, syscall.EADDRNOTAVAIL), errors.Is(err, syscall.EAGAIN):
+		// Linux在临时端口耗尽时connect返回EAGAIN，其Timeout()为true，需先于超时判断
+		return "client_port_exhausted"
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return "refused"
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.EPIPE):
+		if isHandshake {
+			return "handshake_reset"
+		}
+		return "reset"
+	case errors.Is(err, syscall.EHOSTUNREACH), errors.Is(err, syscall.ENETUNREACH):
+		return "unreachable"
+	case errors.As(err, &dnsErr):
+		return "dns"
+	case errors.As(err, &certErr), errors.As(err, &unknownAuthority), errors.As(err, &hostnameErr):
+		return "tls_certificate"
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, os.ErrDeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		if isHandshake {
+			return "handshake_timeout"
+		}
+		return "connect_timeout"
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		if isHandshake {
+			return "handshake_closed"
+		}
+		return "closed"
+	case isHandshake && handshakeErr.Status != 0:
+		return fmt.Sprintf("http_%d", handshakeErr.Status)
+	case isHandshake:
+		return "handshake_failed"
+	default:
+		return "other"
+	}
+}
+
+// sleepContext 可被取消的等待
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
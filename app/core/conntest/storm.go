@@ -0,0 +1,607 @@
+package conntest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+)
+
+// 重连退避的抖动策略
+const (
+	JitterNone  = "none"  // 不加抖动，所有客户端在同一时刻重试
+	JitterFull  = "full"  // 在[0, delay]内均匀取值
+	JitterEqual = "equal" // 在[delay/2, delay]内均匀取值
+)
+
+// BackoffPolicy 客户端断线后的重连退避策略：第n次重连前等待min(Max, Initial*Multiplier^n)并按Jitter加抖动
+type BackoffPolicy struct {
+	Initial     time.Duration
+	Max         time.Duration
+	Multiplier  float64
+	Jitter      string
+	MaxAttempts int // 每次断线的最大重连次数，0表示不限，直到重连超时
+}
+
+// Validate 验证退避策略
+func (p BackoffPolicy) Validate() error {
+	if p.Initial <= 0 {
+		return fmt.Errorf("initial backoff must be positive")
+	}
+	if p.Max < p.Initial {
+		return fmt.Errorf("max backoff must not be less than the initial backoff")
+	}
+	if p.Multiplier < 1 {
+		return fmt.Errorf("backoff multiplier must be at least 1")
+	}
+	switch p.Jitter {
+	case JitterNone, JitterFull, JitterEqual:
+	default:
+		return fmt.Errorf("invalid jitter %q (expected none, full or equal)", p.Jitter)
+	}
+	if p.MaxAttempts < 0 {
+		return fmt.Errorf("max reconnect attempts cannot be negative")
+	}
+	return nil
+}
+
+// Delay 第attempt次（从0开始）重连前的等待时间
+func (p BackoffPolicy) Delay(attempt int) time.Duration {
+	delay := time.Duration(math.Min(float64(p.Initial)*math.Pow(p.Multiplier, float64(attempt)), float64(p.Max)))
+	switch p.Jitter {
+	case JitterFull:
+		return time.Duration(rand.Int64N(int64(delay) + 1))
+	case JitterEqual:
+		return delay/2 + time.Duration(rand.Int64N(int64(delay/2)+1))
+	}
+	return delay
+}
+
+// String 退避策略的说明
+func (p BackoffPolicy) String() string {
+	attempts := "unlimited attempts"
+	if p.MaxAttempts > 0 {
+		attempts = fmt.Sprintf("max %d attempts", p.MaxAttempts)
+	}
+	return fmt.Sprintf("%v → %v ×%g, %s jitter, %s", p.Initial, p.Max, p.Multiplier, p.Jitter, attempts)
+}
+
+// StormConfig 重连风暴测试配置
+type StormConfig struct {
+	Clients     int           // 保持的客户端连接数
+	Concurrency int           // 建立初始连接时同时进行的握手数
+	Fraction    float64       // 每次风暴同时断开的已连接客户端比例
+	Storms      int           // 风暴次数
+	Interval    time.Duration // 每次风暴前保持连接的时间
+	Timeout     time.Duration // 风暴开始后等待重连的最长时间，超时仍未重连的客户端计为放弃
+	Backoff     BackoffPolicy
+}
+
+// NewDefaultStormConfig 创建默认重连风暴测试配置
+func NewDefaultStormConfig() *StormConfig {
+	return &StormConfig{
+		Clients:     100,
+		Concurrency: 50,
+		Fraction:    0.5,
+		Storms:      1,
+		Interval:    5 * time.Second,
+		Timeout:     60 * time.Second,
+		Backoff: BackoffPolicy{
+			Initial:    500 * time.Millisecond,
+			Max:        30 * time.Second,
+			Multiplier: 2,
+			Jitter:     JitterFull,
+		},
+	}
+}
+
+// Validate 验证配置
+func (c *StormConfig) Validate() error {
+	if c.Clients <= 0 {
+		return fmt.Errorf("clients must be positive")
+	}
+	if c.Concurrency <= 0 {
+		return fmt.Errorf("concurrency must be positive")
+	}
+	if c.Fraction <= 0 || c.Fraction > 1 {
+		return fmt.Errorf("storm fraction must be in (0, 1]")
+	}
+	if c.Storms <= 0 {
+		return fmt.Errorf("storms must be positive")
+	}
+	if c.Interval < 0 {
+		return fmt.Errorf("storm interval cannot be negative")
+	}
+	if c.Timeout <= 0 {
+		return fmt.Errorf("reconnect timeout must be positive")
+	}
+	return c.Backoff.Validate()
+}
+
+// StormSample 每秒的重连统计
+type StormSample struct {
+	Elapsed     time.Duration `json:"elapsed"`
+	Storm       int           `json:"storm"` // 当前风暴序号，0表示首次风暴之前
+	Attempts    int64         `json:"attempts"`
+	Reconnected int64         `json:"reconnected"`
+	Failed      int64         `json:"failed"` // 失败的重连尝试
+	Connected   int64         `json:"connected"`
+}
+
+// StormRound 单次风暴的结果
+type StormRound struct {
+	Storm        int                    `json:"storm"`
+	Disconnected int                    `json:"disconnected"`
+	Reconnected  int64                  `json:"reconnected"`
+	GaveUp       int64                  `json:"gave_up"`
+	Attempts     int64                  `json:"attempts"`
+	RecoveryTime time.Duration          `json:"recovery_time"` // 风暴开始到最后一个客户端重连成功
+	Reconnect    metrics.LatencyMetrics `json:"reconnect"`     // 断开到重连成功的耗时
+}
+
+// StormAttemptBucket 在第Attempts次尝试时重连成功的客户端数
+type StormAttemptBucket struct {
+	Attempts int   `json:"attempts"`
+	Count    int64 `json:"count"`
+}
+
+// StormStats 重连风暴测试结果
+type StormStats struct {
+	Transport            string                 `json:"transport"`
+	Target               string                 `json:"target"`
+	Backoff              string                 `json:"backoff"`
+	Duration             time.Duration          `json:"duration"`
+	Clients              int                    `json:"clients"`
+	Established          int                    `json:"established"` // 首次风暴前建立的连接数
+	Disconnected         int64                  `json:"disconnected"`
+	Reconnected          int64                  `json:"reconnected"`
+	GaveUp               int64                  `json:"gave_up"` // 超时或用尽重连次数
+	SuccessRate          float64                `json:"success_rate"`
+	Attempts             int64                  `json:"attempts"`
+	AttemptsPerReconnect float64                `json:"attempts_per_reconnect"`
+	AttemptDistribution  []StormAttemptBucket   `json:"attempt_distribution"`
+	Reconnect            metrics.LatencyMetrics `json:"reconnect"` // 断开到重连成功的耗时，含退避等待
+	Handshake            metrics.LatencyMetrics `json:"handshake"` // 成功重连那次建连的耗时
+	Rounds               []StormRound           `json:"rounds"`
+	Failures             map[string]int64       `json:"failures"` // 按原因统计的失败尝试
+	Samples              []StormSample          `json:"samples"`
+}
+
+// FailureCauses 按次数从高到低排列的失败原因
+func (s *StormStats) FailureCauses() []string {
+	return sortCauses(s.Failures)
+}
+
+// ToMap 转换为报告使用的map
+func (s *StormStats) ToMap() map[string]interface{} {
+	failures := make(map[string]interface{}, len(s.Failures))
+	for cause, count := range s.Failures {
+		failures[cause] = count
+	}
+	attempts := make(map[string]interface{}, len(s.AttemptDistribution))
+	for _, bucket := range s.AttemptDistribution {
+		attempts[fmt.Sprint(bucket.Attempts)] = bucket.Count
+	}
+	rounds := make([]map[string]interface{}, 0, len(s.Rounds))
+	for _, round := range s.Rounds {
+		rounds = append(rounds, map[string]interface{}{
+			"storm":         round.Storm,
+			"disconnected":  round.Disconnected,
+			"reconnected":   round.Reconnected,
+			"gave_up":       round.GaveUp,
+			"attempts":      round.Attempts,
+			"recovery_time": round.RecoveryTime.String(),
+			"reconnect":     latencyToMap(round.Reconnect),
+		})
+	}
+	return map[string]interface{}{
+		"transport":              s.Transport,
+		"target":                 s.Target,
+		"backoff":                s.Backoff,
+		"duration":               s.Duration.String(),
+		"clients":                s.Clients,
+		"established":            s.Established,
+		"disconnected":           s.Disconnected,
+		"reconnected":            s.Reconnected,
+		"gave_up":                s.GaveUp,
+		"success_rate":           s.SuccessRate,
+		"attempts":               s.Attempts,
+		"attempts_per_reconnect": s.AttemptsPerReconnect,
+		"attempt_distribution":   attempts,
+		"reconnect":              latencyToMap(s.Reconnect),
+		"handshake":              latencyToMap(s.Handshake),
+		"rounds":                 rounds,
+		"failures":               failures,
+	}
+}
+
+// stormRound 单次风暴进行中的统计
+type stormRound struct {
+	start       time.Time
+	attempts    atomic.Int64
+	reconnected atomic.Int64
+	gaveUp      atomic.Int64
+	lastMutex   sync.Mutex
+	last        time.Time
+	reconnect   *metrics.LatencyTracker
+}
+
+// StormRunner 重连风暴测试执行器
+// 保持一批长连接，同时断开其中一部分，按退避策略重连，衡量目标在重连洪峰下的恢复能力
+type StormRunner struct {
+	dialer    Dialer
+	config    *StormConfig
+	collector *metrics.BaseCollector[map[string]interface{}]
+
+	connsMutex sync.Mutex
+	conns      []io.Closer // 按客户端编号保存连接，断开期间为nil
+
+	storm       atomic.Int64
+	attempts    atomic.Int64
+	reconnected atomic.Int64
+	failed      atomic.Int64
+	connected   atomic.Int64
+
+	reconnect *metrics.LatencyTracker
+	handshake *metrics.LatencyTracker
+
+	statsMutex sync.Mutex
+	attemptsAt map[int]int64 // 成功时的尝试次数分布
+	failures   map[string]int64
+}
+
+// NewStormRunner 创建重连风暴测试执行器，collector可为nil
+func NewStormRunner(dialer Dialer, config *StormConfig, collector *metrics.BaseCollector[map[string]interface{}]) *StormRunner {
+	return &StormRunner{
+		dialer:     dialer,
+		config:     config,
+		collector:  collector,
+		conns:      make([]io.Closer, config.Clients),
+		reconnect:  newStormTracker(),
+		handshake:  newStormTracker(),
+		attemptsAt: make(map[int]int64),
+		failures:   make(map[string]int64),
+	}
+}
+
+func newStormTracker() *metrics.LatencyTracker {
+	return metrics.NewLatencyTracker(metrics.LatencyConfig{
+		SamplingRate: 1.0,
+	})
+}
+
+// Run 建立客户端连接并执行配置的风暴次数，onSample每秒回调一次
+func (r *StormRunner) Run(ctx context.Context, onSample func(StormSample)) (*StormStats, error) {
+	if err := r.config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid reconnect storm config: %w", err)
+	}
+	defer r.closeAll()
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	start := time.Now()
+	samples := make(chan []StormSample, 1)
+	go func() {
+		samples <- r.sample(runCtx, start, onSample)
+	}()
+
+	established, cause := r.establish(runCtx)
+	if established == 0 {
+		cancel()
+		<-samples
+		return nil, fmt.Errorf("no client could connect to %s (%s)", r.dialer.Target(), cause)
+	}
+
+	var rounds []StormRound
+	for storm := 1; storm <= r.config.Storms && runCtx.Err() == nil; storm++ {
+		select {
+		case <-runCtx.Done():
+		case <-time.After(r.config.Interval):
+			rounds = append(rounds, r.runStorm(runCtx, storm))
+		}
+	}
+	duration := time.Since(start)
+	cancel()
+
+	stats := r.stats(established, duration, rounds, <-samples)
+	if ctx.Err() != nil {
+		return stats, ctx.Err()
+	}
+	return stats, nil
+}
+
+// establish 以配置的并发建立全部客户端连接，返回建立成功的连接数与最常见的失败原因
+func (r *StormRunner) establish(ctx context.Context) (int, string) {
+	indexes := make(chan int)
+	var established atomic.Int64
+	failures := make(map[string]int64)
+	var mutex sync.Mutex
+
+	var wg sync.WaitGroup
+	for i := 0; i < min(r.config.Concurrency, r.config.Clients); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				conn, _, err := r.dialer.Dial(ctx)
+				if err != nil {
+					mutex.Lock()
+					failures[ClassifyError(err)]++
+					mutex.Unlock()
+					continue
+				}
+				r.setConn(index, conn)
+				r.connected.Add(1)
+				established.Add(1)
+			}
+		}()
+	}
+	for i := 0; i < r.config.Clients && ctx.Err() == nil; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	cause := ""
+	if causes := sortCauses(failures); len(causes) > 0 {
+		cause = causes[0]
+	}
+	return int(established.Load()), cause
+}
+
+// runStorm 同时断开一部分已连接的客户端，等待它们按退避策略重连
+func (r *StormRunner) runStorm(ctx context.Context, storm int) StormRound {
+	r.storm.Store(int64(storm))
+	round := &stormRound{reconnect: newStormTracker()}
+
+	// 以定时取消而非截止时间结束重连：截止时间会传递给在途握手，使其以超时失败
+	roundCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := time.AfterFunc(r.config.Timeout, cancel)
+	defer stop.Stop()
+
+	// 先断开全部选中的客户端，再开始重连，使断开尽量同时发生
+	victims := r.pickVictims()
+	disconnectedAt := make([]time.Time, len(victims))
+	round.start = time.Now()
+	for i, index := range victims {
+		r.takeConn(index).Close()
+		r.connected.Add(-1)
+		disconnectedAt[i] = time.Now()
+	}
+
+	var wg sync.WaitGroup
+	for i, index := range victims {
+		wg.Add(1)
+		go func(index int, disconnectedAt time.Time) {
+			defer wg.Done()
+			r.reconnectClient(roundCtx, index, disconnectedAt, round)
+		}(index, disconnectedAt[i])
+	}
+	wg.Wait()
+
+	result := StormRound{
+		Storm:        storm,
+		Disconnected: len(victims),
+		Reconnected:  round.reconnected.Load(),
+		GaveUp:       round.gaveUp.Load(),
+		Attempts:     round.attempts.Load(),
+		Reconnect:    round.reconnect.GetMetrics(),
+	}
+	if !round.last.IsZero() {
+		result.RecoveryTime = round.last.Sub(round.start)
+	}
+	return result
+}
+
+// pickVictims 随机选择本次风暴断开的客户端
+func (r *StormRunner) pickVictims() []int {
+	r.connsMutex.Lock()
+	defer r.connsMutex.Unlock()
+
+	var connected []int
+	for index, conn := range r.conns {
+		if conn != nil {
+			connected = append(connected, index)
+		}
+	}
+	rand.Shuffle(len(connected), func(i, j int) { connected[i], connected[j] = connected[j], connected[i] })
+	count := int(math.Round(float64(len(connected)) * r.config.Fraction))
+	if count == 0 && len(connected) > 0 {
+		count = 1
+	}
+	return connected[:count]
+}
+
+// reconnectClient 按退避策略重连一个客户端，直到成功、用尽重连次数或超时
+func (r *StormRunner) reconnectClient(ctx context.Context, index int, disconnectedAt time.Time, round *stormRound) {
+	for attempt := 0; ; attempt++ {
+		if r.config.Backoff.MaxAttempts > 0 && attempt >= r.config.Backoff.MaxAttempts {
+			r.giveUp(round, disconnectedAt, "max_attempts")
+			return
+		}
+
+		timer := time.NewTimer(r.config.Backoff.Delay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			r.giveUp(round, disconnectedAt, "timeout")
+			return
+		case <-timer.C:
+		}
+
+		conn, timing, err := r.dialer.Dial(ctx)
+		// 重连超时时被取消的握手不计为失败尝试
+		if err != nil && ctx.Err() != nil {
+			r.giveUp(round, disconnectedAt, "timeout")
+			return
+		}
+		r.attempts.Add(1)
+		round.attempts.Add(1)
+		if err != nil {
+			r.failed.Add(1)
+			r.statsMutex.Lock()
+			r.failures[ClassifyError(err)]++
+			r.statsMutex.Unlock()
+			continue
+		}
+
+		now := time.Now()
+		elapsed := now.Sub(disconnectedAt)
+		r.setConn(index, conn)
+		r.connected.Add(1)
+		r.reconnected.Add(1)
+		round.reconnected.Add(1)
+		round.reconnect.Record(elapsed)
+		round.lastMutex.Lock()
+		if now.After(round.last) {
+			round.last = now
+		}
+		round.lastMutex.Unlock()
+		r.reconnect.Record(elapsed)
+		r.handshake.Record(timing.Total)
+		r.statsMutex.Lock()
+		r.attemptsAt[attempt+1]++
+		r.statsMutex.Unlock()
+		r.record(true, elapsed, nil, attempt+1, "")
+		return
+	}
+}
+
+// giveUp 记录放弃重连的客户端
+func (r *StormRunner) giveUp(round *stormRound, disconnectedAt time.Time, reason string) {
+	round.gaveUp.Add(1)
+	r.record(false, time.Since(disconnectedAt), fmt.Errorf("gave up reconnecting: %s", reason), 0, reason)
+}
+
+// record 将重连结果写入收集器，断开到重连成功（或放弃）的耗时即操作耗时
+func (r *StormRunner) record(success bool, duration time.Duration, err error, attempts int, reason string) {
+	if r.collector == nil {
+		return
+	}
+	metadata := map[string]interface{}{"operation_type": "reconnect"}
+	if attempts > 0 {
+		metadata["attempts"] = attempts
+	}
+	if reason != "" {
+		metadata["failure_cause"] = reason
+	}
+	r.collector.Record(&interfaces.OperationResult{
+		Success:  success,
+		Duration: duration,
+		Error:    err,
+		Metadata: metadata,
+	})
+}
+
+// setConn 保存客户端连接
+func (r *StormRunner) setConn(index int, conn io.Closer) {
+	r.connsMutex.Lock()
+	r.conns[index] = conn
+	r.connsMutex.Unlock()
+}
+
+// takeConn 取出客户端连接并置为断开
+func (r *StormRunner) takeConn(index int) io.Closer {
+	r.connsMutex.Lock()
+	defer r.connsMutex.Unlock()
+	conn := r.conns[index]
+	r.conns[index] = nil
+	return conn
+}
+
+// closeAll 关闭全部客户端连接
+func (r *StormRunner) closeAll() {
+	r.connsMutex.Lock()
+	defer r.connsMutex.Unlock()
+	for index, conn := range r.conns {
+		if conn != nil {
+			conn.Close()
+			r.conns[index] = nil
+		}
+	}
+	r.connected.Store(0)
+}
+
+// sample 每秒统计一次增量，直到测试结束
+func (r *StormRunner) sample(ctx context.Context, start time.Time, onSample func(StormSample)) []StormSample {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	var samples []StormSample
+	var lastAttempts, lastReconnected, lastFailed int64
+	for {
+		select {
+		case <-ctx.Done():
+			return samples
+		case now := <-ticker.C:
+			attempts, reconnected, failed := r.attempts.Load(), r.reconnected.Load(), r.failed.Load()
+			sample := StormSample{
+				Elapsed:     now.Sub(start).Round(time.Second),
+				Storm:       int(r.storm.Load()),
+				Attempts:    attempts - lastAttempts,
+				Reconnected: reconnected - lastReconnected,
+				Failed:      failed - lastFailed,
+				Connected:   r.connected.Load(),
+			}
+			lastAttempts, lastReconnected, lastFailed = attempts, reconnected, failed
+			samples = append(samples, sample)
+			if onSample != nil {
+				onSample(sample)
+			}
+		}
+	}
+}
+
+// stats 汇总测试结果
+func (r *StormRunner) stats(established int, duration time.Duration, rounds []StormRound, samples []StormSample) *StormStats {
+	stats := &StormStats{
+		Transport:   r.dialer.Name(),
+		Target:      r.dialer.Target(),
+		Backoff:     r.config.Backoff.String(),
+		Duration:    duration,
+		Clients:     r.config.Clients,
+		Established: established,
+		Attempts:    r.attempts.Load(),
+		Reconnected: r.reconnected.Load(),
+		Reconnect:   r.reconnect.GetMetrics(),
+		Handshake:   r.handshake.GetMetrics(),
+		Rounds:      rounds,
+		Failures:    make(map[string]int64),
+		Samples:     samples,
+	}
+	for _, round := range rounds {
+		stats.Disconnected += int64(round.Disconnected)
+		stats.GaveUp += round.GaveUp
+	}
+
+	r.statsMutex.Lock()
+	for cause, count := range r.failures {
+		stats.Failures[cause] = count
+	}
+	for attempts, count := range r.attemptsAt {
+		stats.AttemptDistribution = append(stats.AttemptDistribution, StormAttemptBucket{Attempts: attempts, Count: count})
+	}
+	r.statsMutex.Unlock()
+	sort.Slice(stats.AttemptDistribution, func(i, j int) bool {
+		return stats.AttemptDistribution[i].Attempts < stats.AttemptDistribution[j].Attempts
+	})
+
+	if stats.Disconnected > 0 {
+		stats.SuccessRate = float64(stats.Reconnected) / float64(stats.Disconnected) * 100
+	}
+	if stats.Reconnected > 0 {
+		// 放弃重连的客户端的尝试也计入，反映的是每次成功重连付出的总尝试数
+		stats.AttemptsPerReconnect = float64(stats.Attempts) / float64(stats.Reconnected)
+	}
+	return stats
+}
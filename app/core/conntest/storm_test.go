@@ -0,0 +1,146 @@
+package conntest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// startHoldingWebSocketServer 启动保持连接直到客户端断开的WebSocket服务
+func startHoldingWebSocketServer(t *testing.T) string {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return "ws://" + strings.TrimPrefix(server.URL, "http://")
+}
+
+// limitedDialer 前limit次建连成功，之后全部失败
+type limitedDialer struct {
+	Dialer
+	limit int64
+	dials atomic.Int64
+}
+
+func (d *limitedDialer) Dial(ctx context.Context) (io.Closer, DialTiming, error) {
+	if d.dials.Add(1) > d.limit {
+		return nil, DialTiming{}, fmt.Errorf("dial: %w", syscall.ECONNREFUSED)
+	}
+	return d.Dialer.Dial(ctx)
+}
+
+func TestStormRunner_WebSocketReconnect(t *testing.T) {
+	dialer, err := NewDialer(startHoldingWebSocketServer(t), DialerOptions{})
+	if err != nil {
+		t.Fatalf("NewDialer: %v", err)
+	}
+	stats, err := NewStormRunner(dialer, &StormConfig{
+		Clients:     20,
+		Concurrency: 5,
+		Fraction:    0.5,
+		Storms:      2,
+		Interval:    50 * time.Millisecond,
+		Timeout:     5 * time.Second,
+		Backoff:     BackoffPolicy{Initial: 10 * time.Millisecond, Max: 40 * time.Millisecond, Multiplier: 2, Jitter: JitterNone},
+	}, nil).Run(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if stats.Established != 20 || stats.Disconnected != 20 || len(stats.Rounds) != 2 {
+		t.Fatalf("established = %d, disconnected = %d, rounds = %d", stats.Established, stats.Disconnected, len(stats.Rounds))
+	}
+	if stats.Reconnected != 20 || stats.GaveUp != 0 || stats.SuccessRate != 100 {
+		t.Errorf("reconnected = %d, gave up = %d, success rate = %v", stats.Reconnected, stats.GaveUp, stats.SuccessRate)
+	}
+	if len(stats.AttemptDistribution) != 1 || stats.AttemptDistribution[0] != (StormAttemptBucket{Attempts: 1, Count: 20}) {
+		t.Errorf("attempt distribution = %v", stats.AttemptDistribution)
+	}
+	// 无抖动时首次重连至少等待初始退避
+	if stats.Reconnect.Min < 10*time.Millisecond || stats.Handshake.P50 <= 0 {
+		t.Errorf("reconnect min = %v, handshake p50 = %v", stats.Reconnect.Min, stats.Handshake.P50)
+	}
+	for _, round := range stats.Rounds {
+		if round.Disconnected != 10 || round.Reconnected != 10 || round.RecoveryTime <= 0 {
+			t.Errorf("round = %+v", round)
+		}
+	}
+}
+
+func TestStormRunner_GiveUp(t *testing.T) {
+	dialer, err := NewDialer(startHoldingWebSocketServer(t), DialerOptions{})
+	if err != nil {
+		t.Fatalf("NewDialer: %v", err)
+	}
+	stats, err := NewStormRunner(&limitedDialer{Dialer: dialer, limit: 4}, &StormConfig{
+		Clients:     4,
+		Concurrency: 4,
+		Fraction:    1,
+		Storms:      1,
+		Timeout:     5 * time.Second,
+		Backoff:     BackoffPolicy{Initial: time.Millisecond, Max: time.Millisecond, Multiplier: 1, Jitter: JitterNone, MaxAttempts: 3},
+	}, nil).Run(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if stats.Disconnected != 4 || stats.Reconnected != 0 || stats.GaveUp != 4 {
+		t.Errorf("disconnected = %d, reconnected = %d, gave up = %d", stats.Disconnected, stats.Reconnected, stats.GaveUp)
+	}
+	if stats.Attempts != 12 || stats.Failures["refused"] != 12 {
+		t.Errorf("attempts = %d, failures = %v", stats.Attempts, stats.Failures)
+	}
+}
+
+func TestBackoffPolicy_Delay(t *testing.T) {
+	policy := BackoffPolicy{Initial: 100 * time.Millisecond, Max: time.Second, Multiplier: 2, Jitter: JitterNone}
+	for attempt, want := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		if got := policy.Delay(attempt); got != want*time.Millisecond {
+			t.Errorf("Delay(%d) = %v, want %v", attempt, got, want*time.Millisecond)
+		}
+	}
+
+	policy.Jitter = JitterEqual
+	for i := 0; i < 100; i++ {
+		if got := policy.Delay(2); got < 200*time.Millisecond || got > 400*time.Millisecond {
+			t.Fatalf("equal jitter Delay(2) = %v, want within [200ms, 400ms]", got)
+		}
+	}
+	policy.Jitter = JitterFull
+	for i := 0; i < 100; i++ {
+		if got := policy.Delay(2); got < 0 || got > 400*time.Millisecond {
+			t.Fatalf("full jitter Delay(2) = %v, want within [0, 400ms]", got)
+		}
+	}
+
+	invalid := []BackoffPolicy{
+		{Initial: 0, Max: time.Second, Multiplier: 2, Jitter: JitterNone},
+		{Initial: time.Second, Max: time.Millisecond, Multiplier: 2, Jitter: JitterNone},
+		{Initial: time.Second, Max: time.Second, Multiplier: 0.5, Jitter: JitterNone},
+		{Initial: time.Second, Max: time.Second, Multiplier: 2, Jitter: "random"},
+	}
+	for _, policy := range invalid {
+		if err := policy.Validate(); err == nil {
+			t.Errorf("Validate(%+v) succeeded, want error", policy)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package distributed
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"abc-runner/app/core/metrics"
+	"abc-runner/config"
+)
+
+// Agent 分布式测试worker，接收协调器的运行请求并在本地执行协议命令
+// 所有请求需携带共享令牌，且只执行允许列表中的命令
+type Agent struct {
+	id       string
+	token    string
+	allowed  map[string]bool
+	executor CommandExecutor
+	busy     int32
+}
+
+// NewAgent 创建agent，token为空时拒绝所有请求
+func NewAgent(id, token string, executor CommandExecutor) *Agent {
+	if id == "" {
+		id = defaultAgentID()
+	}
+	agent := &Agent{
+		id:       id,
+		token:    token,
+		executor: executor,
+	}
+	agent.SetAllowedCommands(DefaultAllowedCommands)
+	return agent
+}
+
+// SetAllowedCommands 设置允许执行的命令列表
+func (a *Agent) SetAllowedCommands(commands []string) {
+	a.allowed = make(map[string]bool, len(commands))
+	for _, command := range commands {
+		a.allowed[strings.ToLower(command)] = true
+	}
+}
+
+// ID 获取agent标识
+func (a *Agent) ID() string {
+	return a.id
+}
+
+// Handler 获取agent的HTTP处理器
+func (a *Agent) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(HealthPath, a.authorize(a.handleHealth))
+	mux.HandleFunc(RunPath, a.authorize(a.handleRun))
+	return mux
+}
+
+// authorize 校验请求携带的共享令牌
+func (a *Agent) authorize(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if a.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(a.token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// ListenAndServe 在指定地址上提供服务，直到ctx取消
+func (a *Agent) ListenAndServe(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	return a.Serve(ctx, listener)
+}
+
+// Serve 在给定监听器上提供服务，直到ctx取消
+func (a *Agent) Serve(ctx context.Context, listener net.Listener) error {
+	server := &http.Server{Handler: a.Handler()}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- server.Serve(listener)
+	}()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	case err := <-errChan:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	}
+}
+
+// handleHealth 健康检查
+func (a *Agent) handleHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(HealthResponse{
+		Status:  "ok",
+		AgentID: a.id,
+		Version: config.AppVersion,
+		Busy:    atomic.LoadInt32(&a.busy) == 1,
+	})
+}
+
+// handleRun 执行运行请求，并以NDJSON流返回中间快照和最终快照
+func (a *Agent) handleRun(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req RunRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid run request: %v", err), http.StatusBadRequest)
+		return
+	}
+	if req.Command == "" {
+		http.Error(w, "command is required", http.StatusBadRequest)
+		return
+	}
+	if !a.allowed[strings.ToLower(req.Command)] {
+		http.Error(w, fmt.Sprintf("command %q is not allowed on this agent", req.Command), http.StatusForbidden)
+		return
+	}
+
+	// 同一时间只执行一个运行请求，避免多个测试互相干扰
+	if !atomic.CompareAndSwapInt32(&a.busy, 0, 1) {
+		http.Error(w, "agent is busy", http.StatusConflict)
+		return
+	}
+	defer atomic.StoreInt32(&a.busy, 0)
+
+	log.Printf("📥 Agent %s received run %s: %s %v", a.id, req.RunID, req.Command, req.Args)
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	stream := &streamSink{agentID: a.id, writer: w, encoder: json.NewEncoder(w)}
+	if flusher, ok := w.(http.Flusher); ok {
+		stream.flusher = flusher
+	}
+
+	ctx := metrics.WithSnapshotSink(r.Context(), stream)
+	err := a.executor(ctx, req.Command, req.Args)
+
+	switch {
+	case err != nil:
+		stream.send(StreamMessage{Type: MessageError, AgentID: a.id, Error: err.Error()})
+	case !stream.finished():
+		stream.send(StreamMessage{Type: MessageError, AgentID: a.id, Error: "command finished without producing a metrics snapshot"})
+	}
+
+	log.Printf("📤 Agent %s finished run %s", a.id, req.RunID)
+}
+
+// streamSink 将快照以NDJSON写回协调器的接收器
+type streamSink struct {
+	agentID string
+	writer  http.ResponseWriter
+	encoder *json.Encoder
+	flusher http.Flusher
+	final   bool
+	mutex   sync.Mutex
+}
+
+// OnProgress 发送中间快照
+func (s *streamSink) OnProgress(snapshot *metrics.DefaultMetricsSnapshot) {
+	s.send(StreamMessage{Type: MessageProgress, AgentID: s.agentID, Snapshot: sanitizeSnapshot(snapshot)})
+}
+
+// OnFinal 发送最终快照
+func (s *streamSink) OnFinal(snapshot *metrics.DefaultMetricsSnapshot) {
+	s.mutex.Lock()
+	s.final = true
+	s.mutex.Unlock()
+	s.send(StreamMessage{Type: MessageFinal, AgentID: s.agentID, Snapshot: sanitizeSnapshot(snapshot)})
+}
+
+// finished 是否已发送最终快照
+func (s *streamSink) finished() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.final
+}
+
+// send 写入一条消息
+func (s *streamSink) send(message StreamMessage) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := s.encoder.Encode(message); err != nil {
+		log.Printf("Warning: failed to stream message to coordinator: %v", err)
+		return
+	}
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+}
+
+// sanitizeSnapshot 复制快照并只保留可JSON序列化的协议数据
+func sanitizeSnapshot(snapshot *metrics.DefaultMetricsSnapshot) *metrics.DefaultMetricsSnapshot {
+	if snapshot == nil {
+		return nil
+	}
+	copied := *snapshot
+	copied.Protocol = make(map[string]interface{}, len(snapshot.Protocol))
+	for key, value := range snapshot.Protocol {
+		if _, err := json.Marshal(value); err == nil {
+			copied.Protocol[key] = value
+		}
+	}
+	return &copied
+}
+
+// defaultAgentID 默认使用主机名作为agent标识
+func defaultAgentID() string {
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		return hostname
+	}
+	return fmt.Sprintf("agent-%d", time.Now().UnixNano())
+}
@@ -0,0 +1,273 @@
+package distributed
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"abc-runner/app/core/metrics"
+)
+
+// ProgressFunc 协调器汇总中间进度的回调
+type ProgressFunc func(merged *metrics.DefaultMetricsSnapshot, reporting int)
+
+// Coordinator 分布式测试协调器，负责分配操作预算并汇总各agent的指标
+type Coordinator struct {
+	agents     []string
+	token      string
+	client     *http.Client
+	onProgress ProgressFunc
+}
+
+// NewCoordinator 创建协调器，agents为agent地址列表（host:port或完整URL），token为agent共享令牌
+func NewCoordinator(agents []string, token string) *Coordinator {
+	return &Coordinator{
+		agents: agents,
+		token:  token,
+		// 运行请求为长连接流式响应，不设置整体超时，由ctx控制
+		client: &http.Client{},
+	}
+}
+
+// SetProgressFunc 设置中间进度回调
+func (c *Coordinator) SetProgressFunc(fn ProgressFunc) {
+	c.onProgress = fn
+}
+
+// Agents 获取agent地址列表
+func (c *Coordinator) Agents() []string {
+	return c.agents
+}
+
+// CheckAgents 检查所有agent是否可用
+func (c *Coordinator) CheckAgents(ctx context.Context) error {
+	for _, agent := range c.agents {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, agentURL(agent, HealthPath), nil)
+		if err != nil {
+			return fmt.Errorf("invalid agent address %s: %w", agent, err)
+		}
+		req.Header.Set("Authorization", "Bearer "+c.token)
+
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("agent %s unreachable: %w", agent, err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return fmt.Errorf("agent %s rejected health check: %s", agent, resp.Status)
+		}
+
+		var health HealthResponse
+		err = json.NewDecoder(resp.Body).Decode(&health)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("agent %s returned invalid health response: %w", agent, err)
+		}
+		if health.Busy {
+			return fmt.Errorf("agent %s (%s) is busy running another test", agent, health.AgentID)
+		}
+	}
+	return nil
+}
+
+// Run 将命令分发到所有agent执行，返回各agent结果和合并后的快照
+// 命令参数中的 -n 操作总数按agent数量拆分，其余参数原样下发
+func (c *Coordinator) Run(ctx context.Context, command string, args []string) ([]*AgentResult, *metrics.DefaultMetricsSnapshot, error) {
+	if len(c.agents) == 0 {
+		return nil, nil, fmt.Errorf("no agents configured")
+	}
+
+	runID := fmt.Sprintf("run-%d", time.Now().UnixNano())
+	agentArgs := SplitOperationBudget(args, len(c.agents))
+
+	results := make([]*AgentResult, len(c.agents))
+	progress := newProgressAggregator(len(c.agents), c.onProgress)
+
+	var wg sync.WaitGroup
+	for i, agent := range c.agents {
+		wg.Add(1)
+		go func(index int, address string) {
+			defer wg.Done()
+			results[index] = c.runOnAgent(ctx, address, RunRequest{
+				RunID:   runID,
+				Command: command,
+				Args:    agentArgs[index],
+			}, func(snapshot *metrics.DefaultMetricsSnapshot) {
+				progress.update(index, snapshot)
+			})
+		}(i, agent)
+	}
+	wg.Wait()
+
+	var snapshots []*metrics.DefaultMetricsSnapshot
+	var errs []string
+	for _, result := range results {
+		if result.Error != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", result.Address, result.Error))
+			continue
+		}
+		snapshots = append(snapshots, result.Snapshot)
+	}
+
+	if len(snapshots) == 0 {
+		return results, nil, fmt.Errorf("all agents failed: %s", strings.Join(errs, "; "))
+	}
+
+	return results, metrics.MergeSnapshots(snapshots...), nil
+}
+
+// runOnAgent 在单个agent上执行并读取NDJSON结果流
+func (c *Coordinator) runOnAgent(ctx context.Context, address string, runReq RunRequest, onProgress func(*metrics.DefaultMetricsSnapshot)) *AgentResult {
+	result := &AgentResult{Address: address}
+	start := time.Now()
+	defer func() { result.Duration = time.Since(start) }()
+
+	body, err := json.Marshal(runReq)
+	if err != nil {
+		result.Error = fmt.Errorf("failed to encode run request: %w", err)
+		return result
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, agentURL(address, RunPath), bytes.NewReader(body))
+	if err != nil {
+		result.Error = fmt.Errorf("failed to create run request: %w", err)
+		return result
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		result.Error = fmt.Errorf("run request failed: %w", err)
+		return result
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var buf bytes.Buffer
+		buf.ReadFrom(resp.Body)
+		result.Error = fmt.Errorf("agent returned %s: %s", resp.Status, strings.TrimSpace(buf.String()))
+		return result
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var message StreamMessage
+		if err := json.Unmarshal(scanner.Bytes(), &message); err != nil {
+			result.Error = fmt.Errorf("invalid stream message: %w", err)
+			return result
+		}
+		if message.AgentID != "" {
+			result.AgentID = message.AgentID
+		}
+
+		switch message.Type {
+		case MessageProgress:
+			if message.Snapshot != nil && onProgress != nil {
+				onProgress(message.Snapshot)
+			}
+		case MessageFinal:
+			result.Snapshot = message.Snapshot
+			if onProgress != nil {
+				onProgress(message.Snapshot)
+			}
+		case MessageError:
+			result.Error = fmt.Errorf("agent error: %s", message.Error)
+			return result
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		result.Error = fmt.Errorf("failed to read agent stream: %w", err)
+		return result
+	}
+	if result.Snapshot == nil {
+		result.Error = fmt.Errorf("agent stream ended without final snapshot")
+	}
+	return result
+}
+
+// SplitOperationBudget 按agent数量拆分 -n 操作总数，余数分配给前面的agent
+// 未指定 -n 时各agent使用相同参数（如按持续时间运行的测试）
+func SplitOperationBudget(args []string, agents int) [][]string {
+	result := make([][]string, agents)
+
+	totalIndex := -1
+	total := 0
+	for i := 0; i < len(args)-1; i++ {
+		if args[i] == "-n" {
+			if n, err := strconv.Atoi(args[i+1]); err == nil {
+				totalIndex = i + 1
+				total = n
+			}
+		}
+	}
+
+	for i := 0; i < agents; i++ {
+		agentArgs := make([]string, len(args))
+		copy(agentArgs, args)
+		if totalIndex >= 0 {
+			share := total / agents
+			if i < total%agents {
+				share++
+			}
+			agentArgs[totalIndex] = strconv.Itoa(share)
+		}
+		result[i] = agentArgs
+	}
+	return result
+}
+
+// agentURL 构造agent接口地址
+func agentURL(address, path string) string {
+	if !strings.HasPrefix(address, "http://") && !strings.HasPrefix(address, "https://") {
+		if !strings.Contains(address, ":") {
+			address = fmt.Sprintf("%s:%d", address, DefaultAgentPort)
+		}
+		address = "http://" + address
+	}
+	return strings.TrimRight(address, "/") + path
+}
+
+// progressAggregator 汇总各agent的最新中间快照
+type progressAggregator struct {
+	latest   []*metrics.DefaultMetricsSnapshot
+	callback ProgressFunc
+	mutex    sync.Mutex
+}
+
+// newProgressAggregator 创建进度汇总器
+func newProgressAggregator(agents int, callback ProgressFunc) *progressAggregator {
+	return &progressAggregator{
+		latest:   make([]*metrics.DefaultMetricsSnapshot, agents),
+		callback: callback,
+	}
+}
+
+// update 更新某个agent的最新快照并回调合并结果
+func (p *progressAggregator) update(index int, snapshot *metrics.DefaultMetricsSnapshot) {
+	if p.callback == nil || snapshot == nil {
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.latest[index] = snapshot
+	reporting := 0
+	for _, s := range p.latest {
+		if s != nil {
+			reporting++
+		}
+	}
+	p.callback(metrics.MergeSnapshots(p.latest...), reporting)
+}
@@ -0,0 +1,155 @@
+package distributed
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"abc-runner/app/core/metrics"
+)
+
+func TestSplitOperationBudget(t *testing.T) {
+	split := SplitOperationBudget([]string{"--host", "localhost", "-n", "10", "-c", "4"}, 3)
+
+	expected := []string{"4", "3", "3"}
+	for i, args := range split {
+		if args[3] != expected[i] {
+			t.Errorf("agent %d: expected -n %s, got %s", i, expected[i], args[3])
+		}
+		if args[5] != "4" {
+			t.Errorf("agent %d: concurrency should not be split, got %s", i, args[5])
+		}
+	}
+
+	// 未指定 -n 时参数保持不变
+	split = SplitOperationBudget([]string{"-c", "2"}, 2)
+	if len(split) != 2 || split[1][1] != "2" {
+		t.Errorf("unexpected split without -n: %v", split)
+	}
+}
+
+func TestCoordinator_RunMergesAgentSnapshots(t *testing.T) {
+	// 模拟agent：按 -n 生成操作数，延迟因agent而异
+	newFakeAgent := func(id string, latency time.Duration) *httptest.Server {
+		agent := NewAgent(id, "test-token", func(ctx context.Context, command string, args []string) error {
+			if command != "redis" {
+				return fmt.Errorf("unexpected command %s", command)
+			}
+			total, _ := strconv.Atoi(args[1])
+
+			sink, ok := metrics.SnapshotSinkFromContext(ctx)
+			if !ok {
+				return fmt.Errorf("snapshot sink missing")
+			}
+
+			snapshot := &metrics.DefaultMetricsSnapshot{Protocol: map[string]interface{}{"protocol": command}}
+			snapshot.Core.Operations.Total = int64(total)
+			snapshot.Core.Operations.Success = int64(total)
+			snapshot.Core.Throughput.RPS = 100
+			snapshot.Core.Latency.Min = latency
+			snapshot.Core.Latency.Max = latency
+			snapshot.Core.Latency.Average = latency
+			snapshot.Core.Latency.P99 = latency
+			snapshot.Core.Duration = time.Second
+
+			sink.OnProgress(snapshot)
+			sink.OnFinal(snapshot)
+			return nil
+		})
+		return httptest.NewServer(agent.Handler())
+	}
+
+	agentA := newFakeAgent("a", 10*time.Millisecond)
+	defer agentA.Close()
+	agentB := newFakeAgent("b", 20*time.Millisecond)
+	defer agentB.Close()
+
+	coordinator := NewCoordinator([]string{agentA.URL, agentB.URL}, "test-token")
+	if err := coordinator.CheckAgents(context.Background()); err != nil {
+		t.Fatalf("agent check failed: %v", err)
+	}
+
+	progressCalls := 0
+	coordinator.SetProgressFunc(func(merged *metrics.DefaultMetricsSnapshot, reporting int) {
+		progressCalls++
+	})
+
+	results, merged, err := coordinator.Run(context.Background(), "redis", []string{"-n", "30"})
+	if err != nil {
+		t.Fatalf("distributed run failed: %v", err)
+	}
+
+	if len(results) != 2 || results[0].AgentID != "a" || results[1].AgentID != "b" {
+		t.Fatalf("unexpected agent results: %+v", results)
+	}
+	if merged.Core.Operations.Total != 30 {
+		t.Errorf("expected 30 merged operations, got %d", merged.Core.Operations.Total)
+	}
+	if merged.Core.Throughput.RPS != 200 {
+		t.Errorf("expected merged RPS 200, got %.2f", merged.Core.Throughput.RPS)
+	}
+	if merged.Core.Latency.Min != 10*time.Millisecond || merged.Core.Latency.Max != 20*time.Millisecond {
+		t.Errorf("unexpected merged latency range: min=%v max=%v", merged.Core.Latency.Min, merged.Core.Latency.Max)
+	}
+	// 15个操作@10ms + 15个操作@20ms
+	if merged.Core.Latency.Average != 15*time.Millisecond {
+		t.Errorf("expected weighted average 15ms, got %v", merged.Core.Latency.Average)
+	}
+	if progressCalls == 0 {
+		t.Error("expected progress callbacks")
+	}
+}
+
+func TestAgent_RejectsUnauthorizedAndDisallowedCommands(t *testing.T) {
+	executed := false
+	agent := NewAgent("a", "test-token", func(ctx context.Context, command string, args []string) error {
+		executed = true
+		return nil
+	})
+	server := httptest.NewServer(agent.Handler())
+	defer server.Close()
+
+	if err := NewCoordinator([]string{server.URL}, "wrong").CheckAgents(context.Background()); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected health check to be rejected with 401, got %v", err)
+	}
+
+	results, _, err := NewCoordinator([]string{server.URL}, "wrong").Run(context.Background(), "redis", nil)
+	if err == nil || !strings.Contains(results[0].Error.Error(), "401") {
+		t.Errorf("expected run with wrong token to be rejected with 401, got %v", err)
+	}
+
+	results, _, err = NewCoordinator([]string{server.URL}, "test-token").Run(context.Background(), "server", []string{"--backend", "redis"})
+	if err == nil || !strings.Contains(results[0].Error.Error(), "403") {
+		t.Errorf("expected non-benchmark command to be rejected with 403, got %v", err)
+	}
+
+	if executed {
+		t.Error("executor must not run for rejected requests")
+	}
+}
+
+func TestAgent_AllowedCommandsRequireFullNames(t *testing.T) {
+	var executed []string
+	agent := NewAgent("a", "test-token", func(ctx context.Context, command string, args []string) error {
+		executed = append(executed, command)
+		return nil
+	})
+	server := httptest.NewServer(agent.Handler())
+	defer server.Close()
+	coordinator := NewCoordinator([]string{server.URL}, "test-token")
+
+	// 别名不在允许列表中，即使本地路由能解析也会被拒绝
+	for _, alias := range []string{"r", "h", "https", "pg", "mongo", "es", "ws"} {
+		results, _, err := coordinator.Run(context.Background(), alias, nil)
+		if err == nil || !strings.Contains(results[0].Error.Error(), "403") {
+			t.Errorf("expected alias %q to be rejected with 403, got %v", alias, err)
+		}
+	}
+	if len(executed) != 0 {
+		t.Errorf("executor must not run for aliases, ran %v", executed)
+	}
+}
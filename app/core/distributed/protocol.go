@@ -0,0 +1,74 @@
+package distributed
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"abc-runner/app/core/metrics"
+)
+
+// 协调器与agent之间的HTTP接口路径
+const (
+	HealthPath = "/v1/health"
+	RunPath    = "/v1/run"
+)
+
+// DefaultAgentPort agent默认监听端口
+const DefaultAgentPort = 7070
+
+// DefaultAgentListen agent默认监听地址，仅绑定本机回环，对外暴露需显式指定 --listen
+var DefaultAgentListen = fmt.Sprintf("127.0.0.1:%d", DefaultAgentPort)
+
+// TokenEnv 共享令牌的环境变量，agent和协调器在未指定 --token 时读取
+const TokenEnv = "ABC_RUNNER_AGENT_TOKEN"
+
+// DefaultAllowedCommands agent默认允许执行的基准测试命令
+// 不包含agent、coordinator、server、config等非测试命令，协议别名需使用完整名称
+var DefaultAllowedCommands = []string{
+	"redis", "http", "kafka", "grpc", "tcp", "udp", "snmp", "syslog",
+	"postgres", "mysql", "mongodb", "rabbitmq", "pulsar", "elasticsearch", "s3", "dns", "etcd", "clickhouse", "smtp", "remotewrite", "otlp", "websocket",
+	"fanout", "churn", "maxconn", "drain",
+}
+
+// 流式消息类型
+const (
+	MessageProgress = "progress" // 运行中的中间快照
+	MessageFinal    = "final"    // 最终快照
+	MessageError    = "error"    // 执行失败
+)
+
+// CommandExecutor 在agent本地执行协议命令的函数
+type CommandExecutor func(ctx context.Context, command string, args []string) error
+
+// RunRequest 协调器下发给agent的运行请求
+type RunRequest struct {
+	RunID   string   `json:"run_id"`  // 本次分布式运行ID
+	Command string   `json:"command"` // 协议命令，如 redis、http
+	Args    []string `json:"args"`    // 该agent的命令参数（已分配操作预算）
+}
+
+// StreamMessage agent以NDJSON流形式返回的消息
+type StreamMessage struct {
+	Type     string                          `json:"type"`
+	AgentID  string                          `json:"agent_id"`
+	Snapshot *metrics.DefaultMetricsSnapshot `json:"snapshot,omitempty"`
+	Error    string                          `json:"error,omitempty"`
+}
+
+// HealthResponse agent健康检查响应
+type HealthResponse struct {
+	Status  string `json:"status"`
+	AgentID string `json:"agent_id"`
+	Version string `json:"version"`
+	Busy    bool   `json:"busy"`
+}
+
+// AgentResult 单个agent的运行结果
+type AgentResult struct {
+	Address  string                          `json:"address"`
+	AgentID  string                          `json:"agent_id"`
+	Snapshot *metrics.DefaultMetricsSnapshot `json:"snapshot,omitempty"`
+	Error    error                           `json:"-"`
+	Duration time.Duration                   `json:"duration"`
+}
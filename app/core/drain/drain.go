@@ -0,0 +1,266 @@
+package drain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+)
+
+// Queue 被测队列抽象
+// Push批量写入消息，Backlog返回目标消费者尚未处理的消息数
+type Queue interface {
+	Name() string
+	Push(ctx context.Context, payloads [][]byte) error
+	Backlog(ctx context.Context) (int64, error)
+	Close() error
+}
+
+// Config 消费速率测试配置
+type Config struct {
+	Messages    int           // 预填充的消息数
+	PayloadSize int           // 消息大小（字节）
+	BatchSize   int           // 每批写入的消息数
+	Interval    time.Duration // 积压量采样间隔
+	Timeout     time.Duration // 等待积压清空的最长时间
+}
+
+// NewDefaultConfig 创建默认消费速率测试配置
+func NewDefaultConfig() *Config {
+	return &Config{
+		Messages:    100000,
+		PayloadSize: 128,
+		BatchSize:   500,
+		Interval:    time.Second,
+		Timeout:     5 * time.Minute,
+	}
+}
+
+// Validate 验证配置
+func (c *Config) Validate() error {
+	if c.Messages <= 0 {
+		return fmt.Errorf("message count must be positive")
+	}
+	if c.PayloadSize < 0 {
+		return fmt.Errorf("payload size cannot be negative")
+	}
+	if c.BatchSize <= 0 {
+		return fmt.Errorf("batch size must be positive")
+	}
+	if c.Interval <= 0 {
+		return fmt.Errorf("poll interval must be positive")
+	}
+	if c.Timeout < c.Interval {
+		return fmt.Errorf("timeout must be at least the poll interval")
+	}
+	return nil
+}
+
+// Sample 一次积压量采样
+type Sample struct {
+	Elapsed time.Duration `json:"elapsed"` // 距填充完成的时间
+	Backlog int64         `json:"backlog"`
+	Rate    float64       `json:"rate"` // 与上次采样之间的消费速率（msg/s）
+}
+
+// Result 消费速率测试结果
+type Result struct {
+	Queue          string        `json:"queue"`
+	Filled         int64         `json:"filled"`
+	FillErrors     int64         `json:"fill_errors"`
+	FillDuration   time.Duration `json:"fill_duration"`
+	InitialBacklog int64         `json:"initial_backlog"`
+	FinalBacklog   int64         `json:"final_backlog"`
+	Drained        bool          `json:"drained"`
+	DrainDuration  time.Duration `json:"drain_duration"`
+	Half           time.Duration `json:"half"`     // 积压降至初始一半的时间
+	Ninety         time.Duration `json:"ninety"`   // 消费90%初始积压的时间
+	AvgRate        float64       `json:"avg_rate"` // (初始积压 - 最终积压) / 消费时长
+	PeakRate       float64       `json:"peak_rate"`
+	Curve          []Sample      `json:"curve"`
+}
+
+// ToMap 转换为报告使用的map
+func (r *Result) ToMap() map[string]interface{} {
+	curve := make([]map[string]interface{}, 0, len(r.Curve))
+	for _, s := range r.Curve {
+		curve = append(curve, map[string]interface{}{
+			"elapsed": s.Elapsed.String(),
+			"backlog": s.Backlog,
+			"rate":    s.Rate,
+		})
+	}
+	return map[string]interface{}{
+		"queue":           r.Queue,
+		"filled":          r.Filled,
+		"fill_errors":     r.FillErrors,
+		"fill_duration":   r.FillDuration.String(),
+		"initial_backlog": r.InitialBacklog,
+		"final_backlog":   r.FinalBacklog,
+		"drained":         r.Drained,
+		"drain_duration":  r.DrainDuration.String(),
+		"time_to_50":      r.Half.String(),
+		"time_to_90":      r.Ninety.String(),
+		"avg_rate":        r.AvgRate,
+		"peak_rate":       r.PeakRate,
+		"curve":           curve,
+	}
+}
+
+// Runner 消费速率测试执行器
+// 先向队列预填充消息，再按固定间隔采样积压量，直到积压清空或超时
+type Runner struct {
+	queue     Queue
+	config    *Config
+	collector *metrics.BaseCollector[map[string]interface{}]
+}
+
+// NewRunner 创建消费速率测试执行器，collector可为nil
+func NewRunner(queue Queue, config *Config, collector *metrics.BaseCollector[map[string]interface{}]) *Runner {
+	return &Runner{
+		queue:     queue,
+		config:    config,
+		collector: collector,
+	}
+}
+
+// Run 执行填充与消费曲线采样，onSample在每次采样后调用
+func (r *Runner) Run(ctx context.Context, onSample func(Sample)) (*Result, error) {
+	result := &Result{Queue: r.queue.Name()}
+
+	if err := r.fill(ctx, result); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	backlog, err := r.queue.Backlog(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backlog: %w", err)
+	}
+	result.InitialBacklog = backlog
+	r.observe(result, Sample{Backlog: backlog}, onSample)
+
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+	deadline := time.NewTimer(r.config.Timeout)
+	defer deadline.Stop()
+
+	for backlog > 0 {
+		select {
+		case <-ctx.Done():
+			result.finish()
+			return result, ctx.Err()
+		case <-deadline.C:
+			result.finish()
+			return result, nil
+		case <-ticker.C:
+		}
+
+		backlog, err = r.queue.Backlog(ctx)
+		if err != nil {
+			result.finish()
+			return result, fmt.Errorf("failed to read backlog: %w", err)
+		}
+		r.observe(result, Sample{Elapsed: time.Since(start), Backlog: backlog}, onSample)
+	}
+
+	result.finish()
+	return result, nil
+}
+
+// fill 分批写入预填充消息，每批作为一次操作记录到指标收集器
+func (r *Runner) fill(ctx context.Context, result *Result) error {
+	payload := make([]byte, r.config.PayloadSize)
+	for i := range payload {
+		payload[i] = 'a' + byte(i%26)
+	}
+
+	start := time.Now()
+	var lastErr error
+	for sent := 0; sent < r.config.Messages; sent += r.config.BatchSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n := min(r.config.BatchSize, r.config.Messages-sent)
+		batch := make([][]byte, n)
+		for i := range batch {
+			batch[i] = payload
+		}
+
+		batchStart := time.Now()
+		err := r.queue.Push(ctx, batch)
+		if r.collector != nil {
+			r.collector.Record(&interfaces.OperationResult{
+				Success:  err == nil,
+				Duration: time.Since(batchStart),
+				Error:    err,
+				Metadata: map[string]interface{}{"operation_type": "drain_fill", "batch_size": n},
+			})
+		}
+		if err != nil {
+			result.FillErrors += int64(n)
+			lastErr = err
+			continue
+		}
+		result.Filled += int64(n)
+	}
+	result.FillDuration = time.Since(start)
+
+	if result.Filled == 0 {
+		return fmt.Errorf("failed to fill %s: %w", r.queue.Name(), lastErr)
+	}
+	return nil
+}
+
+// observe 计算与上次采样之间的消费速率并追加到消费曲线
+func (r *Runner) observe(result *Result, sample Sample, onSample func(Sample)) {
+	if n := len(result.Curve); n > 0 {
+		prev := result.Curve[n-1]
+		if elapsed := (sample.Elapsed - prev.Elapsed).Seconds(); elapsed > 0 {
+			sample.Rate = float64(prev.Backlog-sample.Backlog) / elapsed
+		}
+	}
+	result.Curve = append(result.Curve, sample)
+	if onSample != nil {
+		onSample(sample)
+	}
+}
+
+// finish 根据消费曲线计算汇总指标
+func (r *Result) finish() {
+	if len(r.Curve) == 0 {
+		return
+	}
+	last := r.Curve[len(r.Curve)-1]
+	r.FinalBacklog = last.Backlog
+	r.Drained = last.Backlog <= 0
+	r.DrainDuration = last.Elapsed
+
+	consumed := r.InitialBacklog - r.FinalBacklog
+	if r.DrainDuration > 0 && consumed > 0 {
+		r.AvgRate = float64(consumed) / r.DrainDuration.Seconds()
+	}
+	for _, s := range r.Curve {
+		r.PeakRate = max(r.PeakRate, s.Rate)
+	}
+	r.Half = r.timeTo(r.InitialBacklog / 2)
+	r.Ninety = r.timeTo(r.InitialBacklog / 10)
+}
+
+// timeTo 积压首次降至target及以下的时间，在相邻采样间线性插值；未达到时返回0
+func (r *Result) timeTo(target int64) time.Duration {
+	for i, s := range r.Curve {
+		if s.Backlog > target {
+			continue
+		}
+		if i == 0 {
+			return 0
+		}
+		prev := r.Curve[i-1]
+		fraction := float64(prev.Backlog-target) / float64(prev.Backlog-s.Backlog)
+		return prev.Elapsed + time.Duration(fraction*float64(s.Elapsed-prev.Elapsed))
+	}
+	return 0
+}
@@ -0,0 +1,121 @@
+package drain
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// memoryQueue 内存队列，每次读取积压量时模拟消费者取走consumePerPoll条消息
+type memoryQueue struct {
+	backlog        atomic.Int64
+	consumePerPoll int64
+}
+
+func (m *memoryQueue) Name() string { return "memory" }
+
+func (m *memoryQueue) Push(ctx context.Context, payloads [][]byte) error {
+	m.backlog.Add(int64(len(payloads)))
+	return nil
+}
+
+func (m *memoryQueue) Backlog(ctx context.Context) (int64, error) {
+	backlog := m.backlog.Load()
+	if backlog > 0 {
+		m.backlog.Store(max(backlog-m.consumePerPoll, 0))
+	}
+	return backlog, nil
+}
+
+func (m *memoryQueue) Close() error { return nil }
+
+func TestRunner_DrainCurve(t *testing.T) {
+	queue := &memoryQueue{consumePerPoll: 300}
+	config := &Config{
+		Messages:    1000,
+		PayloadSize: 16,
+		BatchSize:   64,
+		Interval:    20 * time.Millisecond,
+		Timeout:     time.Second,
+	}
+
+	var samples int
+	result, err := NewRunner(queue, config, nil).Run(context.Background(), func(Sample) { samples++ })
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if result.Filled != 1000 || result.InitialBacklog != 1000 || !result.Drained {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	// 1000 -> 700 -> 400 -> 100 -> 0
+	if samples != 5 || len(result.Curve) != 5 || result.Curve[2].Backlog != 400 {
+		t.Fatalf("unexpected curve: %+v", result.Curve)
+	}
+	if result.PeakRate < result.AvgRate || result.AvgRate <= 0 {
+		t.Errorf("unexpected rates: avg=%.1f peak=%.1f", result.AvgRate, result.PeakRate)
+	}
+	if result.Half <= result.Curve[1].Elapsed || result.Half >= result.Curve[2].Elapsed {
+		t.Errorf("time to 50%% %v should fall between the second and third samples", result.Half)
+	}
+	if result.Ninety <= result.Half || result.Ninety > result.DrainDuration {
+		t.Errorf("time to 90%% %v should fall between %v and %v", result.Ninety, result.Half, result.DrainDuration)
+	}
+}
+
+func TestRunner_Timeout(t *testing.T) {
+	queue := &memoryQueue{}
+	config := &Config{Messages: 10, BatchSize: 10, Interval: 10 * time.Millisecond, Timeout: 50 * time.Millisecond}
+
+	result, err := NewRunner(queue, config, nil).Run(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if result.Drained || result.FinalBacklog != 10 || result.AvgRate != 0 || result.Half != 0 {
+		t.Fatalf("unexpected result for a stalled consumer: %+v", result)
+	}
+}
+
+func TestRabbitMQQueue(t *testing.T) {
+	var published atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "guest" || pass != "guest" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.Method + " " + r.URL.EscapedPath() {
+		case "GET /api/queues/%2F/jobs":
+			json.NewEncoder(w).Encode(map[string]interface{}{"name": "jobs", "messages": published.Load()})
+		case "POST /api/exchanges/%2F/amq.default/publish":
+			var body struct {
+				RoutingKey string `json:"routing_key"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			published.Add(1)
+			json.NewEncoder(w).Encode(map[string]bool{"routed": body.RoutingKey == "jobs"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	if _, err := NewRabbitMQQueue(ctx, server.URL, "/", "missing", "guest", "guest", time.Second); err == nil {
+		t.Fatal("expected an error for a queue that does not exist")
+	}
+	queue, err := NewRabbitMQQueue(ctx, server.URL+"/", "/", "jobs", "guest", "guest", time.Second)
+	if err != nil {
+		t.Fatalf("failed to create queue: %v", err)
+	}
+	defer queue.Close()
+
+	if err := queue.Push(ctx, make([][]byte, 20)); err != nil {
+		t.Fatalf("push failed: %v", err)
+	}
+	if backlog, err := queue.Backlog(ctx); err != nil || backlog != 20 {
+		t.Fatalf("expected backlog 20, got %d (%v)", backlog, err)
+	}
+}
@@ -0,0 +1,137 @@
+package drain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaQueue 基于Kafka主题的队列，积压量为消费者组在各分区上的滞后量之和
+type KafkaQueue struct {
+	client     *kafka.Client
+	writer     *kafka.Writer
+	topic      string
+	group      string
+	partitions []int
+}
+
+// NewKafkaQueue 创建Kafka主题队列，group为被测消费者所在的消费者组
+func NewKafkaQueue(brokers []string, topic, group string, batchSize int, timeout time.Duration) (*KafkaQueue, error) {
+	if group == "" {
+		return nil, fmt.Errorf("kafka consumer group is required to measure lag")
+	}
+	addr := kafka.TCP(brokers...)
+	return &KafkaQueue{
+		client: &kafka.Client{Addr: addr, Timeout: timeout},
+		writer: &kafka.Writer{
+			Addr:                   addr,
+			Topic:                  topic,
+			Balancer:               &kafka.RoundRobin{},
+			RequiredAcks:           kafka.RequireAll,
+			BatchSize:              batchSize,
+			BatchTimeout:           10 * time.Millisecond,
+			WriteTimeout:           timeout,
+			AllowAutoTopicCreation: true,
+		},
+		topic: topic,
+		group: group,
+	}, nil
+}
+
+// Name 获取队列名称
+func (q *KafkaQueue) Name() string {
+	return "kafka"
+}
+
+// Push 同步写入一批消息
+func (q *KafkaQueue) Push(ctx context.Context, payloads [][]byte) error {
+	messages := make([]kafka.Message, len(payloads))
+	for i, payload := range payloads {
+		messages[i] = kafka.Message{Value: payload}
+	}
+	return q.writer.WriteMessages(ctx, messages...)
+}
+
+// Backlog 各分区最新偏移量与消费者组已提交偏移量之差的总和
+// 消费者组尚未在某分区提交过偏移量时，按该分区全部保留消息计算
+func (q *KafkaQueue) Backlog(ctx context.Context) (int64, error) {
+	if err := q.loadPartitions(ctx); err != nil {
+		return 0, err
+	}
+
+	requests := make([]kafka.OffsetRequest, 0, 2*len(q.partitions))
+	for _, partition := range q.partitions {
+		requests = append(requests, kafka.FirstOffsetOf(partition), kafka.LastOffsetOf(partition))
+	}
+	offsets, err := q.client.ListOffsets(ctx, &kafka.ListOffsetsRequest{
+		Topics: map[string][]kafka.OffsetRequest{q.topic: requests},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list offsets of %s: %w", q.topic, err)
+	}
+	committed, err := q.client.OffsetFetch(ctx, &kafka.OffsetFetchRequest{
+		GroupID: q.group,
+		Topics:  map[string][]int{q.topic: q.partitions},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch offsets of group %s: %w", q.group, err)
+	}
+	if committed.Error != nil {
+		return 0, fmt.Errorf("failed to fetch offsets of group %s: %w", q.group, committed.Error)
+	}
+
+	commits := make(map[int]int64, len(q.partitions))
+	for _, p := range committed.Topics[q.topic] {
+		if p.Error == nil {
+			commits[p.Partition] = p.CommittedOffset
+		}
+	}
+
+	var backlog int64
+	for _, p := range offsets.Topics[q.topic] {
+		if p.Error != nil {
+			return 0, fmt.Errorf("failed to list offsets of %s/%d: %w", q.topic, p.Partition, p.Error)
+		}
+		position, ok := commits[p.Partition]
+		if !ok || position < p.FirstOffset {
+			position = p.FirstOffset
+		}
+		if lag := p.LastOffset - position; lag > 0 {
+			backlog += lag
+		}
+	}
+	return backlog, nil
+}
+
+// loadPartitions 首次采样时读取主题的分区列表
+func (q *KafkaQueue) loadPartitions(ctx context.Context) error {
+	if len(q.partitions) > 0 {
+		return nil
+	}
+	metadata, err := q.client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{q.topic}})
+	if err != nil {
+		return fmt.Errorf("failed to load metadata of %s: %w", q.topic, err)
+	}
+	for _, topic := range metadata.Topics {
+		if topic.Name != q.topic {
+			continue
+		}
+		if topic.Error != nil {
+			return fmt.Errorf("failed to load metadata of %s: %w", q.topic, topic.Error)
+		}
+		for _, partition := range topic.Partitions {
+			q.partitions = append(q.partitions, partition.ID)
+		}
+	}
+	if len(q.partitions) == 0 {
+		return fmt.Errorf("topic %s has no partitions", q.topic)
+	}
+	return nil
+}
+
+// Close 关闭写入器
+func (q *KafkaQueue) Close() error {
+	return q.writer.Close()
+}
@@ -0,0 +1,148 @@
+package drain
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rabbitMQPublishWorkers 单批消息并发发布的请求数
+const rabbitMQPublishWorkers = 8
+
+// RabbitMQQueue 通过RabbitMQ管理插件HTTP API访问的队列
+// 经默认交换机按队列名发布消息，以队列的messages（就绪+未确认）作为积压量
+// 管理API的队列统计按collect_statistics_interval（默认5s）刷新，采样间隔不宜小于该值
+type RabbitMQQueue struct {
+	client   *http.Client
+	baseURL  string
+	vhost    string
+	queue    string
+	username string
+	password string
+}
+
+// NewRabbitMQQueue 创建RabbitMQ队列，队列须已由被测消费者声明
+func NewRabbitMQQueue(ctx context.Context, baseURL, vhost, queue, username, password string, timeout time.Duration) (*RabbitMQQueue, error) {
+	q := &RabbitMQQueue{
+		client:   &http.Client{Timeout: timeout},
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		vhost:    vhost,
+		queue:    queue,
+		username: username,
+		password: password,
+	}
+	if _, err := q.Backlog(ctx); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// Name 获取队列名称
+func (q *RabbitMQQueue) Name() string {
+	return "rabbitmq"
+}
+
+// Push 并发发布一批持久化消息，任一消息未路由到队列即视为失败
+func (q *RabbitMQQueue) Push(ctx context.Context, payloads [][]byte) error {
+	var (
+		wg       sync.WaitGroup
+		mutex    sync.Mutex
+		firstErr error
+	)
+	next := make(chan []byte)
+	for i := 0; i < min(rabbitMQPublishWorkers, len(payloads)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for payload := range next {
+				if err := q.publish(ctx, payload); err != nil {
+					mutex.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mutex.Unlock()
+				}
+			}
+		}()
+	}
+	for _, payload := range payloads {
+		next <- payload
+	}
+	close(next)
+	wg.Wait()
+	return firstErr
+}
+
+// publish 发布单条消息
+func (q *RabbitMQQueue) publish(ctx context.Context, payload []byte) error {
+	body, _ := json.Marshal(map[string]interface{}{
+		"properties":       map[string]interface{}{"delivery_mode": 2},
+		"routing_key":      q.queue,
+		"payload":          base64.StdEncoding.EncodeToString(payload),
+		"payload_encoding": "base64",
+	})
+	var response struct {
+		Routed bool `json:"routed"`
+	}
+	if err := q.do(ctx, http.MethodPost, "/api/exchanges/"+url.PathEscape(q.vhost)+"/amq.default/publish", body, &response); err != nil {
+		return err
+	}
+	if !response.Routed {
+		return fmt.Errorf("message was not routed to queue %s", q.queue)
+	}
+	return nil
+}
+
+// Backlog 队列中就绪与未确认的消息总数
+func (q *RabbitMQQueue) Backlog(ctx context.Context) (int64, error) {
+	var response struct {
+		Messages int64 `json:"messages"`
+	}
+	path := "/api/queues/" + url.PathEscape(q.vhost) + "/" + url.PathEscape(q.queue)
+	if err := q.do(ctx, http.MethodGet, path, nil, &response); err != nil {
+		return 0, err
+	}
+	return response.Messages, nil
+}
+
+// do 发送管理API请求并解析JSON响应
+func (q *RabbitMQQueue) do(ctx context.Context, method, path string, body []byte, out interface{}) error {
+	request, err := http.NewRequestWithContext(ctx, method, q.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create rabbitmq request: %w", err)
+	}
+	request.SetBasicAuth(q.username, q.password)
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := q.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("rabbitmq management request failed: %w", err)
+	}
+	defer response.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+
+	switch {
+	case response.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("rabbitmq %s not found (vhost %q, queue %q)", path, q.vhost, q.queue)
+	case response.StatusCode >= 300:
+		return fmt.Errorf("rabbitmq management API returned %d: %s", response.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("invalid rabbitmq management response: %w", err)
+	}
+	return nil
+}
+
+// Close 关闭空闲连接
+func (q *RabbitMQQueue) Close() error {
+	q.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,53 @@
+package drain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisQueue 基于Redis列表的队列，RPUSH写入，以LLEN作为积压量
+// 适用于以LPOP/BLPOP或BRPOPLPUSH等方式消费列表的工作队列
+type RedisQueue struct {
+	client *redis.Client
+	key    string
+}
+
+// NewRedisQueue 创建Redis列表队列
+func NewRedisQueue(ctx context.Context, addr, password string, db int, key string) (*RedisQueue, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+	})
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to redis %s: %w", addr, err)
+	}
+	return &RedisQueue{client: client, key: key}, nil
+}
+
+// Name 获取队列名称
+func (q *RedisQueue) Name() string {
+	return "redis"
+}
+
+// Push 以单条RPUSH写入一批消息
+func (q *RedisQueue) Push(ctx context.Context, payloads [][]byte) error {
+	values := make([]interface{}, len(payloads))
+	for i, payload := range payloads {
+		values[i] = payload
+	}
+	return q.client.RPush(ctx, q.key, values...).Err()
+}
+
+// Backlog 列表长度
+func (q *RedisQueue) Backlog(ctx context.Context) (int64, error) {
+	return q.client.LLen(ctx, q.key).Result()
+}
+
+// Close 关闭连接
+func (q *RedisQueue) Close() error {
+	return q.client.Close()
+}
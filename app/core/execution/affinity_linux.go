@@ -0,0 +1,40 @@
+//go:build linux
+
+package execution
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// cpuSetWords sched_setaffinity掩码的字数，覆盖1024个CPU
+const cpuSetWords = 1024 / 64
+
+// allowedCPUs 当前进程允许运行的CPU编号（受taskset与cgroup cpuset限制），获取失败时返回nil
+func allowedCPUs() []int {
+	var mask [cpuSetWords]uint64
+	_, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_GETAFFINITY, 0, uintptr(len(mask)*8), uintptr(unsafe.Pointer(&mask[0])))
+	if errno != 0 {
+		return nil
+	}
+	var cpus []int
+	for word, bits := range mask {
+		for bit := 0; bit < 64; bit++ {
+			if bits&(1<<uint(bit)) != 0 {
+				cpus = append(cpus, word*64+bit)
+			}
+		}
+	}
+	return cpus
+}
+
+// pinToCPU 将调用线程绑定到指定CPU，调用方须已执行runtime.LockOSThread
+func pinToCPU(cpu int) error {
+	var mask [cpuSetWords]uint64
+	mask[cpu/64] |= 1 << uint(cpu%64)
+	_, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_SETAFFINITY, 0, uintptr(len(mask)*8), uintptr(unsafe.Pointer(&mask[0])))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package execution
+
+// allowedCPUs 不支持查询CPU亲和性的平台上返回nil，执行核不绑定CPU
+func allowedCPUs() []int {
+	return nil
+}
+
+// pinToCPU 不支持设置CPU亲和性的平台上只锁定线程，不绑定CPU
+func pinToCPU(cpu int) error {
+	return nil
+}
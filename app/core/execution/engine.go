@@ -21,9 +21,10 @@ type BenchmarkConfig interface {
 
 // Job 表示一个待执行的任务
 type Job struct {
-	ID        int                  // 任务ID
-	Operation interfaces.Operation // 操作定义
-	Context   context.Context      // 执行上下文
+	ID          int                  // 任务ID
+	Operation   interfaces.Operation // 操作定义
+	Context     context.Context      // 执行上下文
+	ScheduledAt time.Time            // 计划开始时间
 }
 
 // ExecutionResult 执行结果
@@ -35,6 +36,9 @@ type ExecutionResult struct {
 	TotalDuration time.Duration // 总执行时间
 	StartTime     time.Time     // 开始时间
 	EndTime       time.Time     // 结束时间
+	Cores         int           // 按核执行的核数，共享工作池时为0
+	ForkedCores   int           // 使用独立连接的核数
+	Aborted       string        // 中断原因（如收到的信号），运行完整结束时为空
 }
 
 // OperationFactory 操作工厂接口
@@ -42,11 +46,30 @@ type OperationFactory interface {
 	CreateOperation(jobID int, config BenchmarkConfig) interfaces.Operation
 }
 
+// PrepareFactory 支持预填充阶段的操作工厂（可选实现）
+type PrepareFactory interface {
+	// CreatePrepareOperation 创建第index个预填充操作，如写入测量阶段将读取的第index个键
+	CreatePrepareOperation(index int) interfaces.Operation
+}
+
+// PrepareResult 预填充阶段结果
+type PrepareResult struct {
+	Total      int64         // 计划的预填充操作数
+	Success    int64         // 成功数
+	Failed     int64         // 失败数
+	Duration   time.Duration // 耗时
+	FirstError error         // 首个失败原因
+}
+
 // ExecutionEngine 通用执行引擎
 type ExecutionEngine struct {
 	adapter          interfaces.ProtocolAdapter         // 协议适配器
 	metricsCollector interfaces.DefaultMetricsCollector // 指标收集器
 	operationFactory OperationFactory                   // 操作工厂
+	scheduleTracer   *ScheduleTracer                    // 调度追踪器（可选）
+	requestIDs       *RequestIDGenerator                // 请求ID生成器（可选）
+	traceContexts    *TraceContextGenerator             // trace-context生成器（可选）
+	interceptors     []Interceptor                      // 操作拦截器，第一个位于最外层（可选）
 
 	// 状态管理
 	isRunning int32 // 原子操作标记
@@ -57,11 +80,26 @@ type ExecutionEngine struct {
 	completedJobs int64 // 完成任务数
 	successJobs   int64 // 成功任务数
 	failedJobs    int64 // 失败任务数
+	activeJobs    int64 // 正在执行的任务数
+
+	// 本次运行的计划，供Progress读取
+	runStart    time.Time
+	runWorkers  int
+	runDuration time.Duration
+
+	// 中断：Abort关闭abort通道，停止派发新任务
+	abort       chan struct{}
+	abortReason string
+
+	// 从检查点续跑时已完成的任务数与时长
+	resumeJobs    int64
+	resumeElapsed time.Duration
 
 	// 配置
 	maxWorkers       int // 最大工作协程数
 	jobBufferSize    int // 任务缓冲区大小
 	resultBufferSize int // 结果缓冲区大小
+	cores            int // 按核执行的核数，0表示使用共享工作池
 }
 
 // NewExecutionEngine 创建新的执行引擎
@@ -101,6 +139,74 @@ func (e *ExecutionEngine) SetBufferSizes(jobBufferSize, resultBufferSize int) {
 	}
 }
 
+// SetScheduleTracer 设置调度追踪器，用于记录计划与实际的操作开始时间
+func (e *ExecutionEngine) SetScheduleTracer(tracer *ScheduleTracer) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.scheduleTracer = tracer
+}
+
+// SetRequestIDs 设置请求ID生成器，为每个操作生成唯一ID
+// ID经context传给适配器，并写入结果元数据的request_id字段
+func (e *ExecutionEngine) SetRequestIDs(generator *RequestIDGenerator) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.requestIDs = generator
+}
+
+// SetTraceContexts 设置trace-context生成器，为每个操作生成W3C trace-context
+// trace-context经context传给适配器，trace_id、span_id与trace_sampled写入结果元数据
+func (e *ExecutionEngine) SetTraceContexts(generator *TraceContextGenerator) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.traceContexts = generator
+}
+
+// AddInterceptors 追加操作拦截器，拦截器按添加顺序由外向内包裹适配器的Execute
+func (e *ExecutionEngine) AddInterceptors(interceptors ...Interceptor) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.interceptors = append(e.interceptors, interceptors...)
+}
+
+// Progress 运行进度，供实时界面等在运行期间读取
+type Progress struct {
+	Running   bool          // 是否正在运行
+	StartTime time.Time     // 本次运行的开始时间，尚未运行时为零值
+	Duration  time.Duration // 计划运行时长，0表示按任务数运行
+	Workers   int           // 工作协程数
+	Busy      int64         // 正在执行操作的工作协程数
+	Total     int64         // 计划任务数
+	Completed int64         // 完成任务数
+	Failed    int64         // 失败任务数
+}
+
+// Progress 获取当前运行进度
+func (e *ExecutionEngine) Progress() Progress {
+	e.mutex.RLock()
+	progress := Progress{
+		StartTime: e.runStart,
+		Duration:  e.runDuration,
+		Workers:   e.runWorkers,
+	}
+	e.mutex.RUnlock()
+	progress.Running = e.IsRunning()
+	progress.Busy = atomic.LoadInt64(&e.activeJobs)
+	progress.Total = atomic.LoadInt64(&e.totalJobs)
+	progress.Completed = atomic.LoadInt64(&e.completedJobs)
+	progress.Failed = atomic.LoadInt64(&e.failedJobs)
+	return progress
+}
+
+// beginRun 记录本次运行的计划
+func (e *ExecutionEngine) beginRun(startTime time.Time, workers int, duration time.Duration) {
+	e.mutex.Lock()
+	e.runStart = startTime
+	e.runWorkers = workers
+	e.runDuration = duration
+	e.mutex.Unlock()
+}
+
 // RunBenchmark 运行基准测试
 func (e *ExecutionEngine) RunBenchmark(ctx context.Context, config BenchmarkConfig) (*ExecutionResult, error) {
 	// 检查是否已在运行
@@ -115,8 +221,31 @@ func (e *ExecutionEngine) RunBenchmark(ctx context.Context, config BenchmarkConf
 	atomic.StoreInt64(&e.successJobs, 0)
 	atomic.StoreInt64(&e.failedJobs, 0)
 
+	e.resetAbort()
+	if InterruptHandlingFromContext(ctx) {
+		defer e.watchInterrupts()()
+	}
+
 	startTime := time.Now()
 
+	if e.scheduleTracer != nil {
+		e.scheduleTracer.Start(startTime)
+	}
+
+	if e.resumeFinished(config) {
+		now := time.Now()
+		return &ExecutionResult{StartTime: startTime, EndTime: now, TotalDuration: now.Sub(startTime)}, nil
+	}
+
+	e.mutex.RLock()
+	cores := e.cores
+	e.mutex.RUnlock()
+	if cores > 0 {
+		result := e.runPerCore(ctx, config, cores, startTime)
+		result.Aborted = e.AbortReason()
+		return result, nil
+	}
+
 	// 确定工作协程数
 	workerCount := config.GetParallels()
 	if workerCount <= 0 {
@@ -125,6 +254,7 @@ func (e *ExecutionEngine) RunBenchmark(ctx context.Context, config BenchmarkConf
 	if workerCount > e.maxWorkers {
 		workerCount = e.maxWorkers
 	}
+	e.beginRun(startTime, workerCount, e.runDurationOf(config))
 
 	// 创建通道
 	jobChan := make(chan Job, e.jobBufferSize)
@@ -134,9 +264,10 @@ func (e *ExecutionEngine) RunBenchmark(ctx context.Context, config BenchmarkConf
 	var workerWG sync.WaitGroup
 
 	// 启动工作协程
+	execute := e.executor(e.adapter)
 	for i := 0; i < workerCount; i++ {
 		workerWG.Add(1)
-		go e.worker(ctx, &workerWG, jobChan, resultChan)
+		go e.worker(ctx, i, &workerWG, jobChan, resultChan, execute)
 	}
 
 	// 启动结果收集协程
@@ -146,7 +277,7 @@ func (e *ExecutionEngine) RunBenchmark(ctx context.Context, config BenchmarkConf
 
 	// 创建任务生成上下文（支持超时和持续时间）
 	jobCtx := ctx
-	if duration := config.GetDuration(); duration > 0 {
+	if duration := e.runDurationOf(config); duration > 0 {
 		var cancel context.CancelFunc
 		jobCtx, cancel = context.WithTimeout(ctx, duration)
 		defer cancel()
@@ -182,14 +313,81 @@ func (e *ExecutionEngine) RunBenchmark(ctx context.Context, config BenchmarkConf
 		TotalDuration: endTime.Sub(startTime),
 		StartTime:     startTime,
 		EndTime:       endTime,
+		Aborted:       e.AbortReason(),
 	}
 
 	return result, nil
 }
 
-// worker 工作协程
-func (e *ExecutionEngine) worker(ctx context.Context, wg *sync.WaitGroup, jobChan <-chan Job, resultChan chan<- *interfaces.OperationResult) {
+// RunPrepare 在测量开始前以parallels个并发执行count个预填充操作
+// 结果不记录到指标收集器与调度追踪器，操作工厂须实现PrepareFactory
+func (e *ExecutionEngine) RunPrepare(ctx context.Context, count, parallels int) (*PrepareResult, error) {
+	factory, ok := e.operationFactory.(PrepareFactory)
+	if !ok {
+		return nil, fmt.Errorf("%s does not support a prefill phase", e.adapter.GetProtocolName())
+	}
+	if !atomic.CompareAndSwapInt32(&e.isRunning, 0, 1) {
+		return nil, fmt.Errorf("execution engine is already running")
+	}
+	defer atomic.StoreInt32(&e.isRunning, 0)
+
+	e.mutex.RLock()
+	maxWorkers := e.maxWorkers
+	e.mutex.RUnlock()
+	if parallels <= 0 {
+		parallels = 1
+	}
+	if parallels > maxWorkers {
+		parallels = maxWorkers
+	}
+	if parallels > count {
+		parallels = count
+	}
+
+	result := &PrepareResult{Total: int64(count)}
+	var next, success, failed atomic.Int64
+	var errOnce sync.Once
+	startTime := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < parallels; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				index := next.Add(1) - 1
+				if index >= int64(count) {
+					return
+				}
+				operation := factory.CreatePrepareOperation(int(index))
+				opResult, err := e.adapter.Execute(ctx, operation)
+				if err == nil && opResult != nil && opResult.Success {
+					success.Add(1)
+					continue
+				}
+				failed.Add(1)
+				if err == nil && opResult != nil {
+					err = opResult.Error
+				}
+				if err == nil {
+					err = fmt.Errorf("%s %s failed", operation.Type, operation.Key)
+				}
+				errOnce.Do(func() { result.FirstError = err })
+			}
+		}()
+	}
+	wg.Wait()
+
+	result.Success = success.Load()
+	result.Failed = failed.Load()
+	result.Duration = time.Since(startTime)
+	return result, ctx.Err()
+}
+
+// worker 工作协程，execute为本次运行组装好的执行函数
+func (e *ExecutionEngine) worker(ctx context.Context, workerID int, wg *sync.WaitGroup, jobChan <-chan Job, resultChan chan<- *interfaces.OperationResult, execute Executor) {
 	defer wg.Done()
+	abort := e.abortSignal()
 
 	for {
 		select {
@@ -197,9 +395,28 @@ func (e *ExecutionEngine) worker(ctx context.Context, wg *sync.WaitGroup, jobCha
 			if !ok {
 				return // 任务通道已关闭
 			}
+			// 中断后丢弃已在通道中排队的任务
+			if aborted(abort) {
+				continue
+			}
 
 			// 执行任务
-			result := e.executeJob(job)
+			job.Context = WithWorkerID(job.Context, workerID)
+			startedAt := time.Now()
+			result := e.executeJob(execute, job)
+
+			// 记录调度追踪
+			if e.scheduleTracer != nil {
+				e.scheduleTracer.Record(ScheduleTraceEvent{
+					JobID:         job.ID,
+					WorkerID:      workerID,
+					OperationType: job.Operation.Type,
+					ScheduledAt:   job.ScheduledAt,
+					StartedAt:     startedAt,
+					FinishedAt:    time.Now(),
+					Success:       result.Success,
+				})
+			}
 
 			// 发送结果
 			select {
@@ -222,13 +439,59 @@ func (e *ExecutionEngine) worker(ctx context.Context, wg *sync.WaitGroup, jobCha
 	}
 }
 
-// executeJob 执行单个任务
-func (e *ExecutionEngine) executeJob(job Job) *interfaces.OperationResult {
+// executor 使用adapter执行操作的函数，配置了拦截器时组装拦截器链
+// 每次运行在启动工作协程前调用一次，运行期间追加的拦截器从下次运行起生效
+func (e *ExecutionEngine) executor(adapter interfaces.ProtocolAdapter) Executor {
+	e.mutex.RLock()
+	interceptors := e.interceptors
+	e.mutex.RUnlock()
+	if len(interceptors) == 0 {
+		return adapter.Execute
+	}
+	return ChainInterceptors(adapter.Execute, interceptors...)
+}
+
+// executeJob 使用指定的执行函数执行单个任务
+func (e *ExecutionEngine) executeJob(execute Executor, job Job) *interfaces.OperationResult {
+	var requestID string
+	if e.requestIDs != nil {
+		requestID = e.requestIDs.Next()
+		job.Context = WithRequestID(job.Context, requestID)
+	}
+
+	var trace TraceContext
+	if e.traceContexts != nil {
+		trace = e.traceContexts.Next()
+		job.Context = WithTraceContext(job.Context, trace)
+	}
+
+	atomic.AddInt64(&e.activeJobs, 1)
+	result := e.execute(execute, job)
+	atomic.AddInt64(&e.activeJobs, -1)
+	if requestID == "" && trace.TraceID == "" {
+		return result
+	}
+	if result.Metadata == nil {
+		result.Metadata = make(map[string]interface{})
+	}
+	if requestID != "" {
+		result.Metadata["request_id"] = requestID
+	}
+	if trace.TraceID != "" {
+		result.Metadata["trace_id"] = trace.TraceID
+		result.Metadata["span_id"] = trace.SpanID
+		result.Metadata["trace_sampled"] = trace.Sampled
+	}
+	return result
+}
+
+// execute 执行操作并保证返回非空结果
+func (e *ExecutionEngine) execute(execute Executor, job Job) *interfaces.OperationResult {
 	// 测量执行时间
 	startTime := time.Now()
 
-	// 使用适配器执行操作
-	result, err := e.adapter.Execute(job.Context, job.Operation)
+	// 执行操作，配置了拦截器时经拦截器链调用
+	result, err := execute(job.Context, job.Operation)
 
 	// 计算执行时间
 	duration := time.Since(startTime)
@@ -240,6 +503,7 @@ func (e *ExecutionEngine) executeJob(job Job) *interfaces.OperationResult {
 			Duration: duration, // 使用实际测量的时间
 			Error:    err,
 			IsRead:   false, // 默认为写操作，具体可以从operation中获取
+			Metadata: resultMetadata(result),
 		}
 	}
 
@@ -262,6 +526,14 @@ func (e *ExecutionEngine) executeJob(job Job) *interfaces.OperationResult {
 	return result
 }
 
+// resultMetadata 出错时保留适配器与拦截器写入的结果元数据
+func resultMetadata(result *interfaces.OperationResult) map[string]interface{} {
+	if result == nil {
+		return nil
+	}
+	return result.Metadata
+}
+
 // resultCollector 结果收集协程
 func (e *ExecutionEngine) resultCollector(wg *sync.WaitGroup, resultChan <-chan *interfaces.OperationResult) {
 	defer wg.Done()
@@ -276,22 +548,26 @@ func (e *ExecutionEngine) resultCollector(wg *sync.WaitGroup, resultChan <-chan
 
 // generateJobs 生成任务（常规模式）
 func (e *ExecutionEngine) generateJobs(ctx context.Context, config BenchmarkConfig, jobChan chan<- Job) {
-	total := config.GetTotal()
+	total, offset := e.jobRange(config)
 	atomic.StoreInt64(&e.totalJobs, int64(total))
+	abort := e.abortSignal()
 
 	for i := 0; i < total; i++ {
 		select {
 		case <-ctx.Done():
 			return
+		case <-abort:
+			return
 		default:
 			// 创建操作
-			operation := e.operationFactory.CreateOperation(i, config)
+			operation := e.operationFactory.CreateOperation(offset+i, config)
 
-			// 创建任务
+			// 创建任务（闭环模式下计划时间即派发时间）
 			job := Job{
-				ID:        i,
-				Operation: operation,
-				Context:   ctx,
+				ID:          offset + i,
+				Operation:   operation,
+				Context:     ctx,
+				ScheduledAt: time.Now(),
 			}
 
 			// 发送任务
@@ -299,6 +575,8 @@ func (e *ExecutionEngine) generateJobs(ctx context.Context, config BenchmarkConf
 			case jobChan <- job:
 			case <-ctx.Done():
 				return
+			case <-abort:
+				return
 			}
 		}
 	}
@@ -306,7 +584,7 @@ func (e *ExecutionEngine) generateJobs(ctx context.Context, config BenchmarkConf
 
 // generateJobsWithRampUp 生成任务（渐进加载模式）
 func (e *ExecutionEngine) generateJobsWithRampUp(ctx context.Context, config BenchmarkConfig, jobChan chan<- Job) {
-	total := config.GetTotal()
+	total, offset := e.jobRange(config)
 	rampUp := config.GetRampUp()
 	atomic.StoreInt64(&e.totalJobs, int64(total))
 
@@ -318,20 +596,25 @@ func (e *ExecutionEngine) generateJobsWithRampUp(ctx context.Context, config Ben
 
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
+	scheduleStart := time.Now()
+	abort := e.abortSignal()
 
 	for i := 0; i < total; i++ {
 		select {
 		case <-ctx.Done():
 			return
+		case <-abort:
+			return
 		case <-ticker.C:
 			// 创建操作
-			operation := e.operationFactory.CreateOperation(i, config)
+			operation := e.operationFactory.CreateOperation(offset+i, config)
 
-			// 创建任务
+			// 创建任务（计划时间按固定到达间隔推算）
 			job := Job{
-				ID:        i,
-				Operation: operation,
-				Context:   ctx,
+				ID:          offset + i,
+				Operation:   operation,
+				Context:     ctx,
+				ScheduledAt: scheduleStart.Add(time.Duration(i+1) * interval),
 			}
 
 			// 发送任务
@@ -339,6 +622,8 @@ func (e *ExecutionEngine) generateJobsWithRampUp(ctx context.Context, config Ben
 			case jobChan <- job:
 			case <-ctx.Done():
 				return
+			case <-abort:
+				return
 			}
 		}
 	}
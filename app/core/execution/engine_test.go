@@ -3,11 +3,16 @@ package execution
 import (
 	"context"
 	"fmt"
+	"os"
+	"slices"
+	"sync"
 	"sync/atomic"
+	"syscall"
 	"testing"
 	"time"
 
 	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
 )
 
 // 测试用的mock适配器
@@ -65,15 +70,18 @@ func (m *mockProtocolAdapter) GetMetricsCollector() interfaces.DefaultMetricsCol
 	return nil // mock实现
 }
 
-// 测试用的mock指标收集器
+// 测试用的mock指标收集器，按核心分片执行时Record被多个worker并发调用
 type mockMetricsCollector struct {
 	recordCount int64
+	mutex       sync.Mutex
 	results     []*interfaces.OperationResult
 }
 
 func (m *mockMetricsCollector) Record(result *interfaces.OperationResult) {
 	atomic.AddInt64(&m.recordCount, 1)
+	m.mutex.Lock()
 	m.results = append(m.results, result)
+	m.mutex.Unlock()
 }
 
 func (m *mockMetricsCollector) Snapshot() *interfaces.MetricsSnapshot[map[string]interface{}] {
@@ -91,7 +99,9 @@ func (m *mockMetricsCollector) Snapshot() *interfaces.MetricsSnapshot[map[string
 
 func (m *mockMetricsCollector) Reset() {
 	atomic.StoreInt64(&m.recordCount, 0)
+	m.mutex.Lock()
 	m.results = nil
+	m.mutex.Unlock()
 }
 
 func (m *mockMetricsCollector) Stop() {
@@ -179,3 +189,428 @@ func TestExecutionEngine_RunBenchmark_Basic(t *testing.T) {
 		t.Errorf("Expected metrics collector to record 10 times, got %d", recordCount)
 	}
 }
+
+// workerRecordingAdapter 记录执行各操作的工作协程ID
+type workerRecordingAdapter struct {
+	mockProtocolAdapter
+	workers sync.Map
+	missing atomic.Int64
+}
+
+func (w *workerRecordingAdapter) Execute(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	if workerID, ok := WorkerIDFromContext(ctx); ok {
+		w.workers.Store(workerID, true)
+	} else {
+		w.missing.Add(1)
+	}
+	return w.mockProtocolAdapter.Execute(ctx, operation)
+}
+
+func TestExecutionEngine_WorkerIDInContext(t *testing.T) {
+	adapter := &workerRecordingAdapter{mockProtocolAdapter: mockProtocolAdapter{executionDelay: time.Millisecond}}
+	engine := NewExecutionEngine(adapter, &mockMetricsCollector{}, &mockOperationFactory{operationType: "test"})
+
+	if _, err := engine.RunBenchmark(context.Background(), &mockBenchmarkConfig{total: 40, parallels: 4}); err != nil {
+		t.Fatalf("RunBenchmark failed: %v", err)
+	}
+
+	workers := 0
+	adapter.workers.Range(func(key, _ interface{}) bool {
+		if id := key.(int); id < 0 || id >= 4 {
+			t.Errorf("unexpected worker ID %d", id)
+		}
+		workers++
+		return true
+	})
+	if workers == 0 || adapter.missing.Load() != 0 {
+		t.Errorf("expected every operation to carry a worker ID, got %d workers", workers)
+	}
+	if _, ok := WorkerIDFromContext(context.Background()); ok {
+		t.Errorf("expected no worker ID outside the engine")
+	}
+}
+
+// requestIDAdapter 记录各操作context中的请求ID
+type requestIDAdapter struct {
+	mockProtocolAdapter
+	ids sync.Map
+}
+
+func (r *requestIDAdapter) Execute(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	if requestID, ok := RequestIDFromContext(ctx); ok {
+		r.ids.Store(requestID, true)
+	}
+	return r.mockProtocolAdapter.Execute(ctx, operation)
+}
+
+func TestExecutionEngine_RequestIDs(t *testing.T) {
+	adapter := &requestIDAdapter{mockProtocolAdapter: mockProtocolAdapter{shouldFail: true}}
+	collector := &mockMetricsCollector{}
+	engine := NewExecutionEngine(adapter, collector, &mockOperationFactory{operationType: "test"})
+	generator := NewRequestIDGenerator()
+	engine.SetRequestIDs(generator)
+
+	if _, err := engine.RunBenchmark(context.Background(), &mockBenchmarkConfig{total: 50, parallels: 4}); err != nil {
+		t.Fatalf("RunBenchmark failed: %v", err)
+	}
+
+	// 每个操作的ID唯一，且与结果元数据中的ID一致（失败的操作同样携带ID）
+	seen := make(map[string]bool)
+	for _, result := range collector.results {
+		requestID, _ := result.Metadata["request_id"].(string)
+		if _, ok := adapter.ids.Load(requestID); !ok || seen[requestID] {
+			t.Fatalf("unexpected request ID %q", requestID)
+		}
+		if len(generator.Prefix()) != 8 || requestID[:9] != generator.Prefix()+"-" {
+			t.Fatalf("request ID %q does not use the run prefix %q", requestID, generator.Prefix())
+		}
+		seen[requestID] = true
+	}
+	if len(seen) != 50 {
+		t.Errorf("expected 50 unique request IDs, got %d", len(seen))
+	}
+	if _, ok := RequestIDFromContext(context.Background()); ok {
+		t.Errorf("expected no request ID outside the engine")
+	}
+}
+
+// traceContextAdapter 记录各操作context中的traceparent
+type traceContextAdapter struct {
+	mockProtocolAdapter
+	traceparents sync.Map
+}
+
+func (r *traceContextAdapter) Execute(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	if trace, ok := TraceContextFromContext(ctx); ok {
+		r.traceparents.Store(trace.TraceID, trace.Traceparent())
+	}
+	return r.mockProtocolAdapter.Execute(ctx, operation)
+}
+
+func TestExecutionEngine_TraceContexts(t *testing.T) {
+	for _, ratio := range []float64{0, 0.5, 1} {
+		adapter := &traceContextAdapter{}
+		collector := &mockMetricsCollector{}
+		engine := NewExecutionEngine(adapter, collector, &mockOperationFactory{operationType: "test"})
+		engine.SetTraceContexts(NewTraceContextGenerator(ratio))
+
+		if _, err := engine.RunBenchmark(context.Background(), &mockBenchmarkConfig{total: 400, parallels: 4}); err != nil {
+			t.Fatalf("RunBenchmark failed: %v", err)
+		}
+
+		// 每个操作一个新trace，结果元数据与注入的traceparent一致
+		sampled := 0
+		for _, result := range collector.results {
+			traceID, _ := result.Metadata["trace_id"].(string)
+			spanID, _ := result.Metadata["span_id"].(string)
+			isSampled, _ := result.Metadata["trace_sampled"].(bool)
+			flags := "-00"
+			if isSampled {
+				flags = "-01"
+				sampled++
+			}
+			traceparent, ok := adapter.traceparents.Load(traceID)
+			if !ok || traceparent != "00-"+traceID+"-"+spanID+flags || len(traceID) != 32 || len(spanID) != 16 {
+				t.Fatalf("trace metadata %s/%s does not match the injected traceparent %v", traceID, spanID, traceparent)
+			}
+		}
+		switch {
+		case ratio == 0 && sampled != 0, ratio == 1 && sampled != 400, ratio == 0.5 && (sampled < 120 || sampled > 280):
+			t.Errorf("ratio %v: %d of 400 traces sampled", ratio, sampled)
+		}
+	}
+}
+
+// mockPrepareFactory 支持预填充阶段的mock操作工厂
+type mockPrepareFactory struct {
+	mockOperationFactory
+	keys sync.Map
+}
+
+func (m *mockPrepareFactory) CreatePrepareOperation(index int) interfaces.Operation {
+	key := fmt.Sprintf("key_%d", index)
+	m.keys.Store(key, true)
+	return interfaces.Operation{Type: "write", Key: key}
+}
+
+func TestExecutionEngine_RunPrepare(t *testing.T) {
+	adapter := &mockProtocolAdapter{}
+	collector := &mockMetricsCollector{}
+	factory := &mockPrepareFactory{}
+
+	engine := NewExecutionEngine(adapter, collector, factory)
+	result, err := engine.RunPrepare(context.Background(), 50, 4)
+	if err != nil {
+		t.Fatalf("RunPrepare failed: %v", err)
+	}
+	if result.Total != 50 || result.Success != 50 || result.Failed != 0 {
+		t.Errorf("unexpected prepare result: %+v", result)
+	}
+
+	keys := 0
+	factory.keys.Range(func(key, value interface{}) bool {
+		keys++
+		return true
+	})
+	if keys != 50 {
+		t.Errorf("expected 50 distinct prepared keys, got %d", keys)
+	}
+	// 预填充结果不计入指标
+	if recordCount := atomic.LoadInt64(&collector.recordCount); recordCount != 0 {
+		t.Errorf("expected prefill to bypass the metrics collector, got %d records", recordCount)
+	}
+
+	adapter.shouldFail = true
+	result, err = engine.RunPrepare(context.Background(), 3, 2)
+	if err != nil || result.Failed != 3 || result.FirstError == nil {
+		t.Errorf("expected failed prefill to be reported, got %+v (%v)", result, err)
+	}
+
+	if _, err := NewExecutionEngine(adapter, collector, &mockOperationFactory{}).RunPrepare(context.Background(), 1, 1); err == nil {
+		t.Error("expected factories without prefill support to be rejected")
+	}
+}
+
+// forkingAdapter 为每个执行核建立独立连接的mock适配器
+type forkingAdapter struct {
+	mockProtocolAdapter
+	forks  atomic.Int64
+	closed *atomic.Int64
+}
+
+func (f *forkingAdapter) ForkConnection(ctx context.Context) (interfaces.ProtocolAdapter, error) {
+	f.forks.Add(1)
+	return &forkingAdapter{closed: f.closed}, nil
+}
+
+func (f *forkingAdapter) Close() error {
+	f.closed.Add(1)
+	return nil
+}
+
+// jobRecordingFactory 记录每个任务ID被创建次数的操作工厂
+type jobRecordingFactory struct {
+	jobs sync.Map
+}
+
+func (f *jobRecordingFactory) CreateOperation(jobID int, config BenchmarkConfig) interfaces.Operation {
+	count, _ := f.jobs.LoadOrStore(jobID, new(atomic.Int64))
+	count.(*atomic.Int64).Add(1)
+	return interfaces.Operation{Type: "read"}
+}
+
+func TestExecutionEngine_RunBenchmark_PerCore(t *testing.T) {
+	adapter := &forkingAdapter{closed: new(atomic.Int64)}
+	collector := metrics.NewBaseCollector(nil, map[string]interface{}{})
+	defer collector.Stop()
+	factory := &jobRecordingFactory{}
+
+	engine := NewExecutionEngine(adapter, collector, factory)
+	engine.SetCores(4)
+	result, err := engine.RunBenchmark(context.Background(), &mockBenchmarkConfig{total: 1000, parallels: 8})
+	if err != nil {
+		t.Fatalf("RunBenchmark failed: %v", err)
+	}
+
+	if result.Cores != 4 || result.ForkedCores != 3 || result.CompletedJobs != 1000 || result.SuccessJobs != 1000 {
+		t.Errorf("unexpected per-core result: %+v", result)
+	}
+	if forks, closed := adapter.forks.Load(), adapter.closed.Load(); forks != 3 || closed != 3 {
+		t.Errorf("expected 3 forked connections closed after the run, got %d forks and %d closes", forks, closed)
+	}
+
+	jobs := 0
+	factory.jobs.Range(func(key, value interface{}) bool {
+		jobs++
+		if count := value.(*atomic.Int64).Load(); count != 1 {
+			t.Errorf("job %v executed %d times", key, count)
+		}
+		return true
+	})
+	if jobs != 1000 {
+		t.Errorf("expected 1000 distinct jobs, got %d", jobs)
+	}
+
+	// 各核的本地分片在快照时合并
+	snapshot := collector.Snapshot()
+	if ops := snapshot.Core.Operations; ops.Total != 1000 || ops.Success != 1000 || ops.Read != 1000 || ops.Rate != 100 {
+		t.Errorf("unexpected merged operations: %+v", ops)
+	}
+	if snapshot.Core.Throughput.ReadRPS <= 0 {
+		t.Errorf("expected merged throughput, got %+v", snapshot.Core.Throughput)
+	}
+
+	collector.Reset()
+	if total := collector.Snapshot().Core.Operations.Total; total != 0 {
+		t.Errorf("expected Reset to clear the shards, got %d operations", total)
+	}
+}
+
+func TestExecutionEngine_RunBenchmark_PerCoreRampUp(t *testing.T) {
+	collector := metrics.NewBaseCollector(nil, map[string]interface{}{})
+	defer collector.Stop()
+	engine := NewExecutionEngine(&mockProtocolAdapter{}, collector, &mockOperationFactory{operationType: "write"})
+	engine.SetCores(2)
+
+	result, err := engine.RunBenchmark(context.Background(), &mockBenchmarkConfig{total: 20, parallels: 4, rampUp: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("RunBenchmark failed: %v", err)
+	}
+	if result.CompletedJobs != 20 || result.ForkedCores != 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if result.TotalDuration < 90*time.Millisecond {
+		t.Errorf("expected jobs to be paced over the ramp-up, finished in %v", result.TotalDuration)
+	}
+	if total := collector.Snapshot().Core.Operations.Write; total != 20 {
+		t.Errorf("expected 20 recorded writes, got %d", total)
+	}
+}
+
+func TestExecutionEngine_AbortDuringPerCoreRampUp(t *testing.T) {
+	engine := NewExecutionEngine(&mockProtocolAdapter{}, &mockMetricsCollector{}, &mockOperationFactory{operationType: "test"})
+	engine.SetCores(2)
+
+	// 每个任务间隔1s，中断应立即结束等待中的工作线程
+	time.AfterFunc(30*time.Millisecond, func() { engine.Abort("test") })
+	result, err := engine.RunBenchmark(context.Background(), &mockBenchmarkConfig{total: 20, parallels: 4, rampUp: 20 * time.Second})
+	if err != nil {
+		t.Fatalf("RunBenchmark failed: %v", err)
+	}
+	if result.Aborted != "test" || result.CompletedJobs != 0 {
+		t.Errorf("unexpected aborted result: %+v", result)
+	}
+	if result.TotalDuration > time.Second {
+		t.Errorf("expected the ramp-up wait to end on abort, run took %v", result.TotalDuration)
+	}
+}
+
+func TestExecutionEngine_Abort(t *testing.T) {
+	for _, cores := range []int{0, 2} {
+		adapter := &mockProtocolAdapter{executionDelay: 5 * time.Millisecond}
+		collector := &mockMetricsCollector{}
+		engine := NewExecutionEngine(adapter, collector, &mockOperationFactory{operationType: "test"})
+		engine.SetCores(cores)
+
+		time.AfterFunc(30*time.Millisecond, func() { engine.Abort("test") })
+		result, err := engine.RunBenchmark(context.Background(), &mockBenchmarkConfig{total: 100000, parallels: 4})
+		if err != nil {
+			t.Fatalf("cores=%d: RunBenchmark failed: %v", cores, err)
+		}
+		// 进行中的操作照常完成，不因中断而失败
+		if result.Aborted != "test" || result.CompletedJobs == 0 || result.CompletedJobs >= 100000 || result.FailedJobs != 0 {
+			t.Errorf("cores=%d: unexpected aborted result: %+v", cores, result)
+		}
+		if engine.IsRunning() {
+			t.Errorf("cores=%d: engine still running after abort", cores)
+		}
+	}
+
+	// 启用中断处理时SIGTERM中断运行，下一次运行重置中断状态
+	process, _ := os.FindProcess(os.Getpid())
+	engine := NewExecutionEngine(&mockProtocolAdapter{executionDelay: 5 * time.Millisecond}, &mockMetricsCollector{}, &mockOperationFactory{operationType: "test"})
+	var signalErr atomic.Value
+	time.AfterFunc(30*time.Millisecond, func() {
+		if err := process.Signal(syscall.SIGTERM); err != nil {
+			signalErr.Store(err)
+			engine.Abort("unsupported")
+		}
+	})
+	result, err := engine.RunBenchmark(WithInterruptHandling(context.Background()), &mockBenchmarkConfig{total: 100000, parallels: 4})
+	if err != nil {
+		t.Fatalf("RunBenchmark failed: %v", err)
+	}
+	if signalErr.Load() != nil {
+		t.Skipf("cannot signal the test process: %v", signalErr.Load())
+	}
+	if result.Aborted != syscall.SIGTERM.String() {
+		t.Errorf("expected the run to be aborted by SIGTERM, got %+v", result)
+	}
+	result, err = engine.RunBenchmark(context.Background(), &mockBenchmarkConfig{total: 5, parallels: 1})
+	if err != nil || result.Aborted != "" || result.CompletedJobs != 5 {
+		t.Errorf("expected a complete second run, got %+v, %v", result, err)
+	}
+}
+
+func TestExecutionEngine_Resume(t *testing.T) {
+	for _, cores := range []int{0, 2} {
+		collector := &mockMetricsCollector{}
+		factory := &recordingOperationFactory{}
+		engine := NewExecutionEngine(&mockProtocolAdapter{}, collector, factory)
+		engine.SetCores(cores)
+		engine.SetResume(10, 0)
+
+		result, err := engine.RunBenchmark(context.Background(), &mockBenchmarkConfig{total: 25, parallels: 2})
+		if err != nil {
+			t.Fatalf("cores=%d: RunBenchmark failed: %v", cores, err)
+		}
+		// 任务序号从检查点继续，只执行剩余的任务
+		if result.CompletedJobs != 15 || factory.min() != 10 || factory.max() != 24 {
+			t.Errorf("cores=%d: expected jobs 10-24, got %d jobs in %d-%d", cores, result.CompletedJobs, factory.min(), factory.max())
+		}
+		// 按核心执行时各worker并发记录结果，全部结果都应到达收集器
+		if recorded := atomic.LoadInt64(&collector.recordCount); recorded != 15 || len(collector.results) != 15 {
+			t.Errorf("cores=%d: expected 15 recorded results, got %d (%d kept)", cores, recorded, len(collector.results))
+		}
+	}
+
+	// 检查点已覆盖全部时长时不再执行
+	engine := NewExecutionEngine(&mockProtocolAdapter{}, &mockMetricsCollector{}, &mockOperationFactory{operationType: "test"})
+	engine.SetResume(100, time.Minute)
+	result, err := engine.RunBenchmark(context.Background(), &mockBenchmarkConfig{total: 1000, parallels: 1, duration: time.Minute})
+	if err != nil || result.CompletedJobs != 0 {
+		t.Errorf("expected nothing left to run, got %+v, %v", result, err)
+	}
+}
+
+// recordingOperationFactory 记录创建过的任务序号范围
+type recordingOperationFactory struct {
+	mutex sync.Mutex
+	ids   []int
+}
+
+func (r *recordingOperationFactory) CreateOperation(jobID int, config BenchmarkConfig) interfaces.Operation {
+	r.mutex.Lock()
+	r.ids = append(r.ids, jobID)
+	r.mutex.Unlock()
+	return interfaces.Operation{Type: "test", Key: fmt.Sprintf("key_%d", jobID)}
+}
+
+func (r *recordingOperationFactory) min() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return slices.Min(r.ids)
+}
+
+func (r *recordingOperationFactory) max() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return slices.Max(r.ids)
+}
+
+// benchmarkEngine 以零延迟的适配器运行b.N个操作，比较共享工作池与按核执行的调度开销
+func benchmarkEngine(b *testing.B, cores int) {
+	collector := metrics.NewBaseCollector(nil, map[string]interface{}{})
+	defer collector.Stop()
+	engine := NewExecutionEngine(&mockProtocolAdapter{}, collector, &mockOperationFactory{operationType: "read"})
+	engine.SetMaxWorkers(1000)
+	engine.SetCores(cores)
+
+	parallels := 4 * DefaultCores()
+	b.ReportAllocs()
+	b.ResetTimer()
+	result, err := engine.RunBenchmark(context.Background(), &mockBenchmarkConfig{total: b.N, parallels: parallels})
+	b.StopTimer()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportMetric(float64(result.CompletedJobs)/result.TotalDuration.Seconds(), "ops/s")
+}
+
+func BenchmarkExecutionEngine_SharedPool(b *testing.B) {
+	benchmarkEngine(b, 0)
+}
+
+func BenchmarkExecutionEngine_PerCore(b *testing.B) {
+	benchmarkEngine(b, DefaultCores())
+}
@@ -0,0 +1,60 @@
+package execution
+
+import (
+	"context"
+
+	"abc-runner/app/core/interfaces"
+)
+
+// Executor 执行一个操作，签名与ProtocolAdapter.Execute相同
+type Executor func(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error)
+
+// Interceptor 操作拦截器，包裹适配器的Execute
+// 可以在调用next之前修改context与操作，在之后修改结果与错误，或不调用next直接返回；
+// 同一拦截器会被所有工作协程并发调用
+type Interceptor interface {
+	Intercept(ctx context.Context, operation interfaces.Operation, next Executor) (*interfaces.OperationResult, error)
+}
+
+// InterceptorFunc 函数形式的拦截器
+type InterceptorFunc func(ctx context.Context, operation interfaces.Operation, next Executor) (*interfaces.OperationResult, error)
+
+// Intercept 调用函数本身
+func (f InterceptorFunc) Intercept(ctx context.Context, operation interfaces.Operation, next Executor) (*interfaces.OperationResult, error) {
+	return f(ctx, operation, next)
+}
+
+// ChainInterceptors 将拦截器依次包裹在executor外，第一个拦截器位于最外层
+func ChainInterceptors(executor Executor, interceptors ...Interceptor) Executor {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], executor
+		executor = func(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+			return interceptor.Intercept(ctx, operation, next)
+		}
+	}
+	return executor
+}
+
+// interceptorsKey context键
+type interceptorsKey struct{}
+
+// WithInterceptors 返回追加了拦截器的context，嵌入调用方以此为单次运行配置拦截器
+func WithInterceptors(ctx context.Context, interceptors ...Interceptor) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	existing := InterceptorsFromContext(ctx)
+	combined := make([]Interceptor, 0, len(existing)+len(interceptors))
+	combined = append(combined, existing...)
+	combined = append(combined, interceptors...)
+	return context.WithValue(ctx, interceptorsKey{}, combined)
+}
+
+// InterceptorsFromContext 从context中获取拦截器
+func InterceptorsFromContext(ctx context.Context) []Interceptor {
+	if ctx == nil {
+		return nil
+	}
+	interceptors, _ := ctx.Value(interceptorsKey{}).([]Interceptor)
+	return interceptors
+}
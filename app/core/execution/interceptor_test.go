@@ -0,0 +1,221 @@
+package execution
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+)
+
+func TestChainInterceptorsOrder(t *testing.T) {
+	var mutex sync.Mutex
+	var calls []string
+	record := func(name string) Interceptor {
+		return InterceptorFunc(func(ctx context.Context, operation interfaces.Operation, next Executor) (*interfaces.OperationResult, error) {
+			mutex.Lock()
+			calls = append(calls, name+">")
+			mutex.Unlock()
+			result, err := next(ctx, operation)
+			mutex.Lock()
+			calls = append(calls, "<"+name)
+			mutex.Unlock()
+			return result, err
+		})
+	}
+	final := func(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+		calls = append(calls, "execute")
+		return &interfaces.OperationResult{Success: true}, nil
+	}
+
+	if _, err := ChainInterceptors(final, record("a"), record("b"))(context.Background(), interfaces.Operation{}); err != nil {
+		t.Fatalf("chain failed: %v", err)
+	}
+	if got := strings.Join(calls, " "); got != "a> b> execute <b <a" {
+		t.Fatalf("unexpected call order: %s", got)
+	}
+}
+
+func TestExecutionEngine_InterceptorsAddedDuringRun(t *testing.T) {
+	var late atomic.Int64
+	lateInterceptor := InterceptorFunc(func(ctx context.Context, operation interfaces.Operation, next Executor) (*interfaces.OperationResult, error) {
+		late.Add(1)
+		return next(ctx, operation)
+	})
+
+	for _, cores := range []int{0, 2} {
+		late.Store(0)
+		engine := NewExecutionEngine(&mockProtocolAdapter{}, &mockMetricsCollector{}, &mockOperationFactory{operationType: "test"})
+		engine.SetCores(cores)
+		var once sync.Once
+		engine.AddInterceptors(InterceptorFunc(func(ctx context.Context, operation interfaces.Operation, next Executor) (*interfaces.OperationResult, error) {
+			once.Do(func() { engine.AddInterceptors(lateInterceptor) })
+			return next(ctx, operation)
+		}))
+
+		// 拦截器链在运行开始前组装，运行期间追加的拦截器从下次运行起生效
+		if _, err := engine.RunBenchmark(context.Background(), &mockBenchmarkConfig{total: 20, parallels: 4}); err != nil {
+			t.Fatalf("cores=%d: RunBenchmark failed: %v", cores, err)
+		}
+		if late.Load() != 0 {
+			t.Errorf("cores=%d: interceptor added mid-run was applied %d times", cores, late.Load())
+		}
+		if _, err := engine.RunBenchmark(context.Background(), &mockBenchmarkConfig{total: 20, parallels: 4}); err != nil {
+			t.Fatalf("cores=%d: RunBenchmark failed: %v", cores, err)
+		}
+		if late.Load() != 20 {
+			t.Errorf("cores=%d: expected the added interceptor on all 20 operations of the next run, got %d", cores, late.Load())
+		}
+	}
+}
+
+func TestParseInterceptor(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    Interceptor
+		wantErr bool
+	}{
+		{spec: "label:run=canary", want: &LabelInterceptor{Key: "run", Value: "canary"}},
+		{spec: "header:Authorization=Bearer a=b", want: &HeaderInterceptor{Name: "Authorization", Value: "Bearer a=b"}},
+		{spec: "delay:50ms", want: &DelayInterceptor{Delay: 50 * time.Millisecond, Ratio: 1}},
+		{spec: "delay:1s@0.25", want: &DelayInterceptor{Delay: time.Second, Ratio: 0.25}},
+		{spec: "error:0.01", want: &ErrorInterceptor{Ratio: 0.01}},
+		{spec: "label", wantErr: true},
+		{spec: "label:novalue", wantErr: true},
+		{spec: "header:=x", wantErr: true},
+		{spec: "delay:-1s", wantErr: true},
+		{spec: "delay:1s@2", wantErr: true},
+		{spec: "error:5%", wantErr: true},
+		{spec: "retry:3", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseInterceptor(tt.spec)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", tt.spec)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.spec, err)
+			continue
+		}
+		gotJSON, _ := json.Marshal(got)
+		wantJSON, _ := json.Marshal(tt.want)
+		if string(gotJSON) != string(wantJSON) {
+			t.Errorf("%s: got %T %s, want %T %s", tt.spec, got, gotJSON, tt.want, wantJSON)
+		}
+	}
+}
+
+func TestExecutionEngineInterceptors(t *testing.T) {
+	adapter := &headerRecordingAdapter{}
+	collector := &mockMetricsCollector{}
+	engine := NewExecutionEngine(adapter, collector, &mockOperationFactory{operationType: "test"})
+	engine.SetMaxWorkers(2)
+	engine.AddInterceptors(
+		&LabelInterceptor{Key: "scenario", Value: "canary"},
+		&HeaderInterceptor{Name: "Authorization", Value: "Bearer token"},
+		&DelayInterceptor{Delay: 5 * time.Millisecond, Ratio: 1},
+	)
+
+	if _, err := engine.RunBenchmark(context.Background(), &mockBenchmarkConfig{total: 10, parallels: 2}); err != nil {
+		t.Fatalf("RunBenchmark failed: %v", err)
+	}
+	if adapter.headers.Load() != 10 {
+		t.Errorf("expected the header on 10 operations, got %d", adapter.headers.Load())
+	}
+	for _, result := range collector.results {
+		if !result.Success || result.Metadata["scenario"] != "canary" || result.Duration < 5*time.Millisecond {
+			t.Fatalf("unexpected result: %+v", result)
+		}
+	}
+
+	// 注入的错误不发往适配器，结果保留拦截器写入的元数据
+	adapter = &headerRecordingAdapter{}
+	collector = &mockMetricsCollector{}
+	engine = NewExecutionEngine(adapter, collector, &mockOperationFactory{operationType: "test"})
+	engine.AddInterceptors(&LabelInterceptor{Key: "scenario", Value: "faults"}, &ErrorInterceptor{Ratio: 1})
+	result, err := engine.RunBenchmark(context.Background(), &mockBenchmarkConfig{total: 5, parallels: 1})
+	if err != nil {
+		t.Fatalf("RunBenchmark failed: %v", err)
+	}
+	if result.FailedJobs != 5 || adapter.executeCount != 0 {
+		t.Fatalf("expected 5 injected failures, got %+v (adapter calls %d)", result, adapter.executeCount)
+	}
+	for _, result := range collector.results {
+		if !errors.Is(result.Error, ErrInjectedFault) || result.Metadata["scenario"] != "faults" || result.Metadata["fault"] != "error" {
+			t.Fatalf("unexpected failure metadata: %+v", result.Metadata)
+		}
+	}
+}
+
+// headerRecordingAdapter 统计携带Authorization头部的操作数
+type headerRecordingAdapter struct {
+	mockProtocolAdapter
+	headers atomic.Int64
+}
+
+func (h *headerRecordingAdapter) Execute(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+	for _, header := range HeadersFromContext(ctx) {
+		if header == [2]string{"Authorization", "Bearer token"} {
+			h.headers.Add(1)
+		}
+	}
+	return h.mockProtocolAdapter.Execute(ctx, operation)
+}
+
+func TestRequestLogger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "requests.jsonl")
+	logger, err := NewRequestLogger(path)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	ok := func(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+		return &interfaces.OperationResult{Success: true, Duration: 3 * time.Millisecond}, nil
+	}
+	failed := func(ctx context.Context, operation interfaces.Operation) (*interfaces.OperationResult, error) {
+		return nil, errors.New("refused")
+	}
+	ctx := WithRequestID(context.Background(), "abc-1")
+	logger.Intercept(ctx, interfaces.Operation{Type: "get", Key: "k1"}, ok)
+	if _, err := logger.Intercept(context.Background(), interfaces.Operation{Type: "set"}, failed); err == nil || err.Error() != "refused" {
+		t.Fatalf("logger changed the error: %v", err)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open log: %v", err)
+	}
+	defer file.Close()
+	var entries []requestLogEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry requestLogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("invalid line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 2 || logger.Count() != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+	if e := entries[0]; e.Operation != "get" || e.Key != "k1" || !e.Success || e.DurationNs != int64(3*time.Millisecond) || e.RequestID != "abc-1" {
+		t.Errorf("unexpected first entry: %+v", e)
+	}
+	if e := entries[1]; e.Operation != "set" || e.Success || e.Error != "refused" {
+		t.Errorf("unexpected second entry: %+v", e)
+	}
+}
@@ -0,0 +1,267 @@
+package execution
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+)
+
+// ErrInjectedFault 错误注入拦截器使操作失败时返回的错误
+var ErrInjectedFault = errors.New("injected fault")
+
+// ParseInterceptor 解析--intercept的拦截器描述：
+//
+//	label:KEY=VALUE     在每个结果的元数据中写入KEY=VALUE
+//	header:NAME=VALUE   在请求中附加头部（HTTP请求头、gRPC metadata、Kafka消息头），如认证令牌
+//	delay:DUR[@RATIO]   在按比例选中的操作前等待DUR，计入延迟（RATIO默认1）
+//	error:RATIO         按比例使操作直接失败，不发往目标
+//	log:FILE            将每个操作以JSON行写入FILE
+func ParseInterceptor(spec string) (Interceptor, error) {
+	kind, arg, ok := strings.Cut(spec, ":")
+	if !ok || arg == "" {
+		return nil, fmt.Errorf("invalid interceptor %q (expected KIND:ARG)", spec)
+	}
+	switch kind {
+	case "label", "header":
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid %s interceptor %q (expected %s:NAME=VALUE)", kind, spec, kind)
+		}
+		if kind == "label" {
+			return &LabelInterceptor{Key: name, Value: value}, nil
+		}
+		return &HeaderInterceptor{Name: name, Value: value}, nil
+	case "delay":
+		durationText, ratioText, hasRatio := strings.Cut(arg, "@")
+		delay, err := time.ParseDuration(durationText)
+		if err != nil || delay <= 0 {
+			return nil, fmt.Errorf("invalid delay interceptor %q (expected a positive duration)", spec)
+		}
+		ratio := 1.0
+		if hasRatio {
+			if ratio, err = parseRatio(ratioText); err != nil {
+				return nil, fmt.Errorf("invalid delay interceptor %q: %w", spec, err)
+			}
+		}
+		return &DelayInterceptor{Delay: delay, Ratio: ratio}, nil
+	case "error":
+		ratio, err := parseRatio(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid error interceptor %q: %w", spec, err)
+		}
+		return &ErrorInterceptor{Ratio: ratio}, nil
+	case "log":
+		return NewRequestLogger(arg)
+	default:
+		return nil, fmt.Errorf("unknown interceptor %q (expected label, header, delay, error or log)", kind)
+	}
+}
+
+// parseRatio 解析0到1之间的比例
+func parseRatio(text string) (float64, error) {
+	ratio, err := strconv.ParseFloat(text, 64)
+	if err != nil || ratio < 0 || ratio > 1 {
+		return 0, fmt.Errorf("ratio %q must be between 0 and 1", text)
+	}
+	return ratio, nil
+}
+
+// sampled 按比例选中
+func sampled(ratio float64) bool {
+	return ratio >= 1 || (ratio > 0 && rand.Float64() < ratio)
+}
+
+// LabelInterceptor 在结果元数据中写入固定标签，便于在原始样本与观察器中区分
+type LabelInterceptor struct {
+	Key   string
+	Value string
+}
+
+// Intercept 执行操作并写入标签
+func (l *LabelInterceptor) Intercept(ctx context.Context, operation interfaces.Operation, next Executor) (*interfaces.OperationResult, error) {
+	result, err := next(ctx, operation)
+	if result != nil {
+		if result.Metadata == nil {
+			result.Metadata = make(map[string]interface{})
+		}
+		result.Metadata[l.Key] = l.Value
+	}
+	return result, err
+}
+
+// HeaderInterceptor 经context为每个请求附加头部
+type HeaderInterceptor struct {
+	Name  string
+	Value string
+}
+
+// Intercept 附加头部后执行操作
+func (h *HeaderInterceptor) Intercept(ctx context.Context, operation interfaces.Operation, next Executor) (*interfaces.OperationResult, error) {
+	return next(WithHeader(ctx, h.Name, h.Value), operation)
+}
+
+// headersKey context键
+type headersKey struct{}
+
+// WithHeader 返回追加了请求头部的context
+// 支持的适配器将其注入请求（HTTP请求头、gRPC metadata、Kafka消息头）
+func WithHeader(ctx context.Context, name, value string) context.Context {
+	existing := HeadersFromContext(ctx)
+	headers := make([][2]string, 0, len(existing)+1)
+	headers = append(headers, existing...)
+	headers = append(headers, [2]string{name, value})
+	return context.WithValue(ctx, headersKey{}, headers)
+}
+
+// HeadersFromContext 从context中获取按添加顺序排列的请求头部
+func HeadersFromContext(ctx context.Context) [][2]string {
+	if ctx == nil {
+		return nil
+	}
+	headers, _ := ctx.Value(headersKey{}).([][2]string)
+	return headers
+}
+
+// DelayInterceptor 在按比例选中的操作前注入固定延迟，模拟慢网络或慢依赖
+type DelayInterceptor struct {
+	Delay time.Duration
+	Ratio float64
+}
+
+// Intercept 等待后执行操作，注入的延迟计入操作耗时
+func (d *DelayInterceptor) Intercept(ctx context.Context, operation interfaces.Operation, next Executor) (*interfaces.OperationResult, error) {
+	if !sampled(d.Ratio) {
+		return next(ctx, operation)
+	}
+	timer := time.NewTimer(d.Delay)
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		timer.Stop()
+		return nil, ctx.Err()
+	}
+	result, err := next(ctx, operation)
+	// 适配器自行计时的结果不含等待时间，为0时由引擎测量整个调用链
+	if result != nil && result.Duration > 0 {
+		result.Duration += d.Delay
+	}
+	return result, err
+}
+
+// ErrorInterceptor 按比例使操作直接失败，模拟目标错误
+type ErrorInterceptor struct {
+	Ratio float64
+}
+
+// Intercept 选中时返回ErrInjectedFault，否则执行操作
+func (e *ErrorInterceptor) Intercept(ctx context.Context, operation interfaces.Operation, next Executor) (*interfaces.OperationResult, error) {
+	if !sampled(e.Ratio) {
+		return next(ctx, operation)
+	}
+	return &interfaces.OperationResult{
+		Success:  false,
+		Error:    ErrInjectedFault,
+		Metadata: map[string]interface{}{"fault": "error"},
+	}, ErrInjectedFault
+}
+
+// requestLogEntry 请求日志中的一行
+type requestLogEntry struct {
+	Time       time.Time `json:"time"`
+	Operation  string    `json:"operation"`
+	Key        string    `json:"key,omitempty"`
+	DurationNs int64     `json:"duration_ns"`
+	Success    bool      `json:"success"`
+	Error      string    `json:"error,omitempty"`
+	RequestID  string    `json:"request_id,omitempty"`
+}
+
+// RequestLogger 将每个操作以JSON行写入文件的拦截器，运行结束后需调用Close
+type RequestLogger struct {
+	path   string
+	file   *os.File
+	mutex  sync.Mutex
+	writer *bufio.Writer
+	count  int64
+}
+
+// NewRequestLogger 创建请求日志文件
+func NewRequestLogger(path string) (*RequestLogger, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request log: %w", err)
+	}
+	return &RequestLogger{path: path, file: file, writer: bufio.NewWriterSize(file, 64*1024)}, nil
+}
+
+// Intercept 执行操作并记录一行日志
+func (l *RequestLogger) Intercept(ctx context.Context, operation interfaces.Operation, next Executor) (*interfaces.OperationResult, error) {
+	start := time.Now()
+	result, err := next(ctx, operation)
+
+	entry := requestLogEntry{
+		Time:       start,
+		Operation:  operation.Type,
+		Key:        operation.Key,
+		DurationNs: int64(time.Since(start)),
+		Success:    err == nil && result != nil && result.Success,
+	}
+	if result != nil && result.Duration > 0 {
+		entry.DurationNs = int64(result.Duration)
+	}
+	failure := err
+	if failure == nil && result != nil {
+		failure = result.Error
+	}
+	if failure != nil {
+		entry.Error = failure.Error()
+	}
+	entry.RequestID, _ = RequestIDFromContext(ctx)
+	line, _ := json.Marshal(entry)
+
+	l.mutex.Lock()
+	if l.writer != nil {
+		l.writer.Write(line)
+		l.writer.WriteByte('\n')
+		l.count++
+	}
+	l.mutex.Unlock()
+	return result, err
+}
+
+// Path 日志文件路径
+func (l *RequestLogger) Path() string {
+	return l.path
+}
+
+// Count 已记录的操作数
+func (l *RequestLogger) Count() int64 {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.count
+}
+
+// Close 写完缓冲并关闭文件
+func (l *RequestLogger) Close() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.writer == nil {
+		return nil
+	}
+	err := l.writer.Flush()
+	l.writer = nil
+	if closeErr := l.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
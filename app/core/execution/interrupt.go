@@ -0,0 +1,95 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// interruptHandlingKey context键
+type interruptHandlingKey struct{}
+
+// WithInterruptHandling 返回启用中断处理的context
+// 执行引擎运行期间捕获SIGINT/SIGTERM并中断运行（见Abort）；收到信号后恢复默认处理，再次中断立即结束进程。
+// 由命令行入口启用，嵌入调用方通过取消context自行控制
+func WithInterruptHandling(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, interruptHandlingKey{}, true)
+}
+
+// InterruptHandlingFromContext context是否启用了中断处理
+func InterruptHandlingFromContext(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	enabled, _ := ctx.Value(interruptHandlingKey{}).(bool)
+	return enabled
+}
+
+// Abort 中断正在进行的运行：停止派发新任务，进行中的操作照常完成并计入指标，
+// RunBenchmark随后正常返回，结果的Aborted为reason；未在运行或已中断时无效
+func (e *ExecutionEngine) Abort(reason string) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if e.abort == nil || e.abortReason != "" {
+		return
+	}
+	e.abortReason = reason
+	close(e.abort)
+}
+
+// AbortReason 最近一次运行的中断原因，未中断时为空
+func (e *ExecutionEngine) AbortReason() string {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	return e.abortReason
+}
+
+// resetAbort 为新的运行重置中断状态
+func (e *ExecutionEngine) resetAbort() {
+	e.mutex.Lock()
+	e.abort = make(chan struct{})
+	e.abortReason = ""
+	e.mutex.Unlock()
+}
+
+// abortSignal 本次运行的中断通道，中断时关闭
+func (e *ExecutionEngine) abortSignal() <-chan struct{} {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	return e.abort
+}
+
+// aborted 本次运行是否已中断
+func aborted(abort <-chan struct{}) bool {
+	select {
+	case <-abort:
+		return true
+	default:
+		return false
+	}
+}
+
+// watchInterrupts 在运行期间捕获SIGINT/SIGTERM，返回停止捕获的函数
+func (e *ExecutionEngine) watchInterrupts() func() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case sig := <-signals:
+			signal.Stop(signals)
+			fmt.Fprintf(os.Stderr, "\n⚠️  Received %v: waiting for in-flight operations, then writing the report (press Ctrl+C again to quit immediately)\n", sig)
+			e.Abort(sig.String())
+		case <-done:
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
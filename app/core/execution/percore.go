@@ -0,0 +1,249 @@
+package execution
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+)
+
+// ConnectionForker 可为每个执行核建立独立连接的适配器（可选实现）
+// 按核执行时每个核使用自己的连接；未实现或返回错误时该核与其他核共享原适配器的连接
+type ConnectionForker interface {
+	ForkConnection(ctx context.Context) (interfaces.ProtocolAdapter, error)
+}
+
+// shardedCollector 支持本地指标分片的收集器
+type shardedCollector interface {
+	NewShard() *metrics.MetricsShard
+}
+
+// coreState 单个执行核的状态，只由本核的工作线程访问，按缓存行填充避免核间伪共享
+type coreState struct {
+	id        int
+	cpu       int // 绑定的CPU，-1表示不绑定
+	adapter   interfaces.ProtocolAdapter
+	forked    bool
+	execute   Executor // 经拦截器链调用adapter，运行开始前组装
+	record    func(result *interfaces.OperationResult)
+	next      atomic.Int64 // 本核内下一个任务序号
+	completed atomic.Int64
+	success   atomic.Int64
+	failed    atomic.Int64
+	_         [64]byte
+}
+
+// SetCores 启用按核执行（thread-per-core）：cores个执行核各自绑定一个CPU，
+// 拥有独立的连接（适配器实现ConnectionForker时）与本地指标分片（收集器快照时合并），
+// 任务按序号在核间交错分配，核之间不经过共享通道或锁；0表示使用共享工作池
+func (e *ExecutionEngine) SetCores(cores int) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if cores >= 0 {
+		e.cores = cores
+	}
+}
+
+// DefaultCores 按核执行的默认核数：当前进程可用的CPU数
+func DefaultCores() int {
+	if cpus := allowedCPUs(); len(cpus) > 0 {
+		return len(cpus)
+	}
+	return runtime.NumCPU()
+}
+
+// runPerCore 按核执行基准测试
+// 并发数在各核之间平均分配，每个工作协程锁定到一个OS线程并绑定到所属核的CPU，
+// 并发数等于核数时即每核一个线程；线程在工作协程退出时随之销毁，不把亲和性带回调度器
+func (e *ExecutionEngine) runPerCore(ctx context.Context, config BenchmarkConfig, cores int, startTime time.Time) *ExecutionResult {
+	e.mutex.RLock()
+	maxWorkers := e.maxWorkers
+	e.mutex.RUnlock()
+
+	workerCount := config.GetParallels()
+	if workerCount <= 0 {
+		workerCount = 1
+	}
+	if workerCount > maxWorkers {
+		workerCount = maxWorkers
+	}
+	if cores > workerCount {
+		cores = workerCount
+	}
+	duration := e.runDurationOf(config)
+	e.beginRun(startTime, workerCount, duration)
+
+	total, offset := e.jobRange(config)
+	atomic.StoreInt64(&e.totalJobs, int64(total))
+
+	jobCtx := ctx
+	if duration > 0 {
+		var cancel context.CancelFunc
+		jobCtx, cancel = context.WithTimeout(ctx, duration)
+		defer cancel()
+	}
+
+	var interval time.Duration
+	if rampUp := config.GetRampUp(); rampUp > 0 && total > 0 {
+		interval = rampUp / time.Duration(total)
+		if interval < time.Microsecond {
+			interval = time.Microsecond
+		}
+	}
+
+	states := e.prepareCores(ctx, cores)
+	defer func() {
+		for _, state := range states {
+			if state.forked {
+				state.adapter.Close()
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	workerID := 0
+	for _, state := range states {
+		workers := workerCount / cores
+		if state.id < workerCount%cores {
+			workers++
+		}
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go e.coreWorker(jobCtx, state, workerID, cores, total, offset, startTime, interval, config, &wg)
+			workerID++
+		}
+	}
+	wg.Wait()
+
+	endTime := time.Now()
+	result := &ExecutionResult{
+		TotalJobs:     int64(total),
+		TotalDuration: endTime.Sub(startTime),
+		StartTime:     startTime,
+		EndTime:       endTime,
+		Cores:         cores,
+	}
+	for _, state := range states {
+		result.CompletedJobs += state.completed.Load()
+		result.SuccessJobs += state.success.Load()
+		result.FailedJobs += state.failed.Load()
+		if state.forked {
+			result.ForkedCores++
+		}
+	}
+	atomic.StoreInt64(&e.completedJobs, result.CompletedJobs)
+	atomic.StoreInt64(&e.successJobs, result.SuccessJobs)
+	atomic.StoreInt64(&e.failedJobs, result.FailedJobs)
+	return result
+}
+
+// prepareCores 为每个核分配CPU、连接与指标分片
+func (e *ExecutionEngine) prepareCores(ctx context.Context, cores int) []*coreState {
+	cpus := allowedCPUs()
+	forker, canFork := e.adapter.(ConnectionForker)
+	sharded, canShard := e.metricsCollector.(shardedCollector)
+
+	states := make([]*coreState, cores)
+	for i := range states {
+		state := &coreState{id: i, cpu: -1, adapter: e.adapter}
+		if len(cpus) > 0 {
+			state.cpu = cpus[i%len(cpus)]
+		}
+		// 第一个核沿用原适配器，其余核建立独立连接
+		if canFork && i > 0 {
+			if adapter, err := forker.ForkConnection(ctx); err == nil {
+				state.adapter, state.forked = adapter, true
+			}
+		}
+		state.execute = e.executor(state.adapter)
+		switch {
+		case canShard:
+			state.record = sharded.NewShard().Record
+		case e.metricsCollector != nil:
+			state.record = e.metricsCollector.Record
+		default:
+			state.record = func(*interfaces.OperationResult) {}
+		}
+		states[i] = state
+	}
+	return states
+}
+
+// coreWorker 执行核的工作线程，执行本核分到的任务：core, core+cores, core+2*cores, ...
+func (e *ExecutionEngine) coreWorker(ctx context.Context, state *coreState, workerID, cores, total, offset int, startTime time.Time, interval time.Duration, config BenchmarkConfig, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	// 不调用UnlockOSThread：协程退出时线程随之销毁，绑定的亲和性不会影响其他协程
+	runtime.LockOSThread()
+	if state.cpu >= 0 {
+		_ = pinToCPU(state.cpu)
+	}
+	jobCtx := WithWorkerID(ctx, workerID)
+	abort := e.abortSignal()
+
+	for ctx.Err() == nil && !aborted(abort) {
+		id := state.id + int(state.next.Add(1)-1)*cores
+		if id >= total {
+			return
+		}
+
+		scheduledAt := time.Now()
+		if interval > 0 {
+			scheduledAt = startTime.Add(time.Duration(id+1) * interval)
+			if !sleepUntil(ctx, abort, scheduledAt) {
+				return
+			}
+		}
+
+		job := Job{
+			ID:          offset + id,
+			Operation:   e.operationFactory.CreateOperation(offset+id, config),
+			Context:     jobCtx,
+			ScheduledAt: scheduledAt,
+		}
+		startedAt := time.Now()
+		result := e.executeJob(state.execute, job)
+
+		if e.scheduleTracer != nil {
+			e.scheduleTracer.Record(ScheduleTraceEvent{
+				JobID:         job.ID,
+				WorkerID:      workerID,
+				OperationType: job.Operation.Type,
+				ScheduledAt:   job.ScheduledAt,
+				StartedAt:     startedAt,
+				FinishedAt:    time.Now(),
+				Success:       result.Success,
+			})
+		}
+
+		state.record(result)
+		state.completed.Add(1)
+		if result.Success {
+			state.success.Add(1)
+		} else {
+			state.failed.Add(1)
+		}
+	}
+}
+
+// sleepUntil 等待到指定时间，context结束或运行中断时返回false
+func sleepUntil(ctx context.Context, abort <-chan struct{}, t time.Time) bool {
+	wait := time.Until(t)
+	if wait <= 0 {
+		return ctx.Err() == nil && !aborted(abort)
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	case <-abort:
+		return false
+	}
+}
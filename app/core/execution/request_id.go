@@ -0,0 +1,61 @@
+package execution
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"sync/atomic"
+)
+
+// 请求ID在各协议中的注入位置
+const (
+	RequestIDHeader   = "X-Request-ID" // HTTP请求头
+	RequestIDMetadata = "x-request-id" // gRPC metadata与Kafka消息头
+)
+
+// RequestIDGenerator 请求ID生成器
+// ID形如"3f9c2a1b-42"：每次运行随机的8位十六进制前缀加递增序号，运行内唯一，
+// 跨运行也可区分，便于在目标端日志与链路追踪中检索
+type RequestIDGenerator struct {
+	prefix   string
+	sequence atomic.Uint64
+}
+
+// NewRequestIDGenerator 创建请求ID生成器
+func NewRequestIDGenerator() *RequestIDGenerator {
+	var random [4]byte
+	rand.Read(random[:])
+	return &RequestIDGenerator{prefix: hex.EncodeToString(random[:]) + "-"}
+}
+
+// Prefix 本次运行的ID前缀（不含分隔符）
+func (g *RequestIDGenerator) Prefix() string {
+	return g.prefix[:len(g.prefix)-1]
+}
+
+// Next 生成下一个请求ID
+func (g *RequestIDGenerator) Next() string {
+	return g.prefix + strconv.FormatUint(g.sequence.Add(1), 10)
+}
+
+// requestIDKey context键
+type requestIDKey struct{}
+
+// WithRequestID 返回携带请求ID的context
+// 支持的适配器将其注入请求（HTTP请求头、gRPC metadata、Kafka消息头）
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext 从context中获取当前操作的请求ID
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	requestID, ok := ctx.Value(requestIDKey{}).(string)
+	return requestID, ok && requestID != ""
+}
@@ -0,0 +1,53 @@
+package execution
+
+import "time"
+
+// SetResume 从检查点续跑：任务序号从jobs继续，总任务数与运行时长扣除已完成的jobs个任务与elapsed时长
+func (e *ExecutionEngine) SetResume(jobs int64, elapsed time.Duration) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if jobs < 0 {
+		jobs = 0
+	}
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	e.resumeJobs = jobs
+	e.resumeElapsed = elapsed
+}
+
+// jobRange 本次运行的任务数与起始任务序号
+func (e *ExecutionEngine) jobRange(config BenchmarkConfig) (total, offset int) {
+	e.mutex.RLock()
+	offset = int(e.resumeJobs)
+	e.mutex.RUnlock()
+	total = config.GetTotal() - offset
+	if total < 0 {
+		total = 0
+	}
+	return total, offset
+}
+
+// runDurationOf 本次运行的时长，0表示按任务数运行
+func (e *ExecutionEngine) runDurationOf(config BenchmarkConfig) time.Duration {
+	duration := config.GetDuration()
+	if duration <= 0 {
+		return 0
+	}
+	e.mutex.RLock()
+	elapsed := e.resumeElapsed
+	e.mutex.RUnlock()
+	return duration - elapsed
+}
+
+// resumeFinished 续跑时检查点是否已覆盖全部任务或时长
+func (e *ExecutionEngine) resumeFinished(config BenchmarkConfig) bool {
+	e.mutex.RLock()
+	resumed := e.resumeJobs > 0 || e.resumeElapsed > 0
+	e.mutex.RUnlock()
+	if !resumed {
+		return false
+	}
+	total, _ := e.jobRange(config)
+	return total == 0 || (config.GetDuration() > 0 && e.runDurationOf(config) <= 0)
+}
@@ -0,0 +1,313 @@
+package execution
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// 调度追踪导出格式
+const (
+	TraceFormatChrome = "chrome" // Chrome trace event格式 (chrome://tracing / Perfetto)
+	TraceFormatOTLP   = "otlp"   // OTLP/JSON span格式
+)
+
+// ScheduleTraceEvent 单个操作的调度追踪事件
+type ScheduleTraceEvent struct {
+	JobID         int       `json:"job_id"`         // 任务ID
+	WorkerID      int       `json:"worker_id"`      // 执行的工作协程ID
+	OperationType string    `json:"operation_type"` // 操作类型
+	ScheduledAt   time.Time `json:"scheduled_at"`   // 计划开始时间
+	StartedAt     time.Time `json:"started_at"`     // 实际开始时间
+	FinishedAt    time.Time `json:"finished_at"`    // 完成时间
+	Success       bool      `json:"success"`        // 是否成功
+}
+
+// Lag 实际开始时间相对计划时间的滞后
+func (e ScheduleTraceEvent) Lag() time.Duration {
+	return e.StartedAt.Sub(e.ScheduledAt)
+}
+
+// ScheduleTraceSummary 调度偏差汇总
+type ScheduleTraceSummary struct {
+	Events     int           `json:"events"`      // 记录的事件数
+	Dropped    int64         `json:"dropped"`     // 超出容量被丢弃的事件数
+	AverageLag time.Duration `json:"average_lag"` // 平均滞后
+	P99Lag     time.Duration `json:"p99_lag"`     // P99滞后
+	MaxLag     time.Duration `json:"max_lag"`     // 最大滞后
+}
+
+// ScheduleTracer 调度追踪器，记录计划与实际的操作开始时间
+type ScheduleTracer struct {
+	events    []ScheduleTraceEvent
+	maxEvents int
+	dropped   int64
+	origin    time.Time
+	mutex     sync.Mutex
+}
+
+// NewScheduleTracer 创建调度追踪器，maxEvents<=0时使用默认容量
+func NewScheduleTracer(maxEvents int) *ScheduleTracer {
+	if maxEvents <= 0 {
+		maxEvents = 1000000
+	}
+	return &ScheduleTracer{
+		events:    make([]ScheduleTraceEvent, 0, 1024),
+		maxEvents: maxEvents,
+	}
+}
+
+// Start 标记追踪起点，导出时间戳均相对于该时间
+func (t *ScheduleTracer) Start(origin time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.origin = origin
+	t.events = t.events[:0]
+	t.dropped = 0
+}
+
+// Record 记录一个调度事件
+func (t *ScheduleTracer) Record(event ScheduleTraceEvent) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if len(t.events) >= t.maxEvents {
+		t.dropped++
+		return
+	}
+	t.events = append(t.events, event)
+}
+
+// Events 获取按计划时间排序的事件副本
+func (t *ScheduleTracer) Events() []ScheduleTraceEvent {
+	t.mutex.Lock()
+	events := make([]ScheduleTraceEvent, len(t.events))
+	copy(events, t.events)
+	t.mutex.Unlock()
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].ScheduledAt.Before(events[j].ScheduledAt)
+	})
+	return events
+}
+
+// Summary 计算调度滞后汇总
+func (t *ScheduleTracer) Summary() ScheduleTraceSummary {
+	events := t.Events()
+
+	t.mutex.Lock()
+	summary := ScheduleTraceSummary{Events: len(events), Dropped: t.dropped}
+	t.mutex.Unlock()
+
+	if len(events) == 0 {
+		return summary
+	}
+
+	lags := make([]time.Duration, len(events))
+	var total time.Duration
+	for i, event := range events {
+		lags[i] = event.Lag()
+		total += lags[i]
+	}
+	sort.Slice(lags, func(i, j int) bool { return lags[i] < lags[j] })
+
+	index := int(float64(len(lags)) * 0.99)
+	if index >= len(lags) {
+		index = len(lags) - 1
+	}
+
+	summary.AverageLag = total / time.Duration(len(lags))
+	summary.P99Lag = lags[index]
+	summary.MaxLag = lags[len(lags)-1]
+	return summary
+}
+
+// Export 按指定格式导出追踪数据
+func (t *ScheduleTracer) Export(w io.Writer, format string) error {
+	switch format {
+	case TraceFormatChrome, "":
+		return t.exportChrome(w)
+	case TraceFormatOTLP:
+		return t.exportOTLP(w)
+	default:
+		return fmt.Errorf("unsupported schedule trace format: %s", format)
+	}
+}
+
+// ExportToFile 导出追踪数据到文件
+func (t *ScheduleTracer) ExportToFile(path, format string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create trace file: %w", err)
+	}
+	defer file.Close()
+
+	if err := t.Export(file, format); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// chromeTraceEvent Chrome trace event格式的单个事件
+type chromeTraceEvent struct {
+	Name  string                 `json:"name"`
+	Cat   string                 `json:"cat"`
+	Phase string                 `json:"ph"`
+	TS    float64                `json:"ts"`
+	Dur   float64                `json:"dur,omitempty"`
+	PID   int                    `json:"pid"`
+	TID   int                    `json:"tid"`
+	Scope string                 `json:"s,omitempty"`
+	Args  map[string]interface{} `json:"args,omitempty"`
+}
+
+// exportChrome 导出Chrome trace event格式
+// 每个操作生成一个计划时刻的瞬时事件(scheduler线程)和一个实际执行区间(对应worker线程)，
+// 另附调度滞后计数器，便于在chrome://tracing或Perfetto中直观比对
+func (t *ScheduleTracer) exportChrome(w io.Writer) error {
+	events := t.Events()
+	origin := t.traceOrigin(events)
+
+	micros := func(ts time.Time) float64 {
+		return float64(ts.Sub(origin).Nanoseconds()) / 1000.0
+	}
+
+	traceEvents := make([]chromeTraceEvent, 0, len(events)*3)
+	for _, event := range events {
+		name := event.OperationType
+		if name == "" {
+			name = "operation"
+		}
+
+		traceEvents = append(traceEvents, chromeTraceEvent{
+			Name:  "scheduled",
+			Cat:   "schedule",
+			Phase: "i",
+			TS:    micros(event.ScheduledAt),
+			PID:   1,
+			TID:   0,
+			Scope: "t",
+			Args:  map[string]interface{}{"job_id": event.JobID},
+		})
+		traceEvents = append(traceEvents, chromeTraceEvent{
+			Name:  name,
+			Cat:   "operation",
+			Phase: "X",
+			TS:    micros(event.StartedAt),
+			Dur:   float64(event.FinishedAt.Sub(event.StartedAt).Nanoseconds()) / 1000.0,
+			PID:   1,
+			TID:   event.WorkerID + 1,
+			Args: map[string]interface{}{
+				"job_id":       event.JobID,
+				"scheduled_us": micros(event.ScheduledAt),
+				"lag_us":       float64(event.Lag().Nanoseconds()) / 1000.0,
+				"success":      event.Success,
+			},
+		})
+		traceEvents = append(traceEvents, chromeTraceEvent{
+			Name:  "schedule_lag",
+			Cat:   "schedule",
+			Phase: "C",
+			TS:    micros(event.StartedAt),
+			PID:   1,
+			Args:  map[string]interface{}{"lag_us": float64(event.Lag().Nanoseconds()) / 1000.0},
+		})
+	}
+
+	summary := t.Summary()
+	output := map[string]interface{}{
+		"traceEvents":     traceEvents,
+		"displayTimeUnit": "ms",
+		"otherData": map[string]interface{}{
+			"origin":         origin.Format(time.RFC3339Nano),
+			"events":         summary.Events,
+			"dropped":        summary.Dropped,
+			"average_lag_us": float64(summary.AverageLag.Nanoseconds()) / 1000.0,
+			"p99_lag_us":     float64(summary.P99Lag.Nanoseconds()) / 1000.0,
+			"max_lag_us":     float64(summary.MaxLag.Nanoseconds()) / 1000.0,
+		},
+	}
+
+	encoder := json.NewEncoder(w)
+	return encoder.Encode(output)
+}
+
+// exportOTLP 导出OTLP/JSON格式 (ExportTraceServiceRequest)
+// 每个操作对应一个span，计划时间和滞后作为span属性，可被OpenTelemetry Collector的文件接收器读取
+func (t *ScheduleTracer) exportOTLP(w io.Writer) error {
+	events := t.Events()
+	traceID := fmt.Sprintf("%016x%016x", t.traceOrigin(events).UnixNano(), uint64(len(events)))
+
+	spans := make([]map[string]interface{}, 0, len(events))
+	for _, event := range events {
+		name := event.OperationType
+		if name == "" {
+			name = "operation"
+		}
+
+		statusCode := 1 // STATUS_CODE_OK
+		if !event.Success {
+			statusCode = 2 // STATUS_CODE_ERROR
+		}
+
+		spans = append(spans, map[string]interface{}{
+			"traceId":           traceID,
+			"spanId":            fmt.Sprintf("%016x", uint64(event.JobID)+1),
+			"name":              name,
+			"kind":              3, // SPAN_KIND_CLIENT
+			"startTimeUnixNano": fmt.Sprintf("%d", event.StartedAt.UnixNano()),
+			"endTimeUnixNano":   fmt.Sprintf("%d", event.FinishedAt.UnixNano()),
+			"attributes": []map[string]interface{}{
+				otlpIntAttribute("abc_runner.job_id", int64(event.JobID)),
+				otlpIntAttribute("abc_runner.worker_id", int64(event.WorkerID)),
+				otlpIntAttribute("abc_runner.scheduled_time_unix_nano", event.ScheduledAt.UnixNano()),
+				otlpIntAttribute("abc_runner.schedule_lag_ns", event.Lag().Nanoseconds()),
+			},
+			"status": map[string]interface{}{"code": statusCode},
+		})
+	}
+
+	output := map[string]interface{}{
+		"resourceSpans": []map[string]interface{}{
+			{
+				"resource": map[string]interface{}{
+					"attributes": []map[string]interface{}{
+						{"key": "service.name", "value": map[string]interface{}{"stringValue": "abc-runner"}},
+					},
+				},
+				"scopeSpans": []map[string]interface{}{
+					{
+						"scope": map[string]interface{}{"name": "abc-runner/schedule"},
+						"spans": spans,
+					},
+				},
+			},
+		},
+	}
+
+	encoder := json.NewEncoder(w)
+	return encoder.Encode(output)
+}
+
+// traceOrigin 获取追踪起点，未显式设置时使用最早的计划时间
+func (t *ScheduleTracer) traceOrigin(events []ScheduleTraceEvent) time.Time {
+	t.mutex.Lock()
+	origin := t.origin
+	t.mutex.Unlock()
+
+	if origin.IsZero() && len(events) > 0 {
+		origin = events[0].ScheduledAt
+	}
+	return origin
+}
+
+// otlpIntAttribute 构造OTLP整型属性
+func otlpIntAttribute(key string, value int64) map[string]interface{} {
+	return map[string]interface{}{
+		"key":   key,
+		"value": map[string]interface{}{"intValue": fmt.Sprintf("%d", value)},
+	}
+}
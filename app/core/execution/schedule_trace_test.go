@@ -0,0 +1,100 @@
+package execution
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestExecutionEngine_ScheduleTrace_RampUp(t *testing.T) {
+	adapter := &mockProtocolAdapter{}
+	collector := &mockMetricsCollector{}
+	factory := &mockOperationFactory{operationType: "test"}
+
+	engine := NewExecutionEngine(adapter, collector, factory)
+	tracer := NewScheduleTracer(0)
+	engine.SetScheduleTracer(tracer)
+
+	config := &mockBenchmarkConfig{
+		total:     20,
+		parallels: 4,
+		rampUp:    100 * time.Millisecond,
+	}
+
+	if _, err := engine.RunBenchmark(context.Background(), config); err != nil {
+		t.Fatalf("RunBenchmark failed: %v", err)
+	}
+
+	events := tracer.Events()
+	if len(events) != 20 {
+		t.Fatalf("Expected 20 trace events, got %d", len(events))
+	}
+
+	// 渐进加载模式下计划时间应按固定间隔递增
+	for i := 1; i < len(events); i++ {
+		gap := events[i].ScheduledAt.Sub(events[i-1].ScheduledAt)
+		if gap != 5*time.Millisecond {
+			t.Errorf("Expected 5ms schedule gap between events %d and %d, got %v", i-1, i, gap)
+		}
+	}
+
+	for _, event := range events {
+		if event.StartedAt.IsZero() || event.FinishedAt.Before(event.StartedAt) {
+			t.Errorf("Invalid start/finish times for job %d", event.JobID)
+		}
+	}
+}
+
+func TestScheduleTracer_Export(t *testing.T) {
+	tracer := NewScheduleTracer(2)
+	origin := time.Now()
+	tracer.Start(origin)
+
+	for i := 0; i < 3; i++ {
+		scheduled := origin.Add(time.Duration(i) * time.Millisecond)
+		tracer.Record(ScheduleTraceEvent{
+			JobID:         i,
+			OperationType: "get",
+			ScheduledAt:   scheduled,
+			StartedAt:     scheduled.Add(200 * time.Microsecond),
+			FinishedAt:    scheduled.Add(time.Millisecond),
+			Success:       true,
+		})
+	}
+
+	summary := tracer.Summary()
+	if summary.Events != 2 || summary.Dropped != 1 {
+		t.Errorf("Expected 2 events and 1 dropped, got %d events and %d dropped", summary.Events, summary.Dropped)
+	}
+	if summary.MaxLag != 200*time.Microsecond {
+		t.Errorf("Expected max lag 200us, got %v", summary.MaxLag)
+	}
+
+	var chrome bytes.Buffer
+	if err := tracer.Export(&chrome, TraceFormatChrome); err != nil {
+		t.Fatalf("Chrome export failed: %v", err)
+	}
+	var chromeDoc struct {
+		TraceEvents []map[string]interface{} `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(chrome.Bytes(), &chromeDoc); err != nil {
+		t.Fatalf("Invalid chrome trace JSON: %v", err)
+	}
+	if len(chromeDoc.TraceEvents) != 6 {
+		t.Errorf("Expected 6 chrome trace events, got %d", len(chromeDoc.TraceEvents))
+	}
+
+	var otlp bytes.Buffer
+	if err := tracer.Export(&otlp, TraceFormatOTLP); err != nil {
+		t.Fatalf("OTLP export failed: %v", err)
+	}
+	if !json.Valid(otlp.Bytes()) {
+		t.Error("Invalid OTLP JSON output")
+	}
+
+	if err := tracer.Export(&bytes.Buffer{}, "xml"); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+}
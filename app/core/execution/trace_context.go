@@ -0,0 +1,91 @@
+package execution
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	mathrand "math/rand/v2"
+	"sync"
+)
+
+// TraceparentHeader W3C trace-context请求头，同时用作gRPC metadata键与Kafka消息头键
+const TraceparentHeader = "traceparent"
+
+// TraceContext 单个操作的W3C trace-context，操作本身即为trace中的客户端根span
+type TraceContext struct {
+	TraceID string // 32位十六进制
+	SpanID  string // 16位十六进制
+	Sampled bool
+}
+
+// Traceparent 按W3C Trace Context格式编码，如"00-<trace-id>-<span-id>-01"
+func (t TraceContext) Traceparent() string {
+	flags := "00"
+	if t.Sampled {
+		flags = "01"
+	}
+	return "00-" + t.TraceID + "-" + t.SpanID + "-" + flags
+}
+
+// TraceContextGenerator trace-context生成器
+// 每个操作都生成新的trace，按采样率设置sampled标志；未采样的操作仍注入traceparent，
+// 目标端据此关联日志但不记录span
+type TraceContextGenerator struct {
+	sampleRatio float64
+
+	mutex  sync.Mutex
+	random *mathrand.ChaCha8
+}
+
+// NewTraceContextGenerator 创建trace-context生成器，sampleRatio取值[0, 1]
+func NewTraceContextGenerator(sampleRatio float64) *TraceContextGenerator {
+	var seed [32]byte
+	rand.Read(seed[:])
+	return &TraceContextGenerator{
+		sampleRatio: min(max(sampleRatio, 0), 1),
+		random:      mathrand.NewChaCha8(seed),
+	}
+}
+
+// SampleRatio 采样率
+func (g *TraceContextGenerator) SampleRatio() float64 {
+	return g.sampleRatio
+}
+
+// Next 生成下一个操作的trace-context
+func (g *TraceContextGenerator) Next() TraceContext {
+	var ids [24]byte
+	g.mutex.Lock()
+	g.random.Read(ids[:])
+	g.mutex.Unlock()
+
+	// 以trace ID的低8字节决定是否采样，与OpenTelemetry的TraceIdRatioBased采样器一致
+	sampled := g.sampleRatio >= 1 || float64(binary.BigEndian.Uint64(ids[8:16])>>1) < g.sampleRatio*(1<<63)
+	return TraceContext{
+		TraceID: hex.EncodeToString(ids[:16]),
+		SpanID:  hex.EncodeToString(ids[16:]),
+		Sampled: sampled,
+	}
+}
+
+// traceContextKey context键
+type traceContextKey struct{}
+
+// WithTraceContext 返回携带trace-context的context
+// 支持的适配器将其以traceparent注入请求（HTTP请求头、gRPC metadata、Kafka消息头）
+func WithTraceContext(ctx context.Context, trace TraceContext) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, traceContextKey{}, trace)
+}
+
+// TraceContextFromContext 从context中获取当前操作的trace-context
+func TraceContextFromContext(ctx context.Context) (TraceContext, bool) {
+	if ctx == nil {
+		return TraceContext{}, false
+	}
+	trace, ok := ctx.Value(traceContextKey{}).(TraceContext)
+	return trace, ok
+}
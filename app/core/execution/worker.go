@@ -0,0 +1,24 @@
+package execution
+
+import "context"
+
+// workerIDKey context键
+type workerIDKey struct{}
+
+// WithWorkerID 返回携带工作协程ID的context
+// 同一工作协程顺序执行的操作ID相同，适配器可据此为每个虚拟用户保持会话状态（如cookie）
+func WithWorkerID(ctx context.Context, workerID int) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, workerIDKey{}, workerID)
+}
+
+// WorkerIDFromContext 从context中获取执行当前操作的工作协程ID
+func WorkerIDFromContext(ctx context.Context) (int, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	workerID, ok := ctx.Value(workerIDKey{}).(int)
+	return workerID, ok
+}
@@ -0,0 +1,354 @@
+package fanout
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+)
+
+// errDropped 未在等待窗口内投递的消息
+var errDropped = errors.New("message dropped")
+
+// DeliverFunc 订阅者收到消息时的回调
+type DeliverFunc func(payload []byte)
+
+// Transport 发布订阅传输层抽象
+// 每次Subscribe建立一个独立的订阅者连接，Publish向频道发布一条消息
+type Transport interface {
+	Name() string
+	Subscribe(ctx context.Context, channel string, deliver DeliverFunc) (io.Closer, error)
+	Publish(ctx context.Context, channel string, payload []byte) error
+	Close() error
+}
+
+// Config 扇出测试配置
+type Config struct {
+	Channel     string        // 频道/主题名称
+	Subscribers []int         // 逐级递增的订阅者数量
+	Rate        int           // 每秒发布消息数
+	Duration    time.Duration // 每级发布持续时间
+	PayloadSize int           // 消息大小（字节）
+	Settle      time.Duration // 订阅建立后的等待时间
+	Drain       time.Duration // 发布结束后等待在途消息的最长时间
+}
+
+// NewDefaultConfig 创建默认扇出测试配置
+func NewDefaultConfig() *Config {
+	return &Config{
+		Channel:     "abc-runner-fanout",
+		Subscribers: []int{10, 100, 1000},
+		Rate:        100,
+		Duration:    10 * time.Second,
+		PayloadSize: 128,
+		Settle:      time.Second,
+		Drain:       2 * time.Second,
+	}
+}
+
+// Validate 验证配置
+func (c *Config) Validate() error {
+	if c.Channel == "" {
+		return fmt.Errorf("channel cannot be empty")
+	}
+	if len(c.Subscribers) == 0 {
+		return fmt.Errorf("at least one subscriber step is required")
+	}
+	last := 0
+	for _, n := range c.Subscribers {
+		if n <= 0 {
+			return fmt.Errorf("subscriber count must be positive: %d", n)
+		}
+		if n < last {
+			return fmt.Errorf("subscriber steps must be ascending: %v", c.Subscribers)
+		}
+		last = n
+	}
+	if c.Rate <= 0 {
+		return fmt.Errorf("publish rate must be positive")
+	}
+	if c.Duration <= 0 {
+		return fmt.Errorf("duration must be positive")
+	}
+	if c.PayloadSize < 0 {
+		return fmt.Errorf("payload size cannot be negative")
+	}
+	return nil
+}
+
+// StepResult 单个订阅者规模下的测试结果
+type StepResult struct {
+	Subscribers int           `json:"subscribers"`
+	Published   int64         `json:"published"`
+	PublishErrs int64         `json:"publish_errors"`
+	Expected    int64         `json:"expected"`
+	Delivered   int64         `json:"delivered"`
+	Dropped     int64         `json:"dropped"`
+	DropRate    float64       `json:"drop_rate"`
+	Duration    time.Duration `json:"duration"`
+	LatencyP50  time.Duration `json:"latency_p50"`
+	LatencyP90  time.Duration `json:"latency_p90"`
+	LatencyP99  time.Duration `json:"latency_p99"`
+	LatencyMax  time.Duration `json:"latency_max"`
+}
+
+// ToMap 转换为报告使用的map
+func (s StepResult) ToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"subscribers":    s.Subscribers,
+		"published":      s.Published,
+		"publish_errors": s.PublishErrs,
+		"expected":       s.Expected,
+		"delivered":      s.Delivered,
+		"dropped":        s.Dropped,
+		"drop_rate":      s.DropRate,
+		"duration":       s.Duration.String(),
+		"latency_p50":    s.LatencyP50.String(),
+		"latency_p90":    s.LatencyP90.String(),
+		"latency_p99":    s.LatencyP99.String(),
+		"latency_max":    s.LatencyMax.String(),
+	}
+}
+
+// Runner 扇出测试执行器
+// 逐级增加订阅者连接，在每一级以固定速率发布消息，统计投递延迟与丢失率
+type Runner struct {
+	transport Transport
+	config    *Config
+	collector *metrics.BaseCollector[map[string]interface{}]
+
+	// 当前级别的统计，订阅者回调中并发访问
+	step      atomic.Int64
+	delivered atomic.Int64
+	latency   atomic.Pointer[metrics.LatencyTracker]
+}
+
+// NewRunner 创建扇出测试执行器，collector可为nil
+func NewRunner(transport Transport, config *Config, collector *metrics.BaseCollector[map[string]interface{}]) *Runner {
+	return &Runner{
+		transport: transport,
+		config:    config,
+		collector: collector,
+	}
+}
+
+// Run 依次执行各订阅者规模的测试
+// 已建立的订阅者在各级之间保留，每级只补足新增的连接
+func (r *Runner) Run(ctx context.Context, onStep func(StepResult)) ([]StepResult, error) {
+	if err := r.config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid fanout config: %w", err)
+	}
+
+	var subscribers []io.Closer
+	defer func() {
+		for _, sub := range subscribers {
+			sub.Close()
+		}
+	}()
+
+	results := make([]StepResult, 0, len(r.config.Subscribers))
+	for i, target := range r.config.Subscribers {
+		for len(subscribers) < target {
+			sub, err := r.transport.Subscribe(ctx, r.config.Channel, r.deliver)
+			if err != nil {
+				return results, fmt.Errorf("failed to open subscriber %d: %w", len(subscribers)+1, err)
+			}
+			subscribers = append(subscribers, sub)
+		}
+
+		if err := sleepContext(ctx, r.config.Settle); err != nil {
+			return results, err
+		}
+
+		result, err := r.runStep(ctx, int64(i+1), target)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+		if onStep != nil {
+			onStep(result)
+		}
+	}
+	return results, nil
+}
+
+// runStep 以固定速率发布消息并等待投递完成
+func (r *Runner) runStep(ctx context.Context, step int64, subscribers int) (StepResult, error) {
+	tracker := metrics.NewLatencyTracker(metrics.LatencyConfig{
+		SamplingRate: 1.0,
+	})
+	r.latency.Store(tracker)
+	r.delivered.Store(0)
+	r.step.Store(step)
+
+	result := StepResult{Subscribers: subscribers}
+	interval := time.Second / time.Duration(r.config.Rate)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	start := time.Now()
+	deadline := start.Add(r.config.Duration)
+	var seq int64
+publish:
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			break publish
+		case <-ticker.C:
+			seq++
+			payload := EncodePayload(step, seq, time.Now(), r.config.PayloadSize)
+			if err := r.transport.Publish(ctx, r.config.Channel, payload); err != nil {
+				result.PublishErrs++
+				continue
+			}
+			result.Published++
+		}
+	}
+	result.Duration = time.Since(start)
+	result.Expected = result.Published * int64(subscribers)
+
+	// 等待在途消息投递完成
+	drainDeadline := time.Now().Add(r.config.Drain)
+	for r.delivered.Load() < result.Expected && time.Now().Before(drainDeadline) && ctx.Err() == nil {
+		time.Sleep(10 * time.Millisecond)
+	}
+	// 停止统计本级消息，迟到的投递视为丢失
+	r.step.Store(0)
+
+	result.Delivered = r.delivered.Load()
+	if result.Delivered > result.Expected {
+		result.Delivered = result.Expected
+	}
+	result.Dropped = result.Expected - result.Delivered
+	if result.Expected > 0 {
+		result.DropRate = float64(result.Dropped) / float64(result.Expected) * 100
+	}
+
+	latency := tracker.GetMetrics()
+	result.LatencyP50 = latency.P50
+	result.LatencyP90 = latency.P90
+	result.LatencyP99 = latency.P99
+	result.LatencyMax = latency.Max
+
+	// 丢失的消息计为失败操作
+	if r.collector != nil {
+		for i := int64(0); i < result.Dropped; i++ {
+			r.collector.Record(&interfaces.OperationResult{
+				Success:  false,
+				IsRead:   true,
+				Error:    errDropped,
+				Metadata: map[string]interface{}{"operation_type": "deliver", "subscribers": subscribers},
+			})
+		}
+	}
+
+	return result, ctx.Err()
+}
+
+// deliver 订阅者回调，统计投递延迟
+func (r *Runner) deliver(payload []byte) {
+	received := time.Now()
+	step, _, sentAt, ok := DecodePayload(payload)
+	if !ok || step != r.step.Load() {
+		return
+	}
+
+	latency := received.Sub(sentAt)
+	r.delivered.Add(1)
+	if tracker := r.latency.Load(); tracker != nil {
+		tracker.Record(latency)
+	}
+	if r.collector != nil {
+		r.collector.Record(&interfaces.OperationResult{
+			Success:  true,
+			IsRead:   true,
+			Duration: latency,
+			Metadata: map[string]interface{}{"operation_type": "deliver"},
+		})
+	}
+}
+
+// EncodePayload 编码消息：级别、序号与发布时间戳，以可打印字符填充到指定大小
+func EncodePayload(step, seq int64, sentAt time.Time, size int) []byte {
+	payload := make([]byte, 0, size)
+	payload = strconv.AppendInt(payload, step, 10)
+	payload = append(payload, ' ')
+	payload = strconv.AppendInt(payload, seq, 10)
+	payload = append(payload, ' ')
+	payload = strconv.AppendInt(payload, sentAt.UnixNano(), 10)
+	payload = append(payload, ' ')
+	for len(payload) < size {
+		payload = append(payload, 'x')
+	}
+	return payload
+}
+
+// DecodePayload 解析EncodePayload编码的消息
+func DecodePayload(payload []byte) (step, seq int64, sentAt time.Time, ok bool) {
+	fields := bytes.SplitN(payload, []byte{' '}, 4)
+	if len(fields) < 3 {
+		return 0, 0, time.Time{}, false
+	}
+	var values [3]int64
+	for i := range values {
+		v, err := strconv.ParseInt(string(fields[i]), 10, 64)
+		if err != nil {
+			return 0, 0, time.Time{}, false
+		}
+		values[i] = v
+	}
+	return values[0], values[1], time.Unix(0, values[2]), true
+}
+
+// sleepContext 可被取消的等待
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// closerFunc 将函数适配为io.Closer
+type closerFunc func() error
+
+// Close 关闭
+func (f closerFunc) Close() error { return f() }
+
+// subscriberSet 记录传输层创建的订阅者，便于统一关闭
+type subscriberSet struct {
+	mutex   sync.Mutex
+	closers []io.Closer
+}
+
+// add 添加订阅者
+func (s *subscriberSet) add(closer io.Closer) {
+	s.mutex.Lock()
+	s.closers = append(s.closers, closer)
+	s.mutex.Unlock()
+}
+
+// closeAll 关闭全部订阅者
+func (s *subscriberSet) closeAll() {
+	s.mutex.Lock()
+	closers := s.closers
+	s.closers = nil
+	s.mutex.Unlock()
+	for _, closer := range closers {
+		closer.Close()
+	}
+}
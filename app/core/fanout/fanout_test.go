@@ -0,0 +1,103 @@
+package fanout
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryTransport 内存传输层，dropEvery>0时每个订阅者丢弃每第N条消息
+type memoryTransport struct {
+	mutex     sync.Mutex
+	delivers  map[int]DeliverFunc
+	nextID    int
+	dropEvery int
+	counts    map[int]int
+}
+
+func newMemoryTransport(dropEvery int) *memoryTransport {
+	return &memoryTransport{delivers: map[int]DeliverFunc{}, counts: map[int]int{}, dropEvery: dropEvery}
+}
+
+func (m *memoryTransport) Name() string { return "memory" }
+
+func (m *memoryTransport) Subscribe(ctx context.Context, channel string, deliver DeliverFunc) (io.Closer, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	id := m.nextID
+	m.nextID++
+	m.delivers[id] = deliver
+	return closerFunc(func() error {
+		m.mutex.Lock()
+		delete(m.delivers, id)
+		m.mutex.Unlock()
+		return nil
+	}), nil
+}
+
+func (m *memoryTransport) Publish(ctx context.Context, channel string, payload []byte) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for id, deliver := range m.delivers {
+		m.counts[id]++
+		if m.dropEvery > 0 && m.counts[id]%m.dropEvery == 0 {
+			continue
+		}
+		deliver(payload)
+	}
+	return nil
+}
+
+func (m *memoryTransport) Close() error { return nil }
+
+func TestRunner_StepsAndDrops(t *testing.T) {
+	config := &Config{
+		Channel:     "test",
+		Subscribers: []int{2, 5},
+		Rate:        200,
+		Duration:    200 * time.Millisecond,
+		PayloadSize: 64,
+		Drain:       100 * time.Millisecond,
+	}
+
+	results, err := NewRunner(newMemoryTransport(4), config, nil).Run(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 step results, got %d", len(results))
+	}
+
+	for i, result := range results {
+		if result.Subscribers != config.Subscribers[i] {
+			t.Errorf("step %d: expected %d subscribers, got %d", i, config.Subscribers[i], result.Subscribers)
+		}
+		if result.Published == 0 || result.Expected != result.Published*int64(result.Subscribers) {
+			t.Errorf("step %d: inconsistent published/expected: %+v", i, result)
+		}
+		if result.Dropped == 0 || result.Delivered+result.Dropped != result.Expected {
+			t.Errorf("step %d: expected drops to be accounted for: %+v", i, result)
+		}
+		if result.DropRate < 15 || result.DropRate > 35 {
+			t.Errorf("step %d: expected ~25%% drop rate, got %.2f", i, result.DropRate)
+		}
+	}
+}
+
+func TestPayloadRoundTrip(t *testing.T) {
+	sentAt := time.Unix(0, 1700000000123456789)
+	payload := EncodePayload(3, 42, sentAt, 100)
+	if len(payload) != 100 {
+		t.Fatalf("expected payload padded to 100 bytes, got %d", len(payload))
+	}
+
+	step, seq, decoded, ok := DecodePayload(payload)
+	if !ok || step != 3 || seq != 42 || !decoded.Equal(sentAt) {
+		t.Fatalf("unexpected decode result: step=%d seq=%d sentAt=%v ok=%v", step, seq, decoded, ok)
+	}
+	if _, _, _, ok := DecodePayload([]byte("welcome")); ok {
+		t.Error("expected non-benchmark message to be rejected")
+	}
+}
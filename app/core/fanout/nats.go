@@ -0,0 +1,178 @@
+package fanout
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NATSTransport 基于NATS文本协议的传输层
+// 直接实现CONNECT/PUB/SUB/MSG/PING/PONG的最小子集，每个订阅者使用独立的TCP连接
+type NATSTransport struct {
+	addr      string
+	timeout   time.Duration
+	publisher *natsConn
+	subs      subscriberSet
+}
+
+// NewNATSTransport 创建NATS传输层
+func NewNATSTransport(ctx context.Context, addr string, timeout time.Duration) (*NATSTransport, error) {
+	addr = strings.TrimPrefix(addr, "nats://")
+	publisher, err := dialNATS(ctx, addr, timeout, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &NATSTransport{addr: addr, timeout: timeout, publisher: publisher}, nil
+}
+
+// Name 获取传输层名称
+func (t *NATSTransport) Name() string {
+	return "nats"
+}
+
+// Subscribe 建立订阅者连接，通过PING/PONG往返确认订阅已生效
+func (t *NATSTransport) Subscribe(ctx context.Context, channel string, deliver DeliverFunc) (io.Closer, error) {
+	conn, err := dialNATS(ctx, t.addr, t.timeout, deliver)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.write("SUB " + channel + " 1\r\n"); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to subscribe to %s: %w", channel, err)
+	}
+	if err := conn.flush(ctx); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to confirm subscription to %s: %w", channel, err)
+	}
+
+	t.subs.add(conn)
+	return conn, nil
+}
+
+// Publish 发布消息
+func (t *NATSTransport) Publish(ctx context.Context, channel string, payload []byte) error {
+	return t.publisher.write("PUB " + channel + " " + strconv.Itoa(len(payload)) + "\r\n" + string(payload) + "\r\n")
+}
+
+// Close 关闭全部连接
+func (t *NATSTransport) Close() error {
+	t.subs.closeAll()
+	return t.publisher.Close()
+}
+
+// natsConn 单个NATS连接
+type natsConn struct {
+	conn    net.Conn
+	writer  *bufio.Writer
+	mutex   sync.Mutex
+	pongs   chan struct{}
+	deliver DeliverFunc
+	once    sync.Once
+}
+
+// dialNATS 建立连接并完成INFO/CONNECT握手
+func dialNATS(ctx context.Context, addr string, timeout time.Duration, deliver DeliverFunc) (*natsConn, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to nats %s: %w", addr, err)
+	}
+
+	reader := bufio.NewReaderSize(conn, 64*1024)
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	line, err := reader.ReadString('\n')
+	conn.SetReadDeadline(time.Time{})
+	if err != nil || !strings.HasPrefix(line, "INFO") {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected nats greeting from %s: %q (%v)", addr, strings.TrimSpace(line), err)
+	}
+
+	nc := &natsConn{
+		conn:    conn,
+		writer:  bufio.NewWriter(conn),
+		pongs:   make(chan struct{}, 1),
+		deliver: deliver,
+	}
+	if err := nc.write(`CONNECT {"verbose":false,"pedantic":false,"name":"abc-runner-fanout"}` + "\r\n"); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to send nats CONNECT: %w", err)
+	}
+	go nc.readLoop(reader)
+	return nc, nil
+}
+
+// write 写入并刷新协议数据
+func (c *natsConn) write(data string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, err := c.writer.WriteString(data); err != nil {
+		return err
+	}
+	return c.writer.Flush()
+}
+
+// flush 发送PING并等待PONG，确认之前的协议命令已被服务端处理
+func (c *natsConn) flush(ctx context.Context) error {
+	if err := c.write("PING\r\n"); err != nil {
+		return err
+	}
+	select {
+	case <-c.pongs:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// readLoop 读取服务端消息
+func (c *natsConn) readLoop(reader *bufio.Reader) {
+	defer close(c.pongs)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case strings.HasPrefix(line, "MSG "):
+			// MSG <subject> <sid> [reply-to] <#bytes>
+			fields := strings.Fields(line)
+			size, err := strconv.Atoi(fields[len(fields)-1])
+			if err != nil {
+				return
+			}
+			payload := make([]byte, size+2)
+			if _, err := io.ReadFull(reader, payload); err != nil {
+				return
+			}
+			if c.deliver != nil {
+				c.deliver(payload[:size])
+			}
+		case line == "PING":
+			c.write("PONG\r\n")
+		case line == "PONG":
+			select {
+			case c.pongs <- struct{}{}:
+			default:
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			fmt.Printf("⚠️  NATS error: %s\n", line)
+		}
+	}
+}
+
+// Close 关闭连接
+func (c *natsConn) Close() error {
+	var err error
+	c.once.Do(func() {
+		err = c.conn.Close()
+	})
+	return err
+}
@@ -0,0 +1,66 @@
+package fanout
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisTransport 基于Redis PUBLISH/SUBSCRIBE的传输层
+// 每个订阅者使用独立的PubSub连接，发布者共享一个普通客户端
+type RedisTransport struct {
+	client *redis.Client
+	subs   subscriberSet
+}
+
+// NewRedisTransport 创建Redis传输层
+func NewRedisTransport(ctx context.Context, addr, password string, db int) (*RedisTransport, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+	})
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to redis %s: %w", addr, err)
+	}
+	return &RedisTransport{client: client}, nil
+}
+
+// Name 获取传输层名称
+func (t *RedisTransport) Name() string {
+	return "redis"
+}
+
+// Subscribe 建立订阅者连接并等待订阅确认
+func (t *RedisTransport) Subscribe(ctx context.Context, channel string, deliver DeliverFunc) (io.Closer, error) {
+	pubsub := t.client.Subscribe(ctx, channel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, fmt.Errorf("failed to subscribe to %s: %w", channel, err)
+	}
+
+	// 使用较大的通道缓冲，避免消费端慢于投递时被go-redis丢弃消息
+	messages := pubsub.ChannelSize(10000)
+	go func() {
+		for msg := range messages {
+			deliver([]byte(msg.Payload))
+		}
+	}()
+
+	t.subs.add(pubsub)
+	return pubsub, nil
+}
+
+// Publish 发布消息
+func (t *RedisTransport) Publish(ctx context.Context, channel string, payload []byte) error {
+	return t.client.Publish(ctx, channel, payload).Err()
+}
+
+// Close 关闭全部连接
+func (t *RedisTransport) Close() error {
+	t.subs.closeAll()
+	return t.client.Close()
+}
@@ -0,0 +1,118 @@
+package fanout
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// WebSocketTransport 基于WebSocket服务端广播的传输层
+// 订阅者为WebSocket连接，发布通过服务端的 /broadcast HTTP端点完成，频道参数被忽略
+type WebSocketTransport struct {
+	url          string
+	broadcastURL string
+	dialer       *websocket.Dialer
+	client       *http.Client
+	subs         subscriberSet
+}
+
+// NewWebSocketTransport 创建WebSocket传输层，broadcastURL为空时根据wsURL推导
+func NewWebSocketTransport(wsURL, broadcastURL string, timeout time.Duration) (*WebSocketTransport, error) {
+	if broadcastURL == "" {
+		derived, err := deriveBroadcastURL(wsURL)
+		if err != nil {
+			return nil, err
+		}
+		broadcastURL = derived
+	}
+	return &WebSocketTransport{
+		url:          wsURL,
+		broadcastURL: broadcastURL,
+		dialer:       &websocket.Dialer{HandshakeTimeout: timeout},
+		client:       &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Name 获取传输层名称
+func (t *WebSocketTransport) Name() string {
+	return "websocket"
+}
+
+// Subscribe 建立WebSocket订阅者连接
+func (t *WebSocketTransport) Subscribe(ctx context.Context, channel string, deliver DeliverFunc) (io.Closer, error) {
+	conn, _, err := t.dialer.DialContext(ctx, t.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", t.url, err)
+	}
+
+	go func() {
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			deliver(data)
+		}
+	}()
+
+	t.subs.add(conn)
+	return conn, nil
+}
+
+// Publish 通过服务端广播端点发布消息
+func (t *WebSocketTransport) Publish(ctx context.Context, channel string, payload []byte) error {
+	body, err := json.Marshal(map[string]string{"type": "text", "message": string(payload)})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.broadcastURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("broadcast failed with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Close 关闭全部连接
+func (t *WebSocketTransport) Close() error {
+	t.subs.closeAll()
+	return nil
+}
+
+// deriveBroadcastURL 由WebSocket地址推导广播端点地址
+func deriveBroadcastURL(wsURL string) (string, error) {
+	parsed, err := url.Parse(wsURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid websocket url %s: %w", wsURL, err)
+	}
+	switch parsed.Scheme {
+	case "ws":
+		parsed.Scheme = "http"
+	case "wss":
+		parsed.Scheme = "https"
+	default:
+		return "", fmt.Errorf("unsupported websocket url scheme: %s", parsed.Scheme)
+	}
+	parsed.Path = "/broadcast"
+	parsed.RawQuery = ""
+	return parsed.String(), nil
+}
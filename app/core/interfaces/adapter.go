@@ -41,12 +41,14 @@ type Operation struct {
 
 // OperationResult 操作执行结果
 type OperationResult struct {
-	Success  bool                   `json:"success"`  // 是否成功
-	Duration time.Duration          `json:"duration"` // 执行时间
-	IsRead   bool                   `json:"is_read"`  // 是否为读操作
-	Error    error                  `json:"error"`    // 错误信息
-	Value    interface{}            `json:"value"`    // 返回值
-	Metadata map[string]interface{} `json:"metadata"` // 结果元数据
+	Success      bool                   `json:"success"`       // 是否成功
+	Duration     time.Duration          `json:"duration"`      // 执行时间
+	IsRead       bool                   `json:"is_read"`       // 是否为读操作
+	Error        error                  `json:"error"`         // 错误信息
+	Value        interface{}            `json:"value"`         // 返回值
+	Metadata     map[string]interface{} `json:"metadata"`      // 结果元数据
+	BytesRead    int64                  `json:"bytes_read"`    // 从目标读取（接收）的负载字节数
+	BytesWritten int64                  `json:"bytes_written"` // 向目标写出（发送）的负载字节数
 }
 
 // Config 统一配置接口
@@ -118,10 +120,23 @@ type MetricsSnapshot[T any] struct {
 	// System 系统监控指标
 	System SystemMetrics `json:"system"`
 
+	// TimeSeries 按采样间隔（默认每秒）记录的时间序列
+	TimeSeries []TimeSeriesPoint `json:"time_series,omitempty"`
+
+	// LatencyHistogram 延迟直方图计数，仅在需要跨来源合并分位数时（如分布式agent）填充
+	LatencyHistogram *LatencyHistogram `json:"latency_histogram,omitempty"`
+
 	// Timestamp 快照时间戳
 	Timestamp time.Time `json:"timestamp"`
 }
 
+// LatencyHistogram HDR延迟直方图的可序列化形式，只保存非零计数槽位
+type LatencyHistogram struct {
+	Highest           int64       `json:"highest"`            // 最大可记录值（纳秒）
+	SignificantDigits int         `json:"significant_digits"` // 有效数字位数
+	Counts            [][2]uint64 `json:"counts"`             // [槽位, 计数]
+}
+
 // CoreMetrics 核心通用指标
 type CoreMetrics struct {
 	// Operations 操作指标
@@ -145,6 +160,9 @@ type OperationMetrics struct {
 	Read    int64   `json:"read"`         // 读操作数
 	Write   int64   `json:"write"`        // 写操作数
 	Rate    float64 `json:"success_rate"` // 成功率 (%)
+
+	BytesRead    int64 `json:"bytes_read"`    // 读取（接收）的负载字节数
+	BytesWritten int64 `json:"bytes_written"` // 写出（发送）的负载字节数
 }
 
 // LatencyMetrics 延迟指标
@@ -156,6 +174,7 @@ type LatencyMetrics struct {
 	P90          time.Duration `json:"p90"`           // P90延迟
 	P95          time.Duration `json:"p95"`           // P95延迟
 	P99          time.Duration `json:"p99"`           // P99延迟
+	P999         time.Duration `json:"p999"`          // P99.9延迟
 	StdDeviation time.Duration `json:"std_deviation"` // 标准差
 }
 
@@ -164,6 +183,20 @@ type ThroughputMetrics struct {
 	RPS      float64 `json:"rps"`       // 每秒请求数
 	ReadRPS  float64 `json:"read_rps"`  // 每秒读请求数
 	WriteRPS float64 `json:"write_rps"` // 每秒写请求数
+
+	ReadMBps  float64 `json:"read_mb_per_sec"`  // 每秒读取（接收）的MB数
+	WriteMBps float64 `json:"write_mb_per_sec"` // 每秒写出（发送）的MB数
+}
+
+// TimeSeriesPoint 时间序列采样点，汇总一个采样间隔内的操作
+type TimeSeriesPoint struct {
+	Timestamp  time.Time     `json:"timestamp"`  // 采样间隔结束时间
+	Elapsed    time.Duration `json:"elapsed"`    // 相对测试开始的偏移
+	Operations int64         `json:"operations"` // 间隔内操作数
+	Errors     int64         `json:"errors"`     // 间隔内失败数
+	RPS        float64       `json:"rps"`        // 每秒请求数
+	ErrorRate  float64       `json:"error_rate"` // 错误率 (%)
+	P95        time.Duration `json:"p95"`        // 间隔内P95延迟
 }
 
 // SystemMetrics 系统监控指标
@@ -41,3 +41,78 @@ type GRPCAdapterFactory interface {
 type WebSocketAdapterFactory interface {
 	CreateWebSocketAdapter() ProtocolAdapter
 }
+
+// SNMPAdapterFactory SNMP适配器工厂接口
+type SNMPAdapterFactory interface {
+	CreateSNMPAdapter() ProtocolAdapter
+}
+
+// SyslogAdapterFactory Syslog适配器工厂接口
+type SyslogAdapterFactory interface {
+	CreateSyslogAdapter() ProtocolAdapter
+}
+
+// PostgresAdapterFactory PostgreSQL适配器工厂接口
+type PostgresAdapterFactory interface {
+	CreatePostgresAdapter() ProtocolAdapter
+}
+
+// MySQLAdapterFactory MySQL适配器工厂接口
+type MySQLAdapterFactory interface {
+	CreateMySQLAdapter() ProtocolAdapter
+}
+
+// MongoAdapterFactory MongoDB适配器工厂接口
+type MongoAdapterFactory interface {
+	CreateMongoAdapter() ProtocolAdapter
+}
+
+// RabbitMQAdapterFactory RabbitMQ适配器工厂接口
+type RabbitMQAdapterFactory interface {
+	CreateRabbitMQAdapter() ProtocolAdapter
+}
+
+// PulsarAdapterFactory Pulsar适配器工厂接口
+type PulsarAdapterFactory interface {
+	CreatePulsarAdapter() ProtocolAdapter
+}
+
+// ElasticsearchAdapterFactory Elasticsearch/OpenSearch适配器工厂接口
+type ElasticsearchAdapterFactory interface {
+	CreateElasticsearchAdapter() ProtocolAdapter
+}
+
+// S3AdapterFactory S3兼容对象存储适配器工厂接口
+type S3AdapterFactory interface {
+	CreateS3Adapter() ProtocolAdapter
+}
+
+// DNSAdapterFactory DNS适配器工厂接口
+type DNSAdapterFactory interface {
+	CreateDNSAdapter() ProtocolAdapter
+}
+
+// EtcdAdapterFactory etcd适配器工厂接口
+type EtcdAdapterFactory interface {
+	CreateEtcdAdapter() ProtocolAdapter
+}
+
+// ClickHouseAdapterFactory ClickHouse适配器工厂接口
+type ClickHouseAdapterFactory interface {
+	CreateClickHouseAdapter() ProtocolAdapter
+}
+
+// SMTPAdapterFactory SMTP适配器工厂接口
+type SMTPAdapterFactory interface {
+	CreateSMTPAdapter() ProtocolAdapter
+}
+
+// RemoteWriteAdapterFactory Prometheus remote-write适配器工厂接口
+type RemoteWriteAdapterFactory interface {
+	CreateRemoteWriteAdapter() ProtocolAdapter
+}
+
+// OTLPAdapterFactory OpenTelemetry OTLP适配器工厂接口
+type OTLPAdapterFactory interface {
+	CreateOTLPAdapter() ProtocolAdapter
+}
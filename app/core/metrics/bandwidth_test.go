@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+)
+
+func TestBaseCollector_BytesTransferred(t *testing.T) {
+	collector := NewBaseCollector(DefaultMetricsConfig(), map[string]interface{}{})
+	defer collector.Stop()
+
+	collector.Record(&interfaces.OperationResult{Success: true, IsRead: true, Duration: time.Millisecond, BytesRead: 2 * bytesPerMB, BytesWritten: 100})
+	collector.Record(&interfaces.OperationResult{Success: true, Duration: time.Millisecond, BytesWritten: bytesPerMB})
+	// 失败操作已收发的字节同样计入
+	collector.Record(&interfaces.OperationResult{Success: false, Duration: time.Millisecond, BytesWritten: 24})
+
+	operations := collector.Snapshot().Core.Operations
+	if operations.BytesRead != 2*bytesPerMB || operations.BytesWritten != bytesPerMB+124 {
+		t.Fatalf("bytes read=%d written=%d", operations.BytesRead, operations.BytesWritten)
+	}
+
+	read, write := Bandwidth(operations, 2*time.Second)
+	if read != 1 || write < 0.5 || write > 0.5001 {
+		t.Errorf("bandwidth read=%.4f write=%.4f MB/s, want 1 and 0.5", read, write)
+	}
+	if read, write := Bandwidth(operations, 0); read != 0 || write != 0 {
+		t.Errorf("zero duration should give zero bandwidth, got %.2f/%.2f", read, write)
+	}
+
+	collector.Reset()
+	if operations := collector.Snapshot().Core.Operations; operations.BytesRead != 0 || operations.BytesWritten != 0 {
+		t.Errorf("bytes not reset: %+v", operations)
+	}
+}
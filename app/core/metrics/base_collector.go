@@ -3,7 +3,6 @@ package metrics
 import (
 	"context"
 	"math"
-	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -17,25 +16,39 @@ type BaseCollector[T any] struct {
 	config *MetricsConfig
 
 	// 核心指标收集组件
-	operations  *OperationTracker
-	latency     *LatencyTracker
-	throughput  *ThroughputTracker
+	operations *OperationTracker
+	latency    *LatencyTracker
+	throughput *ThroughputTracker
 
 	// 系统监控组件
 	system *SystemTracker
 
+	// 时间序列采样（未启用时为nil）
+	timeSeries *TimeSeriesTracker
+
 	// 协议特定指标
 	protocol T
 
 	// 状态管理
-	startTime   time.Time
-	mutex       sync.RWMutex
-	ctx         context.Context
-	cancel      context.CancelFunc
-	isRunning   int32
+	startTime time.Time
+	mutex     sync.RWMutex
+	ctx       context.Context
+	cancel    context.CancelFunc
+	isRunning int32
 
 	// 健康检查器
 	healthChecker HealthChecker
+
+	// 操作结果观察者（[]ResultObserver）
+	observers atomic.Value
+
+	// 按核执行引擎的本地指标分片（[]*MetricsShard），快照时合并
+	shards atomic.Value
+}
+
+// ResultObserver 操作结果观察者，收集器记录结果时同步回调
+type ResultObserver interface {
+	Observe(result *interfaces.OperationResult)
 }
 
 // NewBaseCollector 创建基础收集器
@@ -64,6 +77,12 @@ func NewBaseCollector[T any](config *MetricsConfig, protocolData T) *BaseCollect
 		collector.startBackgroundMonitoring()
 	}
 
+	// 启动时间序列采样
+	if config.TimeSeries.Enabled {
+		collector.timeSeries = NewTimeSeriesTracker(config.TimeSeries, collector.startTime)
+		collector.startTimeSeriesSampling()
+	}
+
 	atomic.StoreInt32(&collector.isRunning, 1)
 	return collector
 }
@@ -82,6 +101,20 @@ func (bc *BaseCollector[T]) Record(result *interfaces.OperationResult) {
 
 	// 更新吞吐量指标
 	bc.throughput.Record(result)
+
+	// 记录时间序列并通知观察者
+	bc.observe(result)
+}
+
+// AddObserver 注册操作结果观察者
+func (bc *BaseCollector[T]) AddObserver(observer ResultObserver) {
+	bc.mutex.Lock()
+	defer bc.mutex.Unlock()
+
+	current, _ := bc.observers.Load().([]ResultObserver)
+	updated := make([]ResultObserver, len(current), len(current)+1)
+	copy(updated, current)
+	bc.observers.Store(append(updated, observer))
 }
 
 // Snapshot 获取当前指标快照
@@ -91,16 +124,27 @@ func (bc *BaseCollector[T]) Snapshot() *MetricsSnapshot[T] {
 
 	duration := time.Since(bc.startTime)
 
+	var timeSeries []TimeSeriesPoint
+	if bc.timeSeries != nil {
+		timeSeries = bc.timeSeries.Points()
+	}
+
+	core := CoreMetrics{Duration: duration}
+	if shards, _ := bc.shards.Load().([]*MetricsShard); len(shards) > 0 {
+		core.Operations, core.Latency, core.Throughput = bc.mergeShards(shards, duration)
+	} else {
+		core.Operations = bc.operations.GetMetrics()
+		core.Latency = bc.latency.GetMetrics()
+		core.Throughput = bc.throughput.GetMetrics(duration)
+	}
+	core.Throughput.ReadMBps, core.Throughput.WriteMBps = Bandwidth(core.Operations, duration)
+
 	return &MetricsSnapshot[T]{
-		Core: CoreMetrics{
-			Operations: bc.operations.GetMetrics(),
-			Latency:    bc.latency.GetMetrics(),
-			Throughput: bc.throughput.GetMetrics(duration),
-			Duration:   duration,
-		},
-		Protocol:  bc.protocol,
-		System:    bc.system.GetMetrics(),
-		Timestamp: time.Now(),
+		Core:       core,
+		Protocol:   bc.protocol,
+		System:     bc.system.GetMetrics(),
+		TimeSeries: timeSeries,
+		Timestamp:  time.Now(),
 	}
 }
 
@@ -113,7 +157,15 @@ func (bc *BaseCollector[T]) Reset() {
 	bc.latency.Reset()
 	bc.throughput.Reset()
 	bc.system.Reset()
+	if shards, ok := bc.shards.Load().([]*MetricsShard); ok {
+		for _, shard := range shards {
+			shard.reset()
+		}
+	}
 	bc.startTime = time.Now()
+	if bc.timeSeries != nil {
+		bc.timeSeries.Reset(bc.startTime)
+	}
 }
 
 // Stop 停止收集器
@@ -153,6 +205,23 @@ func (bc *BaseCollector[T]) startBackgroundMonitoring() {
 	}()
 }
 
+// startTimeSeriesSampling 启动时间序列采样
+func (bc *BaseCollector[T]) startTimeSeriesSampling() {
+	go func() {
+		ticker := time.NewTicker(bc.timeSeries.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-bc.ctx.Done():
+				return
+			case now := <-ticker.C:
+				bc.timeSeries.Sample(now)
+			}
+		}
+	}()
+}
+
 // OperationTracker 操作追踪器
 type OperationTracker struct {
 	total   int64
@@ -161,6 +230,9 @@ type OperationTracker struct {
 	read    int64
 	write   int64
 	mutex   sync.RWMutex
+
+	bytesRead    int64
+	bytesWritten int64
 }
 
 // NewOperationTracker 创建操作追踪器
@@ -183,6 +255,13 @@ func (ot *OperationTracker) Record(result *interfaces.OperationResult) {
 	} else {
 		atomic.AddInt64(&ot.write, 1)
 	}
+
+	if result.BytesRead > 0 {
+		atomic.AddInt64(&ot.bytesRead, result.BytesRead)
+	}
+	if result.BytesWritten > 0 {
+		atomic.AddInt64(&ot.bytesWritten, result.BytesWritten)
+	}
 }
 
 // GetMetrics 获取操作指标
@@ -205,6 +284,9 @@ func (ot *OperationTracker) GetMetrics() OperationMetrics {
 		Read:    read,
 		Write:   write,
 		Rate:    rate,
+
+		BytesRead:    atomic.LoadInt64(&ot.bytesRead),
+		BytesWritten: atomic.LoadInt64(&ot.bytesWritten),
 	}
 }
 
@@ -215,12 +297,15 @@ func (ot *OperationTracker) Reset() {
 	atomic.StoreInt64(&ot.failed, 0)
 	atomic.StoreInt64(&ot.read, 0)
 	atomic.StoreInt64(&ot.write, 0)
+	atomic.StoreInt64(&ot.bytesRead, 0)
+	atomic.StoreInt64(&ot.bytesWritten, 0)
 }
 
 // LatencyTracker 延迟追踪器
+// 分位数基于HDR直方图计算，记录开销为O(1)且精度不随样本数量下降
 type LatencyTracker struct {
 	config      LatencyConfig
-	buffer      *RingBuffer[time.Duration]
+	histogram   *HdrHistogram
 	min         int64 // nanoseconds
 	max         int64 // nanoseconds
 	total       int64 // nanoseconds
@@ -234,7 +319,7 @@ type LatencyTracker struct {
 func NewLatencyTracker(config LatencyConfig) *LatencyTracker {
 	return &LatencyTracker{
 		config:      config,
-		buffer:      NewRingBuffer[time.Duration](config.HistorySize),
+		histogram:   NewHdrHistogram(DefaultHdrHighestValue, config.SignificantDigits),
 		min:         math.MaxInt64,
 		max:         0,
 		lastCompute: time.Now(),
@@ -252,7 +337,7 @@ func (lt *LatencyTracker) Record(duration time.Duration) {
 	}
 
 	nanos := duration.Nanoseconds()
-	
+
 	// 更新基础统计
 	atomic.AddInt64(&lt.total, nanos)
 	atomic.AddInt64(&lt.count, 1)
@@ -273,8 +358,8 @@ func (lt *LatencyTracker) Record(duration time.Duration) {
 		}
 	}
 
-	// 添加到历史记录
-	lt.buffer.Push(duration)
+	// 记录到直方图
+	lt.histogram.Record(nanos)
 }
 
 // GetMetrics 获取延迟指标
@@ -284,7 +369,7 @@ func (lt *LatencyTracker) GetMetrics() LatencyMetrics {
 	if count == 0 {
 		return LatencyMetrics{}
 	}
-	
+
 	// 检查是否需要重新计算或缓存为空
 	lt.mutex.RLock()
 	cachedIsEmpty := lt.cached.Average == 0 && lt.cached.Min == 0 && lt.cached.Max == 0
@@ -320,14 +405,20 @@ func (lt *LatencyTracker) GetMetrics() LatencyMetrics {
 		Average: time.Duration(total / count),
 	}
 
-	// 计算分位数
-	if data := lt.buffer.ToSlice(); len(data) > 0 {
-		percentiles := lt.calculatePercentiles(data)
-		metrics.P50 = percentiles[50]
-		metrics.P90 = percentiles[90]
-		metrics.P95 = percentiles[95]
-		metrics.P99 = percentiles[99]
-		metrics.StdDeviation = lt.calculateStdDev(data, metrics.Average)
+	// 计算分位数（桶上界不超过实际最大值）
+	if lt.histogram.TotalCount() > 0 {
+		values := lt.histogram.ValuesAtQuantiles(50, 90, 95, 99, 99.9)
+		for i, v := range values {
+			if v > max {
+				values[i] = max
+			}
+		}
+		metrics.P50 = time.Duration(values[0])
+		metrics.P90 = time.Duration(values[1])
+		metrics.P95 = time.Duration(values[2])
+		metrics.P99 = time.Duration(values[3])
+		metrics.P999 = time.Duration(values[4])
+		metrics.StdDeviation = time.Duration(lt.histogram.StdDev())
 	}
 
 	lt.cached = metrics
@@ -341,7 +432,7 @@ func (lt *LatencyTracker) Reset() {
 	atomic.StoreInt64(&lt.count, 0)
 	atomic.StoreInt64(&lt.min, math.MaxInt64)
 	atomic.StoreInt64(&lt.max, 0)
-	lt.buffer.Clear()
+	lt.histogram.Reset()
 
 	lt.mutex.Lock()
 	lt.cached = LatencyMetrics{}
@@ -349,52 +440,6 @@ func (lt *LatencyTracker) Reset() {
 	lt.mutex.Unlock()
 }
 
-// calculatePercentiles 计算分位数
-func (lt *LatencyTracker) calculatePercentiles(data []time.Duration) map[int]time.Duration {
-	if len(data) == 0 {
-		return make(map[int]time.Duration)
-	}
-
-	// 复制并排序数据
-	sorted := make([]time.Duration, len(data))
-	copy(sorted, data)
-	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i] < sorted[j]
-	})
-
-	percentiles := make(map[int]time.Duration)
-	for _, p := range []int{50, 90, 95, 99} {
-		index := int(float64(len(sorted)) * float64(p) / 100.0)
-		if index >= len(sorted) {
-			index = len(sorted) - 1
-		}
-		if index < 0 {
-			index = 0
-		}
-		percentiles[p] = sorted[index]
-	}
-
-	return percentiles
-}
-
-// calculateStdDev 计算标准差
-func (lt *LatencyTracker) calculateStdDev(data []time.Duration, mean time.Duration) time.Duration {
-	if len(data) <= 1 {
-		return 0
-	}
-
-	var sum float64
-	meanNanos := float64(mean.Nanoseconds())
-
-	for _, d := range data {
-		diff := float64(d.Nanoseconds()) - meanNanos
-		sum += diff * diff
-	}
-
-	variance := sum / float64(len(data)-1)
-	return time.Duration(math.Sqrt(variance))
-}
-
 // ThroughputTracker 吞吐量追踪器
 type ThroughputTracker struct {
 	config     ThroughputConfig
@@ -444,6 +489,18 @@ func (tt *ThroughputTracker) GetMetrics(duration time.Duration) ThroughputMetric
 	}
 }
 
+// bytesPerMB 带宽换算使用的MB大小
+const bytesPerMB = 1024 * 1024
+
+// Bandwidth 按操作指标中的字节数计算每秒读写的MB数
+func Bandwidth(operations OperationMetrics, duration time.Duration) (readMBps, writeMBps float64) {
+	seconds := duration.Seconds()
+	if seconds <= 0 {
+		return 0, 0
+	}
+	return float64(operations.BytesRead) / bytesPerMB / seconds, float64(operations.BytesWritten) / bytesPerMB / seconds
+}
+
 // Reset 重置吞吐量统计
 func (tt *ThroughputTracker) Reset() {
 	atomic.StoreInt64(&tt.readCount, 0)
@@ -455,10 +512,10 @@ func (tt *ThroughputTracker) Reset() {
 func DefaultMetricsConfig() *MetricsConfig {
 	return &MetricsConfig{
 		Latency: LatencyConfig{
-			HistorySize:     10000,
-			Percentiles:     []float64{0.5, 0.9, 0.95, 0.99},
-			SamplingRate:    1.0,
-			ComputeInterval: time.Second,
+			SignificantDigits: DefaultHdrSignificantDigits,
+			Percentiles:       []float64{0.5, 0.9, 0.95, 0.99, 0.999},
+			SamplingRate:      1.0,
+			ComputeInterval:   time.Second,
 		},
 		Throughput: ThroughputConfig{
 			WindowSize:     60 * time.Second,
@@ -484,6 +541,41 @@ func DefaultMetricsConfig() *MetricsConfig {
 			Format:   []string{"json"},
 			Interval: 10 * time.Second,
 			Enabled:  false,
+			OTLP:     DefaultOTLPConfig(),
+			Live:     DefaultLiveExportConfig(),
+		},
+		TimeSeries: TimeSeriesConfig{
+			Enabled:   true,
+			Interval:  time.Second,
+			MaxPoints: 3600,
 		},
 	}
-}
\ No newline at end of file
+}
+
+// DefaultLiveExportConfig 默认实时数据点输出配置（未启用任何输出目标）
+func DefaultLiveExportConfig() LiveExportConfig {
+	return LiveExportConfig{
+		Interval:      time.Second,
+		FlushInterval: time.Second,
+		InfluxDB: InfluxDBConfig{
+			Measurement: "abc_runner",
+			Timeout:     5 * time.Second,
+		},
+		StatsD: StatsDConfig{
+			Address: "localhost:8125",
+			Prefix:  "abc_runner",
+		},
+	}
+}
+
+// DefaultOTLPConfig 默认OTLP导出配置
+func DefaultOTLPConfig() OTLPConfig {
+	return OTLPConfig{
+		Enabled:      false,
+		Endpoint:     "http://localhost:4318",
+		Interval:     10 * time.Second,
+		Timeout:      5 * time.Second,
+		ServiceName:  "abc-runner",
+		MaxQueueSize: 10000,
+	}
+}
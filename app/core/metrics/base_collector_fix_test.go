@@ -10,7 +10,6 @@ import (
 func TestLatencyTrackerMinValueFix(t *testing.T) {
 	// 测试延迟追踪器的最小值修复
 	config := LatencyConfig{
-		HistorySize:     1000,
 		ComputeInterval: 100 * time.Millisecond,
 		SamplingRate:    1.0,
 	}
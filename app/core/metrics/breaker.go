@@ -0,0 +1,212 @@
+package metrics
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+)
+
+// DefaultErrorRateWindow 错误率停止条件的默认统计窗口
+const DefaultErrorRateWindow = 10 * time.Second
+
+const (
+	// errorRateBuckets 错误率窗口划分的桶数，窗口随时间逐桶滑动
+	errorRateBuckets = 10
+	// errorRateMinOperations 窗口内少于该操作数时不判定错误率，避免低吞吐时个别失败触发停止
+	errorRateMinOperations = 20
+)
+
+// StopCondition 停止条件的种类
+type StopCondition string
+
+const (
+	StopOnErrorRate         StopCondition = "error_rate"
+	StopOnMaxErrors         StopCondition = "max_errors"
+	StopOnConsecutiveErrors StopCondition = "consecutive_errors"
+)
+
+// StopConditions 熔断式停止条件，字段为零值时不检查对应条件
+type StopConditions struct {
+	ErrorRate         float64       // 窗口内错误率超过该比例（0-1）时停止
+	ErrorRateWindow   time.Duration // 错误率的统计窗口，运行满一个窗口后才开始判定
+	MaxErrors         int64         // 累计失败数达到该值时停止
+	ConsecutiveErrors int64         // 连续失败数达到该值时停止
+}
+
+// Enabled 是否设置了任一停止条件
+func (c StopConditions) Enabled() bool {
+	return c.ErrorRate > 0 || c.MaxErrors > 0 || c.ConsecutiveErrors > 0
+}
+
+// ParseErrorRateCondition 解析"PCT[@WINDOW]"形式的错误率条件，如"20%@10s"，未指定窗口时使用默认窗口
+func ParseErrorRateCondition(value string) (float64, time.Duration, error) {
+	rateText, windowText, hasWindow := strings.Cut(value, "@")
+	percent, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(rateText), "%"), 64)
+	if err != nil || percent <= 0 || percent >= 100 {
+		return 0, 0, fmt.Errorf("invalid error rate %q (expected a percentage between 0 and 100, e.g. 20%%@10s)", value)
+	}
+	window := DefaultErrorRateWindow
+	if hasWindow {
+		window, err = time.ParseDuration(strings.TrimSpace(windowText))
+		if err != nil || window <= 0 {
+			return 0, 0, fmt.Errorf("invalid error rate window %q (expected a positive duration, e.g. 20%%@10s)", value)
+		}
+	}
+	return percent / 100, window, nil
+}
+
+// StopTrip 停止条件触发时的记录
+type StopTrip struct {
+	Condition StopCondition
+	Reason    string        // 触发原因，如"error rate 35.0% > 20.0% over 10s"
+	Elapsed   time.Duration // 自运行开始的时长
+	Total     int64         // 触发时的累计操作数
+	Failed    int64         // 触发时的累计失败数
+	LastError string        // 最近一次失败的错误
+}
+
+// rateBucket 错误率窗口中的一个桶
+type rateBucket struct {
+	index  int64 // 桶序号（自开始起的桶宽个数），用于识别环形缓冲中过期的桶
+	total  int64
+	failed int64
+}
+
+// ErrorBreaker 停止条件检查器
+// 作为结果观察者检查每个操作结果，首个满足的条件以触发记录调用一次trip（通常据此中断执行引擎），之后不再检查
+type ErrorBreaker struct {
+	conditions  StopConditions
+	trip        func(StopTrip)
+	bucketWidth time.Duration
+
+	mutex       sync.Mutex
+	startedAt   time.Time
+	total       int64
+	failed      int64
+	consecutive int64
+	lastError   string
+	buckets     [errorRateBuckets]rateBucket
+	tripped     *StopTrip
+}
+
+// NewErrorBreaker 创建停止条件检查器并开始计时
+func NewErrorBreaker(conditions StopConditions, trip func(StopTrip)) *ErrorBreaker {
+	if conditions.ErrorRate > 0 && conditions.ErrorRateWindow <= 0 {
+		conditions.ErrorRateWindow = DefaultErrorRateWindow
+	}
+	b := &ErrorBreaker{
+		conditions: conditions,
+		trip:       trip,
+		startedAt:  time.Now(),
+	}
+	if conditions.ErrorRate > 0 {
+		b.bucketWidth = conditions.ErrorRateWindow / errorRateBuckets
+		if b.bucketWidth <= 0 {
+			b.bucketWidth = 1
+		}
+	}
+	for i := range b.buckets {
+		b.buckets[i].index = -1
+	}
+	return b
+}
+
+// Observe 记录单个操作结果
+func (b *ErrorBreaker) Observe(result *interfaces.OperationResult) {
+	if result == nil {
+		return
+	}
+	b.observeAt(result, time.Now())
+}
+
+// observeAt 在now时刻记录操作结果并检查停止条件
+func (b *ErrorBreaker) observeAt(result *interfaces.OperationResult, now time.Time) {
+	b.mutex.Lock()
+	if b.tripped != nil {
+		b.mutex.Unlock()
+		return
+	}
+	elapsed := now.Sub(b.startedAt)
+	b.total++
+	if result.Success {
+		b.consecutive = 0
+	} else {
+		b.failed++
+		b.consecutive++
+		if result.Error != nil {
+			b.lastError = result.Error.Error()
+		}
+	}
+	var bucket *rateBucket
+	if b.bucketWidth > 0 {
+		index := int64(elapsed / b.bucketWidth)
+		bucket = &b.buckets[index%errorRateBuckets]
+		if bucket.index != index {
+			*bucket = rateBucket{index: index}
+		}
+		bucket.total++
+		if !result.Success {
+			bucket.failed++
+		}
+	}
+
+	condition, reason := b.check(elapsed, bucket)
+	if condition == "" {
+		b.mutex.Unlock()
+		return
+	}
+	trip := StopTrip{
+		Condition: condition,
+		Reason:    reason,
+		Elapsed:   elapsed,
+		Total:     b.total,
+		Failed:    b.failed,
+		LastError: b.lastError,
+	}
+	b.tripped = &trip
+	b.mutex.Unlock()
+
+	if b.trip != nil {
+		b.trip(trip)
+	}
+}
+
+// check 返回满足的停止条件及原因，未满足时为空；调用方持有锁
+func (b *ErrorBreaker) check(elapsed time.Duration, current *rateBucket) (StopCondition, string) {
+	c := b.conditions
+	if c.ConsecutiveErrors > 0 && b.consecutive >= c.ConsecutiveErrors {
+		return StopOnConsecutiveErrors, fmt.Sprintf("%d consecutive failed operations", b.consecutive)
+	}
+	if c.MaxErrors > 0 && b.failed >= c.MaxErrors {
+		return StopOnMaxErrors, fmt.Sprintf("%d failed operations (limit %d)", b.failed, c.MaxErrors)
+	}
+	if current == nil || elapsed < c.ErrorRateWindow {
+		return "", ""
+	}
+	var total, failed int64
+	for _, bucket := range b.buckets {
+		if bucket.index >= 0 && bucket.index > current.index-errorRateBuckets {
+			total += bucket.total
+			failed += bucket.failed
+		}
+	}
+	if total < errorRateMinOperations {
+		return "", ""
+	}
+	if rate := float64(failed) / float64(total); rate > c.ErrorRate {
+		return StopOnErrorRate, fmt.Sprintf("error rate %.1f%% > %.1f%% over %v (%d of %d operations failed)",
+			rate*100, c.ErrorRate*100, c.ErrorRateWindow, failed, total)
+	}
+	return "", ""
+}
+
+// Tripped 已触发的停止条件，未触发时为nil
+func (b *ErrorBreaker) Tripped() *StopTrip {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.tripped
+}
@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+)
+
+func TestErrorBreaker_ErrorRate(t *testing.T) {
+	var trips []StopTrip
+	breaker := NewErrorBreaker(StopConditions{ErrorRate: 0.2, ErrorRateWindow: 10 * time.Second}, func(trip StopTrip) {
+		trips = append(trips, trip)
+	})
+	start := breaker.startedAt
+	ok := &interfaces.OperationResult{Success: true}
+	failed := &interfaces.OperationResult{Success: false, Error: errors.New("connection refused")}
+
+	// 第一个窗口内全部失败，但运行未满一个窗口不判定
+	for i := 0; i < 100; i++ {
+		breaker.observeAt(failed, start.Add(time.Duration(i)*50*time.Millisecond))
+	}
+	if len(trips) != 0 {
+		t.Fatalf("tripped before a full window: %+v", trips)
+	}
+
+	// 窗口滑过失败的样本后错误率为10%，不触发
+	for i := 0; i < 90; i++ {
+		breaker.observeAt(ok, start.Add(15*time.Second+time.Duration(i)*50*time.Millisecond))
+	}
+	for i := 0; i < 10; i++ {
+		breaker.observeAt(failed, start.Add(20*time.Second+time.Duration(i)*50*time.Millisecond))
+	}
+	if len(trips) != 0 {
+		t.Fatalf("tripped at 10%% error rate: %+v", trips)
+	}
+
+	// 继续失败直至窗口内错误率超过20%，只触发一次
+	for i := 0; i < 100; i++ {
+		breaker.observeAt(failed, start.Add(21*time.Second+time.Duration(i)*10*time.Millisecond))
+	}
+	if len(trips) != 1 {
+		t.Fatalf("expected one trip, got %d", len(trips))
+	}
+	trip := trips[0]
+	if trip.Condition != StopOnErrorRate || !strings.Contains(trip.Reason, "> 20.0% over 10s") || trip.LastError != "connection refused" {
+		t.Errorf("unexpected trip: %+v", trip)
+	}
+	if breaker.Tripped() == nil || breaker.Tripped().Total != trip.Total {
+		t.Errorf("Tripped() = %+v, want %+v", breaker.Tripped(), trip)
+	}
+}
+
+func TestErrorBreaker_Counts(t *testing.T) {
+	ok := &interfaces.OperationResult{Success: true}
+	failed := &interfaces.OperationResult{Success: false, Error: errors.New("timeout")}
+
+	var consecutive []StopTrip
+	breaker := NewErrorBreaker(StopConditions{ConsecutiveErrors: 3, MaxErrors: 5}, func(trip StopTrip) {
+		consecutive = append(consecutive, trip)
+	})
+	for _, result := range []*interfaces.OperationResult{failed, failed, ok, failed, failed, failed, failed} {
+		breaker.Observe(result)
+	}
+	if len(consecutive) != 1 || consecutive[0].Condition != StopOnConsecutiveErrors || consecutive[0].Total != 6 || consecutive[0].Failed != 5 {
+		t.Errorf("unexpected consecutive trip: %+v", consecutive)
+	}
+
+	var total []StopTrip
+	breaker = NewErrorBreaker(StopConditions{MaxErrors: 3}, func(trip StopTrip) {
+		total = append(total, trip)
+	})
+	for _, result := range []*interfaces.OperationResult{failed, ok, failed, ok, failed, failed} {
+		breaker.Observe(result)
+	}
+	if len(total) != 1 || total[0].Condition != StopOnMaxErrors || total[0].Total != 5 {
+		t.Errorf("unexpected max errors trip: %+v", total)
+	}
+}
+
+func TestParseErrorRateCondition(t *testing.T) {
+	tests := []struct {
+		value  string
+		rate   float64
+		window time.Duration
+		ok     bool
+	}{
+		{"20%@10s", 0.2, 10 * time.Second, true},
+		{"5", 0.05, DefaultErrorRateWindow, true},
+		{"50%@1m", 0.5, time.Minute, true},
+		{"0%", 0, 0, false},
+		{"120%", 0, 0, false},
+		{"20%@", 0, 0, false},
+		{"abc", 0, 0, false},
+	}
+	for _, tt := range tests {
+		rate, window, err := ParseErrorRateCondition(tt.value)
+		if (err == nil) != tt.ok || rate != tt.rate || window != tt.window {
+			t.Errorf("ParseErrorRateCondition(%q) = %v, %v, %v", tt.value, rate, window, err)
+		}
+	}
+}
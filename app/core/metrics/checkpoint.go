@@ -0,0 +1,206 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultCheckpointInterval 默认的检查点写入间隔
+const DefaultCheckpointInterval = time.Minute
+
+// checkpointVersion 检查点文件格式版本
+const checkpointVersion = 1
+
+// Checkpoint 长时间运行的检查点
+// 保存截至写入时的累计快照（含延迟直方图）与执行进度；运行中断后以--resume从检查点继续，
+// 续跑的运行段与检查点中的各段合并为一份报告
+type Checkpoint struct {
+	Version   int                     `json:"version"`
+	StartedAt time.Time               `json:"started_at"` // 第一段的开始时间
+	UpdatedAt time.Time               `json:"updated_at"`
+	Segments  int                     `json:"segments"` // 包含的运行段数
+	Jobs      int64                   `json:"jobs"`     // 已完成的任务数，续跑时任务序号从此继续
+	Elapsed   time.Duration           `json:"elapsed"`  // 各段累计的测量时长
+	Complete  bool                    `json:"complete"` // 最后一段已完整结束
+	Snapshot  *DefaultMetricsSnapshot `json:"snapshot"` // 各段合并后的累计快照
+}
+
+// LoadCheckpoint 读取检查点文件
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint %s: %w", path, err)
+	}
+	if checkpoint.Version != checkpointVersion {
+		return nil, fmt.Errorf("unsupported checkpoint version %d in %s", checkpoint.Version, path)
+	}
+	if checkpoint.Snapshot == nil {
+		return nil, fmt.Errorf("checkpoint %s has no metrics snapshot", path)
+	}
+	return &checkpoint, nil
+}
+
+// Extend 返回在检查点之后追加一个运行段后的检查点，c为nil时即第一段
+// jobs为本段完成的任务数，snapshot为本段的快照（应携带延迟直方图，以便精确合并分位数）
+func (c *Checkpoint) Extend(snapshot *DefaultMetricsSnapshot, jobs int64, startedAt time.Time) *Checkpoint {
+	extended := &Checkpoint{
+		Version:   checkpointVersion,
+		StartedAt: startedAt,
+		UpdatedAt: time.Now(),
+		Segments:  1,
+		Jobs:      jobs,
+		Elapsed:   snapshot.Core.Duration,
+		Snapshot:  snapshot,
+	}
+	if c != nil {
+		extended.StartedAt = c.StartedAt
+		extended.Segments += c.Segments
+		extended.Jobs += c.Jobs
+		extended.Elapsed += c.Elapsed
+		extended.Snapshot = ConcatSnapshots(c.Snapshot, snapshot)
+		extended.Snapshot.Protocol["resumed_segments"] = extended.Segments
+	}
+	return extended
+}
+
+// ConcatSnapshots 合并先后执行的运行段的快照
+// 与MergeSnapshots不同，各段依次执行：时长累加，吞吐量按总操作数与总时长重新计算，
+// 时间序列按段首尾相接，系统与协议指标取最后一段
+func ConcatSnapshots(snapshots ...*DefaultMetricsSnapshot) *DefaultMetricsSnapshot {
+	var segments []*DefaultMetricsSnapshot
+	for _, snapshot := range snapshots {
+		if snapshot != nil {
+			segments = append(segments, snapshot)
+		}
+	}
+	merged := MergeSnapshots(segments...)
+	if len(segments) == 0 {
+		return merged
+	}
+
+	last := segments[len(segments)-1]
+	merged.System = last.System
+	merged.Protocol = make(map[string]interface{}, len(last.Protocol)+1)
+	for key, value := range last.Protocol {
+		merged.Protocol[key] = value
+	}
+
+	merged.Core.Duration = 0
+	merged.TimeSeries = nil
+	for _, segment := range segments {
+		for _, point := range segment.TimeSeries {
+			point.Elapsed += merged.Core.Duration
+			merged.TimeSeries = append(merged.TimeSeries, point)
+		}
+		merged.Core.Duration += segment.Core.Duration
+	}
+	merged.Core.Throughput = ThroughputMetrics{}
+	if seconds := merged.Core.Duration.Seconds(); seconds > 0 {
+		ops := merged.Core.Operations
+		merged.Core.Throughput.RPS = float64(ops.Total) / seconds
+		merged.Core.Throughput.ReadRPS = float64(ops.Read) / seconds
+		merged.Core.Throughput.WriteRPS = float64(ops.Write) / seconds
+	}
+	merged.Core.Throughput.ReadMBps, merged.Core.Throughput.WriteMBps = Bandwidth(merged.Core.Operations, merged.Core.Duration)
+	return merged
+}
+
+// CheckpointWriter 检查点写入器
+// 按间隔取当前运行段的快照与进度，与续跑前的检查点合并后整体写入文件；
+// 先写临时文件再重命名，任何时刻文件都是完整可解析的
+type CheckpointWriter struct {
+	path      string
+	interval  time.Duration
+	base      *Checkpoint
+	startedAt time.Time
+	source    func() (*DefaultMetricsSnapshot, int64)
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewCheckpointWriter 创建检查点写入器并开始按间隔写入，interval<=0时使用默认间隔
+// base为续跑前的检查点（可为nil），source返回当前运行段的快照与完成的任务数
+func NewCheckpointWriter(path string, interval time.Duration, base *Checkpoint, source func() (*DefaultMetricsSnapshot, int64)) (*CheckpointWriter, error) {
+	if interval <= 0 {
+		interval = DefaultCheckpointInterval
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+	w := &CheckpointWriter{
+		path:      path,
+		interval:  interval,
+		base:      base,
+		startedAt: time.Now(),
+		source:    source,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go w.run()
+	return w, nil
+}
+
+// Path 检查点文件路径
+func (w *CheckpointWriter) Path() string {
+	return w.path
+}
+
+// Interval 写入间隔
+func (w *CheckpointWriter) Interval() time.Duration {
+	return w.interval
+}
+
+// run 按间隔写入检查点
+func (w *CheckpointWriter) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			// 写入失败时保留上一次的检查点，下个间隔重试
+			w.write(false)
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// write 写入当前检查点
+func (w *CheckpointWriter) write(complete bool) (*Checkpoint, error) {
+	snapshot, jobs := w.source()
+	if snapshot == nil {
+		return nil, fmt.Errorf("no metrics snapshot to checkpoint")
+	}
+	checkpoint := w.base.Extend(snapshot, jobs, w.startedAt)
+	checkpoint.Complete = complete
+
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+	tmp := w.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, w.path); err != nil {
+		return nil, fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return checkpoint, nil
+}
+
+// Close 停止定时写入并写入最终检查点，complete表示本段是否完整结束
+func (w *CheckpointWriter) Close(complete bool) (*Checkpoint, error) {
+	close(w.stop)
+	<-w.done
+	return w.write(complete)
+}
@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// segmentSnapshot 以n个固定延迟样本构造一个运行段的快照
+func segmentSnapshot(n int64, latency, duration time.Duration) *DefaultMetricsSnapshot {
+	histogram := NewHdrHistogram(DefaultHdrHighestValue, DefaultHdrSignificantDigits)
+	for i := int64(0); i < n; i++ {
+		histogram.Record(int64(latency))
+	}
+	snapshot := &DefaultMetricsSnapshot{Protocol: map[string]interface{}{"protocol": "redis"}}
+	snapshot.Core.Operations = OperationMetrics{Total: n, Success: n, Read: n}
+	snapshot.Core.Latency = LatencyMetrics{Min: latency, Max: latency, Average: latency, P99: latency}
+	snapshot.Core.Duration = duration
+	snapshot.Core.Throughput.RPS = float64(n) / duration.Seconds()
+	snapshot.TimeSeries = []TimeSeriesPoint{{Elapsed: time.Second, Operations: n}}
+	snapshot.LatencyHistogram = histogram.Export()
+	return snapshot
+}
+
+func TestConcatSnapshots(t *testing.T) {
+	merged := ConcatSnapshots(
+		segmentSnapshot(9000, time.Millisecond, 10*time.Second),
+		segmentSnapshot(1000, 20*time.Millisecond, 10*time.Second),
+	)
+	if merged.Core.Operations.Total != 10000 || merged.Core.Duration != 20*time.Second {
+		t.Fatalf("unexpected totals: %+v", merged.Core)
+	}
+	// 依次执行的运行段按总时长计算吞吐量，而不是各段吞吐量之和
+	if merged.Core.Throughput.RPS != 500 || merged.Core.Throughput.ReadRPS != 500 {
+		t.Errorf("expected 500 ops/s, got %+v", merged.Core.Throughput)
+	}
+	if p99 := merged.Core.Latency.P99; p99 < 19*time.Millisecond || p99 > 21*time.Millisecond {
+		t.Errorf("expected the p99 from the merged histogram, got %v", p99)
+	}
+	if len(merged.TimeSeries) != 2 || merged.TimeSeries[1].Elapsed != 11*time.Second {
+		t.Errorf("expected the second segment's time series to follow the first, got %+v", merged.TimeSeries)
+	}
+	if merged.Protocol["protocol"] != "redis" {
+		t.Errorf("expected the last segment's protocol metrics, got %v", merged.Protocol)
+	}
+}
+
+func TestCheckpointWriterResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "soak", "checkpoint.json")
+	first := segmentSnapshot(600, time.Millisecond, time.Minute)
+	writer, err := NewCheckpointWriter(path, 20*time.Millisecond, nil, func() (*DefaultMetricsSnapshot, int64) {
+		return first, 600
+	})
+	if err != nil {
+		t.Fatalf("failed to create checkpoint writer: %v", err)
+	}
+
+	// 运行中按间隔写入，模拟中断后可读取的检查点
+	time.Sleep(60 * time.Millisecond)
+	saved, err := LoadCheckpoint(path)
+	if err != nil {
+		t.Fatalf("failed to load running checkpoint: %v", err)
+	}
+	if saved.Complete || saved.Segments != 1 || saved.Jobs != 600 || saved.Elapsed != time.Minute {
+		t.Fatalf("unexpected running checkpoint: %+v", saved)
+	}
+	if _, err := writer.Close(false); err != nil {
+		t.Fatalf("failed to close checkpoint writer: %v", err)
+	}
+
+	// 续跑的第二段与检查点合并
+	second := segmentSnapshot(300, time.Millisecond, 30*time.Second)
+	writer, err = NewCheckpointWriter(path, time.Hour, saved, func() (*DefaultMetricsSnapshot, int64) {
+		return second, 300
+	})
+	if err != nil {
+		t.Fatalf("failed to create checkpoint writer: %v", err)
+	}
+	final, err := writer.Close(true)
+	if err != nil {
+		t.Fatalf("failed to close checkpoint writer: %v", err)
+	}
+	if !final.Complete || final.Segments != 2 || final.Jobs != 900 || final.Elapsed != 90*time.Second || !final.StartedAt.Equal(saved.StartedAt) {
+		t.Errorf("unexpected final checkpoint: %+v", final)
+	}
+	if ops := final.Snapshot.Core.Operations.Total; ops != 900 || final.Snapshot.Core.Throughput.RPS != 10 || final.Snapshot.Protocol["resumed_segments"] != 2 {
+		t.Errorf("unexpected merged snapshot: %d ops, %+v", ops, final.Snapshot.Core.Throughput)
+	}
+	loaded, err := LoadCheckpoint(path)
+	if err != nil || !loaded.Complete || loaded.Snapshot.LatencyHistogram == nil {
+		t.Errorf("unexpected saved checkpoint: %+v, %v", loaded, err)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"abc-runner/app/core/config/yamlfile"
 	"abc-runner/config"
 
 	"gopkg.in/yaml.v3"
@@ -42,22 +43,24 @@ func (cm *ConfigManager) LoadConfig() error {
 		return cm.CreateDefaultConfig()
 	}
 
-	// 读取配置文件
-	data, err := os.ReadFile(cm.configPath)
-	if err != nil {
-		return fmt.Errorf("failed to read config file: %w", err)
-	}
-
-	// 解析配置文件
+	// 解析配置文件，YAML文件合并include:引用的片段文件并替换环境变量
 	config := &MetricsConfig{}
 	ext := strings.ToLower(filepath.Ext(cm.configPath))
 
 	switch ext {
 	case ".yaml", ".yml":
+		data, err := yamlfile.Read(cm.configPath)
+		if err != nil {
+			return fmt.Errorf("failed to read config file: %w", err)
+		}
 		if err := yaml.Unmarshal(data, config); err != nil {
 			return fmt.Errorf("failed to parse YAML config: %w", err)
 		}
 	case ".json":
+		data, err := os.ReadFile(cm.configPath)
+		if err != nil {
+			return fmt.Errorf("failed to read config file: %w", err)
+		}
 		if err := json.Unmarshal(data, config); err != nil {
 			return fmt.Errorf("failed to parse JSON config: %w", err)
 		}
@@ -246,9 +249,6 @@ func (cm *ConfigManager) isZeroValue(v reflect.Value) bool {
 // validateConfig 验证配置
 func (cm *ConfigManager) validateConfig(config *MetricsConfig) error {
 	// 验证延迟配置
-	if config.Latency.HistorySize <= 0 {
-		return fmt.Errorf("latency.history_size must be positive")
-	}
 	if config.Latency.SamplingRate < 0 || config.Latency.SamplingRate > 1 {
 		return fmt.Errorf("latency.sampling_rate must be between 0 and 1")
 	}
@@ -293,6 +293,33 @@ func (cm *ConfigManager) validateConfig(config *MetricsConfig) error {
 	if config.Export.Interval <= 0 {
 		return fmt.Errorf("export.interval must be positive")
 	}
+	if config.Export.OTLP.Enabled {
+		if config.Export.OTLP.Endpoint == "" {
+			return fmt.Errorf("export.otlp.endpoint is required when OTLP export is enabled")
+		}
+		if config.Export.OTLP.Interval <= 0 {
+			return fmt.Errorf("export.otlp.interval must be positive")
+		}
+	}
+
+	if err := validateLiveExport(config.Export.Live); err != nil {
+		return err
+	}
+
+	// 验证时间序列配置
+	if config.TimeSeries.Enabled {
+		if config.TimeSeries.Interval <= 0 {
+			return fmt.Errorf("time_series.interval must be positive")
+		}
+		if config.TimeSeries.MaxPoints <= 0 {
+			return fmt.Errorf("time_series.max_points must be positive")
+		}
+	}
+
+	// 验证SLA规则
+	if _, err := ParseSLARules(config.SLA); err != nil {
+		return fmt.Errorf("sla: %w", err)
+	}
 
 	return nil
 }
@@ -423,7 +450,6 @@ func (cm *ConfigManager) GenerateTemplate() *ConfigTemplate {
 		Examples: map[string]interface{}{
 			"high_performance": map[string]interface{}{
 				"latency": map[string]interface{}{
-					"history_size":     50000,
 					"sampling_rate":    0.5,
 					"compute_interval": "500ms",
 				},
@@ -434,7 +460,6 @@ func (cm *ConfigManager) GenerateTemplate() *ConfigTemplate {
 			},
 			"memory_optimized": map[string]interface{}{
 				"latency": map[string]interface{}{
-					"history_size":  5000,
 					"sampling_rate": 0.1,
 				},
 				"storage": map[string]interface{}{
@@ -481,16 +506,6 @@ func NewConfigValidator() *ConfigValidator {
 	}
 
 	// 添加默认验证规则
-	validator.AddRule("latency_history_size", "Latency history size validation", func(config *MetricsConfig) error {
-		if config.Latency.HistorySize < 100 {
-			return fmt.Errorf("latency history size too small (minimum: 100)")
-		}
-		if config.Latency.HistorySize > 1000000 {
-			return fmt.Errorf("latency history size too large (maximum: 1000000)")
-		}
-		return nil
-	})
-
 	validator.AddRule("memory_limit", "Memory limit validation", func(config *MetricsConfig) error {
 		if config.Storage.MemoryLimit < 1024*1024 { // 1MB
 			return fmt.Errorf("memory limit too small (minimum: 1MB)")
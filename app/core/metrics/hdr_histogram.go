@@ -0,0 +1,290 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+	"sync/atomic"
+)
+
+// HdrHistogram 高动态范围(HDR)直方图
+// 采用对数-线性分桶，在[1, highest]范围内保证指定的有效数字精度；
+// 记录为单次原子自增(O(1)、无锁)，内存占用与样本数量无关
+type HdrHistogram struct {
+	highest                     int64
+	significantDigits           int
+	subBucketHalfCountMagnitude uint
+	subBucketHalfCount          int64
+	subBucketMask               int64
+	subBucketCount              int64
+	leadingZeroCountBase        int
+	counts                      []uint64
+	totalCount                  uint64
+}
+
+// DefaultHdrSignificantDigits 默认有效数字位数（0.1%相对误差）
+const DefaultHdrSignificantDigits = 3
+
+// DefaultHdrHighestValue 默认最大可记录值（纳秒，1小时）
+const DefaultHdrHighestValue = int64(3600 * 1e9)
+
+// NewHdrHistogram 创建HDR直方图，可记录[1, highest]范围的值，significantDigits取值1-5
+func NewHdrHistogram(highest int64, significantDigits int) *HdrHistogram {
+	if significantDigits < 1 || significantDigits > 5 {
+		significantDigits = DefaultHdrSignificantDigits
+	}
+	if highest < 2 {
+		highest = DefaultHdrHighestValue
+	}
+
+	largestValueWithSingleUnitResolution := 2 * int64(math.Pow10(significantDigits))
+	subBucketCountMagnitude := uint(math.Ceil(math.Log2(float64(largestValueWithSingleUnitResolution))))
+	subBucketHalfCountMagnitude := subBucketCountMagnitude - 1
+	subBucketCount := int64(1) << subBucketCountMagnitude
+	subBucketHalfCount := subBucketCount / 2
+
+	// 计算覆盖highest所需的桶数量
+	smallestUntrackable := subBucketCount
+	bucketCount := 1
+	for smallestUntrackable <= highest {
+		if smallestUntrackable > math.MaxInt64/2 {
+			bucketCount++
+			break
+		}
+		smallestUntrackable <<= 1
+		bucketCount++
+	}
+
+	return &HdrHistogram{
+		highest:                     highest,
+		significantDigits:           significantDigits,
+		subBucketHalfCountMagnitude: subBucketHalfCountMagnitude,
+		subBucketHalfCount:          subBucketHalfCount,
+		subBucketMask:               subBucketCount - 1,
+		subBucketCount:              subBucketCount,
+		leadingZeroCountBase:        64 - int(subBucketHalfCountMagnitude) - 1,
+		counts:                      make([]uint64, int64(bucketCount+1)*subBucketHalfCount),
+	}
+}
+
+// Record 记录一个值，超出范围的值被截断到[0, highest]
+func (h *HdrHistogram) Record(value int64) {
+	if value < 0 {
+		value = 0
+	}
+	if value > h.highest {
+		value = h.highest
+	}
+	atomic.AddUint64(&h.counts[h.countsIndex(value)], 1)
+	atomic.AddUint64(&h.totalCount, 1)
+}
+
+// TotalCount 获取记录的样本总数
+func (h *HdrHistogram) TotalCount() uint64 {
+	return atomic.LoadUint64(&h.totalCount)
+}
+
+// ValueAtQuantile 获取分位数对应的值，quantile取值0-100
+// 返回值为所在桶的最大等价值，相对误差不超过有效数字精度
+func (h *HdrHistogram) ValueAtQuantile(quantile float64) int64 {
+	total := h.TotalCount()
+	if total == 0 {
+		return 0
+	}
+	if quantile > 100 {
+		quantile = 100
+	}
+
+	target := uint64(quantile/100*float64(total) + 0.5)
+	if target < 1 {
+		target = 1
+	}
+
+	var cumulative uint64
+	for i := range h.counts {
+		cumulative += atomic.LoadUint64(&h.counts[i])
+		if cumulative >= target {
+			return h.highestEquivalentValue(h.valueFromIndex(i))
+		}
+	}
+	return h.highest
+}
+
+// ValuesAtQuantiles 一次遍历获取多个分位数的值，quantiles需按升序排列
+func (h *HdrHistogram) ValuesAtQuantiles(quantiles ...float64) []int64 {
+	values := make([]int64, len(quantiles))
+	total := h.TotalCount()
+	if total == 0 || len(quantiles) == 0 {
+		return values
+	}
+
+	targets := make([]uint64, len(quantiles))
+	for i, q := range quantiles {
+		targets[i] = uint64(q/100*float64(total) + 0.5)
+		if targets[i] < 1 {
+			targets[i] = 1
+		}
+		values[i] = h.highest
+	}
+
+	var cumulative uint64
+	next := 0
+	for i := range h.counts {
+		cumulative += atomic.LoadUint64(&h.counts[i])
+		for next < len(targets) && cumulative >= targets[next] {
+			values[next] = h.highestEquivalentValue(h.valueFromIndex(i))
+			next++
+		}
+		if next == len(targets) {
+			break
+		}
+	}
+	return values
+}
+
+// Mean 获取均值（基于桶中点的近似值）
+func (h *HdrHistogram) Mean() float64 {
+	var total, sum float64
+	for i := range h.counts {
+		count := atomic.LoadUint64(&h.counts[i])
+		if count == 0 {
+			continue
+		}
+		total += float64(count)
+		sum += float64(count) * float64(h.medianEquivalentValue(h.valueFromIndex(i)))
+	}
+	if total == 0 {
+		return 0
+	}
+	return sum / total
+}
+
+// StdDev 获取标准差（基于桶中点的近似值）
+func (h *HdrHistogram) StdDev() float64 {
+	mean := h.Mean()
+	var total, sumSquares float64
+	for i := range h.counts {
+		count := atomic.LoadUint64(&h.counts[i])
+		if count == 0 {
+			continue
+		}
+		diff := float64(h.medianEquivalentValue(h.valueFromIndex(i))) - mean
+		total += float64(count)
+		sumSquares += float64(count) * diff * diff
+	}
+	if total <= 1 {
+		return 0
+	}
+	return math.Sqrt(sumSquares / (total - 1))
+}
+
+// Distribution 按升序边界统计各区间的样本数
+// 返回len(bounds)+1个计数：第i个为落在[bounds[i-1], bounds[i])的样本，最后一个为不小于最后边界的样本；
+// 样本按所在桶的下界归组，边界附近存在桶精度内的误差
+func (h *HdrHistogram) Distribution(bounds []int64) []uint64 {
+	groups := make([]uint64, len(bounds)+1)
+	for i := range h.counts {
+		count := atomic.LoadUint64(&h.counts[i])
+		if count == 0 {
+			continue
+		}
+		value := h.valueFromIndex(i)
+		groups[sort.Search(len(bounds), func(j int) bool { return bounds[j] > value })] += count
+	}
+	return groups
+}
+
+// Compatible 两个直方图是否以相同的范围与精度创建，可直接合并
+func (h *HdrHistogram) Compatible(other *HdrHistogram) bool {
+	return h.highest == other.highest && h.significantDigits == other.significantDigits
+}
+
+// Merge 将other的计数累加到h，两者须以相同的范围与精度创建
+func (h *HdrHistogram) Merge(other *HdrHistogram) {
+	for i := range other.counts {
+		if count := atomic.LoadUint64(&other.counts[i]); count > 0 {
+			atomic.AddUint64(&h.counts[i], count)
+		}
+	}
+	atomic.AddUint64(&h.totalCount, other.TotalCount())
+}
+
+// Export 导出非零计数，用于跨进程传输后合并
+func (h *HdrHistogram) Export() *LatencyHistogram {
+	data := &LatencyHistogram{
+		Highest:           h.highest,
+		SignificantDigits: h.significantDigits,
+	}
+	for i := range h.counts {
+		if count := atomic.LoadUint64(&h.counts[i]); count > 0 {
+			data.Counts = append(data.Counts, [2]uint64{uint64(i), count})
+		}
+	}
+	return data
+}
+
+// NewHdrHistogramFromExport 从导出的计数重建直方图
+func NewHdrHistogramFromExport(data *LatencyHistogram) (*HdrHistogram, error) {
+	if data == nil {
+		return nil, fmt.Errorf("histogram data is nil")
+	}
+	h := NewHdrHistogram(data.Highest, data.SignificantDigits)
+	if h.highest != data.Highest || h.significantDigits != data.SignificantDigits {
+		return nil, fmt.Errorf("invalid histogram range %d with %d significant digits", data.Highest, data.SignificantDigits)
+	}
+	for _, entry := range data.Counts {
+		if entry[0] >= uint64(len(h.counts)) {
+			return nil, fmt.Errorf("histogram index %d out of range", entry[0])
+		}
+		h.counts[entry[0]] += entry[1]
+		h.totalCount += entry[1]
+	}
+	return h, nil
+}
+
+// Reset 清空直方图
+func (h *HdrHistogram) Reset() {
+	for i := range h.counts {
+		atomic.StoreUint64(&h.counts[i], 0)
+	}
+	atomic.StoreUint64(&h.totalCount, 0)
+}
+
+// countsIndex 计算值所在的计数槽位
+func (h *HdrHistogram) countsIndex(value int64) int {
+	bucketIdx := h.leadingZeroCountBase - bits.LeadingZeros64(uint64(value|h.subBucketMask))
+	subBucketIdx := value >> uint(bucketIdx)
+	return int((int64(bucketIdx+1) << h.subBucketHalfCountMagnitude) + (subBucketIdx - h.subBucketHalfCount))
+}
+
+// valueFromIndex 计算槽位对应的最小值
+func (h *HdrHistogram) valueFromIndex(index int) int64 {
+	bucketIdx := (index >> h.subBucketHalfCountMagnitude) - 1
+	subBucketIdx := int64(index)&(h.subBucketHalfCount-1) + h.subBucketHalfCount
+	if bucketIdx < 0 {
+		subBucketIdx -= h.subBucketHalfCount
+		bucketIdx = 0
+	}
+	return subBucketIdx << uint(bucketIdx)
+}
+
+// sizeOfEquivalentRange 获取与value落在同一槽位的值区间大小
+func (h *HdrHistogram) sizeOfEquivalentRange(value int64) int64 {
+	bucketIdx := h.leadingZeroCountBase - bits.LeadingZeros64(uint64(value|h.subBucketMask))
+	subBucketIdx := value >> uint(bucketIdx)
+	if subBucketIdx >= h.subBucketCount {
+		bucketIdx++
+	}
+	return int64(1) << uint(bucketIdx)
+}
+
+// highestEquivalentValue 获取同一槽位中的最大值
+func (h *HdrHistogram) highestEquivalentValue(value int64) int64 {
+	return value + h.sizeOfEquivalentRange(value) - 1
+}
+
+// medianEquivalentValue 获取同一槽位的中点值
+func (h *HdrHistogram) medianEquivalentValue(value int64) int64 {
+	return value + h.sizeOfEquivalentRange(value)/2
+}
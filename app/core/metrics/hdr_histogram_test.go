@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"math"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHdrHistogram_IndexRoundTrip(t *testing.T) {
+	h := NewHdrHistogram(DefaultHdrHighestValue, DefaultHdrSignificantDigits)
+	for _, value := range []int64{0, 1, 1023, 2047, 2048, 4095, 123456, 987654321, DefaultHdrHighestValue} {
+		lowest := h.valueFromIndex(h.countsIndex(value))
+		highest := h.highestEquivalentValue(lowest)
+		if value < lowest || value > highest {
+			t.Errorf("value %d mapped to bucket [%d, %d]", value, lowest, highest)
+		}
+		if value > 0 && float64(highest-lowest)/float64(value) > 0.001 {
+			t.Errorf("bucket for %d too wide: [%d, %d]", value, lowest, highest)
+		}
+	}
+}
+
+func TestHdrHistogram_QuantilesAtMillionsOfSamples(t *testing.T) {
+	h := NewHdrHistogram(DefaultHdrHighestValue, DefaultHdrSignificantDigits)
+
+	// 1..1,000,000 微秒的均匀分布，精确分位数已知
+	const samples = 1000000
+	for i := int64(1); i <= samples; i++ {
+		h.Record(i * int64(time.Microsecond))
+	}
+	if h.TotalCount() != samples {
+		t.Fatalf("expected %d samples, got %d", samples, h.TotalCount())
+	}
+
+	quantiles := []float64{50, 90, 99, 99.9, 100}
+	values := h.ValuesAtQuantiles(quantiles...)
+	for i, q := range quantiles {
+		expected := q / 100 * samples * float64(time.Microsecond)
+		if relErr := math.Abs(float64(values[i])-expected) / expected; relErr > 0.001 {
+			t.Errorf("p%v = %d, expected ~%.0f (relative error %.5f)", q, values[i], expected, relErr)
+		}
+		if single := h.ValueAtQuantile(q); single != values[i] {
+			t.Errorf("ValueAtQuantile(%v) = %d, ValuesAtQuantiles = %d", q, single, values[i])
+		}
+	}
+
+	expectedMean := float64(samples+1) / 2 * float64(time.Microsecond)
+	if relErr := math.Abs(h.Mean()-expectedMean) / expectedMean; relErr > 0.001 {
+		t.Errorf("mean %.0f, expected ~%.0f", h.Mean(), expectedMean)
+	}
+
+	h.Reset()
+	if h.TotalCount() != 0 || h.ValueAtQuantile(99) != 0 {
+		t.Error("expected empty histogram after reset")
+	}
+}
+
+func TestHdrHistogram_Distribution(t *testing.T) {
+	h := NewHdrHistogram(DefaultHdrHighestValue, DefaultHdrSignificantDigits)
+	for _, value := range []time.Duration{50 * time.Microsecond, 500 * time.Microsecond, 999 * time.Microsecond, 1500 * time.Microsecond, 3 * time.Millisecond, time.Second} {
+		h.Record(int64(value))
+	}
+	groups := h.Distribution([]int64{int64(time.Millisecond), int64(10 * time.Millisecond)})
+	if len(groups) != 3 || groups[0] != 3 || groups[1] != 2 || groups[2] != 1 {
+		t.Errorf("unexpected distribution %v", groups)
+	}
+}
+
+func TestLatencyTracker_P999(t *testing.T) {
+	tracker := NewLatencyTracker(DefaultMetricsConfig().Latency)
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 2500; i++ {
+				tracker.Record(time.Millisecond)
+			}
+		}()
+	}
+	wg.Wait()
+	// 约0.2%的长尾样本只应体现在P99.9上
+	for i := 0; i < 20; i++ {
+		tracker.Record(500 * time.Millisecond)
+	}
+
+	m := tracker.GetMetrics()
+	if m.P99 > time.Millisecond+time.Millisecond/1000 {
+		t.Errorf("expected P99 ~1ms, got %v", m.P99)
+	}
+	if m.P999 < 499*time.Millisecond || m.P999 > m.Max {
+		t.Errorf("expected P99.9 ~500ms and <= max, got %v (max %v)", m.P999, m.Max)
+	}
+}
+
+func TestMergeLatency_Shards(t *testing.T) {
+	config := DefaultMetricsConfig().Latency
+	fast, slow, empty := NewLatencyTracker(config), NewLatencyTracker(config), NewLatencyTracker(config)
+	for i := 0; i < 900; i++ {
+		fast.Record(time.Millisecond)
+	}
+	for i := 0; i < 100; i++ {
+		slow.Record(100 * time.Millisecond)
+	}
+
+	m := mergeLatency(config, []*LatencyTracker{empty, fast, slow})
+	if m.Min != time.Millisecond || m.Max != 100*time.Millisecond {
+		t.Errorf("expected merged min/max 1ms/100ms, got %v/%v", m.Min, m.Max)
+	}
+	if m.P50 > time.Millisecond+time.Millisecond/1000 || m.P99 < 99*time.Millisecond {
+		t.Errorf("expected P50 ~1ms and P99 ~100ms, got %v and %v", m.P50, m.P99)
+	}
+	if expected := (900*time.Millisecond + 100*100*time.Millisecond) / 1000; m.Average != expected {
+		t.Errorf("expected average %v, got %v", expected, m.Average)
+	}
+}
@@ -13,7 +13,9 @@ type MetricsSnapshot[T any] = interfaces.MetricsSnapshot[T]
 type CoreMetrics = interfaces.CoreMetrics
 type OperationMetrics = interfaces.OperationMetrics
 type LatencyMetrics = interfaces.LatencyMetrics
+type LatencyHistogram = interfaces.LatencyHistogram
 type ThroughputMetrics = interfaces.ThroughputMetrics
+type TimeSeriesPoint = interfaces.TimeSeriesPoint
 type DefaultMetricsCollector = interfaces.DefaultMetricsCollector
 type DefaultMetricsSnapshot = interfaces.DefaultMetricsSnapshot
 
@@ -35,12 +37,18 @@ type MetricsConfig struct {
 
 	// Export 导出配置
 	Export ExportConfig `json:"export"`
+
+	// TimeSeries 时间序列采样配置
+	TimeSeries TimeSeriesConfig `json:"time_series" yaml:"time_series"`
+
+	// SLA 测试结束后判定的SLA规则，如 "p99 < 20ms"
+	SLA []string `json:"sla,omitempty" yaml:"sla,omitempty"`
 }
 
 // LatencyConfig 延迟配置
 type LatencyConfig struct {
-	// HistorySize 延迟历史记录大小(环形缓冲区大小)
-	HistorySize int `json:"history_size" default:"10000"`
+	// SignificantDigits HDR直方图有效数字位数(1-5)，决定分位数的相对精度
+	SignificantDigits int `json:"significant_digits" default:"3"`
 
 	// Percentiles 需要计算的分位数
 	Percentiles []float64 `json:"percentiles" default:"[0.5,0.9,0.95,0.99]"`
@@ -61,6 +69,18 @@ type ThroughputConfig struct {
 	UpdateInterval time.Duration `json:"update_interval" default:"1s"`
 }
 
+// TimeSeriesConfig 时间序列采样配置
+type TimeSeriesConfig struct {
+	// Enabled 是否启用时间序列采样
+	Enabled bool `json:"enabled" yaml:"enabled" default:"true"`
+
+	// Interval 采样间隔
+	Interval time.Duration `json:"interval" yaml:"interval" default:"1s"`
+
+	// MaxPoints 保留的最大采样点数量，超出后丢弃最早的采样点
+	MaxPoints int `json:"max_points" yaml:"max_points" default:"3600"`
+}
+
 // SystemConfig 系统监控配置
 type SystemConfig struct {
 	// MonitorInterval 监控间隔
@@ -113,6 +133,90 @@ type ExportConfig struct {
 
 	// Enabled 是否启用自动导出
 	Enabled bool `json:"enabled" default:"false"`
+
+	// OTLP OpenTelemetry导出配置
+	OTLP OTLPConfig `json:"otlp" yaml:"otlp"`
+
+	// Live 运行期间的实时数据点输出（InfluxDB行协议、StatsD）
+	Live LiveExportConfig `json:"live" yaml:"live"`
+}
+
+// LiveExportConfig 实时数据点输出配置，每个间隔输出一个数据点（间隔内的操作数、RPS、错误率与延迟分位数）
+type LiveExportConfig struct {
+	// Sinks 启用的输出目标：influxdb、statsd，为空时不输出
+	Sinks []string `json:"sinks" yaml:"sinks"`
+
+	// Interval 数据点间隔
+	Interval time.Duration `json:"interval" yaml:"interval" default:"1s"`
+
+	// FlushInterval 缓冲的数据点发送间隔，发送失败的数据点在下次发送时重试
+	FlushInterval time.Duration `json:"flush_interval" yaml:"flush_interval" default:"1s"`
+
+	// InfluxDB InfluxDB行协议输出配置
+	InfluxDB InfluxDBConfig `json:"influxdb" yaml:"influxdb"`
+
+	// StatsD StatsD输出配置
+	StatsD StatsDConfig `json:"statsd" yaml:"statsd"`
+}
+
+// InfluxDBConfig InfluxDB行协议（HTTP写入）配置
+type InfluxDBConfig struct {
+	// URL 写入地址，如 http://localhost:8086/api/v2/write?org=perf&bucket=bench（1.x为 /write?db=bench）
+	URL string `json:"url" yaml:"url"`
+
+	// Token InfluxDB 2.x的API token，以"Authorization: Token"发送
+	Token string `json:"token" yaml:"token"`
+
+	// Measurement 数据点的measurement名称
+	Measurement string `json:"measurement" yaml:"measurement" default:"abc_runner"`
+
+	// Tags 附加到每个数据点的标签
+	Tags map[string]string `json:"tags" yaml:"tags"`
+
+	// Timeout 单次写入超时
+	Timeout time.Duration `json:"timeout" yaml:"timeout" default:"5s"`
+}
+
+// StatsDConfig StatsD（UDP）输出配置
+type StatsDConfig struct {
+	// Address StatsD服务地址
+	Address string `json:"address" yaml:"address" default:"localhost:8125"`
+
+	// Prefix 指标名前缀，未启用DogStatsD标签时协议名接在前缀之后
+	Prefix string `json:"prefix" yaml:"prefix" default:"abc_runner"`
+
+	// DogStatsD 以DogStatsD格式（|#key:value）附加协议与Tags标签
+	DogStatsD bool `json:"dogstatsd" yaml:"dogstatsd"`
+
+	// Tags 附加标签，仅DogStatsD格式输出
+	Tags map[string]string `json:"tags" yaml:"tags"`
+}
+
+// OTLPConfig OpenTelemetry(OTLP/HTTP)导出配置
+type OTLPConfig struct {
+	// Enabled 是否启用OTLP导出
+	Enabled bool `json:"enabled" yaml:"enabled" default:"false"`
+
+	// Endpoint OTel collector的OTLP/HTTP地址，如 http://localhost:4318
+	Endpoint string `json:"endpoint" yaml:"endpoint"`
+
+	// Interval 快照推送间隔
+	Interval time.Duration `json:"interval" yaml:"interval" default:"10s"`
+
+	// Timeout 单次请求超时
+	Timeout time.Duration `json:"timeout" yaml:"timeout" default:"5s"`
+
+	// Headers 附加请求头（如认证信息）
+	Headers map[string]string `json:"headers" yaml:"headers"`
+
+	// ServiceName 资源属性 service.name
+	ServiceName string `json:"service_name" yaml:"service_name" default:"abc-runner"`
+
+	// Traces 是否为每个操作导出span
+	Traces bool `json:"traces" yaml:"traces" default:"false"`
+
+	// MaxQueueSize 两次推送之间缓冲的最大span数量，超出部分丢弃
+	MaxQueueSize int `json:"max_queue_size" yaml:"max_queue_size" default:"10000"`
 }
 
 // MetricsCollectorFactory 指标收集器工厂接口
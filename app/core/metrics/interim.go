@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+)
+
+// DefaultInterimInterval 默认的阶段汇总间隔
+const DefaultInterimInterval = 10 * time.Second
+
+// InterimSummary 运行期间的阶段汇总：累计计数与最近一个间隔的吞吐量和延迟
+type InterimSummary struct {
+	Elapsed  time.Duration   // 自运行开始的时长
+	Total    int64           // 累计操作数
+	Failed   int64           // 累计失败数
+	Interval IntervalSummary // 最近一个间隔
+}
+
+// String 单行汇总，同时用于标准输出与日志文件
+func (s InterimSummary) String() string {
+	errorRate := 0.0
+	if s.Total > 0 {
+		errorRate = float64(s.Failed) / float64(s.Total) * 100
+	}
+	return fmt.Sprintf("[%s] ops %d (+%d) | rps %.0f | p99 %v | errors %d (%.2f%%)",
+		s.Elapsed.Truncate(time.Second), s.Total, s.Interval.Operations, s.Interval.RPS,
+		s.Interval.P99.Round(time.Microsecond), s.Failed, errorRate)
+}
+
+// InterimReporter 阶段汇总器
+// 作为结果观察者按间隔汇总操作结果，每个间隔结束时以最近间隔的吞吐量与P99（滚动值）和累计计数调用emit
+type InterimReporter struct {
+	interval time.Duration
+	emit     func(InterimSummary)
+
+	mutex     sync.RWMutex
+	startedAt time.Time
+	current   *intervalAccumulator
+	closed    bool
+
+	// 累计计数，只在汇总协程中更新
+	total  int64
+	failed int64
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewInterimReporter 创建阶段汇总器并开始计时，interval<=0时使用默认间隔
+func NewInterimReporter(interval time.Duration, emit func(InterimSummary)) *InterimReporter {
+	if interval <= 0 {
+		interval = DefaultInterimInterval
+	}
+	now := time.Now()
+	r := &InterimReporter{
+		interval:  interval,
+		emit:      emit,
+		startedAt: now,
+		current:   newIntervalAccumulator(now),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+// Interval 汇总间隔
+func (r *InterimReporter) Interval() time.Duration {
+	return r.interval
+}
+
+// Observe 记录单个操作结果
+func (r *InterimReporter) Observe(result *interfaces.OperationResult) {
+	if result == nil {
+		return
+	}
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if r.closed {
+		return
+	}
+	r.current.add(result)
+}
+
+// run 按间隔输出阶段汇总
+func (r *InterimReporter) run() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			r.emit(r.flush(now))
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// flush 结束当前间隔并生成阶段汇总
+func (r *InterimReporter) flush(now time.Time) InterimSummary {
+	r.mutex.Lock()
+	interval := r.current
+	r.current = newIntervalAccumulator(now)
+	r.mutex.Unlock()
+
+	// 切换后仍持有旧累加器的记录已在写锁前完成，此处可直接读取
+	summary := interval.summary(now)
+	r.total += summary.Operations
+	r.failed += summary.Failed
+	return InterimSummary{
+		Elapsed:  now.Sub(r.startedAt),
+		Total:    r.total,
+		Failed:   r.failed,
+		Interval: summary,
+	}
+}
+
+// Close 停止汇总，不再输出
+func (r *InterimReporter) Close() {
+	r.stopOnce.Do(func() {
+		r.mutex.Lock()
+		r.closed = true
+		r.mutex.Unlock()
+		close(r.stop)
+	})
+	<-r.done
+}
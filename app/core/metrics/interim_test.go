@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+)
+
+func TestInterimReporter_RollingIntervals(t *testing.T) {
+	summaries := make(chan InterimSummary, 1)
+	reporter := NewInterimReporter(time.Hour, func(s InterimSummary) { summaries <- s })
+	defer reporter.Close()
+
+	start := reporter.startedAt
+	for i := 0; i < 100; i++ {
+		if i%20 == 0 {
+			reporter.Observe(&interfaces.OperationResult{Success: false, Duration: 100 * time.Millisecond, Error: errors.New("timeout")})
+			continue
+		}
+		reporter.Observe(&interfaces.OperationResult{Success: true, Duration: time.Millisecond})
+	}
+	first := reporter.flush(start.Add(10 * time.Second))
+	if first.Total != 100 || first.Failed != 5 || first.Interval.RPS != 10 || first.Interval.P99 < 99*time.Millisecond {
+		t.Errorf("unexpected first summary: %+v", first)
+	}
+
+	// 第二个间隔的P99只反映本间隔的样本，计数累计
+	for i := 0; i < 50; i++ {
+		reporter.Observe(&interfaces.OperationResult{Success: true, Duration: 2 * time.Millisecond})
+	}
+	second := reporter.flush(start.Add(20 * time.Second))
+	if second.Total != 150 || second.Failed != 5 || second.Interval.Operations != 50 || second.Interval.P99 > 3*time.Millisecond {
+		t.Errorf("unexpected second summary: %+v", second)
+	}
+
+	line := second.String()
+	for _, want := range []string{"[20s]", "ops 150 (+50)", "rps 5", "errors 5 (3.33%)"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("summary line %q missing %q", line, want)
+		}
+	}
+
+	reporter.Close()
+	reporter.Observe(&interfaces.OperationResult{Success: true})
+	select {
+	case s := <-summaries:
+		t.Errorf("unexpected summary after close: %+v", s)
+	default:
+	}
+}
@@ -0,0 +1,433 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+)
+
+// 实时数据点的输出目标
+const (
+	LiveSinkInfluxDB = "influxdb"
+	LiveSinkStatsD   = "statsd"
+)
+
+// maxLivePending 每个输出目标最多缓冲的数据点，目标持续不可用时丢弃最早的
+const maxLivePending = 3600
+
+// statsdPacketSize 单个StatsD UDP包的最大字节数，避免在常见MTU下分片
+const statsdPacketSize = 1432
+
+// LiveSink 实时数据点的输出目标
+type LiveSink interface {
+	// Name 目标名称，用于提示信息
+	Name() string
+	// Write 发送一批数据点
+	Write(ctx context.Context, points []InterimSummary) error
+	// Close 释放连接
+	Close() error
+}
+
+// liveTarget 输出目标及其待发送的数据点
+type liveTarget struct {
+	sink    LiveSink
+	pending []InterimSummary
+	failing bool
+	dropped int
+}
+
+// LiveExporter 运行期间按间隔输出数据点到InfluxDB、StatsD等目标
+// 作为结果观察者按Interval汇总操作结果，每个FlushInterval发送一次缓冲的数据点
+type LiveExporter struct {
+	config   LiveExportConfig
+	targets  []*liveTarget
+	reporter *InterimReporter
+
+	mutex  sync.Mutex
+	points []InterimSummary
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// validateLiveExport 校验实时数据点输出配置
+func validateLiveExport(config LiveExportConfig) error {
+	if len(config.Sinks) == 0 {
+		return nil
+	}
+	if config.Interval <= 0 {
+		return fmt.Errorf("export.live.interval must be positive")
+	}
+	if config.FlushInterval <= 0 {
+		return fmt.Errorf("export.live.flush_interval must be positive")
+	}
+	for _, sink := range config.Sinks {
+		switch sink {
+		case LiveSinkInfluxDB:
+			if config.InfluxDB.URL == "" {
+				return fmt.Errorf("export.live.influxdb.url is required when the influxdb sink is enabled")
+			}
+		case LiveSinkStatsD:
+			if config.StatsD.Address == "" {
+				return fmt.Errorf("export.live.statsd.address is required when the statsd sink is enabled")
+			}
+		default:
+			return fmt.Errorf("unknown export.live sink %q (expected %s or %s)", sink, LiveSinkInfluxDB, LiveSinkStatsD)
+		}
+	}
+	return nil
+}
+
+// NewLiveExporter 创建实时数据点输出器，protocol作为数据点的protocol标签（为空时不输出）
+func NewLiveExporter(config LiveExportConfig, protocol string) (*LiveExporter, error) {
+	defaults := DefaultLiveExportConfig()
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = defaults.FlushInterval
+	}
+	if err := validateLiveExport(config); err != nil {
+		return nil, err
+	}
+
+	e := &LiveExporter{
+		config: config,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	for _, name := range config.Sinks {
+		var sink LiveSink
+		switch name {
+		case LiveSinkInfluxDB:
+			sink = newInfluxSink(config.InfluxDB, protocol)
+		case LiveSinkStatsD:
+			statsd, err := newStatsDSink(config.StatsD, protocol)
+			if err != nil {
+				e.closeSinks()
+				return nil, err
+			}
+			sink = statsd
+		}
+		e.targets = append(e.targets, &liveTarget{sink: sink})
+	}
+	return e, nil
+}
+
+// Sinks 启用的输出目标名称
+func (e *LiveExporter) Sinks() []string {
+	names := make([]string, len(e.targets))
+	for i, target := range e.targets {
+		names[i] = target.sink.Name()
+	}
+	return names
+}
+
+// Start 开始按间隔汇总并周期性发送
+func (e *LiveExporter) Start() {
+	e.reporter = NewInterimReporter(e.config.Interval, func(summary InterimSummary) {
+		e.mutex.Lock()
+		e.points = append(e.points, summary)
+		e.mutex.Unlock()
+	})
+	go e.run()
+}
+
+// Observe 记录单个操作结果
+func (e *LiveExporter) Observe(result *interfaces.OperationResult) {
+	if e.reporter != nil {
+		e.reporter.Observe(result)
+	}
+}
+
+// run 按发送间隔发送缓冲的数据点
+func (e *LiveExporter) run() {
+	defer close(e.done)
+	ticker := time.NewTicker(e.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			e.flush()
+		case <-e.stop:
+			return
+		}
+	}
+}
+
+// flush 将新数据点交给各目标并发送，失败时保留待下次重试
+func (e *LiveExporter) flush() {
+	e.mutex.Lock()
+	points := e.points
+	e.points = nil
+	e.mutex.Unlock()
+
+	for _, target := range e.targets {
+		target.pending = append(target.pending, points...)
+		if excess := len(target.pending) - maxLivePending; excess > 0 {
+			target.pending = target.pending[excess:]
+			target.dropped += excess
+		}
+		if len(target.pending) == 0 {
+			continue
+		}
+
+		// 各目标自身限定单次发送的超时
+		if err := target.sink.Write(context.Background(), target.pending); err != nil {
+			// 只在首次失败时提示，避免目标不可用期间每次发送都输出
+			if !target.failing {
+				fmt.Printf("⚠️  Live export to %s failed, retrying: %v\n", target.sink.Name(), err)
+			}
+			target.failing = true
+			continue
+		}
+		target.pending = nil
+		target.failing = false
+	}
+}
+
+// Stop 停止汇总，发送剩余数据点并关闭各目标；返回仍未送达的数据点数
+func (e *LiveExporter) Stop() int {
+	undelivered := 0
+	e.stopOnce.Do(func() {
+		if e.reporter != nil {
+			e.reporter.Close()
+			close(e.stop)
+			<-e.done
+		}
+		e.flush()
+		for _, target := range e.targets {
+			undelivered += len(target.pending) + target.dropped
+		}
+		e.closeSinks()
+	})
+	return undelivered
+}
+
+// closeSinks 关闭各目标
+func (e *LiveExporter) closeSinks() {
+	for _, target := range e.targets {
+		target.sink.Close()
+	}
+}
+
+// influxSink 以InfluxDB行协议经HTTP写入
+type influxSink struct {
+	config InfluxDBConfig
+	tags   string // 已排序、转义的标签部分（以","开头）
+	client *http.Client
+}
+
+// newInfluxSink 创建InfluxDB输出目标
+func newInfluxSink(config InfluxDBConfig, protocol string) *influxSink {
+	defaults := DefaultLiveExportConfig().InfluxDB
+	if config.Measurement == "" {
+		config.Measurement = defaults.Measurement
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+	tags := make(map[string]string, len(config.Tags)+1)
+	for key, value := range config.Tags {
+		tags[key] = value
+	}
+	if protocol != "" {
+		tags["protocol"] = protocol
+	}
+	return &influxSink{
+		config: config,
+		tags:   influxTags(tags),
+		client: &http.Client{Timeout: config.Timeout},
+	}
+}
+
+// influxTags 按键排序的标签（行协议建议排序以提高写入性能），空值的标签被跳过
+func influxTags(tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for key, value := range tags {
+		if value != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, key := range keys {
+		b.WriteString("," + escapeInflux(key) + "=" + escapeInflux(tags[key]))
+	}
+	return b.String()
+}
+
+// escapeInflux 转义行协议中标签键、值与measurement里的特殊字符
+func escapeInflux(value string) string {
+	return strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `).Replace(value)
+}
+
+// Name 目标名称
+func (s *influxSink) Name() string {
+	return LiveSinkInfluxDB
+}
+
+// Write 将数据点编码为行协议并写入
+func (s *influxSink) Write(ctx context.Context, points []InterimSummary) error {
+	var body bytes.Buffer
+	for _, point := range points {
+		writeInfluxLine(&body, s.config.Measurement, s.tags, point)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.URL, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if s.config.Token != "" {
+		req.Header.Set("Authorization", "Token "+s.config.Token)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, maxPushErrorBody))
+		return fmt.Errorf("influxdb responded with status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// Close 关闭空闲连接
+func (s *influxSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// writeInfluxLine 写出一个数据点，延迟以毫秒为单位，时间戳为间隔结束时间（纳秒）
+func writeInfluxLine(w io.Writer, measurement, tags string, point InterimSummary) {
+	interval := point.Interval
+	fmt.Fprintf(w, "%s%s operations=%di,success=%di,failed=%di,rps=%s,error_rate=%s,"+
+		"latency_avg_ms=%s,latency_p50_ms=%s,latency_p90_ms=%s,latency_p99_ms=%s,latency_max_ms=%s,"+
+		"total_operations=%di,total_failed=%di %d\n",
+		escapeInflux(measurement), tags,
+		interval.Operations, interval.Success, interval.Failed,
+		influxFloat(interval.RPS), influxFloat(interval.ErrorRate),
+		influxFloat(milliseconds(interval.Average)), influxFloat(milliseconds(interval.P50)),
+		influxFloat(milliseconds(interval.P90)), influxFloat(milliseconds(interval.P99)),
+		influxFloat(milliseconds(interval.Max)),
+		point.Total, point.Failed, interval.End.UnixNano())
+}
+
+// influxFloat 格式化行协议中的浮点字段
+func influxFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// milliseconds 以毫秒表示的时长
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// statsdSink 经UDP发送StatsD指标
+type statsdSink struct {
+	conn   net.Conn
+	prefix string
+	suffix string // DogStatsD标签部分（以"|#"开头），未启用时为空
+}
+
+// newStatsDSink 创建StatsD输出目标
+func newStatsDSink(config StatsDConfig, protocol string) (*statsdSink, error) {
+	defaults := DefaultLiveExportConfig().StatsD
+	if config.Prefix == "" {
+		config.Prefix = defaults.Prefix
+	}
+	conn, err := net.Dial("udp", config.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open statsd socket %s: %w", config.Address, err)
+	}
+
+	sink := &statsdSink{conn: conn, prefix: config.Prefix}
+	if !config.DogStatsD {
+		if protocol != "" {
+			sink.prefix += "." + protocol
+		}
+		return sink, nil
+	}
+	tags := make([]string, 0, len(config.Tags)+1)
+	if protocol != "" {
+		tags = append(tags, "protocol:"+protocol)
+	}
+	for key, value := range config.Tags {
+		tags = append(tags, key+":"+value)
+	}
+	sort.Strings(tags)
+	if len(tags) > 0 {
+		sink.suffix = "|#" + strings.Join(tags, ",")
+	}
+	return sink, nil
+}
+
+// Name 目标名称
+func (s *statsdSink) Name() string {
+	return LiveSinkStatsD
+}
+
+// Write 每个数据点输出操作与失败计数（counter）及RPS、错误率与延迟（gauge，毫秒），按包大小分批发送
+func (s *statsdSink) Write(ctx context.Context, points []InterimSummary) error {
+	var packet []byte
+	send := func() error {
+		if len(packet) == 0 {
+			return nil
+		}
+		_, err := s.conn.Write(packet)
+		packet = packet[:0]
+		return err
+	}
+	for _, point := range points {
+		for _, line := range s.lines(point) {
+			if len(packet) > 0 && len(packet)+1+len(line) > statsdPacketSize {
+				if err := send(); err != nil {
+					return err
+				}
+			}
+			if len(packet) > 0 {
+				packet = append(packet, '\n')
+			}
+			packet = append(packet, line...)
+		}
+	}
+	return send()
+}
+
+// lines 一个数据点的StatsD指标行
+func (s *statsdSink) lines(point InterimSummary) []string {
+	interval := point.Interval
+	metric := func(name, value, kind string) string {
+		return s.prefix + "." + name + ":" + value + "|" + kind + s.suffix
+	}
+	return []string{
+		metric("operations", strconv.FormatInt(interval.Operations, 10), "c"),
+		metric("errors", strconv.FormatInt(interval.Failed, 10), "c"),
+		metric("rps", influxFloat(interval.RPS), "g"),
+		metric("error_rate", influxFloat(interval.ErrorRate), "g"),
+		metric("latency.avg", influxFloat(milliseconds(interval.Average)), "g"),
+		metric("latency.p50", influxFloat(milliseconds(interval.P50)), "g"),
+		metric("latency.p90", influxFloat(milliseconds(interval.P90)), "g"),
+		metric("latency.p99", influxFloat(milliseconds(interval.P99)), "g"),
+		metric("latency.max", influxFloat(milliseconds(interval.Max)), "g"),
+	}
+}
+
+// Close 关闭UDP连接
+func (s *statsdSink) Close() error {
+	return s.conn.Close()
+}
@@ -0,0 +1,151 @@
+package metrics
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+)
+
+func TestConfigManager_LoadLiveExportConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.yaml")
+	content := "export:\n  live:\n    sinks: [influxdb, statsd]\n    flush_interval: \"5s\"\n    influxdb:\n      url: \"http://influx:8086/write?db=bench\"\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := NewConfigManager(path)
+	if err := manager.LoadConfig(); err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	live := manager.GetConfig().Export.Live
+	if len(live.Sinks) != 2 || live.FlushInterval != 5*time.Second || live.InfluxDB.URL != "http://influx:8086/write?db=bench" {
+		t.Errorf("unexpected live config: %+v", live)
+	}
+	if live.Interval != time.Second || live.InfluxDB.Measurement != "abc_runner" || live.StatsD.Address != "localhost:8125" {
+		t.Errorf("expected defaults to be applied, got %+v", live)
+	}
+
+	if err := os.WriteFile(path, []byte("export:\n  live:\n    sinks: [graphite]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewConfigManager(path).LoadConfig(); err == nil || !strings.Contains(err.Error(), "graphite") {
+		t.Errorf("expected unknown sink to be rejected, got %v", err)
+	}
+}
+
+func TestLiveExporter_InfluxDB(t *testing.T) {
+	var mutex sync.Mutex
+	var lines []string
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		requests++
+		// 第一次写入失败，数据点应在下次发送时重试
+		if requests == 1 {
+			http.Error(w, "database is starting", http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("Authorization") != "Token secret" {
+			t.Errorf("missing token: %q", r.Header.Get("Authorization"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		lines = append(lines, strings.Split(strings.TrimSpace(string(body)), "\n")...)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	config := DefaultLiveExportConfig()
+	config.Sinks = []string{LiveSinkInfluxDB}
+	config.Interval = 20 * time.Millisecond
+	config.FlushInterval = 50 * time.Millisecond
+	config.InfluxDB.URL = server.URL
+	config.InfluxDB.Token = "secret"
+	config.InfluxDB.Tags = map[string]string{"env": "ci"}
+	exporter, err := NewLiveExporter(config, "http")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exporter.Start()
+	for i := 0; i < 10; i++ {
+		exporter.Observe(&interfaces.OperationResult{Success: i != 0, Duration: 2 * time.Millisecond})
+	}
+	time.Sleep(150 * time.Millisecond)
+	if undelivered := exporter.Stop(); undelivered != 0 {
+		t.Errorf("undelivered = %d, want 0", undelivered)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if requests < 2 || len(lines) < 3 {
+		t.Fatalf("requests = %d, lines = %v", requests, lines)
+	}
+	first := lines[0]
+	if !strings.HasPrefix(first, "abc_runner,env=ci,protocol=http operations=10i,success=9i,failed=1i,") ||
+		!strings.Contains(first, ",latency_max_ms=2,") || !strings.Contains(first, "total_operations=10i,total_failed=1i ") {
+		t.Errorf("unexpected first line: %s", first)
+	}
+	// 没有操作的间隔同样输出数据点，累计计数保持不变
+	if !strings.Contains(lines[1], "operations=0i") || !strings.Contains(lines[1], "total_operations=10i") {
+		t.Errorf("unexpected second line: %s", lines[1])
+	}
+}
+
+func TestLiveExporter_StatsD(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	config := DefaultLiveExportConfig()
+	config.Sinks = []string{LiveSinkStatsD}
+	config.Interval = 20 * time.Millisecond
+	config.FlushInterval = 30 * time.Millisecond
+	config.StatsD.Address = conn.LocalAddr().String()
+	config.StatsD.DogStatsD = true
+	config.StatsD.Tags = map[string]string{"env": "ci"}
+	exporter, err := NewLiveExporter(config, "redis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exporter.Start()
+	exporter.Observe(&interfaces.OperationResult{Success: false, Duration: 3 * time.Millisecond})
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buffer := make([]byte, statsdPacketSize)
+	n, _, err := conn.ReadFrom(buffer)
+	exporter.Stop()
+	if err != nil {
+		t.Fatalf("no statsd packet received: %v", err)
+	}
+	packet := string(buffer[:n])
+	for _, want := range []string{
+		"abc_runner.operations:1|c|#env:ci,protocol:redis\n",
+		"abc_runner.errors:1|c|#env:ci,protocol:redis\n",
+		"abc_runner.latency.max:3|g|#env:ci,protocol:redis",
+	} {
+		if !strings.Contains(packet, want) {
+			t.Errorf("packet missing %q:\n%s", want, packet)
+		}
+	}
+
+	// 未启用DogStatsD时协议名接在前缀之后
+	sink, err := newStatsDSink(StatsDConfig{Address: conn.LocalAddr().String(), Prefix: "bench"}, "redis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	if line := sink.lines(InterimSummary{})[2]; line != "bench.redis.rps:0|g" {
+		t.Errorf("plain statsd line = %q", line)
+	}
+}
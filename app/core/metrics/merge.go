@@ -0,0 +1,176 @@
+package metrics
+
+import (
+	"math"
+	"time"
+)
+
+// MergeSnapshots 合并多个来源（如分布式测试中的多个worker）的指标快照
+// 计数类指标直接累加，吞吐量按并发来源求和；
+// 各来源均携带延迟直方图时合并直方图计算分位数，否则按操作数加权近似
+func MergeSnapshots(snapshots ...*DefaultMetricsSnapshot) *DefaultMetricsSnapshot {
+	merged := &DefaultMetricsSnapshot{
+		Protocol: make(map[string]interface{}),
+	}
+	histogram := mergeHistograms(snapshots)
+
+	var (
+		latencyOps   int64
+		weightedAvg  float64
+		weightedP50  float64
+		weightedP90  float64
+		weightedP95  float64
+		weightedP99  float64
+		weightedP999 float64
+		sumOfSquares float64
+		cpuUsage     float64
+		sources      int
+	)
+
+	for _, snapshot := range snapshots {
+		if snapshot == nil {
+			continue
+		}
+		sources++
+
+		// 操作指标
+		ops := snapshot.Core.Operations
+		merged.Core.Operations.Total += ops.Total
+		merged.Core.Operations.Success += ops.Success
+		merged.Core.Operations.Failed += ops.Failed
+		merged.Core.Operations.Read += ops.Read
+		merged.Core.Operations.Write += ops.Write
+		merged.Core.Operations.BytesRead += ops.BytesRead
+		merged.Core.Operations.BytesWritten += ops.BytesWritten
+
+		// 吞吐量（各来源并发执行，直接求和）
+		merged.Core.Throughput.RPS += snapshot.Core.Throughput.RPS
+		merged.Core.Throughput.ReadRPS += snapshot.Core.Throughput.ReadRPS
+		merged.Core.Throughput.WriteRPS += snapshot.Core.Throughput.WriteRPS
+		merged.Core.Throughput.ReadMBps += snapshot.Core.Throughput.ReadMBps
+		merged.Core.Throughput.WriteMBps += snapshot.Core.Throughput.WriteMBps
+
+		if snapshot.Core.Duration > merged.Core.Duration {
+			merged.Core.Duration = snapshot.Core.Duration
+		}
+		if snapshot.Timestamp.After(merged.Timestamp) {
+			merged.Timestamp = snapshot.Timestamp
+		}
+
+		// 延迟指标
+		latency := snapshot.Core.Latency
+		if ops.Total > 0 {
+			if latency.Min > 0 && (merged.Core.Latency.Min == 0 || latency.Min < merged.Core.Latency.Min) {
+				merged.Core.Latency.Min = latency.Min
+			}
+			if latency.Max > merged.Core.Latency.Max {
+				merged.Core.Latency.Max = latency.Max
+			}
+
+			weight := float64(ops.Total)
+			latencyOps += ops.Total
+			weightedAvg += float64(latency.Average) * weight
+			weightedP50 += float64(latency.P50) * weight
+			weightedP90 += float64(latency.P90) * weight
+			weightedP95 += float64(latency.P95) * weight
+			weightedP99 += float64(latency.P99) * weight
+			weightedP999 += float64(latency.P999) * weight
+
+			// 合并方差所需的 n*(sd^2 + mean^2)
+			sd := float64(latency.StdDeviation)
+			mean := float64(latency.Average)
+			sumOfSquares += weight * (sd*sd + mean*mean)
+		}
+
+		// 系统指标（跨主机累加）
+		system := snapshot.System
+		merged.System.MemoryUsage.Allocated += system.MemoryUsage.Allocated
+		merged.System.MemoryUsage.InUse += system.MemoryUsage.InUse
+		merged.System.MemoryUsage.TotalAlloc += system.MemoryUsage.TotalAlloc
+		merged.System.MemoryUsage.Sys += system.MemoryUsage.Sys
+		merged.System.MemoryUsage.GCReleased += system.MemoryUsage.GCReleased
+		merged.System.GCStats.NumGC += system.GCStats.NumGC
+		merged.System.GCStats.TotalPause += system.GCStats.TotalPause
+		if system.GCStats.LastGC.After(merged.System.GCStats.LastGC) {
+			merged.System.GCStats.LastGC = system.GCStats.LastGC
+		}
+		merged.System.GoroutineCount += system.GoroutineCount
+		merged.System.CPUUsage.Cores += system.CPUUsage.Cores
+		cpuUsage += system.CPUUsage.UsagePercent
+	}
+
+	if merged.Core.Operations.Total > 0 {
+		merged.Core.Operations.Rate = float64(merged.Core.Operations.Success) / float64(merged.Core.Operations.Total) * 100.0
+	}
+
+	if latencyOps > 0 {
+		total := float64(latencyOps)
+		mean := weightedAvg / total
+		merged.Core.Latency.Average = time.Duration(mean)
+		merged.Core.Latency.P50 = time.Duration(weightedP50 / total)
+		merged.Core.Latency.P90 = time.Duration(weightedP90 / total)
+		merged.Core.Latency.P95 = time.Duration(weightedP95 / total)
+		merged.Core.Latency.P99 = time.Duration(weightedP99 / total)
+		merged.Core.Latency.P999 = time.Duration(weightedP999 / total)
+
+		variance := sumOfSquares/total - mean*mean
+		if variance > 0 {
+			merged.Core.Latency.StdDeviation = time.Duration(math.Sqrt(variance))
+		}
+	}
+
+	if histogram != nil && histogram.TotalCount() > 0 {
+		max := int64(merged.Core.Latency.Max)
+		values := histogram.ValuesAtQuantiles(50, 90, 95, 99, 99.9)
+		for i, v := range values {
+			if max > 0 && v > max {
+				values[i] = max
+			}
+		}
+		merged.Core.Latency.P50 = time.Duration(values[0])
+		merged.Core.Latency.P90 = time.Duration(values[1])
+		merged.Core.Latency.P95 = time.Duration(values[2])
+		merged.Core.Latency.P99 = time.Duration(values[3])
+		merged.Core.Latency.P999 = time.Duration(values[4])
+		merged.LatencyHistogram = histogram.Export()
+	}
+
+	if merged.System.GCStats.NumGC > 0 {
+		merged.System.GCStats.AveragePause = merged.System.GCStats.TotalPause / time.Duration(merged.System.GCStats.NumGC)
+	}
+	if sources > 0 {
+		merged.System.CPUUsage.UsagePercent = cpuUsage / float64(sources)
+	}
+	if merged.Timestamp.IsZero() {
+		merged.Timestamp = time.Now()
+	}
+
+	merged.Protocol["merged_sources"] = sources
+	return merged
+}
+
+// mergeHistograms 合并各来源的延迟直方图，任一有操作的来源缺少直方图或范围不一致时返回nil
+func mergeHistograms(snapshots []*DefaultMetricsSnapshot) *HdrHistogram {
+	var merged *HdrHistogram
+	for _, snapshot := range snapshots {
+		if snapshot == nil || snapshot.Core.Operations.Total == 0 {
+			continue
+		}
+		if snapshot.LatencyHistogram == nil {
+			return nil
+		}
+		histogram, err := NewHdrHistogramFromExport(snapshot.LatencyHistogram)
+		if err != nil {
+			return nil
+		}
+		if merged == nil {
+			merged = histogram
+			continue
+		}
+		if !merged.Compatible(histogram) {
+			return nil
+		}
+		merged.Merge(histogram)
+	}
+	return merged
+}
@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestMergeSnapshots_MergesLatencyHistograms(t *testing.T) {
+	// 来源A：50个1ms的操作；来源B：150个100ms的操作
+	// 按操作数加权的p50约为75ms，合并直方图的真实p50与p90均为100ms
+	newSnapshot := func(fast, slow int) *DefaultMetricsSnapshot {
+		histogram := NewHdrHistogram(DefaultHdrHighestValue, DefaultHdrSignificantDigits)
+		for i := 0; i < fast; i++ {
+			histogram.Record(int64(time.Millisecond))
+		}
+		for i := 0; i < slow; i++ {
+			histogram.Record(int64(100 * time.Millisecond))
+		}
+		values := histogram.ValuesAtQuantiles(50, 90, 99)
+
+		snapshot := &DefaultMetricsSnapshot{Protocol: map[string]interface{}{}}
+		snapshot.Core.Operations.Total = int64(fast + slow)
+		snapshot.Core.Operations.Success = int64(fast + slow)
+		snapshot.Core.Latency.Min = time.Millisecond
+		snapshot.Core.Latency.Max = 100 * time.Millisecond
+		if slow == 0 {
+			snapshot.Core.Latency.Max = time.Millisecond
+		}
+		snapshot.Core.Latency.P50 = time.Duration(values[0])
+		snapshot.Core.Latency.P90 = time.Duration(values[1])
+		snapshot.Core.Latency.P99 = time.Duration(values[2])
+		snapshot.LatencyHistogram = histogram.Export()
+
+		// 模拟agent经JSON传输
+		data, err := json.Marshal(snapshot)
+		if err != nil {
+			t.Fatalf("failed to encode snapshot: %v", err)
+		}
+		decoded := &DefaultMetricsSnapshot{}
+		if err := json.Unmarshal(data, decoded); err != nil {
+			t.Fatalf("failed to decode snapshot: %v", err)
+		}
+		return decoded
+	}
+
+	a := newSnapshot(50, 0)
+	b := newSnapshot(0, 150)
+
+	merged := MergeSnapshots(a, b)
+	within := func(got, want time.Duration) bool {
+		return got >= want && float64(got-want) <= float64(want)*0.001
+	}
+	if !within(merged.Core.Latency.P50, 100*time.Millisecond) {
+		t.Errorf("p50 = %v, want ~100ms (from merged histogram)", merged.Core.Latency.P50)
+	}
+	if !within(merged.Core.Latency.P90, 100*time.Millisecond) {
+		t.Errorf("p90 = %v, want ~100ms", merged.Core.Latency.P90)
+	}
+	if merged.LatencyHistogram == nil {
+		t.Fatal("merged snapshot should carry the merged histogram")
+	}
+	if histogram, err := NewHdrHistogramFromExport(merged.LatencyHistogram); err != nil || histogram.TotalCount() != 200 {
+		t.Errorf("merged histogram invalid: %v", err)
+	}
+
+	// 任一来源缺少直方图时退回加权近似
+	b.LatencyHistogram = nil
+	approximated := MergeSnapshots(a, b)
+	if approximated.LatencyHistogram != nil {
+		t.Error("approximated merge should not carry a histogram")
+	}
+	if approximated.Core.Latency.P50 >= 100*time.Millisecond {
+		t.Errorf("expected weighted approximation below 100ms, got %v", approximated.Core.Latency.P50)
+	}
+}
+
+func TestNewHdrHistogramFromExport_RejectsInvalidData(t *testing.T) {
+	h := NewHdrHistogram(DefaultHdrHighestValue, DefaultHdrSignificantDigits)
+	data := h.Export()
+	data.Counts = [][2]uint64{{uint64(len(h.counts)), 1}}
+	if _, err := NewHdrHistogramFromExport(data); err == nil {
+		t.Error("expected out-of-range index to be rejected")
+	}
+	if _, err := NewHdrHistogramFromExport(&LatencyHistogram{Highest: 1, SignificantDigits: 9}); err == nil {
+		t.Error("expected invalid range to be rejected")
+	}
+}
@@ -0,0 +1,173 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+// noiseSleepInterval 定时器偏差测量中每次请求的睡眠时长
+const noiseSleepInterval = 100 * time.Microsecond
+
+// NoiseFloor 测量前在本机空载运行得到的调度与计时噪声基底，
+// 亚毫秒级结果与之同量级时，差异可能来自主机而非被测系统
+type NoiseFloor struct {
+	Duration time.Duration `json:"duration"`
+
+	// Timer 连续两次读取时钟的间隔，反映计时开销与被抢占的尖刺
+	Timer LatencyMetrics `json:"timer"`
+
+	// Wakeup goroutine间无缓冲通道往返，反映调度唤醒延迟
+	Wakeup LatencyMetrics `json:"wakeup"`
+
+	// SleepOvershoot 请求睡眠100µs时实际多睡的时长，反映定时器精度
+	SleepOvershoot LatencyMetrics `json:"sleep_overshoot"`
+
+	// Loopback 本机TCP回环单字节往返，监听失败时为空
+	Loopback LatencyMetrics `json:"loopback"`
+
+	// Floor 噪声基底：调度唤醒P99与计时P99之和
+	Floor time.Duration `json:"floor"`
+}
+
+// MeasureNoiseFloor 在给定时长内依次测量计时、调度唤醒、定时器与TCP回环噪声，各占四分之一时长
+func MeasureNoiseFloor(ctx context.Context, duration time.Duration) (*NoiseFloor, error) {
+	if duration <= 0 {
+		return nil, fmt.Errorf("noise floor duration must be positive")
+	}
+	phase := duration / 4
+	start := time.Now()
+
+	result := &NoiseFloor{
+		Timer:          measureTimer(ctx, phase),
+		Wakeup:         measureWakeup(ctx, phase),
+		SleepOvershoot: measureSleep(ctx, phase),
+	}
+	// 回环不可用（如受限沙箱）时只缺少该项，不影响其余测量
+	if loopback, err := measureLoopback(ctx, phase); err == nil {
+		result.Loopback = loopback
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result.Duration = time.Since(start)
+	result.Floor = result.Wakeup.P99 + result.Timer.P99
+	return result, nil
+}
+
+func newNoiseTracker() *LatencyTracker {
+	return NewLatencyTracker(LatencyConfig{
+		SamplingRate: 1.0,
+	})
+}
+
+// measureTimer 紧循环读取时钟
+func measureTimer(ctx context.Context, phase time.Duration) LatencyMetrics {
+	tracker := newNoiseTracker()
+	deadline := time.Now().Add(phase)
+	for i := 0; ; i++ {
+		t0 := time.Now()
+		t1 := time.Now()
+		tracker.Record(t1.Sub(t0))
+		if t1.After(deadline) || (i%1024 == 0 && ctx.Err() != nil) {
+			break
+		}
+	}
+	return tracker.GetMetrics()
+}
+
+// measureWakeup 两个goroutine通过无缓冲通道交替唤醒
+func measureWakeup(ctx context.Context, phase time.Duration) LatencyMetrics {
+	tracker := newNoiseTracker()
+	ping := make(chan struct{})
+	pong := make(chan struct{})
+	go func() {
+		for range ping {
+			pong <- struct{}{}
+		}
+	}()
+	defer close(ping)
+
+	deadline := time.Now().Add(phase)
+	for i := 0; ; i++ {
+		t0 := time.Now()
+		ping <- struct{}{}
+		<-pong
+		t1 := time.Now()
+		tracker.Record(t1.Sub(t0))
+		if t1.After(deadline) || (i%1024 == 0 && ctx.Err() != nil) {
+			break
+		}
+	}
+	return tracker.GetMetrics()
+}
+
+// measureSleep 反复短睡眠并记录超出请求时长的部分
+func measureSleep(ctx context.Context, phase time.Duration) LatencyMetrics {
+	tracker := newNoiseTracker()
+	deadline := time.Now().Add(phase)
+	for ctx.Err() == nil {
+		t0 := time.Now()
+		time.Sleep(noiseSleepInterval)
+		t1 := time.Now()
+		if overshoot := t1.Sub(t0) - noiseSleepInterval; overshoot > 0 {
+			tracker.Record(overshoot)
+		} else {
+			tracker.Record(0)
+		}
+		if t1.After(deadline) {
+			break
+		}
+	}
+	return tracker.GetMetrics()
+}
+
+// measureLoopback 通过127.0.0.1上的回显连接做单字节往返
+func measureLoopback(ctx context.Context, phase time.Duration) (LatencyMetrics, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return LatencyMetrics{}, err
+	}
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		io.Copy(conn, conn)
+	}()
+
+	conn, err := net.DialTimeout("tcp", listener.Addr().String(), time.Second)
+	if err != nil {
+		return LatencyMetrics{}, err
+	}
+	defer conn.Close()
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		tcp.SetNoDelay(true)
+	}
+
+	tracker := newNoiseTracker()
+	buf := make([]byte, 1)
+	deadline := time.Now().Add(phase)
+	conn.SetDeadline(deadline.Add(time.Second))
+	for ctx.Err() == nil {
+		t0 := time.Now()
+		if _, err := conn.Write(buf); err != nil {
+			return LatencyMetrics{}, err
+		}
+		if _, err := io.ReadFull(conn, buf); err != nil {
+			return LatencyMetrics{}, err
+		}
+		t1 := time.Now()
+		tracker.Record(t1.Sub(t0))
+		if t1.After(deadline) {
+			break
+		}
+	}
+	return tracker.GetMetrics(), nil
+}
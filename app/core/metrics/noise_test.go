@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMeasureNoiseFloor(t *testing.T) {
+	floor, err := MeasureNoiseFloor(context.Background(), 200*time.Millisecond)
+	if err != nil {
+		t.Fatalf("MeasureNoiseFloor: %v", err)
+	}
+	if floor.Wakeup.P50 <= 0 || floor.Wakeup.P99 < floor.Wakeup.P50 {
+		t.Errorf("wakeup = %+v", floor.Wakeup)
+	}
+	if floor.Timer.Max <= 0 || floor.SleepOvershoot.Max <= 0 {
+		t.Errorf("timer = %+v, sleep overshoot = %+v", floor.Timer, floor.SleepOvershoot)
+	}
+	if floor.Floor != floor.Wakeup.P99+floor.Timer.P99 {
+		t.Errorf("floor = %v, want wakeup p99 + timer p99", floor.Floor)
+	}
+	if floor.Duration < 150*time.Millisecond {
+		t.Errorf("duration = %v, want about 200ms", floor.Duration)
+	}
+
+	if _, err := MeasureNoiseFloor(context.Background(), 0); err == nil {
+		t.Error("expected error for zero duration")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := MeasureNoiseFloor(ctx, time.Second); err == nil {
+		t.Error("expected error for cancelled context")
+	}
+}
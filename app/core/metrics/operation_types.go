@@ -0,0 +1,156 @@
+package metrics
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrorClassifier 将执行错误归类为错误码（如服务端错误号、"timeout"），用于按错误码统计
+type ErrorClassifier func(err error) string
+
+// OperationTypeStats 单个操作类型的统计
+// Volume与Flagged的含义由调用方定义，JSON输出时使用创建统计器时指定的字段名（如"rows"、"bytes"）
+type OperationTypeStats struct {
+	Type       string
+	Count      int64
+	Errors     int64
+	ErrorRate  float64
+	Volume     int64            // 成功执行处理的数据量（字节、文档、行等）
+	Flagged    int64            // 成功执行中被调用方标记的次数（如重新投递的消息）
+	ErrorCodes map[string]int64 // 按错误分类器返回的错误码统计
+	Latency    LatencyMetrics   // 成功执行的延迟
+
+	volumeKey  string
+	flaggedKey string
+}
+
+// MarshalJSON 以调用方指定的字段名输出数据量与标记次数
+func (s OperationTypeStats) MarshalJSON() ([]byte, error) {
+	fields := map[string]interface{}{
+		"type":       s.Type,
+		"count":      s.Count,
+		"errors":     s.Errors,
+		"error_rate": s.ErrorRate,
+		"latency":    s.Latency,
+	}
+	volumeKey := s.volumeKey
+	if volumeKey == "" {
+		volumeKey = "volume"
+	}
+	fields[volumeKey] = s.Volume
+	if s.flaggedKey != "" && s.Flagged > 0 {
+		fields[s.flaggedKey] = s.Flagged
+	}
+	if len(s.ErrorCodes) > 0 {
+		fields["error_codes"] = s.ErrorCodes
+	}
+	return json.Marshal(fields)
+}
+
+// operationTypeCounter 单个操作类型的计数器
+type operationTypeCounter struct {
+	count      int64
+	errors     int64
+	volume     int64
+	flagged    int64
+	errorCodes map[string]int64
+	latency    *LatencyTracker
+}
+
+// OperationTypeTracker 按操作类型统计延迟、数据量与错误码，错误码由适配器提供的分类器决定
+type OperationTypeTracker struct {
+	mutex      sync.Mutex
+	counters   map[string]*operationTypeCounter
+	classify   ErrorClassifier
+	volumeKey  string
+	flaggedKey string
+	config     LatencyConfig
+}
+
+// NewOperationTypeTracker 创建按操作类型的统计器
+// volumeKey与flaggedKey为JSON输出中数据量与标记次数的字段名，flaggedKey为空时不输出标记次数；
+// classify为nil时错误统一记为"error"
+func NewOperationTypeTracker(classify ErrorClassifier, volumeKey, flaggedKey string) *OperationTypeTracker {
+	if classify == nil {
+		classify = func(error) string { return "error" }
+	}
+	return &OperationTypeTracker{
+		counters:   make(map[string]*operationTypeCounter),
+		classify:   classify,
+		volumeKey:  volumeKey,
+		flaggedKey: flaggedKey,
+		config: LatencyConfig{
+			SignificantDigits: DefaultHdrSignificantDigits,
+			SamplingRate:      1.0,
+		},
+	}
+}
+
+// Record 记录一次执行结果，成功时累计数据量与标记次数，失败时按错误码计数
+func (t *OperationTypeTracker) Record(operationType string, volume int64, flagged bool, latency time.Duration, err error) {
+	var code string
+	if err != nil {
+		code = t.classify(err)
+	}
+
+	t.mutex.Lock()
+	counter, ok := t.counters[operationType]
+	if !ok {
+		counter = &operationTypeCounter{
+			errorCodes: make(map[string]int64),
+			latency:    NewLatencyTracker(t.config),
+		}
+		t.counters[operationType] = counter
+	}
+	counter.count++
+	if err != nil {
+		counter.errors++
+		counter.errorCodes[code]++
+	} else {
+		counter.volume += volume
+		if flagged {
+			counter.flagged++
+		}
+	}
+	t.mutex.Unlock()
+
+	if err == nil {
+		counter.latency.Record(latency)
+	}
+}
+
+// Stats 获取按操作类型排序的统计结果
+func (t *OperationTypeTracker) Stats() []OperationTypeStats {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	stats := make([]OperationTypeStats, 0, len(t.counters))
+	for operationType, counter := range t.counters {
+		entry := OperationTypeStats{
+			Type:       operationType,
+			Count:      counter.count,
+			Errors:     counter.errors,
+			Volume:     counter.volume,
+			Flagged:    counter.flagged,
+			Latency:    counter.latency.GetMetrics(),
+			volumeKey:  t.volumeKey,
+			flaggedKey: t.flaggedKey,
+		}
+		if counter.count > 0 {
+			entry.ErrorRate = float64(counter.errors) / float64(counter.count) * 100
+		}
+		if len(counter.errorCodes) > 0 {
+			entry.ErrorCodes = make(map[string]int64, len(counter.errorCodes))
+			for code, n := range counter.errorCodes {
+				entry.ErrorCodes[code] = n
+			}
+		}
+		stats = append(stats, entry)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Type < stats[j].Type
+	})
+	return stats
+}
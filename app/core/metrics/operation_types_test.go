@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOperationTypeTracker(t *testing.T) {
+	errTimeout := errors.New("timeout")
+	tracker := NewOperationTypeTracker(func(err error) string {
+		if errors.Is(err, errTimeout) {
+			return "timeout"
+		}
+		return "client"
+	}, "bytes", "redelivered")
+
+	tracker.Record("read", 3, false, time.Millisecond, nil)
+	tracker.Record("read", 2, true, 3*time.Millisecond, nil)
+	tracker.Record("read", 9, true, time.Second, errTimeout)
+	tracker.Record("write", 1, false, time.Millisecond, errors.New("boom"))
+
+	stats := tracker.Stats()
+	if len(stats) != 2 || stats[0].Type != "read" || stats[1].Type != "write" {
+		t.Fatalf("unexpected stats order: %+v", stats)
+	}
+
+	read := stats[0]
+	if read.Count != 3 || read.Errors != 1 || read.Volume != 5 || read.Flagged != 1 {
+		t.Errorf("unexpected read counters: %+v", read)
+	}
+	if read.ErrorCodes["timeout"] != 1 {
+		t.Errorf("expected one timeout, got %v", read.ErrorCodes)
+	}
+	// 失败执行不计入延迟
+	if read.Latency.Max != 3*time.Millisecond {
+		t.Errorf("expected max latency of successful reads, got %v", read.Latency.Max)
+	}
+
+	write := stats[1]
+	if write.ErrorRate != 100 || write.ErrorCodes["client"] != 1 || write.Latency.Max != 0 {
+		t.Errorf("unexpected write stats: %+v", write)
+	}
+
+	// JSON使用调用方指定的字段名
+	var encoded map[string]interface{}
+	data, err := json.Marshal(read)
+	if err != nil {
+		t.Fatalf("failed to encode stats: %v", err)
+	}
+	json.Unmarshal(data, &encoded)
+	if encoded["bytes"] != float64(5) || encoded["redelivered"] != float64(1) || encoded["volume"] != nil {
+		t.Errorf("unexpected JSON field names: %s", data)
+	}
+}
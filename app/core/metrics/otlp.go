@@ -0,0 +1,460 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+)
+
+// OTLP/HTTP 标准路径
+const (
+	otlpMetricsPath = "/v1/metrics"
+	otlpTracesPath  = "/v1/traces"
+)
+
+// OTLPExporter 以OTLP/HTTP(JSON编码)周期性推送指标快照，并可选导出每个操作的span
+// 作为ResultObserver接收操作结果，span在内存队列中缓冲并随每次推送批量发送
+type OTLPExporter struct {
+	config    OTLPConfig
+	collector DefaultMetricsCollector
+	client    *http.Client
+	startTime time.Time
+
+	spans        []otlpSpan
+	droppedSpans uint64
+	mutex        sync.Mutex
+
+	// 只导出携带trace-context的结果，跳过适配器自行记录的结果，避免同一操作产生两个span
+	traceContextOnly bool
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewOTLPExporter 创建OTLP导出器
+func NewOTLPExporter(config OTLPConfig, collector DefaultMetricsCollector) *OTLPExporter {
+	defaults := DefaultOTLPConfig()
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+	if config.ServiceName == "" {
+		config.ServiceName = defaults.ServiceName
+	}
+	if config.MaxQueueSize <= 0 {
+		config.MaxQueueSize = defaults.MaxQueueSize
+	}
+	config.Endpoint = strings.TrimRight(config.Endpoint, "/")
+
+	return &OTLPExporter{
+		config:    config,
+		collector: collector,
+		client:    &http.Client{Timeout: config.Timeout},
+		startTime: time.Now(),
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// Observe 记录单个操作结果，启用traces时转换为span
+// 结果携带注入目标的trace-context时沿用其trace ID与span ID，使客户端span与目标端的span
+// 同属一个trace；未采样的操作不导出span
+func (o *OTLPExporter) Observe(result *interfaces.OperationResult) {
+	if result == nil || !o.config.Traces {
+		return
+	}
+
+	traceID, spanID := randomHex(16), randomHex(8)
+	var requestID string
+	if _, ok := result.Metadata["trace_id"]; !ok && o.traceContextOnly {
+		return
+	}
+	if result.Metadata != nil {
+		if sampled, ok := result.Metadata["trace_sampled"].(bool); ok && !sampled {
+			return
+		}
+		if id, ok := result.Metadata["trace_id"].(string); ok {
+			traceID = id
+		}
+		if id, ok := result.Metadata["span_id"].(string); ok {
+			spanID = id
+		}
+		requestID, _ = result.Metadata["request_id"].(string)
+	}
+
+	end := time.Now()
+	span := otlpSpan{
+		TraceID:           traceID,
+		SpanID:            spanID,
+		Name:              operationLabel(result),
+		Kind:              otlpSpanKindClient,
+		StartTimeUnixNano: strconv.FormatInt(end.Add(-result.Duration).UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(end.UnixNano(), 10),
+		Attributes: []otlpKeyValue{
+			boolAttribute("abc_runner.read", result.IsRead),
+		},
+		Status: otlpStatus{Code: otlpStatusOK},
+	}
+	if requestID != "" {
+		span.Attributes = append(span.Attributes, stringAttribute("abc_runner.request_id", requestID))
+	}
+	if !result.Success {
+		span.Status.Code = otlpStatusError
+		if result.Error != nil {
+			span.Status.Message = result.Error.Error()
+		}
+	}
+
+	o.mutex.Lock()
+	if len(o.spans) >= o.config.MaxQueueSize {
+		o.droppedSpans++
+	} else {
+		o.spans = append(o.spans, span)
+	}
+	o.mutex.Unlock()
+}
+
+// SetTraceContextOnly 设置只为携带trace-context的结果导出span，应在Start之前调用
+func (o *OTLPExporter) SetTraceContextOnly(enabled bool) {
+	o.traceContextOnly = enabled
+}
+
+// Start 启动周期性推送
+func (o *OTLPExporter) Start() {
+	o.wg.Add(1)
+	go func() {
+		defer o.wg.Done()
+		ticker := time.NewTicker(o.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := o.Push(context.Background()); err != nil {
+					fmt.Printf("⚠️  OTLP export failed: %v\n", err)
+				}
+			case <-o.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Stop 停止周期性推送，并推送最终快照与剩余span
+func (o *OTLPExporter) Stop() error {
+	var err error
+	o.stopOnce.Do(func() {
+		close(o.stopCh)
+		o.wg.Wait()
+		err = o.Push(context.Background())
+	})
+	return err
+}
+
+// DroppedSpans 获取因队列已满而丢弃的span数量
+func (o *OTLPExporter) DroppedSpans() uint64 {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	return o.droppedSpans
+}
+
+// Push 立即推送当前快照和已缓冲的span
+func (o *OTLPExporter) Push(ctx context.Context) error {
+	var snapshot *DefaultMetricsSnapshot
+	if o.collector != nil {
+		snapshot = o.collector.Snapshot()
+	}
+
+	resource := o.resource(snapshot)
+	if snapshot != nil {
+		request := otlpMetricsRequest{ResourceMetrics: []otlpResourceMetrics{{
+			Resource:     resource,
+			ScopeMetrics: []otlpScopeMetrics{{Scope: otlpScope(), Metrics: o.buildMetrics(snapshot)}},
+		}}}
+		if err := o.post(ctx, otlpMetricsPath, request); err != nil {
+			return fmt.Errorf("failed to export metrics: %w", err)
+		}
+	}
+
+	o.mutex.Lock()
+	spans := o.spans
+	o.spans = nil
+	o.mutex.Unlock()
+
+	if len(spans) > 0 {
+		request := otlpTracesRequest{ResourceSpans: []otlpResourceSpans{{
+			Resource:   resource,
+			ScopeSpans: []otlpScopeSpans{{Scope: otlpScope(), Spans: spans}},
+		}}}
+		if err := o.post(ctx, otlpTracesPath, request); err != nil {
+			return fmt.Errorf("failed to export spans: %w", err)
+		}
+	}
+	return nil
+}
+
+// buildMetrics 将快照转换为OTLP指标
+func (o *OTLPExporter) buildMetrics(snapshot *DefaultMetricsSnapshot) []otlpMetric {
+	core := snapshot.Core
+	now := strconv.FormatInt(snapshot.Timestamp.UnixNano(), 10)
+	if snapshot.Timestamp.IsZero() {
+		now = strconv.FormatInt(time.Now().UnixNano(), 10)
+	}
+	start := strconv.FormatInt(o.startTime.UnixNano(), 10)
+
+	errorRate := 0.0
+	if core.Operations.Total > 0 {
+		errorRate = float64(core.Operations.Failed) / float64(core.Operations.Total) * 100
+	}
+
+	gauge := func(name, unit, description string, value float64) otlpMetric {
+		return otlpMetric{
+			Name:        name,
+			Unit:        unit,
+			Description: description,
+			Gauge: &otlpGauge{DataPoints: []otlpNumberDataPoint{{
+				TimeUnixNano: now,
+				AsDouble:     &value,
+			}}},
+		}
+	}
+
+	return []otlpMetric{
+		{
+			Name:        "abc_runner.operations",
+			Unit:        "{operation}",
+			Description: "Completed operations by result.",
+			Sum: &otlpSum{
+				AggregationTemporality: otlpTemporalityCumulative,
+				IsMonotonic:            true,
+				DataPoints: []otlpNumberDataPoint{
+					{
+						Attributes:        []otlpKeyValue{stringAttribute("result", "success")},
+						StartTimeUnixNano: start,
+						TimeUnixNano:      now,
+						AsInt:             strconv.FormatInt(core.Operations.Success, 10),
+					},
+					{
+						Attributes:        []otlpKeyValue{stringAttribute("result", "failure")},
+						StartTimeUnixNano: start,
+						TimeUnixNano:      now,
+						AsInt:             strconv.FormatInt(core.Operations.Failed, 10),
+					},
+				},
+			},
+		},
+		{
+			Name:        "abc_runner.operation.duration",
+			Unit:        "s",
+			Description: "Operation latency percentiles computed by the collector.",
+			Summary: &otlpSummary{DataPoints: []otlpSummaryDataPoint{{
+				StartTimeUnixNano: start,
+				TimeUnixNano:      now,
+				Count:             strconv.FormatInt(core.Operations.Total, 10),
+				Sum:               core.Latency.Average.Seconds() * float64(core.Operations.Total),
+				QuantileValues: []otlpQuantileValue{
+					{Quantile: 0, Value: core.Latency.Min.Seconds()},
+					{Quantile: 0.5, Value: core.Latency.P50.Seconds()},
+					{Quantile: 0.9, Value: core.Latency.P90.Seconds()},
+					{Quantile: 0.95, Value: core.Latency.P95.Seconds()},
+					{Quantile: 0.99, Value: core.Latency.P99.Seconds()},
+					{Quantile: 0.999, Value: core.Latency.P999.Seconds()},
+					{Quantile: 1, Value: core.Latency.Max.Seconds()},
+				},
+			}}},
+		},
+		gauge("abc_runner.error_rate", "%", "Percentage of failed operations.", errorRate),
+		gauge("abc_runner.throughput", "{operation}/s", "Current overall throughput.", core.Throughput.RPS),
+		gauge("abc_runner.elapsed", "s", "Elapsed benchmark time.", core.Duration.Seconds()),
+	}
+}
+
+// resource 构造资源属性
+func (o *OTLPExporter) resource(snapshot *DefaultMetricsSnapshot) otlpResource {
+	attributes := []otlpKeyValue{stringAttribute("service.name", o.config.ServiceName)}
+	if snapshot != nil {
+		if protocol, ok := snapshot.Protocol["protocol"].(string); ok && protocol != "" {
+			attributes = append(attributes, stringAttribute("abc_runner.protocol", protocol))
+		}
+	}
+	return otlpResource{Attributes: attributes}
+}
+
+// post 以JSON编码发送OTLP请求
+func (o *OTLPExporter) post(ctx context.Context, path string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.config.Endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range o.config.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("collector responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// randomHex 生成指定字节数的随机十六进制ID
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// OTLP JSON 编码结构（仅包含导出所需的字段）
+
+const (
+	otlpSpanKindClient        = 3
+	otlpStatusOK              = 1
+	otlpStatusError           = 2
+	otlpTemporalityCumulative = 2
+)
+
+type otlpMetricsRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpScopeMetrics struct {
+	Scope   otlpInstrumentationScope `json:"scope"`
+	Metrics []otlpMetric             `json:"metrics"`
+}
+
+type otlpMetric struct {
+	Name        string       `json:"name"`
+	Unit        string       `json:"unit,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Gauge       *otlpGauge   `json:"gauge,omitempty"`
+	Sum         *otlpSum     `json:"sum,omitempty"`
+	Summary     *otlpSummary `json:"summary,omitempty"`
+}
+
+type otlpGauge struct {
+	DataPoints []otlpNumberDataPoint `json:"dataPoints"`
+}
+
+type otlpSum struct {
+	DataPoints             []otlpNumberDataPoint `json:"dataPoints"`
+	AggregationTemporality int                   `json:"aggregationTemporality"`
+	IsMonotonic            bool                  `json:"isMonotonic"`
+}
+
+type otlpSummary struct {
+	DataPoints []otlpSummaryDataPoint `json:"dataPoints"`
+}
+
+type otlpNumberDataPoint struct {
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano,omitempty"`
+	TimeUnixNano      string         `json:"timeUnixNano"`
+	AsDouble          *float64       `json:"asDouble,omitempty"`
+	AsInt             string         `json:"asInt,omitempty"`
+}
+
+type otlpSummaryDataPoint struct {
+	StartTimeUnixNano string              `json:"startTimeUnixNano"`
+	TimeUnixNano      string              `json:"timeUnixNano"`
+	Count             string              `json:"count"`
+	Sum               float64             `json:"sum"`
+	QuantileValues    []otlpQuantileValue `json:"quantileValues"`
+}
+
+type otlpQuantileValue struct {
+	Quantile float64 `json:"quantile"`
+	Value    float64 `json:"value"`
+}
+
+type otlpTracesRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpInstrumentationScope `json:"scope"`
+	Spans []otlpSpan               `json:"spans"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpInstrumentationScope struct {
+	Name string `json:"name"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	BoolValue   *bool   `json:"boolValue,omitempty"`
+}
+
+// otlpScope 导出器的instrumentation scope
+func otlpScope() otlpInstrumentationScope {
+	return otlpInstrumentationScope{Name: "abc-runner"}
+}
+
+// stringAttribute 构造字符串属性
+func stringAttribute(key, value string) otlpKeyValue {
+	return otlpKeyValue{Key: key, Value: otlpAnyValue{StringValue: &value}}
+}
+
+// boolAttribute 构造布尔属性
+func boolAttribute(key string, value bool) otlpKeyValue {
+	return otlpKeyValue{Key: key, Value: otlpAnyValue{BoolValue: &value}}
+}
@@ -0,0 +1,121 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+)
+
+func TestOTLPExporter_Push(t *testing.T) {
+	var mutex sync.Mutex
+	bodies := map[string][]byte{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mutex.Lock()
+		bodies[r.URL.Path] = body
+		mutex.Unlock()
+		if r.Header.Get("X-Token") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	collector := NewBaseCollector(DefaultMetricsConfig(), map[string]interface{}{"protocol": "http"})
+	defer collector.Stop()
+
+	config := DefaultOTLPConfig()
+	config.Enabled = true
+	config.Endpoint = server.URL
+	config.Traces = true
+	config.Headers = map[string]string{"X-Token": "secret"}
+	exporter := NewOTLPExporter(config, collector)
+	collector.AddObserver(exporter)
+
+	traceID, spanID := "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"
+	collector.Record(&interfaces.OperationResult{Success: true, Duration: 3 * time.Millisecond, Metadata: map[string]interface{}{
+		"operation_type": "get", "trace_id": traceID, "span_id": spanID, "trace_sampled": true,
+	}})
+	collector.Record(&interfaces.OperationResult{Success: false, Duration: time.Millisecond, Error: errors.New("boom")})
+	// 未采样的trace不导出span
+	exporter.Observe(&interfaces.OperationResult{Success: true, Duration: time.Millisecond, Metadata: map[string]interface{}{
+		"trace_id": traceID, "span_id": "b7ad6b7169203331", "trace_sampled": false,
+	}})
+	// 只导出携带trace-context的结果时跳过其他结果
+	exporter.SetTraceContextOnly(true)
+	exporter.Observe(&interfaces.OperationResult{Success: true, Duration: time.Millisecond})
+	exporter.SetTraceContextOnly(false)
+
+	if err := exporter.Push(context.Background()); err != nil {
+		t.Fatalf("push failed: %v", err)
+	}
+
+	var metricsRequest otlpMetricsRequest
+	if err := json.Unmarshal(bodies[otlpMetricsPath], &metricsRequest); err != nil {
+		t.Fatalf("invalid metrics payload: %v", err)
+	}
+	resource := metricsRequest.ResourceMetrics[0]
+	if got := *resource.Resource.Attributes[1].Value.StringValue; got != "http" {
+		t.Errorf("expected protocol resource attribute http, got %s", got)
+	}
+	names := map[string]otlpMetric{}
+	for _, metric := range resource.ScopeMetrics[0].Metrics {
+		names[metric.Name] = metric
+	}
+	operations, ok := names["abc_runner.operations"]
+	if !ok || operations.Sum.DataPoints[0].AsInt != "1" || operations.Sum.DataPoints[1].AsInt != "1" {
+		t.Errorf("unexpected operations metric: %+v", operations)
+	}
+	if errorRate := names["abc_runner.error_rate"]; errorRate.Gauge == nil || *errorRate.Gauge.DataPoints[0].AsDouble != 50 {
+		t.Errorf("expected 50%% error rate, got %+v", errorRate.Gauge)
+	}
+	if summary := names["abc_runner.operation.duration"]; summary.Summary == nil || len(summary.Summary.DataPoints[0].QuantileValues) != 7 {
+		t.Errorf("expected latency summary with quantiles, got %+v", summary.Summary)
+	}
+
+	var tracesRequest otlpTracesRequest
+	if err := json.Unmarshal(bodies[otlpTracesPath], &tracesRequest); err != nil {
+		t.Fatalf("invalid traces payload: %v", err)
+	}
+	spans := tracesRequest.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 || spans[0].Name != "get" || spans[1].Status.Code != otlpStatusError || spans[1].Status.Message != "boom" {
+		t.Errorf("unexpected spans: %+v", spans)
+	}
+	if spans[0].TraceID != traceID || spans[0].SpanID != spanID {
+		t.Errorf("expected the propagated trace context to be reused, got %q %q", spans[0].TraceID, spans[0].SpanID)
+	}
+	if len(spans[1].TraceID) != 32 || len(spans[1].SpanID) != 16 {
+		t.Errorf("unexpected span id lengths: %q %q", spans[1].TraceID, spans[1].SpanID)
+	}
+}
+
+func TestConfigManager_LoadOTLPConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.yaml")
+	content := "export:\n  otlp:\n    enabled: true\n    endpoint: \"http://collector:4318\"\n    interval: \"2s\"\n    traces: true\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := NewConfigManager(path)
+	if err := manager.LoadConfig(); err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	otlp := manager.GetConfig().Export.OTLP
+	if !otlp.Enabled || otlp.Endpoint != "http://collector:4318" || otlp.Interval != 2*time.Second || !otlp.Traces {
+		t.Errorf("unexpected OTLP config: %+v", otlp)
+	}
+	if otlp.ServiceName != "abc-runner" || otlp.MaxQueueSize != 10000 {
+		t.Errorf("expected defaults to be applied, got %+v", otlp)
+	}
+}
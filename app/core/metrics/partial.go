@@ -0,0 +1,300 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+)
+
+// DefaultPartialReportInterval 默认的部分报告写入间隔
+const DefaultPartialReportInterval = 10 * time.Second
+
+// MaxPartialIntervals 部分报告中保留的最近间隔数，更早的间隔只计入累计汇总，避免长时间运行时每次写入的文件无限增长
+const MaxPartialIntervals = 720
+
+// 部分报告状态
+const (
+	PartialStatusRunning   = "running"
+	PartialStatusCompleted = "completed"
+)
+
+// IntervalSummary 单个写入间隔（或整个运行）的指标汇总
+type IntervalSummary struct {
+	Start      time.Time     `json:"start"`
+	End        time.Time     `json:"end"`
+	Operations int64         `json:"operations"`
+	Success    int64         `json:"success"`
+	Failed     int64         `json:"failed"`
+	Read       int64         `json:"read"`
+	Write      int64         `json:"write"`
+	RPS        float64       `json:"rps"`
+	ErrorRate  float64       `json:"error_rate"` // 百分比
+	Average    time.Duration `json:"avg_latency"`
+	P50        time.Duration `json:"p50_latency"`
+	P90        time.Duration `json:"p90_latency"`
+	P99        time.Duration `json:"p99_latency"`
+	Max        time.Duration `json:"max_latency"`
+}
+
+// PartialReport 运行期间周期性写入的部分报告
+// 进程崩溃或被OOM终止时，文件中保留最近一次写入为止的全部间隔与累计汇总
+type PartialReport struct {
+	Status    string            `json:"status"`
+	StartedAt time.Time         `json:"started_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	Interval  string            `json:"interval"`
+	Total     IntervalSummary   `json:"total"`
+	Intervals []IntervalSummary `json:"intervals"`
+	// DroppedIntervals 超出保留上限而被丢弃的最早间隔数
+	DroppedIntervals int `json:"dropped_intervals,omitempty"`
+}
+
+// intervalAccumulator 间隔内的累加器
+// 计数与最大值均为原子操作，直方图本身无锁，并发记录互不阻塞
+type intervalAccumulator struct {
+	start     time.Time
+	success   int64
+	failed    int64
+	read      int64
+	write     int64
+	max       int64
+	histogram *HdrHistogram
+}
+
+func newIntervalAccumulator(start time.Time) *intervalAccumulator {
+	return &intervalAccumulator{start: start, histogram: NewHdrHistogram(DefaultHdrHighestValue, DefaultHdrSignificantDigits)}
+}
+
+// add 记录单个操作结果
+func (a *intervalAccumulator) add(result *interfaces.OperationResult) {
+	if result.Success {
+		atomic.AddInt64(&a.success, 1)
+	} else {
+		atomic.AddInt64(&a.failed, 1)
+	}
+	if result.IsRead {
+		atomic.AddInt64(&a.read, 1)
+	} else {
+		atomic.AddInt64(&a.write, 1)
+	}
+	nanos := int64(result.Duration)
+	for {
+		current := atomic.LoadInt64(&a.max)
+		if nanos <= current || atomic.CompareAndSwapInt64(&a.max, current, nanos) {
+			break
+		}
+	}
+	a.histogram.Record(nanos)
+}
+
+// merge 将已结束间隔的累加结果并入当前累加器
+func (a *intervalAccumulator) merge(other *intervalAccumulator) {
+	a.success += other.success
+	a.failed += other.failed
+	a.read += other.read
+	a.write += other.write
+	a.max = max(a.max, other.max)
+	a.histogram.Merge(other.histogram)
+}
+
+// summary 生成截至end的汇总
+func (a *intervalAccumulator) summary(end time.Time) IntervalSummary {
+	summary := IntervalSummary{
+		Start:      a.start,
+		End:        end,
+		Operations: a.success + a.failed,
+		Success:    a.success,
+		Failed:     a.failed,
+		Read:       a.read,
+		Write:      a.write,
+		Max:        time.Duration(a.max),
+	}
+	if summary.Operations == 0 {
+		return summary
+	}
+	if seconds := end.Sub(a.start).Seconds(); seconds > 0 {
+		summary.RPS = float64(summary.Operations) / seconds
+	}
+	summary.ErrorRate = float64(summary.Failed) / float64(summary.Operations) * 100
+	summary.Average = time.Duration(a.histogram.Mean())
+	quantiles := a.histogram.ValuesAtQuantiles(50, 90, 99)
+	summary.P50, summary.P90, summary.P99 = time.Duration(quantiles[0]), time.Duration(quantiles[1]), time.Duration(quantiles[2])
+	return summary
+}
+
+// PartialReporter 部分报告写入器
+// 作为结果观察者按间隔汇总操作结果，每个间隔结束时将最近的间隔与累计汇总整体写入JSON文件；
+// 先写临时文件再重命名，任何时刻文件都是完整可解析的。
+// 记录时只持有读锁并原子更新当前间隔，间隔结束时才在写锁下切换累加器并计入累计汇总
+type PartialReporter struct {
+	path         string
+	interval     time.Duration
+	maxIntervals int
+
+	mutex     sync.RWMutex
+	startedAt time.Time
+	current   *intervalAccumulator
+	total     *intervalAccumulator
+	intervals []IntervalSummary
+	dropped   int
+	closed    bool
+	final     *PartialReport // Close后的最终部分报告
+	err       error          // 最近一次写入的错误
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewPartialReporter 创建部分报告写入器并立即写入初始文件，interval<=0时使用默认间隔
+func NewPartialReporter(path string, interval time.Duration) (*PartialReporter, error) {
+	if interval <= 0 {
+		interval = DefaultPartialReportInterval
+	}
+	now := time.Now()
+	r := &PartialReporter{
+		path:         path,
+		interval:     interval,
+		maxIntervals: MaxPartialIntervals,
+		startedAt:    now,
+		current:      newIntervalAccumulator(now),
+		total:        newIntervalAccumulator(now),
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create partial report directory: %w", err)
+	}
+	if err := r.write(r.report(now, PartialStatusRunning)); err != nil {
+		return nil, err
+	}
+	go r.run()
+	return r, nil
+}
+
+// Path 部分报告文件路径
+func (r *PartialReporter) Path() string {
+	return r.path
+}
+
+// Interval 写入间隔
+func (r *PartialReporter) Interval() time.Duration {
+	return r.interval
+}
+
+// Observe 记录单个操作结果
+func (r *PartialReporter) Observe(result *interfaces.OperationResult) {
+	if result == nil {
+		return
+	}
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if r.closed {
+		return
+	}
+	r.current.add(result)
+}
+
+// run 按间隔结束当前间隔并写入文件
+func (r *PartialReporter) run() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			r.flush(now, PartialStatusRunning)
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// flush 结束当前间隔并写入文件，写入在锁外进行，不阻塞结果记录
+func (r *PartialReporter) flush(now time.Time, status string) {
+	r.mutex.Lock()
+	r.intervals = append(r.intervals, r.current.summary(now))
+	if excess := len(r.intervals) - r.maxIntervals; excess > 0 {
+		r.intervals = append(r.intervals[:0], r.intervals[excess:]...)
+		r.dropped += excess
+	}
+	r.total.merge(r.current)
+	r.current = newIntervalAccumulator(now)
+	report := r.report(now, status)
+	r.mutex.Unlock()
+
+	err := r.write(report)
+	r.mutex.Lock()
+	r.err = err
+	r.mutex.Unlock()
+}
+
+// report 生成部分报告，调用方需持有锁或独占访问
+func (r *PartialReporter) report(now time.Time, status string) *PartialReport {
+	return &PartialReport{
+		Status:           status,
+		StartedAt:        r.startedAt,
+		UpdatedAt:        now,
+		Interval:         r.interval.String(),
+		Total:            r.total.summary(now),
+		Intervals:        append([]IntervalSummary{}, r.intervals...),
+		DroppedIntervals: r.dropped,
+	}
+}
+
+// write 原子地写入部分报告文件
+func (r *PartialReporter) write(report *PartialReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode partial report: %w", err)
+	}
+	tmp := r.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write partial report: %w", err)
+	}
+	if err := os.Rename(tmp, r.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write partial report: %w", err)
+	}
+	return nil
+}
+
+// Close 结束最后一个间隔，以completed状态写入最终的部分报告并返回
+func (r *PartialReporter) Close() (*PartialReport, error) {
+	r.mutex.Lock()
+	if r.closed {
+		defer r.mutex.Unlock()
+		return r.final, r.err
+	}
+	r.closed = true
+	r.mutex.Unlock()
+
+	close(r.stop)
+	<-r.done
+	r.flush(time.Now(), PartialStatusCompleted)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.final = r.report(r.current.start, PartialStatusCompleted)
+	return r.final, r.err
+}
+
+// LoadPartialReport 读取部分报告文件
+func LoadPartialReport(path string) (*PartialReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read partial report: %w", err)
+	}
+	var report PartialReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse partial report %s: %w", path, err)
+	}
+	return &report, nil
+}
@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+)
+
+func TestPartialReporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reports", "partial.json")
+	reporter, err := NewPartialReporter(path, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("failed to create partial reporter: %v", err)
+	}
+
+	// 创建后立即写入初始文件
+	initial, err := LoadPartialReport(path)
+	if err != nil || initial.Status != PartialStatusRunning || len(initial.Intervals) != 0 {
+		t.Fatalf("unexpected initial partial report: %+v, %v", initial, err)
+	}
+
+	record := func(n int, success bool, latency time.Duration) {
+		for i := 0; i < n; i++ {
+			reporter.Observe(&interfaces.OperationResult{Success: success, IsRead: i%2 == 0, Duration: latency})
+		}
+	}
+	record(100, true, time.Millisecond)
+	time.Sleep(120 * time.Millisecond)
+
+	// 运行中的文件包含已结束的间隔与累计汇总，模拟崩溃时可读取的内容
+	running, err := LoadPartialReport(path)
+	if err != nil {
+		t.Fatalf("failed to load running partial report: %v", err)
+	}
+	if running.Status != PartialStatusRunning || len(running.Intervals) == 0 || running.Total.Operations != 100 {
+		t.Fatalf("unexpected running partial report: status %s, %d intervals, total %+v", running.Status, len(running.Intervals), running.Total)
+	}
+
+	record(50, false, 10*time.Millisecond)
+	final, err := reporter.Close()
+	if err != nil {
+		t.Fatalf("failed to close partial reporter: %v", err)
+	}
+	record(10, true, time.Millisecond)
+
+	total := final.Total
+	if total.Operations != 150 || total.Success != 100 || total.Failed != 50 || total.Read != 75 {
+		t.Errorf("unexpected total: %+v", total)
+	}
+	if total.P50 < 900*time.Microsecond || total.P50 > 1100*time.Microsecond || total.P99 < 9*time.Millisecond || total.Max != 10*time.Millisecond {
+		t.Errorf("unexpected total latency: p50 %v, p99 %v, max %v", total.P50, total.P99, total.Max)
+	}
+
+	sum := int64(0)
+	var last IntervalSummary
+	for i, interval := range final.Intervals {
+		sum += interval.Operations
+		if i > 0 && !interval.Start.Equal(final.Intervals[i-1].End) {
+			t.Errorf("interval %d does not start where the previous one ended", i)
+		}
+		// 定时刷新可能恰好发生在Close之前，此时最后一个间隔为空
+		if interval.Operations > 0 {
+			last = interval
+		}
+	}
+	if sum != 150 {
+		t.Errorf("intervals add up to %d operations, want 150", sum)
+	}
+	if last.Failed != 50 || last.ErrorRate != 100 {
+		t.Errorf("unexpected last non-empty interval: %+v", last)
+	}
+
+	written, err := LoadPartialReport(path)
+	if err != nil || written.Status != PartialStatusCompleted || len(written.Intervals) != len(final.Intervals) {
+		t.Errorf("final partial report not written: %+v, %v", written, err)
+	}
+	if again, _ := reporter.Close(); again != final {
+		t.Errorf("expected Close to be idempotent")
+	}
+}
+
+func TestPartialReporterRetention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "partial.json")
+	reporter, err := NewPartialReporter(path, time.Hour)
+	if err != nil {
+		t.Fatalf("failed to create partial reporter: %v", err)
+	}
+	reporter.maxIntervals = 3
+
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		reporter.Observe(&interfaces.OperationResult{Success: true, Duration: time.Duration(i+1) * time.Millisecond})
+		reporter.flush(now.Add(time.Duration(i+1)*time.Second), PartialStatusRunning)
+	}
+
+	// 只保留最近的间隔，累计汇总仍覆盖整个运行
+	written, err := LoadPartialReport(path)
+	if err != nil {
+		t.Fatalf("failed to load partial report: %v", err)
+	}
+	if len(written.Intervals) != 3 || written.DroppedIntervals != 2 || written.Intervals[0].Max != 3*time.Millisecond {
+		t.Errorf("unexpected retained intervals: %d kept, %d dropped, first %+v", len(written.Intervals), written.DroppedIntervals, written.Intervals[0])
+	}
+	if written.Total.Operations != 5 || written.Total.Max != 5*time.Millisecond {
+		t.Errorf("unexpected total: %+v", written.Total)
+	}
+	if _, err := reporter.Close(); err != nil {
+		t.Fatalf("failed to close partial reporter: %v", err)
+	}
+}
@@ -0,0 +1,182 @@
+package metrics
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+// LatencyPhase 一次操作耗时中的阶段
+type LatencyPhase string
+
+const (
+	PhaseDNS       LatencyPhase = "dns"        // 域名解析
+	PhaseConnect   LatencyPhase = "connect"    // 建立TCP连接
+	PhaseTLS       LatencyPhase = "tls"        // TLS握手
+	PhaseRequest   LatencyPhase = "request"    // 取得连接后写出请求
+	PhaseFirstByte LatencyPhase = "first_byte" // 请求写出后等待响应首字节（服务端处理时间）
+	PhaseTransfer  LatencyPhase = "transfer"   // 从首字节到读完响应
+)
+
+// latencyPhases 各阶段的先后顺序
+var latencyPhases = []LatencyPhase{PhaseDNS, PhaseConnect, PhaseTLS, PhaseRequest, PhaseFirstByte, PhaseTransfer}
+
+// PhaseTimings 一次操作各阶段的耗时，未经历的阶段（如复用连接时的建连）为0
+type PhaseTimings struct {
+	DNS       time.Duration
+	Connect   time.Duration
+	TLS       time.Duration
+	Request   time.Duration
+	FirstByte time.Duration
+	Transfer  time.Duration
+}
+
+// get 阶段的耗时
+func (p PhaseTimings) get(phase LatencyPhase) time.Duration {
+	switch phase {
+	case PhaseDNS:
+		return p.DNS
+	case PhaseConnect:
+		return p.Connect
+	case PhaseTLS:
+		return p.TLS
+	case PhaseRequest:
+		return p.Request
+	case PhaseFirstByte:
+		return p.FirstByte
+	case PhaseTransfer:
+		return p.Transfer
+	}
+	return 0
+}
+
+// PhaseLatency 单个阶段的延迟统计
+type PhaseLatency struct {
+	Phase   LatencyPhase   `json:"phase"`
+	Count   int64          `json:"count"` // 经历该阶段的次数，建连阶段即新建的连接数
+	Total   time.Duration  `json:"total"` // 该阶段的累计耗时
+	Share   float64        `json:"share"` // 累计耗时占全部阶段累计耗时的比例（0-1）
+	Latency LatencyMetrics `json:"latency"`
+}
+
+// phaseStat 单个阶段的累计
+type phaseStat struct {
+	count   atomic.Int64
+	total   atomic.Int64
+	latency *LatencyTracker
+}
+
+// PhaseTracker 按阶段统计延迟，用于说明时间花在建连、TLS、服务端处理还是传输上
+type PhaseTracker struct {
+	phases map[LatencyPhase]*phaseStat
+}
+
+// NewPhaseTracker 创建阶段延迟统计
+func NewPhaseTracker() *PhaseTracker {
+	t := &PhaseTracker{phases: make(map[LatencyPhase]*phaseStat, len(latencyPhases))}
+	for _, phase := range latencyPhases {
+		t.phases[phase] = &phaseStat{latency: NewLatencyTracker(LatencyConfig{
+			SignificantDigits: DefaultHdrSignificantDigits,
+			SamplingRate:      1.0,
+		})}
+	}
+	return t
+}
+
+// Record 记录一次操作的各阶段耗时，跳过为0的阶段
+func (t *PhaseTracker) Record(timings PhaseTimings) {
+	for _, phase := range latencyPhases {
+		t.RecordPhase(phase, timings.get(phase))
+	}
+}
+
+// RecordPhase 记录单个阶段的耗时，d<=0时忽略
+func (t *PhaseTracker) RecordPhase(phase LatencyPhase, d time.Duration) {
+	stat, ok := t.phases[phase]
+	if !ok || d <= 0 {
+		return
+	}
+	stat.count.Add(1)
+	stat.total.Add(int64(d))
+	stat.latency.Record(d)
+}
+
+// Breakdown 按阶段顺序返回有记录的阶段的统计，没有任何记录时为nil
+func (t *PhaseTracker) Breakdown() []PhaseLatency {
+	var breakdown []PhaseLatency
+	var total time.Duration
+	for _, phase := range latencyPhases {
+		stat := t.phases[phase]
+		count := stat.count.Load()
+		if count == 0 {
+			continue
+		}
+		phaseTotal := time.Duration(stat.total.Load())
+		total += phaseTotal
+		breakdown = append(breakdown, PhaseLatency{
+			Phase:   phase,
+			Count:   count,
+			Total:   phaseTotal,
+			Latency: stat.latency.GetMetrics(),
+		})
+	}
+	if total > 0 {
+		for i := range breakdown {
+			breakdown[i].Share = float64(breakdown[i].Total) / float64(total)
+		}
+	}
+	return breakdown
+}
+
+// DialFunc 建立网络连接的函数，与net.Dialer.DialContext的签名一致
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// WrapDial 返回记录建连耗时的拨号函数，dial为nil时使用net.Dialer
+// 成功建立的连接计入connect阶段（dial自行完成TLS握手时包含握手耗时）
+func (t *PhaseTracker) WrapDial(dial DialFunc) DialFunc {
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		start := time.Now()
+		conn, err := dial(ctx, network, addr)
+		if err == nil {
+			t.RecordPhase(PhaseConnect, time.Since(start))
+		}
+		return conn, err
+	}
+}
+
+// DialTLS 建立连接，tlsConfig非nil时随后完成TLS握手，建连与握手分别计入connect与tls阶段
+func (t *PhaseTracker) DialTLS(ctx context.Context, dialer *net.Dialer, network, addr string, tlsConfig *tls.Config) (net.Conn, error) {
+	conn, err := t.WrapDial(dialer.DialContext)(ctx, network, addr)
+	if err != nil || tlsConfig == nil {
+		return conn, err
+	}
+
+	config := tlsConfig
+	if config.ServerName == "" {
+		// 与tls.Dial一致，未指定时以目标主机名校验证书
+		host, _, splitErr := net.SplitHostPort(addr)
+		if splitErr != nil {
+			host = addr
+		}
+		config = tlsConfig.Clone()
+		config.ServerName = host
+	}
+	if dialer.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, dialer.Timeout)
+		defer cancel()
+	}
+	start := time.Now()
+	tlsConn := tls.Client(conn, config)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	t.RecordPhase(PhaseTLS, time.Since(start))
+	return tlsConn, nil
+}
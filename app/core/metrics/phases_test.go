@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPhaseTracker_Breakdown(t *testing.T) {
+	tracker := NewPhaseTracker()
+	if tracker.Breakdown() != nil {
+		t.Fatal("expected no breakdown before any record")
+	}
+
+	// 第一次请求新建连接，第二次复用连接
+	tracker.Record(PhaseTimings{Connect: 2 * time.Millisecond, TLS: 4 * time.Millisecond, Request: time.Millisecond, FirstByte: 10 * time.Millisecond, Transfer: 3 * time.Millisecond})
+	tracker.Record(PhaseTimings{Request: time.Millisecond, FirstByte: 10 * time.Millisecond, Transfer: 1 * time.Millisecond})
+
+	breakdown := tracker.Breakdown()
+	want := []LatencyPhase{PhaseConnect, PhaseTLS, PhaseRequest, PhaseFirstByte, PhaseTransfer}
+	if len(breakdown) != len(want) {
+		t.Fatalf("breakdown = %+v, want phases %v", breakdown, want)
+	}
+	var share float64
+	for i, phase := range breakdown {
+		if phase.Phase != want[i] {
+			t.Errorf("phase %d = %s, want %s", i, phase.Phase, want[i])
+		}
+		share += phase.Share
+	}
+	if breakdown[0].Count != 1 || breakdown[3].Count != 2 || breakdown[3].Total != 20*time.Millisecond {
+		t.Errorf("unexpected counts: %+v", breakdown)
+	}
+	// 全部阶段共32ms，首字节等待占20ms
+	if got := breakdown[3].Share; got < 0.62 || got > 0.63 {
+		t.Errorf("first byte share = %.3f, want 0.625", got)
+	}
+	if share < 0.999 || share > 1.001 {
+		t.Errorf("shares sum to %.3f, want 1", share)
+	}
+}
+
+func TestPhaseTracker_DialTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	addr := server.Listener.Addr().String()
+
+	tracker := NewPhaseTracker()
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	conn, err := tracker.DialTLS(context.Background(), dialer, "tcp", addr, &tls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatalf("DialTLS: %v", err)
+	}
+	if _, ok := conn.(*tls.Conn); !ok {
+		t.Errorf("expected a TLS connection, got %T", conn)
+	}
+	conn.Close()
+
+	// 不需要TLS时只记录建连
+	conn, err = tracker.DialTLS(context.Background(), dialer, "tcp", addr, nil)
+	if err != nil {
+		t.Fatalf("DialTLS without TLS: %v", err)
+	}
+	conn.Close()
+
+	breakdown := tracker.Breakdown()
+	if len(breakdown) != 2 || breakdown[0].Phase != PhaseConnect || breakdown[0].Count != 2 ||
+		breakdown[1].Phase != PhaseTLS || breakdown[1].Count != 1 {
+		t.Errorf("unexpected breakdown: %+v", breakdown)
+	}
+
+	// 建连失败不计入
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := listener.Addr().String()
+	listener.Close()
+	if _, err := tracker.WrapDial(nil)(context.Background(), "tcp", closed); err == nil {
+		t.Fatal("expected dial to a closed port to fail")
+	}
+	if got := tracker.Breakdown()[0].Count; got != 2 {
+		t.Errorf("connect count after failed dial = %d, want 2", got)
+	}
+}
@@ -0,0 +1,244 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+)
+
+// PrometheusContentType Prometheus文本暴露格式
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultLatencyBuckets 默认延迟直方图桶（秒）
+var DefaultLatencyBuckets = []float64{
+	0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10,
+}
+
+// operationStats 单个操作类型的累计统计
+type operationStats struct {
+	success uint64
+	failed  uint64
+	buckets []uint64
+	sum     float64
+	count   uint64
+}
+
+// PrometheusExporter 以Prometheus文本格式暴露运行中的基准测试指标
+// 作为ResultObserver记录每个操作的延迟直方图和计数，并结合收集器快照输出实时汇总指标
+type PrometheusExporter struct {
+	collector  DefaultMetricsCollector
+	buckets    []float64
+	operations map[string]*operationStats
+	mutex      sync.Mutex
+
+	server   *http.Server
+	listener net.Listener
+}
+
+// NewPrometheusExporter 创建Prometheus导出器
+func NewPrometheusExporter(collector DefaultMetricsCollector) *PrometheusExporter {
+	return &PrometheusExporter{
+		collector:  collector,
+		buckets:    DefaultLatencyBuckets,
+		operations: make(map[string]*operationStats),
+	}
+}
+
+// Observe 记录单个操作结果
+func (p *PrometheusExporter) Observe(result *interfaces.OperationResult) {
+	if result == nil {
+		return
+	}
+
+	operation := operationLabel(result)
+	seconds := result.Duration.Seconds()
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	stats, ok := p.operations[operation]
+	if !ok {
+		stats = &operationStats{buckets: make([]uint64, len(p.buckets))}
+		p.operations[operation] = stats
+	}
+
+	if result.Success {
+		stats.success++
+	} else {
+		stats.failed++
+	}
+	for i, bound := range p.buckets {
+		if seconds <= bound {
+			stats.buckets[i]++
+		}
+	}
+	stats.sum += seconds
+	stats.count++
+}
+
+// WriteMetrics 以Prometheus文本格式写出全部指标
+func (p *PrometheusExporter) WriteMetrics(w io.Writer) error {
+	var b strings.Builder
+
+	protocol := "unknown"
+	var snapshot *DefaultMetricsSnapshot
+	if p.collector != nil {
+		snapshot = p.collector.Snapshot()
+	}
+	if snapshot != nil {
+		if name, ok := snapshot.Protocol["protocol"].(string); ok && name != "" {
+			protocol = name
+		}
+	}
+	base := fmt.Sprintf(`protocol="%s"`, escapeLabel(protocol))
+
+	// 按操作类型的计数与延迟直方图
+	p.mutex.Lock()
+	names := make([]string, 0, len(p.operations))
+	for name := range p.operations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	b.WriteString("# HELP abc_runner_operations_total Completed operations by type and result.\n")
+	b.WriteString("# TYPE abc_runner_operations_total counter\n")
+	for _, name := range names {
+		stats := p.operations[name]
+		labels := fmt.Sprintf(`%s,operation="%s"`, base, escapeLabel(name))
+		fmt.Fprintf(&b, "abc_runner_operations_total{%s,result=\"success\"} %d\n", labels, stats.success)
+		fmt.Fprintf(&b, "abc_runner_operations_total{%s,result=\"failure\"} %d\n", labels, stats.failed)
+	}
+
+	b.WriteString("# HELP abc_runner_operation_duration_seconds Operation latency distribution.\n")
+	b.WriteString("# TYPE abc_runner_operation_duration_seconds histogram\n")
+	for _, name := range names {
+		stats := p.operations[name]
+		labels := fmt.Sprintf(`%s,operation="%s"`, base, escapeLabel(name))
+		for i, bound := range p.buckets {
+			fmt.Fprintf(&b, "abc_runner_operation_duration_seconds_bucket{%s,le=\"%s\"} %d\n", labels, formatFloat(bound), stats.buckets[i])
+		}
+		fmt.Fprintf(&b, "abc_runner_operation_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, stats.count)
+		fmt.Fprintf(&b, "abc_runner_operation_duration_seconds_sum{%s} %s\n", labels, formatFloat(stats.sum))
+		fmt.Fprintf(&b, "abc_runner_operation_duration_seconds_count{%s} %d\n", labels, stats.count)
+	}
+	p.mutex.Unlock()
+
+	// 收集器快照中的实时汇总指标
+	if snapshot != nil {
+		core := snapshot.Core
+		writeGauge(&b, "abc_runner_elapsed_seconds", "Elapsed benchmark time.", base, core.Duration.Seconds())
+		writeGauge(&b, "abc_runner_throughput_rps", "Current overall throughput in operations per second.", base, core.Throughput.RPS)
+		writeGauge(&b, "abc_runner_success_rate_percent", "Percentage of successful operations.", base, core.Operations.Rate)
+		b.WriteString("# HELP abc_runner_transferred_bytes_total Payload bytes read from and written to the target.\n")
+		b.WriteString("# TYPE abc_runner_transferred_bytes_total counter\n")
+		fmt.Fprintf(&b, "abc_runner_transferred_bytes_total{%s,direction=\"read\"} %d\n", base, core.Operations.BytesRead)
+		fmt.Fprintf(&b, "abc_runner_transferred_bytes_total{%s,direction=\"write\"} %d\n", base, core.Operations.BytesWritten)
+
+		// 收集器计算的分位数以summary类型暴露，quantile标签仅在summary上合法
+		b.WriteString("# HELP abc_runner_latency_seconds Latency percentiles computed by the collector.\n")
+		b.WriteString("# TYPE abc_runner_latency_seconds summary\n")
+		for _, q := range []struct {
+			quantile string
+			value    time.Duration
+		}{
+			{"0.5", core.Latency.P50},
+			{"0.9", core.Latency.P90},
+			{"0.95", core.Latency.P95},
+			{"0.99", core.Latency.P99},
+			{"0.999", core.Latency.P999},
+		} {
+			fmt.Fprintf(&b, "abc_runner_latency_seconds{%s,quantile=\"%s\"} %s\n", base, q.quantile, formatFloat(q.value.Seconds()))
+		}
+		fmt.Fprintf(&b, "abc_runner_latency_seconds_sum{%s} %s\n", base, formatFloat(core.Latency.Average.Seconds()*float64(core.Operations.Total)))
+		fmt.Fprintf(&b, "abc_runner_latency_seconds_count{%s} %d\n", base, core.Operations.Total)
+
+		writeGauge(&b, "abc_runner_memory_inuse_bytes", "Heap memory in use by the runner.", base, float64(snapshot.System.MemoryUsage.InUse))
+		writeGauge(&b, "abc_runner_goroutines", "Number of goroutines in the runner.", base, float64(snapshot.System.GoroutineCount))
+		writeGauge(&b, "abc_runner_gc_count", "Number of completed GC cycles.", base, float64(snapshot.System.GCStats.NumGC))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Handler 获取 /metrics HTTP处理器
+func (p *PrometheusExporter) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", PrometheusContentType)
+		p.WriteMetrics(w)
+	})
+	return mux
+}
+
+// Start 在指定地址上启动 /metrics 端点
+func (p *PrometheusExporter) Start(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	p.listener = listener
+	p.server = &http.Server{Handler: p.Handler()}
+	go func() {
+		if err := p.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Printf("⚠️  Metrics endpoint stopped: %v\n", err)
+		}
+	}()
+	return nil
+}
+
+// Addr 获取实际监听地址
+func (p *PrometheusExporter) Addr() string {
+	if p.listener == nil {
+		return ""
+	}
+	return p.listener.Addr().String()
+}
+
+// Stop 停止 /metrics 端点
+func (p *PrometheusExporter) Stop() error {
+	if p.server == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return p.server.Shutdown(ctx)
+}
+
+// operationLabel 获取操作类型标签
+func operationLabel(result *interfaces.OperationResult) string {
+	if result.Metadata != nil {
+		if opType, ok := result.Metadata["operation_type"].(string); ok && opType != "" {
+			return opType
+		}
+	}
+	if result.IsRead {
+		return "read"
+	}
+	return "write"
+}
+
+// writeGauge 写出单值gauge
+func writeGauge(b *strings.Builder, name, help, labels string, value float64) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s gauge\n%s{%s} %s\n", name, help, name, name, labels, formatFloat(value))
+}
+
+// formatFloat 格式化Prometheus数值
+func formatFloat(value float64) string {
+	return fmt.Sprintf("%g", value)
+}
+
+// escapeLabel 转义标签值
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
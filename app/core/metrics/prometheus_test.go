@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+)
+
+func TestPrometheusExporter_Endpoint(t *testing.T) {
+	collector := NewBaseCollector(DefaultMetricsConfig(), map[string]interface{}{"protocol": "redis"})
+	defer collector.Stop()
+
+	exporter := NewPrometheusExporter(collector)
+	collector.AddObserver(exporter)
+
+	collector.Record(&interfaces.OperationResult{
+		Success:  true,
+		Duration: 2 * time.Millisecond,
+		IsRead:   true,
+		Metadata: map[string]interface{}{"operation_type": "get"},
+	})
+	collector.Record(&interfaces.OperationResult{
+		Success:  false,
+		Duration: 30 * time.Millisecond,
+		Metadata: map[string]interface{}{"operation_type": "set"},
+	})
+
+	if err := exporter.Start("127.0.0.1:0"); err != nil {
+		t.Fatalf("failed to start exporter: %v", err)
+	}
+	defer exporter.Stop()
+
+	resp, err := http.Get("http://" + exporter.Addr() + "/metrics")
+	if err != nil {
+		t.Fatalf("failed to scrape metrics: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	output := string(body)
+
+	for _, expected := range []string{
+		`abc_runner_operations_total{protocol="redis",operation="get",result="success"} 1`,
+		`abc_runner_operations_total{protocol="redis",operation="set",result="failure"} 1`,
+		`abc_runner_operation_duration_seconds_bucket{protocol="redis",operation="get",le="0.0025"} 1`,
+		`abc_runner_operation_duration_seconds_bucket{protocol="redis",operation="set",le="0.025"} 0`,
+		`abc_runner_operation_duration_seconds_count{protocol="redis",operation="set"} 1`,
+		`# TYPE abc_runner_throughput_rps gauge`,
+		`# TYPE abc_runner_latency_seconds summary`,
+		`abc_runner_latency_seconds{protocol="redis",quantile="0.99"}`,
+		`abc_runner_latency_seconds_count{protocol="redis"}`,
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("metrics output missing %q\n%s", expected, output)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/s2"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// 最终结果推送的默认值
+const (
+	DefaultResultJob         = "abc_runner"
+	DefaultResultPushTimeout = 10 * time.Second
+)
+
+// maxPushErrorBody 推送失败时保留的响应正文字节数
+const maxPushErrorBody = 512
+
+// prompb.WriteRequest/TimeSeries/Label/Sample的字段号
+const (
+	fieldWriteRequestTimeSeries protowire.Number = 1
+	fieldTimeSeriesLabels       protowire.Number = 1
+	fieldTimeSeriesSamples      protowire.Number = 2
+	fieldLabelName              protowire.Number = 1
+	fieldLabelValue             protowire.Number = 2
+	fieldSampleValue            protowire.Number = 1
+	fieldSampleTimestamp        protowire.Number = 2
+)
+
+// RunLabels 区分各次运行的标签，为空的标签不输出
+type RunLabels struct {
+	Protocol string
+	Target   string
+	GitSHA   string
+	RunID    string
+}
+
+// pairs 按名称排序的标签
+func (l RunLabels) pairs() [][2]string {
+	var pairs [][2]string
+	for _, label := range [][2]string{{"git_sha", l.GitSHA}, {"protocol", l.Protocol}, {"run_id", l.RunID}, {"target", l.Target}} {
+		if label[1] != "" {
+			pairs = append(pairs, label)
+		}
+	}
+	return pairs
+}
+
+// FinalResult 一次运行的最终汇总结果
+type FinalResult struct {
+	Labels     RunLabels
+	Core       CoreMetrics
+	Passed     bool      // 运行是否通过（未中断、未触发停止条件且SLA全部满足）
+	FinishedAt time.Time // 样本时间戳
+}
+
+// resultSample 最终结果中的一个样本
+type resultSample struct {
+	name   string
+	help   string
+	labels [][2]string // 样本自身的标签（如quantile），不含运行标签
+	value  float64
+}
+
+// samples 将最终结果转换为gauge样本，同名样本相邻
+func (r FinalResult) samples() []resultSample {
+	core := r.Core
+	errorRate := 0.0
+	if core.Operations.Total > 0 {
+		errorRate = float64(core.Operations.Failed) / float64(core.Operations.Total) * 100
+	}
+	passed := 0.0
+	if r.Passed {
+		passed = 1
+	}
+
+	samples := []resultSample{
+		{"abc_runner_result_operations", "Operations completed in the run by result.", [][2]string{{"result", "success"}}, float64(core.Operations.Success)},
+		{"abc_runner_result_operations", "", [][2]string{{"result", "failure"}}, float64(core.Operations.Failed)},
+		{"abc_runner_result_duration_seconds", "Measured duration of the run.", nil, core.Duration.Seconds()},
+		{"abc_runner_result_throughput_rps", "Overall throughput of the run in operations per second.", nil, core.Throughput.RPS},
+		{"abc_runner_result_error_rate_percent", "Percentage of failed operations in the run.", nil, errorRate},
+	}
+	for i, q := range []struct {
+		quantile string
+		value    time.Duration
+	}{
+		{"0", core.Latency.Min},
+		{"0.5", core.Latency.P50},
+		{"0.9", core.Latency.P90},
+		{"0.95", core.Latency.P95},
+		{"0.99", core.Latency.P99},
+		{"0.999", core.Latency.P999},
+		{"1", core.Latency.Max},
+	} {
+		help := ""
+		if i == 0 {
+			help = "Latency percentiles of the run (quantile 0 and 1 are min and max)."
+		}
+		samples = append(samples, resultSample{"abc_runner_result_latency_seconds", help, [][2]string{{"quantile", q.quantile}}, q.value.Seconds()})
+	}
+	samples = append(samples,
+		resultSample{"abc_runner_result_latency_average_seconds", "Average latency of the run.", nil, core.Latency.Average.Seconds()},
+		resultSample{"abc_runner_result_transferred_bytes", "Payload bytes read from and written to the target during the run.", [][2]string{{"direction", "read"}}, float64(core.Operations.BytesRead)},
+		resultSample{"abc_runner_result_transferred_bytes", "", [][2]string{{"direction", "write"}}, float64(core.Operations.BytesWritten)},
+		resultSample{"abc_runner_result_passed", "Whether the run passed (1) or failed, was aborted or stopped early (0).", nil, passed},
+		resultSample{"abc_runner_result_timestamp_seconds", "Unix time the run finished.", nil, float64(r.FinishedAt.UnixNano()) / 1e9},
+	)
+	return samples
+}
+
+// ResultPusher 运行结束后将最终汇总结果推送到Pushgateway或Prometheus remote-write端点，
+// 使历史运行进入已有的Prometheus/Grafana
+type ResultPusher struct {
+	pushgateway string
+	remoteWrite string
+	job         string
+	client      *http.Client
+}
+
+// NewResultPusher 创建最终结果推送器，地址为空的目标不推送
+func NewResultPusher(pushgateway, remoteWrite string) *ResultPusher {
+	return &ResultPusher{
+		pushgateway: strings.TrimRight(pushgateway, "/"),
+		remoteWrite: remoteWrite,
+		job:         DefaultResultJob,
+		client:      &http.Client{Timeout: DefaultResultPushTimeout},
+	}
+}
+
+// Push 推送最终结果，各目标分别推送，返回合并的错误
+func (p *ResultPusher) Push(ctx context.Context, result FinalResult) error {
+	if result.FinishedAt.IsZero() {
+		result.FinishedAt = time.Now()
+	}
+	var errs []error
+	if p.pushgateway != "" {
+		if err := p.pushGateway(ctx, result); err != nil {
+			errs = append(errs, fmt.Errorf("pushgateway: %w", err))
+		}
+	}
+	if p.remoteWrite != "" {
+		if err := p.pushRemoteWrite(ctx, result); err != nil {
+			errs = append(errs, fmt.Errorf("remote-write: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// pushGateway 以PUT替换分组中的指标，分组键为job、协议与目标，
+// 每次运行覆盖同一目标上一次的结果，历史由抓取Pushgateway的Prometheus保存
+func (p *ResultPusher) pushGateway(ctx context.Context, result FinalResult) error {
+	url := p.pushgateway + "/metrics/job/" + p.job
+	for _, key := range [][2]string{{"protocol", result.Labels.Protocol}, {"target", result.Labels.Target}} {
+		if key[1] != "" {
+			// 标签值可能包含"/"，按Pushgateway约定以base64编码
+			url += "/" + key[0] + "@base64/" + base64.RawURLEncoding.EncodeToString([]byte(key[1]))
+		}
+	}
+
+	var body bytes.Buffer
+	writeResultText(&body, result)
+	return p.send(ctx, http.MethodPut, url, &body, http.Header{"Content-Type": {PrometheusContentType}})
+}
+
+// writeResultText 以Prometheus文本格式写出最终结果；分组键中的标签由Pushgateway附加
+func writeResultText(w io.Writer, result FinalResult) {
+	labels := RunLabels{GitSHA: result.Labels.GitSHA, RunID: result.Labels.RunID}.pairs()
+	for _, sample := range result.samples() {
+		if sample.help != "" {
+			fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", sample.name, sample.help, sample.name)
+		}
+		all := append(append([][2]string{}, labels...), sample.labels...)
+		parts := make([]string, len(all))
+		for i, label := range all {
+			parts[i] = fmt.Sprintf("%s=\"%s\"", label[0], escapeLabel(label[1]))
+		}
+		fmt.Fprintf(w, "%s{%s} %s\n", sample.name, strings.Join(parts, ","), formatFloat(sample.value))
+	}
+}
+
+// pushRemoteWrite 以remote-write 1.0协议写入，每个样本一个序列，时间戳为运行结束时间
+func (p *ResultPusher) pushRemoteWrite(ctx context.Context, result FinalResult) error {
+	body := s2.EncodeSnappy(nil, encodeWriteRequest(result, p.job))
+	return p.send(ctx, http.MethodPost, p.remoteWrite, bytes.NewReader(body), http.Header{
+		"Content-Type":                      {"application/x-protobuf"},
+		"Content-Encoding":                  {"snappy"},
+		"X-Prometheus-Remote-Write-Version": {"0.1.0"},
+	})
+}
+
+// encodeWriteRequest 将最终结果编码为未压缩的prompb.WriteRequest
+func encodeWriteRequest(result FinalResult, job string) []byte {
+	runLabels := append(result.Labels.pairs(), [2]string{"job", job})
+	timestamp := result.FinishedAt.UnixMilli()
+
+	var request []byte
+	for _, sample := range result.samples() {
+		labels := append([][2]string{{"__name__", sample.name}}, runLabels...)
+		labels = append(labels, sample.labels...)
+		// remote-write要求标签按名称排序
+		sort.Slice(labels, func(a, b int) bool { return labels[a][0] < labels[b][0] })
+
+		var series []byte
+		for _, label := range labels {
+			var body []byte
+			body = protowire.AppendTag(body, fieldLabelName, protowire.BytesType)
+			body = protowire.AppendString(body, label[0])
+			body = protowire.AppendTag(body, fieldLabelValue, protowire.BytesType)
+			body = protowire.AppendString(body, label[1])
+			series = protowire.AppendTag(series, fieldTimeSeriesLabels, protowire.BytesType)
+			series = protowire.AppendBytes(series, body)
+		}
+		var point []byte
+		point = protowire.AppendTag(point, fieldSampleValue, protowire.Fixed64Type)
+		point = protowire.AppendFixed64(point, math.Float64bits(sample.value))
+		point = protowire.AppendTag(point, fieldSampleTimestamp, protowire.VarintType)
+		point = protowire.AppendVarint(point, uint64(timestamp))
+		series = protowire.AppendTag(series, fieldTimeSeriesSamples, protowire.BytesType)
+		series = protowire.AppendBytes(series, point)
+
+		request = protowire.AppendTag(request, fieldWriteRequestTimeSeries, protowire.BytesType)
+		request = protowire.AppendBytes(request, series)
+	}
+	return request
+}
+
+// send 发送请求，非2xx响应返回包含正文摘要的错误
+func (p *ResultPusher) send(ctx context.Context, method, url string, body io.Reader, headers http.Header) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	req.Header = headers
+	req.Header.Set("User-Agent", "abc-runner")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, maxPushErrorBody))
+		return fmt.Errorf("%s responded with status %d: %s", url, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,141 @@
+package metrics
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/s2"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// testFinalResult 用于推送测试的最终结果
+func testFinalResult() FinalResult {
+	result := FinalResult{
+		Labels:     RunLabels{Protocol: "http", Target: "http://api:8080/v1", GitSHA: "abc123", RunID: "20261016-120000-beef"},
+		Passed:     true,
+		FinishedAt: time.UnixMilli(1760000000000),
+	}
+	result.Core.Operations = OperationMetrics{Total: 100, Success: 98, Failed: 2, BytesRead: 4096}
+	result.Core.Latency.P99 = 25 * time.Millisecond
+	result.Core.Throughput.RPS = 1000
+	result.Core.Duration = 100 * time.Millisecond
+	return result
+}
+
+func TestResultPusher_Pushgateway(t *testing.T) {
+	var method, path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		method, path, body = r.Method, r.URL.Path, string(data)
+	}))
+	defer server.Close()
+
+	if err := NewResultPusher(server.URL+"/", "").Push(context.Background(), testFinalResult()); err != nil {
+		t.Fatalf("push failed: %v", err)
+	}
+	target := base64.RawURLEncoding.EncodeToString([]byte("http://api:8080/v1"))
+	if method != http.MethodPut || path != "/metrics/job/abc_runner/protocol@base64/aHR0cA/target@base64/"+target {
+		t.Errorf("request = %s %s", method, path)
+	}
+	for _, want := range []string{
+		"# TYPE abc_runner_result_operations gauge\n",
+		`abc_runner_result_operations{git_sha="abc123",run_id="20261016-120000-beef",result="failure"} 2`,
+		`abc_runner_result_latency_seconds{git_sha="abc123",run_id="20261016-120000-beef",quantile="0.99"} 0.025`,
+		`abc_runner_result_passed{git_sha="abc123",run_id="20261016-120000-beef"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+	// 分组键中的标签不能再出现在样本中
+	if strings.Contains(body, "protocol=") || strings.Contains(body, "target=") {
+		t.Errorf("grouping labels repeated in body:\n%s", body)
+	}
+}
+
+func TestResultPusher_RemoteWrite(t *testing.T) {
+	var request []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != "snappy" || r.Header.Get("Content-Type") != "application/x-protobuf" {
+			t.Errorf("unexpected headers: %v", r.Header)
+		}
+		data, _ := io.ReadAll(r.Body)
+		request, _ = s2.Decode(nil, data)
+	}))
+	defer server.Close()
+
+	result := testFinalResult()
+	if err := NewResultPusher("", server.URL).Push(context.Background(), result); err != nil {
+		t.Fatalf("push failed: %v", err)
+	}
+
+	// 解码每个序列的标签与样本
+	series := 0
+	for len(request) > 0 {
+		_, _, n := protowire.ConsumeTag(request)
+		data, m := protowire.ConsumeBytes(request[n:])
+		request = request[n+m:]
+		series++
+
+		labels := map[string]string{}
+		var names []string
+		var value float64
+		var timestamp int64
+		for len(data) > 0 {
+			num, _, n := protowire.ConsumeTag(data)
+			field, m := protowire.ConsumeBytes(data[n:])
+			data = data[n+m:]
+			if num == fieldTimeSeriesLabels {
+				_, _, n := protowire.ConsumeTag(field)
+				name, m := protowire.ConsumeString(field[n:])
+				_, _, k := protowire.ConsumeTag(field[n+m:])
+				labels[name], _ = protowire.ConsumeString(field[n+m+k:])
+				names = append(names, name)
+				continue
+			}
+			_, _, n = protowire.ConsumeTag(field)
+			bits, m := protowire.ConsumeFixed64(field[n:])
+			value = math.Float64frombits(bits)
+			_, _, k := protowire.ConsumeTag(field[n+m:])
+			ts, _ := protowire.ConsumeVarint(field[n+m+k:])
+			timestamp = int64(ts)
+		}
+		for i := 1; i < len(names); i++ {
+			if names[i-1] >= names[i] {
+				t.Fatalf("labels not sorted: %v", names)
+			}
+		}
+		if labels["job"] != DefaultResultJob || labels["target"] != "http://api:8080/v1" || labels["run_id"] != result.Labels.RunID {
+			t.Errorf("missing run labels: %v", labels)
+		}
+		if timestamp != 1760000000000 {
+			t.Errorf("timestamp = %d", timestamp)
+		}
+		if labels["__name__"] == "abc_runner_result_throughput_rps" && value != 1000 {
+			t.Errorf("throughput = %v, want 1000", value)
+		}
+	}
+	if want := len(result.samples()); series != want {
+		t.Errorf("series = %d, want %d", series, want)
+	}
+}
+
+func TestResultPusher_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "out of order sample", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	err := NewResultPusher(server.URL, server.URL).Push(context.Background(), testFinalResult())
+	if err == nil || !strings.Contains(err.Error(), "pushgateway:") || !strings.Contains(err.Error(), "remote-write:") ||
+		!strings.Contains(err.Error(), "out of order sample") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package metrics
+
+import (
+	"sync/atomic"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+)
+
+// MetricsShard 收集器的本地指标分片，供按核执行的引擎使用
+// 每个分片只由一个执行核写入，操作计数与延迟直方图不与其他核共享缓存行，收集器快照时合并全部分片；
+// 时间序列与结果观察者仍由收集器统一处理
+type MetricsShard struct {
+	parent     shardParent
+	operations *OperationTracker
+	latency    *LatencyTracker
+}
+
+// shardParent 分片所属收集器中与类型参数无关的部分
+type shardParent interface {
+	running() bool
+	observe(result *interfaces.OperationResult)
+}
+
+// NewShard 创建并注册一个本地指标分片
+func (bc *BaseCollector[T]) NewShard() *MetricsShard {
+	bc.mutex.Lock()
+	defer bc.mutex.Unlock()
+
+	shard := &MetricsShard{
+		parent:     bc,
+		operations: NewOperationTracker(),
+		latency:    NewLatencyTracker(bc.config.Latency),
+	}
+	current, _ := bc.shards.Load().([]*MetricsShard)
+	updated := make([]*MetricsShard, len(current), len(current)+1)
+	copy(updated, current)
+	bc.shards.Store(append(updated, shard))
+	return shard
+}
+
+// Record 记录操作结果到分片
+func (s *MetricsShard) Record(result *interfaces.OperationResult) {
+	if !s.parent.running() {
+		return
+	}
+	s.operations.Record(result)
+	s.latency.Record(result.Duration)
+	s.parent.observe(result)
+}
+
+// reset 重置分片统计
+func (s *MetricsShard) reset() {
+	s.operations.Reset()
+	s.latency.Reset()
+}
+
+// running 收集器是否仍在运行
+func (bc *BaseCollector[T]) running() bool {
+	return atomic.LoadInt32(&bc.isRunning) == 1
+}
+
+// observe 记录时间序列并通知观察者
+func (bc *BaseCollector[T]) observe(result *interfaces.OperationResult) {
+	if bc.timeSeries != nil {
+		bc.timeSeries.Record(result)
+	}
+	if observers, ok := bc.observers.Load().([]ResultObserver); ok {
+		for _, observer := range observers {
+			observer.Observe(result)
+		}
+	}
+}
+
+// mergeShards 合并收集器自身与各分片的操作、延迟与吞吐量指标
+func (bc *BaseCollector[T]) mergeShards(shards []*MetricsShard, duration time.Duration) (OperationMetrics, LatencyMetrics, ThroughputMetrics) {
+	operations := bc.operations.GetMetrics()
+	trackers := []*LatencyTracker{bc.latency}
+	for _, shard := range shards {
+		shardOps := shard.operations.GetMetrics()
+		operations.Total += shardOps.Total
+		operations.Success += shardOps.Success
+		operations.Failed += shardOps.Failed
+		operations.Read += shardOps.Read
+		operations.Write += shardOps.Write
+		operations.BytesRead += shardOps.BytesRead
+		operations.BytesWritten += shardOps.BytesWritten
+		trackers = append(trackers, shard.latency)
+	}
+	operations.Rate = 0
+	if operations.Total > 0 {
+		operations.Rate = float64(operations.Success) / float64(operations.Total) * 100.0
+	}
+
+	var throughput ThroughputMetrics
+	if seconds := duration.Seconds(); seconds > 0 {
+		throughput.RPS = float64(operations.Read+operations.Write) / seconds
+		throughput.ReadRPS = float64(operations.Read) / seconds
+		throughput.WriteRPS = float64(operations.Write) / seconds
+	}
+	return operations, mergeLatency(bc.config.Latency, trackers), throughput
+}
+
+// mergeLatency 合并多个延迟追踪器的直方图与极值
+func mergeLatency(config LatencyConfig, trackers []*LatencyTracker) LatencyMetrics {
+	merged := NewLatencyTracker(config)
+	for _, tracker := range trackers {
+		count := atomic.LoadInt64(&tracker.count)
+		if count == 0 {
+			continue
+		}
+		merged.count += count
+		merged.total += atomic.LoadInt64(&tracker.total)
+		if min := atomic.LoadInt64(&tracker.min); min < merged.min {
+			merged.min = min
+		}
+		if max := atomic.LoadInt64(&tracker.max); max > merged.max {
+			merged.max = max
+		}
+		merged.histogram.Merge(tracker.histogram)
+	}
+	return merged.GetMetrics()
+}
+
+// LatencyHistogram 导出收集器自身与各分片合并后的延迟直方图
+func (bc *BaseCollector[T]) LatencyHistogram() *LatencyHistogram {
+	merged := NewHdrHistogram(DefaultHdrHighestValue, bc.config.Latency.SignificantDigits)
+	merged.Merge(bc.latency.histogram)
+	if shards, _ := bc.shards.Load().([]*MetricsShard); len(shards) > 0 {
+		for _, shard := range shards {
+			merged.Merge(shard.latency.histogram)
+		}
+	}
+	return merged.Export()
+}
@@ -0,0 +1,30 @@
+package metrics
+
+import "context"
+
+// SnapshotSink 指标快照接收器
+// 由调用方通过context注入，命令执行期间周期性推送中间快照，结束时推送最终快照
+type SnapshotSink interface {
+	// OnProgress 接收运行中的中间快照
+	OnProgress(snapshot *DefaultMetricsSnapshot)
+
+	// OnFinal 接收测试结束后的最终快照
+	OnFinal(snapshot *DefaultMetricsSnapshot)
+}
+
+// snapshotSinkKey context键
+type snapshotSinkKey struct{}
+
+// WithSnapshotSink 返回携带快照接收器的context
+func WithSnapshotSink(ctx context.Context, sink SnapshotSink) context.Context {
+	return context.WithValue(ctx, snapshotSinkKey{}, sink)
+}
+
+// SnapshotSinkFromContext 从context中获取快照接收器
+func SnapshotSinkFromContext(ctx context.Context) (SnapshotSink, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	sink, ok := ctx.Value(snapshotSinkKey{}).(SnapshotSink)
+	return sink, ok && sink != nil
+}
@@ -0,0 +1,215 @@
+package metrics
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SLA规则支持的指标
+const (
+	SLAMetricP50         = "p50"
+	SLAMetricP90         = "p90"
+	SLAMetricP95         = "p95"
+	SLAMetricP99         = "p99"
+	SLAMetricP999        = "p999"
+	SLAMetricAvg         = "avg"
+	SLAMetricMax         = "max"
+	SLAMetricRPS         = "rps"
+	SLAMetricErrorRate   = "error_rate"
+	SLAMetricSuccessRate = "success_rate"
+)
+
+// slaMetricAliases 指标别名到规范名称的映射
+var slaMetricAliases = map[string]string{
+	"p50":          SLAMetricP50,
+	"median":       SLAMetricP50,
+	"p90":          SLAMetricP90,
+	"p95":          SLAMetricP95,
+	"p99":          SLAMetricP99,
+	"p999":         SLAMetricP999,
+	"p99.9":        SLAMetricP999,
+	"avg":          SLAMetricAvg,
+	"mean":         SLAMetricAvg,
+	"average":      SLAMetricAvg,
+	"max":          SLAMetricMax,
+	"rps":          SLAMetricRPS,
+	"qps":          SLAMetricRPS,
+	"throughput":   SLAMetricRPS,
+	"error_rate":   SLAMetricErrorRate,
+	"success_rate": SLAMetricSuccessRate,
+}
+
+// slaRulePattern 规则表达式：<指标> <比较符> <阈值>
+var slaRulePattern = regexp.MustCompile(`^\s*([A-Za-z0-9_.]+)\s*(<=|>=|<|>)\s*(\S+)\s*$`)
+
+// SLARule 单条SLA规则，如 "p99 < 20ms"、"error_rate < 1%"、"rps > 5000"
+type SLARule struct {
+	Metric    string  // 规范化的指标名称
+	Operator  string  // <、<=、>或>=
+	Threshold float64 // 阈值，延迟单位为ns，比率单位为%
+	raw       string  // 阈值原文，用于展示
+}
+
+// ParseSLARule 解析SLA规则表达式
+func ParseSLARule(expr string) (SLARule, error) {
+	match := slaRulePattern.FindStringSubmatch(expr)
+	if match == nil {
+		return SLARule{}, fmt.Errorf("invalid SLA rule %q (expected e.g. \"p99 < 20ms\", \"error_rate < 1%%\", \"rps > 5000\")", expr)
+	}
+
+	metric, ok := slaMetricAliases[strings.ToLower(match[1])]
+	if !ok {
+		return SLARule{}, fmt.Errorf("invalid SLA rule %q: unknown metric %q", expr, match[1])
+	}
+
+	rule := SLARule{Metric: metric, Operator: match[2], raw: match[3]}
+	switch metric {
+	case SLAMetricRPS:
+		threshold, err := strconv.ParseFloat(match[3], 64)
+		if err != nil {
+			return SLARule{}, fmt.Errorf("invalid SLA rule %q: %w", expr, err)
+		}
+		rule.Threshold = threshold
+	case SLAMetricErrorRate, SLAMetricSuccessRate:
+		threshold, err := strconv.ParseFloat(strings.TrimSuffix(match[3], "%"), 64)
+		if err != nil {
+			return SLARule{}, fmt.Errorf("invalid SLA rule %q: %w", expr, err)
+		}
+		if threshold < 0 || threshold > 100 {
+			return SLARule{}, fmt.Errorf("invalid SLA rule %q: %s must be between 0%% and 100%%", expr, metric)
+		}
+		rule.Threshold = threshold
+		rule.raw = strings.TrimSuffix(match[3], "%") + "%"
+	default:
+		threshold, err := time.ParseDuration(match[3])
+		if err != nil {
+			return SLARule{}, fmt.Errorf("invalid SLA rule %q: latency threshold needs a unit, e.g. 20ms: %w", expr, err)
+		}
+		rule.Threshold = float64(threshold)
+	}
+
+	if rule.Threshold < 0 {
+		return SLARule{}, fmt.Errorf("invalid SLA rule %q: threshold must not be negative", expr)
+	}
+	return rule, nil
+}
+
+// ParseSLARules 批量解析SLA规则表达式
+func ParseSLARules(exprs []string) ([]SLARule, error) {
+	rules := make([]SLARule, 0, len(exprs))
+	for _, expr := range exprs {
+		rule, err := ParseSLARule(expr)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// String 规范化的规则表达式
+func (r SLARule) String() string {
+	return fmt.Sprintf("%s %s %s", r.Metric, r.Operator, r.raw)
+}
+
+// SLAAssertion 单条SLA规则的判定结果
+type SLAAssertion struct {
+	Name      string  `json:"name"`      // 规则表达式，如 "p99 < 20ms"
+	Metric    string  `json:"metric"`    // 规范化的指标名称
+	Operator  string  `json:"operator"`  // 比较符
+	Threshold float64 `json:"threshold"` // 阈值，延迟单位为ns，比率单位为%
+	Actual    float64 `json:"actual"`    // 实测值，单位同Threshold
+	Passed    bool    `json:"passed"`
+	Message   string  `json:"message"`
+}
+
+// Evaluate 根据最终核心指标判定规则
+// 未记录任何操作时所有规则均判定为失败，避免空跑被当作达标
+func (r SLARule) Evaluate(core CoreMetrics) SLAAssertion {
+	assertion := SLAAssertion{
+		Name:      r.String(),
+		Metric:    r.Metric,
+		Operator:  r.Operator,
+		Threshold: r.Threshold,
+	}
+
+	if core.Operations.Total == 0 {
+		assertion.Message = "no operations recorded"
+		return assertion
+	}
+
+	assertion.Actual = slaActual(r.Metric, core)
+	switch r.Operator {
+	case "<":
+		assertion.Passed = assertion.Actual < r.Threshold
+	case "<=":
+		assertion.Passed = assertion.Actual <= r.Threshold
+	case ">":
+		assertion.Passed = assertion.Actual > r.Threshold
+	case ">=":
+		assertion.Passed = assertion.Actual >= r.Threshold
+	}
+	assertion.Message = fmt.Sprintf("%s = %s, expected %s %s", r.Metric, formatSLAValue(r.Metric, assertion.Actual), r.Operator, r.raw)
+	return assertion
+}
+
+// slaActual 从核心指标中取出规则对应的实测值
+func slaActual(metric string, core CoreMetrics) float64 {
+	switch metric {
+	case SLAMetricP50:
+		return float64(core.Latency.P50)
+	case SLAMetricP90:
+		return float64(core.Latency.P90)
+	case SLAMetricP95:
+		return float64(core.Latency.P95)
+	case SLAMetricP99:
+		return float64(core.Latency.P99)
+	case SLAMetricP999:
+		return float64(core.Latency.P999)
+	case SLAMetricAvg:
+		return float64(core.Latency.Average)
+	case SLAMetricMax:
+		return float64(core.Latency.Max)
+	case SLAMetricRPS:
+		return core.Throughput.RPS
+	case SLAMetricErrorRate:
+		return float64(core.Operations.Failed) / float64(core.Operations.Total) * 100
+	case SLAMetricSuccessRate:
+		return float64(core.Operations.Success) / float64(core.Operations.Total) * 100
+	}
+	return 0
+}
+
+// formatSLAValue 按指标类型格式化实测值
+func formatSLAValue(metric string, value float64) string {
+	switch metric {
+	case SLAMetricRPS:
+		return fmt.Sprintf("%.2f ops/sec", value)
+	case SLAMetricErrorRate, SLAMetricSuccessRate:
+		return fmt.Sprintf("%.4f%%", value)
+	}
+	return time.Duration(value).Round(time.Microsecond).String()
+}
+
+// EvaluateSLA 根据最终快照的核心指标判定所有SLA规则
+func EvaluateSLA(core CoreMetrics, rules []SLARule) []SLAAssertion {
+	assertions := make([]SLAAssertion, 0, len(rules))
+	for _, rule := range rules {
+		assertions = append(assertions, rule.Evaluate(core))
+	}
+	return assertions
+}
+
+// SLAFailures 统计未通过的SLA断言数量
+func SLAFailures(assertions []SLAAssertion) int {
+	failures := 0
+	for _, assertion := range assertions {
+		if !assertion.Passed {
+			failures++
+		}
+	}
+	return failures
+}
@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSLARule(t *testing.T) {
+	tests := []struct {
+		expr      string
+		name      string
+		threshold float64
+	}{
+		{"p99 < 20ms", "p99 < 20ms", float64(20 * time.Millisecond)},
+		{"P99.9<=1s", "p999 <= 1s", float64(time.Second)},
+		{"error_rate < 1%", "error_rate < 1%", 1},
+		{"success_rate >= 99.9", "success_rate >= 99.9%", 99.9},
+		{"throughput > 5000", "rps > 5000", 5000},
+	}
+	for _, test := range tests {
+		rule, err := ParseSLARule(test.expr)
+		if err != nil {
+			t.Errorf("ParseSLARule(%q): %v", test.expr, err)
+			continue
+		}
+		if rule.String() != test.name || rule.Threshold != test.threshold {
+			t.Errorf("ParseSLARule(%q) = %q (%v), want %q (%v)", test.expr, rule, rule.Threshold, test.name, test.threshold)
+		}
+	}
+
+	for _, expr := range []string{"", "p99", "p99 = 20ms", "p99 < 20", "latency < 1ms", "rps > fast", "error_rate < 150%"} {
+		if _, err := ParseSLARule(expr); err == nil {
+			t.Errorf("ParseSLARule(%q) succeeded, want error", expr)
+		}
+	}
+}
+
+func TestEvaluateSLA(t *testing.T) {
+	var core CoreMetrics
+	core.Operations.Total = 10000
+	core.Operations.Success = 9950
+	core.Operations.Failed = 50
+	core.Throughput.RPS = 950
+	core.Latency.P99 = 40 * time.Millisecond
+
+	rules, err := ParseSLARules([]string{"p99 < 50ms", "rps > 1000", "error_rate <= 0.5%", "success_rate > 99.5%"})
+	if err != nil {
+		t.Fatalf("ParseSLARules: %v", err)
+	}
+	assertions := EvaluateSLA(core, rules)
+
+	want := []bool{true, false, true, false}
+	for i, assertion := range assertions {
+		if assertion.Passed != want[i] {
+			t.Errorf("%s passed = %v, want %v (%s)", assertion.Name, assertion.Passed, want[i], assertion.Message)
+		}
+	}
+	if failures := SLAFailures(assertions); failures != 2 {
+		t.Errorf("failures = %d, want 2", failures)
+	}
+
+	// 未记录任何操作时规则一律失败
+	if assertions := EvaluateSLA(CoreMetrics{}, rules[:1]); assertions[0].Passed {
+		t.Errorf("empty run passed %s", assertions[0].Name)
+	}
+}
@@ -0,0 +1,309 @@
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+)
+
+// sampleRecordSize 单个原始样本在分段文件中的定长编码大小
+// 布局（小端）：开始时间UnixNano int64 | 延迟ns int64 | 状态码 int32 | 操作类型编号 uint16 | 标志 uint8 | 保留 uint8 |
+// 请求ID [32]byte（不足补0）
+const sampleRecordSize = 56
+
+// maxSampleRequestID 记录中请求ID的最大长度，超出部分截断
+const maxSampleRequestID = 32
+
+// DefaultSpoolSegmentSamples 每个分段文件容纳的样本数（56MiB）
+const DefaultSpoolSegmentSamples = 1 << 20
+
+// maxSpoolOperations 操作类型字典上限，超出的操作类型记为other
+const maxSpoolOperations = math.MaxUint16
+
+// 样本标志位
+const (
+	sampleFlagSuccess = 1 << iota
+	sampleFlagRead
+)
+
+// rawSampleHeader 导出CSV的表头
+const rawSampleHeader = "start_unix_nano,operation_type,latency_ns,success,is_read,status_code,request_id\n"
+
+// SpoolSummary 原始样本导出结果
+type SpoolSummary struct {
+	Samples  int64 // 写入的样本数
+	Dropped  int64 // 导出失败或关闭后到达而未写入的样本数
+	Segments int   // 使用的分段文件数
+	Bytes    int64 // 压缩后的导出文件大小
+}
+
+// spoolSegment 一个内存映射的分段文件
+type spoolSegment struct {
+	file  *os.File
+	data  []byte
+	count int
+	names []string // 写满时的操作类型字典，字典只追加，分段中的编号都小于其长度
+}
+
+// SampleSpool 原始样本导出
+// 每个操作结果编码为定长记录写入内存映射的分段文件，分段写满后交给后台协程转换为CSV并gzip压缩追加到导出文件，
+// 随后解除映射并删除分段。样本不在堆上缓冲，运行期间内存占用与样本数无关；压缩跟不上时分段暂存在磁盘上
+type SampleSpool struct {
+	path            string
+	dir             string
+	segmentCapacity int
+
+	mutex      sync.Mutex
+	active     *spoolSegment
+	segments   int
+	operations map[string]uint16
+	names      []string
+	samples    int64
+	dropped    int64
+	closed     bool
+
+	queue chan *spoolSegment
+	done  chan struct{}
+	err   error // 后台压缩的错误，仅在done关闭后读取
+
+	output *os.File
+}
+
+// NewSampleSpool 创建原始样本导出，导出为gzip压缩的CSV文件path
+// 分段文件位于path所在目录下的临时目录中，segmentSamples<=0时使用默认分段大小
+func NewSampleSpool(path string, segmentSamples int) (*SampleSpool, error) {
+	if segmentSamples <= 0 {
+		segmentSamples = DefaultSpoolSegmentSamples
+	}
+	output, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create raw sample file: %w", err)
+	}
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".abc-spool-*")
+	if err != nil {
+		output.Close()
+		return nil, fmt.Errorf("failed to create spool directory: %w", err)
+	}
+
+	s := &SampleSpool{
+		path:            path,
+		dir:             dir,
+		segmentCapacity: segmentSamples,
+		operations:      make(map[string]uint16),
+		names:           []string{"other"},
+		// 排队的分段位于磁盘上，队列容量只限制待压缩的分段数
+		queue:  make(chan *spoolSegment, 4096),
+		done:   make(chan struct{}),
+		output: output,
+	}
+	go s.compress()
+	return s, nil
+}
+
+// Path 导出文件路径
+func (s *SampleSpool) Path() string {
+	return s.path
+}
+
+// Observe 记录单个操作结果
+func (s *SampleSpool) Observe(result *interfaces.OperationResult) {
+	if result == nil {
+		return
+	}
+	end := time.Now()
+
+	var record [sampleRecordSize]byte
+	binary.LittleEndian.PutUint64(record[0:], uint64(end.Add(-result.Duration).UnixNano()))
+	binary.LittleEndian.PutUint64(record[8:], uint64(result.Duration))
+	if code, ok := result.Metadata["status_code"].(int); ok {
+		binary.LittleEndian.PutUint32(record[16:], uint32(int32(code)))
+	}
+	if result.Success {
+		record[22] |= sampleFlagSuccess
+	}
+	if result.IsRead {
+		record[22] |= sampleFlagRead
+	}
+	if requestID, ok := result.Metadata["request_id"].(string); ok {
+		copy(record[24:24+maxSampleRequestID], requestID)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		s.dropped++
+		return
+	}
+	binary.LittleEndian.PutUint16(record[20:], s.operationID(operationLabel(result)))
+
+	if s.active == nil {
+		segment, err := s.newSegment()
+		if err != nil {
+			s.dropped++
+			return
+		}
+		s.active = segment
+	}
+	copy(s.active.data[s.active.count*sampleRecordSize:], record[:])
+	s.active.count++
+	s.samples++
+	if s.active.count == s.segmentCapacity {
+		s.seal()
+	}
+}
+
+// seal 将当前分段交给后台压缩，队列已满时阻塞写入方
+func (s *SampleSpool) seal() {
+	s.active.names = s.names
+	s.queue <- s.active
+	s.active = nil
+}
+
+// operationID 操作类型在字典中的编号，0为字典已满时的other
+func (s *SampleSpool) operationID(name string) uint16 {
+	if id, ok := s.operations[name]; ok {
+		return id
+	}
+	if len(s.names) > maxSpoolOperations {
+		return 0
+	}
+	id := uint16(len(s.names))
+	s.operations[name] = id
+	s.names = append(s.names, name)
+	return id
+}
+
+// newSegment 创建并映射一个新的分段文件
+func (s *SampleSpool) newSegment() (*spoolSegment, error) {
+	s.segments++
+	file, err := os.Create(filepath.Join(s.dir, fmt.Sprintf("segment-%06d.bin", s.segments)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create spool segment: %w", err)
+	}
+	data, err := mapSegment(file, s.segmentCapacity*sampleRecordSize)
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, fmt.Errorf("failed to map spool segment: %w", err)
+	}
+	return &spoolSegment{file: file, data: data}, nil
+}
+
+// compress 后台按顺序将写满的分段转换为CSV并压缩写入导出文件
+func (s *SampleSpool) compress() {
+	defer close(s.done)
+
+	buffered := bufio.NewWriterSize(s.output, 256<<10)
+	writer, _ := gzip.NewWriterLevel(buffered, gzip.BestSpeed)
+	lines := bufio.NewWriterSize(writer, 256<<10)
+	_, s.err = lines.WriteString(rawSampleHeader)
+
+	line := make([]byte, 0, 128)
+	for segment := range s.queue {
+		if s.err == nil {
+			names := segment.names
+			for i := 0; i < segment.count && s.err == nil; i++ {
+				record := segment.data[i*sampleRecordSize : (i+1)*sampleRecordSize]
+				flags := record[22]
+				line = strconv.AppendInt(line[:0], int64(binary.LittleEndian.Uint64(record[0:])), 10)
+				line = append(line, ',')
+				line = appendCSVField(line, names[binary.LittleEndian.Uint16(record[20:])])
+				line = append(line, ',')
+				line = strconv.AppendInt(line, int64(binary.LittleEndian.Uint64(record[8:])), 10)
+				line = append(line, ',')
+				line = strconv.AppendBool(line, flags&sampleFlagSuccess != 0)
+				line = append(line, ',')
+				line = strconv.AppendBool(line, flags&sampleFlagRead != 0)
+				line = append(line, ',')
+				line = strconv.AppendInt(line, int64(int32(binary.LittleEndian.Uint32(record[16:]))), 10)
+				line = append(line, ',')
+				line = appendCSVField(line, requestIDField(record[24:24+maxSampleRequestID]))
+				line = append(line, '\n')
+				_, s.err = lines.Write(line)
+			}
+		}
+		s.releaseSegment(segment)
+	}
+
+	for _, err := range []error{lines.Flush(), writer.Close(), buffered.Flush(), s.output.Sync(), s.output.Close()} {
+		if s.err == nil && err != nil {
+			s.err = err
+		}
+	}
+}
+
+// requestIDField 去掉请求ID字段末尾的填充
+func requestIDField(field []byte) string {
+	if end := bytes.IndexByte(field, 0); end >= 0 {
+		field = field[:end]
+	}
+	return string(field)
+}
+
+// appendCSVField 追加CSV字段，含逗号、引号或换行时按RFC 4180加引号
+func appendCSVField(line []byte, field string) []byte {
+	if !strings.ContainsAny(field, ",\"\r\n") {
+		return append(line, field...)
+	}
+	line = append(line, '"')
+	line = append(line, strings.ReplaceAll(field, `"`, `""`)...)
+	return append(line, '"')
+}
+
+// releaseSegment 解除映射并删除分段文件
+func (s *SampleSpool) releaseSegment(segment *spoolSegment) {
+	unmapSegment(segment.data)
+	segment.file.Close()
+	os.Remove(segment.file.Name())
+}
+
+// Close 写入剩余样本，等待后台压缩完成并删除临时目录
+func (s *SampleSpool) Close() (SpoolSummary, error) {
+	s.mutex.Lock()
+	if s.closed {
+		s.mutex.Unlock()
+		<-s.done
+		return s.summary(), s.err
+	}
+	s.closed = true
+	if s.active != nil {
+		s.seal()
+	}
+	close(s.queue)
+	s.mutex.Unlock()
+
+	<-s.done
+	os.RemoveAll(s.dir)
+	if s.err != nil {
+		return s.summary(), fmt.Errorf("failed to write raw samples: %w", s.err)
+	}
+	return s.summary(), nil
+}
+
+// summary 导出结果汇总，须在后台压缩完成后调用
+func (s *SampleSpool) summary() SpoolSummary {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	summary := SpoolSummary{Samples: s.samples, Dropped: s.dropped, Segments: s.segments}
+	if s.err != nil {
+		summary.Dropped += summary.Samples
+		summary.Samples = 0
+	}
+	if info, err := os.Stat(s.path); err == nil {
+		summary.Bytes = info.Size()
+	}
+	return summary
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package metrics
+
+import "os"
+
+// mapSegment 不支持mmap的平台上分段使用堆内存，压缩完成后释放
+func mapSegment(file *os.File, size int) ([]byte, error) {
+	return make([]byte, size), nil
+}
+
+// unmapSegment 堆内存分段由GC回收
+func unmapSegment(data []byte) error {
+	return nil
+}
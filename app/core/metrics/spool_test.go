@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+)
+
+func TestSampleSpool(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "samples.csv.gz")
+	spool, err := NewSampleSpool(path, 100)
+	if err != nil {
+		t.Fatalf("failed to create spool: %v", err)
+	}
+
+	collector := NewBaseCollector(DefaultMetricsConfig(), map[string]interface{}{})
+	defer collector.Stop()
+	collector.AddObserver(spool)
+
+	// 8个协程各记录130个结果，跨越多个分段
+	var wg sync.WaitGroup
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := 0; i < 130; i++ {
+				collector.Record(&interfaces.OperationResult{
+					Success:  i%10 != 0,
+					IsRead:   worker%2 == 0,
+					Duration: time.Duration(i+1) * time.Microsecond,
+					Metadata: map[string]interface{}{
+						"operation_type": fmt.Sprintf("op,%d", worker),
+						"status_code":    200,
+						"request_id":     fmt.Sprintf("run-%d-%d", worker, i),
+					},
+				})
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	summary, err := spool.Close()
+	if err != nil {
+		t.Fatalf("failed to close spool: %v", err)
+	}
+	if summary.Samples != 1040 || summary.Segments != 11 || summary.Dropped != 0 || summary.Bytes == 0 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+	spool.Observe(&interfaces.OperationResult{Success: true})
+	if summary, _ := spool.Close(); summary.Dropped != 1 {
+		t.Errorf("expected a sample recorded after Close to be dropped, got %+v", summary)
+	}
+
+	// 分段文件在压缩后删除，只留下导出文件
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("expected only the export file to remain, found %d entries", len(entries))
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open export: %v", err)
+	}
+	defer file.Close()
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("export is not gzip: %v", err)
+	}
+	rows, err := csv.NewReader(reader).ReadAll()
+	if err != nil {
+		t.Fatalf("export is not CSV: %v", err)
+	}
+	if len(rows) != 1041 || rows[0][0] != "start_unix_nano" {
+		t.Fatalf("unexpected export: %d rows, header %v", len(rows), rows[0])
+	}
+
+	perOperation := make(map[string]int)
+	failures := 0
+	for _, row := range rows[1:] {
+		perOperation[row[1]]++
+		if row[3] == "false" {
+			failures++
+		}
+		if row[5] != "200" {
+			t.Fatalf("unexpected status code in %v", row)
+		}
+		if !strings.HasPrefix(row[6], "run-"+strings.TrimPrefix(row[1], "op,")+"-") {
+			t.Fatalf("unexpected request ID in %v", row)
+		}
+	}
+	if len(perOperation) != 8 || perOperation["op,3"] != 130 || failures != 8*13 {
+		t.Errorf("unexpected samples: %v, %d failures", perOperation, failures)
+	}
+}
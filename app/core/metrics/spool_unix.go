@@ -0,0 +1,21 @@
+//go:build unix
+
+package metrics
+
+import (
+	"os"
+	"syscall"
+)
+
+// mapSegment 将分段文件扩展到size字节并以共享方式映射，写入的页由内核回写，可在内存紧张时换出
+func mapSegment(file *os.File, size int) ([]byte, error) {
+	if err := file.Truncate(int64(size)); err != nil {
+		return nil, err
+	}
+	return syscall.Mmap(int(file.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+}
+
+// unmapSegment 解除分段文件的映射
+func unmapSegment(data []byte) error {
+	return syscall.Munmap(data)
+}
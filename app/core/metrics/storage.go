@@ -103,7 +103,7 @@ type TimeWindow struct {
 	buckets        []int64
 	bucketCount    int
 	currentBucket  int64
-	lastUpdate     time.Time
+	lastUpdate     int64 // 上次移动桶的时间（UnixNano），原子访问
 	mutex          sync.RWMutex
 }
 
@@ -119,7 +119,7 @@ func NewTimeWindow(windowSize, updateInterval time.Duration) *TimeWindow {
 		updateInterval: updateInterval,
 		buckets:        make([]int64, bucketCount),
 		bucketCount:    bucketCount,
-		lastUpdate:     time.Now(),
+		lastUpdate:     time.Now().UnixNano(),
 	}
 }
 
@@ -139,8 +139,8 @@ func (tw *TimeWindow) GetRate() float64 {
 	defer tw.mutex.RUnlock()
 	
 	var total int64
-	for _, bucket := range tw.buckets {
-		total += atomic.LoadInt64(&bucket)
+	for i := range tw.buckets {
+		total += atomic.LoadInt64(&tw.buckets[i])
 	}
 	
 	return float64(total) / tw.windowSize.Seconds()
@@ -155,21 +155,22 @@ func (tw *TimeWindow) Reset() {
 		atomic.StoreInt64(&tw.buckets[i], 0)
 	}
 	atomic.StoreInt64(&tw.currentBucket, 0)
-	tw.lastUpdate = time.Now()
+	atomic.StoreInt64(&tw.lastUpdate, time.Now().UnixNano())
 }
 
 // updateBuckets 更新桶位置
 func (tw *TimeWindow) updateBuckets() {
-	now := time.Now()
-	elapsed := now.Sub(tw.lastUpdate)
-	
-	if elapsed < tw.updateInterval {
+	now := time.Now().UnixNano()
+	if time.Duration(now-atomic.LoadInt64(&tw.lastUpdate)) < tw.updateInterval {
 		return
 	}
 	
 	tw.mutex.Lock()
 	defer tw.mutex.Unlock()
 	
+	// 持有锁后重新计算，其他协程可能已完成移动
+	elapsed := time.Duration(now - atomic.LoadInt64(&tw.lastUpdate))
+	
 	// 计算需要移动的桶数
 	bucketsToMove := int(elapsed / tw.updateInterval)
 	if bucketsToMove <= 0 {
@@ -196,7 +197,7 @@ func (tw *TimeWindow) updateBuckets() {
 	newBucket := (atomic.LoadInt64(&tw.currentBucket) + int64(bucketsToMove)) % int64(tw.bucketCount)
 	atomic.StoreInt64(&tw.currentBucket, newBucket)
 	
-	tw.lastUpdate = now
+	atomic.StoreInt64(&tw.lastUpdate, now)
 }
 
 // SystemTracker 系统监控追踪器
@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTimeWindow_ConcurrentRecordAndRate(t *testing.T) {
+	window := NewTimeWindow(time.Second, time.Millisecond)
+
+	// 并发记录与读取时移动桶，-race下不应报告数据竞争
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 500; j++ {
+				window.Record(1)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				window.GetRate()
+			}
+		}()
+	}
+	wg.Wait()
+
+	window.Reset()
+	window.Record(10)
+	if rate := window.GetRate(); rate != 10 {
+		t.Errorf("expected rate 10/s after reset, got %v", rate)
+	}
+}
@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// TargetHotspotRatio 单个目标的P99延迟或错误率达到其余目标中位数的该倍数时标记为热点，
+// 吞吐量低于中位数的该分之一时同样标记
+const TargetHotspotRatio = 2.0
+
+// TargetSummary 多目标运行中单个目标的指标汇总
+type TargetSummary struct {
+	Target     string        `json:"target"`
+	Error      string        `json:"error,omitempty"` // 运行失败时的错误，此时其余字段为零值
+	Operations int64         `json:"operations"`
+	Failed     int64         `json:"failed"`
+	Share      float64       `json:"share"` // 占全部目标操作数的百分比
+	RPS        float64       `json:"rps"`
+	ErrorRate  float64       `json:"error_rate"` // 百分比
+	Average    time.Duration `json:"avg_latency"`
+	P50        time.Duration `json:"p50_latency"`
+	P99        time.Duration `json:"p99_latency"`
+	Max        time.Duration `json:"max_latency"`
+
+	// Hotspot 与其余目标相比延迟、错误率或吞吐量明显偏离，Reasons说明偏离的指标
+	Hotspot bool     `json:"hotspot"`
+	Reasons []string `json:"hotspot_reasons,omitempty"`
+}
+
+// SummarizeTarget 汇总单个目标的快照，snapshot为nil时只记录错误
+func SummarizeTarget(target string, snapshot *DefaultMetricsSnapshot, err error) TargetSummary {
+	summary := TargetSummary{Target: target}
+	if err != nil {
+		summary.Error = err.Error()
+		return summary
+	}
+	if snapshot == nil {
+		return summary
+	}
+
+	core := snapshot.Core
+	summary.Operations = core.Operations.Total
+	summary.Failed = core.Operations.Failed
+	summary.RPS = core.Throughput.RPS
+	if core.Operations.Total > 0 {
+		summary.ErrorRate = float64(core.Operations.Failed) / float64(core.Operations.Total) * 100
+	}
+	summary.Average = core.Latency.Average
+	summary.P50 = core.Latency.P50
+	summary.P99 = core.Latency.P99
+	summary.Max = core.Latency.Max
+	return summary
+}
+
+// CompareTargets 计算各目标的操作占比，并将明显偏离其余目标中位数的目标标记为热点
+// 少于两个成功运行的目标时不标记热点
+func CompareTargets(summaries []TargetSummary) {
+	var total int64
+	var succeeded []int
+	for i, summary := range summaries {
+		if summary.Error == "" {
+			total += summary.Operations
+			succeeded = append(succeeded, i)
+		}
+	}
+
+	for _, i := range succeeded {
+		summary := &summaries[i]
+		if total > 0 {
+			summary.Share = float64(summary.Operations) / float64(total) * 100
+		}
+		if len(succeeded) < 2 {
+			continue
+		}
+
+		// 与其余目标的中位数比较，只有两个目标时即为与另一目标比较
+		var p99s, errorRates, rates []float64
+		for _, j := range succeeded {
+			if j != i {
+				p99s = append(p99s, float64(summaries[j].P99))
+				errorRates = append(errorRates, summaries[j].ErrorRate)
+				rates = append(rates, summaries[j].RPS)
+			}
+		}
+		medianP99, medianErrorRate, medianRPS := median(p99s), median(errorRates), median(rates)
+
+		if medianP99 > 0 && float64(summary.P99) >= medianP99*TargetHotspotRatio {
+			summary.Reasons = append(summary.Reasons,
+				fmt.Sprintf("p99 %v is %.1fx the others' median %v", summary.P99, float64(summary.P99)/medianP99, time.Duration(medianP99)))
+		}
+		// 其余目标无错误时，错误率达到1%即视为偏离
+		if summary.ErrorRate >= 1 && summary.ErrorRate >= medianErrorRate*TargetHotspotRatio {
+			summary.Reasons = append(summary.Reasons,
+				fmt.Sprintf("error rate %.2f%% vs the others' median %.2f%%", summary.ErrorRate, medianErrorRate))
+		}
+		if medianRPS > 0 && summary.RPS*TargetHotspotRatio <= medianRPS {
+			summary.Reasons = append(summary.Reasons,
+				fmt.Sprintf("throughput %.2f ops/sec vs the others' median %.2f", summary.RPS, medianRPS))
+		}
+		summary.Hotspot = len(summary.Reasons) > 0
+	}
+}
+
+// median 中位数，空切片返回0
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// targetSnapshot 构造带指定操作数、失败数、吞吐量与P99的快照
+func targetSnapshot(total, failed int64, rps float64, p99 time.Duration) *DefaultMetricsSnapshot {
+	snapshot := &DefaultMetricsSnapshot{}
+	snapshot.Core.Operations.Total = total
+	snapshot.Core.Operations.Success = total - failed
+	snapshot.Core.Operations.Failed = failed
+	snapshot.Core.Throughput.RPS = rps
+	snapshot.Core.Latency.P50 = p99 / 4
+	snapshot.Core.Latency.P99 = p99
+	return snapshot
+}
+
+func TestCompareTargets(t *testing.T) {
+	summaries := []TargetSummary{
+		SummarizeTarget("shard1", targetSnapshot(1000, 0, 1000, 2*time.Millisecond), nil),
+		SummarizeTarget("shard2", targetSnapshot(1000, 0, 1000, 2*time.Millisecond), nil),
+		SummarizeTarget("shard3", targetSnapshot(1000, 50, 900, 9*time.Millisecond), nil),
+		SummarizeTarget("shard4", targetSnapshot(1000, 0, 1100, 3*time.Millisecond), nil),
+		SummarizeTarget("shard5", nil, errors.New("connection refused")),
+	}
+	CompareTargets(summaries)
+
+	for i, summary := range summaries[:4] {
+		if summary.Share != 25 {
+			t.Errorf("expected %s to have a 25%% share, got %.2f", summary.Target, summary.Share)
+		}
+		if summary.Hotspot != (i == 2) {
+			t.Errorf("unexpected hotspot flag for %s: %v (%v)", summary.Target, summary.Hotspot, summary.Reasons)
+		}
+	}
+	// shard3的P99为其余目标中位数2ms的4.5倍，错误率5%而其余目标无错误
+	if reasons := summaries[2].Reasons; len(reasons) != 2 || summaries[2].ErrorRate != 5 {
+		t.Errorf("expected latency and error rate reasons for shard3, got %v", reasons)
+	}
+	if failed := summaries[4]; failed.Error != "connection refused" || failed.Hotspot || failed.Share != 0 {
+		t.Errorf("unexpected summary for a failed target: %+v", failed)
+	}
+}
+
+func TestCompareTargetsPair(t *testing.T) {
+	// 只有两个目标时与另一目标比较，吞吐量低一半的目标被标记
+	summaries := []TargetSummary{
+		SummarizeTarget("a", targetSnapshot(3000, 0, 3000, time.Millisecond), nil),
+		SummarizeTarget("b", targetSnapshot(1000, 0, 1000, time.Millisecond), nil),
+	}
+	CompareTargets(summaries)
+	if summaries[0].Hotspot || !summaries[1].Hotspot || len(summaries[1].Reasons) != 1 {
+		t.Errorf("expected only b to be flagged for throughput: %+v", summaries)
+	}
+	if summaries[0].Share != 75 {
+		t.Errorf("expected a to have a 75%% share, got %.2f", summaries[0].Share)
+	}
+
+	// 单个目标不标记热点
+	single := []TargetSummary{SummarizeTarget("a", targetSnapshot(10, 10, 1, time.Second), nil)}
+	CompareTargets(single)
+	if single[0].Hotspot || single[0].Share != 100 {
+		t.Errorf("unexpected single target summary: %+v", single[0])
+	}
+}
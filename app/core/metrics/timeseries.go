@@ -0,0 +1,119 @@
+package metrics
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+)
+
+// timeSeriesHdrDigits 时间序列直方图精度，每个采样间隔只需要粗粒度的P95
+const timeSeriesHdrDigits = 2
+
+// timeSeriesBucket 单个采样间隔内的累加器
+type timeSeriesBucket struct {
+	operations int64
+	errors     int64
+	histogram  *HdrHistogram
+}
+
+func newTimeSeriesBucket() *timeSeriesBucket {
+	return &timeSeriesBucket{histogram: NewHdrHistogram(DefaultHdrHighestValue, timeSeriesHdrDigits)}
+}
+
+// TimeSeriesTracker 时间序列追踪器
+// 记录路径无锁：结果写入当前累加器，采样时换入新的累加器并由旧累加器生成采样点；
+// 旧累加器不会被原地重置复用，交换前已取得它的写入者不会与重置交错而破坏直方图
+type TimeSeriesTracker struct {
+	config    TimeSeriesConfig
+	current   atomic.Pointer[timeSeriesBucket]
+	points    *RingBuffer[TimeSeriesPoint]
+	startTime time.Time
+	lastTick  time.Time
+	mutex     sync.Mutex
+}
+
+// NewTimeSeriesTracker 创建时间序列追踪器
+func NewTimeSeriesTracker(config TimeSeriesConfig, startTime time.Time) *TimeSeriesTracker {
+	if config.Interval <= 0 {
+		config.Interval = time.Second
+	}
+	if config.MaxPoints <= 0 {
+		config.MaxPoints = 3600
+	}
+
+	tracker := &TimeSeriesTracker{
+		config:    config,
+		points:    NewRingBuffer[TimeSeriesPoint](config.MaxPoints),
+		startTime: startTime,
+		lastTick:  startTime,
+	}
+	tracker.current.Store(newTimeSeriesBucket())
+	return tracker
+}
+
+// Record 记录操作结果
+func (tt *TimeSeriesTracker) Record(result *interfaces.OperationResult) {
+	bucket := tt.current.Load()
+	atomic.AddInt64(&bucket.operations, 1)
+	if !result.Success {
+		atomic.AddInt64(&bucket.errors, 1)
+	}
+	bucket.histogram.Record(int64(result.Duration))
+}
+
+// Sample 结束当前采样间隔并记录采样点
+func (tt *TimeSeriesTracker) Sample(now time.Time) {
+	tt.mutex.Lock()
+	defer tt.mutex.Unlock()
+
+	bucket := tt.current.Swap(newTimeSeriesBucket())
+	tt.points.Push(tt.pointFrom(bucket, now))
+	tt.lastTick = now
+}
+
+// Points 获取所有采样点，包含尚未结束的当前间隔
+func (tt *TimeSeriesTracker) Points() []TimeSeriesPoint {
+	tt.mutex.Lock()
+	defer tt.mutex.Unlock()
+
+	points := tt.points.ToSlice()
+	bucket := tt.current.Load()
+	if atomic.LoadInt64(&bucket.operations) > 0 {
+		points = append(points, tt.pointFrom(bucket, time.Now()))
+	}
+	return points
+}
+
+// Reset 清空采样点并重新开始计时
+func (tt *TimeSeriesTracker) Reset(startTime time.Time) {
+	tt.mutex.Lock()
+	defer tt.mutex.Unlock()
+
+	tt.current.Store(newTimeSeriesBucket())
+	tt.points.Clear()
+	tt.startTime = startTime
+	tt.lastTick = startTime
+}
+
+// pointFrom 根据累加器生成采样点，调用方需持有锁
+func (tt *TimeSeriesTracker) pointFrom(bucket *timeSeriesBucket, now time.Time) TimeSeriesPoint {
+	operations := atomic.LoadInt64(&bucket.operations)
+	errors := atomic.LoadInt64(&bucket.errors)
+
+	point := TimeSeriesPoint{
+		Timestamp:  now,
+		Elapsed:    now.Sub(tt.startTime),
+		Operations: operations,
+		Errors:     errors,
+	}
+	if interval := now.Sub(tt.lastTick); interval > 0 {
+		point.RPS = float64(operations) / interval.Seconds()
+	}
+	if operations > 0 {
+		point.ErrorRate = float64(errors) / float64(operations) * 100
+		point.P95 = time.Duration(bucket.histogram.ValueAtQuantile(95))
+	}
+	return point
+}
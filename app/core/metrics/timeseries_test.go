@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"abc-runner/app/core/interfaces"
+)
+
+func TestTimeSeriesTracker_Sample(t *testing.T) {
+	start := time.Now()
+	tracker := NewTimeSeriesTracker(TimeSeriesConfig{Enabled: true, Interval: time.Second, MaxPoints: 2}, start)
+
+	// 第1秒: 100个操作，其中10个失败
+	for i := 0; i < 100; i++ {
+		tracker.Record(&interfaces.OperationResult{Success: i%10 != 0, Duration: time.Duration(i+1) * time.Millisecond})
+	}
+	tracker.Sample(start.Add(time.Second))
+
+	// 第2秒: 空闲；第3秒: 50个操作
+	tracker.Sample(start.Add(2 * time.Second))
+	for i := 0; i < 50; i++ {
+		tracker.Record(&interfaces.OperationResult{Success: true, Duration: time.Millisecond})
+	}
+	tracker.Sample(start.Add(3 * time.Second))
+
+	points := tracker.Points()
+	if len(points) != 2 {
+		t.Fatalf("expected MaxPoints to cap series at 2, got %d", len(points))
+	}
+	if points[0].Operations != 0 || points[0].RPS != 0 || points[0].Elapsed != 2*time.Second {
+		t.Errorf("unexpected idle point: %+v", points[0])
+	}
+	if points[1].RPS != 50 || points[1].ErrorRate != 0 || points[1].Elapsed != 3*time.Second {
+		t.Errorf("unexpected point: %+v", points[1])
+	}
+
+	tracker.Reset(start)
+	for i := 0; i < 100; i++ {
+		tracker.Record(&interfaces.OperationResult{Success: i%10 != 0, Duration: time.Duration(i+1) * time.Millisecond})
+	}
+	tracker.Sample(start.Add(time.Second))
+	points = tracker.Points()
+	if len(points) != 1 {
+		t.Fatalf("expected 1 point after reset, got %d", len(points))
+	}
+	p := points[0]
+	if p.Operations != 100 || p.Errors != 10 || p.RPS != 100 || p.ErrorRate != 10 {
+		t.Errorf("unexpected point: %+v", p)
+	}
+	if p.P95 < 94*time.Millisecond || p.P95 > 96*time.Millisecond {
+		t.Errorf("expected P95 ~95ms, got %v", p.P95)
+	}
+}
+
+func TestTimeSeriesTracker_ConcurrentSample(t *testing.T) {
+	start := time.Now()
+	tracker := NewTimeSeriesTracker(TimeSeriesConfig{Enabled: true, Interval: time.Second, MaxPoints: 1000}, start)
+
+	// 采样与记录并发进行，交换前取得旧累加器的写入者不能使采样点的P95越界
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					tracker.Record(&interfaces.OperationResult{Success: true, Duration: time.Millisecond})
+				}
+			}
+		}()
+	}
+	for i := 1; i <= 500; i++ {
+		tracker.Sample(start.Add(time.Duration(i) * time.Millisecond))
+	}
+	close(stop)
+	wg.Wait()
+
+	for _, point := range tracker.Points() {
+		if point.P95 > 2*time.Millisecond {
+			t.Fatalf("unexpected P95 after concurrent sampling: %+v", point)
+		}
+	}
+}
+
+func TestBaseCollector_SnapshotIncludesInProgressInterval(t *testing.T) {
+	collector := NewBaseCollector(DefaultMetricsConfig(), map[string]interface{}{})
+	defer collector.Stop()
+
+	collector.Record(&interfaces.OperationResult{Success: true, Duration: time.Millisecond})
+	series := collector.Snapshot().TimeSeries
+	if len(series) == 0 || series[len(series)-1].Operations != 1 {
+		t.Errorf("expected in-progress interval in snapshot, got %+v", series)
+	}
+}
@@ -0,0 +1,61 @@
+// Package transport 协议适配器可选的网络传输层
+// 默认使用Go标准库的网络栈（netpoller），实验性的io_uring传输在Linux上以-tags uring构建时可用
+package transport
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 传输类型
+const (
+	Net     = "net"      // Go标准库网络栈（默认）
+	IOURing = "io_uring" // io_uring批量提交与收割（实验性，仅Linux，需-tags uring构建）
+)
+
+// DefaultRingEntries io_uring提交队列的默认长度
+const DefaultRingEntries = 1024
+
+// ErrIOURingUnsupported 当前构建不包含io_uring传输
+var ErrIOURingUnsupported = errors.New("the io_uring transport requires a Linux build with -tags uring")
+
+// Validate 检查传输类型，空值表示默认的net
+func Validate(kind string) error {
+	switch kind {
+	case "", Net:
+		return nil
+	case IOURing:
+		if !IOURingSupported {
+			return ErrIOURingUnsupported
+		}
+		return nil
+	}
+	return fmt.Errorf("invalid transport %q (expected %s or %s)", kind, Net, IOURing)
+}
+
+// Stats io_uring传输的统计，用于观察每次系统调用批量处理的操作数
+type Stats struct {
+	Connections int64 // 经io_uring收发的连接数
+	Operations  int64 // 提交的收发操作数
+	SubmitCalls int64 // 提交操作的io_uring_enter调用次数
+	Completions int64 // 收割的完成事件数（不含链接的超时事件）
+	ReapCalls   int64 // 等待完成事件的io_uring_enter调用次数
+}
+
+// Map 转换为协议指标
+func (s Stats) Map() map[string]interface{} {
+	metrics := map[string]interface{}{
+		"connections":  s.Connections,
+		"operations":   s.Operations,
+		"submit_calls": s.SubmitCalls,
+		"completions":  s.Completions,
+		"reap_calls":   s.ReapCalls,
+	}
+	if s.SubmitCalls > 0 {
+		metrics["ops_per_submit"] = float64(s.Operations) / float64(s.SubmitCalls)
+	}
+	if s.ReapCalls > 0 {
+		metrics["completions_per_reap"] = float64(s.Completions) / float64(s.ReapCalls)
+	}
+	return metrics
+}
@@ -0,0 +1,27 @@
+package transport
+
+import "testing"
+
+func TestValidate(t *testing.T) {
+	for _, kind := range []string{"", Net} {
+		if err := Validate(kind); err != nil {
+			t.Errorf("Validate(%q): %v", kind, err)
+		}
+	}
+	if err := Validate(IOURing); (err == nil) != IOURingSupported {
+		t.Errorf("Validate(%q) = %v with IOURingSupported=%v", IOURing, err, IOURingSupported)
+	}
+	if err := Validate("dpdk"); err == nil {
+		t.Error("expected an error for an unknown transport")
+	}
+}
+
+func TestStatsMap(t *testing.T) {
+	metrics := Stats{Operations: 300, SubmitCalls: 100, Completions: 300, ReapCalls: 50}.Map()
+	if metrics["ops_per_submit"] != 3.0 || metrics["completions_per_reap"] != 6.0 {
+		t.Errorf("unexpected batching ratios: %v", metrics)
+	}
+	if _, ok := (Stats{}).Map()["ops_per_submit"]; ok {
+		t.Error("expected no ratio without submit calls")
+	}
+}
@@ -0,0 +1,527 @@
+//go:build linux && uring
+
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// IOURingSupported 当前构建是否包含io_uring传输
+const IOURingSupported = true
+
+// io_uring系统调用、mmap偏移与操作码（include/uapi/linux/io_uring.h）
+const (
+	sysIOURingSetup = 425
+	sysIOURingEnter = 426
+
+	ringOffSQ   = 0
+	ringOffCQ   = 0x8000000
+	ringOffSQEs = 0x10000000
+
+	enterGetEvents = 1 << 0
+	sqeIOLink      = 1 << 2
+
+	opNop         = 0
+	opLinkTimeout = 15
+	opSend        = 26
+	opRecv        = 27
+)
+
+// user_data的保留值：链接的超时事件带timeoutFlag，关闭时的唤醒事件为closeToken
+const (
+	timeoutFlag = uint64(1) << 63
+	closeToken  = timeoutFlag - 1
+)
+
+type sqRingOffsets struct {
+	head, tail, ringMask, ringEntries, flags, dropped, array, resv1 uint32
+	userAddr                                                        uint64
+}
+
+type cqRingOffsets struct {
+	head, tail, ringMask, ringEntries, overflow, cqes, flags, resv1 uint32
+	userAddr                                                        uint64
+}
+
+type ringParams struct {
+	sqEntries, cqEntries, flags, sqThreadCPU, sqThreadIdle, features, wqFD uint32
+	resv                                                                   [3]uint32
+	sqOff                                                                  sqRingOffsets
+	cqOff                                                                  cqRingOffsets
+}
+
+// submissionEntry io_uring_sqe（64字节）
+type submissionEntry struct {
+	opcode      uint8
+	flags       uint8
+	ioprio      uint16
+	fd          int32
+	off         uint64
+	addr        uint64
+	len         uint32
+	opFlags     uint32
+	userData    uint64
+	bufIndex    uint16
+	personality uint16
+	spliceFDIn  int32
+	addr3       uint64
+	_           uint64
+}
+
+// completionEntry io_uring_cqe（16字节）
+type completionEntry struct {
+	userData uint64
+	res      int32
+	flags    uint32
+}
+
+// kernelTimespec __kernel_timespec
+type kernelTimespec struct {
+	sec, nsec int64
+}
+
+// ringOp 一个进行中的收发操作，缓冲区与超时在完成前由pending持有
+type ringOp struct {
+	id      uint64
+	buf     []byte
+	timeout kernelTimespec
+	done    chan int32
+}
+
+// Ring 多个连接共享的io_uring实例
+// 各协程把收发请求写入提交队列后由一次io_uring_enter批量提交（并发提交时后到的请求随先到者一起提交），
+// 一个收割线程阻塞等待并批量取走完成事件后唤醒对应协程；超时通过链接的IORING_OP_LINK_TIMEOUT实现。
+// 建立连接仍使用标准库，只有数据收发经过io_uring
+type Ring struct {
+	fd int
+
+	sqRing, cqRing, sqeMem []byte
+	sqTail                 *uint32
+	sqMask                 uint32
+	sqArray                []uint32
+	sqes                   []submissionEntry
+	cqHead, cqTail         *uint32
+	cqMask                 uint32
+	cqes                   []completionEntry
+
+	mu          sync.Mutex // 保护提交队列尾部、pending与closed
+	submitMu    sync.Mutex // 串行化io_uring_enter提交
+	pending     map[uint64]*ringOp
+	nextID      uint64
+	unsubmitted uint32
+	closed      bool // 不再接受新操作
+	shutdown    bool // Close已提交唤醒收割线程的事件
+
+	slots chan struct{} // 限制进行中的操作数，保证完成队列不溢出
+	done  chan struct{} // 收割线程退出
+
+	stats Stats
+}
+
+// NewRing 创建提交队列长度为entries（内核向上取整为2的幂）的io_uring实例并启动收割线程
+func NewRing(entries uint32) (*Ring, error) {
+	if entries == 0 {
+		entries = DefaultRingEntries
+	}
+	var params ringParams
+	fd, _, errno := syscall.Syscall(sysIOURingSetup, uintptr(entries), uintptr(unsafe.Pointer(&params)), 0)
+	if errno != 0 {
+		return nil, fmt.Errorf("io_uring_setup failed: %w", errno)
+	}
+
+	r := &Ring{fd: int(fd), pending: make(map[uint64]*ringOp), done: make(chan struct{})}
+	if err := r.mmap(&params); err != nil {
+		r.unmap()
+		syscall.Close(r.fd)
+		return nil, err
+	}
+	// 每个操作最多占用两个提交项与两个完成项（收发与链接的超时）
+	r.slots = make(chan struct{}, params.sqEntries/2)
+	go r.reap()
+	return r, nil
+}
+
+// mmap 映射提交队列、完成队列与提交项数组
+func (r *Ring) mmap(params *ringParams) error {
+	var err error
+	prot, flags := syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED|syscall.MAP_POPULATE
+	sqSize := params.sqOff.array + params.sqEntries*4
+	if r.sqRing, err = syscall.Mmap(r.fd, ringOffSQ, int(sqSize), prot, flags); err != nil {
+		return fmt.Errorf("failed to map the io_uring submission queue: %w", err)
+	}
+	cqSize := params.cqOff.cqes + params.cqEntries*uint32(unsafe.Sizeof(completionEntry{}))
+	if r.cqRing, err = syscall.Mmap(r.fd, ringOffCQ, int(cqSize), prot, flags); err != nil {
+		return fmt.Errorf("failed to map the io_uring completion queue: %w", err)
+	}
+	sqeSize := params.sqEntries * uint32(unsafe.Sizeof(submissionEntry{}))
+	if r.sqeMem, err = syscall.Mmap(r.fd, ringOffSQEs, int(sqeSize), prot, flags); err != nil {
+		return fmt.Errorf("failed to map the io_uring submission entries: %w", err)
+	}
+
+	r.sqTail = (*uint32)(unsafe.Pointer(&r.sqRing[params.sqOff.tail]))
+	r.sqMask = *(*uint32)(unsafe.Pointer(&r.sqRing[params.sqOff.ringMask]))
+	r.sqArray = unsafe.Slice((*uint32)(unsafe.Pointer(&r.sqRing[params.sqOff.array])), params.sqEntries)
+	r.sqes = unsafe.Slice((*submissionEntry)(unsafe.Pointer(&r.sqeMem[0])), params.sqEntries)
+	r.cqHead = (*uint32)(unsafe.Pointer(&r.cqRing[params.cqOff.head]))
+	r.cqTail = (*uint32)(unsafe.Pointer(&r.cqRing[params.cqOff.tail]))
+	r.cqMask = *(*uint32)(unsafe.Pointer(&r.cqRing[params.cqOff.ringMask]))
+	r.cqes = unsafe.Slice((*completionEntry)(unsafe.Pointer(&r.cqRing[params.cqOff.cqes])), params.cqEntries)
+	return nil
+}
+
+// unmap 解除映射
+func (r *Ring) unmap() {
+	for _, mem := range [][]byte{r.sqRing, r.cqRing, r.sqeMem} {
+		if mem != nil {
+			syscall.Munmap(mem)
+		}
+	}
+	r.sqRing, r.cqRing, r.sqeMem = nil, nil, nil
+}
+
+// enter 调用io_uring_enter
+func (r *Ring) enter(toSubmit, minComplete, flags uint32) (uint32, error) {
+	n, _, errno := syscall.Syscall6(sysIOURingEnter, uintptr(r.fd), uintptr(toSubmit), uintptr(minComplete), uintptr(flags), 0, 0)
+	if errno != 0 {
+		return 0, errno
+	}
+	return uint32(n), nil
+}
+
+// entry 取提交队列中序号为index的提交项，调用方须持有mu
+func (r *Ring) entry(index uint32) *submissionEntry {
+	slot := index & r.sqMask
+	r.sqArray[slot] = slot
+	return &r.sqes[slot]
+}
+
+// do 提交一个收发操作并等待完成，返回内核的结果（字节数或负的errno）
+// timeout大于0时链接一个超时，到期后操作以-ECANCELED完成
+func (r *Ring) do(opcode uint8, fd int, buf []byte, opFlags uint32, timeout time.Duration) (int32, error) {
+	select {
+	case r.slots <- struct{}{}:
+	case <-r.done:
+		return 0, net.ErrClosed
+	}
+	defer func() { <-r.slots }()
+
+	op := &ringOp{buf: buf, done: make(chan int32, 1)}
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return 0, net.ErrClosed
+	}
+	r.nextID++
+	op.id = r.nextID
+	r.pending[op.id] = op
+
+	tail := atomic.LoadUint32(r.sqTail)
+	entry := r.entry(tail)
+	*entry = submissionEntry{opcode: opcode, fd: int32(fd), len: uint32(len(buf)), opFlags: opFlags, userData: op.id}
+	if len(buf) > 0 {
+		entry.addr = uint64(uintptr(unsafe.Pointer(&buf[0])))
+	}
+	count := uint32(1)
+	if timeout > 0 {
+		entry.flags = sqeIOLink
+		op.timeout = kernelTimespec{sec: int64(timeout / time.Second), nsec: int64(timeout % time.Second)}
+		*r.entry(tail + 1) = submissionEntry{
+			opcode:   opLinkTimeout,
+			fd:       -1,
+			addr:     uint64(uintptr(unsafe.Pointer(&op.timeout))),
+			len:      1,
+			userData: op.id | timeoutFlag,
+		}
+		count = 2
+	}
+	atomic.StoreUint32(r.sqTail, tail+count)
+	r.unsubmitted += count
+	r.mu.Unlock()
+	atomic.AddInt64(&r.stats.Operations, 1)
+
+	if err := r.submit(); err != nil {
+		r.abort()
+		return 0, err
+	}
+	res := <-op.done
+	runtime.KeepAlive(op)
+	return res, nil
+}
+
+// submit 一次提交所有尚未提交的提交项；其他协程已把本协程的提交项一并提交时不做系统调用
+func (r *Ring) submit() error {
+	r.submitMu.Lock()
+	defer r.submitMu.Unlock()
+
+	r.mu.Lock()
+	n := r.unsubmitted
+	r.unsubmitted = 0
+	r.mu.Unlock()
+
+	for n > 0 {
+		submitted, err := r.enter(n, 0, 0)
+		if errors.Is(err, syscall.EINTR) || errors.Is(err, syscall.EAGAIN) || errors.Is(err, syscall.EBUSY) {
+			runtime.Gosched()
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("io_uring_enter failed: %w", err)
+		}
+		atomic.AddInt64(&r.stats.SubmitCalls, 1)
+		n -= submitted
+	}
+	return nil
+}
+
+// reap 收割线程：阻塞等待完成事件，批量取走后唤醒对应的操作
+func (r *Ring) reap() {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	defer close(r.done)
+
+	for {
+		if _, err := r.enter(0, 1, enterGetEvents); err != nil && !errors.Is(err, syscall.EINTR) {
+			r.abort()
+			return
+		}
+		atomic.AddInt64(&r.stats.ReapCalls, 1)
+
+		stop := false
+		head, tail := atomic.LoadUint32(r.cqHead), atomic.LoadUint32(r.cqTail)
+		for ; head != tail; head++ {
+			completion := r.cqes[head&r.cqMask]
+			switch {
+			case completion.userData == closeToken:
+				stop = true
+			case completion.userData&timeoutFlag != 0:
+			default:
+				r.mu.Lock()
+				op := r.pending[completion.userData]
+				delete(r.pending, completion.userData)
+				r.mu.Unlock()
+				if op != nil {
+					atomic.AddInt64(&r.stats.Completions, 1)
+					op.done <- completion.res
+				}
+			}
+		}
+		atomic.StoreUint32(r.cqHead, head)
+		if stop {
+			return
+		}
+	}
+}
+
+// abort 提交或收割失败时结束所有进行中的操作
+func (r *Ring) abort() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	for id, op := range r.pending {
+		op.done <- -int32(syscall.ECANCELED)
+		delete(r.pending, id)
+	}
+}
+
+// Wrap 把已建立的TCP连接改为经io_uring收发，原连接被关闭，套接字由返回的连接持有
+// 读写超时在操作提交时确定，操作进行中修改deadline不影响该操作；Close会立即结束进行中的读
+func (r *Ring) Wrap(conn net.Conn) (net.Conn, error) {
+	tcp, ok := conn.(*net.TCPConn)
+	if !ok {
+		conn.Close()
+		return nil, fmt.Errorf("the io_uring transport supports TCP connections only, got %T", conn)
+	}
+	file, err := tcp.File()
+	local, remote := conn.LocalAddr(), conn.RemoteAddr()
+	conn.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to take over the TCP socket: %w", err)
+	}
+	// Fd把复制的套接字切换为阻塞模式，由io_uring在内核中等待可读写
+	fd := int(file.Fd())
+	atomic.AddInt64(&r.stats.Connections, 1)
+	return &ringConn{ring: r, file: file, fd: fd, local: local, remote: remote}, nil
+}
+
+// Dialer 返回以dialer建立连接、经io_uring收发的拨号函数，可用作http.Transport.DialContext
+func (r *Ring) Dialer(dialer *net.Dialer) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		conn, err := dialer.DialContext(ctx, network, address)
+		if err != nil {
+			return nil, err
+		}
+		return r.Wrap(conn)
+	}
+}
+
+// Stats 获取统计
+func (r *Ring) Stats() Stats {
+	return Stats{
+		Connections: atomic.LoadInt64(&r.stats.Connections),
+		Operations:  atomic.LoadInt64(&r.stats.Operations),
+		SubmitCalls: atomic.LoadInt64(&r.stats.SubmitCalls),
+		Completions: atomic.LoadInt64(&r.stats.Completions),
+		ReapCalls:   atomic.LoadInt64(&r.stats.ReapCalls),
+	}
+}
+
+// Close 停止收割线程并释放io_uring实例，应在关闭其上的所有连接之后调用
+func (r *Ring) Close() error {
+	r.mu.Lock()
+	if r.shutdown {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed, r.shutdown = true, true
+	tail := atomic.LoadUint32(r.sqTail)
+	*r.entry(tail) = submissionEntry{opcode: opNop, userData: closeToken}
+	atomic.StoreUint32(r.sqTail, tail+1)
+	r.unsubmitted++
+	r.mu.Unlock()
+
+	if err := r.submit(); err != nil {
+		// 收割线程无法唤醒，保留映射以免其访问已释放的完成队列
+		return err
+	}
+	<-r.done
+	r.abort()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.unmap()
+	return syscall.Close(r.fd)
+}
+
+// ringConn 经io_uring收发的TCP连接
+type ringConn struct {
+	ring          *Ring
+	file          *os.File
+	fd            int
+	local, remote net.Addr
+	readDeadline  atomic.Int64 // Unix纳秒，0表示不超时
+	writeDeadline atomic.Int64
+	closed        atomic.Bool
+}
+
+func (c *ringConn) Read(b []byte) (int, error) {
+	if c.closed.Load() {
+		return 0, c.opError("read", net.ErrClosed)
+	}
+	if len(b) == 0 {
+		return 0, nil
+	}
+	timeout, err := remaining(&c.readDeadline)
+	if err != nil {
+		return 0, c.opError("read", err)
+	}
+	res, err := c.ring.do(opRecv, c.fd, b, 0, timeout)
+	switch {
+	case err != nil:
+		return 0, c.opError("read", err)
+	case c.closed.Load():
+		return 0, c.opError("read", net.ErrClosed)
+	case res > 0:
+		return int(res), nil
+	case res == 0:
+		return 0, io.EOF
+	}
+	return 0, c.opError("read", resultError(res, timeout))
+}
+
+func (c *ringConn) Write(b []byte) (int, error) {
+	if c.closed.Load() {
+		return 0, c.opError("write", net.ErrClosed)
+	}
+	written := 0
+	for written < len(b) {
+		if c.closed.Load() {
+			return written, c.opError("write", net.ErrClosed)
+		}
+		timeout, err := remaining(&c.writeDeadline)
+		if err != nil {
+			return written, c.opError("write", err)
+		}
+		res, err := c.ring.do(opSend, c.fd, b[written:], syscall.MSG_NOSIGNAL, timeout)
+		if err != nil {
+			return written, c.opError("write", err)
+		}
+		if res < 0 {
+			return written, c.opError("write", resultError(res, timeout))
+		}
+		written += int(res)
+	}
+	return written, nil
+}
+
+// Close 关闭连接；先shutdown使进行中的收发立即完成
+func (c *ringConn) Close() error {
+	if !c.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+	syscall.Shutdown(c.fd, syscall.SHUT_RDWR)
+	return c.file.Close()
+}
+
+func (c *ringConn) LocalAddr() net.Addr  { return c.local }
+func (c *ringConn) RemoteAddr() net.Addr { return c.remote }
+
+func (c *ringConn) SetDeadline(t time.Time) error {
+	c.SetReadDeadline(t)
+	return c.SetWriteDeadline(t)
+}
+
+func (c *ringConn) SetReadDeadline(t time.Time) error {
+	c.readDeadline.Store(deadlineNanos(t))
+	return nil
+}
+
+func (c *ringConn) SetWriteDeadline(t time.Time) error {
+	c.writeDeadline.Store(deadlineNanos(t))
+	return nil
+}
+
+// opError 与标准库一致的连接错误
+func (c *ringConn) opError(op string, err error) error {
+	return &net.OpError{Op: op, Net: "tcp", Source: c.local, Addr: c.remote, Err: err}
+}
+
+// deadlineNanos 零值时间表示不超时
+func deadlineNanos(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// remaining 距deadline的剩余时间，未设置时为0，已过期时返回os.ErrDeadlineExceeded
+func remaining(deadline *atomic.Int64) (time.Duration, error) {
+	nanos := deadline.Load()
+	if nanos == 0 {
+		return 0, nil
+	}
+	left := time.Until(time.Unix(0, nanos))
+	if left <= 0 {
+		return 0, os.ErrDeadlineExceeded
+	}
+	return left, nil
+}
+
+// resultError 负的内核结果对应的错误；链接的超时到期取消操作时为os.ErrDeadlineExceeded
+func resultError(res int32, timeout time.Duration) error {
+	errno := syscall.Errno(-res)
+	if errno == syscall.ECANCELED && timeout > 0 {
+		return os.ErrDeadlineExceeded
+	}
+	return errno
+}
@@ -0,0 +1,173 @@
+//go:build linux && uring
+
+package transport
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// startEchoServer 启动TCP回显服务
+func startEchoServer(t *testing.T) net.Listener {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+	return listener
+}
+
+func TestRing_Echo(t *testing.T) {
+	listener := startEchoServer(t)
+	ring, err := NewRing(64)
+	if err != nil {
+		t.Fatalf("NewRing: %v", err)
+	}
+
+	dial := ring.Dialer(&net.Dialer{Timeout: time.Second})
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for c := 0; c < 16; c++ {
+		wg.Add(1)
+		go func(c int) {
+			defer wg.Done()
+			conn, err := dial(t.Context(), "tcp", listener.Addr().String())
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer conn.Close()
+			conn.SetDeadline(time.Now().Add(5 * time.Second))
+			for i := 0; i < 200; i++ {
+				message := []byte(fmt.Sprintf("conn-%d-message-%d", c, i))
+				if _, err := conn.Write(message); err != nil {
+					errs <- err
+					return
+				}
+				reply := make([]byte, len(message))
+				if _, err := io.ReadFull(conn, reply); err != nil {
+					errs <- err
+					return
+				}
+				if !bytes.Equal(reply, message) {
+					errs <- fmt.Errorf("expected %q, got %q", message, reply)
+					return
+				}
+			}
+		}(c)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	stats := ring.Stats()
+	if stats.Connections != 16 || stats.Completions < 16*200*2 || stats.SubmitCalls == 0 || stats.ReapCalls == 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if err := ring.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+	if err := ring.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+}
+
+func TestRing_DeadlineAndClose(t *testing.T) {
+	listener := startEchoServer(t)
+	ring, err := NewRing(16)
+	if err != nil {
+		t.Fatalf("NewRing: %v", err)
+	}
+	defer ring.Close()
+
+	conn, err := ring.Dialer(&net.Dialer{})(t.Context(), "tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 没有数据可读时按读超时结束
+	conn.SetReadDeadline(time.Now().Add(50 * time.Millisecond))
+	start := time.Now()
+	_, err = conn.Read(make([]byte, 8))
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() || !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("expected a timeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond || elapsed > time.Second {
+		t.Errorf("timeout fired after %v", elapsed)
+	}
+
+	// 关闭连接立即结束进行中的读
+	conn.SetReadDeadline(time.Time{})
+	result := make(chan error, 1)
+	go func() {
+		_, err := conn.Read(make([]byte, 8))
+		result <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	conn.Close()
+	select {
+	case err := <-result:
+		if !errors.Is(err, net.ErrClosed) {
+			t.Errorf("expected net.ErrClosed, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close did not interrupt the pending read")
+	}
+}
+
+func TestRing_HTTPTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Write(append([]byte("echo:"), body...))
+	}))
+	defer server.Close()
+
+	ring, err := NewRing(0)
+	if err != nil {
+		t.Fatalf("NewRing: %v", err)
+	}
+	transport := &http.Transport{DialContext: ring.Dialer(&net.Dialer{})}
+	client := &http.Client{Transport: transport, Timeout: 5 * time.Second}
+	for i := 0; i < 50; i++ {
+		resp, err := client.Post(server.URL, "text/plain", bytes.NewBufferString(fmt.Sprint(i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if string(body) != fmt.Sprintf("echo:%d", i) {
+			t.Fatalf("unexpected body %q", body)
+		}
+	}
+	transport.CloseIdleConnections()
+	if stats := ring.Stats(); stats.Connections != 1 {
+		t.Errorf("expected keep-alive to reuse one connection, got %d", stats.Connections)
+	}
+	if err := ring.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+//go:build !linux || !uring
+
+package transport
+
+import (
+	"context"
+	"net"
+)
+
+// IOURingSupported 当前构建是否包含io_uring传输
+const IOURingSupported = false
+
+// Ring 未包含io_uring传输的构建中的占位类型
+type Ring struct{}
+
+// NewRing 当前构建不支持io_uring
+func NewRing(entries uint32) (*Ring, error) {
+	return nil, ErrIOURingUnsupported
+}
+
+// Wrap 当前构建不支持io_uring
+func (r *Ring) Wrap(conn net.Conn) (net.Conn, error) {
+	return nil, ErrIOURingUnsupported
+}
+
+// Dialer 当前构建不支持io_uring，返回的拨号函数总是失败
+func (r *Ring) Dialer(dialer *net.Dialer) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		return nil, ErrIOURingUnsupported
+	}
+}
+
+// Stats 当前构建不支持io_uring
+func (r *Ring) Stats() Stats {
+	return Stats{}
+}
+
+// Close 当前构建不支持io_uring
+func (r *Ring) Close() error {
+	return nil
+}
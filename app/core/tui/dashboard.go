@@ -0,0 +1,312 @@
+// Package tui 基准测试运行期间的实时终端面板
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/metrics"
+)
+
+// DefaultInterval 默认刷新间隔
+const DefaultInterval = time.Second
+
+// 面板布局
+const (
+	progressWidth  = 30 // 进度条宽度
+	sparklineWidth = 40 // 吞吐量走势保留的采样数
+	histogramWidth = 30 // 直方图柱的最大宽度
+)
+
+// histogramBounds 延迟直方图的分组边界（1-2.5-5序列）
+var histogramBounds = []time.Duration{
+	100 * time.Microsecond, 250 * time.Microsecond, 500 * time.Microsecond,
+	time.Millisecond, 2500 * time.Microsecond, 5 * time.Millisecond,
+	10 * time.Millisecond, 25 * time.Millisecond, 50 * time.Millisecond,
+	100 * time.Millisecond, 250 * time.Millisecond, 500 * time.Millisecond,
+	time.Second, 2500 * time.Millisecond, 5 * time.Second, 10 * time.Second,
+}
+
+// sparkLevels 走势图的字符等级
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// Source 面板的数据来源
+type Source struct {
+	Snapshot  func() *metrics.DefaultMetricsSnapshot // 指标快照
+	Histogram func() *metrics.LatencyHistogram       // 延迟直方图，可为nil
+	Progress  func() execution.Progress              // 执行引擎进度
+}
+
+// Dashboard 实时终端面板
+// 每个刷新间隔从指标快照重绘一帧（光标上移后逐行覆盖，不切换终端屏幕），
+// 执行引擎结束运行后绘制最后一帧并自动停止
+type Dashboard struct {
+	out      io.Writer
+	source   Source
+	interval time.Duration
+
+	lastTotal int64
+	lastTime  time.Time
+	rates     []float64
+	lines     int  // 上一帧的行数
+	seenRun   bool // 是否观察到引擎在运行
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// New 创建面板
+func New(out io.Writer, source Source) *Dashboard {
+	return &Dashboard{
+		out:      out,
+		source:   source,
+		interval: DefaultInterval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start 在后台开始刷新
+func (d *Dashboard) Start() {
+	go d.loop()
+}
+
+// Stop 停止刷新并等待最后一帧绘制完成
+func (d *Dashboard) Stop() {
+	d.stopOnce.Do(func() { close(d.stop) })
+	<-d.done
+}
+
+// loop 刷新循环
+func (d *Dashboard) loop() {
+	defer close(d.done)
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			running := d.source.Progress().Running
+			if running {
+				d.seenRun = true
+			}
+			// 引擎开始运行前不绘制，避免覆盖连接阶段的输出
+			if !d.seenRun {
+				continue
+			}
+			d.draw(now)
+			if !running {
+				return
+			}
+		case <-d.stop:
+			if d.seenRun {
+				d.draw(time.Now())
+			}
+			return
+		}
+	}
+}
+
+// draw 覆盖上一帧绘制新的一帧
+func (d *Dashboard) draw(now time.Time) {
+	frame := d.Render(now)
+	var b strings.Builder
+	if d.lines > 0 {
+		fmt.Fprintf(&b, "\x1b[%dA", d.lines)
+	}
+	for _, line := range frame {
+		b.WriteString("\r\x1b[2K")
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	// 新帧较短时清除上一帧余下的行
+	for i := len(frame); i < d.lines; i++ {
+		b.WriteString("\r\x1b[2K\n")
+	}
+	if extra := d.lines - len(frame); extra > 0 {
+		fmt.Fprintf(&b, "\x1b[%dA", extra)
+	}
+	io.WriteString(d.out, b.String())
+	d.lines = len(frame)
+}
+
+// Render 生成一帧的各行，并更新吞吐量走势
+func (d *Dashboard) Render(now time.Time) []string {
+	progress := d.source.Progress()
+	snapshot := d.source.Snapshot()
+	if snapshot == nil {
+		snapshot = &metrics.DefaultMetricsSnapshot{}
+	}
+	ops := snapshot.Core.Operations
+	latency := snapshot.Core.Latency
+
+	// 以两帧之间的操作增量计算实时吞吐量
+	rate := snapshot.Core.Throughput.RPS
+	if !d.lastTime.IsZero() {
+		if elapsed := now.Sub(d.lastTime).Seconds(); elapsed > 0 {
+			rate = float64(ops.Total-d.lastTotal) / elapsed
+		}
+	}
+	d.lastTotal, d.lastTime = ops.Total, now
+	d.rates = append(d.rates, rate)
+	if len(d.rates) > sparklineWidth {
+		d.rates = d.rates[len(d.rates)-sparklineWidth:]
+	}
+
+	var elapsed time.Duration
+	if !progress.StartTime.IsZero() {
+		elapsed = now.Sub(progress.StartTime)
+	}
+	ratio, eta := estimate(progress, elapsed)
+	state := "running"
+	if !progress.Running {
+		state = "finished"
+	}
+
+	errorRate := 0.0
+	if ops.Total > 0 {
+		errorRate = float64(ops.Failed) / float64(ops.Total) * 100
+	}
+
+	lines := []string{
+		fmt.Sprintf("── abc-runner live ── %s, elapsed %s, ETA %s", state, elapsed.Truncate(time.Second), formatETA(eta, progress.Running)),
+		fmt.Sprintf("Progress    [%s] %5.1f%%  %d ops", bar(ratio), ratio*100, ops.Total),
+		fmt.Sprintf("Throughput  %.0f ops/s  %s", rate, sparkline(d.rates)),
+		fmt.Sprintf("Errors      %d (%.2f%%)", ops.Failed, errorRate),
+		fmt.Sprintf("Latency     avg %s  p50 %s  p95 %s  p99 %s  max %s",
+			formatLatency(latency.Average), formatLatency(latency.P50), formatLatency(latency.P95),
+			formatLatency(latency.P99), formatLatency(latency.Max)),
+		fmt.Sprintf("Workers     %d/%d busy", progress.Busy, progress.Workers),
+	}
+	return append(lines, d.histogram()...)
+}
+
+// estimate 计算完成比例与预计剩余时间，按时长运行时以时长为准，否则按任务数推算
+func estimate(progress execution.Progress, elapsed time.Duration) (float64, time.Duration) {
+	var ratio float64
+	switch {
+	case progress.Duration > 0:
+		ratio = float64(elapsed) / float64(progress.Duration)
+	case progress.Total > 0:
+		ratio = float64(progress.Completed) / float64(progress.Total)
+	}
+	if ratio > 1 {
+		ratio = 1
+	}
+	if ratio <= 0 {
+		return 0, -1
+	}
+	if progress.Duration > 0 {
+		return ratio, progress.Duration - elapsed
+	}
+	return ratio, time.Duration(float64(elapsed) * (1 - ratio) / ratio)
+}
+
+// histogram 延迟分布，只显示有样本的区间
+func (d *Dashboard) histogram() []string {
+	if d.source.Histogram == nil {
+		return nil
+	}
+	h, err := metrics.NewHdrHistogramFromExport(d.source.Histogram())
+	if err != nil || h.TotalCount() == 0 {
+		return nil
+	}
+	bounds := make([]int64, len(histogramBounds))
+	for i, bound := range histogramBounds {
+		bounds[i] = int64(bound)
+	}
+	groups := h.Distribution(bounds)
+
+	first, last := -1, -1
+	var peak uint64
+	for i, count := range groups {
+		if count == 0 {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		last = i
+		if count > peak {
+			peak = count
+		}
+	}
+
+	lines := []string{"Latency histogram"}
+	for i := first; i <= last; i++ {
+		label := "≥" + formatLatency(histogramBounds[len(histogramBounds)-1])
+		if i < len(histogramBounds) {
+			label = "<" + formatLatency(histogramBounds[i])
+		}
+		width := int(groups[i] * histogramWidth / peak)
+		if width == 0 && groups[i] > 0 {
+			width = 1
+		}
+		lines = append(lines, fmt.Sprintf("  %8s  %-*s %d", label, histogramWidth, strings.Repeat("█", width), groups[i]))
+	}
+	return lines
+}
+
+// bar 进度条
+func bar(ratio float64) string {
+	filled := int(ratio * progressWidth)
+	return strings.Repeat("█", filled) + strings.Repeat("░", progressWidth-filled)
+}
+
+// sparkline 按最大值缩放的走势图
+func sparkline(values []float64) string {
+	var peak float64
+	for _, value := range values {
+		if value > peak {
+			peak = value
+		}
+	}
+	runes := make([]rune, len(values))
+	for i, value := range values {
+		level := 0
+		if peak > 0 && value > 0 {
+			level = int(value / peak * float64(len(sparkLevels)-1))
+		}
+		runes[i] = sparkLevels[level]
+	}
+	return string(runes)
+}
+
+// formatETA 格式化预计剩余时间
+func formatETA(eta time.Duration, running bool) string {
+	switch {
+	case !running:
+		return "done"
+	case eta < 0:
+		return "--"
+	default:
+		return eta.Round(time.Second).String()
+	}
+}
+
+// formatLatency 格式化延迟
+func formatLatency(d time.Duration) string {
+	switch {
+	case d <= 0:
+		return "0"
+	case d < time.Millisecond:
+		return fmt.Sprintf("%dµs", d.Microseconds())
+	case d < time.Second:
+		return trimZeros(fmt.Sprintf("%.2f", float64(d)/float64(time.Millisecond))) + "ms"
+	default:
+		return trimZeros(fmt.Sprintf("%.2f", d.Seconds())) + "s"
+	}
+}
+
+// trimZeros 去掉小数末尾的0
+func trimZeros(text string) string {
+	if !strings.Contains(text, ".") {
+		return text
+	}
+	return strings.TrimSuffix(strings.TrimRight(text, "0"), ".")
+}
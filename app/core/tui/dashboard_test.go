@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/metrics"
+)
+
+func TestDashboardRender(t *testing.T) {
+	histogram := metrics.NewHdrHistogram(metrics.DefaultHdrHighestValue, metrics.DefaultHdrSignificantDigits)
+	for i := 0; i < 90; i++ {
+		histogram.Record(int64(3 * time.Millisecond))
+	}
+	for i := 0; i < 10; i++ {
+		histogram.Record(int64(40 * time.Millisecond))
+	}
+
+	start := time.Now()
+	snapshot := &metrics.DefaultMetricsSnapshot{}
+	snapshot.Core.Operations.Total = 100
+	snapshot.Core.Operations.Failed = 5
+	snapshot.Core.Latency.P99 = 40 * time.Millisecond
+	d := New(&bytes.Buffer{}, Source{
+		Snapshot:  func() *metrics.DefaultMetricsSnapshot { return snapshot },
+		Histogram: histogram.Export,
+		Progress: func() execution.Progress {
+			return execution.Progress{Running: true, StartTime: start, Duration: 40 * time.Second, Workers: 8, Busy: 3}
+		},
+	})
+
+	d.Render(start.Add(10 * time.Second))
+	snapshot.Core.Operations.Total = 300
+	frame := strings.Join(d.Render(start.Add(20*time.Second)), "\n")
+
+	for _, want := range []string{
+		"ETA 20s",
+		" 50.0%",
+		"Throughput  20 ops/s",
+		"Errors      5 (1.67%)",
+		"p99 40ms",
+		"Workers     3/8 busy",
+		"<5ms",
+		"<50ms",
+	} {
+		if !strings.Contains(frame, want) {
+			t.Errorf("frame missing %q:\n%s", want, frame)
+		}
+	}
+	// 只显示有样本的区间
+	if strings.Contains(frame, "<2.5ms") || strings.Contains(frame, "<100ms") {
+		t.Errorf("frame shows empty histogram groups:\n%s", frame)
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	ratio, eta := estimate(execution.Progress{Total: 1000, Completed: 250}, 10*time.Second)
+	if ratio != 0.25 || eta != 30*time.Second {
+		t.Errorf("count-based estimate = %v, %v", ratio, eta)
+	}
+	if _, eta := estimate(execution.Progress{Total: 1000}, time.Second); eta >= 0 {
+		t.Errorf("expected an unknown ETA before the first completion, got %v", eta)
+	}
+}
+
+func TestDashboardStopsAfterRun(t *testing.T) {
+	var running atomic.Bool
+	running.Store(true)
+	var out lockedBuffer
+	d := New(&out, Source{
+		Snapshot: func() *metrics.DefaultMetricsSnapshot { return &metrics.DefaultMetricsSnapshot{} },
+		Progress: func() execution.Progress { return execution.Progress{Running: running.Load()} },
+	})
+	d.interval = 5 * time.Millisecond
+	d.Start()
+
+	time.Sleep(30 * time.Millisecond)
+	running.Store(false)
+	select {
+	case <-d.done:
+	case <-time.After(time.Second):
+		t.Fatal("dashboard did not stop after the run finished")
+	}
+	d.Stop()
+
+	text := out.String()
+	if !strings.Contains(text, "\x1b[") || !strings.Contains(text, "finished") {
+		t.Errorf("expected redrawn frames ending in a finished frame, got %q", text)
+	}
+}
+
+// lockedBuffer 可并发读写的缓冲
+type lockedBuffer struct {
+	mutex sync.Mutex
+	buf   bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.String()
+}
@@ -0,0 +1,208 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// 键访问分布类型
+const (
+	DistributionSequential = "sequential" // 按操作编号顺序扫描键空间
+	DistributionUniform    = "uniform"    // 均匀随机
+	DistributionZipfian    = "zipfian"    // 少数键承担大部分访问，编号越小越热
+	DistributionGaussian   = "gaussian"   // 访问集中在键空间中的一个热点区域
+)
+
+// 分布参数默认值
+const (
+	defaultZipfSkew       = 0.99
+	defaultGaussianMean   = 0.5
+	defaultGaussianStdDev = 0.05
+)
+
+// KeyDistributionConfig 键访问分布配置
+type KeyDistributionConfig struct {
+	Type   string  `yaml:"type" json:"type"`     // sequential（默认）、uniform、zipfian、gaussian
+	Skew   float64 `yaml:"skew" json:"skew"`     // zipfian偏斜系数，取值(0,1)，越大越集中，默认0.99
+	Mean   float64 `yaml:"mean" json:"mean"`     // gaussian热点中心在键空间中的相对位置，取值[0,1]，默认0.5
+	StdDev float64 `yaml:"stddev" json:"stddev"` // gaussian标准差占键空间的比例，默认0.05
+}
+
+// GetType 获取分布类型，未设置时为sequential
+func (c KeyDistributionConfig) GetType() string {
+	if c.Type == "" {
+		return DistributionSequential
+	}
+	return c.Type
+}
+
+// GetSkew 获取zipfian偏斜系数
+func (c KeyDistributionConfig) GetSkew() float64 {
+	if c.Skew == 0 {
+		return defaultZipfSkew
+	}
+	return c.Skew
+}
+
+// GetMean 获取gaussian热点中心
+func (c KeyDistributionConfig) GetMean() float64 {
+	if c.Mean == 0 {
+		return defaultGaussianMean
+	}
+	return c.Mean
+}
+
+// GetStdDev 获取gaussian标准差比例
+func (c KeyDistributionConfig) GetStdDev() float64 {
+	if c.StdDev == 0 {
+		return defaultGaussianStdDev
+	}
+	return c.StdDev
+}
+
+// Validate 验证分布配置，keySpace为键空间大小
+func (c KeyDistributionConfig) Validate(keySpace int) error {
+	switch c.GetType() {
+	case DistributionSequential:
+		return nil
+	case DistributionUniform, DistributionZipfian, DistributionGaussian:
+	default:
+		return fmt.Errorf("unsupported key distribution: %s (expected sequential, uniform, zipfian or gaussian)", c.Type)
+	}
+
+	if keySpace <= 0 {
+		return fmt.Errorf("%s key distribution requires a key space (random_keys > 0)", c.GetType())
+	}
+	if skew := c.GetSkew(); skew <= 0 || skew >= 1 {
+		return fmt.Errorf("zipfian skew must be between 0 and 1 (exclusive), got: %g", skew)
+	}
+	if mean := c.GetMean(); mean < 0 || mean > 1 {
+		return fmt.Errorf("gaussian mean must be between 0 and 1, got: %g", mean)
+	}
+	if stddev := c.GetStdDev(); stddev <= 0 {
+		return fmt.Errorf("gaussian stddev must be positive, got: %g", stddev)
+	}
+	return nil
+}
+
+// String 返回分布的简要描述
+func (c KeyDistributionConfig) String() string {
+	switch c.GetType() {
+	case DistributionZipfian:
+		return fmt.Sprintf("zipfian (skew=%g)", c.GetSkew())
+	case DistributionGaussian:
+		return fmt.Sprintf("gaussian (mean=%g, stddev=%g)", c.GetMean(), c.GetStdDev())
+	default:
+		return c.GetType()
+	}
+}
+
+// KeyDistribution 键访问分布，将操作编号映射为键空间内的键编号，实现需并发安全
+type KeyDistribution interface {
+	Next(index int) int
+}
+
+// NewKeyDistribution 按配置创建键访问分布
+func NewKeyDistribution(cfg KeyDistributionConfig, keySpace int) (KeyDistribution, error) {
+	if err := cfg.Validate(keySpace); err != nil {
+		return nil, err
+	}
+
+	switch cfg.GetType() {
+	case DistributionUniform:
+		return uniformDistribution{keySpace: keySpace}, nil
+	case DistributionZipfian:
+		return newZipfianDistribution(keySpace, cfg.GetSkew()), nil
+	case DistributionGaussian:
+		return gaussianDistribution{
+			keySpace: keySpace,
+			center:   cfg.GetMean() * float64(keySpace-1),
+			stddev:   cfg.GetStdDev() * float64(keySpace),
+		}, nil
+	default:
+		return sequentialDistribution{keySpace: keySpace}, nil
+	}
+}
+
+// sequentialDistribution 顺序扫描，键空间为0时键编号即操作编号
+type sequentialDistribution struct {
+	keySpace int
+}
+
+func (d sequentialDistribution) Next(index int) int {
+	if d.keySpace <= 0 {
+		return index
+	}
+	return index % d.keySpace
+}
+
+// uniformDistribution 均匀随机
+type uniformDistribution struct {
+	keySpace int
+}
+
+func (d uniformDistribution) Next(int) int {
+	return rand.Intn(d.keySpace)
+}
+
+// gaussianDistribution 正态分布热点，超出键空间的样本重新采样
+type gaussianDistribution struct {
+	keySpace int
+	center   float64
+	stddev   float64
+}
+
+func (d gaussianDistribution) Next(int) int {
+	var value float64
+	for attempt := 0; attempt < 8; attempt++ {
+		value = math.Round(d.center + d.stddev*rand.NormFloat64())
+		if value >= 0 && value < float64(d.keySpace) {
+			return int(value)
+		}
+	}
+	return int(math.Min(math.Max(value, 0), float64(d.keySpace-1)))
+}
+
+// zipfianDistribution Gray等人的zipfian生成算法（与YCSB相同），构造时计算一次zeta(n)
+type zipfianDistribution struct {
+	keySpace int
+	theta    float64
+	alpha    float64
+	zetaN    float64
+	eta      float64
+	half     float64 // 1 + 0.5^theta
+}
+
+func newZipfianDistribution(keySpace int, theta float64) *zipfianDistribution {
+	zetaN := 0.0
+	for i := 1; i <= keySpace; i++ {
+		zetaN += 1 / math.Pow(float64(i), theta)
+	}
+	zeta2 := 1 + 1/math.Pow(2, theta)
+
+	return &zipfianDistribution{
+		keySpace: keySpace,
+		theta:    theta,
+		alpha:    1 / (1 - theta),
+		zetaN:    zetaN,
+		eta:      (1 - math.Pow(2/float64(keySpace), 1-theta)) / (1 - zeta2/zetaN),
+		half:     1 + math.Pow(0.5, theta),
+	}
+}
+
+func (d *zipfianDistribution) Next(int) int {
+	u := rand.Float64()
+	uz := u * d.zetaN
+	if uz < 1 || d.keySpace == 1 {
+		return 0
+	}
+	if uz < d.half {
+		return 1
+	}
+	value := int(float64(d.keySpace) * math.Pow(d.eta*u-d.eta+1, d.alpha))
+	if value >= d.keySpace {
+		value = d.keySpace - 1
+	}
+	return value
+}
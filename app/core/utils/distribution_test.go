@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"testing"
+)
+
+// sampleCounts 采样并统计每个键编号的访问次数
+func sampleCounts(t *testing.T, cfg KeyDistributionConfig, keySpace, samples int) []int {
+	t.Helper()
+	distribution, err := NewKeyDistribution(cfg, keySpace)
+	if err != nil {
+		t.Fatalf("NewKeyDistribution(%+v) failed: %v", cfg, err)
+	}
+	counts := make([]int, keySpace)
+	for i := 0; i < samples; i++ {
+		key := distribution.Next(i)
+		if key < 0 || key >= keySpace {
+			t.Fatalf("%s: key %d out of range [0, %d)", cfg.GetType(), key, keySpace)
+		}
+		counts[key]++
+	}
+	return counts
+}
+
+func TestKeyDistributions(t *testing.T) {
+	const keySpace, samples = 1000, 200000
+
+	sequential := sampleCounts(t, KeyDistributionConfig{}, keySpace, samples)
+	for key, count := range sequential {
+		if count != samples/keySpace {
+			t.Fatalf("sequential: key %d accessed %d times, want %d", key, count, samples/keySpace)
+		}
+	}
+
+	// zipfian(0.99)：键0约占14%，前10个键约占40%
+	zipfian := sampleCounts(t, KeyDistributionConfig{Type: DistributionZipfian}, keySpace, samples)
+	top := 0
+	for key := 0; key < 10; key++ {
+		top += zipfian[key]
+	}
+	if share := float64(zipfian[0]) / samples; share < 0.10 || share > 0.18 {
+		t.Fatalf("zipfian: key 0 share = %.3f, want ~0.14", share)
+	}
+	if share := float64(top) / samples; share < 0.35 || share > 0.47 {
+		t.Fatalf("zipfian: top 10 share = %.3f, want ~0.41", share)
+	}
+
+	// gaussian：约95%的访问落在中心±2个标准差内
+	gaussian := sampleCounts(t, KeyDistributionConfig{Type: DistributionGaussian, Mean: 0.2}, keySpace, samples)
+	hot := 0
+	for key := 100; key < 300; key++ {
+		hot += gaussian[key]
+	}
+	if share := float64(hot) / samples; share < 0.93 {
+		t.Fatalf("gaussian: share within 2 stddev = %.3f, want >= 0.93", share)
+	}
+
+	uniform := sampleCounts(t, KeyDistributionConfig{Type: DistributionUniform}, keySpace, samples)
+	for key, count := range uniform {
+		if count < 100 || count > 300 {
+			t.Fatalf("uniform: key %d accessed %d times, want ~200", key, count)
+		}
+	}
+}
+
+func TestKeyDistributionValidate(t *testing.T) {
+	cases := []struct {
+		cfg      KeyDistributionConfig
+		keySpace int
+		valid    bool
+	}{
+		{KeyDistributionConfig{}, 0, true},
+		{KeyDistributionConfig{Type: DistributionZipfian}, 0, false},
+		{KeyDistributionConfig{Type: DistributionZipfian, Skew: 1.2}, 100, false},
+		{KeyDistributionConfig{Type: DistributionGaussian, Mean: 1.5}, 100, false},
+		{KeyDistributionConfig{Type: DistributionGaussian, StdDev: -1}, 100, false},
+		{KeyDistributionConfig{Type: "pareto"}, 100, false},
+		{KeyDistributionConfig{Type: DistributionUniform}, 100, true},
+	}
+	for _, c := range cases {
+		if err := c.cfg.Validate(c.keySpace); (err == nil) != c.valid {
+			t.Errorf("Validate(%+v, %d) = %v, want valid=%v", c.cfg, c.keySpace, err, c.valid)
+		}
+	}
+}
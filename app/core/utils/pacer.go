@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// maxPacerBacklog 限速器允许追赶的最大积压时间
+const maxPacerBacklog = 100 * time.Millisecond
+
+// Pacer 按固定间隔为每次操作分配开始时刻，可由多个并发工作者共享
+type Pacer struct {
+	interval time.Duration
+	mutex    sync.Mutex
+	next     time.Time
+}
+
+// NewRatePacer 创建每秒perSecond次的限速器，perSecond不大于0时返回nil（不限速）
+func NewRatePacer(perSecond float64) *Pacer {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &Pacer{interval: time.Duration(float64(time.Second) / perSecond)}
+}
+
+// Wait 等待到分配的开始时刻，nil限速器立即返回
+// 定时器唤醒延迟造成的落后会被追赶，但积压最多保留maxPacerBacklog，避免停顿后突发
+func (p *Pacer) Wait(ctx context.Context) error {
+	if p == nil {
+		return nil
+	}
+
+	p.mutex.Lock()
+	now := time.Now()
+	if p.next.IsZero() {
+		p.next = now
+	} else if earliest := now.Add(-maxPacerBacklog); p.next.Before(earliest) {
+		p.next = earliest
+	}
+	due := p.next
+	p.next = p.next.Add(p.interval)
+	p.mutex.Unlock()
+
+	delay := time.Until(due)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,344 @@
+package utils
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PayloadTemplateConfig 负载模板配置（HTTP请求体、Redis值、Kafka消息）
+type PayloadTemplateConfig struct {
+	Template string `yaml:"template" json:"template"`   // 负载模板，如 {"id":"{{uuid}}","seq":{{seq}}}
+	DataFile string `yaml:"data_file" json:"data_file"` // CSV数据文件，首行为列名，以{{csv.列名}}引用，按操作编号循环取行
+}
+
+// Enabled 是否配置了负载模板
+func (c PayloadTemplateConfig) Enabled() bool {
+	return c.Template != ""
+}
+
+// Validate 验证模板语法及其引用的数据文件列
+func (c PayloadTemplateConfig) Validate() error {
+	if !c.Enabled() {
+		if c.DataFile != "" {
+			_, err := LoadTemplateData(c.DataFile)
+			return err
+		}
+		return nil
+	}
+	_, err := c.Compile()
+	return err
+}
+
+// LoadData 加载数据文件，未配置时返回nil
+func (c PayloadTemplateConfig) LoadData() (*TemplateData, error) {
+	if c.DataFile == "" {
+		return nil, nil
+	}
+	return LoadTemplateData(c.DataFile)
+}
+
+// Compile 加载数据文件并解析模板
+func (c PayloadTemplateConfig) Compile() (*PayloadTemplate, error) {
+	data, err := c.LoadData()
+	if err != nil {
+		return nil, err
+	}
+	return ParsePayloadTemplate(c.Template, data)
+}
+
+// TemplateData 模板使用的CSV数据
+type TemplateData struct {
+	columns map[string]int
+	rows    [][]string
+}
+
+// LoadTemplateData 读取CSV数据文件，首行为列名
+func LoadTemplateData(path string) (*TemplateData, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open data file: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data file header %s: %w", path, err)
+	}
+	data := &TemplateData{columns: make(map[string]int, len(header))}
+	for i, column := range header {
+		data.columns[strings.TrimSpace(column)] = i
+	}
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read data file %s: %w", path, err)
+		}
+		data.rows = append(data.rows, row)
+	}
+	if len(data.rows) == 0 {
+		return nil, fmt.Errorf("data file %s has no rows", path)
+	}
+	return data, nil
+}
+
+// Rows 数据行数
+func (d *TemplateData) Rows() int {
+	return len(d.rows)
+}
+
+// templatePart 模板片段：字面文本或占位符
+type templatePart struct {
+	literal string
+	render  func(buf []byte, seq int) []byte
+}
+
+// PayloadTemplate 已解析的负载模板
+// 支持的占位符：
+//
+//	{{seq}}                 操作编号
+//	{{uuid}}                随机UUID v4
+//	{{timestamp}}           当前时间，默认Unix毫秒；可选格式 s、ms、ns、rfc3339
+//	{{randInt}}             随机非负整数；{{randInt MIN MAX}} 取闭区间[MIN,MAX]
+//	{{randFloat}}           [0,1)随机小数；{{randFloat MIN MAX}} 取[MIN,MAX)
+//	{{randString}}          16位随机字母数字；{{randString N}} 指定长度
+//	{{pick A B C}}          从给定值中随机取一个
+//	{{name}} / {{email}}    随机姓名与邮箱
+//	{{csv.COLUMN}}          数据文件当前行的列值，第seq%行数行
+type PayloadTemplate struct {
+	parts []templatePart
+}
+
+// IsPayloadTemplate 文本是否包含模板占位符
+func IsPayloadTemplate(text string) bool {
+	return strings.Contains(text, "{{")
+}
+
+// ParsePayloadTemplate 解析模板，data为nil时不能引用csv列
+func ParsePayloadTemplate(text string, data *TemplateData) (*PayloadTemplate, error) {
+	t := &PayloadTemplate{}
+	for text != "" {
+		start := strings.Index(text, "{{")
+		if start < 0 {
+			t.parts = append(t.parts, templatePart{literal: text})
+			break
+		}
+		end := strings.Index(text[start:], "}}")
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed placeholder in template: %q", text[start:])
+		}
+		if start > 0 {
+			t.parts = append(t.parts, templatePart{literal: text[:start]})
+		}
+		tag := text[start : start+end+2]
+		render, err := parsePlaceholder(strings.Fields(tag[2:len(tag)-2]), data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid placeholder %s: %w", tag, err)
+		}
+		t.parts = append(t.parts, templatePart{render: render})
+		text = text[start+end+2:]
+	}
+	return t, nil
+}
+
+// Render 按操作编号渲染模板
+func (t *PayloadTemplate) Render(seq int) string {
+	buf := make([]byte, 0, 256)
+	for _, part := range t.parts {
+		if part.render != nil {
+			buf = part.render(buf, seq)
+		} else {
+			buf = append(buf, part.literal...)
+		}
+	}
+	return string(buf)
+}
+
+// 随机姓名使用的名与姓
+var (
+	templateFirstNames = []string{"Alice", "Bob", "Carol", "David", "Emma", "Frank", "Grace", "Henry", "Iris", "Jack", "Kate", "Liam", "Mia", "Noah", "Olivia", "Peter"}
+	templateLastNames  = []string{"Smith", "Johnson", "Brown", "Garcia", "Miller", "Davis", "Wilson", "Moore", "Taylor", "Lee", "Martin", "Clark", "Lewis", "Walker", "Young", "King"}
+)
+
+// templateCharset 随机字符串的字符集
+const templateCharset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// parsePlaceholder 解析占位符名称与参数
+func parsePlaceholder(fields []string, data *TemplateData) (func(buf []byte, seq int) []byte, error) {
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty placeholder")
+	}
+	name, args := fields[0], fields[1:]
+
+	if column, ok := strings.CutPrefix(name, "csv."); ok {
+		if data == nil {
+			return nil, fmt.Errorf("no data file configured")
+		}
+		index, ok := data.columns[column]
+		if !ok {
+			return nil, fmt.Errorf("data file has no column %q", column)
+		}
+		return func(buf []byte, seq int) []byte {
+			row := data.rows[max(seq, 0)%len(data.rows)]
+			if index < len(row) {
+				buf = append(buf, row[index]...)
+			}
+			return buf
+		}, nil
+	}
+
+	switch name {
+	case "seq":
+		return noArgs(args, func(buf []byte, seq int) []byte {
+			return strconv.AppendInt(buf, int64(seq), 10)
+		})
+	case "uuid":
+		return noArgs(args, func(buf []byte, seq int) []byte {
+			return appendUUID(buf)
+		})
+	case "timestamp":
+		if len(args) > 1 {
+			return nil, fmt.Errorf("expected at most one format argument")
+		}
+		format := "ms"
+		if len(args) > 0 {
+			format = args[0]
+		}
+		var render func(buf []byte, seq int) []byte
+		switch format {
+		case "s":
+			render = func(buf []byte, seq int) []byte { return strconv.AppendInt(buf, time.Now().Unix(), 10) }
+		case "ms":
+			render = func(buf []byte, seq int) []byte { return strconv.AppendInt(buf, time.Now().UnixMilli(), 10) }
+		case "ns":
+			render = func(buf []byte, seq int) []byte { return strconv.AppendInt(buf, time.Now().UnixNano(), 10) }
+		case "rfc3339":
+			render = func(buf []byte, seq int) []byte { return time.Now().UTC().AppendFormat(buf, time.RFC3339Nano) }
+		default:
+			return nil, fmt.Errorf("unsupported timestamp format %q (expected s, ms, ns or rfc3339)", format)
+		}
+		return render, nil
+	case "randInt":
+		low, high, err := intRange(args, 0, 1<<31-1)
+		if err != nil {
+			return nil, err
+		}
+		if high-low+1 <= 0 {
+			return nil, fmt.Errorf("range %d %d is too large", low, high)
+		}
+		return func(buf []byte, seq int) []byte {
+			return strconv.AppendInt(buf, low+rand.Int63n(high-low+1), 10)
+		}, nil
+	case "randFloat":
+		low, high := 0.0, 1.0
+		if len(args) != 0 {
+			if len(args) != 2 {
+				return nil, fmt.Errorf("expected no arguments or MIN MAX")
+			}
+			var err1, err2 error
+			low, err1 = strconv.ParseFloat(args[0], 64)
+			high, err2 = strconv.ParseFloat(args[1], 64)
+			if err1 != nil || err2 != nil || high < low {
+				return nil, fmt.Errorf("invalid range %s %s", args[0], args[1])
+			}
+		}
+		return func(buf []byte, seq int) []byte {
+			return strconv.AppendFloat(buf, low+rand.Float64()*(high-low), 'f', 4, 64)
+		}, nil
+	case "randString":
+		if len(args) > 1 {
+			return nil, fmt.Errorf("expected at most one length argument")
+		}
+		length := 16
+		if len(args) > 0 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("invalid length %q", args[0])
+			}
+			length = n
+		}
+		return func(buf []byte, seq int) []byte {
+			for i := 0; i < length; i++ {
+				buf = append(buf, templateCharset[rand.Intn(len(templateCharset))])
+			}
+			return buf
+		}, nil
+	case "pick":
+		if len(args) == 0 {
+			return nil, fmt.Errorf("expected at least one value")
+		}
+		return func(buf []byte, seq int) []byte {
+			return append(buf, args[rand.Intn(len(args))]...)
+		}, nil
+	case "name":
+		return noArgs(args, func(buf []byte, seq int) []byte {
+			buf = append(buf, templateFirstNames[rand.Intn(len(templateFirstNames))]...)
+			buf = append(buf, ' ')
+			return append(buf, templateLastNames[rand.Intn(len(templateLastNames))]...)
+		})
+	case "email":
+		return noArgs(args, func(buf []byte, seq int) []byte {
+			buf = append(buf, strings.ToLower(templateFirstNames[rand.Intn(len(templateFirstNames))])...)
+			buf = append(buf, '.')
+			buf = append(buf, strings.ToLower(templateLastNames[rand.Intn(len(templateLastNames))])...)
+			buf = strconv.AppendInt(buf, int64(rand.Intn(10000)), 10)
+			return append(buf, "@example.com"...)
+		})
+	default:
+		return nil, fmt.Errorf("unknown function %q", name)
+	}
+}
+
+// noArgs 不接受参数的占位符
+func noArgs(args []string, render func(buf []byte, seq int) []byte) (func(buf []byte, seq int) []byte, error) {
+	if len(args) > 0 {
+		return nil, fmt.Errorf("unexpected arguments %v", args)
+	}
+	return render, nil
+}
+
+// intRange 解析可选的MIN MAX整数闭区间
+func intRange(args []string, low, high int64) (int64, int64, error) {
+	if len(args) == 0 {
+		return low, high, nil
+	}
+	if len(args) != 2 {
+		return 0, 0, fmt.Errorf("expected no arguments or MIN MAX")
+	}
+	low, err1 := strconv.ParseInt(args[0], 10, 64)
+	high, err2 := strconv.ParseInt(args[1], 10, 64)
+	if err1 != nil || err2 != nil || high < low {
+		return 0, 0, fmt.Errorf("invalid range %s %s", args[0], args[1])
+	}
+	return low, high, nil
+}
+
+// appendUUID 追加随机UUID v4
+func appendUUID(buf []byte) []byte {
+	const hex = "0123456789abcdef"
+	var b [16]byte
+	high, low := rand.Uint64(), rand.Uint64()
+	for i := 0; i < 8; i++ {
+		b[i] = byte(high >> (8 * i))
+		b[8+i] = byte(low >> (8 * i))
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	for i, c := range b {
+		if i == 4 || i == 6 || i == 8 || i == 10 {
+			buf = append(buf, '-')
+		}
+		buf = append(buf, hex[c>>4], hex[c&0x0f])
+	}
+	return buf
+}
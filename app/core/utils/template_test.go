@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestPayloadTemplate(t *testing.T) {
+	dir := t.TempDir()
+	dataFile := filepath.Join(dir, "users.csv")
+	if err := os.WriteFile(dataFile, []byte("user, city\nalice,Paris\nbob,\"New York, NY\"\ncarol,Oslo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := PayloadTemplateConfig{
+		Template: `{"id":"{{uuid}}","seq":{{seq}},"user":"{{csv.user}}","city":"{{csv.city}}","n":{{randInt 5 7}},` +
+			`"f":{{randFloat 1 2}},"s":"{{randString 8}}","p":"{{pick red green}}","ts":{{timestamp s}},"email":"{{email}}"}`,
+		DataFile: dataFile,
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	template, err := cfg.Compile()
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	users := []string{"alice", "bob", "carol"}
+	for seq := 0; seq < 6; seq++ {
+		var payload struct {
+			ID    string  `json:"id"`
+			Seq   int     `json:"seq"`
+			User  string  `json:"user"`
+			City  string  `json:"city"`
+			N     int     `json:"n"`
+			F     float64 `json:"f"`
+			S     string  `json:"s"`
+			P     string  `json:"p"`
+			TS    int64   `json:"ts"`
+			Email string  `json:"email"`
+		}
+		rendered := template.Render(seq)
+		if err := json.Unmarshal([]byte(rendered), &payload); err != nil {
+			t.Fatalf("rendered payload is not JSON: %v\n%s", err, rendered)
+		}
+		if !uuid.MatchString(payload.ID) || payload.Seq != seq || payload.User != users[seq%3] {
+			t.Errorf("unexpected id/seq/user in %s", rendered)
+		}
+		if payload.N < 5 || payload.N > 7 || payload.F < 1 || payload.F >= 2 || len(payload.S) != 8 || payload.TS <= 0 {
+			t.Errorf("unexpected random values in %s", rendered)
+		}
+		if (payload.P != "red" && payload.P != "green") || !strings.HasSuffix(payload.Email, "@example.com") {
+			t.Errorf("unexpected faker values in %s", rendered)
+		}
+		if seq == 1 && payload.City != "New York, NY" {
+			t.Errorf("quoted CSV field not preserved: %q", payload.City)
+		}
+	}
+
+	// 不含占位符的模板原样输出
+	plain, err := ParsePayloadTemplate("plain {text}", nil)
+	if err != nil || plain.Render(3) != "plain {text}" {
+		t.Errorf("plain template rendered incorrectly")
+	}
+	if seq, _ := ParsePayloadTemplate("{{ seq }}", nil); seq.Render(42) != strconv.Itoa(42) {
+		t.Errorf("spaces inside placeholders should be ignored")
+	}
+
+	for _, invalid := range []string{
+		"{{unknown}}", "{{seq", "{{}}", "{{randInt 9 1}}", "{{randString 0}}",
+		"{{timestamp hours}}", "{{pick}}", "{{uuid 1}}", "{{csv.user}}",
+	} {
+		if _, err := ParsePayloadTemplate(invalid, nil); err == nil {
+			t.Errorf("expected %q to be rejected", invalid)
+		}
+	}
+	if err := (PayloadTemplateConfig{Template: "{{csv.missing}}", DataFile: dataFile}).Validate(); err == nil {
+		t.Errorf("expected an unknown CSV column to be rejected")
+	}
+	if err := (PayloadTemplateConfig{Template: "{{seq}}", DataFile: filepath.Join(dir, "none.csv")}).Validate(); err == nil {
+		t.Errorf("expected a missing data file to be rejected")
+	}
+}
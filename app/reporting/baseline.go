@@ -0,0 +1,232 @@
+package reporting
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"runtime"
+	"strings"
+	"time"
+
+	"abc-runner/app/core/metrics"
+)
+
+// 目标位置
+const (
+	TargetLoopback = "loopback"
+	TargetRemote   = "remote"
+)
+
+// CPU档位，按可用逻辑核数划分
+const (
+	CPUClassSmall  = "small"  // ≤2核
+	CPUClassMedium = "medium" // 3-8核
+	CPUClassLarge  = "large"  // >8核
+)
+
+// 基线判定结果
+const (
+	VerdictTooFast = "too_fast"
+	VerdictTooSlow = "too_slow"
+)
+
+// minBaselineSamples 参与基线比对所需的最少成功操作数，样本过少时P50不可信
+const minBaselineSamples = 100
+
+// LatencyRange 延迟区间，Max为0表示不设上限
+type LatencyRange struct {
+	Min time.Duration `json:"min"`
+	Max time.Duration `json:"max,omitempty"`
+}
+
+// BaselineProfile 参考基线：给定协议、操作、目标位置与CPU档位下P50延迟的合理区间
+type BaselineProfile struct {
+	Name       string
+	Protocol   string
+	Operations []string // 适用的test_case，为空表示任意
+	Target     string   // loopback或remote
+	CPUClass   string   // 为空表示任意档位
+	P50        LatencyRange
+	Excludes   []string // 协议指标中存在这些键时不适用（如pipeline批量发送使单次延迟不可比）
+}
+
+// BaselineWarning 结果偏离参考基线的告警
+type BaselineWarning struct {
+	Profile  string        `json:"profile"`
+	Verdict  string        `json:"verdict"`
+	Observed time.Duration `json:"observed_p50"`
+	Expected LatencyRange  `json:"expected_p50"`
+	Message  string        `json:"message"`
+}
+
+// 基线告警消息，参数依次为观测的P50、基线名称与越过的参考界限
+const (
+	baselineTooFastFormat = "P50延迟%v低于%s参考下限%v，结果快得不合理，请确认压测目标不是mock或模拟数据"
+	baselineTooSlowFormat = "P50延迟%v高于%s参考上限%v，结果慢得不合理，请检查调试日志、资源争用或目标是否经过代理/隧道"
+)
+
+// localizedMessage 按报告语言重新生成告警消息
+func (w BaselineWarning) localizedMessage(t func(string) string) string {
+	switch w.Verdict {
+	case VerdictTooFast:
+		return fmt.Sprintf(t(baselineTooFastFormat), w.Observed, w.Profile, w.Expected.Min)
+	case VerdictTooSlow:
+		return fmt.Sprintf(t(baselineTooSlowFormat), w.Observed, w.Profile, w.Expected.Max)
+	}
+	return w.Message
+}
+
+// referenceBaselines 内置参考基线。下限取常见硬件上可达到的最快往返，低于下限通常意味着
+// 压到了mock或模拟数据；上限只为行为稳定的服务设置（如本机Redis），高于上限通常意味着
+// 开启了调试日志、资源争用或目标实际经过代理/隧道
+var referenceBaselines = []BaselineProfile{
+	{Name: "redis-loopback-small", Protocol: "redis", Operations: redisSimpleCases, Target: TargetLoopback, CPUClass: CPUClassSmall,
+		P50: LatencyRange{Min: 30 * time.Microsecond, Max: 5 * time.Millisecond}, Excludes: []string{"pipeline"}},
+	{Name: "redis-loopback-medium", Protocol: "redis", Operations: redisSimpleCases, Target: TargetLoopback, CPUClass: CPUClassMedium,
+		P50: LatencyRange{Min: 20 * time.Microsecond, Max: 3 * time.Millisecond}, Excludes: []string{"pipeline"}},
+	{Name: "redis-loopback-large", Protocol: "redis", Operations: redisSimpleCases, Target: TargetLoopback, CPUClass: CPUClassLarge,
+		P50: LatencyRange{Min: 15 * time.Microsecond, Max: 2 * time.Millisecond}, Excludes: []string{"pipeline"}},
+	{Name: "redis-remote", Protocol: "redis", Operations: redisSimpleCases, Target: TargetRemote,
+		P50: LatencyRange{Min: 60 * time.Microsecond}, Excludes: []string{"pipeline"}},
+
+	{Name: "http-loopback", Protocol: "http", Target: TargetLoopback, P50: LatencyRange{Min: 25 * time.Microsecond}},
+	{Name: "http-remote", Protocol: "http", Target: TargetRemote, P50: LatencyRange{Min: 80 * time.Microsecond}},
+
+	{Name: "tcp-echo-loopback", Protocol: "tcp", Operations: []string{"echo_test"}, Target: TargetLoopback, P50: LatencyRange{Min: 8 * time.Microsecond}},
+	{Name: "tcp-echo-remote", Protocol: "tcp", Operations: []string{"echo_test"}, Target: TargetRemote, P50: LatencyRange{Min: 50 * time.Microsecond}},
+
+	{Name: "udp-echo-loopback", Protocol: "udp", Operations: []string{"echo_udp"}, Target: TargetLoopback, P50: LatencyRange{Min: 5 * time.Microsecond}},
+	{Name: "udp-echo-remote", Protocol: "udp", Operations: []string{"echo_udp"}, Target: TargetRemote, P50: LatencyRange{Min: 50 * time.Microsecond}},
+
+	{Name: "grpc-unary-loopback", Protocol: "grpc", Operations: []string{"unary_call"}, Target: TargetLoopback, P50: LatencyRange{Min: 40 * time.Microsecond}},
+	{Name: "grpc-unary-remote", Protocol: "grpc", Operations: []string{"unary_call"}, Target: TargetRemote, P50: LatencyRange{Min: 100 * time.Microsecond}},
+
+	{Name: "websocket-loopback", Protocol: "websocket", Operations: []string{"message_exchange", "ping_pong"}, Target: TargetLoopback, P50: LatencyRange{Min: 15 * time.Microsecond}},
+	{Name: "websocket-remote", Protocol: "websocket", Operations: []string{"message_exchange", "ping_pong"}, Target: TargetRemote, P50: LatencyRange{Min: 60 * time.Microsecond}},
+}
+
+// redisSimpleCases 单命令往返的Redis用例
+var redisSimpleCases = []string{"get", "set", "set_get", "set_get_random"}
+
+// ReferenceBaselines 返回内置参考基线
+func ReferenceBaselines() []BaselineProfile {
+	return referenceBaselines
+}
+
+// DetectCPUClass 按本机逻辑核数判定CPU档位
+func DetectCPUClass() string {
+	return cpuClassFor(runtime.NumCPU())
+}
+
+func cpuClassFor(cpus int) string {
+	switch {
+	case cpus <= 2:
+		return CPUClassSmall
+	case cpus <= 8:
+		return CPUClassMedium
+	default:
+		return CPUClassLarge
+	}
+}
+
+// CheckBaselines 将快照的P50延迟与匹配的参考基线比对，返回快得或慢得不合理的告警。
+// 快照需在协议指标中记录target（目标地址）与test_case，缺少时不做比对
+func CheckBaselines(snapshot *metrics.MetricsSnapshot[map[string]interface{}]) []BaselineWarning {
+	return checkBaselines(snapshot, DetectCPUClass(), referenceBaselines)
+}
+
+func checkBaselines(snapshot *metrics.MetricsSnapshot[map[string]interface{}], cpuClass string, profiles []BaselineProfile) []BaselineWarning {
+	if snapshot == nil || snapshot.Core.Operations.Success < minBaselineSamples {
+		return nil
+	}
+	target, _ := snapshot.Protocol["target"].(string)
+	if target == "" {
+		return nil
+	}
+	protocol := getProtocolFromSnapshot(snapshot)
+	operation, _ := snapshot.Protocol["test_case"].(string)
+	location := classifyTarget(target)
+	p50 := snapshot.Core.Latency.P50
+
+	var warnings []BaselineWarning
+	for _, profile := range profiles {
+		if !profile.matches(protocol, operation, location, cpuClass, snapshot.Protocol) {
+			continue
+		}
+		switch {
+		case p50 < profile.P50.Min:
+			warnings = append(warnings, BaselineWarning{
+				Profile:  profile.Name,
+				Verdict:  VerdictTooFast,
+				Observed: p50,
+				Expected: profile.P50,
+				Message:  fmt.Sprintf(baselineTooFastFormat, p50, profile.Name, profile.P50.Min),
+			})
+		case profile.P50.Max > 0 && p50 > profile.P50.Max:
+			warnings = append(warnings, BaselineWarning{
+				Profile:  profile.Name,
+				Verdict:  VerdictTooSlow,
+				Observed: p50,
+				Expected: profile.P50,
+				Message:  fmt.Sprintf(baselineTooSlowFormat, p50, profile.Name, profile.P50.Max),
+			})
+		}
+	}
+	return warnings
+}
+
+// matches 判断基线是否适用于给定的协议、操作、目标位置与CPU档位
+func (p BaselineProfile) matches(protocol, operation, location, cpuClass string, protocolMetrics map[string]interface{}) bool {
+	if p.Protocol != protocol || p.Target != location {
+		return false
+	}
+	if p.CPUClass != "" && p.CPUClass != cpuClass {
+		return false
+	}
+	if len(p.Operations) > 0 && !containsString(p.Operations, operation) {
+		return false
+	}
+	for _, key := range p.Excludes {
+		if _, ok := protocolMetrics[key]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// classifyTarget 判定目标地址是本机回环还是远端，支持URL、host:port与unix套接字路径
+func classifyTarget(target string) string {
+	host := target
+	if strings.Contains(target, "://") {
+		parsed, err := url.Parse(target)
+		if err != nil {
+			return TargetRemote
+		}
+		if parsed.Scheme == "unix" {
+			return TargetLoopback
+		}
+		host = parsed.Hostname()
+	} else if strings.HasPrefix(target, "/") {
+		return TargetLoopback
+	} else if h, _, err := net.SplitHostPort(target); err == nil {
+		host = h
+	}
+
+	host = strings.ToLower(strings.Trim(host, "[]"))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return TargetLoopback
+	}
+	if ip := net.ParseIP(host); ip != nil && (ip.IsLoopback() || ip.IsUnspecified()) {
+		return TargetLoopback
+	}
+	return TargetRemote
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package reporting
+
+import (
+	"testing"
+	"time"
+
+	"abc-runner/app/core/metrics"
+)
+
+func baselineTestSnapshot(target, testCase string, p50 time.Duration) *metrics.MetricsSnapshot[map[string]interface{}] {
+	snapshot := &metrics.MetricsSnapshot[map[string]interface{}]{
+		Protocol: map[string]interface{}{
+			"protocol":  "redis",
+			"test_case": testCase,
+			"target":    target,
+		},
+	}
+	snapshot.Core.Operations.Success = 1000
+	snapshot.Core.Latency.P50 = p50
+	return snapshot
+}
+
+func TestCheckBaselines(t *testing.T) {
+	// 本机Redis GET的P50为2µs：远低于回环下限，疑似压到mock
+	warnings := checkBaselines(baselineTestSnapshot("localhost:6379", "get", 2*time.Microsecond), CPUClassMedium, referenceBaselines)
+	if len(warnings) != 1 || warnings[0].Verdict != VerdictTooFast || warnings[0].Profile != "redis-loopback-medium" {
+		t.Fatalf("warnings = %+v, want one too_fast from redis-loopback-medium", warnings)
+	}
+
+	// 固定10ms（模拟数据的典型值）：高于回环上限
+	warnings = checkBaselines(baselineTestSnapshot("127.0.0.1:6379", "set", 10*time.Millisecond), CPUClassSmall, referenceBaselines)
+	if len(warnings) != 1 || warnings[0].Verdict != VerdictTooSlow || warnings[0].Profile != "redis-loopback-small" {
+		t.Fatalf("warnings = %+v, want one too_slow from redis-loopback-small", warnings)
+	}
+
+	// 远端目标不设上限；区间内的结果不告警
+	for _, snapshot := range []*metrics.MetricsSnapshot[map[string]interface{}]{
+		baselineTestSnapshot("10.0.0.5:6379", "get", 10*time.Millisecond),
+		baselineTestSnapshot("localhost:6379", "get", 200*time.Microsecond),
+		baselineTestSnapshot("localhost:6379", "leaderboard", 2*time.Microsecond),
+		baselineTestSnapshot("", "get", 2*time.Microsecond),
+	} {
+		if warnings := checkBaselines(snapshot, CPUClassMedium, referenceBaselines); len(warnings) != 0 {
+			t.Errorf("target %v case %v: unexpected warnings %+v", snapshot.Protocol["target"], snapshot.Protocol["test_case"], warnings)
+		}
+	}
+
+	// pipeline批量发送与样本不足时不比对
+	snapshot := baselineTestSnapshot("localhost:6379", "get", 2*time.Microsecond)
+	snapshot.Protocol["pipeline"] = struct{}{}
+	if warnings := checkBaselines(snapshot, CPUClassMedium, referenceBaselines); len(warnings) != 0 {
+		t.Errorf("pipeline run: unexpected warnings %+v", warnings)
+	}
+	snapshot = baselineTestSnapshot("localhost:6379", "get", 2*time.Microsecond)
+	snapshot.Core.Operations.Success = 10
+	if warnings := checkBaselines(snapshot, CPUClassMedium, referenceBaselines); len(warnings) != 0 {
+		t.Errorf("few samples: unexpected warnings %+v", warnings)
+	}
+}
+
+func TestClassifyTarget(t *testing.T) {
+	cases := map[string]string{
+		"localhost:6379":          TargetLoopback,
+		"127.0.0.1:9090":          TargetLoopback,
+		"[::1]:50051":             TargetLoopback,
+		"http://localhost:8080/":  TargetLoopback,
+		"ws://127.0.0.1:7070/ws":  TargetLoopback,
+		"unix:///tmp/redis.sock":  TargetLoopback,
+		"/var/run/redis.sock":     TargetLoopback,
+		"0.0.0.0:9091":            TargetLoopback,
+		"redis.internal:6379":     TargetRemote,
+		"https://api.example.com": TargetRemote,
+		"192.168.1.20:9092":       TargetRemote,
+	}
+	for target, want := range cases {
+		if got := classifyTarget(target); got != want {
+			t.Errorf("classifyTarget(%q) = %q, want %q", target, got, want)
+		}
+	}
+}
+
+func TestCPUClassFor(t *testing.T) {
+	for cpus, want := range map[int]string{1: CPUClassSmall, 2: CPUClassSmall, 4: CPUClassMedium, 8: CPUClassMedium, 16: CPUClassLarge} {
+		if got := cpuClassFor(cpus); got != want {
+			t.Errorf("cpuClassFor(%d) = %q, want %q", cpus, got, want)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package reporting
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"abc-runner/app/core/metrics"
+)
+
+// 时间序列图表尺寸
+const (
+	chartWidth   = 1100
+	chartHeight  = 260
+	chartPadding = 50
+)
+
+// chartSeries 图表中的一条折线
+type chartSeries struct {
+	Name   string
+	Color  string
+	Values []float64
+}
+
+// throughputChart 渲染吞吐量与错误率随时间变化的折线图，t翻译图例
+func throughputChart(points []metrics.TimeSeriesPoint, t func(string) string) template.HTML {
+	rps := make([]float64, len(points))
+	errorRate := make([]float64, len(points))
+	for i, point := range points {
+		rps[i] = point.RPS
+		errorRate[i] = point.ErrorRate
+	}
+
+	return renderLineChart(points, "ops/sec",
+		chartSeries{Name: "RPS", Color: "#667eea", Values: rps},
+		&chartSeries{Name: t("错误率 (%)"), Color: "#dc3545", Values: errorRate})
+}
+
+// latencyChart 渲染P95延迟随时间变化的折线图，t翻译图例
+func latencyChart(points []metrics.TimeSeriesPoint, t func(string) string) template.HTML {
+	p95 := make([]float64, len(points))
+	for i, point := range points {
+		p95[i] = float64(point.P95.Microseconds()) / 1000
+	}
+
+	return renderLineChart(points, "ms",
+		chartSeries{Name: "P95", Color: "#28a745", Values: p95}, nil)
+}
+
+// renderLineChart 渲染内联SVG折线图，secondary非空时使用右侧独立坐标轴
+func renderLineChart(points []metrics.TimeSeriesPoint, unit string, primary chartSeries, secondary *chartSeries) template.HTML {
+	if len(points) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg viewBox="0 0 %d %d" width="100%%" xmlns="http://www.w3.org/2000/svg" font-size="12" font-family="sans-serif">`,
+		chartWidth, chartHeight)
+
+	plotWidth := float64(chartWidth - 2*chartPadding)
+	plotHeight := float64(chartHeight - 2*chartPadding)
+	bottom := float64(chartHeight - chartPadding)
+
+	// 坐标轴与网格
+	fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%.0f" stroke="#999"/>`, chartPadding, chartPadding, chartPadding, bottom)
+	fmt.Fprintf(&b, `<line x1="%d" y1="%.0f" x2="%d" y2="%.0f" stroke="#999"/>`, chartPadding, bottom, chartWidth-chartPadding, bottom)
+	primaryMax := maxValue(primary.Values)
+	for i := 0; i <= 4; i++ {
+		y := bottom - plotHeight*float64(i)/4
+		fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#eee"/>`, chartPadding, y, chartWidth-chartPadding, y)
+		fmt.Fprintf(&b, `<text x="%d" y="%.1f" text-anchor="end" fill="%s">%s</text>`,
+			chartPadding-5, y+4, primary.Color, formatChartValue(primaryMax*float64(i)/4))
+	}
+	fmt.Fprintf(&b, `<text x="%d" y="%d" fill="#666">%s</text>`, chartPadding, chartPadding-10, template.HTMLEscapeString(unit))
+
+	// X轴标签：起止时间
+	first, last := points[0].Elapsed, points[len(points)-1].Elapsed
+	fmt.Fprintf(&b, `<text x="%d" y="%.0f" fill="#666">%s</text>`, chartPadding, bottom+20, first.Round(time.Second))
+	fmt.Fprintf(&b, `<text x="%d" y="%.0f" text-anchor="end" fill="#666">%s</text>`, chartWidth-chartPadding, bottom+20, last.Round(time.Second))
+
+	xOf := func(i int) float64 {
+		if len(points) == 1 {
+			return chartPadding + plotWidth/2
+		}
+		return chartPadding + plotWidth*float64(i)/float64(len(points)-1)
+	}
+	writePolyline(&b, primary, primaryMax, xOf, bottom, plotHeight)
+
+	legend := fmt.Sprintf(`<tspan fill="%s">● %s</tspan>`, primary.Color, template.HTMLEscapeString(primary.Name))
+	if secondary != nil {
+		secondaryMax := maxValue(secondary.Values)
+		for i := 0; i <= 4; i++ {
+			y := bottom - plotHeight*float64(i)/4
+			fmt.Fprintf(&b, `<text x="%d" y="%.1f" fill="%s">%s</text>`,
+				chartWidth-chartPadding+5, y+4, secondary.Color, formatChartValue(secondaryMax*float64(i)/4))
+		}
+		writePolyline(&b, *secondary, secondaryMax, xOf, bottom, plotHeight)
+		legend += fmt.Sprintf(` <tspan fill="%s">● %s</tspan>`, secondary.Color, template.HTMLEscapeString(secondary.Name))
+	}
+	fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="end">%s</text>`, chartWidth-chartPadding, chartPadding-10, legend)
+
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
+
+// writePolyline 按比例绘制一条折线
+func writePolyline(b *strings.Builder, series chartSeries, max float64, xOf func(int) float64, bottom, plotHeight float64) {
+	coords := make([]string, len(series.Values))
+	for i, value := range series.Values {
+		y := bottom
+		if max > 0 {
+			y = bottom - plotHeight*value/max
+		}
+		coords[i] = fmt.Sprintf("%.1f,%.1f", xOf(i), y)
+	}
+	fmt.Fprintf(b, `<polyline fill="none" stroke="%s" stroke-width="2" points="%s"/>`, series.Color, strings.Join(coords, " "))
+}
+
+// maxValue 获取最大值，全部为0时返回0
+func maxValue(values []float64) float64 {
+	var max float64
+	for _, value := range values {
+		if value > max {
+			max = value
+		}
+	}
+	return max
+}
+
+// formatChartValue 格式化坐标轴刻度
+func formatChartValue(value float64) string {
+	switch {
+	case value >= 1000000:
+		return fmt.Sprintf("%.1fM", value/1000000)
+	case value >= 10000:
+		return fmt.Sprintf("%.1fk", value/1000)
+	case value >= 100:
+		return fmt.Sprintf("%.0f", value)
+	default:
+		return fmt.Sprintf("%.2f", value)
+	}
+}
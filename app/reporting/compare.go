@@ -0,0 +1,304 @@
+package reporting
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 可比较的指标名称
+const (
+	CompareThroughput = "throughput"
+	CompareAverage    = "avg"
+	CompareP50        = "p50"
+	CompareP90        = "p90"
+	CompareP95        = "p95"
+	CompareP99        = "p99"
+	CompareP999       = "p999"
+	CompareErrorRate  = "error_rate"
+)
+
+// CompareMetrics 参与比较的指标，按输出顺序排列
+var CompareMetrics = []string{
+	CompareThroughput, CompareAverage, CompareP50, CompareP90,
+	CompareP95, CompareP99, CompareP999, CompareErrorRate,
+}
+
+// RegressionThresholds 回归判定阈值
+type RegressionThresholds struct {
+	// MaxThroughputDrop 吞吐量允许下降的百分比
+	MaxThroughputDrop float64 `json:"max_throughput_drop"`
+
+	// MaxLatencyIncrease 延迟（平均值与各分位数）允许上升的百分比
+	MaxLatencyIncrease float64 `json:"max_latency_increase"`
+
+	// MaxErrorRateIncrease 错误率允许上升的百分点
+	MaxErrorRateIncrease float64 `json:"max_error_rate_increase"`
+
+	// MinLatencyDelta 延迟变化低于该绝对值时不视为回归，用于过滤亚毫秒级抖动
+	MinLatencyDelta time.Duration `json:"min_latency_delta"`
+
+	// Overrides 按指标覆盖的阈值（百分比，错误率为百分点）
+	Overrides map[string]float64 `json:"overrides,omitempty"`
+}
+
+// DefaultRegressionThresholds 默认回归判定阈值
+func DefaultRegressionThresholds() RegressionThresholds {
+	return RegressionThresholds{
+		MaxThroughputDrop:    5,
+		MaxLatencyIncrease:   10,
+		MaxErrorRateIncrease: 1,
+		Overrides:            make(map[string]float64),
+	}
+}
+
+// thresholdFor 获取指标的阈值
+func (t RegressionThresholds) thresholdFor(metric string) float64 {
+	if value, ok := t.Overrides[metric]; ok {
+		return value
+	}
+	switch metric {
+	case CompareThroughput:
+		return t.MaxThroughputDrop
+	case CompareErrorRate:
+		return t.MaxErrorRateIncrease
+	default:
+		return t.MaxLatencyIncrease
+	}
+}
+
+// MetricDelta 单个指标的对比结果
+type MetricDelta struct {
+	Metric     string  `json:"metric"`
+	Baseline   float64 `json:"baseline"`
+	Current    float64 `json:"current"`
+	Delta      float64 `json:"delta"`      // 绝对变化
+	Change     float64 `json:"change"`     // 相对变化(%)，错误率为百分点变化
+	Threshold  float64 `json:"threshold"`  // 判定阈值
+	Comparable bool    `json:"comparable"` // 基线缺失该指标时为false
+	Improved   bool    `json:"improved"`   // 是否优于基线
+	Regression bool    `json:"regression"` // 是否判定为回归
+	Unit       string  `json:"unit"`       // ops/s、ns或%
+	Note       string  `json:"note,omitempty"`
+}
+
+// ComparisonResult 基线对比结果
+type ComparisonResult struct {
+	BaselineProtocol string               `json:"baseline_protocol"`
+	CurrentProtocol  string               `json:"current_protocol"`
+	Thresholds       RegressionThresholds `json:"thresholds"`
+	Deltas           []MetricDelta        `json:"deltas"`
+}
+
+// Regressions 返回判定为回归的指标
+func (r *ComparisonResult) Regressions() []MetricDelta {
+	var regressions []MetricDelta
+	for _, delta := range r.Deltas {
+		if delta.Regression {
+			regressions = append(regressions, delta)
+		}
+	}
+	return regressions
+}
+
+// HasRegression 是否存在回归
+func (r *ComparisonResult) HasRegression() bool {
+	return len(r.Regressions()) > 0
+}
+
+// LoadStructuredReport 从JSON文件加载结构化报告
+func LoadStructuredReport(path string) (*StructuredReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report %s: %w", path, err)
+	}
+
+	var report StructuredReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse report %s: %w", path, err)
+	}
+	return &report, nil
+}
+
+// CompareReports 对比基线报告与当前报告
+func CompareReports(baseline, current *StructuredReport, thresholds RegressionThresholds) *ComparisonResult {
+	result := &ComparisonResult{
+		BaselineProtocol: baseline.Context.TestConfiguration.Protocol,
+		CurrentProtocol:  current.Context.TestConfiguration.Protocol,
+		Thresholds:       thresholds,
+	}
+
+	for _, metric := range CompareMetrics {
+		result.Deltas = append(result.Deltas, compareMetric(metric,
+			compareValue(baseline, metric), compareValue(current, metric), thresholds))
+	}
+	return result
+}
+
+// compareValue 从报告中提取指标值
+func compareValue(report *StructuredReport, metric string) float64 {
+	latency := report.Metrics.LatencyAnalysis
+	switch metric {
+	case CompareThroughput:
+		return report.Metrics.CoreOperations.OperationsPerSecond
+	case CompareAverage:
+		return float64(latency.AverageLatency)
+	case CompareP50:
+		return float64(latency.Percentiles.P50)
+	case CompareP90:
+		return float64(latency.Percentiles.P90)
+	case CompareP95:
+		return float64(latency.Percentiles.P95)
+	case CompareP99:
+		return float64(latency.Percentiles.P99)
+	case CompareP999:
+		return float64(latency.Percentiles.P999)
+	case CompareErrorRate:
+		return report.Metrics.CoreOperations.ErrorRate
+	}
+	return 0
+}
+
+// compareMetric 计算单个指标的变化并判定是否回归
+func compareMetric(metric string, baseline, current float64, thresholds RegressionThresholds) MetricDelta {
+	delta := MetricDelta{
+		Metric:     metric,
+		Baseline:   baseline,
+		Current:    current,
+		Delta:      current - baseline,
+		Threshold:  thresholds.thresholdFor(metric),
+		Comparable: true,
+	}
+
+	switch metric {
+	case CompareErrorRate:
+		// 错误率按百分点比较，基线为0时同样有效
+		delta.Unit = "%"
+		delta.Change = delta.Delta
+		delta.Improved = delta.Delta < 0
+		delta.Regression = delta.Delta > delta.Threshold
+
+	case CompareThroughput:
+		delta.Unit = "ops/s"
+		if baseline <= 0 {
+			delta.Comparable = false
+			delta.Note = "no baseline"
+			break
+		}
+		delta.Change = delta.Delta / baseline * 100
+		delta.Improved = delta.Delta > 0
+		delta.Regression = -delta.Change > delta.Threshold
+
+	default:
+		delta.Unit = "ns"
+		if baseline <= 0 {
+			delta.Comparable = false
+			delta.Note = "no baseline"
+			break
+		}
+		delta.Change = delta.Delta / baseline * 100
+		delta.Improved = delta.Delta < 0
+		delta.Regression = delta.Change > delta.Threshold
+		if delta.Regression && time.Duration(delta.Delta) < thresholds.MinLatencyDelta {
+			delta.Regression = false
+			delta.Note = "below min latency delta"
+		}
+	}
+	return delta
+}
+
+// FormatComparisonTable 将对比结果格式化为文本表格
+func FormatComparisonTable(result *ComparisonResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-12s %14s %14s %14s %10s %10s  %s\n", "METRIC", "BASELINE", "CURRENT", "DELTA", "CHANGE", "THRESHOLD", "STATUS")
+
+	for _, delta := range result.Deltas {
+		change, threshold := "n/a", ""
+		if delta.Comparable {
+			if delta.Metric == CompareErrorRate {
+				change = fmt.Sprintf("%+.2fpp", delta.Change)
+				threshold = fmt.Sprintf("+%.2fpp", delta.Threshold)
+			} else {
+				change = fmt.Sprintf("%+.2f%%", delta.Change)
+				if delta.Metric == CompareThroughput {
+					threshold = fmt.Sprintf("-%.2f%%", delta.Threshold)
+				} else {
+					threshold = fmt.Sprintf("+%.2f%%", delta.Threshold)
+				}
+			}
+		}
+
+		status := "✅ ok"
+		switch {
+		case delta.Regression:
+			status = "❌ REGRESSION"
+		case !delta.Comparable:
+			status = "➖ skipped"
+		case delta.Improved:
+			status = "⬆️  improved"
+		}
+		if delta.Note != "" {
+			status += " (" + delta.Note + ")"
+		}
+
+		fmt.Fprintf(&b, "%-12s %14s %14s %14s %10s %10s  %s\n", delta.Metric,
+			formatCompareValue(delta, delta.Baseline), formatCompareValue(delta, delta.Current),
+			formatCompareDelta(delta), change, threshold, status)
+	}
+	return b.String()
+}
+
+// formatCompareValue 按单位格式化指标值
+func formatCompareValue(delta MetricDelta, value float64) string {
+	switch delta.Unit {
+	case "ns":
+		return time.Duration(value).Round(time.Microsecond).String()
+	case "%":
+		return fmt.Sprintf("%.2f%%", value)
+	default:
+		return fmt.Sprintf("%.2f", value)
+	}
+}
+
+// formatCompareDelta 格式化带符号的绝对变化
+func formatCompareDelta(delta MetricDelta) string {
+	value := formatCompareValue(delta, delta.Delta)
+	if delta.Delta >= 0 {
+		return "+" + value
+	}
+	return value
+}
+
+// ParseThresholdOverride 解析 "metric=value" 形式的阈值覆盖
+func ParseThresholdOverride(spec string) (string, float64, error) {
+	name, value, ok := strings.Cut(spec, "=")
+	if !ok {
+		return "", 0, fmt.Errorf("invalid threshold %q (expected metric=value)", spec)
+	}
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !isCompareMetric(name) {
+		metrics := append([]string(nil), CompareMetrics...)
+		sort.Strings(metrics)
+		return "", 0, fmt.Errorf("unknown metric %q in threshold (expected one of %s)", name, strings.Join(metrics, ", "))
+	}
+
+	threshold, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(value), "%"), 64)
+	if err != nil || threshold < 0 {
+		return "", 0, fmt.Errorf("invalid threshold value %q for %s", value, name)
+	}
+	return name, threshold, nil
+}
+
+// isCompareMetric 判断是否为可比较的指标
+func isCompareMetric(name string) bool {
+	for _, metric := range CompareMetrics {
+		if metric == name {
+			return true
+		}
+	}
+	return false
+}
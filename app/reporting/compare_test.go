@@ -0,0 +1,66 @@
+package reporting
+
+import (
+	"testing"
+	"time"
+)
+
+func compareTestReport(rps float64, p99 time.Duration, errorRate float64) *StructuredReport {
+	report := &StructuredReport{}
+	report.Context.TestConfiguration.Protocol = "redis"
+	report.Metrics.CoreOperations.OperationsPerSecond = rps
+	report.Metrics.CoreOperations.ErrorRate = errorRate
+	report.Metrics.LatencyAnalysis.AverageLatency = p99 / 4
+	report.Metrics.LatencyAnalysis.Percentiles = LatencyPercentiles{
+		P50: p99 / 4, P90: p99 / 2, P95: p99 * 3 / 4, P99: p99,
+	}
+	return report
+}
+
+func TestCompareReports(t *testing.T) {
+	baseline := compareTestReport(10000, 4*time.Millisecond, 0.5)
+
+	// 吞吐量下降3%、P99上升5%：均在默认阈值内；基线缺少P99.9
+	result := CompareReports(baseline, compareTestReport(9700, 4200*time.Microsecond, 1.0), DefaultRegressionThresholds())
+	if result.HasRegression() {
+		t.Fatalf("unexpected regressions: %+v", result.Regressions())
+	}
+	for _, delta := range result.Deltas {
+		if delta.Metric == CompareP999 && delta.Comparable {
+			t.Errorf("expected p999 without baseline to be skipped")
+		}
+	}
+
+	// 吞吐量下降20%、延迟上升50%、错误率上升2个百分点
+	result = CompareReports(baseline, compareTestReport(8000, 6*time.Millisecond, 2.5), DefaultRegressionThresholds())
+	regressed := make(map[string]bool)
+	for _, delta := range result.Regressions() {
+		regressed[delta.Metric] = true
+	}
+	for _, metric := range []string{CompareThroughput, CompareAverage, CompareP50, CompareP90, CompareP95, CompareP99, CompareErrorRate} {
+		if !regressed[metric] {
+			t.Errorf("expected %s to regress", metric)
+		}
+	}
+
+	// 按指标覆盖阈值，并忽略小于3ms的延迟变化
+	thresholds := DefaultRegressionThresholds()
+	thresholds.Overrides[CompareThroughput] = 25
+	thresholds.Overrides[CompareErrorRate] = 5
+	thresholds.MinLatencyDelta = 3 * time.Millisecond
+	result = CompareReports(baseline, compareTestReport(8000, 6*time.Millisecond, 2.5), thresholds)
+	if regressions := result.Regressions(); len(regressions) != 0 {
+		t.Errorf("expected overrides to suppress regressions, got %+v", regressions)
+	}
+}
+
+func TestParseThresholdOverride(t *testing.T) {
+	if metric, value, err := ParseThresholdOverride("P99=7.5%"); err != nil || metric != CompareP99 || value != 7.5 {
+		t.Errorf("unexpected result: %s %v %v", metric, value, err)
+	}
+	for _, spec := range []string{"p99", "p42=5", "p99=-1", "p99=abc"} {
+		if _, _, err := ParseThresholdOverride(spec); err == nil {
+			t.Errorf("expected error for %q", spec)
+		}
+	}
+}
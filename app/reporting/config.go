@@ -13,7 +13,7 @@ func NewStandardReportConfig(protocolPrefix string) *RenderConfig {
 
 // GetSupportedFormats 获取支持的报告格式列表
 func GetSupportedFormats() []string {
-	return []string{"console", "json", "csv", "html"}
+	return []string{"console", "json", "csv", "html", "junit"}
 }
 
 // GetDefaultOutputDir 获取默认输出目录
@@ -0,0 +1,391 @@
+package reporting
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite" // 纯Go实现的SQLite驱动，无需cgo
+)
+
+// DefaultHistoryDir 运行历史的默认目录
+const DefaultHistoryDir = "reports/history"
+
+// 运行历史的状态筛选
+const (
+	HistoryPassed = "passed"
+	HistoryFailed = "failed"
+)
+
+// RunRecord 运行历史中的一次运行：运行结果摘要、命令参数、标签、生成的报告文件与完整的结构化报告
+type RunRecord struct {
+	ID string `json:"id"`
+	RunResult
+	Args    []string          `json:"args,omitempty"`
+	Tags    []string          `json:"tags,omitempty"`
+	Reports []string          `json:"reports,omitempty"`
+	Report  *StructuredReport `json:"report,omitempty"` // 未生成报告时为空
+}
+
+// Protocol 运行的协议，未生成报告时为命令名
+func (r *RunRecord) Protocol() string {
+	if r.Summary != nil && r.Summary.Protocol != "" {
+		return r.Summary.Protocol
+	}
+	return r.Command
+}
+
+// HistoryMetrics 可在运行之间比较的指标，延迟单位为毫秒，错误率为百分比
+var HistoryMetrics = []string{"rps", "avg", "p50", "p90", "p95", "p99", "max", "error_rate", "read_mbps", "write_mbps"}
+
+// Metric 返回运行的指定指标；运行未生成报告，或指标只在完整报告中而记录早于完整报告入库时返回false
+func (r *RunRecord) Metric(name string) (float64, bool) {
+	if report := r.Report; report != nil {
+		ops := report.Metrics.CoreOperations
+		latency := report.Metrics.LatencyAnalysis
+		switch name {
+		case "p90":
+			return milliseconds(latency.Percentiles.P90), true
+		case "read_mbps":
+			return ops.ReadMBPerSec, true
+		case "write_mbps":
+			return ops.WriteMBPerSec, true
+		}
+	}
+	summary := r.Summary
+	if summary == nil {
+		return 0, false
+	}
+	switch name {
+	case "rps":
+		return summary.RPS, true
+	case "avg":
+		return summary.AvgLatency, true
+	case "p50":
+		return summary.P50, true
+	case "p95":
+		return summary.P95, true
+	case "p99":
+		return summary.P99, true
+	case "max":
+		return summary.MaxLatency, true
+	case "error_rate":
+		return summary.ErrorRate, true
+	}
+	return 0, false
+}
+
+// runIDPattern 运行ID的格式
+var runIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// NewRunID 生成运行ID：开始时间加随机后缀
+func NewRunID(startedAt time.Time) string {
+	return fmt.Sprintf("%s-%04x", startedAt.Format("20060102-150405"), rand.Intn(0x10000))
+}
+
+// ValidRunID 运行ID是否只包含字母、数字、"."、"_"与"-"
+func ValidRunID(id string) bool {
+	return runIDPattern.MatchString(id)
+}
+
+// HistoryFilter 运行历史的筛选条件，零值匹配全部记录
+type HistoryFilter struct {
+	Protocol string    // 协议或命令名
+	Tags     []string  // 须包含全部标签
+	Since    time.Time // 开始时间不早于
+	Until    time.Time // 开始时间早于
+	Status   string    // passed、failed或空
+}
+
+// Empty 是否未设置任何条件
+func (f HistoryFilter) Empty() bool {
+	return f.Protocol == "" && len(f.Tags) == 0 && f.Since.IsZero() && f.Until.IsZero() && f.Status == ""
+}
+
+// where 生成筛选条件对应的SQL条件与参数，零值返回空条件
+func (f HistoryFilter) where() (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if f.Protocol != "" {
+		conditions = append(conditions, "(protocol = ? COLLATE NOCASE OR command = ? COLLATE NOCASE)")
+		args = append(args, f.Protocol, f.Protocol)
+	}
+	for _, tag := range f.Tags {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM run_tags WHERE run_tags.run_id = runs.id AND run_tags.tag = ?)")
+		args = append(args, tag)
+	}
+	if !f.Since.IsZero() {
+		conditions = append(conditions, "started_at >= ?")
+		args = append(args, f.Since.UnixNano())
+	}
+	if !f.Until.IsZero() {
+		conditions = append(conditions, "started_at < ?")
+		args = append(args, f.Until.UnixNano())
+	}
+	switch f.Status {
+	case HistoryPassed:
+		conditions = append(conditions, "passed = 1")
+	case HistoryFailed:
+		conditions = append(conditions, "passed = 0")
+	}
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// HistoryDatabase 运行历史目录中的SQLite数据库文件名
+const HistoryDatabase = "history.db"
+
+// historySchema 运行历史的表结构：runs保存每次运行的摘要列、记录与完整的结构化报告（JSON），run_tags保存标签
+const historySchema = `
+CREATE TABLE IF NOT EXISTS runs (
+	id         TEXT PRIMARY KEY,
+	command    TEXT NOT NULL,
+	protocol   TEXT NOT NULL,
+	started_at INTEGER NOT NULL,
+	passed     INTEGER NOT NULL,
+	record     TEXT NOT NULL,
+	report     TEXT
+);
+CREATE INDEX IF NOT EXISTS runs_started_at ON runs (started_at);
+CREATE TABLE IF NOT EXISTS run_tags (
+	run_id TEXT NOT NULL,
+	tag    TEXT NOT NULL,
+	PRIMARY KEY (run_id, tag)
+);
+CREATE INDEX IF NOT EXISTS run_tags_tag ON run_tags (tag);
+`
+
+// HistoryStore 本地运行历史，保存在历史目录下的SQLite数据库中
+type HistoryStore struct {
+	dir string
+}
+
+// NewHistoryStore 创建运行历史，目录与数据库在首次写入时创建
+func NewHistoryStore(dir string) *HistoryStore {
+	return &HistoryStore{dir: dir}
+}
+
+// Path 运行历史数据库路径
+func (s *HistoryStore) Path() string {
+	return filepath.Join(s.dir, HistoryDatabase)
+}
+
+// Save 写入一条记录，未设置ID时按开始时间生成，已存在的同ID记录被替换
+func (s *HistoryStore) Save(record *RunRecord) error {
+	if record.ID == "" {
+		record.ID = NewRunID(record.StartedAt)
+	}
+	db, err := s.open(true)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	if err := saveRecord(db, record); err != nil {
+		return fmt.Errorf("failed to write run record %s: %w", record.ID, err)
+	}
+	return nil
+}
+
+// List 按开始时间从新到旧返回满足条件的记录
+func (s *HistoryStore) List(filter HistoryFilter) ([]*RunRecord, error) {
+	db, err := s.open(false)
+	if err != nil || db == nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	where, args := filter.where()
+	return queryRecords(db, "SELECT record, report FROM runs"+where+" ORDER BY started_at DESC, id DESC", args...)
+}
+
+// Load 按ID或唯一的ID前缀读取记录
+func (s *HistoryStore) Load(id string) (*RunRecord, error) {
+	db, err := s.open(false)
+	if err != nil {
+		return nil, err
+	}
+	if db == nil {
+		return nil, fmt.Errorf("run %q not found in %s", id, s.Path())
+	}
+	defer db.Close()
+
+	records, err := queryRecords(db, "SELECT record, report FROM runs WHERE id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(id)
+		records, err = queryRecords(db, `SELECT record, report FROM runs WHERE id LIKE ? ESCAPE '\' ORDER BY started_at DESC, id DESC`, escaped+"%")
+		if err != nil {
+			return nil, err
+		}
+	}
+	switch len(records) {
+	case 0:
+		return nil, fmt.Errorf("run %q not found in %s", id, s.Path())
+	case 1:
+		return records[0], nil
+	default:
+		return nil, fmt.Errorf("run ID prefix %q is ambiguous (%d runs match)", id, len(records))
+	}
+}
+
+// Delete 删除记录，removeReports为true时一并删除其报告文件，已不存在的报告文件被忽略
+func (s *HistoryStore) Delete(record *RunRecord, removeReports bool) error {
+	if removeReports {
+		for _, report := range record.Reports {
+			if err := os.Remove(report); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("failed to delete report %s: %w", report, err)
+			}
+		}
+	}
+	db, err := s.open(false)
+	if err != nil {
+		return err
+	}
+	if db == nil {
+		return fmt.Errorf("failed to delete run %s: not found in %s", record.ID, s.Path())
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to delete run %s: %w", record.ID, err)
+	}
+	defer tx.Rollback()
+	result, err := tx.Exec("DELETE FROM runs WHERE id = ?", record.ID)
+	if err == nil {
+		_, err = tx.Exec("DELETE FROM run_tags WHERE run_id = ?", record.ID)
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete run %s: %w", record.ID, err)
+	}
+	if deleted, _ := result.RowsAffected(); deleted == 0 {
+		return fmt.Errorf("failed to delete run %s: not found in %s", record.ID, s.Path())
+	}
+	return nil
+}
+
+// open 打开运行历史数据库，数据库不存在且create为false时返回nil
+func (s *HistoryStore) open(create bool) (*sql.DB, error) {
+	if _, err := os.Stat(s.Path()); errors.Is(err, os.ErrNotExist) {
+		if !create {
+			return nil, nil
+		}
+		if err := os.MkdirAll(s.dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create history directory: %w", err)
+		}
+	}
+
+	db, err := sql.Open("sqlite", s.Path()+"?_pragma=busy_timeout(5000)")
+	if err == nil {
+		_, err = db.Exec(historySchema)
+	}
+	if err != nil {
+		if db != nil {
+			db.Close()
+		}
+		return nil, fmt.Errorf("failed to open run history %s: %w", s.Path(), err)
+	}
+	return db, nil
+}
+
+// saveRecord 在一个事务中写入记录与其标签
+func saveRecord(db *sql.DB, record *RunRecord) error {
+	stored := *record
+	stored.Report = nil
+	data, err := json.Marshal(&stored)
+	if err != nil {
+		return fmt.Errorf("failed to encode run record: %w", err)
+	}
+	var report interface{}
+	if record.Report != nil {
+		encoded, err := json.Marshal(record.Report)
+		if err != nil {
+			return fmt.Errorf("failed to encode report: %w", err)
+		}
+		report = string(encoded)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec("INSERT OR REPLACE INTO runs (id, command, protocol, started_at, passed, record, report) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		record.ID, record.Command, record.Protocol(), record.StartedAt.UnixNano(), record.Passed, string(data), report); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM run_tags WHERE run_id = ?", record.ID); err != nil {
+		return err
+	}
+	for _, tag := range record.Tags {
+		if _, err := tx.Exec("INSERT OR IGNORE INTO run_tags (run_id, tag) VALUES (?, ?)", record.ID, tag); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// queryRecords 执行查询并解析每行的记录与报告
+func queryRecords(db *sql.DB, query string, args ...interface{}) ([]*RunRecord, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query run history: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*RunRecord
+	for rows.Next() {
+		var data string
+		var report sql.NullString
+		if err := rows.Scan(&data, &report); err != nil {
+			return nil, fmt.Errorf("failed to read run history: %w", err)
+		}
+		var record RunRecord
+		if err := json.Unmarshal([]byte(data), &record); err != nil {
+			return nil, fmt.Errorf("failed to parse run record: %w", err)
+		}
+		if report.Valid {
+			record.Report = &StructuredReport{}
+			if err := json.Unmarshal([]byte(report.String), record.Report); err != nil {
+				return nil, fmt.Errorf("failed to parse report of run %s: %w", record.ID, err)
+			}
+		}
+		records = append(records, &record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read run history: %w", err)
+	}
+	return records, nil
+}
+
+// historyStoreKey context键
+type historyStoreKey struct{}
+
+// WithHistoryStore 返回携带运行历史的context
+func WithHistoryStore(ctx context.Context, store *HistoryStore) context.Context {
+	return context.WithValue(ctx, historyStoreKey{}, store)
+}
+
+// HistoryStoreFromContext 从context中获取运行历史
+func HistoryStoreFromContext(ctx context.Context) (*HistoryStore, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	store, ok := ctx.Value(historyStoreKey{}).(*HistoryStore)
+	return store, ok && store != nil
+}
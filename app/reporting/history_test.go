@@ -0,0 +1,148 @@
+package reporting
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHistoryStore(t *testing.T) {
+	dir := t.TempDir()
+	store := NewHistoryStore(filepath.Join(dir, "history"))
+
+	if records, err := store.List(HistoryFilter{}); err != nil || len(records) != 0 {
+		t.Fatalf("expected empty history before first save, got %v, %v", records, err)
+	}
+
+	report := filepath.Join(dir, "redis_report.json")
+	if err := os.WriteFile(report, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	base := time.Date(2026, 1, 31, 14, 0, 0, 0, time.UTC)
+	runs := []*RunRecord{
+		{ID: "run-redis", RunResult: RunResult{Command: "redis", Passed: true, StartedAt: base,
+			Summary: &ResultSummary{Protocol: "redis"}}, Tags: []string{"nightly", "v2"}, Reports: []string{report}},
+		{ID: "run-http", RunResult: RunResult{Command: "h", ExitCode: 3, StartedAt: base.Add(time.Hour),
+			Summary: &ResultSummary{Protocol: "http"}}, Tags: []string{"nightly"}},
+		{RunResult: RunResult{Command: "compare", ExitCode: 1, StartedAt: base.Add(2 * time.Hour)}},
+	}
+	for _, run := range runs {
+		if err := store.Save(run); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+	if runs[2].ID == "" {
+		t.Fatal("expected generated ID")
+	}
+
+	all, err := store.List(HistoryFilter{})
+	if err != nil || len(all) != 3 || all[0].ID != runs[2].ID || all[2].ID != "run-redis" {
+		t.Fatalf("expected 3 runs newest first, got %v, %v", all, err)
+	}
+
+	cases := []struct {
+		name   string
+		filter HistoryFilter
+		want   []string
+	}{
+		{"protocol", HistoryFilter{Protocol: "HTTP"}, []string{"run-http"}},
+		{"command", HistoryFilter{Protocol: "compare"}, []string{runs[2].ID}},
+		{"tags", HistoryFilter{Tags: []string{"nightly", "v2"}}, []string{"run-redis"}},
+		{"since", HistoryFilter{Since: base.Add(time.Hour)}, []string{runs[2].ID, "run-http"}},
+		{"until", HistoryFilter{Until: base.Add(time.Hour)}, []string{"run-redis"}},
+		{"passed", HistoryFilter{Status: HistoryPassed}, []string{"run-redis"}},
+		{"failed", HistoryFilter{Status: HistoryFailed, Tags: []string{"nightly"}}, []string{"run-http"}},
+	}
+	for _, tc := range cases {
+		records, err := store.List(tc.filter)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		var ids []string
+		for _, record := range records {
+			ids = append(ids, record.ID)
+		}
+		if len(ids) != len(tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, ids, tc.want)
+			continue
+		}
+		for i := range ids {
+			if ids[i] != tc.want[i] {
+				t.Errorf("%s: got %v, want %v", tc.name, ids, tc.want)
+				break
+			}
+		}
+	}
+
+	loaded, err := store.Load("run-r")
+	if err != nil || loaded.ID != "run-redis" || loaded.Protocol() != "redis" || len(loaded.Reports) != 1 {
+		t.Fatalf("Load by prefix: %+v, %v", loaded, err)
+	}
+	if _, err := store.Load("run-"); err == nil {
+		t.Error("expected ambiguous prefix error")
+	}
+	if _, err := store.Load("missing"); err == nil {
+		t.Error("expected not found error")
+	}
+	// 前缀中的"_"与"%"按字面匹配
+	if _, err := store.Load("run_"); err == nil {
+		t.Error("expected LIKE wildcards in the prefix to match literally")
+	}
+
+	if err := store.Delete(loaded, true); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := os.Stat(report); !os.IsNotExist(err) {
+		t.Error("expected report file to be deleted")
+	}
+	if remaining, _ := store.List(HistoryFilter{}); len(remaining) != 2 {
+		t.Errorf("expected 2 runs after delete, got %d", len(remaining))
+	}
+	if tagged, _ := store.List(HistoryFilter{Tags: []string{"v2"}}); len(tagged) != 0 {
+		t.Errorf("expected tags of the deleted run to be removed, got %d runs", len(tagged))
+	}
+
+	// 同ID再次写入时替换记录与标签
+	runs[1].Tags = []string{"rerun"}
+	if err := store.Save(runs[1]); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if tagged, _ := store.List(HistoryFilter{Tags: []string{"nightly"}}); len(tagged) != 0 {
+		t.Errorf("expected replaced tags, got %d nightly runs", len(tagged))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "history", HistoryDatabase)); err != nil {
+		t.Errorf("expected history database: %v", err)
+	}
+}
+
+func TestRunRecord_Metric(t *testing.T) {
+	summaryOnly := &RunRecord{RunResult: RunResult{Summary: &ResultSummary{RPS: 1200, P99: 4.5, ErrorRate: 0.2}}}
+	if value, ok := summaryOnly.Metric("p99"); !ok || value != 4.5 {
+		t.Errorf("p99 = %v, %v", value, ok)
+	}
+	// 完整报告入库之前的记录没有P90与带宽
+	if _, ok := summaryOnly.Metric("p90"); ok {
+		t.Error("expected p90 to be unavailable without the full report")
+	}
+
+	report := &StructuredReport{}
+	report.Metrics.LatencyAnalysis.Percentiles.P90 = 2500 * time.Microsecond
+	report.Metrics.CoreOperations.ReadMBPerSec = 12.5
+	full := &RunRecord{RunResult: RunResult{Summary: NewResultSummary(report)}, Report: report}
+	if value, ok := full.Metric("p90"); !ok || value != 2.5 {
+		t.Errorf("p90 = %v, %v", value, ok)
+	}
+	if value, ok := full.Metric("read_mbps"); !ok || value != 12.5 {
+		t.Errorf("read_mbps = %v, %v", value, ok)
+	}
+
+	if _, ok := (&RunRecord{}).Metric("rps"); ok {
+		t.Error("expected no metrics for a run without a report")
+	}
+	for _, name := range HistoryMetrics {
+		if _, ok := full.Metric(name); !ok {
+			t.Errorf("metric %s not available on a full record", name)
+		}
+	}
+}
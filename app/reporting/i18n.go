@@ -0,0 +1,145 @@
+package reporting
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 报告语言
+const (
+	LocaleZH      = "zh"
+	LocaleEN      = "en"
+	DefaultLocale = LocaleZH
+)
+
+// SupportedLocales 支持的报告语言
+func SupportedLocales() []string {
+	return []string{LocaleZH, LocaleEN}
+}
+
+// NormalizeLocale 将语言标签（如en、en-US、zh_CN、EN）规范为支持的报告语言，空值为默认语言
+func NormalizeLocale(value string) (string, error) {
+	if value == "" {
+		return DefaultLocale, nil
+	}
+	base, _, _ := strings.Cut(strings.ToLower(value), "-")
+	base, _, _ = strings.Cut(base, "_")
+	for _, locale := range SupportedLocales() {
+		if base == locale {
+			return locale, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported locale %q (expected %s)", value, strings.Join(SupportedLocales(), " or "))
+}
+
+// messageCatalog 以中文原文为键的译文表，与gettext一样原文即消息ID，
+// 带格式化动词的消息译文须保持动词的顺序与类型
+type messageCatalog map[string]string
+
+// messageCatalogs 各语言的译文表，中文为原文不需要译文表
+var messageCatalogs = map[string]messageCatalog{
+	LocaleEN: {
+		// 报告结构
+		"ABC-RUNNER 性能测试报告": "ABC-RUNNER PERFORMANCE TEST REPORT",
+		"ABC-Runner 性能测试报告": "ABC-Runner Performance Test Report",
+		"执行摘要":              "Executive Summary",
+		"核心性能指标":            "Core Metrics",
+		"延迟分析":              "Latency Analysis",
+		"延迟阶段分解":            "Latency by Phase",
+		"噪声基底":              "Noise Floor",
+		"系统健康状态":            "System Health",
+		"SLA断言":             "SLA Assertions",
+		"分段汇总":              "Intervals",
+		"各目标汇总":             "Per-Target Summary",
+		"时间序列":              "Time Series",
+		"基线偏离":              "Baseline Deviations",
+		"关键洞察":              "Key Insights",
+		"优化建议":              "Recommendations",
+		"报告生成时间":            "Generated at",
+
+		// 指标名称
+		"性能评分":             "Performance score",
+		"系统状态":             "System status",
+		"协议":               "Protocol",
+		"协议类型":             "Protocol",
+		"测试时长":             "Duration",
+		"生成时间":             "Generated",
+		"总操作数":             "Total operations",
+		"成功操作":             "Successful",
+		"失败操作":             "Failed",
+		"成功率":              "Success rate",
+		"错误率":              "Error rate",
+		"错误率 (%)":          "Error rate (%)",
+		"吞吐量":              "Throughput",
+		"吞吐量 (ops/sec)":    "Throughput (ops/sec)",
+		"吞吐量与错误率":          "Throughput and error rate",
+		"读取 / 写出带宽 (MB/s)": "Read / write bandwidth (MB/s)",
+		"平均延迟":             "Average latency",
+		"最小延迟":             "Min latency",
+		"最大延迟":             "Max latency",
+		"P95延迟":            "P95 latency",
+		"P99延迟":            "P99 latency",
+		"延迟百分位":            "Latency percentiles",
+		"阶段":               "Phase",
+		"次数":               "Count",
+		"平均":               "Average",
+		"最大":               "Max",
+		"占比":               "Share",
+		"基底":               "Floor",
+		"调度唤醒":             "Scheduler wakeup",
+		"定时器偏差":            "Timer overshoot",
+		"TCP回环往返":          "TCP loopback round trip",
+		"内存使用":             "Memory usage",
+		"活跃协程":             "Active goroutines",
+		"GC次数":             "GC count",
+		"结果":               "Result",
+		"规则":               "Rule",
+		"详情":               "Details",
+		"良好":               "Good",
+		"警告":               "Warning",
+		"严重":               "Critical",
+		"未知":               "Unknown",
+
+		// 带参数的消息
+		"运行被中断（%s），报告只包含中断前收集的数据":                                       "Run aborted (%s); the report only covers data collected before the abort",
+		"传输: 读取 %s (%.2f MB/s), 写出 %s (%.2f MB/s)":                      "Transferred: read %s (%.2f MB/s), written %s (%.2f MB/s)",
+		"按阶段分解 (次数, 平均/P99, 占比)":                                        "By phase (count, avg/P99, share)",
+		"基底: %v (调度唤醒P99 %v + 计时P99 %v)":                                "Floor: %v (scheduler wakeup P99 %v + timer P99 %v)",
+		"调度唤醒: P50 %v, P99 %v, 最大 %v":                                   "Scheduler wakeup: P50 %v, P99 %v, max %v",
+		"P50延迟仅为噪声基底的%.1f倍，结果中相当部分可能来自主机调度与计时噪声":                        "P50 latency is only %.1fx the noise floor; a large part of the result may be host scheduling and timer noise",
+		"操作 %-8d 吞吐量 %-10.2f 错误率 %-6.2f%% P50 %-12v P99 %v":             "ops %-8d rps %-10.2f errors %-6.2f%% P50 %-12v P99 %v",
+		"操作 %-8d 占比 %-6.2f%% 吞吐量 %-10.2f 错误率 %-6.2f%% P50 %-12v P99 %v": "ops %-8d share %-6.2f%% rps %-10.2f errors %-6.2f%% P50 %-12v P99 %v",
+		"由 ABC-Runner %s 生成 | 会话ID: %s":                                 "Generated by ABC-Runner %s | Session ID: %s",
+		baselineTooFastFormat: "P50 latency %v is below the %s reference minimum %v; the result is implausibly fast, make sure the target is not a mock or simulated data",
+		baselineTooSlowFormat: "P50 latency %v is above the %s reference maximum %v; the result is implausibly slow, check for debug logging, resource contention or a proxy/tunnel in front of the target",
+
+		// 洞察与建议
+		"高吞吐量性能":               "High throughput",
+		"系统展现出优秀的吞吐量表现":        "The system shows excellent throughput",
+		"出色的可靠性":               "Excellent reliability",
+		"系统可靠性指标优秀，成功率超过99.5%": "The success rate is above 99.5%",
+		"可靠性":      "Reliability",
+		"性能":       "Performance",
+		"调查并修复错误源": "Investigate and fix the source of errors",
+		"优化延迟性能":   "Reduce latency",
+	},
+}
+
+// translator 返回指定语言的翻译函数，译文表中没有的消息保持原文
+func translator(locale string) func(string) string {
+	catalog := messageCatalogs[locale]
+	return func(message string) string {
+		if translated, ok := catalog[message]; ok {
+			return translated
+		}
+		return message
+	}
+}
+
+// htmlLang 报告语言对应的HTML lang属性
+func htmlLang(locale string) string {
+	if locale == LocaleEN {
+		return "en"
+	}
+	return "zh-CN"
+}
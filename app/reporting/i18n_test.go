@@ -0,0 +1,90 @@
+package reporting
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+
+	"abc-runner/app/core/metrics"
+)
+
+func TestNormalizeLocale(t *testing.T) {
+	for value, want := range map[string]string{"": LocaleZH, "en": LocaleEN, "en-US": LocaleEN, "EN_gb": LocaleEN, "zh_CN": LocaleZH} {
+		if got, err := NormalizeLocale(value); err != nil || got != want {
+			t.Errorf("NormalizeLocale(%q) = %q, %v, want %q", value, got, err, want)
+		}
+	}
+	if _, err := NormalizeLocale("fr"); err == nil {
+		t.Error("expected unsupported locale to be rejected")
+	}
+}
+
+// formatVerbPattern 格式化动词（含宽度与精度）
+var formatVerbPattern = regexp.MustCompile(`%[-+# 0]*\d*(\.\d+)?[a-zA-Z%]`)
+
+func TestMessageCatalogs_FormatVerbs(t *testing.T) {
+	for locale, catalog := range messageCatalogs {
+		for message, translated := range catalog {
+			want := strings.Join(formatVerbPattern.FindAllString(message, -1), " ")
+			if got := strings.Join(formatVerbPattern.FindAllString(translated, -1), " "); got != want {
+				t.Errorf("%s: %q has verbs %q, want %q", locale, translated, got, want)
+			}
+		}
+	}
+}
+
+// localizedTestReport 包含全部可选章节的报告
+func localizedTestReport() *StructuredReport {
+	snapshot := baselineTestSnapshot("localhost:6379", "get", 2*time.Microsecond)
+	snapshot.Core.Operations = metrics.OperationMetrics{Total: 10000, Success: 9000, Failed: 1000, BytesRead: 4096, Rate: 99.9}
+	snapshot.Core.Throughput.RPS = 5000
+	snapshot.Core.Latency.Average = 200 * time.Millisecond
+	report := ConvertFromMetricsSnapshot(snapshot)
+	report.BaselineWarnings = checkBaselines(snapshot, CPUClassMedium, referenceBaselines)
+
+	report.Context.Aborted = "interrupted"
+	report.Context.NoiseFloor = &metrics.NoiseFloor{Floor: time.Millisecond, Loopback: metrics.LatencyMetrics{P50: time.Millisecond}}
+	report.Metrics.LatencyAnalysis.Percentiles.P50 = 2 * time.Millisecond
+	report.Metrics.LatencyAnalysis.Phases = []metrics.PhaseLatency{{Phase: "connect", Count: 1}}
+	report.Metrics.TimeSeries = []metrics.TimeSeriesPoint{{RPS: 10}, {RPS: 20}}
+	report.SLA = []metrics.SLAAssertion{{Name: "p99 < 10ms", Passed: true}}
+	start := time.Now()
+	report.Intervals = []metrics.IntervalSummary{{Start: start, End: start.Add(time.Second)}}
+	report.Targets = []metrics.TargetSummary{{Target: "a"}}
+	return report
+}
+
+func TestRenderers_EnglishLocale(t *testing.T) {
+	report := localizedTestReport()
+	if len(report.Dashboard.KeyInsights) == 0 || len(report.Dashboard.Recommendations) == 0 || len(report.BaselineWarnings) == 0 {
+		t.Fatalf("test report lacks insights, recommendations or baseline warnings: %+v", report.Dashboard)
+	}
+
+	console, _ := NewConsoleRenderer(LocaleEN).Render(report)
+	html, err := NewHTMLRenderer(LocaleEN).Render(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, output := range map[string]string{"console": string(console), "html": string(html)} {
+		if i := strings.IndexFunc(output, func(r rune) bool { return unicode.Is(unicode.Han, r) }); i >= 0 {
+			end := min(i+60, len(output))
+			t.Errorf("%s report has untranslated text: %q", name, output[i:end])
+		}
+	}
+	for _, want := range []string{"Executive Summary", "Run aborted (interrupted)", "below the redis-loopback-medium reference minimum", "System status: 🟡 Warning"} {
+		if !strings.Contains(string(console), want) {
+			t.Errorf("console report missing %q", want)
+		}
+	}
+	if !strings.Contains(string(html), `<html lang="en">`) || !strings.Contains(string(html), "Error rate (%)") {
+		t.Error("html report missing lang attribute or translated chart legend")
+	}
+
+	// 默认语言保持原文
+	console, _ = NewConsoleRenderer("").Render(report)
+	if !strings.Contains(string(console), "性能评分: ") || !strings.Contains(string(console), report.BaselineWarnings[0].Message) {
+		t.Errorf("default locale report changed:\n%s", console)
+	}
+}
@@ -0,0 +1,145 @@
+package reporting
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+
+	"abc-runner/app/core/metrics"
+)
+
+// JUnitRenderer JUnit XML渲染器
+// 每项SLA断言输出为一个测试用例，供Jenkins/GitLab等CI原生展示性能测试的通过情况
+type JUnitRenderer struct{}
+
+func NewJUnitRenderer() *JUnitRenderer {
+	return &JUnitRenderer{}
+}
+
+func (j *JUnitRenderer) Format() string {
+	return "junit"
+}
+
+func (j *JUnitRenderer) Extension() string {
+	return "xml"
+}
+
+// junitTestSuites JUnit XML根元素
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite 单个测试套件
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr"`
+	Hostname   string          `xml:"hostname,attr,omitempty"`
+	Properties []junitProperty `xml:"properties>property"`
+	TestCases  []junitTestCase `xml:"testcase"`
+	SystemOut  string          `xml:"system-out,omitempty"`
+}
+
+// junitProperty 套件属性
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// junitTestCase 单个测试用例
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+// junitFailure 用例失败信息
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// Render 渲染JUnit XML，未设置SLA规则时输出不含用例的空套件
+func (j *JUnitRenderer) Render(report *StructuredReport) ([]byte, error) {
+	config := report.Context.TestConfiguration
+	protocol := config.Protocol
+	if protocol == "" {
+		protocol = "unknown"
+	}
+	suiteName := "abc-runner." + protocol
+	duration := formatJUnitSeconds(config.TestDuration)
+
+	suite := junitTestSuite{
+		Name:      suiteName,
+		Tests:     len(report.SLA),
+		Failures:  metrics.SLAFailures(report.SLA),
+		Time:      duration,
+		Timestamp: report.Context.ExecutionContext.GeneratedAt.Format("2006-01-02T15:04:05"),
+		Hostname:  report.Context.Environment.Hostname,
+		Properties: []junitProperty{
+			{Name: "protocol", Value: protocol},
+			{Name: "total_operations", Value: fmt.Sprintf("%d", report.Metrics.CoreOperations.TotalOperations)},
+			{Name: "ops_per_second", Value: fmt.Sprintf("%.2f", report.Metrics.CoreOperations.OperationsPerSecond)},
+			{Name: "error_rate", Value: fmt.Sprintf("%.4f", report.Metrics.CoreOperations.ErrorRate)},
+			{Name: "p99", Value: report.Metrics.LatencyAnalysis.Percentiles.P99.String()},
+			{Name: "session_id", Value: report.Context.ExecutionContext.UniqueSessionID},
+		},
+		SystemOut: j.summary(report),
+	}
+
+	for _, assertion := range report.SLA {
+		testCase := junitTestCase{
+			Name:      assertion.Name,
+			ClassName: suiteName + ".sla",
+			Time:      duration,
+		}
+		if !assertion.Passed {
+			testCase.Failure = &junitFailure{
+				Message: assertion.Message,
+				Type:    "SLAViolation",
+				Text:    fmt.Sprintf("SLA %s violated: %s", assertion.Name, assertion.Message),
+			}
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	suites := junitTestSuites{
+		Name:     "abc-runner",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Time:     duration,
+		Suites:   []junitTestSuite{suite},
+	}
+
+	data, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JUnit XML: %w", err)
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+// summary 套件的文本摘要，CI中展开用例输出时可见
+func (j *JUnitRenderer) summary(report *StructuredReport) string {
+	ops := report.Metrics.CoreOperations
+	latency := report.Metrics.LatencyAnalysis
+	return fmt.Sprintf("operations=%d success=%d failed=%d rps=%.2f error_rate=%.4f%% avg=%v p50=%v p90=%v p99=%v p999=%v",
+		ops.TotalOperations, ops.SuccessfulOps, ops.FailedOps, ops.OperationsPerSecond, ops.ErrorRate,
+		latency.AverageLatency, latency.Percentiles.P50, latency.Percentiles.P90,
+		latency.Percentiles.P99, latency.Percentiles.P999)
+}
+
+// formatJUnitSeconds 按JUnit约定将时长格式化为秒
+func formatJUnitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,65 @@
+package reporting
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"abc-runner/app/core/metrics"
+)
+
+func slaReport() *StructuredReport {
+	report := &StructuredReport{}
+	report.Context.TestConfiguration.Protocol = "redis"
+	report.Context.TestConfiguration.TestDuration = 1500 * time.Millisecond
+	report.Metrics.CoreOperations.TotalOperations = 10000
+	report.Metrics.CoreOperations.OperationsPerSecond = 950
+	report.Metrics.CoreOperations.ErrorRate = 0.5
+	report.Metrics.LatencyAnalysis.Percentiles.P99 = 40 * time.Millisecond
+	return report
+}
+
+func TestJUnitRenderer_Render(t *testing.T) {
+	report := slaReport()
+	rules, err := metrics.ParseSLARules([]string{"p99 <= 50ms", "rps >= 1000"})
+	if err != nil {
+		t.Fatalf("ParseSLARules: %v", err)
+	}
+	var core metrics.CoreMetrics
+	core.Operations.Total = 10000
+	core.Throughput.RPS = 950
+	core.Latency.P99 = 40 * time.Millisecond
+	report.SLA = metrics.EvaluateSLA(core, rules)
+
+	data, err := NewJUnitRenderer().Render(report)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if !strings.HasPrefix(string(data), xml.Header) {
+		t.Errorf("missing XML header")
+	}
+
+	var suites junitTestSuites
+	if err := xml.Unmarshal(data, &suites); err != nil {
+		t.Fatalf("output is not valid XML: %v\n%s", err, data)
+	}
+	if suites.Tests != 2 || suites.Failures != 1 || len(suites.Suites) != 1 {
+		t.Fatalf("tests = %d, failures = %d, suites = %d", suites.Tests, suites.Failures, len(suites.Suites))
+	}
+
+	suite := suites.Suites[0]
+	if suite.Name != "abc-runner.redis" || suite.Time != "1.500" {
+		t.Errorf("suite name = %q, time = %q", suite.Name, suite.Time)
+	}
+	if len(suite.TestCases) != 2 {
+		t.Fatalf("got %d test cases, want 2", len(suite.TestCases))
+	}
+	if p99 := suite.TestCases[0]; p99.Name != "p99 <= 50ms" || p99.Failure != nil {
+		t.Errorf("p99 case = %+v", p99)
+	}
+	rps := suite.TestCases[1]
+	if rps.Failure == nil || rps.Failure.Type != "SLAViolation" || !strings.Contains(rps.Failure.Message, "950.00") {
+		t.Errorf("rps case = %+v", rps)
+	}
+}
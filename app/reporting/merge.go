@@ -0,0 +1,240 @@
+package reporting
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"abc-runner/app/core/metrics"
+)
+
+// MergeSource 合并报告的一个来源报告
+type MergeSource struct {
+	File        string    `json:"file,omitempty"`
+	Hostname    string    `json:"hostname"`
+	SessionID   string    `json:"session_id"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Operations  int64     `json:"operations"`
+	Histogram   bool      `json:"histogram"` // 来源报告是否带有延迟直方图
+}
+
+// 合并报告协议指标中记录分位数来源的取值
+const (
+	MergedPercentilesHistogram = "histogram" // 由合并后的直方图计算，结果精确
+	MergedPercentilesWeighted  = "weighted"  // 有来源缺少直方图，按操作数加权近似
+)
+
+// MergeReports 合并多个运行实例（如手动分布到多台机器、压测同一目标）的结构化报告
+// 计数累加、吞吐量求和，全部来源带有延迟直方图时合并直方图计算分位数，否则按操作数加权近似；
+// 时间序列按采样间隔对齐后逐点合并。files与reports一一对应，仅用于记录来源，可为nil
+func MergeReports(reports []*StructuredReport, files []string) (*StructuredReport, error) {
+	if len(reports) < 2 {
+		return nil, fmt.Errorf("need at least 2 reports to merge, got %d", len(reports))
+	}
+
+	protocol := reports[0].Context.TestConfiguration.Protocol
+	snapshots := make([]*metrics.DefaultMetricsSnapshot, len(reports))
+	sources := make([]MergeSource, len(reports))
+	exact := true
+	var (
+		clients   int
+		start     time.Time
+		end       time.Time
+		hostnames []string
+		aborted   []string
+	)
+	for i, report := range reports {
+		if report.Context.TestConfiguration.Protocol != protocol {
+			return nil, fmt.Errorf("cannot merge reports of different protocols (%s and %s)",
+				protocol, report.Context.TestConfiguration.Protocol)
+		}
+		snapshots[i] = snapshotFromReport(report)
+
+		source := MergeSource{
+			Hostname:    report.Context.Environment.Hostname,
+			SessionID:   report.Context.ExecutionContext.UniqueSessionID,
+			GeneratedAt: report.Context.ExecutionContext.GeneratedAt,
+			Operations:  report.Metrics.CoreOperations.TotalOperations,
+			Histogram:   report.Metrics.LatencyAnalysis.Histogram != nil,
+		}
+		if i < len(files) {
+			source.File = files[i]
+		}
+		sources[i] = source
+		if !source.Histogram && source.Operations > 0 {
+			exact = false
+		}
+
+		clients += report.Context.TestConfiguration.ConcurrentClients
+		runtime := report.System.RuntimeMetrics
+		if !runtime.StartTime.IsZero() && (start.IsZero() || runtime.StartTime.Before(start)) {
+			start = runtime.StartTime
+		}
+		if runtime.EndTime.After(end) {
+			end = runtime.EndTime
+		}
+		if source.Hostname != "" && !containsString(hostnames, source.Hostname) {
+			hostnames = append(hostnames, source.Hostname)
+		}
+		if report.Context.Aborted != "" {
+			aborted = append(aborted, fmt.Sprintf("%s: %s", sourceName(source, i), report.Context.Aborted))
+		}
+	}
+
+	merged := metrics.MergeSnapshots(snapshots...)
+	merged.Protocol["protocol"] = protocol
+	merged.Protocol["merged_reports"] = len(reports)
+	// 直方图范围不一致时MergeSnapshots同样退回加权近似
+	if exact && merged.LatencyHistogram != nil {
+		merged.Protocol["merged_percentiles"] = MergedPercentilesHistogram
+	} else {
+		merged.Protocol["merged_percentiles"] = MergedPercentilesWeighted
+	}
+	merged.TimeSeries = mergeTimeSeries(reports)
+
+	result := ConvertFromMetricsSnapshot(merged)
+	result.Context.TestConfiguration.ConcurrentClients = clients
+	result.Context.Environment.Hostname = strings.Join(hostnames, ",")
+	result.Context.Aborted = strings.Join(aborted, "; ")
+	result.Context.MergedFrom = sources
+	// 各实例的运行时间不完全重合时，合并报告覆盖最早开始到最晚结束的区间
+	if !start.IsZero() && end.After(start) {
+		result.System.RuntimeMetrics.StartTime = start
+		result.System.RuntimeMetrics.EndTime = end
+	}
+	return result, nil
+}
+
+// PercentilesExact 合并报告的分位数是否由合并后的直方图精确计算；非合并报告返回true
+func (r *StructuredReport) PercentilesExact() bool {
+	parameters := r.Context.TestConfiguration.Parameters
+	value, ok := parameters["merged_percentiles"]
+	return !ok || value == MergedPercentilesHistogram
+}
+
+// sourceName 来源报告在提示中的名称
+func sourceName(source MergeSource, index int) string {
+	switch {
+	case source.File != "":
+		return source.File
+	case source.Hostname != "":
+		return source.Hostname
+	}
+	return fmt.Sprintf("report %d", index+1)
+}
+
+// snapshotFromReport 由结构化报告还原合并所需的指标快照
+func snapshotFromReport(report *StructuredReport) *metrics.DefaultMetricsSnapshot {
+	ops := report.Metrics.CoreOperations
+	latency := report.Metrics.LatencyAnalysis
+	runtime := report.System.RuntimeMetrics
+
+	snapshot := &metrics.DefaultMetricsSnapshot{
+		Protocol:         make(map[string]interface{}),
+		LatencyHistogram: latency.Histogram,
+		Timestamp:        runtime.EndTime,
+	}
+	snapshot.Core.Operations = metrics.OperationMetrics{
+		Total:        ops.TotalOperations,
+		Success:      ops.SuccessfulOps,
+		Failed:       ops.FailedOps,
+		Read:         ops.OperationTypes["read"],
+		Write:        ops.OperationTypes["write"],
+		Rate:         ops.SuccessRate,
+		BytesRead:    ops.BytesRead,
+		BytesWritten: ops.BytesWritten,
+	}
+	snapshot.Core.Latency = metrics.LatencyMetrics{
+		Min:     latency.MinLatency,
+		Max:     latency.MaxLatency,
+		Average: latency.AverageLatency,
+		P50:     latency.Percentiles.P50,
+		P90:     latency.Percentiles.P90,
+		P95:     latency.Percentiles.P95,
+		P99:     latency.Percentiles.P99,
+		P999:    latency.Percentiles.P999,
+	}
+	snapshot.Core.Duration = runtime.TestDuration
+	snapshot.Core.Throughput.RPS = ops.OperationsPerSecond
+	snapshot.Core.Throughput.ReadMBps = ops.ReadMBPerSec
+	snapshot.Core.Throughput.WriteMBps = ops.WriteMBPerSec
+	if seconds := runtime.TestDuration.Seconds(); seconds > 0 {
+		snapshot.Core.Throughput.ReadRPS = float64(snapshot.Core.Operations.Read) / seconds
+		snapshot.Core.Throughput.WriteRPS = float64(snapshot.Core.Operations.Write) / seconds
+	}
+
+	memory := report.System.MemoryProfile
+	snapshot.System.MemoryUsage.Allocated = uint64(memory.AllocatedMemory)
+	snapshot.System.MemoryUsage.TotalAlloc = uint64(memory.TotalAllocations)
+	snapshot.System.MemoryUsage.InUse = uint64(report.System.ResourceHealth.MaxMemoryUsed)
+	snapshot.System.GCStats.NumGC = memory.GCCount
+	snapshot.System.GCStats.TotalPause = time.Duration(memory.GCPauseTotal)
+	snapshot.System.GoroutineCount = runtime.ActiveGoroutines
+	snapshot.System.CPUUsage.UsagePercent = runtime.CPUUsagePercent
+	return snapshot
+}
+
+// mergeTimeSeries 按采样间隔对齐各报告的时间序列并逐点合并：操作数、错误数与RPS累加，
+// 错误率按合并后的计数重新计算，P95按各点操作数加权近似
+func mergeTimeSeries(reports []*StructuredReport) []metrics.TimeSeriesPoint {
+	interval := timeSeriesInterval(reports)
+	type bucket struct {
+		point       metrics.TimeSeriesPoint
+		weightedP95 float64
+	}
+	buckets := make(map[time.Time]*bucket)
+	for _, report := range reports {
+		for _, point := range report.Metrics.TimeSeries {
+			key := point.Timestamp.Truncate(interval)
+			b, ok := buckets[key]
+			if !ok {
+				b = &bucket{point: metrics.TimeSeriesPoint{Timestamp: key}}
+				buckets[key] = b
+			}
+			b.point.Operations += point.Operations
+			b.point.Errors += point.Errors
+			b.point.RPS += point.RPS
+			b.weightedP95 += float64(point.P95) * float64(point.Operations)
+		}
+	}
+	if len(buckets) == 0 {
+		return nil
+	}
+
+	series := make([]metrics.TimeSeriesPoint, 0, len(buckets))
+	for _, b := range buckets {
+		point := b.point
+		if point.Operations > 0 {
+			point.ErrorRate = float64(point.Errors) / float64(point.Operations) * 100
+			point.P95 = time.Duration(b.weightedP95 / float64(point.Operations))
+		}
+		series = append(series, point)
+	}
+	sort.Slice(series, func(i, j int) bool { return series[i].Timestamp.Before(series[j].Timestamp) })
+	first := series[0].Timestamp
+	for i := range series {
+		series[i].Elapsed = series[i].Timestamp.Sub(first) + interval
+	}
+	return series
+}
+
+// timeSeriesInterval 推断时间序列的采样间隔：各报告相邻采样点的最小间隔，无法推断时为1秒
+func timeSeriesInterval(reports []*StructuredReport) time.Duration {
+	var interval time.Duration
+	for _, report := range reports {
+		points := report.Metrics.TimeSeries
+		for i := 1; i < len(points); i++ {
+			gap := points[i].Elapsed - points[i-1].Elapsed
+			if gap > 0 && (interval == 0 || gap < interval) {
+				interval = gap
+			}
+		}
+	}
+	// 采样点可能有少量抖动，按整秒对齐
+	interval = interval.Round(time.Second)
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return interval
+}
@@ -0,0 +1,129 @@
+package reporting
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"abc-runner/app/core/metrics"
+)
+
+func mergeTestReport(host string, start time.Time, latencies []time.Duration, failed int64) *StructuredReport {
+	histogram := metrics.NewHdrHistogram(int64(10*time.Second), 3)
+	snapshot := &metrics.DefaultMetricsSnapshot{
+		Protocol:  map[string]interface{}{"protocol": "redis"},
+		Timestamp: start.Add(2 * time.Second),
+	}
+	for _, latency := range latencies {
+		histogram.Record(int64(latency))
+	}
+	total := int64(len(latencies))
+	snapshot.Core.Operations = metrics.OperationMetrics{Total: total, Success: total - failed, Failed: failed, Read: total}
+	snapshot.Core.Latency.Min = latencies[0]
+	snapshot.Core.Latency.Max = latencies[len(latencies)-1]
+	snapshot.Core.Latency.P99 = time.Duration(histogram.ValueAtQuantile(99))
+	snapshot.Core.Duration = 2 * time.Second
+	snapshot.Core.Throughput.RPS = float64(total) / 2
+	snapshot.LatencyHistogram = histogram.Export()
+	for i := 1; i <= 2; i++ {
+		snapshot.TimeSeries = append(snapshot.TimeSeries, metrics.TimeSeriesPoint{
+			Timestamp:  start.Add(time.Duration(i) * time.Second),
+			Elapsed:    time.Duration(i) * time.Second,
+			Operations: total / 2,
+			Errors:     failed / 2,
+			RPS:        float64(total) / 2,
+			P95:        snapshot.Core.Latency.P99,
+		})
+	}
+
+	report := ConvertFromMetricsSnapshot(snapshot)
+	report.Context.Environment.Hostname = host
+	report.Context.TestConfiguration.ConcurrentClients = 10
+	return report
+}
+
+func TestMergeReports(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	var fast, slow []time.Duration
+	for i := 0; i < 100; i++ {
+		fast = append(fast, time.Millisecond)
+		slow = append(slow, 100*time.Millisecond)
+	}
+	a := mergeTestReport("host1", start, fast, 0)
+	b := mergeTestReport("host2", start.Add(300*time.Millisecond), slow, 10)
+
+	// 经JSON往返，与读取磁盘上的报告一致
+	var loaded []*StructuredReport
+	for _, report := range []*StructuredReport{a, b} {
+		data, err := json.Marshal(report)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded StructuredReport
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		loaded = append(loaded, &decoded)
+	}
+
+	merged, err := MergeReports(loaded, []string{"a.json", "b.json"})
+	if err != nil {
+		t.Fatalf("merge failed: %v", err)
+	}
+	ops := merged.Metrics.CoreOperations
+	if ops.TotalOperations != 200 || ops.FailedOps != 10 || ops.OperationsPerSecond != 100 {
+		t.Errorf("unexpected counters: %+v", ops)
+	}
+	if merged.Context.TestConfiguration.Protocol != "redis" || merged.Context.TestConfiguration.ConcurrentClients != 20 {
+		t.Errorf("unexpected test configuration: %+v", merged.Context.TestConfiguration)
+	}
+	if merged.Context.Environment.Hostname != "host1,host2" || len(merged.Context.MergedFrom) != 2 {
+		t.Errorf("unexpected sources: %s %+v", merged.Context.Environment.Hostname, merged.Context.MergedFrom)
+	}
+
+	// 一半请求为100ms：合并直方图的P99应接近100ms，而不是两个P99的平均值
+	if !merged.PercentilesExact() {
+		t.Fatalf("expected percentiles from merged histograms")
+	}
+	p99 := merged.Metrics.LatencyAnalysis.Percentiles.P99
+	if p99 < 99*time.Millisecond || p99 > 101*time.Millisecond {
+		t.Errorf("expected merged p99 around 100ms, got %v", p99)
+	}
+	if merged.Metrics.LatencyAnalysis.Histogram == nil {
+		t.Errorf("expected merged report to keep the histogram for further merges")
+	}
+
+	// 两个实例的采样点相差300ms，按秒对齐后逐点合并
+	series := merged.Metrics.TimeSeries
+	if len(series) != 2 {
+		t.Fatalf("expected 2 aligned points, got %+v", series)
+	}
+	for _, point := range series {
+		if point.Operations != 100 || point.Errors != 5 || point.RPS != 100 || point.ErrorRate != 5 {
+			t.Errorf("unexpected merged point: %+v", point)
+		}
+	}
+}
+
+func TestMergeReports_FallsBackWithoutHistogram(t *testing.T) {
+	start := time.Now()
+	a := mergeTestReport("host1", start, []time.Duration{time.Millisecond, 2 * time.Millisecond}, 0)
+	b := mergeTestReport("host2", start, []time.Duration{3 * time.Millisecond, 4 * time.Millisecond}, 0)
+	b.Metrics.LatencyAnalysis.Histogram = nil
+
+	merged, err := MergeReports([]*StructuredReport{a, b}, nil)
+	if err != nil {
+		t.Fatalf("merge failed: %v", err)
+	}
+	if merged.PercentilesExact() {
+		t.Errorf("expected weighted percentiles when a report has no histogram")
+	}
+
+	b.Context.TestConfiguration.Protocol = "http"
+	if _, err := MergeReports([]*StructuredReport{a, b}, nil); err == nil {
+		t.Errorf("expected an error for reports of different protocols")
+	}
+	if _, err := MergeReports([]*StructuredReport{a}, nil); err == nil {
+		t.Errorf("expected an error for a single report")
+	}
+}
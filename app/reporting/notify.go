@@ -0,0 +1,308 @@
+package reporting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"abc-runner/app/core/metrics"
+)
+
+// 通知的消息格式
+const (
+	NotifySlack   = "slack"
+	NotifyTeams   = "teams"
+	NotifyGeneric = "generic"
+)
+
+// 何时发送通知
+const (
+	NotifyAlways    = "always"
+	NotifyOnFailure = "failure"
+)
+
+// DefaultNotifyTimeout 发送通知的超时
+const DefaultNotifyTimeout = 10 * time.Second
+
+// maxNotifyErrorBody 错误信息中保留的响应正文长度
+const maxNotifyErrorBody = 512
+
+// 通知卡片的颜色
+const (
+	notifyColorPassed = "2EB886"
+	notifyColorFailed = "D00000"
+)
+
+// RunNotification 运行结束通知的内容：运行结果摘要、最终报告（未生成报告时为空）、
+// 运行ID、标签与报告链接
+type RunNotification struct {
+	RunID  string
+	Result *RunResult
+	Report *StructuredReport
+	Tags   []string
+	Link   string // 报告的URL或路径，可为空
+}
+
+// Title 通知标题，如 "✅ abc-runner http passed"
+func (n *RunNotification) Title() string {
+	protocol := n.Result.Command
+	if summary := n.Result.Summary; summary != nil && summary.Protocol != "" {
+		protocol = summary.Protocol
+	}
+	if n.Result.Passed {
+		return fmt.Sprintf("✅ abc-runner %s passed", protocol)
+	}
+	return fmt.Sprintf("❌ abc-runner %s failed: %s (exit code %d)", protocol, n.Result.Status, n.Result.ExitCode)
+}
+
+// Facts 通知中的名称与值，只包含本次运行有的项
+func (n *RunNotification) Facts() [][2]string {
+	var facts [][2]string
+	if n.Report != nil {
+		facts = append(facts, [2]string{"Score", fmt.Sprintf("%d/100", n.Report.Dashboard.PerformanceScore)})
+	}
+	if summary := n.Result.Summary; summary != nil {
+		facts = append(facts,
+			[2]string{"RPS", fmt.Sprintf("%.1f", summary.RPS)},
+			[2]string{"P99", fmt.Sprintf("%.2fms", summary.P99)},
+			[2]string{"Error rate", fmt.Sprintf("%.2f%%", summary.ErrorRate)},
+			[2]string{"Operations", fmt.Sprintf("%d", summary.Total)},
+			[2]string{"Duration", fmt.Sprintf("%.1fs", summary.Duration)},
+		)
+	}
+	if n.Report != nil && len(n.Report.SLA) > 0 {
+		facts = append(facts, [2]string{"Thresholds", slaVerdict(n.Report.SLA)})
+	}
+	if n.Result.Error != "" {
+		facts = append(facts, [2]string{"Error", n.Result.Error})
+	}
+	if n.RunID != "" {
+		facts = append(facts, [2]string{"Run ID", n.RunID})
+	}
+	if len(n.Tags) > 0 {
+		facts = append(facts, [2]string{"Tags", strings.Join(n.Tags, ", ")})
+	}
+	if n.Link != "" {
+		facts = append(facts, [2]string{"Report", n.Link})
+	}
+	return facts
+}
+
+// slaVerdict SLA判定摘要，如 "1/2 passed; failed: p99 < 20ms (p99 is 35ms)"
+func slaVerdict(assertions []metrics.SLAAssertion) string {
+	failed := metrics.SLAFailures(assertions)
+	verdict := fmt.Sprintf("%d/%d passed", len(assertions)-failed, len(assertions))
+	var failures []string
+	for _, assertion := range assertions {
+		if !assertion.Passed {
+			failures = append(failures, fmt.Sprintf("%s (%s)", assertion.Name, assertion.Message))
+		}
+	}
+	if len(failures) > 0 {
+		verdict += "; failed: " + strings.Join(failures, ", ")
+	}
+	return verdict
+}
+
+// DetectNotifyFormat 根据webhook地址推断消息格式：Slack与Microsoft Teams的入站webhook，其余为通用JSON
+func DetectNotifyFormat(webhook string) string {
+	parsed, err := url.Parse(webhook)
+	if err != nil {
+		return NotifyGeneric
+	}
+	host := strings.ToLower(parsed.Hostname())
+	switch {
+	case host == "hooks.slack.com":
+		return NotifySlack
+	case strings.HasSuffix(host, ".webhook.office.com"), host == "outlook.office.com", strings.HasSuffix(host, ".logic.azure.com"):
+		return NotifyTeams
+	}
+	return NotifyGeneric
+}
+
+// Notifier 运行结束后向webhook发送运行摘要
+type Notifier struct {
+	webhook string
+	format  string
+	client  *http.Client
+}
+
+// NewNotifier 创建通知器，format为空时按地址推断
+func NewNotifier(webhook, format string) (*Notifier, error) {
+	parsed, err := url.Parse(webhook)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid webhook URL %q", webhook)
+	}
+	switch format {
+	case "":
+		format = DetectNotifyFormat(webhook)
+	case NotifySlack, NotifyTeams, NotifyGeneric:
+	default:
+		return nil, fmt.Errorf("unknown notification format %q (expected slack, teams or generic)", format)
+	}
+	return &Notifier{
+		webhook: webhook,
+		format:  format,
+		client:  &http.Client{Timeout: DefaultNotifyTimeout},
+	}, nil
+}
+
+// Format 消息格式
+func (n *Notifier) Format() string {
+	return n.format
+}
+
+// Notify 发送通知，非2xx响应返回包含正文摘要的错误
+func (n *Notifier) Notify(ctx context.Context, notification *RunNotification) error {
+	body, err := json.Marshal(n.payload(notification))
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "abc-runner")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, maxNotifyErrorBody))
+		return fmt.Errorf("webhook responded with status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// payload 按消息格式生成请求正文
+func (n *Notifier) payload(notification *RunNotification) interface{} {
+	switch n.format {
+	case NotifySlack:
+		return slackPayload(notification)
+	case NotifyTeams:
+		return teamsPayload(notification)
+	}
+	return genericPayload(notification)
+}
+
+// notifyColor 按运行结果选择卡片颜色
+func notifyColor(notification *RunNotification) string {
+	if notification.Result.Passed {
+		return notifyColorPassed
+	}
+	return notifyColorFailed
+}
+
+// slackPayload Slack入站webhook消息：text作为通知预览，附件以颜色条标示结果
+func slackPayload(notification *RunNotification) map[string]interface{} {
+	var fields []map[string]interface{}
+	for _, fact := range notification.Facts() {
+		value := fact[1]
+		if fact[0] == "Report" && isURL(value) {
+			value = fmt.Sprintf("<%s|Open report>", value)
+		}
+		fields = append(fields, map[string]interface{}{
+			"title": fact[0],
+			"value": value,
+			"short": len(value) <= 24,
+		})
+	}
+	return map[string]interface{}{
+		"text": notification.Title(),
+		"attachments": []map[string]interface{}{{
+			"color":  "#" + notifyColor(notification),
+			"fields": fields,
+		}},
+	}
+}
+
+// teamsPayload Microsoft Teams入站webhook的MessageCard
+func teamsPayload(notification *RunNotification) map[string]interface{} {
+	var facts []map[string]string
+	for _, fact := range notification.Facts() {
+		facts = append(facts, map[string]string{"name": fact[0], "value": fact[1]})
+	}
+	card := map[string]interface{}{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"summary":    notification.Title(),
+		"title":      notification.Title(),
+		"themeColor": notifyColor(notification),
+		"sections":   []map[string]interface{}{{"facts": facts}},
+	}
+	if isURL(notification.Link) {
+		card["potentialAction"] = []map[string]interface{}{{
+			"@type":   "OpenUri",
+			"name":    "Open report",
+			"targets": []map[string]string{{"os": "default", "uri": notification.Link}},
+		}}
+	}
+	return card
+}
+
+// genericNotification 通用webhook的JSON正文
+type genericNotification struct {
+	Title  string                 `json:"title"`
+	RunID  string                 `json:"run_id,omitempty"`
+	Score  *int                   `json:"score,omitempty"`
+	Result *RunResult             `json:"result"`
+	SLA    []metrics.SLAAssertion `json:"sla,omitempty"`
+	Tags   []string               `json:"tags,omitempty"`
+	Link   string                 `json:"report,omitempty"`
+}
+
+// genericPayload 通用JSON：标题、运行结果摘要（与--result-file相同）、评分、SLA判定与报告链接
+func genericPayload(notification *RunNotification) genericNotification {
+	payload := genericNotification{
+		Title:  notification.Title(),
+		RunID:  notification.RunID,
+		Result: notification.Result,
+		Tags:   notification.Tags,
+		Link:   notification.Link,
+	}
+	if report := notification.Report; report != nil {
+		score := report.Dashboard.PerformanceScore
+		payload.Score = &score
+		payload.SLA = report.SLA
+	}
+	return payload
+}
+
+// isURL 是否为http(s)链接（本地报告路径不生成按钮）
+func isURL(value string) bool {
+	return strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://")
+}
+
+// ReportLink 从写出的报告文件中选择通知中的链接：优先HTML报告，其次第一个报告；
+// baseURL非空时（如CI产物页面）以其拼接文件名，否则为本地绝对路径
+func ReportLink(files []string, baseURL string) string {
+	if len(files) == 0 {
+		return strings.TrimRight(baseURL, "/")
+	}
+	chosen := files[0]
+	for _, file := range files {
+		if filepath.Ext(file) == ".html" {
+			chosen = file
+			break
+		}
+	}
+	if baseURL != "" {
+		return strings.TrimRight(baseURL, "/") + "/" + url.PathEscape(filepath.Base(chosen))
+	}
+	if abs, err := filepath.Abs(chosen); err == nil {
+		return abs
+	}
+	return chosen
+}
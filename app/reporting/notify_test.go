@@ -0,0 +1,153 @@
+package reporting
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"abc-runner/app/core/metrics"
+)
+
+// testNotification 未达到SLA的运行
+func testNotification() *RunNotification {
+	report := &StructuredReport{}
+	report.Dashboard.PerformanceScore = 72
+	report.SLA = []metrics.SLAAssertion{
+		{Name: "rps > 1000", Passed: true},
+		{Name: "p99 < 20ms", Passed: false, Message: "p99 is 35ms"},
+	}
+	return &RunNotification{
+		RunID: "20261016-120000-beef",
+		Result: &RunResult{Command: "h", ExitCode: 3, Status: "threshold_breach",
+			Summary: &ResultSummary{Protocol: "http", RPS: 1500, P99: 35, ErrorRate: 0.5}},
+		Report: report,
+		Tags:   []string{"nightly"},
+		Link:   "https://ci.example.com/artifacts/http_performance.html",
+	}
+}
+
+// captureWebhook 记录请求正文的webhook
+func captureWebhook(t *testing.T, status int) (*httptest.Server, *map[string]interface{}) {
+	payload := map[string]interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request: %s %v", r.Method, r.Header)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("invalid JSON body: %v", err)
+		}
+		if status != http.StatusOK {
+			http.Error(w, "invalid_token", status)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &payload
+}
+
+func TestNotifier_Slack(t *testing.T) {
+	server, payload := captureWebhook(t, http.StatusOK)
+	notifier, err := NewNotifier(server.URL, NotifySlack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := notifier.Notify(context.Background(), testNotification()); err != nil {
+		t.Fatalf("notify failed: %v", err)
+	}
+
+	if text := (*payload)["text"]; text != "❌ abc-runner http failed: threshold_breach (exit code 3)" {
+		t.Errorf("text = %v", text)
+	}
+	attachment := (*payload)["attachments"].([]interface{})[0].(map[string]interface{})
+	if attachment["color"] != "#"+notifyColorFailed {
+		t.Errorf("color = %v", attachment["color"])
+	}
+	fields := map[string]string{}
+	for _, field := range attachment["fields"].([]interface{}) {
+		field := field.(map[string]interface{})
+		fields[field["title"].(string)] = field["value"].(string)
+	}
+	for title, want := range map[string]string{
+		"Score":      "72/100",
+		"RPS":        "1500.0",
+		"P99":        "35.00ms",
+		"Error rate": "0.50%",
+		"Thresholds": "1/2 passed; failed: p99 < 20ms (p99 is 35ms)",
+		"Report":     "<https://ci.example.com/artifacts/http_performance.html|Open report>",
+	} {
+		if fields[title] != want {
+			t.Errorf("field %s = %q, want %q", title, fields[title], want)
+		}
+	}
+}
+
+func TestNotifier_TeamsAndGeneric(t *testing.T) {
+	server, payload := captureWebhook(t, http.StatusOK)
+	notifier, _ := NewNotifier(server.URL, NotifyTeams)
+	if err := notifier.Notify(context.Background(), testNotification()); err != nil {
+		t.Fatalf("notify failed: %v", err)
+	}
+	if (*payload)["@type"] != "MessageCard" || (*payload)["themeColor"] != notifyColorFailed || (*payload)["potentialAction"] == nil {
+		t.Errorf("unexpected teams card: %v", *payload)
+	}
+
+	// 未生成报告的运行只包含错误与运行结果
+	notification := &RunNotification{Result: &RunResult{Command: "redis", ExitCode: 5, Status: "connection_failure",
+		Error: "connection refused"}}
+	notifier, _ = NewNotifier(server.URL, "")
+	if err := notifier.Notify(context.Background(), notification); err != nil {
+		t.Fatalf("notify failed: %v", err)
+	}
+	result, _ := (*payload)["result"].(map[string]interface{})
+	if (*payload)["title"] != "❌ abc-runner redis failed: connection_failure (exit code 5)" ||
+		result["error"] != "connection refused" || (*payload)["score"] != nil {
+		t.Errorf("unexpected generic payload: %v", *payload)
+	}
+}
+
+func TestNotifier_Errors(t *testing.T) {
+	server, _ := captureWebhook(t, http.StatusForbidden)
+	notifier, _ := NewNotifier(server.URL, NotifyGeneric)
+	err := notifier.Notify(context.Background(), testNotification())
+	if err == nil || !strings.Contains(err.Error(), "403") || !strings.Contains(err.Error(), "invalid_token") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if _, err := NewNotifier("hooks.slack.com/services/x", ""); err == nil {
+		t.Error("expected URL without scheme to be rejected")
+	}
+	if _, err := NewNotifier("https://example.com", "discord"); err == nil {
+		t.Error("expected unknown format to be rejected")
+	}
+}
+
+func TestDetectNotifyFormat(t *testing.T) {
+	for webhook, want := range map[string]string{
+		"https://hooks.slack.com/services/T0/B0/X":             NotifySlack,
+		"https://contoso.webhook.office.com/webhookb2/abc":     NotifyTeams,
+		"https://prod-01.westus.logic.azure.com/workflows/abc": NotifyTeams,
+		"https://ci.example.com/hooks/benchmarks":              NotifyGeneric,
+		"https://hooks.slack.com.example.com/services/T0/B0/X": NotifyGeneric,
+	} {
+		if got := DetectNotifyFormat(webhook); got != want {
+			t.Errorf("DetectNotifyFormat(%s) = %s, want %s", webhook, got, want)
+		}
+	}
+}
+
+func TestReportLink(t *testing.T) {
+	files := []string{"reports/http_performance.json", "reports/http_performance.html"}
+	if link := ReportLink(files, "https://ci.example.com/artifacts/"); link != "https://ci.example.com/artifacts/http_performance.html" {
+		t.Errorf("link = %s", link)
+	}
+	if link := ReportLink(files[:1], ""); !strings.HasSuffix(link, "/reports/http_performance.json") || !strings.HasPrefix(link, "/") {
+		t.Errorf("local link = %s", link)
+	}
+	if link := ReportLink(nil, ""); link != "" {
+		t.Errorf("link without reports = %q", link)
+	}
+}
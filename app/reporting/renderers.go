@@ -10,6 +10,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"abc-runner/app/core/metrics"
 )
 
 // Renderer 渲染器接口
@@ -25,6 +27,10 @@ type RenderConfig struct {
 	OutputDir     string   `json:"output_dir"`
 	FilePrefix    string   `json:"file_prefix"`
 	Timestamp     bool     `json:"timestamp"`
+	Locale        string   `json:"locale"` // 控制台与HTML报告的语言，空值为默认语言
+
+	// OnFileWritten 每写出一个报告文件后调用，可为空
+	OnFileWritten func(path string) `json:"-"`
 }
 
 // DefaultRenderConfig 默认渲染配置
@@ -38,10 +44,13 @@ func DefaultRenderConfig() *RenderConfig {
 }
 
 // ConsoleRenderer 控制台渲染器
-type ConsoleRenderer struct{}
+type ConsoleRenderer struct {
+	t func(string) string
+}
 
-func NewConsoleRenderer() *ConsoleRenderer {
-	return &ConsoleRenderer{}
+// NewConsoleRenderer 创建指定语言的控制台渲染器
+func NewConsoleRenderer(locale string) *ConsoleRenderer {
+	return &ConsoleRenderer{t: translator(locale)}
 }
 
 func (c *ConsoleRenderer) Format() string {
@@ -54,73 +63,159 @@ func (c *ConsoleRenderer) Extension() string {
 
 func (c *ConsoleRenderer) Render(report *StructuredReport) ([]byte, error) {
 	var buf bytes.Buffer
+	t := c.t
 
 	// 报告头部
 	buf.WriteString("\n" + strings.Repeat("=", 80) + "\n")
-	buf.WriteString("             ABC-RUNNER 性能测试报告\n")
+	buf.WriteString("             " + t("ABC-RUNNER 性能测试报告") + "\n")
 	buf.WriteString(strings.Repeat("=", 80) + "\n")
 
 	// 执行摘要
-	buf.WriteString("\n📊 执行摘要\n")
+	buf.WriteString("\n📊 " + t("执行摘要") + "\n")
 	buf.WriteString(strings.Repeat("-", 40) + "\n")
-	buf.WriteString(fmt.Sprintf("性能评分: %d/100\n", report.Dashboard.PerformanceScore))
-	buf.WriteString(fmt.Sprintf("系统状态: %s\n", c.formatStatus(report.Dashboard.StatusIndicator)))
-	buf.WriteString(fmt.Sprintf("协议类型: %s\n", report.Context.TestConfiguration.Protocol))
-	buf.WriteString(fmt.Sprintf("测试时长: %v\n", report.Context.TestConfiguration.TestDuration))
+	buf.WriteString(fmt.Sprintf("%s: %d/100\n", t("性能评分"), report.Dashboard.PerformanceScore))
+	buf.WriteString(fmt.Sprintf("%s: %s\n", t("系统状态"), c.formatStatus(report.Dashboard.StatusIndicator)))
+	buf.WriteString(fmt.Sprintf("%s: %s\n", t("协议类型"), report.Context.TestConfiguration.Protocol))
+	buf.WriteString(fmt.Sprintf("%s: %v\n", t("测试时长"), report.Context.TestConfiguration.TestDuration))
+	if report.Context.Aborted != "" {
+		buf.WriteString("⚠️ " + fmt.Sprintf(t("运行被中断（%s），报告只包含中断前收集的数据"), report.Context.Aborted) + "\n")
+	}
 
 	// 核心指标
-	buf.WriteString("\n⚡ 核心性能指标\n")
+	buf.WriteString("\n⚡ " + t("核心性能指标") + "\n")
 	buf.WriteString(strings.Repeat("-", 40) + "\n")
 	ops := report.Metrics.CoreOperations
-	buf.WriteString(fmt.Sprintf("总操作数: %d\n", ops.TotalOperations))
-	buf.WriteString(fmt.Sprintf("成功操作: %d (%.2f%%)\n", ops.SuccessfulOps, ops.SuccessRate))
-	buf.WriteString(fmt.Sprintf("失败操作: %d (%.2f%%)\n", ops.FailedOps, ops.ErrorRate))
-	buf.WriteString(fmt.Sprintf("吞吐量: %.2f ops/sec\n", ops.OperationsPerSecond))
+	buf.WriteString(fmt.Sprintf("%s: %d\n", t("总操作数"), ops.TotalOperations))
+	buf.WriteString(fmt.Sprintf("%s: %d (%.2f%%)\n", t("成功操作"), ops.SuccessfulOps, ops.SuccessRate))
+	buf.WriteString(fmt.Sprintf("%s: %d (%.2f%%)\n", t("失败操作"), ops.FailedOps, ops.ErrorRate))
+	buf.WriteString(fmt.Sprintf("%s: %.2f ops/sec\n", t("吞吐量"), ops.OperationsPerSecond))
+	if ops.BytesRead > 0 || ops.BytesWritten > 0 {
+		buf.WriteString(fmt.Sprintf(t("传输: 读取 %s (%.2f MB/s), 写出 %s (%.2f MB/s)")+"\n",
+			formatBytes(ops.BytesRead), ops.ReadMBPerSec, formatBytes(ops.BytesWritten), ops.WriteMBPerSec))
+	}
 
 	// 延迟分析
-	buf.WriteString("\n🚀 延迟分析\n")
+	buf.WriteString("\n🚀 " + t("延迟分析") + "\n")
 	buf.WriteString(strings.Repeat("-", 40) + "\n")
 	latency := report.Metrics.LatencyAnalysis
-	buf.WriteString(fmt.Sprintf("平均延迟: %v\n", latency.AverageLatency))
-	buf.WriteString(fmt.Sprintf("最小延迟: %v\n", latency.MinLatency))
-	buf.WriteString(fmt.Sprintf("最大延迟: %v\n", latency.MaxLatency))
-	buf.WriteString("延迟百分位:\n")
+	buf.WriteString(fmt.Sprintf("%s: %v\n", t("平均延迟"), latency.AverageLatency))
+	buf.WriteString(fmt.Sprintf("%s: %v\n", t("最小延迟"), latency.MinLatency))
+	buf.WriteString(fmt.Sprintf("%s: %v\n", t("最大延迟"), latency.MaxLatency))
+	buf.WriteString(t("延迟百分位") + ":\n")
 	buf.WriteString(fmt.Sprintf("  P50: %v\n", latency.Percentiles.P50))
 	buf.WriteString(fmt.Sprintf("  P90: %v\n", latency.Percentiles.P90))
 	buf.WriteString(fmt.Sprintf("  P95: %v\n", latency.Percentiles.P95))
 	buf.WriteString(fmt.Sprintf("  P99: %v\n", latency.Percentiles.P99))
+	buf.WriteString(fmt.Sprintf("  P99.9: %v\n", latency.Percentiles.P999))
+	if len(latency.Phases) > 0 {
+		buf.WriteString(t("按阶段分解 (次数, 平均/P99, 占比)") + ":\n")
+		for _, phase := range latency.Phases {
+			buf.WriteString(fmt.Sprintf("  %-10s %d, %v/%v, %.1f%%\n",
+				phase.Phase, phase.Count, phase.Latency.Average, phase.Latency.P99, phase.Share*100))
+		}
+	}
+
+	// 噪声基底
+	if noise := report.Context.NoiseFloor; noise != nil {
+		buf.WriteString("\n🔇 " + t("噪声基底") + "\n")
+		buf.WriteString(strings.Repeat("-", 40) + "\n")
+		buf.WriteString(fmt.Sprintf(t("基底: %v (调度唤醒P99 %v + 计时P99 %v)")+"\n", noise.Floor, noise.Wakeup.P99, noise.Timer.P99))
+		buf.WriteString(fmt.Sprintf(t("调度唤醒: P50 %v, P99 %v, 最大 %v")+"\n", noise.Wakeup.P50, noise.Wakeup.P99, noise.Wakeup.Max))
+		buf.WriteString(fmt.Sprintf("%s: P50 %v, P99 %v\n", t("定时器偏差"), noise.SleepOvershoot.P50, noise.SleepOvershoot.P99))
+		if noise.Loopback.P50 > 0 {
+			buf.WriteString(fmt.Sprintf("%s: P50 %v, P99 %v\n", t("TCP回环往返"), noise.Loopback.P50, noise.Loopback.P99))
+		}
+		if ratio := noiseFloorRatio(report); ratio > 0 && ratio < noiseFloorWarnRatio {
+			buf.WriteString("⚠️ " + fmt.Sprintf(t("P50延迟仅为噪声基底的%.1f倍，结果中相当部分可能来自主机调度与计时噪声"), ratio) + "\n")
+		}
+	}
 
 	// 系统健康状态
-	buf.WriteString("\n💻 系统健康状态\n")
+	buf.WriteString("\n💻 " + t("系统健康状态") + "\n")
 	buf.WriteString(strings.Repeat("-", 40) + "\n")
 	system := report.System
-	buf.WriteString(fmt.Sprintf("内存使用: %.2f%%\n", system.MemoryProfile.MemoryUsagePercent))
-	buf.WriteString(fmt.Sprintf("活跃协程: %d\n", system.RuntimeMetrics.ActiveGoroutines))
-	buf.WriteString(fmt.Sprintf("GC次数: %d\n", system.MemoryProfile.GCCount))
+	buf.WriteString(fmt.Sprintf("%s: %.2f%%\n", t("内存使用"), system.MemoryProfile.MemoryUsagePercent))
+	buf.WriteString(fmt.Sprintf("%s: %d\n", t("活跃协程"), system.RuntimeMetrics.ActiveGoroutines))
+	buf.WriteString(fmt.Sprintf("%s: %d\n", t("GC次数"), system.MemoryProfile.GCCount))
+
+	// SLA断言
+	if len(report.SLA) > 0 {
+		buf.WriteString("\n🎯 " + t("SLA断言") + "\n")
+		buf.WriteString(strings.Repeat("-", 40) + "\n")
+		for _, assertion := range report.SLA {
+			status := "✅"
+			if !assertion.Passed {
+				status = "❌"
+			}
+			buf.WriteString(fmt.Sprintf("%s %s (%s)\n", status, assertion.Name, assertion.Message))
+		}
+	}
+
+	// 分段汇总
+	if len(report.Intervals) > 0 {
+		buf.WriteString("\n⏱️ " + t("分段汇总") + "\n")
+		buf.WriteString(strings.Repeat("-", 40) + "\n")
+		start := report.Intervals[0].Start
+		for _, interval := range report.Intervals {
+			buf.WriteString(fmt.Sprintf("+%-8v "+t("操作 %-8d 吞吐量 %-10.2f 错误率 %-6.2f%% P50 %-12v P99 %v")+"\n",
+				interval.End.Sub(start).Round(time.Second), interval.Operations, interval.RPS,
+				interval.ErrorRate, interval.P50, interval.P99))
+		}
+	}
+
+	// 各目标汇总
+	if len(report.Targets) > 0 {
+		buf.WriteString("\n🎯 " + t("各目标汇总") + "\n")
+		buf.WriteString(strings.Repeat("-", 40) + "\n")
+		for _, target := range report.Targets {
+			if target.Error != "" {
+				buf.WriteString(fmt.Sprintf("❌ %s: %s\n", target.Target, target.Error))
+				continue
+			}
+			marker := "  "
+			if target.Hotspot {
+				marker = "🔥"
+			}
+			buf.WriteString(fmt.Sprintf("%s %-24s "+t("操作 %-8d 占比 %-6.2f%% 吞吐量 %-10.2f 错误率 %-6.2f%% P50 %-12v P99 %v")+"\n",
+				marker, target.Target, target.Operations, target.Share, target.RPS, target.ErrorRate, target.P50, target.P99))
+			for _, reason := range target.Reasons {
+				buf.WriteString(fmt.Sprintf("     ↳ %s\n", reason))
+			}
+		}
+	}
+
+	// 基线偏离
+	if len(report.BaselineWarnings) > 0 {
+		buf.WriteString("\n🧪 " + t("基线偏离") + "\n")
+		buf.WriteString(strings.Repeat("-", 40) + "\n")
+		for _, warning := range report.BaselineWarnings {
+			buf.WriteString(fmt.Sprintf("⚠️ %s\n", warning.localizedMessage(t)))
+		}
+	}
 
 	// 关键洞察
 	if len(report.Dashboard.KeyInsights) > 0 {
-		buf.WriteString("\n💡 关键洞察\n")
+		buf.WriteString("\n💡 " + t("关键洞察") + "\n")
 		buf.WriteString(strings.Repeat("-", 40) + "\n")
 		for _, insight := range report.Dashboard.KeyInsights {
-			buf.WriteString(fmt.Sprintf("• %s: %s\n", insight.Title, insight.Description))
+			buf.WriteString(fmt.Sprintf("• %s: %s\n", t(insight.Title), t(insight.Description)))
 		}
 	}
 
 	// 优化建议
 	if len(report.Dashboard.Recommendations) > 0 {
-		buf.WriteString("\n🔧 优化建议\n")
+		buf.WriteString("\n🔧 " + t("优化建议") + "\n")
 		buf.WriteString(strings.Repeat("-", 40) + "\n")
 		for _, rec := range report.Dashboard.Recommendations {
 			buf.WriteString(fmt.Sprintf("• [%s] %s: %s\n",
 				strings.ToUpper(string(rec.Priority)),
-				rec.Category,
-				rec.Action))
+				t(rec.Category),
+				t(rec.Action)))
 		}
 	}
 
 	buf.WriteString("\n" + strings.Repeat("=", 80) + "\n")
-	buf.WriteString(fmt.Sprintf("报告生成时间: %s\n", report.Context.ExecutionContext.GeneratedAt.Format("2006-01-02 15:04:05")))
+	buf.WriteString(fmt.Sprintf("%s: %s\n", t("报告生成时间"), report.Context.ExecutionContext.GeneratedAt.Format("2006-01-02 15:04:05")))
 	buf.WriteString(strings.Repeat("=", 80) + "\n")
 
 	return buf.Bytes(), nil
@@ -129,13 +224,27 @@ func (c *ConsoleRenderer) Render(report *StructuredReport) ([]byte, error) {
 func (c *ConsoleRenderer) formatStatus(status StatusLevel) string {
 	switch status {
 	case StatusGood:
-		return "🟢 良好"
+		return "🟢 " + c.t("良好")
 	case StatusWarning:
-		return "🟡 警告"
+		return "🟡 " + c.t("警告")
 	case StatusCritical:
-		return "🔴 严重"
+		return "🔴 " + c.t("严重")
+	default:
+		return "⚪ " + c.t("未知")
+	}
+}
+
+// formatBytes 以B、KiB、MiB、GiB显示字节数
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.2fGiB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.2fMiB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.2fKiB", float64(n)/(1<<10))
 	default:
-		return "⚪ 未知"
+		return fmt.Sprintf("%dB", n)
 	}
 }
 
@@ -184,6 +293,7 @@ func (c *CSVRenderer) Render(report *StructuredReport) ([]byte, error) {
 		"avg_latency_ms", "min_latency_ms", "max_latency_ms",
 		"p90_latency_ms", "p95_latency_ms", "p99_latency_ms",
 		"memory_usage_percent", "active_goroutines", "gc_count",
+		"sla_result", "sla_violations",
 	}
 
 	if err := writer.Write(headers); err != nil {
@@ -212,6 +322,7 @@ func (c *CSVRenderer) Render(report *StructuredReport) ([]byte, error) {
 		fmt.Sprintf("%d", report.System.RuntimeMetrics.ActiveGoroutines),
 		fmt.Sprintf("%d", report.System.MemoryProfile.GCCount),
 	}
+	record = append(record, c.slaColumns(report.SLA)...)
 
 	if err := writer.Write(record); err != nil {
 		return nil, fmt.Errorf("failed to write CSV record: %w", err)
@@ -225,11 +336,32 @@ func (c *CSVRenderer) Render(report *StructuredReport) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// slaColumns SLA判定结果列：pass/fail（未设置规则时为空）与分号分隔的未通过规则
+func (c *CSVRenderer) slaColumns(assertions []metrics.SLAAssertion) []string {
+	if len(assertions) == 0 {
+		return []string{"", ""}
+	}
+
+	var violations []string
+	for _, assertion := range assertions {
+		if !assertion.Passed {
+			violations = append(violations, assertion.Name)
+		}
+	}
+	if len(violations) > 0 {
+		return []string{"fail", strings.Join(violations, "; ")}
+	}
+	return []string{"pass", ""}
+}
+
 // HTMLRenderer HTML渲染器
-type HTMLRenderer struct{}
+type HTMLRenderer struct {
+	locale string
+}
 
-func NewHTMLRenderer() *HTMLRenderer {
-	return &HTMLRenderer{}
+// NewHTMLRenderer 创建指定语言的HTML渲染器
+func NewHTMLRenderer(locale string) *HTMLRenderer {
+	return &HTMLRenderer{locale: locale}
 }
 
 func (h *HTMLRenderer) Format() string {
@@ -241,6 +373,8 @@ func (h *HTMLRenderer) Extension() string {
 }
 
 func (h *HTMLRenderer) Render(report *StructuredReport) ([]byte, error) {
+	t := translator(h.locale)
+
 	// 定义自定义模板函数
 	funcMap := template.FuncMap{
 		"upper": func(v interface{}) string {
@@ -254,6 +388,20 @@ func (h *HTMLRenderer) Render(report *StructuredReport) ([]byte, error) {
 				return strings.ToUpper(fmt.Sprintf("%v", val))
 			}
 		},
+		"percent": func(ratio float64) string {
+			return fmt.Sprintf("%.1f%%", ratio*100)
+		},
+		"throughputChart": func(points []metrics.TimeSeriesPoint) template.HTML {
+			return throughputChart(points, t)
+		},
+		"latencyChart": func(points []metrics.TimeSeriesPoint) template.HTML {
+			return latencyChart(points, t)
+		},
+		"baselineMessage": func(warning BaselineWarning) string {
+			return warning.localizedMessage(t)
+		},
+		"t":    t,
+		"lang": func() string { return htmlLang(h.locale) },
 	}
 
 	tmpl := template.Must(template.New("report").Funcs(funcMap).Parse(htmlTemplate))
@@ -284,10 +432,11 @@ func NewReportGenerator(config *RenderConfig) *ReportGenerator {
 	}
 
 	// 注册内置渲染器
-	generator.renderers["console"] = NewConsoleRenderer()
+	generator.renderers["console"] = NewConsoleRenderer(config.Locale)
 	generator.renderers["json"] = NewJSONRenderer()
 	generator.renderers["csv"] = NewCSVRenderer()
-	generator.renderers["html"] = NewHTMLRenderer()
+	generator.renderers["html"] = NewHTMLRenderer(config.Locale)
+	generator.renderers["junit"] = NewJUnitRenderer()
 
 	return generator
 }
@@ -335,6 +484,9 @@ func (g *ReportGenerator) renderFormat(report *StructuredReport, format string)
 	}
 
 	fmt.Printf("✅ %s report saved to: %s\n", strings.ToUpper(format), filename)
+	if g.config.OnFileWritten != nil {
+		g.config.OnFileWritten(filename)
+	}
 	return nil
 }
 
@@ -376,11 +528,11 @@ func (g *ReportGenerator) RegisterRenderer(format string, renderer Renderer) {
 // HTML模板
 const htmlTemplate = `
 <!DOCTYPE html>
-<html lang="zh-CN">
+<html lang="{{lang}}">
 <head>
     <meta charset="UTF-8">
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <title>ABC-Runner 性能测试报告</title>
+    <title>{{t "ABC-Runner 性能测试报告"}}</title>
     <style>
         body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; margin: 0; padding: 20px; background: #f5f5f5; }
         .container { max-width: 1200px; margin: 0 auto; background: white; border-radius: 8px; box-shadow: 0 2px 10px rgba(0,0,0,0.1); }
@@ -399,67 +551,156 @@ const htmlTemplate = `
         .status-critical { color: #dc3545; }
         .insights ul, .recommendations ul { list-style: none; padding: 0; }
         .insights li, .recommendations li { background: #f8f9fa; margin: 10px 0; padding: 15px; border-radius: 6px; border-left: 4px solid #17a2b8; }
+        .chart { background: #f8f9fa; padding: 10px; border-radius: 6px; margin-top: 20px; }
+        .chart h3 { margin: 5px 10px; color: #555; font-size: 1em; }
+        .sla { width: 100%; border-collapse: collapse; margin-top: 20px; }
+        .sla th, .sla td { text-align: left; padding: 10px; border-bottom: 1px solid #eee; }
+        .sla-pass td:first-child { color: #28a745; font-weight: bold; }
+        .sla-fail td:first-child { color: #dc3545; font-weight: bold; }
+        .aborted { background: #fff3cd; color: #856404; padding: 15px; border-radius: 6px; margin-bottom: 30px; border-left: 4px solid #ffc107; }
         .footer { text-align: center; padding: 20px; color: #666; border-top: 1px solid #eee; }
     </style>
 </head>
 <body>
     <div class="container">
         <div class="header">
-            <h1>ABC-Runner 性能测试报告</h1>
-            <div class="subtitle">协议: {{.Context.TestConfiguration.Protocol}} | 生成时间: {{.Context.ExecutionContext.GeneratedAt.Format "2006-01-02 15:04:05"}}</div>
+            <h1>{{t "ABC-Runner 性能测试报告"}}</h1>
+            <div class="subtitle">{{t "协议"}}: {{.Context.TestConfiguration.Protocol}} | {{t "生成时间"}}: {{.Context.ExecutionContext.GeneratedAt.Format "2006-01-02 15:04:05"}}</div>
         </div>
         
         <div class="content">
+            {{with .Context.Aborted}}
+            <div class="aborted">⚠️ {{printf (t "运行被中断（%s），报告只包含中断前收集的数据") .}}</div>
+            {{end}}
             <div class="section">
-                <h2>📊 执行摘要</h2>
+                <h2>📊 {{t "执行摘要"}}</h2>
                 <div class="metrics-grid">
                     <div class="metric-card">
                         <div class="metric-value">{{.Dashboard.PerformanceScore}}/100</div>
-                        <div class="metric-label">性能评分</div>
+                        <div class="metric-label">{{t "性能评分"}}</div>
                     </div>
                     <div class="metric-card">
                         <div class="metric-value status-{{.Dashboard.StatusIndicator}}">{{.Dashboard.StatusIndicator}}</div>
-                        <div class="metric-label">系统状态</div>
+                        <div class="metric-label">{{t "系统状态"}}</div>
                     </div>
                     <div class="metric-card">
                         <div class="metric-value">{{.Metrics.CoreOperations.TotalOperations}}</div>
-                        <div class="metric-label">总操作数</div>
+                        <div class="metric-label">{{t "总操作数"}}</div>
                     </div>
                     <div class="metric-card">
                         <div class="metric-value">{{printf "%.2f" .Metrics.CoreOperations.OperationsPerSecond}}</div>
-                        <div class="metric-label">吞吐量 (ops/sec)</div>
+                        <div class="metric-label">{{t "吞吐量 (ops/sec)"}}</div>
                     </div>
                 </div>
             </div>
             
             <div class="section">
-                <h2>⚡ 核心性能指标</h2>
+                <h2>⚡ {{t "核心性能指标"}}</h2>
                 <div class="metrics-grid">
                     <div class="metric-card">
                         <div class="metric-value">{{printf "%.2f%%" .Metrics.CoreOperations.SuccessRate}}</div>
-                        <div class="metric-label">成功率</div>
+                        <div class="metric-label">{{t "成功率"}}</div>
                     </div>
                     <div class="metric-card">
                         <div class="metric-value">{{printf "%.2f%%" .Metrics.CoreOperations.ErrorRate}}</div>
-                        <div class="metric-label">错误率</div>
+                        <div class="metric-label">{{t "错误率"}}</div>
                     </div>
                     <div class="metric-card">
                         <div class="metric-value">{{.Metrics.LatencyAnalysis.AverageLatency}}</div>
-                        <div class="metric-label">平均延迟</div>
+                        <div class="metric-label">{{t "平均延迟"}}</div>
                     </div>
                     <div class="metric-card">
                         <div class="metric-value">{{.Metrics.LatencyAnalysis.Percentiles.P99}}</div>
-                        <div class="metric-label">P99延迟</div>
+                        <div class="metric-label">{{t "P99延迟"}}</div>
                     </div>
+                    {{with .Metrics.CoreOperations}}{{if or .BytesRead .BytesWritten}}
+                    <div class="metric-card">
+                        <div class="metric-value">{{printf "%.2f" .ReadMBPerSec}} / {{printf "%.2f" .WriteMBPerSec}}</div>
+                        <div class="metric-label">{{t "读取 / 写出带宽 (MB/s)"}}</div>
+                    </div>
+                    {{end}}{{end}}
                 </div>
             </div>
             
+            {{with .Metrics.LatencyAnalysis.Phases}}
+            <div class="section">
+                <h2>⏱️ {{t "延迟阶段分解"}}</h2>
+                <table class="sla">
+                    <tr><th>{{t "阶段"}}</th><th>{{t "次数"}}</th><th>{{t "平均"}}</th><th>P50</th><th>P99</th><th>{{t "最大"}}</th><th>{{t "占比"}}</th></tr>
+                    {{range .}}
+                    <tr>
+                        <td>{{.Phase}}</td>
+                        <td>{{.Count}}</td>
+                        <td>{{.Latency.Average}}</td>
+                        <td>{{.Latency.P50}}</td>
+                        <td>{{.Latency.P99}}</td>
+                        <td>{{.Latency.Max}}</td>
+                        <td>{{percent .Share}}</td>
+                    </tr>
+                    {{end}}
+                </table>
+            </div>
+            {{end}}
+            
+            {{with .Context.NoiseFloor}}
+            <div class="section">
+                <h2>🔇 {{t "噪声基底"}}</h2>
+                <ul>
+                    <li><strong>{{t "基底"}}</strong>: {{.Floor}}</li>
+                    <li><strong>{{t "调度唤醒"}}</strong>: P50 {{.Wakeup.P50}}, P99 {{.Wakeup.P99}}</li>
+                    <li><strong>{{t "定时器偏差"}}</strong>: P50 {{.SleepOvershoot.P50}}, P99 {{.SleepOvershoot.P99}}</li>
+                    <li><strong>{{t "TCP回环往返"}}</strong>: P50 {{.Loopback.P50}}, P99 {{.Loopback.P99}}</li>
+                </ul>
+            </div>
+            {{end}}
+            
+            {{if .SLA}}
+            <div class="section">
+                <h2>🎯 {{t "SLA断言"}}</h2>
+                <table class="sla">
+                    <tr><th>{{t "结果"}}</th><th>{{t "规则"}}</th><th>{{t "详情"}}</th></tr>
+                    {{range .SLA}}
+                    <tr class="{{if .Passed}}sla-pass{{else}}sla-fail{{end}}">
+                        <td>{{if .Passed}}✅ PASS{{else}}❌ FAIL{{end}}</td>
+                        <td>{{.Name}}</td>
+                        <td>{{.Message}}</td>
+                    </tr>
+                    {{end}}
+                </table>
+            </div>
+            {{end}}
+            
+            {{if .Metrics.TimeSeries}}
+            <div class="section">
+                <h2>📈 {{t "时间序列"}}</h2>
+                <div class="chart">
+                    <h3>{{t "吞吐量与错误率"}}</h3>
+                    {{throughputChart .Metrics.TimeSeries}}
+                </div>
+                <div class="chart">
+                    <h3>{{t "P95延迟"}}</h3>
+                    {{latencyChart .Metrics.TimeSeries}}
+                </div>
+            </div>
+            {{end}}
+            
+            {{if .BaselineWarnings}}
+            <div class="section">
+                <h2>🧪 {{t "基线偏离"}}</h2>
+                <ul>
+                    {{range .BaselineWarnings}}
+                    <li><strong>{{.Profile}}</strong>: {{baselineMessage .}}</li>
+                    {{end}}
+                </ul>
+            </div>
+            {{end}}
+            
             {{if .Dashboard.KeyInsights}}
             <div class="section insights">
-                <h2>💡 关键洞察</h2>
+                <h2>💡 {{t "关键洞察"}}</h2>
                 <ul>
                     {{range .Dashboard.KeyInsights}}
-                    <li><strong>{{.Title}}</strong>: {{.Description}}</li>
+                    <li><strong>{{t .Title}}</strong>: {{t .Description}}</li>
                     {{end}}
                 </ul>
             </div>
@@ -467,10 +708,10 @@ const htmlTemplate = `
             
             {{if .Dashboard.Recommendations}}
             <div class="section recommendations">
-                <h2>🔧 优化建议</h2>
+                <h2>🔧 {{t "优化建议"}}</h2>
                 <ul>
                     {{range .Dashboard.Recommendations}}
-                    <li><strong>[{{.Priority | upper}}] {{.Category}}</strong>: {{.Action}}</li>
+                    <li><strong>[{{.Priority | upper}}] {{t .Category}}</strong>: {{t .Action}}</li>
                     {{end}}
                 </ul>
             </div>
@@ -478,7 +719,7 @@ const htmlTemplate = `
         </div>
         
         <div class="footer">
-            <p>由 ABC-Runner {{.Context.Environment.ABCRunnerVersion}} 生成 | 会话ID: {{.Context.ExecutionContext.UniqueSessionID}}</p>
+            <p>{{printf (t "由 ABC-Runner %s 生成 | 会话ID: %s") .Context.Environment.ABCRunnerVersion .Context.ExecutionContext.UniqueSessionID}}</p>
         </div>
     </div>
 </body>
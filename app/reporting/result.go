@@ -0,0 +1,194 @@
+package reporting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"abc-runner/app/core/metrics"
+)
+
+// DefaultResultFile 运行结果摘要的默认路径
+const DefaultResultFile = "reports/result.json"
+
+// RunResult 机器可读的运行结果摘要
+// 每次运行都会写出（包括参数错误、连接失败等未生成报告的情况），CI脚本只需读取passed与exit_code
+type RunResult struct {
+	Command   string         `json:"command"`
+	Passed    bool           `json:"passed"`
+	ExitCode  int            `json:"exit_code"`
+	Status    string         `json:"status"`
+	Error     string         `json:"error,omitempty"`
+	StartedAt time.Time      `json:"started_at"`
+	Elapsed   float64        `json:"elapsed_seconds"`
+	Summary   *ResultSummary `json:"summary,omitempty"` // 未生成报告时为空
+}
+
+// ResultSummary 运行结果的核心数字，延迟单位为毫秒，错误率为百分比
+type ResultSummary struct {
+	Protocol   string  `json:"protocol"`
+	Duration   float64 `json:"duration_seconds"`
+	Total      int64   `json:"total_operations"`
+	Successful int64   `json:"successful_operations"`
+	Failed     int64   `json:"failed_operations"`
+	ErrorRate  float64 `json:"error_rate"`
+	RPS        float64 `json:"rps"`
+	AvgLatency float64 `json:"avg_latency_ms"`
+	P50        float64 `json:"p50_ms"`
+	P95        float64 `json:"p95_ms"`
+	P99        float64 `json:"p99_ms"`
+	MaxLatency float64 `json:"max_latency_ms"`
+	SLAChecked int     `json:"sla_checked"`
+	SLAFailed  int     `json:"sla_failed"`
+}
+
+// NewResultSummary 从结构化报告提取核心数字
+func NewResultSummary(report *StructuredReport) *ResultSummary {
+	ops := report.Metrics.CoreOperations
+	latency := report.Metrics.LatencyAnalysis
+	return &ResultSummary{
+		Protocol:   report.Context.TestConfiguration.Protocol,
+		Duration:   report.Context.TestConfiguration.TestDuration.Seconds(),
+		Total:      ops.TotalOperations,
+		Successful: ops.SuccessfulOps,
+		Failed:     ops.FailedOps,
+		ErrorRate:  ops.ErrorRate,
+		RPS:        ops.OperationsPerSecond,
+		AvgLatency: milliseconds(latency.AverageLatency),
+		P50:        milliseconds(latency.Percentiles.P50),
+		P95:        milliseconds(latency.Percentiles.P95),
+		P99:        milliseconds(latency.Percentiles.P99),
+		MaxLatency: milliseconds(latency.MaxLatency),
+		SLAChecked: len(report.SLA),
+		SLAFailed:  metrics.SLAFailures(report.SLA),
+	}
+}
+
+// milliseconds 将时长转换为毫秒
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// WriteRunResult 将运行结果写入path，目录不存在时自动创建
+func WriteRunResult(path string, result *RunResult) error {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create result directory: %w", err)
+		}
+	}
+	// SLA规则名包含 < >，不做HTML转义以便直接阅读
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return fmt.Errorf("failed to marshal run result: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write run result %s: %w", path, err)
+	}
+	return nil
+}
+
+// ResultRecorder 保存本次运行最终报告的记录器，由调用方通过context注入命令
+// 同时收集运行标签与写出的报告文件，供运行历史使用
+type ResultRecorder struct {
+	mutex   sync.Mutex
+	report  *StructuredReport
+	runID   string
+	tags    []string
+	reports []string
+}
+
+// NewResultRecorder 创建运行结果记录器
+func NewResultRecorder() *ResultRecorder {
+	return &ResultRecorder{}
+}
+
+// Record 记录最终报告
+func (r *ResultRecorder) Record(report *StructuredReport) {
+	r.mutex.Lock()
+	r.report = report
+	r.mutex.Unlock()
+}
+
+// SetRunID 设置运行ID，运行历史以此作为记录ID
+func (r *ResultRecorder) SetRunID(id string) {
+	r.mutex.Lock()
+	r.runID = id
+	r.mutex.Unlock()
+}
+
+// RunID 运行ID，未设置时为空
+func (r *ResultRecorder) RunID() string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.runID
+}
+
+// SetTags 设置运行标签
+func (r *ResultRecorder) SetTags(tags []string) {
+	r.mutex.Lock()
+	r.tags = append([]string(nil), tags...)
+	r.mutex.Unlock()
+}
+
+// Tags 运行标签
+func (r *ResultRecorder) Tags() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]string(nil), r.tags...)
+}
+
+// AddReportFile 记录一个写出的报告文件
+func (r *ResultRecorder) AddReportFile(path string) {
+	r.mutex.Lock()
+	r.reports = append(r.reports, path)
+	r.mutex.Unlock()
+}
+
+// ReportFiles 本次运行写出的报告文件
+func (r *ResultRecorder) ReportFiles() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]string(nil), r.reports...)
+}
+
+// Report 最终报告，未记录报告时返回nil
+func (r *ResultRecorder) Report() *StructuredReport {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.report
+}
+
+// Summary 最终报告的核心数字，未记录报告时返回nil
+func (r *ResultRecorder) Summary() *ResultSummary {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.report == nil {
+		return nil
+	}
+	return NewResultSummary(r.report)
+}
+
+// resultRecorderKey context键
+type resultRecorderKey struct{}
+
+// WithResultRecorder 返回携带运行结果记录器的context
+func WithResultRecorder(ctx context.Context, recorder *ResultRecorder) context.Context {
+	return context.WithValue(ctx, resultRecorderKey{}, recorder)
+}
+
+// ResultRecorderFromContext 从context中获取运行结果记录器
+func ResultRecorderFromContext(ctx context.Context) (*ResultRecorder, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	recorder, ok := ctx.Value(resultRecorderKey{}).(*ResultRecorder)
+	return recorder, ok && recorder != nil
+}
@@ -0,0 +1,58 @@
+package reporting
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"abc-runner/app/core/metrics"
+)
+
+func TestWriteRunResult(t *testing.T) {
+	recorder := NewResultRecorder()
+	if recorder.Summary() != nil {
+		t.Fatal("expected no summary before a report is recorded")
+	}
+
+	ctx := WithResultRecorder(context.Background(), recorder)
+	fromCtx, ok := ResultRecorderFromContext(ctx)
+	if !ok || fromCtx != recorder {
+		t.Fatal("recorder not found in context")
+	}
+
+	report := slaReport()
+	report.SLA = []metrics.SLAAssertion{{Name: "p99 <= 50ms", Passed: true}, {Name: "rps >= 1000"}}
+	fromCtx.Record(report)
+
+	path := filepath.Join(t.TempDir(), "out", "result.json")
+	result := &RunResult{
+		Command:   "redis",
+		ExitCode:  3,
+		Status:    "threshold_breach",
+		StartedAt: time.Now(),
+		Summary:   recorder.Summary(),
+	}
+	if err := WriteRunResult(path, result); err != nil {
+		t.Fatalf("WriteRunResult: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read result: %v", err)
+	}
+	var decoded RunResult
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	summary := decoded.Summary
+	if decoded.Passed || decoded.ExitCode != 3 || summary == nil {
+		t.Fatalf("unexpected result: %s", data)
+	}
+	if summary.Protocol != "redis" || summary.Total != 10000 || summary.RPS != 950 || summary.P99 != 40 ||
+		summary.Duration != 1.5 || summary.SLAChecked != 2 || summary.SLAFailed != 1 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+}
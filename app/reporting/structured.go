@@ -23,6 +23,18 @@ type StructuredReport struct {
 
 	// ContextMetadata 上下文元数据
 	Context ContextMetadata `json:"context"`
+
+	// SLA SLA断言结果，未设置SLA规则时为空
+	SLA []metrics.SLAAssertion `json:"sla,omitempty"`
+
+	// Intervals 运行期间按--partial-report间隔写入的分段汇总，未启用部分报告时为空
+	Intervals []metrics.IntervalSummary `json:"intervals,omitempty"`
+
+	// BaselineWarnings 结果偏离内置参考基线的告警，用于发现压到mock等配置错误
+	BaselineWarnings []BaselineWarning `json:"baseline_warnings,omitempty"`
+
+	// Targets 多目标运行中各目标的汇总，其余指标为全部目标的聚合；单目标运行时为空
+	Targets []metrics.TargetSummary `json:"targets,omitempty"`
 }
 
 // ExecutiveDashboard 高管仪表板
@@ -104,6 +116,9 @@ type MetricsBreakdown struct {
 
 	// ProtocolSpecific 协议特定指标
 	ProtocolSpecific interface{} `json:"protocol_specific"`
+
+	// TimeSeries 按秒采样的吞吐量、错误率与P95延迟
+	TimeSeries []metrics.TimeSeriesPoint `json:"time_series,omitempty"`
 }
 
 // OperationAnalysis 操作分析
@@ -115,6 +130,12 @@ type OperationAnalysis struct {
 	ErrorRate           float64 `json:"error_rate"`
 	OperationsPerSecond float64 `json:"operations_per_second"`
 
+	// 传输的负载字节数与带宽，适配器未上报字节数时为0
+	BytesRead     int64   `json:"bytes_read"`
+	BytesWritten  int64   `json:"bytes_written"`
+	ReadMBPerSec  float64 `json:"read_mb_per_sec"`
+	WriteMBPerSec float64 `json:"write_mb_per_sec"`
+
 	// 操作分布
 	OperationTypes map[string]int64 `json:"operation_types"`
 }
@@ -130,6 +151,12 @@ type LatencyBreakdown struct {
 
 	// 延迟分布
 	Distribution LatencyDistribution `json:"distribution"`
+
+	// Phases 按阶段的延迟分解（DNS、建连、TLS、写请求、首字节、传输），适配器未记录时为空
+	Phases []metrics.PhaseLatency `json:"phases,omitempty"`
+
+	// Histogram 延迟直方图计数，供"report merge"精确合并多个报告的分位数；收集器不支持导出时为空
+	Histogram *metrics.LatencyHistogram `json:"histogram,omitempty"`
 }
 
 // LatencyPercentiles 延迟百分位
@@ -201,6 +228,18 @@ type ContextMetadata struct {
 
 	// ExecutionContext 执行上下文
 	ExecutionContext ExecContext `json:"execution_context"`
+
+	// NoiseFloor 测量前的主机噪声基底（--noise-floor），未测量时为空
+	NoiseFloor *metrics.NoiseFloor `json:"noise_floor,omitempty"`
+
+	// RequestIDPrefix 本次运行的请求ID前缀（--request-id），目标端按"<前缀>-<序号>"检索
+	RequestIDPrefix string `json:"request_id_prefix,omitempty"`
+
+	// Aborted 运行被中断（如Ctrl+C）时的原因，报告只包含中断前收集的数据；运行完整结束时为空
+	Aborted string `json:"aborted,omitempty"`
+
+	// MergedFrom 由"report merge"合并生成时的各来源报告，普通运行时为空
+	MergedFrom []MergeSource `json:"merged_from,omitempty"`
 }
 
 // TestConfig 测试配置
@@ -236,6 +275,8 @@ func ConvertFromMetricsSnapshot(snapshot *metrics.MetricsSnapshot[map[string]int
 		Metrics:   generateMetricsBreakdown(snapshot),
 		System:    generateSystemHealth(snapshot),
 		Context:   generateContextMetadata(snapshot),
+
+		BaselineWarnings: CheckBaselines(snapshot),
 	}
 
 	return report
@@ -272,6 +313,10 @@ func generateMetricsBreakdown(snapshot *metrics.MetricsSnapshot[map[string]inter
 			SuccessRate:         snapshot.Core.Operations.Rate,
 			ErrorRate:           errorRate,
 			OperationsPerSecond: snapshot.Core.Throughput.RPS,
+			BytesRead:           snapshot.Core.Operations.BytesRead,
+			BytesWritten:        snapshot.Core.Operations.BytesWritten,
+			ReadMBPerSec:        snapshot.Core.Throughput.ReadMBps,
+			WriteMBPerSec:       snapshot.Core.Throughput.WriteMBps,
 			OperationTypes: map[string]int64{
 				"read":  snapshot.Core.Operations.Read,
 				"write": snapshot.Core.Operations.Write,
@@ -282,18 +327,28 @@ func generateMetricsBreakdown(snapshot *metrics.MetricsSnapshot[map[string]inter
 			MinLatency:     snapshot.Core.Latency.Min,
 			MaxLatency:     snapshot.Core.Latency.Max,
 			Percentiles: LatencyPercentiles{
-				P50: snapshot.Core.Latency.P50,
-				P90: snapshot.Core.Latency.P90,
-				P95: snapshot.Core.Latency.P95,
-				P99: snapshot.Core.Latency.P99,
+				P50:  snapshot.Core.Latency.P50,
+				P90:  snapshot.Core.Latency.P90,
+				P95:  snapshot.Core.Latency.P95,
+				P99:  snapshot.Core.Latency.P99,
+				P999: snapshot.Core.Latency.P999,
 			},
 			// 计算延迟分布
 			Distribution: calculateLatencyDistribution(snapshot),
+			Phases:       latencyPhases(snapshot),
+			Histogram:    snapshot.LatencyHistogram,
 		},
 		ProtocolSpecific: snapshot.Protocol,
+		TimeSeries:       snapshot.TimeSeries,
 	}
 }
 
+// latencyPhases 从协议指标中取出适配器记录的按阶段延迟分解
+func latencyPhases(snapshot *metrics.MetricsSnapshot[map[string]interface{}]) []metrics.PhaseLatency {
+	phases, _ := snapshot.Protocol["latency_phases"].([]metrics.PhaseLatency)
+	return phases
+}
+
 // calculateLatencyDistribution 计算延迟分布（基于现有指标估算）
 func calculateLatencyDistribution(snapshot *metrics.MetricsSnapshot[map[string]interface{}]) LatencyDistribution {
 	// 获取操作总数
@@ -557,6 +612,18 @@ func generateRecommendations(snapshot *metrics.MetricsSnapshot[map[string]interf
 	return recommendations
 }
 
+// noiseFloorWarnRatio P50延迟低于噪声基底该倍数时提示结果受主机噪声影响
+const noiseFloorWarnRatio = 10
+
+// noiseFloorRatio P50延迟与噪声基底之比，未测量噪声基底时为0
+func noiseFloorRatio(report *StructuredReport) float64 {
+	noise := report.Context.NoiseFloor
+	if noise == nil || noise.Floor <= 0 {
+		return 0
+	}
+	return float64(report.Metrics.LatencyAnalysis.Percentiles.P50) / float64(noise.Floor)
+}
+
 func getProtocolFromSnapshot(snapshot *metrics.MetricsSnapshot[map[string]interface{}]) string {
 	if protocolData, ok := snapshot.Protocol["protocol"]; ok {
 		if protocol, ok := protocolData.(string); ok {
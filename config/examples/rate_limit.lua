@@ -0,0 +1,11 @@
+-- 固定窗口限流示例脚本
+-- KEYS[1]: 计数键；ARGV[1]: 窗口内允许的请求数；ARGV[2]: 窗口长度（秒）
+-- 返回1表示放行，0表示被限流
+local current = redis.call('INCR', KEYS[1])
+if current == 1 then
+  redis.call('EXPIRE', KEYS[1], ARGV[2])
+end
+if current > tonumber(ARGV[1]) then
+  return 0
+end
+return 1
@@ -1,10 +1,18 @@
 package main
 
-import "abc-runner/app/bootstrap"
+import (
+	"fmt"
+	"os"
+
+	"abc-runner/app/bootstrap"
+	"abc-runner/app/commands"
+)
 
 func main() {
 	app := bootstrap.NewApplication()
 	if err := app.Run(); err != nil {
-		panic(err)
+		// 按失败原因退出：回归1、内部错误2、SLA未满足3、参数或配置错误4、连接失败5、中断130
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(commands.ExitCodeOf(err))
 	}
 }
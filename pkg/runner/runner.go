@@ -0,0 +1,216 @@
+// Package runner 以库的形式在其他Go程序中运行abc-runner基准测试
+//
+// 场景与命令行用法一致：Command为命令名（redis、http、kafka等），Args为其后的参数。
+// 运行在当前进程内完成，不生成报告文件，结果以结构化报告返回给调用方：
+//
+//	r, err := runner.New()
+//	if err != nil {
+//		return err
+//	}
+//	result, err := r.Run(ctx, runner.Scenario{
+//		Command: "http",
+//		Args:    []string{"--url", "http://localhost:8080", "-n", "1000", "-c", "10"},
+//		SLA:     []string{"p99 < 50ms", "error_rate < 1%"},
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	fmt.Println(result.Report.Metrics.LatencyAnalysis.Percentiles.P99, result.Passed())
+//
+// 命令的运行过程仍会输出到标准输出。同一个Runner可以被多个goroutine同时使用；
+// 返回的错误保留命令的错误分类，可以用ExitCode得到与命令行一致的退出码。
+package runner
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"abc-runner/app/bootstrap/discovery"
+	"abc-runner/app/commands"
+	"abc-runner/app/core/execution"
+	"abc-runner/app/core/interfaces"
+	"abc-runner/app/core/metrics"
+	"abc-runner/app/reporting"
+)
+
+// Report 结构化测试报告，与命令行生成的JSON报告结构相同
+type Report = reporting.StructuredReport
+
+// Snapshot 指标快照
+type Snapshot = metrics.DefaultMetricsSnapshot
+
+// SLAAssertion 单条SLA规则的判定结果
+type SLAAssertion = metrics.SLAAssertion
+
+// Interceptor 操作拦截器，包裹每个操作的执行
+type Interceptor = execution.Interceptor
+
+// InterceptorFunc 函数形式的拦截器
+type InterceptorFunc = execution.InterceptorFunc
+
+// Executor 拦截器调用的下一环
+type Executor = execution.Executor
+
+// Operation 拦截器看到的操作
+type Operation = interfaces.Operation
+
+// OperationResult 操作结果
+type OperationResult = interfaces.OperationResult
+
+// Scenario 一次测试场景
+type Scenario struct {
+	Command  string          // 命令名称或别名，如redis、http、kafka
+	Args     []string        // 命令参数，与 abc-runner <command> 之后的参数相同
+	SLA      []string        // SLA规则，如 "p99 < 20ms"、"error_rate < 1%"、"rps > 5000"
+	Timeout  time.Duration   // 场景超时，0表示仅受调用方context约束
+	Progress func(*Snapshot) // 运行期间周期性接收中间快照，可为nil
+
+	// Interceptors 包裹每个操作的拦截器，第一个位于最外层，位于Args中--intercept之外
+	Interceptors []Interceptor
+}
+
+// Result 场景运行结果
+type Result struct {
+	Report   *Report   // 由最终快照生成的结构化报告，设置了SLA规则时包含判定结果
+	Snapshot *Snapshot // 最终指标快照
+}
+
+// Passed 是否全部SLA规则通过，未设置SLA规则时为true
+func (r *Result) Passed() bool {
+	return metrics.SLAFailures(r.Report.SLA) == 0
+}
+
+// Failures 未通过的SLA断言
+func (r *Result) Failures() []SLAAssertion {
+	var failures []SLAAssertion
+	for _, assertion := range r.Report.SLA {
+		if !assertion.Passed {
+			failures = append(failures, assertion)
+		}
+	}
+	return failures
+}
+
+// Runner 在当前进程内运行场景
+type Runner struct {
+	router *discovery.CommandRouter
+}
+
+// New 完成协议发现与命令注册并创建Runner
+func New() (*Runner, error) {
+	builder := discovery.NewAutoDIBuilder()
+	if err := builder.Build(); err != nil {
+		return nil, fmt.Errorf("auto DI build failed: %w", err)
+	}
+	router := discovery.NewCommandRouter(builder)
+	if err := router.AutoRegister(); err != nil {
+		return nil, fmt.Errorf("command auto-registration failed: %w", err)
+	}
+	return &Runner{router: router}, nil
+}
+
+var (
+	defaultOnce   sync.Once
+	defaultRunner *Runner
+	defaultErr    error
+)
+
+// Default 返回进程内共享的Runner，首次调用时创建
+func Default() (*Runner, error) {
+	defaultOnce.Do(func() {
+		defaultRunner, defaultErr = New()
+	})
+	return defaultRunner, defaultErr
+}
+
+// Run 使用共享的Runner运行场景
+func Run(ctx context.Context, scenario Scenario) (*Result, error) {
+	r, err := Default()
+	if err != nil {
+		return nil, err
+	}
+	return r.Run(ctx, scenario)
+}
+
+// Commands 可运行的命令名称
+func (r *Runner) Commands() []string {
+	return r.router.GetCommands()
+}
+
+// Help 命令的帮助信息
+func (r *Runner) Help(command string) (string, error) {
+	return r.router.GetCommandHelp(command)
+}
+
+// scenarioSink 转发中间快照并保留最终快照的接收器
+type scenarioSink struct {
+	progress func(*Snapshot)
+	mutex    sync.Mutex
+	snapshot *Snapshot
+}
+
+// OnProgress 转发中间快照
+func (s *scenarioSink) OnProgress(snapshot *Snapshot) {
+	if s.progress != nil {
+		s.progress(snapshot)
+	}
+}
+
+// OnFinal 保存最终快照
+func (s *scenarioSink) OnFinal(snapshot *Snapshot) {
+	s.mutex.Lock()
+	s.snapshot = snapshot
+	s.mutex.Unlock()
+}
+
+// Run 运行场景并生成结构化报告
+// 运行失败、未产生指标快照或目标不可达而以模拟数据运行时返回错误，SLA违规不视为错误
+func (r *Runner) Run(ctx context.Context, scenario Scenario) (*Result, error) {
+	if scenario.Command == "" {
+		return nil, commands.NewConfigError(fmt.Errorf("scenario command is required"))
+	}
+	if !r.router.HasCommand(scenario.Command) {
+		return nil, commands.NewConfigError(fmt.Errorf("unknown command: %s", scenario.Command))
+	}
+	rules, err := metrics.ParseSLARules(scenario.SLA)
+	if err != nil {
+		return nil, commands.NewConfigError(err)
+	}
+
+	if scenario.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, scenario.Timeout)
+		defer cancel()
+	}
+	if len(scenario.Interceptors) > 0 {
+		ctx = execution.WithInterceptors(ctx, scenario.Interceptors...)
+	}
+	sink := &scenarioSink{progress: scenario.Progress}
+	if err := r.router.Execute(metrics.WithSnapshotSink(ctx, sink), scenario.Command, scenario.Args); err != nil {
+		return nil, fmt.Errorf("scenario %s failed: %w", scenario.Command, err)
+	}
+
+	sink.mutex.Lock()
+	snapshot := sink.snapshot
+	sink.mutex.Unlock()
+	if snapshot == nil {
+		return nil, fmt.Errorf("scenario %s finished without producing a metrics snapshot", scenario.Command)
+	}
+	// 目标不可达时部分协议以模拟数据运行，结果不可用
+	if reason, ok := snapshot.Protocol["simulated"].(string); ok {
+		return nil, commands.NewConnectionError(fmt.Errorf("scenario %s target unreachable, results are simulated: %s", scenario.Command, reason))
+	}
+
+	report := reporting.ConvertFromMetricsSnapshot(snapshot)
+	if len(rules) > 0 {
+		report.SLA = metrics.EvaluateSLA(snapshot.Core, rules)
+	}
+	return &Result{Report: report, Snapshot: snapshot}, nil
+}
+
+// ExitCode 错误对应的命令行退出码：nil为0，参数或配置错误4，连接失败5，其余运行失败2
+func ExitCode(err error) int {
+	return commands.ExitCodeOf(err)
+}
@@ -0,0 +1,112 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunnerRun(t *testing.T) {
+	var slow atomic.Bool
+	var tagged atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Bench") == "1" {
+			tagged.Add(1)
+		}
+		if slow.Load() {
+			time.Sleep(15 * time.Millisecond)
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	r, err := Default()
+	if err != nil {
+		t.Fatalf("failed to create runner: %v", err)
+	}
+
+	// 运行超过一个推送周期，以收到中间快照
+	slow.Store(true)
+	var progress atomic.Int64
+	result, err := r.Run(context.Background(), Scenario{
+		Command:  "http",
+		Args:     []string{"--url", server.URL, "-n", "100", "-c", "1"},
+		SLA:      []string{"error_rate < 1%", "p99 < 1ns"},
+		Progress: func(*Snapshot) { progress.Add(1) },
+	})
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	ops := result.Report.Metrics.CoreOperations
+	if ops.TotalOperations == 0 || ops.FailedOps != 0 || ops.TotalOperations != result.Snapshot.Core.Operations.Total {
+		t.Errorf("unexpected report operations: %+v", ops)
+	}
+	if len(result.Report.SLA) != 2 || result.Passed() ||
+		len(result.Failures()) != 1 || result.Failures()[0].Metric != "p99" {
+		t.Errorf("expected only the p99 rule to fail, got %+v", result.Report.SLA)
+	}
+	if progress.Load() == 0 {
+		t.Error("expected progress snapshots during the run")
+	}
+
+	// 未设置SLA规则时视为通过；拦截器同时来自Scenario与--intercept
+	slow.Store(false)
+	var intercepted atomic.Int64
+	result, err = Run(context.Background(), Scenario{
+		Command: "http",
+		Args:    []string{"--url", server.URL, "-n", "10", "--intercept", "header:X-Bench=1"},
+		Interceptors: []Interceptor{InterceptorFunc(func(ctx context.Context, operation Operation, next Executor) (*OperationResult, error) {
+			intercepted.Add(1)
+			return next(ctx, operation)
+		})},
+	})
+	if err != nil || !result.Passed() || result.Report.SLA != nil {
+		t.Errorf("expected a passing run without SLA results, got %v, %v", result, err)
+	}
+	if intercepted.Load() != 10 || tagged.Load() != 10 {
+		t.Errorf("expected 10 intercepted and tagged requests, got %d and %d", intercepted.Load(), tagged.Load())
+	}
+}
+
+func TestRunnerErrors(t *testing.T) {
+	r, err := Default()
+	if err != nil {
+		t.Fatalf("failed to create runner: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		scenario Scenario
+		exitCode int
+	}{
+		{"missing command", Scenario{}, 4},
+		{"unknown command", Scenario{Command: "nope"}, 4},
+		{"invalid SLA", Scenario{Command: "http", SLA: []string{"p99 fast"}}, 4},
+		{"help only", Scenario{Command: "http", Args: []string{"--help"}}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := r.Run(context.Background(), tt.scenario)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if code := ExitCode(err); code != tt.exitCode {
+				t.Errorf("exit code = %d, want %d (%v)", code, tt.exitCode, err)
+			}
+		})
+	}
+
+	found := false
+	for _, command := range r.Commands() {
+		found = found || command == "http"
+	}
+	if !found {
+		t.Errorf("http missing from commands: %v", r.Commands())
+	}
+	if help, err := r.Help("http"); err != nil || help == "" {
+		t.Errorf("expected http help, got %q, %v", help, err)
+	}
+}
@@ -4,4 +4,4 @@ go 1.21
 
 require gopkg.in/yaml.v3 v3.0.1
 
-require github.com/gorilla/websocket v1.5.3 // indirect
+require github.com/gorilla/websocket v1.5.3
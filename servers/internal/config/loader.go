@@ -24,9 +24,9 @@ func NewHTTPConfigLoader() *HTTPConfigLoader {
 
 // LoadFromFile 从文件加载HTTP配置
 func (loader *HTTPConfigLoader) LoadFromFile(configPath string) (*http.HTTPServerConfig, error) {
-	data, err := ioutil.ReadFile(configPath)
+	data, err := readConfigFile(configPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return nil, err
 	}
 
 	config := http.NewHTTPServerConfig()
@@ -223,9 +223,9 @@ func NewWebSocketConfigLoader() *WebSocketConfigLoader {
 
 // LoadFromFile 从文件加载WebSocket配置
 func (loader *WebSocketConfigLoader) LoadFromFile(configPath string) (*websocket.WebSocketServerConfig, error) {
-	data, err := ioutil.ReadFile(configPath)
+	data, err := readConfigFile(configPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return nil, err
 	}
 
 	config := websocket.NewWebSocketServerConfig()
@@ -328,9 +328,9 @@ func (loader *UniversalConfigLoader) LoadByProtocol(protocol, configPath string)
 
 // DetectProtocol 从配置文件检测协议类型
 func (loader *UniversalConfigLoader) DetectProtocol(configPath string) (string, error) {
-	data, err := ioutil.ReadFile(configPath)
+	data, err := readConfigFile(configPath)
 	if err != nil {
-		return "", fmt.Errorf("failed to read config file: %w", err)
+		return "", err
 	}
 
 	// 尝试解析为通用结构体来获取协议字段
@@ -0,0 +1,199 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 服务端配置文件与abc-runner的配置加载器相同的预处理：合并include:引用的片段文件，
+// 并以环境变量替换值中的${VAR}与${VAR:-default}
+//
+// 本文件是app/core/config/yamlfile的副本：servers是独立模块，服务端镜像只以servers目录为构建上下文
+// （Go 1.21），无法引用根模块的包。修改时两处需同步，两份实现由同一组用例
+// app/core/config/yamlfile/testdata验证
+
+// includeKey 引用片段文件的顶层键，值为一个路径或路径列表，相对路径相对于当前文件所在目录
+const includeKey = "include"
+
+// envPattern 匹配$${（转义）、${VAR}与${VAR:-default}
+var envPattern = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// readConfigFile 读取YAML配置文件并完成预处理，返回可交给任意YAML解析器的内容
+// 片段文件按列出的顺序合并，后者覆盖前者，当前文件的键覆盖全部片段；映射逐键深度合并，列表与标量整体替换
+func readConfigFile(path string) ([]byte, error) {
+	root, err := loadConfigNode(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return nil, nil
+	}
+	data, err := yaml.Marshal(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// expandEnv 替换值中的环境变量引用：${VAR:-default}在VAR未设置或为空时取default；
+// ${VAR}在VAR未设置时原样保留（如HTTP场景变量${token}）；$${写作字面的${
+func expandEnv(value string) string {
+	if !strings.Contains(value, "${") {
+		return value
+	}
+	return envPattern.ReplaceAllStringFunc(value, func(match string) string {
+		if match == "$${" {
+			return "${"
+		}
+		groups := envPattern.FindStringSubmatch(match)
+		env, ok := os.LookupEnv(groups[1])
+		if groups[2] == "" {
+			if !ok {
+				return match
+			}
+			return env
+		}
+		if env == "" {
+			return groups[3]
+		}
+		return env
+	})
+}
+
+// loadConfigNode 读取文件的根节点并展开其中的引用，stack为正在读取的文件链，用于发现循环引用
+func loadConfigNode(path string, stack []string) (*yaml.Node, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve config path %s: %w", path, err)
+	}
+	for _, parent := range stack {
+		if parent == abs {
+			return nil, fmt.Errorf("config include cycle: %s -> %s", strings.Join(stack, " -> "), abs)
+		}
+	}
+	stack = append(stack, abs)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	if len(document.Content) == 0 {
+		return nil, nil
+	}
+	root := document.Content[0]
+	expandNode(root)
+
+	includes, err := takeIncludes(root, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(includes) == 0 {
+		return root, nil
+	}
+
+	merged := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, include := range includes {
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(path), include)
+		}
+		fragment, err := loadConfigNode(include, stack)
+		if err != nil {
+			return nil, err
+		}
+		if fragment == nil {
+			continue
+		}
+		if fragment.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("included config %s must be a mapping", include)
+		}
+		mergeMapping(merged, fragment)
+	}
+	mergeMapping(merged, root)
+	return merged, nil
+}
+
+// takeIncludes 取出并移除根映射中的include:键
+func takeIncludes(root *yaml.Node, path string) ([]string, error) {
+	if root.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != includeKey {
+			continue
+		}
+		value := root.Content[i+1]
+		root.Content = append(root.Content[:i], root.Content[i+2:]...)
+
+		var includes []string
+		switch value.Kind {
+		case yaml.ScalarNode:
+			includes = []string{value.Value}
+		case yaml.SequenceNode:
+			for _, item := range value.Content {
+				if item.Kind != yaml.ScalarNode {
+					return nil, fmt.Errorf("invalid %s: entry in %s, expected a file path", includeKey, path)
+				}
+				includes = append(includes, item.Value)
+			}
+		default:
+			return nil, fmt.Errorf("invalid %s: in %s, expected a file path or a list of paths", includeKey, path)
+		}
+		return includes, nil
+	}
+	return nil, nil
+}
+
+// expandNode 替换全部标量值中的环境变量引用，映射的键保持不变
+// 替换后的非引号标量清除已解析的类型，重新按内容解析，使${PORT:-6379}可写入整数字段
+func expandNode(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		expanded := expandEnv(node.Value)
+		if expanded == node.Value {
+			return
+		}
+		node.Value = expanded
+		if node.Style&(yaml.TaggedStyle|yaml.SingleQuotedStyle|yaml.DoubleQuotedStyle|yaml.LiteralStyle|yaml.FoldedStyle) == 0 {
+			node.Tag = ""
+		}
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			expandNode(node.Content[i])
+		}
+	default:
+		for _, child := range node.Content {
+			expandNode(child)
+		}
+	}
+}
+
+// mergeMapping 将src的键合并到dst：两侧均为映射时递归合并，否则以src的值替换
+func mergeMapping(dst, src *yaml.Node) {
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		existing := -1
+		for j := 0; j+1 < len(dst.Content); j += 2 {
+			if dst.Content[j].Value == key.Value {
+				existing = j
+				break
+			}
+		}
+		switch {
+		case existing < 0:
+			dst.Content = append(dst.Content, key, value)
+		case dst.Content[existing+1].Kind == yaml.MappingNode && value.Kind == yaml.MappingNode:
+			mergeMapping(dst.Content[existing+1], value)
+		default:
+			dst.Content[existing+1] = value
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("SERVERS_HOST", "0.0.0.0")
+	t.Setenv("SERVERS_EMPTY", "")
+
+	cases := map[string]string{
+		"${SERVERS_HOST}":               "0.0.0.0",
+		"${SERVERS_HOST:-localhost}:80": "0.0.0.0:80",
+		"${SERVERS_EMPTY:-fallback}":    "fallback",
+		"${SERVERS_UNSET:-}":            "",
+		"/users/${SERVERS_UNSET}":       "/users/${SERVERS_UNSET}", // 未设置且无默认值时原样保留
+		"$${SERVERS_HOST}":              "${SERVERS_HOST}",
+		"plain":                         "plain",
+	}
+	for input, want := range cases {
+		if got := expandEnv(input); got != want {
+			t.Errorf("expandEnv(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestHTTPConfigLoader_IncludesAndEnv(t *testing.T) {
+	t.Setenv("SERVERS_PORT", "8088")
+	dir := t.TempDir()
+
+	writeFile(t, dir, "common/base.yaml", `
+host: localhost
+port: 8080
+read_timeout: 30s
+write_timeout: 30s
+`)
+	path := writeFile(t, dir, "http-server.yaml", `
+include: common/base.yaml
+host: ${SERVERS_HOST:-0.0.0.0}
+port: ${SERVERS_PORT}
+write_timeout: ${SERVERS_WRITE_TIMEOUT:-5s}
+`)
+
+	config, err := NewHTTPConfigLoader().LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile failed: %v", err)
+	}
+	// 当前文件的键覆盖片段，片段中其余的键保留
+	if config.Host != "0.0.0.0" || config.Port != 8088 {
+		t.Errorf("unexpected address %s:%d", config.Host, config.Port)
+	}
+	if config.ReadTimeout != 30*time.Second || config.WriteTimeout != 5*time.Second {
+		t.Errorf("unexpected timeouts: read %v, write %v", config.ReadTimeout, config.WriteTimeout)
+	}
+
+	data, err := readConfigFile(path)
+	if err != nil {
+		t.Fatalf("readConfigFile failed: %v", err)
+	}
+	if strings.Contains(string(data), includeKey+":") {
+		t.Errorf("expected include: to be removed:\n%s", data)
+	}
+}
+
+// sharedFixtures 与根模块yamlfile包共用的预处理用例目录
+const sharedFixtures = "../../../app/core/config/yamlfile/testdata"
+
+// TestReadConfigFile_SharedFixtures 以根模块yamlfile包的用例验证本副本，防止两份实现产生差异；
+// 用例格式见该包的TestRead_Fixtures，servers模块单独检出时跳过
+func TestReadConfigFile_SharedFixtures(t *testing.T) {
+	cases, err := os.ReadDir(sharedFixtures)
+	if os.IsNotExist(err) {
+		t.Skipf("shared fixtures %s not found", sharedFixtures)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range cases {
+		dir := filepath.Join(sharedFixtures, entry.Name())
+		t.Run(entry.Name(), func(t *testing.T) {
+			if env, err := os.ReadFile(filepath.Join(dir, "env")); err == nil {
+				for _, line := range strings.Split(strings.TrimSpace(string(env)), "\n") {
+					key, value, _ := strings.Cut(line, "=")
+					t.Setenv(key, value)
+				}
+			}
+
+			data, err := readConfigFile(filepath.Join(dir, "main.yaml"))
+			if want, readErr := os.ReadFile(filepath.Join(dir, "error")); readErr == nil {
+				if err == nil || !strings.Contains(err.Error(), strings.TrimSpace(string(want))) {
+					t.Fatalf("expected error containing %q, got %v", strings.TrimSpace(string(want)), err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readConfigFile failed: %v", err)
+			}
+
+			want, err := os.ReadFile(filepath.Join(dir, "want.yaml"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got, expected interface{}
+			if err := yaml.Unmarshal(data, &got); err != nil {
+				t.Fatalf("failed to decode result: %v\n%s", err, data)
+			}
+			if err := yaml.Unmarshal(want, &expected); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("unexpected result:\n%s\nwant:\n%s", data, want)
+			}
+		})
+	}
+}